@@ -0,0 +1,76 @@
+package app
+
+import (
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	accountactivitykeeper "github.com/Asphere-xyz/tacchain/x/accountactivity/keeper"
+)
+
+// AccountActivityDecorator counts the txs of a block for the accounts that
+// signed them, along with the gas of the ones they paid for, when the
+// accountactivity module is enabled. The EVM txs are counted for their
+// sender, the other txs for their signers. It runs last so the gas includes
+// the one of the other post decorators, and its own writes aren't charged:
+// enabling the index doesn't change the gas of the txs.
+type AccountActivityDecorator struct {
+	keeper accountactivitykeeper.Keeper
+}
+
+// NewAccountActivityDecorator returns a post decorator recording the activity of the accounts
+func NewAccountActivityDecorator(keeper accountactivitykeeper.Keeper) AccountActivityDecorator {
+	return AccountActivityDecorator{keeper: keeper}
+}
+
+func (d AccountActivityDecorator) PostHandle(ctx sdk.Context, tx sdk.Tx, simulate, success bool, next sdk.PostHandler) (sdk.Context, error) {
+	// the txs are counted once they are in a block
+	if !success || simulate || ctx.IsCheckTx() {
+		return next(ctx, tx, simulate, success)
+	}
+
+	gasUsed := ctx.GasMeter().GasConsumed()
+	signers, payer, err := txAccounts(tx)
+	if err != nil {
+		return ctx, err
+	}
+	d.keeper.RecordTx(ctx.WithGasMeter(storetypes.NewInfiniteGasMeter()), signers, payer, gasUsed)
+
+	return next(ctx, tx, simulate, success)
+}
+
+// txAccounts returns the accounts that signed tx and the one paying its fees.
+// An EVM tx is signed by its sender, which pays its fees, rather than by the
+// signers of the Cosmos tx wrapping it.
+func txAccounts(tx sdk.Tx) ([]sdk.AccAddress, sdk.AccAddress, error) {
+	var senders []sdk.AccAddress
+	for _, msg := range tx.GetMsgs() {
+		if ethMsg, ok := msg.(*evmvmtypes.MsgEthereumTx); ok {
+			senders = append(senders, sdk.AccAddress(ethMsg.GetFrom()))
+		}
+	}
+	if len(senders) > 0 {
+		return senders, senders[0], nil
+	}
+
+	sigTx, ok := tx.(authsigning.SigVerifiableTx)
+	if !ok {
+		return nil, nil, nil
+	}
+	signers, err := sigTx.GetSigners()
+	if err != nil {
+		return nil, nil, err
+	}
+	accounts := make([]sdk.AccAddress, 0, len(signers))
+	for _, signer := range signers {
+		accounts = append(accounts, signer)
+	}
+	var payer sdk.AccAddress
+	if feeTx, ok := tx.(sdk.FeeTx); ok {
+		payer = feeTx.FeePayer()
+	}
+	return accounts, payer, nil
+}
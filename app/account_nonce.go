@@ -0,0 +1,38 @@
+package app
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// AccountNonceExplanation describes how the Cosmos sequence and the EVM nonce
+// of an account relate on tacchain.
+const AccountNonceExplanation = "The Cosmos sequence and the EVM nonce are a single counter stored on the account. " +
+	"Every Cosmos tx signed by the account and every EVM tx sent from it increments it by one, " +
+	"so when txs of both kinds are mixed each one must use the next value, regardless of which kind of tx used the previous one. " +
+	"An EVM tx that reverts still increments it, a tx rejected before execution doesn't."
+
+// AccountNonce is the replay protection counter of an account as seen by the
+// Cosmos and the EVM side of the chain.
+type AccountNonce struct {
+	Address       string `json:"address"`
+	EthAddress    string `json:"eth_address"`
+	AccountNumber uint64 `json:"account_number"`
+	Sequence      uint64 `json:"sequence"`
+	Nonce         uint64 `json:"nonce"`
+	Explanation   string `json:"explanation"`
+}
+
+// NewAccountNonce returns the counters of the account at addr, sequence is
+// read from x/auth and nonce from x/vm.
+func NewAccountNonce(addr sdk.AccAddress, accountNumber, sequence, nonce uint64) AccountNonce {
+	return AccountNonce{
+		Address:       addr.String(),
+		EthAddress:    common.BytesToAddress(addr).Hex(),
+		AccountNumber: accountNumber,
+		Sequence:      sequence,
+		Nonce:         nonce,
+		Explanation:   AccountNonceExplanation,
+	}
+}
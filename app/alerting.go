@@ -0,0 +1,444 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/spf13/cast"
+
+	"cosmossdk.io/log"
+	storetypes "cosmossdk.io/store/types"
+	upgradetypes "cosmossdk.io/x/upgrade/types"
+
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+
+	selfbondtypes "github.com/Asphere-xyz/tacchain/x/selfbond/types"
+)
+
+const (
+	FlagAlertingWebhookURL    = "alerting.webhook-url"
+	FlagAlertingEvents        = "alerting.events"
+	FlagAlertingMinPeers      = "alerting.min-peers"
+	FlagAlertingStallTimeout  = "alerting.stall-timeout"
+	FlagAlertingCheckInterval = "alerting.check-interval"
+)
+
+// Events the node alerts on
+const (
+	AlertValidatorJailed  = "validator_jailed"
+	AlertUpgradeScheduled = "upgrade_scheduled"
+	AlertLowPeers         = "low_peers"
+	AlertBlockStall       = "block_stall"
+	AlertTxEvicted        = "tx_evicted"
+)
+
+// AlertEvents are the events the node can alert on
+var AlertEvents = []string{AlertValidatorJailed, AlertUpgradeScheduled, AlertLowPeers, AlertBlockStall, AlertTxEvicted}
+
+// DefaultAlertEvents are the events alerted on by default, the evicted txs
+// are only alerted on when asked for since every tx evicted is alerted
+var DefaultAlertEvents = []string{AlertValidatorJailed, AlertUpgradeScheduled, AlertLowPeers, AlertBlockStall}
+
+// DefaultAlertingConfigTemplate defines the app.toml section of the operator alerts
+const DefaultAlertingConfigTemplate = `
+###############################################################################
+###                          Alerting Configuration                         ###
+###############################################################################
+
+[alerting]
+
+# URL the node POSTs a JSON alert to when one of the events below occurs, empty
+# disables alerting. An alert has the fields event, chain_id, height, time, message
+# and details, it is sent once per occurrence and not retried.
+webhook-url = "{{ .Alerting.WebhookURL }}"
+
+# Events to alert on:
+# - validator_jailed: a validator was jailed for downtime, double signing or a self
+#   bond below the min self bond
+# - upgrade_scheduled: a software upgrade was scheduled
+# - low_peers: the node has fewer than min-peers peers
+# - block_stall: the node committed no block for stall-timeout
+# - tx_evicted: a tx was evicted from the mempool of the node after the tx-ttl-blocks
+#   or the tx-ttl of the tx limits, alerted for every tx evicted
+events = [{{ range $i, $event := .Alerting.Events }}{{ if $i }}, {{ end }}"{{ $event }}"{{ end }}]
+
+# Number of peers below which low_peers is alerted.
+min-peers = {{ .Alerting.MinPeers }}
+
+# Time without a committed block after which block_stall is alerted.
+stall-timeout = "{{ .Alerting.StallTimeout }}"
+
+# Interval of the peer count and block stall checks. The checks run once the node
+# serves the gRPC or the API server, which gives them access to its CometBFT node.
+check-interval = "{{ .Alerting.CheckInterval }}"
+`
+
+// AlertingConfig configures the webhook alerts of the node
+type AlertingConfig struct {
+	WebhookURL    string        `mapstructure:"webhook-url"`
+	Events        []string      `mapstructure:"events"`
+	MinPeers      uint64        `mapstructure:"min-peers"`
+	StallTimeout  time.Duration `mapstructure:"stall-timeout"`
+	CheckInterval time.Duration `mapstructure:"check-interval"`
+}
+
+// DefaultAlertingConfig returns the default alerting config, alerting is
+// disabled until a webhook URL is set
+func DefaultAlertingConfig() AlertingConfig {
+	return AlertingConfig{
+		Events:        DefaultAlertEvents,
+		MinPeers:      1,
+		StallTimeout:  time.Minute,
+		CheckInterval: 10 * time.Second,
+	}
+}
+
+// AlertingConfigFromAppOptions reads the alerting config of the node
+func AlertingConfigFromAppOptions(appOpts servertypes.AppOptions) AlertingConfig {
+	return AlertingConfig{
+		WebhookURL:    cast.ToString(appOpts.Get(FlagAlertingWebhookURL)),
+		Events:        cast.ToStringSlice(appOpts.Get(FlagAlertingEvents)),
+		MinPeers:      cast.ToUint64(appOpts.Get(FlagAlertingMinPeers)),
+		StallTimeout:  cast.ToDuration(appOpts.Get(FlagAlertingStallTimeout)),
+		CheckInterval: cast.ToDuration(appOpts.Get(FlagAlertingCheckInterval)),
+	}
+}
+
+// Validate checks the webhook URL and the events of an enabled config
+func (c AlertingConfig) Validate() error {
+	if c.WebhookURL == "" {
+		return nil
+	}
+	u, err := url.Parse(c.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid alerting webhook url %q", c.WebhookURL)
+	}
+	for _, event := range c.Events {
+		known := false
+		for _, e := range AlertEvents {
+			known = known || e == event
+		}
+		if !known {
+			return fmt.Errorf("unknown alerting event %q, the events are %v", event, AlertEvents)
+		}
+	}
+	return nil
+}
+
+// Alert is the JSON body the node POSTs to the webhook
+type Alert struct {
+	Event   string            `json:"event"`
+	ChainID string            `json:"chain_id"`
+	Height  int64             `json:"height"`
+	Time    time.Time         `json:"time"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+const (
+	// alertQueueSize bounds the alerts waiting to be sent, alerts are dropped
+	// when the webhook can't keep up
+	alertQueueSize = 100
+	// alertTimeout bounds the time a webhook call takes
+	alertTimeout = 10 * time.Second
+)
+
+// UpgradePlanReader reads the scheduled software upgrade
+type UpgradePlanReader interface {
+	GetUpgradePlan(ctx context.Context) (upgradetypes.Plan, error)
+}
+
+// netInfoClient is the part of the CometBFT RPC client reporting the peers
+type netInfoClient interface {
+	NetInfo(ctx context.Context) (*coretypes.ResultNetInfo, error)
+}
+
+// Alerter sends the alerts of the node to the webhook. It is an ABCI listener
+// spotting the jails in the events of the blocks and the upgrade plans in the
+// committed state, the peer count and block stall checks run once started
+// with the CometBFT node. Alerts are sent in the background, the webhook never
+// delays blocks.
+type Alerter struct {
+	cfg     AlertingConfig
+	events  map[string]bool
+	chainID string
+	upgrade UpgradePlanReader
+	logger  log.Logger
+	client  *http.Client
+
+	alerts    chan Alert
+	done      chan struct{}
+	closeOnce sync.Once
+	startOnce sync.Once
+
+	mtx        sync.Mutex
+	height     int64
+	lastCommit time.Time
+	// plan is the last upgrade plan alerted, stalled and lowPeers are true
+	// while the condition alerted lasts, so it is alerted once
+	plan     string
+	stalled  bool
+	lowPeers bool
+}
+
+var _ storetypes.ABCIListener = (*Alerter)(nil)
+
+// NewAlerter returns the alerter of cfg, it must be closed
+func NewAlerter(cfg AlertingConfig, chainID string, upgrade UpgradePlanReader, logger log.Logger) (*Alerter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	a := &Alerter{
+		cfg:        cfg,
+		events:     map[string]bool{},
+		chainID:    chainID,
+		upgrade:    upgrade,
+		logger:     logger.With("module", "alerting"),
+		client:     &http.Client{Timeout: alertTimeout},
+		alerts:     make(chan Alert, alertQueueSize),
+		done:       make(chan struct{}),
+		lastCommit: time.Now(),
+	}
+	for _, event := range cfg.Events {
+		a.events[event] = true
+	}
+	go a.run()
+	return a, nil
+}
+
+// Close stops the alerter, the alerts not sent yet are dropped
+func (a *Alerter) Close() {
+	a.closeOnce.Do(func() { close(a.done) })
+}
+
+// ListenFinalizeBlock implements storetypes.ABCIListener.
+func (a *Alerter) ListenFinalizeBlock(_ context.Context, req abci.RequestFinalizeBlock, res abci.ResponseFinalizeBlock) error {
+	for _, event := range res.Events {
+		validator, reason, ok := jailedValidator(event)
+		if !ok {
+			continue
+		}
+		a.send(Alert{
+			Event:   AlertValidatorJailed,
+			Height:  req.Height,
+			Message: fmt.Sprintf("validator %s was jailed: %s", validator, reason),
+			Details: map[string]string{"validator": validator, "reason": reason},
+		})
+	}
+	return nil
+}
+
+// ListenCommit implements storetypes.ABCIListener.
+func (a *Alerter) ListenCommit(ctx context.Context, _ abci.ResponseCommit, _ []*storetypes.StoreKVPair) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	height := sdkCtx.BlockHeight()
+
+	a.mtx.Lock()
+	a.height = height
+	a.lastCommit = time.Now()
+	a.stalled = false
+	a.mtx.Unlock()
+
+	// the state of the block is committed, its context reads it
+	plan, err := a.upgrade.GetUpgradePlan(ctx)
+	if errors.Is(err, upgradetypes.ErrNoUpgradePlanFound) {
+		return nil
+	}
+	if err != nil {
+		a.logger.Error("failed to read the upgrade plan", "height", height, "err", err)
+		return nil
+	}
+	key := plan.Name + "@" + strconv.FormatInt(plan.Height, 10)
+
+	a.mtx.Lock()
+	scheduled := key != a.plan
+	a.plan = key
+	a.mtx.Unlock()
+	if scheduled {
+		a.send(Alert{
+			Event:   AlertUpgradeScheduled,
+			Height:  height,
+			Message: fmt.Sprintf("upgrade %s is scheduled at height %d", plan.Name, plan.Height),
+			Details: map[string]string{"name": plan.Name, "height": strconv.FormatInt(plan.Height, 10), "info": plan.Info},
+		})
+	}
+	return nil
+}
+
+// TxEvicted alerts on a tx evicted from the mempool, see MempoolTTL.OnEvict
+func (a *Alerter) TxEvicted(eviction TxEviction) {
+	details := map[string]string{
+		"hash":   eviction.Hash,
+		"reason": eviction.Reason,
+		"blocks": strconv.FormatInt(eviction.Blocks, 10),
+		"age":    eviction.Age.String(),
+	}
+	if eviction.EVMHash != "" {
+		details["evm_hash"] = eviction.EVMHash
+		details["sender"] = eviction.Sender
+		details["nonce"] = strconv.FormatUint(eviction.Nonce, 10)
+	}
+	a.send(Alert{
+		Event:   AlertTxEvicted,
+		Height:  eviction.Height,
+		Message: fmt.Sprintf("tx %s was evicted from the mempool after %d blocks and %s", eviction.Hash, eviction.Blocks, eviction.Age),
+		Details: details,
+	})
+}
+
+// StartChecks starts the peer count and block stall checks, every check
+// interval until the alerter is closed. client is the RPC client of the
+// CometBFT node, the peers are only checked if it reports them.
+func (a *Alerter) StartChecks(client any) {
+	if a.cfg.CheckInterval <= 0 {
+		return
+	}
+	netInfo, _ := client.(netInfoClient)
+	a.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(a.cfg.CheckInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-a.done:
+					return
+				case now := <-ticker.C:
+					a.check(netInfo, now)
+				}
+			}
+		}()
+	})
+}
+
+// check alerts when the peer count falls below the min peers or when no
+// block was committed for the stall timeout
+func (a *Alerter) check(netInfo netInfoClient, now time.Time) {
+	if netInfo != nil && a.events[AlertLowPeers] {
+		ctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+		res, err := netInfo.NetInfo(ctx)
+		cancel()
+		if err != nil {
+			a.logger.Error("failed to read the peers of the node", "err", err)
+		} else {
+			low := uint64(res.NPeers) < a.cfg.MinPeers
+			a.mtx.Lock()
+			alert := low && !a.lowPeers
+			a.lowPeers = low
+			height := a.height
+			a.mtx.Unlock()
+			if alert {
+				a.send(Alert{
+					Event:   AlertLowPeers,
+					Height:  height,
+					Message: fmt.Sprintf("the node has %d peers, fewer than %d", res.NPeers, a.cfg.MinPeers),
+					Details: map[string]string{"peers": strconv.Itoa(res.NPeers), "min_peers": strconv.FormatUint(a.cfg.MinPeers, 10)},
+				})
+			}
+		}
+	}
+
+	if a.cfg.StallTimeout > 0 {
+		a.mtx.Lock()
+		since := now.Sub(a.lastCommit)
+		alert := since >= a.cfg.StallTimeout && !a.stalled
+		a.stalled = a.stalled || alert
+		height := a.height
+		a.mtx.Unlock()
+		if alert {
+			a.send(Alert{
+				Event:   AlertBlockStall,
+				Height:  height,
+				Message: fmt.Sprintf("no block was committed since height %d %s ago", height, since.Truncate(time.Second)),
+				Details: map[string]string{"since": since.Truncate(time.Second).String()},
+			})
+		}
+	}
+}
+
+// send queues alert unless its event isn't alerted on
+func (a *Alerter) send(alert Alert) {
+	if !a.events[alert.Event] {
+		return
+	}
+	alert.ChainID = a.chainID
+	alert.Time = time.Now().UTC()
+	select {
+	case a.alerts <- alert:
+	default:
+		a.logger.Error("dropped alert, the webhook is too slow", "event", alert.Event, "message", alert.Message)
+	}
+}
+
+func (a *Alerter) run() {
+	for {
+		select {
+		case <-a.done:
+			return
+		case alert := <-a.alerts:
+			if err := a.post(alert); err != nil {
+				a.logger.Error("failed to send alert", "event", alert.Event, "message", alert.Message, "err", err)
+			}
+		}
+	}
+}
+
+func (a *Alerter) post(alert Alert) error {
+	bz, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	res, err := a.client.Post(a.cfg.WebhookURL, "application/json", bytes.NewReader(bz))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered %s", res.Status)
+	}
+	return nil
+}
+
+// jailedValidator returns the validator jailed and the reason if event
+// reports a jail. x/slashing reports the jails for downtime and double signing
+// with the consensus address of the validator, x/selfbond the jails below the
+// min self bond with its operator address.
+func jailedValidator(event abci.Event) (string, string, bool) {
+	switch event.Type {
+	case slashingtypes.EventTypeSlash:
+		var validator, reason string
+		for _, attr := range event.Attributes {
+			switch attr.Key {
+			case slashingtypes.AttributeKeyJailed:
+				validator = attr.Value
+			case slashingtypes.AttributeKeyReason:
+				reason = attr.Value
+			}
+		}
+		// the jail for double signing has its own event, after the slash
+		if reason == "" {
+			reason = slashingtypes.AttributeValueDoubleSign
+		}
+		return validator, reason, validator != ""
+	case proto.MessageName(&selfbondtypes.EventJailBelowMinSelfBond{}):
+		msg, err := sdk.ParseTypedEvent(event)
+		if err != nil {
+			return "", "", false
+		}
+		jail := msg.(*selfbondtypes.EventJailBelowMinSelfBond)
+		return jail.Validator, fmt.Sprintf("self bond %s below the min self bond %s", jail.SelfBond, jail.MinSelfBond), true
+	}
+	return "", "", false
+}
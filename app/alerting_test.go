@@ -0,0 +1,251 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	upgradetypes "cosmossdk.io/x/upgrade/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+
+	selfbondtypes "github.com/Asphere-xyz/tacchain/x/selfbond/types"
+)
+
+// fakeUpgradePlan is the upgrade plan of the committed state, if any
+type fakeUpgradePlan struct {
+	plan *upgradetypes.Plan
+}
+
+func (f *fakeUpgradePlan) GetUpgradePlan(context.Context) (upgradetypes.Plan, error) {
+	if f.plan == nil {
+		return upgradetypes.Plan{}, upgradetypes.ErrNoUpgradePlanFound
+	}
+	return *f.plan, nil
+}
+
+// fakeNetInfo reports peers peers
+type fakeNetInfo struct {
+	peers int
+}
+
+func (f *fakeNetInfo) NetInfo(context.Context) (*coretypes.ResultNetInfo, error) {
+	return &coretypes.ResultNetInfo{NPeers: f.peers}, nil
+}
+
+// newTestAlerter returns an alerter of the events posting to a test webhook
+// and the channel of the alerts the webhook receives
+func newTestAlerter(t *testing.T, events ...string) (*Alerter, *fakeUpgradePlan, <-chan Alert) {
+	t.Helper()
+
+	alerts := make(chan Alert, alertQueueSize)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert Alert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		alerts <- alert
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := DefaultAlertingConfig()
+	cfg.WebhookURL = srv.URL
+	cfg.Events = events
+	upgrade := &fakeUpgradePlan{}
+	a, err := NewAlerter(cfg, DefaultChainID, upgrade, log.NewNopLogger())
+	require.NoError(t, err)
+	t.Cleanup(a.Close)
+	return a, upgrade, alerts
+}
+
+// nextAlert returns the next alert the webhook receives
+func nextAlert(t *testing.T, alerts <-chan Alert) Alert {
+	t.Helper()
+	select {
+	case alert := <-alerts:
+		return alert
+	case <-time.After(5 * time.Second):
+		t.Fatal("no alert received")
+		return Alert{}
+	}
+}
+
+func alerterCommit(t *testing.T, a *Alerter, height int64) {
+	t.Helper()
+	ctx := sdk.Context{}.WithContext(context.Background()).WithBlockHeight(height)
+	require.NoError(t, a.ListenCommit(ctx, abci.ResponseCommit{}, nil))
+}
+
+func TestAlertingConfigValidate(t *testing.T) {
+	cfg := DefaultAlertingConfig()
+	require.NoError(t, cfg.Validate(), "a disabled config is valid")
+
+	cfg.WebhookURL = "https://alerts.example.com/tacchain"
+	require.NoError(t, cfg.Validate())
+
+	cfg.Events = []string{AlertLowPeers, "validator_slashed"}
+	require.ErrorContains(t, cfg.Validate(), `unknown alerting event "validator_slashed"`)
+
+	cfg.Events = AlertEvents
+	for _, webhookURL := range []string{"alerts.example.com", "ftp://alerts.example.com", "http://"} {
+		cfg.WebhookURL = webhookURL
+		require.ErrorContains(t, cfg.Validate(), "invalid alerting webhook url", webhookURL)
+	}
+}
+
+func TestAlertValidatorJailed(t *testing.T) {
+	a, _, alerts := newTestAlerter(t, AlertEvents...)
+
+	selfBondJail, err := sdk.TypedEventToEvent(&selfbondtypes.EventJailBelowMinSelfBond{
+		Validator:   "tacvaloper1operator",
+		SelfBond:    "999",
+		MinSelfBond: "1000",
+	})
+	require.NoError(t, err)
+	events := []abci.Event{
+		// a slash without jail
+		{Type: slashingtypes.EventTypeSlash, Attributes: []abci.EventAttribute{
+			{Key: slashingtypes.AttributeKeyAddress, Value: "tacvalcons1doublesign"},
+			{Key: slashingtypes.AttributeKeyReason, Value: slashingtypes.AttributeValueDoubleSign},
+		}},
+		// the jail for double signing
+		{Type: slashingtypes.EventTypeSlash, Attributes: []abci.EventAttribute{
+			{Key: slashingtypes.AttributeKeyJailed, Value: "tacvalcons1doublesign"},
+		}},
+		{Type: slashingtypes.EventTypeSlash, Attributes: []abci.EventAttribute{
+			{Key: slashingtypes.AttributeKeyAddress, Value: "tacvalcons1downtime"},
+			{Key: slashingtypes.AttributeKeyReason, Value: slashingtypes.AttributeValueMissingSignature},
+			{Key: slashingtypes.AttributeKeyJailed, Value: "tacvalcons1downtime"},
+		}},
+		abci.Event(selfBondJail),
+		{Type: "transfer"},
+	}
+	require.NoError(t, a.ListenFinalizeBlock(context.Background(), abci.RequestFinalizeBlock{Height: 10}, abci.ResponseFinalizeBlock{Events: events}))
+
+	alert := nextAlert(t, alerts)
+	require.Equal(t, AlertValidatorJailed, alert.Event)
+	require.Equal(t, DefaultChainID, alert.ChainID)
+	require.Equal(t, int64(10), alert.Height)
+	require.Equal(t, map[string]string{"validator": "tacvalcons1doublesign", "reason": slashingtypes.AttributeValueDoubleSign}, alert.Details)
+
+	alert = nextAlert(t, alerts)
+	require.Equal(t, map[string]string{"validator": "tacvalcons1downtime", "reason": slashingtypes.AttributeValueMissingSignature}, alert.Details)
+
+	alert = nextAlert(t, alerts)
+	require.Equal(t, "tacvaloper1operator", alert.Details["validator"])
+	require.Equal(t, "validator tacvaloper1operator was jailed: self bond 999 below the min self bond 1000", alert.Message)
+}
+
+func TestAlertUpgradeScheduled(t *testing.T) {
+	a, upgrade, alerts := newTestAlerter(t, AlertEvents...)
+
+	alerterCommit(t, a, 1)
+	upgrade.plan = &upgradetypes.Plan{Name: "v0.0.13", Height: 100, Info: "binaries"}
+	alerterCommit(t, a, 2)
+	alert := nextAlert(t, alerts)
+	require.Equal(t, AlertUpgradeScheduled, alert.Event)
+	require.Equal(t, int64(2), alert.Height)
+	require.Equal(t, "upgrade v0.0.13 is scheduled at height 100", alert.Message)
+	require.Equal(t, map[string]string{"name": "v0.0.13", "height": "100", "info": "binaries"}, alert.Details)
+
+	// a plan is alerted once, a new plan is alerted again
+	alerterCommit(t, a, 3)
+	upgrade.plan = &upgradetypes.Plan{Name: "v0.0.13", Height: 120}
+	alerterCommit(t, a, 4)
+	alert = nextAlert(t, alerts)
+	require.Equal(t, int64(4), alert.Height)
+	require.Equal(t, "120", alert.Details["height"])
+}
+
+func TestAlertLowPeersAndBlockStall(t *testing.T) {
+	a, _, alerts := newTestAlerter(t, AlertLowPeers, AlertBlockStall)
+	netInfo := &fakeNetInfo{peers: 1}
+	alerterCommit(t, a, 5)
+	now := time.Now()
+
+	// nothing to alert
+	a.check(netInfo, now)
+
+	netInfo.peers = 0
+	a.check(netInfo, now)
+	alert := nextAlert(t, alerts)
+	require.Equal(t, AlertLowPeers, alert.Event)
+	require.Equal(t, int64(5), alert.Height)
+	require.Equal(t, map[string]string{"peers": "0", "min_peers": "1"}, alert.Details)
+
+	// the peer count is alerted again once it recovered and fell again, the
+	// stall once blocks resumed and stalled again
+	a.check(netInfo, now)
+	netInfo.peers = 1
+	a.check(netInfo, now)
+	netInfo.peers = 0
+	a.check(netInfo, now)
+	require.Equal(t, AlertLowPeers, nextAlert(t, alerts).Event)
+
+	a.check(netInfo, now.Add(2*time.Minute))
+	alert = nextAlert(t, alerts)
+	require.Equal(t, AlertBlockStall, alert.Event)
+	require.Equal(t, int64(5), alert.Height)
+	a.check(netInfo, now.Add(3*time.Minute))
+
+	alerterCommit(t, a, 6)
+	a.check(netInfo, time.Now().Add(2*time.Minute))
+	alert = nextAlert(t, alerts)
+	require.Equal(t, AlertBlockStall, alert.Event)
+	require.Equal(t, int64(6), alert.Height)
+}
+
+func TestAlertTxEvicted(t *testing.T) {
+	require.NotContains(t, DefaultAlertingConfig().Events, AlertTxEvicted, "the evicted txs should be alerted on when asked for")
+	a, _, alerts := newTestAlerter(t, AlertTxEvicted)
+
+	a.TxEvicted(TxEviction{
+		Hash:    "4B5A",
+		EVMHash: "0x4b5a",
+		Sender:  "0x1111111111111111111111111111111111111111",
+		Nonce:   7,
+		Reason:  EvictionReasonBlocks,
+		Height:  42,
+		Blocks:  10,
+		Age:     time.Minute,
+	})
+	alert := nextAlert(t, alerts)
+	require.Equal(t, AlertTxEvicted, alert.Event)
+	require.Equal(t, int64(42), alert.Height)
+	require.Equal(t, "tx 4B5A was evicted from the mempool after 10 blocks and 1m0s", alert.Message)
+	require.Equal(t, map[string]string{
+		"hash":     "4B5A",
+		"reason":   EvictionReasonBlocks,
+		"blocks":   "10",
+		"age":      "1m0s",
+		"evm_hash": "0x4b5a",
+		"sender":   "0x1111111111111111111111111111111111111111",
+		"nonce":    "7",
+	}, alert.Details)
+}
+
+func TestAlertEventsFilter(t *testing.T) {
+	a, upgrade, alerts := newTestAlerter(t, AlertBlockStall)
+
+	// the jails, upgrades and peers aren't alerted on
+	jail := abci.Event{Type: slashingtypes.EventTypeSlash, Attributes: []abci.EventAttribute{
+		{Key: slashingtypes.AttributeKeyJailed, Value: "tacvalcons1downtime"},
+	}}
+	require.NoError(t, a.ListenFinalizeBlock(context.Background(), abci.RequestFinalizeBlock{Height: 1}, abci.ResponseFinalizeBlock{Events: []abci.Event{jail}}))
+	upgrade.plan = &upgradetypes.Plan{Name: "v0.0.13", Height: 100}
+	alerterCommit(t, a, 1)
+	a.check(&fakeNetInfo{peers: 0}, time.Now())
+
+	a.check(nil, time.Now().Add(time.Hour))
+	require.Equal(t, AlertBlockStall, nextAlert(t, alerts).Event)
+}
@@ -8,10 +8,12 @@ import (
 	"github.com/cosmos/ibc-go/v8/modules/core/keeper"
 
 	corestoretypes "cosmossdk.io/core/store"
+	errorsmod "cosmossdk.io/errors"
 	circuitante "cosmossdk.io/x/circuit/ante"
 	circuitkeeper "cosmossdk.io/x/circuit/keeper"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	errortypes "github.com/cosmos/cosmos-sdk/types/errors"
 	authante "github.com/cosmos/cosmos-sdk/x/auth/ante"
 	sdkvesting "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
 
@@ -37,6 +39,13 @@ type HandlerOptions struct {
 	FeeMarketKeeper evmanteinterfaces.FeeMarketKeeper
 	EvmKeeper       evmanteinterfaces.EVMKeeper
 	MaxTxGasWanted  uint64
+
+	TxLimits          TxLimitsConfig
+	CommittedSequence AccountSequenceFunc
+	EVMTxReplacements *EVMTxReplacements
+	MempoolTTL        *MempoolTTL
+	GasProfile        GasProfileConfig
+	ContractGasKeeper ContractGasKeeper
 }
 
 // NewAnteHandler returns an ante handler responsible for attempting to route an
@@ -62,6 +71,9 @@ func NewAnteHandler(options HandlerOptions) (sdk.AnteHandler, error) {
 	if options.EvmKeeper == nil {
 		return nil, errors.New("evm keeper is required for ante builder")
 	}
+	if options.ContractGasKeeper == nil {
+		return nil, errors.New("contract gas keeper is required for ante builder")
+	}
 
 	return func(
 		ctx sdk.Context, tx sdk.Tx, sim bool,
@@ -75,14 +87,21 @@ func NewAnteHandler(options HandlerOptions) (sdk.AnteHandler, error) {
 				switch typeURL := opts[0].GetTypeUrl(); typeURL {
 				case "/cosmos.evm.vm.v1.ExtensionOptionsEthereumTx":
 					// handle as *evmtypes.MsgEthereumTx
-					anteHandler = sdk.ChainAnteDecorators(
+					anteHandler = sdk.ChainAnteDecorators(profileAnteDecorators(options.GasProfile,
+						NewSharedSequenceDecorator(),
+						NewTxLimitDecorator(options.TxLimits),
+						NewEVMTxReplacementDecorator(options.AccountKeeper, options.CommittedSequence, options.EVMTxReplacements),
+						NewMempoolTTLDecorator(options.MempoolTTL),
+						NewPendingTxLimitDecorator(options.AccountKeeper, options.CommittedSequence, options.TxLimits),
+						NewReplayProtectionDecorator(options.TxLimits, evmChainID),
+						NewContractGasDecorator(options.ContractGasKeeper),
 						evmante.NewEVMMonoDecorator(
 							options.AccountKeeper,
 							options.FeeMarketKeeper,
 							options.EvmKeeper,
 							options.MaxTxGasWanted,
 						),
-					)
+					)...)
 				case "/cosmos.evm.types.v1.ExtensionOptionDynamicFeeTx":
 					// cosmos-sdk tx with dynamic fee extension
 					anteHandler, err = newCosmosAnteHandler(options)
@@ -115,13 +134,19 @@ func NewAnteHandler(options HandlerOptions) (sdk.AnteHandler, error) {
 }
 
 func newCosmosAnteHandler(options HandlerOptions) (sdk.AnteHandler, error) {
-	return sdk.ChainAnteDecorators(
+	return sdk.ChainAnteDecorators(profileAnteDecorators(options.GasProfile,
 		evmcosmosante.NewRejectMessagesDecorator(), // reject MsgEthereumTxs
 		evmcosmosante.NewAuthzLimiterDecorator( // disable the Msg types that cannot be included on an authz.MsgExec msgs field
 			sdk.MsgTypeURL(&evmtypes.MsgEthereumTx{}),
 			sdk.MsgTypeURL(&sdkvesting.MsgCreateVestingAccount{}),
 		),
 		authante.NewSetUpContextDecorator(),
+		// the TAC decorators run on the gas meter of the tx, so its gas wanted is
+		// reported with their errors, and in the order of the EVM chain
+		NewSharedSequenceDecorator(),
+		NewTxLimitDecorator(options.TxLimits),
+		NewMempoolTTLDecorator(options.MempoolTTL),
+		NewPendingTxLimitDecorator(options.AccountKeeper, options.CommittedSequence, options.TxLimits),
 		circuitante.NewCircuitBreakerDecorator(options.CircuitKeeper),
 		authante.NewExtensionOptionsDecorator(options.ExtensionOptionChecker),
 		authante.NewValidateBasicDecorator(),
@@ -138,5 +163,26 @@ func newCosmosAnteHandler(options HandlerOptions) (sdk.AnteHandler, error) {
 		authante.NewIncrementSequenceDecorator(options.AccountKeeper),
 		ibcante.NewRedundantRelayDecorator(options.IBCKeeper),
 		evmante.NewGasWantedDecorator(options.EvmKeeper, options.FeeMarketKeeper),
-	), nil
+	)...), nil
+}
+
+// sharedSequenceHint is added to sequence and nonce errors, clients that only
+// track one kind of tx easily miss that the other kind used up the value.
+const sharedSequenceHint = "the Cosmos sequence and the EVM nonce of an account are the same counter, incremented by txs of both kinds"
+
+// SharedSequenceDecorator annotates the account sequence errors of the Cosmos
+// ante handler and the nonce errors of the EVM ante handler, since both check
+// and increment the same counter of the account. The error codes are kept.
+type SharedSequenceDecorator struct{}
+
+func NewSharedSequenceDecorator() SharedSequenceDecorator {
+	return SharedSequenceDecorator{}
+}
+
+func (SharedSequenceDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	newCtx, err := next(ctx, tx, simulate)
+	if errors.Is(err, errortypes.ErrWrongSequence) || errors.Is(err, errortypes.ErrInvalidSequence) {
+		return newCtx, errorsmod.Wrap(err, sharedSequenceHint)
+	}
+	return newCtx, err
 }
@@ -0,0 +1,359 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	protov2 "google.golang.org/protobuf/proto"
+
+	errorsmod "cosmossdk.io/errors"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	errortypes "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+	evmanteinterfaces "github.com/cosmos/evm/ante/interfaces"
+	evmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+func TestSharedSequenceDecorator(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		wantHint bool
+	}{
+		{"no error", nil, false},
+		{"cosmos sequence mismatch", errorsmod.Wrapf(errortypes.ErrWrongSequence, "account sequence mismatch, expected %d, got %d", 2, 1), true},
+		{"evm nonce mismatch", errorsmod.Wrapf(errortypes.ErrInvalidSequence, "invalid nonce; got %d, expected %d", 1, 2), true},
+		{"other error", errorsmod.Wrap(errortypes.ErrInsufficientFunds, "insufficient funds"), false},
+		{"plain error", errors.New("failed"), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			next := func(ctx sdk.Context, _ sdk.Tx, _ bool) (sdk.Context, error) {
+				return ctx, tc.err
+			}
+
+			_, err := NewSharedSequenceDecorator().AnteHandle(sdk.Context{}, nil, false, next)
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, tc.err)
+			require.Contains(t, err.Error(), tc.err.Error())
+			if !tc.wantHint {
+				require.Equal(t, tc.err.Error(), err.Error())
+				return
+			}
+
+			require.Contains(t, err.Error(), sharedSequenceHint)
+			// clients match on the error code, it must not change
+			wantCodespace, wantCode, _ := errorsmod.ABCIInfo(tc.err, false)
+			codespace, code, _ := errorsmod.ABCIInfo(err, false)
+			require.Equal(t, wantCodespace, codespace)
+			require.Equal(t, wantCode, code)
+		})
+	}
+}
+
+// limitTestTx is a Cosmos tx with the given gas, msgs and signers
+type limitTestTx struct {
+	gas     uint64
+	msgs    []sdk.Msg
+	signers [][]byte
+}
+
+func (tx limitTestTx) GetMsgs() []sdk.Msg                    { return tx.msgs }
+func (tx limitTestTx) GetMsgsV2() ([]protov2.Message, error) { return nil, nil }
+func (tx limitTestTx) GetGas() uint64                        { return tx.gas }
+func (tx limitTestTx) GetFee() sdk.Coins                     { return nil }
+func (tx limitTestTx) FeePayer() []byte                      { return nil }
+func (tx limitTestTx) FeeGranter() []byte                    { return nil }
+
+func (tx limitTestTx) GetSigners() ([][]byte, error)                   { return tx.signers, nil }
+func (tx limitTestTx) GetPubKeys() ([]cryptotypes.PubKey, error)       { return nil, nil }
+func (tx limitTestTx) GetSignaturesV2() ([]signing.SignatureV2, error) { return nil, nil }
+
+func TestTxLimitDecorator(t *testing.T) {
+	limits := TxLimitsConfig{MaxTxBytes: 1000, MaxGasWanted: 500000}
+	ethTx := func(gas uint64) sdk.Msg {
+		return evmtypes.NewTx(&evmtypes.EvmTxArgs{
+			ChainID:  big.NewInt(2391),
+			GasLimit: gas,
+			GasPrice: big.NewInt(1),
+			Amount:   big.NewInt(0),
+		})
+	}
+	blockParams := cmtproto.ConsensusParams{Block: &cmtproto.BlockParams{MaxBytes: 200000, MaxGas: 1000000}}
+
+	testCases := []struct {
+		name     string
+		limits   TxLimitsConfig
+		checkTx  bool
+		simulate bool
+		txBytes  int
+		tx       sdk.Tx
+		expErr   error
+	}{
+		{"within limits", limits, true, false, 1000, limitTestTx{gas: 500000}, nil},
+		{"cosmos tx too large", limits, true, false, 1001, limitTestTx{gas: 200000}, errortypes.ErrTxTooLarge},
+		{"cosmos tx wants too much gas", limits, true, false, 100, limitTestTx{gas: 500001}, errortypes.ErrInvalidGasLimit},
+		{"evm tx within limits", limits, true, false, 100, limitTestTx{gas: 1, msgs: []sdk.Msg{ethTx(500000)}}, nil},
+		{"evm tx wants too much gas", limits, true, false, 100, limitTestTx{gas: 1, msgs: []sdk.Msg{ethTx(500001)}}, errortypes.ErrInvalidGasLimit},
+		{"evm tx too large", limits, true, false, 1001, limitTestTx{msgs: []sdk.Msg{ethTx(21000)}}, errortypes.ErrTxTooLarge},
+		{"no size limit", TxLimitsConfig{MaxGasWanted: 500000}, true, false, 100000, limitTestTx{gas: 200000}, nil},
+		{"larger than a block", TxLimitsConfig{MaxTxBytes: DefaultMaxTxBytes}, true, false, 200001, limitTestTx{gas: 200000}, errortypes.ErrTxTooLarge},
+		{"gas limited by block max gas", TxLimitsConfig{}, true, false, 100, limitTestTx{gas: 1000001}, errortypes.ErrInvalidGasLimit},
+		{"within block max gas", TxLimitsConfig{}, true, false, 100, limitTestTx{gas: 1000000}, nil},
+		{"not enforced in blocks", limits, false, false, 1001, limitTestTx{gas: 500001}, nil},
+		{"not enforced in simulations", limits, true, true, 1001, limitTestTx{gas: 500001}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := sdk.Context{}.
+				WithIsCheckTx(tc.checkTx).
+				WithTxBytes(make([]byte, tc.txBytes)).
+				WithConsensusParams(blockParams)
+
+			called := false
+			next := func(ctx sdk.Context, _ sdk.Tx, _ bool) (sdk.Context, error) {
+				called = true
+				return ctx, nil
+			}
+
+			_, err := NewTxLimitDecorator(tc.limits).AnteHandle(ctx, tc.tx, tc.simulate, next)
+			if tc.expErr != nil {
+				require.ErrorIs(t, err, tc.expErr)
+				require.False(t, called)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, called)
+		})
+	}
+}
+
+// sequenceAccountKeeper returns accounts with the given check state sequences
+type sequenceAccountKeeper struct {
+	evmanteinterfaces.AccountKeeper
+
+	sequences map[string]uint64
+}
+
+func (ak sequenceAccountKeeper) GetAccount(_ context.Context, addr sdk.AccAddress) sdk.AccountI {
+	seq, ok := ak.sequences[addr.String()]
+	if !ok {
+		return nil
+	}
+	return authtypes.NewBaseAccount(addr, nil, 1, seq)
+}
+
+func TestPendingTxLimitDecorator(t *testing.T) {
+	sender := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	newAccount := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	limits := TxLimitsConfig{MaxPendingCosmosTxs: 3, MaxPendingEVMTxs: 2}
+	ethTx := evmtypes.NewTx(&evmtypes.EvmTxArgs{
+		ChainID:  big.NewInt(2391),
+		GasLimit: 21000,
+		GasPrice: big.NewInt(1),
+		Amount:   big.NewInt(0),
+	})
+
+	committed := func(addr sdk.AccAddress) (uint64, error) {
+		if addr.Equals(sender) {
+			return 10, nil
+		}
+		return 0, errors.New("not found")
+	}
+
+	testCases := []struct {
+		name      string
+		checkSeq  uint64
+		reCheckTx bool
+		tx        limitTestTx
+		expErr    bool
+	}{
+		{"nothing pending", 10, false, limitTestTx{signers: [][]byte{sender}}, false},
+		{"below cosmos limit", 12, false, limitTestTx{signers: [][]byte{sender}}, false},
+		{"cosmos limit reached", 13, false, limitTestTx{signers: [][]byte{sender}}, true},
+		{"below evm limit", 11, false, limitTestTx{msgs: []sdk.Msg{ethTx}, signers: [][]byte{sender}}, false},
+		{"evm limit reached", 12, false, limitTestTx{msgs: []sdk.Msg{ethTx}, signers: [][]byte{sender}}, true},
+		{"not enforced on recheck", 13, true, limitTestTx{signers: [][]byte{sender}}, false},
+		{"account not committed yet", 10, false, limitTestTx{signers: [][]byte{newAccount}}, false},
+		{"any signer over the limit", 13, false, limitTestTx{signers: [][]byte{newAccount, sender}}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ak := sequenceAccountKeeper{sequences: map[string]uint64{
+				sender.String():     tc.checkSeq,
+				newAccount.String(): 5,
+			}}
+			ctx := sdk.Context{}.WithIsCheckTx(true).WithIsReCheckTx(tc.reCheckTx)
+
+			called := false
+			next := func(ctx sdk.Context, _ sdk.Tx, _ bool) (sdk.Context, error) {
+				called = true
+				return ctx, nil
+			}
+
+			_, err := NewPendingTxLimitDecorator(ak, committed, limits).AnteHandle(ctx, tc.tx, false, next)
+			if tc.expErr {
+				require.ErrorIs(t, err, errortypes.ErrMempoolIsFull)
+				require.False(t, called)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, called)
+		})
+	}
+
+	// the limits are not enforced when executing blocks
+	ak := sequenceAccountKeeper{sequences: map[string]uint64{sender.String(): 20}}
+	_, err := NewPendingTxLimitDecorator(ak, committed, limits).AnteHandle(sdk.Context{}, limitTestTx{signers: [][]byte{sender}}, false,
+		func(ctx sdk.Context, _ sdk.Tx, _ bool) (sdk.Context, error) { return ctx, nil })
+	require.NoError(t, err)
+}
+
+func TestReplayProtectionDecorator(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID, otherChainID := big.NewInt(2391), big.NewInt(1)
+	to := common.HexToAddress("0xdead")
+
+	signed := func(signer ethtypes.Signer, txData ethtypes.TxData) limitTestTx {
+		tx, err := ethtypes.SignNewTx(key, signer, txData)
+		require.NoError(t, err)
+		msg := &evmtypes.MsgEthereumTx{}
+		require.NoError(t, msg.FromEthereumTx(tx))
+		return limitTestTx{msgs: []sdk.Msg{msg}}
+	}
+	legacy := func() *ethtypes.LegacyTx {
+		return &ethtypes.LegacyTx{To: &to, Gas: 21000, GasPrice: big.NewInt(1), Value: big.NewInt(0)}
+	}
+	accessList := func(chainID *big.Int) *ethtypes.AccessListTx {
+		return &ethtypes.AccessListTx{ChainID: chainID, To: &to, Gas: 21000, GasPrice: big.NewInt(1), Value: big.NewInt(0)}
+	}
+	dynamicFee := func(chainID *big.Int) *ethtypes.DynamicFeeTx {
+		return &ethtypes.DynamicFeeTx{ChainID: chainID, To: &to, Gas: 21000, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1), Value: big.NewInt(0)}
+	}
+	reject := TxLimitsConfig{RejectUnprotectedEVMTxs: true}
+
+	testCases := []struct {
+		name     string
+		limits   TxLimitsConfig
+		checkTx  bool
+		simulate bool
+		tx       sdk.Tx
+		expErr   error
+	}{
+		{"legacy", reject, true, false, signed(ethtypes.NewEIP155Signer(chainID), legacy()), nil},
+		{"access list", reject, true, false, signed(ethtypes.LatestSignerForChainID(chainID), accessList(chainID)), nil},
+		{"dynamic fee", reject, true, false, signed(ethtypes.LatestSignerForChainID(chainID), dynamicFee(chainID)), nil},
+		{"cosmos tx", reject, true, false, limitTestTx{}, nil},
+		{"unprotected legacy", reject, true, false, signed(ethtypes.HomesteadSigner{}, legacy()), errortypes.ErrNotSupported},
+		{"unprotected legacy allowed", TxLimitsConfig{}, true, false, signed(ethtypes.HomesteadSigner{}, legacy()), nil},
+		{"legacy for another chain", TxLimitsConfig{}, true, false, signed(ethtypes.NewEIP155Signer(otherChainID), legacy()), errortypes.ErrInvalidChainID},
+		{"access list for another chain", reject, true, false, signed(ethtypes.LatestSignerForChainID(otherChainID), accessList(otherChainID)), errortypes.ErrInvalidChainID},
+		{"dynamic fee for another chain", reject, true, false, signed(ethtypes.LatestSignerForChainID(otherChainID), dynamicFee(otherChainID)), errortypes.ErrInvalidChainID},
+		{"not enforced in blocks", reject, false, false, signed(ethtypes.HomesteadSigner{}, legacy()), nil},
+		{"not enforced in simulations", reject, true, true, signed(ethtypes.LatestSignerForChainID(otherChainID), dynamicFee(otherChainID)), nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			next := func(ctx sdk.Context, _ sdk.Tx, _ bool) (sdk.Context, error) {
+				called = true
+				return ctx, nil
+			}
+
+			decorator := NewReplayProtectionDecorator(tc.limits, func() *big.Int { return chainID })
+			_, err := decorator.AnteHandle(sdk.Context{}.WithIsCheckTx(tc.checkTx), tc.tx, tc.simulate, next)
+			if tc.expErr != nil {
+				require.ErrorIs(t, err, tc.expErr)
+				require.False(t, called)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, called)
+		})
+	}
+}
+
+// capKeeper caps the calls to contract at maxGas
+type capKeeper struct {
+	contract common.Address
+	maxGas   uint64
+}
+
+func (k capKeeper) CheckCallGas(_ sdk.Context, to common.Address, gasLimit uint64) error {
+	if to == k.contract && gasLimit > k.maxGas {
+		return errortypes.ErrOutOfGas
+	}
+	return nil
+}
+
+func TestContractGasDecorator(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := ethtypes.LatestSignerForChainID(big.NewInt(2391))
+	contract, other := common.HexToAddress("0xc0"), common.HexToAddress("0xdead")
+
+	ethTx := func(to *common.Address, gas uint64) limitTestTx {
+		tx, err := ethtypes.SignNewTx(key, signer, &ethtypes.DynamicFeeTx{
+			ChainID: big.NewInt(2391), To: to, Gas: gas, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1), Value: big.NewInt(0),
+		})
+		require.NoError(t, err)
+		msg := &evmtypes.MsgEthereumTx{}
+		require.NoError(t, msg.FromEthereumTx(tx))
+		return limitTestTx{msgs: []sdk.Msg{msg}}
+	}
+
+	testCases := []struct {
+		name    string
+		checkTx bool
+		tx      sdk.Tx
+		expErr  bool
+	}{
+		{"call at the cap", true, ethTx(&contract, 100_000), false},
+		{"call above the cap", true, ethTx(&contract, 100_001), true},
+		{"call above the cap in a block", false, ethTx(&contract, 100_001), true},
+		{"call to another address", true, ethTx(&other, 1_000_000), false},
+		{"contract creation", true, ethTx(nil, 1_000_000), false},
+		{"cosmos tx", true, limitTestTx{gas: 1_000_000}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			next := func(ctx sdk.Context, _ sdk.Tx, _ bool) (sdk.Context, error) {
+				called = true
+				return ctx, nil
+			}
+
+			decorator := NewContractGasDecorator(capKeeper{contract: contract, maxGas: 100_000})
+			_, err := decorator.AnteHandle(sdk.Context{}.WithIsCheckTx(tc.checkTx), tc.tx, false, next)
+			if tc.expErr {
+				require.ErrorIs(t, err, errortypes.ErrOutOfGas)
+				require.False(t, called)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, called)
+		})
+	}
+}
@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"io"
 	"maps"
+	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
+	"time"
 
 	// Force-load the tracer engines to trigger registration due to Go-Ethereum v1.10.15 changes
 	_ "github.com/ethereum/go-ethereum/eth/tracers/js"
@@ -75,6 +79,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/server/api"
 	"github.com/cosmos/cosmos-sdk/server/config"
 	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+	"github.com/cosmos/cosmos-sdk/telemetry"
 	testdata_pulsar "github.com/cosmos/cosmos-sdk/testutil/testdata/testpb"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/module"
@@ -85,7 +90,6 @@ import (
 	authante "github.com/cosmos/cosmos-sdk/x/auth/ante"
 	authcodec "github.com/cosmos/cosmos-sdk/x/auth/codec"
 	authkeeper "github.com/cosmos/cosmos-sdk/x/auth/keeper"
-	"github.com/cosmos/cosmos-sdk/x/auth/posthandler"
 	authsims "github.com/cosmos/cosmos-sdk/x/auth/simulation"
 	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
 	authtxconfig "github.com/cosmos/cosmos-sdk/x/auth/tx/config"
@@ -114,6 +118,7 @@ import (
 	govkeeper "github.com/cosmos/cosmos-sdk/x/gov/keeper"
 	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
 	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govv1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
 	"github.com/cosmos/cosmos-sdk/x/group"
 	groupkeeper "github.com/cosmos/cosmos-sdk/x/group/keeper"
 	groupmodule "github.com/cosmos/cosmos-sdk/x/group/module"
@@ -124,6 +129,7 @@ import (
 	paramsclient "github.com/cosmos/cosmos-sdk/x/params/client"
 	paramskeeper "github.com/cosmos/cosmos-sdk/x/params/keeper"
 	paramstypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	paramproposal "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
 	"github.com/cosmos/cosmos-sdk/x/slashing"
 	slashingkeeper "github.com/cosmos/cosmos-sdk/x/slashing/keeper"
 	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
@@ -152,6 +158,59 @@ import (
 	evmibctransferkeeper "github.com/cosmos/evm/x/ibc/transfer/keeper"
 	evmvmkeeper "github.com/cosmos/evm/x/vm/keeper"
 	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	"github.com/Asphere-xyz/tacchain/app/upgrades"
+	"github.com/Asphere-xyz/tacchain/x/accountactivity"
+	accountactivitykeeper "github.com/Asphere-xyz/tacchain/x/accountactivity/keeper"
+	accountactivitytypes "github.com/Asphere-xyz/tacchain/x/accountactivity/types"
+	"github.com/Asphere-xyz/tacchain/x/autocompound"
+	autocompoundkeeper "github.com/Asphere-xyz/tacchain/x/autocompound/keeper"
+	autocompoundtypes "github.com/Asphere-xyz/tacchain/x/autocompound/types"
+	"github.com/Asphere-xyz/tacchain/x/bridge"
+	bridgekeeper "github.com/Asphere-xyz/tacchain/x/bridge/keeper"
+	bridgetypes "github.com/Asphere-xyz/tacchain/x/bridge/types"
+	"github.com/Asphere-xyz/tacchain/x/contractgas"
+	contractgaskeeper "github.com/Asphere-xyz/tacchain/x/contractgas/keeper"
+	contractgastypes "github.com/Asphere-xyz/tacchain/x/contractgas/types"
+	"github.com/Asphere-xyz/tacchain/x/contractmeta"
+	contractmetakeeper "github.com/Asphere-xyz/tacchain/x/contractmeta/keeper"
+	contractmetatypes "github.com/Asphere-xyz/tacchain/x/contractmeta/types"
+	"github.com/Asphere-xyz/tacchain/x/emission"
+	emissionkeeper "github.com/Asphere-xyz/tacchain/x/emission/keeper"
+	emissiontypes "github.com/Asphere-xyz/tacchain/x/emission/types"
+	"github.com/Asphere-xyz/tacchain/x/evmupgrade"
+	evmupgradekeeper "github.com/Asphere-xyz/tacchain/x/evmupgrade/keeper"
+	evmupgradetypes "github.com/Asphere-xyz/tacchain/x/evmupgrade/types"
+	"github.com/Asphere-xyz/tacchain/x/feeburn"
+	feeburnkeeper "github.com/Asphere-xyz/tacchain/x/feeburn/keeper"
+	feeburntypes "github.com/Asphere-xyz/tacchain/x/feeburn/types"
+	"github.com/Asphere-xyz/tacchain/x/feehistory"
+	feehistorykeeper "github.com/Asphere-xyz/tacchain/x/feehistory/keeper"
+	feehistorytypes "github.com/Asphere-xyz/tacchain/x/feehistory/types"
+	"github.com/Asphere-xyz/tacchain/x/feerouting"
+	feeroutingkeeper "github.com/Asphere-xyz/tacchain/x/feerouting/keeper"
+	feeroutingtypes "github.com/Asphere-xyz/tacchain/x/feerouting/types"
+	"github.com/Asphere-xyz/tacchain/x/ibchooks"
+	ibchookskeeper "github.com/Asphere-xyz/tacchain/x/ibchooks/keeper"
+	ibchookstypes "github.com/Asphere-xyz/tacchain/x/ibchooks/types"
+	"github.com/Asphere-xyz/tacchain/x/nameservice"
+	nameservicekeeper "github.com/Asphere-xyz/tacchain/x/nameservice/keeper"
+	nameservicetypes "github.com/Asphere-xyz/tacchain/x/nameservice/types"
+	"github.com/Asphere-xyz/tacchain/x/performance"
+	performancekeeper "github.com/Asphere-xyz/tacchain/x/performance/keeper"
+	performancetypes "github.com/Asphere-xyz/tacchain/x/performance/types"
+	"github.com/Asphere-xyz/tacchain/x/recovery"
+	recoverykeeper "github.com/Asphere-xyz/tacchain/x/recovery/keeper"
+	recoverytypes "github.com/Asphere-xyz/tacchain/x/recovery/types"
+	"github.com/Asphere-xyz/tacchain/x/rewardsnapshot"
+	rewardsnapshotkeeper "github.com/Asphere-xyz/tacchain/x/rewardsnapshot/keeper"
+	rewardsnapshottypes "github.com/Asphere-xyz/tacchain/x/rewardsnapshot/types"
+	"github.com/Asphere-xyz/tacchain/x/selfbond"
+	selfbondkeeper "github.com/Asphere-xyz/tacchain/x/selfbond/keeper"
+	selfbondtypes "github.com/Asphere-xyz/tacchain/x/selfbond/types"
+	"github.com/Asphere-xyz/tacchain/x/tacgov"
+	tacgovkeeper "github.com/Asphere-xyz/tacchain/x/tacgov/keeper"
+	tacgovtypes "github.com/Asphere-xyz/tacchain/x/tacgov/types"
 )
 
 // module account permissions
@@ -171,6 +230,13 @@ var maccPerms = map[string][]string{
 	evmvmtypes.ModuleName:        {authtypes.Minter, authtypes.Burner},
 	evmfeemarkettypes.ModuleName: nil,
 	evmerc20types.ModuleName:     {authtypes.Minter, authtypes.Burner},
+	// Tac modules
+	emissiontypes.ModuleName: {authtypes.Minter},
+	feeburntypes.ModuleName:  {authtypes.Burner},
+	// mints the TON assets deposited on TON and burns them as they are released
+	bridgetypes.ModuleName: {authtypes.Minter, authtypes.Burner},
+	// grantee of the delegators' authorizations to delegate, holds no funds
+	autocompoundtypes.ModuleName: nil,
 }
 
 var (
@@ -233,10 +299,51 @@ type TacChainApp struct {
 	// module configurator
 	configurator module.Configurator
 
+	// node home dir, the application database and its backend, and the
+	// backup of the database taken before upgrade handlers run
+	homePath     string
+	appDB        dbm.DB
+	appDBBackend dbm.BackendType
+	backupConfig upgrades.BackupConfig
+
+	// background compaction of the application database
+	compactor *compactor
+	// index of the EVM logs by address and topic, nil if disabled
+	logIndex *LogIndex
+	receipts *ReceiptStore
+	// EVM txs replacing pending txs of the mempool, nil if disabled
+	evmTxReplacements *EVMTxReplacements
+	// ages of the txs of the mempool, nil if the tx ttls are disabled
+	mempoolTTL *MempoolTTL
+	// webhook alerts of the operator, nil if disabled
+	alerter *Alerter
+	// queries in flight, drained for the grace period of the shutdown
+	queryDrain     *queryDrain
+	shutdownConfig ShutdownConfig
+
 	// Cosmos EVM keepers
 	FeeMarketKeeper evmfeemarketkeeper.Keeper
 	EVMKeeper       *evmvmkeeper.Keeper
 	Erc20Keeper     evmerc20keeper.Keeper
+
+	// Tac keepers
+	RecoveryKeeper        recoverykeeper.Keeper
+	IBCHooksKeeper        ibchookskeeper.Keeper
+	EmissionKeeper        emissionkeeper.Keeper
+	FeeBurnKeeper         feeburnkeeper.Keeper
+	PerformanceKeeper     performancekeeper.Keeper
+	AutoCompoundKeeper    autocompoundkeeper.Keeper
+	SelfBondKeeper        selfbondkeeper.Keeper
+	EVMUpgradeKeeper      evmupgradekeeper.Keeper
+	ContractMetaKeeper    contractmetakeeper.Keeper
+	BridgeKeeper          bridgekeeper.Keeper
+	ContractGasKeeper     contractgaskeeper.Keeper
+	TacGovKeeper          tacgovkeeper.Keeper
+	RewardSnapshotKeeper  rewardsnapshotkeeper.Keeper
+	AccountActivityKeeper accountactivitykeeper.Keeper
+	FeeHistoryKeeper      feehistorykeeper.Keeper
+	FeeRoutingKeeper      feeroutingkeeper.Keeper
+	NameServiceKeeper     nameservicekeeper.Keeper
 }
 
 // NewTacChainApp returns a reference to an initialized TacChainApp.
@@ -286,6 +393,15 @@ func NewTacChainApp(
 	bApp.SetInterfaceRegistry(encodingConfig.InterfaceRegistry)
 	bApp.SetTxEncoder(encodingConfig.TxConfig.TxEncoder())
 
+	// include the txs the chain depends on ahead of the fee competition,
+	// without the EVM txs replaced in the mempool
+	evmTxReplacements := NewEVMTxReplacements(TxLimitsConfigFromAppOptions(appOpts).EVMPriceBump, encodingConfig.TxConfig.TxDecoder())
+	bApp.SetPrepareProposal(evmTxReplacementsHandler(evmTxReplacements, priorityLanesHandler(
+		PriorityLanesConfigFromAppOptions(appOpts),
+		encodingConfig.TxConfig.TxDecoder(),
+		baseapp.NewDefaultProposalHandler(bApp.Mempool(), bApp).PrepareProposalHandler(),
+	)))
+
 	// initialize the Cosmos EVM application configuration
 	if err := evmAppOptions(bApp.ChainID()); err != nil {
 		panic(err)
@@ -302,6 +418,10 @@ func NewTacChainApp(
 		icahosttypes.StoreKey, icacontrollertypes.StoreKey,
 		// Cosmos EVM store keys
 		evmvmtypes.StoreKey, evmfeemarkettypes.StoreKey, evmerc20types.StoreKey,
+		// Tac store keys
+		recoverytypes.StoreKey, performancetypes.StoreKey, autocompoundtypes.StoreKey, contractmetatypes.StoreKey,
+		bridgetypes.StoreKey, tacgovtypes.StoreKey, rewardsnapshottypes.StoreKey, accountactivitytypes.StoreKey,
+		feehistorytypes.StoreKey, feeroutingtypes.StoreKey, nameservicetypes.StoreKey, selfbondtypes.StoreKey,
 	)
 
 	tkeys := storetypes.NewTransientStoreKeys(paramstypes.TStoreKey, evmvmtypes.TransientKey, evmfeemarkettypes.TransientKey)
@@ -312,6 +432,22 @@ func NewTacChainApp(
 		panic(err)
 	}
 
+	// report the writes of each module store and the gas utilization of the
+	// last blocks along with the telemetry of the node
+	if cast.ToBool(appOpts.Get(flagTelemetryEnabled)) {
+		listenKeys := make([]storetypes.StoreKey, 0, len(keys))
+		for _, key := range keys {
+			listenKeys = append(listenKeys, key)
+		}
+		bApp.CommitMultiStore().AddListeners(listenKeys)
+
+		streamingManager := bApp.StreamingManager()
+		streamingManager.ABCIListeners = append(streamingManager.ABCIListeners,
+			newStoreMetrics(filepath.Join(cast.ToString(appOpts.Get(flags.FlagHome)), "data", "application.db")),
+			newGasUtilizationMetrics(GasUtilizationWindow))
+		bApp.SetStreamingManager(streamingManager)
+	}
+
 	app := &TacChainApp{
 		BaseApp:           bApp,
 		legacyAmino:       encodingConfig.Amino,
@@ -321,6 +457,8 @@ func NewTacChainApp(
 		keys:              keys,
 		tkeys:             tkeys,
 		memKeys:           memKeys,
+		queryDrain:        newQueryDrain(),
+		shutdownConfig:    ShutdownConfigFromAppOptions(appOpts),
 	}
 
 	app.ParamsKeeper = initParamsKeeper(
@@ -376,6 +514,8 @@ func NewTacChainApp(
 		authAddr,
 		logger,
 	)
+	// prevent users from locking funds in module accounts that can't pay them out
+	app.BankKeeper.AppendSendRestriction(ModuleAccountSendRestriction(ReceivableModuleAccounts))
 
 	// optional: enable sign mode textual by overwriting the default tx config (after setting the bank keeper)
 	enabledSignModes := append(authtx.DefaultSignModes, signingtypes.SignMode_SIGN_MODE_TEXTUAL)
@@ -422,11 +562,19 @@ func NewTacChainApp(
 		authAddr,
 	)
 
+	app.SelfBondKeeper = selfbondkeeper.NewKeeper(
+		runtime.NewKVStoreService(keys[selfbondtypes.StoreKey]),
+		app.GetSubspace(selfbondtypes.ModuleName),
+		app.StakingKeeper,
+		stakingkeeper.NewQuerier(app.StakingKeeper),
+	)
+
+	// validators can't unjail below the min self bond of x/selfbond
 	app.SlashingKeeper = slashingkeeper.NewKeeper(
 		encodingConfig.Codec,
 		encodingConfig.Amino,
 		runtime.NewKVStoreService(keys[slashingtypes.StoreKey]),
-		app.StakingKeeper,
+		selfbondkeeper.NewUnjailStakingKeeper(app.StakingKeeper, app.SelfBondKeeper),
 		authAddr,
 	)
 
@@ -445,7 +593,7 @@ func NewTacChainApp(
 	// register the staking hooks
 	// NOTE: stakingKeeper above is passed by reference, so that it will contain these hooks
 	app.StakingKeeper.SetHooks(
-		stakingtypes.NewMultiStakingHooks(app.DistrKeeper.Hooks(), app.SlashingKeeper.Hooks()),
+		stakingtypes.NewMultiStakingHooks(app.DistrKeeper.Hooks(), app.SlashingKeeper.Hooks(), app.SelfBondKeeper.Hooks()),
 	)
 
 	app.CircuitKeeper = circuitkeeper.NewKeeper(
@@ -483,6 +631,43 @@ func NewTacChainApp(
 		skipUpgradeHeights[int64(h)] = true
 	}
 	homePath := cast.ToString(appOpts.Get(flags.FlagHome))
+	app.homePath = homePath
+	app.appDB = db
+	app.appDBBackend = server.GetAppDBBackend(appOpts)
+	app.backupConfig = upgrades.BackupConfigFromAppOptions(appOpts, homePath)
+	app.compactor = newCompactor(db, CompactionConfigFromAppOptions(appOpts), logger)
+	if logIndexConfig := LogIndexConfigFromAppOptions(appOpts); logIndexConfig.Enabled {
+		logIndex, err := OpenLogIndex(filepath.Join(homePath, "data"), server.GetAppDBBackend(appOpts), logIndexConfig, logger)
+		if err != nil {
+			panic(err)
+		}
+		app.logIndex = logIndex
+		streamingManager := app.StreamingManager()
+		streamingManager.ABCIListeners = append(streamingManager.ABCIListeners, logIndex)
+		app.SetStreamingManager(streamingManager)
+	}
+	if receiptsConfig := ReceiptsConfigFromAppOptions(appOpts); receiptsConfig.Compact {
+		receipts, err := OpenReceiptStore(filepath.Join(homePath, "data"), server.GetAppDBBackend(appOpts), receiptsConfig, app.txConfig.TxDecoder(), logger)
+		if err != nil {
+			panic(err)
+		}
+		app.receipts = receipts
+		streamingManager := app.StreamingManager()
+		streamingManager.ABCIListeners = append(streamingManager.ABCIListeners, receipts)
+		app.SetStreamingManager(streamingManager)
+	}
+	if evmTxReplacements != nil {
+		app.evmTxReplacements = evmTxReplacements
+		streamingManager := app.StreamingManager()
+		streamingManager.ABCIListeners = append(streamingManager.ABCIListeners, evmTxReplacements)
+		app.SetStreamingManager(streamingManager)
+	}
+	if mempoolTTL := NewMempoolTTL(TxLimitsConfigFromAppOptions(appOpts), logger); mempoolTTL != nil {
+		app.mempoolTTL = mempoolTTL
+		streamingManager := app.StreamingManager()
+		streamingManager.ABCIListeners = append(streamingManager.ABCIListeners, mempoolTTL)
+		app.SetStreamingManager(streamingManager)
+	}
 	// set the governance module account as the authority for conducting upgrades
 	app.UpgradeKeeper = upgradekeeper.NewKeeper(
 		skipUpgradeHeights,
@@ -492,6 +677,19 @@ func NewTacChainApp(
 		app.BaseApp,
 		authAddr,
 	)
+	if alertingConfig := AlertingConfigFromAppOptions(appOpts); alertingConfig.WebhookURL != "" {
+		alerter, err := NewAlerter(alertingConfig, bApp.ChainID(), app.UpgradeKeeper, logger)
+		if err != nil {
+			panic(err)
+		}
+		app.alerter = alerter
+		if app.mempoolTTL != nil {
+			app.mempoolTTL.OnEvict(alerter.TxEvicted)
+		}
+		streamingManager := app.StreamingManager()
+		streamingManager.ABCIListeners = append(streamingManager.ABCIListeners, alerter)
+		app.SetStreamingManager(streamingManager)
+	}
 
 	app.IBCKeeper = ibckeeper.NewKeeper(
 		encodingConfig.Codec,
@@ -513,13 +711,21 @@ func NewTacChainApp(
 		runtime.NewKVStoreService(keys[govtypes.StoreKey]),
 		app.AccountKeeper,
 		app.BankKeeper,
-		app.StakingKeeper,
+		// tallies count the stake held through the voting weight contract of x/tacgov
+		tacgovkeeper.NewTallyStakingKeeper(app.StakingKeeper, &app.TacGovKeeper),
 		app.DistrKeeper,
 		app.MsgServiceRouter(),
 		govConfig,
 		authAddr,
 	)
 
+	// register the legacy proposal types, param changes are used to manage the Tac module params
+	// and are validated against the whole param set of each Tac module
+	govRouter := govv1beta1.NewRouter()
+	govRouter.AddRoute(govtypes.RouterKey, govv1beta1.ProposalHandler).
+		AddRoute(paramproposal.RouterKey, NewParamChangeProposalHandler(app.ParamsKeeper))
+	govKeeper.SetLegacyRouter(govRouter)
+
 	app.GovKeeper = *govKeeper.SetHooks(
 		govtypes.NewMultiGovHooks(
 		// register the governance hooks
@@ -583,26 +789,26 @@ func NewTacChainApp(
 		&app.TransferKeeper,
 	)
 
+	// IBC Fee Module keeper
+	app.IBCFeeKeeper = ibcfeekeeper.NewKeeper(
+		encodingConfig.Codec, keys[ibcfeetypes.StoreKey],
+		app.IBCKeeper.ChannelKeeper, // may be replaced with IBC middleware
+		app.IBCKeeper.ChannelKeeper,
+		app.IBCKeeper.PortKeeper, app.AccountKeeper, app.BankKeeper,
+	)
+
 	// instantiate IBC transfer keeper AFTER the ERC-20 keeper to use it in the instantiation
 	app.TransferKeeper = evmibctransferkeeper.NewKeeper(
 		encodingConfig.Codec,
 		keys[ibctransfertypes.StoreKey],
 		app.GetSubspace(ibctransfertypes.ModuleName),
-		nil, // we are passing no ics4 wrapper
+		app.IBCFeeKeeper, // use ics29 fee as ics4Wrapper in middleware stack
 		app.IBCKeeper.ChannelKeeper, app.IBCKeeper.PortKeeper,
 		app.AccountKeeper, app.BankKeeper, app.ScopedTransferKeeper,
 		app.Erc20Keeper, // Add ERC20 Keeper for ERC20 transfers
 		authAddr,
 	)
 
-	// IBC Fee Module keeper
-	app.IBCFeeKeeper = ibcfeekeeper.NewKeeper(
-		encodingConfig.Codec, keys[ibcfeetypes.StoreKey],
-		app.IBCKeeper.ChannelKeeper, // may be replaced with IBC middleware
-		app.IBCKeeper.ChannelKeeper,
-		app.IBCKeeper.PortKeeper, app.AccountKeeper, app.BankKeeper,
-	)
-
 	app.ICAHostKeeper = icahostkeeper.NewKeeper(
 		encodingConfig.Codec,
 		keys[icahosttypes.StoreKey],
@@ -650,10 +856,25 @@ func NewTacChainApp(
 	icaHostStack = icahost.NewIBCModule(app.ICAHostKeeper)
 	icaHostStack = ibcfee.NewIBCMiddleware(icaHostStack, app.IBCFeeKeeper)
 
+	app.IBCHooksKeeper = ibchookskeeper.NewKeeper(
+		app.GetSubspace(ibchookstypes.ModuleName),
+		app.EVMKeeper,
+		app.BankKeeper,
+		&app.Erc20Keeper,
+		BaseDenom,
+	)
+
 	// Create Transfer Stack
+	// SendPacket, since it is originating from the application to core IBC:
+	// transferKeeper.SendPacket -> fee.SendPacket -> channel.SendPacket
+	//
+	// RecvPacket, message that originates from core IBC and goes down to app, the flow is:
+	// channel.RecvPacket -> fee.OnRecvPacket -> ibchooks.OnRecvPacket -> erc20.OnRecvPacket -> transfer.OnRecvPacket
 	var transferStack porttypes.IBCModule
 	transferStack = evmibctransfer.NewIBCModule(app.TransferKeeper)
 	transferStack = evmerc20.NewIBCMiddleware(app.Erc20Keeper, transferStack)
+	transferStack = ibchooks.NewIBCMiddleware(transferStack, app.IBCHooksKeeper)
+	transferStack = ibcfee.NewIBCMiddleware(transferStack, app.IBCFeeKeeper)
 
 	// Create static IBC router, add app routes, then set and seal it
 	ibcRouter := porttypes.NewRouter().
@@ -662,22 +883,127 @@ func NewTacChainApp(
 		AddRoute(icahosttypes.SubModuleName, icaHostStack)
 	app.IBCKeeper.SetRouter(ibcRouter)
 
+	// the name resolver precompile reads the names of x/nameservice, its
+	// keeper is created along with the precompiles
+	app.NameServiceKeeper = nameservicekeeper.NewKeeper(
+		runtime.NewKVStoreService(keys[nameservicetypes.StoreKey]),
+		app.GetSubspace(nameservicetypes.ModuleName),
+		app.DistrKeeper,
+	)
+
 	// NOTE: we are adding all available Cosmos EVM EVM extensions.
 	// Not all of them need to be enabled, which can be configured on a per-chain basis.
-	app.EVMKeeper.WithStaticPrecompiles(
-		evmd.NewAvailableStaticPrecompiles(
-			*app.StakingKeeper,
-			app.DistrKeeper,
-			app.BankKeeper,
-			app.Erc20Keeper,
-			app.AuthzKeeper,
-			app.TransferKeeper,
-			app.IBCKeeper.ChannelKeeper,
-			app.EVMKeeper,
-			app.GovKeeper,
-			app.SlashingKeeper,
-			app.EvidenceKeeper,
+	// The distribution precompile only lets a delegator set its own withdraw
+	// address, see DistributionPrecompile. The name resolver precompile is
+	// added to them, see NameResolverPrecompile.
+	app.EVMKeeper.WithStaticPrecompiles(withNameResolverPrecompile(
+		withDistributionPrecompile(
+			evmd.NewAvailableStaticPrecompiles(
+				*app.StakingKeeper,
+				app.DistrKeeper,
+				app.BankKeeper,
+				app.Erc20Keeper,
+				app.AuthzKeeper,
+				app.TransferKeeper,
+				app.IBCKeeper.ChannelKeeper,
+				app.EVMKeeper,
+				app.GovKeeper,
+				app.SlashingKeeper,
+				app.EvidenceKeeper,
+			),
 		),
+		app.NameServiceKeeper,
+	))
+
+	// Tac keepers
+	app.RecoveryKeeper = recoverykeeper.NewKeeper(
+		runtime.NewKVStoreService(keys[recoverytypes.StoreKey]),
+		app.GetSubspace(recoverytypes.ModuleName),
+		app.BankKeeper,
+	)
+	app.EmissionKeeper = emissionkeeper.NewKeeper(
+		app.GetSubspace(emissiontypes.ModuleName),
+		app.BankKeeper,
+		authtypes.FeeCollectorName,
+	)
+	app.FeeBurnKeeper = feeburnkeeper.NewKeeper(
+		app.GetSubspace(feeburntypes.ModuleName),
+		app.BankKeeper,
+		app.FeeMarketKeeper,
+		authtypes.FeeCollectorName,
+		BaseDenom,
+	)
+	app.PerformanceKeeper = performancekeeper.NewKeeper(
+		runtime.NewKVStoreService(keys[performancetypes.StoreKey]),
+		app.GetSubspace(performancetypes.ModuleName),
+	)
+	app.RewardSnapshotKeeper = rewardsnapshotkeeper.NewKeeper(
+		runtime.NewKVStoreService(keys[rewardsnapshottypes.StoreKey]),
+		app.GetSubspace(rewardsnapshottypes.ModuleName),
+		app.StakingKeeper,
+		app.DistrKeeper,
+	)
+	app.AccountActivityKeeper = accountactivitykeeper.NewKeeper(
+		runtime.NewKVStoreService(keys[accountactivitytypes.StoreKey]),
+		app.GetSubspace(accountactivitytypes.ModuleName),
+	)
+	app.FeeHistoryKeeper = feehistorykeeper.NewKeeper(
+		runtime.NewKVStoreService(keys[feehistorytypes.StoreKey]),
+		app.GetSubspace(feehistorytypes.ModuleName),
+		app.FeeMarketKeeper,
+	)
+	app.FeeRoutingKeeper = feeroutingkeeper.NewKeeper(
+		runtime.NewKVStoreService(keys[feeroutingtypes.StoreKey]),
+		app.GetSubspace(feeroutingtypes.ModuleName),
+		app.BankKeeper,
+		app.StakingKeeper,
+		app.DistrKeeper,
+		app.FeeMarketKeeper,
+		app.RewardSnapshotKeeper,
+		authtypes.FeeCollectorName,
+		BaseDenom,
+	)
+	// the provisions minted and the fees burnt count in the protocol revenue
+	app.EmissionKeeper.SetHooks(app.FeeRoutingKeeper.Hooks())
+	app.FeeBurnKeeper.SetHooks(app.FeeRoutingKeeper.Hooks())
+	app.AutoCompoundKeeper = autocompoundkeeper.NewKeeper(
+		runtime.NewKVStoreService(keys[autocompoundtypes.StoreKey]),
+		app.GetSubspace(autocompoundtypes.ModuleName),
+		app.AuthzKeeper,
+		app.StakingKeeper,
+		app.DistrKeeper,
+	)
+	app.EVMUpgradeKeeper = evmupgradekeeper.NewKeeper(
+		app.GetSubspace(evmupgradetypes.ModuleName),
+		app.EVMKeeper,
+	)
+	app.ContractMetaKeeper = contractmetakeeper.NewKeeper(
+		runtime.NewKVStoreService(keys[contractmetatypes.StoreKey]),
+		app.GetSubspace(contractmetatypes.ModuleName),
+		app.EVMKeeper,
+	)
+	app.BridgeKeeper = bridgekeeper.NewKeeper(
+		runtime.NewKVStoreService(keys[bridgetypes.StoreKey]),
+		app.GetSubspace(bridgetypes.ModuleName),
+		app.BankKeeper,
+		app.StakingKeeper,
+		app.DistrKeeper,
+	)
+	app.ContractGasKeeper = contractgaskeeper.NewKeeper(
+		app.GetSubspace(contractgastypes.ModuleName),
+		app.EVMKeeper,
+	)
+	skipHaltHeights := map[int64]bool{}
+	for _, h := range cast.ToIntSlice(appOpts.Get(tacgov.FlagUnsafeSkipHaltHeights)) {
+		skipHaltHeights[int64(h)] = true
+	}
+	app.TacGovKeeper = tacgovkeeper.NewKeeper(
+		skipHaltHeights,
+		runtime.NewKVStoreService(keys[tacgovtypes.StoreKey]),
+		app.GetSubspace(tacgovtypes.ModuleName),
+		app.StakingKeeper,
+		app.EVMKeeper,
+		&app.GovKeeper,
 	)
 
 	/****  Module Options ****/
@@ -725,6 +1051,24 @@ func NewTacChainApp(
 		vm.NewAppModule(app.EVMKeeper, app.AccountKeeper, app.GetSubspace(evmvmtypes.ModuleName)),
 		feemarket.NewAppModule(app.FeeMarketKeeper, app.GetSubspace(evmfeemarkettypes.ModuleName)),
 		evmerc20.NewAppModule(app.Erc20Keeper, app.AccountKeeper, app.GetSubspace(evmerc20types.ModuleName)),
+		// Tac modules
+		recovery.NewAppModule(app.RecoveryKeeper),
+		ibchooks.NewAppModule(app.IBCHooksKeeper),
+		emission.NewAppModule(app.EmissionKeeper),
+		feeburn.NewAppModule(app.FeeBurnKeeper),
+		performance.NewAppModule(app.PerformanceKeeper),
+		autocompound.NewAppModule(app.AutoCompoundKeeper),
+		selfbond.NewAppModule(app.SelfBondKeeper),
+		evmupgrade.NewAppModule(app.EVMUpgradeKeeper),
+		contractmeta.NewAppModule(app.ContractMetaKeeper),
+		bridge.NewAppModule(app.BridgeKeeper),
+		contractgas.NewAppModule(app.ContractGasKeeper),
+		tacgov.NewAppModule(app.TacGovKeeper),
+		rewardsnapshot.NewAppModule(app.RewardSnapshotKeeper),
+		accountactivity.NewAppModule(app.AccountActivityKeeper),
+		feehistory.NewAppModule(app.FeeHistoryKeeper),
+		feerouting.NewAppModule(app.FeeRoutingKeeper),
+		nameservice.NewAppModule(app.NameServiceKeeper),
 	)
 
 	// BasicModuleManager defines the module BasicManager is in charge of setting up basic,
@@ -758,14 +1102,31 @@ func NewTacChainApp(
 	// NOTE: capability module's beginblocker must come before any modules using capabilities (e.g. IBC)
 	app.ModuleManager.SetOrderBeginBlockers(
 		capabilitytypes.ModuleName,
+		// fails the block at the halt height set by governance before any
+		// module runs it
+		tacgovtypes.ModuleName,
+		// fees are burnt before x/distribution hands out the rest along with the
+		// provision of the emission schedule, which mints in place of x/mint
+		feeburntypes.ModuleName,
+		emissiontypes.ModuleName,
+		// records the community tax x/distribution takes on them
+		feeroutingtypes.ModuleName,
 		distrtypes.ModuleName,
+		// adds the rewards x/distribution handed out in the block to the epoch
+		rewardsnapshottypes.ModuleName,
 		stakingtypes.ModuleName,
 		slashingtypes.ModuleName,
+		// counts the signatures of the last commit like x/slashing
+		performancetypes.ModuleName,
 		minttypes.ModuleName,
 		ibcexported.ModuleName,
 		ibctransfertypes.ModuleName,
 
 		// Cosmos EVM BeginBlockers
+		//
+		// NOTE: evmupgrade activates the scheduled EIPs before the EVM runs the
+		// transactions of the block
+		evmupgradetypes.ModuleName,
 		evmerc20types.ModuleName,
 		evmfeemarkettypes.ModuleName,
 		evmvmtypes.ModuleName, // NOTE: EVM BeginBlocker must come after FeeMarket BeginBlocker
@@ -789,6 +1150,8 @@ func NewTacChainApp(
 
 	app.ModuleManager.SetOrderEndBlockers(
 		crisistypes.ModuleName,
+		// casts the delegated votes on the proposals x/gov tallies in the block
+		tacgovtypes.ModuleName,
 		govtypes.ModuleName,
 
 		// Cosmos EVM EndBlockers
@@ -798,6 +1161,9 @@ func NewTacChainApp(
 
 		feegrant.ModuleName,
 		group.ModuleName,
+		// jails the validators below the min self bond before x/staking
+		// applies the validator set updates
+		selfbondtypes.ModuleName,
 		// no-op modules
 		stakingtypes.ModuleName,
 		ibctransfertypes.ModuleName,
@@ -817,6 +1183,22 @@ func NewTacChainApp(
 		upgradetypes.ModuleName,
 		vestingtypes.ModuleName,
 		consensusparamtypes.ModuleName,
+
+		// Tac EndBlockers
+		recoverytypes.ModuleName,
+		autocompoundtypes.ModuleName,
+		contractmetatypes.ModuleName,
+		bridgetypes.ModuleName,
+		// snapshots the validators once x/staking applied the validator set
+		// updates
+		rewardsnapshottypes.ModuleName,
+		// records the gas used by the block after the fee market
+		feehistorytypes.ModuleName,
+		// routes the tips of the block to its proposer after x/rewardsnapshot
+		// recorded the rewards of the validators, so they count with the
+		// rewards of the next block, and reports the protocol revenue of the
+		// epoch it ended
+		feeroutingtypes.ModuleName,
 	)
 
 	// NOTE: The genutils module must occur after staking so that pools are
@@ -851,6 +1233,25 @@ func NewTacChainApp(
 
 		ibctransfertypes.ModuleName,
 
+		// Tac modules
+		recoverytypes.ModuleName,
+		ibchookstypes.ModuleName,
+		emissiontypes.ModuleName,
+		feeburntypes.ModuleName,
+		performancetypes.ModuleName,
+		autocompoundtypes.ModuleName,
+		selfbondtypes.ModuleName,
+		evmupgradetypes.ModuleName,
+		contractmetatypes.ModuleName,
+		bridgetypes.ModuleName,
+		contractgastypes.ModuleName,
+		tacgovtypes.ModuleName,
+		rewardsnapshottypes.ModuleName,
+		accountactivitytypes.ModuleName,
+		feehistorytypes.ModuleName,
+		feeroutingtypes.ModuleName,
+		nameservicetypes.ModuleName,
+
 		genutiltypes.ModuleName,
 		evidencetypes.ModuleName,
 		authz.ModuleName,
@@ -871,7 +1272,12 @@ func NewTacChainApp(
 	// app.ModuleManager.SetOrderMigrations(custom order)
 
 	app.ModuleManager.RegisterInvariants(app.CrisisKeeper)
-	app.configurator = module.NewConfigurator(app.appCodec, app.MsgServiceRouter(), app.GRPCQueryRouter())
+	// the query services registered by the modules apply the query limits of
+	// the node, and their queries are drained on shutdown
+	queryServer := newQueryLimitsServer(newQueryDrainServer(app.GRPCQueryRouter(), app.queryDrain), QueryLimitsConfigFromAppOptions(appOpts))
+	// and the msg services report the gas of each message in debug mode
+	msgServer := newGasProfileMsgServer(app.MsgServiceRouter(), GasProfileConfigFromAppOptions(appOpts))
+	app.configurator = module.NewConfigurator(app.appCodec, msgServer, queryServer)
 	err = app.ModuleManager.RegisterServices(app.configurator)
 	if err != nil {
 		panic(err)
@@ -916,6 +1322,8 @@ func NewTacChainApp(
 	app.setAnteHandler(
 		txConfig,
 		cast.ToUint64(appOpts.Get(evmsrvflags.EVMMaxTxGasWanted)),
+		TxLimitsConfigFromAppOptions(appOpts),
+		GasProfileConfigFromAppOptions(appOpts),
 	)
 
 	// In v0.46, the SDK introduces _postHandlers_. PostHandlers are like
@@ -950,12 +1358,16 @@ func NewTacChainApp(
 		if err := app.LoadLatestVersion(); err != nil {
 			panic(fmt.Errorf("error loading last version: %w", err))
 		}
+
+		if err := app.ValidateStateVersion(); err != nil {
+			panic(fmt.Errorf("refusing to start: %w", err))
+		}
 	}
 
 	return app
 }
 
-func (app *TacChainApp) setAnteHandler(txConfig client.TxConfig, maxGasWanted uint64) {
+func (app *TacChainApp) setAnteHandler(txConfig client.TxConfig, maxGasWanted uint64, txLimits TxLimitsConfig, gasProfile GasProfileConfig) {
 	anteHandler, err := NewAnteHandler(HandlerOptions{
 		HandlerOptions: authante.HandlerOptions{
 			BankKeeper:             app.BankKeeper,
@@ -971,6 +1383,13 @@ func (app *TacChainApp) setAnteHandler(txConfig client.TxConfig, maxGasWanted ui
 		EvmKeeper:       app.EVMKeeper,
 		FeeMarketKeeper: app.FeeMarketKeeper,
 		MaxTxGasWanted:  maxGasWanted,
+
+		TxLimits:          txLimits,
+		CommittedSequence: app.committedSequence,
+		EVMTxReplacements: app.evmTxReplacements,
+		MempoolTTL:        app.mempoolTTL,
+		GasProfile:        gasProfile,
+		ContractGasKeeper: app.ContractGasKeeper,
 	},
 	)
 	if err != nil {
@@ -981,15 +1400,33 @@ func (app *TacChainApp) setAnteHandler(txConfig client.TxConfig, maxGasWanted ui
 	app.SetAnteHandler(anteHandler)
 }
 
-func (app *TacChainApp) setPostHandler() {
-	postHandler, err := posthandler.NewPostHandler(
-		posthandler.HandlerOptions{},
-	)
+// committedSequence returns the sequence of an account in the last committed state
+func (app *TacChainApp) committedSequence(addr sdk.AccAddress) (uint64, error) {
+	ctx, err := app.CreateQueryContext(0, false)
 	if err != nil {
-		panic(err)
+		return 0, err
+	}
+
+	acc := app.AccountKeeper.GetAccount(ctx, addr)
+	if acc == nil {
+		return 0, nil
 	}
+	return acc.GetSequence(), nil
+}
 
-	app.SetPostHandler(postHandler)
+func (app *TacChainApp) setPostHandler() {
+	chainCallGas := NewChainCallGas(app.BankKeeper, app.FeeMarketKeeper, app.EVMKeeper)
+	app.SetPostHandler(sdk.ChainPostDecorators(
+		// runs first, the gas used by the EVM txs is the one they paid for
+		NewProposerTipsDecorator(app.FeeRoutingKeeper, evmcosmosante.NewDynamicFeeChecker(app.FeeMarketKeeper)),
+		NewContractRegistryDecorator(app.ContractMetaKeeper, chainCallGas),
+		NewBridgeEscrowDecorator(app.BridgeKeeper, app.EVMKeeper, chainCallGas),
+		NewVoteDelegationDecorator(app.TacGovKeeper, chainCallGas),
+		NewValidatorExitDecorator(app.SelfBondKeeper, chainCallGas),
+		NewVoucherRegistryDecorator(app.IBCHooksKeeper, chainCallGas),
+		NewNameRegistryDecorator(app.NameServiceKeeper, chainCallGas),
+		NewAccountActivityDecorator(app.AccountActivityKeeper),
+	))
 }
 
 // Name returns the name of the App
@@ -1014,6 +1451,48 @@ func (app *TacChainApp) FinalizeBlock(req *abci.RequestFinalizeBlock) (res *abci
 	return app.BaseApp.FinalizeBlock(req)
 }
 
+// Commit commits the block and compacts the application database when it is due
+func (app *TacChainApp) Commit() (*abci.ResponseCommit, error) {
+	defer telemetry.MeasureSince(time.Now(), "store", "commit")
+
+	res, err := app.BaseApp.Commit()
+	if err != nil {
+		return nil, err
+	}
+	app.compactor.afterCommit(app.LastBlockHeight())
+	return res, nil
+}
+
+// LogIndex returns the index of the EVM logs of the node, nil if it is disabled
+func (app *TacChainApp) LogIndex() *LogIndex {
+	return app.logIndex
+}
+
+// ReceiptStore returns the compact receipt store of the node, nil if it is disabled
+func (app *TacChainApp) ReceiptStore() *ReceiptStore {
+	return app.receipts
+}
+
+// Close drains the queries in flight, then closes the log index, the receipt
+// store and the alerter along with the databases of the application
+func (app *TacChainApp) Close() error {
+	app.drainQueries()
+	if app.alerter != nil {
+		app.alerter.Close()
+	}
+	if app.logIndex != nil {
+		if err := app.logIndex.Close(); err != nil {
+			return err
+		}
+	}
+	if app.receipts != nil {
+		if err := app.receipts.Close(); err != nil {
+			return err
+		}
+	}
+	return app.BaseApp.Close()
+}
+
 func (a *TacChainApp) Configurator() module.Configurator {
 	return a.configurator
 }
@@ -1029,7 +1508,17 @@ func (app *TacChainApp) InitChainer(ctx sdk.Context, req *abci.RequestInitChain)
 		panic(err)
 	}
 	response, err := app.ModuleManager.InitGenesis(ctx, app.appCodec, genesisState)
-	return response, err
+	if err != nil {
+		return nil, err
+	}
+	// the validator set of a genesis exported from a running chain may have
+	// grown past the genesis limits, only new chains are checked
+	if req.InitialHeight <= 1 {
+		if err := app.SelfBondKeeper.ValidateGenesisValidators(ctx); err != nil {
+			return nil, fmt.Errorf("invalid genesis validator set: %w", err)
+		}
+	}
+	return response, nil
 }
 
 // LoadHeight loads a particular height
@@ -1163,11 +1652,24 @@ func (app *TacChainApp) RegisterAPIRoutes(apiSvr *api.Server, apiConfig config.A
 	if err := server.RegisterSwaggerAPI(apiSvr.ClientCtx, apiSvr.Router, apiConfig.Swagger); err != nil {
 		panic(err)
 	}
+
+	// serve the OpenAPI document of all gateway routes next to the swagger UI
+	if apiConfig.Swagger {
+		doc, err := NewOpenAPIDoc()
+		if err != nil {
+			panic(err)
+		}
+		handler, err := openAPIHandler(doc)
+		if err != nil {
+			panic(err)
+		}
+		apiSvr.Router.HandleFunc(OpenAPIPath, handler).Methods(http.MethodGet)
+	}
 }
 
 // RegisterTxService implements the Application.RegisterTxService method.
 func (app *TacChainApp) RegisterTxService(clientCtx client.Context) {
-	authtx.RegisterTxService(app.BaseApp.GRPCQueryRouter(), clientCtx, app.BaseApp.Simulate, app.interfaceRegistry)
+	authtx.RegisterTxService(newQueryDrainServer(app.BaseApp.GRPCQueryRouter(), app.queryDrain), clientCtx, app.BaseApp.Simulate, app.interfaceRegistry)
 }
 
 // RegisterTendermintService implements the Application.RegisterTendermintService method.
@@ -1181,8 +1683,13 @@ func (app *TacChainApp) RegisterTendermintService(clientCtx client.Context) {
 	)
 }
 
+// RegisterNodeService registers the node gRPC service and starts the checks
+// of the alerter, which need the CometBFT node behind clientCtx
 func (app *TacChainApp) RegisterNodeService(clientCtx client.Context, cfg config.Config) {
 	nodeservice.RegisterNodeService(clientCtx, app.GRPCQueryRouter(), cfg)
+	if app.alerter != nil {
+		app.alerter.StartChecks(clientCtx.Client)
+	}
 }
 
 // GetMaccPerms returns a copy of the module account permissions
@@ -1208,7 +1715,7 @@ func BlockedAddresses() map[string]bool {
 		blockedAddrs[authtypes.NewModuleAddress(acc).String()] = true
 	}
 
-	blockedPrecompilesHex := evmvmtypes.AvailableStaticPrecompiles
+	blockedPrecompilesHex := append(slices.Clone(evmvmtypes.AvailableStaticPrecompiles), nameservicetypes.ResolverPrecompileAddress)
 	for _, addr := range evmcorevm.PrecompiledAddressesBerlin {
 		blockedPrecompilesHex = append(blockedPrecompilesHex, addr.Hex())
 	}
@@ -1246,6 +1753,25 @@ func initParamsKeeper(appCodec codec.BinaryCodec, legacyAmino *codec.LegacyAmino
 	paramsKeeper.Subspace(evmfeemarkettypes.ModuleName).WithKeyTable(evmfeemarkettypes.ParamKeyTable())
 	paramsKeeper.Subspace(evmerc20types.ModuleName)
 
+	// Tac modules
+	paramsKeeper.Subspace(recoverytypes.ModuleName).WithKeyTable(recoverytypes.ParamKeyTable())
+	paramsKeeper.Subspace(ibchookstypes.ModuleName).WithKeyTable(ibchookstypes.ParamKeyTable())
+	paramsKeeper.Subspace(emissiontypes.ModuleName).WithKeyTable(emissiontypes.ParamKeyTable())
+	paramsKeeper.Subspace(feeburntypes.ModuleName).WithKeyTable(feeburntypes.ParamKeyTable())
+	paramsKeeper.Subspace(performancetypes.ModuleName).WithKeyTable(performancetypes.ParamKeyTable())
+	paramsKeeper.Subspace(autocompoundtypes.ModuleName).WithKeyTable(autocompoundtypes.ParamKeyTable())
+	paramsKeeper.Subspace(selfbondtypes.ModuleName).WithKeyTable(selfbondtypes.ParamKeyTable())
+	paramsKeeper.Subspace(evmupgradetypes.ModuleName).WithKeyTable(evmupgradetypes.ParamKeyTable())
+	paramsKeeper.Subspace(contractmetatypes.ModuleName).WithKeyTable(contractmetatypes.ParamKeyTable())
+	paramsKeeper.Subspace(bridgetypes.ModuleName).WithKeyTable(bridgetypes.ParamKeyTable())
+	paramsKeeper.Subspace(contractgastypes.ModuleName).WithKeyTable(contractgastypes.ParamKeyTable())
+	paramsKeeper.Subspace(tacgovtypes.ModuleName).WithKeyTable(tacgovtypes.ParamKeyTable())
+	paramsKeeper.Subspace(rewardsnapshottypes.ModuleName).WithKeyTable(rewardsnapshottypes.ParamKeyTable())
+	paramsKeeper.Subspace(accountactivitytypes.ModuleName).WithKeyTable(accountactivitytypes.ParamKeyTable())
+	paramsKeeper.Subspace(feehistorytypes.ModuleName).WithKeyTable(feehistorytypes.ParamKeyTable())
+	paramsKeeper.Subspace(feeroutingtypes.ModuleName).WithKeyTable(feeroutingtypes.ParamKeyTable())
+	paramsKeeper.Subspace(nameservicetypes.ModuleName).WithKeyTable(nameservicetypes.ParamKeyTable())
+
 	paramsKeeper.Subspace(baseapp.Paramspace).WithKeyTable(paramstypes.ConsensusParamsKeyTable())
 
 	return paramsKeeper
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/core/header"
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+)
+
+// TestAuthzVote covers the voting services of staking platforms: a
+// delegator authorizes a service to vote on its behalf, the service votes
+// with MsgExec until the delegator revokes the grant or it expires.
+func TestAuthzVote(t *testing.T) {
+	tacApp := NewTacChainAppWithCustomOptions(t, false, 0, SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// authz reads the time of the block from the header info
+	at := func(t time.Time) sdk.Context {
+		return tacApp.NewContext(false).WithBlockTime(t).WithHeaderInfo(header.Info{Time: t})
+	}
+	ctx := at(now)
+
+	// the delegator has the voting power of its delegation
+	delegator := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	service := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	stake := sdkmath.NewInt(1_000_000)
+	coins := sdk.NewCoins(sdk.NewCoin(BaseDenom, stake))
+	require.NoError(t, tacApp.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins))
+	require.NoError(t, tacApp.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, delegator, coins))
+	validators, err := tacApp.StakingKeeper.GetAllValidators(ctx)
+	require.NoError(t, err)
+	_, err = tacApp.StakingKeeper.Delegate(ctx, delegator, stake, stakingtypes.Unbonded, validators[0], true)
+	require.NoError(t, err)
+
+	proposal, err := tacApp.GovKeeper.SubmitProposal(ctx, nil, "", "authz vote", "summary", delegator, false)
+	require.NoError(t, err)
+	require.NoError(t, tacApp.GovKeeper.ActivateVotingPeriod(ctx, proposal))
+
+	vote := func(ctx sdk.Context, option govv1.VoteOption) error {
+		_, err := tacApp.AuthzKeeper.Exec(ctx, tacsdk.NewExecVoteMsg(service, delegator, proposal.Id, option))
+		return err
+	}
+	requireVote := func(option govv1.VoteOption) {
+		t.Helper()
+		v, err := tacApp.GovKeeper.Votes.Get(ctx, collections.Join(proposal.Id, delegator))
+		require.NoError(t, err)
+		require.Equal(t, govv1.NewNonSplitVoteOption(option), v.Options)
+	}
+
+	// without a grant the service can't vote
+	require.ErrorIs(t, vote(ctx, govv1.OptionYes), authz.ErrNoAuthorizationFound)
+
+	expiration := now.Add(time.Hour)
+	grant, err := tacsdk.NewVoteGrantMsg(delegator, service, &expiration)
+	require.NoError(t, err)
+	_, err = tacApp.AuthzKeeper.Grant(ctx, grant)
+	require.NoError(t, err)
+
+	// the vote is the delegator's, weighted by its delegation
+	require.NoError(t, vote(ctx, govv1.OptionYes))
+	requireVote(govv1.OptionYes)
+	proposal, err = tacApp.GovKeeper.Proposals.Get(ctx, proposal.Id)
+	require.NoError(t, err)
+	_, _, tally, err := tacApp.GovKeeper.Tally(ctx, proposal)
+	require.NoError(t, err)
+	require.Equal(t, stake.String(), tally.YesCount)
+
+	// the service can change the vote, the grant isn't used up
+	require.NoError(t, vote(ctx, govv1.OptionNo))
+	requireVote(govv1.OptionNo)
+
+	// the grant authorizes MsgVote only
+	weighted := govv1.NewMsgVoteWeighted(delegator, proposal.Id, govv1.NewNonSplitVoteOption(govv1.OptionAbstain), "")
+	exec := authz.NewMsgExec(service, []sdk.Msg{weighted})
+	_, err = tacApp.AuthzKeeper.Exec(ctx, &exec)
+	require.ErrorIs(t, err, authz.ErrNoAuthorizationFound)
+
+	// the grant expires
+	require.ErrorIs(t, vote(at(expiration.Add(time.Second)), govv1.OptionAbstain), authz.ErrAuthorizationExpired)
+	requireVote(govv1.OptionNo)
+
+	// the delegator revokes the grant
+	_, err = tacApp.AuthzKeeper.Revoke(ctx, &authz.MsgRevoke{
+		Granter:    delegator.String(),
+		Grantee:    service.String(),
+		MsgTypeUrl: tacsdk.VoteMsgTypeURL,
+	})
+	require.NoError(t, err)
+	require.ErrorIs(t, vote(ctx, govv1.OptionAbstain), authz.ErrNoAuthorizationFound)
+	requireVote(govv1.OptionNo)
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"bytes"
+	"strconv"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/spf13/cast"
+
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+)
+
+const FlagBlockResultsRetention = "block-results.retention"
+
+// DefaultBlockResultsConfigTemplate defines the app.toml section of the
+// retention of the block results
+const DefaultBlockResultsConfigTemplate = `
+###############################################################################
+###                       Block Results Configuration                       ###
+###############################################################################
+
+[block-results]
+
+# Number of most recent blocks whose results are kept in data/state.db, 0 keeps them
+# as long as the blocks. The results are the tx results and the events of each block
+# served by the block_results RPC, they often take more space than the blocks. The
+# results of older blocks are deleted when the node starts and by
+# 'tacchaind tools prune-block-results', the blocks themselves are kept until
+# min-retain-blocks prunes them. Relayers and indexers reading the events of past
+# blocks, and the EVM JSON-RPC without the [receipts] and [log-index] sections, need
+# the results of the blocks they read.
+retention = {{ .BlockResults.Retention }}
+`
+
+// BlockResultsConfig configures the retention of the block results
+type BlockResultsConfig struct {
+	Retention uint64 `mapstructure:"retention"`
+}
+
+// DefaultBlockResultsConfig returns the default block results config, which
+// keeps the results as long as the blocks
+func DefaultBlockResultsConfig() BlockResultsConfig {
+	return BlockResultsConfig{Retention: 0}
+}
+
+// BlockResultsConfigFromAppOptions reads the block results config of the node
+func BlockResultsConfigFromAppOptions(appOpts servertypes.AppOptions) BlockResultsConfig {
+	return BlockResultsConfig{
+		Retention: cast.ToUint64(appOpts.Get(FlagBlockResultsRetention)),
+	}
+}
+
+// blockResultsPrefix prefixes the FinalizeBlock responses by height in the
+// state database of CometBFT, the height being in decimal
+var blockResultsPrefix = []byte("abciResponsesKey:")
+
+// blockResultsPruneBatch is the number of results deleted per write
+const blockResultsPruneBatch = 10_000
+
+// PruneBlockResults deletes from db, the state database of CometBFT, the
+// results of the blocks more than retention blocks before the latest results
+// it holds. It returns the number of blocks whose results were deleted and
+// the height of the latest results. The response CometBFT keeps apart for the
+// latest block is left untouched, so db must not be in use by a node.
+func PruneBlockResults(db dbm.DB, retention uint64) (int, int64, error) {
+	// the heights aren't padded, so the keys aren't in the order of the heights
+	it, err := dbm.IteratePrefix(db, blockResultsPrefix)
+	if err != nil {
+		return 0, 0, err
+	}
+	var heights []int64
+	var latest int64
+	for ; it.Valid(); it.Next() {
+		height, err := strconv.ParseInt(string(bytes.TrimPrefix(it.Key(), blockResultsPrefix)), 10, 64)
+		if err != nil {
+			continue
+		}
+		heights = append(heights, height)
+		latest = max(latest, height)
+	}
+	err = it.Error()
+	if closeErr := it.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || retention == 0 || uint64(latest) <= retention {
+		return 0, latest, err
+	}
+
+	cutoff := latest - int64(retention)
+	pruned := 0
+	batch := db.NewBatch()
+	defer func() { batch.Close() }()
+	for _, height := range heights {
+		if height > cutoff {
+			continue
+		}
+		if err := batch.Delete(append(bytes.Clone(blockResultsPrefix), strconv.FormatInt(height, 10)...)); err != nil {
+			return pruned, latest, err
+		}
+		pruned++
+		if pruned%blockResultsPruneBatch == 0 {
+			if err := batch.WriteSync(); err != nil {
+				return pruned, latest, err
+			}
+			batch.Close()
+			batch = db.NewBatch()
+		}
+	}
+	return pruned, latest, batch.WriteSync()
+}
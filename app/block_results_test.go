@@ -0,0 +1,84 @@
+package app
+
+import (
+	"testing"
+
+	cmtdb "github.com/cometbft/cometbft-db"
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtstate "github.com/cometbft/cometbft/state"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+)
+
+// saveBlockResults writes the results of the blocks at heights from to to in
+// the state database of CometBFT in dir, along with the validators of block 1
+func saveBlockResults(t *testing.T, dir string, from, to int64) {
+	db, err := cmtdb.NewGoLevelDB("state", dir)
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := cmtstate.NewStore(db, cmtstate.StoreOptions{})
+	for h := from; h <= to; h++ {
+		require.NoError(t, store.SaveFinalizeBlockResponse(h, &abci.ResponseFinalizeBlock{
+			TxResults: []*abci.ExecTxResult{{GasUsed: h}},
+			AppHash:   []byte{byte(h)},
+		}))
+	}
+	require.NoError(t, db.Set([]byte("validatorsKey:1"), []byte{0x01}))
+}
+
+func pruneBlockResults(t *testing.T, dir string, retention uint64) (int, int64) {
+	db, err := dbm.NewDB("state", dbm.GoLevelDBBackend, dir)
+	require.NoError(t, err)
+	defer db.Close()
+
+	pruned, latest, err := PruneBlockResults(db, retention)
+	require.NoError(t, err)
+	return pruned, latest
+}
+
+func TestPruneBlockResults(t *testing.T) {
+	dir := t.TempDir()
+	saveBlockResults(t, dir, 1, 150)
+
+	// nothing is pruned without retention or within it
+	pruned, latest := pruneBlockResults(t, dir, 0)
+	require.Zero(t, pruned)
+	require.Equal(t, int64(150), latest)
+	pruned, _ = pruneBlockResults(t, dir, 150)
+	require.Zero(t, pruned)
+
+	// the results of the last 40 blocks are kept, whatever the order of the keys
+	pruned, latest = pruneBlockResults(t, dir, 40)
+	require.Equal(t, 110, pruned)
+	require.Equal(t, int64(150), latest)
+	pruned, _ = pruneBlockResults(t, dir, 40)
+	require.Zero(t, pruned)
+
+	db, err := cmtdb.NewGoLevelDB("state", dir)
+	require.NoError(t, err)
+	store := cmtstate.NewStore(db, cmtstate.StoreOptions{})
+	for _, h := range []int64{1, 2, 20, 100, 110} {
+		_, err := store.LoadFinalizeBlockResponse(h)
+		require.Error(t, err, "results of block %d should be pruned", h)
+	}
+	for _, h := range []int64{111, 120, 150} {
+		res, err := store.LoadFinalizeBlockResponse(h)
+		require.NoError(t, err)
+		require.Equal(t, h, res.TxResults[0].GasUsed)
+	}
+	// CometBFT still finds the latest results on restart, and the rest of the state
+	res, err := store.LoadLastFinalizeBlockResponse(150)
+	require.NoError(t, err)
+	require.Equal(t, []byte{150}, res.AppHash)
+	validators, err := db.Get([]byte("validatorsKey:1"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x01}, validators)
+	require.NoError(t, db.Close())
+
+	// the blocks committed since push the results of older ones out
+	saveBlockResults(t, dir, 151, 160)
+	pruned, latest = pruneBlockResults(t, dir, 40)
+	require.Equal(t, 10, pruned)
+	require.Equal(t, int64(160), latest)
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	bridgekeeper "github.com/Asphere-xyz/tacchain/x/bridge/keeper"
+	bridgetypes "github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+// evmParamsKeeper reads the EVM params, the EVM denom is taken from them
+type evmParamsKeeper interface {
+	GetParams(ctx sdk.Context) evmvmtypes.Params
+}
+
+// BridgeEscrowDecorator executes the EVM txs sent to the bridge escrow
+// address. Like for the contract registry, no code lives at the escrow: the
+// EVM moves the value of a withdrawal or a bond to it as a plain transfer and
+// the decorator queues the withdrawal or records the bond once the tx
+// succeeded, moving the value on to the bridge module account. Only the txs
+// calling the escrow directly count: a call that fails fails its whole tx,
+// and so does any tx leaving coins at the escrow, sent by a contract, a bank
+// send or an IBC transfer, so no value is stuck there. The txs pay for the
+// escrow calls through ChainCallGas.
+//
+// CheckTx doesn't execute the messages, the escrow has received nothing to
+// move yet, the calls are only handled once the tx is executed.
+type BridgeEscrowDecorator struct {
+	keeper    bridgekeeper.Keeper
+	evmKeeper evmParamsKeeper
+	gas       ChainCallGas
+}
+
+// NewBridgeEscrowDecorator returns a post decorator handling the escrow calls
+func NewBridgeEscrowDecorator(keeper bridgekeeper.Keeper, evmKeeper evmParamsKeeper, gas ChainCallGas) BridgeEscrowDecorator {
+	return BridgeEscrowDecorator{keeper: keeper, evmKeeper: evmKeeper, gas: gas}
+}
+
+func (d BridgeEscrowDecorator) PostHandle(ctx sdk.Context, tx sdk.Tx, simulate, success bool, next sdk.PostHandler) (sdk.Context, error) {
+	if !success || (ctx.IsCheckTx() && !simulate) {
+		return next(ctx, tx, simulate, success)
+	}
+
+	escrow := bridgetypes.EscrowAddress()
+	for _, msg := range tx.GetMsgs() {
+		ethMsg, ok := msg.(*evmvmtypes.MsgEthereumTx)
+		if !ok {
+			continue
+		}
+		ethTx := ethMsg.AsTransaction()
+		if ethTx.To() == nil || *ethTx.To() != escrow {
+			continue
+		}
+
+		value := sdk.NewCoin(d.evmKeeper.GetParams(ctx).EvmDenom, sdkmath.NewIntFromBigInt(ethTx.Value()))
+		sender := common.BytesToAddress(ethMsg.GetFrom())
+		err := d.gas.Handle(ctx, sender, ethTx, func(ctx sdk.Context) error {
+			return d.keeper.HandleEscrowCall(ctx, sender, value, ethTx.Data())
+		})
+		if err != nil {
+			return ctx, err
+		}
+	}
+	if err := d.keeper.CheckEscrowBalance(ctx); err != nil {
+		return ctx, err
+	}
+
+	return next(ctx, tx, simulate, success)
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	errorsmod "cosmossdk.io/errors"
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	errortypes "github.com/cosmos/cosmos-sdk/types/errors"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+	evmfeemarkettypes "github.com/cosmos/evm/x/feemarket/types"
+)
+
+// MaxChainCallDataSize bounds the calldata of the EVM txs sent to the chain
+// addresses whose calls the post decorators handle
+const MaxChainCallDataSize = 4096
+
+// chainCallBankKeeper collects the fees of the chain calls
+type chainCallBankKeeper interface {
+	SendCoinsFromAccountToModule(ctx context.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins) error
+}
+
+// chainCallFeeMarketKeeper prices the gas of the chain calls
+type chainCallFeeMarketKeeper interface {
+	GetBaseFee(ctx sdk.Context) sdkmath.LegacyDec
+	GetParams(ctx sdk.Context) evmfeemarkettypes.Params
+}
+
+// ChainCallGas charges the EVM txs sent to the chain addresses, such as the
+// contract registry or the bridge escrow, for the work of the post decorators
+// handling their calls. No code lives at these addresses: the EVM runs the
+// txs as plain transfers, charging them the intrinsic gas only, and its ante
+// handler turns off the gas of the store accesses.
+//
+// The keeper call of a tx runs with the store gas of the Cosmos txs on the
+// gas meter of the tx, the calldata being bounded by MaxChainCallDataSize.
+// The gas the EVM used and the gas of the call must fit in the gas limit of
+// the tx, and the sender pays for the gas of the call at the gas price of the
+// tx: the EVM refunded the gas it didn't use before the post handlers run.
+// The gas estimations of the JSON-RPC only run the EVM, the txs must set a
+// gas limit above them.
+type ChainCallGas struct {
+	bankKeeper      chainCallBankKeeper
+	feeMarketKeeper chainCallFeeMarketKeeper
+	evmKeeper       evmParamsKeeper
+}
+
+// NewChainCallGas returns the meter of the chain calls
+func NewChainCallGas(bankKeeper chainCallBankKeeper, feeMarketKeeper chainCallFeeMarketKeeper, evmKeeper evmParamsKeeper) ChainCallGas {
+	return ChainCallGas{bankKeeper: bankKeeper, feeMarketKeeper: feeMarketKeeper, evmKeeper: evmKeeper}
+}
+
+// Handle runs call, the keeper call handling ethTx sent by sender, metering
+// its store accesses, and charges the sender for the gas it used. CheckTx
+// and the simulations only meter it: the EVM tx isn't executed, nothing was
+// refunded yet.
+func (g ChainCallGas) Handle(ctx sdk.Context, sender common.Address, ethTx *ethtypes.Transaction, call func(ctx sdk.Context) error) error {
+	if size := len(ethTx.Data()); size > MaxChainCallDataSize {
+		return errorsmod.Wrapf(errortypes.ErrTxTooLarge, "calldata of %d bytes above %d", size, MaxChainCallDataSize)
+	}
+
+	gasUsed := ctx.GasMeter().GasConsumed()
+	callCtx := ctx.WithKVGasConfig(storetypes.KVGasConfig()).WithTransientKVGasConfig(storetypes.TransientGasConfig())
+	if err := call(callCtx); err != nil {
+		return err
+	}
+	consumed := ctx.GasMeter().GasConsumed()
+	if consumed > ethTx.Gas() {
+		return errorsmod.Wrapf(errortypes.ErrOutOfGas, "chain call needs %d gas, the limit is %d", consumed, ethTx.Gas())
+	}
+	if ctx.IsCheckTx() || consumed == gasUsed {
+		return nil
+	}
+
+	price := g.gasPrice(ctx, ethTx)
+	fee := sdk.NewCoin(g.evmKeeper.GetParams(ctx).EvmDenom, price.Mul(sdkmath.NewIntFromUint64(consumed-gasUsed)))
+	if fee.IsZero() {
+		return nil
+	}
+	return g.bankKeeper.SendCoinsFromAccountToModule(ctx, sdk.AccAddress(sender.Bytes()), authtypes.FeeCollectorName, sdk.NewCoins(fee))
+}
+
+// gasPrice returns the gas price ethTx pays, the base fee of the fee market
+// plus its effective tip, or the gas price of the tx when the base fee is
+// disabled, like the EVM ante handler charges it.
+func (g ChainCallGas) gasPrice(ctx sdk.Context, ethTx *ethtypes.Transaction) sdkmath.Int {
+	if !g.feeMarketKeeper.GetParams(ctx).NoBaseFee {
+		// the ante handlers charge the base fee truncated to an integer
+		if baseFee := g.feeMarketKeeper.GetBaseFee(ctx); !baseFee.IsNil() {
+			base := baseFee.TruncateInt().BigInt()
+			if tip, err := ethTx.EffectiveGasTip(base); err == nil {
+				return sdkmath.NewIntFromBigInt(tip.Add(tip, base))
+			}
+		}
+	}
+	return sdkmath.NewIntFromBigInt(ethTx.GasPrice())
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"math/big"
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	errortypes "github.com/cosmos/cosmos-sdk/types/errors"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+)
+
+func TestChainCallGas(t *testing.T) {
+	app := NewTacChainAppWithCustomOptions(t, false, 0, SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := app.NewContext(false).WithBlockHeight(2)
+	feeMarketParams := app.FeeMarketKeeper.GetParams(ctx)
+	feeMarketParams.NoBaseFee = false
+	feeMarketParams.BaseFee = sdkmath.LegacyNewDec(100)
+	require.NoError(t, app.FeeMarketKeeper.SetParams(ctx, feeMarketParams))
+	denom := app.EVMKeeper.GetParams(ctx).EvmDenom
+
+	sender := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	coins := sdk.NewCoins(sdk.NewCoin(denom, sdkmath.NewInt(1_000_000_000)))
+	require.NoError(t, app.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins))
+	require.NoError(t, app.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, sdk.AccAddress(sender.Bytes()), coins))
+	feeCollector := authtypes.NewModuleAddress(authtypes.FeeCollectorName)
+	balance := func(addr sdk.AccAddress) sdkmath.Int {
+		return app.BankKeeper.GetBalance(ctx, addr, denom).Amount
+	}
+
+	// evmCtx is the context of an EVM tx which used 21000 gas, store
+	// accesses are free once the EVM ante handler ran
+	evmCtx := func(gasLimit uint64) sdk.Context {
+		meter := storetypes.NewInfiniteGasMeterWithLimit(gasLimit)
+		meter.ConsumeGas(21_000, "evm")
+		return ctx.WithGasMeter(meter).WithKVGasConfig(storetypes.GasConfig{}).WithTransientKVGasConfig(storetypes.GasConfig{})
+	}
+	// the tx pays a tip of 20 over the base fee of 100
+	chainTx := func(gasLimit uint64, data []byte) *ethtypes.Transaction {
+		return ethtypes.NewTx(&ethtypes.DynamicFeeTx{Gas: gasLimit, GasFeeCap: big.NewInt(1000), GasTipCap: big.NewInt(20), Data: data})
+	}
+	write := func(ctx sdk.Context) error {
+		ctx.KVStore(app.GetKey(banktypes.StoreKey)).Set([]byte("chain-call"), make([]byte, 100))
+		return nil
+	}
+	gas := NewChainCallGas(app.BankKeeper, app.FeeMarketKeeper, app.EVMKeeper)
+
+	// without the meter the writes are free
+	txCtx := evmCtx(100_000)
+	require.NoError(t, write(txCtx))
+	require.Equal(t, uint64(21_000), txCtx.GasMeter().GasConsumed())
+
+	// the sender pays for the gas of the call at the gas price of the tx
+	senderBalance, collected := balance(sdk.AccAddress(sender.Bytes())), balance(feeCollector)
+	txCtx = evmCtx(100_000)
+	require.NoError(t, gas.Handle(txCtx, sender, chainTx(100_000, []byte{0x01}), write))
+	callGas := txCtx.GasMeter().GasConsumed() - 21_000
+	require.Positive(t, callGas)
+	fee := sdkmath.NewIntFromUint64(callGas * 120)
+	require.Equal(t, senderBalance.Sub(fee), balance(sdk.AccAddress(sender.Bytes())))
+	require.Equal(t, collected.Add(fee), balance(feeCollector))
+
+	// the call must fit in the gas limit of the tx
+	err := gas.Handle(evmCtx(21_000+callGas-1), sender, chainTx(21_000+callGas-1, []byte{0x01}), write)
+	require.ErrorIs(t, err, errortypes.ErrOutOfGas)
+
+	// the calldata is bounded
+	called := false
+	err = gas.Handle(evmCtx(100_000), sender, chainTx(100_000, make([]byte, MaxChainCallDataSize+1)), func(sdk.Context) error {
+		called = true
+		return nil
+	})
+	require.ErrorIs(t, err, errortypes.ErrTxTooLarge)
+	require.False(t, called)
+
+	// CheckTx meters the call without charging it
+	senderBalance = balance(sdk.AccAddress(sender.Bytes()))
+	txCtx = evmCtx(100_000).WithIsCheckTx(true)
+	require.NoError(t, gas.Handle(txCtx, sender, chainTx(100_000, []byte{0x01}), write))
+	require.Equal(t, 21_000+callGas, txCtx.GasMeter().GasConsumed())
+	require.Equal(t, senderBalance, balance(sdk.AccAddress(sender.Bytes())))
+
+	// a sender who can't pay fails the tx
+	poor := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	err = gas.Handle(evmCtx(100_000), poor, chainTx(100_000, []byte{0x01}), write)
+	require.ErrorIs(t, err, errortypes.ErrInsufficientFunds)
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/spf13/cast"
+
+	"cosmossdk.io/log"
+
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+)
+
+const FlagCompactionInterval = "compaction.interval"
+
+// DefaultCompactionConfigTemplate defines the app.toml section of the auto-compaction
+const DefaultCompactionConfigTemplate = `
+###############################################################################
+###                         Compaction Configuration                        ###
+###############################################################################
+
+[compaction]
+
+# Compact the application database in the background every interval blocks,
+# 0 disables it. Compaction reclaims the space of deleted and overwritten state
+# and keeps reads fast on long-running nodes, at the cost of disk IO while it runs.
+# The databases of CometBFT are compacted with 'tacchaind tools compact-db'.
+interval = {{ .Compaction.Interval }}
+`
+
+// CompactionConfig configures the auto-compaction of the application database
+type CompactionConfig struct {
+	Interval uint64 `mapstructure:"interval"`
+}
+
+// DefaultCompactionConfig returns the default auto-compaction configuration
+func DefaultCompactionConfig() CompactionConfig {
+	return CompactionConfig{Interval: 0}
+}
+
+// CompactionConfigFromAppOptions reads the auto-compaction configuration of the node
+func CompactionConfigFromAppOptions(appOpts servertypes.AppOptions) CompactionConfig {
+	return CompactionConfig{
+		Interval: cast.ToUint64(appOpts.Get(FlagCompactionInterval)),
+	}
+}
+
+// CompactDB compacts all keys of db. Compaction runs alongside reads and
+// writes, so db can be in use.
+func CompactDB(db dbm.DB) error {
+	switch db := db.(type) {
+	case *dbm.GoLevelDB:
+		return db.ForceCompact(nil, nil)
+	case *dbm.PebbleDB:
+		// pebble needs the bounds of the range to compact
+		first, last, err := keyRange(db)
+		if err != nil || first == nil {
+			return err
+		}
+		return db.DB().Compact(first, append(last, 0), true)
+	case *dbm.MemDB:
+		return nil
+	default:
+		return fmt.Errorf("compaction of %T is not supported", db)
+	}
+}
+
+// keyRange returns the first and last key of db, nil if it is empty
+func keyRange(db dbm.DB) ([]byte, []byte, error) {
+	it, err := db.Iterator(nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+	if !it.Valid() {
+		return nil, nil, it.Error()
+	}
+	first := bytes.Clone(it.Key())
+
+	rit, err := db.ReverseIterator(nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rit.Close()
+	if !rit.Valid() {
+		return nil, nil, rit.Error()
+	}
+	return first, bytes.Clone(rit.Key()), nil
+}
+
+// compactor compacts the application database every interval blocks, one
+// compaction at a time. Compaction doesn't change the state, so it runs in the
+// background and blocks are committed while it runs.
+type compactor struct {
+	db       dbm.DB
+	interval uint64
+	logger   log.Logger
+
+	running atomic.Bool
+}
+
+func newCompactor(db dbm.DB, cfg CompactionConfig, logger log.Logger) *compactor {
+	return &compactor{db: db, interval: cfg.Interval, logger: logger.With("module", "compaction")}
+}
+
+// afterCommit starts a compaction if one is due at height and none is running.
+// It returns whether a compaction was started.
+func (c *compactor) afterCommit(height int64) bool {
+	if c.interval == 0 || height <= 0 || uint64(height)%c.interval != 0 {
+		return false
+	}
+	if !c.running.CompareAndSwap(false, true) {
+		c.logger.Info("skipping compaction, the previous one is still running", "height", height)
+		return false
+	}
+
+	go func() {
+		defer c.running.Store(false)
+		start := time.Now()
+		if err := CompactDB(c.db); err != nil {
+			c.logger.Error("failed to compact the application database", "height", height, "err", err)
+			return
+		}
+		c.logger.Info("compacted the application database", "height", height, "duration", time.Since(start))
+	}()
+	return true
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+)
+
+// populateDB writes n keys to db and deletes every other one
+func populateDB(t *testing.T, db dbm.DB, n int) {
+	value := make([]byte, 1024)
+	batch := db.NewBatch()
+	for i := 0; i < n; i++ {
+		require.NoError(t, batch.Set([]byte(fmt.Sprintf("key%06d", i)), value))
+	}
+	require.NoError(t, batch.Write())
+	require.NoError(t, batch.Close())
+
+	for i := 0; i < n; i += 2 {
+		require.NoError(t, db.Delete([]byte(fmt.Sprintf("key%06d", i))))
+	}
+}
+
+func TestCompactDB(t *testing.T) {
+	for _, backend := range []dbm.BackendType{dbm.GoLevelDBBackend, dbm.PebbleDBBackend} {
+		t.Run(string(backend), func(t *testing.T) {
+			db, err := dbm.NewDB("application", backend, t.TempDir())
+			require.NoError(t, err)
+			defer db.Close()
+
+			// an empty database has nothing to compact
+			require.NoError(t, CompactDB(db))
+
+			populateDB(t, db, 10000)
+			require.NoError(t, CompactDB(db))
+
+			// compaction keeps the data
+			for _, i := range []int{0, 1, 5000, 9999} {
+				value, err := db.Get([]byte(fmt.Sprintf("key%06d", i)))
+				require.NoError(t, err)
+				if i%2 == 0 {
+					require.Nil(t, value)
+				} else {
+					require.Len(t, value, 1024)
+				}
+			}
+		})
+	}
+}
+
+func TestCompactorInterval(t *testing.T) {
+	db := dbm.NewMemDB()
+	populateDB(t, db, 10)
+
+	c := newCompactor(db, CompactionConfig{Interval: 0}, log.NewNopLogger())
+	require.False(t, c.afterCommit(100), "auto-compaction is disabled by default")
+
+	c = newCompactor(db, CompactionConfig{Interval: 10}, log.NewNopLogger())
+	require.False(t, c.afterCommit(9))
+	require.True(t, c.afterCommit(10))
+	require.Eventually(t, func() bool { return !c.running.Load() }, time.Second, 10*time.Millisecond)
+	require.False(t, c.afterCommit(15))
+	require.True(t, c.afterCommit(20))
+
+	// one compaction at a time
+	require.Eventually(t, func() bool { return !c.running.Load() }, time.Second, 10*time.Millisecond)
+	c.running.Store(true)
+	require.False(t, c.afterCommit(30))
+}
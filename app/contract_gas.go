@@ -0,0 +1,50 @@
+package app
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	evmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+// ContractGasKeeper checks the gas limit of EVM txs calling a contract
+// against the gas cap set by governance, see x/contractgas
+type ContractGasKeeper interface {
+	CheckCallGas(ctx sdk.Context, to common.Address, gasLimit uint64) error
+}
+
+// ContractGasDecorator rejects the EVM txs calling a contract with a gas
+// limit above the cap of the contractgas params. Unlike the tx limits it is
+// part of consensus: it applies to CheckTx and to the txs of a block, so a
+// proposer can't include a call above the cap. It runs before the EVM ante
+// handler, a rejected tx doesn't pay fees nor use up its nonce. It only sees
+// the contract the tx calls, see CheckCallGas for the calls between contracts.
+type ContractGasDecorator struct {
+	keeper ContractGasKeeper
+}
+
+// NewContractGasDecorator returns a ContractGasDecorator using the cap and
+// exemptions of keeper
+func NewContractGasDecorator(keeper ContractGasKeeper) ContractGasDecorator {
+	return ContractGasDecorator{keeper: keeper}
+}
+
+func (d ContractGasDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	for _, msg := range tx.GetMsgs() {
+		ethMsg, ok := msg.(*evmtypes.MsgEthereumTx)
+		if !ok {
+			continue
+		}
+		ethTx := ethMsg.AsTransaction()
+		if ethTx.To() == nil {
+			// contract creations aren't capped
+			continue
+		}
+		if err := d.keeper.CheckCallGas(ctx, *ethTx.To(), ethTx.Gas()); err != nil {
+			return ctx, err
+		}
+	}
+
+	return next(ctx, tx, simulate)
+}
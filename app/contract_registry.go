@@ -0,0 +1,61 @@
+package app
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	contractmetakeeper "github.com/Asphere-xyz/tacchain/x/contractmeta/keeper"
+	contractmetatypes "github.com/Asphere-xyz/tacchain/x/contractmeta/types"
+)
+
+// ContractRegistryDecorator executes the EVM txs sent to the contract metadata
+// registry address. No code lives at the registry, the EVM runs the txs as
+// plain transfers and the decorator handles their calldata once they
+// succeeded, so deployers register metadata with the wallet they deployed
+// with. A registry call that fails, or carries value, fails its whole tx.
+// The txs pay for the registry calls through ChainCallGas.
+type ContractRegistryDecorator struct {
+	keeper contractmetakeeper.Keeper
+	gas    ChainCallGas
+}
+
+// NewContractRegistryDecorator returns a post decorator handling the registry calls
+func NewContractRegistryDecorator(keeper contractmetakeeper.Keeper, gas ChainCallGas) ContractRegistryDecorator {
+	return ContractRegistryDecorator{keeper: keeper, gas: gas}
+}
+
+func (d ContractRegistryDecorator) PostHandle(ctx sdk.Context, tx sdk.Tx, simulate, success bool, next sdk.PostHandler) (sdk.Context, error) {
+	if !success {
+		return next(ctx, tx, simulate, success)
+	}
+
+	registry := contractmetatypes.RegistryAddress()
+	for _, msg := range tx.GetMsgs() {
+		ethMsg, ok := msg.(*evmvmtypes.MsgEthereumTx)
+		if !ok {
+			continue
+		}
+		ethTx := ethMsg.AsTransaction()
+		if ethTx.To() == nil || *ethTx.To() != registry {
+			continue
+		}
+
+		if ethTx.Value().Sign() != 0 {
+			return ctx, errorsmod.Wrap(contractmetatypes.ErrInvalidRegistryCall, "registry calls can't transfer value")
+		}
+		sender := common.BytesToAddress(ethMsg.GetFrom())
+		err := d.gas.Handle(ctx, sender, ethTx, func(ctx sdk.Context) error {
+			return d.keeper.HandleRegistryCall(ctx, sender, ethTx.Data())
+		})
+		if err != nil {
+			return ctx, err
+		}
+	}
+
+	return next(ctx, tx, simulate, success)
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	distprecompile "github.com/cosmos/evm/precompiles/distribution"
+	evmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+// DistributionPrecompile is the distribution precompile of Cosmos EVM, with
+// the withdraw address of a delegator only set by the delegator itself. A
+// contract delegating through the staking precompile manages its own
+// delegations and sets where their rewards go, but neither another contract
+// nor the account that sent the tx may set it in its place: the delegator
+// must be the direct caller of the precompile, tx.origin doesn't count.
+type DistributionPrecompile struct {
+	*distprecompile.Precompile
+}
+
+// Run implements vm.PrecompiledContract
+func (p DistributionPrecompile) Run(evm *vm.EVM, contract *vm.Contract, readOnly bool) ([]byte, error) {
+	if err := p.checkWithdrawAddressCaller(contract); err != nil {
+		return nil, err
+	}
+	return p.Precompile.Run(evm, contract, readOnly)
+}
+
+// checkWithdrawAddressCaller rejects a setWithdrawAddress call whose caller
+// isn't the delegator. The other methods and the malformed inputs are left to
+// the precompile.
+func (p DistributionPrecompile) checkWithdrawAddressCaller(contract *vm.Contract) error {
+	if len(contract.Input) < 4 {
+		return nil
+	}
+	method, err := p.MethodById(contract.Input[:4])
+	if err != nil || method.Name != distprecompile.SetWithdrawAddressMethod {
+		return nil
+	}
+	args, err := method.Inputs.Unpack(contract.Input[4:])
+	if err != nil || len(args) == 0 {
+		return nil
+	}
+	delegator, ok := args[0].(common.Address)
+	if !ok {
+		return nil
+	}
+	if caller := contract.Caller(); caller != delegator {
+		return fmt.Errorf("caller %s can't set the withdraw address of delegator %s", caller, delegator)
+	}
+	return nil
+}
+
+// withDistributionPrecompile replaces the distribution precompile of
+// precompiles with DistributionPrecompile
+func withDistributionPrecompile(precompiles map[common.Address]vm.PrecompiledContract) map[common.Address]vm.PrecompiledContract {
+	address := common.HexToAddress(evmtypes.DistributionPrecompileAddress)
+	if precompile, ok := precompiles[address].(*distprecompile.Precompile); ok {
+		precompiles[address] = DistributionPrecompile{Precompile: precompile}
+	}
+	return precompiles
+}
@@ -0,0 +1,183 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+)
+
+const (
+	FlagSignWatermarkFile = "double-sign-protection.watermark-file"
+	FlagSignWatermarkURL  = "double-sign-protection.watermark-url"
+
+	// signWatermarkTimeout bounds the request to the watermark service at startup
+	signWatermarkTimeout = 10 * time.Second
+)
+
+// DefaultDoubleSignProtectionConfigTemplate defines the app.toml section of the startup sign state check
+const DefaultDoubleSignProtectionConfigTemplate = `
+###############################################################################
+###                     Double Sign Protection Configuration                ###
+###############################################################################
+
+[double-sign-protection]
+
+# Before starting, the node compares the height of data/priv_validator_state.json with
+# the watermarks below and refuses to start if it is behind any of them, since the
+# validator key may have signed those heights on another node. A watermark is either a
+# priv_validator_state.json copied from the previous signer, a JSON object with a
+# height field or a plain height. Nodes using priv_validator_laddr are not checked.
+
+# Path of a watermark file, empty disables the check.
+watermark-file = "{{ .DoubleSignProtection.WatermarkFile }}"
+
+# URL of a high watermark service answering GET requests with a watermark, empty
+# disables the check. The node doesn't start if the service can't be reached.
+watermark-url = "{{ .DoubleSignProtection.WatermarkURL }}"
+`
+
+// DoubleSignProtectionConfig configures the startup check of the validator sign state
+type DoubleSignProtectionConfig struct {
+	WatermarkFile string `mapstructure:"watermark-file"`
+	WatermarkURL  string `mapstructure:"watermark-url"`
+}
+
+// DefaultDoubleSignProtectionConfig returns the default config, without watermarks
+func DefaultDoubleSignProtectionConfig() DoubleSignProtectionConfig {
+	return DoubleSignProtectionConfig{}
+}
+
+// DoubleSignProtectionConfigFromAppOptions reads the watermarks of the node
+func DoubleSignProtectionConfigFromAppOptions(appOpts servertypes.AppOptions) DoubleSignProtectionConfig {
+	return DoubleSignProtectionConfig{
+		WatermarkFile: cast.ToString(appOpts.Get(FlagSignWatermarkFile)),
+		WatermarkURL:  cast.ToString(appOpts.Get(FlagSignWatermarkURL)),
+	}
+}
+
+// CheckSignWatermark fails if the sign state at stateFile is behind one of
+// the configured watermarks. A missing state file is at height 0.
+func CheckSignWatermark(ctx context.Context, cfg DoubleSignProtectionConfig, stateFile string) error {
+	if cfg.WatermarkFile == "" && cfg.WatermarkURL == "" {
+		return nil
+	}
+
+	height, err := readSignStateHeight(stateFile)
+	if err != nil {
+		return err
+	}
+
+	check := func(source string, watermark int64) error {
+		if height < watermark {
+			return fmt.Errorf(
+				"refusing to start, %s is at height %d but the watermark of %s is at height %d: the validator key may have signed up to that height on another node, "+
+					"replace the state file with the one of the previous signer",
+				stateFile, height, source, watermark,
+			)
+		}
+		return nil
+	}
+
+	if cfg.WatermarkFile != "" {
+		bz, err := os.ReadFile(cfg.WatermarkFile)
+		if err != nil {
+			return fmt.Errorf("failed to read watermark file: %w", err)
+		}
+		watermark, err := parseSignWatermark(bz)
+		if err != nil {
+			return fmt.Errorf("invalid watermark file %s: %w", cfg.WatermarkFile, err)
+		}
+		if err := check(cfg.WatermarkFile, watermark); err != nil {
+			return err
+		}
+	}
+
+	if cfg.WatermarkURL != "" {
+		watermark, err := fetchSignWatermark(ctx, cfg.WatermarkURL)
+		if err != nil {
+			return fmt.Errorf("failed to get the watermark of %s, refusing to start: %w", cfg.WatermarkURL, err)
+		}
+		if err := check(cfg.WatermarkURL, watermark); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readSignStateHeight(stateFile string) (int64, error) {
+	bz, err := os.ReadFile(stateFile)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	height, err := parseSignWatermark(bz)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sign state %s: %w", stateFile, err)
+	}
+	return height, nil
+}
+
+func fetchSignWatermark(ctx context.Context, url string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, signWatermarkTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	bz, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil {
+		return 0, err
+	}
+	return parseSignWatermark(bz)
+}
+
+// parseSignWatermark reads the height of a priv_validator_state.json, of a
+// JSON object with a height field or of a plain height.
+func parseSignWatermark(bz []byte) (int64, error) {
+	trimmed := strings.TrimSpace(string(bz))
+
+	var state struct {
+		Height json.RawMessage `json:"height"`
+	}
+	if strings.HasPrefix(trimmed, "{") {
+		if err := json.Unmarshal([]byte(trimmed), &state); err != nil {
+			return 0, err
+		}
+		if len(state.Height) == 0 {
+			return 0, fmt.Errorf("no height field")
+		}
+		// CometBFT encodes heights as strings
+		trimmed = strings.Trim(string(state.Height), `"`)
+	}
+
+	height, err := strconv.ParseInt(trimmed, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid height %q", trimmed)
+	}
+	if height < 0 {
+		return 0, fmt.Errorf("negative height %d", height)
+	}
+	return height, nil
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// signStateJSON is a priv_validator_state.json as written by CometBFT
+func signStateJSON(height int64) string {
+	return fmt.Sprintf(`{
+  "height": "%d",
+  "round": 0,
+  "step": 3,
+  "signature": "c2lnbmF0dXJl",
+  "signbytes": "0A0B"
+}`, height)
+}
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestCheckSignWatermarkFile(t *testing.T) {
+	dir := t.TempDir()
+	stateFile := writeTestFile(t, dir, "priv_validator_state.json", signStateJSON(100))
+
+	testCases := []struct {
+		name      string
+		watermark string
+		expErr    string
+	}{
+		{"state of the previous signer behind", signStateJSON(99), ""},
+		{"state of the previous signer at the same height", signStateJSON(100), ""},
+		{"stale state file", signStateJSON(101), "refusing to start"},
+		{"plain height", "150\n", "height 150"},
+		{"json height", `{"height": 50}`, ""},
+		{"invalid watermark", "latest", "invalid watermark file"},
+		{"watermark without height", `{"round": 1}`, "no height field"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := DoubleSignProtectionConfig{WatermarkFile: writeTestFile(t, dir, "watermark.json", tc.watermark)}
+			err := CheckSignWatermark(context.Background(), cfg, stateFile)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestCheckSignWatermarkMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	missingState := filepath.Join(dir, "priv_validator_state.json")
+
+	// without watermarks nothing is checked
+	require.NoError(t, CheckSignWatermark(context.Background(), DefaultDoubleSignProtectionConfig(), missingState))
+
+	// a wiped data dir is at height 0, so it is behind any watermark
+	cfg := DoubleSignProtectionConfig{WatermarkFile: writeTestFile(t, dir, "watermark", "1")}
+	require.ErrorContains(t, CheckSignWatermark(context.Background(), cfg, missingState), "is at height 0")
+
+	cfg = DoubleSignProtectionConfig{WatermarkFile: writeTestFile(t, dir, "watermark", "0")}
+	require.NoError(t, CheckSignWatermark(context.Background(), cfg, missingState))
+
+	// a configured watermark file must exist
+	cfg = DoubleSignProtectionConfig{WatermarkFile: filepath.Join(dir, "missing")}
+	require.ErrorContains(t, CheckSignWatermark(context.Background(), cfg, missingState), "failed to read watermark file")
+}
+
+func TestCheckSignWatermarkURL(t *testing.T) {
+	dir := t.TempDir()
+	stateFile := writeTestFile(t, dir, "priv_validator_state.json", signStateJSON(100))
+
+	watermark := signStateJSON(100)
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(watermark))
+	}))
+	defer srv.Close()
+	cfg := DoubleSignProtectionConfig{WatermarkURL: srv.URL}
+
+	require.NoError(t, CheckSignWatermark(context.Background(), cfg, stateFile))
+
+	watermark = `{"height":"120"}`
+	require.ErrorContains(t, CheckSignWatermark(context.Background(), cfg, stateFile), "height 120")
+
+	// the node doesn't start if the service can't vouch for the state
+	watermark = "0"
+	status = http.StatusInternalServerError
+	require.ErrorContains(t, CheckSignWatermark(context.Background(), cfg, stateFile), "unexpected status")
+
+	srv.Close()
+	require.ErrorContains(t, CheckSignWatermark(context.Background(), cfg, stateFile), "refusing to start")
+
+	// both watermarks are checked
+	cfg = DoubleSignProtectionConfig{
+		WatermarkFile: writeTestFile(t, dir, "watermark", "200"),
+		WatermarkURL:  srv.URL,
+	}
+	require.ErrorContains(t, CheckSignWatermark(context.Background(), cfg, stateFile), "height 200")
+}
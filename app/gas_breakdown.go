@@ -0,0 +1,50 @@
+package app
+
+// RefundQuotient is the maximum share of the gas used that is refunded since
+// London (EIP-3529).
+const RefundQuotient = 5
+
+// EVMGasBreakdown decomposes the gas charged for an EVM tx.
+//
+// The EVM charges the intrinsic gas of the tx and the gas of its execution,
+// then refunds the refund counter (storage slots cleared) capped at a fifth
+// of that. The Cosmos gas meter of the tx is then set to the EVM gas used, but
+// at least the gas limit times the fee market min gas multiplier, which keeps
+// txs with a gas limit far above their usage from blocking block space for
+// free. GasCharged is what the sender pays for and what the Cosmos tx result
+// reports as gas used.
+type EVMGasBreakdown struct {
+	TxHash           string `json:"tx_hash"`
+	GasLimit         uint64 `json:"gas_limit"`
+	Intrinsic        uint64 `json:"intrinsic"`
+	Execution        uint64 `json:"execution"`
+	RefundCounter    uint64 `json:"refund_counter"`
+	Refund           uint64 `json:"refund"`
+	EVMGasUsed       uint64 `json:"evm_gas_used"`
+	MinGasAdjustment uint64 `json:"min_gas_adjustment"`
+	GasCharged       uint64 `json:"gas_charged"`
+	CosmosGasUsed    int64  `json:"cosmos_gas_used"`
+}
+
+// NewEVMGasBreakdown derives the refund and the min gas adjustment from the
+// gas components of a tx and the gas it was charged.
+func NewEVMGasBreakdown(txHash string, gasLimit, intrinsic, execution, refundCounter, gasCharged uint64, cosmosGasUsed int64) EVMGasBreakdown {
+	used := intrinsic + execution
+	refund := min(refundCounter, used/RefundQuotient)
+
+	b := EVMGasBreakdown{
+		TxHash:        txHash,
+		GasLimit:      gasLimit,
+		Intrinsic:     intrinsic,
+		Execution:     execution,
+		RefundCounter: refundCounter,
+		Refund:        refund,
+		EVMGasUsed:    used - refund,
+		GasCharged:    gasCharged,
+		CosmosGasUsed: cosmosGasUsed,
+	}
+	if gasCharged > b.EVMGasUsed {
+		b.MinGasAdjustment = gasCharged - b.EVMGasUsed
+	}
+	return b
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEVMGasBreakdown(t *testing.T) {
+	testCases := []struct {
+		name          string
+		gasLimit      uint64
+		intrinsic     uint64
+		execution     uint64
+		refundCounter uint64
+		gasCharged    uint64
+		expRefund     uint64
+		expEVMGasUsed uint64
+		expAdjustment uint64
+	}{
+		{"plain transfer", 21000, 21000, 0, 0, 21000, 0, 21000, 0},
+		{"plain transfer with high gas limit", 100000, 21000, 0, 0, 50000, 0, 21000, 29000},
+		// clearing a slot written by an earlier tx refunds 4800
+		{"sstore clear", 30000, 21000, 5006, 4800, 21206, 4800, 21206, 0},
+		{"refund capped at a fifth", 30000, 21000, 4000, 10000, 20000, 5000, 20000, 0},
+		{"refund and min gas", 100000, 21000, 5006, 4800, 50000, 4800, 21206, 28794},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := NewEVMGasBreakdown("0x01", tc.gasLimit, tc.intrinsic, tc.execution, tc.refundCounter, tc.gasCharged, int64(tc.gasCharged))
+			require.Equal(t, tc.expRefund, b.Refund)
+			require.Equal(t, tc.expEVMGasUsed, b.EVMGasUsed)
+			require.Equal(t, tc.expAdjustment, b.MinGasAdjustment)
+			require.Equal(t, b.GasCharged, b.EVMGasUsed+b.MinGasAdjustment)
+		})
+	}
+}
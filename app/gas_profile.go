@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gogogrpc "github.com/cosmos/gogoproto/grpc"
+	"github.com/spf13/cast"
+	"google.golang.org/grpc"
+
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	FlagGasProfileEnabled = "gas-profile.enabled"
+
+	// EventTypeGasProfile reports the gas consumed by an ante decorator or a message of a tx
+	EventTypeGasProfile = "gas_profile"
+
+	AttributeKeyGasProfileStage = "stage"
+	AttributeKeyGasProfileName  = "name"
+	AttributeKeyGasProfileGas   = "gas"
+
+	GasProfileStageAnte = "ante"
+	GasProfileStageMsg  = "msg"
+)
+
+// DefaultGasProfileConfigTemplate defines the app.toml section of the gas profile
+const DefaultGasProfileConfigTemplate = `
+###############################################################################
+###                         Gas Profile Configuration                       ###
+###############################################################################
+
+[gas-profile]
+
+# Debug mode for contract and module developers: every tx result gets a gas_profile
+# event per ante decorator and per message with the gas it consumed, which add up to
+# the gas used by the tx, also in simulations. Events aren't part of consensus, so
+# nodes enable it independently of each other.
+enabled = {{ .GasProfile.Enabled }}
+`
+
+// GasProfileConfig configures the gas profile of the txs a node executes
+type GasProfileConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// DefaultGasProfileConfig returns the default gas profile config, disabled
+func DefaultGasProfileConfig() GasProfileConfig {
+	return GasProfileConfig{}
+}
+
+// GasProfileConfigFromAppOptions reads the gas profile config of the node
+func GasProfileConfigFromAppOptions(appOpts servertypes.AppOptions) GasProfileConfig {
+	return GasProfileConfig{
+		Enabled: cast.ToBool(appOpts.Get(FlagGasProfileEnabled)),
+	}
+}
+
+func emitGasProfile(ctx sdk.Context, stage, name string, gas int64) {
+	ctx.EventManager().EmitEvent(sdk.NewEvent(
+		EventTypeGasProfile,
+		sdk.NewAttribute(AttributeKeyGasProfileStage, stage),
+		sdk.NewAttribute(AttributeKeyGasProfileName, name),
+		sdk.NewAttribute(AttributeKeyGasProfileGas, strconv.FormatInt(gas, 10)),
+	))
+}
+
+// gasProfileDecorator reports the gas consumed by an ante decorator before it
+// calls the next one.
+type gasProfileDecorator struct {
+	name      string
+	decorator sdk.AnteDecorator
+}
+
+// profileAnteDecorators wraps decorators with the gas profile if it is enabled
+func profileAnteDecorators(cfg GasProfileConfig, decorators ...sdk.AnteDecorator) []sdk.AnteDecorator {
+	if !cfg.Enabled {
+		return decorators
+	}
+	profiled := make([]sdk.AnteDecorator, len(decorators))
+	for i, decorator := range decorators {
+		profiled[i] = gasProfileDecorator{
+			name:      strings.TrimPrefix(fmt.Sprintf("%T", decorator), "*"),
+			decorator: decorator,
+		}
+	}
+	return profiled
+}
+
+func (d gasProfileDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	meter := ctx.GasMeter()
+	start := meter.GasConsumed()
+	return d.decorator.AnteHandle(ctx, tx, simulate, func(newCtx sdk.Context, tx sdk.Tx, simulate bool) (sdk.Context, error) {
+		gas := newCtx.GasMeter().GasConsumed()
+		// decorators setting up the gas meter of the tx are charged what
+		// they consumed on it, the tx doesn't pay for the gas before
+		if newCtx.GasMeter() == meter {
+			gas -= start
+		}
+		emitGasProfile(newCtx, GasProfileStageAnte, d.name, int64(gas))
+		return next(newCtx, tx, simulate)
+	})
+}
+
+// gasProfileMsgServer registers msg services reporting the gas consumed by
+// each message they execute. It wraps the msg service router, so messages
+// executed by other messages, like those of an authz MsgExec, are reported
+// too, their gas being part of the gas of the executing message.
+type gasProfileMsgServer struct {
+	gogogrpc.Server
+}
+
+// newGasProfileMsgServer wraps the msg service router of the app with the gas
+// profile if it is enabled.
+func newGasProfileMsgServer(server gogogrpc.Server, cfg GasProfileConfig) gogogrpc.Server {
+	if !cfg.Enabled {
+		return server
+	}
+	return gasProfileMsgServer{Server: server}
+}
+
+// RegisterService implements gogogrpc.Server.
+func (s gasProfileMsgServer) RegisterService(sd *grpc.ServiceDesc, ss interface{}) {
+	desc := *sd
+	desc.Methods = make([]grpc.MethodDesc, len(sd.Methods))
+	for i, method := range sd.Methods {
+		handler := method.Handler
+		method.Handler = func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			sdkCtx, ok := ctx.(sdk.Context)
+			if !ok {
+				return handler(srv, ctx, dec, interceptor)
+			}
+
+			var name string
+			start := sdkCtx.GasMeter().GasConsumed()
+			res, err := handler(srv, ctx, func(req interface{}) error {
+				if msg, ok := req.(sdk.Msg); ok {
+					name = sdk.MsgTypeURL(msg)
+				}
+				return dec(req)
+			}, interceptor)
+			if err != nil {
+				return res, err
+			}
+			// the difference may be negative, EVM txs reset the gas meter of
+			// the tx to the gas used by the EVM
+			emitGasProfile(sdkCtx, GasProfileStageMsg, name, int64(sdkCtx.GasMeter().GasConsumed())-int64(start))
+			return res, nil
+		}
+		desc.Methods[i] = method
+	}
+	s.Server.RegisterService(&desc, ss)
+}
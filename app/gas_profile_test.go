@@ -0,0 +1,141 @@
+package app
+
+import (
+	"strconv"
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+)
+
+// consumeGasDecorator consumes gas before calling the next decorator
+type consumeGasDecorator struct {
+	gas uint64
+}
+
+func (d consumeGasDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	ctx.GasMeter().ConsumeGas(d.gas, "test")
+	return next(ctx, tx, simulate)
+}
+
+// setUpGasMeterDecorator sets up the gas meter of the tx like authante.SetUpContextDecorator
+type setUpGasMeterDecorator struct{}
+
+func (setUpGasMeterDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	return next(ctx.WithGasMeter(storetypes.NewGasMeter(1000000)), tx, simulate)
+}
+
+// gasProfile returns the gas of the gas_profile events by stage and name
+func gasProfile(t *testing.T, events []sdk.Event) (profile []string, total int64) {
+	t.Helper()
+	for _, event := range events {
+		if event.Type != EventTypeGasProfile {
+			continue
+		}
+		attrs := make(map[string]string)
+		for _, attr := range event.Attributes {
+			attrs[attr.Key] = attr.Value
+		}
+		gas, err := strconv.ParseInt(attrs[AttributeKeyGasProfileGas], 10, 64)
+		require.NoError(t, err)
+		profile = append(profile, attrs[AttributeKeyGasProfileStage]+" "+attrs[AttributeKeyGasProfileName]+" "+attrs[AttributeKeyGasProfileGas])
+		total += gas
+	}
+	return profile, total
+}
+
+func TestGasProfileDecorator(t *testing.T) {
+	decorators := []sdk.AnteDecorator{
+		setUpGasMeterDecorator{},
+		consumeGasDecorator{gas: 100},
+		consumeGasDecorator{gas: 0},
+		consumeGasDecorator{gas: 30},
+	}
+
+	var txCtx sdk.Context
+	terminator := func(ctx sdk.Context, _ sdk.Tx, _ bool) (sdk.Context, error) {
+		txCtx = ctx
+		return ctx, nil
+	}
+	ctx := sdk.Context{}.
+		WithGasMeter(storetypes.NewInfiniteGasMeter()).
+		WithEventManager(sdk.NewEventManager())
+
+	_, err := sdk.ChainAnteDecorators(append(profileAnteDecorators(GasProfileConfig{Enabled: true}, decorators...), decoratorFunc(terminator))...)(ctx, nil, false)
+	require.NoError(t, err)
+
+	profile, total := gasProfile(t, ctx.EventManager().Events())
+	require.Equal(t, []string{
+		"ante app.setUpGasMeterDecorator 0",
+		"ante app.consumeGasDecorator 100",
+		"ante app.consumeGasDecorator 0",
+		"ante app.consumeGasDecorator 30",
+	}, profile)
+	// the profile adds up to the gas of the tx
+	require.EqualValues(t, txCtx.GasMeter().GasConsumed(), total)
+
+	// disabled, the decorators are left as is
+	require.Equal(t, decorators, profileAnteDecorators(GasProfileConfig{}, decorators...))
+}
+
+// decoratorFunc turns an ante handler into the last decorator of a chain
+type decoratorFunc sdk.AnteHandler
+
+func (f decoratorFunc) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, _ sdk.AnteHandler) (sdk.Context, error) {
+	return f(ctx, tx, simulate)
+}
+
+func TestGasProfileMsgServer(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		t.Run(strconv.FormatBool(enabled), func(t *testing.T) {
+			tacApp := NewTacChainAppWithCustomOptions(t, false, 0, SetupOptions{
+				Logger: log.NewTestLogger(t),
+				DB:     dbm.NewMemDB(),
+				AppOpts: simtestutil.AppOptionsMap{
+					flags.FlagHome:        t.TempDir(),
+					FlagGasProfileEnabled: enabled,
+				},
+			})
+			ctx := tacApp.NewContext(false).WithGasMeter(storetypes.NewGasMeter(1000000))
+
+			from := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+			to := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+			coins := sdk.NewCoins(sdk.NewCoin(BaseDenom, sdkmath.NewInt(1000)))
+			require.NoError(t, tacApp.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins))
+			require.NoError(t, tacApp.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, from, coins))
+
+			msg := banktypes.NewMsgSend(from, to, coins)
+			handler := tacApp.MsgServiceRouter().Handler(msg)
+			require.NotNil(t, handler)
+
+			start := ctx.GasMeter().GasConsumed()
+			res, err := handler(ctx, msg)
+			require.NoError(t, err)
+			used := ctx.GasMeter().GasConsumed() - start
+			require.Positive(t, used)
+
+			var events []sdk.Event
+			for _, event := range res.Events {
+				events = append(events, sdk.Event(event))
+			}
+			profile, total := gasProfile(t, events)
+			if !enabled {
+				require.Empty(t, profile)
+				return
+			}
+			require.Equal(t, []string{"msg /cosmos.bank.v1beta1.MsgSend " + strconv.FormatUint(used, 10)}, profile)
+			require.EqualValues(t, used, total)
+		})
+	}
+}
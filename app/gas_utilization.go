@@ -0,0 +1,147 @@
+package app
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/hashicorp/go-metrics"
+
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GasUtilizationWindow is the default number of blocks gas utilization is computed over
+const GasUtilizationWindow = 100
+
+// GasPercentiles summarizes the gas of a series of blocks
+type GasPercentiles struct {
+	Mean uint64 `json:"mean"`
+	P50  uint64 `json:"p50"`
+	P90  uint64 `json:"p90"`
+	P99  uint64 `json:"p99"`
+	Max  uint64 `json:"max"`
+}
+
+// newGasPercentiles returns the nearest-rank percentiles of gas
+func newGasPercentiles(gas []uint64) GasPercentiles {
+	if len(gas) == 0 {
+		return GasPercentiles{}
+	}
+	sorted := slices.Clone(gas)
+	slices.Sort(sorted)
+
+	var sum uint64
+	for _, g := range sorted {
+		sum += g
+	}
+	rank := func(p float64) uint64 {
+		return sorted[int(math.Ceil(p*float64(len(sorted))))-1]
+	}
+	return GasPercentiles{
+		Mean: sum / uint64(len(sorted)),
+		P50:  rank(0.5),
+		P90:  rank(0.9),
+		P99:  rank(0.99),
+		Max:  sorted[len(sorted)-1],
+	}
+}
+
+// GasUtilization is the gas of the last blocks against the block gas limit,
+// to size max_gas of the consensus params on data. Blocks are filled up to
+// max_gas by the gas txs want, so utilization and headroom are those of the
+// 99th percentile of gas wanted. Both are zero if block gas is unlimited.
+type GasUtilization struct {
+	Blocks      int               `json:"blocks"`
+	MaxGas      int64             `json:"max_gas"`
+	GasUsed     GasPercentiles    `json:"gas_used"`
+	GasWanted   GasPercentiles    `json:"gas_wanted"`
+	Utilization sdkmath.LegacyDec `json:"utilization"`
+	Headroom    sdkmath.LegacyDec `json:"headroom"`
+}
+
+// NewGasUtilization returns the utilization of blocks with the given gas used
+// and wanted under maxGas.
+func NewGasUtilization(maxGas int64, gasUsed, gasWanted []uint64) GasUtilization {
+	u := GasUtilization{
+		Blocks:      len(gasUsed),
+		MaxGas:      maxGas,
+		GasUsed:     newGasPercentiles(gasUsed),
+		GasWanted:   newGasPercentiles(gasWanted),
+		Utilization: sdkmath.LegacyZeroDec(),
+		Headroom:    sdkmath.LegacyZeroDec(),
+	}
+	if maxGas > 0 {
+		u.Utilization = sdkmath.LegacyNewDecFromInt(sdkmath.NewIntFromUint64(u.GasWanted.P99)).QuoInt64(maxGas)
+		u.Headroom = sdkmath.LegacyOneDec().Sub(u.Utilization)
+	}
+	return u
+}
+
+// BlockGas returns the gas used and wanted by the txs of a block
+func BlockGas(txResults []*abci.ExecTxResult) (used, wanted uint64) {
+	for _, res := range txResults {
+		if res.GasUsed > 0 {
+			used += uint64(res.GasUsed)
+		}
+		if res.GasWanted > 0 {
+			wanted += uint64(res.GasWanted)
+		}
+	}
+	return used, wanted
+}
+
+// gasUtilizationMetrics reports the gas utilization of the last blocks along
+// with the telemetry of the node. It receives the results of each block as a
+// streaming listener.
+type gasUtilizationMetrics struct {
+	window    int
+	gasUsed   []uint64
+	gasWanted []uint64
+}
+
+var _ storetypes.ABCIListener = (*gasUtilizationMetrics)(nil)
+
+func newGasUtilizationMetrics(window int) *gasUtilizationMetrics {
+	return &gasUtilizationMetrics{window: window}
+}
+
+// ListenFinalizeBlock implements storetypes.ABCIListener.
+func (m *gasUtilizationMetrics) ListenFinalizeBlock(ctx context.Context, _ abci.RequestFinalizeBlock, res abci.ResponseFinalizeBlock) error {
+	used, wanted := BlockGas(res.TxResults)
+	m.gasUsed = append(m.gasUsed, used)
+	m.gasWanted = append(m.gasWanted, wanted)
+	if len(m.gasUsed) > m.window {
+		m.gasUsed = m.gasUsed[1:]
+		m.gasWanted = m.gasWanted[1:]
+	}
+
+	var maxGas int64
+	if sdkCtx, ok := ctx.(sdk.Context); ok && sdkCtx.ConsensusParams().Block != nil {
+		maxGas = sdkCtx.ConsensusParams().Block.MaxGas
+	}
+	u := NewGasUtilization(maxGas, m.gasUsed, m.gasWanted)
+	setGasGauges("used", u.GasUsed)
+	setGasGauges("wanted", u.GasWanted)
+	telemetry.SetGauge(float32(u.Utilization.MustFloat64()), "block", "gas", "utilization")
+	telemetry.SetGauge(float32(u.Headroom.MustFloat64()), "block", "gas", "headroom")
+	return nil
+}
+
+func setGasGauges(name string, p GasPercentiles) {
+	for _, q := range []struct {
+		quantile string
+		gas      uint64
+	}{{"p50", p.P50}, {"p90", p.P90}, {"p99", p.P99}} {
+		telemetry.SetGaugeWithLabels([]string{"block", "gas", name}, float32(q.gas), []metrics.Label{telemetry.NewLabel("quantile", q.quantile)})
+	}
+}
+
+// ListenCommit implements storetypes.ABCIListener.
+func (m *gasUtilizationMetrics) ListenCommit(context.Context, abci.ResponseCommit, []*storetypes.StoreKVPair) error {
+	return nil
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestNewGasUtilization(t *testing.T) {
+	// 1..100 in a shuffled order
+	gas := make([]uint64, 100)
+	for i := range gas {
+		gas[i] = uint64((i*37)%100 + 1)
+	}
+
+	u := NewGasUtilization(200, gas, gas)
+	require.Equal(t, 100, u.Blocks)
+	require.Equal(t, GasPercentiles{Mean: 50, P50: 50, P90: 90, P99: 99, Max: 100}, u.GasUsed)
+	require.Equal(t, u.GasUsed, u.GasWanted)
+	require.Equal(t, sdkmath.LegacyMustNewDecFromStr("0.495"), u.Utilization)
+	require.Equal(t, sdkmath.LegacyMustNewDecFromStr("0.505"), u.Headroom)
+	// the input is left as is
+	require.Equal(t, uint64(1), gas[0])
+
+	// a single block is all percentiles
+	u = NewGasUtilization(100, []uint64{10}, []uint64{100})
+	require.Equal(t, GasPercentiles{Mean: 10, P50: 10, P90: 10, P99: 10, Max: 10}, u.GasUsed)
+	require.Equal(t, sdkmath.LegacyOneDec(), u.Utilization)
+	require.True(t, u.Headroom.IsZero())
+
+	// unlimited block gas
+	u = NewGasUtilization(-1, []uint64{10}, []uint64{100})
+	require.True(t, u.Utilization.IsZero())
+	require.True(t, u.Headroom.IsZero())
+
+	u = NewGasUtilization(100, nil, nil)
+	require.Zero(t, u.Blocks)
+	require.Equal(t, GasPercentiles{}, u.GasWanted)
+	require.Equal(t, sdkmath.LegacyOneDec(), u.Headroom)
+}
+
+func TestBlockGas(t *testing.T) {
+	used, wanted := BlockGas([]*abci.ExecTxResult{
+		{GasUsed: 50000, GasWanted: 200000},
+		{GasUsed: 21000, GasWanted: 21000},
+		// txs rejected before running report no gas
+		{Code: 1},
+	})
+	require.Equal(t, uint64(71000), used)
+	require.Equal(t, uint64(221000), wanted)
+}
+
+func TestGasUtilizationMetricsWindow(t *testing.T) {
+	m := newGasUtilizationMetrics(3)
+	ctx := sdk.Context{}.WithConsensusParams(cmtproto.ConsensusParams{Block: &cmtproto.BlockParams{MaxGas: 1000}})
+
+	for i := int64(1); i <= 5; i++ {
+		res := abci.ResponseFinalizeBlock{TxResults: []*abci.ExecTxResult{{GasUsed: i * 10, GasWanted: i * 100}}}
+		require.NoError(t, m.ListenFinalizeBlock(ctx, abci.RequestFinalizeBlock{}, res))
+	}
+	require.Equal(t, []uint64{30, 40, 50}, m.gasUsed)
+	require.Equal(t, []uint64{300, 400, 500}, m.gasWanted)
+}
@@ -73,3 +73,13 @@ func TacLinearInflationFormula(_ context.Context, _ minttypes.Minter, params min
 func TacZeroInflation(_ context.Context, _ minttypes.Minter, params minttypes.Params, bondedRatio math.LegacyDec) math.LegacyDec {
 	return math.LegacyZeroDec()
 }
+
+// StakingAPR is the yearly reward rate of bonded tokens before validator
+// commission: the annual provisions minted by x/mint, less the community tax
+// kept by x/distribution, over the bonded tokens. Fees are not included.
+func StakingAPR(annualProvisions math.LegacyDec, bondedTokens math.Int, communityTax math.LegacyDec) math.LegacyDec {
+	if !bondedTokens.IsPositive() {
+		return math.LegacyZeroDec()
+	}
+	return annualProvisions.Mul(math.LegacyOneDec().Sub(communityTax)).QuoInt(bondedTokens)
+}
@@ -81,3 +81,25 @@ func TestTacLinearInflationFormula(t *testing.T) {
 		})
 	}
 }
+
+func TestStakingAPR(t *testing.T) {
+	testCases := []struct {
+		name             string
+		annualProvisions string
+		bondedTokens     int64
+		communityTax     string
+		expectedAPR      string
+	}{
+		{"No community tax", "70", 1000, "0", "0.07"},
+		{"Community tax", "70", 1000, "0.02", "0.0686"},
+		{"Nothing bonded", "70", 0, "0.02", "0"},
+		{"No provisions", "0", 1000, "0.02", "0"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apr := StakingAPR(math.LegacyMustNewDecFromStr(tc.annualProvisions), math.NewInt(tc.bondedTokens), math.LegacyMustNewDecFromStr(tc.communityTax))
+			require.Equal(t, math.LegacyMustNewDecFromStr(tc.expectedAPR), apr)
+		})
+	}
+}
@@ -0,0 +1,444 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"sync"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cast"
+
+	"cosmossdk.io/log"
+	storetypes "cosmossdk.io/store/types"
+
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+const (
+	FlagLogIndexEnabled       = "log-index.enabled"
+	FlagLogIndexRetention     = "log-index.retention"
+	FlagLogIndexMaxBlockRange = "log-index.max-block-range"
+
+	// LogIndexSectionSize is the number of blocks a bitmap of the log index covers
+	LogIndexSectionSize = 4096
+
+	// logIndexMaxTopics is the number of topic positions of an EVM log
+	logIndexMaxTopics = 4
+)
+
+// DefaultLogIndexConfigTemplate defines the app.toml section of the EVM log index
+const DefaultLogIndexConfigTemplate = `
+###############################################################################
+###                         Log Index Configuration                         ###
+###############################################################################
+
+[log-index]
+
+# Index the addresses and topics of the EVM logs of each block as it is committed,
+# so eth_getLogs only reads the blocks with matching logs instead of every block of
+# the range. The index is kept in data/evm_log_index.db and starts at the first block
+# committed once enabled, the ranges it doesn't cover are scanned block by block as
+# without it. eth_getLogs uses the index once "logindex" follows "eth" in the api
+# list of the json-rpc section.
+enabled = {{ .LogIndex.Enabled }}
+
+# Number of most recent blocks kept in the index, 0 keeps every indexed block.
+retention = {{ .LogIndex.Retention }}
+
+# Largest block range eth_getLogs serves from the index, it replaces the
+# block-range-cap of the json-rpc section for the ranges the index covers.
+max-block-range = {{ .LogIndex.MaxBlockRange }}
+`
+
+// LogIndexConfig configures the EVM log index of the node
+type LogIndexConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	Retention     uint64 `mapstructure:"retention"`
+	MaxBlockRange uint64 `mapstructure:"max-block-range"`
+}
+
+// DefaultLogIndexConfig returns the default EVM log index config, disabled
+func DefaultLogIndexConfig() LogIndexConfig {
+	return LogIndexConfig{MaxBlockRange: 1_000_000}
+}
+
+// LogIndexConfigFromAppOptions reads the EVM log index config of the node
+func LogIndexConfigFromAppOptions(appOpts servertypes.AppOptions) LogIndexConfig {
+	return LogIndexConfig{
+		Enabled:       cast.ToBool(appOpts.Get(FlagLogIndexEnabled)),
+		Retention:     cast.ToUint64(appOpts.Get(FlagLogIndexRetention)),
+		MaxBlockRange: cast.ToUint64(appOpts.Get(FlagLogIndexMaxBlockRange)),
+	}
+}
+
+var (
+	logIndexFirstKey = []byte{0x00, 0x00}
+	logIndexLastKey  = []byte{0x00, 0x01}
+	logIndexBitmaps  = []byte{0x01}
+)
+
+// kinds of the bitmaps of a section of the log index
+const (
+	logIndexKindAny     byte = 0x00
+	logIndexKindAddress byte = 0x01
+	logIndexKindTopic   byte = 0x10 // plus the topic position
+)
+
+// LogIndex indexes the EVM logs of the committed blocks by address and by
+// topic at each position. For every section of LogIndexSectionSize blocks it
+// keeps a bitmap per address and per topic of the blocks with a log matching
+// it, so the blocks a log filter may match are the intersection of a few
+// bitmaps.
+//
+// The index covers the contiguous heights from first to last. It receives the
+// results of each block as a streaming listener and writes them once the block
+// is committed. A gap in the heights, a node restored from a snapshot or
+// restarted with the index disabled for a while, starts the index over.
+type LogIndex struct {
+	db        dbm.DB
+	retention uint64
+	logger    log.Logger
+
+	// mtx keeps queries from reading a block half written or pruned
+	mtx     sync.RWMutex
+	pending *logIndexBlock
+}
+
+// logIndexBlock is the bitmap ids of a block finalized but not committed yet
+type logIndexBlock struct {
+	height int64
+	ids    [][]byte
+}
+
+var _ storetypes.ABCIListener = (*LogIndex)(nil)
+
+// NewLogIndex returns the log index stored in db, keeping the last retention
+// blocks or every block if retention is 0
+func NewLogIndex(db dbm.DB, retention uint64, logger log.Logger) *LogIndex {
+	return &LogIndex{db: db, retention: retention, logger: logger.With("module", "log-index")}
+}
+
+// OpenLogIndex opens the log index of the node in dir
+func OpenLogIndex(dir string, backend dbm.BackendType, cfg LogIndexConfig, logger log.Logger) (*LogIndex, error) {
+	db, err := dbm.NewDB("evm_log_index", backend, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the EVM log index: %w", err)
+	}
+	return NewLogIndex(db, cfg.Retention, logger), nil
+}
+
+// Close closes the database of the index
+func (idx *LogIndex) Close() error {
+	return idx.db.Close()
+}
+
+// ListenFinalizeBlock implements storetypes.ABCIListener.
+func (idx *LogIndex) ListenFinalizeBlock(_ context.Context, req abci.RequestFinalizeBlock, res abci.ResponseFinalizeBlock) error {
+	ids, err := blockLogIDs(res.TxResults)
+	if err != nil {
+		// the block is left out, the index starts over at the next one
+		idx.logger.Error("failed to parse the EVM logs of the block", "height", req.Height, "error", err)
+		idx.pending = nil
+		return nil
+	}
+	idx.pending = &logIndexBlock{height: req.Height, ids: ids}
+	return nil
+}
+
+// ListenCommit implements storetypes.ABCIListener.
+func (idx *LogIndex) ListenCommit(context.Context, abci.ResponseCommit, []*storetypes.StoreKVPair) error {
+	block := idx.pending
+	idx.pending = nil
+	if block == nil {
+		return nil
+	}
+	if err := idx.addBlock(block.height, block.ids); err != nil {
+		idx.logger.Error("failed to index the EVM logs of the block", "height", block.height, "error", err)
+	}
+	return nil
+}
+
+// addBlock sets the bits of height in the bitmaps of ids and prunes the
+// blocks past the retention
+func (idx *LogIndex) addBlock(height int64, ids [][]byte) error {
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+
+	first, last, ok := idx.bounds()
+	if ok && height <= last {
+		// a block replayed after a restart is already indexed
+		return nil
+	}
+	if ok && height != last+1 {
+		idx.logger.Info("log index has a gap, starting over", "last", last, "height", height)
+		if err := idx.reset(); err != nil {
+			return err
+		}
+		ok = false
+	}
+	if !ok {
+		first = height
+	}
+
+	batch := idx.db.NewBatch()
+	defer batch.Close()
+
+	section, bit := uint64(height)/LogIndexSectionSize, uint64(height)%LogIndexSectionSize
+	for _, id := range ids {
+		key := logIndexBitmapKey(section, id)
+		bitmap, err := idx.db.Get(key)
+		if err != nil {
+			return err
+		}
+		if bitmap == nil {
+			bitmap = make([]byte, LogIndexSectionSize/8)
+		}
+		bitmap[bit/8] |= 1 << (bit % 8)
+		if err := batch.Set(key, bitmap); err != nil {
+			return err
+		}
+	}
+
+	if idx.retention > 0 && uint64(height) >= idx.retention {
+		if cutoff := height - int64(idx.retention) + 1; cutoff > first {
+			if err := idx.pruneSections(batch, uint64(first)/LogIndexSectionSize, uint64(cutoff)/LogIndexSectionSize); err != nil {
+				return err
+			}
+			first = cutoff
+		}
+	}
+
+	if err := batch.Set(logIndexFirstKey, sdk.Uint64ToBigEndian(uint64(first))); err != nil {
+		return err
+	}
+	if err := batch.Set(logIndexLastKey, sdk.Uint64ToBigEndian(uint64(height))); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// pruneSections deletes the bitmaps of the sections from start to end, end excluded
+func (idx *LogIndex) pruneSections(batch dbm.Batch, start, end uint64) error {
+	if start >= end {
+		return nil
+	}
+	it, err := idx.db.Iterator(logIndexSectionPrefix(start), logIndexSectionPrefix(end))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// reset deletes the whole index
+func (idx *LogIndex) reset() error {
+	it, err := idx.db.Iterator(nil, nil)
+	if err != nil {
+		return err
+	}
+	var keys [][]byte
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	if err := it.Error(); err != nil {
+		it.Close()
+		return err
+	}
+	it.Close()
+
+	batch := idx.db.NewBatch()
+	defer batch.Close()
+	for _, key := range keys {
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// Bounds returns the first and last heights the index covers, false if it is empty
+func (idx *LogIndex) Bounds() (first, last int64, ok bool) {
+	idx.mtx.RLock()
+	defer idx.mtx.RUnlock()
+	return idx.bounds()
+}
+
+func (idx *LogIndex) bounds() (first, last int64, ok bool) {
+	firstBz, err := idx.db.Get(logIndexFirstKey)
+	if err != nil || firstBz == nil {
+		return 0, 0, false
+	}
+	lastBz, err := idx.db.Get(logIndexLastKey)
+	if err != nil || lastBz == nil {
+		return 0, 0, false
+	}
+	return int64(sdk.BigEndianToUint64(firstBz)), int64(sdk.BigEndianToUint64(lastBz)), true
+}
+
+// Candidates returns the heights from from to to, both included, of the
+// blocks with a log matching the filter, with the semantics of eth_getLogs:
+// the log is emitted by one of addresses, and at every position of topics its
+// topic is one of those listed, an empty list or position matching any. A
+// candidate block may still have no matching log when distinct logs match
+// the address and the topics, so the logs of the candidates must be filtered.
+// It returns false when the index doesn't cover the whole range.
+func (idx *LogIndex) Candidates(from, to int64, addresses []common.Address, topics [][]common.Hash) ([]int64, bool) {
+	if len(topics) > logIndexMaxTopics || from > to || from < 0 {
+		return nil, false
+	}
+
+	idx.mtx.RLock()
+	defer idx.mtx.RUnlock()
+
+	first, last, ok := idx.bounds()
+	if !ok || from < first || to > last {
+		return nil, false
+	}
+
+	heights := []int64{}
+	for section := uint64(from) / LogIndexSectionSize; section <= uint64(to)/LogIndexSectionSize; section++ {
+		bitmap, err := idx.sectionCandidates(section, addresses, topics)
+		if err != nil {
+			idx.logger.Error("failed to read the log index", "section", section, "error", err)
+			return nil, false
+		}
+		for i, b := range bitmap {
+			for b != 0 {
+				bit := bits.TrailingZeros8(b)
+				b &^= 1 << bit
+				height := int64(section*LogIndexSectionSize) + int64(i*8+bit)
+				if height >= from && height <= to {
+					heights = append(heights, height)
+				}
+			}
+		}
+	}
+	return heights, true
+}
+
+// sectionCandidates returns the bitmap of the blocks of section matching the filter
+func (idx *LogIndex) sectionCandidates(section uint64, addresses []common.Address, topics [][]common.Hash) ([]byte, error) {
+	bitmap, err := idx.db.Get(logIndexBitmapKey(section, []byte{logIndexKindAny}))
+	if err != nil || bitmap == nil {
+		return nil, err
+	}
+
+	if len(addresses) > 0 {
+		ids := make([][]byte, 0, len(addresses))
+		for _, address := range addresses {
+			ids = append(ids, logIndexAddressID(address))
+		}
+		if bitmap, err = idx.intersect(section, bitmap, ids); err != nil {
+			return nil, err
+		}
+	}
+	for position, alternatives := range topics {
+		if len(alternatives) == 0 {
+			continue
+		}
+		ids := make([][]byte, 0, len(alternatives))
+		for _, topic := range alternatives {
+			ids = append(ids, logIndexTopicID(position, topic))
+		}
+		if bitmap, err = idx.intersect(section, bitmap, ids); err != nil {
+			return nil, err
+		}
+	}
+	return bitmap, nil
+}
+
+// intersect returns bitmap and the union of the bitmaps of ids in section
+func (idx *LogIndex) intersect(section uint64, bitmap []byte, ids [][]byte) ([]byte, error) {
+	union := make([]byte, len(bitmap))
+	for _, id := range ids {
+		other, err := idx.db.Get(logIndexBitmapKey(section, id))
+		if err != nil {
+			return nil, err
+		}
+		for i := range other {
+			union[i] |= other[i]
+		}
+	}
+	for i := range union {
+		union[i] &= bitmap[i]
+	}
+	return union, nil
+}
+
+// evmLog is the part of the EVM logs of tx_log events the log index and the
+// receipt store read
+type evmLog struct {
+	Address string   `json:"address"`
+	Topics  []string `json:"topics"`
+	Data    []byte   `json:"data"`
+}
+
+// blockLogIDs returns the bitmap ids of the EVM logs of a block, none if it
+// has no logs
+func blockLogIDs(txResults []*abci.ExecTxResult) ([][]byte, error) {
+	seen := make(map[string]bool)
+	var ids [][]byte
+	add := func(id []byte) {
+		if !seen[string(id)] {
+			seen[string(id)] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, res := range txResults {
+		if res.Code != 0 {
+			continue
+		}
+		for _, event := range res.Events {
+			if event.Type != evmvmtypes.EventTypeTxLog {
+				continue
+			}
+			for _, attr := range event.Attributes {
+				if attr.Key != evmvmtypes.AttributeKeyTxLog {
+					continue
+				}
+				var l evmLog
+				if err := json.Unmarshal([]byte(attr.Value), &l); err != nil {
+					return nil, err
+				}
+				if len(l.Topics) > logIndexMaxTopics {
+					return nil, fmt.Errorf("log of %s has %d topics", l.Address, len(l.Topics))
+				}
+				add([]byte{logIndexKindAny})
+				add(logIndexAddressID(common.HexToAddress(l.Address)))
+				for position, topic := range l.Topics {
+					add(logIndexTopicID(position, common.HexToHash(topic)))
+				}
+			}
+		}
+	}
+	return ids, nil
+}
+
+func logIndexAddressID(address common.Address) []byte {
+	return append([]byte{logIndexKindAddress}, address.Bytes()...)
+}
+
+func logIndexTopicID(position int, topic common.Hash) []byte {
+	return append([]byte{logIndexKindTopic + byte(position)}, topic.Bytes()...)
+}
+
+func logIndexSectionPrefix(section uint64) []byte {
+	return append(append([]byte{}, logIndexBitmaps...), sdk.Uint64ToBigEndian(section)...)
+}
+
+func logIndexBitmapKey(section uint64, id []byte) []byte {
+	return append(logIndexSectionPrefix(section), id...)
+}
@@ -0,0 +1,288 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+// testLog is a log of a block of the log index tests
+type testLog struct {
+	height  int64
+	address common.Address
+	topics  []common.Hash
+}
+
+func txLogEvent(t *testing.T, logs ...testLog) abci.Event {
+	event := abci.Event{Type: evmvmtypes.EventTypeTxLog}
+	for _, l := range logs {
+		topics := make([]string, 0, len(l.topics))
+		for _, topic := range l.topics {
+			topics = append(topics, topic.Hex())
+		}
+		bz, err := json.Marshal(evmLog{Address: l.address.Hex(), Topics: topics})
+		require.NoError(t, err)
+		event.Attributes = append(event.Attributes, abci.EventAttribute{Key: evmvmtypes.AttributeKeyTxLog, Value: string(bz)})
+	}
+	return event
+}
+
+// commitBlock passes the results of a block with a tx per log to idx
+func commitBlock(t *testing.T, idx *LogIndex, height int64, logs ...testLog) {
+	res := abci.ResponseFinalizeBlock{}
+	for _, l := range logs {
+		res.TxResults = append(res.TxResults, &abci.ExecTxResult{Events: []abci.Event{txLogEvent(t, l)}})
+	}
+	require.NoError(t, idx.ListenFinalizeBlock(context.Background(), abci.RequestFinalizeBlock{Height: height}, res))
+	require.NoError(t, idx.ListenCommit(context.Background(), abci.ResponseCommit{}, nil))
+}
+
+// matchLog reports whether l matches the filter the way eth_getLogs does
+func matchLog(l testLog, addresses []common.Address, topics [][]common.Hash) bool {
+	if len(addresses) > 0 {
+		found := false
+		for _, address := range addresses {
+			found = found || address == l.address
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(topics) > len(l.topics) {
+		return false
+	}
+	for position, alternatives := range topics {
+		if len(alternatives) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range alternatives {
+			found = found || topic == l.topics[position]
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLogIndexCandidates(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	addresses := []common.Address{
+		common.HexToAddress("0x01"), common.HexToAddress("0x02"), common.HexToAddress("0x03"),
+	}
+	topics := []common.Hash{
+		common.HexToHash("0xa1"), common.HexToHash("0xa2"), common.HexToHash("0xa3"), common.HexToHash("0xa4"),
+	}
+	randomTopics := func(n int) []common.Hash {
+		hashes := make([]common.Hash, n)
+		for i := range hashes {
+			hashes[i] = topics[r.Intn(len(topics))]
+		}
+		return hashes
+	}
+
+	// the blocks span three sections, most of them without logs
+	idx := NewLogIndex(dbm.NewMemDB(), 0, log.NewNopLogger())
+	const first, last = int64(LogIndexSectionSize - 100), int64(3*LogIndexSectionSize + 50)
+	var logs []testLog
+	for height := first; height <= last; height++ {
+		var blockLogs []testLog
+		if r.Intn(20) == 0 {
+			for i := r.Intn(3) + 1; i > 0; i-- {
+				blockLogs = append(blockLogs, testLog{
+					height:  height,
+					address: addresses[r.Intn(len(addresses))],
+					topics:  randomTopics(r.Intn(logIndexMaxTopics + 1)),
+				})
+			}
+		}
+		commitBlock(t, idx, height, blockLogs...)
+		logs = append(logs, blockLogs...)
+	}
+
+	bFirst, bLast, ok := idx.Bounds()
+	require.True(t, ok)
+	require.Equal(t, first, bFirst)
+	require.Equal(t, last, bLast)
+
+	filters := []struct {
+		addresses []common.Address
+		topics    [][]common.Hash
+	}{
+		{nil, nil},
+		{addresses[:1], nil},
+		{addresses[1:], nil},
+		{nil, [][]common.Hash{{topics[0]}}},
+		{nil, [][]common.Hash{nil, {topics[1], topics[2]}}},
+		{addresses[2:], [][]common.Hash{{topics[3]}, nil, {topics[0]}}},
+		{addresses[:2], [][]common.Hash{nil, nil, nil, {topics[1]}}},
+		{[]common.Address{common.HexToAddress("0x04")}, nil},
+		{nil, [][]common.Hash{{common.HexToHash("0xff")}}},
+	}
+	ranges := [][2]int64{
+		{first, last},
+		{first, first},
+		{LogIndexSectionSize - 1, LogIndexSectionSize},
+		{LogIndexSectionSize + 17, 2*LogIndexSectionSize + 3000},
+		{last - 10, last},
+	}
+
+	for _, f := range filters {
+		for _, rng := range ranges {
+			candidates, ok := idx.Candidates(rng[0], rng[1], f.addresses, f.topics)
+			require.True(t, ok)
+
+			// the candidates are the blocks in range with a log matching the
+			// address and each topic, possibly in distinct logs, filtering
+			// their logs gives the logs of a scan of the range
+			var expectedHeights []int64
+			var expected, filtered []testLog
+			candidate := make(map[int64]bool)
+			for _, height := range candidates {
+				candidate[height] = true
+			}
+			for _, l := range logs {
+				if l.height < rng[0] || l.height > rng[1] {
+					continue
+				}
+				if matchLog(l, f.addresses, f.topics) {
+					expected = append(expected, l)
+					if len(expectedHeights) == 0 || expectedHeights[len(expectedHeights)-1] != l.height {
+						expectedHeights = append(expectedHeights, l.height)
+					}
+				}
+				if candidate[l.height] && matchLog(l, f.addresses, f.topics) {
+					filtered = append(filtered, l)
+				}
+			}
+			require.Subset(t, candidates, expectedHeights, "filter %v, range %v", f, rng)
+			require.Equal(t, expected, filtered, "filter %v, range %v", f, rng)
+			for _, height := range candidates {
+				require.GreaterOrEqual(t, height, rng[0])
+				require.LessOrEqual(t, height, rng[1])
+			}
+			if len(f.topics) == 0 {
+				// without topics the candidates are exact
+				if expectedHeights == nil {
+					expectedHeights = []int64{}
+				}
+				require.Equal(t, expectedHeights, candidates, "filter %v, range %v", f, rng)
+			}
+		}
+	}
+}
+
+func TestLogIndexCandidatesUncovered(t *testing.T) {
+	idx := NewLogIndex(dbm.NewMemDB(), 0, log.NewNopLogger())
+	_, ok := idx.Candidates(1, 10, nil, nil)
+	require.False(t, ok, "empty index")
+
+	for height := int64(10); height <= 20; height++ {
+		commitBlock(t, idx, height)
+	}
+	heights, ok := idx.Candidates(10, 20, nil, nil)
+	require.True(t, ok)
+	require.Empty(t, heights)
+
+	_, ok = idx.Candidates(9, 20, nil, nil)
+	require.False(t, ok, "range starting before the index")
+	_, ok = idx.Candidates(10, 21, nil, nil)
+	require.False(t, ok, "range ending after the index")
+	_, ok = idx.Candidates(15, 14, nil, nil)
+	require.False(t, ok, "empty range")
+	_, ok = idx.Candidates(10, 20, nil, make([][]common.Hash, logIndexMaxTopics+1))
+	require.False(t, ok, "too many topics")
+}
+
+func TestLogIndexRetention(t *testing.T) {
+	const retention = LogIndexSectionSize + 10
+	idx := NewLogIndex(dbm.NewMemDB(), retention, log.NewNopLogger())
+	address := common.HexToAddress("0x01")
+
+	for height := int64(1); height <= 3*LogIndexSectionSize; height++ {
+		commitBlock(t, idx, height, testLog{address: address})
+
+		first, last, ok := idx.Bounds()
+		require.True(t, ok)
+		require.Equal(t, height, last)
+		require.Equal(t, max(1, height-retention+1), first)
+	}
+
+	first, last, _ := idx.Bounds()
+	_, ok := idx.Candidates(first-1, last, nil, nil)
+	require.False(t, ok, "pruned blocks")
+	heights, ok := idx.Candidates(first, last, []common.Address{address}, nil)
+	require.True(t, ok)
+	require.Len(t, heights, retention)
+
+	// only the sections of the retained blocks are kept
+	it, err := idx.db.Iterator(logIndexSectionPrefix(0), logIndexSectionPrefix(uint64(first)/LogIndexSectionSize))
+	require.NoError(t, err)
+	defer it.Close()
+	require.False(t, it.Valid())
+}
+
+func TestLogIndexGapAndReplay(t *testing.T) {
+	idx := NewLogIndex(dbm.NewMemDB(), 0, log.NewNopLogger())
+	address := common.HexToAddress("0x01")
+	for height := int64(1); height <= 10; height++ {
+		commitBlock(t, idx, height, testLog{address: address})
+	}
+
+	// a replayed block is already indexed
+	commitBlock(t, idx, 5)
+	heights, ok := idx.Candidates(1, 10, []common.Address{address}, nil)
+	require.True(t, ok)
+	require.Len(t, heights, 10)
+
+	// the index starts over after a gap
+	commitBlock(t, idx, 20, testLog{address: address})
+	first, last, ok := idx.Bounds()
+	require.True(t, ok)
+	require.EqualValues(t, 20, first)
+	require.EqualValues(t, 20, last)
+	heights, ok = idx.Candidates(20, 20, []common.Address{address}, nil)
+	require.True(t, ok)
+	require.Equal(t, []int64{20}, heights)
+}
+
+func TestBlockLogIDs(t *testing.T) {
+	address := common.HexToAddress("0x01")
+	topic := common.HexToHash("0xa1")
+	l := testLog{address: address, topics: []common.Hash{topic, topic}}
+
+	ids, err := blockLogIDs([]*abci.ExecTxResult{
+		{Events: []abci.Event{txLogEvent(t, l, l)}},
+		// logs of failed txs are reverted
+		{Code: 1, Events: []abci.Event{txLogEvent(t, testLog{address: common.HexToAddress("0x02")})}},
+		{Events: []abci.Event{{Type: "transfer"}}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{
+		{logIndexKindAny},
+		logIndexAddressID(address),
+		logIndexTopicID(0, topic),
+		logIndexTopicID(1, topic),
+	}, ids)
+
+	ids, err = blockLogIDs(nil)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	_, err = blockLogIDs([]*abci.ExecTxResult{{Events: []abci.Event{{
+		Type:       evmvmtypes.EventTypeTxLog,
+		Attributes: []abci.EventAttribute{{Key: evmvmtypes.AttributeKeyTxLog, Value: "{"}},
+	}}}})
+	require.Error(t, err)
+}
@@ -0,0 +1,240 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/go-metrics"
+
+	errorsmod "cosmossdk.io/errors"
+	"cosmossdk.io/log"
+	storetypes "cosmossdk.io/store/types"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	errortypes "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// Reasons a tx is evicted from the mempool
+const (
+	EvictionReasonBlocks = "blocks"
+	EvictionReasonAge    = "age"
+)
+
+// MempoolTTL tracks when the txs the node accepted into its mempool entered
+// it, so the txs left in the mempool for longer than the tx ttl are evicted
+// when the mempool rechecks them after a block. A tx the validators never
+// include, e.g. an EVM tx paying less than their minimum gas prices but not
+// less than the ones of the node, would otherwise be rechecked successfully
+// and stay in the mempool until the node restarts.
+//
+// Like the other tx limits it is local to the node: the ages are counted in
+// the blocks and the block time the node saw since the tx entered its
+// mempool, and a tx evicted by the node may still be included by a validator
+// it was gossiped to.
+type MempoolTTL struct {
+	maxBlocks uint64
+	maxAge    time.Duration
+	logger    log.Logger
+
+	mtx     sync.Mutex
+	entries map[cmttypes.TxKey]mempoolEntry
+	onEvict []func(TxEviction)
+}
+
+var _ storetypes.ABCIListener = (*MempoolTTL)(nil)
+
+// mempoolEntry is the height and the block time a tx entered the mempool at
+type mempoolEntry struct {
+	height int64
+	time   time.Time
+}
+
+// TxEviction is a tx evicted from the mempool of the node, with the EVM hash,
+// the sender and the nonce of an EVM tx
+type TxEviction struct {
+	Hash    string
+	EVMHash string
+	Sender  string
+	Nonce   uint64
+	Reason  string
+	Height  int64
+	Blocks  int64
+	Age     time.Duration
+}
+
+// NewMempoolTTL returns the tracker of the ages of the txs of the mempool, nil
+// if the limits disable both tx ttls.
+func NewMempoolTTL(limits TxLimitsConfig, logger log.Logger) *MempoolTTL {
+	if limits.TxTTLBlocks == 0 && limits.TxTTL <= 0 {
+		return nil
+	}
+	return &MempoolTTL{
+		maxBlocks: limits.TxTTLBlocks,
+		maxAge:    limits.TxTTL,
+		logger:    logger.With("module", "mempool-ttl"),
+		entries:   map[cmttypes.TxKey]mempoolEntry{},
+	}
+}
+
+// OnEvict calls f with every tx evicted from the mempool, f must not block
+func (m *MempoolTTL) OnEvict(f func(TxEviction)) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.onEvict = append(m.onEvict, f)
+}
+
+// track records the tx of key as entering the mempool at height and time,
+// unless it is already tracked
+func (m *MempoolTTL) track(key cmttypes.TxKey, height int64, blockTime time.Time) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if _, ok := m.entries[key]; !ok {
+		m.entries[key] = mempoolEntry{height: height, time: blockTime}
+	}
+}
+
+func (m *MempoolTTL) entry(key cmttypes.TxKey) (mempoolEntry, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	entry, ok := m.entries[key]
+	return entry, ok
+}
+
+func (m *MempoolTTL) forget(key cmttypes.TxKey) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	delete(m.entries, key)
+}
+
+// expired returns the reason the tx of entry must be evicted at height and
+// blockTime, if it must
+func (m *MempoolTTL) expired(entry mempoolEntry, height int64, blockTime time.Time) (string, bool) {
+	if m.maxBlocks > 0 && height-entry.height >= int64(m.maxBlocks) {
+		return EvictionReasonBlocks, true
+	}
+	if m.maxAge > 0 && blockTime.Sub(entry.time) >= m.maxAge {
+		return EvictionReasonAge, true
+	}
+	return "", false
+}
+
+// evict forgets the tx of key and reports its eviction
+func (m *MempoolTTL) evict(key cmttypes.TxKey, tx sdk.Tx, eviction TxEviction) {
+	// the hash CometBFT reports the tx with
+	eviction.Hash = cmtbytes.HexBytes(key[:]).String()
+	if msg, ok := singleEVMTx(tx); ok {
+		ethTx := msg.AsTransaction()
+		eviction.EVMHash = ethTx.Hash().Hex()
+		eviction.Sender = common.BytesToAddress(msg.GetFrom()).Hex()
+		eviction.Nonce = ethTx.Nonce()
+	}
+
+	m.mtx.Lock()
+	delete(m.entries, key)
+	onEvict := m.onEvict
+	m.mtx.Unlock()
+
+	m.logger.Info(
+		"evicted tx from the mempool",
+		"hash", eviction.Hash, "evm_hash", eviction.EVMHash, "reason", eviction.Reason,
+		"blocks", eviction.Blocks, "age", eviction.Age, "height", eviction.Height,
+	)
+	telemetry.IncrCounterWithLabels([]string{"mempool", "evicted_txs"}, 1, []metrics.Label{telemetry.NewLabel("reason", eviction.Reason)})
+	for _, f := range onEvict {
+		f(eviction)
+	}
+}
+
+// ListenFinalizeBlock implements storetypes.ABCIListener, the txs of the
+// block left the mempool.
+func (m *MempoolTTL) ListenFinalizeBlock(_ context.Context, req abci.RequestFinalizeBlock, _ abci.ResponseFinalizeBlock) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for _, bz := range req.Txs {
+		delete(m.entries, cmttypes.Tx(bz).Key())
+	}
+	return nil
+}
+
+// ListenCommit implements storetypes.ABCIListener, the txs which left the
+// mempool without being rechecked, e.g. when the mempool doesn't recheck its
+// txs, are forgotten once they are twice as old as the tx ttl.
+func (m *MempoolTTL) ListenCommit(ctx context.Context, _ abci.ResponseCommit, _ []*storetypes.StoreKVPair) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	height, blockTime := sdkCtx.BlockHeight(), sdkCtx.BlockTime()
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for key, entry := range m.entries {
+		stale := mempoolEntry{
+			height: entry.height + int64(m.maxBlocks),
+			time:   entry.time.Add(m.maxAge),
+		}
+		if _, expired := m.expired(stale, height, blockTime); expired {
+			delete(m.entries, key)
+		}
+	}
+	return nil
+}
+
+// MempoolTTLDecorator evicts the txs older than the tx ttl of the node when
+// the mempool rechecks them, see MempoolTTL. The txs entering the mempool
+// are tracked once the rest of the ante handler accepted them, blocks aren't
+// affected.
+type MempoolTTLDecorator struct {
+	ttl *MempoolTTL
+}
+
+func NewMempoolTTLDecorator(ttl *MempoolTTL) MempoolTTLDecorator {
+	return MempoolTTLDecorator{ttl: ttl}
+}
+
+func (d MempoolTTLDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	if !ctx.IsCheckTx() || simulate || d.ttl == nil {
+		return next(ctx, tx, simulate)
+	}
+	key := cmttypes.Tx(ctx.TxBytes()).Key()
+
+	if ctx.IsReCheckTx() {
+		if entry, ok := d.ttl.entry(key); ok {
+			if reason, expired := d.ttl.expired(entry, ctx.BlockHeight(), ctx.BlockTime()); expired {
+				eviction := TxEviction{
+					Reason: reason,
+					Height: ctx.BlockHeight(),
+					Blocks: ctx.BlockHeight() - entry.height,
+					Age:    ctx.BlockTime().Sub(entry.time),
+				}
+				d.ttl.evict(key, tx, eviction)
+				return ctx, errorsmod.Wrapf(
+					errortypes.ErrTxTimeoutHeight,
+					"tx was evicted from the mempool after %d blocks and %s (%s, %s)",
+					eviction.Blocks, eviction.Age, FlagTxTTLBlocks, FlagTxTTL,
+				)
+			}
+		}
+		newCtx, err := next(ctx, tx, simulate)
+		if err != nil {
+			// the mempool drops the txs failing the recheck
+			d.ttl.forget(key)
+		}
+		return newCtx, err
+	}
+
+	newCtx, err := next(ctx, tx, simulate)
+	if err == nil {
+		d.ttl.track(key, ctx.BlockHeight(), ctx.BlockTime())
+	}
+	return newCtx, err
+}
@@ -0,0 +1,174 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	errortypes "github.com/cosmos/cosmos-sdk/types/errors"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+func TestNewMempoolTTLDisabled(t *testing.T) {
+	require.Nil(t, NewMempoolTTL(TxLimitsConfig{}, log.NewNopLogger()))
+	require.NotNil(t, NewMempoolTTL(TxLimitsConfig{TxTTLBlocks: 1}, log.NewNopLogger()))
+	require.NotNil(t, NewMempoolTTL(DefaultTxLimitsConfig(), log.NewNopLogger()))
+
+	called := false
+	next := func(ctx sdk.Context, _ sdk.Tx, _ bool) (sdk.Context, error) {
+		called = true
+		return ctx, nil
+	}
+	ctx := sdk.Context{}.WithIsCheckTx(true).WithIsReCheckTx(true)
+	_, err := NewMempoolTTLDecorator(nil).AnteHandle(ctx, limitTestTx{}, false, next)
+	require.NoError(t, err)
+	require.True(t, called)
+}
+
+func TestMempoolTTLDecorator(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	ethTx := signedReplacementTx(t, key, 7, 100, 10)
+	evmTx := replacementTestTx(t, key, ethTx)
+	sendTx := limitTestTx{msgs: []sdk.Msg{&banktypes.MsgSend{}}}
+
+	checkTx := func(bz string, height int64, blockTime time.Time) sdk.Context {
+		return sdk.Context{}.WithIsCheckTx(true).WithTxBytes([]byte(bz)).WithBlockHeight(height).WithBlockTime(blockTime)
+	}
+	reCheckTx := func(bz string, height int64, blockTime time.Time) sdk.Context {
+		return checkTx(bz, height, blockTime).WithIsReCheckTx(true)
+	}
+	var nextErr error
+	next := func(ctx sdk.Context, _ sdk.Tx, _ bool) (sdk.Context, error) {
+		return ctx, nextErr
+	}
+
+	t.Run("blocks", func(t *testing.T) {
+		ttl := NewMempoolTTL(TxLimitsConfig{TxTTLBlocks: 3}, log.NewNopLogger())
+		var evictions []TxEviction
+		ttl.OnEvict(func(eviction TxEviction) { evictions = append(evictions, eviction) })
+		decorator := NewMempoolTTLDecorator(ttl)
+
+		_, err := decorator.AnteHandle(checkTx("evm", 10, start), evmTx, false, next)
+		require.NoError(t, err)
+		_, err = decorator.AnteHandle(checkTx("send", 11, start), sendTx, false, next)
+		require.NoError(t, err)
+		// the recheck of a tx gossiped again doesn't make it younger
+		_, err = decorator.AnteHandle(checkTx("evm", 11, start), evmTx, false, next)
+		require.NoError(t, err)
+
+		_, err = decorator.AnteHandle(reCheckTx("evm", 12, start.Add(time.Hour)), evmTx, false, next)
+		require.NoError(t, err)
+		_, err = decorator.AnteHandle(reCheckTx("evm", 13, start.Add(time.Hour)), evmTx, false, next)
+		require.ErrorIs(t, err, errortypes.ErrTxTimeoutHeight)
+		require.Contains(t, err.Error(), "evicted from the mempool after 3 blocks")
+		_, err = decorator.AnteHandle(reCheckTx("send", 13, start.Add(time.Hour)), sendTx, false, next)
+		require.NoError(t, err)
+
+		require.Equal(t, []TxEviction{{
+			Hash:    cmtbytes.HexBytes(cmttypes.Tx("evm").Hash()).String(),
+			EVMHash: ethTx.Hash().Hex(),
+			Sender:  crypto.PubkeyToAddress(key.PublicKey).Hex(),
+			Nonce:   7,
+			Reason:  EvictionReasonBlocks,
+			Height:  13,
+			Blocks:  3,
+			Age:     time.Hour,
+		}}, evictions)
+		_, found := ttl.entry(cmttypes.Tx("evm").Key())
+		require.False(t, found, "the evicted tx should be forgotten")
+
+		_, err = decorator.AnteHandle(reCheckTx("send", 14, start.Add(time.Hour)), sendTx, false, next)
+		require.ErrorIs(t, err, errortypes.ErrTxTimeoutHeight)
+		require.Len(t, evictions, 2)
+		require.Empty(t, evictions[1].EVMHash, "a Cosmos tx has no EVM hash")
+	})
+
+	t.Run("age", func(t *testing.T) {
+		ttl := NewMempoolTTL(TxLimitsConfig{TxTTL: time.Minute}, log.NewNopLogger())
+		var evictions []TxEviction
+		ttl.OnEvict(func(eviction TxEviction) { evictions = append(evictions, eviction) })
+		decorator := NewMempoolTTLDecorator(ttl)
+
+		_, err := decorator.AnteHandle(checkTx("send", 10, start), sendTx, false, next)
+		require.NoError(t, err)
+		_, err = decorator.AnteHandle(reCheckTx("send", 100, start.Add(59*time.Second)), sendTx, false, next)
+		require.NoError(t, err)
+		_, err = decorator.AnteHandle(reCheckTx("send", 101, start.Add(time.Minute)), sendTx, false, next)
+		require.ErrorIs(t, err, errortypes.ErrTxTimeoutHeight)
+		require.Len(t, evictions, 1)
+		require.Equal(t, EvictionReasonAge, evictions[0].Reason)
+		require.Equal(t, int64(91), evictions[0].Blocks)
+	})
+
+	t.Run("rejected txs", func(t *testing.T) {
+		ttl := NewMempoolTTL(TxLimitsConfig{TxTTLBlocks: 1}, log.NewNopLogger())
+		decorator := NewMempoolTTLDecorator(ttl)
+
+		// the txs the ante handler rejects never enter the mempool
+		nextErr = errortypes.ErrInsufficientFee
+		_, err := decorator.AnteHandle(checkTx("send", 10, start), sendTx, false, next)
+		require.ErrorIs(t, err, errortypes.ErrInsufficientFee)
+		_, found := ttl.entry(cmttypes.Tx("send").Key())
+		require.False(t, found)
+
+		// the txs failing the recheck leave it
+		nextErr = nil
+		_, err = decorator.AnteHandle(checkTx("send", 10, start), sendTx, false, next)
+		require.NoError(t, err)
+		nextErr = errortypes.ErrInvalidSequence
+		_, err = decorator.AnteHandle(reCheckTx("send", 10, start), sendTx, false, next)
+		require.ErrorIs(t, err, errortypes.ErrInvalidSequence)
+		_, found = ttl.entry(cmttypes.Tx("send").Key())
+		require.False(t, found)
+		nextErr = nil
+
+		// blocks and simulations aren't affected
+		_, err = decorator.AnteHandle(checkTx("send", 10, start), sendTx, false, next)
+		require.NoError(t, err)
+		block := sdk.Context{}.WithTxBytes([]byte("send")).WithBlockHeight(20)
+		_, err = decorator.AnteHandle(block, sendTx, false, next)
+		require.NoError(t, err)
+		_, err = decorator.AnteHandle(reCheckTx("send", 20, start), sendTx, true, next)
+		require.NoError(t, err)
+	})
+}
+
+func TestMempoolTTLListener(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ttl := NewMempoolTTL(TxLimitsConfig{TxTTLBlocks: 5, TxTTL: time.Hour}, log.NewNopLogger())
+	included, pending, stale := cmttypes.Tx("included"), cmttypes.Tx("pending"), cmttypes.Tx("stale")
+	ttl.track(included.Key(), 10, start)
+	ttl.track(pending.Key(), 10, start)
+	ttl.track(stale.Key(), 4, start)
+
+	// the txs of a block leave the mempool
+	err := ttl.ListenFinalizeBlock(context.Background(), abci.RequestFinalizeBlock{Height: 15, Txs: [][]byte{included}}, abci.ResponseFinalizeBlock{})
+	require.NoError(t, err)
+	_, found := ttl.entry(included.Key())
+	require.False(t, found)
+
+	// the expired txs are left to the recheck, unless they are twice as old
+	// as the ttl, which the recheck would have evicted
+	ctx := sdk.Context{}.WithContext(context.Background()).WithBlockHeight(15).WithBlockTime(start.Add(time.Minute))
+	require.NoError(t, ttl.ListenCommit(ctx, abci.ResponseCommit{}, nil))
+	_, found = ttl.entry(pending.Key())
+	require.True(t, found)
+	_, found = ttl.entry(stale.Key())
+	require.False(t, found)
+
+	ctx = ctx.WithBlockHeight(16).WithBlockTime(start.Add(2 * time.Hour))
+	require.NoError(t, ttl.ListenCommit(ctx, abci.ResponseCommit{}, nil))
+	_, found = ttl.entry(pending.Key())
+	require.False(t, found)
+}
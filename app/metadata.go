@@ -0,0 +1,97 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ChainMetadata describes the chain for explorers, wallets and tooling that
+// configure themselves from a single document.
+type ChainMetadata struct {
+	ChainID    string         `json:"chain_id"`
+	EVMChainID uint64         `json:"evm_chain_id"`
+	Bech32     Bech32Prefixes `json:"bech32_prefixes"`
+	Denoms     []DenomUnit    `json:"denoms"`
+	BaseDenom  string         `json:"base_denom"`
+	Display    string         `json:"display_denom"`
+	Endpoints  Endpoints      `json:"endpoints"`
+	Node       NodeMetadata   `json:"node"`
+}
+
+// Bech32Prefixes lists the bech32 prefixes used by the chain
+type Bech32Prefixes struct {
+	AccountAddress   string `json:"account_address"`
+	AccountPubKey    string `json:"account_pubkey"`
+	ValidatorAddress string `json:"validator_address"`
+	ValidatorPubKey  string `json:"validator_pubkey"`
+	ConsensusAddress string `json:"consensus_address"`
+	ConsensusPubKey  string `json:"consensus_pubkey"`
+}
+
+// DenomUnit is a denomination of the native coin with its decimals relative to the base denom
+type DenomUnit struct {
+	Denom    string `json:"denom"`
+	Exponent uint32 `json:"exponent"`
+}
+
+// Endpoints are the public interfaces served by the node, taken from its config
+type Endpoints struct {
+	RPC              string `json:"rpc"`
+	API              string `json:"api,omitempty"`
+	GRPC             string `json:"grpc,omitempty"`
+	JSONRPC          string `json:"json_rpc,omitempty"`
+	JSONRPCWebSocket string `json:"json_rpc_ws,omitempty"`
+}
+
+// NodeMetadata describes the node answering the query and the binary querying it
+type NodeMetadata struct {
+	Moniker      string `json:"moniker,omitempty"`
+	AppVersion   string `json:"app_version"`
+	CometBFT     string `json:"cometbft_version,omitempty"`
+	LatestHeight int64  `json:"latest_block_height"`
+	CatchingUp   bool   `json:"catching_up"`
+
+	BinaryVersion string `json:"binary_version"`
+	BinaryCommit  string `json:"binary_commit"`
+}
+
+var evmChainIDRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*_([0-9]+)-[0-9]+$`)
+
+// EVMChainID extracts the EIP-155 chain id from a cosmos chain id of the form
+// <name>_<evm chain id>-<epoch>, e.g. tacchain_2391-1.
+func EVMChainID(chainID string) (uint64, error) {
+	matches := evmChainIDRegex.FindStringSubmatch(chainID)
+	if matches == nil {
+		return 0, fmt.Errorf("invalid chain id %q, expected <name>_<evm chain id>-<epoch>", chainID)
+	}
+	return strconv.ParseUint(matches[1], 10, 64)
+}
+
+// NewChainMetadata returns the static metadata of the chain with the given id,
+// endpoints and node information are filled in by the caller.
+func NewChainMetadata(chainID string) (ChainMetadata, error) {
+	evmChainID, err := EVMChainID(chainID)
+	if err != nil {
+		return ChainMetadata{}, err
+	}
+
+	return ChainMetadata{
+		ChainID:    chainID,
+		EVMChainID: evmChainID,
+		Bech32: Bech32Prefixes{
+			AccountAddress:   Bech32PrefixAccAddr,
+			AccountPubKey:    Bech32PrefixAccPub,
+			ValidatorAddress: Bech32PrefixValAddr,
+			ValidatorPubKey:  Bech32PrefixValPub,
+			ConsensusAddress: Bech32PrefixConsAddr,
+			ConsensusPubKey:  Bech32PrefixConsPub,
+		},
+		Denoms: []DenomUnit{
+			{Denom: BaseDenom, Exponent: 0},
+			{Denom: DisplayDenom, Exponent: BaseDenomUnit},
+		},
+		BaseDenom: BaseDenom,
+		Display:   DisplayDenom,
+	}, nil
+}
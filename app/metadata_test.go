@@ -0,0 +1,51 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEVMChainID(t *testing.T) {
+	testCases := []struct {
+		chainID   string
+		expected  uint64
+		expectErr bool
+	}{
+		{DefaultChainID, 2391, false},
+		{"tacchain_239-1", 239, false},
+		{"tac-testnet_2390-12", 2390, false},
+		{"tacchain", 0, true},
+		{"tacchain_2391", 0, true},
+		{"tacchain-2391-1", 0, true},
+		{"tacchain_abc-1", 0, true},
+		{"_2391-1", 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.chainID, func(t *testing.T) {
+			evmChainID, err := EVMChainID(tc.chainID)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, evmChainID)
+		})
+	}
+}
+
+func TestNewChainMetadata(t *testing.T) {
+	metadata, err := NewChainMetadata(DefaultChainID)
+	require.NoError(t, err)
+
+	require.Equal(t, DefaultChainID, metadata.ChainID)
+	require.Equal(t, uint64(2391), metadata.EVMChainID)
+	require.Equal(t, "tac", metadata.Bech32.AccountAddress)
+	require.Equal(t, "tacvaloper", metadata.Bech32.ValidatorAddress)
+	require.Equal(t, "tacvalcons", metadata.Bech32.ConsensusAddress)
+	require.Equal(t, []DenomUnit{{Denom: "utac", Exponent: 0}, {Denom: "tac", Exponent: 18}}, metadata.Denoms)
+
+	_, err = NewChainMetadata("invalid")
+	require.Error(t, err)
+}
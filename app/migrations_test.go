@@ -0,0 +1,371 @@
+package app
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	gogogrpc "github.com/cosmos/gogoproto/grpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"cosmossdk.io/log"
+	"cosmossdk.io/store/prefix"
+	storetypes "cosmossdk.io/store/types"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	paramstypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	feehistorytypes "github.com/Asphere-xyz/tacchain/x/feehistory/types"
+)
+
+var (
+	updateMigrationFixtures = flag.Bool("update-migrations", false, "write the post-states of the migration fixtures instead of checking them")
+	captureMigrationFixture = flag.String("capture-migration", "", "write the state of a module as the pre-state of the migration from its current consensus version")
+)
+
+// migrationFixturesDir holds a fixture per migration of the Tac modules,
+// <module>/v<from>.json for the migration from consensus version <from>
+var migrationFixturesDir = filepath.Join("testdata", "migrations")
+
+// tacModulePath prefixes the package of the modules whose migrations must
+// ship with a fixture
+const tacModulePath = "github.com/Asphere-xyz/tacchain/"
+
+// moduleState is the state of a module: the entries of its store, keys and
+// values in hex, and of its params subspace, param keys and their amino JSON
+// values as the params store holds them.
+type moduleState struct {
+	Store  map[string]string `json:"store"`
+	Params map[string]string `json:"params"`
+}
+
+// migrationFixture is the state of a module before a migration and the state
+// the migration must leave. Post is written by -update-migrations.
+type migrationFixture struct {
+	Pre  moduleState  `json:"pre"`
+	Post *moduleState `json:"post"`
+}
+
+func migrationFixturePath(dir, name string, from uint64) string {
+	return filepath.Join(dir, name, fmt.Sprintf("v%d.json", from))
+}
+
+func loadMigrationFixture(path string) (migrationFixture, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return migrationFixture{}, err
+	}
+	var fixture migrationFixture
+	if err := json.Unmarshal(bz, &fixture); err != nil {
+		return migrationFixture{}, fmt.Errorf("invalid migration fixture %s: %w", path, err)
+	}
+	return fixture, nil
+}
+
+func writeMigrationFixture(path string, fixture migrationFixture) error {
+	bz, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(bz, '\n'), 0o644)
+}
+
+// migrationRecorder is a module.Configurator recording the migrations the
+// modules register, their services are dropped
+type migrationRecorder struct {
+	migrations map[string]map[uint64]module.MigrationHandler
+}
+
+var _ module.Configurator = (*migrationRecorder)(nil)
+
+func (r *migrationRecorder) RegisterService(*grpc.ServiceDesc, interface{}) {}
+
+func (r *migrationRecorder) Error() error { return nil }
+
+func (r *migrationRecorder) MsgServer() gogogrpc.Server { return r }
+
+func (r *migrationRecorder) QueryServer() gogogrpc.Server { return r }
+
+func (r *migrationRecorder) RegisterMigration(moduleName string, fromVersion uint64, handler module.MigrationHandler) error {
+	if r.migrations[moduleName] == nil {
+		r.migrations[moduleName] = map[uint64]module.MigrationHandler{}
+	}
+	if _, ok := r.migrations[moduleName][fromVersion]; ok {
+		return fmt.Errorf("another migration of %s from version %d is registered", moduleName, fromVersion)
+	}
+	r.migrations[moduleName][fromVersion] = handler
+	return nil
+}
+
+// tacModules returns the consensus versions of the Tac modules of app
+func tacModules(app *TacChainApp) map[string]uint64 {
+	versions := map[string]uint64{}
+	for name, mod := range app.ModuleManager.Modules {
+		if !strings.HasPrefix(reflect.TypeOf(mod).PkgPath(), tacModulePath) {
+			continue
+		}
+		if mod, ok := mod.(module.HasConsensusVersion); ok {
+			versions[name] = mod.ConsensusVersion()
+		}
+	}
+	return versions
+}
+
+// registeredMigrations returns the migrations the Tac modules of app register
+// along with their services
+func registeredMigrations(t *testing.T, app *TacChainApp) map[string]map[uint64]module.MigrationHandler {
+	t.Helper()
+
+	recorder := &migrationRecorder{migrations: map[string]map[uint64]module.MigrationHandler{}}
+	for name := range tacModules(app) {
+		if mod, ok := app.ModuleManager.Modules[name].(module.HasServices); ok {
+			mod.RegisterServices(recorder)
+		}
+	}
+	return recorder.migrations
+}
+
+// getModuleState returns the state of module name in ctx
+func getModuleState(ctx sdk.Context, app *TacChainApp, name string) moduleState {
+	state := moduleState{Store: map[string]string{}, Params: map[string]string{}}
+	if key := app.GetKey(name); key != nil {
+		iterateStore(ctx.KVStore(key), func(key, value []byte) {
+			state.Store[hex.EncodeToString(key)] = hex.EncodeToString(value)
+		})
+	}
+	iterateStore(paramsStore(ctx, app, name), func(key, value []byte) {
+		state.Params[string(key)] = string(value)
+	})
+	return state
+}
+
+// setModuleState replaces the state of module name in ctx with state
+func setModuleState(ctx sdk.Context, app *TacChainApp, name string, state moduleState) error {
+	key := app.GetKey(name)
+	if key == nil && len(state.Store) > 0 {
+		return fmt.Errorf("module %s has no store", name)
+	}
+	if key != nil {
+		if err := replaceStore(ctx.KVStore(key), state.Store, hex.DecodeString); err != nil {
+			return err
+		}
+	}
+	return replaceStore(paramsStore(ctx, app, name), state.Params, func(s string) ([]byte, error) {
+		return []byte(s), nil
+	})
+}
+
+// paramsStore returns the store of the params subspace of module name
+func paramsStore(ctx sdk.Context, app *TacChainApp, name string) storetypes.KVStore {
+	return prefix.NewStore(ctx.KVStore(app.GetKey(paramstypes.StoreKey)), append([]byte(name), '/'))
+}
+
+func iterateStore(store storetypes.KVStore, cb func(key, value []byte)) {
+	iterator := store.Iterator(nil, nil)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		cb(iterator.Key(), iterator.Value())
+	}
+}
+
+// replaceStore deletes the entries of store and sets entries, decoded with
+// decode
+func replaceStore(store storetypes.KVStore, entries map[string]string, decode func(string) ([]byte, error)) error {
+	var keys [][]byte
+	iterateStore(store, func(key, _ []byte) {
+		keys = append(keys, key)
+	})
+	for _, key := range keys {
+		store.Delete(key)
+	}
+	for k, v := range entries {
+		key, err := decode(k)
+		if err != nil {
+			return fmt.Errorf("invalid key %q: %w", k, err)
+		}
+		value, err := decode(v)
+		if err != nil {
+			return fmt.Errorf("invalid value of %q: %w", k, err)
+		}
+		store.Set(key, value)
+	}
+	return nil
+}
+
+// runMigrationFixture runs the migration handler of module name on the
+// pre-state of fixture, in a cache of the state of app, and returns the
+// state it leaves
+func runMigrationFixture(app *TacChainApp, name string, handler module.MigrationHandler, fixture migrationFixture) (moduleState, error) {
+	ctx, _ := app.NewContext(false).CacheContext()
+	if err := setModuleState(ctx, app, name, fixture.Pre); err != nil {
+		return moduleState{}, err
+	}
+	if err := handler(ctx); err != nil {
+		return moduleState{}, err
+	}
+	return getModuleState(ctx, app, name), nil
+}
+
+func newMigrationTestApp(t *testing.T) *TacChainApp {
+	t.Helper()
+
+	return NewTacChainAppWithCustomOptions(t, false, 0, SetupOptions{
+		Logger:  log.NewNopLogger(),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+}
+
+// TestMigrationFixtures checks every migration of the Tac modules against its
+// golden fixture: the module state before the upgrade, as the previous
+// consensus version wrote it, and the state the migration must leave. A
+// module bumping its consensus version must register a migration from the
+// previous version and ship its fixture, written with -capture-migration
+// before the bump and completed with -update-migrations.
+func TestMigrationFixtures(t *testing.T) {
+	app := newMigrationTestApp(t)
+	migrations := registeredMigrations(t, app)
+
+	versions := tacModules(app)
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for from := uint64(1); from < versions[name]; from++ {
+			require.Contains(t, migrations[name], from, "module %s is at consensus version %d without a migration from version %d", name, versions[name], from)
+			_, err := os.Stat(migrationFixturePath(migrationFixturesDir, name, from))
+			require.NoError(t, err, "module %s has no fixture of its migration from version %d, capture its state with -capture-migration %s before bumping its version", name, from, name)
+		}
+	}
+
+	paths, err := filepath.Glob(filepath.Join(migrationFixturesDir, "*", "v*.json"))
+	require.NoError(t, err)
+	for _, path := range paths {
+		name := filepath.Base(filepath.Dir(path))
+		var from uint64
+		_, err := fmt.Sscanf(filepath.Base(path), "v%d.json", &from)
+		require.NoError(t, err, "invalid fixture name %s", path)
+		handler, ok := migrations[name][from]
+		require.True(t, ok, "fixture %s has no registered migration", path)
+
+		t.Run(fmt.Sprintf("%s/v%d", name, from), func(t *testing.T) {
+			fixture, err := loadMigrationFixture(path)
+			require.NoError(t, err)
+			post, err := runMigrationFixture(app, name, handler, fixture)
+			require.NoError(t, err)
+
+			if *updateMigrationFixtures {
+				fixture.Post = &post
+				require.NoError(t, writeMigrationFixture(path, fixture))
+				return
+			}
+			require.NotNil(t, fixture.Post, "fixture %s has no post-state, write it with -update-migrations", path)
+			require.Equal(t, *fixture.Post, post, "migration of %s from version %d left another state than its fixture", name, from)
+		})
+	}
+}
+
+// TestCaptureMigrationFixture writes the state of the module named by
+// -capture-migration after the genesis of the test app as the pre-state of
+// the migration from its current consensus version. Extend it with the
+// entries the migration must convert before bumping the version.
+func TestCaptureMigrationFixture(t *testing.T) {
+	name := *captureMigrationFixture
+	if name == "" {
+		t.Skip("no module to capture, set -capture-migration")
+	}
+	app := newMigrationTestApp(t)
+	version, ok := tacModules(app)[name]
+	require.True(t, ok, "%s isn't a Tac module", name)
+
+	path := migrationFixturePath(migrationFixturesDir, name, version)
+	_, err := os.Stat(path)
+	require.True(t, errors.Is(err, os.ErrNotExist), "fixture %s exists", path)
+	state := getModuleState(app.NewContext(false), app, name)
+	require.NoError(t, writeMigrationFixture(path, migrationFixture{Pre: state}))
+}
+
+// TestMigrationHarness runs a migration of the feehistory state through the
+// harness of the fixtures: the pre-state replaces the module state, the
+// handler sees it through the keeper and the post-state is read back.
+func TestMigrationHarness(t *testing.T) {
+	app := newMigrationTestApp(t)
+	require.Equal(t, uint64(1), tacModules(app)[feehistorytypes.ModuleName])
+
+	dir := t.TempDir()
+	path := migrationFixturePath(dir, feehistorytypes.ModuleName, 1)
+	require.NoError(t, writeMigrationFixture(path, migrationFixture{Pre: moduleState{
+		Store: map[string]string{
+			hex.EncodeToString(feehistorytypes.BlockFeeKey(5)): hex.EncodeToString([]byte(`{"height":5}`)),
+			hex.EncodeToString(feehistorytypes.BlockFeeKey(6)): hex.EncodeToString([]byte(`{"height":6}`)),
+		},
+		Params: map[string]string{"Retention": `"1024"`},
+	}}))
+	fixture, err := loadMigrationFixture(path)
+	require.NoError(t, err)
+	require.Nil(t, fixture.Post)
+
+	// a migration lowering the retention and dropping the blocks past it
+	handler := func(ctx sdk.Context) error {
+		params := app.FeeHistoryKeeper.GetParams(ctx)
+		if params.Retention != feehistorytypes.DefaultRetention {
+			return fmt.Errorf("unexpected retention %d", params.Retention)
+		}
+		params.Retention = 1
+		app.FeeHistoryKeeper.SetParams(ctx, params)
+		ctx.KVStore(app.GetKey(feehistorytypes.StoreKey)).Delete(feehistorytypes.BlockFeeKey(5))
+		return nil
+	}
+	post, err := runMigrationFixture(app, feehistorytypes.ModuleName, handler, fixture)
+	require.NoError(t, err)
+	require.Equal(t, moduleState{
+		Store: map[string]string{
+			hex.EncodeToString(feehistorytypes.BlockFeeKey(6)): hex.EncodeToString([]byte(`{"height":6}`)),
+		},
+		Params: map[string]string{"Retention": `"1"`},
+	}, post)
+
+	// the migration ran in a cache, the state of the app is untouched
+	ctx := app.NewContext(false)
+	require.Equal(t, feehistorytypes.DefaultParams(), app.FeeHistoryKeeper.GetParams(ctx))
+	require.Empty(t, getModuleState(ctx, app, feehistorytypes.ModuleName).Store)
+
+	// the post-state round trips through the fixture file
+	fixture.Post = &post
+	require.NoError(t, writeMigrationFixture(path, fixture))
+	written, err := loadMigrationFixture(path)
+	require.NoError(t, err)
+	require.Equal(t, fixture, written)
+
+	// a failing migration and a malformed pre-state are reported
+	_, err = runMigrationFixture(app, feehistorytypes.ModuleName, func(sdk.Context) error {
+		return errors.New("migration failed")
+	}, fixture)
+	require.ErrorContains(t, err, "migration failed")
+	_, err = runMigrationFixture(app, feehistorytypes.ModuleName, handler, migrationFixture{Pre: moduleState{Store: map[string]string{"zz": ""}}})
+	require.ErrorContains(t, err, "invalid key")
+
+	// the recorder keeps the migrations by module and version
+	recorder := &migrationRecorder{migrations: map[string]map[uint64]module.MigrationHandler{}}
+	require.NoError(t, recorder.RegisterMigration(feehistorytypes.ModuleName, 1, handler))
+	require.Error(t, recorder.RegisterMigration(feehistorytypes.ModuleName, 1, handler))
+	require.Contains(t, recorder.migrations[feehistorytypes.ModuleName], uint64(1))
+}
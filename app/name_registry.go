@@ -0,0 +1,62 @@
+package app
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	nameservicekeeper "github.com/Asphere-xyz/tacchain/x/nameservice/keeper"
+	nameservicetypes "github.com/Asphere-xyz/tacchain/x/nameservice/types"
+)
+
+// NameRegistryDecorator executes the EVM txs sent to the name registry
+// address. Like the contract metadata registry, no code lives at the address,
+// the EVM runs the txs as plain transfers and the decorator handles their
+// calldata once they succeeded, so any EVM account registers and manages its
+// names with its wallet. The registration fee is paid from the bank balance of
+// the sender, the tx itself can't carry value, and the gas of the call
+// through ChainCallGas. A registry call that fails fails its whole tx.
+type NameRegistryDecorator struct {
+	keeper nameservicekeeper.Keeper
+	gas    ChainCallGas
+}
+
+// NewNameRegistryDecorator returns a post decorator handling the name registry calls
+func NewNameRegistryDecorator(keeper nameservicekeeper.Keeper, gas ChainCallGas) NameRegistryDecorator {
+	return NameRegistryDecorator{keeper: keeper, gas: gas}
+}
+
+func (d NameRegistryDecorator) PostHandle(ctx sdk.Context, tx sdk.Tx, simulate, success bool, next sdk.PostHandler) (sdk.Context, error) {
+	if !success {
+		return next(ctx, tx, simulate, success)
+	}
+
+	registry := nameservicetypes.RegistryAddress()
+	for _, msg := range tx.GetMsgs() {
+		ethMsg, ok := msg.(*evmvmtypes.MsgEthereumTx)
+		if !ok {
+			continue
+		}
+		ethTx := ethMsg.AsTransaction()
+		if ethTx.To() == nil || *ethTx.To() != registry {
+			continue
+		}
+
+		if ethTx.Value().Sign() != 0 {
+			return ctx, errorsmod.Wrap(nameservicetypes.ErrInvalidRegistryCall, "name registry calls can't transfer value, the fee is paid from the balance")
+		}
+		sender := common.BytesToAddress(ethMsg.GetFrom())
+		err := d.gas.Handle(ctx, sender, ethTx, func(ctx sdk.Context) error {
+			return d.keeper.HandleRegistryCall(ctx, sender, ethTx.Data())
+		})
+		if err != nil {
+			return ctx, err
+		}
+	}
+
+	return next(ctx, tx, simulate, success)
+}
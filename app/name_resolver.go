@@ -0,0 +1,95 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	storetypes "cosmossdk.io/store/types"
+
+	"github.com/cosmos/evm/x/vm/statedb"
+
+	nameservicekeeper "github.com/Asphere-xyz/tacchain/x/nameservice/keeper"
+	nameservicetypes "github.com/Asphere-xyz/tacchain/x/nameservice/types"
+)
+
+// NameResolverGas is the gas of a call to the name resolver precompile, which
+// reads at most two store entries
+const NameResolverGas uint64 = 5_000
+
+var _ vm.PrecompiledContract = NameResolverPrecompile{}
+
+// NameResolverPrecompile lets contracts resolve the names of x/nameservice,
+// see nameservicetypes.ResolverABI. It only reads, the names are managed
+// through the name registry address. Like the precompiles of Cosmos EVM it
+// only runs once its address is in the active_static_precompiles of x/vm.
+type NameResolverPrecompile struct {
+	keeper nameservicekeeper.Keeper
+}
+
+// NewNameResolverPrecompile returns the name resolver precompile
+func NewNameResolverPrecompile(keeper nameservicekeeper.Keeper) NameResolverPrecompile {
+	return NameResolverPrecompile{keeper: keeper}
+}
+
+// Address implements vm.PrecompiledContract
+func (NameResolverPrecompile) Address() common.Address {
+	return nameservicetypes.ResolverAddress()
+}
+
+// RequiredGas implements vm.PrecompiledContract
+func (NameResolverPrecompile) RequiredGas(_ []byte) uint64 {
+	return NameResolverGas
+}
+
+// Run implements vm.PrecompiledContract
+func (p NameResolverPrecompile) Run(evm *vm.EVM, contract *vm.Contract, _ bool) ([]byte, error) {
+	if contract.Value().Sign() != 0 {
+		return nil, errors.New("name resolver calls can't transfer value")
+	}
+	if len(contract.Input) < 4 {
+		return nil, errors.New("missing method selector")
+	}
+	method, err := nameservicetypes.ResolverABI.MethodById(contract.Input[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Unpack(contract.Input[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s arguments: %w", method.Name, err)
+	}
+
+	stateDB, ok := evm.StateDB.(*statedb.StateDB)
+	if !ok {
+		return nil, errors.New("name resolver must run in the cosmos evm")
+	}
+	ctx, err := stateDB.GetCacheContext()
+	if err != nil {
+		return nil, err
+	}
+	// the reads are paid by NameResolverGas
+	ctx = ctx.WithGasMeter(storetypes.NewInfiniteGasMeter())
+
+	switch method.Name {
+	case nameservicetypes.MethodResolve:
+		target, _ := p.keeper.Resolve(ctx, args[0].(string))
+		return method.Outputs.Pack(target)
+	case nameservicetypes.MethodReverseResolve:
+		name, _ := p.keeper.ReverseLookup(ctx, args[0].(common.Address))
+		return method.Outputs.Pack(name)
+	default:
+		record, found := p.keeper.GetRecord(ctx, args[0].(string))
+		if !found {
+			return method.Outputs.Pack(common.Address{}, common.Address{}, int64(0))
+		}
+		return method.Outputs.Pack(record.OwnerAddress(), record.TargetAddress(), record.Expiry)
+	}
+}
+
+// withNameResolverPrecompile adds the name resolver precompile to precompiles
+func withNameResolverPrecompile(precompiles map[common.Address]vm.PrecompiledContract, keeper nameservicekeeper.Keeper) map[common.Address]vm.PrecompiledContract {
+	precompiles[nameservicetypes.ResolverAddress()] = NewNameResolverPrecompile(keeper)
+	return precompiles
+}
@@ -0,0 +1,358 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	gogoproto "github.com/cosmos/gogoproto/proto"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/cosmos/cosmos-sdk/version"
+)
+
+// OpenAPIPath is the route of the REST server serving the OpenAPI document
+// when the swagger API is enabled in app.toml
+const OpenAPIPath = "/swagger.json"
+
+// rpcStatusDefinition is the error returned by the grpc-gateway for every route
+const rpcStatusDefinition = "grpc.gateway.runtime.Error"
+
+// OpenAPIDoc is a Swagger 2.0 document of the REST gateway
+type OpenAPIDoc struct {
+	Swagger     string                                  `json:"swagger"`
+	Info        OpenAPIInfo                             `json:"info"`
+	Consumes    []string                                `json:"consumes"`
+	Produces    []string                                `json:"produces"`
+	Paths       map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Definitions map[string]*OpenAPISchema               `json:"definitions"`
+}
+
+// OpenAPIInfo describes the API
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPIOperation documents a route of the gateway
+type OpenAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary,omitempty"`
+	Tags        []string                   `json:"tags"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter is a path, query or body parameter of an operation
+type OpenAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Type     string         `json:"type,omitempty"`
+	Format   string         `json:"format,omitempty"`
+	Items    *OpenAPISchema `json:"items,omitempty"`
+	Schema   *OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPIResponse is a response of an operation
+type OpenAPIResponse struct {
+	Description string         `json:"description"`
+	Schema      *OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPISchema is the JSON schema of a message or field
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+// NewOpenAPIDoc documents every route of the REST gateway. The routes are read
+// from the google.api.http annotations of the services registered in the
+// binary, so the document covers the SDK, EVM and IBC modules and can't get
+// out of date with the gateway. The tac modules have no gRPC services, hence
+// no routes: their state is read with the tacchaind q tac queries.
+func NewOpenAPIDoc() (*OpenAPIDoc, error) {
+	files, err := gogoproto.MergedRegistry()
+	if err != nil {
+		return nil, err
+	}
+
+	g := openAPIGenerator{
+		doc: &OpenAPIDoc{
+			Swagger:  "2.0",
+			Info:     OpenAPIInfo{Title: "TAC Chain REST API", Version: version.Version},
+			Consumes: []string{"application/json"},
+			Produces: []string{"application/json"},
+			Paths:    make(map[string]map[string]*OpenAPIOperation),
+			Definitions: map[string]*OpenAPISchema{
+				rpcStatusDefinition: {
+					Type: "object",
+					Properties: map[string]*OpenAPISchema{
+						"error":   {Type: "string"},
+						"code":    {Type: "integer", Format: "int32"},
+						"message": {Type: "string"},
+						"details": {Type: "array", Items: anySchema()},
+					},
+				},
+			},
+		},
+	}
+
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		services := fd.Services()
+		for i := 0; i < services.Len(); i++ {
+			methods := services.Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				if err = g.addMethod(methods.Get(j)); err != nil {
+					return false
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g.doc, nil
+}
+
+type openAPIGenerator struct {
+	doc *OpenAPIDoc
+}
+
+// pathParamPattern matches the variables of a google.api.http path template,
+// e.g. {address} or {denom=**}
+var pathParamPattern = regexp.MustCompile(`\{([^}=]+)(=[^}]*)?\}`)
+
+func (g openAPIGenerator) addMethod(method protoreflect.MethodDescriptor) error {
+	rule, err := httpRule(method)
+	if err != nil || rule == nil {
+		return err
+	}
+
+	rules := append([]*annotations.HttpRule{rule}, rule.AdditionalBindings...)
+	for i, rule := range rules {
+		httpMethod, template := httpPattern(rule)
+		if template == "" {
+			continue
+		}
+
+		operationID := fmt.Sprintf("%s_%s", method.Parent().FullName(), method.Name())
+		if i > 0 {
+			operationID = fmt.Sprintf("%s%d", operationID, i)
+		}
+		op := &OpenAPIOperation{
+			OperationID: operationID,
+			Summary:     strings.TrimSpace(method.ParentFile().SourceLocations().ByDescriptor(method).LeadingComments),
+			Tags:        []string{string(method.Parent().FullName())},
+			Responses: map[string]OpenAPIResponse{
+				"200":     {Description: "A successful response.", Schema: g.messageSchema(method.Output())},
+				"default": {Description: "An unexpected error response.", Schema: &OpenAPISchema{Ref: "#/definitions/" + rpcStatusDefinition}},
+			},
+		}
+
+		// path variables, the body and the remaining fields as query parameters
+		path := pathParamPattern.ReplaceAllString(template, "{$1}")
+		inPath := make(map[string]bool)
+		for _, match := range pathParamPattern.FindAllStringSubmatch(template, -1) {
+			inPath[match[1]] = true
+			op.Parameters = append(op.Parameters, OpenAPIParameter{Name: match[1], In: "path", Required: true, Type: "string"})
+		}
+		switch rule.Body {
+		case "":
+			op.Parameters = append(op.Parameters, g.queryParams(method.Input(), "", inPath, 0)...)
+		case "*":
+			op.Parameters = append(op.Parameters, OpenAPIParameter{Name: "body", In: "body", Required: true, Schema: g.messageSchema(method.Input())})
+		default:
+			if field := method.Input().Fields().ByName(protoreflect.Name(rule.Body)); field != nil {
+				op.Parameters = append(op.Parameters, OpenAPIParameter{Name: "body", In: "body", Required: true, Schema: g.fieldSchema(field)})
+			}
+		}
+
+		if g.doc.Paths[path] == nil {
+			g.doc.Paths[path] = make(map[string]*OpenAPIOperation)
+		}
+		g.doc.Paths[path][httpMethod] = op
+	}
+	return nil
+}
+
+// queryParams flattens the fields of msg into query parameters, messages like
+// the pagination are expanded to e.g. pagination.limit.
+func (g openAPIGenerator) queryParams(msg protoreflect.MessageDescriptor, prefix string, inPath map[string]bool, depth int) []OpenAPIParameter {
+	var params []OpenAPIParameter
+	fields := msg.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		name := prefix + string(field.Name())
+		if inPath[name] || field.IsMap() {
+			continue
+		}
+
+		if field.Kind() == protoreflect.MessageKind || field.Kind() == protoreflect.GroupKind {
+			if !field.IsList() && depth < 2 && !isWellKnownType(field.Message()) {
+				params = append(params, g.queryParams(field.Message(), name+".", inPath, depth+1)...)
+			}
+			continue
+		}
+
+		schema := g.fieldSchema(field)
+		param := OpenAPIParameter{Name: name, In: "query", Type: schema.Type, Format: schema.Format, Items: schema.Items}
+		params = append(params, param)
+	}
+	sort.SliceStable(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	return params
+}
+
+// messageSchema returns a reference to the definition of msg, adding it and the
+// messages it refers to on first use.
+func (g openAPIGenerator) messageSchema(msg protoreflect.MessageDescriptor) *OpenAPISchema {
+	if schema := wellKnownSchema(msg); schema != nil {
+		return schema
+	}
+
+	name := string(msg.FullName())
+	ref := &OpenAPISchema{Ref: "#/definitions/" + name}
+	if _, ok := g.doc.Definitions[name]; ok {
+		return ref
+	}
+
+	def := &OpenAPISchema{Type: "object", Properties: make(map[string]*OpenAPISchema)}
+	// added before the fields so recursive messages refer to it
+	g.doc.Definitions[name] = def
+	fields := msg.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		// the gateway marshals with the original proto field names
+		def.Properties[string(fields.Get(i).Name())] = g.fieldSchema(fields.Get(i))
+	}
+	return ref
+}
+
+func (g openAPIGenerator) fieldSchema(field protoreflect.FieldDescriptor) *OpenAPISchema {
+	if field.IsMap() {
+		return &OpenAPISchema{Type: "object", AdditionalProperties: g.singularSchema(field.MapValue())}
+	}
+	schema := g.singularSchema(field)
+	if field.IsList() {
+		return &OpenAPISchema{Type: "array", Items: schema}
+	}
+	return schema
+}
+
+// singularSchema maps a field to its proto3 JSON representation
+func (g openAPIGenerator) singularSchema(field protoreflect.FieldDescriptor) *OpenAPISchema {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return &OpenAPISchema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return &OpenAPISchema{Type: "string", Format: "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &OpenAPISchema{Type: "string", Format: "uint64"}
+	case protoreflect.FloatKind:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case protoreflect.BytesKind:
+		return &OpenAPISchema{Type: "string", Format: "byte"}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return g.messageSchema(field.Message())
+	default:
+		// strings and enums, which are marshaled by name
+		return &OpenAPISchema{Type: "string"}
+	}
+}
+
+func anySchema() *OpenAPISchema {
+	return &OpenAPISchema{
+		Type:                 "object",
+		Properties:           map[string]*OpenAPISchema{"@type": {Type: "string"}},
+		AdditionalProperties: &OpenAPISchema{},
+	}
+}
+
+func isWellKnownType(msg protoreflect.MessageDescriptor) bool {
+	return wellKnownSchema(msg) != nil
+}
+
+// wellKnownSchema returns the schema of the well known types with a special
+// JSON representation
+func wellKnownSchema(msg protoreflect.MessageDescriptor) *OpenAPISchema {
+	switch msg.FullName() {
+	case "google.protobuf.Any":
+		return anySchema()
+	case "google.protobuf.Timestamp":
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	case "google.protobuf.Duration", "google.protobuf.FieldMask":
+		return &OpenAPISchema{Type: "string"}
+	case "google.protobuf.Struct", "google.protobuf.Value":
+		return &OpenAPISchema{Type: "object"}
+	}
+	return nil
+}
+
+// httpRule returns the google.api.http annotation of a method. The option is
+// re-parsed since descriptors of gogoproto files carry it as unknown field.
+func httpRule(method protoreflect.MethodDescriptor) (*annotations.HttpRule, error) {
+	opts, ok := method.Options().(*descriptorpb.MethodOptions)
+	if !ok || opts == nil {
+		return nil, nil
+	}
+	bz, err := proto.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+	resolved := &descriptorpb.MethodOptions{}
+	if err := (proto.UnmarshalOptions{Resolver: protoregistry.GlobalTypes}).Unmarshal(bz, resolved); err != nil {
+		return nil, err
+	}
+	if !proto.HasExtension(resolved, annotations.E_Http) {
+		return nil, nil
+	}
+	rule, _ := proto.GetExtension(resolved, annotations.E_Http).(*annotations.HttpRule)
+	return rule, nil
+}
+
+func httpPattern(rule *annotations.HttpRule) (string, string) {
+	switch pattern := rule.Pattern.(type) {
+	case *annotations.HttpRule_Get:
+		return "get", pattern.Get
+	case *annotations.HttpRule_Post:
+		return "post", pattern.Post
+	case *annotations.HttpRule_Put:
+		return "put", pattern.Put
+	case *annotations.HttpRule_Delete:
+		return "delete", pattern.Delete
+	case *annotations.HttpRule_Patch:
+		return "patch", pattern.Patch
+	case *annotations.HttpRule_Custom:
+		return strings.ToLower(pattern.Custom.Kind), pattern.Custom.Path
+	}
+	return "", ""
+}
+
+// openAPIHandler serves the JSON encoding of doc
+func openAPIHandler(doc *OpenAPIDoc) (http.HandlerFunc, error) {
+	bz, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(bz)
+	}, nil
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIDoc(t *testing.T) {
+	doc, err := NewOpenAPIDoc()
+	require.NoError(t, err)
+	require.Equal(t, "2.0", doc.Swagger)
+
+	for path, method := range map[string]string{
+		"/cosmos/bank/v1beta1/balances/{address}":   "get",
+		"/cosmos/tx/v1beta1/txs":                    "post",
+		"/cosmos/evm/vm/v1/params":                  "get",
+		"/ibc/apps/transfer/v1/params":              "get",
+		"/cosmos/params/v1beta1/params":             "get",
+		"/cosmos/base/tendermint/v1beta1/node_info": "get",
+	} {
+		require.Contains(t, doc.Paths, path)
+		require.Contains(t, doc.Paths[path], method, "%s should be documented", path)
+	}
+
+	// path variables, flattened query parameters and the response definition
+	op := doc.Paths["/cosmos/bank/v1beta1/balances/{address}"]["get"]
+	require.Equal(t, "cosmos.bank.v1beta1.Query_AllBalances", op.OperationID)
+	params := make(map[string]OpenAPIParameter)
+	for _, param := range op.Parameters {
+		params[param.Name] = param
+	}
+	require.Equal(t, "path", params["address"].In)
+	require.True(t, params["address"].Required)
+	require.Equal(t, "query", params["pagination.limit"].In)
+	require.Equal(t, "uint64", params["pagination.limit"].Format)
+	require.Equal(t, "byte", params["pagination.key"].Format)
+
+	res := op.Responses["200"].Schema
+	require.Equal(t, "#/definitions/cosmos.bank.v1beta1.QueryAllBalancesResponse", res.Ref)
+	def := doc.Definitions["cosmos.bank.v1beta1.QueryAllBalancesResponse"]
+	require.NotNil(t, def)
+	require.Equal(t, "array", def.Properties["balances"].Type)
+	require.Contains(t, doc.Definitions, "cosmos.base.v1beta1.Coin")
+
+	// routes with a body
+	op = doc.Paths["/cosmos/tx/v1beta1/txs"]["post"]
+	require.Len(t, op.Parameters, 1)
+	require.Equal(t, "body", op.Parameters[0].In)
+
+	// the tac modules have no gRPC services, so no routes
+	for path := range doc.Paths {
+		require.False(t, strings.HasPrefix(path, "/tacchain/"), "%s isn't served by the gateway", path)
+	}
+
+	// every reference resolves
+	for name, def := range doc.Definitions {
+		for field, schema := range def.Properties {
+			if schema.Items != nil {
+				schema = schema.Items
+			}
+			if schema.Ref != "" {
+				require.Contains(t, doc.Definitions, schema.Ref[len("#/definitions/"):], "%s.%s", name, field)
+			}
+		}
+	}
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	doc, err := NewOpenAPIDoc()
+	require.NoError(t, err)
+	handler, err := openAPIHandler(doc)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, OpenAPIPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var served OpenAPIDoc
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &served))
+	require.Equal(t, len(doc.Paths), len(served.Paths))
+}
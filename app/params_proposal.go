@@ -0,0 +1,99 @@
+package app
+
+import (
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	govv1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	"github.com/cosmos/cosmos-sdk/x/params"
+	paramskeeper "github.com/cosmos/cosmos-sdk/x/params/keeper"
+	paramstypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	paramproposal "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+
+	accountactivitytypes "github.com/Asphere-xyz/tacchain/x/accountactivity/types"
+	autocompoundtypes "github.com/Asphere-xyz/tacchain/x/autocompound/types"
+	bridgetypes "github.com/Asphere-xyz/tacchain/x/bridge/types"
+	contractgastypes "github.com/Asphere-xyz/tacchain/x/contractgas/types"
+	contractmetatypes "github.com/Asphere-xyz/tacchain/x/contractmeta/types"
+	emissiontypes "github.com/Asphere-xyz/tacchain/x/emission/types"
+	evmupgradetypes "github.com/Asphere-xyz/tacchain/x/evmupgrade/types"
+	feeburntypes "github.com/Asphere-xyz/tacchain/x/feeburn/types"
+	feehistorytypes "github.com/Asphere-xyz/tacchain/x/feehistory/types"
+	feeroutingtypes "github.com/Asphere-xyz/tacchain/x/feerouting/types"
+	ibchookstypes "github.com/Asphere-xyz/tacchain/x/ibchooks/types"
+	nameservicetypes "github.com/Asphere-xyz/tacchain/x/nameservice/types"
+	performancetypes "github.com/Asphere-xyz/tacchain/x/performance/types"
+	recoverytypes "github.com/Asphere-xyz/tacchain/x/recovery/types"
+	rewardsnapshottypes "github.com/Asphere-xyz/tacchain/x/rewardsnapshot/types"
+	selfbondtypes "github.com/Asphere-xyz/tacchain/x/selfbond/types"
+	tacgovtypes "github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+// validatedParamSet is a param set validated as a whole
+type validatedParamSet interface {
+	paramstypes.ParamSet
+	Validate() error
+}
+
+// tacParamSets returns an empty param set of each Tac module, by subspace
+var tacParamSets = map[string]func() validatedParamSet{
+	accountactivitytypes.ModuleName: func() validatedParamSet { return &accountactivitytypes.Params{} },
+	autocompoundtypes.ModuleName:    func() validatedParamSet { return &autocompoundtypes.Params{} },
+	bridgetypes.ModuleName:          func() validatedParamSet { return &bridgetypes.Params{} },
+	contractgastypes.ModuleName:     func() validatedParamSet { return &contractgastypes.Params{} },
+	contractmetatypes.ModuleName:    func() validatedParamSet { return &contractmetatypes.Params{} },
+	emissiontypes.ModuleName:        func() validatedParamSet { return &emissiontypes.Params{} },
+	evmupgradetypes.ModuleName:      func() validatedParamSet { return &evmupgradetypes.Params{} },
+	feeburntypes.ModuleName:         func() validatedParamSet { return &feeburntypes.Params{} },
+	feehistorytypes.ModuleName:      func() validatedParamSet { return &feehistorytypes.Params{} },
+	feeroutingtypes.ModuleName:      func() validatedParamSet { return &feeroutingtypes.Params{} },
+	ibchookstypes.ModuleName:        func() validatedParamSet { return &ibchookstypes.Params{} },
+	nameservicetypes.ModuleName:     func() validatedParamSet { return &nameservicetypes.Params{} },
+	performancetypes.ModuleName:     func() validatedParamSet { return &performancetypes.Params{} },
+	recoverytypes.ModuleName:        func() validatedParamSet { return &recoverytypes.Params{} },
+	rewardsnapshottypes.ModuleName:  func() validatedParamSet { return &rewardsnapshottypes.Params{} },
+	selfbondtypes.ModuleName:        func() validatedParamSet { return &selfbondtypes.Params{} },
+	tacgovtypes.ModuleName:          func() validatedParamSet { return &tacgovtypes.Params{} },
+}
+
+// NewParamChangeProposalHandler wraps the param change proposal handler of
+// x/params. x/params only validates each changed param on its own, so the
+// changes are applied in a cache context and the params of the Tac modules
+// they touch are then validated as a whole: a change breaking a constraint
+// between params, e.g. a min burn ratio of x/feeburn above its max burn
+// ratio, is rejected.
+func NewParamChangeProposalHandler(k paramskeeper.Keeper) govv1beta1.Handler {
+	handler := params.NewParamChangeProposalHandler(k)
+	return func(ctx sdk.Context, content govv1beta1.Content) error {
+		proposal, ok := content.(*paramproposal.ParameterChangeProposal)
+		if !ok {
+			return handler(ctx, content)
+		}
+
+		cacheCtx, write := ctx.CacheContext()
+		if err := handler(cacheCtx, content); err != nil {
+			return err
+		}
+		validated := map[string]bool{}
+		for _, change := range proposal.Changes {
+			newParamSet, ok := tacParamSets[change.Subspace]
+			if !ok || validated[change.Subspace] {
+				continue
+			}
+			validated[change.Subspace] = true
+
+			subspace, ok := k.GetSubspace(change.Subspace)
+			if !ok {
+				continue
+			}
+			paramSet := newParamSet()
+			subspace.GetParamSetIfExists(cacheCtx, paramSet)
+			if err := paramSet.Validate(); err != nil {
+				return errorsmod.Wrapf(sdkerrors.ErrInvalidRequest, "invalid %s params: %s", change.Subspace, err)
+			}
+		}
+		write()
+		return nil
+	}
+}
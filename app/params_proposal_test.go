@@ -0,0 +1,49 @@
+package app
+
+import (
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	paramproposal "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+
+	feeburntypes "github.com/Asphere-xyz/tacchain/x/feeburn/types"
+)
+
+func TestParamChangeProposalValidatesParamSet(t *testing.T) {
+	tacApp := NewTacChainAppWithCustomOptions(t, false, 0, SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := tacApp.NewContext(false)
+	handler := NewParamChangeProposalHandler(tacApp.ParamsKeeper)
+	proposal := func(changes ...paramproposal.ParamChange) *paramproposal.ParameterChangeProposal {
+		return paramproposal.NewParameterChangeProposal("burn ratios", "burn ratios", changes)
+	}
+	change := func(key []byte, value string) paramproposal.ParamChange {
+		return paramproposal.NewParamChange(feeburntypes.ModuleName, string(key), value)
+	}
+
+	// both ratios changed together
+	require.NoError(t, handler(ctx, proposal(change(feeburntypes.KeyMinBurnRatio, `"0.2"`), change(feeburntypes.KeyMaxBurnRatio, `"0.5"`))))
+	params := tacApp.FeeBurnKeeper.GetParams(ctx)
+	require.Equal(t, "0.200000000000000000", params.MinBurnRatio.String())
+	require.Equal(t, "0.500000000000000000", params.MaxBurnRatio.String())
+
+	// a min ratio above the max one is rejected, though valid on its own
+	err := handler(ctx, proposal(change(feeburntypes.KeyMinBurnRatio, `"0.6"`)))
+	require.ErrorContains(t, err, "min burn ratio")
+	err = handler(ctx, proposal(change(feeburntypes.KeyMaxBurnRatio, `"0.1"`)))
+	require.ErrorContains(t, err, "min burn ratio")
+	require.Equal(t, params, tacApp.FeeBurnKeeper.GetParams(ctx), "a rejected change must leave the params untouched")
+
+	// changes of a single param are still validated on their own
+	require.Error(t, handler(ctx, proposal(change(feeburntypes.KeyMaxBurnRatio, `"1.5"`))))
+	require.NoError(t, handler(ctx, proposal(change(feeburntypes.KeyMaxBurnRatio, `"0.6"`))))
+	require.Equal(t, "0.600000000000000000", tacApp.FeeBurnKeeper.GetParams(ctx).MaxBurnRatio.String())
+}
@@ -0,0 +1,205 @@
+package app
+
+import (
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/spf13/cast"
+
+	evidencetypes "cosmossdk.io/x/evidence/types"
+
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	channeltypes "github.com/cosmos/ibc-go/v8/modules/core/04-channel/types"
+)
+
+const (
+	FlagEvidenceLaneMaxTxs = "priority-lanes.evidence-max-txs"
+	FlagIBCLaneMaxTxs      = "priority-lanes.ibc-max-txs"
+
+	// DefaultEvidenceLaneMaxTxs is the default number of evidence txs put at the start of a block
+	DefaultEvidenceLaneMaxTxs = 10
+	// DefaultIBCLaneMaxTxs is the default number of IBC relay txs put at the start of a block
+	DefaultIBCLaneMaxTxs = 50
+)
+
+// DefaultPriorityLanesConfigTemplate defines the app.toml section of the priority lanes
+const DefaultPriorityLanesConfigTemplate = `
+###############################################################################
+###                        Priority Lanes Configuration                     ###
+###############################################################################
+
+[priority-lanes]
+
+# Maximum number of txs of a lane the node puts at the start of the blocks it
+# proposes, ahead of the other txs whatever their fees, so the data the chain
+# depends on keeps flowing when blocks are full. A tx belongs to a lane if all
+# its messages do, 0 disables the lane.
+
+# Evidence of misbehaviour (MsgSubmitEvidence).
+evidence-max-txs = {{ .PriorityLanes.EvidenceMaxTxs }}
+
+# IBC relaying: light client updates, packets, acknowledgements and timeouts.
+ibc-max-txs = {{ .PriorityLanes.IBCMaxTxs }}
+`
+
+// PriorityLanesConfig configures the txs a proposer includes ahead of fee competition
+type PriorityLanesConfig struct {
+	EvidenceMaxTxs uint64 `mapstructure:"evidence-max-txs"`
+	IBCMaxTxs      uint64 `mapstructure:"ibc-max-txs"`
+}
+
+// DefaultPriorityLanesConfig returns the default priority lanes
+func DefaultPriorityLanesConfig() PriorityLanesConfig {
+	return PriorityLanesConfig{
+		EvidenceMaxTxs: DefaultEvidenceLaneMaxTxs,
+		IBCMaxTxs:      DefaultIBCLaneMaxTxs,
+	}
+}
+
+// PriorityLanesConfigFromAppOptions reads the priority lanes of the node
+func PriorityLanesConfigFromAppOptions(appOpts servertypes.AppOptions) PriorityLanesConfig {
+	return PriorityLanesConfig{
+		EvidenceMaxTxs: cast.ToUint64(appOpts.Get(FlagEvidenceLaneMaxTxs)),
+		IBCMaxTxs:      cast.ToUint64(appOpts.Get(FlagIBCLaneMaxTxs)),
+	}
+}
+
+// priorityLane is a class of txs included ahead of the others, up to maxTxs per block
+type priorityLane struct {
+	name     string
+	msgTypes map[string]bool
+	maxTxs   uint64
+}
+
+// lanes returns the enabled lanes, in the order their txs are put in a block
+func (c PriorityLanesConfig) lanes() []priorityLane {
+	lanes := []priorityLane{
+		{
+			name:     "evidence",
+			msgTypes: msgTypeURLs(&evidencetypes.MsgSubmitEvidence{}),
+			maxTxs:   c.EvidenceMaxTxs,
+		},
+		{
+			name: "ibc",
+			msgTypes: msgTypeURLs(
+				&clienttypes.MsgUpdateClient{},
+				&channeltypes.MsgRecvPacket{},
+				&channeltypes.MsgAcknowledgement{},
+				&channeltypes.MsgTimeout{},
+				&channeltypes.MsgTimeoutOnClose{},
+			),
+			maxTxs: c.IBCMaxTxs,
+		},
+	}
+
+	enabled := lanes[:0]
+	for _, lane := range lanes {
+		if lane.maxTxs > 0 {
+			enabled = append(enabled, lane)
+		}
+	}
+	return enabled
+}
+
+func msgTypeURLs(msgs ...proto.Message) map[string]bool {
+	urls := make(map[string]bool, len(msgs))
+	for _, msg := range msgs {
+		urls[sdk.MsgTypeURL(msg)] = true
+	}
+	return urls
+}
+
+// matches returns true if all messages of tx belong to the lane
+func (l priorityLane) matches(tx sdk.Tx) bool {
+	msgs := tx.GetMsgs()
+	for _, msg := range msgs {
+		if !l.msgTypes[sdk.MsgTypeURL(msg)] {
+			return false
+		}
+	}
+	return len(msgs) > 0
+}
+
+// priorityLanesHandler puts the txs of the priority lanes found in the
+// mempool of CometBFT at the start of the block and fills the rest of the
+// block with the txs selected by the next handler, within the block limits.
+//
+// Lanes only change the proposal of the node running them, the other
+// validators accept blocks built either way.
+func priorityLanesHandler(cfg PriorityLanesConfig, txDecoder sdk.TxDecoder, next sdk.PrepareProposalHandler) sdk.PrepareProposalHandler {
+	lanes := cfg.lanes()
+	if len(lanes) == 0 {
+		return next
+	}
+
+	return func(ctx sdk.Context, req *abci.RequestPrepareProposal) (*abci.ResponsePrepareProposal, error) {
+		res, err := next(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		maxBytes := req.MaxTxBytes
+		var maxGas uint64
+		if params := ctx.ConsensusParams().Block; params != nil && params.MaxGas > 0 {
+			maxGas = uint64(params.MaxGas)
+		}
+		var txs [][]byte
+		var totalBytes int64
+		var totalGas uint64
+		included := make(map[string]bool)
+		// add appends bz if it fits into the block
+		add := func(bz []byte, tx sdk.Tx) bool {
+			size := cmttypes.ComputeProtoSizeForTxs([]cmttypes.Tx{bz})
+			var gas uint64
+			if feeTx, ok := tx.(sdk.FeeTx); ok {
+				gas = feeTx.GetGas()
+			}
+			if totalBytes+size > maxBytes || (maxGas > 0 && totalGas+gas > maxGas) {
+				return false
+			}
+			txs = append(txs, bz)
+			totalBytes += size
+			totalGas += gas
+			included[string(bz)] = true
+			return true
+		}
+
+		// the lane txs in the order of the CometBFT mempool
+		for _, lane := range lanes {
+			var count uint64
+			for _, bz := range req.Txs {
+				if count >= lane.maxTxs {
+					break
+				}
+				if included[string(bz)] {
+					continue
+				}
+				tx, err := txDecoder(bz)
+				if err != nil || !lane.matches(tx) {
+					continue
+				}
+				if add(bz, tx) {
+					count++
+				}
+			}
+		}
+		if len(txs) == 0 {
+			return res, nil
+		}
+
+		for _, bz := range res.Txs {
+			if included[string(bz)] {
+				continue
+			}
+			tx, err := txDecoder(bz)
+			if err != nil {
+				continue
+			}
+			add(bz, tx)
+		}
+		return &abci.ResponsePrepareProposal{Txs: txs}, nil
+	}
+}
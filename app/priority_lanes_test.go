@@ -0,0 +1,127 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/require"
+
+	evidencetypes "cosmossdk.io/x/evidence/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	channeltypes "github.com/cosmos/ibc-go/v8/modules/core/04-channel/types"
+)
+
+// laneTestTxs decodes the txs of a test by their bytes
+type laneTestTxs map[string]sdk.Tx
+
+func (txs laneTestTxs) add(name string, gas uint64, msgs ...sdk.Msg) []byte {
+	txs[name] = limitTestTx{gas: gas, msgs: msgs}
+	return []byte(name)
+}
+
+func (txs laneTestTxs) decode(bz []byte) (sdk.Tx, error) {
+	tx, ok := txs[string(bz)]
+	if !ok {
+		return nil, fmt.Errorf("unknown tx %s", bz)
+	}
+	return tx, nil
+}
+
+func TestPriorityLanesHandler(t *testing.T) {
+	txs := laneTestTxs{}
+	send1 := txs.add("send1", 100, &banktypes.MsgSend{})
+	send2 := txs.add("send2", 100, &banktypes.MsgSend{})
+	send3 := txs.add("send3", 100, &banktypes.MsgSend{})
+	evidence1 := txs.add("evidence1", 100, &evidencetypes.MsgSubmitEvidence{})
+	evidence2 := txs.add("evidence2", 100, &evidencetypes.MsgSubmitEvidence{})
+	relay := txs.add("relay", 100, &clienttypes.MsgUpdateClient{}, &channeltypes.MsgRecvPacket{})
+	mixed := txs.add("mixed", 100, &channeltypes.MsgRecvPacket{}, &banktypes.MsgSend{})
+	invalid := []byte("invalid")
+
+	// the next handler selects the txs by fee, it dropped the lane txs
+	next := func(_ sdk.Context, _ *abci.RequestPrepareProposal) (*abci.ResponsePrepareProposal, error) {
+		return &abci.ResponsePrepareProposal{Txs: [][]byte{send1, mixed, send2, send3}}, nil
+	}
+	req := &abci.RequestPrepareProposal{
+		Txs:        [][]byte{send1, invalid, mixed, relay, send2, evidence1, send3, evidence2},
+		MaxTxBytes: 1000,
+	}
+	ctx := sdk.Context{}.WithConsensusParams(cmtproto.ConsensusParams{Block: &cmtproto.BlockParams{MaxGas: -1}})
+	evidenceBytes := cmttypes.ComputeProtoSizeForTxs([]cmttypes.Tx{evidence1, evidence2})
+
+	testCases := []struct {
+		name     string
+		cfg      PriorityLanesConfig
+		maxGas   int64
+		maxBytes int64
+		expTxs   [][]byte
+	}{
+		{
+			name:   "lanes first, evidence before ibc",
+			cfg:    DefaultPriorityLanesConfig(),
+			expTxs: [][]byte{evidence1, evidence2, relay, send1, mixed, send2, send3},
+		},
+		{
+			name:   "lane capped",
+			cfg:    PriorityLanesConfig{EvidenceMaxTxs: 1, IBCMaxTxs: 1},
+			expTxs: [][]byte{evidence1, relay, send1, mixed, send2, send3},
+		},
+		{
+			name:   "disabled lane",
+			cfg:    PriorityLanesConfig{IBCMaxTxs: 1},
+			expTxs: [][]byte{relay, send1, mixed, send2, send3},
+		},
+		{
+			name:   "all lanes disabled",
+			cfg:    PriorityLanesConfig{},
+			expTxs: [][]byte{send1, mixed, send2, send3},
+		},
+		{
+			name:   "block max gas",
+			cfg:    DefaultPriorityLanesConfig(),
+			maxGas: 400,
+			expTxs: [][]byte{evidence1, evidence2, relay, send1},
+		},
+		{
+			name:     "block max bytes",
+			cfg:      DefaultPriorityLanesConfig(),
+			maxBytes: evidenceBytes,
+			expTxs:   [][]byte{evidence1, evidence2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := ctx
+			if tc.maxGas > 0 {
+				ctx = ctx.WithConsensusParams(cmtproto.ConsensusParams{Block: &cmtproto.BlockParams{MaxGas: tc.maxGas}})
+			}
+			req := *req
+			if tc.maxBytes > 0 {
+				req.MaxTxBytes = tc.maxBytes
+			}
+
+			res, err := priorityLanesHandler(tc.cfg, txs.decode, next)(ctx, &req)
+			require.NoError(t, err)
+			require.Equal(t, tc.expTxs, res.Txs)
+		})
+	}
+}
+
+func TestPriorityLanesHandlerError(t *testing.T) {
+	errNext := errors.New("failed")
+	next := func(_ sdk.Context, _ *abci.RequestPrepareProposal) (*abci.ResponsePrepareProposal, error) {
+		return nil, errNext
+	}
+
+	_, err := priorityLanesHandler(DefaultPriorityLanesConfig(), laneTestTxs{}.decode, next)(sdk.Context{}, &abci.RequestPrepareProposal{})
+	require.ErrorIs(t, err, errNext)
+}
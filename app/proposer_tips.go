@@ -0,0 +1,56 @@
+package app
+
+import (
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authante "github.com/cosmos/cosmos-sdk/x/auth/ante"
+
+	feeroutingkeeper "github.com/Asphere-xyz/tacchain/x/feerouting/keeper"
+)
+
+// ProposerTipsDecorator adds the tips of the txs of a block, the priority fee
+// of an EVM tx and the fee a Cosmos tx was charged above the base fee, to the
+// tips the feerouting module routes to the proposer of the block at its end.
+// The EVM txs tip for the gas they used, the gas left is refunded before the
+// post handlers run, and it runs first so the gas of the other post
+// decorators isn't counted. The fee charged to a Cosmos tx is the one of the fee
+// checker of the ante handler.
+//
+// Only successful txs tip, the fees of a Cosmos tx whose messages failed are
+// distributed to all stakers. Its writes aren't charged to the txs.
+type ProposerTipsDecorator struct {
+	keeper     feeroutingkeeper.Keeper
+	feeChecker authante.TxFeeChecker
+}
+
+// NewProposerTipsDecorator returns a post decorator collecting the tips of the txs for the block proposer
+func NewProposerTipsDecorator(keeper feeroutingkeeper.Keeper, feeChecker authante.TxFeeChecker) ProposerTipsDecorator {
+	return ProposerTipsDecorator{keeper: keeper, feeChecker: feeChecker}
+}
+
+func (d ProposerTipsDecorator) PostHandle(ctx sdk.Context, tx sdk.Tx, simulate, success bool, next sdk.PostHandler) (sdk.Context, error) {
+	if !success || simulate || ctx.IsCheckTx() {
+		return next(ctx, tx, simulate, success)
+	}
+
+	gasUsed := ctx.GasMeter().GasConsumed()
+	tipsCtx := ctx.WithGasMeter(storetypes.NewInfiniteGasMeter())
+	if !d.keeper.GetParams(tipsCtx).Enabled() {
+		return next(ctx, tx, simulate, success)
+	}
+
+	evmTip, cosmosSurplus := sdkmath.ZeroInt(), sdkmath.ZeroInt()
+	if msg, ok := singleEVMTx(tx); ok {
+		evmTip = d.keeper.EVMTip(tipsCtx, msg.AsTransaction(), gasUsed)
+	} else if feeTx, ok := tx.(sdk.FeeTx); ok && !isEVMTx(tx) {
+		// the ante handler accepted the fee, the fee checker doesn't fail
+		if fee, _, err := d.feeChecker(tipsCtx, feeTx); err == nil {
+			cosmosSurplus = d.keeper.CosmosSurplus(tipsCtx, fee, feeTx.GetGas())
+		}
+	}
+	d.keeper.AddBlockTips(tipsCtx, evmTip, cosmosSurplus)
+
+	return next(ctx, tx, simulate, success)
+}
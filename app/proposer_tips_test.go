@@ -0,0 +1,88 @@
+package app
+
+import (
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	feeroutingtypes "github.com/Asphere-xyz/tacchain/x/feerouting/types"
+)
+
+func TestProposerTipsDecorator(t *testing.T) {
+	app := NewTacChainAppWithCustomOptions(t, false, 0, SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := app.NewContext(false).WithBlockHeight(2)
+	feeMarketParams := app.FeeMarketKeeper.GetParams(ctx)
+	feeMarketParams.NoBaseFee = false
+	feeMarketParams.BaseFee = sdkmath.LegacyNewDec(100)
+	require.NoError(t, app.FeeMarketKeeper.SetParams(ctx, feeMarketParams))
+	k := app.FeeRoutingKeeper
+	k.SetParams(ctx, feeroutingtypes.Params{EVMTipShare: sdkmath.LegacyOneDec(), CosmosSurplusShare: sdkmath.LegacyOneDec()})
+
+	// the fee checker charges the Cosmos txs 500 above the base fee
+	checked := 0
+	decorator := NewProposerTipsDecorator(k, func(_ sdk.Context, tx sdk.FeeTx) (sdk.Coins, int64, error) {
+		checked++
+		return sdk.NewCoins(sdk.NewCoin(BaseDenom, sdkmath.NewIntFromUint64(100*tx.GetGas()+500))), 0, nil
+	})
+	next := func(ctx sdk.Context, _ sdk.Tx, _, _ bool) (sdk.Context, error) { return ctx, nil }
+	// txCtx is the context of a tx of the block which used gasUsed
+	txCtx := func(gasUsed uint64) sdk.Context {
+		meter := storetypes.NewGasMeter(1_000_000)
+		meter.ConsumeGas(gasUsed, "tx")
+		return ctx.WithGasMeter(meter)
+	}
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	evmTx := replacementTestTx(t, key, signedReplacementTx(t, key, 0, 1000, 20))
+	sendTx := limitTestTx{gas: 200, msgs: []sdk.Msg{&banktypes.MsgSend{}}}
+
+	// the EVM tx tips for the gas it used
+	newCtx, err := decorator.PostHandle(txCtx(21_000), evmTx, false, true, next)
+	require.NoError(t, err)
+	require.Equal(t, uint64(21_000), newCtx.GasMeter().GasConsumed(), "the writes aren't charged to the tx")
+	require.Equal(t, "420000", k.GetBlockTips(ctx).EVMTips.String())
+
+	_, err = decorator.PostHandle(txCtx(50_000), sendTx, false, true, next)
+	require.NoError(t, err)
+	require.Equal(t, "500", k.GetBlockTips(ctx).CosmosSurplus.String())
+	require.Equal(t, 1, checked)
+
+	// failed txs, simulations and the mempool don't tip
+	for _, tc := range []struct {
+		ctx      sdk.Context
+		simulate bool
+		success  bool
+	}{
+		{txCtx(21_000), false, false},
+		{txCtx(21_000), true, true},
+		{txCtx(21_000).WithIsCheckTx(true), false, true},
+	} {
+		_, err = decorator.PostHandle(tc.ctx, evmTx, tc.simulate, tc.success, next)
+		require.NoError(t, err)
+		_, err = decorator.PostHandle(tc.ctx, sendTx, tc.simulate, tc.success, next)
+		require.NoError(t, err)
+	}
+	require.Equal(t, "420500", k.GetBlockTips(ctx).Total().String())
+
+	// nothing is routed with the default params
+	k.SetParams(ctx, feeroutingtypes.DefaultParams())
+	_, err = decorator.PostHandle(txCtx(21_000), sendTx, false, true, next)
+	require.NoError(t, err)
+	require.Equal(t, 1, checked)
+	require.Equal(t, "420500", k.GetBlockTips(ctx).Total().String())
+}
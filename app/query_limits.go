@@ -0,0 +1,145 @@
+package app
+
+import (
+	"context"
+	"reflect"
+
+	gogogrpc "github.com/cosmos/gogoproto/grpc"
+	"github.com/spf13/cast"
+	"google.golang.org/grpc"
+
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+)
+
+const (
+	FlagQueryDefaultLimit = "query-limits.default-limit"
+	FlagQueryMaxLimit     = "query-limits.max-limit"
+
+	// DefaultQueryMaxLimit is the default maximum number of items a list query returns
+	DefaultQueryMaxLimit = 1000
+)
+
+// DefaultQueryLimitsConfigTemplate defines the app.toml section of the query limits
+const DefaultQueryLimitsConfigTemplate = `
+###############################################################################
+###                         Query Limits Configuration                      ###
+###############################################################################
+
+[query-limits]
+
+# Number of items returned by a paginated gRPC, REST or CLI query that doesn't set
+# a limit, 0 uses the Cosmos SDK default of 100.
+default-limit = {{ .QueryLimits.DefaultLimit }}
+
+# Maximum number of items returned by a paginated query, larger limits are lowered
+# to it so a single query can't scan a whole store. 0 disables the check.
+max-limit = {{ .QueryLimits.MaxLimit }}
+`
+
+// QueryLimitsConfig configures the pagination limits a node applies to the
+// queries it serves
+type QueryLimitsConfig struct {
+	DefaultLimit uint64 `mapstructure:"default-limit"`
+	MaxLimit     uint64 `mapstructure:"max-limit"`
+}
+
+// DefaultQueryLimitsConfig returns the default query limits
+func DefaultQueryLimitsConfig() QueryLimitsConfig {
+	return QueryLimitsConfig{
+		DefaultLimit: query.DefaultLimit,
+		MaxLimit:     DefaultQueryMaxLimit,
+	}
+}
+
+// QueryLimitsConfigFromAppOptions reads the query limits of the node
+func QueryLimitsConfigFromAppOptions(appOpts servertypes.AppOptions) QueryLimitsConfig {
+	return QueryLimitsConfig{
+		DefaultLimit: cast.ToUint64(appOpts.Get(FlagQueryDefaultLimit)),
+		MaxLimit:     cast.ToUint64(appOpts.Get(FlagQueryMaxLimit)),
+	}
+}
+
+// paginatedRequest is implemented by the generated query requests carrying a PageRequest
+type paginatedRequest interface {
+	GetPagination() *query.PageRequest
+}
+
+// limitPagination applies the default and maximum limit to the pagination of
+// req, a request without pagination gets one.
+func (c QueryLimitsConfig) limitPagination(req interface{}) {
+	if c == (QueryLimitsConfig{}) {
+		return
+	}
+	r, ok := req.(paginatedRequest)
+	if !ok {
+		return
+	}
+
+	pageReq := r.GetPagination()
+	var limit uint64
+	if pageReq != nil {
+		limit = pageReq.Limit
+	}
+	if limit == 0 {
+		limit = c.DefaultLimit
+		if limit == 0 {
+			limit = query.DefaultLimit
+		}
+	}
+	if c.MaxLimit > 0 && limit > c.MaxLimit {
+		limit = c.MaxLimit
+	}
+
+	if pageReq == nil {
+		field := reflect.ValueOf(req).Elem().FieldByName("Pagination")
+		if !field.IsValid() || !field.CanSet() || field.Type() != reflect.TypeOf(pageReq) {
+			return
+		}
+		pageReq = &query.PageRequest{}
+		field.Set(reflect.ValueOf(pageReq))
+	}
+	pageReq.Limit = limit
+}
+
+// queryLimitsServer registers query services with the pagination of their
+// requests limited. It wraps the gRPC query router, so the limits apply to
+// queries served over gRPC, REST and ABCI alike.
+type queryLimitsServer struct {
+	gogogrpc.Server
+
+	limits QueryLimitsConfig
+}
+
+// newQueryLimitsServer wraps the query router of the app with the query limits of the node.
+func newQueryLimitsServer(server gogogrpc.Server, limits QueryLimitsConfig) gogogrpc.Server {
+	return queryLimitsServer{Server: server, limits: limits}
+}
+
+// RegisterService implements gogogrpc.Server.
+func (s queryLimitsServer) RegisterService(sd *grpc.ServiceDesc, ss interface{}) {
+	desc := *sd
+	desc.Methods = make([]grpc.MethodDesc, len(sd.Methods))
+	for i, method := range sd.Methods {
+		handler := method.Handler
+		method.Handler = func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			return handler(srv, ctx, func(req interface{}) error {
+				if err := dec(req); err != nil {
+					return err
+				}
+				// requests served over gRPC are decoded before they get an SDK
+				// context, ABCI queries run on a check state. Consensus code
+				// calling the router, e.g. ICA host queries, must not depend on
+				// the config of the node.
+				if sdkCtx, ok := ctx.(sdk.Context); ok && !sdkCtx.IsCheckTx() {
+					return nil
+				}
+				s.limits.limitPagination(req)
+				return nil
+			}, interceptor)
+		}
+		desc.Methods[i] = method
+	}
+	s.Server.RegisterService(&desc, ss)
+}
@@ -0,0 +1,222 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+func TestLimitPagination(t *testing.T) {
+	limits := QueryLimitsConfig{DefaultLimit: 10, MaxLimit: 50}
+
+	testCases := []struct {
+		name     string
+		limits   QueryLimitsConfig
+		pageReq  *query.PageRequest
+		expLimit uint64
+	}{
+		{"default limit without pagination", limits, nil, 10},
+		{"default limit without limit", limits, &query.PageRequest{CountTotal: true}, 10},
+		{"limit below the maximum", limits, &query.PageRequest{Limit: 20}, 20},
+		{"limit above the maximum", limits, &query.PageRequest{Limit: 1000}, 50},
+		{"sdk default limit", QueryLimitsConfig{MaxLimit: 500}, nil, query.DefaultLimit},
+		{"sdk default limit above the maximum", QueryLimitsConfig{MaxLimit: 20}, nil, 20},
+		{"no maximum", QueryLimitsConfig{DefaultLimit: 10}, &query.PageRequest{Limit: 1000}, 1000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &banktypes.QueryAllBalancesRequest{Pagination: tc.pageReq}
+			if tc.pageReq != nil {
+				tc.pageReq.Key = []byte("key")
+				tc.pageReq.Reverse = true
+			}
+			tc.limits.limitPagination(req)
+
+			require.NotNil(t, req.Pagination)
+			require.Equal(t, tc.expLimit, req.Pagination.Limit)
+			if tc.pageReq != nil {
+				// everything else is passed through
+				require.Equal(t, []byte("key"), req.Pagination.Key)
+				require.True(t, req.Pagination.Reverse)
+				require.Equal(t, tc.pageReq.CountTotal, req.Pagination.CountTotal)
+			}
+		})
+	}
+
+	// without limits requests are left alone
+	req := &banktypes.QueryAllBalancesRequest{}
+	QueryLimitsConfig{}.limitPagination(req)
+	require.Nil(t, req.Pagination)
+
+	// requests that aren't paginated too
+	balanceReq := &banktypes.QueryBalanceRequest{Denom: BaseDenom}
+	limits.limitPagination(balanceReq)
+	require.Equal(t, &banktypes.QueryBalanceRequest{Denom: BaseDenom}, balanceReq)
+}
+
+// listQuery queries a page of a list and returns the ids of its items
+type listQuery func(t *testing.T, pageReq *query.PageRequest) ([]string, *query.PageResponse)
+
+// routeQuery sends req through the query router of the app, like queries
+// served over gRPC and ABCI.
+func routeQuery(t *testing.T, tacApp *TacChainApp, ctx sdk.Context, path string, req, res proto.Message) {
+	t.Helper()
+
+	handler := tacApp.GRPCQueryRouter().Route(path)
+	require.NotNil(t, handler, path)
+	bz, err := proto.Marshal(req)
+	require.NoError(t, err)
+
+	resp, err := handler(ctx.WithIsCheckTx(true), &abci.RequestQuery{Path: path, Data: bz})
+	require.NoError(t, err)
+	require.NoError(t, proto.Unmarshal(resp.Value, res))
+}
+
+func TestListQueryPagination(t *testing.T) {
+	const items = 6
+	limits := QueryLimitsConfig{DefaultLimit: 3, MaxLimit: 4}
+
+	tacApp := NewTacChainAppWithCustomOptions(t, false, 0, SetupOptions{
+		Logger: log.NewTestLogger(t),
+		DB:     dbm.NewMemDB(),
+		AppOpts: simtestutil.AppOptionsMap{
+			flags.FlagHome:        t.TempDir(),
+			FlagQueryDefaultLimit: limits.DefaultLimit,
+			FlagQueryMaxLimit:     limits.MaxLimit,
+		},
+	})
+	ctx := tacApp.NewContext(false)
+
+	fund := func(addr sdk.AccAddress, coins sdk.Coins) {
+		require.NoError(t, tacApp.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins))
+		require.NoError(t, tacApp.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, addr, coins))
+	}
+
+	holder := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	for i := 0; i < items; i++ {
+		fund(holder, sdk.NewCoins(sdk.NewCoin(fmt.Sprintf("denom%d", i), sdkmath.NewInt(100))))
+	}
+
+	validators, err := tacApp.StakingKeeper.GetAllValidators(ctx)
+	require.NoError(t, err)
+	validator := validators[0]
+	for i := 0; i < items; i++ {
+		delegator := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+		fund(delegator, sdk.NewCoins(sdk.NewCoin(BaseDenom, sdkmath.NewInt(1000))))
+		_, err := tacApp.StakingKeeper.Delegate(ctx, delegator, sdkmath.NewInt(1000), stakingtypes.Unbonded, validator, true)
+		require.NoError(t, err)
+	}
+
+	for i := 0; i < items; i++ {
+		_, err := tacApp.GovKeeper.SubmitProposal(ctx, nil, "", fmt.Sprintf("proposal %d", i), "summary", holder, false)
+		require.NoError(t, err)
+	}
+
+	testCases := []struct {
+		name string
+		list listQuery
+	}{
+		{
+			"balances",
+			func(t *testing.T, pageReq *query.PageRequest) ([]string, *query.PageResponse) {
+				var res banktypes.QueryAllBalancesResponse
+				routeQuery(t, tacApp, ctx, "/cosmos.bank.v1beta1.Query/AllBalances",
+					&banktypes.QueryAllBalancesRequest{Address: holder.String(), Pagination: pageReq}, &res)
+				var ids []string
+				for _, coin := range res.Balances {
+					ids = append(ids, coin.Denom)
+				}
+				return ids, res.Pagination
+			},
+		},
+		{
+			"delegations",
+			func(t *testing.T, pageReq *query.PageRequest) ([]string, *query.PageResponse) {
+				var res stakingtypes.QueryValidatorDelegationsResponse
+				routeQuery(t, tacApp, ctx, "/cosmos.staking.v1beta1.Query/ValidatorDelegations",
+					&stakingtypes.QueryValidatorDelegationsRequest{ValidatorAddr: validator.OperatorAddress, Pagination: pageReq}, &res)
+				var ids []string
+				for _, delegation := range res.DelegationResponses {
+					ids = append(ids, delegation.Delegation.DelegatorAddress)
+				}
+				return ids, res.Pagination
+			},
+		},
+		{
+			"proposals",
+			func(t *testing.T, pageReq *query.PageRequest) ([]string, *query.PageResponse) {
+				var res govv1.QueryProposalsResponse
+				routeQuery(t, tacApp, ctx, "/cosmos.gov.v1.Query/Proposals",
+					&govv1.QueryProposalsRequest{Pagination: pageReq}, &res)
+				var ids []string
+				for _, proposal := range res.Proposals {
+					ids = append(ids, fmt.Sprint(proposal.Id))
+				}
+				return ids, res.Pagination
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// walk the list with next keys
+			var all []string
+			pageReq := &query.PageRequest{Limit: 2}
+			for {
+				ids, pageRes := tc.list(t, pageReq)
+				require.LessOrEqual(t, len(ids), 2)
+				all = append(all, ids...)
+				if len(pageRes.NextKey) == 0 {
+					break
+				}
+				pageReq = &query.PageRequest{Key: pageRes.NextKey, Limit: 2}
+			}
+			require.GreaterOrEqual(t, len(all), items)
+			seen := make(map[string]bool)
+			for _, id := range all {
+				require.False(t, seen[id], "pages should not overlap")
+				seen[id] = true
+			}
+
+			// offset, like the page flag of the CLI
+			ids, _ := tc.list(t, &query.PageRequest{Offset: 2, Limit: 2})
+			require.Equal(t, all[2:4], ids)
+
+			ids, pageRes := tc.list(t, &query.PageRequest{Limit: 2, CountTotal: true})
+			require.Equal(t, all[:2], ids)
+			require.Equal(t, uint64(len(all)), pageRes.Total)
+
+			reversed := slices.Clone(all)
+			slices.Reverse(reversed)
+			ids, _ = tc.list(t, &query.PageRequest{Limit: 2, Reverse: true})
+			require.Equal(t, reversed[:2], ids)
+
+			// the node limits what a single query returns
+			ids, pageRes = tc.list(t, &query.PageRequest{Limit: 100})
+			require.Equal(t, all[:limits.MaxLimit], ids)
+			require.NotEmpty(t, pageRes.NextKey)
+
+			ids, _ = tc.list(t, nil)
+			require.Equal(t, all[:limits.DefaultLimit], ids)
+		})
+	}
+}
@@ -0,0 +1,577 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/spf13/cast"
+
+	"cosmossdk.io/log"
+	storetypes "cosmossdk.io/store/types"
+
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	rpctypes "github.com/cosmos/evm/rpc/types"
+	feemarkettypes "github.com/cosmos/evm/x/feemarket/types"
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+const (
+	FlagReceiptsCompact      = "receipts.compact"
+	FlagReceiptsLogRetention = "receipts.log-retention"
+)
+
+// DefaultReceiptsConfigTemplate defines the app.toml section of the compact receipt store
+const DefaultReceiptsConfigTemplate = `
+###############################################################################
+###                          Receipts Configuration                         ###
+###############################################################################
+
+[receipts]
+
+# Keep the receipts of the EVM txs in data/evm_receipts.db, a few dozen bytes per tx
+# plus its logs, so CometBFT doesn't have to keep the results of every block: set
+# discard_abci_responses = true in the storage section of config.toml. Receipts are
+# served from the store once "receipts" follows "eth" in the api list of the json-rpc
+# section, the receipts of the blocks committed before it was enabled are read from
+# the block results as without it. The namespace also serves eth_getBlockReceipts and
+# reports the height of the state sync rpc_servers in eth_syncing, with or without
+# the store.
+compact = {{ .Receipts.Compact }}
+
+# Number of most recent blocks whose logs are kept, 0 keeps every log. The logs of
+# older receipts are reconstructed by replaying the tx, which needs the state of the
+# block before it: the pruning of the node must keep it.
+log-retention = {{ .Receipts.LogRetention }}
+`
+
+// ReceiptsConfig configures the compact receipt store of the node
+type ReceiptsConfig struct {
+	Compact      bool   `mapstructure:"compact"`
+	LogRetention uint64 `mapstructure:"log-retention"`
+}
+
+// DefaultReceiptsConfig returns the default receipts config, receipts are
+// read from the block results
+func DefaultReceiptsConfig() ReceiptsConfig {
+	return ReceiptsConfig{LogRetention: 100_000}
+}
+
+// ReceiptsConfigFromAppOptions reads the receipts config of the node
+func ReceiptsConfigFromAppOptions(appOpts servertypes.AppOptions) ReceiptsConfig {
+	return ReceiptsConfig{
+		Compact:      cast.ToBool(appOpts.Get(FlagReceiptsCompact)),
+		LogRetention: cast.ToUint64(appOpts.Get(FlagReceiptsLogRetention)),
+	}
+}
+
+var (
+	receiptLogsFirstKey  = []byte{0x00, 0x00}
+	receiptFirstKey      = []byte{0x00, 0x01}
+	receiptLastKey       = []byte{0x00, 0x02}
+	receiptPrefix        = []byte{0x01}
+	receiptLogsPrefix    = []byte{0x02}
+	receiptHashPrefix    = []byte{0x03}
+	receiptBaseFeePrefix = []byte{0x04}
+)
+
+// StoredReceipt is the part of the receipt of an EVM tx the receipt store
+// keeps, the rest is read from the block or derived from the logs.
+type StoredReceipt struct {
+	TxHash common.Hash
+	Height uint64
+	// TxIndex is the index of the cosmos tx in the block, MsgIndex the index
+	// of the MsgEthereumTx in the cosmos tx
+	TxIndex           uint64
+	MsgIndex          uint64
+	EthTxIndex        uint64
+	GasUsed           uint64
+	CumulativeGasUsed uint64
+	Failed            bool
+	// FirstLogIndex is the index in the block of the first log of the tx
+	FirstLogIndex uint64
+	NumLogs       uint64
+}
+
+// CompactLog is the part of an EVM log the receipt store keeps
+type CompactLog struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+}
+
+// EthLogs returns the logs of the receipt in block blockHash
+func (r StoredReceipt) EthLogs(logs []CompactLog, blockHash common.Hash) []*ethtypes.Log {
+	ethLogs := make([]*ethtypes.Log, 0, len(logs))
+	for i, l := range logs {
+		ethLogs = append(ethLogs, &ethtypes.Log{
+			Address:     l.Address,
+			Topics:      l.Topics,
+			Data:        l.Data,
+			BlockNumber: r.Height,
+			TxHash:      r.TxHash,
+			TxIndex:     uint(r.EthTxIndex),
+			BlockHash:   blockHash,
+			Index:       uint(r.FirstLogIndex) + uint(i),
+		})
+	}
+	return ethLogs
+}
+
+// ReceiptLogsTracer is a JavaScript EVM tracer returning the logs of the
+// traced tx as CompactLogs, the logs of reverted calls left out. It recreates
+// the logs of the receipts whose logs the store no longer keeps.
+const ReceiptLogsTracer = `{
+	frames: [[]],
+	step: function(log) {
+		var op = log.op.toNumber();
+		if (op < 0xa0 || op > 0xa4) {
+			return;
+		}
+		var offset = log.stack.peek(0).valueOf(), size = log.stack.peek(1).valueOf();
+		var topics = [];
+		for (var i = 0; i < op - 0xa0; i++) {
+			topics.push(toHex(toWord("0x" + log.stack.peek(2 + i).toString(16))));
+		}
+		// the memory the log reads past the end is expanded with zeros
+		var end = offset + size, available = log.memory.length();
+		var data = offset < available ? toHex(log.memory.slice(offset, Math.min(end, available))) : "0x";
+		if (end > available) {
+			data += "00".repeat(end - Math.max(offset, available));
+		}
+		this.frames[this.frames.length - 1].push({address: toHex(log.contract.getAddress()), topics: topics, data: data});
+	},
+	enter: function() {
+		this.frames.push([]);
+	},
+	exit: function(res) {
+		var logs = this.frames.pop();
+		if (res.getError() === undefined) {
+			Array.prototype.push.apply(this.frames[this.frames.length - 1], logs);
+		}
+	},
+	fault: function() {},
+	result: function() {
+		return this.frames[0];
+	}
+}`
+
+// DecodeReplayedLogs decodes the result of ReceiptLogsTracer
+func DecodeReplayedLogs(result json.RawMessage) ([]CompactLog, error) {
+	var logs []CompactLog
+	if err := json.Unmarshal(result, &logs); err != nil {
+		return nil, fmt.Errorf("failed to decode the replayed logs: %w", err)
+	}
+	return logs, nil
+}
+
+// ReceiptStore keeps the receipts of the EVM txs of the committed blocks in a
+// compact form, and their logs for the last blocks, so the node can serve
+// receipts without the block results. It receives the results of each block
+// as a streaming listener and writes them once the block is committed.
+type ReceiptStore struct {
+	db           dbm.DB
+	logRetention uint64
+	txDecoder    sdk.TxDecoder
+	logger       log.Logger
+
+	// mtx keeps queries from reading the logs of a block being pruned
+	mtx     sync.RWMutex
+	pending *receiptBlock
+}
+
+// receiptBlock is a block finalized but not committed yet
+type receiptBlock struct {
+	height   int64
+	baseFee  *big.Int
+	receipts []StoredReceipt
+	logs     [][]CompactLog
+}
+
+var _ storetypes.ABCIListener = (*ReceiptStore)(nil)
+
+// NewReceiptStore returns the receipt store in db, keeping the logs of the
+// last logRetention blocks or every log if logRetention is 0
+func NewReceiptStore(db dbm.DB, logRetention uint64, txDecoder sdk.TxDecoder, logger log.Logger) *ReceiptStore {
+	return &ReceiptStore{db: db, logRetention: logRetention, txDecoder: txDecoder, logger: logger.With("module", "receipts")}
+}
+
+// OpenReceiptStore opens the receipt store of the node in dir
+func OpenReceiptStore(dir string, backend dbm.BackendType, cfg ReceiptsConfig, txDecoder sdk.TxDecoder, logger log.Logger) (*ReceiptStore, error) {
+	db, err := dbm.NewDB("evm_receipts", backend, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the receipt store: %w", err)
+	}
+	return NewReceiptStore(db, cfg.LogRetention, txDecoder, logger), nil
+}
+
+// Close closes the database of the store
+func (s *ReceiptStore) Close() error {
+	return s.db.Close()
+}
+
+// ListenFinalizeBlock implements storetypes.ABCIListener.
+func (s *ReceiptStore) ListenFinalizeBlock(_ context.Context, req abci.RequestFinalizeBlock, res abci.ResponseFinalizeBlock) error {
+	block, err := s.parseBlock(req, res)
+	if err != nil {
+		// the receipts of the block are read from the block results
+		s.logger.Error("failed to parse the EVM receipts of the block", "height", req.Height, "error", err)
+		s.pending = nil
+		return nil
+	}
+	s.pending = block
+	return nil
+}
+
+// ListenCommit implements storetypes.ABCIListener.
+func (s *ReceiptStore) ListenCommit(context.Context, abci.ResponseCommit, []*storetypes.StoreKVPair) error {
+	block := s.pending
+	s.pending = nil
+	if block == nil {
+		return nil
+	}
+	if err := s.addBlock(block); err != nil {
+		s.logger.Error("failed to store the EVM receipts of the block", "height", block.height, "error", err)
+	}
+	return nil
+}
+
+// parseBlock returns the receipts of the EVM txs of a block, computed the way
+// the JSON-RPC server computes them from the block results
+func (s *ReceiptStore) parseBlock(req abci.RequestFinalizeBlock, res abci.ResponseFinalizeBlock) (*receiptBlock, error) {
+	if len(req.Txs) != len(res.TxResults) {
+		return nil, fmt.Errorf("%d txs but %d results", len(req.Txs), len(res.TxResults))
+	}
+
+	block := &receiptBlock{height: req.Height, baseFee: blockBaseFee(res.Events)}
+	var ethTxIndex, logIndex, blockGasUsed uint64
+	for i, txBz := range req.Txs {
+		result := res.TxResults[i]
+		txGasUsed := uint64(result.GasUsed)
+		if !rpctypes.TxSucessOrExpectedFailure(result) {
+			blockGasUsed += txGasUsed
+			continue
+		}
+		tx, err := s.txDecoder(txBz)
+		if err != nil || !isEthTx(tx) {
+			blockGasUsed += txGasUsed
+			continue
+		}
+		parsed, err := rpctypes.ParseTxResult(result, tx)
+		if err != nil {
+			return nil, err
+		}
+
+		var cumulativeGasUsed uint64
+		for msgIndex, msg := range tx.GetMsgs() {
+			ethMsg := msg.(*evmvmtypes.MsgEthereumTx)
+			receipt := StoredReceipt{
+				TxHash:     ethMsg.AsTransaction().Hash(),
+				Height:     uint64(req.Height),
+				TxIndex:    uint64(i),
+				MsgIndex:   uint64(msgIndex),
+				EthTxIndex: ethTxIndex,
+			}
+			var logs []CompactLog
+			if result.Code != abci.CodeTypeOK {
+				// the ante handler charged the gas limit
+				receipt.GasUsed = ethMsg.GetGas()
+				receipt.Failed = true
+			} else {
+				parsedTx := parsed.GetTxByMsgIndex(msgIndex)
+				if parsedTx == nil {
+					return nil, fmt.Errorf("no result for msg %d of tx %d", msgIndex, i)
+				}
+				receipt.GasUsed = parsedTx.GasUsed
+				receipt.Failed = parsedTx.Failed
+				if logs, err = txLogs(result.Events, msgIndex); err != nil {
+					return nil, err
+				}
+			}
+			cumulativeGasUsed += receipt.GasUsed
+			receipt.CumulativeGasUsed = blockGasUsed + cumulativeGasUsed
+			receipt.FirstLogIndex = logIndex
+			receipt.NumLogs = uint64(len(logs))
+
+			block.receipts = append(block.receipts, receipt)
+			block.logs = append(block.logs, logs)
+			ethTxIndex++
+			logIndex += receipt.NumLogs
+		}
+		blockGasUsed += txGasUsed
+	}
+	return block, nil
+}
+
+// addBlock writes the receipts of a block and prunes the logs past the retention
+func (s *ReceiptStore) addBlock(block *receiptBlock) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	height := uint64(block.height)
+	first, last, ok, err := s.bounds()
+	if err != nil {
+		return err
+	}
+	if ok && height <= last {
+		// a block replayed after a restart is already stored
+		return nil
+	}
+	if !ok || height != last+1 {
+		// the blocks before a gap are no longer covered
+		first = height
+	}
+	if err := batch.Set(receiptFirstKey, sdk.Uint64ToBigEndian(first)); err != nil {
+		return err
+	}
+	if err := batch.Set(receiptLastKey, sdk.Uint64ToBigEndian(height)); err != nil {
+		return err
+	}
+
+	if block.baseFee != nil {
+		if err := batch.Set(receiptBaseFeeKey(height), block.baseFee.Bytes()); err != nil {
+			return err
+		}
+	}
+	for i, receipt := range block.receipts {
+		bz, err := rlp.EncodeToBytes(&receipt)
+		if err != nil {
+			return err
+		}
+		if err := batch.Set(receiptKey(receiptPrefix, height, receipt.EthTxIndex), bz); err != nil {
+			return err
+		}
+		if err := batch.Set(append(append([]byte{}, receiptHashPrefix...), receipt.TxHash.Bytes()...), receiptKey(nil, height, receipt.EthTxIndex)); err != nil {
+			return err
+		}
+		if len(block.logs[i]) == 0 {
+			continue
+		}
+		if bz, err = rlp.EncodeToBytes(block.logs[i]); err != nil {
+			return err
+		}
+		if err := batch.Set(receiptKey(receiptLogsPrefix, height, receipt.EthTxIndex), bz); err != nil {
+			return err
+		}
+	}
+
+	logsFirst, err := s.height(receiptLogsFirstKey)
+	if err != nil {
+		return err
+	}
+	if logsFirst == 0 {
+		logsFirst = height
+	}
+	if s.logRetention > 0 && height >= s.logRetention {
+		if cutoff := height - s.logRetention + 1; cutoff > logsFirst {
+			if err := s.pruneLogs(batch, logsFirst, cutoff); err != nil {
+				return err
+			}
+			logsFirst = cutoff
+		}
+	}
+	if err := batch.Set(receiptLogsFirstKey, sdk.Uint64ToBigEndian(logsFirst)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// pruneLogs deletes the logs of the blocks from start to end, end excluded
+func (s *ReceiptStore) pruneLogs(batch dbm.Batch, start, end uint64) error {
+	it, err := s.db.Iterator(receiptHeightPrefix(receiptLogsPrefix, start), receiptHeightPrefix(receiptLogsPrefix, end))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// bounds returns the first and last heights of the contiguous blocks the
+// store has the receipts of, false if it is empty
+func (s *ReceiptStore) bounds() (first, last uint64, ok bool, err error) {
+	if first, err = s.height(receiptFirstKey); err != nil || first == 0 {
+		return 0, 0, false, err
+	}
+	if last, err = s.height(receiptLastKey); err != nil {
+		return 0, 0, false, err
+	}
+	return first, last, true, nil
+}
+
+func (s *ReceiptStore) height(key []byte) (uint64, error) {
+	bz, err := s.db.Get(key)
+	if err != nil || bz == nil {
+		return 0, err
+	}
+	return sdk.BigEndianToUint64(bz), nil
+}
+
+// BlockReceipts returns the stored receipts of the block at height by EVM tx
+// index, false if the store doesn't have the receipts of the block
+func (s *ReceiptStore) BlockReceipts(height uint64) ([]StoredReceipt, bool, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	first, last, ok, err := s.bounds()
+	if err != nil || !ok || height < first || height > last {
+		return nil, false, err
+	}
+
+	it, err := s.db.Iterator(receiptHeightPrefix(receiptPrefix, height), receiptHeightPrefix(receiptPrefix, height+1))
+	if err != nil {
+		return nil, false, err
+	}
+	defer it.Close()
+
+	receipts := []StoredReceipt{}
+	for ; it.Valid(); it.Next() {
+		var receipt StoredReceipt
+		if err := rlp.DecodeBytes(it.Value(), &receipt); err != nil {
+			return nil, false, err
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, true, it.Error()
+}
+
+// Receipt returns the stored receipt of the EVM tx hash
+func (s *ReceiptStore) Receipt(hash common.Hash) (StoredReceipt, bool, error) {
+	key, err := s.db.Get(append(append([]byte{}, receiptHashPrefix...), hash.Bytes()...))
+	if err != nil || key == nil {
+		return StoredReceipt{}, false, err
+	}
+	bz, err := s.db.Get(append(append([]byte{}, receiptPrefix...), key...))
+	if err != nil || bz == nil {
+		return StoredReceipt{}, false, err
+	}
+	var receipt StoredReceipt
+	if err := rlp.DecodeBytes(bz, &receipt); err != nil {
+		return StoredReceipt{}, false, err
+	}
+	return receipt, true, nil
+}
+
+// Logs returns the logs of receipt, false if the store no longer keeps them
+func (s *ReceiptStore) Logs(receipt StoredReceipt) ([]CompactLog, bool, error) {
+	if receipt.NumLogs == 0 {
+		return []CompactLog{}, true, nil
+	}
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if first, err := s.height(receiptLogsFirstKey); err != nil || receipt.Height < first {
+		return nil, false, err
+	}
+	bz, err := s.db.Get(receiptKey(receiptLogsPrefix, receipt.Height, receipt.EthTxIndex))
+	if err != nil || bz == nil {
+		return nil, false, err
+	}
+	var logs []CompactLog
+	if err := rlp.DecodeBytes(bz, &logs); err != nil {
+		return nil, false, err
+	}
+	return logs, true, nil
+}
+
+// BaseFee returns the base fee of the block at height, nil without a fee market
+func (s *ReceiptStore) BaseFee(height uint64) (*big.Int, error) {
+	bz, err := s.db.Get(receiptBaseFeeKey(height))
+	if err != nil || bz == nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bz), nil
+}
+
+// isEthTx reports whether tx is an EVM tx, made of MsgEthereumTx only
+func isEthTx(tx sdk.Tx) bool {
+	msgs := tx.GetMsgs()
+	if len(msgs) == 0 {
+		return false
+	}
+	for _, msg := range msgs {
+		if _, ok := msg.(*evmvmtypes.MsgEthereumTx); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// txLogs returns the logs of the msgIndex-th EVM message of a tx from its
+// tx_log events, one per message
+func txLogs(events []abci.Event, msgIndex int) ([]CompactLog, error) {
+	var n int
+	for _, event := range events {
+		if event.Type != evmvmtypes.EventTypeTxLog {
+			continue
+		}
+		if n++; n <= msgIndex {
+			continue
+		}
+		logs := make([]CompactLog, 0, len(event.Attributes))
+		for _, attr := range event.Attributes {
+			if attr.Key != evmvmtypes.AttributeKeyTxLog {
+				continue
+			}
+			var l evmLog
+			if err := json.Unmarshal([]byte(attr.Value), &l); err != nil {
+				return nil, err
+			}
+			topics := make([]common.Hash, 0, len(l.Topics))
+			for _, topic := range l.Topics {
+				topics = append(topics, common.HexToHash(topic))
+			}
+			logs = append(logs, CompactLog{Address: common.HexToAddress(l.Address), Topics: topics, Data: l.Data})
+		}
+		return logs, nil
+	}
+	return nil, nil
+}
+
+// blockBaseFee returns the base fee of the fee market event of a block
+func blockBaseFee(events []abci.Event) *big.Int {
+	for _, event := range events {
+		if event.Type != feemarkettypes.EventTypeFeeMarket {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if attr.Key != feemarkettypes.AttributeKeyBaseFee {
+				continue
+			}
+			if baseFee, ok := new(big.Int).SetString(attr.Value, 10); ok {
+				return baseFee
+			}
+		}
+	}
+	return nil
+}
+
+func receiptHeightPrefix(prefix []byte, height uint64) []byte {
+	return append(append([]byte{}, prefix...), sdk.Uint64ToBigEndian(height)...)
+}
+
+func receiptKey(prefix []byte, height, ethTxIndex uint64) []byte {
+	return append(receiptHeightPrefix(prefix, height), sdk.Uint64ToBigEndian(ethTxIndex)...)
+}
+
+func receiptBaseFeeKey(height uint64) []byte {
+	return receiptHeightPrefix(receiptBaseFeePrefix, height)
+}
@@ -0,0 +1,276 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	_ "github.com/ethereum/go-ethereum/eth/tracers/js"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	feemarkettypes "github.com/cosmos/evm/x/feemarket/types"
+	evmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+// receiptTestTx is an EVM tx of a block of the receipt store tests
+type receiptTestTx struct {
+	msg    *evmtypes.MsgEthereumTx
+	result *abci.ExecTxResult
+}
+
+func newReceiptTestTx(t *testing.T, nonce uint64, gasUsed int64, failed bool, logs ...testLog) receiptTestTx {
+	msg := evmtypes.NewTx(&evmtypes.EvmTxArgs{
+		ChainID:  big.NewInt(2391),
+		Nonce:    nonce,
+		GasLimit: 100_000,
+		GasPrice: big.NewInt(1),
+		Amount:   big.NewInt(0),
+	})
+	attrs := []abci.EventAttribute{
+		{Key: evmtypes.AttributeKeyEthereumTxHash, Value: msg.AsTransaction().Hash().Hex()},
+		{Key: evmtypes.AttributeKeyTxGasUsed, Value: strconv.FormatInt(gasUsed, 10)},
+	}
+	if failed {
+		attrs = append(attrs, abci.EventAttribute{Key: evmtypes.AttributeKeyEthereumTxFailed, Value: "execution reverted"})
+	}
+	return receiptTestTx{msg: msg, result: &abci.ExecTxResult{
+		GasUsed: gasUsed,
+		Events:  []abci.Event{{Type: evmtypes.EventTypeEthereumTx, Attributes: attrs}, txLogEvent(t, logs...)},
+	}}
+}
+
+func TestReceiptStore(t *testing.T) {
+	address := common.HexToAddress("0x01")
+	topic := common.HexToHash("0xa1")
+	logA1 := testLog{address: address, topics: []common.Hash{topic}}
+	logA2 := testLog{address: common.HexToAddress("0x02")}
+	logD := testLog{address: address, topics: []common.Hash{topic, topic}}
+
+	txA := newReceiptTestTx(t, 0, 30_000, false, logA1, logA2)
+	txB := newReceiptTestTx(t, 1, 25_000, true)
+	// an EVM tx over the block gas limit charges its whole gas limit, the
+	// cumulative gas of the next txs counts the gas of its cosmos result
+	txC := newReceiptTestTx(t, 2, 0, false)
+	txC.result.Code = 11
+	txC.result.Log = "out of gas in location: block gas meter; gasWanted: 100000: exceeds block gas limit"
+	txD := newReceiptTestTx(t, 3, 40_000, false, logD)
+	// an EVM tx the ante handler rejected isn't in the receipts
+	txE := newReceiptTestTx(t, 4, 0, false)
+	txE.result.Code = 5
+
+	txs := map[string]sdk.Tx{"bank": limitTestTx{}}
+	req := abci.RequestFinalizeBlock{Height: 10, Txs: [][]byte{[]byte("bank")}}
+	res := abci.ResponseFinalizeBlock{
+		TxResults: []*abci.ExecTxResult{{GasUsed: 50_000}},
+		Events: []abci.Event{{Type: feemarkettypes.EventTypeFeeMarket, Attributes: []abci.EventAttribute{
+			{Key: feemarkettypes.AttributeKeyBaseFee, Value: "1000000000"},
+		}}},
+	}
+	for i, tx := range []receiptTestTx{txA, txB, txC, txD, txE} {
+		key := "eth" + strconv.Itoa(i)
+		txs[key] = limitTestTx{msgs: []sdk.Msg{tx.msg}}
+		req.Txs = append(req.Txs, []byte(key))
+		res.TxResults = append(res.TxResults, tx.result)
+	}
+	decoder := func(bz []byte) (sdk.Tx, error) {
+		tx, ok := txs[string(bz)]
+		if !ok {
+			return nil, errors.New("unknown tx")
+		}
+		return tx, nil
+	}
+
+	store := NewReceiptStore(dbm.NewMemDB(), 2, decoder, log.NewNopLogger())
+	commit := func(req abci.RequestFinalizeBlock, res abci.ResponseFinalizeBlock) {
+		require.NoError(t, store.ListenFinalizeBlock(context.Background(), req, res))
+		require.NoError(t, store.ListenCommit(context.Background(), abci.ResponseCommit{}, nil))
+	}
+	commit(req, res)
+
+	// the receipts are the ones the JSON-RPC server computes from the block
+	// results: the cumulative gas counts the cosmos txs before
+	blockHash := common.HexToHash("0xb10c")
+	expected := []struct {
+		tx                receiptTestTx
+		txIndex           uint64
+		gasUsed           uint64
+		cumulativeGasUsed uint64
+		failed            bool
+		logs              []testLog
+	}{
+		{txA, 1, 30_000, 80_000, false, []testLog{logA1, logA2}},
+		{txB, 2, 25_000, 105_000, true, nil},
+		{txC, 3, 100_000, 205_000, true, nil},
+		{txD, 4, 40_000, 145_000, false, []testLog{logD}},
+	}
+	var logIndex uint
+	for ethTxIndex, exp := range expected {
+		hash := exp.tx.msg.AsTransaction().Hash()
+		receipt, found, err := store.Receipt(hash)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, StoredReceipt{
+			TxHash:            hash,
+			Height:            10,
+			TxIndex:           exp.txIndex,
+			EthTxIndex:        uint64(ethTxIndex),
+			GasUsed:           exp.gasUsed,
+			CumulativeGasUsed: exp.cumulativeGasUsed,
+			Failed:            exp.failed,
+			FirstLogIndex:     uint64(logIndex),
+			NumLogs:           uint64(len(exp.logs)),
+		}, receipt)
+
+		logs, found, err := store.Logs(receipt)
+		require.NoError(t, err)
+		require.True(t, found)
+		expectedLogs := []*ethtypes.Log{}
+		for _, l := range exp.logs {
+			expectedLogs = append(expectedLogs, &ethtypes.Log{
+				Address:     l.address,
+				Topics:      append([]common.Hash{}, l.topics...),
+				Data:        []byte{},
+				BlockNumber: 10,
+				TxHash:      hash,
+				TxIndex:     uint(ethTxIndex),
+				BlockHash:   blockHash,
+				Index:       logIndex,
+			})
+			logIndex++
+		}
+		ethLogs := receipt.EthLogs(logs, blockHash)
+		for _, l := range ethLogs {
+			if len(l.Data) == 0 {
+				l.Data = []byte{}
+			}
+		}
+		require.Equal(t, expectedLogs, ethLogs)
+	}
+	_, found, err := store.Receipt(txE.msg.AsTransaction().Hash())
+	require.NoError(t, err)
+	require.False(t, found)
+
+	baseFee, err := store.BaseFee(10)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1_000_000_000), baseFee)
+	baseFee, err = store.BaseFee(11)
+	require.NoError(t, err)
+	require.Nil(t, baseFee)
+
+	// the logs of the blocks past the retention are pruned, the receipts stay
+	commit(abci.RequestFinalizeBlock{Height: 11}, abci.ResponseFinalizeBlock{})
+	receipt, _, err := store.Receipt(txA.msg.AsTransaction().Hash())
+	require.NoError(t, err)
+	_, found, err = store.Logs(receipt)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	commit(abci.RequestFinalizeBlock{Height: 12}, abci.ResponseFinalizeBlock{})
+	_, found, err = store.Logs(receipt)
+	require.NoError(t, err)
+	require.False(t, found)
+	receipt, found, err = store.Receipt(txD.msg.AsTransaction().Hash())
+	require.NoError(t, err)
+	require.True(t, found)
+	require.EqualValues(t, 1, receipt.NumLogs)
+
+	// a tx without logs needs no replay
+	receipt, _, err = store.Receipt(txB.msg.AsTransaction().Hash())
+	require.NoError(t, err)
+	logs, found, err := store.Logs(receipt)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Empty(t, logs)
+
+	// the receipts of a block are in EVM tx order
+	receipts, found, err := store.BlockReceipts(10)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Len(t, receipts, len(expected))
+	for i, exp := range expected {
+		require.Equal(t, exp.tx.msg.AsTransaction().Hash(), receipts[i].TxHash)
+	}
+	receipts, found, err = store.BlockReceipts(11)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Empty(t, receipts)
+	_, found, err = store.BlockReceipts(9)
+	require.NoError(t, err)
+	require.False(t, found, "block before the store")
+	_, found, err = store.BlockReceipts(13)
+	require.NoError(t, err)
+	require.False(t, found, "block not committed yet")
+
+	// a replayed block is already stored, the blocks before a gap are no
+	// longer covered but their receipts stay
+	commit(req, res)
+	commit(abci.RequestFinalizeBlock{Height: 20}, abci.ResponseFinalizeBlock{})
+	_, found, err = store.BlockReceipts(10)
+	require.NoError(t, err)
+	require.False(t, found)
+	_, found, err = store.BlockReceipts(20)
+	require.NoError(t, err)
+	require.True(t, found)
+	_, found, err = store.Receipt(txA.msg.AsTransaction().Hash())
+	require.NoError(t, err)
+	require.True(t, found)
+}
+
+func TestReceiptLogsTracer(t *testing.T) {
+	reverting := common.HexToAddress("0xaa")
+	logging := common.HexToAddress("0xbb")
+	code := common.FromHex("0x" +
+		"6042600052" + // MSTORE(0, 0x42)
+		"600160206000a1" + // LOG1(0, 32, 1)
+		"6000600060006000600060aa5af150" + // CALL(gas, 0xaa), its log is reverted
+		"6000600060006000600060bb5af150" + // CALL(gas, 0xbb)
+		"601160206040a1" + // LOG1(64, 32, 0x11) past the end of the memory
+		"00")
+
+	statedb, err := state.New(ethtypes.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+	statedb.SetCode(reverting, common.FromHex("0x60206000a060006000fd")) // LOG0(0, 32), REVERT
+	statedb.SetCode(logging, common.FromHex("0x60bb60aa60006000a200"))   // LOG2(0, 0, 0xaa, 0xbb)
+
+	tracer, err := tracers.DefaultDirectory.New(ReceiptLogsTracer, new(tracers.Context), nil)
+	require.NoError(t, err)
+	_, statedb, err = runtime.Execute(code, nil, &runtime.Config{
+		State:     statedb,
+		GasLimit:  1_000_000,
+		EVMConfig: vm.Config{Tracer: tracer},
+	})
+	require.NoError(t, err)
+
+	result, err := tracer.GetResult()
+	require.NoError(t, err)
+	replayed, err := DecodeReplayedLogs(result)
+	require.NoError(t, err)
+
+	// the replayed logs are the logs the execution emitted
+	executed := statedb.Logs()
+	require.Len(t, executed, 3)
+	require.Len(t, replayed, len(executed))
+	for i, l := range executed {
+		require.Equal(t, l.Address, replayed[i].Address, "log %d", i)
+		require.Equal(t, l.Topics, replayed[i].Topics, "log %d", i)
+		require.Equal(t, hexutil.Encode(l.Data), replayed[i].Data.String(), "log %d", i)
+	}
+	require.Equal(t, logging, replayed[1].Address)
+	require.Equal(t, make([]byte, 32), []byte(replayed[2].Data))
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"math/big"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	errortypes "github.com/cosmos/cosmos-sdk/types/errors"
+
+	evmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+// ReplayProtectionDecorator rejects the EVM txs that could be replayed on
+// another chain before they enter the mempool: txs signed for another chain id
+// and, unless the node allows them, legacy txs signed without a chain id
+// (pre EIP-155). Both are rejected with the codes the EVM ante handler uses
+// for them, ErrInvalidChainID and ErrNotSupported, but with the chain id of the
+// tx and the setting in the error, and before any other check of the tx.
+//
+// Like the tx limits it only applies to CheckTx: the EVM allow_unprotected_txs
+// param decides whether unprotected txs included in a block are executed.
+type ReplayProtectionDecorator struct {
+	rejectUnprotected bool
+	chainID           func() *big.Int
+}
+
+// NewReplayProtectionDecorator returns a ReplayProtectionDecorator checking
+// the chain id of txs against the EIP-155 chain id returned by chainID
+func NewReplayProtectionDecorator(limits TxLimitsConfig, chainID func() *big.Int) ReplayProtectionDecorator {
+	return ReplayProtectionDecorator{
+		rejectUnprotected: limits.RejectUnprotectedEVMTxs,
+		chainID:           chainID,
+	}
+}
+
+// evmChainID returns the EIP-155 chain id of the EVM
+func evmChainID() *big.Int {
+	return evmtypes.GetEthChainConfig().ChainID
+}
+
+func (d ReplayProtectionDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	if !ctx.IsCheckTx() || simulate {
+		return next(ctx, tx, simulate)
+	}
+
+	chainID := d.chainID()
+	for _, msg := range tx.GetMsgs() {
+		ethMsg, ok := msg.(*evmtypes.MsgEthereumTx)
+		if !ok {
+			continue
+		}
+		ethTx := ethMsg.AsTransaction()
+		if !ethTx.Protected() {
+			if d.rejectUnprotected {
+				return ctx, errorsmod.Wrapf(
+					errortypes.ErrNotSupported,
+					"tx %s is not replay protected, sign it with EIP-155 and chain id %s (%s)",
+					ethTx.Hash(), chainID, FlagRejectUnprotectedEVMTxs,
+				)
+			}
+			continue
+		}
+		if ethTx.ChainId().Cmp(chainID) != 0 {
+			return ctx, errorsmod.Wrapf(
+				errortypes.ErrInvalidChainID,
+				"tx %s is signed for chain id %s, the chain id is %s",
+				ethTx.Hash(), ethTx.ChainId(), chainID,
+			)
+		}
+	}
+
+	return next(ctx, tx, simulate)
+}
@@ -0,0 +1,259 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	distrkeeper "github.com/cosmos/cosmos-sdk/x/distribution/keeper"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingkeeper "github.com/cosmos/cosmos-sdk/x/staking/keeper"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	emissiontypes "github.com/Asphere-xyz/tacchain/x/emission/types"
+)
+
+const (
+	// rewardsBlocksPerYear and rewardsBlockProvision are the emission schedule
+	// of the rewards tests, a year is short enough to run in process
+	rewardsBlocksPerYear  = 20
+	rewardsBlockProvision = 1_000_000
+)
+
+// rewardsTest is an app before its first block with the emission minting
+// rewardsBlockProvision per block, a 2% community tax and a single validator
+// at 10% commission. The genesis delegator has 1 and the second delegator 3
+// of the 4 bonded tokens.
+type rewardsTest struct {
+	app        *TacChainApp
+	validator  abci.Validator
+	valAddr    sdk.ValAddress
+	delegators []sdk.AccAddress
+	height     int64
+}
+
+func newRewardsTest(t *testing.T) *rewardsTest {
+	t.Helper()
+
+	app := NewTacChainAppWithCustomOptions(t, false, 0, SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	// the state set before the first block is committed with it
+	ctx := app.NewContext(false)
+
+	params := emissiontypes.DefaultParams()
+	params.MintDenom = BaseDenom
+	params.AnnualProvisions = sdkmath.NewInt(rewardsBlocksPerYear * rewardsBlockProvision)
+	params.BlocksPerYear = rewardsBlocksPerYear
+	params.ReductionInterval = 0
+	require.NoError(t, params.Validate())
+	app.EmissionKeeper.SetParams(ctx, params)
+
+	distrParams, err := app.DistrKeeper.Params.Get(ctx)
+	require.NoError(t, err)
+	distrParams.CommunityTax = sdkmath.LegacyNewDecWithPrec(2, 2)
+	require.NoError(t, app.DistrKeeper.Params.Set(ctx, distrParams))
+
+	validators, err := app.StakingKeeper.GetAllValidators(ctx)
+	require.NoError(t, err)
+	require.Len(t, validators, 1)
+	validator := validators[0]
+	validator.Commission = stakingtypes.NewCommission(sdkmath.LegacyNewDecWithPrec(1, 1), sdkmath.LegacyOneDec(), sdkmath.LegacyOneDec())
+	require.NoError(t, app.StakingKeeper.SetValidator(ctx, validator))
+	valAddr, err := sdk.ValAddressFromBech32(validator.GetOperator())
+	require.NoError(t, err)
+
+	// the genesis validator bonded without the hooks creating its signing info
+	consAddr, err := validator.GetConsAddr()
+	require.NoError(t, err)
+	signingInfo := slashingtypes.NewValidatorSigningInfo(sdk.ConsAddress(consAddr), 0, 0, time.Unix(0, 0), false, 0)
+	require.NoError(t, app.SlashingKeeper.SetValidatorSigningInfo(ctx, sdk.ConsAddress(consAddr), signingInfo))
+
+	delegations, err := app.StakingKeeper.GetValidatorDelegations(ctx, valAddr)
+	require.NoError(t, err)
+	require.Len(t, delegations, 1)
+	genesisDelegator, err := sdk.AccAddressFromBech32(delegations[0].DelegatorAddress)
+	require.NoError(t, err)
+	require.True(t, validator.Tokens.Equal(PowerReduction), validator.Tokens.String())
+
+	delegator := sdk.AccAddress([]byte("second delegator...."))
+	bond := sdk.NewCoins(sdk.NewCoin(BaseDenom, PowerReduction.MulRaw(3)))
+	require.NoError(t, app.BankKeeper.MintCoins(ctx, minttypes.ModuleName, bond))
+	require.NoError(t, app.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, delegator, bond))
+	_, err = stakingkeeper.NewMsgServerImpl(app.StakingKeeper).Delegate(ctx, stakingtypes.NewMsgDelegate(delegator.String(), valAddr.String(), bond[0]))
+	require.NoError(t, err)
+
+	return &rewardsTest{
+		app:        app,
+		validator:  abci.Validator{Address: consAddr, Power: 1},
+		valAddr:    valAddr,
+		delegators: []sdk.AccAddress{genesisDelegator, delegator},
+	}
+}
+
+// finalizeBlocks finalizes and commits n blocks, the commit of the previous
+// block has the vote of the validator if signed
+func (r *rewardsTest) finalizeBlocks(t *testing.T, n int, signed bool) {
+	t.Helper()
+
+	var votes []abci.VoteInfo
+	if signed {
+		votes = []abci.VoteInfo{{Validator: r.validator, BlockIdFlag: cmtproto.BlockIDFlagCommit}}
+	}
+	for i := 0; i < n; i++ {
+		r.height++
+		_, err := r.app.FinalizeBlock(&abci.RequestFinalizeBlock{
+			Height:            r.height,
+			Time:              time.Unix(r.height*5, 0).UTC(),
+			ProposerAddress:   r.validator.Address,
+			DecidedLastCommit: abci.CommitInfo{Votes: votes},
+		})
+		require.NoError(t, err)
+		_, err = r.app.Commit()
+		require.NoError(t, err)
+	}
+}
+
+// rewards is the state of the rewards at the last committed height, in utac
+type rewards struct {
+	FeeCollector  int64
+	CommunityPool int64
+	Outstanding   int64
+	Commission    int64
+	// Delegators are the rewards the delegators can withdraw
+	Delegators []int64
+}
+
+func (r *rewardsTest) rewards(t *testing.T) rewards {
+	t.Helper()
+
+	// queries must not change the committed state
+	ctx, _ := r.app.NewUncachedContext(false, cmtproto.Header{Height: r.height}).CacheContext()
+	// every amount must be whole, the expectations are exact
+	amount := func(coins sdk.DecCoins) int64 {
+		amount := coins.AmountOf(BaseDenom)
+		require.True(t, amount.IsInteger(), amount.String())
+		return amount.TruncateInt64()
+	}
+
+	var res rewards
+	feeCollector := r.app.AccountKeeper.GetModuleAddress(authtypes.FeeCollectorName)
+	res.FeeCollector = r.app.BankKeeper.GetBalance(ctx, feeCollector, BaseDenom).Amount.Int64()
+
+	feePool, err := r.app.DistrKeeper.FeePool.Get(ctx)
+	require.NoError(t, err)
+	res.CommunityPool = amount(feePool.CommunityPool)
+
+	outstanding, err := r.app.DistrKeeper.GetValidatorOutstandingRewards(ctx, r.valAddr)
+	require.NoError(t, err)
+	res.Outstanding = amount(outstanding.Rewards)
+
+	commission, err := r.app.DistrKeeper.GetValidatorAccumulatedCommission(ctx, r.valAddr)
+	require.NoError(t, err)
+	res.Commission = amount(commission.Commission)
+
+	querier := distrkeeper.NewQuerier(r.app.DistrKeeper)
+	for _, delegator := range r.delegators {
+		delegationRewards, err := querier.DelegationRewards(ctx, &distrtypes.QueryDelegationRewardsRequest{
+			DelegatorAddress: delegator.String(),
+			ValidatorAddress: r.valAddr.String(),
+		})
+		require.NoError(t, err)
+		res.Delegators = append(res.Delegators, amount(delegationRewards.Rewards))
+	}
+	return res
+}
+
+func TestEmissionRewardDistribution(t *testing.T) {
+	r := newRewardsTest(t)
+
+	// distribution skips the first block, its provision waits in the fee collector
+	r.finalizeBlocks(t, 1, true)
+	require.Equal(t, rewards{
+		FeeCollector: rewardsBlockProvision,
+		Delegators:   []int64{0, 0},
+	}, r.rewards(t))
+
+	// the second block distributes the provisions of both blocks: 2% to the
+	// community pool, 10% of the rest as commission and the remaining
+	// 1,764,000 to the delegators by stake
+	r.finalizeBlocks(t, 1, true)
+	require.Equal(t, rewards{
+		CommunityPool: 40_000,
+		Outstanding:   1_960_000,
+		Commission:    196_000,
+		Delegators:    []int64{441_000, 1_323_000},
+	}, r.rewards(t))
+
+	// the provision of every other block is distributed at once
+	r.finalizeBlocks(t, 3, true)
+	require.Equal(t, rewards{
+		CommunityPool: 100_000,
+		Outstanding:   4_900_000,
+		Commission:    490_000,
+		Delegators:    []int64{1_102_500, 3_307_500},
+	}, r.rewards(t))
+}
+
+func TestEmissionRewardsMatchStakingAPR(t *testing.T) {
+	r := newRewardsTest(t)
+
+	// the provisions of a year, the last one is distributed in the next block
+	r.finalizeBlocks(t, rewardsBlocksPerYear+1, true)
+	res := r.rewards(t)
+	yearly := int64(rewardsBlocksPerYear * rewardsBlockProvision)
+	require.Equal(t, int64(rewardsBlockProvision), res.FeeCollector)
+	require.Equal(t, yearly, res.CommunityPool+res.Outstanding)
+
+	ctx := r.app.NewUncachedContext(false, cmtproto.Header{Height: r.height})
+	validator, err := r.app.StakingKeeper.GetValidator(ctx, r.valAddr)
+	require.NoError(t, err)
+	distrParams, err := r.app.DistrKeeper.Params.Get(ctx)
+	require.NoError(t, err)
+	apr := StakingAPR(sdkmath.LegacyNewDec(yearly), validator.Tokens, distrParams.CommunityTax)
+	require.Equal(t, "0.000000000004900000", apr.String())
+
+	// the delegators earned the APR on their stake over the year, less the
+	// commission of the validator
+	for i, stake := range []sdkmath.Int{PowerReduction, PowerReduction.MulRaw(3)} {
+		expected := apr.MulInt(stake).Mul(sdkmath.LegacyOneDec().Sub(validator.Commission.Rate))
+		require.Equal(t, expected.TruncateInt64(), res.Delegators[i], "delegator %d", i)
+	}
+	require.Equal(t, []int64{4_410_000, 13_230_000}, res.Delegators)
+	require.Equal(t, int64(1_960_000), res.Commission)
+}
+
+func TestEmissionWithoutVotesFundsCommunityPool(t *testing.T) {
+	r := newRewardsTest(t)
+
+	// without the votes of the last commit there is no power to reward, the
+	// provisions go to the community pool
+	r.finalizeBlocks(t, 3, false)
+	require.Equal(t, rewards{
+		CommunityPool: 3 * rewardsBlockProvision,
+		Delegators:    []int64{0, 0},
+	}, r.rewards(t))
+
+	// the validator signs again
+	r.finalizeBlocks(t, 1, true)
+	require.Equal(t, rewards{
+		CommunityPool: 3*rewardsBlockProvision + 20_000,
+		Outstanding:   980_000,
+		Commission:    98_000,
+		Delegators:    []int64{220_500, 661_500},
+	}, r.rewards(t))
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+
+	ibcfeetypes "github.com/cosmos/ibc-go/v8/modules/apps/29-fee/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v8/modules/apps/transfer/types"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+
+	evmerc20types "github.com/cosmos/evm/x/erc20/types"
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	bridgetypes "github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+// ReceivableModuleAccounts lists the module accounts that intentionally take in
+// funds from regular accounts through their own keeper flows: fee payment,
+// community pool funding, proposal deposits, IBC escrow/burn, relayer fee escrow,
+// EVM/ERC-20 conversions and the bridge burning the TON assets released on TON.
+//
+// Every other module account (mint, staking pools, ICA, nft, ...) is only ever
+// funded by other modules, so coins sent to it by a user can't be recovered.
+var ReceivableModuleAccounts = []string{
+	authtypes.FeeCollectorName,
+	distrtypes.ModuleName,
+	govtypes.ModuleName,
+	ibctransfertypes.ModuleName,
+	ibcfeetypes.ModuleName,
+	evmvmtypes.ModuleName,
+	evmerc20types.ModuleName,
+	bridgetypes.ModuleName,
+}
+
+// ModuleAccountSendRestriction returns a bank SendRestrictionFn rejecting
+// transfers from regular accounts to module accounts that are not part of the
+// receivable allow-list. Module to module transfers (e.g. mint -> fee collector,
+// bonded -> not bonded pool) are internal bookkeeping and always pass.
+func ModuleAccountSendRestriction(receivable []string) banktypes.SendRestrictionFn {
+	moduleAddrs := make(map[string]string, len(maccPerms))
+	for name := range GetMaccPerms() {
+		moduleAddrs[authtypes.NewModuleAddress(name).String()] = name
+	}
+
+	allowed := make(map[string]bool, len(receivable))
+	for _, name := range receivable {
+		allowed[authtypes.NewModuleAddress(name).String()] = true
+	}
+
+	return func(_ context.Context, fromAddr, toAddr sdk.AccAddress, _ sdk.Coins) (sdk.AccAddress, error) {
+		name, isModule := moduleAddrs[toAddr.String()]
+		if !isModule || allowed[toAddr.String()] {
+			return toAddr, nil
+		}
+
+		if _, fromModule := moduleAddrs[fromAddr.String()]; fromModule {
+			return toAddr, nil
+		}
+
+		return nil, errorsmod.Wrapf(sdkerrors.ErrUnauthorized, "%s is the %s module account and cannot receive funds", toAddr, name)
+	}
+}
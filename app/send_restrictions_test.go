@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	bridgetypes "github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+func TestModuleAccountSendRestriction(t *testing.T) {
+	restriction := ModuleAccountSendRestriction(ReceivableModuleAccounts)
+
+	user := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	otherUser := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	coins := sdk.NewCoins(sdk.NewCoin(BaseDenom, sdkmath.NewInt(100)))
+
+	testCases := []struct {
+		name      string
+		from      sdk.AccAddress
+		to        sdk.AccAddress
+		expectErr bool
+	}{
+		{"user to user", user, otherUser, false},
+		{"user to fee collector", user, authtypes.NewModuleAddress(authtypes.FeeCollectorName), false},
+		{"user to community pool", user, authtypes.NewModuleAddress(distrtypes.ModuleName), false},
+		{"user to bridge", user, authtypes.NewModuleAddress(bridgetypes.ModuleName), false},
+		{"user to mint", user, authtypes.NewModuleAddress(minttypes.ModuleName), true},
+		{"user to bonded pool", user, authtypes.NewModuleAddress(stakingtypes.BondedPoolName), true},
+		{"user to not bonded pool", user, authtypes.NewModuleAddress(stakingtypes.NotBondedPoolName), true},
+		{"mint to fee collector", authtypes.NewModuleAddress(minttypes.ModuleName), authtypes.NewModuleAddress(authtypes.FeeCollectorName), false},
+		{"bonded to not bonded pool", authtypes.NewModuleAddress(stakingtypes.BondedPoolName), authtypes.NewModuleAddress(stakingtypes.NotBondedPoolName), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			to, err := restriction(context.Background(), tc.from, tc.to, coins)
+			if tc.expectErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "cannot receive funds")
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.to, to)
+		})
+	}
+}
+
+func TestBankSendToModuleAccounts(t *testing.T) {
+	app := NewTacChainAppWithCustomOptions(t, false, 0, SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := app.NewContext(false)
+
+	user := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	coins := sdk.NewCoins(sdk.NewCoin(BaseDenom, sdkmath.NewInt(1000)))
+	require.NoError(t, app.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins))
+	require.NoError(t, app.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, user, coins))
+
+	amount := sdk.NewCoins(sdk.NewCoin(BaseDenom, sdkmath.NewInt(100)))
+
+	// direct sends to non-receivable module accounts are rejected
+	for _, name := range []string{minttypes.ModuleName, stakingtypes.BondedPoolName, stakingtypes.NotBondedPoolName} {
+		err := app.BankKeeper.SendCoins(ctx, user, authtypes.NewModuleAddress(name), amount)
+		require.Error(t, err, "send to %s should fail", name)
+	}
+	require.Equal(t, coins, app.BankKeeper.GetAllBalances(ctx, user))
+
+	// allow-listed module accounts can still be funded through their keeper flows
+	require.NoError(t, app.BankKeeper.SendCoinsFromAccountToModule(ctx, user, authtypes.FeeCollectorName, amount))
+	require.NoError(t, app.DistrKeeper.FundCommunityPool(ctx, amount, user))
+	require.Equal(t, coins.Sub(amount...).Sub(amount...), app.BankKeeper.GetAllBalances(ctx, user))
+}
@@ -0,0 +1,166 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	gogogrpc "github.com/cosmos/gogoproto/grpc"
+	"github.com/spf13/cast"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const FlagShutdownGracePeriod = "shutdown.grace-period"
+
+// DefaultShutdownConfigTemplate defines the app.toml section of the shutdown
+const DefaultShutdownConfigTemplate = `
+###############################################################################
+###                          Shutdown Configuration                         ###
+###############################################################################
+
+[shutdown]
+
+# Time the node waits, once it is stopped with SIGINT or SIGTERM, for the queries
+# it serves over gRPC, REST, JSON-RPC and ABCI to finish before it closes its
+# databases. The queries received meanwhile are refused as unavailable. 0 closes
+# the databases right away.
+grace-period = "{{ .Shutdown.GracePeriod }}"
+`
+
+// ShutdownConfig configures the shutdown of the node
+type ShutdownConfig struct {
+	GracePeriod time.Duration `mapstructure:"grace-period"`
+}
+
+// DefaultShutdownConfig returns the default shutdown configuration
+func DefaultShutdownConfig() ShutdownConfig {
+	return ShutdownConfig{GracePeriod: 10 * time.Second}
+}
+
+// ShutdownConfigFromAppOptions reads the shutdown configuration of the node
+func ShutdownConfigFromAppOptions(appOpts servertypes.AppOptions) ShutdownConfig {
+	return ShutdownConfig{
+		GracePeriod: cast.ToDuration(appOpts.Get(FlagShutdownGracePeriod)),
+	}
+}
+
+// ErrShuttingDown is returned to the queries received while the node drains
+// the queries in flight
+var ErrShuttingDown = status.Error(codes.Unavailable, "node is shutting down")
+
+// queryDrain counts the queries in flight, so that the app can wait for them
+// before closing its databases
+type queryDrain struct {
+	mu       sync.Mutex
+	inFlight int
+	draining bool
+	// done is closed once draining with no query in flight
+	done chan struct{}
+}
+
+func newQueryDrain() *queryDrain {
+	return &queryDrain{done: make(chan struct{})}
+}
+
+// begin counts a new query in flight, unless the drain started
+func (d *queryDrain) begin() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return ErrShuttingDown
+	}
+	d.inFlight++
+	return nil
+}
+
+// end counts the end of a query begin accepted
+func (d *queryDrain) end() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inFlight--
+	if d.draining && d.inFlight == 0 {
+		close(d.done)
+	}
+}
+
+// pending returns the number of queries in flight
+func (d *queryDrain) pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.inFlight
+}
+
+// drain refuses the new queries and waits up to timeout for the ones in
+// flight to end. It returns the number of queries still in flight.
+func (d *queryDrain) drain(timeout time.Duration) int {
+	d.mu.Lock()
+	if !d.draining {
+		d.draining = true
+		if d.inFlight == 0 {
+			close(d.done)
+		}
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-d.done:
+		return 0
+	case <-time.After(timeout):
+		return d.pending()
+	}
+}
+
+// queryDrainServer registers query services whose queries are counted by
+// drain. It wraps the gRPC query router, so it counts the queries served over
+// gRPC, REST and ABCI alike.
+type queryDrainServer struct {
+	gogogrpc.Server
+
+	drain *queryDrain
+}
+
+// newQueryDrainServer wraps the query router of the app with the drain of its queries
+func newQueryDrainServer(server gogogrpc.Server, drain *queryDrain) gogogrpc.Server {
+	return queryDrainServer{Server: server, drain: drain}
+}
+
+// RegisterService implements gogogrpc.Server.
+func (s queryDrainServer) RegisterService(sd *grpc.ServiceDesc, ss interface{}) {
+	desc := *sd
+	desc.Methods = make([]grpc.MethodDesc, len(sd.Methods))
+	for i, method := range sd.Methods {
+		handler := method.Handler
+		method.Handler = func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			// consensus code calling the router, e.g. ICA host queries, must
+			// run until the block is committed
+			if sdkCtx, ok := ctx.(sdk.Context); ok && !sdkCtx.IsCheckTx() {
+				return handler(srv, ctx, dec, interceptor)
+			}
+			if err := s.drain.begin(); err != nil {
+				return nil, err
+			}
+			defer s.drain.end()
+			return handler(srv, ctx, dec, interceptor)
+		}
+		desc.Methods[i] = method
+	}
+	s.Server.RegisterService(&desc, ss)
+}
+
+// drainQueries refuses the new queries and waits up to the shutdown grace
+// period for the ones in flight to end, so that they don't read the
+// databases as they are closed
+func (app *TacChainApp) drainQueries() {
+	logger := app.Logger()
+	logger.Info("draining in-flight queries", "in_flight", app.queryDrain.pending(), "grace_period", app.shutdownConfig.GracePeriod)
+	if n := app.queryDrain.drain(app.shutdownConfig.GracePeriod); n > 0 {
+		logger.Error("shutdown grace period elapsed with queries in flight", "in_flight", n)
+		return
+	}
+	logger.Info("in-flight queries drained")
+}
@@ -0,0 +1,124 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"cosmossdk.io/log"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+// serviceRecorder records the service registered on it
+type serviceRecorder struct {
+	desc *grpc.ServiceDesc
+}
+
+func (r *serviceRecorder) RegisterService(sd *grpc.ServiceDesc, _ interface{}) {
+	r.desc = sd
+}
+
+// newDrainedService returns the handler of a query service whose queries
+// block until release is closed, registered through a queryDrainServer
+func newDrainedService(drain *queryDrain, release <-chan struct{}) grpc.MethodHandler {
+	recorder := &serviceRecorder{}
+	newQueryDrainServer(recorder, drain).RegisterService(&grpc.ServiceDesc{
+		ServiceName: "tacchain.test.Query",
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Block",
+			Handler: func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+				<-release
+				return "done", nil
+			},
+		}},
+	}, nil)
+	return recorder.desc.Methods[0].Handler
+}
+
+func TestQueryDrain(t *testing.T) {
+	drain := newQueryDrain()
+	release := make(chan struct{})
+	handler := newDrainedService(drain, release)
+
+	results := make(chan error, 2)
+	query := func(ctx context.Context) {
+		_, err := handler(nil, ctx, nil, nil)
+		results <- err
+	}
+	go query(context.Background())
+	go query(sdk.Context{}.WithContext(context.Background()).WithIsCheckTx(true))
+	require.Eventually(t, func() bool { return drain.pending() == 2 }, time.Second, time.Millisecond)
+
+	drained := make(chan int)
+	go func() { drained <- drain.drain(time.Minute) }()
+	require.Eventually(t, func() bool {
+		drain.mu.Lock()
+		defer drain.mu.Unlock()
+		return drain.draining
+	}, time.Second, time.Millisecond)
+	// the queries received once the drain started are refused
+	_, err := handler(nil, context.Background(), nil, nil)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	// consensus code still queries the router
+	consensusCtx := sdk.Context{}.WithContext(context.Background())
+	released := make(chan struct{})
+	close(released)
+	_, err = newDrainedService(drain, released)(nil, consensusCtx, nil, nil)
+	require.NoError(t, err)
+
+	close(release)
+	require.NoError(t, <-results)
+	require.NoError(t, <-results)
+	require.Zero(t, <-drained)
+	require.Zero(t, drain.drain(time.Minute), "draining again returns right away")
+}
+
+func TestQueryDrainGracePeriod(t *testing.T) {
+	drain := newQueryDrain()
+	release := make(chan struct{})
+	defer close(release)
+	handler := newDrainedService(drain, release)
+
+	go func() { _, _ = handler(nil, context.Background(), nil, nil) }()
+	require.Eventually(t, func() bool { return drain.pending() == 1 }, time.Second, time.Millisecond)
+
+	start := time.Now()
+	require.Equal(t, 1, drain.drain(50*time.Millisecond))
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestCloseRefusesQueries(t *testing.T) {
+	tacApp := NewTacChainAppWithCustomOptions(t, false, 0, SetupOptions{
+		Logger: log.NewTestLogger(t),
+		DB:     dbm.NewMemDB(),
+		AppOpts: simtestutil.AppOptionsMap{
+			flags.FlagHome:          t.TempDir(),
+			FlagShutdownGracePeriod: "5s",
+		},
+	})
+	require.Equal(t, 5*time.Second, tacApp.shutdownConfig.GracePeriod)
+	ctx := tacApp.NewContext(false).WithIsCheckTx(true)
+
+	path := "/cosmos.bank.v1beta1.Query/Params"
+	bz, err := proto.Marshal(&banktypes.QueryParamsRequest{})
+	require.NoError(t, err)
+	_, err = tacApp.GRPCQueryRouter().Route(path)(ctx, &abci.RequestQuery{Path: path, Data: bz})
+	require.NoError(t, err)
+
+	require.NoError(t, tacApp.Close())
+	_, err = tacApp.GRPCQueryRouter().Route(path)(ctx, &abci.RequestQuery{Path: path, Data: bz})
+	require.ErrorIs(t, err, ErrShuttingDown)
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/hashicorp/go-metrics"
+
+	storetypes "cosmossdk.io/store/types"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+)
+
+const (
+	// flagTelemetryEnabled is the app.toml setting enabling the telemetry of the node
+	flagTelemetryEnabled = "telemetry.enabled"
+
+	// diskSizeSampleInterval is the minimum time between two samples of the size of the application database
+	diskSizeSampleInterval = time.Minute
+)
+
+// storeWriteStats are the writes of a block to the store of a module
+type storeWriteStats struct {
+	Sets    int
+	Deletes int
+	Bytes   int
+}
+
+// writeStats sums up the writes of changeSet per store
+func writeStats(changeSet []*storetypes.StoreKVPair) map[string]storeWriteStats {
+	stats := make(map[string]storeWriteStats)
+	for _, pair := range changeSet {
+		s := stats[pair.StoreKey]
+		if pair.Delete {
+			s.Deletes++
+		} else {
+			s.Sets++
+		}
+		s.Bytes += len(pair.Key) + len(pair.Value)
+		stats[pair.StoreKey] = s
+	}
+	return stats
+}
+
+// storeMetrics reports the writes of each block per module store and the size
+// of the application database, so the modules driving the state growth can be
+// told apart. It receives the writes of a block as a streaming listener.
+type storeMetrics struct {
+	dbDir string
+
+	lastDiskSample time.Time
+	sampling       atomic.Bool
+}
+
+var _ storetypes.ABCIListener = (*storeMetrics)(nil)
+
+func newStoreMetrics(dbDir string) *storeMetrics {
+	return &storeMetrics{dbDir: dbDir}
+}
+
+// ListenFinalizeBlock implements storetypes.ABCIListener.
+func (m *storeMetrics) ListenFinalizeBlock(context.Context, abci.RequestFinalizeBlock, abci.ResponseFinalizeBlock) error {
+	return nil
+}
+
+// ListenCommit implements storetypes.ABCIListener.
+func (m *storeMetrics) ListenCommit(_ context.Context, _ abci.ResponseCommit, changeSet []*storetypes.StoreKVPair) error {
+	stats := writeStats(changeSet)
+	modules := make([]string, 0, len(stats))
+	for module := range stats {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	for _, module := range modules {
+		s := stats[module]
+		label := telemetry.NewLabel("module", module)
+		telemetry.IncrCounterWithLabels([]string{"store", "writes"}, float32(s.Sets), []metrics.Label{label, telemetry.NewLabel("op", "set")})
+		telemetry.IncrCounterWithLabels([]string{"store", "writes"}, float32(s.Deletes), []metrics.Label{label, telemetry.NewLabel("op", "delete")})
+		telemetry.IncrCounterWithLabels([]string{"store", "write", "bytes"}, float32(s.Bytes), []metrics.Label{label})
+	}
+
+	m.sampleDiskSize()
+	return nil
+}
+
+// sampleDiskSize reports the size of the application database in the
+// background, at most once per diskSizeSampleInterval.
+func (m *storeMetrics) sampleDiskSize() {
+	if time.Since(m.lastDiskSample) < diskSizeSampleInterval || !m.sampling.CompareAndSwap(false, true) {
+		return
+	}
+	m.lastDiskSample = time.Now()
+
+	go func() {
+		defer m.sampling.Store(false)
+		size, err := dirSize(m.dbDir)
+		if err != nil {
+			return
+		}
+		telemetry.SetGaugeWithLabels([]string{"store", "disk", "size"}, float32(size), []metrics.Label{telemetry.NewLabel("db", "application")})
+	}()
+}
+
+// dirSize returns the size of the files in dir
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/stretchr/testify/require"
+
+	storetypes "cosmossdk.io/store/types"
+)
+
+func TestWriteStats(t *testing.T) {
+	changeSet := []*storetypes.StoreKVPair{
+		{StoreKey: "evm", Key: []byte("k1"), Value: []byte("value")},
+		{StoreKey: "evm", Key: []byte("k2"), Value: []byte("v")},
+		{StoreKey: "evm", Key: []byte("k3"), Delete: true},
+		{StoreKey: "bank", Key: []byte("balance"), Value: []byte("100")},
+	}
+
+	stats := writeStats(changeSet)
+	require.Equal(t, map[string]storeWriteStats{
+		"evm":  {Sets: 2, Deletes: 1, Bytes: 7 + 3 + 2},
+		"bank": {Sets: 1, Bytes: 10},
+	}, stats)
+	require.Empty(t, writeStats(nil))
+}
+
+func TestStoreMetricsDiskSize(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "000001.log"), make([]byte, 100), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "MANIFEST"), make([]byte, 23), 0o644))
+
+	size, err := dirSize(dir)
+	require.NoError(t, err)
+	require.EqualValues(t, 123, size)
+
+	// the size is sampled at most once per interval
+	m := newStoreMetrics(dir)
+	require.NoError(t, m.ListenCommit(context.Background(), abci.ResponseCommit{}, nil))
+	sampled := m.lastDiskSample
+	require.False(t, sampled.IsZero())
+	require.NoError(t, m.ListenCommit(context.Background(), abci.ResponseCommit{}, nil))
+	require.Equal(t, sampled, m.lastDiskSample)
+	require.Eventually(t, func() bool { return !m.sampling.Load() }, time.Second, 10*time.Millisecond)
+}
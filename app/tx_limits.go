@@ -0,0 +1,283 @@
+package app
+
+import (
+	"time"
+
+	"github.com/spf13/cast"
+
+	errorsmod "cosmossdk.io/errors"
+
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	errortypes "github.com/cosmos/cosmos-sdk/types/errors"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+
+	evmanteinterfaces "github.com/cosmos/evm/ante/interfaces"
+	evmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+const (
+	FlagMaxTxBytes          = "tx-limits.max-tx-bytes"
+	FlagMaxGasWanted        = "tx-limits.max-gas-wanted"
+	FlagMaxPendingCosmosTxs = "tx-limits.max-pending-cosmos-txs"
+	FlagMaxPendingEVMTxs    = "tx-limits.max-pending-evm-txs"
+	// FlagRejectUnprotectedEVMTxs rejects EVM txs signed without a chain id
+	FlagRejectUnprotectedEVMTxs = "tx-limits.reject-unprotected-evm-txs"
+	// FlagEVMPriceBump is the fee increase in percent an EVM tx needs to
+	// replace the pending tx of its sender at the same nonce
+	FlagEVMPriceBump = "tx-limits.evm-price-bump"
+	// FlagTxTTLBlocks and FlagTxTTL are the number of blocks and the time
+	// after which a tx still in the mempool is evicted
+	FlagTxTTLBlocks = "tx-limits.tx-ttl-blocks"
+	FlagTxTTL       = "tx-limits.tx-ttl"
+
+	// DefaultMaxTxBytes matches the default max_tx_bytes of the CometBFT mempool
+	DefaultMaxTxBytes = 1048576
+	// DefaultMaxPendingTxs is the default number of txs a sender can have in the mempool
+	DefaultMaxPendingTxs = 64
+	// DefaultEVMPriceBump is the default fee increase of replacement txs, the one of geth
+	DefaultEVMPriceBump = 10
+	// DefaultTxTTL is the default time a tx can stay in the mempool, the
+	// lifetime of the txs queued in the txpool of geth
+	DefaultTxTTL = 3 * time.Hour
+)
+
+// DefaultTxLimitsConfigTemplate defines the app.toml section of the tx limits
+const DefaultTxLimitsConfigTemplate = `
+###############################################################################
+###                           Tx Limits Configuration                       ###
+###############################################################################
+
+[tx-limits]
+
+# Maximum size in bytes of a Cosmos or EVM tx accepted into the mempool, 0 disables the check.
+# Txs larger than the max_bytes of a block are always rejected, they could never be included.
+max-tx-bytes = {{ .TxLimits.MaxTxBytes }}
+
+# Maximum gas wanted by a Cosmos or EVM tx accepted into the mempool,
+# 0 limits it to the max gas of a block.
+max-gas-wanted = {{ .TxLimits.MaxGasWanted }}
+
+# Maximum number of pending txs a sender can have in the mempool when it submits
+# a Cosmos or an EVM tx, 0 disables the check. Cosmos and EVM txs of a sender share
+# its sequence, so pending txs of both kinds count towards either limit.
+max-pending-cosmos-txs = {{ .TxLimits.MaxPendingCosmosTxs }}
+max-pending-evm-txs = {{ .TxLimits.MaxPendingEVMTxs }}
+
+# Reject legacy EVM txs signed without a chain id (pre EIP-155), which can be
+# replayed on any chain. EVM txs signed for another chain id are always rejected.
+# Unprotected txs in blocks are executed if the EVM allow_unprotected_txs param allows them.
+reject-unprotected-evm-txs = {{ .TxLimits.RejectUnprotectedEVMTxs }}
+
+# Minimum increase in percent of the fee cap and the tip cap of an EVM tx replacing
+# the pending tx of its sender at the same nonce, to speed it up or cancel it. The
+# replaced tx is dropped from the mempool and from the blocks the node proposes.
+# 0 disables replacements: a tx reusing the nonce of a pending tx is rejected.
+evm-price-bump = {{ .TxLimits.EVMPriceBump }}
+
+# Number of blocks and time after which a tx still in the mempool is evicted, so a tx
+# the validators never include, e.g. one paying less than their minimum gas prices,
+# doesn't stay in the mempool of the node forever. The age of a tx is counted from the
+# block it entered the mempool after, in blocks and in block time, and the tx is
+# evicted when the mempool rechecks it. The node logs the evicted txs and alerts on
+# them when tx_evicted is among the alerting events. 0 disables either limit.
+tx-ttl-blocks = {{ .TxLimits.TxTTLBlocks }}
+tx-ttl = "{{ .TxLimits.TxTTL }}"
+`
+
+// TxLimitsConfig configures the limits a node applies to txs entering its mempool
+type TxLimitsConfig struct {
+	MaxTxBytes          uint64 `mapstructure:"max-tx-bytes"`
+	MaxGasWanted        uint64 `mapstructure:"max-gas-wanted"`
+	MaxPendingCosmosTxs uint64 `mapstructure:"max-pending-cosmos-txs"`
+	MaxPendingEVMTxs    uint64 `mapstructure:"max-pending-evm-txs"`
+
+	RejectUnprotectedEVMTxs bool   `mapstructure:"reject-unprotected-evm-txs"`
+	EVMPriceBump            uint64 `mapstructure:"evm-price-bump"`
+
+	TxTTLBlocks uint64        `mapstructure:"tx-ttl-blocks"`
+	TxTTL       time.Duration `mapstructure:"tx-ttl"`
+}
+
+// DefaultTxLimitsConfig returns the default tx limits
+func DefaultTxLimitsConfig() TxLimitsConfig {
+	return TxLimitsConfig{
+		MaxTxBytes:          DefaultMaxTxBytes,
+		MaxGasWanted:        0,
+		MaxPendingCosmosTxs: DefaultMaxPendingTxs,
+		MaxPendingEVMTxs:    DefaultMaxPendingTxs,
+
+		RejectUnprotectedEVMTxs: true,
+		EVMPriceBump:            DefaultEVMPriceBump,
+
+		TxTTL: DefaultTxTTL,
+	}
+}
+
+// TxLimitsConfigFromAppOptions reads the tx limits of the node
+func TxLimitsConfigFromAppOptions(appOpts servertypes.AppOptions) TxLimitsConfig {
+	// an app.toml written before the settings existed keeps rejecting
+	// unprotected txs, accepts replacements and evicts the txs older than the
+	// default ttl
+	rejectUnprotected := true
+	if v := appOpts.Get(FlagRejectUnprotectedEVMTxs); v != nil {
+		rejectUnprotected = cast.ToBool(v)
+	}
+	priceBump := uint64(DefaultEVMPriceBump)
+	if v := appOpts.Get(FlagEVMPriceBump); v != nil {
+		priceBump = cast.ToUint64(v)
+	}
+	ttl := DefaultTxTTL
+	if v := appOpts.Get(FlagTxTTL); v != nil {
+		ttl = cast.ToDuration(v)
+	}
+	return TxLimitsConfig{
+		MaxTxBytes:          cast.ToUint64(appOpts.Get(FlagMaxTxBytes)),
+		MaxGasWanted:        cast.ToUint64(appOpts.Get(FlagMaxGasWanted)),
+		MaxPendingCosmosTxs: cast.ToUint64(appOpts.Get(FlagMaxPendingCosmosTxs)),
+		MaxPendingEVMTxs:    cast.ToUint64(appOpts.Get(FlagMaxPendingEVMTxs)),
+
+		RejectUnprotectedEVMTxs: rejectUnprotected,
+		EVMPriceBump:            priceBump,
+
+		TxTTLBlocks: cast.ToUint64(appOpts.Get(FlagTxTTLBlocks)),
+		TxTTL:       ttl,
+	}
+}
+
+// TxLimitDecorator rejects txs exceeding the node's tx size or gas wanted
+// limits. The limits are local to the node, so they are only enforced when a
+// tx enters the mempool and never when a block is executed.
+type TxLimitDecorator struct {
+	limits TxLimitsConfig
+}
+
+func NewTxLimitDecorator(limits TxLimitsConfig) TxLimitDecorator {
+	return TxLimitDecorator{limits: limits}
+}
+
+func (d TxLimitDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	if !ctx.IsCheckTx() || simulate {
+		return next(ctx, tx, simulate)
+	}
+
+	size := uint64(len(ctx.TxBytes()))
+	if d.limits.MaxTxBytes > 0 && size > d.limits.MaxTxBytes {
+		return ctx, errorsmod.Wrapf(errortypes.ErrTxTooLarge, "tx size is %d bytes, the node accepts at most %d bytes (%s)", size, d.limits.MaxTxBytes, FlagMaxTxBytes)
+	}
+	// a tx larger than a block would stay in the mempool forever, e.g. once
+	// governance lowered max_bytes below the max-tx-bytes of the node
+	cp := ctx.ConsensusParams()
+	if cp.Block != nil && cp.Block.MaxBytes > 0 && size > uint64(cp.Block.MaxBytes) {
+		return ctx, errorsmod.Wrapf(errortypes.ErrTxTooLarge, "tx size is %d bytes, blocks hold at most %d bytes (max_bytes of the consensus params)", size, cp.Block.MaxBytes)
+	}
+
+	maxGasWanted := d.limits.MaxGasWanted
+	if maxGasWanted == 0 && cp.Block != nil && cp.Block.MaxGas > 0 {
+		maxGasWanted = uint64(cp.Block.MaxGas)
+	}
+	if gasWanted := txGasWanted(tx); maxGasWanted > 0 && gasWanted > maxGasWanted {
+		return ctx, errorsmod.Wrapf(errortypes.ErrInvalidGasLimit, "tx wants %d gas, the node accepts at most %d gas per tx (%s)", gasWanted, maxGasWanted, FlagMaxGasWanted)
+	}
+
+	return next(ctx, tx, simulate)
+}
+
+// AccountSequenceFunc returns the sequence of an account in the last committed state
+type AccountSequenceFunc func(addr sdk.AccAddress) (uint64, error)
+
+// PendingTxLimitDecorator limits the number of txs a sender can have in the
+// mempool. Every tx accepted by CheckTx increments the sender's sequence in
+// the check state, which is reset to the committed state after each block, so
+// the number of pending txs of a sender is the difference between its check
+// state and its committed sequence. Like the other tx limits it is only
+// enforced when a tx enters the mempool.
+type PendingTxLimitDecorator struct {
+	ak                evmanteinterfaces.AccountKeeper
+	committedSequence AccountSequenceFunc
+	maxCosmos         uint64
+	maxEVM            uint64
+}
+
+func NewPendingTxLimitDecorator(ak evmanteinterfaces.AccountKeeper, committedSequence AccountSequenceFunc, limits TxLimitsConfig) PendingTxLimitDecorator {
+	return PendingTxLimitDecorator{
+		ak:                ak,
+		committedSequence: committedSequence,
+		maxCosmos:         limits.MaxPendingCosmosTxs,
+		maxEVM:            limits.MaxPendingEVMTxs,
+	}
+}
+
+func (d PendingTxLimitDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	// txs are re-checked in the order they were accepted, so they always fit
+	if !ctx.IsCheckTx() || ctx.IsReCheckTx() || simulate || d.committedSequence == nil {
+		return next(ctx, tx, simulate)
+	}
+
+	limit, flag := d.maxCosmos, FlagMaxPendingCosmosTxs
+	if isEVMTx(tx) {
+		limit, flag = d.maxEVM, FlagMaxPendingEVMTxs
+	}
+	if limit == 0 {
+		return next(ctx, tx, simulate)
+	}
+
+	sigTx, ok := tx.(authsigning.SigVerifiableTx)
+	if !ok {
+		return next(ctx, tx, simulate)
+	}
+	signers, err := sigTx.GetSigners()
+	if err != nil {
+		return ctx, err
+	}
+
+	for _, signer := range signers {
+		acc := d.ak.GetAccount(ctx, signer)
+		if acc == nil {
+			continue
+		}
+		committed, err := d.committedSequence(signer)
+		if err != nil {
+			// without a committed state there is nothing pending yet
+			continue
+		}
+		if pending := acc.GetSequence() - min(committed, acc.GetSequence()); pending >= limit {
+			return ctx, errorsmod.Wrapf(
+				errortypes.ErrMempoolIsFull,
+				"sender %s already has %d pending txs, the node accepts at most %d (%s), retry once they are included in a block",
+				sdk.AccAddress(signer), pending, limit, flag,
+			)
+		}
+	}
+
+	return next(ctx, tx, simulate)
+}
+
+func isEVMTx(tx sdk.Tx) bool {
+	for _, msg := range tx.GetMsgs() {
+		if _, ok := msg.(*evmtypes.MsgEthereumTx); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// txGasWanted returns the gas limit of an EVM tx or the gas of a Cosmos tx
+func txGasWanted(tx sdk.Tx) uint64 {
+	var gas uint64
+	var isEVM bool
+	for _, msg := range tx.GetMsgs() {
+		if ethMsg, ok := msg.(*evmtypes.MsgEthereumTx); ok {
+			gas += ethMsg.GetGas()
+			isEVM = true
+		}
+	}
+	if isEVM {
+		return gas
+	}
+
+	if feeTx, ok := tx.(sdk.FeeTx); ok {
+		return feeTx.GetGas()
+	}
+	return 0
+}
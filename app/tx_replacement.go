@@ -0,0 +1,324 @@
+package app
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	errorsmod "cosmossdk.io/errors"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	errortypes "github.com/cosmos/cosmos-sdk/types/errors"
+
+	evmanteinterfaces "github.com/cosmos/evm/ante/interfaces"
+	evmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+// EVMTxReplacements tracks the EVM txs the node accepted into its mempool by
+// sender and nonce, so an EVM tx paying higher fees replaces the pending tx of
+// its sender at the same nonce, like in the txpool of geth.
+//
+// The mempool of CometBFT has no notion of replacement and keeps both txs
+// until the next block: the replaced tx is left out of the blocks the node
+// proposes and rejected when the mempool rechecks it after the next block.
+// The tracking is local to the node, the other nodes replace the tx as the
+// replacement reaches them through the mempool gossip.
+type EVMTxReplacements struct {
+	priceBump uint64
+	txDecoder sdk.TxDecoder
+
+	mtx     sync.Mutex
+	pending map[common.Address]map[uint64]pendingEVMTx
+}
+
+var _ storetypes.ABCIListener = (*EVMTxReplacements)(nil)
+
+// pendingEVMTx is an EVM tx accepted into the mempool
+type pendingEVMTx struct {
+	hash      common.Hash
+	gasFeeCap *big.Int
+	gasTipCap *big.Int
+}
+
+// NewEVMTxReplacements returns the tracker of the pending EVM txs, nil if
+// priceBump is 0 which disables replacements.
+func NewEVMTxReplacements(priceBump uint64, txDecoder sdk.TxDecoder) *EVMTxReplacements {
+	if priceBump == 0 {
+		return nil
+	}
+	return &EVMTxReplacements{
+		priceBump: priceBump,
+		txDecoder: txDecoder,
+		pending:   map[common.Address]map[uint64]pendingEVMTx{},
+	}
+}
+
+// CheckPriceBump returns an error unless the fee cap and the tip cap of
+// replacement are both higher than the ones of pending by priceBump percent,
+// the rule of geth.
+func CheckPriceBump(pendingFeeCap, pendingTipCap *big.Int, replacement *ethtypes.Transaction, priceBump uint64) error {
+	factor := new(big.Int).SetUint64(100 + priceBump)
+	minFeeCap := new(big.Int).Div(new(big.Int).Mul(pendingFeeCap, factor), big.NewInt(100))
+	minTipCap := new(big.Int).Div(new(big.Int).Mul(pendingTipCap, factor), big.NewInt(100))
+	if replacement.GasFeeCap().Cmp(pendingFeeCap) <= 0 || replacement.GasFeeCap().Cmp(minFeeCap) < 0 ||
+		replacement.GasTipCap().Cmp(pendingTipCap) <= 0 || replacement.GasTipCap().Cmp(minTipCap) < 0 {
+		return errorsmod.Wrapf(
+			errortypes.ErrInsufficientFee,
+			"replacement transaction underpriced: the fee cap %s and the tip cap %s of the pending tx must both be raised by %d%% (%s)",
+			pendingFeeCap, pendingTipCap, priceBump, FlagEVMPriceBump,
+		)
+	}
+	return nil
+}
+
+// track records ethTx as the pending tx of sender at its nonce
+func (r *EVMTxReplacements) track(sender common.Address, ethTx *ethtypes.Transaction) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.pending[sender] == nil {
+		r.pending[sender] = map[uint64]pendingEVMTx{}
+	}
+	r.pending[sender][ethTx.Nonce()] = pendingEVMTx{
+		hash:      ethTx.Hash(),
+		gasFeeCap: ethTx.GasFeeCap(),
+		gasTipCap: ethTx.GasTipCap(),
+	}
+}
+
+// pendingTx returns the pending tx of sender at nonce
+func (r *EVMTxReplacements) pendingTx(sender common.Address, nonce uint64) (pendingEVMTx, bool) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	tx, ok := r.pending[sender][nonce]
+	return tx, ok
+}
+
+// replacedBy returns the hash of the tx that replaced ethTx, if it was replaced
+func (r *EVMTxReplacements) replacedBy(sender common.Address, ethTx *ethtypes.Transaction) (common.Hash, bool) {
+	pending, ok := r.pendingTx(sender, ethTx.Nonce())
+	if !ok || pending.hash == ethTx.Hash() {
+		return common.Hash{}, false
+	}
+	return pending.hash, true
+}
+
+// forget removes ethTx from the pending txs, unless it was replaced
+func (r *EVMTxReplacements) forget(sender common.Address, ethTx *ethtypes.Transaction) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if pending, ok := r.pending[sender][ethTx.Nonce()]; ok && pending.hash == ethTx.Hash() {
+		r.removeLocked(sender, ethTx.Nonce())
+	}
+}
+
+// forgetUpTo removes the pending txs of sender up to nonce
+func (r *EVMTxReplacements) forgetUpTo(sender common.Address, nonce uint64) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	for pendingNonce := range r.pending[sender] {
+		if pendingNonce <= nonce {
+			r.removeLocked(sender, pendingNonce)
+		}
+	}
+}
+
+func (r *EVMTxReplacements) removeLocked(sender common.Address, nonce uint64) {
+	delete(r.pending[sender], nonce)
+	if len(r.pending[sender]) == 0 {
+		delete(r.pending, sender)
+	}
+}
+
+// ProposalTxs drops the replaced EVM txs from txs, the txs of the mempool, and
+// orders the EVM txs of each sender by nonce in the slots they take, since a
+// replacement comes after the txs its sender sent after the replaced one.
+func (r *EVMTxReplacements) ProposalTxs(txs [][]byte) [][]byte {
+	type senderTx struct {
+		bz    []byte
+		nonce uint64
+	}
+	res := make([][]byte, 0, len(txs))
+	slots := map[common.Address][]int{}
+	bySender := map[common.Address][]senderTx{}
+	for _, bz := range txs {
+		tx, err := r.txDecoder(bz)
+		if err != nil {
+			res = append(res, bz)
+			continue
+		}
+		msg, ok := singleEVMTx(tx)
+		if !ok {
+			res = append(res, bz)
+			continue
+		}
+		sender, ethTx := common.BytesToAddress(msg.GetFrom()), msg.AsTransaction()
+		if _, replaced := r.replacedBy(sender, ethTx); replaced {
+			continue
+		}
+		slots[sender] = append(slots[sender], len(res))
+		bySender[sender] = append(bySender[sender], senderTx{bz: bz, nonce: ethTx.Nonce()})
+		res = append(res, bz)
+	}
+
+	for sender, senderTxs := range bySender {
+		sort.SliceStable(senderTxs, func(i, j int) bool { return senderTxs[i].nonce < senderTxs[j].nonce })
+		for i, slot := range slots[sender] {
+			res[slot] = senderTxs[i].bz
+		}
+	}
+	return res
+}
+
+// ListenFinalizeBlock implements storetypes.ABCIListener, the txs of the
+// senders of the block up to the nonces they used aren't pending anymore.
+func (r *EVMTxReplacements) ListenFinalizeBlock(_ context.Context, req abci.RequestFinalizeBlock, _ abci.ResponseFinalizeBlock) error {
+	for _, bz := range req.Txs {
+		tx, err := r.txDecoder(bz)
+		if err != nil {
+			continue
+		}
+		for _, msg := range tx.GetMsgs() {
+			if ethMsg, ok := msg.(*evmtypes.MsgEthereumTx); ok {
+				r.forgetUpTo(common.BytesToAddress(ethMsg.GetFrom()), ethMsg.AsTransaction().Nonce())
+			}
+		}
+	}
+	return nil
+}
+
+// ListenCommit implements storetypes.ABCIListener.
+func (r *EVMTxReplacements) ListenCommit(context.Context, abci.ResponseCommit, []*storetypes.StoreKVPair) error {
+	return nil
+}
+
+// evmTxReplacementsHandler builds the proposal with the next handler from the
+// txs of the mempool without the replaced EVM txs, see
+// EVMTxReplacements.ProposalTxs. Like the priority lanes, it only changes the
+// proposals of the node.
+func evmTxReplacementsHandler(replacements *EVMTxReplacements, next sdk.PrepareProposalHandler) sdk.PrepareProposalHandler {
+	if replacements == nil {
+		return next
+	}
+
+	return func(ctx sdk.Context, req *abci.RequestPrepareProposal) (*abci.ResponsePrepareProposal, error) {
+		proposal := *req
+		proposal.Txs = replacements.ProposalTxs(req.Txs)
+		return next(ctx, &proposal)
+	}
+}
+
+// EVMTxReplacementDecorator lets an EVM tx replace the pending tx of its
+// sender at the same nonce when it raises its fee cap and tip cap by the
+// price bump of the node, and rejects the replaced tx when the mempool
+// rechecks it. Like the other tx limits it only applies to the txs entering
+// the mempool, the EVM ante handler checks the nonces of the txs of blocks.
+//
+// The sender pays the fees of the replacement in the check state on top of
+// the ones of the replaced tx, until the check state is reset by the next
+// block. The pending txs of the sender with higher nonces stay behind the
+// replacement in the mempool: the node proposes them in nonce order, but they
+// are dropped if the next block includes none of them and must be sent again.
+type EVMTxReplacementDecorator struct {
+	ak                evmanteinterfaces.AccountKeeper
+	committedSequence AccountSequenceFunc
+	replacements      *EVMTxReplacements
+}
+
+func NewEVMTxReplacementDecorator(ak evmanteinterfaces.AccountKeeper, committedSequence AccountSequenceFunc, replacements *EVMTxReplacements) EVMTxReplacementDecorator {
+	return EVMTxReplacementDecorator{
+		ak:                ak,
+		committedSequence: committedSequence,
+		replacements:      replacements,
+	}
+}
+
+func (d EVMTxReplacementDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	if !ctx.IsCheckTx() || simulate || d.replacements == nil || d.committedSequence == nil {
+		return next(ctx, tx, simulate)
+	}
+	msg, ok := singleEVMTx(tx)
+	if !ok {
+		return next(ctx, tx, simulate)
+	}
+	// the EVM ante handler checks the sender against the signature, the txs
+	// are only tracked once it accepted them
+	sender, ethTx := common.BytesToAddress(msg.GetFrom()), msg.AsTransaction()
+
+	if ctx.IsReCheckTx() {
+		if replacement, replaced := d.replacements.replacedBy(sender, ethTx); replaced {
+			return ctx, errorsmod.Wrapf(errortypes.ErrConflict, "tx %s was replaced by %s", ethTx.Hash().Hex(), replacement.Hex())
+		}
+		newCtx, err := next(ctx, tx, simulate)
+		if err != nil {
+			d.replacements.forget(sender, ethTx)
+		}
+		return newCtx, err
+	}
+
+	acc := d.ak.GetAccount(ctx, sender.Bytes())
+	if acc == nil || ethTx.Nonce() >= acc.GetSequence() {
+		newCtx, err := next(ctx, tx, simulate)
+		if err == nil {
+			d.replacements.track(sender, ethTx)
+		}
+		return newCtx, err
+	}
+	return d.replace(ctx, acc, sender, ethTx, tx, simulate, next)
+}
+
+// replace checks a tx reusing the nonce of a pending tx of its sender
+func (d EVMTxReplacementDecorator) replace(ctx sdk.Context, acc sdk.AccountI, sender common.Address, ethTx *ethtypes.Transaction, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	nonce := ethTx.Nonce()
+	pending, found := d.replacements.pendingTx(sender, nonce)
+	// the EVM ante handler rejects the nonces used by the committed txs
+	if committed, err := d.committedSequence(sender.Bytes()); !found || err != nil || nonce < committed {
+		return next(ctx, tx, simulate)
+	}
+	if pending.hash == ethTx.Hash() {
+		return ctx, errorsmod.Wrapf(errortypes.ErrTxInMempoolCache, "tx %s is already pending", ethTx.Hash().Hex())
+	}
+	if err := CheckPriceBump(pending.gasFeeCap, pending.gasTipCap, ethTx, d.replacements.priceBump); err != nil {
+		return ctx, err
+	}
+
+	// the EVM ante handler checks the nonce against the sequence of the
+	// account in the check state, which counts the pending txs: it checks the
+	// replacement as the next tx of the sender and the sequence is restored
+	checkSequence := acc.GetSequence()
+	if err := acc.SetSequence(nonce); err != nil {
+		return ctx, err
+	}
+	d.ak.SetAccount(ctx, acc)
+	newCtx, err := next(ctx, tx, simulate)
+	if err != nil {
+		return newCtx, err
+	}
+	acc = d.ak.GetAccount(newCtx, sender.Bytes())
+	if err := acc.SetSequence(checkSequence); err != nil {
+		return newCtx, err
+	}
+	d.ak.SetAccount(newCtx, acc)
+
+	d.replacements.track(sender, ethTx)
+	return newCtx, nil
+}
+
+// singleEVMTx returns the MsgEthereumTx of tx if it is its only message
+func singleEVMTx(tx sdk.Tx) (*evmtypes.MsgEthereumTx, bool) {
+	msgs := tx.GetMsgs()
+	if len(msgs) != 1 {
+		return nil, false
+	}
+	msg, ok := msgs[0].(*evmtypes.MsgEthereumTx)
+	return msg, ok
+}
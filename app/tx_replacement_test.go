@@ -0,0 +1,278 @@
+package app
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	errortypes "github.com/cosmos/cosmos-sdk/types/errors"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	evmanteinterfaces "github.com/cosmos/evm/ante/interfaces"
+	evmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+func TestCheckPriceBump(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	replacement := func(feeCap, tipCap int64) *ethtypes.Transaction {
+		return signedReplacementTx(t, key, 0, feeCap, tipCap)
+	}
+
+	testCases := []struct {
+		name        string
+		feeCap      int64
+		tipCap      int64
+		replacement *ethtypes.Transaction
+		priceBump   uint64
+		expErr      bool
+	}{
+		{"both caps bumped", 100, 10, replacement(110, 11), 10, false},
+		{"both caps more than bumped", 100, 10, replacement(1000, 100), 10, false},
+		{"fee cap not bumped enough", 100, 10, replacement(109, 11), 10, true},
+		{"tip cap not bumped enough", 100, 10, replacement(110, 10), 10, true},
+		{"same caps", 100, 10, replacement(100, 10), 10, true},
+		{"lower caps", 100, 10, replacement(50, 5), 10, true},
+		// the rounded down minimum isn't enough, the caps must still rise
+		{"rounded bump of a low tip cap", 100, 1, replacement(110, 1), 10, true},
+		{"low tip cap raised by one", 100, 1, replacement(110, 2), 10, false},
+		{"zero tip cap", 100, 0, replacement(110, 1), 10, false},
+		{"larger price bump", 100, 10, replacement(110, 11), 50, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckPriceBump(big.NewInt(tc.feeCap), big.NewInt(tc.tipCap), tc.replacement, tc.priceBump)
+			if tc.expErr {
+				require.ErrorIs(t, err, errortypes.ErrInsufficientFee)
+				require.Contains(t, err.Error(), "replacement transaction underpriced")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNewEVMTxReplacementsDisabled(t *testing.T) {
+	require.Nil(t, NewEVMTxReplacements(0, nil))
+
+	called := false
+	next := func(sdk.Context, *abci.RequestPrepareProposal) (*abci.ResponsePrepareProposal, error) {
+		called = true
+		return &abci.ResponsePrepareProposal{}, nil
+	}
+	_, err := evmTxReplacementsHandler(nil, next)(sdk.Context{}, &abci.RequestPrepareProposal{})
+	require.NoError(t, err)
+	require.True(t, called)
+}
+
+// signedReplacementTx is a dynamic fee self-send signed by key
+func signedReplacementTx(t *testing.T, key *ecdsa.PrivateKey, nonce uint64, feeCap, tipCap int64) *ethtypes.Transaction {
+	t.Helper()
+
+	chainID := big.NewInt(2391)
+	to := crypto.PubkeyToAddress(key.PublicKey)
+	tx, err := ethtypes.SignNewTx(key, ethtypes.LatestSignerForChainID(chainID), &ethtypes.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		To:        &to,
+		Gas:       21000,
+		GasFeeCap: big.NewInt(feeCap),
+		GasTipCap: big.NewInt(tipCap),
+		Value:     big.NewInt(0),
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+// replacementTestTx wraps ethTx signed by key into a Cosmos tx
+func replacementTestTx(t *testing.T, key *ecdsa.PrivateKey, ethTx *ethtypes.Transaction) limitTestTx {
+	t.Helper()
+
+	msg := &evmtypes.MsgEthereumTx{}
+	require.NoError(t, msg.FromEthereumTx(ethTx))
+	msg.From = crypto.PubkeyToAddress(key.PublicKey).Hex()
+	return limitTestTx{msgs: []sdk.Msg{msg}}
+}
+
+// mutableAccountKeeper stores the accounts set by the decorators
+type mutableAccountKeeper struct {
+	evmanteinterfaces.AccountKeeper
+
+	accounts map[string]sdk.AccountI
+}
+
+func (ak *mutableAccountKeeper) GetAccount(_ context.Context, addr sdk.AccAddress) sdk.AccountI {
+	return ak.accounts[addr.String()]
+}
+
+func (ak *mutableAccountKeeper) SetAccount(_ context.Context, acc sdk.AccountI) {
+	ak.accounts[acc.GetAddress().String()] = acc
+}
+
+func (ak *mutableAccountKeeper) sequence(addr common.Address) uint64 {
+	return ak.accounts[sdk.AccAddress(addr.Bytes()).String()].GetSequence()
+}
+
+func TestEVMTxReplacementDecorator(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	senderAcc := sdk.AccAddress(sender.Bytes())
+
+	// the sender has committed the nonces up to 4
+	ak := &mutableAccountKeeper{accounts: map[string]sdk.AccountI{
+		senderAcc.String(): authtypes.NewBaseAccount(senderAcc, nil, 1, 5),
+	}}
+	committed := func(addr sdk.AccAddress) (uint64, error) {
+		if addr.Equals(senderAcc) {
+			return 5, nil
+		}
+		return 0, errors.New("not found")
+	}
+	replacements := NewEVMTxReplacements(DefaultEVMPriceBump, laneTestTxs{}.decode)
+	decorator := NewEVMTxReplacementDecorator(ak, committed, replacements)
+
+	// next checks the nonces against the sequences like the EVM ante handler
+	next := func(ctx sdk.Context, tx sdk.Tx, _ bool) (sdk.Context, error) {
+		msg, _ := singleEVMTx(tx)
+		acc := ak.GetAccount(ctx, msg.GetFrom())
+		if nonce := msg.AsTransaction().Nonce(); nonce != acc.GetSequence() {
+			return ctx, errorsmod.Wrapf(errortypes.ErrInvalidSequence, "invalid nonce; got %d, expected %d", nonce, acc.GetSequence())
+		}
+		require.NoError(t, acc.SetSequence(acc.GetSequence()+1))
+		ak.SetAccount(ctx, acc)
+		return ctx, nil
+	}
+	checkTx := sdk.Context{}.WithIsCheckTx(true)
+	reCheckTx := checkTx.WithIsReCheckTx(true)
+
+	// two pending txs at nonces 5 and 6
+	pending5 := signedReplacementTx(t, key, 5, 100, 10)
+	pending6 := signedReplacementTx(t, key, 6, 100, 10)
+	for _, ethTx := range []*ethtypes.Transaction{pending5, pending6} {
+		_, err := decorator.AnteHandle(checkTx, replacementTestTx(t, key, ethTx), false, next)
+		require.NoError(t, err)
+	}
+	require.Equal(t, uint64(7), ak.sequence(sender))
+	tracked, found := replacements.pendingTx(sender, 5)
+	require.True(t, found)
+	require.Equal(t, pending5.Hash(), tracked.hash)
+
+	// the same tx again or an underpriced replacement
+	_, err = decorator.AnteHandle(checkTx, replacementTestTx(t, key, pending5), false, next)
+	require.ErrorIs(t, err, errortypes.ErrTxInMempoolCache)
+	_, err = decorator.AnteHandle(checkTx, replacementTestTx(t, key, signedReplacementTx(t, key, 5, 105, 11)), false, next)
+	require.ErrorIs(t, err, errortypes.ErrInsufficientFee)
+	require.Equal(t, uint64(7), ak.sequence(sender))
+
+	// a replacement paying enough takes the nonce of the pending tx, and the
+	// check state sequence still counts the two pending txs
+	replacement5 := signedReplacementTx(t, key, 5, 110, 11)
+	_, err = decorator.AnteHandle(checkTx, replacementTestTx(t, key, replacement5), false, next)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), ak.sequence(sender))
+	tracked, found = replacements.pendingTx(sender, 5)
+	require.True(t, found)
+	require.Equal(t, replacement5.Hash(), tracked.hash)
+
+	// a further replacement must bump the fees of the replacement
+	_, err = decorator.AnteHandle(checkTx, replacementTestTx(t, key, signedReplacementTx(t, key, 5, 110, 11)), false, next)
+	require.ErrorIs(t, err, errortypes.ErrTxInMempoolCache)
+	_, err = decorator.AnteHandle(checkTx, replacementTestTx(t, key, signedReplacementTx(t, key, 5, 120, 12)), false, next)
+	require.ErrorIs(t, err, errortypes.ErrInsufficientFee)
+
+	// the nonces without a pending tx go through the EVM ante handler
+	_, err = decorator.AnteHandle(checkTx, replacementTestTx(t, key, signedReplacementTx(t, key, 4, 1000, 100)), false, next)
+	require.ErrorIs(t, err, errortypes.ErrInvalidSequence)
+
+	// the replaced tx is rejected when the mempool rechecks it, the others go
+	// through the EVM ante handler again with the check state reset to the
+	// committed sequence
+	ak.accounts[senderAcc.String()] = authtypes.NewBaseAccount(senderAcc, nil, 1, 5)
+	_, err = decorator.AnteHandle(reCheckTx, replacementTestTx(t, key, pending5), false, next)
+	require.ErrorIs(t, err, errortypes.ErrConflict)
+	require.Contains(t, err.Error(), replacement5.Hash().Hex())
+	_, err = decorator.AnteHandle(reCheckTx, replacementTestTx(t, key, pending6), false, next)
+	require.ErrorIs(t, err, errortypes.ErrInvalidSequence)
+	_, found = replacements.pendingTx(sender, 6)
+	require.False(t, found, "the tx failing the recheck should be forgotten")
+	_, err = decorator.AnteHandle(reCheckTx, replacementTestTx(t, key, replacement5), false, next)
+	require.NoError(t, err)
+	_, found = replacements.pendingTx(sender, 5)
+	require.True(t, found)
+
+	// blocks aren't affected
+	_, err = decorator.AnteHandle(sdk.Context{}, replacementTestTx(t, key, pending5), false, next)
+	require.ErrorIs(t, err, errortypes.ErrInvalidSequence)
+}
+
+func TestEVMTxReplacementsProposalTxs(t *testing.T) {
+	txs := laneTestTxs{}
+	replacements := NewEVMTxReplacements(DefaultEVMPriceBump, txs.decode)
+
+	alice, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	bob, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	aliceAddr, bobAddr := crypto.PubkeyToAddress(alice.PublicKey), crypto.PubkeyToAddress(bob.PublicKey)
+
+	add := func(name string, key *ecdsa.PrivateKey, ethTx *ethtypes.Transaction) []byte {
+		txs[name] = replacementTestTx(t, key, ethTx)
+		return []byte(name)
+	}
+	alice0Tx, alice1Tx := signedReplacementTx(t, alice, 0, 100, 10), signedReplacementTx(t, alice, 1, 100, 10)
+	alice0ReplacementTx := signedReplacementTx(t, alice, 0, 200, 20)
+	bob0Tx := signedReplacementTx(t, bob, 0, 100, 10)
+	replacements.track(aliceAddr, alice0Tx)
+	replacements.track(aliceAddr, alice1Tx)
+	replacements.track(bobAddr, bob0Tx)
+	replacements.track(aliceAddr, alice0ReplacementTx)
+
+	alice0, alice1 := add("alice0", alice, alice0Tx), add("alice1", alice, alice1Tx)
+	alice0Replacement := add("alice0-replacement", alice, alice0ReplacementTx)
+	bob0 := add("bob0", bob, bob0Tx)
+	send := txs.add("send", 100, &banktypes.MsgSend{})
+	invalid := []byte("invalid")
+
+	// the mempool orders the txs by arrival, the replacement comes last and
+	// takes the slot of the next tx of its sender
+	req := &abci.RequestPrepareProposal{Txs: [][]byte{alice0, bob0, alice1, invalid, send, alice0Replacement}}
+	expTxs := [][]byte{bob0, alice0Replacement, invalid, send, alice1}
+	require.Equal(t, expTxs, replacements.ProposalTxs(req.Txs))
+
+	var proposed [][]byte
+	next := func(_ sdk.Context, req *abci.RequestPrepareProposal) (*abci.ResponsePrepareProposal, error) {
+		proposed = req.Txs
+		return &abci.ResponsePrepareProposal{Txs: req.Txs}, nil
+	}
+	_, err = evmTxReplacementsHandler(replacements, next)(sdk.Context{}, req)
+	require.NoError(t, err)
+	require.Equal(t, expTxs, proposed)
+	require.Len(t, req.Txs, 6, "the request should be left as is")
+
+	// the txs of the block up to their nonces aren't pending anymore
+	err = replacements.ListenFinalizeBlock(context.Background(), abci.RequestFinalizeBlock{
+		Txs: [][]byte{alice0Replacement, invalid, send},
+	}, abci.ResponseFinalizeBlock{})
+	require.NoError(t, err)
+	_, found := replacements.pendingTx(aliceAddr, 0)
+	require.False(t, found)
+	_, found = replacements.pendingTx(aliceAddr, 1)
+	require.True(t, found)
+	_, found = replacements.pendingTx(bobAddr, 0)
+	require.True(t, found)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Asphere-xyz/tacchain/app/upgrades"
 	v0010 "github.com/Asphere-xyz/tacchain/app/upgrades/v0.0.10"
 	v0011 "github.com/Asphere-xyz/tacchain/app/upgrades/v0.0.11"
+	v0012 "github.com/Asphere-xyz/tacchain/app/upgrades/v0.0.12"
 	v009 "github.com/Asphere-xyz/tacchain/app/upgrades/v0.0.9"
 )
 
@@ -16,6 +17,7 @@ var Upgrades = []upgrades.Upgrade{
 	v009.Upgrade,
 	v0010.Upgrade,
 	v0011.Upgrade,
+	v0012.Upgrade,
 }
 
 // RegisterUpgradeHandlers registers the chain upgrade handlers
@@ -26,20 +28,23 @@ func (app *TacChainApp) RegisterUpgradeHandlers() {
 		ConsensusParamsKeeper: &app.ConsensusParamsKeeper,
 		CapabilityKeeper:      app.CapabilityKeeper,
 		IBCKeeper:             app.IBCKeeper,
+		EmissionKeeper:        &app.EmissionKeeper,
+		BaseDenom:             BaseDenom,
 		Codec:                 app.appCodec,
 		GetStoreKey:           app.GetKey,
 	}
 	app.GetStoreKeys()
 	// register all upgrade handlers
 	for _, upgrade := range Upgrades {
-		app.UpgradeKeeper.SetUpgradeHandler(
-			upgrade.UpgradeName,
-			upgrade.CreateUpgradeHandler(
-				app.ModuleManager,
-				app.configurator,
-				&keepers,
-			),
+		handler := upgrade.CreateUpgradeHandler(
+			app.ModuleManager,
+			app.configurator,
+			&keepers,
 		)
+		if app.backupConfig.Enable {
+			handler = upgrades.WithBackup(app.backupConfig, app.appDB, app.appDBBackend, app.Logger(), handler)
+		}
+		app.UpgradeKeeper.SetUpgradeHandler(upgrade.UpgradeName, handler)
 	}
 
 	upgradeInfo, err := app.UpgradeKeeper.ReadUpgradeInfoFromDisk()
@@ -0,0 +1,209 @@
+package upgrades
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/spf13/cast"
+
+	"cosmossdk.io/log"
+	upgradetypes "cosmossdk.io/x/upgrade/types"
+
+	servertypes "github.com/cosmos/cosmos-sdk/server/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+)
+
+const (
+	FlagBackupEnable    = "backup.enable"
+	FlagBackupDir       = "backup.dir"
+	FlagBackupRetention = "backup.retention"
+
+	// DefaultBackupRetention is the default number of pre-upgrade backups kept on disk
+	DefaultBackupRetention = 2
+
+	// applicationDB is the database of the application state inside the data dir
+	applicationDB = "application.db"
+)
+
+// DefaultBackupConfigTemplate defines the app.toml section of the pre-upgrade backup
+const DefaultBackupConfigTemplate = `
+###############################################################################
+###                        Pre-upgrade Backup Configuration                  ###
+###############################################################################
+
+[backup]
+
+# Copy the application state to the backup dir before an upgrade handler runs,
+# so a failed upgrade can be rolled back without a resync. A failed backup is
+# logged, the upgrade proceeds without it.
+enable = {{ .Backup.Enable }}
+
+# Directory the backups are written to, defaults to <home>/backups.
+dir = "{{ .Backup.Dir }}"
+
+# Number of backups kept, older ones are removed after a new backup was taken.
+retention = {{ .Backup.Retention }}
+`
+
+// BackupConfig configures the copy of the application state taken before an upgrade
+type BackupConfig struct {
+	Enable    bool   `mapstructure:"enable"`
+	Dir       string `mapstructure:"dir"`
+	Retention int    `mapstructure:"retention"`
+}
+
+// DefaultBackupConfig returns the default pre-upgrade backup configuration
+func DefaultBackupConfig() BackupConfig {
+	return BackupConfig{
+		Enable:    false,
+		Dir:       "",
+		Retention: DefaultBackupRetention,
+	}
+}
+
+// BackupConfigFromAppOptions reads the pre-upgrade backup configuration of the
+// node with the given home dir.
+func BackupConfigFromAppOptions(appOpts servertypes.AppOptions, homeDir string) BackupConfig {
+	cfg := BackupConfig{
+		Enable:    cast.ToBool(appOpts.Get(FlagBackupEnable)),
+		Dir:       cast.ToString(appOpts.Get(FlagBackupDir)),
+		Retention: cast.ToInt(appOpts.Get(FlagBackupRetention)),
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = filepath.Join(homeDir, "backups")
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = DefaultBackupRetention
+	}
+	return cfg
+}
+
+// WithBackup wraps an upgrade handler to copy db, the application database,
+// before the handler migrates the state. No block is being committed while
+// the handler runs, so the copy holds the state of the previous block. A
+// failed backup is logged and doesn't keep the upgrade from proceeding: every
+// node would halt at the upgrade height otherwise.
+func WithBackup(cfg BackupConfig, db dbm.DB, backend dbm.BackendType, logger log.Logger, handler upgradetypes.UpgradeHandler) upgradetypes.UpgradeHandler {
+	return func(ctx context.Context, plan upgradetypes.Plan, fromVM module.VersionMap) (module.VersionMap, error) {
+		target, err := Backup(cfg, db, backend, plan)
+		if err != nil {
+			logger.Error("failed to back up state before upgrade", "upgrade", plan.Name, "height", plan.Height, "err", err)
+			return handler(ctx, plan, fromVM)
+		}
+		logger.Info("backed up state before upgrade", "upgrade", plan.Name, "height", plan.Height, "path", target)
+
+		if err := PruneBackups(cfg); err != nil {
+			// stale backups are only a disk space concern, the upgrade can proceed
+			logger.Error("failed to prune old backups", "dir", cfg.Dir, "err", err)
+		}
+
+		return handler(ctx, plan, fromVM)
+	}
+}
+
+// backupBatchSize is the number of keys written to a backup per batch
+const backupBatchSize = 10_000
+
+// Backup copies db into a database of backend in
+// <backup dir>/<upgrade name>-<height>/application.db and returns the path
+// of the backup. The keys are read with an iterator, which reads a snapshot
+// of db: the files the database or the compactor of the node remove while
+// the copy runs don't affect it.
+func Backup(cfg BackupConfig, db dbm.DB, backend dbm.BackendType, plan upgradetypes.Plan) (string, error) {
+	target := filepath.Join(cfg.Dir, fmt.Sprintf("%s-%d", plan.Name, plan.Height))
+	if err := os.RemoveAll(target); err != nil {
+		return "", err
+	}
+
+	// copy into a temporary dir first so an interrupted backup never looks complete
+	tmp := target + ".tmp"
+	if err := os.RemoveAll(tmp); err != nil {
+		return "", err
+	}
+	if err := copyDB(db, backend, tmp); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", err
+	}
+
+	return target, os.Rename(tmp, target)
+}
+
+// copyDB copies the keys of db into a new application database in dir
+func copyDB(db dbm.DB, backend dbm.BackendType, dir string) (err error) {
+	out, err := dbm.NewDB(strings.TrimSuffix(applicationDB, ".db"), backend, dir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	it, err := db.Iterator(nil, nil)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	batch := out.NewBatch()
+	defer func() { batch.Close() }()
+	n := 0
+	for ; it.Valid(); it.Next() {
+		if err := batch.Set(it.Key(), it.Value()); err != nil {
+			return err
+		}
+		if n++; n%backupBatchSize == 0 {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Close()
+			batch = out.NewBatch()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.WriteSync()
+}
+
+// PruneBackups removes the oldest backups so at most cfg.Retention are kept.
+func PruneBackups(cfg BackupConfig) error {
+	entries, err := os.ReadDir(cfg.Dir)
+	if err != nil {
+		return err
+	}
+
+	type backup struct {
+		path    string
+		modTime int64
+	}
+	var backups []backup
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasSuffix(entry.Name(), ".tmp") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		backups = append(backups, backup{filepath.Join(cfg.Dir, entry.Name()), info.ModTime().UnixNano()})
+	}
+
+	if len(backups) <= cfg.Retention {
+		return nil
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].modTime > backups[j].modTime })
+	for _, b := range backups[cfg.Retention:] {
+		if err := os.RemoveAll(b.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
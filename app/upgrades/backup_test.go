@@ -0,0 +1,138 @@
+package upgrades
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	upgradetypes "cosmossdk.io/x/upgrade/types"
+
+	"github.com/cosmos/cosmos-sdk/types/module"
+)
+
+// newAppDB returns an application database holding a few keys
+func newAppDB(t *testing.T) dbm.DB {
+	t.Helper()
+
+	db, err := dbm.NewDB("application", dbm.GoLevelDBBackend, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	for i := 0; i < 3*backupBatchSize/2; i++ {
+		require.NoError(t, db.Set([]byte(fmt.Sprintf("key%06d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+	return db
+}
+
+func TestBackup(t *testing.T) {
+	db := newAppDB(t)
+	cfg := BackupConfig{Enable: true, Dir: filepath.Join(t.TempDir(), "backups"), Retention: 1}
+
+	target, err := Backup(cfg, db, dbm.GoLevelDBBackend, upgradetypes.Plan{Name: "v1.0.0", Height: 10})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(cfg.Dir, "v1.0.0-10"), target)
+
+	// the backup is a database of the same keys, usable in place of the original
+	backup, err := dbm.NewDB("application", dbm.GoLevelDBBackend, target)
+	require.NoError(t, err)
+	defer backup.Close()
+	n := 0
+	it, err := backup.Iterator(nil, nil)
+	require.NoError(t, err)
+	for ; it.Valid(); it.Next() {
+		require.Equal(t, fmt.Sprintf("value%d", n), string(it.Value()))
+		n++
+	}
+	require.NoError(t, it.Close())
+	require.Equal(t, 3*backupBatchSize/2, n)
+
+	_, err = os.Stat(target + ".tmp")
+	require.True(t, os.IsNotExist(err), "temporary dir should be gone")
+}
+
+func TestBackupWhileWriting(t *testing.T) {
+	db := newAppDB(t)
+	cfg := BackupConfig{Enable: true, Dir: filepath.Join(t.TempDir(), "backups"), Retention: 1}
+
+	// writes and compactions running alongside don't fail the backup
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			_ = db.Set([]byte(fmt.Sprintf("new%06d", i)), make([]byte, 1024))
+		}
+		_ = db.(*dbm.GoLevelDB).ForceCompact(nil, nil)
+	}()
+	_, err := Backup(cfg, db, dbm.GoLevelDBBackend, upgradetypes.Plan{Name: "v1.0.0", Height: 10})
+	<-done
+	require.NoError(t, err)
+}
+
+func TestPruneBackups(t *testing.T) {
+	cfg := BackupConfig{Enable: true, Dir: t.TempDir(), Retention: 2}
+
+	now := time.Now()
+	names := []string{"v1.0.0-10", "v2.0.0-20", "v3.0.0-30"}
+	for i, name := range names {
+		path := filepath.Join(cfg.Dir, name)
+		require.NoError(t, os.Mkdir(path, 0o755))
+		modTime := now.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	// interrupted backups are not counted
+	require.NoError(t, os.Mkdir(filepath.Join(cfg.Dir, "v4.0.0-40.tmp"), 0o755))
+
+	require.NoError(t, PruneBackups(cfg))
+
+	entries, err := os.ReadDir(cfg.Dir)
+	require.NoError(t, err)
+	var kept []string
+	for _, entry := range entries {
+		kept = append(kept, entry.Name())
+	}
+	require.ElementsMatch(t, []string{"v2.0.0-20", "v3.0.0-30", "v4.0.0-40.tmp"}, kept)
+}
+
+func TestWithBackup(t *testing.T) {
+	db := newAppDB(t)
+	cfg := BackupConfig{Enable: true, Dir: filepath.Join(t.TempDir(), "backups"), Retention: 1}
+	plan := upgradetypes.Plan{Name: "v1.0.0", Height: 10}
+
+	called := false
+	handler := WithBackup(cfg, db, dbm.GoLevelDBBackend, log.NewNopLogger(), func(_ context.Context, _ upgradetypes.Plan, vm module.VersionMap) (module.VersionMap, error) {
+		// the backup must exist before the state is migrated
+		_, err := os.Stat(filepath.Join(cfg.Dir, "v1.0.0-10", applicationDB))
+		require.NoError(t, err)
+		called = true
+		return vm, nil
+	})
+
+	vm, err := handler(context.Background(), plan, module.VersionMap{"bank": 1})
+	require.NoError(t, err)
+	require.True(t, called)
+	require.Equal(t, module.VersionMap{"bank": 1}, vm)
+}
+
+func TestWithBackupFailure(t *testing.T) {
+	// a file in place of the backup dir fails the backup
+	dir := filepath.Join(t.TempDir(), "backups")
+	require.NoError(t, os.WriteFile(dir, []byte("not a dir"), 0o644))
+	cfg := BackupConfig{Enable: true, Dir: dir, Retention: 1}
+
+	called := false
+	handler := WithBackup(cfg, newAppDB(t), dbm.GoLevelDBBackend, log.NewNopLogger(), func(_ context.Context, _ upgradetypes.Plan, vm module.VersionMap) (module.VersionMap, error) {
+		called = true
+		return vm, nil
+	})
+
+	// the upgrade proceeds without its backup
+	_, err := handler(context.Background(), upgradetypes.Plan{Name: "v1.0.0", Height: 10}, module.VersionMap{})
+	require.NoError(t, err)
+	require.True(t, called)
+}
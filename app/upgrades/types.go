@@ -14,6 +14,8 @@ import (
 	authkeeper "github.com/cosmos/cosmos-sdk/x/auth/keeper"
 	consensusparamkeeper "github.com/cosmos/cosmos-sdk/x/consensus/keeper"
 	paramskeeper "github.com/cosmos/cosmos-sdk/x/params/keeper"
+
+	emissionkeeper "github.com/Asphere-xyz/tacchain/x/emission/keeper"
 )
 
 type AppKeepers struct {
@@ -24,6 +26,8 @@ type AppKeepers struct {
 	GetStoreKey           func(storeKey string) *storetypes.KVStoreKey
 	CapabilityKeeper      *capabilitykeeper.Keeper
 	IBCKeeper             *ibckeeper.Keeper
+	EmissionKeeper        *emissionkeeper.Keeper
+	BaseDenom             string
 }
 
 type ModuleManager interface {
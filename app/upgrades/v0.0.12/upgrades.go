@@ -0,0 +1,81 @@
+package v0012
+
+import (
+	"context"
+
+	storetypes "cosmossdk.io/store/types"
+	upgradetypes "cosmossdk.io/x/upgrade/types"
+
+	"github.com/Asphere-xyz/tacchain/app/upgrades"
+	accountactivitytypes "github.com/Asphere-xyz/tacchain/x/accountactivity/types"
+	autocompoundtypes "github.com/Asphere-xyz/tacchain/x/autocompound/types"
+	bridgetypes "github.com/Asphere-xyz/tacchain/x/bridge/types"
+	contractmetatypes "github.com/Asphere-xyz/tacchain/x/contractmeta/types"
+	emissiontypes "github.com/Asphere-xyz/tacchain/x/emission/types"
+	feehistorytypes "github.com/Asphere-xyz/tacchain/x/feehistory/types"
+	feeroutingtypes "github.com/Asphere-xyz/tacchain/x/feerouting/types"
+	nameservicetypes "github.com/Asphere-xyz/tacchain/x/nameservice/types"
+	performancetypes "github.com/Asphere-xyz/tacchain/x/performance/types"
+	recoverytypes "github.com/Asphere-xyz/tacchain/x/recovery/types"
+	rewardsnapshottypes "github.com/Asphere-xyz/tacchain/x/rewardsnapshot/types"
+	selfbondtypes "github.com/Asphere-xyz/tacchain/x/selfbond/types"
+	tacgovtypes "github.com/Asphere-xyz/tacchain/x/tacgov/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+)
+
+// UpgradeName defines the on-chain upgrade name
+const UpgradeName = "v0.0.12"
+
+var Upgrade = upgrades.Upgrade{
+	UpgradeName:          UpgradeName,
+	CreateUpgradeHandler: CreateUpgradeHandler,
+	StoreUpgrades: storetypes.StoreUpgrades{
+		Added: []string{
+			recoverytypes.StoreKey,
+			performancetypes.StoreKey,
+			autocompoundtypes.StoreKey,
+			contractmetatypes.StoreKey,
+			bridgetypes.StoreKey,
+			tacgovtypes.StoreKey,
+			rewardsnapshottypes.StoreKey,
+			accountactivitytypes.StoreKey,
+			feehistorytypes.StoreKey,
+			feeroutingtypes.StoreKey,
+			nameservicetypes.StoreKey,
+			selfbondtypes.StoreKey,
+		},
+		Deleted: []string{},
+	},
+}
+
+func CreateUpgradeHandler(
+	mm upgrades.ModuleManager,
+	configurator module.Configurator,
+	ak *upgrades.AppKeepers,
+) upgradetypes.UpgradeHandler {
+	return func(ctx context.Context, plan upgradetypes.Plan, fromVM module.VersionMap) (module.VersionMap, error) {
+		versionMap, err := mm.RunMigrations(ctx, configurator, fromVM)
+		if err != nil {
+			return nil, err
+		}
+		if err := initEmission(ctx, ak); err != nil {
+			return nil, err
+		}
+		return versionMap, nil
+	}
+}
+
+// initEmission sets the params of the emission module to its defaults in the
+// base denom of the chain, which mint nothing. x/mint runs with zero
+// inflation, so it has no provisions to hand over, and a schedule is only
+// started by a governance param change on the emission subspace.
+func initEmission(ctx context.Context, ak *upgrades.AppKeepers) error {
+	params := emissiontypes.DefaultParams()
+	params.MintDenom = ak.BaseDenom
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	ak.EmissionKeeper.SetParams(sdk.UnwrapSDKContext(ctx), params)
+	return nil
+}
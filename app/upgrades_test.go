@@ -0,0 +1,59 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	upgradetypes "cosmossdk.io/x/upgrade/types"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	"github.com/cosmos/cosmos-sdk/x/mint"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+
+	v0012 "github.com/Asphere-xyz/tacchain/app/upgrades/v0.0.12"
+)
+
+// TestUpgradeV0012Emission runs the v0.0.12 upgrade on the x/mint state of
+// the mainnet genesis, after blocks minted under zero inflation, and checks
+// the emission module mints nothing until governance sets a schedule.
+func TestUpgradeV0012Emission(t *testing.T) {
+	tacApp := NewTacChainAppWithCustomOptions(t, false, 0, SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	const upgradeHeight = 5_000_000
+	ctx := tacApp.NewContextLegacy(false, cmtproto.Header{ChainID: "tacchain_239-1", Height: upgradeHeight, Time: time.Unix(1_760_000_000, 0)})
+
+	bz, err := os.ReadFile(filepath.Join("..", "networks", "tacchain_239-1", "genesis.json"))
+	require.NoError(t, err)
+	var genesis struct {
+		AppState map[string]json.RawMessage `json:"app_state"`
+	}
+	require.NoError(t, json.Unmarshal(bz, &genesis))
+	var mintGenesis minttypes.GenesisState
+	require.NoError(t, tacApp.AppCodec().UnmarshalJSON(genesis.AppState[minttypes.ModuleName], &mintGenesis))
+	require.NoError(t, tacApp.MintKeeper.Minter.Set(ctx, mintGenesis.Minter))
+	require.NoError(t, tacApp.MintKeeper.Params.Set(ctx, mintGenesis.Params))
+	require.NoError(t, mint.BeginBlocker(ctx, tacApp.MintKeeper, TacZeroInflation))
+	minter, err := tacApp.MintKeeper.Minter.Get(ctx)
+	require.NoError(t, err)
+	require.True(t, minter.AnnualProvisions.IsZero(), "x/mint computes no provisions under zero inflation")
+
+	require.NoError(t, tacApp.UpgradeKeeper.ApplyUpgrade(ctx, upgradetypes.Plan{Name: v0012.UpgradeName, Height: upgradeHeight}))
+
+	// the chain keeps its zero inflation
+	params := tacApp.EmissionKeeper.GetParams(ctx)
+	require.Equal(t, BaseDenom, params.MintDenom)
+	require.True(t, params.AnnualProvisions.IsZero())
+	require.True(t, params.BlockProvisionAt(upgradeHeight).IsZero())
+	require.True(t, params.BlockProvisionAt(upgradeHeight+4*15_768_000).IsZero())
+}
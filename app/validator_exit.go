@@ -0,0 +1,61 @@
+package app
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	selfbondkeeper "github.com/Asphere-xyz/tacchain/x/selfbond/keeper"
+	selfbondtypes "github.com/Asphere-xyz/tacchain/x/selfbond/types"
+)
+
+// ValidatorExitDecorator executes the EVM txs sent to the validator exit
+// address. Like the vote delegation address, no code lives there, the EVM
+// runs the txs as plain transfers and the decorator handles their calldata
+// once they succeeded, so only the operator of a validator exits it, with the
+// key of its operator account. An exit call that fails, or carries value,
+// fails its whole tx. The txs pay for the exit calls through ChainCallGas.
+type ValidatorExitDecorator struct {
+	keeper selfbondkeeper.Keeper
+	gas    ChainCallGas
+}
+
+// NewValidatorExitDecorator returns a post decorator handling the validator exit calls
+func NewValidatorExitDecorator(keeper selfbondkeeper.Keeper, gas ChainCallGas) ValidatorExitDecorator {
+	return ValidatorExitDecorator{keeper: keeper, gas: gas}
+}
+
+func (d ValidatorExitDecorator) PostHandle(ctx sdk.Context, tx sdk.Tx, simulate, success bool, next sdk.PostHandler) (sdk.Context, error) {
+	if !success {
+		return next(ctx, tx, simulate, success)
+	}
+
+	target := selfbondtypes.ExitAddress()
+	for _, msg := range tx.GetMsgs() {
+		ethMsg, ok := msg.(*evmvmtypes.MsgEthereumTx)
+		if !ok {
+			continue
+		}
+		ethTx := ethMsg.AsTransaction()
+		if ethTx.To() == nil || *ethTx.To() != target {
+			continue
+		}
+
+		if ethTx.Value().Sign() != 0 {
+			return ctx, errorsmod.Wrap(selfbondtypes.ErrInvalidExitCall, "exit calls can't transfer value")
+		}
+		sender := common.BytesToAddress(ethMsg.GetFrom())
+		err := d.gas.Handle(ctx, sender, ethTx, func(ctx sdk.Context) error {
+			return d.keeper.HandleExitCall(ctx, sender, ethTx.Data())
+		})
+		if err != nil {
+			return ctx, err
+		}
+	}
+
+	return next(ctx, tx, simulate, success)
+}
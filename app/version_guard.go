@@ -0,0 +1,58 @@
+package app
+
+import (
+	"fmt"
+
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/types/module"
+)
+
+// ValidateStateVersion makes sure the loaded state was not written by a newer
+// binary. x/upgrade records the name of every applied upgrade and the
+// consensus version of every module when it migrates them, so state created
+// by a newer release has an upgrade or a module version this binary doesn't
+// know. Running an older binary on such state would silently misinterpret or
+// corrupt the stores.
+func (app *TacChainApp) ValidateStateVersion() error {
+	ctx := app.NewUncachedContext(false, cmtproto.Header{})
+
+	lastUpgrade, height, err := app.UpgradeKeeper.GetLastCompletedUpgrade(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read last completed upgrade: %w", err)
+	}
+	if lastUpgrade != "" && !isKnownUpgrade(lastUpgrade) {
+		return fmt.Errorf(
+			"state was migrated by upgrade %q at height %d which is unknown to this binary (version %s), "+
+				"the node must run a binary that includes this upgrade", lastUpgrade, height, app.Version(),
+		)
+	}
+
+	versionMap, err := app.UpgradeKeeper.GetModuleVersionMap(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read module versions: %w", err)
+	}
+	for name, stateVersion := range versionMap {
+		m, ok := app.ModuleManager.Modules[name].(module.HasConsensusVersion)
+		if !ok {
+			continue
+		}
+		if binaryVersion := m.ConsensusVersion(); stateVersion > binaryVersion {
+			return fmt.Errorf(
+				"state of module %s has consensus version %d but this binary (version %s) only supports up to %d, "+
+					"the state was written by a newer binary", name, stateVersion, app.Version(), binaryVersion,
+			)
+		}
+	}
+
+	return nil
+}
+
+func isKnownUpgrade(name string) bool {
+	for _, upgrade := range Upgrades {
+		if upgrade.UpgradeName == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	upgradetypes "cosmossdk.io/x/upgrade/types"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+
+	evmdcmd "github.com/cosmos/evm/cmd/evmd/cmd"
+
+	recoverytypes "github.com/Asphere-xyz/tacchain/x/recovery/types"
+)
+
+// commitWithState runs a block on a fresh chain, lets modify write to the
+// committed state and returns the database.
+func commitWithState(t *testing.T, modify func(app *TacChainApp, ctx sdk.Context)) *dbm.MemDB {
+	t.Helper()
+
+	db := dbm.NewMemDB()
+	app := NewTacChainAppWithCustomOptions(t, false, 0, SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      db,
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+
+	_, err := app.FinalizeBlock(&abci.RequestFinalizeBlock{Height: 1})
+	require.NoError(t, err)
+
+	modify(app, app.NewUncachedContext(false, cmtproto.Header{Height: 1}))
+
+	_, err = app.Commit()
+	require.NoError(t, err)
+
+	return db
+}
+
+func restartApp(t *testing.T, db *dbm.MemDB) {
+	t.Helper()
+
+	NewTacChainApp(
+		log.NewTestLogger(t),
+		db,
+		nil,
+		true,
+		0,
+		simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+		evmdcmd.NoOpEvmAppOptions,
+	)
+}
+
+func requireRefusesToStart(t *testing.T, db *dbm.MemDB, msg string) {
+	t.Helper()
+
+	defer func() {
+		r := recover()
+		require.NotNil(t, r, "app should refuse to start")
+		err, ok := r.(error)
+		require.True(t, ok, "unexpected panic: %v", r)
+		require.ErrorContains(t, err, "refusing to start")
+		require.ErrorContains(t, err, msg)
+	}()
+
+	restartApp(t, db)
+}
+
+func TestValidateStateVersion(t *testing.T) {
+	db := commitWithState(t, func(app *TacChainApp, ctx sdk.Context) {
+		// the latest known upgrade must not prevent the binary from starting
+		latest := Upgrades[len(Upgrades)-1].UpgradeName
+		app.UpgradeKeeper.SetUpgradeHandler(latest, func(_ context.Context, _ upgradetypes.Plan, vm module.VersionMap) (module.VersionMap, error) {
+			return vm, nil
+		})
+		require.NoError(t, app.UpgradeKeeper.ApplyUpgrade(ctx, upgradetypes.Plan{Name: latest, Height: 1}))
+	})
+
+	require.NotPanics(t, func() { restartApp(t, db) })
+}
+
+func TestValidateStateVersionUnknownUpgrade(t *testing.T) {
+	db := commitWithState(t, func(app *TacChainApp, ctx sdk.Context) {
+		// simulates state migrated by a newer release
+		app.UpgradeKeeper.SetUpgradeHandler("v99.0.0", func(_ context.Context, _ upgradetypes.Plan, vm module.VersionMap) (module.VersionMap, error) {
+			return vm, nil
+		})
+		require.NoError(t, app.UpgradeKeeper.ApplyUpgrade(ctx, upgradetypes.Plan{Name: "v99.0.0", Height: 1}))
+	})
+
+	requireRefusesToStart(t, db, `upgrade "v99.0.0" at height 1 which is unknown to this binary`)
+}
+
+func TestValidateStateVersionNewerModule(t *testing.T) {
+	db := commitWithState(t, func(app *TacChainApp, ctx sdk.Context) {
+		require.NoError(t, app.UpgradeKeeper.SetModuleVersionMap(ctx, module.VersionMap{recoverytypes.ModuleName: 99}))
+	})
+
+	requireRefusesToStart(t, db, "state of module recovery has consensus version 99")
+}
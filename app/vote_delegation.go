@@ -0,0 +1,62 @@
+package app
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	tacgovkeeper "github.com/Asphere-xyz/tacchain/x/tacgov/keeper"
+	tacgovtypes "github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+// VoteDelegationDecorator executes the EVM txs sent to the vote delegation
+// address. Like the contract metadata registry, no code lives at the address,
+// the EVM runs the txs as plain transfers and the decorator handles their
+// calldata once they succeeded, so any EVM account picks its governance
+// delegate with its own wallet. A vote delegation call that fails, or carries
+// value, fails its whole tx. The txs pay for the vote delegation calls through
+// ChainCallGas.
+type VoteDelegationDecorator struct {
+	keeper tacgovkeeper.Keeper
+	gas    ChainCallGas
+}
+
+// NewVoteDelegationDecorator returns a post decorator handling the vote delegation calls
+func NewVoteDelegationDecorator(keeper tacgovkeeper.Keeper, gas ChainCallGas) VoteDelegationDecorator {
+	return VoteDelegationDecorator{keeper: keeper, gas: gas}
+}
+
+func (d VoteDelegationDecorator) PostHandle(ctx sdk.Context, tx sdk.Tx, simulate, success bool, next sdk.PostHandler) (sdk.Context, error) {
+	if !success {
+		return next(ctx, tx, simulate, success)
+	}
+
+	target := tacgovtypes.VoteDelegationAddress()
+	for _, msg := range tx.GetMsgs() {
+		ethMsg, ok := msg.(*evmvmtypes.MsgEthereumTx)
+		if !ok {
+			continue
+		}
+		ethTx := ethMsg.AsTransaction()
+		if ethTx.To() == nil || *ethTx.To() != target {
+			continue
+		}
+
+		if ethTx.Value().Sign() != 0 {
+			return ctx, errorsmod.Wrap(tacgovtypes.ErrInvalidVoteDelegationCall, "vote delegation calls can't transfer value")
+		}
+		sender := common.BytesToAddress(ethMsg.GetFrom())
+		err := d.gas.Handle(ctx, sender, ethTx, func(ctx sdk.Context) error {
+			return d.keeper.HandleVoteDelegationCall(ctx, sender, ethTx.Data())
+		})
+		if err != nil {
+			return ctx, err
+		}
+	}
+
+	return next(ctx, tx, simulate, success)
+}
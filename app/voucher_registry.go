@@ -0,0 +1,62 @@
+package app
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	ibchookskeeper "github.com/Asphere-xyz/tacchain/x/ibchooks/keeper"
+	ibchookstypes "github.com/Asphere-xyz/tacchain/x/ibchooks/types"
+)
+
+// VoucherRegistryDecorator executes the EVM txs sent to the voucher registry
+// address. Like the contract metadata registry, no code lives at the address,
+// the EVM runs the txs as plain transfers and the decorator handles their
+// calldata once they succeeded, so any EVM account registers the ERC-20
+// extension of an IBC voucher whose metadata is set, without governance. A
+// registration call that fails, or carries value, fails its whole tx. The
+// txs pay for the registration calls through ChainCallGas.
+type VoucherRegistryDecorator struct {
+	keeper ibchookskeeper.Keeper
+	gas    ChainCallGas
+}
+
+// NewVoucherRegistryDecorator returns a post decorator handling the voucher registry calls
+func NewVoucherRegistryDecorator(keeper ibchookskeeper.Keeper, gas ChainCallGas) VoucherRegistryDecorator {
+	return VoucherRegistryDecorator{keeper: keeper, gas: gas}
+}
+
+func (d VoucherRegistryDecorator) PostHandle(ctx sdk.Context, tx sdk.Tx, simulate, success bool, next sdk.PostHandler) (sdk.Context, error) {
+	if !success {
+		return next(ctx, tx, simulate, success)
+	}
+
+	target := ibchookstypes.VoucherRegistryAddress()
+	for _, msg := range tx.GetMsgs() {
+		ethMsg, ok := msg.(*evmvmtypes.MsgEthereumTx)
+		if !ok {
+			continue
+		}
+		ethTx := ethMsg.AsTransaction()
+		if ethTx.To() == nil || *ethTx.To() != target {
+			continue
+		}
+
+		if ethTx.Value().Sign() != 0 {
+			return ctx, errorsmod.Wrap(ibchookstypes.ErrInvalidVoucherRegistryCall, "voucher registry calls can't transfer value")
+		}
+		sender := common.BytesToAddress(ethMsg.GetFrom())
+		err := d.gas.Handle(ctx, sender, ethTx, func(ctx sdk.Context) error {
+			return d.keeper.HandleVoucherRegistryCall(ctx, sender, ethTx.Data())
+		})
+		if err != nil {
+			return ctx, err
+		}
+	}
+
+	return next(ctx, tx, simulate, success)
+}
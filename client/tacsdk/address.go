@@ -0,0 +1,48 @@
+package tacsdk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+// Bech32FromEthAddress returns the bech32 form of an EVM address, both forms
+// are the same 20 bytes account
+func Bech32FromEthAddress(addr common.Address) string {
+	return sdk.MustBech32ifyAddressBytes(Bech32PrefixAccAddr, addr.Bytes())
+}
+
+// EthAddressFromBech32 returns the EVM form of a bech32 account address
+func EthAddressFromBech32(addr string) (common.Address, error) {
+	prefix, bz, err := bech32.DecodeAndConvert(addr)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid bech32 address %s: %w", addr, err)
+	}
+	if prefix != Bech32PrefixAccAddr {
+		return common.Address{}, fmt.Errorf("invalid bech32 address %s: prefix %s is not %s", addr, prefix, Bech32PrefixAccAddr)
+	}
+	if len(bz) != common.AddressLength {
+		return common.Address{}, fmt.Errorf("invalid bech32 address %s: %d bytes, not %d", addr, len(bz), common.AddressLength)
+	}
+	return common.BytesToAddress(bz), nil
+}
+
+// ParseAccAddress parses an account address in either form, a 0x prefixed hex
+// EVM address or a bech32 address
+func ParseAccAddress(addr string) (sdk.AccAddress, error) {
+	if strings.HasPrefix(addr, "0x") || strings.HasPrefix(addr, "0X") {
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("invalid hex address %s", addr)
+		}
+		return common.HexToAddress(addr).Bytes(), nil
+	}
+	ethAddr, err := EthAddressFromBech32(addr)
+	if err != nil {
+		return nil, err
+	}
+	return ethAddr.Bytes(), nil
+}
@@ -0,0 +1,159 @@
+package tacsdk
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	gogogrpc "github.com/cosmos/gogoproto/grpc"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// RetryConfig configures the retries of the txs the node rejects for a
+// sequence mismatch or a full mempool
+type RetryConfig struct {
+	// MaxRetries is the number of broadcasts after the first one
+	MaxRetries int
+	// Backoff is the wait before the first retry, doubled before each of the
+	// next ones up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryConfig returns the default retries, about 15s of them
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries: 5,
+		Backoff:    500 * time.Millisecond,
+		MaxBackoff: 8 * time.Second,
+	}
+}
+
+// BroadcastFunc broadcasts the bytes of a signed tx and returns its CheckTx
+// result
+type BroadcastFunc func(ctx context.Context, txBytes []byte) (*sdk.TxResponse, error)
+
+// SequenceFunc returns the sequence of the signer on chain
+type SequenceFunc func(ctx context.Context) (uint64, error)
+
+// SignFunc signs the tx with sequence and returns the bytes to broadcast
+type SignFunc func(ctx context.Context, sequence uint64) ([]byte, error)
+
+// NodeBroadcaster returns the BroadcastFunc broadcasting to a CometBFT node
+// in sync mode, e.g. through its RPC client
+func NodeBroadcaster(node interface {
+	BroadcastTxSync(ctx context.Context, tx cmttypes.Tx) (*coretypes.ResultBroadcastTx, error)
+},
+) BroadcastFunc {
+	return func(ctx context.Context, txBytes []byte) (*sdk.TxResponse, error) {
+		res, err := node.BroadcastTxSync(ctx, txBytes)
+		if err != nil {
+			return nil, err
+		}
+		return sdk.NewResponseFormatBroadcastTx(res), nil
+	}
+}
+
+// AccountSequence returns the SequenceFunc querying the x/auth account of
+// address through conn, a gRPC connection to a node
+func (c EncodingConfig) AccountSequence(conn gogogrpc.ClientConn, address string) SequenceFunc {
+	return func(ctx context.Context) (uint64, error) {
+		res, err := authtypes.NewQueryClient(conn).Account(ctx, &authtypes.QueryAccountRequest{Address: address})
+		if err != nil {
+			return 0, err
+		}
+		var account sdk.AccountI
+		if err := c.InterfaceRegistry.UnpackAny(res.Account, &account); err != nil {
+			return 0, err
+		}
+		return account.GetSequence(), nil
+	}
+}
+
+// BroadcastTx signs the tx of req and broadcasts it with RetryBroadcast, the
+// sequence of signer is the one of the first broadcast
+func (c EncodingConfig) BroadcastTx(ctx context.Context, chainID string, req TxRequest, signer Signer, broadcast BroadcastFunc, sequence SequenceFunc, cfg RetryConfig) (*sdk.TxResponse, error) {
+	sign := func(ctx context.Context, seq uint64) ([]byte, error) {
+		s := signer
+		s.Sequence = seq
+		return c.SignTx(ctx, chainID, req, s)
+	}
+	txBytes, err := sign(ctx, signer.Sequence)
+	if err != nil {
+		return nil, err
+	}
+	return RetryBroadcast(ctx, cfg, txBytes, broadcast, sequence, sign)
+}
+
+// RetryBroadcast broadcasts txBytes and broadcasts the tx again, after the
+// backoff, as long as the node rejects it for a sequence mismatch or a full
+// mempool. A tx with a sequence mismatch is signed again by sign with the
+// sequence the node expects, or the one sequence returns if it is higher, a
+// tx rejected by a full mempool is sent as is. It returns the CheckTx result
+// of the last broadcast, it is up to the caller to check its code.
+func RetryBroadcast(ctx context.Context, cfg RetryConfig, txBytes []byte, broadcast BroadcastFunc, sequence SequenceFunc, sign SignFunc) (*sdk.TxResponse, error) {
+	backoff := cfg.Backoff
+	for retry := 0; ; retry++ {
+		res, err := broadcast(ctx, txBytes)
+		if retry >= cfg.MaxRetries || !IsRetryable(res, err) {
+			return res, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return res, errors.Join(err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, cfg.MaxBackoff)
+
+		if err != nil || res.Code != sdkerrors.ErrWrongSequence.ABCICode() {
+			continue
+		}
+		seq, err := sequence(ctx)
+		if err != nil {
+			return res, err
+		}
+		if expected, ok := ExpectedSequence(res.RawLog); ok && expected > seq {
+			seq = expected
+		}
+		if txBytes, err = sign(ctx, seq); err != nil {
+			return res, err
+		}
+	}
+}
+
+// IsRetryable returns whether the broadcast of a tx with result res or
+// error err failed for a sequence mismatch or a full mempool, so the tx may
+// be accepted later
+func IsRetryable(res *sdk.TxResponse, err error) bool {
+	if err != nil {
+		// CometBFT refuses the txs of a full mempool before CheckTx
+		return strings.Contains(err.Error(), "mempool is full")
+	}
+	if res == nil || res.Codespace != sdkerrors.RootCodespace {
+		return false
+	}
+	return res.Code == sdkerrors.ErrWrongSequence.ABCICode() || res.Code == sdkerrors.ErrMempoolIsFull.ABCICode()
+}
+
+var expectedSequenceRegexp = regexp.MustCompile(`account sequence mismatch, expected (\d+), got \d+`)
+
+// ExpectedSequence returns the sequence the node expects from the log of a tx
+// rejected for a sequence mismatch. It accounts for the txs of the signer in
+// the mempool of the node, unlike the sequence of its account.
+func ExpectedSequence(log string) (uint64, bool) {
+	match := expectedSequenceRegexp.FindStringSubmatch(log)
+	if match == nil {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(match[1], 10, 64)
+	return seq, err == nil
+}
@@ -0,0 +1,185 @@
+package tacsdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	"github.com/cosmos/evm/crypto/ethsecp256k1"
+
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+)
+
+// flakyNode is a CometBFT node answering the broadcasts with its results in
+// turn, the last one once the others were used
+type flakyNode struct {
+	results []flakyResult
+	txs     []cmttypes.Tx
+}
+
+type flakyResult struct {
+	code uint32
+	log  string
+	err  error
+}
+
+func (n *flakyNode) BroadcastTxSync(_ context.Context, tx cmttypes.Tx) (*coretypes.ResultBroadcastTx, error) {
+	n.txs = append(n.txs, tx)
+	res := n.results[min(len(n.txs), len(n.results))-1]
+	if res.err != nil {
+		return nil, res.err
+	}
+	codespace := ""
+	if res.code != 0 {
+		codespace = sdkerrors.RootCodespace
+	}
+	return &coretypes.ResultBroadcastTx{Code: res.code, Codespace: codespace, Log: res.log, Hash: tx.Hash()}, nil
+}
+
+func sequenceMismatch(expected, got uint64) flakyResult {
+	return flakyResult{
+		code: sdkerrors.ErrWrongSequence.ABCICode(),
+		log:  sdkerrors.ErrWrongSequence.Wrapf("account sequence mismatch, expected %d, got %d", expected, got).Error(),
+	}
+}
+
+// broadcastTest broadcasts a bank send of a signer at sequence 5 to a flaky
+// node, the account sequence on chain is the one of sequences in turn
+type broadcastTest struct {
+	cfg       tacsdk.EncodingConfig
+	node      *flakyNode
+	sequences []uint64
+	queries   int
+}
+
+func (b *broadcastTest) broadcast(t *testing.T, retry tacsdk.RetryConfig) (*sdk.TxResponse, error) {
+	t.Helper()
+	key, err := ethsecp256k1.GenerateKey()
+	require.NoError(t, err)
+	from := sdk.AccAddress(key.PubKey().Address())
+	req := tacsdk.TxRequest{
+		Msgs:     []sdk.Msg{banktypes.NewMsgSend(from, from, sdk.NewCoins(sdk.NewInt64Coin(tacsdk.BaseDenom, 1)))},
+		GasLimit: 200000,
+	}
+	sequence := func(context.Context) (uint64, error) {
+		b.queries++
+		if b.queries > len(b.sequences) {
+			return 0, errors.New("no account")
+		}
+		return b.sequences[b.queries-1], nil
+	}
+	signer := tacsdk.Signer{Key: key, AccountNumber: 1, Sequence: 5}
+	return b.cfg.BroadcastTx(context.Background(), tacsdk.TestnetChainID, req, signer, tacsdk.NodeBroadcaster(b.node), sequence, retry)
+}
+
+// txSequences returns the sequences of the txs the node received
+func (b *broadcastTest) txSequences(t *testing.T) []uint64 {
+	t.Helper()
+	var sequences []uint64
+	for _, bz := range b.node.txs {
+		tx, err := b.cfg.DecodeTx(bz)
+		require.NoError(t, err)
+		sigs, err := tx.(authsigning.SigVerifiableTx).GetSignaturesV2()
+		require.NoError(t, err)
+		sequences = append(sequences, sigs[0].Sequence)
+	}
+	return sequences
+}
+
+func testRetryConfig() tacsdk.RetryConfig {
+	return tacsdk.RetryConfig{MaxRetries: 3, Backoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+}
+
+func TestBroadcastRetriesSequenceMismatch(t *testing.T) {
+	b := &broadcastTest{
+		cfg: tacsdk.MakeEncodingConfig(),
+		node: &flakyNode{results: []flakyResult{
+			// the node expects more than the account sequence, with txs in its mempool
+			sequenceMismatch(7, 5),
+			// the account sequence is ahead of the node
+			sequenceMismatch(8, 7),
+			{},
+		}},
+		sequences: []uint64{6, 9},
+	}
+	res, err := b.broadcast(t, testRetryConfig())
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), res.Code)
+	require.Equal(t, []uint64{5, 7, 9}, b.txSequences(t))
+	require.Equal(t, 2, b.queries)
+}
+
+func TestBroadcastRetriesFullMempool(t *testing.T) {
+	b := &broadcastTest{
+		cfg: tacsdk.MakeEncodingConfig(),
+		node: &flakyNode{results: []flakyResult{
+			{err: errors.New("mempool is full: number of txs 5000 (max: 5000)")},
+			{code: sdkerrors.ErrMempoolIsFull.ABCICode(), log: sdkerrors.ErrMempoolIsFull.Error()},
+			{},
+		}},
+	}
+	res, err := b.broadcast(t, testRetryConfig())
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), res.Code)
+	// the tx is sent as is, its sequence is right
+	require.Equal(t, []uint64{5, 5, 5}, b.txSequences(t))
+	require.Zero(t, b.queries)
+}
+
+func TestBroadcastDoesNotRetryOtherFailures(t *testing.T) {
+	b := &broadcastTest{
+		cfg: tacsdk.MakeEncodingConfig(),
+		node: &flakyNode{results: []flakyResult{
+			{code: sdkerrors.ErrInsufficientFunds.ABCICode(), log: "insufficient funds"},
+		}},
+	}
+	res, err := b.broadcast(t, testRetryConfig())
+	require.NoError(t, err)
+	require.Equal(t, sdkerrors.ErrInsufficientFunds.ABCICode(), res.Code)
+	require.Len(t, b.node.txs, 1)
+
+	b.node = &flakyNode{results: []flakyResult{{err: errors.New("connection refused")}}}
+	_, err = b.broadcast(t, testRetryConfig())
+	require.ErrorContains(t, err, "connection refused")
+	require.Len(t, b.node.txs, 1)
+}
+
+func TestBroadcastRetriesGiveUp(t *testing.T) {
+	b := &broadcastTest{
+		cfg:       tacsdk.MakeEncodingConfig(),
+		node:      &flakyNode{results: []flakyResult{sequenceMismatch(9, 5)}},
+		sequences: []uint64{5, 5, 5},
+	}
+	res, err := b.broadcast(t, testRetryConfig())
+	require.NoError(t, err)
+	require.Equal(t, sdkerrors.ErrWrongSequence.ABCICode(), res.Code)
+	require.Equal(t, []uint64{5, 9, 9, 9}, b.txSequences(t))
+
+	// the sequence can't be refreshed
+	b = &broadcastTest{
+		cfg:  tacsdk.MakeEncodingConfig(),
+		node: &flakyNode{results: []flakyResult{sequenceMismatch(9, 5)}},
+	}
+	_, err = b.broadcast(t, testRetryConfig())
+	require.ErrorContains(t, err, "no account")
+	require.Len(t, b.node.txs, 1)
+}
+
+func TestExpectedSequence(t *testing.T) {
+	seq, ok := tacsdk.ExpectedSequence("account sequence mismatch, expected 12, got 10: incorrect account sequence")
+	require.True(t, ok)
+	require.Equal(t, uint64(12), seq)
+
+	_, ok = tacsdk.ExpectedSequence("insufficient funds")
+	require.False(t, ok)
+}
@@ -0,0 +1,56 @@
+package tacsdk
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// BaseDenom is the denom of the native token, with 18 decimals
+	BaseDenom = "utac"
+	// DisplayDenom is the denom the native token is displayed in
+	DisplayDenom = "tac"
+	// BaseDenomUnit is the number of decimals of BaseDenom in DisplayDenom
+	BaseDenomUnit = 18
+
+	// Bech32PrefixAccAddr is the prefix of the bech32 form of account addresses
+	Bech32PrefixAccAddr = "tac"
+	// Bech32PrefixAccPub is the prefix of account public keys
+	Bech32PrefixAccPub = Bech32PrefixAccAddr + "pub"
+	// Bech32PrefixValAddr is the prefix of validator operator addresses
+	Bech32PrefixValAddr = Bech32PrefixAccAddr + "valoper"
+	// Bech32PrefixValPub is the prefix of validator operator public keys
+	Bech32PrefixValPub = Bech32PrefixAccAddr + "valoperpub"
+	// Bech32PrefixConsAddr is the prefix of consensus node addresses
+	Bech32PrefixConsAddr = Bech32PrefixAccAddr + "valcons"
+	// Bech32PrefixConsPub is the prefix of consensus node public keys
+	Bech32PrefixConsPub = Bech32PrefixAccAddr + "valconspub"
+
+	// CoinType is the BIP-44 coin type keys are derived with, the one of
+	// Ethereum, see the keys section of the README
+	CoinType uint32 = 60
+
+	// MainnetChainID is the chain id of the TAC mainnet
+	MainnetChainID = "tacchain_239-1"
+	// TestnetChainID is the chain id of the Saint Petersburg testnet
+	TestnetChainID = "tacchain_2391-1"
+)
+
+// SetSDKConfig sets the bech32 prefixes and the coin type of TAC chain on the
+// global config of the Cosmos SDK, which sdk.AccAddress.String and the
+// address codecs read. It does nothing if the config already has them and
+// panics if another chain sealed the config.
+func SetSDKConfig() {
+	config := sdk.GetConfig()
+	if config.GetBech32AccountAddrPrefix() == Bech32PrefixAccAddr &&
+		config.GetBech32ValidatorAddrPrefix() == Bech32PrefixValAddr &&
+		config.GetBech32ConsensusAddrPrefix() == Bech32PrefixConsAddr &&
+		config.GetCoinType() == CoinType {
+		return
+	}
+
+	config.SetBech32PrefixForAccount(Bech32PrefixAccAddr, Bech32PrefixAccPub)
+	config.SetBech32PrefixForValidator(Bech32PrefixValAddr, Bech32PrefixValPub)
+	config.SetBech32PrefixForConsensusNode(Bech32PrefixConsAddr, Bech32PrefixConsPub)
+	config.SetCoinType(CoinType)
+	config.SetPurpose(sdk.Purpose)
+}
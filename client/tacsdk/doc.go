@@ -0,0 +1,18 @@
+// Package tacsdk is the Go client library of TAC chain for services that
+// read or send txs, such as indexers, wallets backends and relayers.
+//
+// It decodes the Cosmos txs of the chain blocks, including the EVM txs they
+// wrap, converts between the EVM and bech32 forms of addresses, builds and
+// signs Cosmos txs, broadcasts them again when the node rejects them for a
+// sequence mismatch or a full mempool, and builds the messages and EVM calls
+// of the chain specific operations: bridge withdrawals and relayer bonds,
+// contract metadata, auto-compounding grants and validator exits. It also
+// builds gov proposals, such as the update of the consensus params,
+// estimates the fees of Cosmos and EVM txs and verifies the proofs of the
+// inclusion of txs and their results in the blocks.
+//
+// The package only depends on the types of the modules, not on the app or
+// the keepers, so importing it doesn't pull in the node. Call SetSDKConfig,
+// or MakeEncodingConfig which calls it, before using the bech32 form of
+// addresses.
+package tacsdk
@@ -0,0 +1,84 @@
+package tacsdk
+
+import (
+	ibcfeetypes "github.com/cosmos/ibc-go/v8/modules/apps/29-fee/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v8/modules/apps/transfer/types"
+	ibcclienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcconnectiontypes "github.com/cosmos/ibc-go/v8/modules/core/03-connection/types"
+	ibcchanneltypes "github.com/cosmos/ibc-go/v8/modules/core/04-channel/types"
+	ibctm "github.com/cosmos/ibc-go/v8/modules/light-clients/07-tendermint"
+
+	"cosmossdk.io/x/feegrant"
+	upgradetypes "cosmossdk.io/x/upgrade/types"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	consensustypes "github.com/cosmos/cosmos-sdk/x/consensus/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govv1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	paramsproposal "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	evmencoding "github.com/cosmos/evm/encoding"
+	evmerc20types "github.com/cosmos/evm/x/erc20/types"
+	evmfeemarkettypes "github.com/cosmos/evm/x/feemarket/types"
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+// EncodingConfig holds the codecs of the txs and messages of the chain
+type EncodingConfig struct {
+	InterfaceRegistry codectypes.InterfaceRegistry
+	Codec             codec.Codec
+	TxConfig          client.TxConfig
+	Amino             *codec.LegacyAmino
+}
+
+// MakeEncodingConfig returns the encoding config of the messages users send
+// to the chain: the ones of the Cosmos SDK modules, IBC and the EVM, with the
+// eth_secp256k1 keys. The chain specific modules have no messages, their
+// operations are sent as the messages and EVM calls built in this package.
+func MakeEncodingConfig() EncodingConfig {
+	SetSDKConfig()
+
+	config := evmencoding.MakeConfig()
+	for _, registerInterfaces := range []func(codectypes.InterfaceRegistry){
+		authtypes.RegisterInterfaces,
+		vestingtypes.RegisterInterfaces,
+		banktypes.RegisterInterfaces,
+		stakingtypes.RegisterInterfaces,
+		distrtypes.RegisterInterfaces,
+		slashingtypes.RegisterInterfaces,
+		govv1.RegisterInterfaces,
+		govv1beta1.RegisterInterfaces,
+		paramsproposal.RegisterInterfaces,
+		upgradetypes.RegisterInterfaces,
+		consensustypes.RegisterInterfaces,
+		authz.RegisterInterfaces,
+		feegrant.RegisterInterfaces,
+		ibctransfertypes.RegisterInterfaces,
+		ibcfeetypes.RegisterInterfaces,
+		ibcclienttypes.RegisterInterfaces,
+		ibcconnectiontypes.RegisterInterfaces,
+		ibcchanneltypes.RegisterInterfaces,
+		ibctm.RegisterInterfaces,
+		evmvmtypes.RegisterInterfaces,
+		evmfeemarkettypes.RegisterInterfaces,
+		evmerc20types.RegisterInterfaces,
+	} {
+		registerInterfaces(config.InterfaceRegistry)
+	}
+
+	return EncodingConfig{
+		InterfaceRegistry: config.InterfaceRegistry,
+		Codec:             config.Codec,
+		TxConfig:          config.TxConfig,
+		Amino:             config.Amino,
+	}
+}
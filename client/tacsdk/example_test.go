@@ -0,0 +1,82 @@
+package tacsdk_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+)
+
+func ExampleBech32FromEthAddress() {
+	addr := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+	fmt.Println(tacsdk.Bech32FromEthAddress(addr))
+
+	back, _ := tacsdk.EthAddressFromBech32("tac1npvwllfr9dqr8erajqqr6s0vxnk2ak55v6q28s")
+	fmt.Println(back.Hex())
+	// Output:
+	// tac1npvwllfr9dqr8erajqqr6s0vxnk2ak55v6q28s
+	// 0x9858EfFD232B4033E47d90003D41EC34EcaEda94
+}
+
+// An indexer decodes the txs of a block, as returned base64 encoded by the
+// block endpoint of CometBFT, and the chain specific calls of its EVM txs.
+func ExampleEncodingConfig_DecodeTx() {
+	cfg := tacsdk.MakeEncodingConfig()
+
+	var blockTxs []string // the txs of /block?height=...
+	for _, encoded := range blockTxs {
+		bz, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			panic(err)
+		}
+		tx, err := cfg.DecodeTx(bz)
+		if err != nil {
+			panic(err)
+		}
+		for _, msg := range tx.GetMsgs() {
+			fmt.Println(sdk.MsgTypeURL(msg))
+		}
+		for _, ethTx := range tacsdk.EthTxs(tx) {
+			if ethTx.To() == nil {
+				continue
+			}
+			call, err := tacsdk.DecodeEVMCall(*ethTx.To(), ethTx.Data())
+			if err == nil && call != nil {
+				fmt.Printf("%s: %+v\n", ethTx.Hash(), call)
+			}
+		}
+	}
+}
+
+// A delegator signs the tx granting autocompounding of its rewards, to
+// broadcast with the /cosmos/tx/v1beta1/txs endpoint or BroadcastTxSync of
+// CometBFT.
+func ExampleEncodingConfig_SignTx() {
+	cfg := tacsdk.MakeEncodingConfig()
+
+	var signer tacsdk.Signer // the key, account number and sequence of the delegator
+	if signer.Key == nil {
+		return
+	}
+	delegator := sdk.AccAddress(signer.Key.PubKey().Address())
+	validator := sdk.ValAddress(delegator) // the validator of the delegator, to compound the rewards of
+	grant, err := tacsdk.NewAutocompoundGrantMsg(delegator, []sdk.ValAddress{validator}, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	txBytes, err := cfg.SignTx(context.Background(), tacsdk.MainnetChainID, tacsdk.TxRequest{
+		Msgs:     []sdk.Msg{grant},
+		GasLimit: 200000,
+		Fee:      sdk.NewCoins(sdk.NewInt64Coin(tacsdk.BaseDenom, 20000000000000000)),
+	}, signer)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(base64.StdEncoding.EncodeToString(txBytes))
+}
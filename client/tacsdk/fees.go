@@ -0,0 +1,210 @@
+package tacsdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	gogogrpc "github.com/cosmos/gogoproto/grpc"
+
+	"cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+	evmfeemarkettypes "github.com/cosmos/evm/x/feemarket/types"
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+const (
+	// DefaultPriorityBuffer is the percentage added by default to the gas
+	// price of the fee market, so the fee still covers the base fee after it
+	// rose for a few blocks
+	DefaultPriorityBuffer = 10
+	// DefaultGasAdjustment multiplies by default the gas used by the
+	// simulation of a Cosmos tx to get its gas limit
+	DefaultGasAdjustment = 1.2
+	// DefaultEVMGasCap is the most gas the estimate of an EVM tx goes up to,
+	// the default gas cap of the JSON-RPC server
+	DefaultEVMGasCap = 25_000_000
+
+	// TxTypeCosmos is the type of the fee estimate of a Cosmos tx
+	TxTypeCosmos = "cosmos"
+	// TxTypeEVM is the type of the fee estimate of an EVM tx
+	TxTypeEVM = "evm"
+)
+
+// FeeEstimate is the fee suggested for a tx. GasPrice is the gas price of the
+// fee market, the base fee or the min gas price when it is higher, plus
+// PriorityBuffer percent of it. Cosmos txs set Fee and GasLimit, EVM txs use
+// GasPrice as their gas price, or as the fee cap of a dynamic fee tx, and
+// GasLimit as their gas.
+type FeeEstimate struct {
+	TxType         string         `json:"tx_type"`
+	GasUsed        uint64         `json:"gas_used"`
+	GasLimit       uint64         `json:"gas_limit"`
+	BaseFee        math.LegacyDec `json:"base_fee"`
+	MinGasPrice    math.LegacyDec `json:"min_gas_price"`
+	PriorityBuffer uint64         `json:"priority_buffer"`
+	GasPrice       math.LegacyDec `json:"gas_price"`
+	Fee            sdk.Coin       `json:"fee"`
+	FeeDisplay     sdk.DecCoin    `json:"fee_display"`
+}
+
+// NewFeeEstimate returns the fee of a tx of gasLimit in denom with the fee
+// market params and priorityBuffer percent added to the gas price. The fee is
+// rounded up, so it never pays less than the gas price.
+func NewFeeEstimate(txType string, gasUsed, gasLimit uint64, denom string, params evmfeemarkettypes.Params, priorityBuffer uint64) FeeEstimate {
+	baseFee := math.LegacyZeroDec()
+	gasPrice := params.MinGasPrice
+	if !params.NoBaseFee {
+		baseFee = params.BaseFee
+		gasPrice = math.LegacyMaxDec(baseFee, gasPrice)
+	}
+	gasPrice = gasPrice.Mul(math.LegacyNewDec(int64(100 + priorityBuffer))).QuoInt64(100)
+	fee := sdk.NewCoin(denom, gasPrice.MulInt(math.NewIntFromUint64(gasLimit)).Ceil().TruncateInt())
+
+	return FeeEstimate{
+		TxType:         txType,
+		GasUsed:        gasUsed,
+		GasLimit:       gasLimit,
+		BaseFee:        baseFee,
+		MinGasPrice:    params.MinGasPrice,
+		PriorityBuffer: priorityBuffer,
+		GasPrice:       gasPrice,
+		Fee:            fee,
+		FeeDisplay:     DisplayCoin(fee),
+	}
+}
+
+// DisplayCoin returns coin in the display denom when it is in the base
+// denom, as is otherwise
+func DisplayCoin(coin sdk.Coin) sdk.DecCoin {
+	if coin.Denom != BaseDenom {
+		return sdk.NewDecCoinFromCoin(coin)
+	}
+	return sdk.DecCoin{Denom: DisplayDenom, Amount: math.LegacyNewDecFromIntWithPrec(coin.Amount, BaseDenomUnit)}
+}
+
+// EstimateCosmosFee simulates tx through conn, a gRPC connection to a node,
+// and returns its fee with a gas limit of gasAdjustment times the gas used.
+// The signer infos missing from tx, e.g. written with --generate-only, are
+// filled with the public key and sequence of the signers: a signer which
+// never sent a tx is simulated with a placeholder key, which verifies for
+// less gas than an eth_secp256k1 key.
+func (c EncodingConfig) EstimateCosmosFee(ctx context.Context, conn gogogrpc.ClientConn, tx sdk.Tx, gasAdjustment float64, priorityBuffer uint64) (FeeEstimate, error) {
+	if len(EthTxs(tx)) > 0 {
+		return FeeEstimate{}, errors.New("tx wraps an EVM tx, estimate the fee of its call instead")
+	}
+	if gasAdjustment < 1 {
+		return FeeEstimate{}, fmt.Errorf("gas adjustment must be at least 1: %v", gasAdjustment)
+	}
+
+	txBytes, err := c.simulationTx(ctx, conn, tx)
+	if err != nil {
+		return FeeEstimate{}, err
+	}
+	res, err := txtypes.NewServiceClient(conn).Simulate(ctx, &txtypes.SimulateRequest{TxBytes: txBytes})
+	if err != nil {
+		return FeeEstimate{}, fmt.Errorf("failed to simulate tx: %w", err)
+	}
+
+	denom, params, err := queryFeeParams(ctx, conn)
+	if err != nil {
+		return FeeEstimate{}, err
+	}
+	gasUsed := res.GasInfo.GasUsed
+	gasLimit := uint64(gasAdjustment * float64(gasUsed))
+	return NewFeeEstimate(TxTypeCosmos, gasUsed, gasLimit, denom, params, priorityBuffer), nil
+}
+
+// simulationTx returns the bytes of tx to simulate, with the signer infos of
+// its signers if it has no signatures
+func (c EncodingConfig) simulationTx(ctx context.Context, conn gogogrpc.ClientConn, tx sdk.Tx) ([]byte, error) {
+	sigTx, ok := tx.(authsigning.Tx)
+	if !ok {
+		return nil, errors.New("tx can't be signed")
+	}
+	sigs, err := sigTx.GetSignaturesV2()
+	if err != nil {
+		return nil, err
+	}
+	if len(sigs) > 0 {
+		return c.TxConfig.TxEncoder()(tx)
+	}
+
+	signers, err := sigTx.GetSigners()
+	if err != nil {
+		return nil, err
+	}
+	sigs = make([]signing.SignatureV2, len(signers))
+	for i, signer := range signers {
+		address := sdk.AccAddress(signer).String()
+		res, err := authtypes.NewQueryClient(conn).Account(ctx, &authtypes.QueryAccountRequest{Address: address})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query signer %s: %w", address, err)
+		}
+		var account sdk.AccountI
+		if err := c.InterfaceRegistry.UnpackAny(res.Account, &account); err != nil {
+			return nil, err
+		}
+		sigs[i] = signing.SignatureV2{
+			PubKey:   account.GetPubKey(),
+			Data:     &signing.SingleSignatureData{SignMode: signing.SignMode_SIGN_MODE_DIRECT},
+			Sequence: account.GetSequence(),
+		}
+	}
+
+	builder, err := c.TxConfig.WrapTxBuilder(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := builder.SetSignatures(sigs...); err != nil {
+		return nil, err
+	}
+	return c.TxConfig.TxEncoder()(builder.GetTx())
+}
+
+// EstimateEVMFee estimates the gas of the EVM call args through conn, a gRPC
+// connection to a node, like eth_estimateGas, and returns its fee with the
+// estimate as gas limit
+func EstimateEVMFee(ctx context.Context, conn gogogrpc.ClientConn, args evmvmtypes.TransactionArgs, priorityBuffer uint64) (FeeEstimate, error) {
+	if args.From == nil {
+		return FeeEstimate{}, errors.New("the call has no sender")
+	}
+	bz, err := json.Marshal(args)
+	if err != nil {
+		return FeeEstimate{}, err
+	}
+	res, err := evmvmtypes.NewQueryClient(conn).EstimateGas(ctx, &evmvmtypes.EthCallRequest{Args: bz, GasCap: DefaultEVMGasCap})
+	if err != nil {
+		return FeeEstimate{}, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	if res.VmError != "" {
+		return FeeEstimate{}, fmt.Errorf("the call fails: %s", res.VmError)
+	}
+
+	denom, params, err := queryFeeParams(ctx, conn)
+	if err != nil {
+		return FeeEstimate{}, err
+	}
+	return NewFeeEstimate(TxTypeEVM, res.Gas, res.Gas, denom, params, priorityBuffer), nil
+}
+
+// queryFeeParams returns the EVM denom, which txs pay their fees in, and the
+// fee market params
+func queryFeeParams(ctx context.Context, conn gogogrpc.ClientConn) (string, evmfeemarkettypes.Params, error) {
+	evmParams, err := evmvmtypes.NewQueryClient(conn).Params(ctx, &evmvmtypes.QueryParamsRequest{})
+	if err != nil {
+		return "", evmfeemarkettypes.Params{}, fmt.Errorf("failed to query the evm params: %w", err)
+	}
+	feeMarketParams, err := evmfeemarkettypes.NewQueryClient(conn).Params(ctx, &evmfeemarkettypes.QueryParamsRequest{})
+	if err != nil {
+		return "", evmfeemarkettypes.Params{}, fmt.Errorf("failed to query the fee market params: %w", err)
+	}
+	return evmParams.Params.EvmDenom, feeMarketParams.Params, nil
+}
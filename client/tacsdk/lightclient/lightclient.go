@@ -0,0 +1,274 @@
+// Package lightclient verifies the headers and the state of TAC chain without
+// running a node, for relayers and backends reading the chain from untrusted
+// RPC servers.
+//
+// It wraps the light client of CometBFT with the defaults of TAC chain: the
+// trust period fits the unbonding period of the networks and the chain ids are
+// checked to be of the form <name>_<evm chain id>-<revision>. The client
+// verifies the headers from a trusted header, then the store values and the
+// tx proofs of tacsdk.TxProof against the headers it verified.
+package lightclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	dbm "github.com/cometbft/cometbft-db"
+	cmtlog "github.com/cometbft/cometbft/libs/log"
+	cmtmath "github.com/cometbft/cometbft/libs/math"
+	cmtlight "github.com/cometbft/cometbft/light"
+	lightdb "github.com/cometbft/cometbft/light/store/db"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	cmttypes "github.com/cometbft/cometbft/types"
+
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+)
+
+const (
+	// DefaultTrustPeriod is the time a verified header is trusted for, two
+	// thirds of the 21 days unbonding period of the TAC networks. A validator
+	// set can't sign a conflicting header without being slashed for it
+	// within that time.
+	DefaultTrustPeriod = 14 * 24 * time.Hour
+	// DefaultMaxClockDrift is how far in the future the time of a header can
+	// be from the clock of the client
+	DefaultMaxClockDrift = 10 * time.Second
+)
+
+// DefaultTrustLevel is the share of the voting power of a trusted validator
+// set that must sign a header for the client to skip the headers between
+// them, the default of CometBFT
+var DefaultTrustLevel = cmtlight.DefaultTrustLevel
+
+// chainIDRegexp matches the chain ids of TAC networks, e.g. tacchain_239-1
+var chainIDRegexp = regexp.MustCompile(`^([a-z][a-z0-9]*)_([1-9][0-9]*)-([1-9][0-9]*)$`)
+
+// ChainID is a chain id of the form <name>_<evm chain id>-<revision>, e.g.
+// tacchain_239-1
+type ChainID struct {
+	Name string
+	// EVMChainID is the EIP-155 chain id of the EVM txs
+	EVMChainID uint64
+	// Revision is the revision number IBC light clients of the chain track
+	// its heights with, incremented by the upgrades resetting the heights
+	Revision uint64
+}
+
+// ParseChainID parses a chain id of the form <name>_<evm chain id>-<revision>
+func ParseChainID(chainID string) (ChainID, error) {
+	matches := chainIDRegexp.FindStringSubmatch(chainID)
+	if matches == nil {
+		return ChainID{}, fmt.Errorf("invalid chain id %q, expected <name>_<evm chain id>-<revision>", chainID)
+	}
+	evmChainID, err := strconv.ParseUint(matches[2], 10, 64)
+	if err != nil {
+		return ChainID{}, fmt.Errorf("invalid evm chain id of %q: %w", chainID, err)
+	}
+	revision, err := strconv.ParseUint(matches[3], 10, 64)
+	if err != nil {
+		return ChainID{}, fmt.Errorf("invalid revision of %q: %w", chainID, err)
+	}
+	return ChainID{Name: matches[1], EVMChainID: evmChainID, Revision: revision}, nil
+}
+
+// String returns the chain id
+func (c ChainID) String() string {
+	return fmt.Sprintf("%s_%d-%d", c.Name, c.EVMChainID, c.Revision)
+}
+
+// Config configures a light client
+type Config struct {
+	ChainID string
+	// Primary is the RPC server the headers are read from, e.g.
+	// https://rpc.tac.build or tcp://localhost:26657
+	Primary string
+	// Witnesses are the RPC servers the headers of Primary are cross-checked
+	// with, ideally run by other operators. Primary is its own witness when
+	// there are none, which leaves forks of Primary undetected.
+	Witnesses []string
+
+	// TrustHeight and TrustHash are the height and the hash of a header the
+	// client trusts, obtained from a source other than Primary, e.g. an
+	// explorer or a validator. It must be more recent than TrustPeriod.
+	TrustHeight int64
+	TrustHash   []byte
+
+	TrustPeriod   time.Duration
+	TrustLevel    cmtmath.Fraction
+	MaxClockDrift time.Duration
+
+	// DB stores the verified headers, in memory when nil
+	DB     dbm.DB
+	Logger cmtlog.Logger
+}
+
+// DefaultConfig returns the config of a light client of chainID reading the
+// headers from primary and trusting the header at trustHeight with
+// trustHash, with the TAC defaults
+func DefaultConfig(chainID, primary string, trustHeight int64, trustHash []byte) Config {
+	return Config{
+		ChainID:       chainID,
+		Primary:       primary,
+		TrustHeight:   trustHeight,
+		TrustHash:     trustHash,
+		TrustPeriod:   DefaultTrustPeriod,
+		TrustLevel:    DefaultTrustLevel,
+		MaxClockDrift: DefaultMaxClockDrift,
+	}
+}
+
+// TrustOptions returns the trust options of the CometBFT light client
+func (c Config) TrustOptions() cmtlight.TrustOptions {
+	return cmtlight.TrustOptions{Period: c.TrustPeriod, Height: c.TrustHeight, Hash: c.TrustHash}
+}
+
+// Validate checks the config is complete
+func (c Config) Validate() error {
+	if _, err := ParseChainID(c.ChainID); err != nil {
+		return err
+	}
+	if c.Primary == "" {
+		return errors.New("missing primary rpc server")
+	}
+	if err := c.TrustOptions().ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid trust options: %w", err)
+	}
+	if err := cmtlight.ValidateTrustLevel(c.TrustLevel); err != nil {
+		return err
+	}
+	if c.MaxClockDrift < 0 {
+		return fmt.Errorf("negative max clock drift %s", c.MaxClockDrift)
+	}
+	return nil
+}
+
+// Client verifies the headers of a TAC network and the state and the txs
+// they commit to. It is safe for concurrent use.
+type Client struct {
+	chainID ChainID
+	light   *cmtlight.Client
+	rpc     *rpchttp.HTTP
+}
+
+// New returns a light client of the config, which fetches and verifies the
+// trusted header unless the DB already holds verified headers
+func New(ctx context.Context, cfg Config) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	chainID, _ := ParseChainID(cfg.ChainID)
+
+	db := cfg.DB
+	if db == nil {
+		db = dbm.NewMemDB()
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = cmtlog.NewNopLogger()
+	}
+	witnesses := cfg.Witnesses
+	if len(witnesses) == 0 {
+		witnesses = []string{cfg.Primary}
+	}
+
+	light, err := cmtlight.NewHTTPClient(
+		ctx,
+		cfg.ChainID,
+		cfg.TrustOptions(),
+		cfg.Primary,
+		witnesses,
+		lightdb.New(db, cfg.ChainID),
+		cmtlight.SkippingVerification(cfg.TrustLevel),
+		cmtlight.MaxClockDrift(cfg.MaxClockDrift),
+		cmtlight.Logger(logger),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the light client of %s: %w", cfg.ChainID, err)
+	}
+	rpc, err := rpchttp.New(cfg.Primary, "/websocket")
+	if err != nil {
+		return nil, err
+	}
+	return &Client{chainID: chainID, light: light, rpc: rpc}, nil
+}
+
+// ChainID returns the chain id of the network
+func (c *Client) ChainID() ChainID {
+	return c.chainID
+}
+
+// Update verifies the latest header of Primary and returns it
+func (c *Client) Update(ctx context.Context) (*cmttypes.LightBlock, error) {
+	block, err := c.light.Update(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		// the latest header was already verified
+		return c.light.TrustedLightBlock(0)
+	}
+	return block, nil
+}
+
+// VerifyHeader returns the header at height, with its commit and validator
+// set, once verified
+func (c *Client) VerifyHeader(ctx context.Context, height int64) (*cmttypes.LightBlock, error) {
+	return c.light.VerifyLightBlockAtHeight(ctx, height, time.Now())
+}
+
+// VerifyTxProof checks the proof of a tx and its result, as returned by
+// tacchaind q tac tx-proof, against the header of the block after the tx
+func (c *Client) VerifyTxProof(ctx context.Context, proof tacsdk.TxProof) error {
+	if proof.NextHeader.Header == nil {
+		return errors.New("missing next header")
+	}
+	block, err := c.VerifyHeader(ctx, proof.Height+1)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(block.Hash(), proof.NextHeader.Hash()) {
+		return fmt.Errorf("header %d of the proof is %s, the verified one is %s", proof.Height+1, proof.NextHeader.Hash(), block.Hash())
+	}
+	return proof.Verify()
+}
+
+// QueryStore returns the value at key in the store of a module at height,
+// the latest height the client can verify when 0, along with the height it
+// was read at. The value is nil when the key isn't set. It is verified
+// against the app hash of the next header, which commits to the state after
+// the block at height.
+func (c *Client) QueryStore(ctx context.Context, storeName string, key []byte, height int64) ([]byte, int64, error) {
+	if height == 0 {
+		latest, err := c.Update(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		height = latest.Height - 1
+	}
+
+	res, err := c.rpc.ABCIQueryWithOptions(ctx, "/store/"+storeName+"/key", key, rpcclient.ABCIQueryOptions{Height: height, Prove: true})
+	if err != nil {
+		return nil, 0, err
+	}
+	if !res.Response.IsOK() {
+		return nil, 0, fmt.Errorf("query of %s failed with code %d: %s", storeName, res.Response.Code, res.Response.Log)
+	}
+	if res.Response.ProofOps == nil {
+		return nil, 0, fmt.Errorf("query of %s at %d returned no proof", storeName, res.Response.Height)
+	}
+
+	block, err := c.VerifyHeader(ctx, res.Response.Height+1)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := VerifyStoreValue(block.AppHash, storeName, key, res.Response.Value, res.Response.ProofOps); err != nil {
+		return nil, 0, err
+	}
+	return res.Response.Value, res.Response.Height, nil
+}
@@ -0,0 +1,111 @@
+package lightclient_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	cmtmath "github.com/cometbft/cometbft/libs/math"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	"cosmossdk.io/store/metrics"
+	"cosmossdk.io/store/rootmulti"
+	storetypes "cosmossdk.io/store/types"
+
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+	"github.com/Asphere-xyz/tacchain/client/tacsdk/lightclient"
+)
+
+func TestParseChainID(t *testing.T) {
+	chainID, err := lightclient.ParseChainID(tacsdk.MainnetChainID)
+	require.NoError(t, err)
+	require.Equal(t, lightclient.ChainID{Name: "tacchain", EVMChainID: 239, Revision: 1}, chainID)
+	require.Equal(t, tacsdk.MainnetChainID, chainID.String())
+
+	chainID, err = lightclient.ParseChainID("tacchain_2391-12")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2391), chainID.EVMChainID)
+	require.Equal(t, uint64(12), chainID.Revision)
+
+	for _, invalid := range []string{"", "tacchain", "tacchain_239", "tacchain-1", "tacchain_0-1", "tacchain_239-0", "Tacchain_239-1", "tacchain_239-1 ", "tacchain_99999999999999999999-1"} {
+		_, err := lightclient.ParseChainID(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	hash := bytes.Repeat([]byte{0x01}, 32)
+	config := lightclient.DefaultConfig(tacsdk.MainnetChainID, "tcp://localhost:26657", 100, hash)
+	require.NoError(t, config.Validate())
+	require.Equal(t, 14*24*time.Hour, config.TrustPeriod)
+	require.Equal(t, cmtmath.Fraction{Numerator: 1, Denominator: 3}, config.TrustLevel)
+
+	for _, tc := range []struct {
+		name   string
+		config func(c *lightclient.Config)
+	}{
+		{"invalid chain id", func(c *lightclient.Config) { c.ChainID = "tacchain" }},
+		{"no primary", func(c *lightclient.Config) { c.Primary = "" }},
+		{"no trust height", func(c *lightclient.Config) { c.TrustHeight = 0 }},
+		{"invalid trust hash", func(c *lightclient.Config) { c.TrustHash = hash[:20] }},
+		{"no trust period", func(c *lightclient.Config) { c.TrustPeriod = 0 }},
+		{"trust level below a third", func(c *lightclient.Config) { c.TrustLevel = cmtmath.Fraction{Numerator: 1, Denominator: 4} }},
+		{"negative clock drift", func(c *lightclient.Config) { c.MaxClockDrift = -time.Second }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			invalid := config
+			tc.config(&invalid)
+			require.Error(t, invalid.Validate())
+		})
+	}
+}
+
+func TestVerifyStoreValue(t *testing.T) {
+	store := rootmulti.NewStore(dbm.NewMemDB(), log.NewNopLogger(), metrics.NewNoOpMetrics())
+	bankKey, evmKey := storetypes.NewKVStoreKey("bank"), storetypes.NewKVStoreKey("evm")
+	store.MountStoreWithDB(bankKey, storetypes.StoreTypeIAVL, nil)
+	store.MountStoreWithDB(evmKey, storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, store.LoadLatestVersion())
+	store.GetKVStore(bankKey).Set([]byte("balance/a"), []byte("100"))
+	store.GetKVStore(bankKey).Set([]byte("balance/c"), []byte("300"))
+	store.GetKVStore(evmKey).Set([]byte("code"), []byte{0x60, 0x80})
+	commit := store.Commit()
+	appHash := commit.Hash
+
+	query := func(storeName string, key []byte) *storetypes.ResponseQuery {
+		res, err := store.Query(&storetypes.RequestQuery{Path: "/" + storeName + "/key", Data: key, Height: commit.Version, Prove: true})
+		require.NoError(t, err)
+		return res
+	}
+
+	// a value and the absence of a key
+	res := query("bank", []byte("balance/a"))
+	require.Equal(t, []byte("100"), res.Value)
+	require.NoError(t, lightclient.VerifyStoreValue(appHash, "bank", []byte("balance/a"), res.Value, res.ProofOps))
+	absent := query("bank", []byte("balance/b"))
+	require.Empty(t, absent.Value)
+	require.NoError(t, lightclient.VerifyStoreValue(appHash, "bank", []byte("balance/b"), nil, absent.ProofOps))
+
+	for _, tc := range []struct {
+		name      string
+		appHash   []byte
+		storeName string
+		key       []byte
+		value     []byte
+		proof     *storetypes.ResponseQuery
+	}{
+		{"another value", appHash, "bank", []byte("balance/a"), []byte("1000"), res},
+		{"absence of a value", appHash, "bank", []byte("balance/a"), nil, res},
+		{"value of an absent key", appHash, "bank", []byte("balance/b"), []byte("100"), absent},
+		{"another key", appHash, "bank", []byte("balance/c"), []byte("100"), res},
+		{"another store", appHash, "evm", []byte("balance/a"), []byte("100"), res},
+		{"another app hash", bytes.Repeat([]byte{0x01}, 32), "bank", []byte("balance/a"), []byte("100"), res},
+		{"no proof", appHash, "bank", []byte("balance/a"), []byte("100"), &storetypes.ResponseQuery{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, lightclient.VerifyStoreValue(tc.appHash, tc.storeName, tc.key, tc.value, tc.proof.ProofOps))
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package lightclient
+
+import (
+	"fmt"
+
+	"github.com/cometbft/cometbft/crypto/merkle"
+	cmtcrypto "github.com/cometbft/cometbft/proto/tendermint/crypto"
+
+	"cosmossdk.io/store/rootmulti"
+)
+
+// VerifyStoreValue checks proofOps, the proof of a store query with prove
+// set, proves value at key in the store of a module against appHash, the app
+// hash of the header after the queried height. An empty value is proven
+// absent.
+func VerifyStoreValue(appHash []byte, storeName string, key, value []byte, proofOps *cmtcrypto.ProofOps) error {
+	if proofOps == nil {
+		return fmt.Errorf("missing proof of %X in %s", key, storeName)
+	}
+
+	keyPath := merkle.KeyPath{}.
+		AppendKey([]byte(storeName), merkle.KeyEncodingURL).
+		AppendKey(key, merkle.KeyEncodingURL).
+		String()
+	runtime := rootmulti.DefaultProofRuntime()
+	if len(value) == 0 {
+		if err := runtime.VerifyAbsence(proofOps, appHash, keyPath); err != nil {
+			return fmt.Errorf("invalid proof of the absence of %X in %s: %w", key, storeName, err)
+		}
+		return nil
+	}
+	if err := runtime.VerifyValue(proofOps, appHash, keyPath, value); err != nil {
+		return fmt.Errorf("invalid proof of %X in %s: %w", key, storeName, err)
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+package tacsdk
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	autocompoundtypes "github.com/Asphere-xyz/tacchain/x/autocompound/types"
+	bridgetypes "github.com/Asphere-xyz/tacchain/x/bridge/types"
+	contractmetatypes "github.com/Asphere-xyz/tacchain/x/contractmeta/types"
+	ibchookstypes "github.com/Asphere-xyz/tacchain/x/ibchooks/types"
+	nameservicetypes "github.com/Asphere-xyz/tacchain/x/nameservice/types"
+	selfbondtypes "github.com/Asphere-xyz/tacchain/x/selfbond/types"
+	tacgovtypes "github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+// NewAutocompoundGrantMsg returns the message opting delegator in to the
+// compounding of its rewards from validators by the autocompound module,
+// until expiration if set. Revoking the grant opts it out.
+func NewAutocompoundGrantMsg(delegator sdk.AccAddress, validators []sdk.ValAddress, expiration *time.Time) (*authz.MsgGrant, error) {
+	if len(validators) == 0 {
+		return nil, errors.New("no validators to compound the rewards of")
+	}
+	authorization, err := stakingtypes.NewStakeAuthorization(
+		validators, nil, stakingtypes.AuthorizationType_AUTHORIZATION_TYPE_DELEGATE, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return authz.NewMsgGrant(delegator, autocompoundtypes.ModuleAddress(), authorization, expiration)
+}
+
+// VoteMsgTypeURL is the type of the gov messages a voting service is
+// authorized to send on behalf of a delegator
+var VoteMsgTypeURL = sdk.MsgTypeURL(&govv1.MsgVote{})
+
+// NewVoteGrantMsg returns the message of voter authorizing service to vote on
+// its behalf until expiration if set, with the voting power of its
+// delegations. Revoking the grant, or voting directly, takes the vote back.
+func NewVoteGrantMsg(voter, service sdk.AccAddress, expiration *time.Time) (*authz.MsgGrant, error) {
+	return authz.NewMsgGrant(voter, service, authz.NewGenericAuthorization(VoteMsgTypeURL), expiration)
+}
+
+// NewExecVoteMsg returns the message of service voting option on proposalID
+// on behalf of voter, as sent by tacchaind tx tac exec-vote. The vote is the
+// one of voter, it replaces any earlier vote of voter on the proposal.
+func NewExecVoteMsg(service, voter sdk.AccAddress, proposalID uint64, option govv1.VoteOption) *authz.MsgExec {
+	msg := authz.NewMsgExec(service, []sdk.Msg{govv1.NewMsgVote(voter, proposalID, option, "")})
+	return &msg
+}
+
+// EVMCall is a call of a chain specific operation sent as an EVM tx
+type EVMCall struct {
+	To    common.Address
+	Data  []byte
+	Value *big.Int
+}
+
+// DynamicFeeTx returns the unsigned EIP-1559 tx sending the call, to sign
+// with gethtypes.SignTx and send with eth_sendRawTransaction
+func (c EVMCall) DynamicFeeTx(chainID *big.Int, nonce, gas uint64, gasFeeCap, gasTipCap *big.Int) *gethtypes.Transaction {
+	value := c.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	to := c.To
+	return gethtypes.NewTx(&gethtypes.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Gas:       gas,
+		To:        &to,
+		Value:     value,
+		Data:      c.Data,
+	})
+}
+
+// NewWithdrawCall returns the call queuing the withdrawal of amount of the
+// native token to the TON address recipient
+func NewWithdrawCall(recipient string, amount *big.Int) (EVMCall, error) {
+	if err := bridgetypes.ValidateTONAddress(recipient); err != nil {
+		return EVMCall{}, err
+	}
+	if amount == nil || amount.Sign() <= 0 {
+		return EVMCall{}, errors.New("withdrawal amount must be positive")
+	}
+	data, err := bridgetypes.EscrowCall{Method: bridgetypes.MethodWithdraw, Recipient: recipient}.Pack()
+	if err != nil {
+		return EVMCall{}, err
+	}
+	return EVMCall{To: bridgetypes.EscrowAddress(), Data: data, Value: amount}, nil
+}
+
+// NewWithdrawAssetCall returns the call burning amount, the denom of a
+// registered bridge asset, from the sender as it is released to the TON
+// address recipient
+func NewWithdrawAssetCall(recipient string, amount sdk.Coin) (EVMCall, error) {
+	if err := bridgetypes.ValidateTONAddress(recipient); err != nil {
+		return EVMCall{}, err
+	}
+	if !amount.IsValid() || !amount.IsPositive() {
+		return EVMCall{}, fmt.Errorf("invalid asset amount: %s", amount)
+	}
+	data, err := bridgetypes.EscrowCall{Method: bridgetypes.MethodWithdrawAsset, Recipient: recipient, Amount: amount}.Pack()
+	if err != nil {
+		return EVMCall{}, err
+	}
+	return EVMCall{To: bridgetypes.EscrowAddress(), Data: data}, nil
+}
+
+// NewChallengeCall returns the call challenging the queued withdrawal id
+func NewChallengeCall(id uint64) (EVMCall, error) {
+	data, err := bridgetypes.EscrowCall{Method: bridgetypes.MethodChallenge, ID: id}.Pack()
+	if err != nil {
+		return EVMCall{}, err
+	}
+	return EVMCall{To: bridgetypes.EscrowAddress(), Data: data}, nil
+}
+
+// NewDepositCall returns the call executing a deposit from TON with the
+// attestation signatures of the relayers, see bridgetypes.AttestationDigest
+// and Deposit.PayloadHash for what they sign
+func NewDepositCall(deposit bridgetypes.Deposit, signatures [][]byte) (EVMCall, error) {
+	if err := deposit.Validate(); err != nil {
+		return EVMCall{}, err
+	}
+	data, err := bridgetypes.EscrowCall{Method: bridgetypes.MethodDeposit, Deposit: deposit, Signatures: signatures}.Pack()
+	if err != nil {
+		return EVMCall{}, err
+	}
+	return EVMCall{To: bridgetypes.EscrowAddress(), Data: data}, nil
+}
+
+// NewExitValidatorCall returns the call exiting the validator operated by the
+// sender, as sent by tacchaind tx tac exit-validator: its self bond is
+// undelegated and the selfbond module unbonds the other delegations.
+func NewExitValidatorCall() (EVMCall, error) {
+	data, err := selfbondtypes.ExitCall{Method: selfbondtypes.MethodExit}.Pack()
+	if err != nil {
+		return EVMCall{}, err
+	}
+	return EVMCall{To: selfbondtypes.ExitAddress(), Data: data}, nil
+}
+
+// NewBondRelayerCall returns the call adding amount of the bond denom to the
+// bridge relayer bond of the sender, as sent by tacchaind tx tac bond-relayer.
+// Only relayers of the set bond.
+func NewBondRelayerCall(amount *big.Int) (EVMCall, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return EVMCall{}, errors.New("bond amount must be positive")
+	}
+	data, err := bridgetypes.EscrowCall{Method: bridgetypes.MethodBond}.Pack()
+	if err != nil {
+		return EVMCall{}, err
+	}
+	return EVMCall{To: bridgetypes.EscrowAddress(), Data: data, Value: amount}, nil
+}
+
+// NewRegisterContractCall returns the call setting the metadata of contract,
+// sent by its deployer, which deployed it with deployNonce, or by the owner of
+// its metadata
+func NewRegisterContractCall(contract common.Address, deployNonce uint64, name string, sourceHash common.Hash, auditLink string) (EVMCall, error) {
+	data, err := contractmetatypes.RegistryCall{
+		Method:      contractmetatypes.MethodRegister,
+		Contract:    contract,
+		DeployNonce: deployNonce,
+		Name:        name,
+		SourceHash:  sourceHash,
+		AuditLink:   auditLink,
+	}.Pack()
+	if err != nil {
+		return EVMCall{}, err
+	}
+	return EVMCall{To: contractmetatypes.RegistryAddress(), Data: data}, nil
+}
+
+// NewUnregisterContractCall returns the call removing the metadata of
+// contract, sent by the owner of its metadata
+func NewUnregisterContractCall(contract common.Address) (EVMCall, error) {
+	data, err := contractmetatypes.RegistryCall{Method: contractmetatypes.MethodUnregister, Contract: contract}.Pack()
+	if err != nil {
+		return EVMCall{}, err
+	}
+	return EVMCall{To: contractmetatypes.RegistryAddress(), Data: data}, nil
+}
+
+// NewSetVoteDelegateCall returns the call making delegate vote on the
+// proposals the sender doesn't vote on, with the stake of the sender
+func NewSetVoteDelegateCall(delegate common.Address) (EVMCall, error) {
+	data, err := tacgovtypes.VoteDelegationCall{Method: tacgovtypes.MethodSetVoteDelegate, Delegate: delegate}.Pack()
+	if err != nil {
+		return EVMCall{}, err
+	}
+	return EVMCall{To: tacgovtypes.VoteDelegationAddress(), Data: data}, nil
+}
+
+// NewClearVoteDelegateCall returns the call removing the vote delegate of the
+// sender
+func NewClearVoteDelegateCall() (EVMCall, error) {
+	data, err := tacgovtypes.VoteDelegationCall{Method: tacgovtypes.MethodClearVoteDelegate}.Pack()
+	if err != nil {
+		return EVMCall{}, err
+	}
+	return EVMCall{To: tacgovtypes.VoteDelegationAddress(), Data: data}, nil
+}
+
+// NewRegisterVoucherCall returns the call registering the ERC-20 extension of
+// the IBC voucher denom, whose bank metadata must be set
+func NewRegisterVoucherCall(denom string) (EVMCall, error) {
+	data, err := ibchookstypes.VoucherRegistryCall{Method: ibchookstypes.MethodRegisterVoucher, Denom: denom}.Pack()
+	if err != nil {
+		return EVMCall{}, err
+	}
+	return EVMCall{To: ibchookstypes.VoucherRegistryAddress(), Data: data}, nil
+}
+
+// NewRegisterNameCall returns the call registering name for the sender,
+// pointing to target. The sender pays the registration fee from its balance.
+func NewRegisterNameCall(name string, target common.Address) (EVMCall, error) {
+	return newNameRegistryCall(nameservicetypes.RegistryCall{Method: nameservicetypes.MethodRegister, Name: name, Address: target})
+}
+
+// NewRenewNameCall returns the call extending the registration of name, sent
+// by its owner, who pays the registration fee from its balance
+func NewRenewNameCall(name string) (EVMCall, error) {
+	return newNameRegistryCall(nameservicetypes.RegistryCall{Method: nameservicetypes.MethodRenew, Name: name})
+}
+
+// NewSetNameAddressCall returns the call pointing name to target, sent by its
+// owner
+func NewSetNameAddressCall(name string, target common.Address) (EVMCall, error) {
+	return newNameRegistryCall(nameservicetypes.RegistryCall{Method: nameservicetypes.MethodSetAddress, Name: name, Address: target})
+}
+
+// NewTransferNameCall returns the call giving name to newOwner, sent by its
+// owner
+func NewTransferNameCall(name string, newOwner common.Address) (EVMCall, error) {
+	return newNameRegistryCall(nameservicetypes.RegistryCall{Method: nameservicetypes.MethodTransfer, Name: name, Address: newOwner})
+}
+
+// NewSetPrimaryNameCall returns the call setting the name the sender reverse
+// resolves to, one resolving to the sender, or clearing it with an empty name
+func NewSetPrimaryNameCall(name string) (EVMCall, error) {
+	return newNameRegistryCall(nameservicetypes.RegistryCall{Method: nameservicetypes.MethodSetPrimaryName, Name: name})
+}
+
+func newNameRegistryCall(call nameservicetypes.RegistryCall) (EVMCall, error) {
+	data, err := call.Pack()
+	if err != nil {
+		return EVMCall{}, err
+	}
+	return EVMCall{To: nameservicetypes.RegistryAddress(), Data: data}, nil
+}
+
+// DecodeEVMCall decodes the call of an EVM tx to a chain specific address:
+// it returns a bridgetypes.EscrowCall for the bridge escrow, a
+// contractmetatypes.RegistryCall for the contract metadata registry, a
+// tacgovtypes.VoteDelegationCall for the vote delegation address, an
+// ibchookstypes.VoucherRegistryCall for the voucher registry, a
+// nameservicetypes.RegistryCall for the name registry, a
+// selfbondtypes.ExitCall for the validator exit address, and nil for other
+// addresses.
+func DecodeEVMCall(to common.Address, data []byte) (any, error) {
+	switch to {
+	case bridgetypes.EscrowAddress():
+		call, err := bridgetypes.ParseEscrowCall(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid escrow call: %w", err)
+		}
+		return call, nil
+	case contractmetatypes.RegistryAddress():
+		call, err := contractmetatypes.ParseRegistryCall(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid registry call: %w", err)
+		}
+		return call, nil
+	case tacgovtypes.VoteDelegationAddress():
+		call, err := tacgovtypes.ParseVoteDelegationCall(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vote delegation call: %w", err)
+		}
+		return call, nil
+	case ibchookstypes.VoucherRegistryAddress():
+		call, err := ibchookstypes.ParseVoucherRegistryCall(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid voucher registry call: %w", err)
+		}
+		return call, nil
+	case nameservicetypes.RegistryAddress():
+		call, err := nameservicetypes.ParseRegistryCall(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name registry call: %w", err)
+		}
+		return call, nil
+	case selfbondtypes.ExitAddress():
+		call, err := selfbondtypes.ParseExitCall(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exit call: %w", err)
+		}
+		return call, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,134 @@
+package tacsdk
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/crypto/merkle"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/gogoproto/proto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+// TxProof proves that a tx was included in a block and gave a result, as
+// returned by tacchaind q tac tx-proof. The header of a block commits to its
+// txs with the data hash, and the header of the next block to their results
+// with the last results hash and to the block with the last block id. A
+// verifier trusting the hash of NextHeader, from a light client or the
+// signatures of its commit, can therefore trust Tx and the committed part of
+// Result.
+//
+// Only the code, data, gas wanted and gas used of a result are committed.
+// The data holds the responses of the messages of the tx, including the
+// logs of an EVM tx, but the events of the result aren't committed and can't
+// be trusted.
+type TxProof struct {
+	Height int64  `json:"height"`
+	Index  uint32 `json:"index"`
+	// Tx is the tx and its Merkle proof against the data hash of Header
+	Tx cmttypes.TxProof `json:"tx"`
+	// Result is the result of the execution of the tx
+	Result abci.ExecTxResult `json:"result"`
+	// ResultProof is the Merkle proof of Result against the last results hash
+	// of NextHeader
+	ResultProof merkle.Proof `json:"result_proof"`
+	// Header is the header of the block of the tx
+	Header cmttypes.Header `json:"header"`
+	// NextHeader is the header of the next block and the commit signing it
+	NextHeader cmttypes.SignedHeader `json:"next_header"`
+}
+
+// NewTxProof returns the proof of the tx at index in block, given the results
+// of the txs of block and the signed header of the next block.
+func NewTxProof(block *cmttypes.Block, results []*abci.ExecTxResult, index uint32, next *cmttypes.SignedHeader) (TxProof, error) {
+	if int(index) >= len(block.Txs) {
+		return TxProof{}, fmt.Errorf("tx %d not found in block %d of %d txs", index, block.Height, len(block.Txs))
+	}
+	if len(results) != len(block.Txs) {
+		return TxProof{}, fmt.Errorf("block %d has %d txs but %d results", block.Height, len(block.Txs), len(results))
+	}
+	if next == nil || next.Header == nil || next.Commit == nil {
+		return TxProof{}, fmt.Errorf("missing the signed header of block %d", block.Height+1)
+	}
+
+	proof := TxProof{
+		Height:      block.Height,
+		Index:       index,
+		Tx:          block.Txs.Proof(int(index)),
+		Result:      *results[index],
+		ResultProof: cmttypes.NewResults(results).ProveResult(int(index)),
+		Header:      block.Header,
+		NextHeader:  *next,
+	}
+	return proof, proof.Verify()
+}
+
+// Verify checks that the headers of the proof commit to the tx and its
+// result. It doesn't check the signatures of NextHeader, the caller must
+// trust its hash.
+func (p TxProof) Verify() error {
+	next := p.NextHeader
+	if next.Header == nil || next.Commit == nil {
+		return errors.New("missing next header")
+	}
+	if err := p.Header.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid header: %w", err)
+	}
+	if err := next.ValidateBasic(p.Header.ChainID); err != nil {
+		return fmt.Errorf("invalid next header: %w", err)
+	}
+	if p.Header.Height != p.Height || next.Height != p.Height+1 {
+		return fmt.Errorf("headers at heights %d and %d don't prove a tx of block %d", p.Header.Height, next.Height, p.Height)
+	}
+	if !bytes.Equal(next.LastBlockID.Hash, p.Header.Hash()) {
+		return fmt.Errorf("next header follows block %s, not %s", next.LastBlockID.Hash, p.Header.Hash())
+	}
+
+	if p.Tx.Proof.Index != int64(p.Index) || p.ResultProof.Index != int64(p.Index) {
+		return fmt.Errorf("proofs of txs %d and %d instead of %d", p.Tx.Proof.Index, p.ResultProof.Index, p.Index)
+	}
+	if p.Tx.Proof.Total != p.ResultProof.Total {
+		return fmt.Errorf("proofs of %d txs and %d results", p.Tx.Proof.Total, p.ResultProof.Total)
+	}
+	if err := p.Tx.Validate(p.Header.DataHash); err != nil {
+		return fmt.Errorf("invalid tx proof: %w", err)
+	}
+
+	// the leaf is the committed part of the result
+	leaf, err := cmttypes.NewResults([]*abci.ExecTxResult{&p.Result})[0].Marshal()
+	if err != nil {
+		return err
+	}
+	if err := p.ResultProof.Verify(next.LastResultsHash, leaf); err != nil {
+		return fmt.Errorf("invalid result proof: %w", err)
+	}
+	return nil
+}
+
+// EVMResponses decodes the responses of the EVM txs of the proven tx from
+// the committed data of its result, they hold the logs of the EVM txs.
+func (p TxProof) EVMResponses() ([]*evmvmtypes.MsgEthereumTxResponse, error) {
+	var data sdk.TxMsgData
+	if err := proto.Unmarshal(p.Result.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode the result data: %w", err)
+	}
+
+	typeURL := sdk.MsgTypeURL(&evmvmtypes.MsgEthereumTxResponse{})
+	var responses []*evmvmtypes.MsgEthereumTxResponse
+	for _, msgRes := range data.MsgResponses {
+		if msgRes.TypeUrl != typeURL {
+			continue
+		}
+		var res evmvmtypes.MsgEthereumTxResponse
+		if err := proto.Unmarshal(msgRes.Value, &res); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", typeURL, err)
+		}
+		responses = append(responses, &res)
+	}
+	return responses, nil
+}
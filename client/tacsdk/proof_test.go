@@ -0,0 +1,230 @@
+package tacsdk_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtversion "github.com/cometbft/cometbft/proto/tendermint/version"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cometbft/cometbft/version"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/stretchr/testify/require"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+)
+
+// provenBlock returns block 10 holding txs and the signed header of block 11,
+// which commits to the results of txs. The commit isn't really signed.
+func provenBlock(t *testing.T, txs cmttypes.Txs, results []*abci.ExecTxResult) (*cmttypes.Block, *cmttypes.SignedHeader) {
+	t.Helper()
+
+	hash := bytes.Repeat([]byte{0x01}, 32)
+	proposer := bytes.Repeat([]byte{0x02}, 20)
+	header := func(height int64) cmttypes.Header {
+		return cmttypes.Header{
+			Version:            cmtversion.Consensus{Block: version.BlockProtocol},
+			ChainID:            tacsdk.TestnetChainID,
+			Height:             height,
+			Time:               time.Unix(1_750_000_000+height, 0).UTC(),
+			ValidatorsHash:     hash,
+			NextValidatorsHash: hash,
+			ConsensusHash:      hash,
+			AppHash:            hash,
+			ProposerAddress:    proposer,
+		}
+	}
+
+	block := &cmttypes.Block{Header: header(10), Data: cmttypes.Data{Txs: txs}, LastCommit: &cmttypes.Commit{}}
+	next := header(11)
+	next.LastBlockID = cmttypes.BlockID{Hash: block.Hash()}
+	next.LastResultsHash = cmttypes.NewResults(results).Hash()
+	commit := &cmttypes.Commit{
+		Height:  11,
+		BlockID: cmttypes.BlockID{Hash: next.Hash()},
+		Signatures: []cmttypes.CommitSig{{
+			BlockIDFlag:      cmttypes.BlockIDFlagCommit,
+			ValidatorAddress: proposer,
+			Timestamp:        next.Time,
+			Signature:        bytes.Repeat([]byte{0x03}, 64),
+		}},
+	}
+	return block, &cmttypes.SignedHeader{Header: &next, Commit: commit}
+}
+
+// ethTxResults returns the results of a successful EVM tx with a log and of a
+// failed Cosmos tx
+func ethTxResults(t *testing.T) []*abci.ExecTxResult {
+	t.Helper()
+
+	res, err := codectypes.NewAnyWithValue(&evmvmtypes.MsgEthereumTxResponse{
+		Hash:    "0x6c1a4b3c6bd3d1f5d6b5f0e1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5",
+		Logs:    []*evmvmtypes.Log{{Address: "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", Topics: []string{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"}, Data: []byte{0x2a}}},
+		GasUsed: 45_000,
+	})
+	require.NoError(t, err)
+	data, err := proto.Marshal(&sdk.TxMsgData{MsgResponses: []*codectypes.Any{res}})
+	require.NoError(t, err)
+
+	return []*abci.ExecTxResult{
+		{
+			Data:      data,
+			GasWanted: 60_000,
+			GasUsed:   45_000,
+			Events:    []abci.Event{{Type: evmvmtypes.EventTypeEthereumTx, Attributes: []abci.EventAttribute{{Key: evmvmtypes.AttributeKeyEthereumTxHash, Value: "0x6c1a"}}}},
+		},
+		{Code: 5, Codespace: "sdk", Log: "insufficient funds", GasWanted: 200_000, GasUsed: 52_000},
+	}
+}
+
+func TestTxProof(t *testing.T) {
+	txs := cmttypes.Txs{cmttypes.Tx("eth tx"), cmttypes.Tx("bank send")}
+	results := ethTxResults(t)
+	block, next := provenBlock(t, txs, results)
+
+	for i := range txs {
+		proof, err := tacsdk.NewTxProof(block, results, uint32(i), next)
+		require.NoError(t, err)
+		require.Equal(t, int64(10), proof.Height)
+		require.Equal(t, txs[i], proof.Tx.Data)
+		require.Equal(t, results[i].Code, proof.Result.Code)
+
+		// the proof verifies once encoded like tacchaind q tac tx-proof does
+		bz, err := json.Marshal(proof)
+		require.NoError(t, err)
+		var decoded tacsdk.TxProof
+		require.NoError(t, json.Unmarshal(bz, &decoded))
+		require.NoError(t, decoded.Verify())
+		require.Equal(t, txs[i], decoded.Tx.Data)
+	}
+
+	_, err := tacsdk.NewTxProof(block, results, 2, next)
+	require.ErrorContains(t, err, "tx 2 not found")
+	_, err = tacsdk.NewTxProof(block, results[:1], 0, next)
+	require.ErrorContains(t, err, "has 2 txs but 1 results")
+	_, err = tacsdk.NewTxProof(block, results, 0, nil)
+	require.ErrorContains(t, err, "missing the signed header of block 11")
+}
+
+func TestTxProofVerify(t *testing.T) {
+	txs := cmttypes.Txs{cmttypes.Tx("eth tx"), cmttypes.Tx("bank send")}
+	results := ethTxResults(t)
+
+	for _, tc := range []struct {
+		name   string
+		tamper func(p *tacsdk.TxProof)
+		err    string
+	}{
+		{
+			name:   "events aren't committed",
+			tamper: func(p *tacsdk.TxProof) { p.Result.Events = nil },
+		},
+		{
+			name:   "log isn't committed",
+			tamper: func(p *tacsdk.TxProof) { p.Result.Log = "ok" },
+		},
+		{
+			name:   "code",
+			tamper: func(p *tacsdk.TxProof) { p.Result.Code = 5 },
+			err:    "invalid result proof",
+		},
+		{
+			name:   "data",
+			tamper: func(p *tacsdk.TxProof) { p.Result.Data = nil },
+			err:    "invalid result proof",
+		},
+		{
+			name:   "gas used",
+			tamper: func(p *tacsdk.TxProof) { p.Result.GasUsed++ },
+			err:    "invalid result proof",
+		},
+		{
+			name:   "result of another tx",
+			tamper: func(p *tacsdk.TxProof) { p.Result = *results[1] },
+			err:    "invalid result proof",
+		},
+		{
+			name:   "tx",
+			tamper: func(p *tacsdk.TxProof) { p.Tx.Data = cmttypes.Tx("another tx") },
+			err:    "invalid tx proof",
+		},
+		{
+			name:   "index",
+			tamper: func(p *tacsdk.TxProof) { p.Index = 1 },
+			err:    "instead of 1",
+		},
+		{
+			name:   "height",
+			tamper: func(p *tacsdk.TxProof) { p.Height = 11 },
+			err:    "don't prove a tx of block 11",
+		},
+		{
+			name:   "header",
+			tamper: func(p *tacsdk.TxProof) { p.Header.AppHash = bytes.Repeat([]byte{0x04}, 32) },
+			err:    "next header follows block",
+		},
+		{
+			name:   "data hash",
+			tamper: func(p *tacsdk.TxProof) { p.Header.DataHash = cmttypes.Txs{cmttypes.Tx("another tx")}.Hash() },
+			err:    "next header follows block",
+		},
+		{
+			name:   "results hash",
+			tamper: func(p *tacsdk.TxProof) { p.NextHeader.LastResultsHash = bytes.Repeat([]byte{0x04}, 32) },
+			err:    "invalid next header",
+		},
+		{
+			name:   "chain id",
+			tamper: func(p *tacsdk.TxProof) { p.Header.ChainID = tacsdk.MainnetChainID },
+			err:    "invalid next header",
+		},
+		{
+			name:   "next header",
+			tamper: func(p *tacsdk.TxProof) { p.NextHeader.Header = nil },
+			err:    "missing next header",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			block, next := provenBlock(t, txs, results)
+			proof, err := tacsdk.NewTxProof(block, results, 0, next)
+			require.NoError(t, err)
+
+			tc.tamper(&proof)
+			err = proof.Verify()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestTxProofEVMResponses(t *testing.T) {
+	txs := cmttypes.Txs{cmttypes.Tx("eth tx"), cmttypes.Tx("bank send")}
+	results := ethTxResults(t)
+	block, next := provenBlock(t, txs, results)
+
+	proof, err := tacsdk.NewTxProof(block, results, 0, next)
+	require.NoError(t, err)
+	responses, err := proof.EVMResponses()
+	require.NoError(t, err)
+	require.Len(t, responses, 1)
+	require.Equal(t, uint64(45_000), responses[0].GasUsed)
+	require.Len(t, responses[0].Logs, 1)
+	require.Equal(t, []byte{0x2a}, responses[0].Logs[0].Data)
+
+	// a failed tx has no responses
+	proof, err = tacsdk.NewTxProof(block, results, 1, next)
+	require.NoError(t, err)
+	responses, err = proof.EVMResponses()
+	require.NoError(t, err)
+	require.Empty(t, responses)
+}
@@ -0,0 +1,115 @@
+package tacsdk
+
+import (
+	"errors"
+	"fmt"
+
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	consensustypes "github.com/cosmos/cosmos-sdk/x/consensus/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+)
+
+// GovAuthority returns the address of the gov module, the authority of the
+// messages of a proposal
+func GovAuthority() sdk.AccAddress {
+	return authtypes.NewModuleAddress(govtypes.ModuleName)
+}
+
+// ProposalBuilder builds a gov proposal executing messages with the authority
+// of the gov module. The first error of a step is returned by Build.
+type ProposalBuilder struct {
+	title     string
+	summary   string
+	metadata  string
+	deposit   sdk.Coins
+	expedited bool
+	msgs      []sdk.Msg
+	err       error
+}
+
+// NewProposalBuilder returns a builder of a proposal with the given title and
+// summary, no deposit and no messages
+func NewProposalBuilder(title, summary string) *ProposalBuilder {
+	return &ProposalBuilder{title: title, summary: summary}
+}
+
+// WithDeposit sets the initial deposit of the proposal
+func (b *ProposalBuilder) WithDeposit(deposit sdk.Coins) *ProposalBuilder {
+	b.deposit = deposit
+	return b
+}
+
+// WithMetadata sets the metadata of the proposal, usually a link to its
+// full description
+func (b *ProposalBuilder) WithMetadata(metadata string) *ProposalBuilder {
+	b.metadata = metadata
+	return b
+}
+
+// Expedited makes the proposal expedited, with the shorter voting period and
+// the higher threshold of the gov params
+func (b *ProposalBuilder) Expedited(expedited bool) *ProposalBuilder {
+	b.expedited = expedited
+	return b
+}
+
+// AddMsg adds a message executed when the proposal passes, its authority
+// must be GovAuthority
+func (b *ProposalBuilder) AddMsg(msg sdk.Msg) *ProposalBuilder {
+	b.msgs = append(b.msgs, msg)
+	return b
+}
+
+// UpdateConsensusParams adds the message replacing the consensus params with
+// params. x/consensus replaces all the block, evidence and validator params
+// at once, so params is usually the current params with some fields changed.
+// The params are checked like CometBFT does, e.g. the evidence must fit in a
+// block and the validator pubkey types must be known.
+func (b *ProposalBuilder) UpdateConsensusParams(params cmtproto.ConsensusParams) *ProposalBuilder {
+	msg, err := NewUpdateConsensusParamsMsg(params)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	return b.AddMsg(msg)
+}
+
+func (b *ProposalBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Build returns the message of proposer submitting the proposal
+func (b *ProposalBuilder) Build(proposer sdk.AccAddress) (*govv1.MsgSubmitProposal, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.msgs) == 0 {
+		return nil, errors.New("proposal has no messages")
+	}
+	return govv1.NewMsgSubmitProposal(b.msgs, b.deposit, proposer.String(), b.metadata, b.title, b.summary, b.expedited)
+}
+
+// NewUpdateConsensusParamsMsg returns the x/consensus message replacing the
+// consensus params with params, see ProposalBuilder.UpdateConsensusParams
+func NewUpdateConsensusParamsMsg(params cmtproto.ConsensusParams) (*consensustypes.MsgUpdateParams, error) {
+	if params.Block == nil || params.Evidence == nil || params.Validator == nil {
+		return nil, errors.New("consensus params must set the block, evidence and validator params")
+	}
+	if err := cmttypes.ConsensusParamsFromProto(params).ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid consensus params: %w", err)
+	}
+	return &consensustypes.MsgUpdateParams{
+		Authority: GovAuthority().String(),
+		Block:     params.Block,
+		Evidence:  params.Evidence,
+		Validator: params.Validator,
+		Abci:      params.Abci,
+	}, nil
+}
@@ -0,0 +1,364 @@
+package tacsdk_test
+
+import (
+	"context"
+	"math/big"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	consensustypes "github.com/cosmos/cosmos-sdk/x/consensus/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/cosmos/evm/crypto/ethsecp256k1"
+	evmfeemarkettypes "github.com/cosmos/evm/x/feemarket/types"
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+	autocompoundtypes "github.com/Asphere-xyz/tacchain/x/autocompound/types"
+	bridgetypes "github.com/Asphere-xyz/tacchain/x/bridge/types"
+	contractmetatypes "github.com/Asphere-xyz/tacchain/x/contractmeta/types"
+	ibchookstypes "github.com/Asphere-xyz/tacchain/x/ibchooks/types"
+	nameservicetypes "github.com/Asphere-xyz/tacchain/x/nameservice/types"
+	selfbondtypes "github.com/Asphere-xyz/tacchain/x/selfbond/types"
+	tacgovtypes "github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+const (
+	vectorEthAddress = "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+	vectorAddress    = "tac1npvwllfr9dqr8erajqqr6s0vxnk2ak55v6q28s"
+	tonRecipient     = "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+)
+
+func TestConfigMatchesApp(t *testing.T) {
+	require.Equal(t, app.BaseDenom, tacsdk.BaseDenom)
+	require.Equal(t, app.DisplayDenom, tacsdk.DisplayDenom)
+	require.Equal(t, app.BaseDenomUnit, tacsdk.BaseDenomUnit)
+	require.Equal(t, app.Bech32PrefixAccAddr, tacsdk.Bech32PrefixAccAddr)
+	require.Equal(t, app.Bech32PrefixAccPub, tacsdk.Bech32PrefixAccPub)
+	require.Equal(t, app.Bech32PrefixValAddr, tacsdk.Bech32PrefixValAddr)
+	require.Equal(t, app.Bech32PrefixValPub, tacsdk.Bech32PrefixValPub)
+	require.Equal(t, app.Bech32PrefixConsAddr, tacsdk.Bech32PrefixConsAddr)
+	require.Equal(t, app.Bech32PrefixConsPub, tacsdk.Bech32PrefixConsPub)
+	require.Equal(t, app.DefaultChainID, tacsdk.TestnetChainID)
+
+	// the app sets the same config, setting it again is a no-op
+	require.NotPanics(t, tacsdk.SetSDKConfig)
+	require.Equal(t, tacsdk.CoinType, sdk.GetConfig().GetCoinType())
+}
+
+func TestAddresses(t *testing.T) {
+	ethAddr := common.HexToAddress(vectorEthAddress)
+	require.Equal(t, vectorAddress, tacsdk.Bech32FromEthAddress(ethAddr))
+
+	converted, err := tacsdk.EthAddressFromBech32(vectorAddress)
+	require.NoError(t, err)
+	require.Equal(t, ethAddr, converted)
+
+	for _, addr := range []string{vectorAddress, vectorEthAddress, "0x9858effd232b4033e47d90003d41ec34ecaeda94"} {
+		accAddr, err := tacsdk.ParseAccAddress(addr)
+		require.NoError(t, err, addr)
+		require.Equal(t, vectorAddress, accAddr.String(), addr)
+	}
+
+	for _, addr := range []string{
+		sdk.MustBech32ifyAddressBytes("cosmos", ethAddr.Bytes()),
+		sdk.MustBech32ifyAddressBytes(tacsdk.Bech32PrefixAccAddr, make([]byte, 32)),
+		"0x9858EfFD232B4033E47d90003D41EC34EcaEda",
+		"tac1invalid",
+	} {
+		_, err := tacsdk.ParseAccAddress(addr)
+		require.Error(t, err, addr)
+	}
+}
+
+func TestSignAndDecodeTx(t *testing.T) {
+	cfg := tacsdk.MakeEncodingConfig()
+	key, err := ethsecp256k1.GenerateKey()
+	require.NoError(t, err)
+	from := sdk.AccAddress(key.PubKey().Address())
+	to := sdk.AccAddress(common.HexToAddress(vectorEthAddress).Bytes())
+
+	send := banktypes.NewMsgSend(from, to, sdk.NewCoins(sdk.NewInt64Coin(tacsdk.BaseDenom, 1)))
+	bz, err := cfg.SignTx(context.Background(), tacsdk.TestnetChainID, tacsdk.TxRequest{
+		Msgs:     []sdk.Msg{send},
+		GasLimit: 200000,
+		Fee:      sdk.NewCoins(sdk.NewInt64Coin(tacsdk.BaseDenom, 20000000000000000)),
+		Memo:     "tacsdk",
+	}, tacsdk.Signer{Key: key, AccountNumber: 7, Sequence: 3})
+	require.NoError(t, err)
+
+	tx, err := cfg.DecodeTx(bz)
+	require.NoError(t, err)
+	require.Len(t, tx.GetMsgs(), 1)
+	require.Equal(t, send, tx.GetMsgs()[0])
+	require.Empty(t, tacsdk.EthTxs(tx))
+
+	sigTx, ok := tx.(authsigning.SigVerifiableTx)
+	require.True(t, ok)
+	sigs, err := sigTx.GetSignaturesV2()
+	require.NoError(t, err)
+	require.Len(t, sigs, 1)
+	require.Equal(t, key.PubKey(), sigs[0].PubKey)
+	require.Equal(t, uint64(3), sigs[0].Sequence)
+
+	json, err := cfg.TxJSON(tx)
+	require.NoError(t, err)
+	require.Contains(t, string(json), "/cosmos.bank.v1beta1.MsgSend")
+	require.Contains(t, string(json), "/cosmos.evm.crypto.v1.ethsecp256k1.PubKey")
+
+	_, err = cfg.SignTx(context.Background(), tacsdk.TestnetChainID, tacsdk.TxRequest{}, tacsdk.Signer{Key: key})
+	require.Error(t, err, "Txs without messages should not be signed")
+}
+
+func TestDecodeEthTx(t *testing.T) {
+	cfg := tacsdk.MakeEncodingConfig()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID := big.NewInt(2391)
+
+	call, err := tacsdk.NewWithdrawCall(tonRecipient, big.NewInt(1000))
+	require.NoError(t, err)
+	ethTx, err := gethtypes.SignTx(call.DynamicFeeTx(chainID, 5, 100000, big.NewInt(2), big.NewInt(1)), gethtypes.LatestSignerForChainID(chainID), key)
+	require.NoError(t, err)
+
+	// EVM txs are included in blocks as Cosmos txs holding a MsgEthereumTx
+	msg := &evmvmtypes.MsgEthereumTx{}
+	require.NoError(t, msg.FromEthereumTx(ethTx))
+	builder := cfg.TxConfig.NewTxBuilder()
+	require.NoError(t, builder.SetMsgs(msg))
+	bz, err := cfg.TxConfig.TxEncoder()(builder.GetTx())
+	require.NoError(t, err)
+
+	tx, err := cfg.DecodeTx(bz)
+	require.NoError(t, err)
+	ethTxs := tacsdk.EthTxs(tx)
+	require.Len(t, ethTxs, 1)
+	require.Equal(t, ethTx.Hash(), ethTxs[0].Hash())
+
+	decoded, err := tacsdk.DecodeEVMCall(*ethTxs[0].To(), ethTxs[0].Data())
+	require.NoError(t, err)
+	require.Equal(t, bridgetypes.EscrowCall{Method: bridgetypes.MethodWithdraw, Recipient: tonRecipient}, decoded)
+}
+
+func TestMsgs(t *testing.T) {
+	relayer := sdk.AccAddress(common.HexToAddress(vectorEthAddress).Bytes())
+	exit, err := tacsdk.NewExitValidatorCall()
+	require.NoError(t, err)
+	require.Equal(t, selfbondtypes.ExitAddress(), exit.To)
+	require.Nil(t, exit.Value)
+	decoded, err := tacsdk.DecodeEVMCall(exit.To, exit.Data)
+	require.NoError(t, err)
+	require.Equal(t, selfbondtypes.ExitCall{Method: selfbondtypes.MethodExit}, decoded)
+
+	validator := sdk.ValAddress(relayer)
+	expiration := time.Now().Add(time.Hour).UTC()
+	grant, err := tacsdk.NewAutocompoundGrantMsg(relayer, []sdk.ValAddress{validator}, &expiration)
+	require.NoError(t, err)
+	require.Equal(t, autocompoundtypes.ModuleAddress().String(), grant.Grantee)
+	authorization, err := grant.GetAuthorization()
+	require.NoError(t, err)
+	require.Equal(t, sdk.MsgTypeURL(&stakingtypes.MsgDelegate{}), authorization.MsgTypeURL())
+	require.IsType(t, &stakingtypes.StakeAuthorization{}, authorization)
+	_, err = tacsdk.NewAutocompoundGrantMsg(relayer, nil, nil)
+	require.Error(t, err)
+
+	service := sdk.AccAddress(common.HexToAddress("0x1111111111111111111111111111111111111111").Bytes())
+	voteGrant, err := tacsdk.NewVoteGrantMsg(relayer, service, &expiration)
+	require.NoError(t, err)
+	require.Equal(t, relayer.String(), voteGrant.Granter)
+	require.Equal(t, service.String(), voteGrant.Grantee)
+	authorization, err = voteGrant.GetAuthorization()
+	require.NoError(t, err)
+	require.Equal(t, "/cosmos.gov.v1.MsgVote", authorization.MsgTypeURL())
+
+	exec := tacsdk.NewExecVoteMsg(service, relayer, 7, govv1.OptionNoWithVeto)
+	require.Equal(t, service.String(), exec.Grantee)
+	msgs, err := exec.GetMessages()
+	require.NoError(t, err)
+	require.Equal(t, []sdk.Msg{govv1.NewMsgVote(relayer, 7, govv1.OptionNoWithVeto, "")}, msgs)
+}
+
+func TestEVMCalls(t *testing.T) {
+	contract := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	sourceHash := common.HexToHash("0x01")
+
+	_, err := tacsdk.NewWithdrawCall("not a ton address", big.NewInt(1))
+	require.Error(t, err)
+	_, err = tacsdk.NewWithdrawCall(tonRecipient, big.NewInt(0))
+	require.Error(t, err)
+
+	_, err = tacsdk.NewWithdrawAssetCall(tonRecipient, sdk.NewInt64Coin("ajusdt", 0))
+	require.Error(t, err)
+	_, err = tacsdk.NewBondRelayerCall(big.NewInt(0))
+	require.Error(t, err)
+
+	challenge, err := tacsdk.NewChallengeCall(42)
+	require.NoError(t, err)
+	depositArgs := bridgetypes.Deposit{Sequence: 7, Recipient: contract, Amount: math.NewInt(1000)}
+	deposit, err := tacsdk.NewDepositCall(depositArgs, [][]byte{{0x01}})
+	require.NoError(t, err)
+	require.Nil(t, deposit.Value)
+	_, err = tacsdk.NewDepositCall(bridgetypes.Deposit{Sequence: 7, Recipient: contract}, nil)
+	require.Error(t, err)
+	withdrawAsset, err := tacsdk.NewWithdrawAssetCall(tonRecipient, sdk.NewInt64Coin("ajusdt", 1000))
+	require.NoError(t, err)
+	require.Nil(t, withdrawAsset.Value)
+	bond, err := tacsdk.NewBondRelayerCall(big.NewInt(1000))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1000), bond.Value)
+	register, err := tacsdk.NewRegisterContractCall(contract, 3, "Token", sourceHash, "https://example.com/audit.pdf")
+	require.NoError(t, err)
+	unregister, err := tacsdk.NewUnregisterContractCall(contract)
+	require.NoError(t, err)
+	setVoteDelegate, err := tacsdk.NewSetVoteDelegateCall(contract)
+	require.NoError(t, err)
+	clearVoteDelegate, err := tacsdk.NewClearVoteDelegateCall()
+	require.NoError(t, err)
+	registerVoucher, err := tacsdk.NewRegisterVoucherCall("ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2")
+	require.NoError(t, err)
+	registerName, err := tacsdk.NewRegisterNameCall("alice", contract)
+	require.NoError(t, err)
+	transferName, err := tacsdk.NewTransferNameCall("alice", contract)
+	require.NoError(t, err)
+	setPrimaryName, err := tacsdk.NewSetPrimaryNameCall("alice")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		call tacsdk.EVMCall
+		to   common.Address
+		want any
+	}{
+		{challenge, bridgetypes.EscrowAddress(), bridgetypes.EscrowCall{Method: bridgetypes.MethodChallenge, ID: 42}},
+		{bond, bridgetypes.EscrowAddress(), bridgetypes.EscrowCall{Method: bridgetypes.MethodBond}},
+		{withdrawAsset, bridgetypes.EscrowAddress(), bridgetypes.EscrowCall{
+			Method: bridgetypes.MethodWithdrawAsset, Recipient: tonRecipient, Amount: sdk.NewInt64Coin("ajusdt", 1000),
+		}},
+		{deposit, bridgetypes.EscrowAddress(), bridgetypes.EscrowCall{Method: bridgetypes.MethodDeposit, Deposit: depositArgs, Signatures: [][]byte{{0x01}}}},
+		{register, contractmetatypes.RegistryAddress(), contractmetatypes.RegistryCall{
+			Method: contractmetatypes.MethodRegister, Contract: contract, DeployNonce: 3, Name: "Token",
+			SourceHash: sourceHash, AuditLink: "https://example.com/audit.pdf",
+		}},
+		{unregister, contractmetatypes.RegistryAddress(), contractmetatypes.RegistryCall{Method: contractmetatypes.MethodUnregister, Contract: contract}},
+		{setVoteDelegate, tacgovtypes.VoteDelegationAddress(), tacgovtypes.VoteDelegationCall{Method: tacgovtypes.MethodSetVoteDelegate, Delegate: contract}},
+		{clearVoteDelegate, tacgovtypes.VoteDelegationAddress(), tacgovtypes.VoteDelegationCall{Method: tacgovtypes.MethodClearVoteDelegate}},
+		{registerVoucher, ibchookstypes.VoucherRegistryAddress(), ibchookstypes.VoucherRegistryCall{
+			Method: ibchookstypes.MethodRegisterVoucher, Denom: "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2",
+		}},
+		{registerName, nameservicetypes.RegistryAddress(), nameservicetypes.RegistryCall{Method: nameservicetypes.MethodRegister, Name: "alice", Address: contract}},
+		{transferName, nameservicetypes.RegistryAddress(), nameservicetypes.RegistryCall{Method: nameservicetypes.MethodTransfer, Name: "alice", Address: contract}},
+		{setPrimaryName, nameservicetypes.RegistryAddress(), nameservicetypes.RegistryCall{Method: nameservicetypes.MethodSetPrimaryName, Name: "alice"}},
+	} {
+		require.Equal(t, tc.to, tc.call.To)
+		decoded, err := tacsdk.DecodeEVMCall(tc.call.To, tc.call.Data)
+		require.NoError(t, err)
+		require.Equal(t, tc.want, decoded)
+	}
+
+	decoded, err := tacsdk.DecodeEVMCall(contract, []byte{0x01})
+	require.NoError(t, err)
+	require.Nil(t, decoded, "Calls to other contracts are not decoded")
+	_, err = tacsdk.DecodeEVMCall(bridgetypes.EscrowAddress(), []byte{0x01})
+	require.Error(t, err)
+}
+
+func TestProposalBuilder(t *testing.T) {
+	proposer := sdk.AccAddress(common.HexToAddress(vectorEthAddress).Bytes())
+	deposit := sdk.NewCoins(sdk.NewInt64Coin(tacsdk.BaseDenom, 10))
+
+	params := cmttypes.DefaultConsensusParams().ToProto()
+	params.Evidence.MaxAgeNumBlocks = 200000
+	params.Evidence.MaxAgeDuration = 72 * time.Hour
+	params.Validator.PubKeyTypes = []string{cmttypes.ABCIPubKeyTypeEd25519, cmttypes.ABCIPubKeyTypeSecp256k1}
+	msg, err := tacsdk.NewProposalBuilder("Evidence", "Keep evidence for 72h").
+		WithDeposit(deposit).
+		Expedited(true).
+		UpdateConsensusParams(params).
+		Build(proposer)
+	require.NoError(t, err)
+	require.Equal(t, proposer.String(), msg.Proposer)
+	require.Equal(t, deposit, sdk.Coins(msg.InitialDeposit))
+	require.True(t, msg.Expedited)
+	msgs, err := msg.GetMsgs()
+	require.NoError(t, err)
+	require.Equal(t, []sdk.Msg{&consensustypes.MsgUpdateParams{
+		Authority: tacsdk.GovAuthority().String(),
+		Block:     params.Block,
+		Evidence:  params.Evidence,
+		Validator: params.Validator,
+		Abci:      params.Abci,
+	}}, msgs)
+
+	for name, update := range map[string]func(params *cmtproto.ConsensusParams){
+		"evidence larger than a block": func(params *cmtproto.ConsensusParams) { params.Evidence.MaxBytes = params.Block.MaxBytes + 1 },
+		"no evidence age":              func(params *cmtproto.ConsensusParams) { params.Evidence.MaxAgeNumBlocks = 0 },
+		"unknown pubkey type":          func(params *cmtproto.ConsensusParams) { params.Validator.PubKeyTypes = []string{"bls"} },
+		"no pubkey types":              func(params *cmtproto.ConsensusParams) { params.Validator.PubKeyTypes = nil },
+		"no block params":              func(params *cmtproto.ConsensusParams) { params.Block = nil },
+	} {
+		params := cmttypes.DefaultConsensusParams().ToProto()
+		update(&params)
+		_, err := tacsdk.NewProposalBuilder("Invalid", name).UpdateConsensusParams(params).Build(proposer)
+		require.Error(t, err, name)
+	}
+
+	_, err = tacsdk.NewProposalBuilder("Empty", "No messages").Build(proposer)
+	require.Error(t, err)
+}
+
+func TestNewFeeEstimate(t *testing.T) {
+	params := evmfeemarkettypes.DefaultParams()
+	params.BaseFee = math.LegacyNewDec(25_000_000_000)
+	params.MinGasPrice = math.LegacyNewDec(10_000_000_000)
+
+	estimate := tacsdk.NewFeeEstimate(tacsdk.TxTypeCosmos, 80_000, 100_000, tacsdk.BaseDenom, params, tacsdk.DefaultPriorityBuffer)
+	require.Equal(t, math.LegacyNewDec(27_500_000_000), estimate.GasPrice)
+	require.Equal(t, sdk.NewCoin(tacsdk.BaseDenom, math.NewInt(2_750_000_000_000_000)), estimate.Fee)
+	require.Equal(t, sdk.NewDecCoinFromDec(tacsdk.DisplayDenom, math.LegacyMustNewDecFromStr("0.00275")), estimate.FeeDisplay)
+
+	// the min gas price applies when it is above the base fee, and the fee is
+	// rounded up
+	params.BaseFee = math.LegacyNewDec(7)
+	params.MinGasPrice = math.LegacyNewDec(9)
+	estimate = tacsdk.NewFeeEstimate(tacsdk.TxTypeEVM, 21_000, 21_001, tacsdk.BaseDenom, params, 5)
+	require.Equal(t, math.LegacyMustNewDecFromStr("9.45"), estimate.GasPrice)
+	require.Equal(t, math.NewInt(198_460), estimate.Fee.Amount)
+
+	// without base fee only the min gas price counts
+	params.NoBaseFee = true
+	estimate = tacsdk.NewFeeEstimate(tacsdk.TxTypeEVM, 21_000, 21_000, tacsdk.BaseDenom, params, 0)
+	require.True(t, estimate.BaseFee.IsZero())
+	require.Equal(t, math.NewInt(189_000), estimate.Fee.Amount)
+
+	require.Equal(t, sdk.NewInt64DecCoin("uatom", 5), tacsdk.DisplayCoin(sdk.NewInt64Coin("uatom", 5)))
+}
+
+// TestDependencies checks the package doesn't pull in the app or the keepers
+func TestDependencies(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+	output, err := exec.Command("go", "list", "-deps", ".").CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	for _, pkg := range strings.Fields(string(output)) {
+		require.NotEqual(t, "github.com/Asphere-xyz/tacchain/app", pkg)
+		require.False(t, strings.HasPrefix(pkg, "github.com/Asphere-xyz/tacchain/") && strings.HasSuffix(pkg, "/keeper"), pkg)
+		require.NotEqual(t, "github.com/cosmos/evm/x/vm/keeper", pkg)
+	}
+}
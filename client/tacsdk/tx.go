@@ -0,0 +1,103 @@
+package tacsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	clienttx "github.com/cosmos/cosmos-sdk/client/tx"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+// TxRequest is the content of a Cosmos tx
+type TxRequest struct {
+	Msgs          []sdk.Msg
+	GasLimit      uint64
+	Fee           sdk.Coins
+	Memo          string
+	TimeoutHeight uint64
+}
+
+// Signer is the account signing a Cosmos tx. AccountNumber and Sequence are
+// the ones of the account on chain, as returned by the auth account query.
+type Signer struct {
+	Key           cryptotypes.PrivKey
+	AccountNumber uint64
+	Sequence      uint64
+}
+
+// SignTx builds the tx of req and signs it with SIGN_MODE_DIRECT for chainID,
+// it returns the bytes to broadcast
+func (c EncodingConfig) SignTx(ctx context.Context, chainID string, req TxRequest, signer Signer) ([]byte, error) {
+	if len(req.Msgs) == 0 {
+		return nil, errors.New("tx has no messages")
+	}
+
+	builder := c.TxConfig.NewTxBuilder()
+	if err := builder.SetMsgs(req.Msgs...); err != nil {
+		return nil, err
+	}
+	builder.SetGasLimit(req.GasLimit)
+	builder.SetFeeAmount(req.Fee)
+	builder.SetMemo(req.Memo)
+	builder.SetTimeoutHeight(req.TimeoutHeight)
+
+	// the signer infos are part of the signed bytes, set them first
+	pubKey := signer.Key.PubKey()
+	err := builder.SetSignatures(signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     &signing.SingleSignatureData{SignMode: signing.SignMode_SIGN_MODE_DIRECT},
+		Sequence: signer.Sequence,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	signerData := authsigning.SignerData{
+		Address:       sdk.AccAddress(pubKey.Address()).String(),
+		ChainID:       chainID,
+		AccountNumber: signer.AccountNumber,
+		Sequence:      signer.Sequence,
+		PubKey:        pubKey,
+	}
+	sig, err := clienttx.SignWithPrivKey(
+		ctx, signing.SignMode_SIGN_MODE_DIRECT, signerData, builder, signer.Key, c.TxConfig, signer.Sequence,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx: %w", err)
+	}
+	if err := builder.SetSignatures(sig); err != nil {
+		return nil, err
+	}
+
+	return c.TxConfig.TxEncoder()(builder.GetTx())
+}
+
+// DecodeTx decodes the bytes of a Cosmos tx, as found in the blocks
+func (c EncodingConfig) DecodeTx(bz []byte) (sdk.Tx, error) {
+	return c.TxConfig.TxDecoder()(bz)
+}
+
+// TxJSON returns the JSON form of a tx, as printed by the node
+func (c EncodingConfig) TxJSON(tx sdk.Tx) ([]byte, error) {
+	return c.TxConfig.TxJSONEncoder()(tx)
+}
+
+// EthTxs returns the EVM txs wrapped by the messages of tx, in order. The
+// Cosmos txs of EVM txs hold a single MsgEthereumTx.
+func EthTxs(tx sdk.Tx) []*gethtypes.Transaction {
+	var txs []*gethtypes.Transaction
+	for _, msg := range tx.GetMsgs() {
+		if ethMsg, ok := msg.(*evmvmtypes.MsgEthereumTx); ok {
+			txs = append(txs, ethMsg.AsTransaction())
+		}
+	}
+	return txs
+}
@@ -0,0 +1,88 @@
+// EventAttribute and Event are the events of the block_results and tx
+// responses of the node.
+export interface EventAttribute {
+    key: string;
+    value: string;
+    index?: boolean;
+}
+
+export interface Event {
+    type: string;
+    attributes: EventAttribute[];
+}
+
+// typedEvents holds the proto names of the typed events of the tac modules,
+// the type of their events. The modules declare them in Go, in the
+// events.go file of their types package, and have no .proto files.
+export const typedEvents: ReadonlySet<string> = new Set([
+    'tacchain.autocompound.v1.EventCompound',
+    'tacchain.bridge.v1.EventBondRelayer',
+    'tacchain.bridge.v1.EventSlashRelayer',
+    'tacchain.bridge.v1.EventUnbondRelayer',
+    'tacchain.bridge.v1.EventReleaseRelayerBond',
+    'tacchain.bridge.v1.EventQueueWithdrawal',
+    'tacchain.bridge.v1.EventChallengeWithdrawal',
+    'tacchain.bridge.v1.EventCompleteWithdrawal',
+    'tacchain.bridge.v1.EventRefundWithdrawal',
+    'tacchain.bridge.v1.EventDeposit',
+    'tacchain.bridge.v1.EventRegisterAsset',
+    'tacchain.bridge.v1.EventRemoveAsset',
+    'tacchain.bridge.v1.EventMintAsset',
+    'tacchain.bridge.v1.EventBurnAsset',
+    'tacchain.contractmeta.v1.EventSetContractMetadata',
+    'tacchain.contractmeta.v1.EventRemoveContractMetadata',
+    'tacchain.emission.v1.EventMint',
+    'tacchain.evmupgrade.v1.EventActivateEIP',
+    'tacchain.evmupgrade.v1.EventChangePrecompile',
+    'tacchain.feeburn.v1.EventBurnFees',
+    'tacchain.feerouting.v1.EventRouteTips',
+    'tacchain.feerouting.v1.EventEpochRevenue',
+    'tacchain.ibchooks.v1.EventEVMCall',
+    'tacchain.ibchooks.v1.EventSetVoucherMetadata',
+    'tacchain.ibchooks.v1.EventRegisterVoucher',
+    'tacchain.nameservice.v1.EventRegisterName',
+    'tacchain.nameservice.v1.EventRenewName',
+    'tacchain.nameservice.v1.EventUpdateName',
+    'tacchain.nameservice.v1.EventSetPrimaryName',
+    'tacchain.recovery.v1.EventRecoverFunds',
+    'tacchain.recovery.v1.EventRecoverFundsFailed',
+    'tacchain.selfbond.v1.EventJailBelowMinSelfBond',
+    'tacchain.selfbond.v1.EventExitUnbond',
+    'tacchain.selfbond.v1.EventExitValidator',
+    'tacchain.tacgov.v1.EventSetVoteDelegate',
+    'tacchain.tacgov.v1.EventClearVoteDelegate',
+]);
+
+// attributes the SDK adds to the typed events, which aren't message fields
+const sdkAttributes = new Set(['msg_index', 'mode']);
+
+// decodeEvent decodes a typed event of a tac module: each attribute holds a
+// field of the message, JSON encoded, under its proto field name. The fields
+// keep their proto JSON form, e.g. 64-bit integers are strings. It returns
+// undefined for other events.
+export function decodeEvent<T = Record<string, any>>(event: Event): { type: string; message: T } | undefined {
+    if (!typedEvents.has(event.type)) {
+        return undefined;
+    }
+    const message: Record<string, unknown> = {};
+    for (const { key, value } of event.attributes) {
+        if (!sdkAttributes.has(key)) {
+            message[key] = JSON.parse(value);
+        }
+    }
+    return { type: event.type, message: message as T };
+}
+
+// encodeEvent returns the event the node emits for message, the inverse of
+// decodeEvent.
+export function encodeEvent(type: string, message: Record<string, unknown>): Event {
+    if (!typedEvents.has(type)) {
+        throw new Error(`unknown event type ${type}`);
+    }
+    return {
+        type,
+        attributes: Object.keys(message)
+            .sort()
+            .map((key) => ({ key, value: JSON.stringify(message[key]) })),
+    };
+}
@@ -0,0 +1,62 @@
+import * as fs from 'fs';
+import * as path from 'path';
+import { decodeEvent, encodeEvent, Event } from '../src';
+
+// the fixture is checked against the node by TestEventsFixture in
+// x/bridge/types
+const events: Event[] = JSON.parse(
+    fs.readFileSync(path.join(__dirname, 'fixtures', 'bridge_events.json'), 'utf8'),
+);
+
+function find(type: string): Event {
+    const event = events.find((e) => e.type === type);
+    if (!event) {
+        throw new Error(`no ${type} in the fixture`);
+    }
+    return event;
+}
+
+describe('decodeEvent', () => {
+    it('decodes every event of the fixture', () => {
+        for (const event of events) {
+            expect(decodeEvent(event)?.type).toBe(event.type);
+        }
+    });
+
+    it('decodes integers and coins', () => {
+        const { message } = decodeEvent(find('tacchain.bridge.v1.EventQueueWithdrawal'))!;
+        expect(message.id).toBe('1');
+        expect(message.amount.denom).toBe('utac');
+        expect(message.complete_height).toBe('120');
+
+        const asset = decodeEvent(find('tacchain.bridge.v1.EventRegisterAsset'))!.message;
+        expect(asset.decimals).toBe(18);
+        expect(asset.ton_decimals).toBe(6);
+    });
+
+    it('skips the events of other modules', () => {
+        expect(decodeEvent({ type: 'transfer', attributes: [{ key: 'amount', value: '1utac' }] })).toBeUndefined();
+    });
+
+    it('ignores the attributes added by the SDK', () => {
+        const event = find('tacchain.bridge.v1.EventUnbondRelayer');
+        const withIndex = { ...event, attributes: [...event.attributes, { key: 'msg_index', value: '0' }] };
+        expect(decodeEvent(withIndex)).toEqual(decodeEvent(event));
+    });
+});
+
+describe('encodeEvent', () => {
+    it('emits the attributes of the node', () => {
+        for (const event of events) {
+            const { message } = decodeEvent(event)!;
+            const encoded = encodeEvent(event.type, message);
+            const attributes = encoded.attributes.map(({ key, value }) => [key, JSON.parse(value)]);
+            const expected = event.attributes.map(({ key, value }) => [key, JSON.parse(value)]);
+            expect(attributes).toEqual(expected);
+        }
+    });
+
+    it('rejects unknown types', () => {
+        expect(() => encodeEvent('tacchain.bridge.v1.EventUnknown', {})).toThrow();
+    });
+});
@@ -0,0 +1,144 @@
+// localnet runs a local network of 4 validators in docker containers, one per
+// validator, from the tacchaind image. The homes of the validators are
+// initialized on the host by contrib/localnet/init-multi-node.sh and mounted
+// in the containers, the ports of validator i are 451<i+1>0 to 451<i+1>9 on
+// the host like with the script alone. See the network package to run it from
+// Go code.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Asphere-xyz/tacchain/cmd/localnet/network"
+)
+
+const (
+	flagHome    = "home"
+	flagChainID = "chain-id"
+	flagImage   = "image"
+	flagBinary  = "binary"
+	flagRoot    = "root"
+	flagTimeout = "timeout"
+
+	defaultHome    = ".localnet"
+	defaultTimeout = 2 * time.Minute
+)
+
+func main() {
+	if err := NewRootCmd().Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// NewRootCmd returns the localnet command.
+func NewRootCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "localnet",
+		Short:        "Run a local network of dockerized validators",
+		SilenceUsage: true,
+	}
+	cmd.PersistentFlags().String(flagHome, defaultHome, "Directory holding the homes of the validators and the compose file")
+	cmd.PersistentFlags().String(flagChainID, network.DefaultChainID, "Chain id of the network")
+	cmd.PersistentFlags().String(flagImage, network.DefaultImage, "Docker image running tacchaind")
+	cmd.PersistentFlags().String(flagBinary, "tacchaind", "tacchaind binary initializing the homes, the version of the image")
+	cmd.PersistentFlags().String(flagRoot, ".", "Root of the tacchain repository, holding contrib/localnet")
+	cmd.PersistentFlags().Duration(flagTimeout, defaultTimeout, "Timeout of the command")
+
+	cmd.AddCommand(initCmd(), upCmd(), downCmd(), composeCmd())
+	return cmd
+}
+
+func initCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init",
+		Short: "Initialize the homes of the validators, removing the previous network, and write the compose file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return run(cmd, func(ctx context.Context, cfg network.Config) error {
+				return cfg.Init(ctx)
+			})
+		},
+	}
+}
+
+func upCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "up",
+		Short: "Start the validators, initializing the network unless it has a compose file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return run(cmd, func(ctx context.Context, cfg network.Config) error {
+				if _, err := os.Stat(filepath.Join(cfg.HomeDir, network.ComposeFile)); os.IsNotExist(err) {
+					if err := cfg.Init(ctx); err != nil {
+						return err
+					}
+				}
+				if err := cfg.Up(ctx); err != nil {
+					return err
+				}
+				for i := 0; i < network.Validators; i++ {
+					rpc, _ := network.Port(i, "rpc")
+					jsonRPC, _ := network.Port(i, "json-rpc")
+					fmt.Fprintf(cmd.OutOrStdout(), "%s: rpc http://127.0.0.1:%d, json-rpc http://127.0.0.1:%d, home %s\n",
+						network.NodeName(i), rpc, jsonRPC, cfg.NodeHome(i))
+				}
+				return nil
+			})
+		},
+	}
+}
+
+func downCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "down",
+		Short: "Stop and remove the containers of the validators, keeping their homes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return run(cmd, func(ctx context.Context, cfg network.Config) error {
+				return cfg.Down(ctx)
+			})
+		},
+	}
+}
+
+func composeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "compose",
+		Short: "Print the docker compose file of the network",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return run(cmd, func(_ context.Context, cfg network.Config) error {
+				bz, err := cfg.Compose()
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+				return err
+			})
+		},
+	}
+}
+
+// run runs f with the config of the flags of cmd
+func run(cmd *cobra.Command, f func(ctx context.Context, cfg network.Config) error) error {
+	flags := cmd.Flags()
+	root, _ := flags.GetString(flagRoot)
+	home, _ := flags.GetString(flagHome)
+	timeout, _ := flags.GetDuration(flagTimeout)
+
+	cfg := network.DefaultConfig(root, home)
+	cfg.ChainID, _ = flags.GetString(flagChainID)
+	cfg.Image, _ = flags.GetString(flagImage)
+	cfg.Binary, _ = flags.GetString(flagBinary)
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	defer cancel()
+	return f(ctx, cfg)
+}
@@ -0,0 +1,284 @@
+// Package network sets up a local network of dockerized validators: it
+// initializes the homes of the validators with
+// contrib/localnet/init-multi-node.sh, adapts their configs to run in
+// containers and writes the docker compose file running them from the
+// tacchaind image, each container mounting the home of its validator and
+// exposing its ports on the host.
+package network
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Validators is the number of validators init-multi-node.sh sets up
+	Validators = 4
+
+	DefaultChainID = "tacchain_239-1"
+	DefaultImage   = "tacchaind:latest"
+
+	// ComposeFile is the name of the docker compose file in the home of the
+	// network. docker compose reads JSON as the YAML it is a subset of.
+	ComposeFile = "docker-compose.json"
+
+	// containerHome is where the home of a validator is mounted in its container
+	containerHome = "/tacchaind"
+)
+
+// portNames are the services of a validator in the order of the last digit of
+// their port, see Port
+var portNames = []string{"p2p", "rpc", "api", "metrics", "pprof", "prometheus", "grpc-web", "grpc", "json-rpc", "json-ws"}
+
+// Config of a local network
+type Config struct {
+	// ChainID of the network
+	ChainID string
+	// HomeDir holds the home of validator i in node<i>
+	HomeDir string
+	// Image is the docker image running tacchaind
+	Image string
+	// Binary is the tacchaind binary initializing the homes on the host, it
+	// must be the version of the image
+	Binary string
+	// InitScript is the path of contrib/localnet/init-multi-node.sh
+	InitScript string
+}
+
+// DefaultConfig returns the config of a network in home, initialized with the
+// tacchaind of the PATH and the script of the repository at root
+func DefaultConfig(root, home string) Config {
+	return Config{
+		ChainID:    DefaultChainID,
+		HomeDir:    home,
+		Image:      DefaultImage,
+		Binary:     "tacchaind",
+		InitScript: filepath.Join(root, "contrib", "localnet", "init-multi-node.sh"),
+	}
+}
+
+// NodeName returns the name of validator i, its home directory, compose
+// service and host name on the docker network
+func NodeName(i int) string {
+	return fmt.Sprintf("node%d", i)
+}
+
+// NodeHome returns the home of validator i on the host
+func (c Config) NodeHome(i int) string {
+	return filepath.Join(c.HomeDir, NodeName(i))
+}
+
+// Port returns the port of service name of validator i: init-multi-node.sh
+// gives validator i the ports 451<i+1>0 to 451<i+1>9. The containers listen on
+// the same ports as the host, so the addresses in the configs work on both.
+func Port(i int, name string) (int, error) {
+	for digit, portName := range portNames {
+		if portName == name {
+			return 45100 + 10*(i+1) + digit, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown port %s, the ports are %s", name, strings.Join(portNames, ", "))
+}
+
+// Init initializes the homes of the validators, removing any previous
+// network in HomeDir, and writes the compose file.
+func (c Config) Init(ctx context.Context) error {
+	binary, err := exec.LookPath(c.Binary)
+	if err != nil {
+		return fmt.Errorf("tacchaind binary not found: %v", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "bash", c.InitScript)
+	// the script asks to confirm the removal of the home
+	cmd.Stdin = strings.NewReader("y\n")
+	cmd.Env = append(os.Environ(), "HOMEDIR="+c.HomeDir, "CHAIN_ID="+c.ChainID, "TACCHAIND="+binary)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to initialize network %s: %v: %s", c.ChainID, err, output.String())
+	}
+
+	for i := 0; i < Validators; i++ {
+		if err := c.configureNode(i); err != nil {
+			return err
+		}
+	}
+	_, err = c.WriteComposeFile()
+	return err
+}
+
+// configureNode adapts the configs of validator i written for a single host
+// to a container: the node listens on all interfaces and dials its peers by
+// their host names on the docker network.
+func (c Config) configureNode(i int) error {
+	for _, file := range []string{"config.toml", "app.toml"} {
+		path := filepath.Join(c.NodeHome(i), "config", file)
+		bz, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(containerConfig(string(bz))), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	// listenRegexp matches the addresses a node listens on, not the ones it
+	// dials like proxy_app
+	listenRegexp = regexp.MustCompile(`(?m)^((?:laddr|address|ws-address|metrics-address|prometheus_listen_addr|pprof_laddr) = "(?:tcp://)?)(?:127\.0\.0\.1|localhost)(:[0-9]+")`)
+	// peerRegexp matches the peers of persistent_peers and seeds, validator i
+	// listens for p2p at 451<i+1>0
+	peerRegexp = regexp.MustCompile(`@127\.0\.0\.1:451([1-9])0\b`)
+)
+
+func containerConfig(config string) string {
+	config = listenRegexp.ReplaceAllString(config, "${1}0.0.0.0${2}")
+	return peerRegexp.ReplaceAllStringFunc(config, func(peer string) string {
+		digit, _ := strconv.Atoi(peerRegexp.FindStringSubmatch(peer)[1])
+		port, _ := Port(digit-1, "p2p")
+		return fmt.Sprintf("@%s:%d", NodeName(digit-1), port)
+	})
+}
+
+// composeService is a service of a docker compose file
+type composeService struct {
+	Image         string   `json:"image"`
+	ContainerName string   `json:"container_name"`
+	Hostname      string   `json:"hostname"`
+	Command       []string `json:"command"`
+	Volumes       []string `json:"volumes"`
+	Ports         []string `json:"ports"`
+}
+
+// Compose returns the docker compose file running the validators
+func (c Config) Compose() ([]byte, error) {
+	home, err := filepath.Abs(c.HomeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make(map[string]composeService, Validators)
+	for i := 0; i < Validators; i++ {
+		var ports []string
+		for _, name := range portNames {
+			port, err := Port(i, name)
+			if err != nil {
+				return nil, err
+			}
+			ports = append(ports, fmt.Sprintf("%d:%d", port, port))
+		}
+		services[NodeName(i)] = composeService{
+			Image:         c.Image,
+			ContainerName: fmt.Sprintf("%s-%s", c.ChainID, NodeName(i)),
+			Hostname:      NodeName(i),
+			Command:       []string{"tacchaind", "start", "--home", containerHome, "--chain-id", c.ChainID},
+			Volumes:       []string{fmt.Sprintf("%s:%s", filepath.Join(home, NodeName(i)), containerHome)},
+			Ports:         ports,
+		}
+	}
+	return json.MarshalIndent(map[string]any{
+		"name":     strings.ReplaceAll(c.ChainID, "_", "-"),
+		"services": services,
+	}, "", "  ")
+}
+
+// WriteComposeFile writes the compose file in HomeDir and returns its path
+func (c Config) WriteComposeFile() (string, error) {
+	bz, err := c.Compose()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(c.HomeDir, ComposeFile)
+	return path, os.WriteFile(path, bz, 0o644)
+}
+
+// Up starts the containers of the validators and waits until the network
+// produces blocks
+func (c Config) Up(ctx context.Context) error {
+	if err := c.compose(ctx, "up", "--detach"); err != nil {
+		return err
+	}
+	return c.WaitForHeight(ctx, 0, 2)
+}
+
+// Down stops and removes the containers, the homes are kept
+func (c Config) Down(ctx context.Context) error {
+	return c.compose(ctx, "down")
+}
+
+func (c Config) compose(ctx context.Context, args ...string) error {
+	path := filepath.Join(c.HomeDir, ComposeFile)
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("no compose file in %s, initialize the network first: %v", c.HomeDir, err)
+	}
+
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"compose", "--file", path}, args...)...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("docker compose %s: %v: %s", strings.Join(args, " "), err, output.String())
+	}
+	return nil
+}
+
+// Height returns the latest height of validator i, from its rpc port on the host
+func (c Config) Height(ctx context.Context, i int) (int64, error) {
+	port, err := Port(i, "rpc")
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://127.0.0.1:%d/status", port), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	bz, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	var status struct {
+		Result struct {
+			SyncInfo struct {
+				LatestBlockHeight string `json:"latest_block_height"`
+			} `json:"sync_info"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(bz, &status); err != nil {
+		return 0, fmt.Errorf("invalid status of %s: %v", NodeName(i), err)
+	}
+	return strconv.ParseInt(status.Result.SyncInfo.LatestBlockHeight, 10, 64)
+}
+
+// WaitForHeight blocks until validator i reached height
+func (c Config) WaitForHeight(ctx context.Context, i int, height int64) error {
+	for {
+		if latest, err := c.Height(ctx, i); err == nil && latest >= height {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s didn't reach height %d: %v", NodeName(i), height, ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+}
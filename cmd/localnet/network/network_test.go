@@ -0,0 +1,71 @@
+package network
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPort(t *testing.T) {
+	port, err := Port(0, "p2p")
+	require.NoError(t, err)
+	require.Equal(t, 45110, port)
+	port, err = Port(3, "json-rpc")
+	require.NoError(t, err)
+	require.Equal(t, 45148, port)
+	_, err = Port(0, "proxy")
+	require.Error(t, err)
+}
+
+func TestContainerConfig(t *testing.T) {
+	config := `proxy_app = "tcp://127.0.0.1:26658"
+[rpc]
+laddr = "tcp://127.0.0.1:45121"
+pprof_laddr = "localhost:45124"
+[p2p]
+laddr = "tcp://0.0.0.0:45120"
+persistent_peers = "a@127.0.0.1:45110,c@127.0.0.1:45130,d@127.0.0.1:45140"
+[grpc]
+address = "localhost:45127"
+[json-rpc]
+address = "127.0.0.1:45128"
+ws-address = "127.0.0.1:45129"
+`
+	require.Equal(t, `proxy_app = "tcp://127.0.0.1:26658"
+[rpc]
+laddr = "tcp://0.0.0.0:45121"
+pprof_laddr = "0.0.0.0:45124"
+[p2p]
+laddr = "tcp://0.0.0.0:45120"
+persistent_peers = "a@node0:45110,c@node2:45130,d@node3:45140"
+[grpc]
+address = "0.0.0.0:45127"
+[json-rpc]
+address = "0.0.0.0:45128"
+ws-address = "0.0.0.0:45129"
+`, containerConfig(config))
+}
+
+func TestCompose(t *testing.T) {
+	cfg := DefaultConfig("/repo", "/tmp/localnet")
+	bz, err := cfg.Compose()
+	require.NoError(t, err)
+
+	var compose struct {
+		Name     string                    `json:"name"`
+		Services map[string]composeService `json:"services"`
+	}
+	require.NoError(t, json.Unmarshal(bz, &compose))
+	require.Equal(t, "tacchain-239-1", compose.Name)
+	require.Len(t, compose.Services, Validators)
+
+	node1 := compose.Services["node1"]
+	require.Equal(t, DefaultImage, node1.Image)
+	require.Equal(t, "node1", node1.Hostname)
+	require.Equal(t, []string{"/tmp/localnet/node1:/tacchaind"}, node1.Volumes)
+	require.Equal(t, []string{"tacchaind", "start", "--home", "/tacchaind", "--chain-id", DefaultChainID}, node1.Command)
+	require.Len(t, node1.Ports, len(portNames))
+	require.Equal(t, "45120:45120", node1.Ports[0])
+	require.Equal(t, "45129:45129", node1.Ports[len(portNames)-1])
+}
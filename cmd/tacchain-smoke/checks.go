@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	validatorKey = "validator"
+	recipientKey = "recipient"
+
+	initialBalance = "2000000000000000000000utac"
+	initialStake   = "1000000000000000000000utac"
+	sendAmount     = "1000"
+
+	// deployGas covers the deployment of the contract of deployCode
+	deployGas = 100000
+)
+
+var (
+	// deployCode deploys a contract returning 42 to every call
+	deployCode = mustDecodeHex("69602a60005260206000f3600052600a6016f3")
+	// contractCode is the code of the contract deployed by deployCode
+	contractCode = mustDecodeHex("602a60005260206000f3")
+)
+
+func mustDecodeHex(s string) []byte {
+	bz, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// checkInit initializes the home of the node with a funded validator
+func checkInit(ctx context.Context, n *node) error {
+	if _, err := n.exec(ctx, "init", "smoke", "--chain-id", n.chainID, "--default-denom", denom); err != nil {
+		return err
+	}
+	if err := n.patchGenesis(); err != nil {
+		return err
+	}
+	if err := n.setTOMLValue("app.toml", "json-rpc", "enable", "true"); err != nil {
+		return err
+	}
+	for _, file := range []string{"config.toml", "app.toml"} {
+		if err := n.shiftPorts(file); err != nil {
+			return err
+		}
+	}
+
+	if _, err := n.addKey(ctx, validatorKey); err != nil {
+		return err
+	}
+	if _, err := n.exec(ctx, "genesis", "add-genesis-account", validatorKey, initialBalance, "--keyring-backend", keyringBackend); err != nil {
+		return err
+	}
+	if _, err := n.exec(ctx, "genesis", "gentx", validatorKey, initialStake, "--chain-id", n.chainID, "--keyring-backend", keyringBackend, "--gas-prices", gasPrices); err != nil {
+		return err
+	}
+	if _, err := n.exec(ctx, "genesis", "collect-gentxs"); err != nil {
+		return err
+	}
+	_, err := n.exec(ctx, "genesis", "validate")
+	return err
+}
+
+// checkStart starts the node and waits for its first block
+func checkStart(ctx context.Context, n *node) error {
+	if err := n.start(); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	return n.waitForHeight(ctx, 1)
+}
+
+// checkBlockProduction checks the node keeps producing blocks
+func checkBlockProduction(ctx context.Context, n *node) error {
+	height, err := n.height(ctx)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	return n.waitForHeight(ctx, height+3)
+}
+
+// checkBankSend sends funds to a new account
+func checkBankSend(ctx context.Context, n *node) error {
+	recipient, err := n.addKey(ctx, recipientKey)
+	if err != nil {
+		return err
+	}
+	if _, err := n.tx(ctx, validatorKey, "bank", "send", validatorKey, recipient, sendAmount+denom); err != nil {
+		return err
+	}
+
+	var res struct {
+		Balance struct {
+			Amount string `json:"amount"`
+		} `json:"balance"`
+	}
+	if err := n.query(ctx, &res, "bank", "balance", recipient, denom); err != nil {
+		return err
+	}
+	if res.Balance.Amount != sendAmount {
+		return fmt.Errorf("recipient balance is %s%s, expected %s%s", res.Balance.Amount, denom, sendAmount, denom)
+	}
+	return nil
+}
+
+// checkEVMDeploy deploys a contract over JSON-RPC and calls it
+func checkEVMDeploy(ctx context.Context, n *node) error {
+	output, err := n.exec(ctx, "keys", "unsafe-export-eth-key", validatorKey, "--keyring-backend", keyringBackend)
+	if err != nil {
+		return err
+	}
+	key, err := crypto.HexToECDSA(strings.TrimSpace(output))
+	if err != nil {
+		return fmt.Errorf("failed to parse eth key: %v", err)
+	}
+
+	client, err := ethclient.DialContext(ctx, n.jsonRPCAddress())
+	if err != nil {
+		return fmt.Errorf("failed to dial json-rpc: %v", err)
+	}
+	defer client.Close()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain id: %v", err)
+	}
+	nonce, err := client.PendingNonceAt(ctx, crypto.PubkeyToAddress(key.PublicKey))
+	if err != nil {
+		return fmt.Errorf("failed to get nonce: %v", err)
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get gas price: %v", err)
+	}
+	tx, err := gethtypes.SignTx(gethtypes.NewTx(&gethtypes.LegacyTx{
+		Nonce:    nonce,
+		Gas:      deployGas,
+		GasPrice: gasPrice,
+		Data:     deployCode,
+	}), gethtypes.LatestSignerForChainID(chainID), key)
+	if err != nil {
+		return err
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		return fmt.Errorf("failed to send deploy tx: %v", err)
+	}
+
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		return fmt.Errorf("deploy tx %s was not mined: %v", tx.Hash(), err)
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("deploy tx %s failed", tx.Hash())
+	}
+
+	code, err := client.CodeAt(ctx, receipt.ContractAddress, nil)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(code, contractCode) {
+		return fmt.Errorf("contract %s has code %x, expected %x", receipt.ContractAddress, code, contractCode)
+	}
+	res, err := client.CallContract(ctx, ethereum.CallMsg{To: &receipt.ContractAddress}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to call contract %s: %v", receipt.ContractAddress, err)
+	}
+	if new(big.Int).SetBytes(res).Int64() != 42 {
+		return fmt.Errorf("contract %s returned %x, expected 42", receipt.ContractAddress, res)
+	}
+	return nil
+}
+
+// checkGovVote submits a text proposal, votes yes with the validator and waits
+// for the proposal to pass at the end of the voting period.
+func checkGovVote(ctx context.Context, n *node) error {
+	proposal, err := json.Marshal(map[string]any{
+		"messages": []any{},
+		"metadata": "",
+		"deposit":  minDeposit + denom,
+		"title":    "Smoke test",
+		"summary":  "Text proposal of the release smoke test",
+	})
+	if err != nil {
+		return err
+	}
+	proposalFile := filepath.Join(n.home, "proposal.json")
+	if err := os.WriteFile(proposalFile, proposal, 0o644); err != nil {
+		return err
+	}
+
+	res, err := n.tx(ctx, validatorKey, "gov", "submit-proposal", proposalFile)
+	if err != nil {
+		return err
+	}
+	proposalID, err := eventAttribute(res.Events, "submit_proposal", "proposal_id")
+	if err != nil {
+		return err
+	}
+	if _, err := n.tx(ctx, validatorKey, "gov", "vote", proposalID, "yes"); err != nil {
+		return err
+	}
+
+	for {
+		var res struct {
+			Proposal struct {
+				Status string `json:"status"`
+			} `json:"proposal"`
+		}
+		if err := n.query(ctx, &res, "gov", "proposal", proposalID); err != nil {
+			return err
+		}
+		switch res.Proposal.Status {
+		case "PROPOSAL_STATUS_PASSED":
+			return nil
+		case "PROPOSAL_STATUS_REJECTED", "PROPOSAL_STATUS_FAILED":
+			return fmt.Errorf("proposal %s ended with status %s", proposalID, res.Proposal.Status)
+		}
+		if err := sleep(ctx, time.Second); err != nil {
+			return fmt.Errorf("proposal %s did not pass: %v", proposalID, err)
+		}
+	}
+}
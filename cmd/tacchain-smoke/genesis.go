@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+const (
+	// votingPeriod is short enough for the gov check to wait for the end of the vote
+	votingPeriod          = "10s"
+	expeditedVotingPeriod = "5s"
+	minDeposit            = "10000000000000000"
+	expeditedMinDeposit   = "50000000000000000"
+)
+
+// evmChainIDRegexp matches the EIP-155 chain id in a chain id like tacchain_2391-1
+var evmChainIDRegexp = regexp.MustCompile(`^[a-z0-9]+_([1-9][0-9]*)-[1-9][0-9]*$`)
+
+// patchGenesis sets the TAC denom and EVM chain id in the genesis of the node,
+// along with a voting period short enough for the smoke test.
+func (n *node) patchGenesis() error {
+	matches := evmChainIDRegexp.FindStringSubmatch(n.chainID)
+	if matches == nil {
+		return fmt.Errorf("invalid chain id %s, expected <name>_<evm chain id>-<version>", n.chainID)
+	}
+
+	genesisPath := filepath.Join(n.home, "config", "genesis.json")
+	bz, err := os.ReadFile(genesisPath)
+	if err != nil {
+		return err
+	}
+	var genesis map[string]any
+	if err := json.Unmarshal(bz, &genesis); err != nil {
+		return fmt.Errorf("failed to decode genesis: %v", err)
+	}
+	appState, ok := genesis["app_state"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("genesis has no app_state")
+	}
+	if err := patchAppState(appState, matches[1]); err != nil {
+		return err
+	}
+
+	if bz, err = json.MarshalIndent(genesis, "", "  "); err != nil {
+		return err
+	}
+	return os.WriteFile(genesisPath, bz, 0o644)
+}
+
+// patchAppState applies the settings of contrib/localnet/init.sh the smoke
+// test relies on to the default app state.
+func patchAppState(appState map[string]any, evmChainID string) error {
+	// the EVM modules default to the atest denom and chain id 262144
+	replaceValues(appState, "denom", "atest", denom)
+	replaceValues(appState, "evm_denom", "atest", denom)
+	replaceValues(appState, "chain_id", "262144", evmChainID)
+
+	bank, ok := appState["bank"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("genesis has no bank state")
+	}
+	bank["denom_metadata"] = []any{map[string]any{
+		"description": "The native staking token for tacchaind.",
+		"denom_units": []any{
+			map[string]any{"denom": denom, "exponent": 0, "aliases": []any{}},
+			map[string]any{"denom": "tac", "exponent": 18, "aliases": []any{}},
+		},
+		"base":     denom,
+		"display":  "tac",
+		"name":     "TAC Token",
+		"symbol":   "TAC",
+		"uri":      "",
+		"uri_hash": "",
+	}}
+
+	gov, ok := appState["gov"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("genesis has no gov state")
+	}
+	params, ok := gov["params"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("genesis has no gov params")
+	}
+	params["voting_period"] = votingPeriod
+	params["expedited_voting_period"] = expeditedVotingPeriod
+	params["min_deposit"] = []any{map[string]any{"denom": denom, "amount": minDeposit}}
+	params["expedited_min_deposit"] = []any{map[string]any{"denom": denom, "amount": expeditedMinDeposit}}
+	return nil
+}
+
+// replaceValues replaces the string value old of every key in v by new
+func replaceValues(v any, key, old, new string) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, value := range v {
+			if s, ok := value.(string); ok && k == key && s == old {
+				v[k] = new
+				continue
+			}
+			replaceValues(value, key, old, new)
+		}
+	case []any:
+		for _, value := range v {
+			replaceValues(value, key, old, new)
+		}
+	}
+}
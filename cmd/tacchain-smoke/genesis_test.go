@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testGenesis = `{
+  "chain_id": "tacchain_2391-1",
+  "app_state": {
+    "bank": {"denom_metadata": []},
+    "evm": {"params": {"evm_denom": "atest", "chain_config": {"chain_id": "262144", "denom": "atest"}}},
+    "erc20": {"token_pairs": [{"denom": "atest"}, {"denom": "other"}]},
+    "gov": {"params": {"voting_period": "172800s", "expedited_voting_period": "86400s"}}
+  }
+}`
+
+func TestPatchGenesis(t *testing.T) {
+	n := &node{home: t.TempDir(), chainID: "tacchain_2391-1"}
+	genesisPath := filepath.Join(n.home, "config", "genesis.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(genesisPath), 0o755))
+	require.NoError(t, os.WriteFile(genesisPath, []byte(testGenesis), 0o644))
+
+	require.NoError(t, n.patchGenesis())
+
+	bz, err := os.ReadFile(genesisPath)
+	require.NoError(t, err)
+	var genesis struct {
+		ChainID  string `json:"chain_id"`
+		AppState struct {
+			Bank struct {
+				DenomMetadata []struct {
+					Base string `json:"base"`
+				} `json:"denom_metadata"`
+			} `json:"bank"`
+			EVM struct {
+				Params struct {
+					EVMDenom    string `json:"evm_denom"`
+					ChainConfig struct {
+						ChainID string `json:"chain_id"`
+						Denom   string `json:"denom"`
+					} `json:"chain_config"`
+				} `json:"params"`
+			} `json:"evm"`
+			ERC20 struct {
+				TokenPairs []struct {
+					Denom string `json:"denom"`
+				} `json:"token_pairs"`
+			} `json:"erc20"`
+			Gov struct {
+				Params struct {
+					VotingPeriod string `json:"voting_period"`
+					MinDeposit   []struct {
+						Denom  string `json:"denom"`
+						Amount string `json:"amount"`
+					} `json:"min_deposit"`
+				} `json:"params"`
+			} `json:"gov"`
+		} `json:"app_state"`
+	}
+	require.NoError(t, json.Unmarshal(bz, &genesis))
+
+	require.Equal(t, "tacchain_2391-1", genesis.ChainID)
+	require.Equal(t, denom, genesis.AppState.EVM.Params.EVMDenom)
+	require.Equal(t, "2391", genesis.AppState.EVM.Params.ChainConfig.ChainID)
+	require.Equal(t, denom, genesis.AppState.EVM.Params.ChainConfig.Denom)
+	require.Equal(t, denom, genesis.AppState.ERC20.TokenPairs[0].Denom)
+	require.Equal(t, "other", genesis.AppState.ERC20.TokenPairs[1].Denom)
+	require.Len(t, genesis.AppState.Bank.DenomMetadata, 1)
+	require.Equal(t, denom, genesis.AppState.Bank.DenomMetadata[0].Base)
+	require.Equal(t, votingPeriod, genesis.AppState.Gov.Params.VotingPeriod)
+	require.Len(t, genesis.AppState.Gov.Params.MinDeposit, 1)
+	require.Equal(t, minDeposit, genesis.AppState.Gov.Params.MinDeposit[0].Amount)
+
+	for _, chainID := range []string{"tacchain", "tacchain_2391", "tacchain_abc-1", "tacchain_0-1"} {
+		n.chainID = chainID
+		require.Error(t, n.patchGenesis(), chainID)
+	}
+}
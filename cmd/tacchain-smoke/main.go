@@ -0,0 +1,140 @@
+// tacchain-smoke runs the key checks of a release against a tacchaind binary:
+// it initializes and starts a single validator network, then checks block
+// production, a bank send, an EVM contract deployment and a governance vote.
+// It only needs the binary under test, not the e2e suite or a Go toolchain.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagBinary     = "binary"
+	flagHome       = "home"
+	flagChainID    = "chain-id"
+	flagPortOffset = "port-offset"
+	flagTimeout    = "timeout"
+	flagKeepHome   = "keep-home"
+
+	defaultChainID = "tacchain_2391-1"
+	defaultTimeout = 5 * time.Minute
+)
+
+// check is a step of the smoke test, later checks depend on the earlier ones
+type check struct {
+	name string
+	run  func(ctx context.Context, n *node) error
+}
+
+var checks = []check{
+	{"init", checkInit},
+	{"start", checkStart},
+	{"block-production", checkBlockProduction},
+	{"bank-send", checkBankSend},
+	{"evm-deploy", checkEVMDeploy},
+	{"gov-vote", checkGovVote},
+}
+
+func main() {
+	if err := NewRootCmd().Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// NewRootCmd returns the command running the smoke test.
+func NewRootCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tacchain-smoke",
+		Short: "Smoke test a tacchaind release",
+		Long: `Smoke test a tacchaind release.
+
+Initializes a single validator network in a temporary home with the given binary,
+starts it and checks block production, a bank send, an EVM contract deployment and
+a governance vote, stopping at the first failed check. The home is kept on failure,
+the log of the node is node.log in it.
+
+$ tacchain-smoke --binary ./tacchaind-v1.2.0-rc1`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			binary, err := cmd.Flags().GetString(flagBinary)
+			if err != nil {
+				return err
+			}
+			home, err := cmd.Flags().GetString(flagHome)
+			if err != nil {
+				return err
+			}
+			chainID, err := cmd.Flags().GetString(flagChainID)
+			if err != nil {
+				return err
+			}
+			portOffset, err := cmd.Flags().GetInt(flagPortOffset)
+			if err != nil {
+				return err
+			}
+			timeout, err := cmd.Flags().GetDuration(flagTimeout)
+			if err != nil {
+				return err
+			}
+			keepHome, err := cmd.Flags().GetBool(flagKeepHome)
+			if err != nil {
+				return err
+			}
+
+			if home == "" {
+				if home, err = os.MkdirTemp("", "tacchain-smoke"); err != nil {
+					return err
+				}
+			} else if entries, err := os.ReadDir(home); err == nil && len(entries) > 0 {
+				return fmt.Errorf("home %s is not empty", home)
+			}
+
+			n := &node{binary: binary, home: home, chainID: chainID, portOffset: portOffset}
+			defer n.stop()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+			if err := runChecks(ctx, cmd, n); err != nil {
+				cmd.PrintErrf("home kept at %s\n", home)
+				return err
+			}
+
+			if !keepHome {
+				n.stop()
+				return os.RemoveAll(home)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagBinary, "tacchaind", "Path of the tacchaind binary under test")
+	cmd.Flags().String(flagHome, "", "Empty home directory of the node, a temporary directory if unset")
+	cmd.Flags().String(flagChainID, defaultChainID, "Chain id of the network")
+	cmd.Flags().Int(flagPortOffset, 0, "Offset added to the default ports of the node, to run next to another node")
+	cmd.Flags().Duration(flagTimeout, defaultTimeout, "Timeout of the whole smoke test")
+	cmd.Flags().Bool(flagKeepHome, false, "Keep the home directory when all checks pass")
+
+	return cmd
+}
+
+// runChecks runs the checks in order and prints their outcome, it returns the
+// error of the first check failing.
+func runChecks(ctx context.Context, cmd *cobra.Command, n *node) error {
+	for _, c := range checks {
+		start := time.Now()
+		if err := c.run(ctx, n); err != nil {
+			cmd.Printf("FAIL %-16s %s\n", c.name, time.Since(start).Round(time.Millisecond))
+			return fmt.Errorf("%s: %w", c.name, err)
+		}
+		cmd.Printf("ok   %-16s %s\n", c.name, time.Since(start).Round(time.Millisecond))
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	keyringBackend = "test"
+	denom          = "utac"
+	gasPrices      = "100000000000utac"
+)
+
+// defaultPorts are the ports of a node initialized by tacchaind init
+var defaultPorts = map[string]int{
+	"rpc":        26657,
+	"p2p":        26656,
+	"proxy":      26658,
+	"prometheus": 26660,
+	"pprof":      6060,
+	"grpc":       9090,
+	"grpc-web":   9091,
+	"api":        1317,
+	"json-rpc":   8545,
+	"json-ws":    8546,
+	"metrics":    6065,
+}
+
+// node is a single validator network run by the tacchaind binary under test
+type node struct {
+	binary     string
+	home       string
+	chainID    string
+	portOffset int
+
+	cmd *exec.Cmd
+	// exited is closed when the node process exited, with the error of the exit in exitErr
+	exited  chan struct{}
+	exitErr error
+}
+
+func (n *node) port(name string) int {
+	return defaultPorts[name] + n.portOffset
+}
+
+func (n *node) rpcAddress() string {
+	return fmt.Sprintf("tcp://127.0.0.1:%d", n.port("rpc"))
+}
+
+func (n *node) jsonRPCAddress() string {
+	return fmt.Sprintf("http://127.0.0.1:%d", n.port("json-rpc"))
+}
+
+// exec runs the binary with the home of the node and returns its stdout
+func (n *node) exec(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, n.binary, append(args, "--home", n.home)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("tacchaind %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
+
+// query runs a query against the node and decodes its json output into res
+func (n *node) query(ctx context.Context, res any, args ...string) error {
+	output, err := n.exec(ctx, append(append([]string{"q"}, args...), "--node", n.rpcAddress(), "--output", "json")...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(output), res); err != nil {
+		return fmt.Errorf("failed to decode the output of q %s: %v: %s", strings.Join(args, " "), err, output)
+	}
+	return nil
+}
+
+type txEvent struct {
+	Type       string `json:"type"`
+	Attributes []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"attributes"`
+}
+
+type txResponse struct {
+	Height string    `json:"height"`
+	TxHash string    `json:"txhash"`
+	Code   uint32    `json:"code"`
+	RawLog string    `json:"raw_log"`
+	Events []txEvent `json:"events"`
+}
+
+// tx broadcasts a tx signed by from and waits for it to be included in a block
+func (n *node) tx(ctx context.Context, from string, args ...string) (*txResponse, error) {
+	args = append(append([]string{"tx"}, args...),
+		"--from", from,
+		"--chain-id", n.chainID,
+		"--keyring-backend", keyringBackend,
+		"--node", n.rpcAddress(),
+		"--gas", "auto",
+		"--gas-adjustment", "1.5",
+		"--gas-prices", gasPrices,
+		"--output", "json",
+		"-y",
+	)
+	output, err := n.exec(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var res txResponse
+	if err := json.Unmarshal([]byte(output), &res); err != nil {
+		return nil, fmt.Errorf("failed to decode tx response: %v: %s", err, output)
+	}
+	if res.Code != 0 {
+		return nil, fmt.Errorf("tx %s failed with code %d: %s", res.TxHash, res.Code, res.RawLog)
+	}
+
+	for {
+		var included txResponse
+		if err := n.query(ctx, &included, "tx", res.TxHash); err == nil {
+			if included.Code != 0 {
+				return nil, fmt.Errorf("tx %s failed with code %d: %s", res.TxHash, included.Code, included.RawLog)
+			}
+			return &included, nil
+		}
+		if err := sleep(ctx, time.Second); err != nil {
+			return nil, fmt.Errorf("tx %s was not included: %v", res.TxHash, err)
+		}
+	}
+}
+
+// addKey adds a new key to the test keyring of the node and returns its address
+func (n *node) addKey(ctx context.Context, name string) (string, error) {
+	output, err := n.exec(ctx, "keys", "add", name, "--keyring-backend", keyringBackend, "--output", "json")
+	if err != nil {
+		return "", err
+	}
+	var key struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal([]byte(output), &key); err != nil {
+		return "", fmt.Errorf("failed to decode key %s: %v", name, err)
+	}
+	return key.Address, nil
+}
+
+// height returns the latest block height of the node
+func (n *node) height(ctx context.Context) (int64, error) {
+	output, err := n.exec(ctx, "status", "--node", n.rpcAddress())
+	if err != nil {
+		return 0, err
+	}
+	var status struct {
+		SyncInfo struct {
+			LatestBlockHeight string `json:"latest_block_height"`
+		} `json:"sync_info"`
+	}
+	if err := json.Unmarshal([]byte(output), &status); err != nil {
+		return 0, fmt.Errorf("failed to decode status: %v: %s", err, output)
+	}
+	return strconv.ParseInt(status.SyncInfo.LatestBlockHeight, 10, 64)
+}
+
+// waitForHeight blocks until the node reached the given height
+func (n *node) waitForHeight(ctx context.Context, height int64) error {
+	for {
+		latest, err := n.height(ctx)
+		if err == nil && latest >= height {
+			return nil
+		}
+		select {
+		case <-n.exited:
+			return fmt.Errorf("node exited: %v, see %s", n.exitErr, n.logPath())
+		default:
+		}
+		if err := sleep(ctx, time.Second); err != nil {
+			return fmt.Errorf("node did not reach height %d: %v", height, err)
+		}
+	}
+}
+
+func (n *node) logPath() string {
+	return filepath.Join(n.home, "node.log")
+}
+
+// start starts the node in the background, logging to node.log in its home
+func (n *node) start() error {
+	logFile, err := os.Create(n.logPath())
+	if err != nil {
+		return err
+	}
+	n.cmd = exec.Command(n.binary, "start", "--home", n.home, "--chain-id", n.chainID)
+	n.cmd.Stdout = logFile
+	n.cmd.Stderr = logFile
+	if err := n.cmd.Start(); err != nil {
+		logFile.Close()
+		return fmt.Errorf("failed to start node: %v", err)
+	}
+
+	cmd, exited := n.cmd, make(chan struct{})
+	n.exited = exited
+	go func() {
+		n.exitErr = cmd.Wait()
+		logFile.Close()
+		close(exited)
+	}()
+	return nil
+}
+
+// stop kills the node if it is running
+func (n *node) stop() {
+	if n.cmd == nil || n.cmd.Process == nil {
+		return
+	}
+	_ = n.cmd.Process.Kill()
+	<-n.exited
+	n.cmd = nil
+}
+
+// setTOMLValue sets a key of the given section in a toml config file of the
+// node, an empty section sets a top level key.
+func (n *node) setTOMLValue(file, section, key, value string) error {
+	configPath := filepath.Join(n.home, "config", file)
+	bz, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+	updated, err := setTOMLValue(string(bz), section, key, value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return os.WriteFile(configPath, []byte(updated), 0o644)
+}
+
+func setTOMLValue(config, section, key, value string) (string, error) {
+	lines := strings.Split(config, "\n")
+	inSection := section == ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			inSection = trimmed == "["+section+"]"
+			continue
+		}
+		if inSection && strings.HasPrefix(trimmed, key+" =") {
+			lines[i] = fmt.Sprintf("%s = %s", key, value)
+			return strings.Join(lines, "\n"), nil
+		}
+	}
+	return "", fmt.Errorf("key %s not found in section [%s]", key, section)
+}
+
+// portRegexp matches the port of an address in a config file
+var portRegexp = regexp.MustCompile(`:([0-9]+)"`)
+
+// shiftPorts moves the default ports in a config file of the node by the port offset
+func (n *node) shiftPorts(file string) error {
+	if n.portOffset == 0 {
+		return nil
+	}
+	configPath := filepath.Join(n.home, "config", file)
+	bz, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+	ports := make(map[string]int, len(defaultPorts))
+	for name, port := range defaultPorts {
+		ports[strconv.Itoa(port)] = n.port(name)
+	}
+	// a single pass, so a shifted port is never shifted again as another default port
+	config := portRegexp.ReplaceAllStringFunc(string(bz), func(match string) string {
+		if port, ok := ports[portRegexp.FindStringSubmatch(match)[1]]; ok {
+			return fmt.Sprintf(":%d\"", port)
+		}
+		return match
+	})
+	return os.WriteFile(configPath, []byte(config), 0o644)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// eventAttribute returns the value of the first attribute key of an event of the given type
+func eventAttribute(events []txEvent, eventType, key string) (string, error) {
+	for _, event := range events {
+		if event.Type != eventType {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if attr.Key == key {
+				return attr.Value, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no %s attribute in the %s events", key, eventType)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTOMLValue(t *testing.T) {
+	config := "moniker = \"smoke\"\n\n[api]\nenable = false\n\n[json-rpc]\nenable = false\naddress = \"127.0.0.1:8545\"\n"
+
+	updated, err := setTOMLValue(config, "json-rpc", "enable", "true")
+	require.NoError(t, err)
+	require.Equal(t, "moniker = \"smoke\"\n\n[api]\nenable = false\n\n[json-rpc]\nenable = true\naddress = \"127.0.0.1:8545\"\n", updated)
+
+	updated, err = setTOMLValue(config, "", "moniker", "\"other\"")
+	require.NoError(t, err)
+	require.Contains(t, updated, "moniker = \"other\"\n")
+
+	_, err = setTOMLValue(config, "grpc", "enable", "true")
+	require.Error(t, err)
+}
+
+func TestShiftPorts(t *testing.T) {
+	n := &node{home: t.TempDir(), portOffset: 1}
+	configPath := filepath.Join(n.home, "config", "config.toml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0o755))
+	require.NoError(t, os.WriteFile(configPath, []byte("laddr = \"tcp://127.0.0.1:26657\"\nproxy_app = \"tcp://127.0.0.1:26658\"\nmax-txs = 8545\n"), 0o644))
+
+	require.NoError(t, n.shiftPorts("config.toml"))
+
+	bz, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.Equal(t, "laddr = \"tcp://127.0.0.1:26658\"\nproxy_app = \"tcp://127.0.0.1:26659\"\nmax-txs = 8545\n", string(bz))
+}
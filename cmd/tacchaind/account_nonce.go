@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+
+	evmtypes "github.com/cosmos/evm/x/vm/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+)
+
+// AccountNonceCmd returns the Cosmos sequence and the EVM nonce of an account
+// side by side, together with how the two relate.
+func AccountNonceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "account-nonce [address]",
+		Short: "Query the Cosmos sequence and the EVM nonce of an account",
+		Long: `Query the Cosmos sequence and the EVM nonce of an account, given as a bech32 or hex address.
+
+` + app.AccountNonceExplanation,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			addr, err := parseAddress(args[0])
+			if err != nil {
+				return err
+			}
+
+			acc, err := clientCtx.AccountRetriever.GetAccount(clientCtx, addr)
+			if err != nil {
+				return err
+			}
+
+			res, err := evmtypes.NewQueryClient(clientCtx).Account(cmd.Context(), &evmtypes.QueryAccountRequest{
+				Address: common.BytesToAddress(addr).Hex(),
+			})
+			if err != nil {
+				return err
+			}
+
+			bz, err := json.MarshalIndent(app.NewAccountNonce(addr, acc.GetAccountNumber(), acc.GetSequence(), res.Nonce), "", "  ")
+			if err != nil {
+				return err
+			}
+			return clientCtx.PrintRaw(bz)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
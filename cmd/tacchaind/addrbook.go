@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cometbft/cometbft/p2p"
+	"github.com/cometbft/cometbft/p2p/pex"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/server"
+)
+
+// AddrBookExport is the portable form of an address book, peers are given as
+// node_id@ip:port like in persistent_peers and seeds.
+type AddrBookExport struct {
+	Addrs []string `json:"addrs"`
+}
+
+// addrBookFile is the part of the CometBFT addrbook.json needed to export it
+type addrBookFile struct {
+	Addrs []struct {
+		Addr struct {
+			ID   string `json:"id"`
+			IP   string `json:"ip"`
+			Port uint16 `json:"port"`
+		} `json:"addr"`
+		LastSuccess time.Time `json:"last_success"`
+	} `json:"addrs"`
+}
+
+// AddrBookCmd exports and imports the address book of the node.
+func AddrBookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "addrbook",
+		Short: "Export and import the address book of the node",
+	}
+
+	cmd.AddCommand(
+		addrBookExportCmd(),
+		addrBookImportCmd(),
+	)
+
+	return cmd
+}
+
+func addrBookExportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export [file]",
+		Short: "Export the peers of the address book, to stdout if no file is given",
+		Long: `Export the peers of the address book, to stdout if no file is given.
+
+Peers that were reached most recently come first.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := server.GetServerContextFromCmd(cmd).Config
+
+			export, err := exportAddrBook(config.P2P.AddrBookFile())
+			if err != nil {
+				return err
+			}
+			bz, err := json.MarshalIndent(export, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				cmd.Println(string(bz))
+				return nil
+			}
+			return os.WriteFile(args[0], bz, 0o644)
+		},
+	}
+}
+
+func addrBookImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import [file]",
+		Short: "Add the peers of an exported address book to the address book of the node",
+		Long: `Add the peers of an exported address book to the address book of the node.
+
+The node must be stopped, it overwrites the address book when it shuts down. Addresses
+that aren't routable are skipped unless addr_book_strict is disabled in config.toml.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := server.GetServerContextFromCmd(cmd).Config
+
+			bz, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var export AddrBookExport
+			if err := json.Unmarshal(bz, &export); err != nil {
+				return fmt.Errorf("invalid address book export %s: %w", args[0], err)
+			}
+
+			added, err := importAddrBook(config.P2P.AddrBookFile(), export.Addrs, config.P2P.AddrBookStrict)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("added %d of %d peers to %s\n", added, len(export.Addrs), config.P2P.AddrBookFile())
+			return nil
+		},
+	}
+}
+
+// exportAddrBook reads the peers of the address book at path, an address book
+// that doesn't exist yet is empty.
+func exportAddrBook(path string) (AddrBookExport, error) {
+	export := AddrBookExport{Addrs: []string{}}
+
+	bz, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return export, nil
+	} else if err != nil {
+		return export, err
+	}
+
+	var book addrBookFile
+	if err := json.Unmarshal(bz, &book); err != nil {
+		return export, fmt.Errorf("invalid address book %s: %w", path, err)
+	}
+
+	sort.SliceStable(book.Addrs, func(i, j int) bool {
+		return book.Addrs[i].LastSuccess.After(book.Addrs[j].LastSuccess)
+	})
+	for _, ka := range book.Addrs {
+		export.Addrs = append(export.Addrs, ka.Addr.ID+"@"+net.JoinHostPort(ka.Addr.IP, strconv.Itoa(int(ka.Addr.Port))))
+	}
+	return export, nil
+}
+
+// importAddrBook adds peers to the address book at path and returns how many
+// of them are new. The book is updated by CometBFT itself, so peers end up in
+// the buckets the node would have put them in.
+func importAddrBook(path string, peers []string, routabilityStrict bool) (int, error) {
+	addrs := make([]*p2p.NetAddress, len(peers))
+	for i, peer := range peers {
+		addr, err := p2p.NewNetAddressString(peer)
+		if err != nil {
+			return 0, fmt.Errorf("invalid peer %s: %w", peer, err)
+		}
+		addrs[i] = addr
+	}
+
+	// CometBFT panics on a corrupted book, fail with an error instead
+	if _, err := exportAddrBook(path); err != nil {
+		return 0, err
+	}
+
+	book := pex.NewAddrBook(path, routabilityStrict)
+	if err := book.Start(); err != nil {
+		return 0, err
+	}
+	size := book.Size()
+	for _, addr := range addrs {
+		// rejected addresses, e.g. not routable ones, are skipped
+		_ = book.AddAddress(addr, addr)
+	}
+	added := book.Size() - size
+
+	// the book is saved when it stops
+	if err := book.Stop(); err != nil {
+		return 0, err
+	}
+	book.Wait()
+	return added, nil
+}
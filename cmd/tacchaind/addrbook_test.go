@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddrBookRoundTrip(t *testing.T) {
+	peers := []string{
+		testNodeID1 + "@1.2.3.4:26656",
+		testNodeID2 + "@5.6.7.8:26656",
+		"fedcba9876543210fedcba9876543210fedcba98@[2001:4860::8888]:26656",
+	}
+
+	dir := t.TempDir()
+	book := filepath.Join(dir, "addrbook.json")
+
+	added, err := importAddrBook(book, peers, true)
+	require.NoError(t, err)
+	require.Equal(t, len(peers), added)
+
+	export, err := exportAddrBook(book)
+	require.NoError(t, err)
+	require.ElementsMatch(t, peers, export.Addrs)
+
+	// importing the same peers again adds nothing
+	added, err = importAddrBook(book, export.Addrs, true)
+	require.NoError(t, err)
+	require.Zero(t, added)
+
+	// the export can be written to a file and imported into another node
+	bz, err := json.Marshal(export)
+	require.NoError(t, err)
+	var decoded AddrBookExport
+	require.NoError(t, json.Unmarshal(bz, &decoded))
+
+	otherBook := filepath.Join(dir, "other", "addrbook.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(otherBook), 0o755))
+	added, err = importAddrBook(otherBook, decoded.Addrs, true)
+	require.NoError(t, err)
+	require.Equal(t, len(peers), added)
+
+	otherExport, err := exportAddrBook(otherBook)
+	require.NoError(t, err)
+	require.ElementsMatch(t, export.Addrs, otherExport.Addrs)
+}
+
+func TestAddrBookImportRoutability(t *testing.T) {
+	peers := []string{
+		testNodeID1 + "@127.0.0.1:26656",
+		testNodeID2 + "@1.2.3.4:26656",
+	}
+
+	// strict books skip addresses that aren't routable
+	strictBook := filepath.Join(t.TempDir(), "addrbook.json")
+	added, err := importAddrBook(strictBook, peers, true)
+	require.NoError(t, err)
+	require.Equal(t, 1, added)
+	export, err := exportAddrBook(strictBook)
+	require.NoError(t, err)
+	require.Equal(t, []string{testNodeID2 + "@1.2.3.4:26656"}, export.Addrs)
+
+	book := filepath.Join(t.TempDir(), "addrbook.json")
+	added, err = importAddrBook(book, peers, false)
+	require.NoError(t, err)
+	require.Equal(t, 2, added)
+}
+
+func TestAddrBookInvalid(t *testing.T) {
+	book := filepath.Join(t.TempDir(), "addrbook.json")
+
+	// a missing book is empty
+	export, err := exportAddrBook(book)
+	require.NoError(t, err)
+	require.Empty(t, export.Addrs)
+
+	for _, peer := range []string{"1.2.3.4:26656", "abcd@1.2.3.4:26656", testNodeID1 + "@1.2.3.4"} {
+		_, err := importAddrBook(book, []string{peer}, false)
+		require.Error(t, err, peer)
+	}
+	_, err = os.Stat(book)
+	require.True(t, os.IsNotExist(err), "Invalid imports should leave the book untouched")
+
+	require.NoError(t, os.WriteFile(book, []byte("not json"), 0o644))
+	_, err = exportAddrBook(book)
+	require.Error(t, err)
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+)
+
+const (
+	flagFromHeight = "from-height"
+	flagToHeight   = "to-height"
+)
+
+// BlockRecording is the blocks of a node along with the results their
+// execution gave on it, replayed by another node to check they give the same
+// results there.
+type BlockRecording struct {
+	ChainID string        `json:"chain_id"`
+	Blocks  []BlockRecord `json:"blocks"`
+}
+
+// BlockRecord is a block and the results of its execution on a node: the
+// app hash FinalizeBlock returned, the hash of the tx results the next header
+// commits to and the hash of the events, which no header commits to.
+type BlockRecord struct {
+	Height      int64  `json:"height"`
+	Hash        string `json:"hash"`
+	AppHash     string `json:"app_hash"`
+	ResultsHash string `json:"results_hash"`
+	EventsHash  string `json:"events_hash"`
+	Txs         int    `json:"txs"`
+	GasUsed     int64  `json:"gas_used"`
+}
+
+// BlockComparison is the outcome of the comparison of the blocks of a node
+// with a recording, Divergence is the first result that differs.
+type BlockComparison struct {
+	ChainID    string           `json:"chain_id"`
+	FromHeight int64            `json:"from_height"`
+	ToHeight   int64            `json:"to_height"`
+	Compared   int              `json:"compared"`
+	Divergence *BlockDivergence `json:"divergence,omitempty"`
+}
+
+// BlockDivergence is a result of a block that differs between the recording
+// and the node replaying it.
+type BlockDivergence struct {
+	Height   int64  `json:"height"`
+	Field    string `json:"field"`
+	Recorded string `json:"recorded"`
+	Replayed string `json:"replayed"`
+}
+
+// RecordBlocksCmd writes the blocks of a node and the results of their
+// execution to a file, for CompareBlocksCmd.
+func RecordBlocksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "record-blocks [file]",
+		Short: "Record the blocks of a node and the results of their execution",
+		Long: `Record the blocks of a node and the results of their execution to a JSON file: the hash
+of each block, the app hash its execution gave, the hash of its tx results and the hash
+of its events. The blocks range from --from-height, the earliest block of the node by
+default, to --to-height, its latest block by default.
+
+A fresh node built from the same commit, started from the same genesis and block
+syncing from the network, executes the same blocks again. tacchaind tools compare-blocks
+checks it gets the same results, so a non-deterministic ante handler, precompile or
+module shows up as the first block whose results differ.`,
+		Example: "tacchaind tools record-blocks blocks.json --node tcp://localhost:26657",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			node, err := clientCtx.GetNode()
+			if err != nil {
+				return err
+			}
+			fromHeight, _ := cmd.Flags().GetInt64(flagFromHeight)
+			toHeight, _ := cmd.Flags().GetInt64(flagToHeight)
+
+			recording, err := recordBlocks(cmd.Context(), node, fromHeight, toHeight)
+			if err != nil {
+				return err
+			}
+			bz, err := json.MarshalIndent(recording, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(args[0], bz, 0o644); err != nil {
+				return err
+			}
+			blocks := recording.Blocks
+			cmd.Printf("recorded %d blocks of %s from height %d to %d\n", len(blocks), recording.ChainID, blocks[0].Height, blocks[len(blocks)-1].Height)
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64(flagFromHeight, 0, "First block to record, the earliest block of the node by default")
+	cmd.Flags().Int64(flagToHeight, 0, "Last block to record, the latest block of the node by default")
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CompareBlocksCmd compares the results of the blocks of a node with a
+// recording of RecordBlocksCmd.
+func CompareBlocksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare-blocks [file]",
+		Short: "Compare the results of the blocks of a node with a recording",
+		Long: `Compare the results of the blocks of a node with a recording of tacchaind tools
+record-blocks, usually of another node of the network: the block hashes, the app hashes,
+the tx results and the events of every recorded block must be the same. The command
+fails with the first result that differs, or if the node hasn't executed all the
+recorded blocks yet.
+
+CometBFT stops a node at the first block whose app hash or tx results differ from the
+next header, so a node replaying the blocks of a recording reaches its last height only
+if the state it computes is the same. The events aren't part of consensus and are
+compared here only.`,
+		Example: "tacchaind tools compare-blocks blocks.json --node tcp://localhost:36657",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			node, err := clientCtx.GetNode()
+			if err != nil {
+				return err
+			}
+			output, _ := cmd.Flags().GetString(flags.FlagOutput)
+
+			bz, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var recording BlockRecording
+			if err := json.Unmarshal(bz, &recording); err != nil {
+				return fmt.Errorf("invalid block recording: %w", err)
+			}
+
+			comparison, err := compareBlocks(cmd.Context(), node, recording)
+			if err != nil {
+				return err
+			}
+			if output == flags.OutputFormatJSON {
+				bz, err := json.MarshalIndent(comparison, "", "  ")
+				if err != nil {
+					return err
+				}
+				cmd.Println(string(bz))
+			} else {
+				printBlockComparison(cmd, comparison)
+			}
+			if d := comparison.Divergence; d != nil {
+				return fmt.Errorf("block %d diverged, its %s is %s instead of %s", d.Height, d.Field, d.Replayed, d.Recorded)
+			}
+			return nil
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// recordBlocks returns the blocks of node from fromHeight to toHeight, a
+// height of 0 standing for the earliest or the latest block of the node
+func recordBlocks(ctx context.Context, node client.CometRPC, fromHeight, toHeight int64) (BlockRecording, error) {
+	status, err := node.Status(ctx)
+	if err != nil {
+		return BlockRecording{}, err
+	}
+	if fromHeight == 0 {
+		fromHeight = max(status.SyncInfo.EarliestBlockHeight, 1)
+	}
+	if toHeight == 0 {
+		toHeight = status.SyncInfo.LatestBlockHeight
+	}
+	if toHeight > status.SyncInfo.LatestBlockHeight {
+		return BlockRecording{}, fmt.Errorf("the node is at height %d, below --%s %d", status.SyncInfo.LatestBlockHeight, flagToHeight, toHeight)
+	}
+	if fromHeight < 1 || fromHeight > toHeight {
+		return BlockRecording{}, fmt.Errorf("invalid range of heights %d to %d", fromHeight, toHeight)
+	}
+
+	recording := BlockRecording{ChainID: status.NodeInfo.Network}
+	for height := fromHeight; height <= toHeight; height++ {
+		record, err := getBlockRecord(ctx, node, height)
+		if err != nil {
+			return BlockRecording{}, err
+		}
+		recording.Blocks = append(recording.Blocks, record)
+	}
+	return recording, nil
+}
+
+// compareBlocks compares the blocks of node with the blocks of recording, up
+// to the first divergence
+func compareBlocks(ctx context.Context, node client.CometRPC, recording BlockRecording) (BlockComparison, error) {
+	if len(recording.Blocks) == 0 {
+		return BlockComparison{}, fmt.Errorf("the recording has no blocks")
+	}
+	status, err := node.Status(ctx)
+	if err != nil {
+		return BlockComparison{}, err
+	}
+	if network := status.NodeInfo.Network; network != recording.ChainID {
+		return BlockComparison{}, fmt.Errorf("the node is on chain %s, the blocks were recorded on %s", network, recording.ChainID)
+	}
+	last := recording.Blocks[len(recording.Blocks)-1].Height
+	if status.SyncInfo.LatestBlockHeight < last {
+		return BlockComparison{}, fmt.Errorf("the node is at height %d, below the last recorded block %d", status.SyncInfo.LatestBlockHeight, last)
+	}
+
+	comparison := BlockComparison{
+		ChainID:    recording.ChainID,
+		FromHeight: recording.Blocks[0].Height,
+		ToHeight:   last,
+	}
+	for _, recorded := range recording.Blocks {
+		replayed, err := getBlockRecord(ctx, node, recorded.Height)
+		if err != nil {
+			return BlockComparison{}, err
+		}
+		comparison.Compared++
+		if divergence := compareBlockRecords(recorded, replayed); divergence != nil {
+			comparison.Divergence = divergence
+			break
+		}
+	}
+	return comparison, nil
+}
+
+// compareBlockRecords returns the first result of recorded that differs in
+// replayed, nil if they are the same
+func compareBlockRecords(recorded, replayed BlockRecord) *BlockDivergence {
+	for _, field := range []struct {
+		name               string
+		recorded, replayed string
+	}{
+		{"hash", recorded.Hash, replayed.Hash},
+		{"app_hash", recorded.AppHash, replayed.AppHash},
+		{"results_hash", recorded.ResultsHash, replayed.ResultsHash},
+		{"events_hash", recorded.EventsHash, replayed.EventsHash},
+	} {
+		if field.recorded != field.replayed {
+			return &BlockDivergence{Height: recorded.Height, Field: field.name, Recorded: field.recorded, Replayed: field.replayed}
+		}
+	}
+	return nil
+}
+
+// getBlockRecord returns the block of node at height and the results of its
+// execution on node
+func getBlockRecord(ctx context.Context, node client.CometRPC, height int64) (BlockRecord, error) {
+	block, err := node.Block(ctx, &height)
+	if err != nil {
+		return BlockRecord{}, fmt.Errorf("failed to get block %d: %w", height, err)
+	}
+	results, err := node.BlockResults(ctx, &height)
+	if err != nil {
+		return BlockRecord{}, fmt.Errorf("failed to get the results of block %d: %w", height, err)
+	}
+
+	// the events of the txs, then those of the block, in execution order
+	events := sha256.New()
+	for _, res := range results.TxsResults {
+		for _, event := range res.Events {
+			bz, err := event.Marshal()
+			if err != nil {
+				return BlockRecord{}, err
+			}
+			events.Write(bz)
+		}
+	}
+	for _, event := range results.FinalizeBlockEvents {
+		bz, err := event.Marshal()
+		if err != nil {
+			return BlockRecord{}, err
+		}
+		events.Write(bz)
+	}
+
+	record := BlockRecord{
+		Height:      height,
+		Hash:        block.BlockID.Hash.String(),
+		AppHash:     cmtbytes.HexBytes(results.AppHash).String(),
+		ResultsHash: cmtbytes.HexBytes(cmttypes.NewResults(results.TxsResults).Hash()).String(),
+		EventsHash:  cmtbytes.HexBytes(events.Sum(nil)).String(),
+		Txs:         len(block.Block.Txs),
+	}
+	for _, res := range results.TxsResults {
+		record.GasUsed += res.GasUsed
+	}
+	return record, nil
+}
+
+func printBlockComparison(cmd *cobra.Command, comparison BlockComparison) {
+	cmd.Printf("chain: %s compared: %d blocks from height %d to %d\n", comparison.ChainID, comparison.Compared, comparison.FromHeight, comparison.ToHeight)
+	if d := comparison.Divergence; d != nil {
+		cmd.Printf("block %d diverged:\n  %s recorded: %s\n  %s replayed: %s\n", d.Height, d.Field, d.Recorded, d.Field, d.Replayed)
+		return
+	}
+	cmd.Println("no block diverged")
+}
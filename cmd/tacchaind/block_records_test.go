@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtrpctypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newBlocksNode returns a node at height whose blocks hold a tx each, with
+// the results of their execution changed by diverge
+func newBlocksNode(t *testing.T, height int64, diverge func(height int64, results *cmtrpctypes.ResultBlockResults)) *mockNode {
+	t.Helper()
+
+	node := newMockNode(t, height)
+	for h := int64(1); h <= height; h++ {
+		block := &cmttypes.Block{
+			Header: cmttypes.Header{ChainID: "tacchain_239-1", Height: h, ValidatorsHash: []byte{1}},
+			Data:   cmttypes.Data{Txs: cmttypes.Txs{cmttypes.Tx{byte(h)}}},
+		}
+		results := &cmtrpctypes.ResultBlockResults{
+			TxsResults: []*abci.ExecTxResult{{
+				GasWanted: 100_000,
+				GasUsed:   50_000 + h,
+				Events:    []abci.Event{{Type: "tx", Attributes: []abci.EventAttribute{{Key: "height", Value: string(rune('0' + h))}}}},
+			}},
+			FinalizeBlockEvents: []abci.Event{{Type: "mint"}},
+			AppHash:             []byte{byte(h), 0xaa},
+		}
+		if diverge != nil {
+			diverge(h, results)
+		}
+		node.setBlock(block, results)
+	}
+	return node
+}
+
+func TestRecordBlocks(t *testing.T) {
+	ctx := context.Background()
+	node := newBlocksNode(t, 5, nil)
+
+	recording, err := recordBlocks(ctx, node, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, recording.Blocks, 5)
+	for i, record := range recording.Blocks {
+		height := int64(i + 1)
+		require.Equal(t, height, record.Height)
+		require.Equal(t, node.blocks[height].BlockID.Hash.String(), record.Hash)
+		require.Equal(t, node.blockResults[height].AppHash, mustHexBytes(t, record.AppHash))
+		require.Equal(t, 1, record.Txs)
+		require.Equal(t, 50_000+height, record.GasUsed)
+	}
+	// the results hash is the one the next header commits to
+	require.Equal(t, cmttypes.NewResults(node.blockResults[3].TxsResults).Hash(), mustHexBytes(t, recording.Blocks[2].ResultsHash))
+
+	recording, err = recordBlocks(ctx, node, 2, 4)
+	require.NoError(t, err)
+	require.Len(t, recording.Blocks, 3)
+	require.Equal(t, int64(2), recording.Blocks[0].Height)
+
+	_, err = recordBlocks(ctx, node, 0, 6)
+	require.ErrorContains(t, err, "the node is at height 5")
+	_, err = recordBlocks(ctx, node, 4, 3)
+	require.ErrorContains(t, err, "invalid range of heights")
+}
+
+func TestCompareBlocks(t *testing.T) {
+	ctx := context.Background()
+	recording, err := recordBlocks(ctx, newBlocksNode(t, 5, nil), 0, 0)
+	require.NoError(t, err)
+
+	// a node executing the blocks the same way
+	comparison, err := compareBlocks(ctx, newBlocksNode(t, 6, nil), recording)
+	require.NoError(t, err)
+	require.Equal(t, BlockComparison{FromHeight: 1, ToHeight: 5, Compared: 5}, comparison)
+
+	for _, tc := range []struct {
+		name    string
+		diverge func(height int64, results *cmtrpctypes.ResultBlockResults)
+		field   string
+	}{
+		{
+			name: "app hash",
+			diverge: func(height int64, results *cmtrpctypes.ResultBlockResults) {
+				if height >= 3 {
+					results.AppHash = []byte{byte(height), 0xbb}
+				}
+			},
+			field: "app_hash",
+		},
+		{
+			name: "gas used",
+			diverge: func(height int64, results *cmtrpctypes.ResultBlockResults) {
+				if height == 3 {
+					results.TxsResults[0].GasUsed++
+				}
+			},
+			field: "results_hash",
+		},
+		{
+			name: "events",
+			diverge: func(height int64, results *cmtrpctypes.ResultBlockResults) {
+				if height == 3 {
+					results.FinalizeBlockEvents = nil
+				}
+			},
+			field: "events_hash",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			comparison, err := compareBlocks(ctx, newBlocksNode(t, 5, tc.diverge), recording)
+			require.NoError(t, err)
+			require.Equal(t, 3, comparison.Compared, "the comparison should stop at the first divergence")
+			require.NotNil(t, comparison.Divergence)
+			require.Equal(t, int64(3), comparison.Divergence.Height)
+			require.Equal(t, tc.field, comparison.Divergence.Field)
+			require.NotEqual(t, comparison.Divergence.Recorded, comparison.Divergence.Replayed)
+		})
+	}
+
+	_, err = compareBlocks(ctx, newBlocksNode(t, 4, nil), recording)
+	require.ErrorContains(t, err, "the node is at height 4, below the last recorded block 5")
+	_, err = compareBlocks(ctx, newBlocksNode(t, 5, nil), BlockRecording{ChainID: "tacchain_2390-1", Blocks: recording.Blocks})
+	require.ErrorContains(t, err, "the blocks were recorded on tacchain_2390-1")
+	_, err = compareBlocks(ctx, newBlocksNode(t, 5, nil), BlockRecording{})
+	require.ErrorContains(t, err, "the recording has no blocks")
+}
+
+func TestRecordAndCompareBlocksCmd(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "blocks.json")
+	_, err := newBlocksNode(t, 5, nil).run(RecordBlocksCmd(), file, "--from-height", "2")
+	require.NoError(t, err)
+	bz, err := os.ReadFile(file)
+	require.NoError(t, err)
+	var recording BlockRecording
+	require.NoError(t, json.Unmarshal(bz, &recording))
+	require.Len(t, recording.Blocks, 4)
+
+	_, err = newBlocksNode(t, 5, nil).run(CompareBlocksCmd(), file)
+	require.NoError(t, err)
+	_, err = newBlocksNode(t, 5, func(height int64, results *cmtrpctypes.ResultBlockResults) {
+		if height == 4 {
+			results.AppHash = []byte{4, 0xbb}
+		}
+	}).run(CompareBlocksCmd(), file)
+	require.ErrorContains(t, err, "block 4 diverged, its app_hash is 04BB instead of 04AA")
+}
+
+func mustHexBytes(t *testing.T, s string) []byte {
+	t.Helper()
+
+	bz, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return bz
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtcfg "github.com/cometbft/cometbft/config"
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	cmtrpctypes "github.com/cometbft/cometbft/rpc/core/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/gogoproto/jsonpb"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+)
+
+const (
+	flagRaw       = "raw"
+	flagRetention = "retention"
+
+	// stateDB is the name of the database of the CometBFT state, which holds
+	// the block results
+	stateDB = "state"
+)
+
+// BlockResults is the results of the execution of a block returned by the
+// block_results RPC, with the typed events decoded.
+type BlockResults struct {
+	Height                int64                     `json:"height"`
+	TxsResults            []TxResult                `json:"txs_results"`
+	FinalizeBlockEvents   []Event                   `json:"finalize_block_events"`
+	ValidatorUpdates      []abci.ValidatorUpdate    `json:"validator_updates"`
+	ConsensusParamUpdates *cmtproto.ConsensusParams `json:"consensus_param_updates"`
+	AppHash               cmtbytes.HexBytes         `json:"app_hash"`
+}
+
+// TxResult is the result of a tx of a block.
+type TxResult struct {
+	Code      uint32  `json:"code"`
+	Codespace string  `json:"codespace,omitempty"`
+	Log       string  `json:"log,omitempty"`
+	Data      []byte  `json:"data,omitempty"`
+	GasWanted int64   `json:"gas_wanted"`
+	GasUsed   int64   `json:"gas_used"`
+	Events    []Event `json:"events"`
+}
+
+// Event is an event of a block or of a tx. The typed events, which the
+// modules emit with a proto message JSON encoded in the attributes, are
+// decoded into Typed. Attributes then only holds the attributes the SDK adds,
+// e.g. msg_index or mode.
+type Event struct {
+	Type       string           `json:"type"`
+	Typed      json.RawMessage  `json:"typed,omitempty"`
+	Attributes []EventAttribute `json:"attributes,omitempty"`
+}
+
+// EventAttribute is an attribute of an event.
+type EventAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// BlockResultsCmd queries the results of a block. It replaces the command of
+// the SDK, which prints the attributes of the typed events as escaped JSON.
+func BlockResultsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "block-results [height]",
+		Short: "Query for a committed block's results by height",
+		Long: `Query the results of a committed block, the latest one if no height is given, with the
+CometBFT RPC block_results method: the results of its txs and the events of the block.
+
+The typed events of the modules, e.g. the events of x/bridge or of x/feerouting, hold
+their fields JSON encoded in their attributes. They are decoded into the typed field of
+the event, the attributes then only hold the ones the SDK adds, like msg_index or mode.
+The other events are printed as they are. Use --raw for the response of the node as is.
+
+Nodes keep the results of the blocks set by retention in the [block-results] section
+of app.toml, the results of older blocks can't be queried.`,
+		Example: "tacchaind q block-results 1200 --node tcp://localhost:26657",
+		Args:    cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			node, err := clientCtx.GetNode()
+			if err != nil {
+				return err
+			}
+
+			var height *int64
+			if len(args) > 0 {
+				h, err := strconv.ParseInt(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid height %s: %w", args[0], err)
+				}
+				height = &h
+			}
+			res, err := node.BlockResults(cmd.Context(), height)
+			if err != nil {
+				return err
+			}
+
+			if raw, _ := cmd.Flags().GetBool(flagRaw); raw {
+				bz, err := json.Marshal(res)
+				if err != nil {
+					return err
+				}
+				return clientCtx.PrintRaw(bz)
+			}
+			return printJSON(clientCtx, decodeBlockResults(res, clientCtx.InterfaceRegistry))
+		},
+	}
+
+	cmd.Flags().Bool(flagRaw, false, "Print the response of the node without decoding the typed events")
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// decodeBlockResults decodes the typed events of res, resolving the Any
+// fields of the events with resolver
+func decodeBlockResults(res *cmtrpctypes.ResultBlockResults, resolver jsonpb.AnyResolver) BlockResults {
+	results := BlockResults{
+		Height:                res.Height,
+		TxsResults:            make([]TxResult, 0, len(res.TxsResults)),
+		FinalizeBlockEvents:   decodeEvents(res.FinalizeBlockEvents, resolver),
+		ValidatorUpdates:      res.ValidatorUpdates,
+		ConsensusParamUpdates: res.ConsensusParamUpdates,
+		AppHash:               res.AppHash,
+	}
+	for _, tx := range res.TxsResults {
+		results.TxsResults = append(results.TxsResults, TxResult{
+			Code:      tx.Code,
+			Codespace: tx.Codespace,
+			Log:       tx.Log,
+			Data:      tx.Data,
+			GasWanted: tx.GasWanted,
+			GasUsed:   tx.GasUsed,
+			Events:    decodeEvents(tx.Events, resolver),
+		})
+	}
+	return results
+}
+
+func decodeEvents(events []abci.Event, resolver jsonpb.AnyResolver) []Event {
+	decoded := make([]Event, 0, len(events))
+	for _, event := range events {
+		decoded = append(decoded, decodeEvent(event, resolver))
+	}
+	return decoded
+}
+
+// decodeEvent decodes event if it is a typed event whose message is
+// registered, and returns it as is otherwise
+func decodeEvent(event abci.Event, resolver jsonpb.AnyResolver) Event {
+	decoded := Event{Type: event.Type}
+	for _, attr := range event.Attributes {
+		decoded.Attributes = append(decoded.Attributes, EventAttribute{Key: attr.Key, Value: attr.Value})
+	}
+
+	// the attributes added by the SDK, like mode=EndBlock, aren't always JSON
+	typedEvent := abci.Event{Type: event.Type}
+	for _, attr := range event.Attributes {
+		if json.Valid([]byte(attr.Value)) {
+			typedEvent.Attributes = append(typedEvent.Attributes, attr)
+		}
+	}
+	msg, err := sdk.ParseTypedEvent(typedEvent)
+	if err != nil {
+		return decoded
+	}
+	typed, err := codec.ProtoMarshalJSON(msg, resolver)
+	if err != nil {
+		return decoded
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(typed, &fields); err != nil {
+		return decoded
+	}
+
+	decoded.Typed = typed
+	decoded.Attributes = nil
+	for _, attr := range event.Attributes {
+		if _, ok := fields[attr.Key]; !ok {
+			decoded.Attributes = append(decoded.Attributes, EventAttribute{Key: attr.Key, Value: attr.Value})
+		}
+	}
+	return decoded
+}
+
+// PruneBlockResultsCmd prunes the block results of a stopped node.
+func PruneBlockResultsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune-block-results",
+		Short: "Delete the results of the old blocks of the node, keeping the blocks",
+		Long: `Delete the results of the blocks of the node older than the last --retention blocks,
+the retention of the [block-results] section of app.toml by default. The blocks are
+kept, only the tx results and the events served by the block_results RPC are deleted.
+The node must be stopped.
+
+Nodes with a retention in app.toml prune the results when they start, this command
+prunes them without starting the node, e.g. before setting the retention. Run
+tacchaind tools compact-db state afterwards to reclaim the space of the deleted results.`,
+		Example: "tacchaind tools prune-block-results --retention 100000",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			retention := app.BlockResultsConfigFromAppOptions(serverCtx.Viper).Retention
+			if cmd.Flags().Changed(flagRetention) {
+				retention, _ = cmd.Flags().GetUint64(flagRetention)
+			}
+			if retention == 0 {
+				return fmt.Errorf("no retention set, the results are kept as long as the blocks")
+			}
+
+			pruned, latest, err := pruneBlockResults(serverCtx.Config, retention)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("pruned the results of %d blocks, kept the results of the blocks after %d\n", pruned, max(latest-int64(retention), 0))
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint64(flagRetention, 0, "Number of most recent blocks whose results are kept, the retention of app.toml by default")
+
+	return cmd
+}
+
+// addBlockResultsPruning prunes the block results to the retention of
+// app.toml before the node starts, while CometBFT doesn't hold the state
+// database open yet. Pruning is skipped without a state database, e.g. for
+// an app started without CometBFT.
+func addBlockResultsPruning(startCmd *cobra.Command) {
+	preRunE := startCmd.PreRunE
+	startCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if preRunE != nil {
+			if err := preRunE(cmd, args); err != nil {
+				return err
+			}
+		}
+
+		serverCtx := server.GetServerContextFromCmd(cmd)
+		cfg := app.BlockResultsConfigFromAppOptions(serverCtx.Viper)
+		if cfg.Retention == 0 {
+			return nil
+		}
+		if _, err := os.Stat(filepath.Join(serverCtx.Config.DBDir(), stateDB+".db")); err != nil {
+			return nil
+		}
+		pruned, latest, err := pruneBlockResults(serverCtx.Config, cfg.Retention)
+		if err != nil {
+			// the results of old blocks aren't worth keeping the node down
+			serverCtx.Logger.Error("failed to prune the block results", "err", err)
+			return nil
+		}
+		serverCtx.Logger.Info("pruned the block results", "blocks", pruned, "latest", latest, "retention", cfg.Retention)
+		return nil
+	}
+}
+
+// pruneBlockResults prunes the results in the state database of the node to
+// the last retention blocks
+func pruneBlockResults(config *cmtcfg.Config, retention uint64) (int, int64, error) {
+	db, err := dbm.NewDB(stateDB, dbm.BackendType(config.DBBackend), config.DBDir())
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to open the state database: %w", err)
+	}
+	pruned, latest, err := app.PruneBlockResults(db, retention)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	return pruned, latest, err
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtcfg "github.com/cometbft/cometbft/config"
+	cmtrpctypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	bridgetypes "github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+// typedEvent returns the event of msg with the attributes the SDK adds
+func typedEvent(t *testing.T, msg *bridgetypes.EventSlashRelayer, attrs ...abci.EventAttribute) abci.Event {
+	t.Helper()
+	event, err := sdk.TypedEventToEvent(msg)
+	require.NoError(t, err)
+	event.Attributes = append(event.Attributes, attrs...)
+	return abci.Event(event)
+}
+
+func TestBlockResultsCmd(t *testing.T) {
+	node := newMockNode(t, 10)
+	slash := &bridgetypes.EventSlashRelayer{
+		Relayer:  "0x9858EfFD232B4033E47d90003D41EC34EcaEda94",
+		Sequence: 7,
+		Slashed:  sdk.NewCoins(sdk.NewInt64Coin("utac", 100)),
+	}
+	malformed := typedEvent(t, slash)
+	malformed.Attributes[1].Value = `"seven"`
+	node.setBlock(&cmttypes.Block{Header: cmttypes.Header{Height: 10}}, &cmtrpctypes.ResultBlockResults{
+		TxsResults: []*abci.ExecTxResult{{
+			GasWanted: 200_000,
+			GasUsed:   120_000,
+			Events: []abci.Event{
+				{Type: "transfer", Attributes: []abci.EventAttribute{{Key: "amount", Value: "100utac"}, {Key: "msg_index", Value: "0"}}},
+				typedEvent(t, slash, abci.EventAttribute{Key: "msg_index", Value: "0"}),
+			},
+		}},
+		FinalizeBlockEvents: []abci.Event{
+			typedEvent(t, slash, abci.EventAttribute{Key: "mode", Value: "EndBlock"}),
+			{Type: "tacchain.unknown.v1.EventUnknown", Attributes: []abci.EventAttribute{{Key: "id", Value: "1"}}},
+			malformed,
+		},
+		AppHash: []byte{0xab},
+	})
+
+	for _, args := range [][]string{{"10"}, {}} {
+		output, err := node.run(BlockResultsCmd(), args...)
+		require.NoError(t, err)
+		var results BlockResults
+		require.NoError(t, json.Unmarshal([]byte(output), &results), output)
+		require.Equal(t, int64(10), results.Height)
+		require.Len(t, results.TxsResults, 1)
+		require.Equal(t, int64(120_000), results.TxsResults[0].GasUsed)
+
+		// the typed events are decoded, keeping the attributes of the SDK
+		events := results.TxsResults[0].Events
+		require.Equal(t, Event{Type: "transfer", Attributes: []EventAttribute{{"amount", "100utac"}, {"msg_index", "0"}}}, events[0])
+		require.Equal(t, []EventAttribute{{"msg_index", "0"}}, events[1].Attributes)
+		var decoded struct {
+			Relayer  string    `json:"relayer"`
+			Sequence string    `json:"sequence"`
+			Slashed  sdk.Coins `json:"slashed"`
+		}
+		require.NoError(t, json.Unmarshal(events[1].Typed, &decoded))
+		require.Equal(t, slash.Relayer, decoded.Relayer)
+		require.Equal(t, "7", decoded.Sequence)
+		require.Equal(t, slash.Slashed, decoded.Slashed)
+
+		events = results.FinalizeBlockEvents
+		require.Equal(t, proto.MessageName(slash), events[0].Type)
+		require.NotEmpty(t, events[0].Typed)
+		require.Equal(t, []EventAttribute{{"mode", "EndBlock"}}, events[0].Attributes)
+		// unknown and malformed typed events are printed as they are
+		require.Empty(t, events[1].Typed)
+		require.Equal(t, []EventAttribute{{"id", "1"}}, events[1].Attributes)
+		require.Empty(t, events[2].Typed)
+		require.Len(t, events[2].Attributes, 3)
+	}
+
+	output, err := node.run(BlockResultsCmd(), "10", "--raw")
+	require.NoError(t, err)
+	var raw cmtrpctypes.ResultBlockResults
+	require.NoError(t, json.Unmarshal([]byte(output), &raw), output)
+	require.Equal(t, node.blockResults[10].FinalizeBlockEvents, raw.FinalizeBlockEvents)
+
+	_, err = node.run(BlockResultsCmd(), "9")
+	require.ErrorContains(t, err, "could not find results for height #9")
+	_, err = node.run(BlockResultsCmd(), "ten")
+	require.ErrorContains(t, err, "invalid height")
+}
+
+// startWithRetention runs the pre-run of a start command of the node of
+// config with the block results retention
+func startWithRetention(t *testing.T, config *cmtcfg.Config, retention uint64) {
+	t.Helper()
+
+	serverCtx := server.NewDefaultContext()
+	serverCtx.Config = config
+	serverCtx.Viper.Set(app.FlagBlockResultsRetention, retention)
+	startCmd := &cobra.Command{Use: "start", RunE: func(*cobra.Command, []string) error { return nil }}
+	require.NoError(t, server.SetCmdServerContext(startCmd, serverCtx))
+
+	addBlockResultsPruning(startCmd)
+	require.NoError(t, startCmd.PreRunE(startCmd, nil))
+}
+
+func TestBlockResultsPruning(t *testing.T) {
+	config := cmtcfg.DefaultConfig()
+	config.SetRoot(t.TempDir())
+
+	// a node without a state database is left as is
+	startWithRetention(t, config, 5)
+	require.NoFileExists(t, filepath.Join(config.DBDir(), "state.db"))
+
+	db, err := dbm.NewDB(stateDB, dbm.GoLevelDBBackend, config.DBDir())
+	require.NoError(t, err)
+	for h := 1; h <= 20; h++ {
+		require.NoError(t, db.Set([]byte(fmt.Sprintf("abciResponsesKey:%d", h)), []byte{byte(h)}))
+	}
+	require.NoError(t, db.Set([]byte("lastABCIResponseKey"), []byte{20}))
+	require.NoError(t, db.Close())
+
+	// the node starts without pruning by default
+	startWithRetention(t, config, 0)
+	pruned, _, err := pruneBlockResults(config, 20)
+	require.NoError(t, err)
+	require.Zero(t, pruned)
+
+	startWithRetention(t, config, 5)
+	db, err = dbm.NewDB(stateDB, dbm.GoLevelDBBackend, config.DBDir())
+	require.NoError(t, err)
+	defer db.Close()
+	for h := 1; h <= 20; h++ {
+		value, err := db.Get([]byte(fmt.Sprintf("abciResponsesKey:%d", h)))
+		require.NoError(t, err)
+		if h <= 15 {
+			require.Nil(t, value, "results of block %d should be pruned", h)
+		} else {
+			require.Equal(t, []byte{byte(h)}, value)
+		}
+	}
+	value, err := db.Get([]byte("lastABCIResponseKey"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{20}, value)
+}
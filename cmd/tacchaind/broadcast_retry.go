@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	clienttx "github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+)
+
+// BroadcastSyncRetry is the broadcast mode of the txs broadcast in sync mode
+// and broadcast again, signed with the refreshed sequence of the sender if
+// need be, as long as the node rejects them for a sequence mismatch or a
+// full mempool
+const BroadcastSyncRetry = "sync-retry"
+
+// addBroadcastRetryMode lists the sync-retry mode in the --broadcast-mode
+// flag of the tx commands of rootCmd
+func addBroadcastRetryMode(rootCmd *cobra.Command) {
+	if f := rootCmd.Flags().Lookup(flags.FlagBroadcastMode); f != nil && !strings.Contains(f.Usage, BroadcastSyncRetry) {
+		f.Usage = fmt.Sprintf("Transaction broadcasting mode (%s|%s|%s), %s retries the txs rejected for a sequence mismatch or a full mempool",
+			flags.BroadcastSync, flags.BroadcastAsync, BroadcastSyncRetry, BroadcastSyncRetry)
+	}
+	for _, cmd := range rootCmd.Commands() {
+		addBroadcastRetryMode(cmd)
+	}
+}
+
+// setBroadcastRetry makes a tx command run with --broadcast-mode sync-retry
+// broadcast in sync mode through a client retrying the txs the node rejects
+// for a sequence mismatch or a full mempool. It runs after the client context
+// of cmd is set.
+func setBroadcastRetry(cmd *cobra.Command) error {
+	mode := cmd.Flags().Lookup(flags.FlagBroadcastMode)
+	if mode == nil || mode.Value.String() != BroadcastSyncRetry {
+		return nil
+	}
+	// the SDK only broadcasts in sync or async mode
+	if err := mode.Value.Set(flags.BroadcastSync); err != nil {
+		return err
+	}
+
+	clientCtx := client.GetClientContextFromCmd(cmd).WithBroadcastMode(flags.BroadcastSync)
+	if clientCtx.Client == nil {
+		return client.SetCmdClientContext(cmd, clientCtx)
+	}
+	// the tx commands create a new client of a --node flag, without the retries
+	if node := cmd.Flags().Lookup(flags.FlagNode); node != nil {
+		node.Changed = false
+	}
+	return client.SetCmdClientContext(cmd, clientCtx.WithClient(retryClient{
+		CometRPC: clientCtx.Client,
+		cmd:      cmd,
+		retry:    tacsdk.DefaultRetryConfig(),
+	}))
+}
+
+// retryClient is the client of a node broadcasting the txs of cmd with
+// tacsdk.RetryBroadcast
+type retryClient struct {
+	client.CometRPC
+
+	cmd   *cobra.Command
+	retry tacsdk.RetryConfig
+}
+
+func (c retryClient) BroadcastTxSync(ctx context.Context, tx cmttypes.Tx) (*coretypes.ResultBroadcastTx, error) {
+	var last *coretypes.ResultBroadcastTx
+	broadcast := func(ctx context.Context, txBytes []byte) (*sdk.TxResponse, error) {
+		res, err := c.CometRPC.BroadcastTxSync(ctx, txBytes)
+		if err != nil {
+			return nil, err
+		}
+		last = res
+		return sdk.NewResponseFormatBroadcastTx(res), nil
+	}
+	sequence := func(context.Context) (uint64, error) {
+		clientCtx, err := client.GetClientTxContext(c.cmd)
+		if err != nil {
+			return 0, err
+		}
+		_, seq, err := clientCtx.AccountRetriever.GetAccountNumberSequence(clientCtx, clientCtx.FromAddress)
+		return seq, err
+	}
+	sign := func(ctx context.Context, seq uint64) ([]byte, error) {
+		return c.sign(ctx, tx, seq)
+	}
+
+	_, err := tacsdk.RetryBroadcast(ctx, c.retry, tx, broadcast, sequence, sign)
+	if err != nil && last != nil {
+		// the SDK prints the result along with the error
+		return last, fmt.Errorf("failed to broadcast the tx again: %w", err)
+	}
+	return last, err
+}
+
+// sign signs txBytes again with the --from key of the command and seq
+func (c retryClient) sign(ctx context.Context, txBytes []byte, seq uint64) ([]byte, error) {
+	clientCtx, err := client.GetClientTxContext(c.cmd)
+	if err != nil {
+		return nil, err
+	}
+	if clientCtx.FromName == "" {
+		return nil, errors.New("the tx has to be signed with the sequence the node expects, it needs a --from key")
+	}
+
+	tx, err := clientCtx.TxConfig.TxDecoder()(txBytes)
+	if err != nil {
+		return nil, err
+	}
+	builder, err := clientCtx.TxConfig.WrapTxBuilder(tx)
+	if err != nil {
+		return nil, err
+	}
+	txf, err := clienttx.NewFactoryCLI(clientCtx, c.cmd.Flags())
+	if err != nil {
+		return nil, err
+	}
+	accNum, _, err := clientCtx.AccountRetriever.GetAccountNumberSequence(clientCtx, clientCtx.FromAddress)
+	if err != nil {
+		return nil, err
+	}
+	txf = txf.WithAccountNumber(accNum).WithSequence(seq)
+	if err := clienttx.Sign(ctx, txf, clientCtx.FromName, builder, true); err != nil {
+		return nil, err
+	}
+	return clientCtx.TxConfig.TxEncoder()(builder.GetTx())
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	cmtrpctypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+	evmhd "github.com/cosmos/evm/crypto/hd"
+	evmkeyring "github.com/cosmos/evm/crypto/keyring"
+)
+
+// flakyBroadcastNode is a mock node rejecting the first txs it receives for
+// a sequence mismatch, the sequence of the sender account being 3
+type flakyBroadcastNode struct {
+	*mockNode
+
+	// rejections is the number of txs to reject
+	rejections int
+	txs        []cmttypes.Tx
+}
+
+func (n *flakyBroadcastNode) BroadcastTxSync(_ context.Context, tx cmttypes.Tx) (*cmtrpctypes.ResultBroadcastTx, error) {
+	n.txs = append(n.txs, tx)
+	if len(n.txs) > n.rejections {
+		return &cmtrpctypes.ResultBroadcastTx{Hash: tx.Hash()}, nil
+	}
+	// the node has two txs of the sender in its mempool
+	return &cmtrpctypes.ResultBroadcastTx{
+		Codespace: sdkerrors.RootCodespace,
+		Code:      sdkerrors.ErrWrongSequence.ABCICode(),
+		Log:       sdkerrors.ErrWrongSequence.Wrapf("account sequence mismatch, expected 5, got 3").Error(),
+		Hash:      tx.Hash(),
+	}, nil
+}
+
+func newFlakyBroadcastNode(t *testing.T, rejections int) *flakyBroadcastNode {
+	n := &flakyBroadcastNode{mockNode: newMockNode(t, 10), rejections: rejections}
+	handle(n.mockNode, "/cosmos.auth.v1beta1.Query/Account", func(req *authtypes.QueryAccountRequest) (proto.Message, error) {
+		addr, err := sdk.AccAddressFromBech32(req.Address)
+		if err != nil {
+			return nil, err
+		}
+		account, err := codectypes.NewAnyWithValue(authtypes.NewBaseAccount(addr, nil, 7, 3))
+		if err != nil {
+			return nil, err
+		}
+		return &authtypes.QueryAccountResponse{Account: account}, nil
+	})
+	return n
+}
+
+// run sends a vote of a test key on behalf of a voter through the node
+func (n *flakyBroadcastNode) run(mode string) (string, error) {
+	kr := keyring.NewInMemory(n.clientCtx().Codec, evmkeyring.Option())
+	_, err := kr.NewAccount("relayer", testMnemonic, "", hd.CreateHDPath(60, 0, 0).String(), evmhd.EthSecp256k1)
+	require.NoError(n.t, err)
+
+	var out bytes.Buffer
+	clientCtx := n.clientCtx().
+		WithClient(n).
+		WithKeyring(kr).
+		WithAccountRetriever(authtypes.AccountRetriever{}).
+		WithOutput(&out)
+	rootCmd := &cobra.Command{
+		Use: "tacchaind",
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			return setBroadcastRetry(cmd)
+		},
+	}
+	rootCmd.AddCommand(TacTxCmd())
+	rootCmd.SetArgs([]string{
+		"tac", "exec-vote", "tac1npvwllfr9dqr8erajqqr6s0vxnk2ak55v6q28s", "1", "yes",
+		"--from", "relayer", "--chain-id", "tacchain_239-1", "--gas", "200000", "--yes",
+		"--broadcast-mode", mode, "--output", "json",
+	})
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	err = rootCmd.ExecuteContext(context.WithValue(context.Background(), client.ClientContextKey, &clientCtx))
+	return out.String(), err
+}
+
+// sequences returns the sequences the txs the node received are signed with
+func (n *flakyBroadcastNode) sequences() []uint64 {
+	var sequences []uint64
+	for _, bz := range n.txs {
+		tx, err := n.clientCtx().TxConfig.TxDecoder()(bz)
+		require.NoError(n.t, err)
+		sigs, err := tx.(authsigning.SigVerifiableTx).GetSignaturesV2()
+		require.NoError(n.t, err)
+		require.Len(n.t, sigs, 1)
+		sequences = append(sequences, sigs[0].Sequence)
+	}
+	return sequences
+}
+
+func TestBroadcastSyncRetry(t *testing.T) {
+	node := newFlakyBroadcastNode(t, 1)
+	out, err := node.run(BroadcastSyncRetry)
+	require.NoError(t, err)
+	require.Contains(t, out, `"code":0`)
+	// signed again with the sequence the node expects
+	require.Equal(t, []uint64{3, 5}, node.sequences())
+	require.Contains(t, out, fmt.Sprintf("%X", node.txs[1].Hash()))
+
+	// the sync mode doesn't retry
+	node = newFlakyBroadcastNode(t, 1)
+	out, err = node.run(flags.BroadcastSync)
+	require.NoError(t, err)
+	require.Contains(t, out, fmt.Sprintf(`"code":%d`, sdkerrors.ErrWrongSequence.ABCICode()))
+	require.Equal(t, []uint64{3}, node.sequences())
+}
+
+func TestAddBroadcastRetryMode(t *testing.T) {
+	rootCmd := &cobra.Command{Use: "tacchaind"}
+	rootCmd.AddCommand(TacTxCmd())
+	addBroadcastRetryMode(rootCmd)
+	addBroadcastRetryMode(rootCmd)
+
+	for _, cmd := range TacTxCmd().Commands() {
+		cmd, _, err := rootCmd.Find([]string{"tac", cmd.Name()})
+		require.NoError(t, err)
+		usage := cmd.Flags().Lookup(flags.FlagBroadcastMode).Usage
+		require.Contains(t, usage, "(sync|async|sync-retry)")
+		require.Equal(t, 2, bytes.Count([]byte(usage), []byte(BroadcastSyncRetry)))
+	}
+}
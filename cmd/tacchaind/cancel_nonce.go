@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	evmfeemarkettypes "github.com/cosmos/evm/x/feemarket/types"
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+)
+
+const flagPriceBump = "price-bump"
+
+// cancelNonceGas is the gas of the self-send cancelling a nonce
+const cancelNonceGas = 21000
+
+// addEVMTxCmds adds the chain specific EVM tx commands to the tx evm command
+// of the modules, created by autocli after the root command is built.
+func addEVMTxCmds(rootCmd *cobra.Command) {
+	var txCmd, evmCmd *cobra.Command
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == "tx" {
+			txCmd = cmd
+		}
+	}
+	if txCmd == nil {
+		return
+	}
+	for _, cmd := range txCmd.Commands() {
+		if cmd.Name() == "evm" {
+			evmCmd = cmd
+		}
+	}
+	if evmCmd == nil {
+		evmCmd = &cobra.Command{
+			Use:                        "evm",
+			Short:                      "EVM transaction subcommands",
+			DisableFlagParsing:         true,
+			SuggestionsMinimumDistance: 2,
+			RunE:                       client.ValidateCmd,
+		}
+		txCmd.AddCommand(evmCmd)
+	}
+	evmCmd.AddCommand(CancelNonceCmd())
+}
+
+// CancelNonceCmd unblocks the EVM txs of an account stuck behind a pending tx
+// by replacing it with a self-send at the same nonce.
+func CancelNonceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel-nonce [nonce]",
+		Short: "Replace the pending EVM tx of the sender at nonce with a self-send paying higher fees",
+		Long: fmt.Sprintf(`Replace the pending EVM tx of the sender at nonce with a self-send paying higher fees.
+
+The nonce defaults to the nonce of the account in the last block, the one of the tx
+holding back the others. The self-send transfers nothing, it raises the fee cap and the
+tip cap of the pending tx found in the mempool of the node by --price-bump percent, or
+pays the current gas price raised by --price-bump percent when the node has no pending
+tx at the nonce. Nodes accept the replacement when it raises both caps by their
+%s percent, %d%% by default.
+
+The key of the sender must be an eth_secp256k1 key.`, app.FlagEVMPriceBump, app.DefaultEVMPriceBump),
+		Example: "tacchaind tx evm cancel-nonce 42 --from mykey",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+			priceBump, err := cmd.Flags().GetUint64(flagPriceBump)
+			if err != nil {
+				return err
+			}
+			var nonce *uint64
+			if len(args) > 0 {
+				n, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid nonce %s: %w", args[0], err)
+				}
+				nonce = &n
+			}
+
+			ctx := cmd.Context()
+			txData, err := cancelNonceTx(ctx, clientCtx, clientCtx.GetFromAddress(), nonce, priceBump)
+			if err != nil {
+				return err
+			}
+			return broadcastEVMTx(ctx, clientCtx, ethtypes.NewTx(txData))
+		},
+	}
+
+	cmd.Flags().Uint64(flagPriceBump, app.DefaultEVMPriceBump, "Percentage the fees of the self-send are raised by")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// broadcastEVMTx signs ethTx with the key of the sender of clientCtx, which
+// must be an eth_secp256k1 key, and broadcasts it wrapped in a Cosmos tx.
+func broadcastEVMTx(ctx context.Context, clientCtx client.Context, ethTx *ethtypes.Transaction) error {
+	evmParams, err := evmvmtypes.NewQueryClient(clientCtx).Params(ctx, &evmvmtypes.QueryParamsRequest{})
+	if err != nil {
+		return err
+	}
+
+	msg := &evmvmtypes.MsgEthereumTx{}
+	if err := msg.FromEthereumTx(ethTx); err != nil {
+		return err
+	}
+	msg.From = common.BytesToAddress(clientCtx.GetFromAddress()).Hex()
+	if err := msg.Sign(ethtypes.LatestSignerForChainID(ethTx.ChainId()), clientCtx.Keyring); err != nil {
+		return fmt.Errorf("failed to sign the EVM tx, the key must be an eth_secp256k1 key: %w", err)
+	}
+	tx, err := msg.BuildTx(clientCtx.TxConfig.NewTxBuilder(), evmParams.Params.EvmDenom)
+	if err != nil {
+		return err
+	}
+	txBytes, err := clientCtx.TxConfig.TxEncoder()(tx)
+	if err != nil {
+		return err
+	}
+	res, err := clientCtx.BroadcastTx(txBytes)
+	if err != nil {
+		return err
+	}
+	return clientCtx.PrintProto(res)
+}
+
+// cancelNonceTx returns the unsigned self-send of sender replacing its EVM tx
+// at nonce, the nonce of the account in the last block when nil.
+func cancelNonceTx(ctx context.Context, clientCtx client.Context, sender sdk.AccAddress, nonce *uint64, priceBump uint64) (*ethtypes.DynamicFeeTx, error) {
+	chainID, err := app.EVMChainID(clientCtx.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	from := common.BytesToAddress(sender)
+
+	account, err := evmvmtypes.NewQueryClient(clientCtx).Account(ctx, &evmvmtypes.QueryAccountRequest{Address: from.Hex()})
+	if err != nil {
+		return nil, err
+	}
+	if nonce == nil {
+		nonce = &account.Nonce
+	}
+	// the txs at lower nonces are already in blocks
+	if *nonce < account.Nonce {
+		return nil, fmt.Errorf("nonce %d of %s is already used, the next nonce of the account is %d", *nonce, from.Hex(), account.Nonce)
+	}
+
+	feeMarketParams, err := evmfeemarkettypes.NewQueryClient(clientCtx).Params(ctx, &evmfeemarkettypes.QueryParamsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	gasPrice := bridgeGasPrice(feeMarketParams.Params).Ceil().TruncateInt().BigInt()
+
+	feeCap, tipCap := bumpPrice(gasPrice, priceBump), bumpPrice(gasPrice, priceBump)
+	pending, err := pendingEVMTx(ctx, clientCtx, sender, *nonce)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		pendingFeeCap, ok := new(big.Int).SetString(pending.GasFeeCap, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid fee cap of the pending tx %s: %s", pending.Hash, pending.GasFeeCap)
+		}
+		pendingTipCap, ok := new(big.Int).SetString(pending.GasTipCap, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid tip cap of the pending tx %s: %s", pending.Hash, pending.GasTipCap)
+		}
+		feeCap, tipCap = bumpPrice(pendingFeeCap, priceBump), bumpPrice(pendingTipCap, priceBump)
+		// the self-send still pays the current gas price
+		if feeCap.Cmp(gasPrice) < 0 {
+			feeCap = gasPrice
+		}
+	}
+
+	return &ethtypes.DynamicFeeTx{
+		ChainID:   new(big.Int).SetUint64(chainID),
+		Nonce:     *nonce,
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       cancelNonceGas,
+		To:        &from,
+		Value:     big.NewInt(0),
+	}, nil
+}
+
+// pendingEVMTx returns the EVM tx of sender at nonce in the mempool of the
+// node, nil if there is none
+func pendingEVMTx(ctx context.Context, clientCtx client.Context, sender sdk.AccAddress, nonce uint64) (*MempoolEVMTx, error) {
+	mempool, err := queryMempool(ctx, clientCtx, sender, DefaultMempoolLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the mempool of the node: %w", err)
+	}
+	for _, tx := range mempool.Txs {
+		for _, msg := range tx.Msgs {
+			if msg.EVM != nil && msg.EVM.Nonce == nonce {
+				return msg.EVM, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// bumpPrice raises price by priceBump percent, rounded up, and by at least 1
+// so a replacement of a zero tip cap pays a tip
+func bumpPrice(price *big.Int, priceBump uint64) *big.Int {
+	bumped := new(big.Int).Mul(price, new(big.Int).SetUint64(100+priceBump))
+	bumped.Add(bumped, big.NewInt(99))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(price) <= 0 {
+		bumped.Add(price, big.NewInt(1))
+	}
+	return bumped
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	evmfeemarkettypes "github.com/cosmos/evm/x/feemarket/types"
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+)
+
+func TestBumpPrice(t *testing.T) {
+	require.Equal(t, big.NewInt(110), bumpPrice(big.NewInt(100), 10))
+	require.Equal(t, big.NewInt(112), bumpPrice(big.NewInt(101), 10), "rounded up")
+	require.Equal(t, big.NewInt(2), bumpPrice(big.NewInt(1), 10), "raised by at least 1")
+	require.Equal(t, big.NewInt(1), bumpPrice(big.NewInt(0), 10))
+	require.Equal(t, big.NewInt(200), bumpPrice(big.NewInt(100), 100))
+
+	// the bumped prices pass the replacement rule of the nodes
+	for _, price := range []int64{0, 1, 7, 100, 1_000_000_007} {
+		tx := gethtypes.NewTx(&gethtypes.DynamicFeeTx{
+			GasFeeCap: bumpPrice(big.NewInt(price), app.DefaultEVMPriceBump),
+			GasTipCap: bumpPrice(big.NewInt(price), app.DefaultEVMPriceBump),
+		})
+		require.NoError(t, app.CheckPriceBump(big.NewInt(price), big.NewInt(price), tx, app.DefaultEVMPriceBump))
+	}
+}
+
+func TestCancelNonceTx(t *testing.T) {
+	cfg := tacsdk.MakeEncodingConfig()
+	node := newMockNode(t, 10)
+	clientCtx := node.clientCtx().WithChainID(tacsdk.TestnetChainID)
+	ctx := context.Background()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	sender := sdk.AccAddress(from.Bytes())
+	evmChainID, err := app.EVMChainID(tacsdk.TestnetChainID)
+	require.NoError(t, err)
+	chainID := new(big.Int).SetUint64(evmChainID)
+
+	handle(node, "/cosmos.evm.vm.v1.Query/Account", func(req *evmvmtypes.QueryAccountRequest) (proto.Message, error) {
+		require.Equal(t, from.Hex(), req.Address)
+		return &evmvmtypes.QueryAccountResponse{Balance: "1000", Nonce: 5}, nil
+	})
+	baseFee := int64(100)
+	handle(node, "/cosmos.evm.feemarket.v1.Query/Params", func(*evmfeemarkettypes.QueryParamsRequest) (proto.Message, error) {
+		params := evmfeemarkettypes.DefaultParams()
+		params.BaseFee = math.LegacyNewDec(baseFee)
+		params.MinGasPrice = math.LegacyNewDec(20)
+		return &evmfeemarkettypes.QueryParamsResponse{Params: params}, nil
+	})
+
+	// a pending tx of the sender at nonce 6
+	pending, err := gethtypes.SignNewTx(key, gethtypes.LatestSignerForChainID(chainID), &gethtypes.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     6,
+		To:        &common.Address{},
+		Gas:       50000,
+		GasFeeCap: big.NewInt(300),
+		GasTipCap: big.NewInt(50),
+		Value:     big.NewInt(1),
+	})
+	require.NoError(t, err)
+	msg := &evmvmtypes.MsgEthereumTx{}
+	require.NoError(t, msg.FromEthereumTx(pending))
+	builder := cfg.TxConfig.NewTxBuilder()
+	require.NoError(t, builder.SetMsgs(msg))
+	pendingTx, err := cfg.TxConfig.TxEncoder()(builder.GetTx())
+	require.NoError(t, err)
+	node.unconfirmed = []cmttypes.Tx{pendingTx}
+
+	nonce := func(n uint64) *uint64 { return &n }
+	selfSend := func(nonce uint64, feeCap, tipCap int64) *gethtypes.DynamicFeeTx {
+		return &gethtypes.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(tipCap),
+			GasFeeCap: big.NewInt(feeCap),
+			Gas:       cancelNonceGas,
+			To:        &from,
+			Value:     big.NewInt(0),
+		}
+	}
+
+	// the nonce of the last block has no pending tx, the self-send pays the
+	// bumped gas price
+	txData, err := cancelNonceTx(ctx, clientCtx, sender, nil, 10)
+	require.NoError(t, err)
+	require.Equal(t, selfSend(5, 110, 110), txData)
+
+	// the pending tx is replaced with its fees bumped
+	txData, err = cancelNonceTx(ctx, clientCtx, sender, nonce(6), 10)
+	require.NoError(t, err)
+	require.Equal(t, selfSend(6, 330, 55), txData)
+	txData, err = cancelNonceTx(ctx, clientCtx, sender, nonce(6), 100)
+	require.NoError(t, err)
+	require.Equal(t, selfSend(6, 600, 100), txData)
+
+	// the self-send still pays the gas price when it rose above the bumped
+	// fee cap of the pending tx
+	baseFee = 500
+	txData, err = cancelNonceTx(ctx, clientCtx, sender, nonce(6), 10)
+	require.NoError(t, err)
+	require.Equal(t, selfSend(6, 500, 55), txData)
+
+	_, err = cancelNonceTx(ctx, clientCtx, sender, nonce(4), 10)
+	require.ErrorContains(t, err, "nonce 4 of "+from.Hex()+" is already used, the next nonce of the account is 5")
+	_, err = cancelNonceTx(ctx, clientCtx.WithChainID("tacchain"), sender, nil, 10)
+	require.ErrorContains(t, err, "invalid chain id")
+}
+
+func TestAddEVMTxCmds(t *testing.T) {
+	findCancelNonce := func(rootCmd *cobra.Command) *cobra.Command {
+		cmd, _, err := rootCmd.Find([]string{"tx", "evm", "cancel-nonce"})
+		require.NoError(t, err)
+		return cmd
+	}
+
+	// the command joins the tx evm command of the modules
+	rootCmd := &cobra.Command{Use: "tacchaind"}
+	txCmd := &cobra.Command{Use: "tx"}
+	evmCmd := &cobra.Command{Use: "evm"}
+	evmCmd.AddCommand(&cobra.Command{Use: "raw", Run: func(*cobra.Command, []string) {}})
+	txCmd.AddCommand(evmCmd)
+	rootCmd.AddCommand(txCmd)
+	addEVMTxCmds(rootCmd)
+	require.Equal(t, "cancel-nonce", findCancelNonce(rootCmd).Name())
+	require.Len(t, evmCmd.Commands(), 2)
+
+	// or a new one
+	rootCmd = &cobra.Command{Use: "tacchaind"}
+	rootCmd.AddCommand(&cobra.Command{Use: "tx"})
+	addEVMTxCmds(rootCmd)
+	require.Equal(t, "cancel-nonce", findCancelNonce(rootCmd).Name())
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/spf13/viper"
 
 	"cosmossdk.io/log"
-	confixcmd "cosmossdk.io/tools/confix/cmd"
 
 	"github.com/cosmos/cosmos-sdk/baseapp"
 	"github.com/cosmos/cosmos-sdk/client"
@@ -20,7 +19,6 @@ import (
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/cosmos/cosmos-sdk/client/pruning"
 	"github.com/cosmos/cosmos-sdk/client/rpc"
-	"github.com/cosmos/cosmos-sdk/client/snapshot"
 	"github.com/cosmos/cosmos-sdk/server"
 	servertypes "github.com/cosmos/cosmos-sdk/server/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -29,6 +27,7 @@ import (
 	genutilcli "github.com/cosmos/cosmos-sdk/x/genutil/client/cli"
 
 	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/tacgov"
 
 	evmclient "github.com/cosmos/evm/client"
 	evmserver "github.com/cosmos/evm/server"
@@ -39,14 +38,18 @@ func initRootCmd(appInstance *app.TacChainApp, rootCmd *cobra.Command) {
 	cfg := sdk.GetConfig()
 	cfg.Seal()
 
+	genesisCmd := genutilcli.Commands(appInstance.TxConfig(), appInstance.BasicModuleManager, app.DefaultNodeHome)
+	genesisCmd.AddCommand(ValidateGenTxsCmd(appInstance.AppCodec(), appInstance.TxConfig()))
+
 	rootCmd.AddCommand(
-		evmclient.ValidateChainID(genutilcli.InitCmd(appInstance.BasicModuleManager, app.DefaultNodeHome)),
-		genutilcli.Commands(appInstance.TxConfig(), appInstance.BasicModuleManager, app.DefaultNodeHome),
+		evmclient.ValidateChainID(InitCmd(appInstance.BasicModuleManager, app.DefaultNodeHome)),
+		genesisCmd,
 		cmtcli.NewCompletionCmd(rootCmd, true),
 		debug.Cmd(),
-		confixcmd.ConfigCommand(),
+		ConfigCmd(),
 		pruning.Cmd(newApp, app.DefaultNodeHome),
-		snapshot.Cmd(newApp),
+		SnapshotsCmd(newApp),
+		UnsafeResetAllCmd(),
 	)
 
 	// add Cosmos EVM' flavored TM commands to start server, etc.
@@ -57,28 +60,73 @@ func initRootCmd(appInstance *app.TacChainApp, rootCmd *cobra.Command) {
 		addModuleInitFlags,
 	)
 
-	// add Cosmos EVM key commands
-	rootCmd.AddCommand(
-		evmclient.KeyCommands(app.DefaultNodeHome, true),
+	// check the genesis validator set once the gentxs are collected
+	collectCmd, _, err := rootCmd.Find([]string{"genesis", "collect-gentxs"})
+	if err != nil {
+		panic(err)
+	}
+	addGenesisValidatorsCheck(collectCmd, appInstance.AppCodec(), appInstance.TxConfig())
+
+	// refuse to start a validator whose sign state is behind its watermarks,
+	// and prune the block results past their retention
+	startCmd, _, err := rootCmd.Find([]string{"start"})
+	if err != nil {
+		panic(err)
+	}
+	addSignWatermarkCheck(startCmd)
+	addBlockResultsPruning(startCmd)
+
+	// add Cosmos EVM key commands, along with the node key backups and the
+	// bulk recovery of a mnemonics file
+	keysCmd := evmclient.KeyCommands(app.DefaultNodeHome, true)
+	addKeyDerivationCheck(keysCmd)
+	addMnemonicsSource(keysCmd)
+	keysCmd.AddCommand(
+		BackupNodeCmd(),
+		RestoreNodeCmd(),
 	)
+	rootCmd.AddCommand(keysCmd)
 
 	// add keybase, auxiliary RPC, query, genesis, and tx child commands
 	rootCmd.AddCommand(
 		server.StatusCommand(),
 		queryCommand(),
 		txCommand(),
+		FaucetCmd(),
+		ToolsCmd(),
 	)
 
 	// add general tx flags to the root command
-	var err error
 	_, err = evmsrvflags.AddTxFlags(rootCmd)
 	if err != nil {
 		panic(err)
 	}
 }
 
+// addSignWatermarkCheck runs the double sign protection check of app.toml
+// before the node starts. Nodes signing through a remote signer leave the
+// check to the signer.
+func addSignWatermarkCheck(startCmd *cobra.Command) {
+	preRunE := startCmd.PreRunE
+	startCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if preRunE != nil {
+			if err := preRunE(cmd, args); err != nil {
+				return err
+			}
+		}
+
+		serverCtx := server.GetServerContextFromCmd(cmd)
+		if serverCtx.Config.PrivValidatorListenAddr != "" {
+			return nil
+		}
+		cfg := app.DoubleSignProtectionConfigFromAppOptions(serverCtx.Viper)
+		return app.CheckSignWatermark(cmd.Context(), cfg, serverCtx.Config.PrivValidatorStateFile())
+	}
+}
+
 func addModuleInitFlags(cmd *cobra.Command) {
 	crisis.AddModuleInitFlags(cmd)
+	tacgov.AddModuleInitFlags(cmd)
 }
 
 func queryCommand() *cobra.Command {
@@ -97,7 +145,12 @@ func queryCommand() *cobra.Command {
 		authcmd.QueryTxsByEventsCmd(),
 		authcmd.QueryTxCmd(),
 		server.QueryBlockCmd(),
-		server.QueryBlockResultsCmd(),
+		BlockResultsCmd(),
+		NodeInfoExtendedCmd(),
+		AccountNonceCmd(),
+		EVMGasBreakdownCmd(),
+		FileDescriptorsCmd(),
+		TacQueryCmd(),
 	)
 
 	return cmd
@@ -122,6 +175,7 @@ func txCommand() *cobra.Command {
 		authcmd.GetEncodeCommand(),
 		authcmd.GetDecodeCommand(),
 		authcmd.GetSimulateCmd(),
+		TacTxCmd(),
 	)
 
 	cmd.PersistentFlags().String(flags.FlagChainID, "", "The network chain ID")
@@ -138,7 +192,7 @@ func newApp(
 ) servertypes.Application {
 	baseappOptions := server.DefaultBaseappOptions(appOpts)
 
-	return app.NewTacChainApp(
+	tacChainApp := app.NewTacChainApp(
 		logger,
 		db,
 		traceStore,
@@ -148,6 +202,10 @@ func newApp(
 		app.SetupEvmConfig,
 		baseappOptions...,
 	)
+	// the JSON-RPC server of the node runs in the same process
+	nodeLogIndex.Store(tacChainApp.LogIndex())
+	nodeReceiptStore.Store(tacChainApp.ReceiptStore())
+	return tacChainApp
 }
 
 // appExport creates a new TacChain app (optionally at a given height) and exports state.
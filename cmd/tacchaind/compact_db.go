@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/server"
+
+	"github.com/Asphere-xyz/tacchain/app"
+)
+
+// applicationDB is the name of the database of the application state
+const applicationDB = "application"
+
+// CompactDBCmd compacts the databases of a stopped node.
+func CompactDBCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "compact-db [db...]",
+		Short: "Compact the databases of the node, all of them if none is given",
+		Long: `Compact the databases of the node, all of them if none is given.
+
+Databases are given by their name in the data dir without the .db suffix, e.g.
+application, blockstore or state. The application database uses the app-db-backend
+of app.toml, the others the db_backend of config.toml. The node must be stopped.
+
+Set interval in the [compaction] section of app.toml to compact the application
+database while the node runs.`,
+		Example: "tacchaind tools compact-db application blockstore",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := server.GetServerContextFromCmd(cmd)
+			dataDir := filepath.Join(ctx.Config.RootDir, "data")
+
+			names := args
+			if len(names) == 0 {
+				var err error
+				if names, err = listDBs(dataDir); err != nil {
+					return err
+				}
+			}
+
+			for _, name := range names {
+				backend := dbm.BackendType(ctx.Config.DBBackend)
+				if name == applicationDB {
+					backend = server.GetAppDBBackend(ctx.Viper)
+				}
+
+				before, after, err := compactDB(name, backend, dataDir)
+				if err != nil {
+					return fmt.Errorf("failed to compact %s: %w", name, err)
+				}
+				cmd.Printf("%s: %d -> %d bytes\n", name, before, after)
+			}
+			return nil
+		},
+	}
+}
+
+// listDBs returns the names of the databases in dataDir
+func listDBs(dataDir string) ([]string, error) {
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasSuffix(entry.Name(), ".db") {
+			names = append(names, strings.TrimSuffix(entry.Name(), ".db"))
+		}
+	}
+	return names, nil
+}
+
+// compactDB compacts the database name in dir and returns its size before
+// and after the compaction.
+func compactDB(name string, backend dbm.BackendType, dir string) (int64, int64, error) {
+	path := filepath.Join(dir, name+".db")
+	if _, err := os.Stat(path); err != nil {
+		return 0, 0, err
+	}
+	before, err := dirSize(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	db, err := dbm.NewDB(name, backend, dir)
+	if err != nil {
+		return 0, 0, err
+	}
+	err = app.CompactDB(db)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	after, err := dirSize(path)
+	return before, after, err
+}
+
+// dirSize returns the size of the files in dir
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
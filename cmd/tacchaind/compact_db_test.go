@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactDB(t *testing.T) {
+	dir := t.TempDir()
+	db, err := dbm.NewDB("blockstore", dbm.GoLevelDBBackend, dir)
+	require.NoError(t, err)
+
+	// the space of overwritten and deleted keys is reclaimed by compaction
+	value := make([]byte, 4096)
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 2000; i++ {
+			require.NoError(t, db.Set([]byte(fmt.Sprintf("key%06d", i)), value))
+		}
+	}
+	for i := 100; i < 2000; i++ {
+		require.NoError(t, db.Delete([]byte(fmt.Sprintf("key%06d", i))))
+	}
+	require.NoError(t, db.Close())
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "not-a-db"), 0o755))
+
+	names, err := listDBs(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"blockstore"}, names)
+
+	before, after, err := compactDB("blockstore", dbm.GoLevelDBBackend, dir)
+	require.NoError(t, err)
+	require.Less(t, after, before)
+
+	db, err = dbm.NewDB("blockstore", dbm.GoLevelDBBackend, dir)
+	require.NoError(t, err)
+	defer db.Close()
+	value, err = db.Get([]byte("key000099"))
+	require.NoError(t, err)
+	require.Len(t, value, 4096)
+	value, err = db.Get([]byte("key000100"))
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	_, _, err = compactDB("missing", dbm.GoLevelDBBackend, dir)
+	require.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	confixcmd "cosmossdk.io/tools/confix/cmd"
+
+	"github.com/cosmos/cosmos-sdk/client"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/app/upgrades"
+)
+
+// tacAppConfigSections are the sections app.toml has on top of the ones of
+// the SDK and Cosmos EVM, with the config each decodes to
+func tacAppConfigSections() map[string]any {
+	return map[string]any{
+		"backup":                 &upgrades.BackupConfig{},
+		"tx-limits":              &app.TxLimitsConfig{},
+		"double-sign-protection": &app.DoubleSignProtectionConfig{},
+		"query-limits":           &app.QueryLimitsConfig{},
+		"compaction":             &app.CompactionConfig{},
+		"priority-lanes":         &app.PriorityLanesConfig{},
+		"gas-profile":            &app.GasProfileConfig{},
+		"log-index":              &app.LogIndexConfig{},
+		"receipts":               &app.ReceiptsConfig{},
+		"alerting":               &app.AlertingConfig{},
+		"shutdown":               &app.ShutdownConfig{},
+		"block-results":          &app.BlockResultsConfig{},
+	}
+}
+
+// ConfigCmd returns the config command reading and updating the keys of
+// app.toml, client.toml and config.toml. It is the SDK config command, except
+// that set also validates the tac sections of app.toml: the node reads them
+// with casts that turn a malformed value into a zero, which usually disables
+// the setting, so set refuses the value and keeps the file as it was.
+func ConfigCmd() *cobra.Command {
+	cmd := confixcmd.ConfigCommand()
+
+	setCmd, _, err := cmd.Find([]string{"set"})
+	if err != nil || setCmd == cmd {
+		panic("config command has no set subcommand")
+	}
+	set := setCmd.RunE
+	setCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		skipValidate, _ := cmd.Flags().GetBool("skip-validate")
+		stdout, _ := cmd.Flags().GetBool("stdout")
+		homeDir := client.GetClientContextFromCmd(cmd).HomeDir
+		if args[0] != "app" || skipValidate || stdout || homeDir == "" {
+			return set(cmd, args)
+		}
+
+		path := filepath.Join(homeDir, "config", "app.toml")
+		original, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := set(cmd, args); err != nil {
+			return err
+		}
+		if err := validateAppConfig(path); err != nil {
+			if restoreErr := os.WriteFile(path, original, 0o644); restoreErr != nil {
+				return fmt.Errorf("%w, and failed to restore %s: %v", err, path, restoreErr)
+			}
+			return fmt.Errorf("%w, %s is unchanged", err, path)
+		}
+		return nil
+	}
+	return cmd
+}
+
+// validateAppConfig checks the tac sections of the app.toml at path decode
+// to their config without conversion and without unknown keys
+func validateAppConfig(path string) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	for section, config := range tacAppConfigSections() {
+		if !v.IsSet(section) {
+			continue
+		}
+		err := v.UnmarshalKey(section, config, func(dc *mapstructure.DecoderConfig) {
+			dc.WeaklyTypedInput = false
+			dc.ErrorUnused = true
+		})
+		if err != nil {
+			return fmt.Errorf("invalid [%s] section: %w", section, err)
+		}
+	}
+	return nil
+}
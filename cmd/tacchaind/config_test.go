@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/client"
+)
+
+// writeAppConfig writes the default app.toml of the node to home and returns
+// its path
+func writeAppConfig(t *testing.T, home string) string {
+	t.Helper()
+	appTemplate, appConfig := initAppConfig()
+	path := filepath.Join(home, "config", "app.toml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+
+	var buf strings.Builder
+	require.NoError(t, template.Must(template.New("app").Parse(appTemplate)).Execute(&buf, appConfig))
+	require.NoError(t, os.WriteFile(path, []byte(buf.String()), 0o644))
+	return path
+}
+
+func TestValidateAppConfig(t *testing.T) {
+	path := writeAppConfig(t, t.TempDir())
+	require.NoError(t, validateAppConfig(path))
+
+	original, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for name, tc := range map[string]struct {
+		old, new, err string
+	}{
+		"string for a number": {"max-gas-wanted = 0", `max-gas-wanted = "abc"`, "[tx-limits]"},
+		"negative number":     {"max-gas-wanted = 0", "max-gas-wanted = -1", "[tx-limits]"},
+		"number for a bool":   {"reject-unprotected-evm-txs = true", "reject-unprotected-evm-txs = 1", "[tx-limits]"},
+		"unknown key":         {"[compaction]", "[compaction]\nintervall = 10", "[compaction]"},
+		"number for a string": {`dir = ""`, "dir = 1", "[backup]"},
+	} {
+		require.Contains(t, string(original), tc.old, name)
+		updated := strings.Replace(string(original), tc.old, tc.new, 1)
+		require.NoError(t, os.WriteFile(path, []byte(updated), 0o644))
+		require.ErrorContains(t, validateAppConfig(path), tc.err, name)
+	}
+}
+
+func TestConfigSet(t *testing.T) {
+	home := t.TempDir()
+	path := writeAppConfig(t, home)
+
+	run := func(args ...string) (string, error) {
+		cmd := ConfigCmd()
+		var out strings.Builder
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		clientCtx := client.Context{HomeDir: home, Output: &out}
+		ctx := context.WithValue(context.Background(), client.ClientContextKey, &clientCtx)
+		err := cmd.ExecuteContext(ctx)
+		return strings.TrimSpace(out.String()), err
+	}
+
+	out, err := run("get", "app", "tx-limits.max-gas-wanted")
+	require.NoError(t, err)
+	require.Equal(t, "0", out)
+
+	_, err = run("set", "app", "tx-limits.max-gas-wanted", "1000000")
+	require.NoError(t, err)
+	out, err = run("get", "app", "tx-limits.max-gas-wanted")
+	require.NoError(t, err)
+	require.Equal(t, "1000000", out)
+
+	// a malformed value is refused and app.toml is left as it was
+	before, err := os.ReadFile(path)
+	require.NoError(t, err)
+	_, err = run("set", "app", "tx-limits.max-gas-wanted", "abc")
+	require.ErrorContains(t, err, "invalid [tx-limits] section")
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(before), string(after))
+
+	_, err = run("set", "app", "tx-limits.max-gas-wanted", "abc", "--skip-validate")
+	require.NoError(t, err)
+	require.Error(t, validateAppConfig(path))
+}
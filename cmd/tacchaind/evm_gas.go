@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/server"
+	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+
+	evmtypes "github.com/cosmos/evm/x/vm/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+)
+
+const flagEVMRPC = "evm-rpc"
+
+// structLogTrace is the part of a debug_traceTransaction struct logger result
+// needed to measure execution gas and refunds.
+type structLogTrace struct {
+	StructLogs []struct {
+		Gas     uint64 `json:"gas"`
+		GasCost uint64 `json:"gasCost"`
+		Refund  uint64 `json:"refund"`
+	} `json:"structLogs"`
+}
+
+// EVMGasBreakdownCmd decomposes the gas used by an EVM tx into intrinsic gas,
+// execution gas, refund and the min gas multiplier adjustment.
+func EVMGasBreakdownCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "evm-gas-breakdown [eth_tx_hash]",
+		Short: "Decompose the gas used by an EVM tx into intrinsic, execution and refund components",
+		Long: `Decompose the gas used by an EVM tx into intrinsic, execution and refund components.
+
+The tx is traced through the debug namespace of the JSON-RPC server, which must be enabled.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			if !strings.HasPrefix(args[0], "0x") || len(args[0]) != 66 {
+				return fmt.Errorf("invalid eth tx hash: %s", args[0])
+			}
+			hash := common.HexToHash(args[0])
+
+			evmRPC, err := cmd.Flags().GetString(flagEVMRPC)
+			if err != nil {
+				return err
+			}
+			if evmRPC == "" {
+				evmRPC = "http://" + server.GetServerContextFromCmd(cmd).Viper.GetString("json-rpc.address")
+			}
+
+			rpcClient, err := rpc.DialContext(cmd.Context(), evmRPC)
+			if err != nil {
+				return err
+			}
+			defer rpcClient.Close()
+			ethClient := ethclient.NewClient(rpcClient)
+
+			tx, _, err := ethClient.TransactionByHash(cmd.Context(), hash)
+			if err != nil {
+				return fmt.Errorf("failed to get tx %s: %w", hash, err)
+			}
+			receipt, err := ethClient.TransactionReceipt(cmd.Context(), hash)
+			if err != nil {
+				return fmt.Errorf("failed to get receipt of tx %s: %w", hash, err)
+			}
+
+			var trace structLogTrace
+			traceConfig := map[string]any{"disableStack": true, "disableStorage": true}
+			if err := rpcClient.CallContext(cmd.Context(), &trace, "debug_traceTransaction", hash, traceConfig); err != nil {
+				return fmt.Errorf("failed to trace tx %s, is the debug namespace enabled?: %w", hash, err)
+			}
+
+			intrinsic, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, true, true, true)
+			if err != nil {
+				return err
+			}
+
+			// the first step starts with the gas left after the intrinsic gas,
+			// the last one leaves the gas that wasn't used by the execution
+			var execution, refundCounter uint64
+			if n := len(trace.StructLogs); n > 0 {
+				first, last := trace.StructLogs[0], trace.StructLogs[n-1]
+				execution = first.Gas - (last.Gas - min(last.GasCost, last.Gas))
+				refundCounter = last.Refund
+			}
+
+			res, err := authtx.QueryTxsByEvents(clientCtx, 1, 1, fmt.Sprintf("%s.%s='%s'", evmtypes.EventTypeEthereumTx, evmtypes.AttributeKeyEthereumTxHash, hash.Hex()), "")
+			if err != nil {
+				return err
+			}
+			if len(res.Txs) == 0 {
+				return fmt.Errorf("cosmos tx of %s not found", hash)
+			}
+
+			breakdown := app.NewEVMGasBreakdown(hash.Hex(), tx.Gas(), intrinsic, execution, refundCounter, receipt.GasUsed, res.Txs[0].GasUsed)
+			bz, err := json.MarshalIndent(breakdown, "", "  ")
+			if err != nil {
+				return err
+			}
+			return clientCtx.PrintRaw(bz)
+		},
+	}
+
+	cmd.Flags().String(flagEVMRPC, "", "JSON-RPC endpoint with the debug namespace enabled, defaults to the json-rpc.address of app.toml")
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+const (
+	flagFaucetListenAddr = "listen-addr"
+	flagFaucetAmount     = "amount"
+	flagFaucetCooldown   = "cooldown"
+
+	defaultFaucetListenAddr = "127.0.0.1:8000"
+	// 10 TAC
+	defaultFaucetAmount   = "10000000000000000000utac"
+	defaultFaucetCooldown = 24 * time.Hour
+)
+
+// FaucetCmd serves an HTTP faucet that sends a fixed amount from a local key
+// to every address that asks for it, at most once per cooldown period. It is
+// meant for localnets and testnets only, the key should hold no more than the
+// faucet is allowed to hand out.
+func FaucetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "faucet [from_key_or_address]",
+		Short: "Serve an HTTP faucet sending funds from a local key",
+		Long: `Serve an HTTP faucet sending funds from a local key.
+
+Request funds with either a bech32 or a hex address:
+
+$ curl -X POST -d '{"address":"tac1..."}' http://127.0.0.1:8000/
+$ curl -X POST -d '{"address":"0x..."}' http://127.0.0.1:8000/
+
+Every address is funded at most once per cooldown period.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cmd.Flags().Set(flags.FlagFrom, args[0]); err != nil {
+				return err
+			}
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			amountStr, err := cmd.Flags().GetString(flagFaucetAmount)
+			if err != nil {
+				return err
+			}
+			amount, err := sdk.ParseCoinsNormalized(amountStr)
+			if err != nil {
+				return err
+			}
+			if !amount.IsAllPositive() {
+				return fmt.Errorf("faucet amount must be positive: %s", amountStr)
+			}
+
+			cooldown, err := cmd.Flags().GetDuration(flagFaucetCooldown)
+			if err != nil {
+				return err
+			}
+			listenAddr, err := cmd.Flags().GetString(flagFaucetListenAddr)
+			if err != nil {
+				return err
+			}
+
+			txf, err := tx.NewFactoryCLI(clientCtx, cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			sender := &faucetSender{clientCtx: clientCtx, txf: txf, amount: amount}
+			srv := &http.Server{
+				Addr:              listenAddr,
+				Handler:           newFaucetHandler(amount, cooldown, sender.Send),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- srv.ListenAndServe()
+			}()
+			cmd.Printf("faucet sending %s from %s listening on %s\n", amount, clientCtx.GetFromAddress(), listenAddr)
+
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+			select {
+			case err := <-errCh:
+				return err
+			case <-sigCh:
+			case <-cmd.Context().Done():
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return srv.Shutdown(ctx)
+		},
+	}
+
+	cmd.Flags().String(flagFaucetListenAddr, defaultFaucetListenAddr, "Address the faucet HTTP server listens on")
+	cmd.Flags().String(flagFaucetAmount, defaultFaucetAmount, "Amount sent per request")
+	cmd.Flags().Duration(flagFaucetCooldown, defaultFaucetCooldown, "Minimum time between two requests for the same address")
+	flags.AddTxFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// faucetSender broadcasts the faucet bank sends. Requests are sent one by
+// one, tracking the sequence locally so several sends fit into one block.
+type faucetSender struct {
+	clientCtx client.Context
+	txf       tx.Factory
+	amount    sdk.Coins
+
+	mu       sync.Mutex
+	synced   bool
+	sequence uint64
+}
+
+// Send sends the faucet amount to the given address and returns the tx hash.
+func (s *faucetSender) Send(ctx context.Context, to sdk.AccAddress) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	from := s.clientCtx.GetFromAddress()
+	txf := s.txf
+	if !s.synced {
+		num, seq, err := s.clientCtx.AccountRetriever.GetAccountNumberSequence(s.clientCtx, from)
+		if err != nil {
+			return "", fmt.Errorf("failed to query faucet account: %w", err)
+		}
+		s.txf = s.txf.WithAccountNumber(num)
+		s.sequence = seq
+		s.synced = true
+		txf = s.txf
+	}
+	txf = txf.WithSequence(s.sequence)
+
+	msg := banktypes.NewMsgSend(from, to, s.amount)
+	if txf.SimulateAndExecute() {
+		_, gas, err := tx.CalculateGas(s.clientCtx, txf, msg)
+		if err != nil {
+			s.synced = false
+			return "", err
+		}
+		txf = txf.WithGas(gas)
+	}
+
+	txb, err := txf.BuildUnsignedTx(msg)
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Sign(ctx, txf, s.clientCtx.FromName, txb, true); err != nil {
+		return "", err
+	}
+	txBytes, err := s.clientCtx.TxConfig.TxEncoder()(txb.GetTx())
+	if err != nil {
+		return "", err
+	}
+
+	res, err := s.clientCtx.BroadcastTxSync(txBytes)
+	if err != nil {
+		s.synced = false
+		return "", err
+	}
+	if res.Code != 0 {
+		// most likely a sequence mismatch, re-query the account on the next request
+		s.synced = false
+		return "", fmt.Errorf("faucet tx %s failed with code %d: %s", res.TxHash, res.Code, res.RawLog)
+	}
+
+	s.sequence++
+	return res.TxHash, nil
+}
+
+// faucetRequest is the body of a funding request
+type faucetRequest struct {
+	Address string `json:"address"`
+}
+
+// faucetResponse is the body returned for a successful funding request
+type faucetResponse struct {
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+	TxHash  string `json:"txhash"`
+}
+
+type faucetError struct {
+	Error string `json:"error"`
+}
+
+type sendFunc func(ctx context.Context, to sdk.AccAddress) (string, error)
+
+type faucetHandler struct {
+	amount  sdk.Coins
+	limiter *rateLimiter
+	send    sendFunc
+}
+
+func newFaucetHandler(amount sdk.Coins, cooldown time.Duration, send sendFunc) *faucetHandler {
+	return &faucetHandler{
+		amount:  amount,
+		limiter: newRateLimiter(cooldown),
+		send:    send,
+	}
+}
+
+func (h *faucetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeFaucetJSON(w, http.StatusMethodNotAllowed, faucetError{Error: "only POST is supported"})
+		return
+	}
+
+	var req faucetRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&req); err != nil {
+		writeFaucetJSON(w, http.StatusBadRequest, faucetError{Error: fmt.Sprintf("invalid request: %s", err)})
+		return
+	}
+
+	addr, err := parseAddress(req.Address)
+	if err != nil {
+		writeFaucetJSON(w, http.StatusBadRequest, faucetError{Error: err.Error()})
+		return
+	}
+
+	// hex and bech32 forms of an address share the limit
+	key := addr.String()
+	if wait, ok := h.limiter.Reserve(key); !ok {
+		w.Header().Set("Retry-After", fmt.Sprintf("%d", int64(wait.Seconds())+1))
+		writeFaucetJSON(w, http.StatusTooManyRequests, faucetError{Error: fmt.Sprintf("address %s was funded recently, retry in %s", key, wait.Round(time.Second))})
+		return
+	}
+
+	txHash, err := h.send(r.Context(), addr)
+	if err != nil {
+		h.limiter.Release(key)
+		writeFaucetJSON(w, http.StatusInternalServerError, faucetError{Error: err.Error()})
+		return
+	}
+
+	writeFaucetJSON(w, http.StatusOK, faucetResponse{Address: key, Amount: h.amount.String(), TxHash: txHash})
+}
+
+func writeFaucetJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// parseAddress accepts a bech32 account address or a hex EVM address.
+func parseAddress(address string) (sdk.AccAddress, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return nil, errors.New("address is required")
+	}
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid hex address: %s", address)
+		}
+		return sdk.AccAddress(common.HexToAddress(address).Bytes()), nil
+	}
+	addr, err := sdk.AccAddressFromBech32(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %s: %w", address, err)
+	}
+	return addr, nil
+}
+
+// rateLimiter allows one request per key and cooldown period.
+type rateLimiter struct {
+	mu       sync.Mutex
+	cooldown time.Duration
+	last     map[string]time.Time
+	now      func() time.Time
+}
+
+func newRateLimiter(cooldown time.Duration) *rateLimiter {
+	return &rateLimiter{
+		cooldown: cooldown,
+		last:     make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Reserve records a request for key. It returns false and the remaining
+// time if key already made a request within the cooldown period.
+func (l *rateLimiter) Reserve(key string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	for k, t := range l.last {
+		if now.Sub(t) >= l.cooldown {
+			delete(l.last, k)
+		}
+	}
+
+	if t, ok := l.last[key]; ok {
+		return l.cooldown - now.Sub(t), false
+	}
+	l.last[key] = now
+	return 0, true
+}
+
+// Release drops the reservation of a request that didn't go through.
+func (l *rateLimiter) Release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.last, key)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestParseAddress(t *testing.T) {
+	hexAddr := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	bech32Addr := sdk.AccAddress(hexAddr.Bytes()).String()
+
+	for _, address := range []string{hexAddr.Hex(), strings.ToLower(hexAddr.Hex()), bech32Addr, " " + bech32Addr + " "} {
+		addr, err := parseAddress(address)
+		require.NoError(t, err, address)
+		require.Equal(t, bech32Addr, addr.String())
+	}
+
+	for _, address := range []string{"", "0x1234", "tac1invalid", "cosmos1qyqszqgpqyqszqgpqyqszqgpqyqszqgpjnp7du"} {
+		_, err := parseAddress(address)
+		require.Error(t, err, address)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newRateLimiter(time.Minute)
+	l.now = func() time.Time { return now }
+
+	_, ok := l.Reserve("a")
+	require.True(t, ok)
+	_, ok = l.Reserve("b")
+	require.True(t, ok, "limit is per key")
+
+	now = now.Add(20 * time.Second)
+	wait, ok := l.Reserve("a")
+	require.False(t, ok)
+	require.Equal(t, 40*time.Second, wait)
+
+	now = now.Add(40 * time.Second)
+	_, ok = l.Reserve("a")
+	require.True(t, ok, "cooldown has passed")
+
+	l.Release("a")
+	_, ok = l.Reserve("a")
+	require.True(t, ok, "released requests don't count")
+}
+
+func TestFaucetHandler(t *testing.T) {
+	amount := sdk.NewCoins(sdk.NewInt64Coin("utac", 100))
+	hexAddr := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	bech32Addr := sdk.AccAddress(hexAddr.Bytes()).String()
+
+	var sent []string
+	fail := false
+	h := newFaucetHandler(amount, time.Hour, func(_ context.Context, to sdk.AccAddress) (string, error) {
+		if fail {
+			return "", errors.New("broadcast failed")
+		}
+		sent = append(sent, to.String())
+		return "HASH", nil
+	})
+
+	request := func(method, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := request(http.MethodGet, "")
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = request(http.MethodPost, `{"address":"invalid"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// a failed send doesn't use up the address' request
+	fail = true
+	rec = request(http.MethodPost, `{"address":"`+bech32Addr+`"}`)
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	fail = false
+
+	rec = request(http.MethodPost, `{"address":"`+bech32Addr+`"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var res faucetResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.Equal(t, faucetResponse{Address: bech32Addr, Amount: "100utac", TxHash: "HASH"}, res)
+
+	// the hex form of the same account is rate limited too
+	rec = request(http.MethodPost, `{"address":"`+hexAddr.Hex()+`"}`)
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	require.Equal(t, []string{bech32Addr}, sent)
+}
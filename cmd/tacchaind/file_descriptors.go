@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	reflectionv1 "cosmossdk.io/api/cosmos/reflection/v1"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+)
+
+// FileDescriptorsCmd writes the protobuf file descriptors registered in the
+// connected node as a FileDescriptorSet, the format grpcurl reads with
+// -protoset and buf accepts as a binary image.
+func FileDescriptorsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "file-descriptors [file]",
+		Short: "Query the protobuf file descriptors of the node and write them as a FileDescriptorSet",
+		Long: `Query the protobuf file descriptors of the node and write them as a FileDescriptorSet.
+
+The set covers every service and message of the chain, including the Cosmos SDK, EVM and
+IBC modules. Use it to generate clients, e.g. with buf generate, or pass it to
+grpcurl -protoset when the gRPC server reflection of the node isn't reachable.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			res, err := reflectionv1.NewReflectionServiceClient(clientCtx).FileDescriptors(cmd.Context(), &reflectionv1.FileDescriptorsRequest{})
+			if err != nil {
+				return err
+			}
+
+			bz, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: res.Files})
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(args[0], bz, 0o644); err != nil {
+				return err
+			}
+			cmd.Printf("wrote %d file descriptors to %s\n", len(res.Files), args[0])
+			return nil
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/codec"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	genutiltypes "github.com/cosmos/cosmos-sdk/x/genutil/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// flagGenTxDir is the flag of collect-gentxs for the directory of the gentxs
+const flagGenTxDir = "gentx-dir"
+
+// GenTxReport is the outcome of the checks of the gentxs of a genesis.
+type GenTxReport struct {
+	GenesisFile string       `json:"genesis_file"`
+	GenTxDir    string       `json:"gentx_dir"`
+	GenTxs      []GenTxCheck `json:"gentxs"`
+	// Errors are the problems of the gentxs taken together
+	Errors []string `json:"errors"`
+}
+
+// GenTxCheck is the outcome of the checks of a gentx.
+type GenTxCheck struct {
+	File      string   `json:"file"`
+	Moniker   string   `json:"moniker,omitempty"`
+	Validator string   `json:"validator,omitempty"`
+	Delegator string   `json:"delegator,omitempty"`
+	Amount    string   `json:"amount,omitempty"`
+	Peer      string   `json:"peer,omitempty"`
+	Errors    []string `json:"errors"`
+}
+
+// Problems returns the number of problems found in the gentxs
+func (r GenTxReport) Problems() int {
+	problems := len(r.Errors)
+	for _, genTx := range r.GenTxs {
+		problems += len(genTx.Errors)
+	}
+	return problems
+}
+
+// genTxFile is a gentx read from the gentx directory
+type genTxFile struct {
+	name string
+	bz   json.RawMessage
+}
+
+// ValidateGenTxsCmd checks the gentxs of a directory against the genesis
+// before collect-gentxs adds them.
+func ValidateGenTxsCmd(cdc codec.Codec, txConfig client.TxConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate-gentxs",
+		Short: "Check the gentxs against the genesis before collecting them, reporting the problems found",
+		Long: `Check the gentxs against the genesis before collecting them, reporting the problems found.
+
+Each gentx must hold a single MsgCreateValidator with:
+
+- a self delegation in the bond denom, of at least one voting power and of the min
+  self delegation, which the genesis balance of the validator account covers along
+  with the fees of the gentx;
+- commission rates within their max rate and change rate, and above the
+  min_commission_rate of x/staking;
+- a memo of the form node_id@host:port, the peer collect-gentxs adds to the
+  persistent peers.
+
+The validators, their consensus keys and their node ids must be unique across the
+gentxs and the validators already in the genesis, and the validator set must fit
+max_validators and the max genesis power share of x/selfbond. The gentx
+signatures are checked when the chain starts.
+
+The command fails when a problem is found, the gentxs are read from --gentx-dir,
+config/gentx of the home by default.`,
+		Example: "tacchaind genesis validate-gentxs --gentx-dir ./gentxs --output json",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			config := server.GetServerContextFromCmd(cmd).Config
+			genTxDir, _ := cmd.Flags().GetString(flagGenTxDir)
+			if genTxDir == "" {
+				genTxDir = filepath.Join(config.RootDir, "config", "gentx")
+			}
+			output, _ := cmd.Flags().GetString(flags.FlagOutput)
+
+			appState, _, err := genutiltypes.GenesisStateFromGenFile(config.GenesisFile())
+			if err != nil {
+				return err
+			}
+			files, err := readGenTxDir(genTxDir)
+			if err != nil {
+				return err
+			}
+
+			report := validateGenTxs(cdc, txConfig, appState, files)
+			report.GenesisFile = config.GenesisFile()
+			report.GenTxDir = genTxDir
+			if output == flags.OutputFormatJSON {
+				bz, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				cmd.Println(string(bz))
+			} else {
+				printGenTxReport(cmd, report)
+			}
+
+			if problems := report.Problems(); problems > 0 {
+				return fmt.Errorf("%d problems found in the gentxs of %s", problems, genTxDir)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagGenTxDir, "", "Directory of the gentxs, defaults to config/gentx of the home")
+	cmd.Flags().StringP(flags.FlagOutput, "o", flags.OutputFormatText, "Output format (text|json)")
+
+	return cmd
+}
+
+// readGenTxDir reads the gentxs of dir, the .json files like collect-gentxs
+func readGenTxDir(dir string) ([]genTxFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the gentx directory: %w", err)
+	}
+
+	var files []genTxFile
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		bz, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, genTxFile{name: entry.Name(), bz: bz})
+	}
+	return files, nil
+}
+
+// validateGenTxs checks the gentxs files against appState, the genesis they
+// are collected into
+func validateGenTxs(cdc codec.Codec, txConfig client.TxConfig, appState map[string]json.RawMessage, files []genTxFile) GenTxReport {
+	report := GenTxReport{GenTxs: []GenTxCheck{}, Errors: []string{}}
+	if len(files) == 0 {
+		report.Errors = append(report.Errors, "no gentx found")
+		return report
+	}
+
+	stakingGenesis := stakingtypes.GetGenesisStateFromAppState(cdc, appState)
+	balances := map[string]sdk.Coins{}
+	for _, balance := range banktypes.GetGenesisStateFromAppState(cdc, appState).Balances {
+		balances[balance.Address] = balances[balance.Address].Add(balance.Coins...)
+	}
+
+	// the validators, consensus keys and node ids taken, with what took them
+	validators, consKeys, nodeIDs := map[string]string{}, map[string]string{}, map[string]string{}
+	for _, validator := range stakingGenesis.Validators {
+		owner := "the genesis validator " + validator.OperatorAddress
+		validators[validator.OperatorAddress] = owner
+		if pubKey, err := validator.ConsPubKey(); err == nil {
+			consKeys[sdk.ConsAddress(pubKey.Address()).String()] = owner
+		}
+	}
+	claim := func(check *GenTxCheck, taken map[string]string, key, name string) {
+		if owner, ok := taken[key]; ok {
+			check.Errors = append(check.Errors, fmt.Sprintf("%s %s is already used by %s", name, key, owner))
+			return
+		}
+		taken[key] = check.File
+	}
+
+	var genTxs []json.RawMessage
+	for _, file := range files {
+		check, msg, nodeID := checkGenTx(txConfig, stakingGenesis.Params, balances, file)
+		if msg != nil {
+			genTxs = append(genTxs, file.bz)
+			claim(&check, validators, msg.ValidatorAddress, "validator")
+			if pubKey, ok := msg.Pubkey.GetCachedValue().(cryptotypes.PubKey); ok {
+				claim(&check, consKeys, sdk.ConsAddress(pubKey.Address()).String(), "consensus key")
+			}
+			if nodeID != "" {
+				claim(&check, nodeIDs, nodeID, "node id")
+			}
+		}
+		report.GenTxs = append(report.GenTxs, check)
+	}
+
+	// the validator set the chain starts with once the gentxs are collected
+	collected := maps.Clone(appState)
+	collected[genutiltypes.ModuleName] = cdc.MustMarshalJSON(genutiltypes.NewGenesisState(genTxs))
+	if err := checkGenesisValidators(cdc, txConfig, collected); err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	}
+	return report
+}
+
+// checkGenTx checks a gentx on its own, it returns its MsgCreateValidator and
+// the node id of its memo when they could be read
+func checkGenTx(txConfig client.TxConfig, params stakingtypes.Params, balances map[string]sdk.Coins, file genTxFile) (GenTxCheck, *stakingtypes.MsgCreateValidator, string) {
+	check := GenTxCheck{File: file.name, Errors: []string{}}
+	fail := func(format string, args ...any) {
+		check.Errors = append(check.Errors, fmt.Sprintf(format, args...))
+	}
+
+	tx, err := txConfig.TxJSONDecoder()(file.bz)
+	if err != nil {
+		fail("failed to decode the gentx: %s", err)
+		return check, nil, ""
+	}
+	msgs := tx.GetMsgs()
+	if len(msgs) != 1 {
+		fail("the gentx must hold a single MsgCreateValidator, it holds %d messages", len(msgs))
+		return check, nil, ""
+	}
+	msg, ok := msgs[0].(*stakingtypes.MsgCreateValidator)
+	if !ok {
+		fail("the gentx must hold a MsgCreateValidator, it holds a %s", sdk.MsgTypeURL(msgs[0]))
+		return check, nil, ""
+	}
+	check.Moniker = msg.Description.Moniker
+	check.Validator = msg.ValidatorAddress
+	check.Amount = msg.Value.String()
+
+	valAddr, err := sdk.ValAddressFromBech32(msg.ValidatorAddress)
+	if err != nil {
+		fail("invalid validator address: %s", err)
+		return check, nil, ""
+	}
+	// the validator bonds the tokens of the account of its operator
+	delegator := sdk.AccAddress(valAddr).String()
+	check.Delegator = delegator
+
+	// self delegation and balance
+	switch {
+	case msg.Value.Denom != params.BondDenom:
+		fail("the self delegation of %s isn't in the bond denom %s", msg.Value, params.BondDenom)
+	case msg.Value.Amount.LT(sdk.DefaultPowerReduction):
+		fail("the self delegation of %s gives no voting power, the minimum is %s%s", msg.Value, sdk.DefaultPowerReduction, params.BondDenom)
+	}
+	switch {
+	case msg.MinSelfDelegation.IsNil() || !msg.MinSelfDelegation.IsPositive():
+		fail("the min self delegation must be positive")
+	case msg.Value.Amount.LT(msg.MinSelfDelegation):
+		fail("the self delegation of %s is below the min self delegation of %s", msg.Value, msg.MinSelfDelegation)
+	}
+	needed := sdk.NewCoins(msg.Value)
+	if feeTx, ok := tx.(sdk.FeeTx); ok {
+		needed = needed.Add(feeTx.GetFee()...)
+	}
+	if balance := balances[delegator]; !balance.IsAllGTE(needed) {
+		fail("the genesis balance of %s is %q, the self delegation and the fees of the gentx need %s", delegator, balance, needed)
+	}
+
+	// commission
+	if err := msg.Commission.Validate(); err != nil {
+		fail("invalid commission: %s", err)
+	} else if msg.Commission.Rate.LT(params.MinCommissionRate) {
+		fail("the commission rate %s is below the min_commission_rate %s", msg.Commission.Rate, params.MinCommissionRate)
+	}
+
+	// peer
+	var memo string
+	if memoTx, ok := tx.(sdk.TxWithMemo); ok {
+		memo = memoTx.GetMemo()
+	}
+	check.Peer = memo
+	nodeID, err := parsePeerMemo(memo)
+	if err != nil {
+		fail("%s", err)
+	}
+	return check, msg, nodeID
+}
+
+// parsePeerMemo returns the node id of the memo of a gentx, which holds the
+// node_id@host:port of the validator collect-gentxs adds to the persistent
+// peers
+func parsePeerMemo(memo string) (string, error) {
+	nodeID, addr, ok := strings.Cut(memo, "@")
+	if !ok {
+		return "", fmt.Errorf("the memo %q isn't of the form node_id@host:port", memo)
+	}
+	if bz, err := hex.DecodeString(nodeID); err != nil || len(bz) != 20 {
+		return "", fmt.Errorf("invalid node id %q in the memo, it must be 40 hex characters", nodeID)
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return "", fmt.Errorf("invalid address %q in the memo, it must be host:port", addr)
+	}
+	if p, err := strconv.ParseUint(port, 10, 16); err != nil || p == 0 {
+		return "", fmt.Errorf("invalid port %q in the memo", port)
+	}
+	return strings.ToLower(nodeID), nil
+}
+
+func printGenTxReport(cmd *cobra.Command, report GenTxReport) {
+	cmd.Printf("genesis: %s\ngentxs: %s\n", report.GenesisFile, report.GenTxDir)
+	for _, genTx := range report.GenTxs {
+		cmd.Printf("\n%s %s %s %s\n", genTx.File, genTx.Moniker, genTx.Validator, genTx.Amount)
+		if genTx.Peer != "" {
+			cmd.Printf("  peer: %s\n", genTx.Peer)
+		}
+		for _, err := range genTx.Errors {
+			cmd.Printf("  error: %s\n", err)
+		}
+	}
+	if len(report.Errors) > 0 {
+		cmd.Println()
+	}
+	for _, err := range report.Errors {
+		cmd.Printf("error: %s\n", err)
+	}
+	cmd.Printf("\n%d gentxs, %d problems\n", len(report.GenTxs), report.Problems())
+}
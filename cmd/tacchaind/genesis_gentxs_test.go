@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+)
+
+// testGenTx is a gentx of a validator created with its fields
+type testGenTx struct {
+	operator   sdk.AccAddress
+	consKey    cryptotypes.PubKey
+	amount     sdk.Coin
+	commission stakingtypes.CommissionRates
+	memo       string
+	fee        sdk.Coins
+}
+
+func newTestGenTx() testGenTx {
+	nodeID := secp256k1.GenPrivKey().PubKey().Address()
+	return testGenTx{
+		operator:   sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()),
+		consKey:    ed25519.GenPrivKey().PubKey(),
+		amount:     sdk.NewCoin(app.BaseDenom, app.PowerReduction.MulRaw(10)),
+		commission: stakingtypes.NewCommissionRates(sdkmath.LegacyNewDecWithPrec(1, 1), sdkmath.LegacyNewDecWithPrec(2, 1), sdkmath.LegacyNewDecWithPrec(1, 2)),
+		memo:       hex.EncodeToString(nodeID) + "@127.0.0.1:26656",
+		fee:        sdk.NewCoins(sdk.NewInt64Coin(app.BaseDenom, 1000)),
+	}
+}
+
+func (g testGenTx) file(t *testing.T, txConfig client.TxConfig, name string) genTxFile {
+	t.Helper()
+
+	msg, err := stakingtypes.NewMsgCreateValidator(
+		sdk.ValAddress(g.operator).String(),
+		g.consKey,
+		g.amount,
+		stakingtypes.NewDescription(name, "", "", "", ""),
+		g.commission,
+		sdkmath.OneInt(),
+	)
+	require.NoError(t, err)
+	txBuilder := txConfig.NewTxBuilder()
+	require.NoError(t, txBuilder.SetMsgs(msg))
+	txBuilder.SetMemo(g.memo)
+	txBuilder.SetFeeAmount(g.fee)
+	txBuilder.SetGasLimit(200000)
+	bz, err := txConfig.TxJSONEncoder()(txBuilder.GetTx())
+	require.NoError(t, err)
+	return genTxFile{name: name + ".json", bz: bz}
+}
+
+// fundedAppState returns the default genesis funding the accounts of genTxs
+// with their self delegation and fees
+func fundedAppState(t *testing.T, tacApp *app.TacChainApp, cdc codec.Codec, genTxs ...testGenTx) map[string]json.RawMessage {
+	t.Helper()
+
+	appState := tacApp.DefaultGenesis()
+	bankGenesis := banktypes.GetGenesisStateFromAppState(cdc, appState)
+	for _, genTx := range genTxs {
+		bankGenesis.Balances = append(bankGenesis.Balances, banktypes.Balance{
+			Address: genTx.operator.String(),
+			Coins:   genTx.fee.Add(genTx.amount),
+		})
+	}
+	appState[banktypes.ModuleName] = cdc.MustMarshalJSON(bankGenesis)
+	return appState
+}
+
+func TestValidateGenTxs(t *testing.T) {
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.AppOptionsMap{flags.FlagHome: t.TempDir()},
+	})
+	cdc, txConfig := tacApp.AppCodec(), tacApp.TxConfig()
+
+	alice, bob := newTestGenTx(), newTestGenTx()
+	appState := fundedAppState(t, tacApp, cdc, alice, bob)
+	report := validateGenTxs(cdc, txConfig, appState, []genTxFile{alice.file(t, txConfig, "alice"), bob.file(t, txConfig, "bob")})
+	require.Zero(t, report.Problems(), "%+v", report)
+	require.Len(t, report.GenTxs, 2)
+	require.Equal(t, GenTxCheck{
+		File:      "alice.json",
+		Moniker:   "alice",
+		Validator: sdk.ValAddress(alice.operator).String(),
+		Delegator: alice.operator.String(),
+		Amount:    alice.amount.String(),
+		Peer:      alice.memo,
+		Errors:    []string{},
+	}, report.GenTxs[0])
+
+	testCases := []struct {
+		name   string
+		modify func(genTx *testGenTx)
+		// fund funds the account with the modified gentx
+		fund   bool
+		params func(params *stakingtypes.Params)
+		expErr string
+	}{
+		{
+			name:   "balance below the self delegation",
+			modify: func(genTx *testGenTx) { genTx.amount = genTx.amount.AddAmount(sdkmath.OneInt()) },
+			expErr: "the self delegation and the fees of the gentx need",
+		},
+		{
+			name:   "balance below the fees",
+			modify: func(genTx *testGenTx) { genTx.fee = genTx.fee.Add(sdk.NewInt64Coin(app.BaseDenom, 1)) },
+			expErr: "the self delegation and the fees of the gentx need",
+		},
+		{
+			name:   "other denom",
+			modify: func(genTx *testGenTx) { genTx.amount = sdk.NewCoin("uatom", genTx.amount.Amount) },
+			fund:   true,
+			expErr: "isn't in the bond denom " + app.BaseDenom,
+		},
+		{
+			name:   "no voting power",
+			modify: func(genTx *testGenTx) { genTx.amount = sdk.NewCoin(app.BaseDenom, app.PowerReduction.SubRaw(1)) },
+			fund:   true,
+			expErr: "gives no voting power",
+		},
+		{
+			name: "commission rate above the max rate",
+			modify: func(genTx *testGenTx) {
+				genTx.commission.Rate = sdkmath.LegacyNewDecWithPrec(3, 1)
+			},
+			expErr: "invalid commission",
+		},
+		{
+			name: "max change rate above the max rate",
+			modify: func(genTx *testGenTx) {
+				genTx.commission.MaxChangeRate = sdkmath.LegacyNewDecWithPrec(3, 1)
+			},
+			expErr: "invalid commission",
+		},
+		{
+			name:   "commission rate below the min commission rate",
+			params: func(params *stakingtypes.Params) { params.MinCommissionRate = sdkmath.LegacyNewDecWithPrec(15, 2) },
+			expErr: "the commission rate 0.100000000000000000 is below the min_commission_rate 0.150000000000000000",
+		},
+		{
+			name:   "duplicate consensus key",
+			modify: func(genTx *testGenTx) { genTx.consKey = alice.consKey },
+			expErr: "is already used by alice.json",
+		},
+		{
+			name:   "duplicate node id",
+			modify: func(genTx *testGenTx) { genTx.memo = strings.Replace(alice.memo, "127.0.0.1", "10.0.0.1", 1) },
+			expErr: "node id " + strings.Split(alice.memo, "@")[0] + " is already used by alice.json",
+		},
+		{
+			name:   "empty memo",
+			modify: func(genTx *testGenTx) { genTx.memo = "" },
+			expErr: `the memo "" isn't of the form node_id@host:port`,
+		},
+		{
+			name:   "memo without port",
+			modify: func(genTx *testGenTx) { genTx.memo = strings.Split(genTx.memo, ":")[0] },
+			expErr: "it must be host:port",
+		},
+		{
+			name:   "too many validators",
+			params: func(params *stakingtypes.Params) { params.MaxValidators = 1 },
+			expErr: "max_validators is 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			modified := bob
+			if tc.modify != nil {
+				tc.modify(&modified)
+			}
+			var appState map[string]json.RawMessage
+			if tc.fund {
+				appState = fundedAppState(t, tacApp, cdc, alice, modified)
+			} else {
+				appState = fundedAppState(t, tacApp, cdc, alice, bob)
+			}
+			if tc.params != nil {
+				stakingGenesis := stakingtypes.GetGenesisStateFromAppState(cdc, appState)
+				tc.params(&stakingGenesis.Params)
+				appState[stakingtypes.ModuleName] = cdc.MustMarshalJSON(stakingGenesis)
+			}
+
+			report := validateGenTxs(cdc, txConfig, appState, []genTxFile{alice.file(t, txConfig, "alice"), modified.file(t, txConfig, "bob")})
+			require.Equal(t, 1, report.Problems(), "%+v", report)
+			require.Empty(t, report.GenTxs[0].Errors)
+			errs := append(report.GenTxs[1].Errors, report.Errors...)
+			require.Contains(t, errs[0], tc.expErr)
+		})
+	}
+
+	// a gentx of another message or that can't be decoded
+	send := txConfig.NewTxBuilder()
+	require.NoError(t, send.SetMsgs(banktypes.NewMsgSend(alice.operator, bob.operator, sdk.NewCoins(alice.amount))))
+	sendBz, err := txConfig.TxJSONEncoder()(send.GetTx())
+	require.NoError(t, err)
+	report = validateGenTxs(cdc, txConfig, appState, []genTxFile{
+		alice.file(t, txConfig, "alice"),
+		{name: "send.json", bz: sendBz},
+		{name: "invalid.json", bz: []byte("{")},
+	})
+	require.Equal(t, 2, report.Problems(), "%+v", report)
+	require.Contains(t, report.GenTxs[1].Errors[0], "it holds a /cosmos.bank.v1beta1.MsgSend")
+	require.Contains(t, report.GenTxs[2].Errors[0], "failed to decode the gentx")
+
+	report = validateGenTxs(cdc, txConfig, appState, nil)
+	require.Equal(t, []string{"no gentx found"}, report.Errors)
+}
+
+func TestReadGenTxDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gentx-b.json"), []byte("b"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gentx-a.json"), []byte("a"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("c"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "old.json"), 0o700))
+
+	files, err := readGenTxDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, []genTxFile{{name: "gentx-a.json", bz: []byte("a")}, {name: "gentx-b.json", bz: []byte("b")}}, files)
+
+	_, err = readGenTxDir(filepath.Join(dir, "missing"))
+	require.ErrorContains(t, err, "failed to read the gentx directory")
+}
+
+func TestParsePeerMemo(t *testing.T) {
+	nodeID := "4a1cb6a4b3c33ae44baf3c4d5e4ae1d08a0ad1f8"
+	for memo, expErr := range map[string]string{
+		nodeID + "@127.0.0.1:26656":              "",
+		nodeID + "@node0.example.com:26656":      "",
+		strings.ToUpper(nodeID) + "@[::1]:26656": "",
+		nodeID + "@127.0.0.1":                    "it must be host:port",
+		nodeID + "@:26656":                       "it must be host:port",
+		nodeID + "@127.0.0.1:0":                  "invalid port",
+		nodeID + "@127.0.0.1:65536":              "invalid port",
+		nodeID[:38] + "@127.0.0.1:26656":         "it must be 40 hex characters",
+		"127.0.0.1:26656":                        "isn't of the form node_id@host:port",
+	} {
+		id, err := parsePeerMemo(memo)
+		if expErr != "" {
+			require.ErrorContains(t, err, expErr, memo)
+			continue
+		}
+		require.NoError(t, err, memo)
+		require.Equal(t, nodeID, id)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	genutiltypes "github.com/cosmos/cosmos-sdk/x/genutil/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	selfbondtypes "github.com/Asphere-xyz/tacchain/x/selfbond/types"
+)
+
+// addGenesisValidatorsCheck makes collect-gentxs check the validator set of
+// the genesis it writes like the chain does when it starts, so that a gentx
+// above max_validators or the max genesis power share of x/selfbond fails
+// when the gentxs are collected rather than when the validators start.
+func addGenesisValidatorsCheck(collectCmd *cobra.Command, cdc codec.Codec, txConfig client.TxConfig) {
+	runE := collectCmd.RunE
+	collectCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if err := runE(cmd, args); err != nil {
+			return err
+		}
+
+		genFile := server.GetServerContextFromCmd(cmd).Config.GenesisFile()
+		appState, _, err := genutiltypes.GenesisStateFromGenFile(genFile)
+		if err != nil {
+			return err
+		}
+		if err := checkGenesisValidators(cdc, txConfig, appState); err != nil {
+			return fmt.Errorf("invalid genesis validator set in %s: %w", genFile, err)
+		}
+		return nil
+	}
+}
+
+// checkGenesisValidators checks the validators of the x/staking genesis and
+// of the gentxs against max_validators and the max genesis power share
+func checkGenesisValidators(cdc codec.Codec, txConfig client.TxConfig, appState map[string]json.RawMessage) error {
+	stakingGenesis := stakingtypes.GetGenesisStateFromAppState(cdc, appState)
+	selfbondGenesis := selfbondtypes.DefaultGenesisState()
+	if bz, ok := appState[selfbondtypes.ModuleName]; ok {
+		if err := json.Unmarshal(bz, selfbondGenesis); err != nil {
+			return fmt.Errorf("failed to unmarshal %s genesis state: %w", selfbondtypes.ModuleName, err)
+		}
+	}
+
+	var validators []selfbondtypes.GenesisValidator
+	for _, validator := range stakingGenesis.Validators {
+		if !validator.Jailed {
+			validators = append(validators, selfbondtypes.GenesisValidator{
+				Operator: validator.OperatorAddress,
+				Power:    sdk.TokensToConsensusPower(validator.Tokens, sdk.DefaultPowerReduction),
+			})
+		}
+	}
+	for _, bz := range genutiltypes.GetGenesisStateFromAppState(cdc, appState).GenTxs {
+		tx, err := txConfig.TxJSONDecoder()(bz)
+		if err != nil {
+			return fmt.Errorf("failed to decode gentx: %w", err)
+		}
+		for _, msg := range tx.GetMsgs() {
+			if msg, ok := msg.(*stakingtypes.MsgCreateValidator); ok {
+				validators = append(validators, selfbondtypes.GenesisValidator{
+					Operator: msg.ValidatorAddress,
+					Power:    sdk.TokensToConsensusPower(msg.Value.Amount, sdk.DefaultPowerReduction),
+				})
+			}
+		}
+	}
+	return selfbondGenesis.Params.ValidateGenesisValidators(validators, stakingGenesis.Params.MaxValidators)
+}
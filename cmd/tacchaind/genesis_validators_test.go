@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	genutiltypes "github.com/cosmos/cosmos-sdk/x/genutil/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	selfbondtypes "github.com/Asphere-xyz/tacchain/x/selfbond/types"
+)
+
+func TestCheckGenesisValidators(t *testing.T) {
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.AppOptionsMap{flags.FlagHome: t.TempDir()},
+	})
+	cdc, txConfig := tacApp.AppCodec(), tacApp.TxConfig()
+
+	// gentxs of validators with 1, 1 and 2 of the 4 voting power
+	var genTxs []sdk.Tx
+	for _, power := range []int64{1, 1, 2} {
+		pubKey := ed25519.GenPrivKey().PubKey()
+		msg, err := stakingtypes.NewMsgCreateValidator(
+			sdk.ValAddress(pubKey.Address()).String(),
+			pubKey,
+			sdk.NewCoin(app.BaseDenom, app.PowerReduction.MulRaw(power)),
+			stakingtypes.NewDescription("validator", "", "", "", ""),
+			stakingtypes.NewCommissionRates(sdkmath.LegacyNewDecWithPrec(1, 1), sdkmath.LegacyOneDec(), sdkmath.LegacyOneDec()),
+			sdkmath.OneInt(),
+		)
+		require.NoError(t, err)
+		txBuilder := txConfig.NewTxBuilder()
+		require.NoError(t, txBuilder.SetMsgs(msg))
+		genTxs = append(genTxs, txBuilder.GetTx())
+	}
+
+	appState := tacApp.DefaultGenesis()
+	genutilGenesis := genutiltypes.NewGenesisStateFromTx(txConfig.TxJSONEncoder(), genTxs)
+	appState[genutiltypes.ModuleName] = cdc.MustMarshalJSON(&genutilGenesis)
+
+	setMaxValidators := func(maxValidators uint32) {
+		stakingGenesis := stakingtypes.GetGenesisStateFromAppState(cdc, appState)
+		stakingGenesis.Params.MaxValidators = maxValidators
+		appState[stakingtypes.ModuleName] = cdc.MustMarshalJSON(stakingGenesis)
+	}
+	setMaxGenesisPowerShare := func(share sdkmath.LegacyDec) {
+		selfbondGenesis := selfbondtypes.DefaultGenesisState()
+		selfbondGenesis.Params.MaxGenesisPowerShare = share
+		bz, err := json.Marshal(selfbondGenesis)
+		require.NoError(t, err)
+		appState[selfbondtypes.ModuleName] = bz
+	}
+
+	require.NoError(t, checkGenesisValidators(cdc, txConfig, appState))
+
+	setMaxValidators(2)
+	err := checkGenesisValidators(cdc, txConfig, appState)
+	require.ErrorIs(t, err, selfbondtypes.ErrTooManyGenesisValidators)
+	require.ErrorContains(t, err, "3 validators with voting power, max_validators is 2")
+
+	setMaxValidators(3)
+	setMaxGenesisPowerShare(sdkmath.LegacyNewDecWithPrec(5, 1))
+	require.NoError(t, checkGenesisValidators(cdc, txConfig, appState))
+
+	setMaxGenesisPowerShare(sdkmath.LegacyNewDecWithPrec(4, 1))
+	err = checkGenesisValidators(cdc, txConfig, appState)
+	require.ErrorIs(t, err, selfbondtypes.ErrGenesisPowerShare)
+	require.ErrorContains(t, err, "has 2 of the 4 voting power")
+}
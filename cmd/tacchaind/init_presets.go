@@ -0,0 +1,265 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	cmtcfg "github.com/cometbft/cometbft/config"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/server"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	genutilcli "github.com/cosmos/cosmos-sdk/x/genutil/client/cli"
+	genutiltypes "github.com/cosmos/cosmos-sdk/x/genutil/types"
+)
+
+const (
+	flagSeed                  = "seed"
+	flagSentry                = "sentry"
+	flagValidatorBehindSentry = "validator-behind-sentry"
+	flagSentryPeers           = "sentry-peers"
+	flagPrivatePeers          = "private-peers"
+
+	// nodeIDByteLength is the length of a CometBFT node id, the address of its p2p key
+	nodeIDByteLength = 20
+)
+
+// nodePreset adjusts the config of a node to its role in the network
+type nodePreset func(cfg *cmtcfg.Config)
+
+// InitCmd extends the genutil init command with presets writing the genesis
+// params of devnets, testnets and mainnet, and the p2p settings of seed nodes,
+// sentry nodes and validators running behind sentries.
+func InitCmd(mbm module.BasicManager, defaultNodeHome string) *cobra.Command {
+	cmd := genutilcli.InitCmd(mbm, defaultNodeHome)
+	cmd.Long += `
+
+Use --preset to write the genesis params and node settings of a kind of network:
+devnet (mainnet economics, proposals passing within seconds, no base fee), testnet
+(the Saint Petersburg testnet) or mainnet. The peers of the public networks are
+added to the node when it is initialized with their chain id.
+
+Use --seed to set up a seed node crawling the network and handing out peers to new nodes,
+--sentry to set up a sentry node shielding the validators given by --private-peers, or
+--validator-behind-sentry to set up a validator only reachable through the sentries given
+by --sentry-peers. Peers are given as comma separated node_id@host:port.`
+
+	initRunE := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		// validate the flags before anything is written to the home directory
+		preset, err := nodePresetFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		presetName, err := cmd.Flags().GetString(flagPreset)
+		if err != nil {
+			return err
+		}
+		network, err := networkPresetByName(presetName)
+		if err != nil {
+			return err
+		}
+		if err := initRunE(cmd, args); err != nil {
+			return err
+		}
+		if preset == nil && network == nil {
+			return nil
+		}
+
+		config := server.GetServerContextFromCmd(cmd).Config
+		if network != nil {
+			appGenesis, err := genutiltypes.AppGenesisFromFile(config.GenesisFile())
+			if err != nil {
+				return err
+			}
+			if err := network.applyGenesis(appGenesis); err != nil {
+				return fmt.Errorf("failed to apply the %s preset: %w", presetName, err)
+			}
+			if err := appGenesis.SaveAs(config.GenesisFile()); err != nil {
+				return err
+			}
+			network.nodePreset(appGenesis.ChainID)(config)
+		}
+		// node presets come last, the role of the node overrides the network defaults
+		if preset != nil {
+			preset(config)
+		}
+		cmtcfg.WriteConfigFile(filepath.Join(config.RootDir, "config", "config.toml"), config)
+		return nil
+	}
+
+	cmd.Flags().String(flagPreset, "", fmt.Sprintf("Genesis params and node settings of the network, one of %s", strings.Join(networkPresetNames(), ", ")))
+	cmd.Flags().Bool(flagSeed, false, "Configure the node as a seed node, only exchanging peer addresses")
+	cmd.Flags().Bool(flagSentry, false, "Configure the node as a sentry of the validators given by --private-peers")
+	cmd.Flags().Bool(flagValidatorBehindSentry, false, "Configure the node as a validator only connecting to the sentries given by --sentry-peers")
+	cmd.Flags().String(flagPrivatePeers, "", "Comma separated node_id@host:port of the validators behind the sentry, never gossiped to other peers")
+	cmd.Flags().String(flagSentryPeers, "", "Comma separated node_id@host:port of the sentries of the validator")
+	cmd.MarkFlagsMutuallyExclusive(flagSeed, flagSentry, flagValidatorBehindSentry)
+
+	return cmd
+}
+
+// nodePresetFromFlags returns the preset selected by the init flags, nil if none is.
+func nodePresetFromFlags(cmd *cobra.Command) (nodePreset, error) {
+	seed, err := cmd.Flags().GetBool(flagSeed)
+	if err != nil {
+		return nil, err
+	}
+	sentry, err := cmd.Flags().GetBool(flagSentry)
+	if err != nil {
+		return nil, err
+	}
+	behindSentry, err := cmd.Flags().GetBool(flagValidatorBehindSentry)
+	if err != nil {
+		return nil, err
+	}
+	privatePeers, err := cmd.Flags().GetString(flagPrivatePeers)
+	if err != nil {
+		return nil, err
+	}
+	sentryPeers, err := cmd.Flags().GetString(flagSentryPeers)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case seed:
+		if privatePeers != "" || sentryPeers != "" {
+			return nil, fmt.Errorf("--%s and --%s are not used with --%s", flagPrivatePeers, flagSentryPeers, flagSeed)
+		}
+		return seedPreset(), nil
+	case sentry:
+		if sentryPeers != "" {
+			return nil, fmt.Errorf("--%s is only used with --%s", flagSentryPeers, flagValidatorBehindSentry)
+		}
+		peers, err := parsePeers(privatePeers)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s: %w", flagPrivatePeers, err)
+		}
+		if len(peers) == 0 {
+			return nil, fmt.Errorf("--%s requires the validators to shield with --%s", flagSentry, flagPrivatePeers)
+		}
+		return sentryPreset(peers), nil
+	case behindSentry:
+		if privatePeers != "" {
+			return nil, fmt.Errorf("--%s is only used with --%s", flagPrivatePeers, flagSentry)
+		}
+		peers, err := parsePeers(sentryPeers)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s: %w", flagSentryPeers, err)
+		}
+		if len(peers) == 0 {
+			return nil, fmt.Errorf("--%s requires the sentries to connect to with --%s", flagValidatorBehindSentry, flagSentryPeers)
+		}
+		return validatorBehindSentryPreset(peers), nil
+	case privatePeers != "" || sentryPeers != "":
+		return nil, fmt.Errorf("--%s and --%s require --%s or --%s", flagPrivatePeers, flagSentryPeers, flagSentry, flagValidatorBehindSentry)
+	}
+	return nil, nil
+}
+
+// seedPreset turns on seed mode, the node crawls the network for peers and
+// only keeps connections long enough to hand out addresses. Seeds are public,
+// so unlike sentries they only share routable addresses.
+func seedPreset() nodePreset {
+	return func(cfg *cmtcfg.Config) {
+		cfg.P2P.SeedMode = true
+		cfg.P2P.PexReactor = true
+		cfg.P2P.AddrBookStrict = true
+	}
+}
+
+// sentryPreset keeps the sentry connected to its validators and open to the
+// rest of the network, without ever gossiping the validators' addresses.
+func sentryPreset(validators []peer) nodePreset {
+	return func(cfg *cmtcfg.Config) {
+		cfg.P2P.PexReactor = true
+		cfg.P2P.AddrBookStrict = false
+		cfg.P2P.PersistentPeers = joinPeers(cfg.P2P.PersistentPeers, peerAddresses(validators))
+		cfg.P2P.PrivatePeerIDs = joinPeers(cfg.P2P.PrivatePeerIDs, peerIDs(validators))
+		cfg.P2P.UnconditionalPeerIDs = joinPeers(cfg.P2P.UnconditionalPeerIDs, peerIDs(validators))
+	}
+}
+
+// validatorBehindSentryPreset limits the validator to its sentries, it neither
+// discovers nor advertises any other peer.
+func validatorBehindSentryPreset(sentries []peer) nodePreset {
+	return func(cfg *cmtcfg.Config) {
+		cfg.P2P.PexReactor = false
+		cfg.P2P.SeedMode = false
+		cfg.P2P.AddrBookStrict = false
+		cfg.P2P.Seeds = ""
+		cfg.P2P.PersistentPeers = strings.Join(peerAddresses(sentries), ",")
+		cfg.P2P.PrivatePeerIDs = ""
+		cfg.P2P.UnconditionalPeerIDs = strings.Join(peerIDs(sentries), ",")
+	}
+}
+
+// peer is a node_id@host:port entry of a peer list
+type peer struct {
+	ID   string
+	Addr string
+}
+
+// parsePeers parses a comma separated list of node_id@host:port. Hosts are
+// kept as given, they don't need to resolve at init time.
+func parsePeers(list string) ([]peer, error) {
+	var peers []peer
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, hostPort, ok := strings.Cut(entry, "@")
+		if !ok {
+			return nil, fmt.Errorf("%s: expected node_id@host:port", entry)
+		}
+		if bz, err := hex.DecodeString(id); err != nil || len(bz) != nodeIDByteLength {
+			return nil, fmt.Errorf("%s: node id must be %d hex encoded bytes", entry, nodeIDByteLength)
+		}
+		host, port, err := net.SplitHostPort(hostPort)
+		if err != nil || host == "" {
+			return nil, fmt.Errorf("%s: expected node_id@host:port", entry)
+		}
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return nil, fmt.Errorf("%s: invalid port %s", entry, port)
+		}
+		peers = append(peers, peer{ID: strings.ToLower(id), Addr: strings.ToLower(id) + "@" + hostPort})
+	}
+	return peers, nil
+}
+
+func peerAddresses(peers []peer) []string {
+	addrs := make([]string, len(peers))
+	for i, p := range peers {
+		addrs[i] = p.Addr
+	}
+	return addrs
+}
+
+func peerIDs(peers []peer) []string {
+	ids := make([]string, len(peers))
+	for i, p := range peers {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+// joinPeers appends peers to a comma separated list, skipping the ones it already contains.
+func joinPeers(list string, peers []string) string {
+	var all []string
+	seen := make(map[string]bool)
+	for _, p := range append(strings.Split(list, ","), peers...) {
+		p = strings.TrimSpace(p)
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		all = append(all, p)
+	}
+	return strings.Join(all, ",")
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	cmtcfg "github.com/cometbft/cometbft/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testNodeID1 = "0123456789abcdef0123456789abcdef01234567"
+	testNodeID2 = "89abcdef0123456789abcdef0123456789abcdef"
+)
+
+func TestParsePeers(t *testing.T) {
+	peers, err := parsePeers(" " + strings.ToUpper(testNodeID1) + "@sentry-1.example.com:26656, " + testNodeID2 + "@10.0.0.2:26656,")
+	require.NoError(t, err)
+	require.Equal(t, []peer{
+		{ID: testNodeID1, Addr: testNodeID1 + "@sentry-1.example.com:26656"},
+		{ID: testNodeID2, Addr: testNodeID2 + "@10.0.0.2:26656"},
+	}, peers)
+
+	peers, err = parsePeers("")
+	require.NoError(t, err)
+	require.Empty(t, peers)
+
+	for _, list := range []string{
+		"10.0.0.2:26656",
+		testNodeID1,
+		"abcd@10.0.0.2:26656",
+		testNodeID1 + "@10.0.0.2",
+		testNodeID1 + "@:26656",
+		testNodeID1 + "@10.0.0.2:port",
+		testNodeID1 + "@10.0.0.2:26656," + testNodeID2,
+	} {
+		_, err := parsePeers(list)
+		require.Error(t, err, list)
+	}
+}
+
+func TestSentryPreset(t *testing.T) {
+	cfg := cmtcfg.DefaultConfig()
+	cfg.P2P.PexReactor = false
+	cfg.P2P.PersistentPeers = testNodeID2 + "@10.0.0.2:26656"
+
+	peers, err := parsePeers(testNodeID1 + "@10.0.0.1:26656")
+	require.NoError(t, err)
+	sentryPreset(peers)(cfg)
+
+	require.True(t, cfg.P2P.PexReactor, "sentries take part in peer exchange")
+	require.False(t, cfg.P2P.AddrBookStrict)
+	require.Equal(t, testNodeID2+"@10.0.0.2:26656,"+testNodeID1+"@10.0.0.1:26656", cfg.P2P.PersistentPeers)
+	require.Equal(t, testNodeID1, cfg.P2P.PrivatePeerIDs)
+	require.Equal(t, testNodeID1, cfg.P2P.UnconditionalPeerIDs)
+
+	// applying the preset again doesn't duplicate the validator
+	sentryPreset(peers)(cfg)
+	require.Equal(t, testNodeID2+"@10.0.0.2:26656,"+testNodeID1+"@10.0.0.1:26656", cfg.P2P.PersistentPeers)
+	require.Equal(t, testNodeID1, cfg.P2P.PrivatePeerIDs)
+}
+
+func TestValidatorBehindSentryPreset(t *testing.T) {
+	cfg := cmtcfg.DefaultConfig()
+	cfg.P2P.Seeds = testNodeID1 + "@seed.example.com:26656"
+	cfg.P2P.PersistentPeers = testNodeID1 + "@10.0.0.1:26656"
+
+	peers, err := parsePeers(testNodeID2 + "@10.0.0.2:26656")
+	require.NoError(t, err)
+	validatorBehindSentryPreset(peers)(cfg)
+
+	require.False(t, cfg.P2P.PexReactor, "validators behind sentries don't exchange peers")
+	require.False(t, cfg.P2P.SeedMode)
+	require.False(t, cfg.P2P.AddrBookStrict)
+	require.Empty(t, cfg.P2P.Seeds)
+	require.Equal(t, testNodeID2+"@10.0.0.2:26656", cfg.P2P.PersistentPeers, "validators only connect to their sentries")
+	require.Equal(t, testNodeID2, cfg.P2P.UnconditionalPeerIDs)
+	require.Empty(t, cfg.P2P.PrivatePeerIDs)
+}
+
+func TestSeedPreset(t *testing.T) {
+	cfg := cmtcfg.DefaultConfig()
+	cfg.P2P.PexReactor = false
+	cfg.P2P.AddrBookStrict = false
+
+	seedPreset()(cfg)
+
+	require.True(t, cfg.P2P.SeedMode)
+	require.True(t, cfg.P2P.PexReactor, "seed mode relies on peer exchange")
+	require.True(t, cfg.P2P.AddrBookStrict, "seeds only share routable addresses")
+}
+
+func TestNodePresetFromFlags(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool(flagSeed, false, "")
+		cmd.Flags().Bool(flagSentry, false, "")
+		cmd.Flags().Bool(flagValidatorBehindSentry, false, "")
+		cmd.Flags().String(flagPrivatePeers, "", "")
+		cmd.Flags().String(flagSentryPeers, "", "")
+		require.NoError(t, cmd.Flags().Parse(args))
+		return cmd
+	}
+
+	preset, err := nodePresetFromFlags(newCmd())
+	require.NoError(t, err)
+	require.Nil(t, preset, "no preset by default")
+
+	preset, err = nodePresetFromFlags(newCmd("--seed"))
+	require.NoError(t, err)
+	require.NotNil(t, preset)
+
+	preset, err = nodePresetFromFlags(newCmd("--sentry", "--private-peers", testNodeID1+"@10.0.0.1:26656"))
+	require.NoError(t, err)
+	require.NotNil(t, preset)
+
+	preset, err = nodePresetFromFlags(newCmd("--validator-behind-sentry", "--sentry-peers", testNodeID2+"@10.0.0.2:26656"))
+	require.NoError(t, err)
+	require.NotNil(t, preset)
+
+	for _, args := range [][]string{
+		{"--sentry"},
+		{"--validator-behind-sentry"},
+		{"--sentry", "--private-peers", "invalid"},
+		{"--sentry", "--private-peers", testNodeID1 + "@10.0.0.1:26656", "--sentry-peers", testNodeID2 + "@10.0.0.2:26656"},
+		{"--validator-behind-sentry", "--sentry-peers", testNodeID2 + "@10.0.0.2:26656", "--private-peers", testNodeID1 + "@10.0.0.1:26656"},
+		{"--private-peers", testNodeID1 + "@10.0.0.1:26656"},
+		{"--seed", "--sentry-peers", testNodeID2 + "@10.0.0.2:26656"},
+	} {
+		_, err := nodePresetFromFlags(newCmd(args...))
+		require.Error(t, err, args)
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	flagCoinType = "coin-type"
+	flagHDPath   = "hd-path"
+	flagAccount  = "account"
+	flagIndex    = "index"
+	flagRecover  = "recover"
+	flagSource   = "source"
+)
+
+// addKeyDerivationCheck makes keys add refuse to derive keys with another
+// coin type than the one of the chain. Keys are eth_secp256k1 keys, derived
+// from m/44'/60'/0'/0/0 by default like Ethereum wallets do: a key derived with
+// coin type 118 is neither the key Ethereum wallets nor the one Cosmos wallets,
+// which use secp256k1 keys for it, recover from the mnemonic.
+func addKeyDerivationCheck(keysCmd *cobra.Command) {
+	addCmd, _, err := keysCmd.Find([]string{"add"})
+	if err != nil || addCmd == keysCmd {
+		panic("keys command has no add subcommand")
+	}
+
+	preRunE := addCmd.PreRunE
+	addCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if preRunE != nil {
+			if err := preRunE(cmd, args); err != nil {
+				return err
+			}
+		}
+		return checkKeyDerivation(cmd)
+	}
+}
+
+// checkKeyDerivation fails if the coin type of the --coin-type or --hd-path
+// flags isn't the coin type of the chain
+func checkKeyDerivation(cmd *cobra.Command) error {
+	coinType := sdk.GetConfig().GetCoinType()
+
+	if hdPath, _ := cmd.Flags().GetString(flagHDPath); hdPath != "" {
+		params, err := hd.NewParamsFromPath(hdPath)
+		if err != nil {
+			return fmt.Errorf("invalid --%s %s: %w", flagHDPath, hdPath, err)
+		}
+		if params.CoinType != coinType {
+			return errUnsupportedCoinType(params.CoinType, coinType)
+		}
+	}
+
+	if cmd.Flags().Changed(flagCoinType) {
+		requested, err := cmd.Flags().GetUint32(flagCoinType)
+		if err != nil {
+			return err
+		}
+		if requested != coinType {
+			return errUnsupportedCoinType(requested, coinType)
+		}
+	}
+	return nil
+}
+
+func errUnsupportedCoinType(requested, coinType uint32) error {
+	return fmt.Errorf(
+		"coin type %d is not supported, keys are derived with coin type %d (%s) so that Ethereum wallets recover the same accounts from the mnemonic",
+		requested, coinType, hd.CreateHDPath(coinType, 0, 0),
+	)
+}
+
+// mnemonicKey is a key of the mnemonics file of keys add --source
+type mnemonicKey struct {
+	Name     string `json:"name"`
+	Mnemonic string `json:"mnemonic"`
+}
+
+// recoveredKey is a key keys add --source recovered or found in the keyring
+type recoveredKey struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// addMnemonicsSource adds --source to keys add, recovering all the keys of a
+// mnemonics file at once, e.g. to seed the keyrings of test networks with the
+// same accounts on every run.
+func addMnemonicsSource(keysCmd *cobra.Command) {
+	addCmd, _, err := keysCmd.Find([]string{"add"})
+	if err != nil || addCmd == keysCmd {
+		panic("keys command has no add subcommand")
+	}
+	addCmd.Flags().String(flagSource, "", `Recover the keys of a JSON file of {"name", "mnemonic"} objects, with --recover`)
+
+	validateArgs := addCmd.Args
+	addCmd.Args = func(cmd *cobra.Command, args []string) error {
+		if source, _ := cmd.Flags().GetString(flagSource); source != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		if validateArgs == nil {
+			return nil
+		}
+		return validateArgs(cmd, args)
+	}
+
+	runE := addCmd.RunE
+	addCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		source, err := cmd.Flags().GetString(flagSource)
+		if err != nil {
+			return err
+		}
+		if source == "" {
+			return runE(cmd, args)
+		}
+		if recoverKeys, _ := cmd.Flags().GetBool(flagRecover); !recoverKeys {
+			return fmt.Errorf("--%s requires --%s", flagSource, flagRecover)
+		}
+		return runRecoverFromSource(cmd, source)
+	}
+}
+
+// runRecoverFromSource recovers the keys of the mnemonics file source with the
+// key type and derivation path of the flags of keys add.
+func runRecoverFromSource(cmd *cobra.Command, source string) error {
+	clientCtx, err := client.GetClientQueryContext(cmd)
+	if err != nil {
+		return err
+	}
+	keys, err := readMnemonics(source)
+	if err != nil {
+		return err
+	}
+
+	supportedAlgos, _ := clientCtx.Keyring.SupportedAlgorithms()
+	algoStr, _ := cmd.Flags().GetString(flags.FlagKeyType)
+	algo, err := keyring.NewSigningAlgoFromString(algoStr, supportedAlgos)
+	if err != nil {
+		return err
+	}
+	hdPath, _ := cmd.Flags().GetString(flagHDPath)
+	if hdPath == "" {
+		account, _ := cmd.Flags().GetUint32(flagAccount)
+		index, _ := cmd.Flags().GetUint32(flagIndex)
+		hdPath = hd.CreateHDPath(sdk.GetConfig().GetCoinType(), account, index).String()
+	}
+
+	recovered, err := recoverMnemonics(clientCtx.Keyring, keys, algo, hdPath)
+	if err != nil {
+		return err
+	}
+	if clientCtx.OutputFormat == flags.OutputFormatJSON {
+		bz, err := json.Marshal(recovered)
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(bz))
+		return nil
+	}
+	for _, key := range recovered {
+		cmd.Printf("%s: %s\n", key.Name, key.Address)
+	}
+	return nil
+}
+
+// readMnemonics reads a mnemonics file, the mnemonics are checked when the
+// keys are derived.
+func readMnemonics(path string) ([]mnemonicKey, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []mnemonicKey
+	if err := json.Unmarshal(bz, &keys); err != nil {
+		return nil, fmt.Errorf("invalid mnemonics file %s: %w", path, err)
+	}
+
+	names := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		switch {
+		case key.Name == "":
+			return nil, fmt.Errorf("key %d of %s has no name", i, path)
+		case names[key.Name]:
+			return nil, fmt.Errorf("duplicate key %s in %s", key.Name, path)
+		}
+		names[key.Name] = true
+	}
+	return keys, nil
+}
+
+// recoverMnemonics adds the keys to the keyring. A key already in the keyring
+// is kept if it has the address of its mnemonic, so that seeding a keyring
+// twice succeeds, and refused otherwise. Nothing is added when a mnemonic is
+// invalid or a key is refused.
+func recoverMnemonics(kr keyring.Keyring, keys []mnemonicKey, algo keyring.SignatureAlgo, hdPath string) ([]recoveredKey, error) {
+	addresses := make([]sdk.AccAddress, len(keys))
+	existing := make([]bool, len(keys))
+	for i, key := range keys {
+		derived, err := algo.Derive()(key.Mnemonic, "", hdPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key %s: %w", key.Name, err)
+		}
+		addresses[i] = sdk.AccAddress(algo.Generate()(derived).PubKey().Address())
+
+		record, err := kr.Key(key.Name)
+		if err != nil {
+			continue
+		}
+		address, err := record.GetAddress()
+		if err != nil {
+			return nil, err
+		}
+		if !address.Equals(addresses[i]) {
+			return nil, fmt.Errorf("key %s already exists with address %s, the mnemonic derives %s", key.Name, address, addresses[i])
+		}
+		existing[i] = true
+	}
+
+	recovered := make([]recoveredKey, len(keys))
+	for i, key := range keys {
+		if !existing[i] {
+			if _, err := kr.NewAccount(key.Name, key.Mnemonic, "", hdPath, algo); err != nil {
+				return nil, fmt.Errorf("failed to recover key %s: %w", key.Name, err)
+			}
+		}
+		recovered[i] = recoveredKey{Name: key.Name, Address: addresses[i].String()}
+	}
+	return recovered, nil
+}
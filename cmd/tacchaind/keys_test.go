@@ -0,0 +1,131 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+
+	evmhd "github.com/cosmos/evm/crypto/hd"
+	evmkeyring "github.com/cosmos/evm/crypto/keyring"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// deriveAddress derives the eth_secp256k1 key of the mnemonic at path
+func deriveAddress(t *testing.T, path string) sdk.AccAddress {
+	t.Helper()
+	bz, err := evmhd.EthSecp256k1.Derive()(testMnemonic, "", path)
+	require.NoError(t, err)
+	return sdk.AccAddress(evmhd.EthSecp256k1.Generate()(bz).PubKey().Address())
+}
+
+func TestKeyDerivationVectors(t *testing.T) {
+	require.Equal(t, uint32(60), sdk.GetConfig().GetCoinType())
+
+	// the accounts Ethereum wallets derive from the mnemonic
+	for index, want := range []struct {
+		eth  string
+		bech string
+	}{
+		{"0x9858EfFD232B4033E47d90003D41EC34EcaEda94", "tac1npvwllfr9dqr8erajqqr6s0vxnk2ak55v6q28s"},
+		{"0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0", "tac1d7ky6xxfzg6rh7r05uzfxexafepy4wwqwk8za4"},
+	} {
+		addr := deriveAddress(t, hd.CreateHDPath(60, 0, uint32(index)).String())
+		require.Equal(t, common.HexToAddress(want.eth), common.BytesToAddress(addr))
+		require.Equal(t, want.bech, addr.String())
+	}
+
+	// coin type 118 derives another, unrecoverable, account
+	require.NotEqual(t,
+		deriveAddress(t, hd.CreateHDPath(60, 0, 0).String()),
+		deriveAddress(t, hd.CreateHDPath(118, 0, 0).String()),
+	)
+}
+
+func TestCheckKeyDerivation(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Uint32(flagCoinType, 60, "")
+		cmd.Flags().String(flagHDPath, "", "")
+		require.NoError(t, cmd.Flags().Parse(args))
+		return cmd
+	}
+
+	for _, args := range [][]string{
+		nil,
+		{"--coin-type", "60"},
+		{"--hd-path", "m/44'/60'/0'/0/0"},
+		{"--hd-path", "m/44'/60'/2'/0/5"},
+	} {
+		require.NoError(t, checkKeyDerivation(newCmd(args...)), args)
+	}
+
+	for _, args := range [][]string{
+		{"--coin-type", "118"},
+		{"--hd-path", "m/44'/118'/0'/0/0"},
+		{"--coin-type", "60", "--hd-path", "m/44'/118'/0'/0/0"},
+	} {
+		require.ErrorContains(t, checkKeyDerivation(newCmd(args...)), "coin type 118 is not supported", args)
+	}
+	require.ErrorContains(t, checkKeyDerivation(newCmd("--hd-path", "m/44/60")), "invalid --hd-path")
+}
+
+func TestRecoverMnemonics(t *testing.T) {
+	const otherMnemonic = "island mail dice alien project surround orchard ball twist worth innocent arrange assume dragon rotate enough flee rapid rookie swim addict ice destroy run"
+	path := filepath.Join(t.TempDir(), "mnemonics.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"name": "validator", "mnemonic": "`+otherMnemonic+`"},
+		{"name": "recipient", "mnemonic": "`+testMnemonic+`"}
+	]`), 0o600))
+	keys, err := readMnemonics(path)
+	require.NoError(t, err)
+
+	kr := keyring.NewInMemory(tacsdk.MakeEncodingConfig().Codec, evmkeyring.Option())
+	hdPath := hd.CreateHDPath(60, 0, 0).String()
+	recovered, err := recoverMnemonics(kr, keys, evmhd.EthSecp256k1, hdPath)
+	require.NoError(t, err)
+	require.Equal(t, []recoveredKey{
+		{"validator", "tac15lvhklny0khnwy7hgrxsxut6t6ku2cgknw79fr"},
+		{"recipient", "tac1npvwllfr9dqr8erajqqr6s0vxnk2ak55v6q28s"},
+	}, recovered)
+	record, err := kr.Key("recipient")
+	require.NoError(t, err)
+	address, err := record.GetAddress()
+	require.NoError(t, err)
+	require.Equal(t, deriveAddress(t, hdPath), address)
+
+	// seeding the keyring again finds the same keys
+	again, err := recoverMnemonics(kr, keys, evmhd.EthSecp256k1, hdPath)
+	require.NoError(t, err)
+	require.Equal(t, recovered, again)
+
+	// a key of another mnemonic is refused, and nothing is added
+	conflicting := []mnemonicKey{{"new", testMnemonic}, {"validator", testMnemonic}}
+	_, err = recoverMnemonics(kr, conflicting, evmhd.EthSecp256k1, hdPath)
+	require.ErrorContains(t, err, "key validator already exists with address tac15lvhklny0khnwy7hgrxsxut6t6ku2cgknw79fr")
+	_, err = kr.Key("new")
+	require.Error(t, err)
+
+	_, err = recoverMnemonics(kr, []mnemonicKey{{"new", "not a mnemonic"}}, evmhd.EthSecp256k1, hdPath)
+	require.ErrorContains(t, err, "failed to derive key new")
+
+	for content, want := range map[string]string{
+		`{"name": "validator"}`:                  "invalid mnemonics file",
+		`[{"mnemonic": "` + testMnemonic + `"}]`: "key 0 of",
+		`[{"name": "a", "mnemonic": "` + testMnemonic + `"}, {"name": "a", "mnemonic": "` + otherMnemonic + `"}]`: "duplicate key a",
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err := readMnemonics(path)
+		require.ErrorContains(t, err, want, content)
+	}
+}
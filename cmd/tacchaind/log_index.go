@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	rpcclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/spf13/cast"
+
+	"cosmossdk.io/log"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/server"
+
+	evmrpc "github.com/cosmos/evm/rpc"
+	evmbackend "github.com/cosmos/evm/rpc/backend"
+	evmfilters "github.com/cosmos/evm/rpc/namespaces/ethereum/eth/filters"
+	evmtypes "github.com/cosmos/evm/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+)
+
+// LogIndexNamespace is the JSON-RPC namespace serving eth_getLogs from the EVM
+// log index. The APIs of the namespaces listed in the json-rpc section are
+// registered in order and a method registered again replaces the previous one,
+// so the namespace must follow "eth".
+const LogIndexNamespace = "logindex"
+
+// nodeLogIndex is the log index of the app the node runs, nil if disabled
+var nodeLogIndex atomic.Pointer[app.LogIndex]
+
+func init() {
+	if err := evmrpc.RegisterAPINamespace(LogIndexNamespace, newLogIndexAPIs); err != nil {
+		panic(err)
+	}
+}
+
+func newLogIndexAPIs(
+	ctx *server.Context,
+	clientCtx client.Context,
+	_ *rpcclient.WSClient,
+	allowUnprotectedTxs bool,
+	indexer evmtypes.EVMTxIndexer,
+) []rpc.API {
+	index := nodeLogIndex.Load()
+	if index == nil {
+		ctx.Logger.Info("EVM log index is disabled, eth_getLogs scans every block")
+		return nil
+	}
+
+	return []rpc.API{{
+		Namespace: "eth",
+		Service: &logIndexAPI{
+			index:         index,
+			logger:        ctx.Logger.With("module", "log-index"),
+			backend:       evmbackend.NewBackend(ctx, ctx.Logger, clientCtx, allowUnprotectedTxs, indexer),
+			maxBlockRange: cast.ToInt64(ctx.Viper.Get(app.FlagLogIndexMaxBlockRange)),
+		},
+	}}
+}
+
+// logIndexAPI serves eth_getLogs from the log index. Only the blocks the
+// index returns as candidates are read, the ranges the index doesn't cover
+// are scanned block by block like eth_getLogs does without it.
+type logIndexAPI struct {
+	index         *app.LogIndex
+	logger        log.Logger
+	backend       *evmbackend.Backend
+	maxBlockRange int64
+}
+
+// GetLogs returns the logs matching crit, it replaces eth_getLogs
+func (api *logIndexAPI) GetLogs(ctx context.Context, crit filters.FilterCriteria) ([]*ethtypes.Log, error) {
+	if crit.BlockHash != nil {
+		return api.scan(ctx, crit)
+	}
+
+	latest, err := api.backend.BlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	from := resolveBlockNumber(crit.FromBlock, int64(latest))
+	to := resolveBlockNumber(crit.ToBlock, int64(latest))
+	heights, ok := api.index.Candidates(from, to, crit.Addresses, crit.Topics)
+	if !ok {
+		return api.scan(ctx, crit)
+	}
+	if api.maxBlockRange > 0 && to-from+1 > api.maxBlockRange {
+		return nil, fmt.Errorf("block range %d exceeds the log index limit of %d", to-from+1, api.maxBlockRange)
+	}
+
+	logsCap := int(api.backend.RPCLogsCap())
+	logs := []*ethtypes.Log{}
+	for _, height := range heights {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		blockLogs, err := api.backend.GetLogsByHeight(&height)
+		if err != nil {
+			return nil, err
+		}
+		for _, txLogs := range blockLogs {
+			logs = append(logs, evmfilters.FilterLogs(txLogs, nil, nil, crit.Addresses, crit.Topics)...)
+		}
+		if logsCap > 0 && len(logs) > logsCap {
+			return nil, fmt.Errorf("query returned more than %d results", logsCap)
+		}
+	}
+	return logs, nil
+}
+
+// scan returns the logs matching crit the way eth_getLogs does without the index
+func (api *logIndexAPI) scan(ctx context.Context, crit filters.FilterCriteria) ([]*ethtypes.Log, error) {
+	var filter *evmfilters.Filter
+	if crit.BlockHash != nil {
+		filter = evmfilters.NewBlockFilter(api.logger, api.backend, crit)
+	} else {
+		begin, end := rpc.LatestBlockNumber.Int64(), rpc.LatestBlockNumber.Int64()
+		if crit.FromBlock != nil {
+			begin = crit.FromBlock.Int64()
+		}
+		if crit.ToBlock != nil {
+			end = crit.ToBlock.Int64()
+		}
+		filter = evmfilters.NewRangeFilter(api.logger, api.backend, begin, end, crit.Addresses, crit.Topics)
+	}
+
+	logs, err := filter.Logs(ctx, int(api.backend.RPCLogsCap()), int64(api.backend.RPCBlockRangeCap()))
+	if logs == nil {
+		logs = []*ethtypes.Log{}
+	}
+	return logs, err
+}
+
+// resolveBlockNumber returns the height of a block number of a filter, the
+// latest, pending, safe and finalized tags are all the latest block as blocks
+// are final once committed
+func resolveBlockNumber(number *big.Int, latest int64) int64 {
+	if number == nil || number.Sign() < 0 {
+		return latest
+	}
+	return number.Int64()
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	cmtcfg "github.com/cometbft/cometbft/config"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/server"
+)
+
+const (
+	flagFrom = "from"
+	flagTo   = "to"
+
+	// migrateBatchSize is the number of keys written to the new database at once
+	migrateBatchSize = 10000
+
+	// stagedMarker is the file of the staging dir of a migration holding the
+	// backend the databases were copied from, written once all are copied
+	stagedMarker = "from"
+)
+
+// appDBBackendRegexp matches the database backend setting of app.toml
+var appDBBackendRegexp = regexp.MustCompile(`(?m)^app-db-backend = .*$`)
+
+// MigrateDBCmd copies the databases of a stopped node to another backend.
+func MigrateDBCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-db",
+		Short: "Migrate the databases of the node to another backend",
+		Long: `Migrate the databases of the node to another backend.
+
+All databases of the data dir, including the metadata of the state sync snapshots,
+are copied to the backend given by --to in data/<to>-migration. Once all of them are
+copied, they replace the original databases, then db_backend of config.toml and
+app-db-backend of app.toml are set to the new backend. The original databases are
+kept in data/<from>-backup until they are removed by hand. The node must be stopped.
+
+A migration interrupted while copying leaves the databases of the node untouched and
+starts over when the command is run again. Once the copies are complete, running the
+command again with the same --to finishes replacing the databases.
+
+The goleveldb and pebbledb backends are always available, rocksdb needs a binary
+built with WITH_ROCKSDB=yes.`,
+		Example: "tacchaind tools migrate-db --from goleveldb --to pebbledb",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := server.GetServerContextFromCmd(cmd)
+			dataDir := filepath.Join(ctx.Config.RootDir, "data")
+
+			to, _ := cmd.Flags().GetString(flagTo)
+			stagingDir := filepath.Join(dataDir, to+"-migration")
+			from, err := readStagedFrom(stagingDir)
+			if err != nil {
+				return err
+			}
+			if from != "" {
+				if flag, _ := cmd.Flags().GetString(flagFrom); flag != "" && flag != from {
+					return fmt.Errorf("the databases copied in %s are from %s, not %s", stagingDir, from, flag)
+				}
+				cmd.Printf("resuming the migration of the databases from %s to %s\n", from, to)
+			} else {
+				from, _ = cmd.Flags().GetString(flagFrom)
+				if from == "" {
+					from = ctx.Config.DBBackend
+				}
+				if err := stageDBs(cmd, dataDir, stagingDir, from, to, server.GetAppDBBackend(ctx.Viper)); err != nil {
+					return err
+				}
+			}
+
+			backupDir := filepath.Join(dataDir, from+"-backup")
+			names, err := stagedDBs(stagingDir)
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				if err := swapDB(name, dataDir, stagingDir, backupDir); err != nil {
+					return fmt.Errorf("failed to replace %s: %w", name, err)
+				}
+			}
+
+			ctx.Config.DBBackend = to
+			cmtcfg.WriteConfigFile(filepath.Join(ctx.Config.RootDir, "config", "config.toml"), ctx.Config)
+			if err := setAppDBBackend(filepath.Join(ctx.Config.RootDir, "config", "app.toml"), to); err != nil {
+				return err
+			}
+			// the marker is removed last, a run interrupted before resumes with
+			// the configs
+			if err := os.RemoveAll(stagingDir); err != nil {
+				return err
+			}
+			cmd.Printf("the databases use %s, the original ones are in %s\n", to, backupDir)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagFrom, "", "Backend of the databases, defaults to db_backend of config.toml")
+	cmd.Flags().String(flagTo, "", "Backend to migrate the databases to (goleveldb, pebbledb or rocksdb)")
+	_ = cmd.MarkFlagRequired(flagTo)
+
+	return cmd
+}
+
+// stageDBs copies all databases of dataDir from the backend from to the
+// backend to in stagingDir, then marks the copies as complete. The databases
+// of dataDir are left as they are.
+func stageDBs(cmd *cobra.Command, dataDir, stagingDir, from, to string, appBackend dbm.BackendType) error {
+	if from == to {
+		return fmt.Errorf("the databases already use %s", to)
+	}
+	if string(appBackend) != from {
+		return fmt.Errorf("the application database uses %s, not %s", appBackend, from)
+	}
+
+	names, err := listDBs(dataDir)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "snapshots", "metadata.db")); err == nil {
+		names = append(names, filepath.Join("snapshots", "metadata"))
+	}
+	backupDir := filepath.Join(dataDir, from+"-backup")
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(backupDir, name+".db")); err == nil {
+			return fmt.Errorf("backup %s of a previous migration exists", filepath.Join(backupDir, name+".db"))
+		}
+	}
+
+	// the copies of an interrupted run are incomplete
+	if err := os.RemoveAll(stagingDir); err != nil {
+		return err
+	}
+	for _, name := range names {
+		keys, err := stageDB(name, dbm.BackendType(from), dbm.BackendType(to), dataDir, stagingDir)
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", name, err)
+		}
+		cmd.Printf("%s: migrated %d keys\n", name, keys)
+	}
+	return writeStagedFrom(stagingDir, from)
+}
+
+// stageDB copies the database name in dataDir to the backend to in
+// stagingDir and returns the number of keys copied.
+func stageDB(name string, from, to dbm.BackendType, dataDir, stagingDir string) (int, error) {
+	src, err := dbm.NewDB(filepath.Base(name), from, filepath.Join(dataDir, filepath.Dir(name)))
+	if err != nil {
+		return 0, err
+	}
+	dst, err := dbm.NewDB(filepath.Base(name), to, filepath.Join(stagingDir, filepath.Dir(name)))
+	if err != nil {
+		src.Close()
+		return 0, err
+	}
+	keys, err := copyDB(src, dst)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := src.Close(); err == nil {
+		err = closeErr
+	}
+	return keys, err
+}
+
+// stagedDBs returns the databases copied in stagingDir which don't replace
+// the databases of the node yet
+func stagedDBs(stagingDir string) ([]string, error) {
+	names, err := listDBs(stagingDir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(stagingDir, "snapshots", "metadata.db")); err == nil {
+		names = append(names, filepath.Join("snapshots", "metadata"))
+	}
+	return names, nil
+}
+
+// swapDB moves the database name of dataDir to backupDir and its copy in
+// stagingDir in its place. It picks up where an interrupted swap stopped: a
+// database whose copy is already in place is skipped, and one already backed
+// up only has its copy moved.
+func swapDB(name, dataDir, stagingDir, backupDir string) error {
+	staged := filepath.Join(stagingDir, name+".db")
+	if _, err := os.Stat(staged); os.IsNotExist(err) {
+		return nil
+	}
+	current := filepath.Join(dataDir, name+".db")
+	if _, err := os.Stat(current); err == nil {
+		backup := filepath.Join(backupDir, name+".db")
+		if _, err := os.Stat(backup); err == nil {
+			return fmt.Errorf("backup %s of a previous migration exists", backup)
+		}
+		if err := os.MkdirAll(filepath.Dir(backup), 0o755); err != nil {
+			return err
+		}
+		if err := os.Rename(current, backup); err != nil {
+			return err
+		}
+	}
+	return os.Rename(staged, current)
+}
+
+// readStagedFrom returns the backend of the databases copied in stagingDir,
+// empty if the copies aren't complete
+func readStagedFrom(stagingDir string) (string, error) {
+	bz, err := os.ReadFile(filepath.Join(stagingDir, stagedMarker))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	return strings.TrimSpace(string(bz)), err
+}
+
+// writeStagedFrom marks the copies in stagingDir of the databases of the
+// backend from as complete
+func writeStagedFrom(stagingDir, from string) error {
+	f, err := os.Create(filepath.Join(stagingDir, stagedMarker))
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(from + "\n")
+	if syncErr := f.Sync(); err == nil {
+		err = syncErr
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// copyDB writes all keys of src to dst and returns their number
+func copyDB(src, dst dbm.DB) (int, error) {
+	it, err := src.Iterator(nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+
+	var keys int
+	batch := dst.NewBatch()
+	for ; it.Valid(); it.Next() {
+		if err := batch.Set(it.Key(), it.Value()); err != nil {
+			return 0, err
+		}
+		keys++
+		if keys%migrateBatchSize == 0 {
+			if err := batch.Write(); err != nil {
+				return 0, err
+			}
+			if err := batch.Close(); err != nil {
+				return 0, err
+			}
+			batch = dst.NewBatch()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return 0, err
+	}
+	// the last batch is synced, so the copy is on disk when the databases are swapped
+	if err := batch.WriteSync(); err != nil {
+		return 0, err
+	}
+	return keys, batch.Close()
+}
+
+// setAppDBBackend sets app-db-backend in the app.toml at path
+func setAppDBBackend(path, backend string) error {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if !appDBBackendRegexp.Match(bz) {
+		return fmt.Errorf("app-db-backend not found in %s", path)
+	}
+	bz = appDBBackendRegexp.ReplaceAll(bz, []byte(fmt.Sprintf("app-db-backend = %q", backend)))
+	return os.WriteFile(path, bz, 0o644)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+// migrateDBs stages the databases of dataDir to pebbledb and swaps them in
+func migrateDBs(t *testing.T, dataDir string) {
+	t.Helper()
+	stagingDir := filepath.Join(dataDir, "pebbledb-migration")
+	require.NoError(t, stageDBs(&cobra.Command{}, dataDir, stagingDir, "goleveldb", "pebbledb", dbm.GoLevelDBBackend))
+	names, err := stagedDBs(stagingDir)
+	require.NoError(t, err)
+	for _, name := range names {
+		require.NoError(t, swapDB(name, dataDir, stagingDir, filepath.Join(dataDir, "goleveldb-backup")))
+	}
+	require.NoError(t, os.RemoveAll(stagingDir))
+}
+
+// newNodeDBs writes the application database, with keys more than fit into
+// a batch, and the snapshot metadata of a goleveldb node in dataDir and
+// returns the number of keys of the application database
+func newNodeDBs(t *testing.T, dataDir string) int {
+	t.Helper()
+	db, err := dbm.NewDB("application", dbm.GoLevelDBBackend, dataDir)
+	require.NoError(t, err)
+	keys := migrateBatchSize + 123
+	for i := 0; i < keys; i++ {
+		require.NoError(t, db.Set([]byte(fmt.Sprintf("key%06d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+	require.NoError(t, db.Close())
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "snapshots"), 0o755))
+	db, err = dbm.NewDB("metadata", dbm.GoLevelDBBackend, filepath.Join(dataDir, "snapshots"))
+	require.NoError(t, err)
+	require.NoError(t, db.Set([]byte("snapshot"), []byte("metadata")))
+	require.NoError(t, db.Close())
+	return keys
+}
+
+// requireMigrated checks the databases of newNodeDBs are in pebbledb in
+// dataDir and the originals backed up
+func requireMigrated(t *testing.T, dataDir string, keys int) {
+	t.Helper()
+	backupDir := filepath.Join(dataDir, "goleveldb-backup")
+	names, err := listDBs(dataDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"application"}, names)
+	require.DirExists(t, filepath.Join(backupDir, "application.db"))
+	require.DirExists(t, filepath.Join(backupDir, "snapshots", "metadata.db"))
+
+	db, err := dbm.NewDB("application", dbm.PebbleDBBackend, dataDir)
+	require.NoError(t, err)
+	for _, i := range []int{0, migrateBatchSize, keys - 1} {
+		value, err := db.Get([]byte(fmt.Sprintf("key%06d", i)))
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("value%d", i), string(value))
+	}
+	require.NoError(t, db.Close())
+
+	db, err = dbm.NewDB("metadata", dbm.PebbleDBBackend, filepath.Join(dataDir, "snapshots"))
+	require.NoError(t, err)
+	value, err := db.Get([]byte("snapshot"))
+	require.NoError(t, err)
+	require.Equal(t, "metadata", string(value))
+	require.NoError(t, db.Close())
+}
+
+func TestMigrateDB(t *testing.T) {
+	dataDir := t.TempDir()
+	keys := newNodeDBs(t, dataDir)
+
+	migrateDBs(t, dataDir)
+	requireMigrated(t, dataDir, keys)
+	require.NoDirExists(t, filepath.Join(dataDir, "pebbledb-migration"))
+
+	// a migration doesn't overwrite the backups of a previous one
+	err := stageDBs(&cobra.Command{}, dataDir, filepath.Join(dataDir, "pebbledb-migration"), "goleveldb", "pebbledb", dbm.GoLevelDBBackend)
+	require.ErrorContains(t, err, "of a previous migration exists")
+
+	err = stageDBs(&cobra.Command{}, dataDir, filepath.Join(dataDir, "pebbledb-migration"), "pebbledb", "pebbledb", dbm.PebbleDBBackend)
+	require.ErrorContains(t, err, "already use pebbledb")
+	err = stageDBs(&cobra.Command{}, dataDir, filepath.Join(dataDir, "goleveldb-migration"), "pebbledb", "goleveldb", dbm.GoLevelDBBackend)
+	require.ErrorContains(t, err, "the application database uses goleveldb")
+}
+
+func TestMigrateDBInterrupted(t *testing.T) {
+	dataDir := t.TempDir()
+	keys := newNodeDBs(t, dataDir)
+	stagingDir := filepath.Join(dataDir, "pebbledb-migration")
+	backupDir := filepath.Join(dataDir, "goleveldb-backup")
+
+	// a copy interrupted before the marker leaves the node as it is and is
+	// started over
+	_, err := stageDB("application", dbm.GoLevelDBBackend, dbm.PebbleDBBackend, dataDir, stagingDir)
+	require.NoError(t, err)
+	from, err := readStagedFrom(stagingDir)
+	require.NoError(t, err)
+	require.Empty(t, from)
+	require.NoDirExists(t, backupDir)
+
+	require.NoError(t, stageDBs(&cobra.Command{}, dataDir, stagingDir, "goleveldb", "pebbledb", dbm.GoLevelDBBackend))
+	from, err = readStagedFrom(stagingDir)
+	require.NoError(t, err)
+	require.Equal(t, "goleveldb", from)
+
+	// the swap is interrupted after backing up the snapshot metadata
+	require.NoError(t, os.MkdirAll(filepath.Join(backupDir, "snapshots"), 0o755))
+	require.NoError(t, os.Rename(filepath.Join(dataDir, "snapshots", "metadata.db"), filepath.Join(backupDir, "snapshots", "metadata.db")))
+
+	// and picks up where it stopped
+	names, err := stagedDBs(stagingDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"application", filepath.Join("snapshots", "metadata")}, names)
+	for _, name := range names {
+		require.NoError(t, swapDB(name, dataDir, stagingDir, backupDir))
+	}
+	// swapping again is a no-op
+	for _, name := range names {
+		require.NoError(t, swapDB(name, dataDir, stagingDir, backupDir))
+	}
+	names, err = stagedDBs(stagingDir)
+	require.NoError(t, err)
+	require.Empty(t, names)
+	requireMigrated(t, dataDir, keys)
+}
+
+func TestSetAppDBBackend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.toml")
+	require.NoError(t, os.WriteFile(path, []byte("minimum-gas-prices = \"0utac\"\napp-db-backend = \"\"\n\n[api]\nenable = false\n"), 0o644))
+
+	require.NoError(t, setAppDBBackend(path, "pebbledb"))
+	bz, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "minimum-gas-prices = \"0utac\"\napp-db-backend = \"pebbledb\"\n\n[api]\nenable = false\n", string(bz))
+
+	require.NoError(t, os.WriteFile(path, []byte("[api]\nenable = false\n"), 0o644))
+	require.Error(t, setAppDBBackend(path, "pebbledb"))
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmtrpcclient "github.com/cometbft/cometbft/rpc/client"
+	cmtrpctypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	errorsmod "cosmossdk.io/errors"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/kv"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	paramproposal "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+)
+
+// mockNode stands in for the node behind a client.Context. It answers the
+// gRPC queries with the handlers registered in place of the keepers, the
+// store queries with the values of its stores and the x/params queries with
+// the params set by setParams, at heights up to its latest height. Its
+// mempool holds the unconfirmed txs and its block store the blocks and block
+// results set by setBlock, whose txs it indexes. The TAC modules have no
+// gRPC query servers to test with mocked keepers, so their queries are tested
+// through it.
+type mockNode struct {
+	// the methods of the node that aren't mocked panic
+	client.CometRPC
+
+	t        *testing.T
+	height   int64
+	handlers map[string]func(req []byte) ([]byte, error)
+	stores   map[string]map[string][]byte
+	params   map[string]map[string]string
+	// heights records the height of every query by path, 0 being the latest
+	heights      map[string][]int64
+	unconfirmed  []cmttypes.Tx
+	blocks       map[int64]*cmtrpctypes.ResultBlock
+	blockResults map[int64]*cmtrpctypes.ResultBlockResults
+}
+
+func newMockNode(t *testing.T, height int64) *mockNode {
+	n := &mockNode{
+		t:            t,
+		height:       height,
+		handlers:     map[string]func(req []byte) ([]byte, error){},
+		stores:       map[string]map[string][]byte{},
+		params:       map[string]map[string]string{},
+		heights:      map[string][]int64{},
+		blocks:       map[int64]*cmtrpctypes.ResultBlock{},
+		blockResults: map[int64]*cmtrpctypes.ResultBlockResults{},
+	}
+	handle(n, "/cosmos.params.v1beta1.Query/Params", func(req *paramproposal.QueryParamsRequest) (proto.Message, error) {
+		subspace, ok := n.params[req.Subspace]
+		if !ok {
+			return nil, errorsmod.Wrap(paramproposal.ErrUnknownSubspace, req.Subspace)
+		}
+		// like x/params, a key that isn't set has an empty value
+		return &paramproposal.QueryParamsResponse{
+			Param: paramproposal.NewParamChange(req.Subspace, req.Key, subspace[req.Key]),
+		}, nil
+	})
+	return n
+}
+
+// clientCtx returns a query context of the node
+func (n *mockNode) clientCtx() client.Context {
+	encodingConfig := tacsdk.MakeEncodingConfig()
+	return client.Context{}.
+		WithCodec(encodingConfig.Codec).
+		WithInterfaceRegistry(encodingConfig.InterfaceRegistry).
+		WithTxConfig(encodingConfig.TxConfig).
+		WithLegacyAmino(encodingConfig.Amino).
+		WithClient(n)
+}
+
+// run executes the query cmd against the node and returns its output
+func (n *mockNode) run(cmd *cobra.Command, args ...string) (string, error) {
+	var out bytes.Buffer
+	clientCtx := n.clientCtx().WithOutput(&out)
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err := cmd.ExecuteContext(context.WithValue(context.Background(), client.ClientContextKey, &clientCtx))
+	return out.String(), err
+}
+
+// handle registers handler as the gRPC method, the requests are decoded to
+// the type of its argument
+func handle[Req any, PReq interface {
+	*Req
+	proto.Message
+}](n *mockNode, method string, handler func(PReq) (proto.Message, error)) {
+	n.handlers[method] = func(bz []byte) ([]byte, error) {
+		req := PReq(new(Req))
+		if err := proto.Unmarshal(bz, req); err != nil {
+			return nil, errorsmod.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+		}
+		res, err := handler(req)
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(res)
+	}
+}
+
+// setParams stores params in subspace as amino JSON, like x/params does
+func (n *mockNode) setParams(subspace string, params paramtypes.ParamSet) {
+	n.params[subspace] = map[string]string{}
+	for _, pair := range params.ParamSetPairs() {
+		bz, err := n.clientCtx().LegacyAmino.MarshalJSON(pair.Value)
+		require.NoError(n.t, err)
+		n.params[subspace][string(pair.Key)] = string(bz)
+	}
+}
+
+// set stores value at key in the store of a module
+func (n *mockNode) set(store string, key, value []byte) {
+	if n.stores[store] == nil {
+		n.stores[store] = map[string][]byte{}
+	}
+	n.stores[store][string(key)] = value
+}
+
+// setBlock stores block and the results of its execution at the height of
+// block
+func (n *mockNode) setBlock(block *cmttypes.Block, results *cmtrpctypes.ResultBlockResults) {
+	height := block.Height
+	n.blocks[height] = &cmtrpctypes.ResultBlock{BlockID: cmttypes.BlockID{Hash: block.Hash()}, Block: block}
+	results.Height = height
+	n.blockResults[height] = results
+}
+
+func (n *mockNode) Block(_ context.Context, height *int64) (*cmtrpctypes.ResultBlock, error) {
+	block, ok := n.blocks[*height]
+	if !ok {
+		return nil, fmt.Errorf("height %d is not available", *height)
+	}
+	return block, nil
+}
+
+func (n *mockNode) BlockResults(_ context.Context, height *int64) (*cmtrpctypes.ResultBlockResults, error) {
+	h := n.height
+	if height != nil {
+		h = *height
+	}
+	results, ok := n.blockResults[h]
+	if !ok {
+		return nil, fmt.Errorf("could not find results for height #%d", h)
+	}
+	return results, nil
+}
+
+func (n *mockNode) Tx(_ context.Context, hash []byte, _ bool) (*cmtrpctypes.ResultTx, error) {
+	for height, block := range n.blocks {
+		for i, tx := range block.Block.Txs {
+			if bytes.Equal(tx.Hash(), hash) {
+				return n.resultTx(height, i), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("tx (%X) not found", hash)
+}
+
+// TxSearch returns the txs of the blocks with an event attribute matching
+// query, which must be of the form type.key='value'
+func (n *mockNode) TxSearch(_ context.Context, query string, _ bool, _, _ *int, _ string) (*cmtrpctypes.ResultTxSearch, error) {
+	attribute, value, ok := strings.Cut(query, "=")
+	require.True(n.t, ok, "unsupported query %s", query)
+	eventType, key, _ := strings.Cut(attribute, ".")
+	value = strings.Trim(value, "'")
+
+	heights := make([]int64, 0, len(n.blockResults))
+	for height := range n.blockResults {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+
+	res := &cmtrpctypes.ResultTxSearch{Txs: []*cmtrpctypes.ResultTx{}}
+	for _, height := range heights {
+		for i, txRes := range n.blockResults[height].TxsResults {
+			if hasEventAttribute(txRes.Events, eventType, key, value) {
+				res.Txs = append(res.Txs, n.resultTx(height, i))
+			}
+		}
+	}
+	res.TotalCount = len(res.Txs)
+	return res, nil
+}
+
+func (n *mockNode) resultTx(height int64, index int) *cmtrpctypes.ResultTx {
+	tx := n.blocks[height].Block.Txs[index]
+	return &cmtrpctypes.ResultTx{
+		Hash:     tx.Hash(),
+		Height:   height,
+		Index:    uint32(index),
+		TxResult: *n.blockResults[height].TxsResults[index],
+		Tx:       tx,
+	}
+}
+
+func hasEventAttribute(events []abci.Event, eventType, key, value string) bool {
+	for _, event := range events {
+		for _, attr := range event.Attributes {
+			if event.Type == eventType && attr.Key == key && attr.Value == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Commit returns the header of the block at height signed by a commit of its
+// proposer, whose signature isn't real
+func (n *mockNode) Commit(_ context.Context, height *int64) (*cmtrpctypes.ResultCommit, error) {
+	block, ok := n.blocks[*height]
+	if !ok {
+		return nil, fmt.Errorf("height %d is not available", *height)
+	}
+	header := block.Block.Header
+	commit := &cmttypes.Commit{
+		Height:  header.Height,
+		BlockID: block.BlockID,
+		Signatures: []cmttypes.CommitSig{{
+			BlockIDFlag:      cmttypes.BlockIDFlagCommit,
+			ValidatorAddress: header.ProposerAddress,
+			Timestamp:        header.Time,
+			Signature:        bytes.Repeat([]byte{0x01}, 64),
+		}},
+	}
+	return &cmtrpctypes.ResultCommit{SignedHeader: cmttypes.SignedHeader{Header: &header, Commit: commit}, CanonicalCommit: true}, nil
+}
+
+func (n *mockNode) Status(context.Context) (*cmtrpctypes.ResultStatus, error) {
+	return &cmtrpctypes.ResultStatus{SyncInfo: cmtrpctypes.SyncInfo{LatestBlockHeight: n.height}}, nil
+}
+
+func (n *mockNode) UnconfirmedTxs(_ context.Context, limit *int) (*cmtrpctypes.ResultUnconfirmedTxs, error) {
+	txs := n.unconfirmed
+	if limit != nil && *limit < len(txs) {
+		txs = txs[:*limit]
+	}
+	res := &cmtrpctypes.ResultUnconfirmedTxs{Count: len(txs), Total: len(n.unconfirmed), Txs: txs}
+	for _, tx := range n.unconfirmed {
+		res.TotalBytes += int64(len(tx))
+	}
+	return res, nil
+}
+
+func (n *mockNode) ABCIQueryWithOptions(_ context.Context, path string, data cmtbytes.HexBytes, opts cmtrpcclient.ABCIQueryOptions) (*cmtrpctypes.ResultABCIQuery, error) {
+	n.heights[path] = append(n.heights[path], opts.Height)
+
+	height := opts.Height
+	if height == 0 {
+		height = n.height
+	}
+	value, err := n.query(path, data, height)
+	if err != nil {
+		codespace, code, log := errorsmod.ABCIInfo(err, false)
+		return &cmtrpctypes.ResultABCIQuery{Response: abci.ResponseQuery{Codespace: codespace, Code: code, Log: log, Height: height}}, nil
+	}
+	return &cmtrpctypes.ResultABCIQuery{Response: abci.ResponseQuery{Value: value, Height: height}}, nil
+}
+
+func (n *mockNode) query(path string, data []byte, height int64) ([]byte, error) {
+	if height > n.height {
+		return nil, errorsmod.Wrapf(sdkerrors.ErrInvalidHeight, "cannot query with height in the future; please provide a valid height: %d", height)
+	}
+
+	if storePath, ok := strings.CutPrefix(path, "/store/"); ok {
+		name, kind, _ := strings.Cut(storePath, "/")
+		store := n.stores[name]
+		switch kind {
+		case "key":
+			return store[string(data)], nil
+		case "subspace":
+			var keys []string
+			for key := range store {
+				if strings.HasPrefix(key, string(data)) {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			pairs := kv.Pairs{Pairs: []kv.Pair{}}
+			for _, key := range keys {
+				pairs.Pairs = append(pairs.Pairs, kv.Pair{Key: []byte(key), Value: store[key]})
+			}
+			return pairs.Marshal()
+		}
+	}
+
+	handler, ok := n.handlers[path]
+	if !ok {
+		return nil, errorsmod.Wrapf(sdkerrors.ErrUnknownRequest, "unknown query path %s", path)
+	}
+	return handler(data)
+}
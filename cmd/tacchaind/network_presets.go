@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cmtcfg "github.com/cometbft/cometbft/config"
+
+	sdkmath "cosmossdk.io/math"
+
+	genutiltypes "github.com/cosmos/cosmos-sdk/x/genutil/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	emissiontypes "github.com/Asphere-xyz/tacchain/x/emission/types"
+)
+
+const flagPreset = "preset"
+
+// networkPreset holds the genesis params and node settings of a kind of TAC
+// network, so networks initialized with it match the public ones instead of
+// the SDK defaults patched by scripts.
+type networkPreset struct {
+	// ChainID and PersistentPeers of the public network of the preset, the
+	// peers are only written for nodes initialized with its chain id
+	ChainID         string
+	PersistentPeers string
+
+	VotingPeriod           time.Duration
+	ExpeditedVotingPeriod  time.Duration
+	MinDeposit             sdkmath.Int
+	ExpeditedMinDeposit    sdkmath.Int
+	MinInitialDepositRatio sdkmath.LegacyDec
+
+	BlocksPerYear uint64
+	InflationMax  sdkmath.LegacyDec
+	InflationMin  sdkmath.LegacyDec
+	GoalBonded    sdkmath.LegacyDec
+
+	NoBaseFee   bool
+	MinGasPrice sdkmath.LegacyDec
+	MaxGas      int64
+
+	MaxValidators         uint32
+	SignedBlocksWindow    int64
+	SlashFractionDowntime sdkmath.LegacyDec
+
+	// Node adjusts config.toml, nil if the preset keeps the defaults
+	Node nodePreset
+}
+
+// staticPrecompiles are the EVM precompiles active on the public networks:
+// p256, bech32, staking, distribution, ics20, vesting, bank, gov, slashing and evidence.
+var staticPrecompiles = []string{
+	"0x0000000000000000000000000000000000000100",
+	"0x0000000000000000000000000000000000000400",
+	"0x0000000000000000000000000000000000000800",
+	"0x0000000000000000000000000000000000000801",
+	"0x0000000000000000000000000000000000000802",
+	"0x0000000000000000000000000000000000000803",
+	"0x0000000000000000000000000000000000000804",
+	"0x0000000000000000000000000000000000000805",
+	"0x0000000000000000000000000000000000000806",
+	"0x0000000000000000000000000000000000000807",
+}
+
+// mainnetPreset matches the genesis of tacchain_239-1.
+func mainnetPreset() networkPreset {
+	return networkPreset{
+		ChainID:         "tacchain_239-1",
+		PersistentPeers: "d0a80c43a10a6b60475864728db6d9ba4ead42d2@107.6.113.60:58960,10550a03e4f7fa487c78fbd07e0770e2b0f085c7@64.46.115.78:58960,0efae9d157f0ef60ad7d25507d6939799f832e34@69.4.239.26:58960,78079166d06e345dbf4a5c932ee3c69a04148e92@107.6.91.38:58960",
+
+		VotingPeriod:           12 * time.Hour,
+		ExpeditedVotingPeriod:  6 * time.Hour,
+		MinDeposit:             sdkmath.NewIntWithDecimal(1, 16),
+		ExpeditedMinDeposit:    sdkmath.NewIntWithDecimal(5, 16),
+		MinInitialDepositRatio: sdkmath.LegacyOneDec(),
+
+		// 2s blocks
+		BlocksPerYear: 15768000,
+		InflationMax:  sdkmath.LegacyNewDecWithPrec(5, 2),
+		InflationMin:  sdkmath.LegacyZeroDec(),
+		GoalBonded:    sdkmath.LegacyNewDecWithPrec(6, 1),
+
+		NoBaseFee:   false,
+		MinGasPrice: sdkmath.LegacyNewDec(25000000000),
+		MaxGas:      90000000,
+
+		MaxValidators:         14,
+		SignedBlocksWindow:    21600,
+		SlashFractionDowntime: sdkmath.LegacyNewDecWithPrec(1, 3),
+	}
+}
+
+// testnetPreset matches the genesis of the Saint Petersburg testnet tacchain_2391-1.
+func testnetPreset() networkPreset {
+	return networkPreset{
+		ChainID:         "tacchain_2391-1",
+		PersistentPeers: "9c32b3b959a2427bd2aa064f8c9a8efebdad4c23@206.217.210.164:45130,04a2152eed9f73dc44779387a870ea6480c41fe7@206.217.210.164:45140,5aaaf8140262d7416ac53abe4e0bd13b0f582168@23.92.177.41:45110,ddb3e8b8f4d051e914686302dafc2a73adf9b0d2@23.92.177.41:45120",
+
+		VotingPeriod:           15 * time.Minute,
+		ExpeditedVotingPeriod:  10 * time.Minute,
+		MinDeposit:             sdkmath.NewInt(10000000),
+		ExpeditedMinDeposit:    sdkmath.NewInt(50000000),
+		MinInitialDepositRatio: sdkmath.LegacyZeroDec(),
+
+		BlocksPerYear: 15768000,
+		InflationMax:  sdkmath.LegacyNewDecWithPrec(7, 2),
+		InflationMin:  sdkmath.LegacyNewDecWithPrec(2, 2),
+		GoalBonded:    sdkmath.LegacyNewDecWithPrec(7, 1),
+
+		NoBaseFee:   false,
+		MinGasPrice: sdkmath.LegacyZeroDec(),
+		MaxGas:      90000000,
+
+		MaxValidators:         100,
+		SignedBlocksWindow:    100,
+		SlashFractionDowntime: sdkmath.LegacyNewDecWithPrec(1, 2),
+	}
+}
+
+// devnetPreset follows mainnet, except for proposals passing within seconds,
+// txs paying no base fee and downtime being detected within 100 blocks, so
+// local networks and the e2e tests exercise the mainnet economics quickly.
+func devnetPreset() networkPreset {
+	p := mainnetPreset()
+	p.ChainID = ""
+	p.PersistentPeers = ""
+	p.VotingPeriod = 4 * time.Second
+	p.ExpeditedVotingPeriod = 2 * time.Second
+	p.MinInitialDepositRatio = sdkmath.LegacyZeroDec()
+	p.NoBaseFee = true
+	p.MinGasPrice = sdkmath.LegacyZeroDec()
+	p.MaxValidators = 100
+	p.SignedBlocksWindow = 100
+	p.SlashFractionDowntime = sdkmath.LegacyNewDecWithPrec(1, 2)
+	p.Node = func(cfg *cmtcfg.Config) {
+		// devnet nodes usually run side by side on a single host
+		cfg.P2P.AddrBookStrict = false
+		cfg.P2P.AllowDuplicateIP = true
+		cfg.RPC.CORSAllowedOrigins = []string{"*"}
+	}
+	return p
+}
+
+var networkPresets = map[string]func() networkPreset{
+	"devnet":  devnetPreset,
+	"testnet": testnetPreset,
+	"mainnet": mainnetPreset,
+}
+
+func networkPresetNames() []string {
+	names := make([]string, 0, len(networkPresets))
+	for name := range networkPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// networkPresetByName returns the network preset of the given name, nil if name is empty.
+func networkPresetByName(name string) (*networkPreset, error) {
+	if name == "" {
+		return nil, nil
+	}
+	preset, ok := networkPresets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown --%s %s, expected one of %s", flagPreset, name, strings.Join(networkPresetNames(), ", "))
+	}
+	p := preset()
+	return &p, nil
+}
+
+// nodePreset returns the config.toml settings of the preset for a node of the given chain.
+func (p networkPreset) nodePreset(chainID string) nodePreset {
+	return func(cfg *cmtcfg.Config) {
+		if p.Node != nil {
+			p.Node(cfg)
+		}
+		if p.PersistentPeers != "" && chainID == p.ChainID {
+			cfg.P2P.PersistentPeers = joinPeers(cfg.P2P.PersistentPeers, strings.Split(p.PersistentPeers, ","))
+		}
+	}
+}
+
+// applyGenesis sets the params of the preset in a genesis written by init.
+func (p networkPreset) applyGenesis(appGenesis *genutiltypes.AppGenesis) error {
+	evmChainID, err := app.EVMChainID(appGenesis.ChainID)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(appGenesis.AppState))
+	decoder.UseNumber()
+	var appState map[string]any
+	if err := decoder.Decode(&appState); err != nil {
+		return fmt.Errorf("failed to decode app state: %w", err)
+	}
+
+	// the EVM modules default to the atest denom and the 262144 chain id
+	replaceGenesisValues(appState, "denom", "atest", app.BaseDenom)
+	replaceGenesisValues(appState, "evm_denom", "atest", app.BaseDenom)
+	replaceGenesisValues(appState, "chain_id", "262144", strconv.FormatUint(evmChainID, 10))
+
+	deposit := func(amount sdkmath.Int) []any {
+		return []any{map[string]any{"denom": app.BaseDenom, "amount": amount.String()}}
+	}
+	for _, v := range []struct {
+		path  string
+		value any
+	}{
+		{"bank.denom_metadata", []any{map[string]any{
+			"description": "The native staking token for tacchaind.",
+			"denom_units": []any{
+				map[string]any{"denom": app.BaseDenom, "exponent": 0, "aliases": []any{}},
+				map[string]any{"denom": app.DisplayDenom, "exponent": app.BaseDenomUnit, "aliases": []any{}},
+			},
+			"base":     app.BaseDenom,
+			"display":  app.DisplayDenom,
+			"name":     "TAC Token",
+			"symbol":   "TAC",
+			"uri":      "",
+			"uri_hash": "",
+		}}},
+		{"evm.params.extra_eips", []any{"3855"}},
+		{"evm.params.allow_unprotected_txs", true},
+		{"evm.params.active_static_precompiles", staticPrecompiles},
+		{"feemarket.params.no_base_fee", p.NoBaseFee},
+		{"feemarket.params.min_gas_price", p.MinGasPrice.String()},
+		{"gov.params.voting_period", durationString(p.VotingPeriod)},
+		{"gov.params.expedited_voting_period", durationString(p.ExpeditedVotingPeriod)},
+		{"gov.params.min_deposit", deposit(p.MinDeposit)},
+		{"gov.params.expedited_min_deposit", deposit(p.ExpeditedMinDeposit)},
+		{"gov.params.min_initial_deposit_ratio", p.MinInitialDepositRatio.String()},
+		{"mint.params.blocks_per_year", strconv.FormatUint(p.BlocksPerYear, 10)},
+		{"mint.params.inflation_max", p.InflationMax.String()},
+		{"mint.params.inflation_min", p.InflationMin.String()},
+		{"mint.params.goal_bonded", p.GoalBonded.String()},
+		{"emission.params.blocks_per_year", p.BlocksPerYear},
+		{"emission.params.reduction_interval", emissiontypes.DefaultReductionYears * p.BlocksPerYear},
+		{"staking.params.max_validators", p.MaxValidators},
+		{"slashing.params.signed_blocks_window", strconv.FormatInt(p.SignedBlocksWindow, 10)},
+		{"slashing.params.slash_fraction_downtime", p.SlashFractionDowntime.String()},
+	} {
+		if err := setGenesisValue(appState, v.path, v.value); err != nil {
+			return err
+		}
+	}
+
+	bz, err := json.Marshal(appState)
+	if err != nil {
+		return err
+	}
+	appGenesis.AppState = bz
+
+	if appGenesis.Consensus == nil || appGenesis.Consensus.Params == nil {
+		return fmt.Errorf("genesis has no consensus params")
+	}
+	appGenesis.Consensus.Params.Block.MaxGas = p.MaxGas
+	return nil
+}
+
+// setGenesisValue sets the value at a dot separated path of the app state. The
+// path must exist, so a preset never silently adds fields a module ignores.
+func setGenesisValue(appState map[string]any, path string, value any) error {
+	keys := strings.Split(path, ".")
+	obj := appState
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := obj[key].(map[string]any)
+		if !ok {
+			return fmt.Errorf("genesis has no %s", path)
+		}
+		obj = next
+	}
+	last := keys[len(keys)-1]
+	if _, ok := obj[last]; !ok {
+		return fmt.Errorf("genesis has no %s", path)
+	}
+	obj[last] = value
+	return nil
+}
+
+// replaceGenesisValues replaces the string value old of every key in v by new.
+func replaceGenesisValues(v any, key, old, new string) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, value := range v {
+			if s, ok := value.(string); ok && k == key && s == old {
+				v[k] = new
+				continue
+			}
+			replaceGenesisValues(value, key, old, new)
+		}
+	case []any:
+		for _, value := range v {
+			replaceGenesisValues(value, key, old, new)
+		}
+	}
+}
+
+// durationString formats a duration like the protobuf JSON encoding, e.g. 900s
+func durationString(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+}
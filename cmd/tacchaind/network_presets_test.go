@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	cmtcfg "github.com/cometbft/cometbft/config"
+	cmttypes "github.com/cometbft/cometbft/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	genutiltypes "github.com/cosmos/cosmos-sdk/x/genutil/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+)
+
+// genesisValue returns the value at a dot separated path of the app state
+func genesisValue(t *testing.T, appState map[string]any, path ...string) any {
+	t.Helper()
+	var v any = appState
+	for _, key := range path {
+		obj, ok := v.(map[string]any)
+		require.True(t, ok, "%v is not an object", path)
+		v, ok = obj[key]
+		require.True(t, ok, "%v not found", path)
+	}
+	return v
+}
+
+func TestNetworkPresetsApplyGenesis(t *testing.T) {
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.AppOptionsMap{flags.FlagHome: t.TempDir()},
+	})
+
+	for _, name := range networkPresetNames() {
+		t.Run(name, func(t *testing.T) {
+			preset, err := networkPresetByName(name)
+			require.NoError(t, err)
+
+			appState, err := json.Marshal(tacApp.DefaultGenesis())
+			require.NoError(t, err)
+			appGenesis := genutiltypes.NewAppGenesisWithVersion(app.DefaultChainID, appState)
+			appGenesis.Consensus = genutiltypes.NewConsensusGenesis(cmttypes.DefaultConsensusParams().ToProto(), nil)
+
+			require.NoError(t, preset.applyGenesis(appGenesis))
+			require.Equal(t, preset.MaxGas, appGenesis.Consensus.Params.Block.MaxGas)
+
+			// the preset genesis is valid for every module
+			var genesis map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(appGenesis.AppState, &genesis))
+			require.NoError(t, tacApp.BasicModuleManager.ValidateGenesis(tacApp.AppCodec(), tacApp.TxConfig(), genesis))
+
+			var state map[string]any
+			require.NoError(t, json.Unmarshal(appGenesis.AppState, &state))
+			require.Equal(t, app.BaseDenom, genesisValue(t, state, "evm", "params", "evm_denom"))
+			require.Equal(t, "2391", genesisValue(t, state, "evm", "params", "chain_config", "chain_id"))
+			require.Equal(t, durationString(preset.VotingPeriod), genesisValue(t, state, "gov", "params", "voting_period"))
+			require.Equal(t, preset.MinGasPrice.String(), genesisValue(t, state, "feemarket", "params", "min_gas_price"))
+			require.Equal(t, preset.NoBaseFee, genesisValue(t, state, "feemarket", "params", "no_base_fee"))
+			require.Equal(t, "15768000", genesisValue(t, state, "mint", "params", "blocks_per_year"))
+			require.EqualValues(t, 15768000, genesisValue(t, state, "emission", "params", "blocks_per_year"))
+			require.Len(t, genesisValue(t, state, "bank", "denom_metadata"), 1)
+		})
+	}
+
+	_, err := networkPresetByName("localnet")
+	require.Error(t, err)
+	preset, err := networkPresetByName("")
+	require.NoError(t, err)
+	require.Nil(t, preset)
+}
+
+func TestMainnetPresetGenesis(t *testing.T) {
+	// the mainnet preset matches the params of the mainnet genesis
+	appGenesis, err := genutiltypes.AppGenesisFromFile("../../networks/tacchain_239-1/genesis.json")
+	require.NoError(t, err)
+	var state map[string]any
+	require.NoError(t, json.Unmarshal(appGenesis.AppState, &state))
+
+	preset := mainnetPreset()
+	require.Equal(t, durationString(preset.VotingPeriod), genesisValue(t, state, "gov", "params", "voting_period"))
+	require.Equal(t, durationString(preset.ExpeditedVotingPeriod), genesisValue(t, state, "gov", "params", "expedited_voting_period"))
+	require.Equal(t, preset.MinGasPrice.TruncateInt().String(), genesisValue(t, state, "feemarket", "params", "min_gas_price"))
+	require.Equal(t, "15768000", genesisValue(t, state, "mint", "params", "blocks_per_year"))
+	require.Equal(t, preset.MaxGas, appGenesis.Consensus.Params.Block.MaxGas)
+	require.EqualValues(t, preset.MaxValidators, genesisValue(t, state, "staking", "params", "max_validators"))
+	require.Equal(t, "21600", genesisValue(t, state, "slashing", "params", "signed_blocks_window"))
+}
+
+func TestNetworkPresetNodeConfig(t *testing.T) {
+	cfg := cmtcfg.DefaultConfig()
+	mainnetPreset().nodePreset("tacchain_239-1")(cfg)
+	require.Contains(t, cfg.P2P.PersistentPeers, "d0a80c43a10a6b60475864728db6d9ba4ead42d2@107.6.113.60:58960")
+
+	// networks initialized with another chain id don't get the mainnet peers
+	cfg = cmtcfg.DefaultConfig()
+	mainnetPreset().nodePreset("tacchain_2395-1")(cfg)
+	require.Empty(t, cfg.P2P.PersistentPeers)
+
+	cfg = cmtcfg.DefaultConfig()
+	devnetPreset().nodePreset(app.DefaultChainID)(cfg)
+	require.Empty(t, cfg.P2P.PersistentPeers)
+	require.True(t, cfg.P2P.AllowDuplicateIP)
+	require.False(t, cfg.P2P.AddrBookStrict)
+}
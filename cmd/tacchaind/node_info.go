@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/server"
+	"github.com/cosmos/cosmos-sdk/version"
+
+	"github.com/Asphere-xyz/tacchain/app"
+)
+
+// NodeInfoExtendedCmd returns the chain metadata document of the connected
+// node: chain ids, bech32 prefixes, denoms, the endpoints enabled in the
+// local app.toml and the node and binary versions.
+func NodeInfoExtendedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node-info-extended",
+		Short: "Query the chain metadata used to configure explorers, wallets and tooling",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			node, err := clientCtx.GetNode()
+			if err != nil {
+				return err
+			}
+
+			status, err := node.Status(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			metadata, err := app.NewChainMetadata(status.NodeInfo.Network)
+			if err != nil {
+				return err
+			}
+
+			v := server.GetServerContextFromCmd(cmd).Viper
+			metadata.Endpoints = app.Endpoints{
+				RPC:  clientCtx.NodeURI,
+				GRPC: v.GetString("grpc.address"),
+			}
+			if v.GetBool("api.enable") {
+				metadata.Endpoints.API = v.GetString("api.address")
+			}
+			if v.GetBool("json-rpc.enable") {
+				metadata.Endpoints.JSONRPC = v.GetString("json-rpc.address")
+				metadata.Endpoints.JSONRPCWebSocket = v.GetString("json-rpc.ws-address")
+			}
+
+			abciInfo, err := node.ABCIInfo(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			info := version.NewInfo()
+			metadata.Node = app.NodeMetadata{
+				Moniker:       status.NodeInfo.Moniker,
+				AppVersion:    abciInfo.Response.Version,
+				CometBFT:      status.NodeInfo.Version,
+				LatestHeight:  status.SyncInfo.LatestBlockHeight,
+				CatchingUp:    status.SyncInfo.CatchingUp,
+				BinaryVersion: info.Version,
+				BinaryCommit:  info.GitCommit,
+			}
+
+			bz, err := json.MarshalIndent(metadata, "", "  ")
+			if err != nil {
+				return err
+			}
+			return clientCtx.PrintRaw(bz)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	cmtcfg "github.com/cometbft/cometbft/config"
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	"github.com/cometbft/cometbft/p2p"
+	"github.com/cometbft/cometbft/privval"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/cosmos/cosmos-sdk/client/input"
+	"github.com/cosmos/cosmos-sdk/server"
+)
+
+const (
+	flagOverwrite = "overwrite"
+
+	nodeKeyBackupVersion = 1
+
+	// scrypt parameters recommended for interactive logins
+	nodeKeyBackupScryptN = 1 << 15
+	nodeKeyBackupScryptR = 8
+	nodeKeyBackupScryptP = 1
+	nodeKeyBackupKeyLen  = 32
+)
+
+// nodeKeyBackup is the encrypted archive of the node and validator keys. The
+// ciphertext is a tar.gz of the key files sealed with AES-256-GCM, using a key
+// derived from the passphrase with scrypt.
+type nodeKeyBackup struct {
+	Version    int       `json:"version"`
+	CreatedAt  time.Time `json:"created_at"`
+	KDF        string    `json:"kdf"`
+	N          int       `json:"n"`
+	R          int       `json:"r"`
+	P          int       `json:"p"`
+	Salt       []byte    `json:"salt"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+}
+
+// nodeKeyFiles returns the key files of a node by archive name
+func nodeKeyFiles(config *cmtcfg.Config) map[string]string {
+	return map[string]string{
+		filepath.Base(config.NodeKeyFile()):          config.NodeKeyFile(),
+		filepath.Base(config.PrivValidatorKeyFile()): config.PrivValidatorKeyFile(),
+	}
+}
+
+// BackupNodeCmd writes an encrypted archive of node_key.json and priv_validator_key.json.
+func BackupNodeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "backup-node [file]",
+		Short: "Write an encrypted backup of the node key and the validator key",
+		Long: `Write an encrypted backup of node_key.json and priv_validator_key.json.
+
+The passphrase is read from stdin. The validator signing state isn't part of the backup,
+see restore-node for the checks made when the keys are restored.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := server.GetServerContextFromCmd(cmd).Config
+
+			buf := bufio.NewReader(cmd.InOrStdin())
+			passphrase, err := input.GetPassword("Enter a passphrase to encrypt the backup:", buf)
+			if err != nil {
+				return err
+			}
+			confirmation, err := input.GetPassword("Repeat the passphrase:", buf)
+			if err != nil {
+				return err
+			}
+			if passphrase != confirmation {
+				return errors.New("passphrases don't match")
+			}
+
+			backup, err := backupNodeKeys(config, passphrase)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(args[0], backup, 0o600); err != nil {
+				return err
+			}
+			cmd.Printf("node and validator keys of %s written to %s\n", config.RootDir, args[0])
+			return nil
+		},
+	}
+}
+
+// RestoreNodeCmd restores the keys of a backup-node archive.
+func RestoreNodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore-node [file]",
+		Short: "Restore the node key and the validator key from an encrypted backup",
+		Long: `Restore node_key.json and priv_validator_key.json from an encrypted backup.
+
+The passphrase is read from stdin. The keys are only restored into a home whose data
+directory was never used to sign, a validator key must never sign from two nodes. Stop and
+reset the node the keys were taken from first, then restore them before the new node starts.
+Existing keys are only replaced with --overwrite.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := server.GetServerContextFromCmd(cmd).Config
+
+			overwrite, err := cmd.Flags().GetBool(flagOverwrite)
+			if err != nil {
+				return err
+			}
+			backup, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			buf := bufio.NewReader(cmd.InOrStdin())
+			passphrase, err := input.GetPassword("Enter the passphrase of the backup:", buf)
+			if err != nil {
+				return err
+			}
+
+			if err := restoreNodeKeys(config, backup, passphrase, overwrite); err != nil {
+				return err
+			}
+			cmd.Printf("node and validator keys restored to %s\n", config.RootDir)
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(flagOverwrite, false, "Replace the existing keys of the node")
+
+	return cmd
+}
+
+// backupNodeKeys returns the encrypted archive of the keys of the node.
+func backupNodeKeys(config *cmtcfg.Config, passphrase string) ([]byte, error) {
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	for name, path := range nodeKeyFiles(config) {
+		bz, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(bz)), ModTime: time.Now()}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(bz); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	backup, err := encryptNodeKeyBackup(archive.Bytes(), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(backup, "", "  ")
+}
+
+// restoreNodeKeys decrypts an archive and writes its keys into the home of the
+// node, refusing to do so if the node already signed blocks.
+func restoreNodeKeys(config *cmtcfg.Config, bz []byte, passphrase string, overwrite bool) error {
+	var backup nodeKeyBackup
+	if err := json.Unmarshal(bz, &backup); err != nil {
+		return fmt.Errorf("invalid node key backup: %w", err)
+	}
+	archive, err := decryptNodeKeyBackup(backup, passphrase)
+	if err != nil {
+		return err
+	}
+	files, err := readNodeKeyArchive(archive)
+	if err != nil {
+		return err
+	}
+
+	targets := nodeKeyFiles(config)
+	for name := range targets {
+		if _, ok := files[name]; !ok {
+			return fmt.Errorf("backup doesn't contain %s", name)
+		}
+	}
+	if err := validateNodeKeys(files[filepath.Base(config.NodeKeyFile())], files[filepath.Base(config.PrivValidatorKeyFile())]); err != nil {
+		return err
+	}
+
+	if err := checkDataDirUnused(config); err != nil {
+		return err
+	}
+	if !overwrite {
+		for _, path := range targets {
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("%s already exists, use --%s to replace it", path, flagOverwrite)
+			}
+		}
+	}
+
+	for name, path := range targets {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, files[name], 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// checkDataDirUnused fails if the node has state in its data directory. A
+// validator key restored on a node that already signed, or that is running,
+// can sign twice at the same height.
+func checkDataDirUnused(config *cmtcfg.Config) error {
+	for _, db := range []string{"application.db", "blockstore.db", "state.db"} {
+		if _, err := os.Stat(filepath.Join(config.DBDir(), db)); err == nil {
+			return fmt.Errorf("refusing to restore keys onto the live data directory %s, it contains %s", config.DBDir(), db)
+		}
+	}
+
+	stateFile := config.PrivValidatorStateFile()
+	bz, err := os.ReadFile(stateFile)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var state privval.FilePVLastSignState
+	if err := cmtjson.Unmarshal(bz, &state); err != nil {
+		return fmt.Errorf("invalid %s: %w", stateFile, err)
+	}
+	if state.Height > 0 {
+		return fmt.Errorf("refusing to restore keys, %s shows the node signed at height %d", stateFile, state.Height)
+	}
+	return nil
+}
+
+// validateNodeKeys checks the restored files are keys CometBFT can load.
+func validateNodeKeys(nodeKey, pvKey []byte) error {
+	var nk p2p.NodeKey
+	if err := cmtjson.Unmarshal(nodeKey, &nk); err != nil {
+		return fmt.Errorf("backup contains an invalid node key: %w", err)
+	}
+	if nk.PrivKey == nil {
+		return errors.New("backup contains a node key without private key")
+	}
+
+	var pk privval.FilePVKey
+	if err := cmtjson.Unmarshal(pvKey, &pk); err != nil {
+		return fmt.Errorf("backup contains an invalid validator key: %w", err)
+	}
+	if pk.PrivKey == nil || pk.PubKey == nil {
+		return errors.New("backup contains a validator key without private or public key")
+	}
+	if !pk.PrivKey.PubKey().Equals(pk.PubKey) || !bytes.Equal(pk.PubKey.Address(), pk.Address) {
+		return errors.New("backup contains a validator key whose public key or address doesn't match its private key")
+	}
+	return nil
+}
+
+func readNodeKeyArchive(archive []byte) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		} else if err != nil {
+			return nil, err
+		}
+		// the keys are small, anything else isn't one of ours
+		bz, err := io.ReadAll(io.LimitReader(tr, 1<<16))
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Base(header.Name)] = bz
+	}
+}
+
+func encryptNodeKeyBackup(plaintext []byte, passphrase string) (nodeKeyBackup, error) {
+	backup := nodeKeyBackup{
+		Version:   nodeKeyBackupVersion,
+		CreatedAt: time.Now().UTC(),
+		KDF:       "scrypt",
+		N:         nodeKeyBackupScryptN,
+		R:         nodeKeyBackupScryptR,
+		P:         nodeKeyBackupScryptP,
+		Salt:      make([]byte, 32),
+	}
+	if _, err := rand.Read(backup.Salt); err != nil {
+		return backup, err
+	}
+
+	aead, err := nodeKeyBackupAEAD(backup, passphrase)
+	if err != nil {
+		return backup, err
+	}
+	backup.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(backup.Nonce); err != nil {
+		return backup, err
+	}
+	backup.Ciphertext = aead.Seal(nil, backup.Nonce, plaintext, nil)
+	return backup, nil
+}
+
+func decryptNodeKeyBackup(backup nodeKeyBackup, passphrase string) ([]byte, error) {
+	if backup.Version != nodeKeyBackupVersion || backup.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported node key backup version %d with kdf %s", backup.Version, backup.KDF)
+	}
+	aead, err := nodeKeyBackupAEAD(backup, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(backup.Nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid node key backup nonce")
+	}
+	plaintext, err := aead.Open(nil, backup.Nonce, backup.Ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt the backup, wrong passphrase or corrupted file")
+	}
+	return plaintext, nil
+}
+
+func nodeKeyBackupAEAD(backup nodeKeyBackup, passphrase string) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), backup.Salt, backup.N, backup.R, backup.P, nodeKeyBackupKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
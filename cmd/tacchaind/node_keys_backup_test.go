@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cmtcfg "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/p2p"
+	"github.com/cometbft/cometbft/privval"
+	"github.com/stretchr/testify/require"
+)
+
+const testPassphrase = "correct horse battery staple"
+
+// newTestNodeHome returns the config of a home with freshly generated keys
+func newTestNodeHome(t *testing.T, withKeys bool) *cmtcfg.Config {
+	t.Helper()
+
+	config := cmtcfg.DefaultConfig()
+	config.SetRoot(t.TempDir())
+	require.NoError(t, os.MkdirAll(filepath.Join(config.RootDir, "config"), 0o700))
+	require.NoError(t, os.MkdirAll(config.DBDir(), 0o700))
+
+	if withKeys {
+		_, err := p2p.LoadOrGenNodeKey(config.NodeKeyFile())
+		require.NoError(t, err)
+		privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile())
+	}
+	return config
+}
+
+func TestNodeKeysBackupRoundTrip(t *testing.T) {
+	source := newTestNodeHome(t, true)
+	backup, err := backupNodeKeys(source, testPassphrase)
+	require.NoError(t, err)
+
+	// the keys aren't stored in the clear
+	nodeKey, err := os.ReadFile(source.NodeKeyFile())
+	require.NoError(t, err)
+	pvKey, err := os.ReadFile(source.PrivValidatorKeyFile())
+	require.NoError(t, err)
+	nk, err := p2p.LoadNodeKey(source.NodeKeyFile())
+	require.NoError(t, err)
+	require.NotContains(t, string(backup), string(nk.ID()))
+	require.NotContains(t, string(backup), "priv_key")
+
+	target := newTestNodeHome(t, false)
+	require.NoError(t, restoreNodeKeys(target, backup, testPassphrase, false))
+
+	restoredNodeKey, err := os.ReadFile(target.NodeKeyFile())
+	require.NoError(t, err)
+	require.Equal(t, nodeKey, restoredNodeKey)
+	restoredPVKey, err := os.ReadFile(target.PrivValidatorKeyFile())
+	require.NoError(t, err)
+	require.Equal(t, pvKey, restoredPVKey)
+
+	info, err := os.Stat(target.PrivValidatorKeyFile())
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "Keys must only be readable by the owner")
+
+	// CometBFT loads the restored keys
+	_, err = p2p.LoadNodeKey(target.NodeKeyFile())
+	require.NoError(t, err)
+	pv := privval.LoadFilePVEmptyState(target.PrivValidatorKeyFile(), target.PrivValidatorStateFile())
+	sourcePV := privval.LoadFilePVEmptyState(source.PrivValidatorKeyFile(), source.PrivValidatorStateFile())
+	require.Equal(t, sourcePV.GetAddress(), pv.GetAddress())
+}
+
+func TestNodeKeysRestoreWrongPassphrase(t *testing.T) {
+	backup, err := backupNodeKeys(newTestNodeHome(t, true), testPassphrase)
+	require.NoError(t, err)
+
+	target := newTestNodeHome(t, false)
+	err = restoreNodeKeys(target, backup, "wrong passphrase", false)
+	require.ErrorContains(t, err, "wrong passphrase")
+
+	// tampering with the ciphertext is detected too
+	var b nodeKeyBackup
+	require.NoError(t, json.Unmarshal(backup, &b))
+	b.Ciphertext[0] ^= 0xff
+	tampered, err := json.Marshal(b)
+	require.NoError(t, err)
+	require.Error(t, restoreNodeKeys(target, tampered, testPassphrase, false))
+
+	_, err = os.Stat(target.PrivValidatorKeyFile())
+	require.True(t, os.IsNotExist(err), "Nothing should be written on failure")
+}
+
+func TestNodeKeysRestoreSafetyChecks(t *testing.T) {
+	backup, err := backupNodeKeys(newTestNodeHome(t, true), testPassphrase)
+	require.NoError(t, err)
+
+	// a freshly initialized node has keys, they are only replaced with --overwrite
+	target := newTestNodeHome(t, true)
+	require.ErrorContains(t, restoreNodeKeys(target, backup, testPassphrase, false), "already exists")
+	require.NoError(t, restoreNodeKeys(target, backup, testPassphrase, true))
+
+	// a node that signed must not get another validator key
+	signed := newTestNodeHome(t, true)
+	pv := privval.LoadFilePV(signed.PrivValidatorKeyFile(), signed.PrivValidatorStateFile())
+	pv.LastSignState.Height = 10
+	pv.LastSignState.Save()
+	require.ErrorContains(t, restoreNodeKeys(signed, backup, testPassphrase, true), "signed at height 10")
+
+	// nor a node with chain data, it may be running
+	live := newTestNodeHome(t, false)
+	require.NoError(t, os.MkdirAll(filepath.Join(live.DBDir(), "blockstore.db"), 0o700))
+	require.ErrorContains(t, restoreNodeKeys(live, backup, testPassphrase, true), "live data directory")
+	_, err = os.Stat(live.PrivValidatorKeyFile())
+	require.True(t, os.IsNotExist(err), "Nothing should be written on failure")
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Asphere-xyz/tacchain/app"
+)
+
+// OpenAPICmd writes the OpenAPI document of the REST gateway, the same
+// document a node serves at /swagger.json.
+func OpenAPICmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "openapi [file]",
+		Short: "Generate the OpenAPI (Swagger 2.0) document of the REST API",
+		Long: `Generate the OpenAPI (Swagger 2.0) document of the REST API.
+
+The document covers every route of the REST gateway, the ones of the Cosmos SDK, EVM and IBC
+modules, and is generated from the protobuf services compiled into this binary. The tac
+modules have no gRPC services and no REST routes, tacchaind q tac reads their state. Nodes
+with the swagger API enabled in app.toml serve the same document at /swagger.json.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			doc, err := app.NewOpenAPIDoc()
+			if err != nil {
+				return err
+			}
+
+			bz, err := json.MarshalIndent(doc, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(args[0], bz, 0o644); err != nil {
+				return err
+			}
+			cmd.Printf("wrote %d paths to %s\n", len(doc.Paths), args[0])
+			return nil
+		},
+	}
+}
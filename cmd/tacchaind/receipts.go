@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	cmtrpctypes "github.com/cometbft/cometbft/rpc/core/types"
+	rpcclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"cosmossdk.io/log"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/server"
+
+	evmrpc "github.com/cosmos/evm/rpc"
+	evmbackend "github.com/cosmos/evm/rpc/backend"
+	rpctypes "github.com/cosmos/evm/rpc/types"
+	evmtypes "github.com/cosmos/evm/types"
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+)
+
+// ReceiptsNamespace is the JSON-RPC namespace serving eth_getBlockReceipts,
+// eth_syncing and, with the compact receipt store, eth_getTransactionReceipt.
+// It must follow "eth" like LogIndexNamespace.
+const ReceiptsNamespace = "receipts"
+
+// nodeReceiptStore is the receipt store of the app the node runs, nil if disabled
+var nodeReceiptStore atomic.Pointer[app.ReceiptStore]
+
+func init() {
+	if err := evmrpc.RegisterAPINamespace(ReceiptsNamespace, newReceiptsAPIs); err != nil {
+		panic(err)
+	}
+}
+
+func newReceiptsAPIs(
+	ctx *server.Context,
+	clientCtx client.Context,
+	_ *rpcclient.WSClient,
+	allowUnprotectedTxs bool,
+	indexer evmtypes.EVMTxIndexer,
+) []rpc.API {
+	api := &receiptsAPI{
+		store:     nodeReceiptStore.Load(),
+		logger:    ctx.Logger.With("module", "receipts"),
+		clientCtx: clientCtx,
+		backend:   evmbackend.NewBackend(ctx, ctx.Logger, clientCtx, allowUnprotectedTxs, indexer),
+	}
+	if ctx.Config.StateSync != nil && ctx.Config.StateSync.Enable {
+		api.syncServers = ctx.Config.StateSync.RPCServers
+	}
+
+	apis := []rpc.API{{Namespace: "eth", Service: api}}
+	if api.store == nil {
+		ctx.Logger.Info("compact receipts are disabled, receipts are read from the block results")
+		return apis
+	}
+	return append(apis, rpc.API{Namespace: "eth", Service: &transactionReceiptAPI{api: api}})
+}
+
+// receiptsAPI serves the receipts of whole blocks and the sync status of the
+// node. The receipts are read from the receipt store if enabled: the logs it
+// no longer keeps are recreated by replaying the tx, the receipts of the
+// blocks it doesn't have are read from the block results.
+type receiptsAPI struct {
+	store     *app.ReceiptStore
+	logger    log.Logger
+	clientCtx client.Context
+	backend   *evmbackend.Backend
+	// syncServers are the RPC servers the node state syncs from
+	syncServers []string
+}
+
+// transactionReceiptAPI replaces eth_getTransactionReceipt when the receipt
+// store is enabled
+type transactionReceiptAPI struct {
+	api *receiptsAPI
+}
+
+// GetTransactionReceipt returns the receipt of the EVM tx hash
+func (t *transactionReceiptAPI) GetTransactionReceipt(hash common.Hash) (map[string]interface{}, error) {
+	stored, found, err := t.api.store.Receipt(hash)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return t.api.backend.GetTransactionReceipt(hash)
+	}
+	return t.api.receipt(stored)
+}
+
+// GetBlockReceipts returns the receipts of the EVM txs of a block, null if
+// the block doesn't exist
+func (api *receiptsAPI) GetBlockReceipts(blockNrOrHash rpctypes.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	number, err := api.backend.BlockNumberFromTendermint(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	resBlock, err := api.backend.TendermintBlockByNumber(number)
+	if err != nil || resBlock == nil || resBlock.Block == nil {
+		return nil, err
+	}
+
+	receipts := []map[string]interface{}{}
+	if api.store != nil {
+		stored, found, err := api.store.BlockReceipts(uint64(resBlock.Block.Height))
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			for _, r := range stored {
+				receipt, err := api.receiptInBlock(r, resBlock)
+				if err != nil {
+					return nil, err
+				}
+				receipts = append(receipts, receipt)
+			}
+			return receipts, nil
+		}
+	}
+
+	for i, txBz := range resBlock.Block.Txs {
+		tx, err := api.clientCtx.TxConfig.TxDecoder()(txBz)
+		if err != nil {
+			api.logger.Debug("failed to decode tx", "height", resBlock.Block.Height, "index", i, "error", err)
+			continue
+		}
+		for _, msg := range tx.GetMsgs() {
+			ethMsg, ok := msg.(*evmvmtypes.MsgEthereumTx)
+			if !ok {
+				break
+			}
+			receipt, err := api.backend.GetTransactionReceipt(ethMsg.AsTransaction().Hash())
+			if err != nil {
+				return nil, err
+			}
+			// the txs the ante handler rejected have no receipt
+			if receipt != nil {
+				receipts = append(receipts, receipt)
+			}
+		}
+	}
+	return receipts, nil
+}
+
+// Syncing returns false once the node caught up with the network, as
+// CometBFT reports it, and the sync progress until then. The highest block is
+// the latest height of the state sync servers, the node has no other source.
+func (api *receiptsAPI) Syncing(ctx context.Context) (interface{}, error) {
+	status, err := api.clientCtx.Client.Status(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !status.SyncInfo.CatchingUp {
+		return false, nil
+	}
+
+	current := status.SyncInfo.LatestBlockHeight
+	highest := current
+	for _, addr := range api.syncServers {
+		if height := serverHeight(ctx, addr); height > highest {
+			highest = height
+		}
+	}
+	return map[string]interface{}{
+		"startingBlock": hexutil.Uint64(status.SyncInfo.EarliestBlockHeight),
+		"currentBlock":  hexutil.Uint64(current),
+		"highestBlock":  hexutil.Uint64(highest),
+	}, nil
+}
+
+// serverHeight returns the latest height of the CometBFT RPC server, 0 if it
+// can't be reached
+func serverHeight(ctx context.Context, addr string) int64 {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	client, err := rpchttp.New(addr, "/websocket")
+	if err != nil {
+		return 0
+	}
+	status, err := client.Status(ctx)
+	if err != nil {
+		return 0
+	}
+	return status.SyncInfo.LatestBlockHeight
+}
+
+// receipt returns the receipt of stored in the format of eth_getTransactionReceipt
+func (api *receiptsAPI) receipt(stored app.StoredReceipt) (map[string]interface{}, error) {
+	resBlock, err := api.backend.TendermintBlockByNumber(rpctypes.BlockNumber(stored.Height))
+	if err != nil {
+		return nil, err
+	}
+	if resBlock == nil || resBlock.Block == nil {
+		return nil, fmt.Errorf("block %d not found", stored.Height)
+	}
+	return api.receiptInBlock(stored, resBlock)
+}
+
+// receiptInBlock returns the receipt of stored in resBlock, its block
+func (api *receiptsAPI) receiptInBlock(stored app.StoredReceipt, resBlock *cmtrpctypes.ResultBlock) (map[string]interface{}, error) {
+	if stored.TxIndex >= uint64(len(resBlock.Block.Txs)) {
+		return nil, fmt.Errorf("tx %d not found in block %d", stored.TxIndex, stored.Height)
+	}
+	tx, err := api.clientCtx.TxConfig.TxDecoder()(resBlock.Block.Txs[stored.TxIndex])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode tx %d of block %d: %w", stored.TxIndex, stored.Height, err)
+	}
+	msgs := tx.GetMsgs()
+	if stored.MsgIndex >= uint64(len(msgs)) {
+		return nil, fmt.Errorf("msg %d not found in tx %d of block %d", stored.MsgIndex, stored.TxIndex, stored.Height)
+	}
+	ethMsg, ok := msgs[stored.MsgIndex].(*evmvmtypes.MsgEthereumTx)
+	if !ok {
+		return nil, fmt.Errorf("msg %d of tx %d of block %d is not an EVM tx", stored.MsgIndex, stored.TxIndex, stored.Height)
+	}
+	ethTx := ethMsg.AsTransaction()
+	from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(ethTx.ChainId()), ethTx)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := api.logs(stored)
+	if err != nil {
+		return nil, err
+	}
+	blockHash := common.BytesToHash(resBlock.Block.Hash())
+	ethLogs := stored.EthLogs(logs, blockHash)
+
+	status := ethtypes.ReceiptStatusSuccessful
+	if stored.Failed {
+		status = ethtypes.ReceiptStatusFailed
+	}
+	receipt := map[string]interface{}{
+		"status":            hexutil.Uint(status),
+		"cumulativeGasUsed": hexutil.Uint64(stored.CumulativeGasUsed),
+		"logsBloom":         ethtypes.BytesToBloom(ethtypes.LogsBloom(ethLogs)),
+		"logs":              ethLogs,
+		"transactionHash":   stored.TxHash,
+		"contractAddress":   nil,
+		"gasUsed":           hexutil.Uint64(stored.GasUsed),
+		"blockHash":         blockHash.Hex(),
+		"blockNumber":       hexutil.Uint64(stored.Height),
+		"transactionIndex":  hexutil.Uint64(stored.EthTxIndex),
+		"from":              from,
+		"to":                ethTx.To(),
+		"type":              hexutil.Uint(ethTx.Type()),
+	}
+	if ethTx.To() == nil {
+		receipt["contractAddress"] = crypto.CreateAddress(from, ethTx.Nonce())
+	}
+	if ethTx.Type() == ethtypes.DynamicFeeTxType {
+		baseFee, err := api.store.BaseFee(stored.Height)
+		if err != nil {
+			return nil, err
+		}
+		if baseFee != nil {
+			price := new(big.Int).Add(ethTx.GasTipCap(), baseFee)
+			if price.Cmp(ethTx.GasFeeCap()) > 0 {
+				price = ethTx.GasFeeCap()
+			}
+			receipt["effectiveGasPrice"] = hexutil.Big(*price)
+		}
+	}
+	return receipt, nil
+}
+
+// logs returns the logs of stored, replaying the tx if the store no longer
+// keeps them
+func (api *receiptsAPI) logs(stored app.StoredReceipt) ([]app.CompactLog, error) {
+	logs, found, err := api.store.Logs(stored)
+	if err != nil || found {
+		return logs, err
+	}
+
+	res, err := api.backend.TraceTransaction(stored.TxHash, &evmvmtypes.TraceConfig{Tracer: app.ReceiptLogsTracer})
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay %s to recreate its logs: %w", stored.TxHash, err)
+	}
+	bz, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	if logs, err = app.DecodeReplayedLogs(bz); err != nil {
+		return nil, err
+	}
+	if uint64(len(logs)) != stored.NumLogs {
+		// the state the tx was replayed on isn't the one it was executed on
+		return nil, fmt.Errorf("replaying %s recreated %d logs instead of %d", stored.TxHash, len(logs), stored.NumLogs)
+	}
+	api.logger.Debug("recreated the logs of a receipt", "hash", stored.TxHash, "height", stored.Height)
+	return logs, nil
+}
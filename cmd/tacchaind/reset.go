@@ -0,0 +1,185 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	cmtcfg "github.com/cometbft/cometbft/config"
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	"github.com/cometbft/cometbft/privval"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/server"
+)
+
+const (
+	flagKeepKeys      = "keep-keys"
+	flagKeepAddrBook  = "keep-addrbook"
+	flagKeepSnapshots = "keep-snapshots"
+	flagDryRun        = "dry-run"
+)
+
+// resetOptions are what unsafe-reset-all keeps in the home of a node
+type resetOptions struct {
+	// KeepKeys keeps the node key, the validator key and the keyrings
+	KeepKeys bool
+	// KeepAddrBook keeps the peers the node learnt
+	KeepAddrBook bool
+	// KeepSnapshots keeps the state sync snapshots of the data dir
+	KeepSnapshots bool
+}
+
+// resetPlan is what unsafe-reset-all does to the home of a node
+type resetPlan struct {
+	// Remove are the files and directories removed
+	Remove []string
+	// SignState is the validator signing state reset to height 0, empty when
+	// it's removed along with the validator key
+	SignState string
+}
+
+// UnsafeResetAllCmd removes the chain data of a stopped node, so it restarts
+// from genesis, keeping its keys.
+func UnsafeResetAllCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unsafe-reset-all",
+		Short: "Remove the chain data of the node to restart it from genesis, keeping its keys",
+		Long: `Remove the chain data of the node to restart it from genesis, keeping its keys.
+
+Everything in the data dir is removed and the validator signing state is reset to height 0.
+The config files and the genesis are kept, as are node_key.json, priv_validator_key.json and
+the keyrings unless --keep-keys=false is given. The address book is only kept with
+--keep-addrbook and the state sync snapshots with --keep-snapshots. Use --dry-run to list
+what would be removed first. The node must be stopped.
+
+Resetting the signing state is only safe when the whole network restarts from a new genesis,
+the validator would double sign the heights of the old chain. Clear the double-sign-protection
+watermark of app.toml too, the node refuses to start behind it.`,
+		Example: "tacchaind unsafe-reset-all --keep-addrbook --dry-run",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			config := server.GetServerContextFromCmd(cmd).Config
+
+			var opts resetOptions
+			var err error
+			if opts.KeepKeys, err = cmd.Flags().GetBool(flagKeepKeys); err != nil {
+				return err
+			}
+			if opts.KeepAddrBook, err = cmd.Flags().GetBool(flagKeepAddrBook); err != nil {
+				return err
+			}
+			if opts.KeepSnapshots, err = cmd.Flags().GetBool(flagKeepSnapshots); err != nil {
+				return err
+			}
+			dryRun, err := cmd.Flags().GetBool(flagDryRun)
+			if err != nil {
+				return err
+			}
+
+			plan, err := planReset(config, opts)
+			if err != nil {
+				return err
+			}
+			if dryRun {
+				for _, path := range plan.Remove {
+					cmd.Printf("would remove %s\n", path)
+				}
+				if plan.SignState != "" {
+					cmd.Printf("would reset %s to height 0\n", plan.SignState)
+				}
+				return nil
+			}
+
+			if err := applyReset(plan); err != nil {
+				return err
+			}
+			for _, path := range plan.Remove {
+				cmd.Printf("removed %s\n", path)
+			}
+			if plan.SignState != "" {
+				cmd.Printf("reset %s to height 0\n", plan.SignState)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(flagKeepKeys, true, "Keep the node key, the validator key and the keyrings")
+	cmd.Flags().Bool(flagKeepAddrBook, false, "Keep the address book")
+	cmd.Flags().Bool(flagKeepSnapshots, false, "Keep the state sync snapshots")
+	cmd.Flags().Bool(flagDryRun, false, "List what would be removed without removing anything")
+
+	return cmd
+}
+
+// planReset returns what resetting the home of config removes and resets.
+func planReset(config *cmtcfg.Config, opts resetOptions) (resetPlan, error) {
+	var plan resetPlan
+	stateFile := filepath.Clean(config.PrivValidatorStateFile())
+
+	entries, err := os.ReadDir(config.DBDir())
+	if err != nil && !os.IsNotExist(err) {
+		return plan, err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(config.DBDir(), entry.Name())
+		if path == stateFile || (opts.KeepSnapshots && entry.Name() == "snapshots") {
+			continue
+		}
+		plan.Remove = append(plan.Remove, path)
+	}
+
+	if !opts.KeepAddrBook {
+		plan.Remove = appendExisting(plan.Remove, config.P2P.AddrBookFile())
+	}
+
+	if opts.KeepKeys {
+		// a kept validator key can't start without a signing state
+		if fileExists(stateFile) || fileExists(config.PrivValidatorKeyFile()) {
+			plan.SignState = stateFile
+		}
+		return plan, nil
+	}
+	plan.Remove = appendExisting(plan.Remove, config.NodeKeyFile(), config.PrivValidatorKeyFile(), stateFile)
+	keyrings, err := filepath.Glob(filepath.Join(config.RootDir, "keyring-*"))
+	if err != nil {
+		return plan, err
+	}
+	plan.Remove = append(plan.Remove, keyrings...)
+	return plan, nil
+}
+
+// applyReset removes and resets the files of plan.
+func applyReset(plan resetPlan) error {
+	for _, path := range plan.Remove {
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+	if plan.SignState == "" {
+		return nil
+	}
+
+	bz, err := cmtjson.MarshalIndent(privval.FilePVLastSignState{}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(plan.SignState), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(plan.SignState, bz, 0o600)
+}
+
+// appendExisting appends the paths which exist to paths.
+func appendExisting(paths []string, candidates ...string) []string {
+	for _, path := range candidates {
+		if fileExists(path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cmtcfg "github.com/cometbft/cometbft/config"
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	"github.com/cometbft/cometbft/privval"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/server"
+)
+
+// newTestUsedNodeHome returns the config of a home with keys whose node
+// synced and signed up to height 42
+func newTestUsedNodeHome(t *testing.T) *cmtcfg.Config {
+	t.Helper()
+
+	config := newTestNodeHome(t, true)
+	for _, db := range []string{"application.db", "blockstore.db", "state.db", "tx_index.db", filepath.Join("snapshots", "metadata.db")} {
+		require.NoError(t, os.MkdirAll(filepath.Join(config.DBDir(), db), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(config.DBDir(), db, "000001.log"), []byte("data"), 0o600))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(config.DBDir(), "cs.wal"), []byte("wal"), 0o600))
+	require.NoError(t, os.WriteFile(config.P2P.AddrBookFile(), []byte(`{"key":"","addrs":[]}`), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(config.RootDir, "keyring-test"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(config.RootDir, "keyring-test", "validator.info"), []byte("key"), 0o600))
+	require.NoError(t, os.WriteFile(config.GenesisFile(), []byte("{}"), 0o600))
+
+	bz, err := cmtjson.MarshalIndent(privval.FilePVLastSignState{Height: 42, Round: 0, Step: 3}, "", "  ")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(config.PrivValidatorStateFile(), bz, 0o600))
+	return config
+}
+
+// homeFiles returns the contents of the files under the home of config by path
+func homeFiles(t *testing.T, config *cmtcfg.Config) map[string]string {
+	t.Helper()
+
+	files := map[string]string{}
+	require.NoError(t, filepath.Walk(config.RootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		bz, err := os.ReadFile(path)
+		files[path] = string(bz)
+		return err
+	}))
+	return files
+}
+
+func TestUnsafeResetAllKeepsKeys(t *testing.T) {
+	config := newTestUsedNodeHome(t)
+	before := homeFiles(t, config)
+
+	plan, err := planReset(config, resetOptions{KeepKeys: true})
+	require.NoError(t, err)
+	dataDir := config.DBDir()
+	require.ElementsMatch(t, []string{
+		filepath.Join(dataDir, "application.db"),
+		filepath.Join(dataDir, "blockstore.db"),
+		filepath.Join(dataDir, "cs.wal"),
+		filepath.Join(dataDir, "snapshots"),
+		filepath.Join(dataDir, "state.db"),
+		filepath.Join(dataDir, "tx_index.db"),
+		config.P2P.AddrBookFile(),
+	}, plan.Remove)
+	require.Equal(t, config.PrivValidatorStateFile(), plan.SignState)
+
+	// the plan alone changes nothing
+	require.Equal(t, before, homeFiles(t, config))
+	require.Error(t, checkDataDirUnused(config))
+
+	require.NoError(t, applyReset(plan))
+	after := homeFiles(t, config)
+	for _, path := range []string{
+		config.NodeKeyFile(),
+		config.PrivValidatorKeyFile(),
+		filepath.Join(config.RootDir, "keyring-test", "validator.info"),
+		config.GenesisFile(),
+	} {
+		require.Equal(t, before[path], after[path], "%s must survive the reset", path)
+	}
+	require.Len(t, after, 5, "only the keys, the genesis and the signing state are left")
+	require.DirExists(t, dataDir)
+
+	// the validator starts signing from genesis with the same key
+	pv := privval.LoadFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile())
+	require.Equal(t, int64(0), pv.LastSignState.Height)
+	require.NoError(t, checkDataDirUnused(config))
+
+	// resetting a reset home changes nothing
+	plan, err = planReset(config, resetOptions{KeepKeys: true})
+	require.NoError(t, err)
+	require.Empty(t, plan.Remove)
+	require.NoError(t, applyReset(plan))
+	require.Equal(t, after, homeFiles(t, config))
+}
+
+func TestUnsafeResetAllOptions(t *testing.T) {
+	config := newTestUsedNodeHome(t)
+	dataDir := config.DBDir()
+
+	plan, err := planReset(config, resetOptions{KeepKeys: true, KeepAddrBook: true, KeepSnapshots: true})
+	require.NoError(t, err)
+	require.NotContains(t, plan.Remove, config.P2P.AddrBookFile())
+	require.NotContains(t, plan.Remove, filepath.Join(dataDir, "snapshots"))
+	require.NoError(t, applyReset(plan))
+	require.FileExists(t, config.P2P.AddrBookFile())
+	require.FileExists(t, filepath.Join(dataDir, "snapshots", "metadata.db", "000001.log"))
+	require.NoFileExists(t, filepath.Join(dataDir, "application.db", "000001.log"))
+
+	// without the keys only the config and the genesis are left
+	config = newTestUsedNodeHome(t)
+	plan, err = planReset(config, resetOptions{})
+	require.NoError(t, err)
+	require.Empty(t, plan.SignState)
+	require.Contains(t, plan.Remove, config.PrivValidatorStateFile())
+	require.Contains(t, plan.Remove, filepath.Join(config.RootDir, "keyring-test"))
+	require.NoError(t, applyReset(plan))
+	require.Equal(t, map[string]string{config.GenesisFile(): "{}"}, homeFiles(t, config))
+}
+
+func TestUnsafeResetAllCmdDryRun(t *testing.T) {
+	config := newTestUsedNodeHome(t)
+	before := homeFiles(t, config)
+
+	run := func(args ...string) string {
+		cmd := UnsafeResetAllCmd()
+		var out strings.Builder
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		serverCtx := server.NewDefaultContext()
+		serverCtx.Config = config
+		require.NoError(t, cmd.ExecuteContext(context.WithValue(context.Background(), server.ServerContextKey, serverCtx)))
+		return out.String()
+	}
+
+	output := run("--dry-run", "--keep-addrbook")
+	require.Contains(t, output, "would remove "+filepath.Join(config.DBDir(), "application.db"))
+	require.Contains(t, output, "would reset "+config.PrivValidatorStateFile()+" to height 0")
+	require.NotContains(t, output, config.P2P.AddrBookFile())
+	require.NotContains(t, output, config.PrivValidatorKeyFile())
+	require.Equal(t, before, homeFiles(t, config))
+
+	output = run()
+	require.Contains(t, output, "removed "+config.P2P.AddrBookFile())
+	require.NoError(t, checkDataDirUnused(config))
+}
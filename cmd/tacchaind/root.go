@@ -23,6 +23,7 @@ import (
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 
 	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/app/upgrades"
 
 	evmkeyring "github.com/cosmos/evm/crypto/keyring"
 	evmserverconfig "github.com/cosmos/evm/server/config"
@@ -111,6 +112,9 @@ func NewRootCmd() *cobra.Command {
 			if err := client.SetCmdClientContextHandler(initClientCtx, cmd); err != nil {
 				return err
 			}
+			if err := setBroadcastRetry(cmd); err != nil {
+				return err
+			}
 
 			customAppTemplate, customAppConfig := initAppConfig()
 
@@ -137,6 +141,8 @@ func NewRootCmd() *cobra.Command {
 	if err := autoCliOpts.EnhanceRootCommand(rootCmd); err != nil {
 		panic(err)
 	}
+	addBroadcastRetryMode(rootCmd)
+	addEVMTxCmds(rootCmd)
 
 	return rootCmd
 }
@@ -168,6 +174,19 @@ func initAppConfig() (string, interface{}) {
 		EVM     evmserverconfig.EVMConfig
 		JSONRPC evmserverconfig.JSONRPCConfig
 		TLS     evmserverconfig.TLSConfig
+
+		Backup               upgrades.BackupConfig          `mapstructure:"backup"`
+		TxLimits             app.TxLimitsConfig             `mapstructure:"tx-limits"`
+		DoubleSignProtection app.DoubleSignProtectionConfig `mapstructure:"double-sign-protection"`
+		QueryLimits          app.QueryLimitsConfig          `mapstructure:"query-limits"`
+		Compaction           app.CompactionConfig           `mapstructure:"compaction"`
+		PriorityLanes        app.PriorityLanesConfig        `mapstructure:"priority-lanes"`
+		GasProfile           app.GasProfileConfig           `mapstructure:"gas-profile"`
+		LogIndex             app.LogIndexConfig             `mapstructure:"log-index"`
+		Receipts             app.ReceiptsConfig             `mapstructure:"receipts"`
+		Alerting             app.AlertingConfig             `mapstructure:"alerting"`
+		Shutdown             app.ShutdownConfig             `mapstructure:"shutdown"`
+		BlockResults         app.BlockResultsConfig         `mapstructure:"block-results"`
 	}
 
 	// Optionally allow the chain developer to overwrite the SDK's default
@@ -189,15 +208,44 @@ func initAppConfig() (string, interface{}) {
 
 	// srvCfg.BaseConfig.IAVLDisableFastNode = true // disable fastnode by default
 
+	// eth_sendRawTransaction rejects the tx types the chain doesn't support
+	jsonRPCConfig := evmserverconfig.DefaultJSONRPCConfig()
+	jsonRPCConfig.API = append(jsonRPCConfig.API, TxTypesNamespace)
+
 	customAppConfig := CustomAppConfig{
-		Config:  *srvCfg,
-		EVM:     *evmserverconfig.DefaultEVMConfig(),
-		JSONRPC: *evmserverconfig.DefaultJSONRPCConfig(),
-		TLS:     *evmserverconfig.DefaultTLSConfig(),
+		Config:   *srvCfg,
+		EVM:      *evmserverconfig.DefaultEVMConfig(),
+		JSONRPC:  *jsonRPCConfig,
+		TLS:      *evmserverconfig.DefaultTLSConfig(),
+		Backup:   upgrades.DefaultBackupConfig(),
+		TxLimits: app.DefaultTxLimitsConfig(),
+
+		DoubleSignProtection: app.DefaultDoubleSignProtectionConfig(),
+		QueryLimits:          app.DefaultQueryLimitsConfig(),
+		Compaction:           app.DefaultCompactionConfig(),
+		PriorityLanes:        app.DefaultPriorityLanesConfig(),
+		GasProfile:           app.DefaultGasProfileConfig(),
+		LogIndex:             app.DefaultLogIndexConfig(),
+		Receipts:             app.DefaultReceiptsConfig(),
+		Alerting:             app.DefaultAlertingConfig(),
+		Shutdown:             app.DefaultShutdownConfig(),
+		BlockResults:         app.DefaultBlockResultsConfig(),
 	}
 
 	customAppTemplate := serverconfig.DefaultConfigTemplate +
-		evmserverconfig.DefaultEVMConfigTemplate
+		evmserverconfig.DefaultEVMConfigTemplate +
+		upgrades.DefaultBackupConfigTemplate +
+		app.DefaultTxLimitsConfigTemplate +
+		app.DefaultDoubleSignProtectionConfigTemplate +
+		app.DefaultQueryLimitsConfigTemplate +
+		app.DefaultCompactionConfigTemplate +
+		app.DefaultPriorityLanesConfigTemplate +
+		app.DefaultGasProfileConfigTemplate +
+		app.DefaultLogIndexConfigTemplate +
+		app.DefaultReceiptsConfigTemplate +
+		app.DefaultAlertingConfigTemplate +
+		app.DefaultShutdownConfigTemplate +
+		app.DefaultBlockResultsConfigTemplate
 
 	return customAppTemplate, customAppConfig
 }
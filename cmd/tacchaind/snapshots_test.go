@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/store/snapshots"
+	snapshottypes "cosmossdk.io/store/snapshots/types"
+)
+
+func saveTestSnapshot(t *testing.T, store *snapshots.Store, height uint64, chunks ...[]byte) *snapshottypes.Snapshot {
+	ch := make(chan io.ReadCloser, len(chunks))
+	for _, chunk := range chunks {
+		ch <- io.NopCloser(bytes.NewReader(chunk))
+	}
+	close(ch)
+
+	snapshot, err := store.Save(height, snapshottypes.CurrentFormat, ch)
+	require.NoError(t, err)
+	return snapshot
+}
+
+func TestVerifySnapshot(t *testing.T) {
+	dir := t.TempDir()
+	store, err := snapshots.NewStore(dbm.NewMemDB(), dir)
+	require.NoError(t, err)
+	// chunks are stored at <height>/<format>/<index>
+	chunkPath := func(height uint64, chunk int) string {
+		return filepath.Join(dir, strconv.FormatUint(height, 10), strconv.Itoa(int(snapshottypes.CurrentFormat)), strconv.Itoa(chunk))
+	}
+
+	snapshot := saveTestSnapshot(t, store, 10, []byte("chunk 0"), []byte("chunk 1"), []byte("chunk 2"))
+	require.NoError(t, verifySnapshot(store, snapshot))
+
+	// the hashes of the metadata are the ones of the store
+	loaded, err := store.Get(10, snapshottypes.CurrentFormat)
+	require.NoError(t, err)
+	require.NoError(t, verifySnapshot(store, loaded))
+
+	// corrupted chunk
+	require.NoError(t, os.WriteFile(chunkPath(10, 1), []byte("chunk x"), 0o644))
+	require.ErrorContains(t, verifySnapshot(store, snapshot), "hash mismatch of chunk 1")
+
+	// missing chunk
+	require.NoError(t, os.Remove(chunkPath(10, 1)))
+	require.ErrorContains(t, verifySnapshot(store, snapshot), "chunk 1 is missing")
+
+	// inconsistent metadata
+	snapshot = saveTestSnapshot(t, store, 20, []byte("chunk 0"))
+	require.NoError(t, verifySnapshot(store, snapshot))
+
+	tampered := *snapshot
+	tampered.Hash = bytes.Repeat([]byte{1}, 32)
+	require.ErrorContains(t, verifySnapshot(store, &tampered), "hash mismatch of snapshot")
+
+	tampered = *snapshot
+	tampered.Chunks = 2
+	require.ErrorContains(t, verifySnapshot(store, &tampered), "1 chunk hashes")
+
+	tampered = *snapshot
+	tampered.Format = snapshottypes.CurrentFormat + 1
+	require.ErrorContains(t, verifySnapshot(store, &tampered), "unknown format")
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"container/heap"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/spf13/cobra"
+
+	"cosmossdk.io/log"
+	"cosmossdk.io/store/metrics"
+	"cosmossdk.io/store/rootmulti"
+	storetypes "cosmossdk.io/store/types"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/server"
+)
+
+const (
+	flagTop = "top"
+
+	// DefaultStateReportTop is the default number of largest keys in a state report
+	DefaultStateReportTop = 20
+)
+
+// StateReport is the size of the application state at a height, per module
+// store and key prefix. Sizes are the bytes of keys and values, without the
+// overhead of the IAVL trees and of the database.
+type StateReport struct {
+	Height      int64               `json:"height"`
+	Keys        int64               `json:"keys"`
+	Bytes       int64               `json:"bytes"`
+	Modules     []ModuleStateReport `json:"modules"`
+	LargestKeys []KeyStateReport    `json:"largest_keys"`
+}
+
+// ModuleStateReport is the size of the store of a module, largest prefixes first.
+type ModuleStateReport struct {
+	Module   string              `json:"module"`
+	Keys     int64               `json:"keys"`
+	Bytes    int64               `json:"bytes"`
+	Prefixes []PrefixStateReport `json:"prefixes"`
+}
+
+// PrefixStateReport is the size of the keys of a store starting with the
+// same byte, most modules use it to tell their collections apart.
+type PrefixStateReport struct {
+	Prefix string `json:"prefix"`
+	Keys   int64  `json:"keys"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// KeyStateReport is the size of a key and its value.
+type KeyStateReport struct {
+	Module string `json:"module"`
+	Key    string `json:"key"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// StateReportCmd reports the size of the application state of a stopped node.
+func StateReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state-report",
+		Short: "Report the number of keys and bytes of the application state per module and key prefix",
+		Long: `Report the number of keys and bytes of the application state per module and key prefix.
+
+The stores of all modules are walked at the latest height, or at --height if it
+isn't pruned, along with the largest keys. The node must be stopped.`,
+		Example: "tacchaind tools state-report --top 50 --output json",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := server.GetServerContextFromCmd(cmd)
+			height, _ := cmd.Flags().GetInt64(flags.FlagHeight)
+			top, _ := cmd.Flags().GetInt(flagTop)
+			output, _ := cmd.Flags().GetString(flags.FlagOutput)
+
+			db, err := dbm.NewDB("application", server.GetAppDBBackend(ctx.Viper), filepath.Join(ctx.Config.RootDir, "data"))
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			stores, height, err := loadStateStores(db, height)
+			if err != nil {
+				return err
+			}
+			report, err := newStateReport(stores, top)
+			if err != nil {
+				return err
+			}
+			report.Height = height
+
+			if output == flags.OutputFormatJSON {
+				bz, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				cmd.Println(string(bz))
+				return nil
+			}
+			printStateReport(cmd, report)
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64(flags.FlagHeight, 0, "Height of the state, defaults to the latest height")
+	cmd.Flags().Int(flagTop, DefaultStateReportTop, "Number of largest keys to report")
+	cmd.Flags().StringP(flags.FlagOutput, "o", flags.OutputFormatText, "Output format (text|json)")
+
+	return cmd
+}
+
+// loadStateStores loads the module stores of the application state at height,
+// the latest one if height is 0, and returns them with the loaded height.
+func loadStateStores(db dbm.DB, height int64) (map[string]storetypes.KVStore, int64, error) {
+	if height == 0 {
+		height = rootmulti.GetLatestVersion(db)
+	}
+	if height == 0 {
+		return nil, 0, fmt.Errorf("the application state is empty")
+	}
+
+	rs := rootmulti.NewStore(db, log.NewNopLogger(), metrics.NewNoOpMetrics())
+	commitInfo, err := rs.GetCommitInfo(height)
+	if err != nil {
+		return nil, 0, fmt.Errorf("no state at height %d: %w", height, err)
+	}
+	keys := make(map[string]*storetypes.KVStoreKey, len(commitInfo.StoreInfos))
+	for _, info := range commitInfo.StoreInfos {
+		keys[info.Name] = storetypes.NewKVStoreKey(info.Name)
+		rs.MountStoreWithDB(keys[info.Name], storetypes.StoreTypeIAVL, nil)
+	}
+	if err := rs.LoadVersion(height); err != nil {
+		return nil, 0, fmt.Errorf("failed to load the state at height %d: %w", height, err)
+	}
+
+	stores := make(map[string]storetypes.KVStore, len(keys))
+	for name, key := range keys {
+		stores[name] = rs.GetKVStore(key)
+	}
+	return stores, height, nil
+}
+
+// newStateReport walks stores and reports their size and the top largest keys.
+func newStateReport(stores map[string]storetypes.KVStore, top int) (StateReport, error) {
+	report := StateReport{Modules: []ModuleStateReport{}, LargestKeys: []KeyStateReport{}}
+	largest := &largestKeys{}
+
+	for module, store := range stores {
+		prefixes := make(map[byte]*PrefixStateReport)
+		moduleReport := ModuleStateReport{Module: module, Prefixes: []PrefixStateReport{}}
+
+		it := store.Iterator(nil, nil)
+		for ; it.Valid(); it.Next() {
+			key := it.Key()
+			size := int64(len(key) + len(it.Value()))
+			moduleReport.Keys++
+			moduleReport.Bytes += size
+
+			if len(key) > 0 {
+				prefix, ok := prefixes[key[0]]
+				if !ok {
+					prefix = &PrefixStateReport{Prefix: hex.EncodeToString(key[:1])}
+					prefixes[key[0]] = prefix
+				}
+				prefix.Keys++
+				prefix.Bytes += size
+			}
+
+			if top > 0 && (largest.Len() < top || size > (*largest)[0].Bytes) {
+				heap.Push(largest, KeyStateReport{Module: module, Key: hex.EncodeToString(key), Bytes: size})
+				if largest.Len() > top {
+					heap.Pop(largest)
+				}
+			}
+		}
+		err := it.Error()
+		it.Close()
+		if err != nil {
+			return report, fmt.Errorf("failed to walk the store of %s: %w", module, err)
+		}
+
+		for _, prefix := range prefixes {
+			moduleReport.Prefixes = append(moduleReport.Prefixes, *prefix)
+		}
+		sort.Slice(moduleReport.Prefixes, func(i, j int) bool {
+			return moduleReport.Prefixes[i].Bytes > moduleReport.Prefixes[j].Bytes
+		})
+
+		report.Modules = append(report.Modules, moduleReport)
+		report.Keys += moduleReport.Keys
+		report.Bytes += moduleReport.Bytes
+	}
+
+	sort.Slice(report.Modules, func(i, j int) bool {
+		if report.Modules[i].Bytes != report.Modules[j].Bytes {
+			return report.Modules[i].Bytes > report.Modules[j].Bytes
+		}
+		return report.Modules[i].Module < report.Modules[j].Module
+	})
+	for largest.Len() > 0 {
+		report.LargestKeys = append(report.LargestKeys, heap.Pop(largest).(KeyStateReport))
+	}
+	// the heap pops the smallest key first
+	for i, j := 0, len(report.LargestKeys)-1; i < j; i, j = i+1, j-1 {
+		report.LargestKeys[i], report.LargestKeys[j] = report.LargestKeys[j], report.LargestKeys[i]
+	}
+	return report, nil
+}
+
+func printStateReport(cmd *cobra.Command, report StateReport) {
+	cmd.Printf("height: %d keys: %d bytes: %d\n", report.Height, report.Keys, report.Bytes)
+	for _, module := range report.Modules {
+		cmd.Printf("\n%s keys: %d bytes: %d\n", module.Module, module.Keys, module.Bytes)
+		for _, prefix := range module.Prefixes {
+			cmd.Printf("  0x%s keys: %d bytes: %d\n", prefix.Prefix, prefix.Keys, prefix.Bytes)
+		}
+	}
+	if len(report.LargestKeys) > 0 {
+		cmd.Println("\nlargest keys:")
+	}
+	for _, key := range report.LargestKeys {
+		cmd.Printf("  %s 0x%s bytes: %d\n", key.Module, key.Key, key.Bytes)
+	}
+}
+
+// largestKeys is a min-heap of keys by size
+type largestKeys []KeyStateReport
+
+func (h largestKeys) Len() int           { return len(h) }
+func (h largestKeys) Less(i, j int) bool { return h[i].Bytes < h[j].Bytes }
+func (h largestKeys) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *largestKeys) Push(x any)        { *h = append(*h, x.(KeyStateReport)) }
+func (h *largestKeys) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	"cosmossdk.io/store/metrics"
+	"cosmossdk.io/store/rootmulti"
+	storetypes "cosmossdk.io/store/types"
+)
+
+func TestStateReport(t *testing.T) {
+	db := dbm.NewMemDB()
+	rs := rootmulti.NewStore(db, log.NewNopLogger(), metrics.NewNoOpMetrics())
+	bankKey, evmKey := storetypes.NewKVStoreKey("bank"), storetypes.NewKVStoreKey("evm")
+	rs.MountStoreWithDB(bankKey, storetypes.StoreTypeIAVL, nil)
+	rs.MountStoreWithDB(evmKey, storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, rs.LoadLatestVersion())
+
+	bank := rs.GetKVStore(bankKey)
+	bank.Set([]byte{0x02, 1}, []byte("100"))
+	bank.Set([]byte{0x02, 2}, []byte("200"))
+	bank.Set([]byte{0x01, 1}, []byte("supply"))
+	evm := rs.GetKVStore(evmKey)
+	evm.Set([]byte{0x01, 1}, make([]byte, 1000))
+	evm.Set([]byte{0x02, 1}, make([]byte, 32))
+	rs.Commit()
+
+	// later versions don't change the state at height 1
+	bank.Set([]byte{0x02, 3}, []byte("300"))
+	rs.Commit()
+
+	stores, height, err := loadStateStores(db, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, height)
+
+	report, err := newStateReport(stores, 2)
+	require.NoError(t, err)
+	require.Equal(t, StateReport{
+		Keys:  5,
+		Bytes: 2 + 3 + 2 + 3 + 2 + 6 + 2 + 1000 + 2 + 32,
+		Modules: []ModuleStateReport{
+			{Module: "evm", Keys: 2, Bytes: 1036, Prefixes: []PrefixStateReport{
+				{Prefix: "01", Keys: 1, Bytes: 1002},
+				{Prefix: "02", Keys: 1, Bytes: 34},
+			}},
+			{Module: "bank", Keys: 3, Bytes: 18, Prefixes: []PrefixStateReport{
+				{Prefix: "02", Keys: 2, Bytes: 10},
+				{Prefix: "01", Keys: 1, Bytes: 8},
+			}},
+		},
+		LargestKeys: []KeyStateReport{
+			{Module: "evm", Key: "0101", Bytes: 1002},
+			{Module: "evm", Key: "0201", Bytes: 34},
+		},
+	}, report)
+
+	stores, height, err = loadStateStores(db, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, height)
+	report, err = newStateReport(stores, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 6, report.Keys)
+	require.Empty(t, report.LargestKeys)
+
+	_, _, err = loadStateStores(db, 3)
+	require.Error(t, err)
+	_, _, err = loadStateStores(dbm.NewMemDB(), 0)
+	require.Error(t, err)
+}
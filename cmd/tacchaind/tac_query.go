@@ -0,0 +1,2610 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+
+	"cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	paramproposal "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	evmerc20types "github.com/cosmos/evm/x/erc20/types"
+	evmfeemarkettypes "github.com/cosmos/evm/x/feemarket/types"
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+	ibctransfertypes "github.com/cosmos/ibc-go/v8/modules/apps/transfer/types"
+	ibcclienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcchanneltypes "github.com/cosmos/ibc-go/v8/modules/core/04-channel/types"
+	ibctm "github.com/cosmos/ibc-go/v8/modules/light-clients/07-tendermint"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+	accountactivitytypes "github.com/Asphere-xyz/tacchain/x/accountactivity/types"
+	autocompoundtypes "github.com/Asphere-xyz/tacchain/x/autocompound/types"
+	bridgetypes "github.com/Asphere-xyz/tacchain/x/bridge/types"
+	contractgastypes "github.com/Asphere-xyz/tacchain/x/contractgas/types"
+	contractmetatypes "github.com/Asphere-xyz/tacchain/x/contractmeta/types"
+	emissiontypes "github.com/Asphere-xyz/tacchain/x/emission/types"
+	evmupgradetypes "github.com/Asphere-xyz/tacchain/x/evmupgrade/types"
+	feeburntypes "github.com/Asphere-xyz/tacchain/x/feeburn/types"
+	feehistorytypes "github.com/Asphere-xyz/tacchain/x/feehistory/types"
+	feeroutingtypes "github.com/Asphere-xyz/tacchain/x/feerouting/types"
+	ibchookstypes "github.com/Asphere-xyz/tacchain/x/ibchooks/types"
+	nameservicetypes "github.com/Asphere-xyz/tacchain/x/nameservice/types"
+	performancetypes "github.com/Asphere-xyz/tacchain/x/performance/types"
+	recoverytypes "github.com/Asphere-xyz/tacchain/x/recovery/types"
+	rewardsnapshottypes "github.com/Asphere-xyz/tacchain/x/rewardsnapshot/types"
+	selfbondtypes "github.com/Asphere-xyz/tacchain/x/selfbond/types"
+	tacgovtypes "github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+// TacQueryCmd groups the queries of chain specific data, which combine the
+// queries of several modules or read the tac modules. These don't map to a
+// single gRPC method, so they are written by hand rather than with autocli.
+func TacQueryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        "tac",
+		Short:                      "Querying commands for TAC chain specific data",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	cmd.AddCommand(
+		tacAPRCmd(),
+		tacEmissionCmd(),
+		tacAllParamsCmd(),
+		tacAddressMappingCmd(),
+		tacFeeReportCmd(),
+		tacBridgeStatusCmd(),
+		tacGasUtilizationCmd(),
+		tacValidatorPerformanceCmd(),
+		tacAutoCompoundCmd(),
+		tacContractMetadataCmd(),
+		tacVoteDelegationCmd(),
+		tacBridgeRelayersCmd(),
+		tacBridgeWithdrawalsCmd(),
+		tacBridgeAssetsCmd(),
+		tacBridgeFeeQuoteCmd(),
+		tacMempoolCmd(),
+		tacRewardSnapshotsCmd(),
+		tacAccountActivityCmd(),
+		tacFeeHistoryCmd(),
+		tacProposerTipsCmd(),
+		tacEstimateFeeCmd(),
+		tacProtocolRevenueCmd(),
+		tacNameCmd(),
+		tacTxProofCmd(),
+	)
+
+	return cmd
+}
+
+// StakingAPR is the output of the tac apr query
+type StakingAPR struct {
+	BondDenom        string         `json:"bond_denom"`
+	Inflation        math.LegacyDec `json:"inflation"`
+	AnnualProvisions math.LegacyDec `json:"annual_provisions"`
+	BondedTokens     math.Int       `json:"bonded_tokens"`
+	CommunityTax     math.LegacyDec `json:"community_tax"`
+	APR              math.LegacyDec `json:"apr"`
+}
+
+func tacAPRCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apr",
+		Short: "Query the staking APR implied by the current emission, bonded tokens and community tax",
+		Long: `Query the staking APR implied by the current emission, bonded tokens and community tax.
+
+The annual provisions are the ones of the emission schedule at the latest height, the
+inflation is their ratio to the supply of the bond denom. The APR is the rate before
+validator commission and doesn't include fees, so delegators earn the APR less the
+commission of their validator.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+
+			emission, err := queryEmission(ctx, clientCtx)
+			if err != nil {
+				return err
+			}
+
+			stakingClient := stakingtypes.NewQueryClient(clientCtx)
+			pool, err := stakingClient.Pool(ctx, &stakingtypes.QueryPoolRequest{})
+			if err != nil {
+				return err
+			}
+			stakingParams, err := stakingClient.Params(ctx, &stakingtypes.QueryParamsRequest{})
+			if err != nil {
+				return err
+			}
+
+			supply, err := banktypes.NewQueryClient(clientCtx).SupplyOf(ctx, &banktypes.QuerySupplyOfRequest{Denom: stakingParams.Params.BondDenom})
+			if err != nil {
+				return err
+			}
+			distrParams, err := distrtypes.NewQueryClient(clientCtx).Params(ctx, &distrtypes.QueryParamsRequest{})
+			if err != nil {
+				return err
+			}
+
+			inflation := math.LegacyZeroDec()
+			if supply.Amount.Amount.IsPositive() {
+				inflation = emission.AnnualProvisions.QuoInt(supply.Amount.Amount)
+			}
+			return printJSON(clientCtx, StakingAPR{
+				BondDenom:        stakingParams.Params.BondDenom,
+				Inflation:        inflation,
+				AnnualProvisions: emission.AnnualProvisions,
+				BondedTokens:     pool.Pool.BondedTokens,
+				CommunityTax:     distrParams.Params.CommunityTax,
+				APR:              app.StakingAPR(emission.AnnualProvisions, pool.Pool.BondedTokens, distrParams.Params.CommunityTax),
+			})
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// queryUtilization returns the gas utilization of the latest block the fee
+// burn is based on, its gas wanted tracked by the fee market over the block
+// gas limit.
+func queryUtilization(ctx context.Context, clientCtx client.Context) (math.LegacyDec, error) {
+	node, err := clientCtx.GetNode()
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+	status, err := node.Status(ctx)
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+	height := status.SyncInfo.LatestBlockHeight
+	consensusParams, err := node.ConsensusParams(ctx, &height)
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+
+	blockGas, err := evmfeemarkettypes.NewQueryClient(clientCtx.WithHeight(height)).BlockGas(ctx, &evmfeemarkettypes.QueryBlockGasRequest{})
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+	if blockGas.Gas < 0 {
+		return math.LegacyZeroDec(), nil
+	}
+	return feeburntypes.Utilization(uint64(blockGas.Gas), consensusParams.ConsensusParams.Block.MaxGas), nil
+}
+
+// EmissionStatus is the output of the tac emission query, RemainingMintable
+// is left out if the supply isn't capped
+type EmissionStatus struct {
+	Height              int64                `json:"height"`
+	AnnualProvisions    math.LegacyDec       `json:"annual_provisions"`
+	BlockProvision      math.Int             `json:"block_provision"`
+	Reductions          uint64               `json:"reductions"`
+	NextReductionHeight int64                `json:"next_reduction_height"`
+	Supply              math.Int             `json:"supply"`
+	RemainingMintable   *math.Int            `json:"remaining_mintable,omitempty"`
+	Params              emissiontypes.Params `json:"params"`
+}
+
+func tacEmissionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "emission",
+		Short: "Query the provisions of the emission schedule at the latest height",
+		Long: `Query the provisions of the emission schedule at the latest height.
+
+The emission module mints fixed annual provisions, reduced by the reduction factor every
+reduction interval. The annual provisions, the amount minted per block and the height of
+the next reduction are reported along with the params of the schedule. A next reduction
+height of 0 means the provisions are never reduced.
+
+With a max supply set, the amount that can still be minted before the supply of the mint
+denom reaches it is reported as remaining_mintable. Minting stops at the cap.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			emission, err := queryEmission(cmd.Context(), clientCtx)
+			if err != nil {
+				return err
+			}
+			return printJSON(clientCtx, emission)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// queryEmission evaluates the emission schedule at the latest height
+func queryEmission(ctx context.Context, clientCtx client.Context) (EmissionStatus, error) {
+	node, err := clientCtx.GetNode()
+	if err != nil {
+		return EmissionStatus{}, err
+	}
+	status, err := node.Status(ctx)
+	if err != nil {
+		return EmissionStatus{}, err
+	}
+	height := status.SyncInfo.LatestBlockHeight
+
+	clientCtx = clientCtx.WithHeight(height)
+	var params emissiontypes.Params
+	if _, err := legacyParamsQuery(emissiontypes.ModuleName, &params)(ctx, clientCtx); err != nil {
+		return EmissionStatus{}, err
+	}
+	supply, err := banktypes.NewQueryClient(clientCtx).SupplyOf(ctx, &banktypes.QuerySupplyOfRequest{Denom: params.MintDenom})
+	if err != nil {
+		return EmissionStatus{}, err
+	}
+
+	emission := EmissionStatus{
+		Height:              height,
+		AnnualProvisions:    params.AnnualProvisionsAt(height),
+		BlockProvision:      params.BlockProvisionAt(height),
+		Reductions:          params.Reductions(height),
+		NextReductionHeight: params.NextReductionHeight(height),
+		Supply:              supply.Amount.Amount,
+		Params:              params,
+	}
+	if params.IsCapped() {
+		remaining := params.RemainingMintable(supply.Amount.Amount)
+		emission.RemainingMintable = &remaining
+		emission.BlockProvision = math.MinInt(emission.BlockProvision, remaining)
+	}
+	return emission, nil
+}
+
+// moduleParamsQuery returns the JSON encoded params of a module
+type moduleParamsQuery func(ctx context.Context, clientCtx client.Context) ([]byte, error)
+
+// paramsQuery adapts the Params query of a module to a moduleParamsQuery, the
+// params are read from the params field of the response.
+func paramsQuery(query func(ctx context.Context, clientCtx client.Context) (proto.Message, error)) moduleParamsQuery {
+	return func(ctx context.Context, clientCtx client.Context) ([]byte, error) {
+		res, err := query(ctx, clientCtx)
+		if err != nil {
+			return nil, err
+		}
+		bz, err := clientCtx.Codec.MarshalJSON(res)
+		if err != nil {
+			return nil, err
+		}
+		var params struct {
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(bz, &params); err != nil {
+			return nil, err
+		}
+		return params.Params, nil
+	}
+}
+
+// legacyParamsQuery reads the params of a module stored in an x/params
+// subspace, like the params of the tac modules.
+func legacyParamsQuery(subspace string, params paramtypes.ParamSet) moduleParamsQuery {
+	return func(ctx context.Context, clientCtx client.Context) ([]byte, error) {
+		queryClient := paramproposal.NewQueryClient(clientCtx)
+		for _, pair := range params.ParamSetPairs() {
+			res, err := queryClient.Params(ctx, &paramproposal.QueryParamsRequest{Subspace: subspace, Key: string(pair.Key)})
+			if err != nil {
+				return nil, err
+			}
+			// subspaces store their values as amino JSON
+			if err := clientCtx.LegacyAmino.UnmarshalJSON([]byte(res.Param.Value), pair.Value); err != nil {
+				return nil, fmt.Errorf("failed to decode %s/%s: %w", subspace, pair.Key, err)
+			}
+		}
+		return json.Marshal(params)
+	}
+}
+
+// moduleParamsQueries lists the params queried by tac all-params by module name
+var moduleParamsQueries = map[string]moduleParamsQuery{
+	authtypes.ModuleName: paramsQuery(func(ctx context.Context, clientCtx client.Context) (proto.Message, error) {
+		return authtypes.NewQueryClient(clientCtx).Params(ctx, &authtypes.QueryParamsRequest{})
+	}),
+	banktypes.ModuleName: paramsQuery(func(ctx context.Context, clientCtx client.Context) (proto.Message, error) {
+		return banktypes.NewQueryClient(clientCtx).Params(ctx, &banktypes.QueryParamsRequest{})
+	}),
+	stakingtypes.ModuleName: paramsQuery(func(ctx context.Context, clientCtx client.Context) (proto.Message, error) {
+		return stakingtypes.NewQueryClient(clientCtx).Params(ctx, &stakingtypes.QueryParamsRequest{})
+	}),
+	slashingtypes.ModuleName: paramsQuery(func(ctx context.Context, clientCtx client.Context) (proto.Message, error) {
+		return slashingtypes.NewQueryClient(clientCtx).Params(ctx, &slashingtypes.QueryParamsRequest{})
+	}),
+	distrtypes.ModuleName: paramsQuery(func(ctx context.Context, clientCtx client.Context) (proto.Message, error) {
+		return distrtypes.NewQueryClient(clientCtx).Params(ctx, &distrtypes.QueryParamsRequest{})
+	}),
+	minttypes.ModuleName: paramsQuery(func(ctx context.Context, clientCtx client.Context) (proto.Message, error) {
+		return minttypes.NewQueryClient(clientCtx).Params(ctx, &minttypes.QueryParamsRequest{})
+	}),
+	govtypes.ModuleName: paramsQuery(func(ctx context.Context, clientCtx client.Context) (proto.Message, error) {
+		return govv1.NewQueryClient(clientCtx).Params(ctx, &govv1.QueryParamsRequest{})
+	}),
+	evmvmtypes.ModuleName: paramsQuery(func(ctx context.Context, clientCtx client.Context) (proto.Message, error) {
+		return evmvmtypes.NewQueryClient(clientCtx).Params(ctx, &evmvmtypes.QueryParamsRequest{})
+	}),
+	evmfeemarkettypes.ModuleName: paramsQuery(func(ctx context.Context, clientCtx client.Context) (proto.Message, error) {
+		return evmfeemarkettypes.NewQueryClient(clientCtx).Params(ctx, &evmfeemarkettypes.QueryParamsRequest{})
+	}),
+	evmerc20types.ModuleName: paramsQuery(func(ctx context.Context, clientCtx client.Context) (proto.Message, error) {
+		return evmerc20types.NewQueryClient(clientCtx).Params(ctx, &evmerc20types.QueryParamsRequest{})
+	}),
+	ibctransfertypes.ModuleName: paramsQuery(func(ctx context.Context, clientCtx client.Context) (proto.Message, error) {
+		return ibctransfertypes.NewQueryClient(clientCtx).Params(ctx, &ibctransfertypes.QueryParamsRequest{})
+	}),
+	ibchookstypes.ModuleName:        legacyParamsQuery(ibchookstypes.ModuleName, &ibchookstypes.Params{}),
+	recoverytypes.ModuleName:        legacyParamsQuery(recoverytypes.ModuleName, &recoverytypes.Params{}),
+	emissiontypes.ModuleName:        legacyParamsQuery(emissiontypes.ModuleName, &emissiontypes.Params{}),
+	feeburntypes.ModuleName:         legacyParamsQuery(feeburntypes.ModuleName, &feeburntypes.Params{}),
+	performancetypes.ModuleName:     legacyParamsQuery(performancetypes.ModuleName, &performancetypes.Params{}),
+	autocompoundtypes.ModuleName:    legacyParamsQuery(autocompoundtypes.ModuleName, &autocompoundtypes.Params{}),
+	selfbondtypes.ModuleName:        legacyParamsQuery(selfbondtypes.ModuleName, &selfbondtypes.Params{}),
+	evmupgradetypes.ModuleName:      legacyParamsQuery(evmupgradetypes.ModuleName, &evmupgradetypes.Params{}),
+	contractmetatypes.ModuleName:    legacyParamsQuery(contractmetatypes.ModuleName, &contractmetatypes.Params{}),
+	bridgetypes.ModuleName:          legacyParamsQuery(bridgetypes.ModuleName, &bridgetypes.Params{}),
+	contractgastypes.ModuleName:     legacyParamsQuery(contractgastypes.ModuleName, &contractgastypes.Params{}),
+	tacgovtypes.ModuleName:          legacyParamsQuery(tacgovtypes.ModuleName, &tacgovtypes.Params{}),
+	rewardsnapshottypes.ModuleName:  legacyParamsQuery(rewardsnapshottypes.ModuleName, &rewardsnapshottypes.Params{}),
+	accountactivitytypes.ModuleName: legacyParamsQuery(accountactivitytypes.ModuleName, &accountactivitytypes.Params{}),
+	feehistorytypes.ModuleName:      legacyParamsQuery(feehistorytypes.ModuleName, &feehistorytypes.Params{}),
+	feeroutingtypes.ModuleName:      legacyParamsQuery(feeroutingtypes.ModuleName, &feeroutingtypes.Params{}),
+	nameservicetypes.ModuleName:     legacyParamsQuery(nameservicetypes.ModuleName, &nameservicetypes.Params{}),
+}
+
+func tacAllParamsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "all-params",
+		Short: "Query the params of all modules, including the EVM, IBC and tac modules",
+		Long: `Query the params of all modules, including the EVM, IBC and tac modules.
+
+The gas utilization of the last blocks is reported along with the params, see the
+gas-utilization query.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			params := make(map[string]json.RawMessage)
+			for module, query := range moduleParamsQueries {
+				bz, err := query(cmd.Context(), clientCtx)
+				if err != nil {
+					return fmt.Errorf("failed to query %s params: %w", module, err)
+				}
+				params[module] = bz
+			}
+
+			// the block gas limit is a consensus param, gov sizes it on the recent usage
+			utilization, err := queryGasUtilization(cmd.Context(), clientCtx, app.GasUtilizationWindow)
+			if err != nil {
+				return fmt.Errorf("failed to query gas utilization: %w", err)
+			}
+			if params["gas_utilization"], err = json.Marshal(utilization); err != nil {
+				return err
+			}
+
+			return printJSON(clientCtx, params)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// AddressMapping is the output of the tac address-mapping query, the forms
+// the same 20 bytes take on the Cosmos and the EVM side of the chain.
+type AddressMapping struct {
+	Address          string `json:"address"`
+	EthAddress       string `json:"eth_address"`
+	ValidatorAddress string `json:"validator_address"`
+	Bytes            string `json:"bytes"`
+}
+
+// NewAddressMapping returns the forms of addr
+func NewAddressMapping(addr sdk.AccAddress) AddressMapping {
+	return AddressMapping{
+		Address:          addr.String(),
+		EthAddress:       common.BytesToAddress(addr).Hex(),
+		ValidatorAddress: sdk.ValAddress(addr).String(),
+		Bytes:            fmt.Sprintf("%X", addr.Bytes()),
+	}
+}
+
+func tacAddressMappingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "address-mapping [address]",
+		Short: "Convert an address between its bech32 and hex forms",
+		Long: `Convert an address between its bech32 and hex forms.
+
+An account controls the same balance through its tac1... address in Cosmos txs and its
+0x... address in EVM txs. The conversion doesn't need a node.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addr, err := parseAddress(args[0])
+			if err != nil {
+				return err
+			}
+			return printJSON(client.GetClientContextFromCmd(cmd), NewAddressMapping(addr))
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// FeeReport is the output of the tac fee-report query
+type FeeReport struct {
+	EVMDenom           string         `json:"evm_denom"`
+	NoBaseFee          bool           `json:"no_base_fee"`
+	BaseFee            math.LegacyDec `json:"base_fee"`
+	MinGasPrice        math.LegacyDec `json:"min_gas_price"`
+	FeeCollector       sdk.Coins      `json:"fee_collector"`
+	CommunityPool      sdk.DecCoins   `json:"community_pool"`
+	CommunityTax       math.LegacyDec `json:"community_tax"`
+	ProposerReward     math.LegacyDec `json:"base_proposer_reward"`
+	Utilization        math.LegacyDec `json:"utilization"`
+	BurnRatio          math.LegacyDec `json:"burn_ratio"`
+	MinBurnRatio       math.LegacyDec `json:"min_burn_ratio"`
+	MaxBurnRatio       math.LegacyDec `json:"max_burn_ratio"`
+	EVMTipShare        math.LegacyDec `json:"evm_tip_share"`
+	CosmosSurplusShare math.LegacyDec `json:"cosmos_surplus_share"`
+}
+
+func tacFeeReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fee-report",
+		Short: "Query the gas prices of the chain and where the collected fees go",
+		Long: `Query the gas prices of the chain and where the collected fees go.
+
+EVM txs pay at least the base fee of the fee market, and every tx at least the min gas
+price. Fees of a block are collected by the fee collector and distributed to the
+stakers at the start of the next block, less the community tax.
+
+Before that, burn_ratio of the fees in the EVM denom is burnt. It scales from
+min_burn_ratio to max_burn_ratio with the gas utilization of the block, its gas wanted
+over the block gas limit.
+
+evm_tip_share of the priority fees of EVM txs and cosmos_surplus_share of the fees Cosmos
+txs pay above the base fee are routed to the proposer of the block at its end instead,
+see the proposer-tips query.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+
+			evmParams, err := evmvmtypes.NewQueryClient(clientCtx).Params(ctx, &evmvmtypes.QueryParamsRequest{})
+			if err != nil {
+				return err
+			}
+			feeMarketParams, err := evmfeemarkettypes.NewQueryClient(clientCtx).Params(ctx, &evmfeemarkettypes.QueryParamsRequest{})
+			if err != nil {
+				return err
+			}
+
+			feeCollector, err := banktypes.NewQueryClient(clientCtx).AllBalances(ctx, &banktypes.QueryAllBalancesRequest{
+				Address: authtypes.NewModuleAddress(authtypes.FeeCollectorName).String(),
+			})
+			if err != nil {
+				return err
+			}
+
+			distrClient := distrtypes.NewQueryClient(clientCtx)
+			communityPool, err := distrClient.CommunityPool(ctx, &distrtypes.QueryCommunityPoolRequest{})
+			if err != nil {
+				return err
+			}
+			distrParams, err := distrClient.Params(ctx, &distrtypes.QueryParamsRequest{})
+			if err != nil {
+				return err
+			}
+
+			var burnParams feeburntypes.Params
+			if _, err := legacyParamsQuery(feeburntypes.ModuleName, &burnParams)(ctx, clientCtx); err != nil {
+				return err
+			}
+			utilization, err := queryUtilization(ctx, clientCtx)
+			if err != nil {
+				return err
+			}
+			var routingParams feeroutingtypes.Params
+			if _, err := legacyParamsQuery(feeroutingtypes.ModuleName, &routingParams)(ctx, clientCtx); err != nil {
+				return err
+			}
+
+			return printJSON(clientCtx, FeeReport{
+				EVMDenom:           evmParams.Params.EvmDenom,
+				NoBaseFee:          feeMarketParams.Params.NoBaseFee,
+				BaseFee:            feeMarketParams.Params.BaseFee,
+				MinGasPrice:        feeMarketParams.Params.MinGasPrice,
+				FeeCollector:       feeCollector.Balances,
+				CommunityPool:      communityPool.Pool,
+				CommunityTax:       distrParams.Params.CommunityTax,
+				ProposerReward:     distrParams.Params.BaseProposerReward,
+				Utilization:        utilization,
+				BurnRatio:          burnParams.BurnRatio(utilization),
+				MinBurnRatio:       burnParams.MinBurnRatio,
+				MaxBurnRatio:       burnParams.MaxBurnRatio,
+				EVMTipShare:        routingParams.EVMTipShare,
+				CosmosSurplusShare: routingParams.CosmosSurplusShare,
+			})
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// BridgeChannel is the state of an ICS-20 channel in the tac bridge-status query
+type BridgeChannel struct {
+	ChannelID             string    `json:"channel_id"`
+	State                 string    `json:"state"`
+	CounterpartyChannelID string    `json:"counterparty_channel_id"`
+	ClientID              string    `json:"client_id"`
+	ClientStatus          string    `json:"client_status"`
+	CounterpartyChainID   string    `json:"counterparty_chain_id"`
+	EscrowAddress         string    `json:"escrow_address"`
+	Escrowed              sdk.Coins `json:"escrowed"`
+}
+
+// BridgeStatus is the output of the tac bridge-status query
+type BridgeStatus struct {
+	SendEnabled      bool            `json:"send_enabled"`
+	ReceiveEnabled   bool            `json:"receive_enabled"`
+	HookContracts    []string        `json:"hook_contracts"`
+	HookMaxGasLimit  uint64          `json:"hook_max_gas_limit"`
+	TransferChannels []BridgeChannel `json:"transfer_channels"`
+}
+
+func tacBridgeStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bridge-status",
+		Short: "Query the state of the IBC transfer channels, their light clients and escrowed funds",
+		Long: `Query the state of the IBC transfer channels, their light clients and escrowed funds.
+
+A channel only relays transfers while it is open and the light client of the counterparty
+chain is active. The contracts ICS-20 memos may call are listed as hook contracts.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+
+			transferParams, err := ibctransfertypes.NewQueryClient(clientCtx).Params(ctx, &ibctransfertypes.QueryParamsRequest{})
+			if err != nil {
+				return err
+			}
+			var hooksParams ibchookstypes.Params
+			if _, err := legacyParamsQuery(ibchookstypes.ModuleName, &hooksParams)(ctx, clientCtx); err != nil {
+				return err
+			}
+
+			status := BridgeStatus{
+				SendEnabled:      transferParams.Params.SendEnabled,
+				ReceiveEnabled:   transferParams.Params.ReceiveEnabled,
+				HookContracts:    hooksParams.AllowedContracts,
+				HookMaxGasLimit:  hooksParams.MaxGasLimit,
+				TransferChannels: []BridgeChannel{},
+			}
+
+			channelClient := ibcchanneltypes.NewQueryClient(clientCtx)
+			channels, err := channelClient.Channels(ctx, &ibcchanneltypes.QueryChannelsRequest{})
+			if err != nil {
+				return err
+			}
+			for _, channel := range channels.Channels {
+				if channel.PortId != ibctransfertypes.PortID {
+					continue
+				}
+
+				bridgeChannel, err := queryBridgeChannel(ctx, clientCtx, channel)
+				if err != nil {
+					return fmt.Errorf("failed to query %s: %w", channel.ChannelId, err)
+				}
+				status.TransferChannels = append(status.TransferChannels, bridgeChannel)
+			}
+
+			return printJSON(clientCtx, status)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+func queryBridgeChannel(ctx context.Context, clientCtx client.Context, channel *ibcchanneltypes.IdentifiedChannel) (BridgeChannel, error) {
+	escrow := ibctransfertypes.GetEscrowAddress(channel.PortId, channel.ChannelId)
+	bridgeChannel := BridgeChannel{
+		ChannelID:             channel.ChannelId,
+		State:                 channel.State.String(),
+		CounterpartyChannelID: channel.Counterparty.ChannelId,
+		EscrowAddress:         escrow.String(),
+	}
+
+	clientState, err := ibcchanneltypes.NewQueryClient(clientCtx).ChannelClientState(ctx, &ibcchanneltypes.QueryChannelClientStateRequest{
+		PortId:    channel.PortId,
+		ChannelId: channel.ChannelId,
+	})
+	if err != nil {
+		return bridgeChannel, err
+	}
+	bridgeChannel.ClientID = clientState.IdentifiedClientState.ClientId
+	if cs, err := ibcclienttypes.UnpackClientState(clientState.IdentifiedClientState.ClientState); err == nil {
+		if tmClientState, ok := cs.(*ibctm.ClientState); ok {
+			bridgeChannel.CounterpartyChainID = tmClientState.ChainId
+		}
+	}
+
+	clientStatus, err := ibcclienttypes.NewQueryClient(clientCtx).ClientStatus(ctx, &ibcclienttypes.QueryClientStatusRequest{
+		ClientId: bridgeChannel.ClientID,
+	})
+	if err != nil {
+		return bridgeChannel, err
+	}
+	bridgeChannel.ClientStatus = clientStatus.Status
+
+	balances, err := banktypes.NewQueryClient(clientCtx).AllBalances(ctx, &banktypes.QueryAllBalancesRequest{Address: escrow.String()})
+	if err != nil {
+		return bridgeChannel, err
+	}
+	bridgeChannel.Escrowed = balances.Balances
+
+	return bridgeChannel, nil
+}
+
+const flagBlocks = "blocks"
+
+func tacGasUtilizationCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gas-utilization",
+		Short: "Query the gas of the last blocks against the block gas limit",
+		Long: `Query the gas of the last blocks against the block gas limit.
+
+The mean, 50th, 90th and 99th percentile and max gas used and wanted by the txs of the
+last --blocks blocks are reported along with max_gas of the consensus params. Proposers
+fill blocks up to max_gas by the gas txs want, so utilization is the 99th percentile
+of gas wanted over max_gas and headroom the rest, which governance can use to size
+max_gas. Pruned blocks are left out.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			blocks, _ := cmd.Flags().GetInt64(flagBlocks)
+			if blocks <= 0 {
+				return fmt.Errorf("--%s must be positive", flagBlocks)
+			}
+
+			utilization, err := queryGasUtilization(cmd.Context(), clientCtx, blocks)
+			if err != nil {
+				return err
+			}
+			return printJSON(clientCtx, utilization)
+		},
+	}
+
+	cmd.Flags().Int64(flagBlocks, app.GasUtilizationWindow, "Number of blocks to report on")
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// queryGasUtilization reads the gas of the last blocks from the results of
+// their txs kept by the node.
+func queryGasUtilization(ctx context.Context, clientCtx client.Context, blocks int64) (app.GasUtilization, error) {
+	node, err := clientCtx.GetNode()
+	if err != nil {
+		return app.GasUtilization{}, err
+	}
+	status, err := node.Status(ctx)
+	if err != nil {
+		return app.GasUtilization{}, err
+	}
+	latest := status.SyncInfo.LatestBlockHeight
+	consensusParams, err := node.ConsensusParams(ctx, &latest)
+	if err != nil {
+		return app.GasUtilization{}, err
+	}
+
+	from := max(latest-blocks+1, status.SyncInfo.EarliestBlockHeight, 1)
+	var gasUsed, gasWanted []uint64
+	for height := from; height <= latest; height++ {
+		results, err := node.BlockResults(ctx, &height)
+		if err != nil {
+			return app.GasUtilization{}, fmt.Errorf("failed to query the results of block %d: %w", height, err)
+		}
+		used, wanted := app.BlockGas(results.TxsResults)
+		gasUsed = append(gasUsed, used)
+		gasWanted = append(gasWanted, wanted)
+	}
+	return app.NewGasUtilization(consensusParams.ConsensusParams.Block.MaxGas, gasUsed, gasWanted), nil
+}
+
+// ValidatorPerformance is the performance of a validator over the window of
+// the performance module
+type ValidatorPerformance struct {
+	OperatorAddress  string         `json:"operator_address"`
+	ConsensusAddress string         `json:"consensus_address"`
+	Moniker          string         `json:"moniker"`
+	Status           string         `json:"status"`
+	Tokens           math.Int       `json:"tokens"`
+	Jailed           bool           `json:"jailed"`
+	JailedUntil      time.Time      `json:"jailed_until"`
+	Tombstoned       bool           `json:"tombstoned"`
+	CommissionRate   math.LegacyDec `json:"commission_rate"`
+	MaxCommission    math.LegacyDec `json:"max_commission_rate"`
+	CommissionUpdate time.Time      `json:"commission_update_time"`
+	Blocks           uint64         `json:"blocks"`
+	Signed           uint64         `json:"signed"`
+	Missed           uint64         `json:"missed"`
+	Proposed         uint64         `json:"proposed"`
+	Uptime           math.LegacyDec `json:"uptime"`
+	// SlashingMissed is the count of blocks missed in the slashing window
+	SlashingMissed int64 `json:"slashing_missed_blocks"`
+}
+
+// PerformanceReport is the output of the tac validator-performance query
+type PerformanceReport struct {
+	Height     int64                  `json:"height"`
+	Window     uint64                 `json:"window"`
+	Validators []ValidatorPerformance `json:"validators"`
+}
+
+func tacValidatorPerformanceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validator-performance",
+		Short: "Query the uptime, missed blocks, proposals and commission of the validators",
+		Long: `Query the uptime, missed blocks, proposals and commission of the validators.
+
+The signatures and proposals are counted by the performance module over its window of
+blocks, which drops the oldest blocks a tenth of the window at a time. The uptime is the
+share of the blocks of the window a validator signed while in the validator set, the
+jailing and missed blocks of x/slashing are reported next to it. Validators are sorted
+by tokens.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			report, err := queryValidatorPerformance(cmd.Context(), clientCtx)
+			if err != nil {
+				return err
+			}
+			return printJSON(clientCtx, report)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+func queryValidatorPerformance(ctx context.Context, clientCtx client.Context) (PerformanceReport, error) {
+	var params performancetypes.Params
+	if _, err := legacyParamsQuery(performancetypes.ModuleName, &params)(ctx, clientCtx); err != nil {
+		return PerformanceReport{}, err
+	}
+	report := PerformanceReport{Window: params.Window, Validators: []ValidatorPerformance{}}
+
+	signingInfos := map[string]slashingtypes.ValidatorSigningInfo{}
+	slashingClient := slashingtypes.NewQueryClient(clientCtx)
+	for pageKey := []byte(nil); ; {
+		res, err := slashingClient.SigningInfos(ctx, &slashingtypes.QuerySigningInfosRequest{Pagination: &query.PageRequest{Key: pageKey}})
+		if err != nil {
+			return PerformanceReport{}, err
+		}
+		for _, info := range res.Info {
+			signingInfos[info.Address] = info
+		}
+		if pageKey = res.Pagination.GetNextKey(); len(pageKey) == 0 {
+			break
+		}
+	}
+
+	stakingClient := stakingtypes.NewQueryClient(clientCtx)
+	for pageKey := []byte(nil); ; {
+		res, err := stakingClient.Validators(ctx, &stakingtypes.QueryValidatorsRequest{Pagination: &query.PageRequest{Key: pageKey}})
+		if err != nil {
+			return PerformanceReport{}, err
+		}
+		for _, validator := range res.Validators {
+			performance, height, err := queryPerformance(clientCtx, validator)
+			if err != nil {
+				return PerformanceReport{}, fmt.Errorf("failed to query the performance of %s: %w", validator.OperatorAddress, err)
+			}
+			if info, ok := signingInfos[performance.ConsensusAddress]; ok {
+				performance.JailedUntil = info.JailedUntil
+				performance.Tombstoned = info.Tombstoned
+				performance.SlashingMissed = info.MissedBlocksCounter
+			}
+			report.Height = max(report.Height, height)
+			report.Validators = append(report.Validators, performance)
+		}
+		if pageKey = res.Pagination.GetNextKey(); len(pageKey) == 0 {
+			break
+		}
+	}
+
+	sort.SliceStable(report.Validators, func(i, j int) bool {
+		return report.Validators[i].Tokens.GT(report.Validators[j].Tokens)
+	})
+	return report, nil
+}
+
+// queryPerformance reads the window counters of a validator from the store of
+// the performance module, they aren't served over gRPC.
+func queryPerformance(clientCtx client.Context, validator stakingtypes.Validator) (ValidatorPerformance, int64, error) {
+	consAddr, err := validator.GetConsAddr()
+	if err != nil {
+		return ValidatorPerformance{}, 0, err
+	}
+	bz, height, err := clientCtx.QueryStore(performancetypes.WindowCountersKey(consAddr), performancetypes.StoreKey)
+	if err != nil {
+		return ValidatorPerformance{}, 0, err
+	}
+	counters, err := performancetypes.UnmarshalCounters(bz)
+	if err != nil {
+		return ValidatorPerformance{}, 0, err
+	}
+
+	return ValidatorPerformance{
+		OperatorAddress:  validator.OperatorAddress,
+		ConsensusAddress: sdk.ConsAddress(consAddr).String(),
+		Moniker:          validator.Description.Moniker,
+		Status:           validator.Status.String(),
+		Tokens:           validator.Tokens,
+		Jailed:           validator.Jailed,
+		CommissionRate:   validator.Commission.Rate,
+		MaxCommission:    validator.Commission.MaxRate,
+		CommissionUpdate: validator.Commission.UpdateTime,
+		Blocks:           counters.Blocks,
+		Signed:           counters.Signed(),
+		Missed:           counters.Missed,
+		Proposed:         counters.Proposed,
+		Uptime:           counters.Uptime(),
+	}, height, nil
+}
+
+// AutoCompoundStatus is the output of the tac autocompound query, the
+// delegator fields are only set when a delegator is queried
+type AutoCompoundStatus struct {
+	Grantee    string                   `json:"grantee"`
+	Params     autocompoundtypes.Params `json:"params"`
+	Delegator  string                   `json:"delegator,omitempty"`
+	Authorized *bool                    `json:"authorized,omitempty"`
+	// Authorization is the authorization of the grantee to delegate
+	Authorization json.RawMessage `json:"authorization,omitempty"`
+	Expiration    *time.Time      `json:"expiration,omitempty"`
+	// WithdrawAddress must be the delegator for its rewards to be compounded
+	WithdrawAddress string       `json:"withdraw_address,omitempty"`
+	Rewards         sdk.DecCoins `json:"rewards,omitempty"`
+}
+
+func tacAutoCompoundCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "autocompound [delegator]",
+		Short: "Query the auto-compounding params and whether a delegator opted in",
+		Long: `Query the auto-compounding params and whether a delegator opted in.
+
+Delegators opt in by granting the grantee address an authorization to delegate, e.g.
+
+  tacchaind tx authz grant <grantee> delegate --allowed-validators <valoper> --from <delegator>
+
+The allowed or denied validators of the authorization select the delegations whose rewards
+are compounded, its spend limit caps the compounded rewards and its expiration ends the
+opt-in. Revoking it opts out. The rewards are only compounded while they are withdrawn to
+the delegator.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+
+			status := AutoCompoundStatus{Grantee: autocompoundtypes.ModuleAddress().String()}
+			if _, err := legacyParamsQuery(autocompoundtypes.ModuleName, &status.Params)(ctx, clientCtx); err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				return printJSON(clientCtx, status)
+			}
+
+			delegator, err := parseAddress(args[0])
+			if err != nil {
+				return err
+			}
+			status.Delegator = delegator.String()
+
+			grants, err := authz.NewQueryClient(clientCtx).Grants(ctx, &authz.QueryGrantsRequest{
+				Granter: status.Delegator,
+				Grantee: status.Grantee,
+			})
+			if err != nil {
+				return err
+			}
+			authorized := false
+			for _, grant := range grants.Grants {
+				authorization, err := grant.GetAuthorization()
+				if err != nil || authorization.MsgTypeURL() != sdk.MsgTypeURL(&stakingtypes.MsgDelegate{}) {
+					continue
+				}
+				authorized = true
+				if status.Authorization, err = clientCtx.Codec.MarshalInterfaceJSON(authorization); err != nil {
+					return err
+				}
+				status.Expiration = grant.Expiration
+			}
+			status.Authorized = &authorized
+
+			distrClient := distrtypes.NewQueryClient(clientCtx)
+			withdrawAddr, err := distrClient.DelegatorWithdrawAddress(ctx, &distrtypes.QueryDelegatorWithdrawAddressRequest{DelegatorAddress: status.Delegator})
+			if err != nil {
+				return err
+			}
+			status.WithdrawAddress = withdrawAddr.WithdrawAddress
+			rewards, err := distrClient.DelegationTotalRewards(ctx, &distrtypes.QueryDelegationTotalRewardsRequest{DelegatorAddress: status.Delegator})
+			if err != nil {
+				return err
+			}
+			status.Rewards = rewards.Total
+
+			return printJSON(clientCtx, status)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// ContractMetadata is the output of the tac contract-metadata query, the
+// metadata is only set when a contract is queried
+type ContractMetadata struct {
+	Registry string                      `json:"registry"`
+	Metadata *contractmetatypes.Metadata `json:"metadata,omitempty"`
+}
+
+func tacContractMetadataCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "contract-metadata [contract-or-name]",
+		Short: "Query the metadata registered for a contract, by address or name",
+		Long: `Query the metadata registered for a contract, by its 0x... address or its name.
+
+Without argument the query returns the registry address. The deployer of a contract
+registers its metadata by sending the registry an EVM tx calling
+
+  register(address contractAddress, uint64 deployNonce, string name, bytes32 sourceHash, string auditLink)
+
+from the account that deployed it, deployNonce being the nonce of the deployment tx.
+The deployer then owns the metadata, it can update it with the same call and remove it
+with unregister(address contractAddress). Names are unique regardless of case.
+Governance can set or remove any metadata with a param change of the contractmeta module.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			res := ContractMetadata{Registry: contractmetatypes.RegistryAddress().Hex()}
+			if len(args) == 0 {
+				return printJSON(clientCtx, res)
+			}
+
+			if res.Metadata, err = queryContractMetadata(clientCtx, args[0]); err != nil {
+				return err
+			}
+			return printJSON(clientCtx, res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// queryContractMetadata reads the metadata of a contract given by address or
+// name from the store of the contractmeta module, it isn't served over gRPC.
+func queryContractMetadata(clientCtx client.Context, contractOrName string) (*contractmetatypes.Metadata, error) {
+	contract := common.HexToAddress(contractOrName)
+	if !common.IsHexAddress(contractOrName) {
+		bz, _, err := clientCtx.QueryStore(contractmetatypes.NameKey(contractOrName), contractmetatypes.StoreKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(bz) == 0 {
+			return nil, fmt.Errorf("no contract is registered as %q", contractOrName)
+		}
+		contract = common.BytesToAddress(bz)
+	}
+
+	bz, _, err := clientCtx.QueryStore(contractmetatypes.MetadataKey(contract), contractmetatypes.StoreKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) == 0 {
+		return nil, fmt.Errorf("no metadata is registered for %s", contract.Hex())
+	}
+	var metadata contractmetatypes.Metadata
+	if err := json.Unmarshal(bz, &metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// VoteDelegation is the output of the tac vote-delegation query, the
+// delegate is only set when a delegator with a delegate is queried
+type VoteDelegation struct {
+	Address   string `json:"address"`
+	Delegator string `json:"delegator,omitempty"`
+	Delegate  string `json:"delegate,omitempty"`
+	// DelegateAccount is the bech32 address of the delegate, as in the votes
+	DelegateAccount string `json:"delegate_account,omitempty"`
+}
+
+func tacVoteDelegationCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vote-delegation [delegator]",
+		Short: "Query the governance delegate of an account",
+		Long: `Query the governance delegate of an account, by its bech32 or 0x... address.
+
+Without argument the query returns the vote delegation address. An account picks its
+delegate by sending the address an EVM tx calling
+
+  setVoteDelegate(address delegate)
+
+and removes it with clearVoteDelegate(). When a proposal ends, the vote of the delegate
+applies to the stake of the account unless the account voted itself. The vote of the
+delegate of the delegate doesn't apply, delegation isn't transitive.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			res := VoteDelegation{Address: tacgovtypes.VoteDelegationAddress().Hex()}
+			if len(args) == 0 {
+				return printJSON(clientCtx, res)
+			}
+
+			delegator, err := parseAddress(args[0])
+			if err != nil {
+				return err
+			}
+			res.Delegator = common.BytesToAddress(delegator).Hex()
+			delegate, err := queryVoteDelegate(clientCtx, common.BytesToAddress(delegator))
+			if err != nil {
+				return err
+			}
+			if delegate != nil {
+				res.Delegate = delegate.Hex()
+				res.DelegateAccount = sdk.AccAddress(delegate.Bytes()).String()
+			}
+			return printJSON(clientCtx, res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// queryVoteDelegate reads the vote delegate of delegator from the store of the
+// tacgov module, it isn't served over gRPC. It returns nil when delegator has
+// no delegate.
+func queryVoteDelegate(clientCtx client.Context, delegator common.Address) (*common.Address, error) {
+	bz, _, err := clientCtx.QueryStore(tacgovtypes.VoteDelegateKey(delegator), tacgovtypes.StoreKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) == 0 {
+		return nil, nil
+	}
+	if len(bz) != common.AddressLength {
+		return nil, fmt.Errorf("invalid vote delegate of %s: %x", delegator.Hex(), bz)
+	}
+	delegate := common.BytesToAddress(bz)
+	return &delegate, nil
+}
+
+// BridgeRelayers is the output of the tac bridge-relayers query
+type BridgeRelayers struct {
+	Height     int64                      `json:"height"`
+	Relayers   []bridgetypes.RelayerPower `json:"relayers"`
+	TotalPower math.Int                   `json:"total_power"`
+	// QuorumPower is the least power the signers of an attestation must hold
+	QuorumPower math.Int                `json:"quorum_power"`
+	Unbondings  []bridgetypes.Unbonding `json:"unbondings"`
+	Params      bridgetypes.Params      `json:"params"`
+}
+
+func tacBridgeRelayersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bridge-relayers",
+		Short: "Query the relayer set of the TON bridge and the power of every relayer",
+		Long: `Query the relayer set of the TON bridge and the power of every relayer.
+
+Governance adds and removes relayers with a param change of the bridge module. A relayer
+attests with the power of its bond, the bond denom it bonded with tacchaind tx tac
+bond-relayer, once the bond reaches the min relayer bond. An attestation is accepted when
+its signers hold at least the quorum power.
+
+The bonds of the relayers removed from the set are listed under unbondings with the height
+they are sent back at, until then they can still be slashed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			relayers, err := queryBridgeRelayers(cmd.Context(), clientCtx)
+			if err != nil {
+				return err
+			}
+			return printJSON(clientCtx, relayers)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// queryBridgeRelayers computes the relayer powers at the latest height like
+// the bridge module does
+func queryBridgeRelayers(ctx context.Context, clientCtx client.Context) (BridgeRelayers, error) {
+	node, err := clientCtx.GetNode()
+	if err != nil {
+		return BridgeRelayers{}, err
+	}
+	status, err := node.Status(ctx)
+	if err != nil {
+		return BridgeRelayers{}, err
+	}
+	clientCtx = clientCtx.WithHeight(status.SyncInfo.LatestBlockHeight)
+
+	res := BridgeRelayers{
+		Height:      status.SyncInfo.LatestBlockHeight,
+		Relayers:    []bridgetypes.RelayerPower{},
+		TotalPower:  math.ZeroInt(),
+		QuorumPower: math.ZeroInt(),
+		Unbondings:  []bridgetypes.Unbonding{},
+	}
+	if _, err := legacyParamsQuery(bridgetypes.ModuleName, &res.Params)(ctx, clientCtx); err != nil {
+		return BridgeRelayers{}, err
+	}
+	bondPairs, _, err := clientCtx.QuerySubspace(bridgetypes.BondPrefix, bridgetypes.StoreKey)
+	if err != nil {
+		return BridgeRelayers{}, err
+	}
+	bonds := make(map[common.Address]math.Int, len(bondPairs))
+	for _, pair := range bondPairs {
+		var amount math.Int
+		if err := amount.Unmarshal(pair.Value); err != nil {
+			return BridgeRelayers{}, err
+		}
+		bonds[common.BytesToAddress(pair.Key[len(bridgetypes.BondPrefix):])] = amount
+	}
+
+	for _, relayer := range res.Params.Relayers {
+		relayer = common.HexToAddress(relayer).Hex()
+		bond, ok := bonds[common.HexToAddress(relayer)]
+		if !ok {
+			bond = math.ZeroInt()
+		}
+		power := bridgetypes.NewRelayerPower(relayer, bond, res.Params.MinRelayerBond)
+		res.Relayers = append(res.Relayers, power)
+		res.TotalPower = res.TotalPower.Add(power.Power())
+	}
+	if res.TotalPower.IsPositive() {
+		res.QuorumPower = res.Params.AttestationThreshold.MulInt(res.TotalPower).TruncateInt().AddRaw(1)
+	}
+
+	pairs, _, err := clientCtx.QuerySubspace(bridgetypes.UnbondingPrefix, bridgetypes.StoreKey)
+	if err != nil {
+		return BridgeRelayers{}, err
+	}
+	for _, pair := range pairs {
+		res.Unbondings = append(res.Unbondings, bridgetypes.Unbonding{
+			Relayer: common.BytesToAddress(pair.Key[len(bridgetypes.UnbondingPrefix):]).Hex(),
+			Height:  int64(sdk.BigEndianToUint64(pair.Value)),
+		})
+	}
+	return res, nil
+}
+
+// BridgeWithdrawals is the output of the tac bridge-withdrawals query
+type BridgeWithdrawals struct {
+	// Escrow is the address users send their withdrawals to
+	Escrow          string                   `json:"escrow"`
+	WithdrawalDelay uint64                   `json:"withdrawal_delay"`
+	Withdrawals     []bridgetypes.Withdrawal `json:"withdrawals"`
+}
+
+const flagSender = "sender"
+
+func tacBridgeWithdrawalsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bridge-withdrawals [id]",
+		Short: "Query the withdrawals to TON waiting for the end of their challenge window or a ruling",
+		Long: `Query the withdrawals to TON waiting for the end of their challenge window or a ruling.
+
+Users withdraw to TON by sending the escrow address an EVM tx calling
+
+  withdraw(string recipient)
+
+with the amount as value, recipient being a TON address. The withdrawal is queued and
+completes after the withdrawal delay of the bridge params. Until then an active relayer
+can freeze it by calling challenge(uint64 id) on the escrow, the withdrawal is then
+challenged until governance releases it or refunds the sender with a param change of
+the bridge module.
+
+Completed and refunded withdrawals are no longer listed. With an id, the query returns
+that withdrawal only.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 1 {
+				id, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid withdrawal id %q: %w", args[0], err)
+				}
+				bz, _, err := clientCtx.QueryStore(bridgetypes.WithdrawalKey(id), bridgetypes.StoreKey)
+				if err != nil {
+					return err
+				}
+				if len(bz) == 0 {
+					return fmt.Errorf("withdrawal %d is neither queued nor challenged", id)
+				}
+				var withdrawal bridgetypes.Withdrawal
+				if err := json.Unmarshal(bz, &withdrawal); err != nil {
+					return err
+				}
+				return printJSON(clientCtx, withdrawal)
+			}
+
+			sender, err := cmd.Flags().GetString(flagSender)
+			if err != nil {
+				return err
+			}
+			if sender != "" && !common.IsHexAddress(sender) {
+				return fmt.Errorf("invalid sender address %q", sender)
+			}
+
+			var params bridgetypes.Params
+			if _, err := legacyParamsQuery(bridgetypes.ModuleName, &params)(cmd.Context(), clientCtx); err != nil {
+				return err
+			}
+			res := BridgeWithdrawals{
+				Escrow:          bridgetypes.EscrowAddress().Hex(),
+				WithdrawalDelay: params.WithdrawalDelay,
+				Withdrawals:     []bridgetypes.Withdrawal{},
+			}
+			pairs, _, err := clientCtx.QuerySubspace(bridgetypes.WithdrawalPrefix, bridgetypes.StoreKey)
+			if err != nil {
+				return err
+			}
+			for _, pair := range pairs {
+				var withdrawal bridgetypes.Withdrawal
+				if err := json.Unmarshal(pair.Value, &withdrawal); err != nil {
+					return err
+				}
+				if sender != "" && common.HexToAddress(withdrawal.Sender) != common.HexToAddress(sender) {
+					continue
+				}
+				res.Withdrawals = append(res.Withdrawals, withdrawal)
+			}
+			return printJSON(clientCtx, res)
+		},
+	}
+
+	cmd.Flags().String(flagSender, "", "Only list the withdrawals of this 0x... address")
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// BridgeAsset is an asset of the output of the tac bridge-assets query
+type BridgeAsset struct {
+	bridgetypes.Asset
+	// Supply is the amount of the denom minted by the bridge
+	Supply sdk.Coin `json:"supply"`
+}
+
+func tacBridgeAssetsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bridge-assets [jetton-or-denom]",
+		Short: "Query the TON assets the bridge mints and their supply",
+		Long: `Query the TON assets the bridge mints and their supply.
+
+Each asset maps Toncoin ("native") or a jetton master, by its raw TON address, to the
+denom the bridge mints when the asset is deposited on TON and burns when it is released
+there. Amounts are converted between the decimals of the asset on TON and the decimals
+of the denom. Governance registers and removes assets with a param change of the bridge
+module, and exposes a denom to the EVM by registering it with the erc20 module.
+
+With a jetton or a denom, the query returns that asset only.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			pairs, _, err := clientCtx.QuerySubspace(bridgetypes.AssetPrefix, bridgetypes.StoreKey)
+			if err != nil {
+				return err
+			}
+			assets := []BridgeAsset{}
+			for _, pair := range pairs {
+				var asset bridgetypes.Asset
+				if err := json.Unmarshal(pair.Value, &asset); err != nil {
+					return err
+				}
+				if len(args) == 1 && asset.Jetton != bridgetypes.NormalizeJetton(args[0]) && asset.Denom != args[0] {
+					continue
+				}
+				supply, err := banktypes.NewQueryClient(clientCtx).SupplyOf(cmd.Context(), &banktypes.QuerySupplyOfRequest{Denom: asset.Denom})
+				if err != nil {
+					return err
+				}
+				assets = append(assets, BridgeAsset{Asset: asset, Supply: supply.Amount})
+			}
+
+			if len(args) == 1 {
+				if len(assets) == 0 {
+					return fmt.Errorf("no bridge asset for %s", args[0])
+				}
+				return printJSON(clientCtx, assets[0])
+			}
+			return printJSON(clientCtx, assets)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// BridgeFeeQuote is the output of the tac bridge-fee-quote query
+type BridgeFeeQuote struct {
+	Denom string `json:"denom"`
+	bridgetypes.FeeQuote
+}
+
+func tacBridgeFeeQuoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bridge-fee-quote [deposit|withdrawal] [amount]",
+		Short: "Estimate the total fees of bridging amount from or to TON",
+		Long: `Estimate the total fees of bridging amount from or to TON.
+
+The quote sums, in the EVM denom:
+- local_gas, the gas of the withdrawal tx at the current gas price, deposits have none
+- relayer_fee, the relayer_fee_base of the bridge params plus relayer_fee_rate of the amount
+- destination_fee, the TON network fees of releasing a withdrawal at the ton_price of the
+  bridge params, or the gas relayers spend executing a deposit on tacchain
+
+The gas price is the base fee of the fee market, or the min gas price when it is higher.
+The fee params are set by governance, wallets show the total before the user signs.`,
+		Example: "tacchaind q tac bridge-fee-quote withdrawal 1000000000000000000utac",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+
+			amount, err := sdk.ParseCoinNormalized(args[1])
+			if err != nil {
+				return err
+			}
+			evmParams, err := evmvmtypes.NewQueryClient(clientCtx).Params(ctx, &evmvmtypes.QueryParamsRequest{})
+			if err != nil {
+				return err
+			}
+			if amount.Denom != evmParams.Params.EvmDenom {
+				return fmt.Errorf("amount must be in %s: %s", evmParams.Params.EvmDenom, amount)
+			}
+			feeMarketParams, err := evmfeemarkettypes.NewQueryClient(clientCtx).Params(ctx, &evmfeemarkettypes.QueryParamsRequest{})
+			if err != nil {
+				return err
+			}
+			var params bridgetypes.Params
+			if _, err := legacyParamsQuery(bridgetypes.ModuleName, &params)(ctx, clientCtx); err != nil {
+				return err
+			}
+
+			quote, err := params.QuoteFee(args[0], amount.Amount, bridgeGasPrice(feeMarketParams.Params))
+			if err != nil {
+				return err
+			}
+			return printJSON(clientCtx, BridgeFeeQuote{Denom: amount.Denom, FeeQuote: quote})
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// bridgeGasPrice returns the gas price a tx pays with the fee market params
+func bridgeGasPrice(params evmfeemarkettypes.Params) math.LegacyDec {
+	if params.NoBaseFee {
+		return params.MinGasPrice
+	}
+	return math.LegacyMaxDec(params.BaseFee, params.MinGasPrice)
+}
+
+// DefaultMempoolLimit is the number of unconfirmed txs the tac mempool query
+// fetches by default, the most the CometBFT RPC returns
+const DefaultMempoolLimit = 100
+
+// MempoolTxs is the output of the tac mempool query
+type MempoolTxs struct {
+	// Total and TotalBytes are the number and size of all the txs in the
+	// mempool, Fetched the number of them the node returned
+	Total      int         `json:"total"`
+	TotalBytes int64       `json:"total_bytes"`
+	Fetched    int         `json:"fetched"`
+	Txs        []MempoolTx `json:"txs"`
+}
+
+// MempoolTx is an unconfirmed tx, decoded. The messages are in their JSON form
+// but the EVM txs, which are decoded with their sender and call.
+type MempoolTx struct {
+	Hash    string       `json:"hash"`
+	Senders []string     `json:"senders"`
+	Fee     sdk.Coins    `json:"fee"`
+	Gas     uint64       `json:"gas"`
+	Memo    string       `json:"memo,omitempty"`
+	Msgs    []MempoolMsg `json:"msgs"`
+	// Error is set when the tx can't be decoded
+	Error string `json:"error,omitempty"`
+}
+
+// MempoolMsg is a message of an unconfirmed tx
+type MempoolMsg struct {
+	Type string          `json:"type"`
+	Msg  json.RawMessage `json:"msg,omitempty"`
+	EVM  *MempoolEVMTx   `json:"evm,omitempty"`
+}
+
+// MempoolEVMTx is the EVM tx wrapped by a MsgEthereumTx
+type MempoolEVMTx struct {
+	Hash      string `json:"hash"`
+	From      string `json:"from"`
+	To        string `json:"to,omitempty"`
+	Nonce     uint64 `json:"nonce"`
+	Value     string `json:"value"`
+	Gas       uint64 `json:"gas"`
+	GasFeeCap string `json:"gas_fee_cap"`
+	GasTipCap string `json:"gas_tip_cap"`
+	Data      string `json:"data,omitempty"`
+	// Call is the decoded call to a chain specific address, see
+	// tacsdk.DecodeEVMCall
+	Call any `json:"call,omitempty"`
+}
+
+// unconfirmedTxsClient is the part of the CometBFT RPC client listing the
+// txs of the mempool
+type unconfirmedTxsClient interface {
+	UnconfirmedTxs(ctx context.Context, limit *int) (*coretypes.ResultUnconfirmedTxs, error)
+}
+
+func tacMempoolCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mempool",
+		Short: "List the unconfirmed txs of the mempool of the node, decoded",
+		Long: `List the unconfirmed txs of the mempool of the node, decoded with their senders,
+fees and messages. The EVM txs are decoded with their hash, sender, nonce and fees, along
+with their call when they are sent to a chain specific address like the bridge escrow.
+
+The node returns at most --limit txs, in the order of its mempool, the --sender filter
+applies to them. It is meant to find out why a tx isn't included, e.g. a tx waiting
+behind one with a lower nonce or sequence, or paying too low a fee.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			sender, err := cmd.Flags().GetString(flagSender)
+			if err != nil {
+				return err
+			}
+			var senderAddr sdk.AccAddress
+			if sender != "" {
+				if senderAddr, err = parseAddress(sender); err != nil {
+					return err
+				}
+			}
+			limit, err := cmd.Flags().GetInt(flags.FlagLimit)
+			if err != nil {
+				return err
+			}
+
+			res, err := queryMempool(cmd.Context(), clientCtx, senderAddr, limit)
+			if err != nil {
+				return err
+			}
+			return printJSON(clientCtx, res)
+		},
+	}
+
+	cmd.Flags().String(flagSender, "", "Only list the txs signed by this bech32 or 0x... address")
+	cmd.Flags().Int(flags.FlagLimit, DefaultMempoolLimit, "Number of unconfirmed txs to fetch")
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// queryMempool fetches up to limit unconfirmed txs and decodes them, keeping
+// the ones sender signed unless sender is empty
+func queryMempool(ctx context.Context, clientCtx client.Context, sender sdk.AccAddress, limit int) (*MempoolTxs, error) {
+	node, ok := clientCtx.Client.(unconfirmedTxsClient)
+	if !ok {
+		return nil, fmt.Errorf("the node client can't list unconfirmed txs")
+	}
+	unconfirmed, err := node.UnconfirmedTxs(ctx, &limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &MempoolTxs{
+		Total:      unconfirmed.Total,
+		TotalBytes: unconfirmed.TotalBytes,
+		Fetched:    unconfirmed.Count,
+		Txs:        []MempoolTx{},
+	}
+	for _, bz := range unconfirmed.Txs {
+		tx := decodeMempoolTx(clientCtx, bz)
+		if !sender.Empty() && !slices.Contains(tx.Senders, sender.String()) {
+			continue
+		}
+		res.Txs = append(res.Txs, tx)
+	}
+	return res, nil
+}
+
+// decodeMempoolTx decodes an unconfirmed tx, it sets the error of the tx
+// rather than failing when it can't
+func decodeMempoolTx(clientCtx client.Context, bz cmttypes.Tx) MempoolTx {
+	res := MempoolTx{Hash: fmt.Sprintf("%X", bz.Hash()), Senders: []string{}, Msgs: []MempoolMsg{}}
+	tx, err := clientCtx.TxConfig.TxDecoder()(bz)
+	if err != nil {
+		res.Error = fmt.Sprintf("failed to decode tx: %s", err)
+		return res
+	}
+	if feeTx, ok := tx.(sdk.FeeTx); ok {
+		res.Fee = feeTx.GetFee()
+		res.Gas = feeTx.GetGas()
+	}
+	if memoTx, ok := tx.(sdk.TxWithMemo); ok {
+		res.Memo = memoTx.GetMemo()
+	}
+
+	var ethSenders []string
+	for _, msg := range tx.GetMsgs() {
+		mempoolMsg := MempoolMsg{Type: sdk.MsgTypeURL(msg)}
+		if ethMsg, ok := msg.(*evmvmtypes.MsgEthereumTx); ok {
+			evmTx, err := decodeMempoolEVMTx(ethMsg.AsTransaction())
+			if err != nil {
+				res.Error = err.Error()
+				return res
+			}
+			mempoolMsg.EVM = evmTx
+			ethSenders = append(ethSenders, sdk.AccAddress(common.HexToAddress(evmTx.From).Bytes()).String())
+		} else if mempoolMsg.Msg, err = clientCtx.Codec.MarshalInterfaceJSON(msg); err != nil {
+			res.Error = fmt.Sprintf("failed to encode %s: %s", mempoolMsg.Type, err)
+			return res
+		}
+		res.Msgs = append(res.Msgs, mempoolMsg)
+	}
+
+	// the EVM txs are signed by the key of their sender rather than the one
+	// of the Cosmos tx
+	if len(ethSenders) > 0 {
+		res.Senders = ethSenders
+		return res
+	}
+	sigTx, ok := tx.(authsigning.SigVerifiableTx)
+	if !ok {
+		return res
+	}
+	signers, err := sigTx.GetSigners()
+	if err != nil {
+		res.Error = fmt.Sprintf("failed to get the signers: %s", err)
+		return res
+	}
+	for _, signer := range signers {
+		res.Senders = append(res.Senders, sdk.AccAddress(signer).String())
+	}
+	return res
+}
+
+// decodeMempoolEVMTx decodes an EVM tx, recovering its sender from its signature
+func decodeMempoolEVMTx(ethTx *gethtypes.Transaction) (*MempoolEVMTx, error) {
+	from, err := gethtypes.Sender(gethtypes.LatestSignerForChainID(ethTx.ChainId()), ethTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover the sender of %s: %w", ethTx.Hash().Hex(), err)
+	}
+
+	res := &MempoolEVMTx{
+		Hash:      ethTx.Hash().Hex(),
+		From:      from.Hex(),
+		Nonce:     ethTx.Nonce(),
+		Value:     ethTx.Value().String(),
+		Gas:       ethTx.Gas(),
+		GasFeeCap: ethTx.GasFeeCap().String(),
+		GasTipCap: ethTx.GasTipCap().String(),
+	}
+	if len(ethTx.Data()) > 0 {
+		res.Data = hexutil.Encode(ethTx.Data())
+	}
+	if ethTx.To() != nil {
+		res.To = ethTx.To().Hex()
+		// a call failing to decode is still listed, with its raw data
+		res.Call, _ = tacsdk.DecodeEVMCall(*ethTx.To(), ethTx.Data())
+	}
+	return res, nil
+}
+
+// DefaultRewardSnapshotEpochs is the number of last ended epochs the tac
+// reward-snapshots query returns by default
+const DefaultRewardSnapshotEpochs = 10
+
+// MaxRewardSnapshotEpochs is the number of epochs the tac reward-snapshots
+// query returns at most
+const MaxRewardSnapshotEpochs = 100
+
+const (
+	flagFromEpoch = "from-epoch"
+	flagToEpoch   = "to-epoch"
+)
+
+// RewardSnapshots is the output of the tac reward-snapshots query
+type RewardSnapshots struct {
+	Params rewardsnapshottypes.Params `json:"params"`
+	// CurrentEpoch is the epoch in progress, unset when the snapshots are
+	// disabled
+	CurrentEpoch *rewardsnapshottypes.Epoch `json:"current_epoch,omitempty"`
+	Epochs       []EpochSnapshots           `json:"epochs"`
+}
+
+// EpochSnapshots is an ended epoch with the snapshots of its validators
+type EpochSnapshots struct {
+	rewardsnapshottypes.Epoch
+	Validators []rewardsnapshottypes.ValidatorSnapshot `json:"validators"`
+}
+
+func tacRewardSnapshotsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reward-snapshots [validator]",
+		Short: "Query the power, commission and rewards of the validators at the end of the last epochs",
+		Long: fmt.Sprintf(`Query the power, commission and rewards of the validators at the end of the last epochs.
+
+The rewardsnapshot module sums the rewards x/distribution hands out to each validator over
+epochs of epoch_length blocks, and snapshots the bonded validators and the ones handed out
+rewards when an epoch ends. The rewards include the commission, withdrawals don't change
+them. The last retention epochs are kept, the snapshots are disabled with an epoch_length
+of zero.
+
+The query returns the last %d ended epochs by default, --from-epoch and --to-epoch select
+a range of at most %d epochs. With a validator operator address, only its snapshots are
+returned.`, DefaultRewardSnapshotEpochs, MaxRewardSnapshotEpochs),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			var validator sdk.ValAddress
+			if len(args) == 1 {
+				if validator, err = sdk.ValAddressFromBech32(args[0]); err != nil {
+					return fmt.Errorf("invalid validator address %q: %w", args[0], err)
+				}
+			}
+			fromEpoch, err := cmd.Flags().GetUint64(flagFromEpoch)
+			if err != nil {
+				return err
+			}
+			toEpoch, err := cmd.Flags().GetUint64(flagToEpoch)
+			if err != nil {
+				return err
+			}
+
+			res, err := queryRewardSnapshots(cmd.Context(), clientCtx, validator, fromEpoch, toEpoch)
+			if err != nil {
+				return err
+			}
+			return printJSON(clientCtx, res)
+		},
+	}
+
+	cmd.Flags().Uint64(flagFromEpoch, 0, "First epoch to return, defaults to the last epochs")
+	cmd.Flags().Uint64(flagToEpoch, 0, "Last epoch to return, defaults to the last ended epoch")
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// queryRewardSnapshots reads the ended epochs from fromEpoch to toEpoch and
+// their snapshots from the store of the rewardsnapshot module, they aren't
+// served over gRPC. The snapshots are the ones of validator unless it's
+// empty. Zero epochs select the last ended epochs.
+func queryRewardSnapshots(ctx context.Context, clientCtx client.Context, validator sdk.ValAddress, fromEpoch, toEpoch uint64) (RewardSnapshots, error) {
+	res := RewardSnapshots{Epochs: []EpochSnapshots{}}
+	if _, err := legacyParamsQuery(rewardsnapshottypes.ModuleName, &res.Params)(ctx, clientCtx); err != nil {
+		return RewardSnapshots{}, err
+	}
+	bz, _, err := clientCtx.QueryStore(rewardsnapshottypes.CurrentEpochKey, rewardsnapshottypes.StoreKey)
+	if err != nil {
+		return RewardSnapshots{}, err
+	}
+	if len(bz) > 0 {
+		res.CurrentEpoch = &rewardsnapshottypes.Epoch{}
+		if err := json.Unmarshal(bz, res.CurrentEpoch); err != nil {
+			return RewardSnapshots{}, err
+		}
+	}
+
+	// the kept epochs, by number
+	pairs, _, err := clientCtx.QuerySubspace(rewardsnapshottypes.EpochPrefix, rewardsnapshottypes.StoreKey)
+	if err != nil {
+		return RewardSnapshots{}, err
+	}
+	if len(pairs) == 0 {
+		return res, nil
+	}
+	lastEpoch := sdk.BigEndianToUint64(pairs[len(pairs)-1].Key[len(rewardsnapshottypes.EpochPrefix):])
+	if fromEpoch, toEpoch, err = epochRange(lastEpoch, fromEpoch, toEpoch); err != nil {
+		return RewardSnapshots{}, err
+	}
+
+	for _, pair := range pairs {
+		var epoch EpochSnapshots
+		if err := json.Unmarshal(pair.Value, &epoch.Epoch); err != nil {
+			return RewardSnapshots{}, err
+		}
+		if epoch.Number < fromEpoch || epoch.Number > toEpoch {
+			continue
+		}
+		if epoch.Validators, err = queryEpochSnapshots(clientCtx, epoch.Number, validator); err != nil {
+			return RewardSnapshots{}, fmt.Errorf("failed to query the snapshots of epoch %d: %w", epoch.Number, err)
+		}
+		res.Epochs = append(res.Epochs, epoch)
+	}
+	return res, nil
+}
+
+// epochRange returns the range of at most MaxRewardSnapshotEpochs epochs from
+// fromEpoch to toEpoch, zero epochs select the last
+// DefaultRewardSnapshotEpochs epochs up to lastEpoch
+func epochRange(lastEpoch, fromEpoch, toEpoch uint64) (uint64, uint64, error) {
+	if toEpoch == 0 {
+		toEpoch = lastEpoch
+	}
+	if fromEpoch == 0 {
+		fromEpoch = max(toEpoch, DefaultRewardSnapshotEpochs) - DefaultRewardSnapshotEpochs + 1
+	}
+	if fromEpoch > toEpoch {
+		return 0, 0, fmt.Errorf("the epoch range %d to %d is empty", fromEpoch, toEpoch)
+	}
+	if toEpoch-fromEpoch >= MaxRewardSnapshotEpochs {
+		return 0, 0, fmt.Errorf("the epoch range %d to %d is over %d epochs", fromEpoch, toEpoch, MaxRewardSnapshotEpochs)
+	}
+	return fromEpoch, toEpoch, nil
+}
+
+// queryEpochSnapshots reads the snapshots of the validators at the end of an
+// epoch, the one of validator only unless it's empty
+func queryEpochSnapshots(clientCtx client.Context, number uint64, validator sdk.ValAddress) ([]rewardsnapshottypes.ValidatorSnapshot, error) {
+	var values [][]byte
+	if validator.Empty() {
+		pairs, _, err := clientCtx.QuerySubspace(rewardsnapshottypes.SnapshotPrefixAt(number), rewardsnapshottypes.StoreKey)
+		if err != nil {
+			return nil, err
+		}
+		for _, pair := range pairs {
+			values = append(values, pair.Value)
+		}
+	} else {
+		bz, _, err := clientCtx.QueryStore(rewardsnapshottypes.SnapshotKey(number, validator), rewardsnapshottypes.StoreKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(bz) > 0 {
+			values = append(values, bz)
+		}
+	}
+
+	snapshots := []rewardsnapshottypes.ValidatorSnapshot{}
+	for _, bz := range values {
+		var snapshot rewardsnapshottypes.ValidatorSnapshot
+		if err := json.Unmarshal(bz, &snapshot); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// AccountActivity is the output of the tac account-activity query, the
+// activity is unset when the account wasn't seen since the indexing was
+// enabled
+type AccountActivity struct {
+	Address    string                         `json:"address"`
+	EthAddress string                         `json:"eth_address"`
+	Enabled    bool                           `json:"enabled"`
+	Activity   *accountactivitytypes.Activity `json:"activity,omitempty"`
+}
+
+func tacAccountActivityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "account-activity [address]",
+		Short: "Query when an account was first and last seen, its tx count and the gas it spent",
+		Long: `Query when an account was first and last seen, its tx count and the gas it spent, by its
+bech32 or 0x... address.
+
+The accountactivity module records the successful txs of a block for the accounts that
+signed them, or sent them for the EVM txs, and the gas used for the account paying the
+fees. The indexing is disabled by default, governance enables it with a param change of
+the accountactivity module, and it only covers the txs from then on. The counters are
+read from the store of the module, also served by the ABCIQuery gRPC method of
+cosmos.base.tendermint.v1beta1.Service at the path /store/accountactivity/key.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			addr, err := parseAddress(args[0])
+			if err != nil {
+				return err
+			}
+
+			res, err := queryAccountActivity(cmd.Context(), clientCtx, addr)
+			if err != nil {
+				return err
+			}
+			return printJSON(clientCtx, res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// queryAccountActivity reads the activity of an account from the store of the
+// accountactivity module, it isn't served over a gRPC service of its own.
+func queryAccountActivity(ctx context.Context, clientCtx client.Context, addr sdk.AccAddress) (AccountActivity, error) {
+	var params accountactivitytypes.Params
+	if _, err := legacyParamsQuery(accountactivitytypes.ModuleName, &params)(ctx, clientCtx); err != nil {
+		return AccountActivity{}, err
+	}
+	res := AccountActivity{
+		Address:    addr.String(),
+		EthAddress: common.BytesToAddress(addr).Hex(),
+		Enabled:    params.Enabled,
+	}
+	bz, _, err := clientCtx.QueryStore(accountactivitytypes.ActivityKey(addr), accountactivitytypes.StoreKey)
+	if err != nil {
+		return AccountActivity{}, err
+	}
+	if len(bz) == 0 {
+		return res, nil
+	}
+	res.Activity = &accountactivitytypes.Activity{}
+	if err := json.Unmarshal(bz, res.Activity); err != nil {
+		return AccountActivity{}, err
+	}
+	return res, nil
+}
+
+// DefaultFeeHistoryBlocks is the number of last blocks the tac fee-history
+// query returns by default
+const DefaultFeeHistoryBlocks = 20
+
+// FeeHistory is the output of the tac fee-history query
+type FeeHistory struct {
+	Retention uint64            `json:"retention"`
+	Blocks    []FeeHistoryBlock `json:"blocks"`
+}
+
+// FeeHistoryBlock is the base fee and gas used of a block, with the ratio of
+// gas used eth_feeHistory reports
+type FeeHistoryBlock struct {
+	feehistorytypes.BlockFee
+	GasUsedRatio math.LegacyDec `json:"gas_used_ratio"`
+}
+
+func tacFeeHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fee-history",
+		Short: "Query the base fee and gas used of the last blocks",
+		Long: fmt.Sprintf(`Query the base fee and gas used of the last blocks, to estimate the fees of a tx.
+
+The feehistory module records the base fee of the fee market, the gas used by the txs
+and the block gas limit of every block, and keeps the last retention blocks of its params
+(%d by default). The series is read at once from the store of the module, also served by
+the ABCIQuery gRPC method of cosmos.base.tendermint.v1beta1.Service at the path
+/store/feehistory/subspace. The last --blocks kept blocks are returned, up to the
+retention.`, feehistorytypes.DefaultRetention),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			blocks, _ := cmd.Flags().GetInt64(flagBlocks)
+			if blocks <= 0 {
+				return fmt.Errorf("--%s must be positive", flagBlocks)
+			}
+
+			res, err := queryFeeHistory(cmd.Context(), clientCtx, blocks)
+			if err != nil {
+				return err
+			}
+			return printJSON(clientCtx, res)
+		},
+	}
+
+	cmd.Flags().Int64(flagBlocks, DefaultFeeHistoryBlocks, "Number of last blocks to return")
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// queryFeeHistory reads the last blocks kept by the feehistory module in a
+// single query of its store, it isn't served over a gRPC service of its own.
+func queryFeeHistory(ctx context.Context, clientCtx client.Context, blocks int64) (FeeHistory, error) {
+	var params feehistorytypes.Params
+	if _, err := legacyParamsQuery(feehistorytypes.ModuleName, &params)(ctx, clientCtx); err != nil {
+		return FeeHistory{}, err
+	}
+	pairs, _, err := clientCtx.QuerySubspace(feehistorytypes.BlockFeePrefix, feehistorytypes.StoreKey)
+	if err != nil {
+		return FeeHistory{}, err
+	}
+	// the blocks are kept by height
+	if int64(len(pairs)) > blocks {
+		pairs = pairs[int64(len(pairs))-blocks:]
+	}
+
+	res := FeeHistory{Retention: params.Retention, Blocks: []FeeHistoryBlock{}}
+	for _, pair := range pairs {
+		var block FeeHistoryBlock
+		if err := json.Unmarshal(pair.Value, &block.BlockFee); err != nil {
+			return FeeHistory{}, err
+		}
+		block.GasUsedRatio = block.BlockFee.GasUsedRatio()
+		res.Blocks = append(res.Blocks, block)
+	}
+	return res, nil
+}
+
+// ProposerTips is the output of the tac proposer-tips query
+type ProposerTips struct {
+	EVMTipShare        math.LegacyDec                 `json:"evm_tip_share"`
+	CosmosSurplusShare math.LegacyDec                 `json:"cosmos_surplus_share"`
+	Proposers          []feeroutingtypes.ProposerTips `json:"proposers"`
+}
+
+func tacProposerTipsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proposer-tips [validator]",
+		Short: "Query the tips routed to the validators for the blocks they proposed",
+		Long: `Query the tips routed to the validators for the blocks they proposed.
+
+The feerouting module routes evm_tip_share of the priority fees of the EVM txs of a block,
+the gas they used times their effective gas tip over the base fee, and cosmos_surplus_share
+of the fees Cosmos txs paid above the base fee for their gas limit, to the validator that
+proposed the block instead of distributing them to all stakers. Both shares are zero by
+default. The tips are handed to the proposer as rewards, split between its commission and
+its delegators.
+
+The query returns the tips, the number of blocks and the last height each validator was
+routed tips at, in the EVM denom. With a validator operator address, only its tips are
+returned.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			var validator sdk.ValAddress
+			if len(args) == 1 {
+				if validator, err = sdk.ValAddressFromBech32(args[0]); err != nil {
+					return fmt.Errorf("invalid validator address %q: %w", args[0], err)
+				}
+			}
+
+			res, err := queryProposerTips(cmd.Context(), clientCtx, validator)
+			if err != nil {
+				return err
+			}
+			return printJSON(clientCtx, res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// queryProposerTips reads the tips routed to the validator, or to all
+// validators when it is nil, from the store of the feerouting module, they
+// aren't served over a gRPC service of its own.
+func queryProposerTips(ctx context.Context, clientCtx client.Context, validator sdk.ValAddress) (ProposerTips, error) {
+	var params feeroutingtypes.Params
+	if _, err := legacyParamsQuery(feeroutingtypes.ModuleName, &params)(ctx, clientCtx); err != nil {
+		return ProposerTips{}, err
+	}
+	res := ProposerTips{
+		EVMTipShare:        params.EVMTipShare,
+		CosmosSurplusShare: params.CosmosSurplusShare,
+		Proposers:          []feeroutingtypes.ProposerTips{},
+	}
+
+	var values [][]byte
+	if validator != nil {
+		bz, _, err := clientCtx.QueryStore(feeroutingtypes.ProposerTipsKey(validator), feeroutingtypes.StoreKey)
+		if err != nil {
+			return ProposerTips{}, err
+		}
+		if len(bz) > 0 {
+			values = append(values, bz)
+		}
+	} else {
+		pairs, _, err := clientCtx.QuerySubspace(feeroutingtypes.ProposerTipsPrefix, feeroutingtypes.StoreKey)
+		if err != nil {
+			return ProposerTips{}, err
+		}
+		for _, pair := range pairs {
+			values = append(values, pair.Value)
+		}
+	}
+	for _, bz := range values {
+		var tips feeroutingtypes.ProposerTips
+		if err := json.Unmarshal(bz, &tips); err != nil {
+			return ProposerTips{}, err
+		}
+		res.Proposers = append(res.Proposers, tips)
+	}
+	return res, nil
+}
+
+// RevenueReport is a protocol revenue with its net issuance, the minted
+// amount less the burnt one
+type RevenueReport struct {
+	feeroutingtypes.Revenue
+	NetIssuance math.Int `json:"net_issuance"`
+}
+
+// NewRevenueReport returns the report of revenue
+func NewRevenueReport(revenue feeroutingtypes.Revenue) RevenueReport {
+	return RevenueReport{Revenue: revenue, NetIssuance: revenue.NetIssuance()}
+}
+
+// EpochRevenueReport is the protocol revenue of an epoch with its net issuance
+type EpochRevenueReport struct {
+	feeroutingtypes.EpochRevenue
+	NetIssuance math.Int `json:"net_issuance"`
+}
+
+// NewEpochRevenueReport returns the report of the revenue of an epoch
+func NewEpochRevenueReport(revenue feeroutingtypes.EpochRevenue) EpochRevenueReport {
+	return EpochRevenueReport{EpochRevenue: revenue, NetIssuance: revenue.NetIssuance()}
+}
+
+// ProtocolRevenue is the output of the tac protocol-revenue query
+type ProtocolRevenue struct {
+	// Total is the revenue since the feerouting module was added
+	Total RevenueReport `json:"total"`
+	// CurrentEpoch is the revenue of the epoch in progress, unset before it
+	// had any
+	CurrentEpoch *EpochRevenueReport  `json:"current_epoch,omitempty"`
+	Epochs       []EpochRevenueReport `json:"epochs"`
+}
+
+func tacProtocolRevenueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "protocol-revenue",
+		Short: "Query the provisions minted, the fees burnt, the tips and the community tax of the last epochs",
+		Long: fmt.Sprintf(`Query the provisions minted, the fees burnt, the tips and the community tax of the last epochs.
+
+The feerouting module accounts for the protocol economics in the EVM denom: the provision
+x/emission mints every block, the share of the fees x/feeburn burns, the tips routed to
+the block proposers and the community tax x/distribution takes on the fees and provisions
+it hands out, truncated. The net issuance is the minted amount less the burnt one, negative
+when more was burnt than minted.
+
+The revenue is summed over the epochs of the rewardsnapshot module and since the feerouting
+module was added. Nothing is summed over epochs while the snapshots are disabled, and the
+revenue of the last retention epochs is kept. The tips of the block ending an epoch count
+in the next one. When an epoch ends, its revenue is emitted in an EventEpochRevenue event
+and set to the protocol_epoch_revenue telemetry gauges.
+
+The query returns the last %d ended epochs by default, --from-epoch and --to-epoch select
+a range of at most %d epochs.`, DefaultRewardSnapshotEpochs, MaxRewardSnapshotEpochs),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			fromEpoch, err := cmd.Flags().GetUint64(flagFromEpoch)
+			if err != nil {
+				return err
+			}
+			toEpoch, err := cmd.Flags().GetUint64(flagToEpoch)
+			if err != nil {
+				return err
+			}
+
+			res, err := queryProtocolRevenue(clientCtx, fromEpoch, toEpoch)
+			if err != nil {
+				return err
+			}
+			return printJSON(clientCtx, res)
+		},
+	}
+
+	cmd.Flags().Uint64(flagFromEpoch, 0, "First epoch to return, defaults to the last epochs")
+	cmd.Flags().Uint64(flagToEpoch, 0, "Last epoch to return, defaults to the last ended epoch")
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// queryProtocolRevenue reads the total revenue and the revenue of the ended
+// epochs from fromEpoch to toEpoch from the store of the feerouting module.
+// Zero epochs select the last ended epochs.
+func queryProtocolRevenue(clientCtx client.Context, fromEpoch, toEpoch uint64) (ProtocolRevenue, error) {
+	total := feeroutingtypes.ZeroRevenue()
+	bz, _, err := clientCtx.QueryStore(feeroutingtypes.TotalRevenueKey, feeroutingtypes.StoreKey)
+	if err != nil {
+		return ProtocolRevenue{}, err
+	}
+	if len(bz) > 0 {
+		if err := json.Unmarshal(bz, &total); err != nil {
+			return ProtocolRevenue{}, err
+		}
+	}
+	res := ProtocolRevenue{Total: NewRevenueReport(total), Epochs: []EpochRevenueReport{}}
+
+	// the kept epochs by number, the last one is in progress unless it ended
+	pairs, _, err := clientCtx.QuerySubspace(feeroutingtypes.EpochRevenuePrefix, feeroutingtypes.StoreKey)
+	if err != nil {
+		return ProtocolRevenue{}, err
+	}
+	var ended []feeroutingtypes.EpochRevenue
+	for _, pair := range pairs {
+		var revenue feeroutingtypes.EpochRevenue
+		if err := json.Unmarshal(pair.Value, &revenue); err != nil {
+			return ProtocolRevenue{}, err
+		}
+		if revenue.EndHeight == 0 {
+			current := NewEpochRevenueReport(revenue)
+			res.CurrentEpoch = &current
+			continue
+		}
+		ended = append(ended, revenue)
+	}
+	if len(ended) == 0 {
+		return res, nil
+	}
+
+	if fromEpoch, toEpoch, err = epochRange(ended[len(ended)-1].Epoch, fromEpoch, toEpoch); err != nil {
+		return ProtocolRevenue{}, err
+	}
+	for _, revenue := range ended {
+		if revenue.Epoch >= fromEpoch && revenue.Epoch <= toEpoch {
+			res.Epochs = append(res.Epochs, NewEpochRevenueReport(revenue))
+		}
+	}
+	return res, nil
+}
+
+const (
+	flagTx             = "tx"
+	flagPriorityBuffer = "priority-buffer"
+)
+
+func tacEstimateFeeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "estimate-fee",
+		Short: "Estimate the gas and suggest the fee of a Cosmos or an EVM tx",
+		Long: fmt.Sprintf(`Estimate the gas and suggest the fee of a Cosmos or an EVM tx, for wallets.
+
+--tx is a JSON file holding either:
+- a Cosmos tx, e.g. written by a tx command with --generate-only, which the node simulates.
+  The gas limit is --%s times the gas used. A tx without signatures is simulated
+  with the public keys and sequences of its signers.
+- the call of an EVM tx, the object eth_estimateGas takes, with at least "from", e.g.
+  {"from":"0x...","to":"0x...","value":"0x1","input":"0x..."}. The node estimates its gas
+  like eth_estimateGas, which is the gas limit.
+
+The suggested gas price is the base fee of the fee market, or the min gas price when it
+is higher, plus --%s percent of it (%d by default), so the fee still covers the
+base fee after it rose for a few blocks. The fee is the gas price times the gas limit,
+in the EVM denom and in TAC. An EVM tx uses the gas price as its gas price, or as the
+max fee per gas of a dynamic fee tx.
+
+The estimate combines the Simulate method of cosmos.tx.v1beta1.Service, the EstimateGas
+method of cosmos.evm.vm.v1.Query and the fee market params, which Go clients call over
+gRPC with the estimates of client/tacsdk.`, flags.FlagGasAdjustment, flagPriorityBuffer, tacsdk.DefaultPriorityBuffer),
+		Example: "tacchaind q tac estimate-fee --tx tx.json --priority-buffer 20",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			txFile, _ := cmd.Flags().GetString(flagTx)
+			priorityBuffer, _ := cmd.Flags().GetUint64(flagPriorityBuffer)
+			gasAdjustment, _ := cmd.Flags().GetFloat64(flags.FlagGasAdjustment)
+
+			bz, err := os.ReadFile(txFile)
+			if err != nil {
+				return err
+			}
+			res, err := estimateFee(cmd.Context(), clientCtx, bz, gasAdjustment, priorityBuffer)
+			if err != nil {
+				return err
+			}
+			return printJSON(clientCtx, res)
+		},
+	}
+
+	cmd.Flags().String(flagTx, "", "JSON file of the Cosmos tx or of the EVM call")
+	cmd.Flags().Uint64(flagPriorityBuffer, tacsdk.DefaultPriorityBuffer, "Percentage added to the gas price of the fee market")
+	cmd.Flags().Float64(flags.FlagGasAdjustment, tacsdk.DefaultGasAdjustment, "Multiplier of the gas used by a Cosmos tx giving its gas limit")
+	_ = cmd.MarkFlagRequired(flagTx)
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// estimateFee estimates the fee of bz, the JSON of a Cosmos tx, which has a
+// body, or of an EVM call
+func estimateFee(ctx context.Context, clientCtx client.Context, bz []byte, gasAdjustment float64, priorityBuffer uint64) (tacsdk.FeeEstimate, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bz, &fields); err != nil {
+		return tacsdk.FeeEstimate{}, fmt.Errorf("tx file must hold a JSON object: %w", err)
+	}
+
+	if _, ok := fields["body"]; ok {
+		tx, err := clientCtx.TxConfig.TxJSONDecoder()(bz)
+		if err != nil {
+			return tacsdk.FeeEstimate{}, fmt.Errorf("invalid Cosmos tx: %w", err)
+		}
+		encodingConfig := tacsdk.EncodingConfig{
+			InterfaceRegistry: clientCtx.InterfaceRegistry,
+			Codec:             clientCtx.Codec,
+			TxConfig:          clientCtx.TxConfig,
+			Amino:             clientCtx.LegacyAmino,
+		}
+		return encodingConfig.EstimateCosmosFee(ctx, clientCtx, tx, gasAdjustment, priorityBuffer)
+	}
+
+	var args evmvmtypes.TransactionArgs
+	if err := json.Unmarshal(bz, &args); err != nil {
+		return tacsdk.FeeEstimate{}, fmt.Errorf("invalid EVM call: %w", err)
+	}
+	return tacsdk.EstimateEVMFee(ctx, clientCtx, args, priorityBuffer)
+}
+
+// NameLookup is the output of the tac name query. A name lookup returns the
+// record of the name and the address it resolves to, an address lookup the
+// address and its primary name.
+type NameLookup struct {
+	Registry string `json:"registry"`
+	Resolver string `json:"resolver"`
+	// Height is the height the lookup was made at
+	Height int64                        `json:"height,omitempty"`
+	Record *nameservicetypes.NameRecord `json:"record,omitempty"`
+	// Expired is true when the name doesn't resolve in the block after Height
+	Expired     bool            `json:"expired,omitempty"`
+	Address     *AddressMapping `json:"address,omitempty"`
+	PrimaryName string          `json:"primary_name,omitempty"`
+}
+
+func tacNameCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "name [name-or-address]",
+		Short: "Resolve a name to its address, or an address to its primary name",
+		Long: `Resolve a name to its address, or a bech32 or 0x... address to its primary name.
+
+Without argument the query returns the registry and resolver addresses. An account
+registers a name by sending the registry an EVM tx calling
+
+  register(string name, address target)
+
+and pays the registration fee of the nameservice params from its balance to the community
+pool. The name is registered for the registration period of the params, a year by
+default. Names are 3 to 32 letters, digits and hyphens, unique regardless of case. The
+owner extends the registration with renew(string name), at the same fee, points the name
+to another address with setAddress(string name, address target) and gives it away with
+transfer(string name, address newOwner). An expired name doesn't resolve and anyone can
+register it again. An address reverse resolves to the name it set with
+setPrimaryName(string name), as long as the name resolves to it.
+
+Contracts resolve names with the resolver precompile: resolve(string name),
+reverseResolve(address addr) and record(string name).`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			res := NameLookup{
+				Registry: nameservicetypes.RegistryAddress().Hex(),
+				Resolver: nameservicetypes.ResolverAddress().Hex(),
+			}
+			if len(args) == 0 {
+				return printJSON(clientCtx, res)
+			}
+
+			if addr, err := parseAddress(args[0]); err == nil {
+				err = lookupPrimaryName(clientCtx, addr, &res)
+			} else {
+				err = lookupName(clientCtx, args[0], &res)
+			}
+			if err != nil {
+				return err
+			}
+			return printJSON(clientCtx, res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// lookupName reads the record of name from the store of the nameservice
+// module, it isn't served over gRPC.
+func lookupName(clientCtx client.Context, name string, res *NameLookup) error {
+	if err := nameservicetypes.ValidateName(name); err != nil {
+		return err
+	}
+	record, height, err := queryNameRecord(clientCtx, name)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return fmt.Errorf("name %q isn't registered", name)
+	}
+
+	res.Height = height
+	res.Record = record
+	res.Expired = record.IsExpired(height + 1)
+	if !res.Expired {
+		mapping := NewAddressMapping(record.TargetAddress().Bytes())
+		res.Address = &mapping
+	}
+	return nil
+}
+
+// lookupPrimaryName reads the primary name of addr from the store of the
+// nameservice module. Like the resolver, it only reports a name still
+// resolving to addr.
+func lookupPrimaryName(clientCtx client.Context, addr sdk.AccAddress, res *NameLookup) error {
+	mapping := NewAddressMapping(addr)
+	res.Address = &mapping
+
+	bz, height, err := clientCtx.QueryStore(nameservicetypes.PrimaryNameKey(common.BytesToAddress(addr)), nameservicetypes.StoreKey)
+	if err != nil {
+		return err
+	}
+	res.Height = height
+	if len(bz) == 0 {
+		return nil
+	}
+
+	// read the record at the same height
+	record, _, err := queryNameRecord(clientCtx.WithHeight(height), string(bz))
+	if err != nil {
+		return err
+	}
+	if record != nil && !record.IsExpired(height+1) && record.TargetAddress() == common.BytesToAddress(addr) {
+		res.PrimaryName = record.Name
+	}
+	return nil
+}
+
+// queryNameRecord reads the record of name, nil if it was never registered
+func queryNameRecord(clientCtx client.Context, name string) (*nameservicetypes.NameRecord, int64, error) {
+	bz, height, err := clientCtx.QueryStore(nameservicetypes.RecordKey(name), nameservicetypes.StoreKey)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(bz) == 0 {
+		return nil, height, nil
+	}
+	var record nameservicetypes.NameRecord
+	if err := json.Unmarshal(bz, &record); err != nil {
+		return nil, 0, err
+	}
+	return &record, height, nil
+}
+
+func tacTxProofCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tx-proof [hash]",
+		Short: "Query the proof that a tx was included in a block and gave a result",
+		Long: `Query the Merkle proof that a tx was included in a block and gave a result, for verifiers
+outside the chain such as the bridge contracts. The hash is the one of a Cosmos tx, or
+the 0x... hash of an EVM tx, which is looked up in the tx index of the node.
+
+The proof holds the tx and its Merkle proof against the data hash of the header of its
+block, and the result of the tx and its Merkle proof against the last results hash of
+the header of the next block, which also commits to the block of the tx. The signed
+header of the next block is part of the proof: a verifier trusting its hash, from a light
+client or from the signatures of a validator set it trusts, can trust the tx and its
+result. The proof of a tx of the latest block is available once the next block is
+committed.
+
+Only the code, data, gas wanted and gas used of a result are committed by the headers.
+The data holds the responses of the messages of the tx, including the logs of an EVM tx,
+but the events of the result are returned for convenience only and can't be trusted.
+The Go client library verifies the proofs with tacsdk.TxProof.Verify.`,
+		Example: `tacchaind q tac tx-proof 0x6c1a...
+tacchaind q tac tx-proof 9F3B...`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			node, err := clientCtx.GetNode()
+			if err != nil {
+				return err
+			}
+
+			proof, err := queryTxProof(cmd.Context(), node, args[0])
+			if err != nil {
+				return err
+			}
+			return printJSON(clientCtx, proof)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// queryTxProof returns the proof of the tx hash from the block of the tx, its
+// results and the signed header of the next block
+func queryTxProof(ctx context.Context, node client.CometRPC, hash string) (tacsdk.TxProof, error) {
+	height, index, err := findTx(ctx, node, hash)
+	if err != nil {
+		return tacsdk.TxProof{}, err
+	}
+
+	status, err := node.Status(ctx)
+	if err != nil {
+		return tacsdk.TxProof{}, err
+	}
+	next := height + 1
+	if status.SyncInfo.LatestBlockHeight < next {
+		return tacsdk.TxProof{}, fmt.Errorf("tx %s is in the latest block %d, its proof is available once block %d is committed", hash, height, next)
+	}
+
+	block, err := node.Block(ctx, &height)
+	if err != nil {
+		return tacsdk.TxProof{}, fmt.Errorf("failed to get block %d: %w", height, err)
+	}
+	results, err := node.BlockResults(ctx, &height)
+	if err != nil {
+		return tacsdk.TxProof{}, fmt.Errorf("failed to get the results of block %d: %w", height, err)
+	}
+	commit, err := node.Commit(ctx, &next)
+	if err != nil {
+		return tacsdk.TxProof{}, fmt.Errorf("failed to get the signed header of block %d: %w", next, err)
+	}
+	return tacsdk.NewTxProof(block.Block, results.TxsResults, index, &commit.SignedHeader)
+}
+
+// findTx returns the height and the index in its block of the Cosmos tx hash,
+// or of the Cosmos tx of the EVM tx hash
+func findTx(ctx context.Context, node client.CometRPC, hash string) (int64, uint32, error) {
+	if strings.HasPrefix(hash, "0x") {
+		if len(hash) != 66 {
+			return 0, 0, fmt.Errorf("invalid eth tx hash: %s", hash)
+		}
+		query := fmt.Sprintf("%s.%s='%s'", evmvmtypes.EventTypeEthereumTx, evmvmtypes.AttributeKeyEthereumTxHash, common.HexToHash(hash).Hex())
+		page, perPage := 1, 1
+		res, err := node.TxSearch(ctx, query, false, &page, &perPage, "")
+		if err != nil {
+			return 0, 0, err
+		}
+		if len(res.Txs) == 0 {
+			return 0, 0, fmt.Errorf("cosmos tx of %s not found", hash)
+		}
+		return res.Txs[0].Height, res.Txs[0].Index, nil
+	}
+
+	bz, err := hex.DecodeString(hash)
+	if err != nil || len(bz) != 32 {
+		return 0, 0, fmt.Errorf("invalid tx hash: %s", hash)
+	}
+	res, err := node.Tx(ctx, bz, false)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get tx %s: %w", hash, err)
+	}
+	return res.Height, res.Index, nil
+}
+
+// printJSON prints v as indented JSON like the other chain specific queries
+func printJSON(clientCtx client.Context, v interface{}) error {
+	bz, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return clientCtx.PrintRaw(bz)
+}
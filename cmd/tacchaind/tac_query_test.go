@@ -0,0 +1,1712 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtversion "github.com/cometbft/cometbft/proto/tendermint/version"
+	cmtrpctypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cometbft/cometbft/version"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/cosmos/evm/crypto/ethsecp256k1"
+	evmfeemarkettypes "github.com/cosmos/evm/x/feemarket/types"
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+	accountactivitytypes "github.com/Asphere-xyz/tacchain/x/accountactivity/types"
+	autocompoundtypes "github.com/Asphere-xyz/tacchain/x/autocompound/types"
+	bridgetypes "github.com/Asphere-xyz/tacchain/x/bridge/types"
+	contractgastypes "github.com/Asphere-xyz/tacchain/x/contractgas/types"
+	contractmetatypes "github.com/Asphere-xyz/tacchain/x/contractmeta/types"
+	emissiontypes "github.com/Asphere-xyz/tacchain/x/emission/types"
+	evmupgradetypes "github.com/Asphere-xyz/tacchain/x/evmupgrade/types"
+	feeburntypes "github.com/Asphere-xyz/tacchain/x/feeburn/types"
+	feehistorytypes "github.com/Asphere-xyz/tacchain/x/feehistory/types"
+	feeroutingtypes "github.com/Asphere-xyz/tacchain/x/feerouting/types"
+	ibchookstypes "github.com/Asphere-xyz/tacchain/x/ibchooks/types"
+	nameservicetypes "github.com/Asphere-xyz/tacchain/x/nameservice/types"
+	performancetypes "github.com/Asphere-xyz/tacchain/x/performance/types"
+	recoverytypes "github.com/Asphere-xyz/tacchain/x/recovery/types"
+	rewardsnapshottypes "github.com/Asphere-xyz/tacchain/x/rewardsnapshot/types"
+	selfbondtypes "github.com/Asphere-xyz/tacchain/x/selfbond/types"
+	tacgovtypes "github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+func TestNewAddressMapping(t *testing.T) {
+	hexAddr := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	bech32Addr := sdk.AccAddress(hexAddr.Bytes()).String()
+
+	for _, address := range []string{hexAddr.Hex(), bech32Addr} {
+		addr, err := parseAddress(address)
+		require.NoError(t, err)
+
+		mapping := NewAddressMapping(addr)
+		require.Equal(t, bech32Addr, mapping.Address)
+		require.Equal(t, hexAddr.Hex(), mapping.EthAddress)
+		require.Equal(t, sdk.ValAddress(hexAddr.Bytes()).String(), mapping.ValidatorAddress)
+		require.Equal(t, "1111111111111111111111111111111111111111", mapping.Bytes)
+	}
+}
+
+func TestTacQueryCmd(t *testing.T) {
+	cmd := TacQueryCmd()
+
+	var names []string
+	for _, sub := range cmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	require.ElementsMatch(t, []string{"apr", "emission", "all-params", "address-mapping", "fee-report", "bridge-status", "gas-utilization", "validator-performance", "autocompound", "contract-metadata", "vote-delegation", "bridge-relayers", "bridge-withdrawals", "bridge-assets", "bridge-fee-quote", "mempool", "reward-snapshots", "account-activity", "fee-history", "estimate-fee", "proposer-tips", "protocol-revenue", "name", "tx-proof"}, names)
+
+	// every module of the chain with params is covered by all-params
+	for _, module := range []string{"auth", "bank", "staking", "distribution", "mint", "gov", "evm", "feemarket", "erc20", "transfer", "ibchooks", "recovery", "emission", "feeburn", "performance", "autocompound", "selfbond", "evmupgrade", "contractmeta", "bridge", "contractgas", "tacgov", "rewardsnapshot", "accountactivity", "feehistory", "feerouting", "nameservice"} {
+		require.Contains(t, moduleParamsQueries, module)
+	}
+}
+
+// tacDefaultParams returns the default params of the tac modules by subspace
+func tacDefaultParams() map[string]paramtypes.ParamSet {
+	accountactivity := accountactivitytypes.DefaultParams()
+	autocompound := autocompoundtypes.DefaultParams()
+	bridge := bridgetypes.DefaultParams()
+	contractgas := contractgastypes.DefaultParams()
+	contractmeta := contractmetatypes.DefaultParams()
+	emission := emissiontypes.DefaultParams()
+	evmupgrade := evmupgradetypes.DefaultParams()
+	feeburn := feeburntypes.DefaultParams()
+	feehistory := feehistorytypes.DefaultParams()
+	feerouting := feeroutingtypes.DefaultParams()
+	ibchooks := ibchookstypes.DefaultParams()
+	nameservice := nameservicetypes.DefaultParams()
+	performance := performancetypes.DefaultParams()
+	recovery := recoverytypes.DefaultParams()
+	rewardsnapshot := rewardsnapshottypes.DefaultParams()
+	selfbond := selfbondtypes.DefaultParams()
+	tacgov := tacgovtypes.DefaultParams()
+	return map[string]paramtypes.ParamSet{
+		accountactivitytypes.ModuleName: &accountactivity,
+		autocompoundtypes.ModuleName:    &autocompound,
+		bridgetypes.ModuleName:          &bridge,
+		contractgastypes.ModuleName:     &contractgas,
+		contractmetatypes.ModuleName:    &contractmeta,
+		emissiontypes.ModuleName:        &emission,
+		evmupgradetypes.ModuleName:      &evmupgrade,
+		feeburntypes.ModuleName:         &feeburn,
+		feehistorytypes.ModuleName:      &feehistory,
+		feeroutingtypes.ModuleName:      &feerouting,
+		ibchookstypes.ModuleName:        &ibchooks,
+		nameservicetypes.ModuleName:     &nameservice,
+		performancetypes.ModuleName:     &performance,
+		recoverytypes.ModuleName:        &recovery,
+		rewardsnapshottypes.ModuleName:  &rewardsnapshot,
+		selfbondtypes.ModuleName:        &selfbond,
+		tacgovtypes.ModuleName:          &tacgov,
+	}
+}
+
+const paramsPath = "/cosmos.params.v1beta1.Query/Params"
+
+// requireHeights checks every query of the node was made at height
+func requireHeights(t *testing.T, node *mockNode, height int64) {
+	t.Helper()
+	for path, heights := range node.heights {
+		for _, h := range heights {
+			require.Equal(t, height, h, path)
+		}
+	}
+}
+
+func TestLegacyParamsQuery(t *testing.T) {
+	for module, params := range tacDefaultParams() {
+		t.Run(module, func(t *testing.T) {
+			node := newMockNode(t, 10)
+			node.setParams(module, params)
+
+			bz, err := moduleParamsQueries[module](context.Background(), node.clientCtx())
+			require.NoError(t, err)
+			expected, err := json.Marshal(params)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), string(bz))
+			// one query per param, at the height of the client
+			require.Len(t, node.heights[paramsPath], len(params.ParamSetPairs()))
+			requireHeights(t, node, 0)
+		})
+	}
+
+	for _, tc := range []struct {
+		name  string
+		setup func(node *mockNode)
+		// height is the height of the client
+		height int64
+		err    string
+	}{
+		{
+			name:   "past height",
+			height: 7,
+		},
+		{
+			name:   "latest height",
+			height: 10,
+		},
+		{
+			name:   "height in the future",
+			height: 11,
+			err:    "height in the future",
+		},
+		{
+			name:  "unknown subspace",
+			setup: func(node *mockNode) { delete(node.params, emissiontypes.ModuleName) },
+			err:   "unknown subspace",
+		},
+		{
+			// a param added by a software upgrade that didn't migrate the subspace
+			name: "param not set",
+			setup: func(node *mockNode) {
+				delete(node.params[emissiontypes.ModuleName], string(emissiontypes.KeyMaxSupply))
+			},
+			err: "failed to decode emission/MaxSupply",
+		},
+		{
+			name: "invalid value",
+			setup: func(node *mockNode) {
+				node.params[emissiontypes.ModuleName][string(emissiontypes.KeyBlocksPerYear)] = `"-1"`
+			},
+			err: "failed to decode emission/BlocksPerYear",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			node := newMockNode(t, 10)
+			params := emissiontypes.DefaultParams()
+			node.setParams(emissiontypes.ModuleName, &params)
+			if tc.setup != nil {
+				tc.setup(node)
+			}
+
+			_, err := legacyParamsQuery(emissiontypes.ModuleName, &emissiontypes.Params{})(context.Background(), node.clientCtx().WithHeight(tc.height))
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			requireHeights(t, node, tc.height)
+		})
+	}
+}
+
+func TestQueryEmission(t *testing.T) {
+	supply := math.NewInt(1_000_000)
+
+	for _, tc := range []struct {
+		name      string
+		maxSupply math.Int
+		// blockProvision and remaining are the expected block provision and
+		// remaining mintable, remaining is nil if the supply isn't capped
+		blockProvision int64
+		remaining      *math.Int
+	}{
+		{
+			name:           "uncapped",
+			maxSupply:      math.ZeroInt(),
+			blockProvision: 1000,
+		},
+		{
+			name:           "below the cap",
+			maxSupply:      supply.AddRaw(10),
+			blockProvision: 10,
+			remaining:      ptr(math.NewInt(10)),
+		},
+		{
+			name:           "cap reached",
+			maxSupply:      supply,
+			blockProvision: 0,
+			remaining:      ptr(math.ZeroInt()),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			node := newMockNode(t, 100)
+			params := emissiontypes.DefaultParams()
+			params.AnnualProvisions = math.NewIntFromUint64(params.BlocksPerYear * 1000)
+			params.MaxSupply = tc.maxSupply
+			node.setParams(emissiontypes.ModuleName, &params)
+			handle(node, "/cosmos.bank.v1beta1.Query/SupplyOf", func(req *banktypes.QuerySupplyOfRequest) (proto.Message, error) {
+				return &banktypes.QuerySupplyOfResponse{Amount: sdk.NewCoin(req.Denom, supply)}, nil
+			})
+
+			emission, err := queryEmission(context.Background(), node.clientCtx())
+			require.NoError(t, err)
+			require.Equal(t, int64(100), emission.Height)
+			require.Equal(t, params.MintDenom, emission.Params.MintDenom)
+			require.True(t, supply.Equal(emission.Supply))
+			require.Equal(t, math.NewInt(tc.blockProvision).String(), emission.BlockProvision.String())
+			if tc.remaining == nil {
+				require.Nil(t, emission.RemainingMintable)
+			} else {
+				require.Equal(t, tc.remaining.String(), emission.RemainingMintable.String())
+			}
+			// the schedule is evaluated at the height of the status of the node
+			requireHeights(t, node, 100)
+		})
+	}
+
+	t.Run("no supply query", func(t *testing.T) {
+		node := newMockNode(t, 100)
+		params := emissiontypes.DefaultParams()
+		node.setParams(emissiontypes.ModuleName, &params)
+
+		_, err := queryEmission(context.Background(), node.clientCtx())
+		require.ErrorContains(t, err, "unknown query path /cosmos.bank.v1beta1.Query/SupplyOf")
+	})
+}
+
+// page returns the index of the item of the page starting at key and its
+// page response, the mocked keepers return one item per page
+func page(key []byte, total int) (int, *query.PageResponse) {
+	i := 0
+	if len(key) > 0 {
+		i = int(key[0])
+	}
+	res := &query.PageResponse{Total: uint64(total)}
+	if i+1 < total {
+		res.NextKey = []byte{byte(i + 1)}
+	}
+	return i, res
+}
+
+func TestQueryValidatorPerformance(t *testing.T) {
+	newNode := func(t *testing.T) (*mockNode, []stakingtypes.Validator) {
+		node := newMockNode(t, 50)
+		params := performancetypes.DefaultParams()
+		node.setParams(performancetypes.ModuleName, &params)
+
+		var validators []stakingtypes.Validator
+		var signingInfos []slashingtypes.ValidatorSigningInfo
+		for i, tokens := range []int64{100, 300, 200} {
+			pubKey := ed25519.GenPrivKeyFromSecret([]byte{byte(i)}).PubKey()
+			validator, err := stakingtypes.NewValidator(sdk.ValAddress(pubKey.Address()).String(), pubKey, stakingtypes.Description{Moniker: fmt.Sprintf("validator-%d", i)})
+			require.NoError(t, err)
+			validator.Tokens = math.NewInt(tokens)
+			validators = append(validators, validator)
+
+			consAddr := sdk.ConsAddress(pubKey.Address())
+			node.set(performancetypes.StoreKey, performancetypes.WindowCountersKey(consAddr), performancetypes.Counters{Blocks: 10, Missed: uint64(i), Proposed: 1}.Marshal())
+			// the last validator has no signing info yet
+			if i < 2 {
+				signingInfos = append(signingInfos, slashingtypes.ValidatorSigningInfo{Address: consAddr.String(), MissedBlocksCounter: int64(i + 5)})
+			}
+		}
+
+		handle(node, "/cosmos.staking.v1beta1.Query/Validators", func(req *stakingtypes.QueryValidatorsRequest) (proto.Message, error) {
+			i, res := page(req.Pagination.GetKey(), len(validators))
+			return &stakingtypes.QueryValidatorsResponse{Validators: validators[i : i+1], Pagination: res}, nil
+		})
+		handle(node, "/cosmos.slashing.v1beta1.Query/SigningInfos", func(req *slashingtypes.QuerySigningInfosRequest) (proto.Message, error) {
+			i, res := page(req.Pagination.GetKey(), len(signingInfos))
+			return &slashingtypes.QuerySigningInfosResponse{Info: signingInfos[i : i+1], Pagination: res}, nil
+		})
+		return node, validators
+	}
+
+	t.Run("every page", func(t *testing.T) {
+		node, _ := newNode(t)
+
+		report, err := queryValidatorPerformance(context.Background(), node.clientCtx())
+		require.NoError(t, err)
+		require.Equal(t, int64(50), report.Height)
+		require.Len(t, node.heights["/cosmos.staking.v1beta1.Query/Validators"], 3)
+		require.Len(t, node.heights["/cosmos.slashing.v1beta1.Query/SigningInfos"], 2)
+
+		// sorted by tokens
+		var monikers []string
+		for _, validator := range report.Validators {
+			monikers = append(monikers, validator.Moniker)
+		}
+		require.Equal(t, []string{"validator-1", "validator-2", "validator-0"}, monikers)
+
+		require.Equal(t, uint64(1), report.Validators[0].Missed)
+		require.Equal(t, uint64(9), report.Validators[0].Signed)
+		require.Equal(t, math.LegacyNewDecWithPrec(9, 1).String(), report.Validators[0].Uptime.String())
+		require.Equal(t, int64(6), report.Validators[0].SlashingMissed)
+		require.Equal(t, int64(0), report.Validators[1].SlashingMissed)
+		require.Equal(t, int64(5), report.Validators[2].SlashingMissed)
+	})
+
+	t.Run("at height", func(t *testing.T) {
+		node, _ := newNode(t)
+
+		report, err := queryValidatorPerformance(context.Background(), node.clientCtx().WithHeight(20))
+		require.NoError(t, err)
+		require.Equal(t, int64(20), report.Height)
+		requireHeights(t, node, 20)
+	})
+
+	t.Run("invalid counters", func(t *testing.T) {
+		node, validators := newNode(t)
+		consAddr, err := validators[2].GetConsAddr()
+		require.NoError(t, err)
+		node.set(performancetypes.StoreKey, performancetypes.WindowCountersKey(consAddr), []byte{1, 2, 3})
+
+		_, err = queryValidatorPerformance(context.Background(), node.clientCtx())
+		require.ErrorContains(t, err, "failed to query the performance of "+validators[2].OperatorAddress)
+	})
+}
+
+func TestQueryContractMetadata(t *testing.T) {
+	node := newMockNode(t, 10)
+	contract := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	metadata := contractmetatypes.Metadata{
+		Contract:   contract.Hex(),
+		Name:       "Token",
+		SourceHash: common.HexToHash("0x01").Hex(),
+		Owner:      common.HexToAddress("0x2222222222222222222222222222222222222222").Hex(),
+	}
+	bz, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	node.set(contractmetatypes.StoreKey, contractmetatypes.MetadataKey(contract), bz)
+	node.set(contractmetatypes.StoreKey, contractmetatypes.NameKey(metadata.Name), contract.Bytes())
+
+	broken := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	node.set(contractmetatypes.StoreKey, contractmetatypes.MetadataKey(broken), []byte("{"))
+	node.set(contractmetatypes.StoreKey, contractmetatypes.NameKey("broken"), broken.Bytes())
+
+	for _, tc := range []struct {
+		name           string
+		contractOrName string
+		err            string
+	}{
+		{name: "address", contractOrName: contract.Hex()},
+		{name: "lowercase address", contractOrName: strings.ToLower(contract.Hex())},
+		{name: "name", contractOrName: "Token"},
+		{name: "name in another case", contractOrName: "TOKEN"},
+		{name: "unknown name", contractOrName: "Other", err: `no contract is registered as "Other"`},
+		{name: "unknown address", contractOrName: "0x4444444444444444444444444444444444444444", err: "no metadata is registered for 0x4444444444444444444444444444444444444444"},
+		{name: "invalid metadata", contractOrName: "broken", err: "unexpected end of JSON input"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := queryContractMetadata(node.clientCtx(), tc.contractOrName)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, metadata, *res)
+		})
+	}
+
+	t.Run("height in the future", func(t *testing.T) {
+		_, err := queryContractMetadata(node.clientCtx().WithHeight(11), contract.Hex())
+		require.ErrorContains(t, err, "height in the future")
+	})
+}
+
+func TestQueryVoteDelegate(t *testing.T) {
+	node := newMockNode(t, 10)
+	delegator := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	delegate := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	broken := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	node.set(tacgovtypes.StoreKey, tacgovtypes.VoteDelegateKey(delegator), delegate.Bytes())
+	node.set(tacgovtypes.StoreKey, tacgovtypes.VoteDelegateKey(broken), []byte{1, 2, 3})
+
+	res, err := queryVoteDelegate(node.clientCtx(), delegator)
+	require.NoError(t, err)
+	require.Equal(t, delegate, *res)
+
+	res, err = queryVoteDelegate(node.clientCtx(), delegate)
+	require.NoError(t, err)
+	require.Nil(t, res, "the delegate has no delegate")
+
+	_, err = queryVoteDelegate(node.clientCtx(), broken)
+	require.ErrorContains(t, err, "invalid vote delegate of "+broken.Hex())
+}
+
+func TestQueryMempool(t *testing.T) {
+	cfg := tacsdk.MakeEncodingConfig()
+	node := newMockNode(t, 10)
+
+	// a bank send signed with a Cosmos tx
+	key, err := ethsecp256k1.GenerateKey()
+	require.NoError(t, err)
+	sender := sdk.AccAddress(key.PubKey().Address())
+	recipient := sdk.AccAddress(common.HexToAddress("0x1111111111111111111111111111111111111111").Bytes())
+	fee := sdk.NewCoins(sdk.NewInt64Coin(tacsdk.BaseDenom, 20000000000000000))
+	sendTx, err := cfg.SignTx(context.Background(), tacsdk.TestnetChainID, tacsdk.TxRequest{
+		Msgs:     []sdk.Msg{banktypes.NewMsgSend(sender, recipient, sdk.NewCoins(sdk.NewInt64Coin(tacsdk.BaseDenom, 1)))},
+		GasLimit: 200000,
+		Fee:      fee,
+		Memo:     "stuck",
+	}, tacsdk.Signer{Key: key, AccountNumber: 7, Sequence: 3})
+	require.NoError(t, err)
+
+	// a vote delegation call wrapped in a MsgEthereumTx
+	ethKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	ethSender := crypto.PubkeyToAddress(ethKey.PublicKey)
+	delegate := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	call, err := tacsdk.NewSetVoteDelegateCall(delegate)
+	require.NoError(t, err)
+	chainID := big.NewInt(2391)
+	ethTx, err := gethtypes.SignTx(call.DynamicFeeTx(chainID, 5, 100000, big.NewInt(2), big.NewInt(1)), gethtypes.LatestSignerForChainID(chainID), ethKey)
+	require.NoError(t, err)
+	msg := &evmvmtypes.MsgEthereumTx{}
+	require.NoError(t, msg.FromEthereumTx(ethTx))
+	builder := cfg.TxConfig.NewTxBuilder()
+	require.NoError(t, builder.SetMsgs(msg))
+	evmTx, err := cfg.TxConfig.TxEncoder()(builder.GetTx())
+	require.NoError(t, err)
+
+	invalidTx := cmttypes.Tx{0x01, 0x02}
+	node.unconfirmed = []cmttypes.Tx{sendTx, evmTx, invalidTx}
+
+	res, err := queryMempool(context.Background(), node.clientCtx(), nil, DefaultMempoolLimit)
+	require.NoError(t, err)
+	require.Equal(t, 3, res.Total)
+	require.Equal(t, 3, res.Fetched)
+	require.Equal(t, int64(len(sendTx)+len(evmTx)+len(invalidTx)), res.TotalBytes)
+	require.Len(t, res.Txs, 3)
+
+	send := res.Txs[0]
+	require.Equal(t, fmt.Sprintf("%X", cmttypes.Tx(sendTx).Hash()), send.Hash)
+	require.Equal(t, []string{sender.String()}, send.Senders)
+	require.Equal(t, fee, send.Fee)
+	require.Equal(t, uint64(200000), send.Gas)
+	require.Equal(t, "stuck", send.Memo)
+	require.Empty(t, send.Error)
+	require.Len(t, send.Msgs, 1)
+	require.Equal(t, "/cosmos.bank.v1beta1.MsgSend", send.Msgs[0].Type)
+	require.Contains(t, string(send.Msgs[0].Msg), recipient.String())
+	require.Nil(t, send.Msgs[0].EVM)
+
+	wrapped := res.Txs[1]
+	require.Empty(t, wrapped.Error)
+	require.Equal(t, []string{sdk.AccAddress(ethSender.Bytes()).String()}, wrapped.Senders)
+	require.Len(t, wrapped.Msgs, 1)
+	require.Equal(t, sdk.MsgTypeURL(msg), wrapped.Msgs[0].Type)
+	require.Equal(t, &MempoolEVMTx{
+		Hash:      ethTx.Hash().Hex(),
+		From:      ethSender.Hex(),
+		To:        tacgovtypes.VoteDelegationAddress().Hex(),
+		Nonce:     5,
+		Value:     "0",
+		Gas:       100000,
+		GasFeeCap: "2",
+		GasTipCap: "1",
+		Data:      hexutil.Encode(call.Data),
+		Call:      tacgovtypes.VoteDelegationCall{Method: tacgovtypes.MethodSetVoteDelegate, Delegate: delegate},
+	}, wrapped.Msgs[0].EVM)
+
+	require.Contains(t, res.Txs[2].Error, "failed to decode tx")
+	require.Empty(t, res.Txs[2].Senders)
+
+	// the sender filter takes both address formats and drops the txs that
+	// can't be decoded
+	for _, filter := range []string{ethSender.Hex(), sdk.AccAddress(ethSender.Bytes()).String()} {
+		out, err := node.run(tacMempoolCmd(), "--sender", filter)
+		require.NoError(t, err)
+		var filtered MempoolTxs
+		require.NoError(t, json.Unmarshal([]byte(out), &filtered))
+		require.Equal(t, 3, filtered.Total)
+		require.Len(t, filtered.Txs, 1)
+		require.Equal(t, wrapped.Hash, filtered.Txs[0].Hash)
+	}
+
+	// the limit applies before the filter
+	out, err := node.run(tacMempoolCmd(), "--sender", ethSender.Hex(), "--limit", "1")
+	require.NoError(t, err)
+	var limited MempoolTxs
+	require.NoError(t, json.Unmarshal([]byte(out), &limited))
+	require.Equal(t, 3, limited.Total)
+	require.Equal(t, 1, limited.Fetched)
+	require.Empty(t, limited.Txs)
+}
+
+// newBridgeNode returns a node with the bridge params, the staking params and
+// the bank balances and supplies of balances
+func newBridgeNode(t *testing.T, params bridgetypes.Params, balances map[string]math.Int) *mockNode {
+	node := newMockNode(t, 80)
+	node.setParams(bridgetypes.ModuleName, &params)
+	handle(node, "/cosmos.staking.v1beta1.Query/Params", func(*stakingtypes.QueryParamsRequest) (proto.Message, error) {
+		stakingParams := stakingtypes.DefaultParams()
+		stakingParams.BondDenom = "utac"
+		return &stakingtypes.QueryParamsResponse{Params: stakingParams}, nil
+	})
+	handle(node, "/cosmos.bank.v1beta1.Query/Balance", func(req *banktypes.QueryBalanceRequest) (proto.Message, error) {
+		balance := sdk.NewCoin(req.Denom, math.ZeroInt())
+		if amount, ok := balances[req.Address]; ok {
+			balance.Amount = amount
+		}
+		return &banktypes.QueryBalanceResponse{Balance: &balance}, nil
+	})
+	handle(node, "/cosmos.bank.v1beta1.Query/SupplyOf", func(req *banktypes.QuerySupplyOfRequest) (proto.Message, error) {
+		supply := sdk.NewCoin(req.Denom, math.ZeroInt())
+		if amount, ok := balances[req.Denom]; ok {
+			supply.Amount = amount
+		}
+		return &banktypes.QuerySupplyOfResponse{Amount: supply}, nil
+	})
+	return node
+}
+
+func TestQueryBridgeRelayers(t *testing.T) {
+	relayers := []common.Address{
+		common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		common.HexToAddress("0x3333333333333333333333333333333333333333"),
+	}
+	params := bridgetypes.DefaultParams()
+	params.Relayers = nil
+	for _, relayer := range relayers {
+		params.Relayers = append(params.Relayers, strings.ToLower(relayer.Hex()))
+	}
+	params.MinRelayerBond = math.NewInt(100)
+	params.AttestationThreshold = math.LegacyNewDecWithPrec(667, 3)
+
+	for _, tc := range []struct {
+		name     string
+		bonds    []int64
+		active   []bool
+		total    int64
+		quorum   int64
+		clientAt int64
+	}{
+		{
+			name:   "active relayers",
+			bonds:  []int64{1000, 50, 500},
+			active: []bool{true, false, true},
+			total:  1500,
+			// 0.667 * 1500 truncated, plus one
+			quorum: 1001,
+		},
+		{
+			name:   "no active relayer",
+			bonds:  []int64{0, 50, 99},
+			active: []bool{false, false, false},
+		},
+		{
+			// the relayers are always queried at the latest height
+			name:     "client at a past height",
+			bonds:    []int64{100, 100, 100},
+			active:   []bool{true, true, true},
+			total:    300,
+			quorum:   201,
+			clientAt: 20,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			node := newBridgeNode(t, params, map[string]math.Int{})
+			for i, relayer := range relayers {
+				if tc.bonds[i] == 0 {
+					continue
+				}
+				bz, err := math.NewInt(tc.bonds[i]).Marshal()
+				require.NoError(t, err)
+				node.set(bridgetypes.StoreKey, bridgetypes.BondKey(relayer), bz)
+			}
+			unbonding := common.HexToAddress("0x4444444444444444444444444444444444444444")
+			node.set(bridgetypes.StoreKey, bridgetypes.UnbondingKey(unbonding), sdk.Uint64ToBigEndian(120))
+			node.set(bridgetypes.StoreKey, bridgetypes.UnbondingQueueKey(120, unbonding), []byte{})
+
+			res, err := queryBridgeRelayers(context.Background(), node.clientCtx().WithHeight(tc.clientAt))
+			require.NoError(t, err)
+			require.Equal(t, int64(80), res.Height)
+			requireHeights(t, node, 80)
+
+			require.Len(t, res.Relayers, len(relayers))
+			for i, power := range res.Relayers {
+				require.Equal(t, relayers[i].Hex(), power.Relayer)
+				require.Equal(t, tc.bonds[i], power.Bond.Int64())
+				require.Equal(t, tc.active[i], power.Active)
+			}
+			require.Equal(t, tc.total, res.TotalPower.Int64())
+			require.Equal(t, tc.quorum, res.QuorumPower.Int64())
+			require.Equal(t, []bridgetypes.Unbonding{{Relayer: unbonding.Hex(), Height: 120}}, res.Unbondings)
+		})
+	}
+}
+
+func TestTacBridgeWithdrawalsCmd(t *testing.T) {
+	sender := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	withdrawals := []bridgetypes.Withdrawal{
+		{
+			ID:             1,
+			Sender:         sender.Hex(),
+			Recipient:      "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+			Amount:         sdk.NewInt64Coin("utac", 5),
+			Status:         bridgetypes.WithdrawalStatusQueued,
+			CompleteHeight: 90,
+		},
+		{
+			ID:             2,
+			Sender:         common.HexToAddress("0x2222222222222222222222222222222222222222").Hex(),
+			Recipient:      "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+			Amount:         sdk.NewInt64Coin("utac", 7),
+			Status:         bridgetypes.WithdrawalStatusChallenged,
+			CompleteHeight: 60,
+			Challenger:     common.HexToAddress("0x3333333333333333333333333333333333333333").Hex(),
+		},
+	}
+	params := bridgetypes.DefaultParams()
+	params.WithdrawalDelay = 30
+	node := newBridgeNode(t, params, nil)
+	for _, withdrawal := range withdrawals {
+		bz, err := json.Marshal(withdrawal)
+		require.NoError(t, err)
+		node.set(bridgetypes.StoreKey, bridgetypes.WithdrawalKey(withdrawal.ID), bz)
+		node.set(bridgetypes.StoreKey, bridgetypes.WithdrawalQueueKey(withdrawal.CompleteHeight, withdrawal.ID), []byte{})
+	}
+	node.set(bridgetypes.StoreKey, bridgetypes.NextWithdrawalIDKey, sdk.Uint64ToBigEndian(3))
+
+	for _, tc := range []struct {
+		name string
+		args []string
+		// expected is the expected output, a withdrawal or BridgeWithdrawals
+		expected any
+		err      string
+	}{
+		{
+			name:     "all",
+			expected: BridgeWithdrawals{Escrow: bridgetypes.EscrowAddress().Hex(), WithdrawalDelay: 30, Withdrawals: withdrawals},
+		},
+		{
+			name:     "by sender",
+			args:     []string{"--sender", strings.ToLower(sender.Hex())},
+			expected: BridgeWithdrawals{Escrow: bridgetypes.EscrowAddress().Hex(), WithdrawalDelay: 30, Withdrawals: withdrawals[:1]},
+		},
+		{
+			name:     "sender without withdrawals",
+			args:     []string{"--sender", "0x4444444444444444444444444444444444444444"},
+			expected: BridgeWithdrawals{Escrow: bridgetypes.EscrowAddress().Hex(), WithdrawalDelay: 30, Withdrawals: []bridgetypes.Withdrawal{}},
+		},
+		{
+			name:     "by id",
+			args:     []string{"2"},
+			expected: withdrawals[1],
+		},
+		{
+			name: "unknown id",
+			args: []string{"3"},
+			err:  "withdrawal 3 is neither queued nor challenged",
+		},
+		{
+			name: "invalid id",
+			args: []string{"first"},
+			err:  `invalid withdrawal id "first"`,
+		},
+		{
+			name: "invalid sender",
+			args: []string{"--sender", "tac1xyz"},
+			err:  `invalid sender address "tac1xyz"`,
+		},
+		{
+			name: "height in the future",
+			args: []string{"--height", "81"},
+			err:  "height in the future",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := node.run(tacBridgeWithdrawalsCmd(), tc.args...)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			expected, err := json.Marshal(tc.expected)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), out)
+		})
+	}
+}
+
+func TestTacBridgeAssetsCmd(t *testing.T) {
+	native := bridgetypes.Asset{Jetton: bridgetypes.JettonNative, Denom: "uton", TONDecimals: 9, Decimals: 18}
+	jetton := bridgetypes.Asset{Jetton: "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8", Denom: "ujusdt", TONDecimals: 6, Decimals: 18}
+	node := newBridgeNode(t, bridgetypes.DefaultParams(), map[string]math.Int{"uton": math.NewInt(1000), "ujusdt": math.NewInt(5)})
+	for _, asset := range []bridgetypes.Asset{native, jetton} {
+		bz, err := json.Marshal(asset)
+		require.NoError(t, err)
+		node.set(bridgetypes.StoreKey, bridgetypes.AssetKey(asset.Jetton), bz)
+		node.set(bridgetypes.StoreKey, bridgetypes.AssetDenomKey(asset.Denom), []byte(asset.Jetton))
+	}
+	nativeAsset := BridgeAsset{Asset: native, Supply: sdk.NewInt64Coin("uton", 1000)}
+	jettonAsset := BridgeAsset{Asset: jetton, Supply: sdk.NewInt64Coin("ujusdt", 5)}
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		expected any
+		err      string
+	}{
+		{name: "all", expected: []BridgeAsset{jettonAsset, nativeAsset}},
+		{name: "by jetton", args: []string{strings.ToUpper(jetton.Jetton)}, expected: jettonAsset},
+		{name: "native", args: []string{bridgetypes.JettonNative}, expected: nativeAsset},
+		{name: "by denom", args: []string{"ujusdt"}, expected: jettonAsset},
+		{name: "unknown", args: []string{"uatom"}, err: "no bridge asset for uatom"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := node.run(tacBridgeAssetsCmd(), tc.args...)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			expected, err := json.Marshal(tc.expected)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), out)
+		})
+	}
+}
+
+func TestTacBridgeFeeQuoteCmd(t *testing.T) {
+	params := bridgetypes.DefaultParams()
+	params.WithdrawalGas = 100_000
+	params.DepositGas = 200_000
+	params.RelayerFeeBase = math.NewInt(1000)
+	params.RelayerFeeRate = math.LegacyNewDecWithPrec(1, 3)
+
+	for _, tc := range []struct {
+		name      string
+		args      []string
+		baseFee   int64
+		noBaseFee bool
+		// gasPrice is the expected gas price of the quote
+		gasPrice int64
+		err      string
+	}{
+		{
+			name:     "withdrawal",
+			args:     []string{"withdrawal", "1000000utac"},
+			baseFee:  30,
+			gasPrice: 30,
+		},
+		{
+			name:     "deposit",
+			args:     []string{"deposit", "1000000utac"},
+			baseFee:  30,
+			gasPrice: 30,
+		},
+		{
+			name:     "base fee below the min gas price",
+			args:     []string{"withdrawal", "1000000utac"},
+			baseFee:  10,
+			gasPrice: 20,
+		},
+		{
+			name:      "no base fee",
+			args:      []string{"withdrawal", "1000000utac"},
+			baseFee:   30,
+			noBaseFee: true,
+			gasPrice:  20,
+		},
+		{
+			name: "other denom",
+			args: []string{"withdrawal", "1000000uatom"},
+			err:  "amount must be in utac",
+		},
+		{
+			name: "invalid direction",
+			args: []string{"refund", "1000000utac"},
+			err:  `direction must be "deposit" or "withdrawal"`,
+		},
+		{
+			name: "invalid amount",
+			args: []string{"withdrawal", "utac"},
+			err:  "invalid decimal coin expression",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			node := newBridgeNode(t, params, nil)
+			handle(node, "/cosmos.evm.vm.v1.Query/Params", func(*evmvmtypes.QueryParamsRequest) (proto.Message, error) {
+				evmParams := evmvmtypes.DefaultParams()
+				evmParams.EvmDenom = "utac"
+				return &evmvmtypes.QueryParamsResponse{Params: evmParams}, nil
+			})
+			handle(node, "/cosmos.evm.feemarket.v1.Query/Params", func(*evmfeemarkettypes.QueryParamsRequest) (proto.Message, error) {
+				feeMarketParams := evmfeemarkettypes.DefaultParams()
+				feeMarketParams.NoBaseFee = tc.noBaseFee
+				feeMarketParams.BaseFee = math.LegacyNewDec(tc.baseFee)
+				feeMarketParams.MinGasPrice = math.LegacyNewDec(20)
+				return &evmfeemarkettypes.QueryParamsResponse{Params: feeMarketParams}, nil
+			})
+
+			out, err := node.run(tacBridgeFeeQuoteCmd(), tc.args...)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			quote, err := params.QuoteFee(tc.args[0], math.NewInt(1000000), math.LegacyNewDec(tc.gasPrice))
+			require.NoError(t, err)
+			expected, err := json.Marshal(BridgeFeeQuote{Denom: "utac", FeeQuote: quote})
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), out)
+		})
+	}
+}
+
+func TestTacAutoCompoundCmd(t *testing.T) {
+	node := newMockNode(t, 10)
+	params := autocompoundtypes.DefaultParams()
+	node.setParams(autocompoundtypes.ModuleName, &params)
+
+	grantee := autocompoundtypes.ModuleAddress()
+	delegator := sdk.AccAddress(common.HexToAddress("0x1111111111111111111111111111111111111111").Bytes())
+	validator := sdk.ValAddress(common.HexToAddress("0x2222222222222222222222222222222222222222").Bytes())
+	expiration := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	stakeAuthorization, err := stakingtypes.NewStakeAuthorization([]sdk.ValAddress{validator}, nil, stakingtypes.AuthorizationType_AUTHORIZATION_TYPE_DELEGATE, nil)
+	require.NoError(t, err)
+	delegateGrant, err := authz.NewGrant(time.Unix(0, 0), stakeAuthorization, &expiration)
+	require.NoError(t, err)
+	// grants of other messages don't opt in
+	sendGrant, err := authz.NewGrant(time.Unix(0, 0), authz.NewGenericAuthorization(sdk.MsgTypeURL(&banktypes.MsgSend{})), nil)
+	require.NoError(t, err)
+
+	handle(node, "/cosmos.authz.v1beta1.Query/Grants", func(req *authz.QueryGrantsRequest) (proto.Message, error) {
+		res := &authz.QueryGrantsResponse{Grants: []*authz.Grant{&sendGrant}}
+		if req.Granter == delegator.String() && req.Grantee == grantee.String() {
+			res.Grants = append(res.Grants, &delegateGrant)
+		}
+		return res, nil
+	})
+	handle(node, "/cosmos.distribution.v1beta1.Query/DelegatorWithdrawAddress", func(req *distrtypes.QueryDelegatorWithdrawAddressRequest) (proto.Message, error) {
+		return &distrtypes.QueryDelegatorWithdrawAddressResponse{WithdrawAddress: req.DelegatorAddress}, nil
+	})
+	handle(node, "/cosmos.distribution.v1beta1.Query/DelegationTotalRewards", func(req *distrtypes.QueryDelegationTotalRewardsRequest) (proto.Message, error) {
+		res := &distrtypes.QueryDelegationTotalRewardsResponse{}
+		if req.DelegatorAddress == delegator.String() {
+			res.Total = sdk.NewDecCoins(sdk.NewInt64DecCoin("utac", 42))
+		}
+		return res, nil
+	})
+
+	authorization, err := node.clientCtx().Codec.MarshalInterfaceJSON(stakeAuthorization)
+	require.NoError(t, err)
+	other := sdk.AccAddress(common.HexToAddress("0x3333333333333333333333333333333333333333").Bytes())
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		expected AutoCompoundStatus
+		err      string
+	}{
+		{
+			name:     "params",
+			expected: AutoCompoundStatus{Grantee: grantee.String(), Params: params},
+		},
+		{
+			name: "opted in by hex address",
+			args: []string{common.BytesToAddress(delegator).Hex()},
+			expected: AutoCompoundStatus{
+				Grantee:         grantee.String(),
+				Params:          params,
+				Delegator:       delegator.String(),
+				Authorized:      ptr(true),
+				Authorization:   authorization,
+				Expiration:      &expiration,
+				WithdrawAddress: delegator.String(),
+				Rewards:         sdk.NewDecCoins(sdk.NewInt64DecCoin("utac", 42)),
+			},
+		},
+		{
+			name: "not opted in",
+			args: []string{other.String()},
+			expected: AutoCompoundStatus{
+				Grantee:         grantee.String(),
+				Params:          params,
+				Delegator:       other.String(),
+				Authorized:      ptr(false),
+				WithdrawAddress: other.String(),
+			},
+		},
+		{
+			name: "invalid address",
+			args: []string{"delegator"},
+			err:  "invalid address delegator",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := node.run(tacAutoCompoundCmd(), tc.args...)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			expected, err := json.Marshal(tc.expected)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), out)
+		})
+	}
+}
+
+func TestTacRewardSnapshotsCmd(t *testing.T) {
+	node := newMockNode(t, 700)
+	params := rewardsnapshottypes.Params{EpochLength: 100, Retention: 3}
+	node.setParams(rewardsnapshottypes.ModuleName, &params)
+
+	validators := []sdk.ValAddress{
+		sdk.ValAddress(common.HexToAddress("0x1111111111111111111111111111111111111111").Bytes()),
+		sdk.ValAddress(common.HexToAddress("0x2222222222222222222222222222222222222222").Bytes()),
+	}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	// the epochs 1 and 2 were pruned
+	var epochs []EpochSnapshots
+	for number := uint64(3); number <= 5; number++ {
+		epoch := EpochSnapshots{
+			Epoch: rewardsnapshottypes.Epoch{
+				Number:      number,
+				StartHeight: int64(number-1)*100 + 1,
+				StartTime:   start.Add(time.Duration(number-1) * time.Hour),
+				EndHeight:   int64(number) * 100,
+				EndTime:     start.Add(time.Duration(number)*time.Hour - time.Second),
+				TotalPower:  30,
+				Rewards:     sdk.NewDecCoins(sdk.NewInt64DecCoin("utac", 300)),
+			},
+		}
+		for i, validator := range validators {
+			snapshot := rewardsnapshottypes.ValidatorSnapshot{
+				Epoch:          number,
+				Validator:      validator.String(),
+				Power:          int64(10 * (i + 1)),
+				Tokens:         math.NewInt(int64(10 * (i + 1))),
+				CommissionRate: math.LegacyNewDecWithPrec(1, 1),
+				Rewards:        sdk.NewDecCoins(sdk.NewInt64DecCoin("utac", int64(100*(i+1)))),
+				Commission:     sdk.NewDecCoins(sdk.NewInt64DecCoin("utac", int64(10*(i+1)))),
+			}
+			bz, err := json.Marshal(snapshot)
+			require.NoError(t, err)
+			node.set(rewardsnapshottypes.StoreKey, rewardsnapshottypes.SnapshotKey(number, validator), bz)
+			epoch.Validators = append(epoch.Validators, snapshot)
+		}
+		bz, err := json.Marshal(epoch.Epoch)
+		require.NoError(t, err)
+		node.set(rewardsnapshottypes.StoreKey, rewardsnapshottypes.EpochKey(number), bz)
+		epochs = append(epochs, epoch)
+	}
+	current := rewardsnapshottypes.Epoch{Number: 6, StartHeight: 501, StartTime: start.Add(5 * time.Hour)}
+	bz, err := json.Marshal(current)
+	require.NoError(t, err)
+	node.set(rewardsnapshottypes.StoreKey, rewardsnapshottypes.CurrentEpochKey, bz)
+
+	// the epochs with the snapshot of the second validator only
+	ofValidator := make([]EpochSnapshots, len(epochs))
+	for i, epoch := range epochs {
+		ofValidator[i] = EpochSnapshots{Epoch: epoch.Epoch, Validators: epoch.Validators[1:]}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		expected RewardSnapshots
+		err      string
+	}{
+		{
+			name:     "last epochs",
+			expected: RewardSnapshots{Params: params, CurrentEpoch: &current, Epochs: epochs},
+		},
+		{
+			name:     "of a validator",
+			args:     []string{validators[1].String()},
+			expected: RewardSnapshots{Params: params, CurrentEpoch: &current, Epochs: ofValidator},
+		},
+		{
+			name:     "range",
+			args:     []string{"--from-epoch", "4", "--to-epoch", "4"},
+			expected: RewardSnapshots{Params: params, CurrentEpoch: &current, Epochs: epochs[1:2]},
+		},
+		{
+			name:     "range over pruned epochs",
+			args:     []string{"--from-epoch", "1", "--to-epoch", "3"},
+			expected: RewardSnapshots{Params: params, CurrentEpoch: &current, Epochs: epochs[:1]},
+		},
+		{
+			name:     "last epochs before an epoch",
+			args:     []string{"--to-epoch", "14"},
+			expected: RewardSnapshots{Params: params, CurrentEpoch: &current, Epochs: epochs[2:]},
+		},
+		{
+			name: "empty range",
+			args: []string{"--from-epoch", "5", "--to-epoch", "4"},
+			err:  "the epoch range 5 to 4 is empty",
+		},
+		{
+			name: "range over the max",
+			args: []string{"--from-epoch", "1", "--to-epoch", "101"},
+			err:  "the epoch range 1 to 101 is over 100 epochs",
+		},
+		{
+			name: "invalid validator",
+			args: []string{"validator"},
+			err:  `invalid validator address "validator"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := node.run(tacRewardSnapshotsCmd(), tc.args...)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			expected, err := json.Marshal(tc.expected)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), out)
+		})
+	}
+
+	// disabled before the first epoch ended
+	disabled := newMockNode(t, 10)
+	params.EpochLength = 0
+	disabled.setParams(rewardsnapshottypes.ModuleName, &params)
+	res, err := queryRewardSnapshots(context.Background(), disabled.clientCtx(), nil, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, RewardSnapshots{Params: params, Epochs: []EpochSnapshots{}}, res)
+}
+
+func TestTacAccountActivityCmd(t *testing.T) {
+	node := newMockNode(t, 10)
+	params := accountactivitytypes.Params{Enabled: true}
+	node.setParams(accountactivitytypes.ModuleName, &params)
+
+	active := sdk.AccAddress(common.HexToAddress("0x1111111111111111111111111111111111111111").Bytes())
+	activity := accountactivitytypes.Activity{
+		FirstSeenHeight: 2,
+		FirstSeenTime:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		LastSeenHeight:  9,
+		LastSeenTime:    time.Date(2025, 1, 1, 0, 0, 14, 0, time.UTC),
+		TxCount:         3,
+		GasUsed:         63000,
+	}
+	bz, err := json.Marshal(activity)
+	require.NoError(t, err)
+	node.set(accountactivitytypes.StoreKey, accountactivitytypes.ActivityKey(active), bz)
+	inactive := sdk.AccAddress(common.HexToAddress("0x2222222222222222222222222222222222222222").Bytes())
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		expected AccountActivity
+		err      string
+	}{
+		{
+			name: "by hex address",
+			args: []string{common.BytesToAddress(active).Hex()},
+			expected: AccountActivity{
+				Address:    active.String(),
+				EthAddress: common.BytesToAddress(active).Hex(),
+				Enabled:    true,
+				Activity:   &activity,
+			},
+		},
+		{
+			name:     "not seen",
+			args:     []string{inactive.String()},
+			expected: AccountActivity{Address: inactive.String(), EthAddress: common.BytesToAddress(inactive).Hex(), Enabled: true},
+		},
+		{
+			name: "invalid address",
+			args: []string{"account"},
+			err:  "invalid address account",
+		},
+		{
+			name: "no address",
+			err:  "accepts 1 arg(s), received 0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := node.run(tacAccountActivityCmd(), tc.args...)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			expected, err := json.Marshal(tc.expected)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), out)
+		})
+	}
+}
+
+func TestTacFeeHistoryCmd(t *testing.T) {
+	node := newMockNode(t, 10)
+	params := feehistorytypes.Params{Retention: 4}
+	node.setParams(feehistorytypes.ModuleName, &params)
+
+	// the blocks 1 to 6 were pruned
+	var blocks []FeeHistoryBlock
+	for height := int64(7); height <= 10; height++ {
+		fee := feehistorytypes.BlockFee{
+			Height:  height,
+			BaseFee: math.LegacyNewDec(1_000_000_000 + height),
+			GasUsed: uint64(height) * 1_000_000,
+			MaxGas:  20_000_000,
+		}
+		bz, err := json.Marshal(fee)
+		require.NoError(t, err)
+		node.set(feehistorytypes.StoreKey, feehistorytypes.BlockFeeKey(uint64(height)), bz)
+		blocks = append(blocks, FeeHistoryBlock{BlockFee: fee, GasUsedRatio: math.LegacyNewDec(height).QuoInt64(20)})
+	}
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		expected FeeHistory
+		err      string
+	}{
+		{
+			name:     "kept blocks",
+			expected: FeeHistory{Retention: 4, Blocks: blocks},
+		},
+		{
+			name:     "last blocks",
+			args:     []string{"--blocks", "2"},
+			expected: FeeHistory{Retention: 4, Blocks: blocks[2:]},
+		},
+		{
+			name: "no blocks",
+			args: []string{"--blocks", "0"},
+			err:  "--blocks must be positive",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := node.run(tacFeeHistoryCmd(), tc.args...)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			expected, err := json.Marshal(tc.expected)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), out)
+		})
+	}
+	// the ratio of the last block, 10M gas used of 20M
+	out, err := node.run(tacFeeHistoryCmd(), "--blocks", "1")
+	require.NoError(t, err)
+	require.Contains(t, out, `"gas_used_ratio": "0.500000000000000000"`)
+
+	// disabled
+	disabled := newMockNode(t, 10)
+	params.Retention = 0
+	disabled.setParams(feehistorytypes.ModuleName, &params)
+	res, err := queryFeeHistory(context.Background(), disabled.clientCtx(), DefaultFeeHistoryBlocks)
+	require.NoError(t, err)
+	require.Equal(t, FeeHistory{Blocks: []FeeHistoryBlock{}}, res)
+}
+
+func TestTacProposerTipsCmd(t *testing.T) {
+	node := newMockNode(t, 10)
+	params := feeroutingtypes.Params{EVMTipShare: math.LegacyOneDec(), CosmosSurplusShare: math.LegacyNewDecWithPrec(5, 1)}
+	node.setParams(feeroutingtypes.ModuleName, &params)
+
+	var proposers []feeroutingtypes.ProposerTips
+	for i := byte(1); i <= 2; i++ {
+		valAddr := sdk.ValAddress(bytes.Repeat([]byte{i}, 20))
+		tips := feeroutingtypes.ProposerTips{
+			Validator:  valAddr.String(),
+			Tips:       feeroutingtypes.NewTips(math.NewInt(int64(i)*1000), math.NewInt(int64(i)*10)),
+			Blocks:     uint64(i),
+			LastHeight: int64(i) + 7,
+		}
+		bz, err := json.Marshal(tips)
+		require.NoError(t, err)
+		node.set(feeroutingtypes.StoreKey, feeroutingtypes.ProposerTipsKey(valAddr), bz)
+		proposers = append(proposers, tips)
+	}
+	unknown := sdk.ValAddress(bytes.Repeat([]byte{9}, 20))
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		expected ProposerTips
+		err      string
+	}{
+		{
+			name:     "all validators",
+			expected: ProposerTips{EVMTipShare: params.EVMTipShare, CosmosSurplusShare: params.CosmosSurplusShare, Proposers: proposers},
+		},
+		{
+			name:     "validator",
+			args:     []string{proposers[1].Validator},
+			expected: ProposerTips{EVMTipShare: params.EVMTipShare, CosmosSurplusShare: params.CosmosSurplusShare, Proposers: proposers[1:]},
+		},
+		{
+			name:     "validator without tips",
+			args:     []string{unknown.String()},
+			expected: ProposerTips{EVMTipShare: params.EVMTipShare, CosmosSurplusShare: params.CosmosSurplusShare, Proposers: []feeroutingtypes.ProposerTips{}},
+		},
+		{
+			name: "invalid validator",
+			args: []string{"tac1invalid"},
+			err:  "invalid validator address",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := node.run(tacProposerTipsCmd(), tc.args...)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			expected, err := json.Marshal(tc.expected)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), out)
+		})
+	}
+	out, err := node.run(tacProposerTipsCmd(), proposers[0].Validator)
+	require.NoError(t, err)
+	require.Contains(t, out, `"evm_tips": "1000"`)
+}
+
+func TestTacProtocolRevenueCmd(t *testing.T) {
+	revenue := func(minted, burned, tips, tax int64) feeroutingtypes.Revenue {
+		return feeroutingtypes.Revenue{
+			Minted:       math.NewInt(minted),
+			Burned:       math.NewInt(burned),
+			ProposerTips: math.NewInt(tips),
+			CommunityTax: math.NewInt(tax),
+		}
+	}
+
+	// nothing was accounted for yet
+	empty := newMockNode(t, 10)
+	res, err := queryProtocolRevenue(empty.clientCtx(), 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, ProtocolRevenue{Total: NewRevenueReport(feeroutingtypes.ZeroRevenue()), Epochs: []EpochRevenueReport{}}, res)
+
+	node := newMockNode(t, 700)
+	total := revenue(10_000, 12_000, 300, 200)
+	bz, err := json.Marshal(total)
+	require.NoError(t, err)
+	node.set(feeroutingtypes.StoreKey, feeroutingtypes.TotalRevenueKey, bz)
+	// the epochs 1 and 2 were pruned
+	var epochs []EpochRevenueReport
+	for number := uint64(3); number <= 5; number++ {
+		epoch := feeroutingtypes.EpochRevenue{
+			Epoch:       number,
+			StartHeight: int64(number-1)*100 + 1,
+			EndHeight:   int64(number) * 100,
+			Revenue:     revenue(2000, int64(number)*500, 50, 40),
+		}
+		bz, err := json.Marshal(epoch)
+		require.NoError(t, err)
+		node.set(feeroutingtypes.StoreKey, feeroutingtypes.EpochRevenueKey(number), bz)
+		epochs = append(epochs, NewEpochRevenueReport(epoch))
+	}
+	current := feeroutingtypes.NewEpochRevenue(6, 501)
+	current.ProposerTips = math.NewInt(10)
+	bz, err = json.Marshal(current)
+	require.NoError(t, err)
+	node.set(feeroutingtypes.StoreKey, feeroutingtypes.EpochRevenueKey(6), bz)
+	currentReport := NewEpochRevenueReport(current)
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		expected ProtocolRevenue
+		err      string
+	}{
+		{
+			name:     "last epochs",
+			expected: ProtocolRevenue{Total: NewRevenueReport(total), CurrentEpoch: &currentReport, Epochs: epochs},
+		},
+		{
+			name:     "range",
+			args:     []string{"--from-epoch", "4", "--to-epoch", "4"},
+			expected: ProtocolRevenue{Total: NewRevenueReport(total), CurrentEpoch: &currentReport, Epochs: epochs[1:2]},
+		},
+		{
+			name:     "range over pruned epochs",
+			args:     []string{"--from-epoch", "1", "--to-epoch", "3"},
+			expected: ProtocolRevenue{Total: NewRevenueReport(total), CurrentEpoch: &currentReport, Epochs: epochs[:1]},
+		},
+		{
+			name:     "the epoch in progress isn't in the range",
+			args:     []string{"--from-epoch", "6", "--to-epoch", "6"},
+			expected: ProtocolRevenue{Total: NewRevenueReport(total), CurrentEpoch: &currentReport, Epochs: []EpochRevenueReport{}},
+		},
+		{
+			name: "empty range",
+			args: []string{"--from-epoch", "5", "--to-epoch", "4"},
+			err:  "the epoch range 5 to 4 is empty",
+		},
+		{
+			name: "range over the max",
+			args: []string{"--from-epoch", "1", "--to-epoch", "101"},
+			err:  "the epoch range 1 to 101 is over 100 epochs",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := node.run(tacProtocolRevenueCmd(), tc.args...)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			expected, err := json.Marshal(tc.expected)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), out)
+		})
+	}
+	out, err := node.run(tacProtocolRevenueCmd())
+	require.NoError(t, err)
+	require.Contains(t, out, `"net_issuance": "-2000"`, "more was burnt than minted")
+}
+
+func TestTacEstimateFeeCmd(t *testing.T) {
+	cfg := tacsdk.MakeEncodingConfig()
+	dir := t.TempDir()
+	writeTx := func(name string, bz []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, bz, 0o600))
+		return path
+	}
+
+	key, err := ethsecp256k1.GenerateKey()
+	require.NoError(t, err)
+	sender := sdk.AccAddress(key.PubKey().Address())
+	recipient := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	// a bank send written with --generate-only, without signatures
+	builder := cfg.TxConfig.NewTxBuilder()
+	require.NoError(t, builder.SetMsgs(banktypes.NewMsgSend(sender, recipient.Bytes(), sdk.NewCoins(sdk.NewInt64Coin(tacsdk.BaseDenom, 1)))))
+	bz, err := cfg.TxConfig.TxJSONEncoder()(builder.GetTx())
+	require.NoError(t, err)
+	cosmosTx := writeTx("send.json", bz)
+
+	// the call of an EVM transfer
+	evmCall := writeTx("call.json", []byte(fmt.Sprintf(`{"from":%q,"to":%q,"value":"0x1"}`, common.BytesToAddress(sender).Hex(), recipient.Hex())))
+	noSender := writeTx("no-sender.json", []byte(fmt.Sprintf(`{"to":%q,"value":"0x1"}`, recipient.Hex())))
+
+	// an EVM tx wrapped in a Cosmos tx
+	ethKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID := big.NewInt(2391)
+	ethTx, err := gethtypes.SignTx(gethtypes.NewTx(&gethtypes.LegacyTx{To: &recipient, Gas: 21000, GasPrice: big.NewInt(1)}), gethtypes.LatestSignerForChainID(chainID), ethKey)
+	require.NoError(t, err)
+	msg := &evmvmtypes.MsgEthereumTx{}
+	require.NoError(t, msg.FromEthereumTx(ethTx))
+	builder = cfg.TxConfig.NewTxBuilder()
+	require.NoError(t, builder.SetMsgs(msg))
+	bz, err = cfg.TxConfig.TxJSONEncoder()(builder.GetTx())
+	require.NoError(t, err)
+	wrappedTx := writeTx("wrapped.json", bz)
+
+	feeMarketParams := evmfeemarkettypes.DefaultParams()
+	feeMarketParams.BaseFee = math.LegacyNewDec(25_000_000_000)
+	feeMarketParams.MinGasPrice = math.LegacyNewDec(10_000_000_000)
+
+	node := newMockNode(t, 10)
+	handle(node, "/cosmos.evm.vm.v1.Query/Params", func(*evmvmtypes.QueryParamsRequest) (proto.Message, error) {
+		evmParams := evmvmtypes.DefaultParams()
+		evmParams.EvmDenom = tacsdk.BaseDenom
+		return &evmvmtypes.QueryParamsResponse{Params: evmParams}, nil
+	})
+	handle(node, "/cosmos.evm.feemarket.v1.Query/Params", func(*evmfeemarkettypes.QueryParamsRequest) (proto.Message, error) {
+		return &evmfeemarkettypes.QueryParamsResponse{Params: feeMarketParams}, nil
+	})
+	handle(node, "/cosmos.auth.v1beta1.Query/Account", func(req *authtypes.QueryAccountRequest) (proto.Message, error) {
+		require.Equal(t, sender.String(), req.Address)
+		account, err := codectypes.NewAnyWithValue(authtypes.NewBaseAccount(sender, key.PubKey(), 4, 7))
+		require.NoError(t, err)
+		return &authtypes.QueryAccountResponse{Account: account}, nil
+	})
+	handle(node, "/cosmos.tx.v1beta1.Service/Simulate", func(req *txtypes.SimulateRequest) (proto.Message, error) {
+		// the tx is simulated with the signer info of the sender
+		tx, err := cfg.TxConfig.TxDecoder()(req.TxBytes)
+		require.NoError(t, err)
+		sigs, err := tx.(authsigning.Tx).GetSignaturesV2()
+		require.NoError(t, err)
+		require.Len(t, sigs, 1)
+		require.Equal(t, uint64(7), sigs[0].Sequence)
+		require.True(t, key.PubKey().Equals(sigs[0].PubKey))
+		return &txtypes.SimulateResponse{GasInfo: &sdk.GasInfo{GasUsed: 80_000}, Result: &sdk.Result{}}, nil
+	})
+	handle(node, "/cosmos.evm.vm.v1.Query/EstimateGas", func(req *evmvmtypes.EthCallRequest) (proto.Message, error) {
+		var args evmvmtypes.TransactionArgs
+		require.NoError(t, json.Unmarshal(req.Args, &args))
+		require.Equal(t, common.BytesToAddress(sender), *args.From)
+		require.Equal(t, recipient, *args.To)
+		require.Equal(t, uint64(tacsdk.DefaultEVMGasCap), req.GasCap)
+		return &evmvmtypes.EstimateGasResponse{Gas: 21_000}, nil
+	})
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		expected tacsdk.FeeEstimate
+		err      string
+	}{
+		{
+			name:     "cosmos tx",
+			args:     []string{"--tx", cosmosTx, "--gas-adjustment", "1.5"},
+			expected: tacsdk.NewFeeEstimate(tacsdk.TxTypeCosmos, 80_000, 120_000, tacsdk.BaseDenom, feeMarketParams, tacsdk.DefaultPriorityBuffer),
+		},
+		{
+			name:     "evm call",
+			args:     []string{"--tx", evmCall, "--priority-buffer", "20"},
+			expected: tacsdk.NewFeeEstimate(tacsdk.TxTypeEVM, 21_000, 21_000, tacsdk.BaseDenom, feeMarketParams, 20),
+		},
+		{
+			name: "evm call without sender",
+			args: []string{"--tx", noSender},
+			err:  "the call has no sender",
+		},
+		{
+			name: "wrapped evm tx",
+			args: []string{"--tx", wrappedTx},
+			err:  "estimate the fee of its call instead",
+		},
+		{
+			name: "gas adjustment below 1",
+			args: []string{"--tx", cosmosTx, "--gas-adjustment", "0.5"},
+			err:  "gas adjustment must be at least 1",
+		},
+		{
+			name: "not a json object",
+			args: []string{"--tx", writeTx("invalid.json", []byte("[]"))},
+			err:  "tx file must hold a JSON object",
+		},
+		{
+			name: "no tx",
+			err:  `required flag(s) "tx" not set`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := node.run(tacEstimateFeeCmd(), tc.args...)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			expected, err := json.Marshal(tc.expected)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), out)
+		})
+	}
+
+	// 21000 gas at 30 gwei
+	out, err := node.run(tacEstimateFeeCmd(), "--tx", evmCall, "--priority-buffer", "20")
+	require.NoError(t, err)
+	require.Contains(t, out, `"amount": "0.000630000000000000"`)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestTacNameCmd(t *testing.T) {
+	node := newMockNode(t, 10)
+	owner := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	target := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	previous := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	alice := nameservicetypes.NameRecord{Name: "alice", Owner: owner.Hex(), Address: target.Hex(), Expiry: 100}
+	// the name doesn't resolve in block 11, after the queried height
+	expired := nameservicetypes.NameRecord{Name: "expired", Owner: owner.Hex(), Address: previous.Hex(), Expiry: 11}
+	for _, record := range []nameservicetypes.NameRecord{alice, expired} {
+		bz, err := json.Marshal(record)
+		require.NoError(t, err)
+		node.set(nameservicetypes.StoreKey, nameservicetypes.RecordKey(record.Name), bz)
+	}
+	node.set(nameservicetypes.StoreKey, nameservicetypes.PrimaryNameKey(target), []byte("alice"))
+	node.set(nameservicetypes.StoreKey, nameservicetypes.PrimaryNameKey(previous), []byte("expired"))
+	// the primary name of owner doesn't resolve to it
+	node.set(nameservicetypes.StoreKey, nameservicetypes.PrimaryNameKey(owner), []byte("alice"))
+
+	addresses := NameLookup{
+		Registry: nameservicetypes.RegistryAddress().Hex(),
+		Resolver: nameservicetypes.ResolverAddress().Hex(),
+	}
+	lookup := func(update func(res *NameLookup)) NameLookup {
+		res := addresses
+		res.Height = 10
+		update(&res)
+		return res
+	}
+	mapping := func(addr common.Address) *AddressMapping {
+		m := NewAddressMapping(addr.Bytes())
+		return &m
+	}
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		expected NameLookup
+		err      string
+	}{
+		{
+			name:     "addresses",
+			expected: addresses,
+		},
+		{
+			name: "name",
+			args: []string{"alice"},
+			expected: lookup(func(res *NameLookup) {
+				res.Record = &alice
+				res.Address = mapping(target)
+			}),
+		},
+		{
+			name: "name in another case",
+			args: []string{"Alice"},
+			expected: lookup(func(res *NameLookup) {
+				res.Record = &alice
+				res.Address = mapping(target)
+			}),
+		},
+		{
+			name: "expired name",
+			args: []string{"expired"},
+			expected: lookup(func(res *NameLookup) {
+				res.Record = &expired
+				res.Expired = true
+			}),
+		},
+		{
+			name: "hex address",
+			args: []string{target.Hex()},
+			expected: lookup(func(res *NameLookup) {
+				res.Address = mapping(target)
+				res.PrimaryName = "alice"
+			}),
+		},
+		{
+			name: "bech32 address",
+			args: []string{sdk.AccAddress(target.Bytes()).String()},
+			expected: lookup(func(res *NameLookup) {
+				res.Address = mapping(target)
+				res.PrimaryName = "alice"
+			}),
+		},
+		{
+			name:     "address of an expired primary name",
+			args:     []string{previous.Hex()},
+			expected: lookup(func(res *NameLookup) { res.Address = mapping(previous) }),
+		},
+		{
+			name:     "address the primary name doesn't resolve to",
+			args:     []string{owner.Hex()},
+			expected: lookup(func(res *NameLookup) { res.Address = mapping(owner) }),
+		},
+		{
+			name: "unknown name",
+			args: []string{"bob"},
+			err:  `name "bob" isn't registered`,
+		},
+		{
+			name: "invalid name",
+			args: []string{"bob_"},
+			err:  "invalid name",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := node.run(tacNameCmd(), tc.args...)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			expected, err := json.Marshal(tc.expected)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), out)
+		})
+	}
+}
+
+func TestTacTxProofCmd(t *testing.T) {
+	node := newMockNode(t, 11)
+	ethHash := "0x6c1a4b3c6bd3d1f5d6b5f0e1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5"
+	hash := bytes.Repeat([]byte{0x01}, 32)
+	header := func(height int64) cmttypes.Header {
+		return cmttypes.Header{
+			Version:            cmtversion.Consensus{Block: version.BlockProtocol},
+			ChainID:            tacsdk.MainnetChainID,
+			Height:             height,
+			Time:               time.Unix(1_750_000_000+height, 0).UTC(),
+			ValidatorsHash:     hash,
+			NextValidatorsHash: hash,
+			ConsensusHash:      hash,
+			AppHash:            hash,
+			ProposerAddress:    bytes.Repeat([]byte{0x02}, 20),
+		}
+	}
+
+	txs := cmttypes.Txs{cmttypes.Tx("eth tx"), cmttypes.Tx("bank send")}
+	results := []*abci.ExecTxResult{
+		{
+			Data:      []byte{0x0a},
+			GasWanted: 60_000,
+			GasUsed:   45_000,
+			Events:    []abci.Event{{Type: evmvmtypes.EventTypeEthereumTx, Attributes: []abci.EventAttribute{{Key: evmvmtypes.AttributeKeyEthereumTxHash, Value: common.HexToHash(ethHash).Hex()}}}},
+		},
+		{Code: 5, GasWanted: 200_000, GasUsed: 52_000},
+	}
+	node.setBlock(&cmttypes.Block{Header: header(10), Data: cmttypes.Data{Txs: txs}, LastCommit: &cmttypes.Commit{}}, &cmtrpctypes.ResultBlockResults{TxsResults: results})
+	// the latest block commits to block 10 and its results
+	latest := header(11)
+	latest.LastBlockID = node.blocks[10].BlockID
+	latest.LastResultsHash = cmttypes.NewResults(results).Hash()
+	latestTx := cmttypes.Tx("latest tx")
+	node.setBlock(&cmttypes.Block{Header: latest, Data: cmttypes.Data{Txs: cmttypes.Txs{latestTx}}, LastCommit: &cmttypes.Commit{}}, &cmtrpctypes.ResultBlockResults{TxsResults: []*abci.ExecTxResult{{}}})
+
+	for _, tc := range []struct {
+		name  string
+		hash  string
+		index uint32
+		err   string
+	}{
+		{name: "cosmos tx", hash: fmt.Sprintf("%X", txs[1].Hash()), index: 1},
+		{name: "cosmos tx in lowercase", hash: fmt.Sprintf("%x", txs[1].Hash()), index: 1},
+		{name: "eth tx", hash: ethHash, index: 0},
+		{name: "tx of the latest block", hash: fmt.Sprintf("%X", latestTx.Hash()), err: "its proof is available once block 12 is committed"},
+		{name: "unknown tx", hash: fmt.Sprintf("%X", cmttypes.Tx("unknown").Hash()), err: "not found"},
+		{name: "unknown eth tx", hash: "0x" + strings.Repeat("ab", 32), err: "not found"},
+		{name: "invalid hash", hash: "abc", err: "invalid tx hash"},
+		{name: "invalid eth hash", hash: "0x6c1a", err: "invalid eth tx hash"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := node.run(tacTxProofCmd(), tc.hash)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			var proof tacsdk.TxProof
+			require.NoError(t, json.Unmarshal([]byte(out), &proof))
+			require.NoError(t, proof.Verify())
+			require.Equal(t, int64(10), proof.Height)
+			require.Equal(t, tc.index, proof.Index)
+			require.Equal(t, txs[tc.index], proof.Tx.Data)
+			require.Equal(t, results[tc.index].GasUsed, proof.Result.GasUsed)
+			require.Equal(t, node.blocks[11].BlockID.Hash, proof.NextHeader.Hash())
+		})
+	}
+}
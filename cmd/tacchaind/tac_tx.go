@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	consensustypes "github.com/cosmos/cosmos-sdk/x/consensus/types"
+	govutils "github.com/cosmos/cosmos-sdk/x/gov/client/utils"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+
+	evmfeemarkettypes "github.com/cosmos/evm/x/feemarket/types"
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+)
+
+// TacTxCmd groups the chain specific transactions, which build the messages
+// of other modules for common operations.
+func TacTxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        "tac",
+		Short:                      "Transaction commands for TAC chain specific operations",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	cmd.AddCommand(
+		tacExitValidatorCmd(),
+		tacBondRelayerCmd(),
+		tacExecVoteCmd(),
+		tacProposeConsensusParamsCmd(),
+	)
+
+	return cmd
+}
+
+func tacExitValidatorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exit-validator",
+		Short: "Exit the validator operated by the sender, unbonding all its delegations",
+		Long: `Exit the validator operated by the sender, unbonding all its delegations.
+
+The sender sends the selfbond exit address an EVM tx calling exit(). The selfbond module
+undelegates the whole self bond of the validator, jails it and unbonds the remaining
+delegations to it at the end of the block, at most 100 a block, so the delegators get
+their tokens back after the unbonding period without having to undelegate themselves.
+Exits are only enabled while the selfbond params set a min self bond, undelegating the
+self bond with x/staking does not exit the validator.
+
+The key of the sender must be an eth_secp256k1 key, the tx pays the current gas price for
+--gas.`,
+		Example: "tacchaind tx tac exit-validator --from validator",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			gasSetting, err := flags.ParseGasSetting(cmd.Flag(flags.FlagGas).Value.String())
+			if err != nil {
+				return err
+			}
+			if gasSetting.Simulate {
+				return fmt.Errorf("--gas must be a gas limit for the EVM tx")
+			}
+
+			ctx := cmd.Context()
+			call, err := tacsdk.NewExitValidatorCall()
+			if err != nil {
+				return err
+			}
+			ethTx, err := evmCallTx(ctx, clientCtx, clientCtx.GetFromAddress(), call, gasSetting.Gas)
+			if err != nil {
+				return err
+			}
+			return broadcastEVMTx(ctx, clientCtx, ethTx)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+func tacBondRelayerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bond-relayer [amount]",
+		Short: "Add amount to the bridge relayer bond of the sender",
+		Long: `Add amount to the bridge relayer bond of the sender.
+
+The sender sends the bridge escrow address an EVM tx calling bond() with the amount as value,
+the bridge module adds it to the bond of the sender and holds it in the module account. Only
+relayers of the set bond, in the bond denom, so bond once governance added the relayer. The
+bond is sent back to the relayer once governance removed it from the relayer set and the
+unbonding blocks passed, minus what it was slashed for misbehaving.
+
+The key of the sender must be an eth_secp256k1 key, the tx pays the current gas price for
+--gas.`,
+		Example: "tacchaind tx tac bond-relayer 1000000000000000000000utac --from relayer",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			amount, err := sdk.ParseCoinNormalized(args[0])
+			if err != nil {
+				return err
+			}
+			gasSetting, err := flags.ParseGasSetting(cmd.Flag(flags.FlagGas).Value.String())
+			if err != nil {
+				return err
+			}
+			if gasSetting.Simulate {
+				return fmt.Errorf("--gas must be a gas limit for the EVM tx")
+			}
+
+			ctx := cmd.Context()
+			evmParams, err := evmvmtypes.NewQueryClient(clientCtx).Params(ctx, &evmvmtypes.QueryParamsRequest{})
+			if err != nil {
+				return err
+			}
+			if amount.Denom != evmParams.Params.EvmDenom {
+				return fmt.Errorf("the bond must be in %s, got %s", evmParams.Params.EvmDenom, amount.Denom)
+			}
+			call, err := tacsdk.NewBondRelayerCall(amount.Amount.BigInt())
+			if err != nil {
+				return err
+			}
+			ethTx, err := evmCallTx(ctx, clientCtx, clientCtx.GetFromAddress(), call, gasSetting.Gas)
+			if err != nil {
+				return err
+			}
+			return broadcastEVMTx(ctx, clientCtx, ethTx)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// evmCallTx returns the unsigned EVM tx of sender sending call with gas, at
+// the next nonce of the account and the current gas price
+func evmCallTx(ctx context.Context, clientCtx client.Context, sender sdk.AccAddress, call tacsdk.EVMCall, gas uint64) (*ethtypes.Transaction, error) {
+	chainID, err := app.EVMChainID(clientCtx.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	account, err := evmvmtypes.NewQueryClient(clientCtx).Account(ctx, &evmvmtypes.QueryAccountRequest{Address: common.BytesToAddress(sender).Hex()})
+	if err != nil {
+		return nil, err
+	}
+	feeMarketParams, err := evmfeemarkettypes.NewQueryClient(clientCtx).Params(ctx, &evmfeemarkettypes.QueryParamsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	gasPrice := bridgeGasPrice(feeMarketParams.Params).Ceil().TruncateInt().BigInt()
+	return call.DynamicFeeTx(new(big.Int).SetUint64(chainID), account.Nonce, gas, gasPrice, gasPrice), nil
+}
+
+func tacExecVoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec-vote [voter] [proposal-id] [option]",
+		Short: "Vote on a proposal on behalf of voter, which authorized the sender to vote for it",
+		Long: `Vote on a proposal on behalf of voter, which authorized the sender to vote for it.
+
+The vote is sent in an authz MsgExec, it is the vote of voter, weighted by its own
+delegations, and replaces any vote voter cast earlier. The voter authorizes the sender
+with a generic authorization of /cosmos.gov.v1.MsgVote, e.g.:
+
+  tacchaind tx authz grant [sender] generic --msg-type /cosmos.gov.v1.MsgVote --expiration [unix-time] --from [voter]
+
+and takes the authorization back with:
+
+  tacchaind tx authz revoke [sender] /cosmos.gov.v1.MsgVote --from [voter]
+
+The option is one of yes, no, no_with_veto and abstain.`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			voter, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid voter address: %w", err)
+			}
+			proposalID, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid proposal id %s: %w", args[1], err)
+			}
+			option, err := govv1.VoteOptionFromString(govutils.NormalizeVoteOption(args[2]))
+			if err != nil {
+				return err
+			}
+
+			msg := tacsdk.NewExecVoteMsg(clientCtx.GetFromAddress(), voter, proposalID, option)
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+const (
+	flagTitle     = "title"
+	flagSummary   = "summary"
+	flagDeposit   = "deposit"
+	flagExpedited = "expedited"
+
+	flagBlockMaxBytes          = "block-max-bytes"
+	flagBlockMaxGas            = "block-max-gas"
+	flagEvidenceMaxAgeBlocks   = "evidence-max-age-blocks"
+	flagEvidenceMaxAgeDuration = "evidence-max-age-duration"
+	flagEvidenceMaxBytes       = "evidence-max-bytes"
+	flagPubKeyTypes            = "pub-key-types"
+)
+
+func tacProposeConsensusParamsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "propose-consensus-params",
+		Short: "Submit a proposal changing the consensus params set by the flags",
+		Long: `Submit a proposal changing the consensus params set by the flags.
+
+x/consensus replaces the block, evidence and validator params at once, so the proposal
+holds the current params of the chain with the flags applied, and is checked like
+CometBFT checks the params before it is sent. A proposal passing after another one
+changed the params reverts the changes of the other one, check the params before voting.
+
+The evidence must fit in a block: lowering --block-max-bytes below the evidence max bytes,
+1MiB by default, requires lowering --evidence-max-bytes too. The nodes reject the txs
+larger than a block from their mempool, whatever their max-tx-bytes. The validator pubkey
+types must include the type of the keys of the current validators, e.g.:
+
+  tacchaind tx tac propose-consensus-params --evidence-max-age-blocks 200000 \
+    --evidence-max-age-duration 72h --pub-key-types ed25519,secp256k1 \
+    --title "Evidence age" --summary "Keep evidence for 72h" --deposit 10000000000000000000utac --from [key]`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			res, err := consensustypes.NewQueryClient(clientCtx).Params(cmd.Context(), &consensustypes.QueryParamsRequest{})
+			if err != nil {
+				return fmt.Errorf("failed to query the consensus params: %w", err)
+			}
+			if res.Params == nil {
+				return errors.New("the chain has no consensus params")
+			}
+			params := *res.Params
+			if err := applyConsensusParamsFlags(cmd.Flags(), &params); err != nil {
+				return err
+			}
+
+			title, _ := cmd.Flags().GetString(flagTitle)
+			summary, _ := cmd.Flags().GetString(flagSummary)
+			expedited, _ := cmd.Flags().GetBool(flagExpedited)
+			depositStr, _ := cmd.Flags().GetString(flagDeposit)
+			deposit, err := sdk.ParseCoinsNormalized(depositStr)
+			if err != nil {
+				return fmt.Errorf("invalid deposit: %w", err)
+			}
+
+			msg, err := tacsdk.NewProposalBuilder(title, summary).
+				WithDeposit(deposit).
+				Expedited(expedited).
+				UpdateConsensusParams(params).
+				Build(clientCtx.GetFromAddress())
+			if err != nil {
+				return err
+			}
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().Int64(flagBlockMaxBytes, 0, "Max size of a block in bytes")
+	cmd.Flags().Int64(flagBlockMaxGas, 0, "Max gas of a block, -1 for no limit")
+	cmd.Flags().Int64(flagEvidenceMaxAgeBlocks, 0, "Max age of evidence in blocks")
+	cmd.Flags().Duration(flagEvidenceMaxAgeDuration, 0, "Max age of evidence in time, e.g. 48h")
+	cmd.Flags().Int64(flagEvidenceMaxBytes, 0, "Max size of the evidence of a block in bytes")
+	cmd.Flags().StringSlice(flagPubKeyTypes, nil, "Comma separated pubkey types of the validators, e.g. ed25519,secp256k1")
+	cmd.Flags().String(flagTitle, "", "Title of the proposal")
+	cmd.Flags().String(flagSummary, "", "Summary of the proposal")
+	cmd.Flags().String(flagDeposit, "", "Deposit of the proposal")
+	cmd.Flags().Bool(flagExpedited, false, "Submit an expedited proposal")
+	_ = cmd.MarkFlagRequired(flagTitle)
+	_ = cmd.MarkFlagRequired(flagSummary)
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// applyConsensusParamsFlags sets the consensus params of the flags set on the
+// command line, the others keep their current value
+func applyConsensusParamsFlags(fs *pflag.FlagSet, params *cmtproto.ConsensusParams) error {
+	if params.Block == nil || params.Evidence == nil || params.Validator == nil {
+		return errors.New("the chain has incomplete consensus params")
+	}
+
+	changed := false
+	for _, flag := range []struct {
+		name  string
+		field *int64
+	}{
+		{flagBlockMaxBytes, &params.Block.MaxBytes},
+		{flagBlockMaxGas, &params.Block.MaxGas},
+		{flagEvidenceMaxAgeBlocks, &params.Evidence.MaxAgeNumBlocks},
+		{flagEvidenceMaxBytes, &params.Evidence.MaxBytes},
+	} {
+		if !fs.Changed(flag.name) {
+			continue
+		}
+		value, err := fs.GetInt64(flag.name)
+		if err != nil {
+			return err
+		}
+		*flag.field = value
+		changed = true
+	}
+	if fs.Changed(flagEvidenceMaxAgeDuration) {
+		maxAge, err := fs.GetDuration(flagEvidenceMaxAgeDuration)
+		if err != nil {
+			return err
+		}
+		params.Evidence.MaxAgeDuration = maxAge
+		changed = true
+	}
+	if fs.Changed(flagPubKeyTypes) {
+		pubKeyTypes, err := fs.GetStringSlice(flagPubKeyTypes)
+		if err != nil {
+			return err
+		}
+		params.Validator.PubKeyTypes = pubKeyTypes
+		changed = true
+	}
+
+	if !changed {
+		return errors.New("no consensus param to change, set at least one of the params flags")
+	}
+	return nil
+}
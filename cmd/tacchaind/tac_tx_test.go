@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+)
+
+func TestTacTxCmd(t *testing.T) {
+	cmd := TacTxCmd()
+
+	var names []string
+	for _, sub := range cmd.Commands() {
+		names = append(names, sub.Name())
+		require.NotNil(t, sub.Flags().Lookup(flags.FlagFrom), sub.Name())
+	}
+	require.ElementsMatch(t, []string{"exit-validator", "bond-relayer", "exec-vote", "propose-consensus-params"}, names)
+}
+
+func TestApplyConsensusParamsFlags(t *testing.T) {
+	current := cmttypes.DefaultConsensusParams().ToProto()
+
+	// the flags not set keep their current value
+	params := cmttypes.DefaultConsensusParams().ToProto()
+	cmd := tacProposeConsensusParamsCmd()
+	require.NoError(t, cmd.Flags().Parse([]string{
+		"--evidence-max-age-blocks", "200000",
+		"--evidence-max-age-duration", "72h",
+		"--pub-key-types", "ed25519,secp256k1",
+	}))
+	require.NoError(t, applyConsensusParamsFlags(cmd.Flags(), &params))
+	require.Equal(t, current.Block, params.Block)
+	require.Equal(t, int64(200000), params.Evidence.MaxAgeNumBlocks)
+	require.Equal(t, 72*time.Hour, params.Evidence.MaxAgeDuration)
+	require.Equal(t, current.Evidence.MaxBytes, params.Evidence.MaxBytes)
+	require.Equal(t, []string{"ed25519", "secp256k1"}, params.Validator.PubKeyTypes)
+
+	params = cmttypes.DefaultConsensusParams().ToProto()
+	cmd = tacProposeConsensusParamsCmd()
+	require.NoError(t, cmd.Flags().Parse([]string{"--block-max-bytes", "262144", "--evidence-max-bytes", "65536", "--block-max-gas", "-1"}))
+	require.NoError(t, applyConsensusParamsFlags(cmd.Flags(), &params))
+	require.Equal(t, int64(262144), params.Block.MaxBytes)
+	require.Equal(t, int64(-1), params.Block.MaxGas)
+	require.Equal(t, int64(65536), params.Evidence.MaxBytes)
+	require.Equal(t, current.Evidence.MaxAgeNumBlocks, params.Evidence.MaxAgeNumBlocks)
+	require.Equal(t, current.Validator, params.Validator)
+
+	// a proposal changing nothing is an error
+	params = cmttypes.DefaultConsensusParams().ToProto()
+	cmd = tacProposeConsensusParamsCmd()
+	require.NoError(t, cmd.Flags().Parse([]string{"--title", "Nothing"}))
+	require.Error(t, applyConsensusParamsFlags(cmd.Flags(), &params))
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ToolsCmd groups utilities for node operators.
+func ToolsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tools",
+		Short: "Utilities for node operators",
+	}
+
+	cmd.AddCommand(
+		AddrBookCmd(),
+		CompactDBCmd(),
+		CompareBlocksCmd(),
+		MigrateDBCmd(),
+		OpenAPICmd(),
+		PruneBlockResultsCmd(),
+		RecordBlocksCmd(),
+		StateReportCmd(),
+		UpgradeDryRunCmd(),
+	)
+
+	return cmd
+}
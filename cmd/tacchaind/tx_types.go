@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+
+	rpcclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/server"
+
+	evmrpc "github.com/cosmos/evm/rpc"
+	evmbackend "github.com/cosmos/evm/rpc/backend"
+	evmtypes "github.com/cosmos/evm/types"
+)
+
+// TxTypesNamespace is the JSON-RPC namespace checking the type of the raw txs
+// sent with eth_sendRawTransaction. It is in the default api list of the
+// json-rpc section, after "eth" like LogIndexNamespace.
+const TxTypesNamespace = "txtypes"
+
+func init() {
+	if err := evmrpc.RegisterAPINamespace(TxTypesNamespace, newTxTypesAPIs); err != nil {
+		panic(err)
+	}
+}
+
+func newTxTypesAPIs(
+	ctx *server.Context,
+	clientCtx client.Context,
+	_ *rpcclient.WSClient,
+	allowUnprotectedTxs bool,
+	indexer evmtypes.EVMTxIndexer,
+) []rpc.API {
+	return []rpc.API{{
+		Namespace: "eth",
+		Service: &txTypesAPI{
+			backend: evmbackend.NewBackend(ctx, ctx.Logger, clientCtx, allowUnprotectedTxs, indexer),
+		},
+	}}
+}
+
+// txTypesAPI rejects the raw txs of the types the chain can't execute before
+// they are converted to Cosmos txs, which only hold legacy, access list and
+// dynamic fee txs.
+type txTypesAPI struct {
+	backend *evmbackend.Backend
+}
+
+// SendRawTransaction replaces eth_sendRawTransaction
+func (api *txTypesAPI) SendRawTransaction(data hexutil.Bytes) (common.Hash, error) {
+	if err := checkRawTxType(data); err != nil {
+		return common.Hash{}, err
+	}
+	return api.backend.SendRawTransaction(data)
+}
+
+// checkRawTxType fails if the raw tx isn't a legacy, access list (EIP-2930) or
+// dynamic fee (EIP-1559) tx
+func checkRawTxType(data []byte) error {
+	tx := &ethtypes.Transaction{}
+	if err := tx.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("failed to decode tx: %w", err)
+	}
+
+	switch tx.Type() {
+	case ethtypes.LegacyTxType, ethtypes.AccessListTxType, ethtypes.DynamicFeeTxType:
+		return nil
+	case ethtypes.BlobTxType:
+		return fmt.Errorf("blob txs (EIP-4844, type 0x%02x) are not supported, the chain has no blob space: send the calldata in a dynamic fee tx (type 0x%02x)",
+			ethtypes.BlobTxType, ethtypes.DynamicFeeTxType)
+	default:
+		return fmt.Errorf("txs of type 0x%02x are not supported", tx.Type())
+	}
+}
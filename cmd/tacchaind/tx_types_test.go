@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRawTxType(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID := big.NewInt(2391)
+	to := common.HexToAddress("0xdead")
+	signer := ethtypes.LatestSignerForChainID(chainID)
+
+	raw := func(txData ethtypes.TxData) []byte {
+		tx, err := ethtypes.SignNewTx(key, signer, txData)
+		require.NoError(t, err)
+		bz, err := tx.MarshalBinary()
+		require.NoError(t, err)
+		return bz
+	}
+
+	accessList := ethtypes.AccessList{{Address: to, StorageKeys: []common.Hash{{0x01}}}}
+	for name, txData := range map[string]ethtypes.TxData{
+		"legacy":      &ethtypes.LegacyTx{To: &to, Gas: 21000, GasPrice: big.NewInt(1)},
+		"access list": &ethtypes.AccessListTx{ChainID: chainID, To: &to, Gas: 30000, GasPrice: big.NewInt(1), AccessList: accessList},
+		"dynamic fee": &ethtypes.DynamicFeeTx{ChainID: chainID, To: &to, Gas: 30000, GasFeeCap: big.NewInt(2), GasTipCap: big.NewInt(1), AccessList: accessList},
+	} {
+		require.NoError(t, checkRawTxType(raw(txData)), name)
+	}
+
+	blob := raw(&ethtypes.BlobTx{
+		ChainID:    uint256.MustFromBig(chainID),
+		To:         to,
+		Gas:        21000,
+		GasFeeCap:  uint256.NewInt(2),
+		GasTipCap:  uint256.NewInt(1),
+		BlobFeeCap: uint256.NewInt(1),
+		BlobHashes: []common.Hash{{0x01}},
+	})
+	err = checkRawTxType(blob)
+	require.ErrorContains(t, err, "blob txs (EIP-4844, type 0x03) are not supported")
+
+	err = checkRawTxType([]byte{0x05, 0xc0})
+	require.ErrorContains(t, err, "failed to decode tx")
+	err = checkRawTxType(nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"cosmossdk.io/store/prefix"
+	storetypes "cosmossdk.io/store/types"
+	upgradetypes "cosmossdk.io/x/upgrade/types"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	genutiltypes "github.com/cosmos/cosmos-sdk/x/genutil/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/app/upgrades"
+)
+
+const (
+	flagGenesis        = "genesis"
+	flagUpgrade        = "upgrade"
+	flagModuleVersions = "module-versions"
+
+	// DefaultUpgradeDryRunTop is the default number of keys listed per store
+	// in an upgrade dry run report
+	DefaultUpgradeDryRunTop = 10
+)
+
+// UpgradeDryRunReport is the outcome of an upgrade handler run on an exported
+// state: the time it took, the module migrations and the keys it changed per
+// module store.
+type UpgradeDryRunReport struct {
+	Upgrade    string            `json:"upgrade"`
+	ChainID    string            `json:"chain_id"`
+	Height     int64             `json:"height"`
+	Duration   string            `json:"duration"`
+	Migrations []ModuleMigration `json:"migrations"`
+	Stores     []StoreDiff       `json:"stores"`
+}
+
+// ModuleMigration is the consensus version change of a module, a From of 0
+// is a module added by the upgrade.
+type ModuleMigration struct {
+	Module string `json:"module"`
+	From   uint64 `json:"from"`
+	To     uint64 `json:"to"`
+}
+
+// StoreDiff is the keys of a module store the upgrade added, removed and
+// changed, with the first of them in key order.
+type StoreDiff struct {
+	Store   string    `json:"store"`
+	Added   int64     `json:"added"`
+	Removed int64     `json:"removed"`
+	Changed int64     `json:"changed"`
+	Keys    []KeyDiff `json:"keys"`
+}
+
+// KeyDiff is a key of a store the upgrade wrote, with the size of its value
+// before and after the upgrade.
+type KeyDiff struct {
+	Key    string `json:"key"`
+	Change string `json:"change"`
+	Before int64  `json:"before_bytes"`
+	After  int64  `json:"after_bytes"`
+}
+
+// UpgradeDryRunCmd runs an upgrade handler on an exported state, so the
+// migrations of a release are rehearsed before its upgrade proposal.
+func UpgradeDryRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade-dry-run",
+		Short: "Run an upgrade handler and the module migrations on an exported state and report the changes",
+		Long: fmt.Sprintf(`Run an upgrade handler and the module migrations on an exported state and report the changes.
+
+The state of --genesis, the output of tacchaind export on a node of the network, is
+loaded in memory and the upgrade handler of --upgrade runs on it, as it would at the
+upgrade height. The report lists the time the handler took, the consensus versions
+of the modules it migrated and the keys it added, removed and changed per module
+store, the first --top of them for each store. Nothing is written to disk.
+
+The modules migrate from the consensus versions of --module-versions, the output of
+tacchaind q upgrade module-versions --output json on a node running the current
+release. Without it, they migrate from the versions of this binary and the modules
+whose stores the upgrade adds are initialized from their default genesis.
+
+The upgrades known to this binary are %s.`, strings.Join(upgradeNames(), ", ")),
+		Example: "tacchaind tools upgrade-dry-run --genesis export.json --upgrade v0.0.12 --module-versions versions.json",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			genesisFile, _ := cmd.Flags().GetString(flagGenesis)
+			name, _ := cmd.Flags().GetString(flagUpgrade)
+			versionsFile, _ := cmd.Flags().GetString(flagModuleVersions)
+			top, _ := cmd.Flags().GetInt(flagTop)
+			output, _ := cmd.Flags().GetString(flags.FlagOutput)
+
+			upgrade, err := findUpgrade(name)
+			if err != nil {
+				return err
+			}
+			appGenesis, err := genutiltypes.AppGenesisFromFile(genesisFile)
+			if err != nil {
+				return err
+			}
+
+			home, err := os.MkdirTemp("", "upgrade-dry-run")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(home)
+			// the options of the node are left out, the dry run takes no backup
+			// and keeps no index
+			appOpts := viper.New()
+			appOpts.Set(flags.FlagHome, home)
+			tacApp := app.NewTacChainApp(
+				serverCtx.Logger,
+				dbm.NewMemDB(),
+				nil,
+				true,
+				0,
+				appOpts,
+				app.SetupEvmConfig,
+				baseapp.SetChainID(appGenesis.ChainID),
+			)
+			defer tacApp.Close()
+
+			if err := initChainFromGenesis(tacApp, appGenesis); err != nil {
+				return err
+			}
+			ctx := tacApp.NewContextLegacy(false, cmtproto.Header{
+				ChainID: appGenesis.ChainID,
+				Height:  appGenesis.InitialHeight,
+				Time:    appGenesis.GenesisTime,
+			})
+
+			fromVM := defaultFromVersions(tacApp.ModuleManager.GetVersionMap(), upgrade)
+			if versionsFile != "" {
+				if fromVM, err = readModuleVersions(tacApp, versionsFile); err != nil {
+					return err
+				}
+			}
+
+			report, err := dryRunUpgrade(ctx, tacApp, upgrade, fromVM, top)
+			if err != nil {
+				return err
+			}
+
+			if output == flags.OutputFormatJSON {
+				bz, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				cmd.Println(string(bz))
+				return nil
+			}
+			printUpgradeDryRunReport(cmd, report)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagGenesis, "", "Genesis file of the exported state")
+	cmd.Flags().String(flagUpgrade, "", "Name of the upgrade to run")
+	cmd.Flags().String(flagModuleVersions, "", "JSON file of the module versions of the network, the output of q upgrade module-versions")
+	cmd.Flags().Int(flagTop, DefaultUpgradeDryRunTop, "Number of changed keys listed per store")
+	cmd.Flags().StringP(flags.FlagOutput, "o", flags.OutputFormatText, "Output format (text|json)")
+	_ = cmd.MarkFlagRequired(flagGenesis)
+	_ = cmd.MarkFlagRequired(flagUpgrade)
+
+	return cmd
+}
+
+func upgradeNames() []string {
+	names := make([]string, 0, len(app.Upgrades))
+	for _, upgrade := range app.Upgrades {
+		names = append(names, upgrade.UpgradeName)
+	}
+	return names
+}
+
+// findUpgrade returns the upgrade of this binary named name
+func findUpgrade(name string) (upgrades.Upgrade, error) {
+	for _, upgrade := range app.Upgrades {
+		if upgrade.UpgradeName == name {
+			return upgrade, nil
+		}
+	}
+	return upgrades.Upgrade{}, fmt.Errorf("unknown upgrade %q, this binary knows %s", name, strings.Join(upgradeNames(), ", "))
+}
+
+// initChainFromGenesis loads the state of appGenesis in tacApp, the state
+// stays in the block state of the app, uncommitted
+func initChainFromGenesis(tacApp *app.TacChainApp, appGenesis *genutiltypes.AppGenesis) error {
+	req := &abci.RequestInitChain{
+		ChainId:       appGenesis.ChainID,
+		Time:          appGenesis.GenesisTime,
+		InitialHeight: appGenesis.InitialHeight,
+		AppStateBytes: appGenesis.AppState,
+	}
+	if appGenesis.Consensus != nil && appGenesis.Consensus.Params != nil {
+		params := appGenesis.Consensus.Params.ToProto()
+		req.ConsensusParams = &params
+	}
+	if _, err := tacApp.InitChain(req); err != nil {
+		return fmt.Errorf("failed to load the genesis state: %w", err)
+	}
+	return nil
+}
+
+// defaultFromVersions returns the versions of the modules of the binary,
+// without the modules whose stores the upgrade adds
+func defaultFromVersions(current module.VersionMap, upgrade upgrades.Upgrade) module.VersionMap {
+	fromVM := make(module.VersionMap, len(current))
+	for name, version := range current {
+		fromVM[name] = version
+	}
+	for _, name := range upgrade.StoreUpgrades.Added {
+		delete(fromVM, name)
+	}
+	return fromVM
+}
+
+// readModuleVersions reads the module versions of a network from the output
+// of q upgrade module-versions
+func readModuleVersions(tacApp *app.TacChainApp, file string) (module.VersionMap, error) {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var res upgradetypes.QueryModuleVersionsResponse
+	if err := tacApp.AppCodec().UnmarshalJSON(bz, &res); err != nil {
+		return nil, fmt.Errorf("failed to read the module versions of %s: %w", file, err)
+	}
+	if len(res.ModuleVersions) == 0 {
+		return nil, fmt.Errorf("no module versions in %s", file)
+	}
+	fromVM := make(module.VersionMap, len(res.ModuleVersions))
+	for _, version := range res.ModuleVersions {
+		fromVM[version.Name] = version.Version
+	}
+	return fromVM, nil
+}
+
+// dryRunUpgrade runs the handler of upgrade on the state of ctx, with the
+// modules at the versions of fromVM, and reports the changes. The state of ctx
+// is left untouched.
+func dryRunUpgrade(ctx sdk.Context, tacApp *app.TacChainApp, upgrade upgrades.Upgrade, fromVM module.VersionMap, top int) (UpgradeDryRunReport, error) {
+	report := UpgradeDryRunReport{
+		Upgrade:    upgrade.UpgradeName,
+		ChainID:    ctx.ChainID(),
+		Height:     ctx.BlockHeight(),
+		Migrations: []ModuleMigration{},
+		Stores:     []StoreDiff{},
+	}
+	if !tacApp.UpgradeKeeper.HasHandler(upgrade.UpgradeName) {
+		return report, fmt.Errorf("no handler registered for upgrade %q", upgrade.UpgradeName)
+	}
+	doneHeight, err := tacApp.UpgradeKeeper.GetDoneHeight(ctx, upgrade.UpgradeName)
+	if err != nil {
+		return report, err
+	}
+	if doneHeight > 0 {
+		return report, fmt.Errorf("upgrade %q was already applied at height %d in this state", upgrade.UpgradeName, doneHeight)
+	}
+
+	// the state before the upgrade, with the module versions of the network
+	before := ctx.MultiStore().CacheMultiStore()
+	if err := setModuleVersions(ctx.WithMultiStore(before), tacApp, fromVM); err != nil {
+		return report, err
+	}
+	after := before.CacheMultiStore()
+	upgradeCtx := ctx.WithMultiStore(after)
+
+	start := time.Now()
+	if err := tacApp.UpgradeKeeper.ApplyUpgrade(upgradeCtx, upgradetypes.Plan{Name: upgrade.UpgradeName, Height: ctx.BlockHeight()}); err != nil {
+		return report, fmt.Errorf("upgrade %q failed: %w", upgrade.UpgradeName, err)
+	}
+	report.Duration = time.Since(start).String()
+
+	toVM, err := tacApp.UpgradeKeeper.GetModuleVersionMap(upgradeCtx)
+	if err != nil {
+		return report, err
+	}
+	report.Migrations = moduleMigrations(fromVM, toVM)
+
+	for _, key := range tacApp.GetStoreKeys() {
+		diff, err := diffStores(before.GetKVStore(key), after.GetKVStore(key), top)
+		if err != nil {
+			return report, fmt.Errorf("failed to compare the store of %s: %w", key.Name(), err)
+		}
+		if diff.Added+diff.Removed+diff.Changed > 0 {
+			diff.Store = key.Name()
+			report.Stores = append(report.Stores, diff)
+		}
+	}
+	return report, nil
+}
+
+// setModuleVersions replaces the module versions of the state of ctx with
+// versions, the versions of the modules missing from it are removed
+func setModuleVersions(ctx sdk.Context, tacApp *app.TacChainApp, versions module.VersionMap) error {
+	store := prefix.NewStore(ctx.KVStore(tacApp.GetKey(upgradetypes.StoreKey)), []byte{upgradetypes.VersionMapByte})
+	it := store.Iterator(nil, nil)
+	var keys [][]byte
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	if err := it.Close(); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		store.Delete(key)
+	}
+	return tacApp.UpgradeKeeper.SetModuleVersionMap(ctx, versions)
+}
+
+// moduleMigrations returns the modules whose version differs between fromVM
+// and toVM, by name
+func moduleMigrations(fromVM, toVM module.VersionMap) []ModuleMigration {
+	migrations := []ModuleMigration{}
+	for name, to := range toVM {
+		if from := fromVM[name]; from != to {
+			migrations = append(migrations, ModuleMigration{Module: name, From: from, To: to})
+		}
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Module < migrations[j].Module
+	})
+	return migrations
+}
+
+// diffStores walks before and after in key order and counts the keys added,
+// removed and changed, listing the first top of them
+func diffStores(before, after storetypes.KVStore, top int) (StoreDiff, error) {
+	diff := StoreDiff{Keys: []KeyDiff{}}
+	record := func(key []byte, change string, beforeValue, afterValue []byte) {
+		switch change {
+		case "added":
+			diff.Added++
+		case "removed":
+			diff.Removed++
+		default:
+			diff.Changed++
+		}
+		if len(diff.Keys) < top {
+			diff.Keys = append(diff.Keys, KeyDiff{
+				Key:    hex.EncodeToString(key),
+				Change: change,
+				Before: int64(len(beforeValue)),
+				After:  int64(len(afterValue)),
+			})
+		}
+	}
+
+	beforeIt, afterIt := before.Iterator(nil, nil), after.Iterator(nil, nil)
+	defer beforeIt.Close()
+	defer afterIt.Close()
+	for beforeIt.Valid() || afterIt.Valid() {
+		cmp := 0
+		switch {
+		case !beforeIt.Valid():
+			cmp = 1
+		case !afterIt.Valid():
+			cmp = -1
+		default:
+			cmp = bytes.Compare(beforeIt.Key(), afterIt.Key())
+		}
+
+		switch {
+		case cmp < 0:
+			record(beforeIt.Key(), "removed", beforeIt.Value(), nil)
+			beforeIt.Next()
+		case cmp > 0:
+			record(afterIt.Key(), "added", nil, afterIt.Value())
+			afterIt.Next()
+		default:
+			if !bytes.Equal(beforeIt.Value(), afterIt.Value()) {
+				record(afterIt.Key(), "changed", beforeIt.Value(), afterIt.Value())
+			}
+			beforeIt.Next()
+			afterIt.Next()
+		}
+	}
+	if err := beforeIt.Error(); err != nil {
+		return diff, err
+	}
+	return diff, afterIt.Error()
+}
+
+func printUpgradeDryRunReport(cmd *cobra.Command, report UpgradeDryRunReport) {
+	cmd.Printf("upgrade: %s chain: %s height: %d duration: %s\n", report.Upgrade, report.ChainID, report.Height, report.Duration)
+	if len(report.Migrations) > 0 {
+		cmd.Println("\nmigrations:")
+	}
+	for _, migration := range report.Migrations {
+		if migration.From == 0 {
+			cmd.Printf("  %s added at version %d\n", migration.Module, migration.To)
+			continue
+		}
+		cmd.Printf("  %s %d -> %d\n", migration.Module, migration.From, migration.To)
+	}
+	for _, store := range report.Stores {
+		cmd.Printf("\n%s added: %d removed: %d changed: %d\n", store.Store, store.Added, store.Removed, store.Changed)
+		for _, key := range store.Keys {
+			cmd.Printf("  %s 0x%s bytes: %d -> %d\n", key.Change, key.Key, key.Before, key.After)
+		}
+	}
+	if len(report.Stores) == 0 {
+		cmd.Println("\nno store changed")
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	"cosmossdk.io/store/dbadapter"
+	storetypes "cosmossdk.io/store/types"
+	upgradetypes "cosmossdk.io/x/upgrade/types"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/app/upgrades"
+	recoverytypes "github.com/Asphere-xyz/tacchain/x/recovery/types"
+)
+
+func TestDiffStores(t *testing.T) {
+	before, after := dbadapter.Store{DB: dbm.NewMemDB()}, dbadapter.Store{DB: dbm.NewMemDB()}
+	before.Set([]byte{1}, []byte("removed"))
+	before.Set([]byte{2}, []byte("same"))
+	before.Set([]byte{3}, []byte("old"))
+	before.Set([]byte{5}, []byte("removed last"))
+	after.Set([]byte{0}, []byte("added first"))
+	after.Set([]byte{2}, []byte("same"))
+	after.Set([]byte{3}, []byte("new value"))
+	after.Set([]byte{4}, []byte("added"))
+
+	diff, err := diffStores(before, after, 3)
+	require.NoError(t, err)
+	require.Equal(t, StoreDiff{
+		Added:   2,
+		Removed: 2,
+		Changed: 1,
+		Keys: []KeyDiff{
+			{Key: "00", Change: "added", After: 11},
+			{Key: "01", Change: "removed", Before: 7},
+			{Key: "03", Change: "changed", Before: 3, After: 9},
+		},
+	}, diff)
+
+	diff, err = diffStores(before, before, 3)
+	require.NoError(t, err)
+	require.Equal(t, StoreDiff{Keys: []KeyDiff{}}, diff)
+}
+
+func TestDryRunUpgrade(t *testing.T) {
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.AppOptionsMap{flags.FlagHome: t.TempDir()},
+	})
+	ctx := tacApp.NewContextLegacy(false, cmtproto.Header{ChainID: app.DefaultChainID, Height: 1})
+	bankStore := func(ctx context.Context) storetypes.KVStore {
+		return sdk.UnwrapSDKContext(ctx).KVStore(tacApp.GetKey(banktypes.StoreKey))
+	}
+	bankStore(ctx).Set([]byte{0xff, 1}, []byte("removed"))
+	bankStore(ctx).Set([]byte{0xff, 2}, []byte("old"))
+
+	// an upgrade adding the recovery module and rewriting keys of x/bank
+	upgrade := upgrades.Upgrade{
+		UpgradeName:   "v99.0.0",
+		StoreUpgrades: storetypes.StoreUpgrades{Added: []string{recoverytypes.StoreKey}},
+	}
+	tacApp.UpgradeKeeper.SetUpgradeHandler(upgrade.UpgradeName, func(ctx context.Context, _ upgradetypes.Plan, fromVM module.VersionMap) (module.VersionMap, error) {
+		versionMap, err := tacApp.ModuleManager.RunMigrations(ctx, tacApp.Configurator(), fromVM)
+		if err != nil {
+			return nil, err
+		}
+		bankStore(ctx).Delete([]byte{0xff, 1})
+		bankStore(ctx).Set([]byte{0xff, 2}, []byte("new value"))
+		bankStore(ctx).Set([]byte{0xff, 3}, []byte("added"))
+		return versionMap, nil
+	})
+
+	fromVM := defaultFromVersions(tacApp.ModuleManager.GetVersionMap(), upgrade)
+	require.NotContains(t, fromVM, recoverytypes.ModuleName)
+	report, err := dryRunUpgrade(ctx, tacApp, upgrade, fromVM, 10)
+	require.NoError(t, err)
+	require.Equal(t, "v99.0.0", report.Upgrade)
+	require.Equal(t, app.DefaultChainID, report.ChainID)
+	require.EqualValues(t, 1, report.Height)
+	require.NotEmpty(t, report.Duration)
+	require.Equal(t, []ModuleMigration{{Module: recoverytypes.ModuleName, From: 0, To: 1}}, report.Migrations)
+
+	stores := map[string]StoreDiff{}
+	for _, store := range report.Stores {
+		stores[store.Store] = store
+	}
+	require.Equal(t, StoreDiff{
+		Store:   banktypes.StoreKey,
+		Added:   1,
+		Removed: 1,
+		Changed: 1,
+		Keys: []KeyDiff{
+			{Key: "ff01", Change: "removed", Before: 7},
+			{Key: "ff02", Change: "changed", Before: 3, After: 9},
+			{Key: "ff03", Change: "added", After: 5},
+		},
+	}, stores[banktypes.StoreKey])
+	// the version of recovery and the upgrade done
+	require.Contains(t, stores, upgradetypes.StoreKey)
+
+	// the state is left untouched
+	require.Equal(t, []byte("removed"), bankStore(ctx).Get([]byte{0xff, 1}))
+	doneHeight, err := tacApp.UpgradeKeeper.GetDoneHeight(ctx, upgrade.UpgradeName)
+	require.NoError(t, err)
+	require.Zero(t, doneHeight)
+
+	_, err = dryRunUpgrade(ctx, tacApp, upgrades.Upgrade{UpgradeName: "v98.0.0"}, fromVM, 10)
+	require.ErrorContains(t, err, `no handler registered for upgrade "v98.0.0"`)
+
+	require.NoError(t, tacApp.UpgradeKeeper.ApplyUpgrade(ctx, upgradetypes.Plan{Name: upgrade.UpgradeName, Height: 1}))
+	_, err = dryRunUpgrade(ctx, tacApp, upgrade, defaultFromVersions(tacApp.ModuleManager.GetVersionMap(), upgrade), 10)
+	require.ErrorContains(t, err, `upgrade "v99.0.0" was already applied at height 1 in this state`)
+}
+
+func TestReadModuleVersions(t *testing.T) {
+	tacApp := app.NewTacChainAppWithCustomOptions(t, true, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.AppOptionsMap{flags.FlagHome: t.TempDir()},
+	})
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "versions.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"module_versions":[{"name":"bank","version":"4"},{"name":"recovery","version":"1"}]}`), 0o600))
+	versions, err := readModuleVersions(tacApp, file)
+	require.NoError(t, err)
+	require.Equal(t, module.VersionMap{"bank": 4, "recovery": 1}, versions)
+
+	require.NoError(t, os.WriteFile(file, []byte(`{"module_versions":[]}`), 0o600))
+	_, err = readModuleVersions(tacApp, file)
+	require.ErrorContains(t, err, "no module versions")
+
+	require.NoError(t, os.WriteFile(file, []byte(`{"versions":`), 0o600))
+	_, err = readModuleVersions(tacApp, file)
+	require.ErrorContains(t, err, "failed to read the module versions")
+}
+
+func TestFindUpgrade(t *testing.T) {
+	latest := app.Upgrades[len(app.Upgrades)-1]
+	upgrade, err := findUpgrade(latest.UpgradeName)
+	require.NoError(t, err)
+	require.Equal(t, latest.UpgradeName, upgrade.UpgradeName)
+
+	_, err = findUpgrade("v99.0.0")
+	require.ErrorContains(t, err, `unknown upgrade "v99.0.0", this binary knows`)
+	require.ErrorContains(t, err, latest.UpgradeName)
+}
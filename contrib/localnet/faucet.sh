@@ -0,0 +1,13 @@
+#!/bin/bash
+
+CHAIN_ID=${CHAIN_ID:-tacchain_2391-1}
+TACCHAIND=${TACCHAIND:-$(which tacchaind)}
+HOMEDIR=${HOMEDIR:-$HOME/.tacchaind}
+KEYRING_BACKEND=${KEYRING_BACKEND:-test}
+FAUCET_KEY=${FAUCET_KEY:-validator}
+FAUCET_LISTEN_ADDR=${FAUCET_LISTEN_ADDR:-127.0.0.1:8000}
+FAUCET_AMOUNT=${FAUCET_AMOUNT:-10000000000000000000utac}
+FAUCET_COOLDOWN=${FAUCET_COOLDOWN:-1m}
+MIN_GAS_PRICE=${MIN_GAS_PRICE:-25000000000}
+
+$TACCHAIND faucet $FAUCET_KEY --listen-addr $FAUCET_LISTEN_ADDR --amount $FAUCET_AMOUNT --cooldown $FAUCET_COOLDOWN --gas 200000 --gas-prices ${MIN_GAS_PRICE}utac --chain-id $CHAIN_ID --keyring-backend $KEYRING_BACKEND --home $HOMEDIR
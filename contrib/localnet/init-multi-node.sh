@@ -119,6 +119,8 @@ cp $HOMEDIR/gentxs/* "$HOMEDIR/node0/config/gentx/"
 # clear gentx in genesis because we already collect in init.sh, so recollect here instead changing the original script
 jq '.app_state.genutil.gen_txs = []' "$HOMEDIR/node0/config/genesis.json" > "$HOMEDIR/node0/config/genesis_tmp.json" && mv "$HOMEDIR/node0/config/genesis_tmp.json" "$HOMEDIR/node0/config/genesis.json"
 
+# check the gentxs of all validators against the genesis before collecting them
+$TACCHAIND genesis validate-gentxs --home $HOMEDIR/node0 || exit 1
 $TACCHAIND genesis collect-gentxs --home $HOMEDIR/node0
 
 # copy genesis to main directory for reference
@@ -24,6 +24,8 @@ GOAL_BONDED=${GOAL_BONDED:-0.6}
 SLASH_DOWNTIME_PENALTY=${SLASH_DOWNTIME_PENALTY:-0.001}
 SLASH_SIGNED_BLOCKS_WINDOW=${SLASH_SIGNED_BLOCKS_WINDOW:-21600}
 MAX_VALIDATORS=${MAX_VALIDATORS:-14}
+# extra flags of tacchaind init, e.g. the --sentry or --validator-behind-sentry presets
+INIT_FLAGS=${INIT_FLAGS:-}
 
 # ports
 RPC_PORT=${RPC_PORT:-26657}
@@ -49,12 +51,12 @@ fi
 rm -rf $HOMEDIR
 
 # set cli options default values
-$TACCHAIND config set client chain-id $CHAIN_ID
-$TACCHAIND config set client keyring-backend $KEYRING_BACKEND
-$TACCHAIND config set client output json
+$TACCHAIND config set client chain-id $CHAIN_ID --home $HOMEDIR
+$TACCHAIND config set client keyring-backend $KEYRING_BACKEND --home $HOMEDIR
+$TACCHAIND config set client output json --home $HOMEDIR
 
 # init genesis file
-$TACCHAIND init "$NODE_MONIKER" --chain-id $CHAIN_ID --default-denom utac --home $HOMEDIR
+$TACCHAIND init "$NODE_MONIKER" --chain-id $CHAIN_ID --default-denom utac --home $HOMEDIR $INIT_FLAGS
 
 # predeployed contracts (all precompiled contracts need to be defined before genesis accounts to avoid issues with auth account_numbers)
 # safe singleton factory (https://github.com/safe-global/safe-singleton-factory)
@@ -142,9 +144,13 @@ sed -i.bak "s/\"extra_eips\": \[\]/\"extra_eips\": \[\"3855\"\]/g" $HOMEDIR/conf
 # disable EIP-155
 sed -i.bak "s/\"allow_unprotected_txs\": false/\"allow_unprotected_txs\": true/g" $HOMEDIR/config/genesis.json
 sed -i.bak "s/allow-unprotected-txs = false/allow-unprotected-txs = true/g" $HOMEDIR/config/app.toml
+sed -i.bak "s/reject-unprotected-evm-txs = true/reject-unprotected-evm-txs = false/g" $HOMEDIR/config/app.toml
+
+# enable tx tracing over json-rpc
+sed -i.bak "s/api = \"eth,net,web3,/api = \"eth,net,web3,debug,/g" $HOMEDIR/config/app.toml
 
 # set evm precompiles
-sed -i.bak "s/\"active_static_precompiles\": \[\]/\"active_static_precompiles\": \[\"0x0000000000000000000000000000000000000100\",\"0x0000000000000000000000000000000000000400\",\"0x0000000000000000000000000000000000000800\",\"0x0000000000000000000000000000000000000801\",\"0x0000000000000000000000000000000000000802\",\"0x0000000000000000000000000000000000000803\",\"0x0000000000000000000000000000000000000804\",\"0x0000000000000000000000000000000000000805\",\"0x0000000000000000000000000000000000000806\",\"0x0000000000000000000000000000000000000807\"\]/g" $HOMEDIR/config/genesis.json
+sed -i.bak "s/\"active_static_precompiles\": \[\]/\"active_static_precompiles\": \[\"0x0000000000000000000000000000000000000100\",\"0x0000000000000000000000000000000000000400\",\"0x0000000000000000000000000000000000000800\",\"0x0000000000000000000000000000000000000801\",\"0x0000000000000000000000000000000000000802\",\"0x0000000000000000000000000000000000000803\",\"0x0000000000000000000000000000000000000804\",\"0x0000000000000000000000000000000000000805\",\"0x0000000000000000000000000000000000000806\",\"0x0000000000000000000000000000000000000807\",\"0x0000000000000000000000000000000000000900\"\]/g" $HOMEDIR/config/genesis.json
 
 # set x/feemarket min gas price
 sed -i.bak "s/\"min_gas_price\": \"0.000000000000000000\"/\"min_gas_price\": \"$MIN_GAS_PRICE\"/g" $HOMEDIR/config/genesis.json
@@ -191,6 +197,8 @@ jq --arg GOV_MIN_EXPEDITED_DEPOSIT "$GOV_MIN_EXPEDITED_DEPOSIT" '
 
 # enable apis
 sed -i.bak "s/enable = false/enable = true/g" $HOMEDIR/config/app.toml
+# pre-upgrade backups stay opt-in
+sed -i.bak "/^\[backup\]/,/^enable/ s/enable = true/enable = false/" $HOMEDIR/config/app.toml
 
 # enable rpc cors
 sed -i.bak "s/cors_allowed_origins = \[\]/cors_allowed_origins = \[\"*\"\]/g" $HOMEDIR/config/config.toml
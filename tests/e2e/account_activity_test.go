@@ -0,0 +1,117 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	accountactivitytypes "github.com/Asphere-xyz/tacchain/x/accountactivity/types"
+)
+
+const AccountActivityChainID = "tacchain_2417-1"
+
+// AccountActivityTestSuite runs a dedicated chain where governance enables the
+// indexing of the account activity.
+type AccountActivityTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestAccountActivityTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountActivityTestSuite))
+}
+
+func (s *AccountActivityTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: AccountActivityChainID, PortOffset: 2200}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *AccountActivityTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+type accountActivity struct {
+	Enabled  bool `json:"enabled"`
+	Activity *struct {
+		FirstSeenHeight int64  `json:"first_seen_height"`
+		LastSeenHeight  int64  `json:"last_seen_height"`
+		TxCount         uint64 `json:"tx_count"`
+		GasUsed         uint64 `json:"gas_used"`
+	} `json:"activity"`
+}
+
+func (s *AccountActivityTestSuite) queryActivity(ctx context.Context, address string) accountActivity {
+	output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "tac", "account-activity", address)
+	require.NoError(s.T(), err, "Failed to query account activity: %s", output)
+	var activity accountActivity
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &activity), "Output should be a json document: %s", output)
+	return activity
+}
+
+func (s *AccountActivityTestSuite) TestAccountActivity() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	client, err := ethclient.DialContext(ctx, s.chain.JSONRPCAddress())
+	require.NoError(s.T(), err)
+	defer client.Close()
+	key, err := s.chain.EthPrivateKey(ctx, "validator")
+	require.NoError(s.T(), err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	// the indexing is disabled by default
+	receipt, err := SendEthTx(ctx, client, key, &to, big.NewInt(1), 21000, nil)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status)
+	activity := s.queryActivity(ctx, from.Hex())
+	require.False(s.T(), activity.Enabled)
+	require.Nil(s.T(), activity.Activity)
+
+	err = s.chain.PassParamChange(ctx, "validator", accountactivitytypes.ModuleName, string(accountactivitytypes.KeyEnabled), true)
+	require.NoError(s.T(), err)
+
+	// an EVM tx is counted for its sender
+	receipt, err = SendEthTx(ctx, client, key, &to, big.NewInt(1), 21000, nil)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status)
+	activity = s.queryActivity(ctx, from.Hex())
+	require.True(s.T(), activity.Enabled)
+	require.NotNil(s.T(), activity.Activity, "The sender should have been seen")
+	require.Equal(s.T(), receipt.BlockNumber.Int64(), activity.Activity.FirstSeenHeight)
+	require.EqualValues(s.T(), 1, activity.Activity.TxCount)
+	require.Positive(s.T(), activity.Activity.GasUsed)
+	evmGas := activity.Activity.GasUsed
+
+	// a Cosmos tx is counted for its signer, the same account
+	validatorAddr, err := s.chain.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+	output, err := s.chain.Tx(ctx, "validator", "bank", "send", validatorAddr, randomAddress(), UTacAmount("1"))
+	require.NoError(s.T(), err, "Failed to send tokens: %s", output)
+	activity = s.queryActivity(ctx, validatorAddr)
+	require.NotNil(s.T(), activity.Activity)
+	require.Equal(s.T(), receipt.BlockNumber.Int64(), activity.Activity.FirstSeenHeight)
+	require.Greater(s.T(), activity.Activity.LastSeenHeight, activity.Activity.FirstSeenHeight)
+	require.EqualValues(s.T(), 2, activity.Activity.TxCount)
+	require.Greater(s.T(), activity.Activity.GasUsed, evmGas)
+
+	// the recipient of a transfer isn't seen
+	require.Nil(s.T(), s.queryActivity(ctx, to.Hex()).Activity)
+}
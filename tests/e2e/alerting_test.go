@@ -0,0 +1,145 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+)
+
+const AlertingChainID = "tacchain_2412-1"
+
+// webhookAlert mirrors the JSON alerts the node POSTs to its webhook
+type webhookAlert struct {
+	Event   string            `json:"event"`
+	ChainID string            `json:"chain_id"`
+	Height  int64             `json:"height"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details"`
+}
+
+// AlertingTestSuite runs a dedicated chain alerting a mock webhook server.
+// The node runs alone, so it alerts on its peer count right away.
+type AlertingTestSuite struct {
+	suite.Suite
+
+	chain   *Chain
+	webhook *httptest.Server
+	alerts  chan webhookAlert
+}
+
+func TestAlertingTestSuite(t *testing.T) {
+	suite.Run(t, new(AlertingTestSuite))
+}
+
+func (s *AlertingTestSuite) SetupSuite() {
+	s.alerts = make(chan webhookAlert, 100)
+	s.webhook = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert webhookAlert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.alerts <- alert
+	}))
+
+	s.chain = &Chain{ChainID: AlertingChainID, PortOffset: 1900}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	for key, value := range map[string]string{
+		"webhook-url":    strconv.Quote(s.webhook.URL),
+		"min-peers":      "1",
+		"check-interval": `"1s"`,
+	} {
+		if err := s.chain.SetAppConfig("alerting", key, value); err != nil {
+			s.T().Fatalf("Failed to configure alerting: %v", err)
+		}
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *AlertingTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+	if s.webhook != nil {
+		s.webhook.Close()
+	}
+}
+
+// waitForAlert returns the next alert of the event the webhook receives, the
+// alerts of other events are skipped
+func (s *AlertingTestSuite) waitForAlert(ctx context.Context, event string) webhookAlert {
+	for {
+		select {
+		case alert := <-s.alerts:
+			if alert.Event == event {
+				return alert
+			}
+		case <-ctx.Done():
+			s.T().Fatalf("No %s alert received: %v", event, ctx.Err())
+			return webhookAlert{}
+		}
+	}
+}
+
+func (s *AlertingTestSuite) TestLowPeersAlert() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	alert := s.waitForAlert(ctx, "low_peers")
+	require.Equal(s.T(), AlertingChainID, alert.ChainID)
+	require.Equal(s.T(), "0", alert.Details["peers"])
+	require.Equal(s.T(), "1", alert.Details["min_peers"])
+	require.Contains(s.T(), alert.Message, "fewer than 1")
+}
+
+func (s *AlertingTestSuite) TestUpgradeScheduledAlert() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	// far enough for the chain not to halt during the suite
+	name := "alerting-test"
+	height := s.chain.Height(ctx) + 100_000
+	proposal := map[string]any{
+		"messages": []map[string]any{{
+			"@type":     "/cosmos.upgrade.v1beta1.MsgSoftwareUpgrade",
+			"authority": ModuleAddress(govtypes.ModuleName),
+			"plan": map[string]string{
+				"name":   name,
+				"height": strconv.FormatInt(height, 10),
+			},
+		}},
+		"deposit": UTacAmount("10000000000000000"),
+		"title":   fmt.Sprintf("Upgrade to %s", name),
+		"summary": fmt.Sprintf("Software upgrade to %s at height %d", name, height),
+	}
+	bz, err := json.Marshal(proposal)
+	require.NoError(s.T(), err)
+	proposalFile := filepath.Join(s.chain.HomeDir, "upgrade-alerting.json")
+	require.NoError(s.T(), os.WriteFile(proposalFile, bz, 0o644))
+
+	proposalID, err := s.chain.SubmitProposal(ctx, "validator", proposalFile)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.chain.PassProposal(ctx, "validator", proposalID))
+
+	alert := s.waitForAlert(ctx, "upgrade_scheduled")
+	require.Equal(s.T(), AlertingChainID, alert.ChainID)
+	require.Equal(s.T(), name, alert.Details["name"])
+	require.Equal(s.T(), strconv.FormatInt(height, 10), alert.Details["height"])
+	require.Positive(s.T(), alert.Height)
+}
@@ -0,0 +1,137 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/cosmos/cosmos-sdk/x/authz"
+)
+
+const AuthzVoteChainID = "tacchain_2414-1"
+
+// AuthzVoteTestSuite runs a dedicated chain whose voting period is long
+// enough for a voting service to vote through the CLI on behalf of the
+// validator.
+type AuthzVoteTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestAuthzVoteTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthzVoteTestSuite))
+}
+
+func (s *AuthzVoteTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: AuthzVoteChainID, PortOffset: 2100}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	err := s.chain.PatchGenesis(func(appState map[string]any) error {
+		gov, ok := appState["gov"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("genesis has no gov state")
+		}
+		params, ok := gov["params"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("genesis has no gov params")
+		}
+		params["voting_period"] = "300s"
+		return nil
+	})
+	if err != nil {
+		s.T().Fatalf("Failed to patch genesis: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *AuthzVoteTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+// submitTextProposal submits a proposal without messages and returns its id
+func (s *AuthzVoteTestSuite) submitTextProposal(ctx context.Context, title string) string {
+	proposal := map[string]any{
+		"messages": []map[string]any{},
+		"deposit":  UTacAmount("10000000000000000"),
+		"title":    title,
+		"summary":  title,
+	}
+	bz, err := json.Marshal(proposal)
+	require.NoError(s.T(), err)
+	proposalFile := filepath.Join(s.chain.HomeDir, fmt.Sprintf("%s.json", title))
+	require.NoError(s.T(), os.WriteFile(proposalFile, bz, 0o644))
+
+	proposalID, err := s.chain.SubmitProposal(ctx, "validator", proposalFile)
+	require.NoError(s.T(), err)
+	return proposalID
+}
+
+// vote returns the option of the vote of voter on the proposal
+func (s *AuthzVoteTestSuite) vote(ctx context.Context, proposalID, voter string) string {
+	output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "gov", "vote", proposalID, voter, "--output", "json")
+	require.NoError(s.T(), err, "Failed to query vote: %s", output)
+	return parseField(output, "option")
+}
+
+func (s *AuthzVoteTestSuite) TestVoteOnBehalf() {
+	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Minute)
+	defer cancel()
+
+	validator, err := s.chain.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+	_, err = s.chain.AddKey(ctx, "vote_service")
+	require.NoError(s.T(), err)
+	service, err := s.chain.Address(ctx, "vote_service")
+	require.NoError(s.T(), err)
+	output, err := s.chain.Tx(ctx, "validator", "bank", "send", "validator", service, UTacAmount("1000000000000000000"))
+	require.NoError(s.T(), err, "Failed to fund the voting service: %s", output)
+
+	// the validator authorizes the service to vote on its behalf
+	output, err = s.chain.Tx(ctx, "validator", "authz", "grant", service, "generic", "--msg-type", "/cosmos.gov.v1.MsgVote")
+	require.NoError(s.T(), err, "Failed to grant: %s", output)
+
+	proposalID := s.submitTextProposal(ctx, "authz-vote")
+	output, err = s.chain.Tx(ctx, "vote_service", "tac", "exec-vote", validator, proposalID, "yes")
+	require.NoError(s.T(), err, "Failed to vote on behalf of the validator: %s", output)
+	require.Equal(s.T(), "VOTE_OPTION_YES", s.vote(ctx, proposalID, validator))
+
+	// the service votes until the validator revokes the grant
+	output, err = s.chain.Tx(ctx, "validator", "authz", "revoke", service, "/cosmos.gov.v1.MsgVote")
+	require.NoError(s.T(), err, "Failed to revoke: %s", output)
+	output, _ = s.chain.Tx(ctx, "vote_service", "tac", "exec-vote", validator, proposalID, "no")
+	AssertTxFailure(ctx, s.T(), s.chain.QueryParams(), output, authz.ErrNoAuthorizationFound)
+	require.Equal(s.T(), "VOTE_OPTION_YES", s.vote(ctx, proposalID, validator))
+
+	// or until the grant expires
+	expiration := time.Now().Add(20 * time.Second)
+	output, err = s.chain.Tx(ctx, "validator", "authz", "grant", service, "generic", "--msg-type", "/cosmos.gov.v1.MsgVote",
+		"--expiration", strconv.FormatInt(expiration.Unix(), 10))
+	require.NoError(s.T(), err, "Failed to grant: %s", output)
+	output, err = s.chain.Tx(ctx, "vote_service", "tac", "exec-vote", validator, proposalID, "abstain")
+	require.NoError(s.T(), err, "Failed to vote on behalf of the validator: %s", output)
+	require.Equal(s.T(), "VOTE_OPTION_ABSTAIN", s.vote(ctx, proposalID, validator))
+
+	// the expired grants are pruned at the beginning of the blocks
+	time.Sleep(time.Until(expiration))
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 2))
+	output, err = ExecuteCommand(ctx, s.chain.QueryParams(), "q", "authz", "grants", validator, service, "--output", "json")
+	require.NoError(s.T(), err, "Failed to query grants: %s", output)
+	require.NotContains(s.T(), output, "/cosmos.gov.v1.MsgVote", "The expired grant should be pruned")
+	output, _ = s.chain.Tx(ctx, "vote_service", "tac", "exec-vote", validator, proposalID, "no")
+	AssertTxFailure(ctx, s.T(), s.chain.QueryParams(), output, authz.ErrNoAuthorizationFound)
+	require.Equal(s.T(), "VOTE_OPTION_ABSTAIN", s.vote(ctx, proposalID, validator))
+}
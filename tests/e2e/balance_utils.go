@@ -0,0 +1,181 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+)
+
+// CommittedTx is the part of the result of a committed Cosmos tx needed to
+// compute the balances of its signer.
+type CommittedTx struct {
+	TxHash    string
+	Code      uint32
+	Height    int64
+	GasWanted int64
+	GasUsed   int64
+	// Fee is the fee in DefaultDenom the tx paid
+	Fee sdkmath.Int
+}
+
+// EffectiveGasPrice returns the price the tx paid for a unit of gas. Cosmos
+// txs pay the fee of their gas limit, the gas they didn't use isn't refunded.
+func (tx CommittedTx) EffectiveGasPrice() sdkmath.LegacyDec {
+	return sdkmath.LegacyNewDecFromInt(tx.Fee).QuoInt64(tx.GasWanted)
+}
+
+// QueryCommittedTx returns the result of the committed Cosmos tx txHash.
+func QueryCommittedTx(ctx context.Context, params CommandParams, txHash string) (CommittedTx, error) {
+	output, err := ExecuteCommand(ctx, params, "q", "tx", txHash, "--output", "json")
+	if err != nil {
+		return CommittedTx{}, fmt.Errorf("failed to query tx %s: %v: %s", txHash, err, output)
+	}
+
+	var res struct {
+		TxHash    string `json:"txhash"`
+		Code      uint32 `json:"code"`
+		Height    string `json:"height"`
+		GasWanted string `json:"gas_wanted"`
+		GasUsed   string `json:"gas_used"`
+		Tx        struct {
+			AuthInfo struct {
+				Fee struct {
+					Amount []struct {
+						Denom  string `json:"denom"`
+						Amount string `json:"amount"`
+					} `json:"amount"`
+				} `json:"fee"`
+			} `json:"auth_info"`
+		} `json:"tx"`
+	}
+	if err := json.Unmarshal([]byte(output), &res); err != nil {
+		return CommittedTx{}, fmt.Errorf("failed to parse tx %s: %v: %s", txHash, err, output)
+	}
+
+	tx := CommittedTx{TxHash: res.TxHash, Code: res.Code, Fee: sdkmath.ZeroInt()}
+	for _, field := range []struct {
+		value string
+		dest  *int64
+	}{{res.Height, &tx.Height}, {res.GasWanted, &tx.GasWanted}, {res.GasUsed, &tx.GasUsed}} {
+		if *field.dest, err = strconv.ParseInt(field.value, 10, 64); err != nil {
+			return CommittedTx{}, fmt.Errorf("failed to parse tx %s: %v", txHash, err)
+		}
+	}
+	for _, coin := range res.Tx.AuthInfo.Fee.Amount {
+		if coin.Denom != DefaultDenom {
+			continue
+		}
+		amount, ok := sdkmath.NewIntFromString(coin.Amount)
+		if !ok {
+			return CommittedTx{}, fmt.Errorf("invalid fee %s%s of tx %s", coin.Amount, coin.Denom, txHash)
+		}
+		tx.Fee = tx.Fee.Add(amount)
+	}
+	return tx, nil
+}
+
+// QueryBalanceAt returns the balance of address in DefaultDenom at the end
+// of the block at height.
+func QueryBalanceAt(ctx context.Context, params CommandParams, address string, height int64) (sdkmath.Int, error) {
+	output, err := ExecuteCommand(ctx, params, "q", "bank", "balance", address, DefaultDenom, "--height", strconv.FormatInt(height, 10), "--output", "json")
+	if err != nil {
+		return sdkmath.Int{}, fmt.Errorf("failed to query balance of %s at %d: %v: %s", address, height, err, output)
+	}
+	var res struct {
+		Balance struct {
+			Amount string `json:"amount"`
+		} `json:"balance"`
+	}
+	if err := json.Unmarshal([]byte(output), &res); err != nil {
+		return sdkmath.Int{}, fmt.Errorf("failed to parse balance of %s: %v: %s", address, err, output)
+	}
+	if res.Balance.Amount == "" {
+		return sdkmath.ZeroInt(), nil
+	}
+	amount, ok := sdkmath.NewIntFromString(res.Balance.Amount)
+	if !ok {
+		return sdkmath.Int{}, fmt.Errorf("invalid balance %s of %s", res.Balance.Amount, address)
+	}
+	return amount, nil
+}
+
+// RequireCosmosBalanceChange asserts the balance of address changed by
+// exactly delta in the block of tx, less the fee of tx when address signed
+// it. No other tx of the block may move the funds of address.
+func RequireCosmosBalanceChange(ctx context.Context, t *testing.T, params CommandParams, tx CommittedTx, address string, signer bool, delta sdkmath.Int) {
+	t.Helper()
+
+	before, err := QueryBalanceAt(ctx, params, address, tx.Height-1)
+	require.NoError(t, err)
+	after, err := QueryBalanceAt(ctx, params, address, tx.Height)
+	require.NoError(t, err)
+
+	want := before.Add(delta)
+	if signer {
+		want = want.Sub(tx.Fee)
+	}
+	require.Equal(t, want.String(), after.String(), "Balance of %s after tx %s (fee %s)", address, tx.TxHash, tx.Fee)
+}
+
+// EthTxFee returns the fee the sender of an EVM tx paid: the gas charged by
+// its receipt, which includes the adjustment to the min gas multiplier of the
+// fee market, at its effective gas price. The rest of the gas limit is
+// refunded.
+func EthTxFee(receipt *gethtypes.Receipt) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+}
+
+// ExpectedEthSenderBalance returns the balance of the sender of an EVM tx
+// after it, from the one before. The value is only sent by a successful tx,
+// the fee is paid either way.
+func ExpectedEthSenderBalance(before, value *big.Int, receipt *gethtypes.Receipt) *big.Int {
+	after := new(big.Int).Sub(before, EthTxFee(receipt))
+	if receipt.Status == gethtypes.ReceiptStatusSuccessful {
+		after.Sub(after, value)
+	}
+	return after
+}
+
+// EthBalancesAround returns the balance of address before and after the
+// block of receipt.
+func EthBalancesAround(ctx context.Context, client *ethclient.Client, address common.Address, receipt *gethtypes.Receipt) (before, after *big.Int, err error) {
+	if before, err = client.BalanceAt(ctx, address, new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))); err != nil {
+		return nil, nil, fmt.Errorf("failed to get balance of %s: %v", address, err)
+	}
+	if after, err = client.BalanceAt(ctx, address, receipt.BlockNumber); err != nil {
+		return nil, nil, fmt.Errorf("failed to get balance of %s: %v", address, err)
+	}
+	return before, after, nil
+}
+
+// RequireEthTransferBalances asserts the sender of an EVM tx sending value
+// to recipient paid exactly the value, when the tx succeeded, and the fee of
+// its receipt, and that recipient received exactly the value. No other tx of
+// the block may move the funds of either account.
+func RequireEthTransferBalances(ctx context.Context, t *testing.T, client *ethclient.Client, sender, recipient common.Address, value *big.Int, receipt *gethtypes.Receipt) {
+	t.Helper()
+
+	before, after, err := EthBalancesAround(ctx, client, sender, receipt)
+	require.NoError(t, err)
+	require.Equal(t, ExpectedEthSenderBalance(before, value, receipt).String(), after.String(),
+		"Balance of sender %s after tx %s (fee %s)", sender, receipt.TxHash, EthTxFee(receipt))
+
+	before, after, err = EthBalancesAround(ctx, client, recipient, receipt)
+	require.NoError(t, err)
+	received := new(big.Int).Sub(after, before)
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		require.Zero(t, received.Sign(), "Recipient %s of the failed tx %s received %s", recipient, receipt.TxHash, received)
+		return
+	}
+	require.Equal(t, value.String(), received.String(), "Recipient %s of tx %s", recipient, receipt.TxHash)
+}
@@ -0,0 +1,113 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const BlockResultsChainID = "tacchain_2423-1"
+
+// BlockResultsTestSuite runs a dedicated chain whose block results are
+// pruned apart from its blocks.
+type BlockResultsTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestBlockResultsTestSuite(t *testing.T) {
+	suite.Run(t, new(BlockResultsTestSuite))
+}
+
+func (s *BlockResultsTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: BlockResultsChainID, PortOffset: 2900}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *BlockResultsTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+type blockResults struct {
+	Height              int64 `json:"height"`
+	FinalizeBlockEvents []struct {
+		Type       string          `json:"type"`
+		Typed      json.RawMessage `json:"typed"`
+		Attributes []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"attributes"`
+	} `json:"finalize_block_events"`
+}
+
+func (s *BlockResultsTestSuite) queryBlockResults(ctx context.Context, height int64) (blockResults, error) {
+	output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "block-results", strconv.FormatInt(height, 10))
+	if err != nil {
+		return blockResults{}, err
+	}
+	var res blockResults
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Output should be a json document: %s", output)
+	return res, nil
+}
+
+// TestBlockResultsRetention prunes the results of the old blocks with the
+// tool, then on start with the retention of app.toml, and checks the blocks
+// are kept.
+func (s *BlockResultsTestSuite) TestBlockResultsRetention() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 10))
+	res, err := s.queryBlockResults(ctx, 2)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), int64(2), res.Height)
+	// the events of the blocks keep the mode the SDK adds
+	modes := 0
+	for _, event := range res.FinalizeBlockEvents {
+		for _, attr := range event.Attributes {
+			if attr.Key == "mode" {
+				modes++
+			}
+		}
+	}
+	require.Positive(s.T(), modes)
+
+	// the tool prunes a stopped node
+	require.NoError(s.T(), s.chain.Stop())
+	output, err := ExecuteCommand(ctx, CommandParams{HomeDir: s.chain.HomeDir}, "tools", "prune-block-results", "--retention", "5")
+	require.NoError(s.T(), err, "Failed to prune block results: %s", output)
+	require.Contains(s.T(), output, "pruned the results of")
+	require.NoError(s.T(), s.chain.Start())
+	_, err = s.queryBlockResults(ctx, 2)
+	require.Error(s.T(), err, "The results of block 2 should be pruned")
+
+	// the node prunes on start with a retention
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 10))
+	height := s.chain.Height(ctx)
+	_, err = s.queryBlockResults(ctx, height-8)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.chain.SetAppConfig("block-results", "retention", "5"))
+	require.NoError(s.T(), s.chain.Stop())
+	require.NoError(s.T(), s.chain.Start())
+	_, err = s.queryBlockResults(ctx, height-8)
+	require.Error(s.T(), err, "The results of block %d should be pruned on start", height-8)
+	_, err = s.queryBlockResults(ctx, height)
+	require.NoError(s.T(), err)
+
+	// the blocks are kept
+	output, err = ExecuteCommand(ctx, s.chain.QueryParams(), "q", "block", "--type=height", "2")
+	require.NoError(s.T(), err, "Block 2 should be kept: %s", output)
+}
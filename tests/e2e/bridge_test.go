@@ -0,0 +1,317 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	bridgetypes "github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+const BridgeChainID = "tacchain_2407-1"
+
+// BridgeTestSuite runs a dedicated chain where governance makes the validator
+// key a bridge relayer, then slashes it for attesting two payloads at the same
+// sequence. The tests run in order, the withdrawal test makes the validator a
+// relayer again to challenge a withdrawal.
+type BridgeTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestBridgeTestSuite(t *testing.T) {
+	suite.Run(t, new(BridgeTestSuite))
+}
+
+func (s *BridgeTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: BridgeChainID, PortOffset: 1400}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *BridgeTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+func (s *BridgeTestSuite) relayers(ctx context.Context) BridgeRelayers {
+	output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "tac", "bridge-relayers")
+	require.NoError(s.T(), err, "Failed to query bridge relayers: %s", output)
+	var res BridgeRelayers
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Output should be a json document: %s", output)
+	return res
+}
+
+// eventsSince returns the events of the blocks from height to the latest one
+func (s *BridgeTestSuite) eventsSince(ctx context.Context, height int64) []abci.Event {
+	var events []abci.Event
+	for h := height; h <= s.chain.Height(ctx); h++ {
+		blockEvents, err := s.chain.BlockEvents(ctx, h)
+		require.NoError(s.T(), err)
+		events = append(events, blockEvents...)
+	}
+	return events
+}
+
+// BridgeRelayers is the part of the tac bridge-relayers output the tests check
+type BridgeRelayers struct {
+	Relayers []struct {
+		Relayer string `json:"relayer"`
+		Bond    string `json:"bond"`
+		Active  bool   `json:"active"`
+	} `json:"relayers"`
+	TotalPower  string                  `json:"total_power"`
+	QuorumPower string                  `json:"quorum_power"`
+	Unbondings  []bridgetypes.Unbonding `json:"unbondings"`
+}
+
+func (s *BridgeTestSuite) TestRelayerBondAndSlash() {
+	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Minute)
+	defer cancel()
+
+	key, err := s.chain.EthPrivateKey(ctx, "validator")
+	require.NoError(s.T(), err)
+	relayer := crypto.PubkeyToAddress(key.PublicKey)
+
+	// subspaces decode their values as amino JSON, which quotes uint64
+	err = s.chain.PassParamChange(ctx, "validator", bridgetypes.ModuleName, string(bridgetypes.KeyUnbondingBlocks), "5")
+	require.NoError(s.T(), err)
+	err = s.chain.PassParamChange(ctx, "validator", bridgetypes.ModuleName, string(bridgetypes.KeyRelayers), []string{relayer.Hex()})
+	require.NoError(s.T(), err)
+
+	output, err := s.chain.Tx(ctx, "validator", "tac", "bond-relayer", UTacAmount("1000000000000000000000"))
+	require.NoError(s.T(), err, "Failed to bond: %s", output)
+	relayers := s.relayers(ctx)
+	require.Len(s.T(), relayers.Relayers, 1)
+	require.Equal(s.T(), relayer.Hex(), relayers.Relayers[0].Relayer)
+	require.Equal(s.T(), "1000000000000000000000", relayers.Relayers[0].Bond)
+	require.True(s.T(), relayers.Relayers[0].Active)
+	require.Equal(s.T(), "1000000000000000000000", relayers.TotalPower)
+	require.Equal(s.T(), "667000000000000000001", relayers.QuorumPower)
+
+	// the relayer attests two payloads at the same sequence
+	misbehavior := map[string]string{
+		"chain_id": BridgeChainID,
+		"relayer":  relayer.Hex(),
+		"sequence": "1",
+	}
+	for i, field := range []string{"first", "second"} {
+		payloadHash := crypto.Keccak256Hash([]byte{byte(i)})
+		sig, err := crypto.Sign(bridgetypes.AttestationDigest(BridgeChainID, 1, payloadHash).Bytes(), key)
+		require.NoError(s.T(), err)
+		misbehavior[field+"_payload_hash"] = payloadHash.Hex()
+		misbehavior[field+"_signature"] = hexutil.Encode(sig)
+	}
+	from := s.chain.Height(ctx)
+	err = s.chain.PassParamChange(ctx, "validator", bridgetypes.ModuleName, string(bridgetypes.KeyMisbehavior), []map[string]string{misbehavior})
+	require.NoError(s.T(), err)
+
+	slashes, err := TypedEvents[*bridgetypes.EventSlashRelayer](s.eventsSince(ctx, from))
+	require.NoError(s.T(), err)
+	require.Len(s.T(), slashes, 1)
+	require.Equal(s.T(), relayer.Hex(), slashes[0].Relayer)
+	require.Equal(s.T(), UTacAmount("100000000000000000000"), slashes[0].Slashed.String())
+
+	// the slashed relayer left the set, the rest of its bond unbonds
+	relayers = s.relayers(ctx)
+	require.Empty(s.T(), relayers.Relayers)
+	require.Len(s.T(), relayers.Unbondings, 1)
+	require.Equal(s.T(), relayer.Hex(), common.HexToAddress(relayers.Unbondings[0].Relayer).Hex())
+
+	require.Eventually(s.T(), func() bool {
+		return len(s.relayers(ctx).Unbondings) == 0
+	}, time.Minute, time.Second, "The bond should be released after the unbonding blocks")
+	releases, err := TypedEvents[*bridgetypes.EventReleaseRelayerBond](s.eventsSince(ctx, from))
+	require.NoError(s.T(), err)
+	require.Len(s.T(), releases, 1)
+	require.Equal(s.T(), relayer.Hex(), releases[0].Relayer)
+	require.Equal(s.T(), UTacAmount("900000000000000000000"), releases[0].Amount.String())
+}
+
+func (s *BridgeTestSuite) withdrawals(ctx context.Context) BridgeWithdrawals {
+	output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "tac", "bridge-withdrawals")
+	require.NoError(s.T(), err, "Failed to query bridge withdrawals: %s", output)
+	var res BridgeWithdrawals
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Output should be a json document: %s", output)
+	return res
+}
+
+// BridgeWithdrawals is the output of tac bridge-withdrawals
+type BridgeWithdrawals struct {
+	Escrow          string                   `json:"escrow"`
+	WithdrawalDelay json.Number              `json:"withdrawal_delay"`
+	Withdrawals     []bridgetypes.Withdrawal `json:"withdrawals"`
+}
+
+func (s *BridgeTestSuite) TestWithdrawalChallenge() {
+	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Minute)
+	defer cancel()
+
+	client, err := ethclient.DialContext(ctx, s.chain.JSONRPCAddress())
+	require.NoError(s.T(), err)
+	defer client.Close()
+	key, err := s.chain.EthPrivateKey(ctx, "validator")
+	require.NoError(s.T(), err)
+	relayer := crypto.PubkeyToAddress(key.PublicKey)
+
+	// the validator key is the relayer challenging withdrawals, the window
+	// leaves time for the challenge but not for a proposal
+	err = s.chain.PassParamChange(ctx, "validator", bridgetypes.ModuleName, string(bridgetypes.KeyWithdrawalDelay), "20")
+	require.NoError(s.T(), err)
+	err = s.chain.PassParamChange(ctx, "validator", bridgetypes.ModuleName, string(bridgetypes.KeyRelayers), []string{relayer.Hex()})
+	require.NoError(s.T(), err)
+	output, err := s.chain.Tx(ctx, "validator", "tac", "bond-relayer", UTacAmount("1000000000000000000000"))
+	require.NoError(s.T(), err, "Failed to bond: %s", output)
+
+	res := s.withdrawals(ctx)
+	escrow := common.HexToAddress(res.Escrow)
+	require.Equal(s.T(), bridgetypes.EscrowAddress(), escrow)
+	require.Equal(s.T(), "20", res.WithdrawalDelay.String())
+
+	const recipient = "EQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqB2N"
+	amount := big.NewInt(1_000_000_000_000_000_000)
+	withdraw := func() bridgetypes.Withdrawal {
+		data, err := bridgetypes.EscrowCall{Method: bridgetypes.MethodWithdraw, Recipient: recipient}.Pack()
+		require.NoError(s.T(), err)
+		receipt, err := SendEthTx(ctx, client, key, &escrow, amount, 200000, data)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "Withdrawal failed")
+
+		events, err := s.chain.BlockEvents(ctx, receipt.BlockNumber.Int64())
+		require.NoError(s.T(), err)
+		queued, err := TypedEvents[*bridgetypes.EventQueueWithdrawal](events)
+		require.NoError(s.T(), err)
+		require.Len(s.T(), queued, 1)
+		require.Equal(s.T(), relayer.Hex(), queued[0].Sender)
+		require.Equal(s.T(), recipient, queued[0].Recipient)
+		require.Equal(s.T(), UTacAmount(amount.String()), queued[0].Amount.String())
+		require.Equal(s.T(), receipt.BlockNumber.Int64()+20, queued[0].CompleteHeight)
+
+		output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "tac", "bridge-withdrawals", strconv.FormatUint(queued[0].Id, 10))
+		require.NoError(s.T(), err, "Failed to query withdrawal: %s", output)
+		var withdrawal bridgetypes.Withdrawal
+		require.NoError(s.T(), json.Unmarshal([]byte(output), &withdrawal), "Output should be a json document: %s", output)
+		require.Equal(s.T(), bridgetypes.WithdrawalStatusQueued, withdrawal.Status)
+		return withdrawal
+	}
+
+	// an unchallenged withdrawal completes after the delay
+	completed := withdraw()
+	for s.chain.Height(ctx) <= completed.CompleteHeight {
+		require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 1))
+	}
+	events, err := s.chain.BlockEvents(ctx, completed.CompleteHeight)
+	require.NoError(s.T(), err)
+	completions, err := TypedEvents[*bridgetypes.EventCompleteWithdrawal](events)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), completions, 1)
+	require.Equal(s.T(), completed.ID, completions[0].Id)
+	require.Empty(s.T(), s.withdrawals(ctx).Withdrawals)
+
+	// a challenged one waits for governance, which refunds it
+	challenged := withdraw()
+	data, err := bridgetypes.EscrowCall{Method: bridgetypes.MethodChallenge, ID: challenged.ID}.Pack()
+	require.NoError(s.T(), err)
+	receipt, err := SendEthTx(ctx, client, key, &escrow, big.NewInt(0), 200000, data)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "Challenge failed")
+
+	from := s.chain.Height(ctx)
+	for s.chain.Height(ctx) <= challenged.CompleteHeight {
+		require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 1))
+	}
+	pending := s.withdrawals(ctx).Withdrawals
+	require.Len(s.T(), pending, 1)
+	require.Equal(s.T(), bridgetypes.WithdrawalStatusChallenged, pending[0].Status)
+	require.Equal(s.T(), relayer.Hex(), pending[0].Challenger)
+
+	// subspaces decode their values as amino JSON, which quotes uint64
+	rulings := []map[string]any{{"id": strconv.FormatUint(challenged.ID, 10), "release": false}}
+	err = s.chain.PassParamChange(ctx, "validator", bridgetypes.ModuleName, string(bridgetypes.KeyWithdrawalRulings), rulings)
+	require.NoError(s.T(), err)
+
+	events = s.eventsSince(ctx, from)
+	refunds, err := TypedEvents[*bridgetypes.EventRefundWithdrawal](events)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), refunds, 1)
+	require.Equal(s.T(), challenged.ID, refunds[0].Id)
+	completions, err = TypedEvents[*bridgetypes.EventCompleteWithdrawal](events)
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), completions, "The challenged withdrawal shouldn't complete")
+	require.Empty(s.T(), s.withdrawals(ctx).Withdrawals)
+}
+
+// BridgeFeeQuote is the output of tac bridge-fee-quote
+type BridgeFeeQuote struct {
+	Denom          string `json:"denom"`
+	Direction      string `json:"direction"`
+	GasPrice       string `json:"gas_price"`
+	LocalGas       string `json:"local_gas"`
+	RelayerFee     string `json:"relayer_fee"`
+	DestinationFee string `json:"destination_fee"`
+	Total          string `json:"total"`
+}
+
+func (s *BridgeTestSuite) feeQuote(ctx context.Context, direction, amount string) BridgeFeeQuote {
+	output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "tac", "bridge-fee-quote", direction, amount)
+	require.NoError(s.T(), err, "Failed to query fee quote: %s", output)
+	var res BridgeFeeQuote
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Output should be a json document: %s", output)
+
+	total := new(big.Int)
+	for _, fee := range []string{res.LocalGas, res.RelayerFee, res.DestinationFee} {
+		n, ok := new(big.Int).SetString(fee, 10)
+		require.True(s.T(), ok, "Fee should be an integer: %s", fee)
+		total.Add(total, n)
+	}
+	require.Equal(s.T(), total.String(), res.Total, "Total should sum the fees")
+	return res
+}
+
+func (s *BridgeTestSuite) TestFeeQuote() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	amount := UTacAmount("1000000000000000000")
+	// the devnet preset has no base fee nor min gas price, gas is free
+	withdrawal := s.feeQuote(ctx, bridgetypes.FeeDirectionWithdrawal, amount)
+	require.Equal(s.T(), DefaultDenom, withdrawal.Denom)
+	require.Equal(s.T(), "0", withdrawal.LocalGas)
+	require.Equal(s.T(), "1000000000000000", withdrawal.RelayerFee)
+	require.Equal(s.T(), "0", withdrawal.DestinationFee, "TON fees aren't quoted before governance sets a TON price")
+
+	deposit := s.feeQuote(ctx, bridgetypes.FeeDirectionDeposit, amount)
+	require.Equal(s.T(), "0", deposit.LocalGas)
+	require.Equal(s.T(), "1000000000000000", deposit.RelayerFee)
+
+	// a TON worth 2 TAC prices the 0.05 TON of fees of a withdrawal at 0.1 TAC
+	err := s.chain.PassParamChange(ctx, "validator", bridgetypes.ModuleName, string(bridgetypes.KeyTONPrice), "2000000000")
+	require.NoError(s.T(), err)
+	err = s.chain.PassParamChange(ctx, "validator", bridgetypes.ModuleName, string(bridgetypes.KeyRelayerFeeBase), "5")
+	require.NoError(s.T(), err)
+
+	withdrawal = s.feeQuote(ctx, bridgetypes.FeeDirectionWithdrawal, amount)
+	require.Equal(s.T(), "100000000000000000", withdrawal.DestinationFee)
+	require.Equal(s.T(), "1000000000000005", withdrawal.RelayerFee)
+
+	_, err = ExecuteCommand(ctx, s.chain.QueryParams(), "q", "tac", "bridge-fee-quote", "transfer", amount)
+	require.Error(s.T(), err, "Only deposits and withdrawals are quoted")
+}
@@ -0,0 +1,143 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	sdkmath "cosmossdk.io/math"
+)
+
+const ByzantineChainID = "tacchain_2400-1"
+
+// ByzantineTestSuite runs a network where one validator signs on two nodes,
+// so its conflicting proposals and votes go through evidence handling and
+// slashing instead of only the honest path.
+//
+// Equivocating votes are validly signed, so CometBFT keeps the peers relaying
+// them connected, only the validator is punished.
+type ByzantineTestSuite struct {
+	suite.Suite
+
+	network *Network
+}
+
+func TestByzantineTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("byzantine tests run a network of validators")
+	}
+	suite.Run(t, new(ByzantineTestSuite))
+}
+
+func (s *ByzantineTestSuite) SetupSuite() {
+	s.network = &Network{ChainID: ByzantineChainID}
+	if err := s.network.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize network: %v", err)
+	}
+	if err := s.network.Start(); err != nil {
+		s.T().Fatalf("Failed to start network: %v", err)
+	}
+}
+
+func (s *ByzantineTestSuite) TearDownSuite() {
+	if s.network != nil {
+		s.network.Cleanup()
+	}
+}
+
+// validatorInfo returns the operator and consensus address and the consensus
+// pubkey of the validator of node
+func (s *ByzantineTestSuite) validatorInfo(ctx context.Context, node *Chain) (string, string, string) {
+	operator, err := ExecuteCommand(ctx, node.KeyParams(), "keys", "show", "validator", "--bech", "val", "-a")
+	require.NoError(s.T(), err, "Failed to get operator address: %s", operator)
+	consAddress, err := ExecuteCommand(ctx, CommandParams{HomeDir: node.HomeDir}, "comet", "show-address")
+	require.NoError(s.T(), err, "Failed to get consensus address: %s", consAddress)
+	consPubKey, err := ExecuteCommand(ctx, CommandParams{HomeDir: node.HomeDir}, "comet", "show-validator")
+	require.NoError(s.T(), err, "Failed to get consensus pubkey: %s", consPubKey)
+	return strings.TrimSpace(operator), strings.TrimSpace(consAddress), strings.TrimSpace(consPubKey)
+}
+
+type stakingValidator struct {
+	Jailed bool        `json:"jailed"`
+	Tokens sdkmath.Int `json:"tokens"`
+}
+
+func (s *ByzantineTestSuite) queryValidator(ctx context.Context, operator string) stakingValidator {
+	output, err := ExecuteCommand(ctx, s.network.Nodes[0].QueryParams(), "q", "staking", "validator", operator, "--output", "json")
+	require.NoError(s.T(), err, "Failed to query validator: %s", output)
+	var res struct {
+		Validator stakingValidator `json:"validator"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Output should be a json document: %s", output)
+	return res.Validator
+}
+
+// equivocations returns the consensus addresses of the equivocation evidence handled by x/evidence
+func (s *ByzantineTestSuite) equivocations(ctx context.Context) []string {
+	output, err := ExecuteCommand(ctx, s.network.Nodes[0].QueryParams(), "q", "evidence", "list", "--output", "json")
+	if err != nil {
+		return nil
+	}
+	var res struct {
+		Evidence []struct {
+			Type             string `json:"@type"`
+			ConsensusAddress string `json:"consensus_address"`
+		} `json:"evidence"`
+	}
+	if err := json.Unmarshal([]byte(output), &res); err != nil {
+		return nil
+	}
+
+	var addresses []string
+	for _, evidence := range res.Evidence {
+		if evidence.Type == "/cosmos.evidence.v1beta1.Equivocation" {
+			addresses = append(addresses, evidence.ConsensusAddress)
+		}
+	}
+	return addresses
+}
+
+func (s *ByzantineTestSuite) TestEquivocatingValidatorIsSlashed() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	const byzantine = 3
+	nodes := s.network.Nodes
+	operator, consAddress, consPubKey := s.validatorInfo(ctx, nodes[byzantine])
+	before := s.queryValidator(ctx, operator)
+	require.False(s.T(), before.Jailed)
+
+	twin, err := s.network.StartByzantineTwin(byzantine)
+	require.NoError(s.T(), err, "Failed to start byzantine twin")
+
+	require.Eventually(s.T(), func() bool {
+		for _, address := range s.equivocations(ctx) {
+			if address == consAddress {
+				return true
+			}
+		}
+		return false
+	}, 4*time.Minute, 2*time.Second, "Equivocation of %s should be committed as evidence", consAddress)
+	require.NoError(s.T(), twin.Stop())
+
+	// double signing jails and tombstones the validator and slashes its stake
+	after := s.queryValidator(ctx, operator)
+	require.True(s.T(), after.Jailed, "Equivocating validator should be jailed")
+	require.True(s.T(), after.Tokens.LT(before.Tokens), "Equivocating validator should be slashed")
+
+	output, err := ExecuteCommand(ctx, nodes[0].QueryParams(), "q", "slashing", "signing-info", consPubKey, "--output", "json")
+	require.NoError(s.T(), err, "Failed to query signing info: %s", output)
+	tombstoned, ok := ParseBoolField(output, "tombstoned")
+	require.True(s.T(), ok, "Signing info should have a tombstoned field: %s", output)
+	require.True(s.T(), tombstoned, "Equivocating validator should be tombstoned")
+
+	// the honest validators keep more than 2/3 of the voting power
+	for i, node := range nodes[:byzantine] {
+		require.NoError(s.T(), node.WaitForBlocks(ctx, 2), "node%d should keep producing blocks", i)
+	}
+}
@@ -0,0 +1,725 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+)
+
+const (
+	DefaultGasPrice = "100000000000utac"
+	DefaultGas      = "200000"
+
+	// ShutdownTimeout is the time a node gets to shut down on SIGTERM, above
+	// the default grace period of its queries
+	ShutdownTimeout = 30 * time.Second
+)
+
+// Chain is a single node tacchaind network started from contrib/localnet/init.sh.
+// Every chain listens on the default ports shifted by PortOffset, so several
+// of them can run next to the chain of TacchainTestSuite.
+type Chain struct {
+	ChainID    string
+	HomeDir    string
+	PortOffset int
+	// InitFlags are passed on to tacchaind init, e.g. the sentry presets
+	InitFlags string
+	// StartFlags are passed on to tacchaind start
+	StartFlags []string
+	// Node is the RPC endpoint of a remote network the chain stands for,
+	// tx and query commands are sent to it instead of a local node
+	Node string
+
+	cmd *exec.Cmd
+}
+
+func (c *Chain) port(base int) int {
+	return base + c.PortOffset
+}
+
+func (c *Chain) RPCAddress() string {
+	if c.Node != "" {
+		return c.Node
+	}
+	return fmt.Sprintf("tcp://127.0.0.1:%d", c.port(26657))
+}
+
+// P2PAddress returns the node_id@host:port other nodes connect to.
+func (c *Chain) P2PAddress(ctx context.Context) (string, error) {
+	nodeID, err := c.NodeID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s@127.0.0.1:%d", nodeID, c.port(26656)), nil
+}
+
+func (c *Chain) GRPCAddress() string {
+	return fmt.Sprintf("http://127.0.0.1:%d", c.port(9090))
+}
+
+func (c *Chain) JSONRPCAddress() string {
+	return fmt.Sprintf("http://127.0.0.1:%d", c.port(8545))
+}
+
+// KeyParams returns the command params for keyring commands.
+func (c *Chain) KeyParams() CommandParams {
+	return CommandParams{
+		HomeDir:        c.HomeDir,
+		KeyringBackend: DefaultKeyringBackend,
+	}
+}
+
+// TxParams returns the command params for tx and query commands.
+func (c *Chain) TxParams() CommandParams {
+	return CommandParams{
+		ChainID:        c.ChainID,
+		HomeDir:        c.HomeDir,
+		KeyringBackend: DefaultKeyringBackend,
+		Node:           c.RPCAddress(),
+	}
+}
+
+// QueryParams returns the command params for query commands.
+func (c *Chain) QueryParams() CommandParams {
+	return CommandParams{
+		HomeDir: c.HomeDir,
+		Node:    c.RPCAddress(),
+	}
+}
+
+func (c *Chain) Init() error {
+	dir, err := os.MkdirTemp("", c.ChainID)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	c.HomeDir = dir
+
+	pwd, _ := os.Getwd()
+	initScript := filepath.Join(pwd, "../../contrib/localnet/init.sh")
+	cmd := exec.Command("bash", "-c", fmt.Sprintf("echo y | %s", initScript))
+	cmd.Env = append(os.Environ(),
+		"HOMEDIR="+c.HomeDir,
+		"CHAIN_ID="+c.ChainID,
+		fmt.Sprintf("RPC_PORT=%d", c.port(26657)),
+		fmt.Sprintf("P2P_PORT=%d", c.port(26656)),
+		fmt.Sprintf("GRPC_PORT=%d", c.port(9090)),
+		fmt.Sprintf("GRPC_WEB_PORT=%d", c.port(9091)),
+		fmt.Sprintf("API_PORT=%d", c.port(1317)),
+		fmt.Sprintf("JSON_RPC_PORT=%d", c.port(8545)),
+		fmt.Sprintf("JSON_WS_PORT=%d", c.port(8546)),
+		fmt.Sprintf("METRICS_PORT=%d", c.port(6065)),
+		fmt.Sprintf("PROMETHEUS_PORT=%d", c.port(26660)),
+		fmt.Sprintf("PPROF_PORT=%d", c.port(6060)),
+		fmt.Sprintf("PROXY_PORT=%d", c.port(26658)),
+		"INIT_FLAGS="+strings.TrimSpace(DevnetInitFlags+" "+c.InitFlags),
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to initialize chain %s: %v", c.ChainID, err)
+	}
+	return nil
+}
+
+// SetAppConfig sets a key of the given section in app.toml, the node must be
+// (re)started for it to take effect.
+func (c *Chain) SetAppConfig(section, key, value string) error {
+	return setTOMLValue(filepath.Join(c.HomeDir, "config", "app.toml"), section, key, value)
+}
+
+// SetNodeConfig sets a key of the given section in config.toml, an empty
+// section sets a top level key. The node must be (re)started for it to take effect.
+func (c *Chain) SetNodeConfig(section, key, value string) error {
+	return setTOMLValue(filepath.Join(c.HomeDir, "config", "config.toml"), section, key, value)
+}
+
+func setTOMLValue(configPath, section, key, value string) error {
+	bz, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", filepath.Base(configPath), err)
+	}
+
+	lines := strings.Split(string(bz), "\n")
+	inSection := section == ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			inSection = trimmed == "["+section+"]"
+			continue
+		}
+		if inSection && strings.HasPrefix(trimmed, key+" =") {
+			lines[i] = fmt.Sprintf("%s = %s", key, value)
+			return os.WriteFile(configPath, []byte(strings.Join(lines, "\n")), 0o644)
+		}
+	}
+	return fmt.Errorf("key %s not found in section [%s] of %s", key, section, filepath.Base(configPath))
+}
+
+// PatchGenesis applies patch to the app state of the genesis of the node,
+// between Init and Start. Numbers are kept as json.Number, so the amounts
+// above the float64 precision are written back unchanged.
+func (c *Chain) PatchGenesis(patch func(appState map[string]any) error) error {
+	genesisFile := filepath.Join(c.HomeDir, "config", "genesis.json")
+	bz, err := os.ReadFile(genesisFile)
+	if err != nil {
+		return fmt.Errorf("failed to read genesis: %v", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(bz))
+	decoder.UseNumber()
+	var genesis map[string]any
+	if err := decoder.Decode(&genesis); err != nil {
+		return fmt.Errorf("failed to decode genesis: %v", err)
+	}
+	appState, ok := genesis["app_state"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("genesis has no app state")
+	}
+	if err := patch(appState); err != nil {
+		return err
+	}
+
+	bz, err = json.MarshalIndent(genesis, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(genesisFile, bz, 0o644); err != nil {
+		return fmt.Errorf("failed to write genesis: %v", err)
+	}
+	return nil
+}
+
+func (c *Chain) Start() error {
+	if err := c.launch(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	return c.WaitForBlocks(ctx, 1)
+}
+
+// launch starts the node without waiting for blocks, a validator of a
+// network can't produce them on its own.
+func (c *Chain) launch() error {
+	if err := killProcessOnPort(c.port(26657)); err != nil {
+		return err
+	}
+
+	logFile, err := os.OpenFile(c.LogFile(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open the log of chain %s: %v", c.ChainID, err)
+	}
+	defer logFile.Close()
+
+	args := append([]string{"start", "--chain-id", c.ChainID, "--home", c.HomeDir}, c.StartFlags...)
+	c.cmd = exec.Command("tacchaind", args...)
+	c.cmd.Stdout = logFile
+	c.cmd.Stderr = logFile
+	if err := c.cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start chain %s: %v", c.ChainID, err)
+	}
+	return nil
+}
+
+// LogFile returns the file the output of the node is appended to, across
+// its restarts.
+func (c *Chain) LogFile() string {
+	return filepath.Join(c.HomeDir, "node.log")
+}
+
+// Running returns true if the node process was started and hasn't been stopped.
+func (c *Chain) Running() bool {
+	return c.cmd != nil
+}
+
+// Stop shuts the node down with SIGTERM, like an operator or a process
+// manager does.
+func (c *Chain) Stop() error {
+	if c.cmd == nil || c.cmd.Process == nil {
+		return nil
+	}
+
+	err := terminate(c.cmd)
+	c.cmd = nil
+	if err != nil {
+		return fmt.Errorf("failed to stop chain %s: %v", c.ChainID, err)
+	}
+	return nil
+}
+
+// terminate sends SIGTERM to the process of cmd and waits for it to exit,
+// it kills the process if it is still running after ShutdownTimeout
+func terminate(cmd *exec.Cmd) error {
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		return err
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	select {
+	case <-exited:
+		return nil
+	case <-time.After(ShutdownTimeout):
+		_ = cmd.Process.Kill()
+		<-exited
+		return fmt.Errorf("process did not exit within %s of SIGTERM, killed it", ShutdownTimeout)
+	}
+}
+
+// Kill kills the node, like a crash or a power loss.
+func (c *Chain) Kill() error {
+	if c.cmd == nil || c.cmd.Process == nil {
+		return nil
+	}
+
+	if err := c.cmd.Process.Kill(); err != nil {
+		return fmt.Errorf("failed to kill chain %s: %v", c.ChainID, err)
+	}
+	_ = c.cmd.Wait()
+	c.cmd = nil
+	return nil
+}
+
+func (c *Chain) Cleanup() {
+	_ = c.Stop()
+	_ = os.RemoveAll(c.HomeDir)
+}
+
+func (c *Chain) Height(ctx context.Context) int64 {
+	output, err := ExecuteCommand(ctx, c.QueryParams(), "q", "block")
+	if err != nil {
+		return -1
+	}
+	return parseBlockHeight(output)
+}
+
+// WaitForBlocks blocks until the chain produced n more blocks.
+func (c *Chain) WaitForBlocks(ctx context.Context, n int64) error {
+	start := c.Height(ctx)
+	for {
+		if height := c.Height(ctx); start > 0 && height >= start+n {
+			return nil
+		} else if start <= 0 {
+			start = height
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("chain %s did not produce %d blocks: %v", c.ChainID, n, ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (c *Chain) NodeID(ctx context.Context) (string, error) {
+	output, err := ExecuteCommand(ctx, CommandParams{HomeDir: c.HomeDir}, "comet", "show-node-id")
+	if err != nil {
+		return "", fmt.Errorf("failed to get node id of %s: %v", c.HomeDir, err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// Peers returns the node ids of the peers the node is connected to.
+func (c *Chain) Peers(ctx context.Context) ([]string, error) {
+	url := fmt.Sprintf("http://127.0.0.1:%d/net_info", c.port(26657))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query net_info of %s: %v", c.HomeDir, err)
+	}
+	defer resp.Body.Close()
+
+	var netInfo struct {
+		Result struct {
+			Peers []struct {
+				NodeInfo struct {
+					ID string `json:"id"`
+				} `json:"node_info"`
+			} `json:"peers"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&netInfo); err != nil {
+		return nil, fmt.Errorf("failed to parse net_info: %v", err)
+	}
+
+	peers := make([]string, len(netInfo.Result.Peers))
+	for i, peer := range netInfo.Result.Peers {
+		peers[i] = peer.NodeInfo.ID
+	}
+	return peers, nil
+}
+
+// BlockEvents returns the events emitted outside of txs at the given height,
+// e.g. by the end blockers.
+func (c *Chain) BlockEvents(ctx context.Context, height int64) ([]abci.Event, error) {
+	url := fmt.Sprintf("http://127.0.0.1:%d/block_results?height=%d", c.port(26657), height)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query block_results of %s at %d: %v", c.ChainID, height, err)
+	}
+	defer resp.Body.Close()
+
+	var blockResults struct {
+		Result struct {
+			FinalizeBlockEvents []abci.Event `json:"finalize_block_events"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&blockResults); err != nil {
+		return nil, fmt.Errorf("failed to parse block_results: %v", err)
+	}
+	return blockResults.Result.FinalizeBlockEvents, nil
+}
+
+// AppHash returns the app hash in the header of the block at height, the
+// state root after executing the block before it.
+func (c *Chain) AppHash(ctx context.Context, height int64) (string, error) {
+	url := fmt.Sprintf("http://127.0.0.1:%d/block?height=%d", c.port(26657), height)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query block of %s at %d: %v", c.HomeDir, height, err)
+	}
+	defer resp.Body.Close()
+
+	var block struct {
+		Result struct {
+			Block struct {
+				Header struct {
+					AppHash string `json:"app_hash"`
+				} `json:"header"`
+			} `json:"block"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&block); err != nil {
+		return "", fmt.Errorf("failed to parse block: %v", err)
+	}
+	if block.Result.Block.Header.AppHash == "" {
+		return "", fmt.Errorf("block %d of %s not found", height, c.HomeDir)
+	}
+	return block.Result.Block.Header.AppHash, nil
+}
+
+// BlockHash returns the hash of the block at height, e.g. the trust hash of
+// a node state syncing from the chain.
+func (c *Chain) BlockHash(ctx context.Context, height int64) (string, error) {
+	url := fmt.Sprintf("http://127.0.0.1:%d/block?height=%d", c.port(26657), height)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query block of %s at %d: %v", c.HomeDir, height, err)
+	}
+	defer resp.Body.Close()
+
+	var block struct {
+		Result struct {
+			BlockID struct {
+				Hash string `json:"hash"`
+			} `json:"block_id"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&block); err != nil {
+		return "", fmt.Errorf("failed to parse block: %v", err)
+	}
+	if block.Result.BlockID.Hash == "" {
+		return "", fmt.Errorf("block %d of %s not found", height, c.HomeDir)
+	}
+	return block.Result.BlockID.Hash, nil
+}
+
+// BlockTxs returns the raw txs of the block at height.
+func (c *Chain) BlockTxs(ctx context.Context, height int64) ([][]byte, error) {
+	url := fmt.Sprintf("http://127.0.0.1:%d/block?height=%d", c.port(26657), height)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query block of %s at %d: %v", c.HomeDir, height, err)
+	}
+	defer resp.Body.Close()
+
+	var block struct {
+		Result struct {
+			Block struct {
+				Header struct {
+					Height string `json:"height"`
+				} `json:"header"`
+				Data struct {
+					Txs [][]byte `json:"txs"`
+				} `json:"data"`
+			} `json:"block"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&block); err != nil {
+		return nil, fmt.Errorf("failed to parse block: %v", err)
+	}
+	if block.Result.Block.Header.Height == "" {
+		return nil, fmt.Errorf("block %d of %s not found", height, c.HomeDir)
+	}
+	return block.Result.Block.Data.Txs, nil
+}
+
+// CatchingUp returns whether CometBFT reports the node as still syncing with
+// the network.
+func (c *Chain) CatchingUp(ctx context.Context) (bool, error) {
+	url := fmt.Sprintf("http://127.0.0.1:%d/status", c.port(26657))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to query status of %s: %v", c.HomeDir, err)
+	}
+	defer resp.Body.Close()
+
+	var status struct {
+		Result struct {
+			SyncInfo struct {
+				CatchingUp bool `json:"catching_up"`
+			} `json:"sync_info"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return false, fmt.Errorf("failed to parse status: %v", err)
+	}
+	return status.Result.SyncInfo.CatchingUp, nil
+}
+
+// TxEvents returns the events of all txs matching the given CometBFT event
+// query, e.g. "recv_packet.packet_sequence='1'".
+func (c *Chain) TxEvents(ctx context.Context, query string) ([]abci.Event, error) {
+	output, err := ExecuteCommand(ctx, c.QueryParams(), "q", "txs", "--query", query, "--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query txs %q: %v", query, err)
+	}
+
+	var res struct {
+		Txs []struct {
+			Events []abci.Event `json:"events"`
+		} `json:"txs"`
+	}
+	if err := json.Unmarshal([]byte(output), &res); err != nil {
+		return nil, fmt.Errorf("failed to parse txs: %v", err)
+	}
+
+	var events []abci.Event
+	for _, tx := range res.Txs {
+		events = append(events, tx.Events...)
+	}
+	return events, nil
+}
+
+// TypedEvents decodes the events of type T, the custom modules emit them with
+// EmitTypedEvent.
+func TypedEvents[T proto.Message](events []abci.Event) ([]T, error) {
+	var typed []T
+	for _, event := range events {
+		if event.Type != proto.MessageName(*new(T)) {
+			continue
+		}
+		msg, err := sdk.ParseTypedEvent(event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s event: %v", event.Type, err)
+		}
+		typed = append(typed, msg.(T))
+	}
+	return typed, nil
+}
+
+func (c *Chain) Address(ctx context.Context, keyName string) (string, error) {
+	output, err := ExecuteCommand(ctx, c.KeyParams(), "keys", "show", keyName, "-a")
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s address on %s: %v", keyName, c.ChainID, err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// AddKey creates a new key and returns its mnemonic.
+func (c *Chain) AddKey(ctx context.Context, keyName string) (string, error) {
+	output, err := ExecuteCommand(ctx, c.KeyParams(), "keys", "add", keyName, "--output", "json")
+	if err != nil {
+		return "", fmt.Errorf("failed to add key %s on %s: %v", keyName, c.ChainID, err)
+	}
+
+	var key struct {
+		Mnemonic string `json:"mnemonic"`
+	}
+	if err := json.Unmarshal([]byte(output), &key); err != nil {
+		return "", fmt.Errorf("failed to parse key output: %v", err)
+	}
+	return key.Mnemonic, nil
+}
+
+// EthPrivateKey exports the private key of keyName for signing EVM txs.
+func (c *Chain) EthPrivateKey(ctx context.Context, keyName string) (*ecdsa.PrivateKey, error) {
+	output, err := ExecuteCommand(ctx, c.KeyParams(), "keys", "unsafe-export-eth-key", keyName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export %s eth key on %s: %v", keyName, c.ChainID, err)
+	}
+
+	key, err := crypto.HexToECDSA(strings.TrimSpace(output))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s eth key: %v", keyName, err)
+	}
+	return key, nil
+}
+
+// Tx broadcasts a tx with the default gas settings and waits for it to be included.
+func (c *Chain) Tx(ctx context.Context, from string, args ...string) (string, error) {
+	args = append(args, "--from", from, "--gas", DefaultGas, "--gas-prices", DefaultGasPrice, "-y")
+	output, err := ExecuteCommand(ctx, c.TxParams(), append([]string{"tx"}, args...)...)
+	if err != nil {
+		return output, err
+	}
+	return output, c.WaitForBlocks(ctx, 1)
+}
+
+func (c *Chain) Balance(ctx context.Context, address, denom string) (string, error) {
+	output, err := ExecuteCommand(ctx, c.QueryParams(), "q", "bank", "balance", address, denom)
+	if err != nil {
+		return "", fmt.Errorf("failed to query balance: %v", err)
+	}
+	return parseField(output, "amount"), nil
+}
+
+// SubmitProposal submits a governance proposal from the given json file and
+// returns its id.
+func (c *Chain) SubmitProposal(ctx context.Context, from, proposalFile string) (string, error) {
+	if _, err := c.Tx(ctx, from, "gov", "submit-proposal", proposalFile); err != nil {
+		return "", fmt.Errorf("failed to submit proposal: %v", err)
+	}
+	return c.LatestProposalID(ctx)
+}
+
+// LatestProposalID returns the id of the last proposal submitted, e.g. by a
+// command building the proposal itself.
+func (c *Chain) LatestProposalID(ctx context.Context) (string, error) {
+	output, err := ExecuteCommand(ctx, c.QueryParams(), "q", "gov", "proposals", "--reverse", "--limit", "1", "--output", "json")
+	if err != nil {
+		return "", fmt.Errorf("failed to query proposals: %v", err)
+	}
+	return parseField(output, "id"), nil
+}
+
+// PassProposal votes yes with the given key and waits until the proposal passed.
+func (c *Chain) PassProposal(ctx context.Context, from, proposalID string) error {
+	if _, err := c.Tx(ctx, from, "gov", "vote", proposalID, "yes"); err != nil {
+		return fmt.Errorf("failed to vote on proposal %s: %v", proposalID, err)
+	}
+
+	for {
+		output, err := ExecuteCommand(ctx, c.QueryParams(), "q", "gov", "proposal", proposalID, "--output", "json")
+		if err != nil {
+			return fmt.Errorf("failed to query proposal %s: %v", proposalID, err)
+		}
+
+		switch status := parseField(output, "status"); status {
+		case "PROPOSAL_STATUS_PASSED":
+			return nil
+		case "PROPOSAL_STATUS_REJECTED", "PROPOSAL_STATUS_FAILED":
+			return fmt.Errorf("proposal %s ended with status %s", proposalID, status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("proposal %s did not pass: %v", proposalID, ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// PassParamChange passes a legacy ParameterChangeProposal setting key of the
+// given x/params subspace to the JSON encoding of value. The custom modules
+// keep their params in x/params, so governance changes them this way.
+func (c *Chain) PassParamChange(ctx context.Context, from, subspace, key string, value any) error {
+	proposalID, err := c.SubmitParamChange(ctx, from, subspace, map[string]any{key: value})
+	if err != nil {
+		return err
+	}
+	return c.PassProposal(ctx, from, proposalID)
+}
+
+// SubmitParamChange submits a legacy ParameterChangeProposal setting the keys
+// of the given x/params subspace to the JSON encoding of their values, and
+// returns its id.
+func (c *Chain) SubmitParamChange(ctx context.Context, from, subspace string, values map[string]any) (string, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	changes := make([]map[string]string, 0, len(keys))
+	for _, key := range keys {
+		bz, err := json.Marshal(values[key])
+		if err != nil {
+			return "", err
+		}
+		changes = append(changes, map[string]string{
+			"subspace": subspace,
+			"key":      key,
+			"value":    string(bz),
+		})
+	}
+
+	title := fmt.Sprintf("Set %s %s", subspace, strings.Join(keys, ", "))
+	proposal := map[string]any{
+		"messages": []map[string]any{{
+			"@type": "/cosmos.gov.v1.MsgExecLegacyContent",
+			"content": map[string]any{
+				"@type":       "/cosmos.params.v1beta1.ParameterChangeProposal",
+				"title":       title,
+				"description": title,
+				"changes":     changes,
+			},
+			"authority": ModuleAddress(govtypes.ModuleName),
+		}},
+		"deposit": UTacAmount("10000000000000000"),
+		"title":   title,
+		"summary": title,
+	}
+	bz, err := json.Marshal(proposal)
+	if err != nil {
+		return "", err
+	}
+
+	proposalFile := filepath.Join(c.HomeDir, fmt.Sprintf("param-change-%s-%s.json", subspace, strings.Join(keys, "-")))
+	if err := os.WriteFile(proposalFile, bz, 0o644); err != nil {
+		return "", err
+	}
+	return c.SubmitProposal(ctx, from, proposalFile)
+}
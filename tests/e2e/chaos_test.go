@@ -0,0 +1,202 @@
+package e2e
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	ChaosChainID = "tacchain_2399-1"
+
+	// DefaultChaosDuration is how long faults are injected, override it with CHAOS_DURATION
+	DefaultChaosDuration = 90 * time.Second
+)
+
+// ChaosTestSuite injects faults into a network of validators while txs are
+// sent to it: nodes are killed and restarted, isolated from their peers and
+// their links slowed down. The network must keep up and all nodes must end
+// up with the same state.
+//
+// Faults are picked at random, the seed is logged and can be set with
+// CHAOS_SEED to replay a run.
+type ChaosTestSuite struct {
+	suite.Suite
+
+	network *Network
+}
+
+func TestChaosTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("chaos tests are long running")
+	}
+	suite.Run(t, new(ChaosTestSuite))
+}
+
+func (s *ChaosTestSuite) SetupSuite() {
+	s.network = &Network{ChainID: ChaosChainID}
+	if err := s.network.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize network: %v", err)
+	}
+	if err := s.network.Start(); err != nil {
+		s.T().Fatalf("Failed to start network: %v", err)
+	}
+}
+
+func (s *ChaosTestSuite) TearDownSuite() {
+	if s.network != nil {
+		s.network.Cleanup()
+	}
+}
+
+func chaosSettings(t *testing.T) (int64, time.Duration) {
+	seed := time.Now().UnixNano()
+	if v := os.Getenv("CHAOS_SEED"); v != "" {
+		var err error
+		seed, err = strconv.ParseInt(v, 10, 64)
+		require.NoError(t, err, "Invalid CHAOS_SEED")
+	}
+
+	duration := DefaultChaosDuration
+	if v := os.Getenv("CHAOS_DURATION"); v != "" {
+		var err error
+		duration, err = time.ParseDuration(v)
+		require.NoError(t, err, "Invalid CHAOS_DURATION")
+	}
+	return seed, duration
+}
+
+func (s *ChaosTestSuite) TestNetworkRecoversFromFaults() {
+	seed, duration := chaosSettings(s.T())
+	s.T().Logf("Injecting faults for %s with CHAOS_SEED=%d", duration, seed)
+	rng := rand.New(rand.NewSource(seed))
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration+5*time.Minute)
+	defer cancel()
+
+	nodes := s.network.Nodes
+	recipient := randomAddress()
+
+	// faulty is the node currently killed or isolated, faults hit one node at
+	// a time so the others keep more than 2/3 of the voting power
+	var faulty atomic.Int64
+	faulty.Store(-1)
+
+	loadCtx, stopLoad := context.WithCancel(ctx)
+	var accepted atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for ; loadCtx.Err() == nil; time.Sleep(500 * time.Millisecond) {
+			i := rand.Intn(len(nodes))
+			if int64(i) == faulty.Load() {
+				continue
+			}
+			// the genesis accounts of the network are in the keyring of node0
+			params := CommandParams{
+				ChainID:        ChaosChainID,
+				HomeDir:        nodes[0].HomeDir,
+				KeyringBackend: DefaultKeyringBackend,
+				Node:           nodes[i].RPCAddress(),
+			}
+			if _, err := ExecuteCommand(loadCtx, params, "tx", "bank", "send", "genesis_acc_2", recipient, UTacAmount("1"),
+				"--gas", DefaultGas, "--gas-prices", DefaultGasPrice, "-y"); err == nil {
+				accepted.Add(1)
+			}
+		}
+	}()
+
+	deadline := time.Now().Add(duration)
+	for time.Now().Before(deadline) {
+		i := rng.Intn(len(nodes))
+		faultDuration := time.Duration(5+rng.Intn(15)) * time.Second
+
+		switch fault := rng.Intn(3); fault {
+		case 0:
+			s.T().Logf("Killing node%d for %s", i, faultDuration)
+			faulty.Store(int64(i))
+			require.NoError(s.T(), nodes[i].Kill())
+			time.Sleep(faultDuration)
+			require.NoError(s.T(), nodes[i].launch(), "Failed to restart node%d", i)
+		case 1:
+			s.T().Logf("Isolating node%d for %s", i, faultDuration)
+			faulty.Store(int64(i))
+			s.network.Isolate(i)
+			time.Sleep(faultDuration)
+			s.network.Heal()
+		case 2:
+			latency := time.Duration(50+rng.Intn(450)) * time.Millisecond
+			s.T().Logf("Delaying all links by %s for %s", latency, faultDuration)
+			s.network.SetLatency(latency)
+			time.Sleep(faultDuration)
+			s.network.SetLatency(0)
+		}
+		faulty.Store(-1)
+
+		// the network must make progress between faults
+		require.NoError(s.T(), s.waitForHeight(ctx, s.maxHeight(ctx)+1), "Network halted after a fault")
+	}
+
+	stopLoad()
+	wg.Wait()
+	s.T().Logf("%d txs were accepted during the faults", accepted.Load())
+
+	// all nodes catch up to the same height
+	target := s.maxHeight(ctx) + 2
+	require.NoError(s.T(), s.waitForHeight(ctx, target), "Nodes should recover after the faults")
+
+	for height := int64(2); height <= target; height++ {
+		expected, err := nodes[0].AppHash(ctx, height)
+		require.NoError(s.T(), err)
+		for i, node := range nodes[1:] {
+			appHash, err := node.AppHash(ctx, height)
+			require.NoError(s.T(), err)
+			require.Equal(s.T(), expected, appHash, "App hash of node%d diverged at height %d", i+1, height)
+		}
+	}
+
+	// the txs committed during the faults are part of the common state
+	require.Positive(s.T(), accepted.Load(), "Some txs should have been accepted")
+	balance, err := nodes[0].Balance(ctx, recipient, DefaultDenom)
+	require.NoError(s.T(), err)
+	committed, err := strconv.ParseInt(balance, 10, 64)
+	require.NoError(s.T(), err)
+	require.Positive(s.T(), committed, "Some txs should have been committed")
+	// txs accepted into the mempool of a killed node may be lost
+	require.LessOrEqual(s.T(), committed, accepted.Load())
+}
+
+// maxHeight returns the highest height of the running nodes
+func (s *ChaosTestSuite) maxHeight(ctx context.Context) int64 {
+	var height int64
+	for _, node := range s.network.Nodes {
+		height = max(height, node.Height(ctx))
+	}
+	return height
+}
+
+// waitForHeight blocks until all nodes reached height
+func (s *ChaosTestSuite) waitForHeight(ctx context.Context, height int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	for _, node := range s.network.Nodes {
+		for node.Height(ctx) < height {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second):
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package e2e
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	ConfigChainID = "tacchain_2411-1"
+
+	configMaxGasWanted = 1000000
+)
+
+// ConfigTestSuite runs a dedicated chain whose client.toml and app.toml are
+// read and updated with tacchaind config.
+type ConfigTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestConfigTestSuite(t *testing.T) {
+	suite.Run(t, new(ConfigTestSuite))
+}
+
+func (s *ConfigTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: ConfigChainID, PortOffset: 1800}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *ConfigTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+// config runs tacchaind config with the home of the chain
+func (s *ConfigTestSuite) config(ctx context.Context, args ...string) (string, error) {
+	output, err := ExecuteCommand(ctx, CommandParams{HomeDir: s.chain.HomeDir}, append([]string{"config"}, args...)...)
+	return strings.TrimSpace(output), err
+}
+
+func (s *ConfigTestSuite) get(ctx context.Context, config, key string) string {
+	output, err := s.config(ctx, "get", config, key)
+	require.NoError(s.T(), err, "Failed to get %s %s: %s", config, key, output)
+	return strings.Trim(output, `"`)
+}
+
+func (s *ConfigTestSuite) TestClientConfigDefaults() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// init.sh writes the defaults to the client.toml of the node home
+	require.Equal(s.T(), ConfigChainID, s.get(ctx, "client", "chain-id"))
+	require.Equal(s.T(), DefaultKeyringBackend, s.get(ctx, "client", "keyring-backend"))
+	require.Equal(s.T(), "json", s.get(ctx, "client", "output"))
+	require.Equal(s.T(), "tcp://localhost:26657", s.get(ctx, "client", "node"))
+
+	_, err := s.config(ctx, "get", "client", "no-such-key")
+	require.Error(s.T(), err, "Unknown keys should not be read")
+}
+
+func (s *ConfigTestSuite) TestClientConfigSet() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	address, err := s.chain.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+	balance := func() string {
+		output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "bank", "balance", address, "utac")
+		require.NoError(s.T(), err, "Failed to query balance: %s", output)
+		return output
+	}
+	require.Contains(s.T(), balance(), `"balance":{`)
+
+	// client.toml is read by every command, no restart needed
+	output, err := s.config(ctx, "set", "client", "output", "text")
+	require.NoError(s.T(), err, "Failed to set output: %s", output)
+	defer func() {
+		_, err := s.config(ctx, "set", "client", "output", "json")
+		require.NoError(s.T(), err)
+	}()
+	require.Equal(s.T(), "text", s.get(ctx, "client", "output"))
+	require.Contains(s.T(), balance(), "balance:\n")
+
+	_, err = s.config(ctx, "set", "client", "chain-id", "")
+	require.Error(s.T(), err, "An empty chain id should be rejected")
+	require.Equal(s.T(), ConfigChainID, s.get(ctx, "client", "chain-id"))
+}
+
+func (s *ConfigTestSuite) TestAppConfigSetOnRestart() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	require.Equal(s.T(), "0", s.get(ctx, "app", "tx-limits.max-gas-wanted"))
+
+	// malformed values of the tac sections are refused, app.toml is unchanged
+	for key, value := range map[string]string{
+		"tx-limits.max-gas-wanted":             "abc",
+		"tx-limits.max-tx-bytes":               "-1",
+		"tx-limits.reject-unprotected-evm-txs": "1",
+	} {
+		before := s.get(ctx, "app", key)
+		output, err := s.config(ctx, "set", "app", key, value)
+		require.Error(s.T(), err, "Setting %s to %s should be rejected: %s", key, value, output)
+		require.Contains(s.T(), output, "invalid [tx-limits] section")
+		require.Equal(s.T(), before, s.get(ctx, "app", key))
+	}
+	_, err := s.config(ctx, "set", "app", "tx-limits.no-such-key", "1")
+	require.Error(s.T(), err, "Unknown keys should not be set")
+
+	output, err := s.config(ctx, "set", "app", "tx-limits.max-gas-wanted", strconv.Itoa(configMaxGasWanted))
+	require.NoError(s.T(), err, "Failed to set max gas wanted: %s", output)
+	require.Equal(s.T(), strconv.Itoa(configMaxGasWanted), s.get(ctx, "app", "tx-limits.max-gas-wanted"))
+
+	send := func(gas int) (string, error) {
+		return ExecuteCommand(ctx, s.chain.TxParams(), "tx", "bank", "send", "validator", randomAddress(), UTacAmount("1"),
+			"--gas", strconv.Itoa(gas), "--gas-prices", DefaultGasPrice, "-y")
+	}
+
+	// the running node keeps the limit it started with
+	output, err = send(configMaxGasWanted + 1)
+	require.NoError(s.T(), err, "The limit should only apply after a restart: %s", output)
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 1))
+
+	require.NoError(s.T(), s.chain.Stop())
+	require.NoError(s.T(), s.chain.Start())
+
+	output, err = send(configMaxGasWanted + 1)
+	require.Error(s.T(), err, "Tx wanting more gas than the limit should be rejected after a restart: %s", output)
+	require.Contains(s.T(), output, "tx-limits.max-gas-wanted")
+
+	output, err = send(configMaxGasWanted)
+	require.NoError(s.T(), err, "Tx wanting the gas limit should be accepted: %s", output)
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 1))
+}
@@ -0,0 +1,266 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	ConsensusParamsChainID = "tacchain_2418-1"
+
+	// testBlockMaxBytes is the block size governance lowers max_bytes to, the
+	// evidence must fit in a block so its max bytes are lowered too
+	testBlockMaxBytes    = 256 * 1024
+	testEvidenceMaxBytes = 64 * 1024
+	// fillerTxs txs of fillerCalldata bytes each fill several blocks
+	fillerTxs      = 40
+	fillerCalldata = 32 * 1024
+	// fillerGas covers the calldata of a filler tx, zero bytes included
+	fillerGas = 400000
+)
+
+// ConsensusParamsTestSuite runs a network of validators with a short voting
+// period, so governance changes the consensus params while the test runs.
+type ConsensusParamsTestSuite struct {
+	suite.Suite
+
+	network *Network
+}
+
+func TestConsensusParamsTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("consensus params tests run a network of validators")
+	}
+	suite.Run(t, new(ConsensusParamsTestSuite))
+}
+
+func (s *ConsensusParamsTestSuite) SetupSuite() {
+	s.network = &Network{ChainID: ConsensusParamsChainID}
+	if err := s.network.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize network: %v", err)
+	}
+	err := s.network.PatchGenesis(func(appState map[string]any) error {
+		gov, ok := appState["gov"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("genesis has no gov state")
+		}
+		params, ok := gov["params"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("genesis has no gov params")
+		}
+		params["voting_period"] = "20s"
+		params["expedited_voting_period"] = "10s"
+		return nil
+	})
+	if err != nil {
+		s.T().Fatalf("Failed to patch genesis: %v", err)
+	}
+	if err := s.network.Start(); err != nil {
+		s.T().Fatalf("Failed to start network: %v", err)
+	}
+}
+
+func (s *ConsensusParamsTestSuite) TearDownSuite() {
+	if s.network != nil {
+		s.network.Cleanup()
+	}
+}
+
+type consensusParams struct {
+	Block struct {
+		MaxBytes string `json:"max_bytes"`
+		MaxGas   string `json:"max_gas"`
+	} `json:"block"`
+	Evidence struct {
+		MaxAgeNumBlocks string `json:"max_age_num_blocks"`
+		MaxAgeDuration  string `json:"max_age_duration"`
+		MaxBytes        string `json:"max_bytes"`
+	} `json:"evidence"`
+	Validator struct {
+		PubKeyTypes []string `json:"pub_key_types"`
+	} `json:"validator"`
+}
+
+func (s *ConsensusParamsTestSuite) consensusParams(ctx context.Context, node *Chain) consensusParams {
+	output, err := ExecuteCommand(ctx, node.QueryParams(), "q", "consensus", "params", "--output", "json")
+	require.NoError(s.T(), err, "Failed to query consensus params: %s", output)
+	var res struct {
+		Params consensusParams `json:"params"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Output should be a json document: %s", output)
+	return res.Params
+}
+
+// passConsensusParams passes the proposal of tx tac propose-consensus-params
+// with the given params flags and checks every validator applies the same
+// params.
+func (s *ConsensusParamsTestSuite) passConsensusParams(ctx context.Context, title string, flags ...string) consensusParams {
+	nodes := s.network.Nodes
+	args := append([]string{"tac", "propose-consensus-params"}, flags...)
+	args = append(args, "--title", title, "--summary", title, "--deposit", UTacAmount("10000000000000000"))
+	output, err := nodes[0].Tx(ctx, "validator", args...)
+	require.NoError(s.T(), err, "Failed to submit the proposal: %s", output)
+	proposalID, err := nodes[0].LatestProposalID(ctx)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.network.PassProposal(ctx, proposalID))
+	require.NoError(s.T(), nodes[0].WaitForBlocks(ctx, 1))
+
+	params := s.consensusParams(ctx, nodes[0])
+	for i, node := range nodes[1:] {
+		require.Equal(s.T(), params, s.consensusParams(ctx, node), "Consensus params of node%d diverged", i+1)
+	}
+	return params
+}
+
+// TestUpdateEvidenceAndPubKeyTypes lengthens the max age of evidence and
+// allows secp256k1 validator keys next to ed25519 ones through governance.
+func (s *ConsensusParamsTestSuite) TestUpdateEvidenceAndPubKeyTypes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	nodes := s.network.Nodes
+	before := s.consensusParams(ctx, nodes[0])
+	require.Equal(s.T(), []string{"ed25519"}, before.Validator.PubKeyTypes)
+
+	// invalid params are rejected before the proposal is sent
+	for _, flags := range [][]string{
+		{"--pub-key-types", "bls"},
+		{"--evidence-max-age-blocks", "0"},
+		{"--block-max-bytes", "1024"},
+		{},
+	} {
+		args := append([]string{"tx", "tac", "propose-consensus-params"}, flags...)
+		args = append(args, "--title", "Invalid", "--summary", "Invalid", "--from", "validator", "--gas", DefaultGas, "--gas-prices", DefaultGasPrice, "-y")
+		output, err := ExecuteCommand(ctx, nodes[0].TxParams(), args...)
+		require.Error(s.T(), err, "Proposal with %v should be rejected: %s", flags, output)
+	}
+
+	after := s.passConsensusParams(ctx, "Evidence age and validator keys",
+		"--evidence-max-age-blocks", "200000",
+		"--evidence-max-age-duration", "72h",
+		"--pub-key-types", "ed25519,secp256k1",
+	)
+	require.Equal(s.T(), "200000", after.Evidence.MaxAgeNumBlocks)
+	require.Equal(s.T(), "259200s", after.Evidence.MaxAgeDuration)
+	require.Equal(s.T(), []string{"ed25519", "secp256k1"}, after.Validator.PubKeyTypes)
+	// the params not set keep their value
+	require.Equal(s.T(), before.Block, after.Block)
+	require.Equal(s.T(), before.Evidence.MaxBytes, after.Evidence.MaxBytes)
+
+	// the ed25519 validators keep producing blocks
+	height := nodes[0].Height(ctx)
+	for i, node := range nodes {
+		require.Eventually(s.T(), func() bool {
+			return node.Height(ctx) > height+2
+		}, time.Minute, time.Second, "node%d should keep producing blocks", i)
+	}
+}
+
+// TestBlockSizeLimit lowers the max size of a block through governance and
+// fills blocks to the new limit with large EVM txs: every block stays under
+// the limit, the txs gossiped to all validators are included whoever
+// proposes, and a tx larger than a block is rejected from the mempool instead
+// of waiting there forever.
+func (s *ConsensusParamsTestSuite) TestBlockSizeLimit() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	nodes := s.network.Nodes
+	params := s.passConsensusParams(ctx, "Smaller blocks",
+		"--block-max-bytes", strconv.Itoa(testBlockMaxBytes),
+		"--evidence-max-bytes", strconv.Itoa(testEvidenceMaxBytes),
+	)
+	require.Equal(s.T(), strconv.Itoa(testBlockMaxBytes), params.Block.MaxBytes)
+	require.Equal(s.T(), strconv.Itoa(testEvidenceMaxBytes), params.Evidence.MaxBytes)
+
+	client, err := ethclient.DialContext(ctx, s.network.JSONRPCAddress(0))
+	require.NoError(s.T(), err)
+	defer client.Close()
+	key, err := nodes[0].EthPrivateKey(ctx, "validator")
+	require.NoError(s.T(), err)
+	nonce, err := client.PendingNonceAt(ctx, crypto.PubkeyToAddress(key.PublicKey))
+	require.NoError(s.T(), err)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	// the txs don't fit in one block, they queue up in the mempool and are
+	// included over several blocks
+	txs := make([]*gethtypes.Transaction, fillerTxs)
+	for i := range txs {
+		txs[i], err = BroadcastEthTx(ctx, client, key, nonce+uint64(i), &to, big.NewInt(0), fillerGas, make([]byte, fillerCalldata))
+		require.NoError(s.T(), err, "Failed to broadcast tx %d", i)
+	}
+	first, last := int64(0), int64(0)
+	for i, tx := range txs {
+		receipt, err := bind.WaitMined(ctx, client, tx)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "Tx %d failed", i)
+		if height := receipt.BlockNumber.Int64(); first == 0 || height < first {
+			first = height
+		}
+		if height := receipt.BlockNumber.Int64(); height > last {
+			last = height
+		}
+	}
+
+	txBytes, fullest, total := 0, 0, 0
+	for height := first; height <= last; height++ {
+		blockTxs, err := nodes[0].BlockTxs(ctx, height)
+		require.NoError(s.T(), err)
+		size := 0
+		for _, tx := range blockTxs {
+			size += len(tx)
+			txBytes = max(txBytes, len(tx))
+		}
+		require.LessOrEqual(s.T(), size, testBlockMaxBytes, "Block %d exceeds max_bytes", height)
+		fullest = max(fullest, size)
+		total += len(blockTxs)
+	}
+	require.GreaterOrEqual(s.T(), total, fillerTxs)
+	require.GreaterOrEqual(s.T(), last-first+1, int64(fillerTxs*fillerCalldata/testBlockMaxBytes), "The txs should spread over several blocks")
+	require.Greater(s.T(), fullest, testBlockMaxBytes-2*txBytes, "A block should be filled up to the limit")
+
+	// every validator stored the same blocks and reached the same state
+	for i, node := range nodes {
+		require.Eventually(s.T(), func() bool {
+			return node.Height(ctx) > last
+		}, time.Minute, time.Second, "node%d should store the blocks of the txs", i)
+	}
+	for height := first; height <= last+1; height++ {
+		expectedHash, err := nodes[0].BlockHash(ctx, height)
+		require.NoError(s.T(), err)
+		expectedAppHash, err := nodes[0].AppHash(ctx, height)
+		require.NoError(s.T(), err)
+		for i, node := range nodes[1:] {
+			hash, err := node.BlockHash(ctx, height)
+			require.NoError(s.T(), err)
+			require.Equal(s.T(), expectedHash, hash, "Block of node%d diverged at height %d", i+1, height)
+			appHash, err := node.AppHash(ctx, height)
+			require.NoError(s.T(), err)
+			require.Equal(s.T(), expectedAppHash, appHash, "App hash of node%d diverged at height %d", i+1, height)
+		}
+	}
+
+	// the mempool of the node accepts txs up to 1MiB, a tx larger than a
+	// block is rejected nonetheless
+	nonce, err = client.PendingNonceAt(ctx, crypto.PubkeyToAddress(key.PublicKey))
+	require.NoError(s.T(), err)
+	_, err = BroadcastEthTx(ctx, client, key, nonce, &to, big.NewInt(0), 4000000, make([]byte, testBlockMaxBytes+1))
+	require.Error(s.T(), err, "A tx larger than a block should be rejected")
+	require.Contains(s.T(), err.Error(), "max_bytes of the consensus params")
+	receipt, err := SendEthTx(ctx, client, key, &to, big.NewInt(0), fillerGas, make([]byte, fillerCalldata))
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "The nonce of the rejected tx should be free")
+}
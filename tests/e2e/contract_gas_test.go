@@ -0,0 +1,113 @@
+package e2e
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	contractgastypes "github.com/Asphere-xyz/tacchain/x/contractgas/types"
+)
+
+const ContractGasChainID = "tacchain_2409-1"
+
+// the runtime code loops until the call runs out of gas
+var runawayInitCode = common.FromHex("0x6004600c60003960046000f35b600056")
+
+// ContractGasTestSuite runs a dedicated chain where governance caps the gas of
+// contract calls and exempts one contract from the cap.
+type ContractGasTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestContractGasTestSuite(t *testing.T) {
+	suite.Run(t, new(ContractGasTestSuite))
+}
+
+func (s *ContractGasTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: ContractGasChainID, PortOffset: 1700}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *ContractGasTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+func (s *ContractGasTestSuite) TestCallGasCap() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	client, err := ethclient.DialContext(ctx, s.chain.JSONRPCAddress())
+	require.NoError(s.T(), err)
+	defer client.Close()
+	key, err := s.chain.EthPrivateKey(ctx, "validator")
+	require.NoError(s.T(), err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	deploy := func() common.Address {
+		receipt, err := SendEthTx(ctx, client, key, nil, big.NewInt(0), 200000, runawayInitCode)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "Contract deployment failed")
+		return receipt.ContractAddress
+	}
+	runaway, exempt := deploy(), deploy()
+
+	// call sends a call with gas to the contract, which always runs out of gas
+	call := func(contract common.Address, gas uint64) *gethtypes.Receipt {
+		receipt, err := SendEthTx(ctx, client, key, &contract, big.NewInt(0), gas, nil)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), gethtypes.ReceiptStatusFailed, receipt.Status)
+		require.Equal(s.T(), gas, receipt.GasUsed, "The call should use up its gas")
+		return receipt
+	}
+
+	// without cap the runaway contract uses all the gas it is given
+	call(runaway, 1_000_000)
+
+	// subspaces decode their values as amino JSON, which quotes uint64
+	const maxCallGas = 200_000
+	err = s.chain.PassParamChange(ctx, "validator", contractgastypes.ModuleName, string(contractgastypes.KeyMaxCallGas), strconv.Itoa(maxCallGas))
+	require.NoError(s.T(), err)
+	err = s.chain.PassParamChange(ctx, "validator", contractgastypes.ModuleName, string(contractgastypes.KeyExemptContracts), []string{exempt.Hex()})
+	require.NoError(s.T(), err)
+
+	// a call above the cap is rejected before it pays fees or uses the nonce
+	nonce, err := client.PendingNonceAt(ctx, from)
+	require.NoError(s.T(), err)
+	_, err = BroadcastEthTx(ctx, client, key, nonce, &runaway, big.NewInt(0), maxCallGas+1, nil)
+	require.Error(s.T(), err, "Call above the cap should be rejected")
+	require.Contains(s.T(), err.Error(), "above the cap of 200000")
+	pending, err := client.PendingNonceAt(ctx, from)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), nonce, pending)
+
+	// a call within the cap runs out of gas at the cap
+	call(runaway, maxCallGas)
+
+	// the exempt contract and the other accounts aren't capped
+	call(exempt, 1_000_000)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	receipt, err := SendEthTx(ctx, client, key, &to, big.NewInt(1), 1_000_000, nil)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "Transfer above the cap should succeed")
+	receipt, err = SendEthTx(ctx, client, key, nil, big.NewInt(0), 1_000_000, runawayInitCode)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "Deployment above the cap should succeed")
+}
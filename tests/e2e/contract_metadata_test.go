@@ -0,0 +1,87 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	contractmetatypes "github.com/Asphere-xyz/tacchain/x/contractmeta/types"
+)
+
+// queryContractMetadata returns the output of q tac contract-metadata
+func (s *TacchainTestSuite) queryContractMetadata(ctx context.Context, args ...string) (string, error) {
+	return ExecuteCommand(ctx, s.CommandParamsHomeDir(), append([]string{"q", "tac", "contract-metadata"}, args...)...)
+}
+
+func (s *TacchainTestSuite) TestContractMetadataRegistry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	client, err := NewEthClient(ctx)
+	require.NoError(s.T(), err)
+	defer client.Close()
+	key, err := GetEthPrivateKey(ctx, s, "validator")
+	require.NoError(s.T(), err, "Failed to export validator eth key")
+	deployer := crypto.PubkeyToAddress(key.PublicKey)
+
+	output, err := s.queryContractMetadata(ctx)
+	require.NoError(s.T(), err, "Failed to query the registry: %s", output)
+	var res struct {
+		Registry string                      `json:"registry"`
+		Metadata *contractmetatypes.Metadata `json:"metadata"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Output should be a json document: %s", output)
+	registry := common.HexToAddress(res.Registry)
+	require.Equal(s.T(), contractmetatypes.RegistryAddress(), registry)
+
+	nonce, err := client.PendingNonceAt(ctx, deployer)
+	require.NoError(s.T(), err)
+	receipt, err := SendEthTx(ctx, client, key, nil, big.NewInt(0), 200000, sstoreClearInitCode)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "Contract deployment failed")
+	contract := receipt.ContractAddress
+
+	// the deployer registers the metadata with an EVM tx to the registry
+	call := contractmetatypes.RegistryCall{
+		Method:      contractmetatypes.MethodRegister,
+		Contract:    contract,
+		DeployNonce: nonce,
+		Name:        "e2e " + contract.Hex()[:10],
+		SourceHash:  crypto.Keccak256Hash(sstoreClearInitCode),
+		AuditLink:   "https://audits.example.com/e2e.pdf",
+	}
+	data, err := call.Pack()
+	require.NoError(s.T(), err)
+	receipt, err = SendEthTx(ctx, client, key, &registry, big.NewInt(0), 100000, data)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "Registration failed")
+
+	expected := contractmetatypes.Metadata{
+		Contract:   contract.Hex(),
+		Name:       call.Name,
+		SourceHash: call.SourceHash.Hex(),
+		AuditLink:  call.AuditLink,
+		Owner:      deployer.Hex(),
+	}
+	for _, arg := range []string{contract.Hex(), call.Name} {
+		output, err = s.queryContractMetadata(ctx, arg)
+		require.NoError(s.T(), err, "Failed to query contract metadata: %s", output)
+		require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Output should be a json document: %s", output)
+		require.Equal(s.T(), &expected, res.Metadata)
+	}
+
+	// the owner removes it
+	data, err = contractmetatypes.RegistryCall{Method: contractmetatypes.MethodUnregister, Contract: contract}.Pack()
+	require.NoError(s.T(), err)
+	receipt, err = SendEthTx(ctx, client, key, &registry, big.NewInt(0), 100000, data)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "Unregistration failed")
+	output, err = s.queryContractMetadata(ctx, contract.Hex())
+	require.Error(s.T(), err, "Removed metadata shouldn't be found: %s", output)
+}
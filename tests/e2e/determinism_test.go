@@ -0,0 +1,190 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const DeterminismChainID = "tacchain_2419-1"
+
+// DeterminismTestSuite runs a validator through txs exercising the ante
+// handlers, the EVM and the precompiles, records its blocks and replays them
+// on a fresh node block syncing from it, which must compute the same results.
+type DeterminismTestSuite struct {
+	suite.Suite
+
+	validator *Chain
+	replica   *Chain
+}
+
+func TestDeterminismTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("determinism tests replay the blocks of a node on another node")
+	}
+	suite.Run(t, new(DeterminismTestSuite))
+}
+
+func (s *DeterminismTestSuite) SetupSuite() {
+	s.validator = &Chain{ChainID: DeterminismChainID, PortOffset: 2300}
+	if err := s.validator.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize validator: %v", err)
+	}
+	if err := s.validator.Start(); err != nil {
+		s.T().Fatalf("Failed to start validator: %v", err)
+	}
+}
+
+func (s *DeterminismTestSuite) TearDownSuite() {
+	if s.replica != nil {
+		s.replica.Cleanup()
+	}
+	if s.validator != nil {
+		s.validator.Cleanup()
+	}
+}
+
+// sendTraffic sends Cosmos and EVM txs to the validator, successful and
+// failing ones, with contract calls and precompile calls, and returns the
+// last height they were included at
+func (s *DeterminismTestSuite) sendTraffic(ctx context.Context) int64 {
+	client, err := ethclient.DialContext(ctx, s.validator.JSONRPCAddress())
+	require.NoError(s.T(), err)
+	defer client.Close()
+	key, err := s.validator.EthPrivateKey(ctx, "validator")
+	require.NoError(s.T(), err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	precompilesABI, err := abi.JSON(strings.NewReader(delegationPrecompilesABI))
+	require.NoError(s.T(), err)
+
+	// Cosmos txs, one failing in its message after the ante handler
+	validatorAddr, err := s.validator.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+	for i := 0; i < 3; i++ {
+		output, err := s.validator.Tx(ctx, "validator", "bank", "send", validatorAddr, randomAddress(), UTacAmount("1000"))
+		require.NoError(s.T(), err, "Failed to send tokens: %s", output)
+	}
+	// the ante handler takes the fee of the overdrawn send, its message fails
+	output, err := s.validator.Tx(ctx, "validator", "bank", "send", validatorAddr, randomAddress(), UTacAmount("1"+strings.Repeat("0", 40)))
+	require.NoError(s.T(), err, "The overdrawn send should pass the ante handler: %s", output)
+
+	// EVM txs: a contract creation, transfers, a reverted call and calls of
+	// the staking and distribution precompiles
+	receipt, err := SendEthTx(ctx, client, key, nil, big.NewInt(0), 200000, forwarderInitCode)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "Contract deployment failed")
+	forwarder := receipt.ContractAddress
+
+	operator, err := ExecuteCommand(ctx, s.validator.KeyParams(), "keys", "show", "validator", "--bech", "val", "-a")
+	require.NoError(s.T(), err)
+	delegate, err := precompilesABI.Pack("delegate", from, strings.TrimSpace(operator), big.NewInt(1000))
+	require.NoError(s.T(), err)
+	withdrawer, err := precompilesABI.Pack("setWithdrawAddress", from, validatorAddr)
+	require.NoError(s.T(), err)
+	staking := common.HexToAddress(stakingPrecompile)
+	distribution := common.HexToAddress(distributionPrecompile)
+
+	nonce, err := client.PendingNonceAt(ctx, from)
+	require.NoError(s.T(), err)
+	var txs []*gethtypes.Transaction
+	for _, call := range []struct {
+		to   *common.Address
+		gas  uint64
+		data []byte
+	}{
+		{&staking, 500000, delegate},
+		{&distribution, 500000, withdrawer},
+		// the forwarder reverts with the revert of the call it forwards
+		{&forwarder, 500000, append(distribution.Bytes(), []byte{0xde, 0xad, 0xbe, 0xef}...)},
+		{&forwarder, 500000, append(staking.Bytes(), delegate...)},
+	} {
+		tx, err := BroadcastEthTx(ctx, client, key, nonce, call.to, big.NewInt(0), call.gas, call.data)
+		require.NoError(s.T(), err)
+		txs = append(txs, tx)
+		nonce++
+	}
+	for i := 0; i < 5; i++ {
+		tx, err := BroadcastEthTransfer(ctx, client, key, common.BytesToAddress(crypto.Keccak256([]byte{byte(i)})), big.NewInt(1), nonce)
+		require.NoError(s.T(), err)
+		txs = append(txs, tx)
+		nonce++
+	}
+
+	var last int64
+	statuses := map[uint64]int{}
+	for _, tx := range txs {
+		receipt, err := bind.WaitMined(ctx, client, tx)
+		require.NoError(s.T(), err)
+		statuses[receipt.Status]++
+		last = max(last, receipt.BlockNumber.Int64())
+	}
+	require.NotZero(s.T(), statuses[gethtypes.ReceiptStatusSuccessful])
+	require.NotZero(s.T(), statuses[gethtypes.ReceiptStatusFailed], "The traffic should include failed EVM txs")
+	return last
+}
+
+func (s *DeterminismTestSuite) TestReplayBlocks() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	last := s.sendTraffic(ctx)
+	// a few more blocks with the rewards of the delegation
+	require.NoError(s.T(), s.validator.WaitForBlocks(ctx, 3))
+	last += 3
+
+	recording := filepath.Join(s.T().TempDir(), "blocks.json")
+	output, err := ExecuteCommand(ctx, s.validator.QueryParams(), "tools", "record-blocks", recording, "--to-height", fmt.Sprint(last))
+	require.NoError(s.T(), err, "Failed to record blocks: %s", output)
+	require.Contains(s.T(), output, fmt.Sprintf("recorded %d blocks", last))
+
+	// the replica starts from the same genesis and block syncs from the
+	// validator, executing all its blocks again
+	validatorAddr, err := s.validator.P2PAddress(ctx)
+	require.NoError(s.T(), err)
+	s.replica = &Chain{ChainID: DeterminismChainID, PortOffset: 2400}
+	require.NoError(s.T(), s.replica.Init())
+	genesis, err := os.ReadFile(filepath.Join(s.validator.HomeDir, "config", "genesis.json"))
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), os.WriteFile(filepath.Join(s.replica.HomeDir, "config", "genesis.json"), genesis, 0o644))
+	require.NoError(s.T(), s.replica.SetNodeConfig("", "block_sync", "true"))
+	require.NoError(s.T(), s.replica.SetNodeConfig("p2p", "persistent_peers", fmt.Sprintf("%q", validatorAddr)))
+	require.NoError(s.T(), s.replica.launch())
+
+	require.Eventually(s.T(), func() bool {
+		return s.replica.Height(ctx) > last
+	}, 3*time.Minute, time.Second, "The replica should replay the recorded blocks, see %s", s.replica.LogFile())
+
+	output, err = ExecuteCommand(ctx, s.replica.QueryParams(), "tools", "compare-blocks", recording, "--output", "json")
+	require.NoError(s.T(), err, "The replica diverged from the recording: %s", output)
+	var comparison struct {
+		Compared   int `json:"compared"`
+		Divergence any `json:"divergence"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &comparison), "Output should be a json document: %s", output)
+	require.Equal(s.T(), int(last), comparison.Compared)
+	require.Nil(s.T(), comparison.Divergence)
+
+	// a recording of another chain is rejected rather than compared
+	bz, err := os.ReadFile(recording)
+	require.NoError(s.T(), err)
+	other := filepath.Join(s.T().TempDir(), "other.json")
+	require.NoError(s.T(), os.WriteFile(other, []byte(strings.Replace(string(bz), DeterminismChainID, "tacchain_2390-1", 1)), 0o644))
+	output, err = ExecuteCommand(ctx, s.replica.QueryParams(), "tools", "compare-blocks", other)
+	require.Error(s.T(), err)
+	require.Contains(s.T(), output, "the blocks were recorded on tacchain_2390-1")
+}
@@ -0,0 +1,117 @@
+package e2e
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+)
+
+const (
+	stakingPrecompile      = "0x0000000000000000000000000000000000000800"
+	distributionPrecompile = "0x0000000000000000000000000000000000000801"
+)
+
+// forwarderInitCode deploys a contract calling the address in the first 20
+// bytes of its calldata with the rest, and returning or reverting with the
+// result of the call
+var forwarderInitCode = common.FromHex("0x602d80600b6000396000f3" +
+	"6014360380601460003760006000916000600060003560601c5af13d600060003e6028573d6000fd5b3d6000f3")
+
+const delegationPrecompilesABI = `[
+	{"type":"function","name":"delegate","stateMutability":"nonpayable","inputs":[{"name":"delegatorAddress","type":"address"},{"name":"validatorAddress","type":"string"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"success","type":"bool"}]},
+	{"type":"function","name":"setWithdrawAddress","stateMutability":"nonpayable","inputs":[{"name":"delegatorAddress","type":"address"},{"name":"withdrawerAddress","type":"string"}],"outputs":[{"name":"success","type":"bool"}]}
+]`
+
+// TestContractWithdrawAddress has a contract delegate through the staking
+// precompile and redirect the rewards of its delegation, while neither the
+// account calling the contract nor a contract acting for it may set the
+// withdraw address of another delegator.
+func (s *TacchainTestSuite) TestContractWithdrawAddress() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	client, err := NewEthClient(ctx)
+	require.NoError(s.T(), err)
+	defer client.Close()
+	key, err := GetEthPrivateKey(ctx, s, "validator")
+	require.NoError(s.T(), err, "Failed to export validator eth key")
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	precompilesABI, err := abi.JSON(strings.NewReader(delegationPrecompilesABI))
+	require.NoError(s.T(), err)
+
+	conn, err := grpc.NewClient(DefaultGRPCAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(s.T(), err)
+	defer conn.Close()
+	distrClient := distrtypes.NewQueryClient(conn)
+	withdrawAddress := func(delegator common.Address) string {
+		res, err := distrClient.DelegatorWithdrawAddress(ctx, &distrtypes.QueryDelegatorWithdrawAddressRequest{
+			DelegatorAddress: sdk.MustBech32ifyAddressBytes(DefaultBech32Prefix, delegator.Bytes()),
+		})
+		require.NoError(s.T(), err)
+		return res.WithdrawAddress
+	}
+
+	receipt, err := SendEthTx(ctx, client, key, nil, big.NewInt(0), 200000, forwarderInitCode)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "Contract deployment failed")
+	forwarder := receipt.ContractAddress
+	forwarderAddr := sdk.MustBech32ifyAddressBytes(DefaultBech32Prefix, forwarder.Bytes())
+
+	validatorAddr, err := GetAddress(ctx, s, "validator")
+	require.NoError(s.T(), err)
+	output, err := TxBankSend(ctx, s, validatorAddr, forwarderAddr, UTacAmount("1000000"))
+	require.NoError(s.T(), err, "Failed to fund the contract: %s", output)
+	waitForNewBlock(s, nil)
+	waitForNewBlock(s, nil)
+
+	// call has the forwarder call a precompile, the forwarder is the caller
+	// of the precompile and the validator the origin
+	call := func(precompile string, method string, args ...any) *gethtypes.Receipt {
+		input, err := precompilesABI.Pack(method, args...)
+		require.NoError(s.T(), err)
+		data := append(common.HexToAddress(precompile).Bytes(), input...)
+		receipt, err := SendEthTx(ctx, client, key, &forwarder, big.NewInt(0), 500000, data)
+		require.NoError(s.T(), err)
+		return receipt
+	}
+
+	operator, err := GetValidatorAddress(ctx, s)
+	require.NoError(s.T(), err)
+	receipt = call(stakingPrecompile, "delegate", forwarder, operator, big.NewInt(1000))
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "The contract should delegate its funds")
+	output, err = ExecuteCommand(ctx, s.CommandParamsHomeDir(), "q", "staking", "delegation", forwarderAddr, operator)
+	require.NoError(s.T(), err, "The contract should have a delegation: %s", output)
+
+	// the contract redirects the rewards of its delegation
+	withdrawer := randomAddress()
+	receipt = call(distributionPrecompile, "setWithdrawAddress", forwarder, withdrawer)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "The contract should set its withdraw address")
+	require.Equal(s.T(), withdrawer, withdrawAddress(forwarder))
+
+	// the contract can't set the withdraw address of the validator, even
+	// though the validator sent the tx
+	receipt = call(distributionPrecompile, "setWithdrawAddress", from, randomAddress())
+	require.Equal(s.T(), gethtypes.ReceiptStatusFailed, receipt.Status, "The origin of the tx shouldn't authorize the contract")
+	require.Equal(s.T(), validatorAddr, withdrawAddress(from))
+
+	// the validator can't set the withdraw address of the contract
+	input, err := precompilesABI.Pack("setWithdrawAddress", forwarder, validatorAddr)
+	require.NoError(s.T(), err)
+	to := common.HexToAddress(distributionPrecompile)
+	receipt, err = SendEthTx(ctx, client, key, &to, big.NewInt(0), 500000, input)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusFailed, receipt.Status, "A caller other than the delegator should be rejected")
+	require.Equal(s.T(), withdrawer, withdrawAddress(forwarder))
+}
@@ -1,7 +1,7 @@
 package e2e
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -10,6 +10,14 @@ import (
 	"time"
 )
 
+// DevnetInitFlags initializes the e2e chains with the devnet preset, proposals
+// pass within seconds and txs pay no base fee.
+const DevnetInitFlags = "--preset devnet"
+
+// MnemonicsFile holds the mnemonics of the accounts of the tests, the
+// keyring is seeded from it so the accounts are the same on every run
+const MnemonicsFile = "testdata/mnemonics.json"
+
 func (s *TacchainTestSuite) SetupSuite() {
 	s.T().Log("Setting up test suite...")
 
@@ -26,6 +34,9 @@ func (s *TacchainTestSuite) SetupSuite() {
 	if err := s.initChain(); err != nil {
 		s.T().Fatalf("Failed to initialize chain: %v", err)
 	}
+	if err := s.seedKeys(); err != nil {
+		s.T().Fatalf("Failed to seed keyring: %v", err)
+	}
 	if err := s.startChain(); err != nil {
 		s.T().Fatalf("Failed to start chain: %v", err)
 	}
@@ -37,7 +48,7 @@ func (s *TacchainTestSuite) initChain() error {
 	nodeDir := s.homeDir
 	pwd, _ := os.Getwd()
 	initScript := filepath.Join(pwd, "../../contrib/localnet/init.sh")
-	cmd := exec.Command("bash", "-c", fmt.Sprintf("echo y | HOMEDIR=%s %s", nodeDir, initScript))
+	cmd := exec.Command("bash", "-c", fmt.Sprintf("echo y | HOMEDIR=%s INIT_FLAGS=%q %s", nodeDir, DevnetInitFlags, initScript))
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	err := cmd.Run()
@@ -45,10 +56,20 @@ func (s *TacchainTestSuite) initChain() error {
 		return fmt.Errorf("failed to initialize chain: %v", err)
 	}
 
-	if err := ModifyInitialChainConfig(s.homeDir); err != nil {
-		return fmt.Errorf("failed to modify chain config: %v", err)
-	}
+	return nil
+}
+
+// seedKeys recovers the accounts of MnemonicsFile into the keyring, the
+// validator key init.sh created is part of it
+func (s *TacchainTestSuite) seedKeys() error {
+	s.T().Log("Seeding keyring...")
 
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	output, err := ExecuteCommand(ctx, s.DefaultCommandParams(), "keys", "add", "--recover", "--source", MnemonicsFile)
+	if err != nil {
+		return fmt.Errorf("failed to recover the keys of %s: %v: %s", MnemonicsFile, err, output)
+	}
 	return nil
 }
 
@@ -81,55 +102,14 @@ func (s *TacchainTestSuite) startChain() error {
 	return nil
 }
 
-func ModifyInitialChainConfig(homeDir string) error {
-	genesisPath := filepath.Join(homeDir, "config", "genesis.json")
-	genesisData, err := os.ReadFile(genesisPath)
-	if err != nil {
-		return fmt.Errorf("failed to read genesis file: %v", err)
-	}
-
-	var genesis map[string]any
-	if err := json.Unmarshal(genesisData, &genesis); err != nil {
-		return fmt.Errorf("failed to unmarshal genesis: %v", err)
-	}
-
-	if appState, ok := genesis["app_state"].(map[string]any); ok {
-		if gov, ok := appState["gov"].(map[string]any); ok {
-			// Modify voting period
-			if params, ok := gov["params"].(map[string]any); ok {
-				params["voting_period"] = "3s"
-				params["expedited_voting_period"] = "3s"
-			}
-		}
-		if feemarket, ok := appState["feemarket"].(map[string]any); ok {
-			// Modify no_base_fee
-			if params, ok := feemarket["params"].(map[string]any); ok {
-				params["no_base_fee"] = true
-			}
-		}
-	}
-
-	modifiedGenesis, err := json.MarshalIndent(genesis, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal modified genesis: %v", err)
-	}
-
-	if err := os.WriteFile(genesisPath, modifiedGenesis, 0644); err != nil {
-		return fmt.Errorf("failed to write modified genesis: %v", err)
-	}
-
-	return nil
-}
-
 func (s *TacchainTestSuite) TearDownSuite() {
 	s.T().Log("Tearing down Tacchain test suite...")
 
 	if s.cmd != nil {
 		s.T().Log("Stopping chain process...")
-		if err := s.cmd.Process.Kill(); err != nil {
-			s.T().Logf("Error stopping chain process: %v", err)
+		if err := terminate(s.cmd); err != nil {
+			s.T().Errorf("Error stopping chain process: %v", err)
 		}
-		s.cmd.Wait()
 	}
 
 	if err := os.RemoveAll(s.homeDir); err != nil {
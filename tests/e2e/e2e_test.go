@@ -2,15 +2,30 @@ package e2e
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math/big"
+	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+
+	sdkmath "cosmossdk.io/math"
+
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 func TestTacchainTestSuite(t *testing.T) {
@@ -55,37 +70,34 @@ func (s *TacchainTestSuite) TestBankSend() {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
-	params := s.DefaultCommandParams()
-	_, err := ExecuteCommand(ctx, params, "keys", "add", "recipient")
-	require.NoError(s.T(), err, "Failed to add recipient account")
-
 	recipientAddr, err := GetAddress(ctx, s, "recipient")
 	require.NoError(s.T(), err, "Failed to get recipient address")
+	require.Equal(s.T(), "tac1tg73cpsxxca3m2t6w09gezvcg37zrqqxglwsgv", recipientAddr, "Recipient should be seeded from the mnemonics file")
 
 	validatorAddr, err := GetAddress(ctx, s, "validator")
 	require.NoError(s.T(), err, "Failed to get validator address")
 
-	initialValidatorBalance, err := QueryBankBalances(ctx, s, validatorAddr)
-	require.NoError(s.T(), err, "Failed to query validator balance")
-
 	initialRecipientBalance, err := QueryBankBalances(ctx, s, recipientAddr)
 	require.NoError(s.T(), err, "Failed to query recipient balance")
+	require.Equal(s.T(), UTacAmount("0"), initialRecipientBalance, "Recipient should start without funds")
 
 	amount := UTacAmount("1000000")
-	_, err = TxBankSend(ctx, s, "validator", recipientAddr, amount)
+	output, err := TxBankSend(ctx, s, "validator", recipientAddr, amount)
 	require.NoError(s.T(), err, "Failed to send tokens")
 
 	waitForNewBlock(s, nil)
 
-	finalValidatorBalance, err := QueryBankBalances(ctx, s, validatorAddr)
-	require.NoError(s.T(), err, "Failed to query validator balance after tx")
+	tx, err := QueryCommittedTx(ctx, s.CommandParamsHomeDir(), parseField(output, "txhash"))
+	require.NoError(s.T(), err)
+	require.Zero(s.T(), tx.Code)
+	// Cosmos txs pay the fee of their gas limit at the gas price of TxBankSend
+	require.Equal(s.T(), "100000000000.000000000000000000", tx.EffectiveGasPrice().String())
+	RequireCosmosBalanceChange(ctx, s.T(), s.CommandParamsHomeDir(), tx, validatorAddr, true, sdkmath.NewInt(-1000000))
+	RequireCosmosBalanceChange(ctx, s.T(), s.CommandParamsHomeDir(), tx, recipientAddr, false, sdkmath.NewInt(1000000))
 
 	finalRecipientBalance, err := QueryBankBalances(ctx, s, recipientAddr)
 	require.NoError(s.T(), err, "Failed to query recipient balance after tx")
-
-	require.NotEqual(s.T(), initialValidatorBalance, finalValidatorBalance, "Validator balance should have changed")
-	require.NotEqual(s.T(), initialRecipientBalance, finalRecipientBalance, "Recipient balance should have changed")
-	require.Contains(s.T(), finalRecipientBalance, amount, "Recipient should have received the sent amount")
+	require.Equal(s.T(), amount, finalRecipientBalance, "Recipient should have received the sent amount")
 }
 
 func (s *TacchainTestSuite) TestInflationRate() {
@@ -129,9 +141,6 @@ func (s *TacchainTestSuite) TestDelegation() {
 	defer cancel()
 
 	params := s.DefaultCommandParams()
-	_, err := ExecuteCommand(ctx, params, "keys", "add", "delegator")
-	require.NoError(s.T(), err, "Failed to add delegator account")
-
 	delegatorAddr, err := GetAddress(ctx, s, "delegator")
 	require.NoError(s.T(), err, "Failed to get delegator address")
 
@@ -157,7 +166,7 @@ func (s *TacchainTestSuite) TestDelegation() {
 	require.NoError(s.T(), err, "Failed to query delegation")
 
 	delegatedAmount := parseBalanceAmount(output)
-	require.Contains(s.T(), delegatedAmount, delegationAmount, "Delegated amount should match")
+	require.Equal(s.T(), delegationAmount, delegatedAmount, "Delegated amount should match")
 }
 
 func (s *TacchainTestSuite) TestStakingAPR() {
@@ -166,9 +175,6 @@ func (s *TacchainTestSuite) TestStakingAPR() {
 
 	params := s.DefaultCommandParams()
 
-	_, err := ExecuteCommand(ctx, params, "keys", "add", "apr_delegator")
-	require.NoError(s.T(), err, "Failed to add delegator account")
-
 	delegatorAddr, err := GetAddress(ctx, s, "apr_delegator")
 	require.NoError(s.T(), err, "Failed to get delegator address")
 
@@ -183,7 +189,7 @@ func (s *TacchainTestSuite) TestStakingAPR() {
 
 	balance, err := QueryBankBalances(ctx, s, delegatorAddr)
 	require.NoError(s.T(), err, "Failed to query delegator balance")
-	require.Contains(s.T(), balance, initialAmount, "Delegator should have received the tokens")
+	require.Equal(s.T(), initialAmount, balance, "Delegator should have received the tokens")
 
 	delegationAmount := UTacAmount("10000000000000000")
 	output, err := ExecuteCommand(ctx, params, "tx", "staking", "delegate", validatorAddr,
@@ -224,3 +230,200 @@ func (s *TacchainTestSuite) TestStakingAPR() {
 	// require.Greater(s.T(), apr, 5.0, "APR should be greater than 5%")
 	// require.Less(s.T(), apr, 20.0, "APR should be less than 20%")
 }
+
+// TestSendToModuleAccountBlocked checks the transfers of users to a module
+// account, which the bank blocklist rejects. The send restriction also covers
+// the keeper flows moving funds between accounts without the blocklist, see
+// RecoveryTestSuite.TestRecoverToModuleAccountBlocked.
+func (s *TacchainTestSuite) TestSendToModuleAccountBlocked() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	mintAddr := ModuleAddress(minttypes.ModuleName)
+
+	// cosmos side: bank send is rejected before reaching the module account
+	_, err := TxBankSend(ctx, s, "validator", mintAddr, UTacAmount("1000000"))
+	require.Error(s.T(), err, "Bank send to the mint module account should fail")
+	require.ErrorContains(s.T(), err, "not allowed to receive funds")
+
+	// evm side: value transfer must not credit the module account
+	client, err := NewEthClient(ctx)
+	require.NoError(s.T(), err)
+	defer client.Close()
+
+	key, err := GetEthPrivateKey(ctx, s, "validator")
+	require.NoError(s.T(), err, "Failed to export validator eth key")
+
+	mintEthAddr := EthAddressFromBytes(authtypes.NewModuleAddress(minttypes.ModuleName))
+	receipt, err := SendEthTransfer(ctx, client, key, mintEthAddr, big.NewInt(1000000))
+	succeeded := err == nil && receipt.Status == gethtypes.ReceiptStatusSuccessful
+	require.False(s.T(), succeeded, "EVM transfer to the mint module account should fail")
+	if err == nil {
+		// the sender only pays the fee of the failed transfer
+		RequireEthTransferBalances(ctx, s.T(), client, crypto.PubkeyToAddress(key.PublicKey), mintEthAddr, big.NewInt(1000000), receipt)
+	}
+
+	balance, err := QueryBankBalances(ctx, s, mintAddr)
+	require.NoError(s.T(), err, "Failed to query mint module balance")
+	require.Equal(s.T(), UTacAmount("0"), balance, "Mint module account should not hold funds")
+}
+
+func (s *TacchainTestSuite) TestNodeInfoExtended() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	output, err := ExecuteCommand(ctx, s.CommandParamsHomeDir(), "q", "node-info-extended")
+	require.NoError(s.T(), err, "Failed to query extended node info: %s", output)
+
+	var metadata struct {
+		ChainID        string `json:"chain_id"`
+		EVMChainID     uint64 `json:"evm_chain_id"`
+		Bech32Prefixes struct {
+			AccountAddress string `json:"account_address"`
+		} `json:"bech32_prefixes"`
+		BaseDenom string `json:"base_denom"`
+		Endpoints struct {
+			RPC     string `json:"rpc"`
+			JSONRPC string `json:"json_rpc"`
+		} `json:"endpoints"`
+		Node struct {
+			LatestHeight int64 `json:"latest_block_height"`
+		} `json:"node"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &metadata), "Output should be a json document: %s", output)
+
+	require.Equal(s.T(), DefaultChainID, metadata.ChainID)
+	require.Equal(s.T(), uint64(2391), metadata.EVMChainID)
+	require.Equal(s.T(), DefaultBech32Prefix, metadata.Bech32Prefixes.AccountAddress)
+	require.Equal(s.T(), DefaultDenom, metadata.BaseDenom)
+	require.NotEmpty(s.T(), metadata.Endpoints.RPC)
+	require.NotEmpty(s.T(), metadata.Endpoints.JSONRPC, "JSON-RPC is enabled by the localnet config")
+	require.Positive(s.T(), metadata.Node.LatestHeight)
+}
+
+func (s *TacchainTestSuite) TestFaucet() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	const faucetAddr = "127.0.0.1:8000"
+	faucet := exec.CommandContext(ctx, "tacchaind", "faucet", "validator",
+		"--listen-addr", faucetAddr,
+		"--amount", UTacAmount("1000000"),
+		"--cooldown", "1h",
+		"--gas", "200000",
+		"--gas-prices", "100000000000utac",
+		"--chain-id", DefaultChainID,
+		"--keyring-backend", DefaultKeyringBackend,
+		"--home", s.homeDir,
+	)
+	require.NoError(s.T(), faucet.Start(), "Failed to start faucet")
+	defer func() {
+		_ = faucet.Process.Kill()
+		_ = faucet.Wait()
+	}()
+
+	recipientAddr, err := GetAddress(ctx, s, "faucet-recipient")
+	require.NoError(s.T(), err, "Failed to get recipient address")
+
+	request := func(address string) (int, string) {
+		body := fmt.Sprintf(`{"address":%q}`, address)
+		for {
+			res, err := http.Post("http://"+faucetAddr+"/", "application/json", strings.NewReader(body))
+			if err == nil {
+				defer res.Body.Close()
+				bz, _ := io.ReadAll(res.Body)
+				return res.StatusCode, string(bz)
+			}
+
+			// the faucet may still be starting up
+			select {
+			case <-ctx.Done():
+				s.T().Fatalf("Faucet did not respond: %v", err)
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+	}
+
+	status, body := request(recipientAddr)
+	require.Equal(s.T(), http.StatusOK, status, "Faucet request failed: %s", body)
+	txHash := parseField(body, "txhash")
+	require.NotEmpty(s.T(), txHash)
+
+	waitForNewBlock(s, nil)
+	output, err := ExecuteCommand(ctx, s.CommandParamsHomeDir(), "q", "tx", txHash)
+	require.NoError(s.T(), err, "Faucet tx should be committed: %s", output)
+
+	balance, err := QueryBankBalances(ctx, s, recipientAddr)
+	require.NoError(s.T(), err, "Failed to query recipient balance")
+	require.Equal(s.T(), UTacAmount("1000000"), balance, "Recipient should be funded by the faucet")
+
+	status, body = request(recipientAddr)
+	require.Equal(s.T(), http.StatusTooManyRequests, status, "Second request within the cooldown should be refused: %s", body)
+}
+
+type accountNonce struct {
+	EthAddress string `json:"eth_address"`
+	Sequence   uint64 `json:"sequence"`
+	Nonce      uint64 `json:"nonce"`
+}
+
+func (s *TacchainTestSuite) queryAccountNonce(ctx context.Context, address string) accountNonce {
+	output, err := ExecuteCommand(ctx, s.CommandParamsHomeDir(), "q", "account-nonce", address)
+	require.NoError(s.T(), err, "Failed to query account nonce: %s", output)
+
+	var nonce accountNonce
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &nonce), "Output should be a json document: %s", output)
+	require.Equal(s.T(), nonce.Sequence, nonce.Nonce, "Cosmos sequence and EVM nonce are the same counter")
+	return nonce
+}
+
+func (s *TacchainTestSuite) TestMixedCosmosAndEVMNonces() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	testerAddr, err := GetAddress(ctx, s, "nonce-tester")
+	require.NoError(s.T(), err, "Failed to get nonce tester address")
+	validatorAddr, err := GetAddress(ctx, s, "validator")
+	require.NoError(s.T(), err, "Failed to get validator address")
+
+	_, err = TxBankSend(ctx, s, "validator", testerAddr, UTacAmount("10000000000000000000"))
+	require.NoError(s.T(), err, "Failed to fund nonce tester")
+	waitForNewBlock(s, nil)
+
+	start := s.queryAccountNonce(ctx, testerAddr)
+	// the hex form of the address resolves to the same account
+	require.Equal(s.T(), start, s.queryAccountNonce(ctx, start.EthAddress))
+
+	client, err := NewEthClient(ctx)
+	require.NoError(s.T(), err)
+	defer client.Close()
+	key, err := GetEthPrivateKey(ctx, s, "nonce-tester")
+	require.NoError(s.T(), err, "Failed to export nonce tester eth key")
+	validatorEthAddr := common.HexToAddress(s.queryAccountNonce(ctx, validatorAddr).EthAddress)
+
+	// a cosmos tx followed right away by an evm tx using the next value, both
+	// are accepted no matter if they end up in the same block
+	_, err = TxBankSend(ctx, s, "nonce-tester", validatorAddr, UTacAmount("1"))
+	require.NoError(s.T(), err, "Cosmos tx should be accepted")
+	tx, err := BroadcastEthTransfer(ctx, client, key, validatorEthAddr, big.NewInt(1), start.Nonce+1)
+	require.NoError(s.T(), err, "EVM tx using the nonce after the cosmos tx should be accepted")
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status)
+	waitForNewBlock(s, nil)
+
+	current := s.queryAccountNonce(ctx, testerAddr)
+	require.Equal(s.T(), start.Sequence+2, current.Sequence, "Both txs should increment the shared counter")
+
+	// values used by the other kind of tx are rejected with a hint at the shared counter
+	output, err := ExecuteCommand(ctx, s.DefaultCommandParams(), "tx", "bank", "send", "nonce-tester", validatorAddr, UTacAmount("1"),
+		"--sequence", strconv.FormatUint(start.Sequence+1, 10), "--gas", "200000", "--gas-prices", "100000000000utac", "-y")
+	require.Error(s.T(), err, "Cosmos tx reusing the evm nonce should fail: %s", output)
+	require.Contains(s.T(), output, "same counter")
+
+	_, err = BroadcastEthTransfer(ctx, client, key, validatorEthAddr, big.NewInt(1), start.Nonce)
+	require.Error(s.T(), err, "EVM tx reusing the cosmos sequence should fail")
+	require.Contains(s.T(), err.Error(), "nonce")
+
+	require.Equal(s.T(), current, s.queryAccountNonce(ctx, testerAddr), "Rejected txs must not increment the counter")
+}
@@ -0,0 +1,167 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// init code storing 1 in slot 0, the runtime code clears slot 0 on every call
+	sstoreClearInitCode = common.FromHex("0x60016000556006601160003960066000f3600060005500")
+	// the runtime code self-destructs, sending the balance to the caller
+	selfDestructInitCode = common.FromHex("0x6002600c60003960026000f333ff")
+)
+
+// evmGasBreakdown mirrors the output of q evm-gas-breakdown
+type evmGasBreakdown struct {
+	GasLimit         uint64 `json:"gas_limit"`
+	Intrinsic        uint64 `json:"intrinsic"`
+	Execution        uint64 `json:"execution"`
+	RefundCounter    uint64 `json:"refund_counter"`
+	Refund           uint64 `json:"refund"`
+	EVMGasUsed       uint64 `json:"evm_gas_used"`
+	MinGasAdjustment uint64 `json:"min_gas_adjustment"`
+	GasCharged       uint64 `json:"gas_charged"`
+	CosmosGasUsed    int64  `json:"cosmos_gas_used"`
+}
+
+func (s *TacchainTestSuite) queryEVMGasBreakdown(ctx context.Context, receipt *gethtypes.Receipt) evmGasBreakdown {
+	output, err := ExecuteCommand(ctx, s.CommandParamsHomeDir(), "q", "evm-gas-breakdown", receipt.TxHash.Hex(), "--evm-rpc", DefaultJSONRPCAddress)
+	require.NoError(s.T(), err, "Failed to query gas breakdown: %s", output)
+
+	var b evmGasBreakdown
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &b), "Output should be a json document: %s", output)
+
+	// invariants that hold for every tx
+	require.Equal(s.T(), receipt.GasUsed, b.GasCharged)
+	require.Equal(s.T(), b.Intrinsic+b.Execution-b.Refund, b.EVMGasUsed)
+	require.Equal(s.T(), b.GasCharged, b.EVMGasUsed+b.MinGasAdjustment)
+	require.LessOrEqual(s.T(), b.Refund, (b.Intrinsic+b.Execution)/5, "Refund is capped at a fifth of the gas used")
+	// the cosmos gas meter of an EVM tx is set to the gas charged by the EVM
+	require.Equal(s.T(), int64(b.GasCharged), b.CosmosGasUsed)
+	return b
+}
+
+func (s *TacchainTestSuite) TestEVMGasIntrinsic() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	client, err := NewEthClient(ctx)
+	require.NoError(s.T(), err)
+	defer client.Close()
+	key, err := GetEthPrivateKey(ctx, s, "validator")
+	require.NoError(s.T(), err, "Failed to export validator eth key")
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	// a plain transfer only costs the intrinsic gas
+	receipt, err := SendEthTx(ctx, client, key, &to, big.NewInt(1), DefaultEthTransferGas, nil)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status)
+
+	b := s.queryEVMGasBreakdown(ctx, receipt)
+	require.Equal(s.T(), uint64(21000), b.Intrinsic)
+	require.Zero(s.T(), b.Execution)
+	require.Zero(s.T(), b.Refund)
+	require.Zero(s.T(), b.MinGasAdjustment)
+
+	// with a gas limit far above the usage the fee market min gas multiplier
+	// (0.5) decides the gas charged, not the EVM
+	receipt, err = SendEthTx(ctx, client, key, &to, big.NewInt(1), 100000, nil)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status)
+
+	b = s.queryEVMGasBreakdown(ctx, receipt)
+	require.Equal(s.T(), uint64(21000), b.EVMGasUsed)
+	require.Equal(s.T(), uint64(50000), b.GasCharged)
+	require.Equal(s.T(), uint64(29000), b.MinGasAdjustment)
+	// the sender pays for the gas charged, not the gas the EVM used
+	RequireEthTransferBalances(ctx, s.T(), client, crypto.PubkeyToAddress(key.PublicKey), to, big.NewInt(1), receipt)
+}
+
+func (s *TacchainTestSuite) TestEVMGasSStoreRefund() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	client, err := NewEthClient(ctx)
+	require.NoError(s.T(), err)
+	defer client.Close()
+	key, err := GetEthPrivateKey(ctx, s, "validator")
+	require.NoError(s.T(), err, "Failed to export validator eth key")
+
+	receipt, err := SendEthTx(ctx, client, key, nil, big.NewInt(0), 200000, sstoreClearInitCode)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "Contract deployment failed")
+	contract := receipt.ContractAddress
+
+	slot, err := client.StorageAt(ctx, contract, common.Hash{}, nil)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), common.BigToHash(big.NewInt(1)).Bytes(), slot)
+
+	// clearing a slot written by an earlier tx refunds 4800 (EIP-3529)
+	receipt, err = SendEthTx(ctx, client, key, &contract, big.NewInt(0), 30000, nil)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status)
+
+	b := s.queryEVMGasBreakdown(ctx, receipt)
+	require.Equal(s.T(), uint64(21000), b.Intrinsic)
+	// PUSH1, PUSH1, cold SSTORE resetting a slot, STOP
+	require.Equal(s.T(), uint64(3+3+2100+2900), b.Execution)
+	require.Equal(s.T(), uint64(4800), b.RefundCounter)
+	require.Equal(s.T(), uint64(4800), b.Refund)
+	require.Equal(s.T(), uint64(21000+5006-4800), b.GasCharged)
+
+	slot, err = client.StorageAt(ctx, contract, common.Hash{}, nil)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), common.Hash{}.Bytes(), slot)
+
+	// the slot is already empty, nothing is refunded any more
+	receipt, err = SendEthTx(ctx, client, key, &contract, big.NewInt(0), 30000, nil)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status)
+
+	b = s.queryEVMGasBreakdown(ctx, receipt)
+	require.Zero(s.T(), b.Refund)
+}
+
+func (s *TacchainTestSuite) TestEVMGasSelfDestruct() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	client, err := NewEthClient(ctx)
+	require.NoError(s.T(), err)
+	defer client.Close()
+	key, err := GetEthPrivateKey(ctx, s, "validator")
+	require.NoError(s.T(), err, "Failed to export validator eth key")
+
+	value := big.NewInt(1000000)
+	receipt, err := SendEthTx(ctx, client, key, nil, value, 200000, selfDestructInitCode)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "Contract deployment failed")
+	contract := receipt.ContractAddress
+
+	balance, err := client.BalanceAt(ctx, contract, nil)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), value, balance)
+
+	receipt, err = SendEthTx(ctx, client, key, &contract, big.NewInt(0), 50000, nil)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status)
+
+	// self-destruct doesn't refund gas since London (EIP-3529)
+	b := s.queryEVMGasBreakdown(ctx, receipt)
+	require.Zero(s.T(), b.RefundCounter)
+	require.Zero(s.T(), b.Refund)
+	require.Positive(s.T(), b.Execution)
+
+	// the balance is sent to the caller either way
+	balance, err = client.BalanceAt(ctx, contract, nil)
+	require.NoError(s.T(), err)
+	require.Zero(s.T(), balance.Sign(), "Self-destructed contract should have no balance left")
+}
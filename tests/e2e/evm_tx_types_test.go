@@ -0,0 +1,98 @@
+package e2e
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *TacchainTestSuite) TestEVMTxTypes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	client, err := NewEthClient(ctx)
+	require.NoError(s.T(), err)
+	defer client.Close()
+	key, err := GetEthPrivateKey(ctx, s, "validator")
+	require.NoError(s.T(), err, "Failed to export validator eth key")
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	chainID, err := client.ChainID(ctx)
+	require.NoError(s.T(), err)
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	require.NoError(s.T(), err)
+	signer := gethtypes.LatestSignerForChainID(chainID)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	accessList := gethtypes.AccessList{{Address: to, StorageKeys: []common.Hash{{0x01}}}}
+
+	nonce := func() uint64 {
+		nonce, err := client.PendingNonceAt(ctx, from)
+		require.NoError(s.T(), err)
+		return nonce
+	}
+
+	for _, newTx := range []func(nonce uint64) gethtypes.TxData{
+		func(nonce uint64) gethtypes.TxData {
+			return &gethtypes.LegacyTx{Nonce: nonce, To: &to, Value: big.NewInt(1), Gas: DefaultEthTransferGas, GasPrice: gasPrice}
+		},
+		func(nonce uint64) gethtypes.TxData {
+			return &gethtypes.AccessListTx{ChainID: chainID, Nonce: nonce, To: &to, Value: big.NewInt(1), Gas: 30000, GasPrice: gasPrice, AccessList: accessList}
+		},
+		func(nonce uint64) gethtypes.TxData {
+			return &gethtypes.DynamicFeeTx{
+				ChainID: chainID, Nonce: nonce, To: &to, Value: big.NewInt(1), Gas: 30000,
+				GasFeeCap: new(big.Int).Mul(gasPrice, big.NewInt(2)), GasTipCap: gasPrice, AccessList: accessList,
+			}
+		},
+	} {
+		tx, err := gethtypes.SignNewTx(key, signer, newTx(nonce()))
+		require.NoError(s.T(), err)
+		require.NoError(s.T(), client.SendTransaction(ctx, tx), "Tx of type %d should be accepted", tx.Type())
+		receipt, err := bind.WaitMined(ctx, client, tx)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status, "Tx of type %d failed", tx.Type())
+		require.Equal(s.T(), tx.Type(), receipt.Type)
+
+		// the tx is served back as it was signed
+		included, _, err := client.TransactionByHash(ctx, tx.Hash())
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), tx.Type(), included.Type())
+		require.Equal(s.T(), tx.AccessList(), included.AccessList())
+		require.Equal(s.T(), tx.GasFeeCap(), included.GasFeeCap())
+		require.Equal(s.T(), tx.GasTipCap(), included.GasTipCap())
+		sender, err := gethtypes.Sender(signer, included)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), from, sender)
+		if tx.Type() == gethtypes.DynamicFeeTxType {
+			require.LessOrEqual(s.T(), receipt.EffectiveGasPrice.Cmp(tx.GasFeeCap()), 0, "Effective gas price should be capped by the fee cap")
+		}
+	}
+
+	// blob txs are rejected before they reach the mempool
+	before := nonce()
+	blob, err := gethtypes.SignNewTx(key, signer, &gethtypes.BlobTx{
+		ChainID:    uint256.MustFromBig(chainID),
+		Nonce:      before,
+		To:         to,
+		Gas:        DefaultEthTransferGas,
+		GasFeeCap:  uint256.MustFromBig(gasPrice),
+		GasTipCap:  uint256.MustFromBig(gasPrice),
+		BlobFeeCap: uint256.NewInt(1),
+		BlobHashes: []common.Hash{{0x01}},
+	})
+	require.NoError(s.T(), err)
+	err = client.SendTransaction(ctx, blob)
+	require.Error(s.T(), err, "Blob tx should be rejected")
+	require.Contains(s.T(), err.Error(), "blob txs (EIP-4844, type 0x03) are not supported")
+
+	err = client.Client().CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode([]byte{0x05, 0xc0}))
+	require.Error(s.T(), err, "Tx of an unknown type should be rejected")
+	require.Equal(s.T(), before, nonce(), "Rejected txs should not use up the nonce")
+}
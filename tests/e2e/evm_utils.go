@@ -0,0 +1,111 @@
+package e2e
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	DefaultJSONRPCAddress = "http://127.0.0.1:8545"
+	DefaultEthTransferGas = uint64(21000)
+)
+
+func NewEthClient(ctx context.Context) (*ethclient.Client, error) {
+	client, err := ethclient.DialContext(ctx, DefaultJSONRPCAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial json-rpc: %v", err)
+	}
+	return client, nil
+}
+
+func GetEthPrivateKey(ctx context.Context, s *TacchainTestSuite, keyName string) (*ecdsa.PrivateKey, error) {
+	params := s.DefaultCommandParams()
+	output, err := ExecuteCommand(ctx, params, "keys", "unsafe-export-eth-key", keyName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export %s eth key: %v", keyName, err)
+	}
+
+	key, err := crypto.HexToECDSA(strings.TrimSpace(output))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s eth key: %v", keyName, err)
+	}
+	return key, nil
+}
+
+// EthAddressFromBytes converts a bech32-decoded address into its EVM representation.
+func EthAddressFromBytes(addr []byte) common.Address {
+	return common.BytesToAddress(addr)
+}
+
+// SignEthTx signs tx with the latest signer for the chain served by client.
+func SignEthTx(ctx context.Context, client *ethclient.Client, key *ecdsa.PrivateKey, tx *gethtypes.Transaction) (*gethtypes.Transaction, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain id: %v", err)
+	}
+	return gethtypes.SignTx(tx, gethtypes.LatestSignerForChainID(chainID), key)
+}
+
+// SendEthTransfer signs and broadcasts a plain value transfer and waits for it to be mined.
+func SendEthTransfer(ctx context.Context, client *ethclient.Client, key *ecdsa.PrivateKey, to common.Address, value *big.Int) (*gethtypes.Receipt, error) {
+	return SendEthTx(ctx, client, key, &to, value, DefaultEthTransferGas, nil)
+}
+
+// SendEthTx signs and broadcasts a tx with the next nonce of key and waits for
+// it to be mined. A nil to deploys data as init code.
+func SendEthTx(ctx context.Context, client *ethclient.Client, key *ecdsa.PrivateKey, to *common.Address, value *big.Int, gas uint64, data []byte) (*gethtypes.Receipt, error) {
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	nonce, err := client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %v", err)
+	}
+
+	tx, err := BroadcastEthTx(ctx, client, key, nonce, to, value, gas, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return bind.WaitMined(ctx, client, tx)
+}
+
+// BroadcastEthTransfer signs and broadcasts a plain value transfer with the
+// given nonce without waiting for it to be mined.
+func BroadcastEthTransfer(ctx context.Context, client *ethclient.Client, key *ecdsa.PrivateKey, to common.Address, value *big.Int, nonce uint64) (*gethtypes.Transaction, error) {
+	return BroadcastEthTx(ctx, client, key, nonce, &to, value, DefaultEthTransferGas, nil)
+}
+
+// BroadcastEthTx signs and broadcasts a tx with the given nonce without
+// waiting for it to be mined.
+func BroadcastEthTx(ctx context.Context, client *ethclient.Client, key *ecdsa.PrivateKey, nonce uint64, to *common.Address, value *big.Int, gas uint64, data []byte) (*gethtypes.Transaction, error) {
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %v", err)
+	}
+
+	tx, err := SignEthTx(ctx, client, key, gethtypes.NewTx(&gethtypes.LegacyTx{
+		Nonce:    nonce,
+		To:       to,
+		Value:    value,
+		Gas:      gas,
+		GasPrice: gasPrice,
+		Data:     data,
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx: %v", err)
+	}
+
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to send tx: %v", err)
+	}
+
+	return tx, nil
+}
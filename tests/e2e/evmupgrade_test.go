@@ -0,0 +1,162 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	evmupgradetypes "github.com/Asphere-xyz/tacchain/x/evmupgrade/types"
+)
+
+const EVMUpgradeChainID = "tacchain_2406-1"
+
+// EVMUpgradeTestSuite runs a dedicated chain scheduling an EIP activation and a
+// precompile deactivation through governance and checks the EVM on both sides
+// of the heights.
+type EVMUpgradeTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestEVMUpgradeTestSuite(t *testing.T) {
+	suite.Run(t, new(EVMUpgradeTestSuite))
+}
+
+func (s *EVMUpgradeTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: EVMUpgradeChainID, PortOffset: 1300}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *EVMUpgradeTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+// extraEIPs returns the extra EIPs of the EVM params at height
+func (s *EVMUpgradeTestSuite) extraEIPs(ctx context.Context, height int64) []int64 {
+	output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "evm", "params", "--height", strconv.FormatInt(height, 10), "--output", "json")
+	require.NoError(s.T(), err, "Failed to query evm params: %s", output)
+	var res struct {
+		Params struct {
+			ExtraEIPs []json.Number `json:"extra_eips"`
+		} `json:"params"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Output should be a json document: %s", output)
+
+	var eips []int64
+	for _, eip := range res.Params.ExtraEIPs {
+		n, err := strconv.ParseInt(eip.String(), 10, 64)
+		require.NoError(s.T(), err)
+		eips = append(eips, n)
+	}
+	return eips
+}
+
+func (s *EVMUpgradeTestSuite) TestActivateEIPAtHeight() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	const eip = 1153
+	require.NotContains(s.T(), s.extraEIPs(ctx, s.chain.Height(ctx)), int64(eip))
+
+	// the proposal passes within a few blocks, the voting period of the devnet
+	// preset is 4s
+	height := s.chain.Height(ctx) + 30
+	// subspaces decode their values as amino JSON, which quotes int64
+	activations := []map[string]string{{"eip": strconv.Itoa(eip), "height": strconv.FormatInt(height, 10)}}
+	err := s.chain.PassParamChange(ctx, "validator", evmupgradetypes.ModuleName, string(evmupgradetypes.KeyActivations), activations)
+	require.NoError(s.T(), err)
+	require.Less(s.T(), s.chain.Height(ctx), height, "The proposal should pass before the activation height")
+
+	output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "tac", "all-params")
+	require.NoError(s.T(), err, "Failed to query all params: %s", output)
+	var allParams struct {
+		EVMUpgrade evmupgradetypes.Params `json:"evmupgrade"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &allParams), "Output should be a json document: %s", output)
+	require.Equal(s.T(), []evmupgradetypes.Activation{{EIP: eip, Height: height}}, allParams.EVMUpgrade.Activations)
+
+	for s.chain.Height(ctx) <= height {
+		require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 1))
+	}
+
+	// the transactions of the activation height already run with the EIP
+	before := s.extraEIPs(ctx, height-1)
+	require.NotContains(s.T(), before, int64(eip))
+	after := s.extraEIPs(ctx, height)
+	require.Equal(s.T(), append(slices.Clone(before), eip), after)
+	require.Equal(s.T(), after, s.extraEIPs(ctx, height+1))
+
+	for h := height - 1; h <= height+1; h++ {
+		events, err := s.chain.BlockEvents(ctx, h)
+		require.NoError(s.T(), err)
+		activated, err := TypedEvents[*evmupgradetypes.EventActivateEIP](events)
+		require.NoError(s.T(), err)
+		if h != height {
+			require.Empty(s.T(), activated, "No EIP should be activated at %d", h)
+			continue
+		}
+		require.Len(s.T(), activated, 1)
+		require.Equal(s.T(), int64(eip), activated[0].Eip)
+	}
+}
+
+func (s *EVMUpgradeTestSuite) TestDeactivatePrecompileAtHeight() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	client, err := ethclient.DialContext(ctx, s.chain.JSONRPCAddress())
+	require.NoError(s.T(), err)
+	defer client.Close()
+
+	// totalSupply() of the bank precompile returns the supply of every denom
+	bank := common.HexToAddress("0x0000000000000000000000000000000000000804")
+	totalSupply := func(height int64) []byte {
+		output, err := client.CallContract(ctx, ethereum.CallMsg{To: &bank, Data: crypto.Keccak256([]byte("totalSupply()"))[:4]}, big.NewInt(height))
+		require.NoError(s.T(), err, "Call at height %d failed", height)
+		return output
+	}
+	require.NotEmpty(s.T(), totalSupply(s.chain.Height(ctx)), "Bank precompile should be active")
+
+	height := s.chain.Height(ctx) + 30
+	changes := []map[string]any{{"address": bank.Hex(), "active": false, "height": strconv.FormatInt(height, 10)}}
+	err = s.chain.PassParamChange(ctx, "validator", evmupgradetypes.ModuleName, string(evmupgradetypes.KeyPrecompileChanges), changes)
+	require.NoError(s.T(), err)
+	require.Less(s.T(), s.chain.Height(ctx), height, "The proposal should pass before the change height")
+
+	for s.chain.Height(ctx) <= height {
+		require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 1))
+	}
+
+	// the precompile serves calls up to the block before the change height, at
+	// the change height the address has no code and returns nothing
+	require.NotEmpty(s.T(), totalSupply(height-1))
+	require.Empty(s.T(), totalSupply(height))
+	require.Empty(s.T(), totalSupply(height+1))
+
+	events, err := s.chain.BlockEvents(ctx, height)
+	require.NoError(s.T(), err)
+	changed, err := TypedEvents[*evmupgradetypes.EventChangePrecompile](events)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), changed, 1)
+	require.Equal(s.T(), bank.Hex(), changed[0].Address)
+	require.False(s.T(), changed[0].Active)
+}
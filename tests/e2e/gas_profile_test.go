@@ -0,0 +1,85 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const GasProfileChainID = "tacchain_2404-1"
+
+// GasProfileTestSuite runs a dedicated chain with the gas profile of txs enabled in app.toml.
+type GasProfileTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestGasProfileTestSuite(t *testing.T) {
+	suite.Run(t, new(GasProfileTestSuite))
+}
+
+func (s *GasProfileTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: GasProfileChainID, PortOffset: 1200}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.SetAppConfig("gas-profile", "enabled", "true"); err != nil {
+		s.T().Fatalf("Failed to enable gas profile: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *GasProfileTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+func (s *GasProfileTestSuite) TestGasProfileAddsUpToGasUsed() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	output, err := s.chain.Tx(ctx, "validator", "bank", "send", "validator", randomAddress(), UTacAmount("1000"))
+	require.NoError(s.T(), err, "Failed to send: %s", output)
+	var res struct {
+		TxHash string `json:"txhash"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Tx command should have returned a tx response: %s", output)
+
+	output, err = ExecuteCommand(ctx, s.chain.QueryParams(), "q", "tx", res.TxHash, "--output", "json")
+	require.NoError(s.T(), err, "Failed to query tx: %s", output)
+	gasUsed, err := strconv.ParseInt(parseField(output, "gas_used"), 10, 64)
+	require.NoError(s.T(), err, "Failed to parse gas used: %s", output)
+
+	events, err := s.chain.TxEvents(ctx, fmt.Sprintf("tx.hash='%s'", res.TxHash))
+	require.NoError(s.T(), err)
+	var total int64
+	stages := make(map[string][]string)
+	for _, event := range events {
+		if event.Type != "gas_profile" {
+			continue
+		}
+		attrs := make(map[string]string)
+		for _, attr := range event.Attributes {
+			attrs[attr.Key] = attr.Value
+		}
+		gas, err := strconv.ParseInt(attrs["gas"], 10, 64)
+		require.NoError(s.T(), err, "Gas should be a number: %v", event)
+		total += gas
+		stages[attrs["stage"]] = append(stages[attrs["stage"]], attrs["name"])
+	}
+
+	require.Equal(s.T(), []string{"/cosmos.bank.v1beta1.MsgSend"}, stages["msg"])
+	require.Contains(s.T(), stages["ante"], "ante.SigVerificationDecorator")
+	require.Contains(s.T(), stages["ante"], "ante.ConsumeTxSizeGasDecorator")
+	require.Equal(s.T(), gasUsed, total, "The gas profile should add up to the gas used by the tx")
+}
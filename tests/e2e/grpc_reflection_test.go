@@ -0,0 +1,88 @@
+package e2e
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const DefaultGRPCAddress = "127.0.0.1:9090"
+
+// grpcServices lists the services of the node through gRPC server reflection,
+// like grpcurl list does.
+func grpcServices(ctx context.Context, address string) ([]string, error) {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = stream.CloseSend() }()
+
+	if err := stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{ListServices: "*"},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	var services []string
+	for _, service := range res.GetListServicesResponse().GetService() {
+		services = append(services, service.Name)
+	}
+	return services, nil
+}
+
+func (s *TacchainTestSuite) TestGRPCReflection() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	services, err := grpcServices(ctx, DefaultGRPCAddress)
+	require.NoError(s.T(), err, "gRPC server reflection should be enabled")
+
+	for _, service := range []string{
+		"cosmos.bank.v1beta1.Query",
+		"cosmos.tx.v1beta1.Service",
+		"cosmos.evm.vm.v1.Query",
+		"ibc.applications.transfer.v1.Query",
+		"cosmos.reflection.v1.ReflectionService",
+	} {
+		require.Contains(s.T(), services, service)
+	}
+}
+
+func (s *TacchainTestSuite) TestFileDescriptors() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	file := filepath.Join(s.T().TempDir(), "tacchain.binpb")
+	output, err := ExecuteCommand(ctx, s.DefaultCommandParams(), "q", "file-descriptors", file)
+	require.NoError(s.T(), err, "Failed to query file descriptors: %s", output)
+
+	bz, err := os.ReadFile(file)
+	require.NoError(s.T(), err)
+	var set descriptorpb.FileDescriptorSet
+	require.NoError(s.T(), proto.Unmarshal(bz, &set))
+
+	files := make(map[string]bool)
+	for _, fd := range set.File {
+		files[fd.GetName()] = true
+	}
+	require.True(s.T(), files["cosmos/bank/v1beta1/query.proto"], "Descriptor set should contain the SDK services")
+	require.True(s.T(), files["cosmos/evm/vm/v1/query.proto"], "Descriptor set should contain the EVM services")
+}
@@ -0,0 +1,139 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/Asphere-xyz/tacchain/x/tacgov"
+	tacgovtypes "github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+const (
+	HaltChainID = "tacchain_2415-1"
+
+	haltReason = "e2e emergency brake"
+	// haltDelay is the number of blocks between the submission of the halt
+	// proposal and the halt height, enough for the proposal to pass first
+	haltDelay = 30
+)
+
+// HaltTestSuite runs a network of validators with a short voting period, so
+// governance schedules a chain halt while the test runs.
+type HaltTestSuite struct {
+	suite.Suite
+
+	network *Network
+}
+
+func TestHaltTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("halt tests run a network of validators")
+	}
+	suite.Run(t, new(HaltTestSuite))
+}
+
+func (s *HaltTestSuite) SetupSuite() {
+	s.network = &Network{ChainID: HaltChainID}
+	if err := s.network.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize network: %v", err)
+	}
+	err := s.network.PatchGenesis(func(appState map[string]any) error {
+		gov, ok := appState["gov"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("genesis has no gov state")
+		}
+		params, ok := gov["params"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("genesis has no gov params")
+		}
+		params["voting_period"] = "20s"
+		params["expedited_voting_period"] = "10s"
+		return nil
+	})
+	if err != nil {
+		s.T().Fatalf("Failed to patch genesis: %v", err)
+	}
+	if err := s.network.Start(); err != nil {
+		s.T().Fatalf("Failed to start network: %v", err)
+	}
+}
+
+func (s *HaltTestSuite) TearDownSuite() {
+	if s.network != nil {
+		s.network.Cleanup()
+	}
+}
+
+func (s *HaltTestSuite) logContains(node *Chain, text string) bool {
+	bz, err := os.ReadFile(node.LogFile())
+	require.NoError(s.T(), err)
+	return strings.Contains(string(bz), text)
+}
+
+// TestGovernanceHalt passes a proposal halting the chain, checks every
+// validator stops at the same height, and resumes the chain by restarting the
+// validators with the halt height skipped.
+func (s *HaltTestSuite) TestGovernanceHalt() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	nodes := s.network.Nodes
+	haltHeight := nodes[0].Height(ctx) + haltDelay
+	// param changes take the amino JSON of the params, with quoted uint64s
+	proposalID, err := nodes[0].SubmitParamChange(ctx, "validator", tacgovtypes.ModuleName, map[string]any{
+		string(tacgovtypes.KeyHaltHeight): strconv.FormatInt(haltHeight, 10),
+		string(tacgovtypes.KeyHaltReason): haltReason,
+	})
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.network.PassProposal(ctx, proposalID))
+	require.Less(s.T(), nodes[0].Height(ctx), haltHeight, "The proposal should pass before the halt height")
+
+	output, err := ExecuteCommand(ctx, nodes[0].QueryParams(), "q", "tac", "all-params")
+	require.NoError(s.T(), err, "Failed to query all params: %s", output)
+	require.Contains(s.T(), output, haltReason)
+
+	// every validator stores the block at the halt height and stops before
+	// executing it, like at the height of an upgrade plan
+	for i, node := range nodes {
+		require.Eventually(s.T(), func() bool {
+			return node.Height(ctx) == haltHeight
+		}, 2*time.Minute, time.Second, "node%d should reach the halt height", i)
+	}
+	time.Sleep(10 * time.Second)
+	for i, node := range nodes {
+		require.Equal(s.T(), haltHeight, node.Height(ctx), "node%d should stay halted", i)
+		require.True(s.T(), s.logContains(node, fmt.Sprintf("CHAIN HALTED BY GOVERNANCE AT HEIGHT %d: %s", haltHeight, haltReason)),
+			"node%d should log the halt", i)
+	}
+
+	// the validators resume the chain once restarted with the halt skipped
+	for i, node := range nodes {
+		require.NoError(s.T(), node.Stop(), "Failed to stop node%d", i)
+	}
+	for i, node := range nodes {
+		node.StartFlags = []string{fmt.Sprintf("--%s=%d", tacgov.FlagUnsafeSkipHaltHeights, haltHeight)}
+		require.NoError(s.T(), node.launch(), "Failed to restart node%d", i)
+	}
+	for i, node := range nodes {
+		require.Eventually(s.T(), func() bool {
+			return node.Height(ctx) > haltHeight+1
+		}, 2*time.Minute, time.Second, "node%d should run past the halt height", i)
+		require.True(s.T(), s.logContains(node, "skipping the chain halt set by governance"), "node%d should log the skipped halt", i)
+	}
+	// the app hash of the next block is the state after the halt height
+	expectedAppHash, err := nodes[0].AppHash(ctx, haltHeight+1)
+	require.NoError(s.T(), err)
+	for i, node := range nodes[1:] {
+		appHash, err := node.AppHash(ctx, haltHeight+1)
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), expectedAppHash, appHash, "App hash of node%d diverged at the halt height", i+1)
+	}
+}
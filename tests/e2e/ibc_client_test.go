@@ -0,0 +1,186 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+)
+
+const (
+	ClientStatusActive  = "Active"
+	ClientStatusExpired = "Expired"
+	ClientStatusFrozen  = "Frozen"
+
+	// short trusting period used for clients that are expired on purpose
+	expiringTrustingPeriod = 20 * time.Second
+)
+
+// CreateClient creates a client on the host chain tracking the reference chain.
+// A zero trusting period uses the relayer default.
+func (r *Relayer) CreateClient(ctx context.Context, host, reference *Chain, trustingPeriod time.Duration) error {
+	args := []string{"create", "client", "--host-chain", host.ChainID, "--reference-chain", reference.ChainID}
+	if trustingPeriod > 0 {
+		args = append(args, "--trusting-period", trustingPeriod.String())
+	}
+
+	_, err := r.Exec(ctx, args...)
+	return err
+}
+
+// UpdateClient updates a client on the host chain to the latest height of its counterparty.
+func (r *Relayer) UpdateClient(ctx context.Context, host *Chain, clientID string) error {
+	_, err := r.Exec(ctx, "update", "client", "--host-chain", host.ChainID, "--client", clientID)
+	return err
+}
+
+func (c *Chain) ClientIDs(ctx context.Context) ([]string, error) {
+	output, err := ExecuteCommand(ctx, c.QueryParams(), "q", "ibc", "client", "states", "--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query client states: %v", err)
+	}
+
+	var res struct {
+		ClientStates []struct {
+			ClientID string `json:"client_id"`
+		} `json:"client_states"`
+	}
+	if err := json.Unmarshal([]byte(output), &res); err != nil {
+		return nil, fmt.Errorf("failed to parse client states: %v", err)
+	}
+
+	ids := make([]string, 0, len(res.ClientStates))
+	for _, state := range res.ClientStates {
+		ids = append(ids, state.ClientID)
+	}
+	return ids, nil
+}
+
+func (c *Chain) ClientStatus(ctx context.Context, clientID string) (string, error) {
+	output, err := ExecuteCommand(ctx, c.QueryParams(), "q", "ibc", "client", "status", clientID, "--output", "json")
+	if err != nil {
+		return "", fmt.Errorf("failed to query client status: %v", err)
+	}
+	return parseField(output, "status"), nil
+}
+
+// WaitForClientStatus polls the client on the chain until it reports the expected status.
+func (c *Chain) WaitForClientStatus(ctx context.Context, clientID, status string) error {
+	for {
+		current, err := c.ClientStatus(ctx, clientID)
+		if err != nil {
+			return err
+		}
+		if current == status {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("client %s on %s is %s, expected %s: %v", clientID, c.ChainID, current, status, ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// createClient creates a client on host tracking reference and returns its id.
+func (s *IBCTestSuite) createClient(ctx context.Context, host, reference *Chain, trustingPeriod time.Duration) string {
+	before, err := host.ClientIDs(ctx)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.relayer.CreateClient(ctx, host, reference, trustingPeriod))
+	after, err := host.ClientIDs(ctx)
+	require.NoError(s.T(), err)
+
+	existing := make(map[string]bool, len(before))
+	for _, id := range before {
+		existing[id] = true
+	}
+	for _, id := range after {
+		if !existing[id] {
+			return id
+		}
+	}
+
+	s.T().Fatalf("no new client created on %s", host.ChainID)
+	return ""
+}
+
+// freezeClient halts the counterparty so the client on host stops receiving
+// updates, waits until it expired and brings the counterparty back up.
+func (s *IBCTestSuite) freezeClient(ctx context.Context, host, counterparty *Chain, clientID string) {
+	require.NoError(s.T(), counterparty.Stop())
+	require.NoError(s.T(), host.WaitForClientStatus(ctx, clientID, ClientStatusExpired))
+	require.NoError(s.T(), counterparty.Start())
+}
+
+// submitRecoverClient submits a MsgRecoverClient governance proposal replacing
+// the state of the subject client with the substitute client.
+func (s *IBCTestSuite) submitRecoverClient(ctx context.Context, host *Chain, subjectID, substituteID string) string {
+	proposal := map[string]any{
+		"messages": []map[string]string{{
+			"@type":                "/ibc.core.client.v1.MsgRecoverClient",
+			"subject_client_id":    subjectID,
+			"substitute_client_id": substituteID,
+			"signer":               ModuleAddress(govtypes.ModuleName),
+		}},
+		"deposit": UTacAmount("10000000000000000"),
+		"title":   fmt.Sprintf("Recover client %s", subjectID),
+		"summary": fmt.Sprintf("Replace client %s with %s", subjectID, substituteID),
+	}
+	bz, err := json.Marshal(proposal)
+	require.NoError(s.T(), err)
+
+	proposalFile := filepath.Join(host.HomeDir, fmt.Sprintf("recover-%s.json", subjectID))
+	require.NoError(s.T(), os.WriteFile(proposalFile, bz, 0o644))
+
+	proposalID, err := host.SubmitProposal(ctx, "validator", proposalFile)
+	require.NoError(s.T(), err)
+	return proposalID
+}
+
+func (s *IBCTestSuite) TestRecoverExpiredClient() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	subjectID := s.createClient(ctx, s.chainA, s.chainB, expiringTrustingPeriod)
+	status, err := s.chainA.ClientStatus(ctx, subjectID)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), ClientStatusActive, status)
+
+	s.freezeClient(ctx, s.chainA, s.chainB, subjectID)
+
+	// an expired client can't be updated anymore
+	require.Error(s.T(), s.relayer.UpdateClient(ctx, s.chainA, subjectID), "Expired client update should fail")
+
+	substituteID := s.createClient(ctx, s.chainA, s.chainB, 0)
+	proposalID := s.submitRecoverClient(ctx, s.chainA, subjectID, substituteID)
+	require.NoError(s.T(), s.chainA.PassProposal(ctx, "validator", proposalID))
+
+	status, err = s.chainA.ClientStatus(ctx, subjectID)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), ClientStatusActive, status, "Subject client should be active after recovery")
+
+	// the recovered client takes the trusting period of the substitute and accepts updates again
+	require.NoError(s.T(), s.relayer.UpdateClient(ctx, s.chainA, subjectID))
+}
+
+func (s *IBCTestSuite) TestRecoverClientRejectsActiveSubject() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	subjectID := s.createClient(ctx, s.chainA, s.chainB, 0)
+	substituteID := s.createClient(ctx, s.chainA, s.chainB, 0)
+
+	proposalID := s.submitRecoverClient(ctx, s.chainA, subjectID, substituteID)
+	require.Error(s.T(), s.chainA.PassProposal(ctx, "validator", proposalID), "Proposal recovering an active client should fail")
+
+	status, err := s.chainA.ClientStatus(ctx, subjectID)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), ClientStatusActive, status)
+}
@@ -0,0 +1,239 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	IBCChainAID     = "tacchain_2392-1"
+	IBCChainBID     = "tacchain_2393-1"
+	IBCTransferPort = "transfer"
+	IBCFeeVersion   = `{"fee_version":"ics29-1","app_version":"ics20-1"}`
+	RelayerKeyName  = "relayer"
+)
+
+// Relayer drives a hermes instance connecting the chains of the harness.
+// Packets are relayed explicitly with ClearPackets so tests control timing.
+type Relayer struct {
+	HomeDir string
+	Chains  []*Chain
+}
+
+func (r *Relayer) configPath() string {
+	return filepath.Join(r.HomeDir, "config.toml")
+}
+
+func (r *Relayer) Exec(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "hermes", append([]string{"--config", r.configPath()}, args...)...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("hermes %s failed: %v: %s", strings.Join(args, " "), err, output)
+	}
+	return string(output), nil
+}
+
+func (r *Relayer) WriteConfig() error {
+	var cfg strings.Builder
+	cfg.WriteString(`[global]
+log_level = 'info'
+
+[mode.clients]
+enabled = true
+refresh = true
+misbehaviour = false
+
+[mode.connections]
+enabled = false
+
+[mode.channels]
+enabled = false
+
+[mode.packets]
+enabled = true
+clear_interval = 0
+clear_on_start = false
+tx_confirmation = true
+
+[rest]
+enabled = false
+host = '127.0.0.1'
+port = 3000
+
+[telemetry]
+enabled = false
+host = '127.0.0.1'
+port = 3001
+`)
+
+	for _, chain := range r.Chains {
+		rpc := strings.Replace(chain.RPCAddress(), "tcp://", "http://", 1)
+		ws := strings.Replace(chain.RPCAddress(), "tcp://", "ws://", 1) + "/websocket"
+		fmt.Fprintf(&cfg, `
+[[chains]]
+id = '%s'
+type = 'CosmosSdk'
+rpc_addr = '%s'
+grpc_addr = '%s'
+event_source = { mode = 'push', url = '%s', batch_delay = '200ms' }
+rpc_timeout = '10s'
+account_prefix = 'tac'
+key_name = '%s'
+key_store_type = 'Test'
+store_prefix = 'ibc'
+default_gas = 300000
+max_gas = 3000000
+gas_price = { price = 100000000000, denom = 'utac' }
+gas_multiplier = 1.5
+max_msg_num = 30
+max_tx_size = 2097152
+clock_drift = '5s'
+max_block_time = '30s'
+trusting_period = '1h'
+trust_threshold = { numerator = '1', denominator = '3' }
+address_type = { derivation = 'ethermint', proto_type = { pk_type = '/cosmos.evm.crypto.v1.ethsecp256k1.PubKey' } }
+`, chain.ChainID, rpc, chain.GRPCAddress(), ws, RelayerKeyName)
+	}
+
+	return os.WriteFile(r.configPath(), []byte(cfg.String()), 0o644)
+}
+
+// AddKey imports the relayer mnemonic for the given chain into hermes.
+func (r *Relayer) AddKey(ctx context.Context, chain *Chain, mnemonic string) error {
+	mnemonicFile := filepath.Join(r.HomeDir, chain.ChainID+".mnemonic")
+	if err := os.WriteFile(mnemonicFile, []byte(mnemonic), 0o600); err != nil {
+		return err
+	}
+
+	_, err := r.Exec(ctx, "keys", "add", "--chain", chain.ChainID, "--mnemonic-file", mnemonicFile, "--hd-path", "m/44'/60'/0'/0/0", "--overwrite")
+	return err
+}
+
+// CreateChannel creates a new client, connection and channel between chains a and b.
+func (r *Relayer) CreateChannel(ctx context.Context, a, b *Chain, version string) error {
+	args := []string{"create", "channel", "--a-chain", a.ChainID, "--b-chain", b.ChainID,
+		"--a-port", IBCTransferPort, "--b-port", IBCTransferPort, "--new-client-connection", "--yes"}
+	if version != "" {
+		args = append(args, "--channel-version", version)
+	}
+
+	_, err := r.Exec(ctx, args...)
+	return err
+}
+
+// ClearPackets relays all pending packets, acknowledgements and timeouts on a channel.
+func (r *Relayer) ClearPackets(ctx context.Context, chain *Chain, channel string) error {
+	_, err := r.Exec(ctx, "clear", "packets", "--chain", chain.ChainID, "--port", IBCTransferPort, "--channel", channel)
+	return err
+}
+
+// IBCTestSuite runs two tacchain networks connected by a hermes relayer. It is
+// skipped when hermes is not installed.
+type IBCTestSuite struct {
+	suite.Suite
+
+	chainA  *Chain
+	chainB  *Chain
+	relayer *Relayer
+}
+
+func (s *IBCTestSuite) SetupSuite() {
+	if _, err := exec.LookPath("hermes"); err != nil {
+		s.T().Skip("hermes not found in PATH, skipping IBC tests")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	s.chainA = &Chain{ChainID: IBCChainAID, PortOffset: 100}
+	s.chainB = &Chain{ChainID: IBCChainBID, PortOffset: 200}
+
+	for _, chain := range []*Chain{s.chainA, s.chainB} {
+		if err := chain.Init(); err != nil {
+			s.T().Fatalf("Failed to initialize chain: %v", err)
+		}
+		if err := chain.Start(); err != nil {
+			s.T().Fatalf("Failed to start chain: %v", err)
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "hermes")
+	if err != nil {
+		s.T().Fatalf("Failed to create relayer directory: %v", err)
+	}
+	s.relayer = &Relayer{HomeDir: dir, Chains: []*Chain{s.chainA, s.chainB}}
+	if err := s.relayer.WriteConfig(); err != nil {
+		s.T().Fatalf("Failed to write relayer config: %v", err)
+	}
+
+	for _, chain := range s.relayer.Chains {
+		mnemonic, err := chain.AddKey(ctx, RelayerKeyName)
+		if err != nil {
+			s.T().Fatalf("Failed to add relayer key: %v", err)
+		}
+
+		relayerAddr, err := chain.Address(ctx, RelayerKeyName)
+		if err != nil {
+			s.T().Fatalf("Failed to get relayer address: %v", err)
+		}
+
+		if _, err := chain.Tx(ctx, "validator", "bank", "send", "validator", relayerAddr, UTacAmount("100000000000000000000")); err != nil {
+			s.T().Fatalf("Failed to fund relayer: %v", err)
+		}
+
+		if err := s.relayer.AddKey(ctx, chain, mnemonic); err != nil {
+			s.T().Fatalf("Failed to import relayer key: %v", err)
+		}
+	}
+}
+
+func (s *IBCTestSuite) TearDownSuite() {
+	for _, chain := range []*Chain{s.chainA, s.chainB} {
+		if chain != nil {
+			chain.Cleanup()
+		}
+	}
+	if s.relayer != nil {
+		_ = os.RemoveAll(s.relayer.HomeDir)
+	}
+}
+
+// OpenChannel creates a new transfer channel between chain A and B and returns
+// the channel ids on both ends.
+func (s *IBCTestSuite) OpenChannel(ctx context.Context, version string) (string, string) {
+	before := s.channels(ctx, s.chainA)
+	s.Require().NoError(s.relayer.CreateChannel(ctx, s.chainA, s.chainB, version))
+
+	after := s.channels(ctx, s.chainA)
+	s.Require().Len(after, len(before)+1, "expected a new channel on %s", s.chainA.ChainID)
+
+	channel := after[len(after)-1]
+	return channel.ChannelID, channel.Counterparty.ChannelID
+}
+
+type channelInfo struct {
+	ChannelID    string `json:"channel_id"`
+	Version      string `json:"version"`
+	Counterparty struct {
+		ChannelID string `json:"channel_id"`
+	} `json:"counterparty"`
+}
+
+func (s *IBCTestSuite) channels(ctx context.Context, chain *Chain) []channelInfo {
+	output, err := ExecuteCommand(ctx, chain.QueryParams(), "q", "ibc", "channel", "channels", "--output", "json")
+	s.Require().NoError(err, "Failed to query channels: %s", output)
+
+	var res struct {
+		Channels []channelInfo `json:"channels"`
+	}
+	s.Require().NoError(json.Unmarshal([]byte(output), &res))
+	return res.Channels
+}
@@ -0,0 +1,217 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ibchookstypes "github.com/Asphere-xyz/tacchain/x/ibchooks/types"
+)
+
+const (
+	// fees are chosen well above the relayer's tx costs so the payout is observable
+	ibcRecvFee    = "1000000000000000000"
+	ibcAckFee     = "1000000000000000000"
+	ibcTimeoutFee = "1000000000000000000"
+	// fee paid for every tx sent with DefaultGas and DefaultGasPrice
+	ibcTxFee = "20000000000000000"
+)
+
+func TestIBCTestSuite(t *testing.T) {
+	suite.Run(t, new(IBCTestSuite))
+}
+
+func (s *IBCTestSuite) TestFeeEnabledChannel() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	channelA, _ := s.OpenChannel(ctx, IBCFeeVersion)
+
+	output, err := ExecuteCommand(ctx, s.chainA.QueryParams(), "q", "ibc-fee", "channel", IBCTransferPort, channelA)
+	require.NoError(s.T(), err, "Failed to query fee enabled channel: %s", output)
+	require.Contains(s.T(), output, "\"fee_enabled\":true", "Channel should be fee enabled")
+}
+
+func (s *IBCTestSuite) TestIncentivizedTransfer() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	channelA, channelB := s.OpenChannel(ctx, IBCFeeVersion)
+
+	relayerA, err := s.chainA.Address(ctx, RelayerKeyName)
+	require.NoError(s.T(), err)
+	relayerB, err := s.chainB.Address(ctx, RelayerKeyName)
+	require.NoError(s.T(), err)
+	receiver, err := s.chainB.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+
+	// the forward relayer on chain B gets its recv fee paid out to its address on chain A
+	_, err = s.chainB.Tx(ctx, RelayerKeyName, "ibc-fee", "register-counterparty-payee", IBCTransferPort, channelB, relayerB, relayerA)
+	require.NoError(s.T(), err, "Failed to register counterparty payee")
+
+	sequence := s.transferAndPayFee(ctx, channelA, receiver, "0")
+
+	output, err := ExecuteCommand(ctx, s.chainA.QueryParams(), "q", "ibc-fee", "packets-for-channel", IBCTransferPort, channelA, "--output", "json")
+	require.NoError(s.T(), err)
+	require.Contains(s.T(), output, fmt.Sprintf("\"sequence\":\"%d\"", sequence), "Packet fee should be escrowed")
+
+	relayerBalanceBefore := s.balance(ctx, s.chainA, relayerA)
+
+	s.relayPackets(ctx, channelA)
+
+	require.Empty(s.T(), s.incentivizedPackets(ctx, channelA), "Packet fees should be distributed")
+
+	// relayer A receives the recv fee and the ack fee, minus what it paid for relaying the ack
+	relayerBalanceAfter := s.balance(ctx, s.chainA, relayerA)
+	minIncrease, _ := new(big.Int).SetString(ibcRecvFee, 10)
+	require.Greater(s.T(), new(big.Int).Sub(relayerBalanceAfter, relayerBalanceBefore).Cmp(minIncrease), 0,
+		"Relayer should be paid the recv and ack fees")
+
+	ibcDenom := fmt.Sprintf("ibc/%s", s.denomHash(ctx, s.chainB, fmt.Sprintf("%s/%s/%s", IBCTransferPort, channelB, DefaultDenom)))
+	received, err := s.chainB.Balance(ctx, receiver, ibcDenom)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "1000000", received, "Receiver should get the transferred tokens")
+}
+
+func (s *IBCTestSuite) TestIncentivizedTransferTimeout() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	channelA, _ := s.OpenChannel(ctx, IBCFeeVersion)
+
+	sender, err := s.chainA.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+	receiver, err := s.chainB.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+
+	balanceBefore := s.balance(ctx, s.chainA, sender)
+
+	// a 1 second timeout expires before the packet is relayed
+	s.transferAndPayFee(ctx, channelA, receiver, strconv.FormatInt(time.Second.Nanoseconds(), 10))
+	require.NoError(s.T(), s.chainB.WaitForBlocks(ctx, 2))
+
+	s.relayPackets(ctx, channelA)
+
+	require.Empty(s.T(), s.incentivizedPackets(ctx, channelA), "Packet fees should be released")
+
+	// the transfer amount and the recv and ack fees are refunded, the timeout fee is paid to the relayer
+	expected := new(big.Int).Set(balanceBefore)
+	txFee, _ := new(big.Int).SetString(ibcTxFee, 10)
+	timeoutFee, _ := new(big.Int).SetString(ibcTimeoutFee, 10)
+	expected.Sub(expected, new(big.Int).Mul(txFee, big.NewInt(2)))
+	expected.Sub(expected, timeoutFee)
+	require.Equal(s.T(), expected.String(), s.balance(ctx, s.chainA, sender).String(),
+		"Sender should be refunded everything but the tx fees and the timeout fee")
+}
+
+func (s *IBCTestSuite) TestHookCallEvent() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	// an address without code accepts any call, which is all the hook needs
+	contract := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	err := s.chainA.PassParamChange(ctx, "validator", ibchookstypes.ModuleName, string(ibchookstypes.KeyAllowedContracts), []string{contract.Hex()})
+	require.NoError(s.T(), err, "Failed to whitelist contract")
+
+	channelA, channelB := s.OpenChannel(ctx, "")
+	senderB, err := s.chainB.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+
+	// only utac returning home can be attached to the call
+	_, err = s.chainA.Tx(ctx, "validator", "ibc-transfer", "transfer", IBCTransferPort, channelA, senderB, UTacAmount("1000000"))
+	require.NoError(s.T(), err, "Failed to send transfer")
+	s.relayPackets(ctx, channelA)
+
+	output, err := ExecuteCommand(ctx, s.chainB.QueryParams(), "q", "ibc", "channel", "next-sequence-send", IBCTransferPort, channelB, "--output", "json")
+	require.NoError(s.T(), err, "Failed to query next sequence: %s", output)
+	sequence := parseField(output, "next_sequence_send")
+
+	voucher := fmt.Sprintf("ibc/%s", s.denomHash(ctx, s.chainB, fmt.Sprintf("%s/%s/%s", IBCTransferPort, channelB, DefaultDenom)))
+	memo := fmt.Sprintf(`{"evm":{"contract":"%s","gas_limit":100000}}`, contract.Hex())
+	_, err = s.chainB.Tx(ctx, "validator", "ibc-transfer", "transfer", IBCTransferPort, channelB, contract.Hex(), "1000000"+voucher, "--memo", memo)
+	require.NoError(s.T(), err, "Failed to send transfer with hook call")
+	s.relayPackets(ctx, channelA)
+
+	// the call is part of the result of the relayer's recv tx on chain A
+	events, err := s.chainA.TxEvents(ctx, fmt.Sprintf("recv_packet.packet_sequence='%s' AND recv_packet.packet_dst_channel='%s'", sequence, channelA))
+	require.NoError(s.T(), err)
+	calls, err := TypedEvents[*ibchookstypes.EventEVMCall](events)
+	require.NoError(s.T(), err)
+
+	require.Len(s.T(), calls, 1, "Hook should call the contract once")
+	require.Equal(s.T(), contract.Hex(), calls[0].Contract)
+	require.Equal(s.T(), common.BytesToAddress(ibchookstypes.IntermediateSender(channelA, senderB)).Hex(), calls[0].Sender)
+	require.Equal(s.T(), "1000000", calls[0].Amount)
+	require.Positive(s.T(), calls[0].GasUsed)
+
+	balance, err := s.chainA.Balance(ctx, sdk.MustBech32ifyAddressBytes(DefaultBech32Prefix, contract.Bytes()), DefaultDenom)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "1000000", balance, "Contract should receive the value of the call")
+}
+
+// transferAndPayFee sends an ICS-20 transfer from chain A and escrows relayer
+// fees for it. A zero timeout uses the CLI default. It returns the packet sequence.
+func (s *IBCTestSuite) transferAndPayFee(ctx context.Context, channelA, receiver, timeoutNs string) uint64 {
+	output, err := ExecuteCommand(ctx, s.chainA.QueryParams(), "q", "ibc", "channel", "next-sequence-send", IBCTransferPort, channelA, "--output", "json")
+	require.NoError(s.T(), err, "Failed to query next sequence: %s", output)
+	sequence, err := strconv.ParseUint(parseField(output, "next_sequence_send"), 10, 64)
+	require.NoError(s.T(), err)
+
+	args := []string{"ibc-transfer", "transfer", IBCTransferPort, channelA, receiver, UTacAmount("1000000")}
+	if timeoutNs != "0" {
+		args = append(args, "--packet-timeout-timestamp", timeoutNs, "--packet-timeout-height", "0-0")
+	}
+	_, err = s.chainA.Tx(ctx, "validator", args...)
+	require.NoError(s.T(), err, "Failed to send transfer")
+
+	_, err = s.chainA.Tx(ctx, "validator", "ibc-fee", "pay-packet-fee", IBCTransferPort, channelA, strconv.FormatUint(sequence, 10),
+		"--recv-fee", UTacAmount(ibcRecvFee), "--ack-fee", UTacAmount(ibcAckFee), "--timeout-fee", UTacAmount(ibcTimeoutFee))
+	require.NoError(s.T(), err, "Failed to pay packet fee")
+
+	return sequence
+}
+
+// relayPackets clears the channel twice: packets are received on chain B
+// first, their acknowledgements are relayed back on the second pass.
+func (s *IBCTestSuite) relayPackets(ctx context.Context, channelA string) {
+	for i := 0; i < 2; i++ {
+		require.NoError(s.T(), s.relayer.ClearPackets(ctx, s.chainA, channelA))
+		require.NoError(s.T(), s.chainA.WaitForBlocks(ctx, 1))
+	}
+}
+
+func (s *IBCTestSuite) incentivizedPackets(ctx context.Context, channelA string) []json.RawMessage {
+	output, err := ExecuteCommand(ctx, s.chainA.QueryParams(), "q", "ibc-fee", "packets-for-channel", IBCTransferPort, channelA, "--output", "json")
+	require.NoError(s.T(), err, "Failed to query incentivized packets: %s", output)
+
+	var res struct {
+		IncentivizedPackets []json.RawMessage `json:"incentivized_packets"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &res))
+	return res.IncentivizedPackets
+}
+
+func (s *IBCTestSuite) balance(ctx context.Context, chain *Chain, address string) *big.Int {
+	amount, err := chain.Balance(ctx, address, DefaultDenom)
+	require.NoError(s.T(), err)
+
+	balance, ok := new(big.Int).SetString(amount, 10)
+	require.True(s.T(), ok, "Invalid balance %q", amount)
+	return balance
+}
+
+func (s *IBCTestSuite) denomHash(ctx context.Context, chain *Chain, trace string) string {
+	output, err := ExecuteCommand(ctx, chain.QueryParams(), "q", "ibc-transfer", "denom-hash", trace, "--output", "json")
+	require.NoError(s.T(), err, "Failed to query denom hash: %s", output)
+	return parseField(output, "hash")
+}
@@ -0,0 +1,137 @@
+package e2e
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+	"github.com/Asphere-xyz/tacchain/client/tacsdk/lightclient"
+)
+
+const LightClientChainID = "tacchain_2422-1"
+
+// LightClientTestSuite runs a dedicated chain verified by the light client of
+// tacsdk, trusting its first block.
+type LightClientTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestLightClientTestSuite(t *testing.T) {
+	suite.Run(t, new(LightClientTestSuite))
+}
+
+func (s *LightClientTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: LightClientChainID, PortOffset: 2800}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *LightClientTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+func (s *LightClientTestSuite) newClient(ctx context.Context) *lightclient.Client {
+	hash, err := s.chain.BlockHash(ctx, 1)
+	require.NoError(s.T(), err)
+	trustHash, err := hex.DecodeString(hash)
+	require.NoError(s.T(), err)
+
+	client, err := lightclient.New(ctx, lightclient.DefaultConfig(s.chain.ChainID, s.chain.RPCAddress(), 1, trustHash))
+	require.NoError(s.T(), err)
+	return client
+}
+
+// TestVerifyHeaders verifies the latest header from the trusted first block.
+func (s *LightClientTestSuite) TestVerifyHeaders() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 2))
+	client := s.newClient(ctx)
+	require.Equal(s.T(), uint64(2422), client.ChainID().EVMChainID)
+
+	latest, err := client.Update(ctx)
+	require.NoError(s.T(), err)
+	require.Greater(s.T(), latest.Height, int64(1))
+	require.Equal(s.T(), LightClientChainID, latest.ChainID)
+
+	// a header in between is verified backwards from the latest one
+	header, err := client.VerifyHeader(ctx, latest.Height-1)
+	require.NoError(s.T(), err)
+	hash, err := s.chain.BlockHash(ctx, header.Height)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), hash, header.Hash().String())
+}
+
+// TestQueryStore reads a balance from the bank store and proves it against
+// the app hash of a verified header, along with the absence of a balance.
+func (s *LightClientTestSuite) TestQueryStore() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	client := s.newClient(ctx)
+	address, err := s.chain.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+	accAddress := sdk.MustAccAddressFromBech32(address)
+
+	key := append(banktypes.CreateAccountBalancesPrefix(accAddress), []byte(DefaultDenom)...)
+	value, height, err := client.QueryStore(ctx, banktypes.StoreKey, key, 0)
+	require.NoError(s.T(), err)
+	var balance sdkmath.Int
+	require.NoError(s.T(), balance.Unmarshal(value))
+	expected, err := QueryBalanceAt(ctx, s.chain.QueryParams(), address, height)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), expected.String(), balance.String())
+
+	absentKey := append(banktypes.CreateAccountBalancesPrefix(sdk.MustAccAddressFromBech32(randomAddress())), []byte(DefaultDenom)...)
+	value, _, err = client.QueryStore(ctx, banktypes.StoreKey, absentKey, height)
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), value)
+}
+
+// TestVerifyTxProof verifies the proof of a bank send returned by q tac
+// tx-proof against a header verified by the light client.
+func (s *LightClientTestSuite) TestVerifyTxProof() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	client := s.newClient(ctx)
+	address, err := s.chain.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+	output, err := s.chain.Tx(ctx, "validator", "bank", "send", address, randomAddress(), UTacAmount("1"))
+	require.NoError(s.T(), err, "Failed to send tokens: %s", output)
+	txHash := parseField(output, "txhash")
+	require.NotEmpty(s.T(), txHash, "Tx command should have returned a tx response: %s", output)
+
+	// the result of the tx is committed by the block after it
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 2))
+	output, err = ExecuteCommand(ctx, s.chain.QueryParams(), "q", "tac", "tx-proof", txHash)
+	require.NoError(s.T(), err, "Failed to query tx proof: %s", output)
+	var proof tacsdk.TxProof
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &proof), "Output should be a json document: %s", output)
+	require.NoError(s.T(), client.VerifyTxProof(ctx, proof))
+	require.Zero(s.T(), proof.Result.Code)
+
+	// a proof with another result doesn't verify
+	proof.Result.GasUsed++
+	require.Error(s.T(), client.VerifyTxProof(ctx, proof))
+}
@@ -0,0 +1,183 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	MempoolTTLChainID = "tacchain_2420-1"
+
+	// mempoolTTLBlocks is the tx ttl of the full node, in blocks
+	mempoolTTLBlocks = 5
+	// validatorMinGasPrice is the minimum gas price of the validator, far
+	// above the gas price the full node suggests
+	validatorMinGasPrice = "10000000000000utac"
+)
+
+// MempoolTTLTestSuite runs a validator with high minimum gas prices and a
+// full node accepting lower ones, so an EVM tx sent to the full node is
+// never included and must be evicted from its mempool after its tx ttl.
+type MempoolTTLTestSuite struct {
+	suite.Suite
+
+	validator *Chain
+	node      *Chain
+	webhook   *httptest.Server
+	alerts    chan webhookAlert
+}
+
+func TestMempoolTTLTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("mempool ttl tests run a validator and a full node")
+	}
+	suite.Run(t, new(MempoolTTLTestSuite))
+}
+
+func (s *MempoolTTLTestSuite) SetupSuite() {
+	s.alerts = make(chan webhookAlert, 100)
+	s.webhook = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert webhookAlert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.alerts <- alert
+	}))
+
+	s.validator = &Chain{ChainID: MempoolTTLChainID, PortOffset: 2500}
+	if err := s.validator.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize validator: %v", err)
+	}
+	if err := s.validator.SetAppConfig("", "minimum-gas-prices", strconv.Quote(validatorMinGasPrice)); err != nil {
+		s.T().Fatalf("Failed to set the minimum gas prices: %v", err)
+	}
+	if err := s.validator.Start(); err != nil {
+		s.T().Fatalf("Failed to start validator: %v", err)
+	}
+
+	// the full node starts from the genesis of the validator and follows it
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	validatorAddr, err := s.validator.P2PAddress(ctx)
+	require.NoError(s.T(), err)
+	s.node = &Chain{ChainID: MempoolTTLChainID, PortOffset: 2600}
+	require.NoError(s.T(), s.node.Init())
+	genesis, err := os.ReadFile(filepath.Join(s.validator.HomeDir, "config", "genesis.json"))
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), os.WriteFile(filepath.Join(s.node.HomeDir, "config", "genesis.json"), genesis, 0o644))
+	require.NoError(s.T(), s.node.SetNodeConfig("p2p", "persistent_peers", strconv.Quote(validatorAddr)))
+	require.NoError(s.T(), s.node.SetAppConfig("tx-limits", "tx-ttl-blocks", strconv.Itoa(mempoolTTLBlocks)))
+	require.NoError(s.T(), s.node.SetAppConfig("alerting", "webhook-url", strconv.Quote(s.webhook.URL)))
+	require.NoError(s.T(), s.node.SetAppConfig("alerting", "events", `["tx_evicted"]`))
+	require.NoError(s.T(), s.node.launch())
+
+	require.Eventually(s.T(), func() bool {
+		catchingUp, err := s.node.CatchingUp(ctx)
+		return err == nil && !catchingUp && s.node.Height(ctx) >= s.validator.Height(ctx)
+	}, time.Minute, time.Second, "The full node should follow the validator, see %s", s.node.LogFile())
+}
+
+func (s *MempoolTTLTestSuite) TearDownSuite() {
+	if s.node != nil {
+		s.node.Cleanup()
+	}
+	if s.validator != nil {
+		s.validator.Cleanup()
+	}
+	if s.webhook != nil {
+		s.webhook.Close()
+	}
+}
+
+// mempoolEVMHashes returns the hashes of the EVM txs in the mempool of chain
+func (s *MempoolTTLTestSuite) mempoolEVMHashes(ctx context.Context, chain *Chain) []string {
+	output, err := ExecuteCommand(ctx, chain.QueryParams(), "q", "tac", "mempool")
+	require.NoError(s.T(), err, "Failed to query mempool: %s", output)
+	var mempool struct {
+		Txs []struct {
+			Msgs []struct {
+				EVM *struct {
+					Hash string `json:"hash"`
+				} `json:"evm"`
+			} `json:"msgs"`
+		} `json:"txs"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &mempool), "Output should be a json document: %s", output)
+	var hashes []string
+	for _, tx := range mempool.Txs {
+		for _, msg := range tx.Msgs {
+			if msg.EVM != nil {
+				hashes = append(hashes, msg.EVM.Hash)
+			}
+		}
+	}
+	return hashes
+}
+
+// TestEvictUnderpricedEVMTx sends an EVM tx paying the gas price of the full
+// node, below the minimum gas price of the validator: the validator rejects
+// it, the full node keeps rechecking it and evicts it after its tx ttl.
+func (s *MempoolTTLTestSuite) TestEvictUnderpricedEVMTx() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	client, err := ethclient.DialContext(ctx, s.node.JSONRPCAddress())
+	require.NoError(s.T(), err, "Failed to dial json-rpc")
+	defer client.Close()
+	key, err := s.validator.EthPrivateKey(ctx, "validator")
+	require.NoError(s.T(), err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	nonce, err := client.NonceAt(ctx, from, nil)
+	require.NoError(s.T(), err)
+
+	tx, err := BroadcastEthTransfer(ctx, client, key, common.HexToAddress("0x1111111111111111111111111111111111111111"), big.NewInt(1), nonce)
+	require.NoError(s.T(), err, "The full node should accept the tx")
+	sentAt := s.node.Height(ctx)
+	require.Contains(s.T(), s.mempoolEVMHashes(ctx, s.node), tx.Hash().Hex())
+	require.NotContains(s.T(), s.mempoolEVMHashes(ctx, s.validator), tx.Hash().Hex(), "The validator should reject the underpriced tx")
+
+	var alert webhookAlert
+	for alert.Event != "tx_evicted" {
+		select {
+		case alert = <-s.alerts:
+		case <-ctx.Done():
+			s.T().Fatalf("No tx_evicted alert received, see %s", s.node.LogFile())
+		}
+	}
+	require.Equal(s.T(), MempoolTTLChainID, alert.ChainID)
+	require.Equal(s.T(), tx.Hash().Hex(), alert.Details["evm_hash"])
+	require.Equal(s.T(), from.Hex(), alert.Details["sender"])
+	require.Equal(s.T(), strconv.FormatUint(nonce, 10), alert.Details["nonce"])
+	require.Equal(s.T(), "blocks", alert.Details["reason"])
+	require.Equal(s.T(), strconv.Itoa(mempoolTTLBlocks), alert.Details["blocks"])
+	require.GreaterOrEqual(s.T(), alert.Height, sentAt+mempoolTTLBlocks-1)
+
+	require.NotContains(s.T(), s.mempoolEVMHashes(ctx, s.node), tx.Hash().Hex(), "The tx should be evicted")
+	logs, err := os.ReadFile(s.node.LogFile())
+	require.NoError(s.T(), err)
+	require.Contains(s.T(), string(logs), "evicted tx from the mempool")
+
+	// the tx was never included and its nonce is still free
+	_, err = client.TransactionReceipt(ctx, tx.Hash())
+	require.True(s.T(), errors.Is(err, ethereum.NotFound), "The evicted tx should have no receipt: %v", err)
+	current, err := client.NonceAt(ctx, from, nil)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), nonce, current, "The nonce of the evicted tx should still be free")
+}
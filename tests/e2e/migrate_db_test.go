@@ -0,0 +1,87 @@
+package e2e
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const MigrateDBChainID = "tacchain_2402-1"
+
+// MigrateDBTestSuite runs a dedicated chain whose databases are migrated from
+// goleveldb to pebbledb while it is stopped.
+type MigrateDBTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestMigrateDBTestSuite(t *testing.T) {
+	suite.Run(t, new(MigrateDBTestSuite))
+}
+
+func (s *MigrateDBTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: MigrateDBChainID, PortOffset: 1000}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *MigrateDBTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+func (s *MigrateDBTestSuite) TestMigrateToPebbleDB() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	_, err := s.chain.Tx(ctx, "validator", "bank", "send", "validator", randomAddress(), UTacAmount("1000"))
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 2))
+
+	height := s.chain.Height(ctx)
+	appHash, err := s.chain.AppHash(ctx, height)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.chain.Stop())
+
+	output, err := ExecuteCommand(ctx, CommandParams{HomeDir: s.chain.HomeDir}, "tools", "migrate-db", "--from", "goleveldb", "--to", "pebbledb")
+	require.NoError(s.T(), err, "Failed to migrate databases: %s", output)
+	require.Contains(s.T(), output, "application: migrated")
+	require.Contains(s.T(), output, "blockstore: migrated")
+
+	configToml, err := os.ReadFile(filepath.Join(s.chain.HomeDir, "config", "config.toml"))
+	require.NoError(s.T(), err)
+	require.Contains(s.T(), string(configToml), `db_backend = "pebbledb"`)
+	appToml, err := os.ReadFile(filepath.Join(s.chain.HomeDir, "config", "app.toml"))
+	require.NoError(s.T(), err)
+	require.Contains(s.T(), string(appToml), `app-db-backend = "pebbledb"`)
+
+	// migrating again finds the backup of the first migration
+	output, err = ExecuteCommand(ctx, CommandParams{HomeDir: s.chain.HomeDir}, "tools", "migrate-db", "--from", "pebbledb", "--to", "goleveldb")
+	require.NoError(s.T(), err, "Failed to migrate databases back: %s", output)
+	output, err = ExecuteCommand(ctx, CommandParams{HomeDir: s.chain.HomeDir}, "tools", "migrate-db", "--to", "pebbledb")
+	require.Error(s.T(), err, "Migration should refuse to overwrite a backup")
+	require.Contains(s.T(), output, "goleveldb-backup")
+	require.NoError(s.T(), os.RemoveAll(filepath.Join(s.chain.HomeDir, "data", "goleveldb-backup")))
+	output, err = ExecuteCommand(ctx, CommandParams{HomeDir: s.chain.HomeDir}, "tools", "migrate-db", "--to", "pebbledb")
+	require.NoError(s.T(), err, "Failed to migrate databases: %s", output)
+
+	// the node continues the chain from the migrated state, CometBFT checks
+	// the app hash of the migrated application database when it starts
+	require.NoError(s.T(), s.chain.Start())
+	require.Greater(s.T(), s.chain.Height(ctx), height)
+	migratedAppHash, err := s.chain.AppHash(ctx, height)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), appHash, migratedAppHash)
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 2))
+}
@@ -0,0 +1,174 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	errortypes "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// generateSignedSend generates a bank send from the validator, lets edit change
+// the unsigned tx and returns the file of the signed tx.
+func (s *TacchainTestSuite) generateSignedSend(ctx context.Context, to, amount string, edit func(tx map[string]any)) string {
+	params := s.DefaultCommandParams()
+	unsigned, err := ExecuteCommand(ctx, params, "tx", "bank", "send", "validator", to, amount,
+		"--gas", DefaultGas, "--gas-prices", DefaultGasPrice, "--generate-only")
+	require.NoError(s.T(), err, "Failed to generate tx: %s", unsigned)
+
+	var tx map[string]any
+	require.NoError(s.T(), json.Unmarshal([]byte(unsigned), &tx), "Generated tx should be a json document: %s", unsigned)
+	if edit != nil {
+		edit(tx)
+	}
+	bz, err := json.Marshal(tx)
+	require.NoError(s.T(), err)
+	unsignedFile := filepath.Join(s.T().TempDir(), "unsigned.json")
+	require.NoError(s.T(), os.WriteFile(unsignedFile, bz, 0o600))
+
+	signed, err := ExecuteCommand(ctx, params, "tx", "sign", unsignedFile, "--from", "validator")
+	require.NoError(s.T(), err, "Failed to sign tx: %s", signed)
+	signedFile := filepath.Join(s.T().TempDir(), "signed.json")
+	require.NoError(s.T(), os.WriteFile(signedFile, []byte(signed), 0o600))
+	return signedFile
+}
+
+func (s *TacchainTestSuite) TestTxFailsWithInsufficientFunds() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	params := s.DefaultCommandParams()
+	_, err := ExecuteCommand(ctx, params, "keys", "add", "underfunded")
+	require.NoError(s.T(), err, "Failed to add account")
+	sender, err := GetAddress(ctx, s, "underfunded")
+	require.NoError(s.T(), err)
+	validator, err := GetAddress(ctx, s, "validator")
+	require.NoError(s.T(), err)
+
+	// enough to pay the fees, not the amount sent
+	output, err := TxBankSend(ctx, s, "validator", sender, UTacAmount("100000000000000000"))
+	require.NoError(s.T(), err, "Failed to fund account: %s", output)
+	waitForNewBlock(s, nil)
+
+	// the balance is only checked when the tx is executed
+	output, _ = ExecuteCommand(ctx, params, "tx", "bank", "send", "underfunded", validator, UTacAmount("1000000000000000000"),
+		"--gas", DefaultGas, "--gas-prices", DefaultGasPrice, "-y")
+	AssertTxFailure(ctx, s.T(), params, output, errortypes.ErrInsufficientFunds)
+}
+
+func (s *TacchainTestSuite) TestTxFailsWithWrongChainID() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	validator, err := GetAddress(ctx, s, "validator")
+	require.NoError(s.T(), err)
+
+	// the chain id is part of the sign bytes
+	params := CommandParams{HomeDir: s.homeDir, KeyringBackend: DefaultKeyringBackend}
+	output, _ := ExecuteCommand(ctx, params, "tx", "bank", "send", "validator", validator, UTacAmount("1"),
+		"--chain-id", "tacchain_9999-1", "--gas", DefaultGas, "--gas-prices", DefaultGasPrice, "-y")
+	AssertTxFailure(ctx, s.T(), s.DefaultCommandParams(), output, errortypes.ErrUnauthorized)
+	require.Contains(s.T(), output, "chain-id")
+}
+
+func (s *TacchainTestSuite) TestTxFailsWithBadSignature() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	validator, err := GetAddress(ctx, s, "validator")
+	require.NoError(s.T(), err)
+	signedFile := s.generateSignedSend(ctx, validator, UTacAmount("1"), nil)
+
+	bz, err := os.ReadFile(signedFile)
+	require.NoError(s.T(), err)
+	var tx struct {
+		Body       json.RawMessage `json:"body"`
+		AuthInfo   json.RawMessage `json:"auth_info"`
+		Signatures [][]byte        `json:"signatures"`
+	}
+	require.NoError(s.T(), json.Unmarshal(bz, &tx))
+	require.Len(s.T(), tx.Signatures, 1)
+	tx.Signatures[0][0] ^= 0xff
+	bz, err = json.Marshal(tx)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), os.WriteFile(signedFile, bz, 0o600))
+
+	output, _ := ExecuteCommand(ctx, s.DefaultCommandParams(), "tx", "broadcast", signedFile)
+	AssertTxFailure(ctx, s.T(), s.DefaultCommandParams(), output, errortypes.ErrUnauthorized)
+}
+
+func (s *TacchainTestSuite) TestTxFailsWithInvalidDenom() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	validator, err := GetAddress(ctx, s, "validator")
+	require.NoError(s.T(), err)
+
+	// the CLI doesn't build coins with invalid denoms, edit the generated tx
+	signedFile := s.generateSignedSend(ctx, validator, UTacAmount("1"), func(tx map[string]any) {
+		msg := tx["body"].(map[string]any)["messages"].([]any)[0].(map[string]any)
+		msg["amount"] = []map[string]string{{"denom": "u!", "amount": "1"}}
+	})
+
+	output, _ := ExecuteCommand(ctx, s.DefaultCommandParams(), "tx", "broadcast", signedFile)
+	AssertTxFailure(ctx, s.T(), s.DefaultCommandParams(), output, errortypes.ErrInvalidCoins)
+}
+
+func (s *TacchainTestSuite) TestTxFailsExceedingBlockGas() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	output, err := ExecuteCommand(ctx, s.CommandParamsHomeDir(), "q", "consensus", "params", "--output", "json")
+	require.NoError(s.T(), err, "Failed to query consensus params: %s", output)
+	maxGas, err := strconv.ParseInt(parseField(output, "max_gas"), 10, 64)
+	require.NoError(s.T(), err, "Failed to parse block max gas: %s", output)
+	require.Positive(s.T(), maxGas, "Block gas should be limited")
+
+	validator, err := GetAddress(ctx, s, "validator")
+	require.NoError(s.T(), err)
+	output, _ = ExecuteCommand(ctx, s.DefaultCommandParams(), "tx", "bank", "send", "validator", validator, UTacAmount("1"),
+		"--gas", strconv.FormatInt(maxGas+1, 10), "--gas-prices", DefaultGasPrice, "-y")
+	AssertTxFailure(ctx, s.T(), s.DefaultCommandParams(), output, errortypes.ErrInvalidGasLimit)
+}
+
+func (s *TacchainTestSuite) TestUnderpricedEVMTxFails() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	client, err := NewEthClient(ctx)
+	require.NoError(s.T(), err)
+	defer client.Close()
+	key, err := GetEthPrivateKey(ctx, s, "validator")
+	require.NoError(s.T(), err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	nonce, err := client.PendingNonceAt(ctx, from)
+	require.NoError(s.T(), err)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	tx, err := SignEthTx(ctx, client, key, gethtypes.NewTx(&gethtypes.LegacyTx{
+		Nonce:    nonce,
+		To:       &to,
+		Value:    big.NewInt(1),
+		Gas:      DefaultEthTransferGas,
+		GasPrice: big.NewInt(1),
+	}))
+	require.NoError(s.T(), err)
+
+	err = client.SendTransaction(ctx, tx)
+	require.Error(s.T(), err, "Tx paying less than the min gas price should be rejected")
+	require.Contains(s.T(), err.Error(), errortypes.ErrInsufficientFee.Error())
+
+	// rejected txs don't use up the nonce
+	pendingNonce, err := client.PendingNonceAt(ctx, from)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), nonce, pendingNonce)
+}
@@ -0,0 +1,400 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NetworkValidators is the number of validators contrib/localnet/init-multi-node.sh sets up
+const NetworkValidators = 4
+
+// Network is a network of validators started from contrib/localnet/init-multi-node.sh,
+// each with the same stake. The nodes only connect to each other through
+// in-process proxies, which inject latency and partitions into their links.
+//
+// The script uses fixed ports: node i listens for p2p at 451<i+1>0 and for rpc
+// at 451<i+1>1, so only one network can run at a time.
+type Network struct {
+	ChainID string
+	Nodes   []*Chain
+
+	dir string
+	// links[i][j] is the proxy node i dials node j through
+	links [][]*linkProxy
+
+	twins     []*Chain
+	twinLinks []*linkProxy
+}
+
+func (n *Network) Init() error {
+	dir, err := os.MkdirTemp("", n.ChainID)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	n.dir = dir
+
+	pwd, _ := os.Getwd()
+	initScript := filepath.Join(pwd, "../../contrib/localnet/init-multi-node.sh")
+	cmd := exec.Command("bash", "-c", fmt.Sprintf("echo y | %s", initScript))
+	cmd.Env = append(os.Environ(), "HOMEDIR="+n.dir, "CHAIN_ID="+n.ChainID)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to initialize network %s: %v", n.ChainID, err)
+	}
+
+	n.Nodes = make([]*Chain, NetworkValidators)
+	for i := range n.Nodes {
+		// shifts the default p2p and rpc ports to the ones of the script
+		n.Nodes[i] = &Chain{
+			ChainID:    n.ChainID,
+			HomeDir:    filepath.Join(n.dir, fmt.Sprintf("node%d", i)),
+			PortOffset: 45110 + 10*i - 26656,
+		}
+	}
+
+	n.links = make([][]*linkProxy, len(n.Nodes))
+	for i, node := range n.Nodes {
+		n.links[i] = make([]*linkProxy, len(n.Nodes))
+		var peers []*Chain
+		for j, peer := range n.Nodes {
+			if i != j {
+				peers = append(peers, peer)
+			}
+		}
+		links, err := connectThroughProxies(node, peers)
+		if err != nil {
+			return err
+		}
+		for k, peer := range peers {
+			n.links[i][slices.Index(n.Nodes, peer)] = links[k]
+		}
+	}
+	return nil
+}
+
+// connectThroughProxies makes node dial each of peers through a new proxy and
+// returns the proxies in the order of peers.
+func connectThroughProxies(node *Chain, peers []*Chain) ([]*linkProxy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	links := make([]*linkProxy, len(peers))
+	addresses := make([]string, len(peers))
+	for k, peer := range peers {
+		proxy, err := newLinkProxy(fmt.Sprintf("127.0.0.1:%d", peer.port(26656)))
+		if err != nil {
+			return nil, err
+		}
+		links[k] = proxy
+
+		peerID, err := peer.NodeID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		addresses[k] = fmt.Sprintf("%s@%s", peerID, proxy.Address())
+	}
+
+	// without peer exchange the nodes can't learn each other's real
+	// addresses and bypass the proxies
+	for key, value := range map[string]string{
+		"persistent_peers":                 fmt.Sprintf("%q", strings.Join(addresses, ",")),
+		"seeds":                            `""`,
+		"pex":                              "false",
+		"persistent_peers_max_dial_period": `"2s"`,
+	} {
+		if err := node.SetNodeConfig("p2p", key, value); err != nil {
+			return nil, err
+		}
+	}
+	return links, nil
+}
+
+// PatchGenesis applies patch to the app state of the genesis of every node,
+// between Init and Start, see Chain.PatchGenesis.
+func (n *Network) PatchGenesis(patch func(appState map[string]any) error) error {
+	if err := n.Nodes[0].PatchGenesis(patch); err != nil {
+		return err
+	}
+	bz, err := os.ReadFile(filepath.Join(n.Nodes[0].HomeDir, "config", "genesis.json"))
+	if err != nil {
+		return fmt.Errorf("failed to read genesis: %v", err)
+	}
+	for _, node := range n.Nodes[1:] {
+		if err := os.WriteFile(filepath.Join(node.HomeDir, "config", "genesis.json"), bz, 0o644); err != nil {
+			return fmt.Errorf("failed to write genesis: %v", err)
+		}
+	}
+	return nil
+}
+
+// Start starts all validators and waits until they produce blocks.
+func (n *Network) Start() error {
+	for _, node := range n.Nodes {
+		if err := node.launch(); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	return n.Nodes[0].WaitForBlocks(ctx, 2)
+}
+
+// PassProposal votes yes with the validator of every node and waits until
+// the proposal passed, no validator of the network can pass it on its own.
+func (n *Network) PassProposal(ctx context.Context, proposalID string) error {
+	for i, node := range n.Nodes[1:] {
+		if output, err := node.Tx(ctx, "validator", "gov", "vote", proposalID, "yes"); err != nil {
+			return fmt.Errorf("failed to vote on proposal %s with node%d: %v: %s", proposalID, i+1, err, output)
+		}
+	}
+	return n.Nodes[0].PassProposal(ctx, "validator", proposalID)
+}
+
+func (n *Network) Cleanup() {
+	for _, node := range append(n.Nodes, n.twins...) {
+		_ = node.Stop()
+	}
+	for _, links := range append(n.links, n.twinLinks) {
+		for _, link := range links {
+			if link != nil {
+				link.Close()
+			}
+		}
+	}
+	_ = os.RemoveAll(n.dir)
+}
+
+// StartByzantineTwin starts a second node signing with the key of validator
+// i, like an operator running a failover node without a remote signer. Both
+// nodes propose and vote on their own, so the network sees conflicting
+// proposals and votes of validator i, which equivocates as soon as the two
+// disagree, e.g. when it is the proposer and each node proposes its own block.
+//
+// The twin listens on the ports 4515<n> and connects to all nodes of the
+// network, only one twin can run at a time.
+func (n *Network) StartByzantineTwin(i int) (*Chain, error) {
+	original := n.Nodes[i]
+	twin := &Chain{
+		ChainID:    n.ChainID,
+		HomeDir:    filepath.Join(n.dir, fmt.Sprintf("node%d-twin", i)),
+		PortOffset: 45150 - 26656,
+	}
+
+	for _, dir := range []string{"config", "data"} {
+		if err := os.MkdirAll(filepath.Join(twin.HomeDir, dir), 0o755); err != nil {
+			return nil, err
+		}
+	}
+	// the twin gets its own node key when it starts and a fresh sign state,
+	// which lets it sign the heights the original signed
+	for _, file := range []string{"genesis.json", "config.toml", "app.toml", "client.toml", "priv_validator_key.json"} {
+		bz, err := os.ReadFile(filepath.Join(original.HomeDir, "config", file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s of node%d: %v", file, i, err)
+		}
+		// all ports of node i are 451<i+1><n>
+		bz = []byte(strings.ReplaceAll(string(bz), fmt.Sprintf(":451%d", i+1), ":4515"))
+		if err := os.WriteFile(filepath.Join(twin.HomeDir, "config", file), bz, 0o600); err != nil {
+			return nil, err
+		}
+	}
+	state := []byte(`{"height":"0","round":0,"step":0}`)
+	if err := os.WriteFile(filepath.Join(twin.HomeDir, "data", "priv_validator_state.json"), state, 0o600); err != nil {
+		return nil, err
+	}
+
+	links, err := connectThroughProxies(twin, n.Nodes)
+	if err != nil {
+		return nil, err
+	}
+	n.twinLinks = append(n.twinLinks, links...)
+
+	if err := twin.launch(); err != nil {
+		return nil, err
+	}
+	n.twins = append(n.twins, twin)
+	return twin, nil
+}
+
+// JSONRPCAddress returns the EVM JSON-RPC endpoint of node i, at 451<i+1>8
+// like the other ports of the script, which the port offset of the node
+// doesn't shift to.
+func (n *Network) JSONRPCAddress(i int) string {
+	return fmt.Sprintf("http://127.0.0.1:%d", 45118+10*i)
+}
+
+// Isolate cuts all links of node i, existing connections are closed and new
+// ones refused until Heal.
+func (n *Network) Isolate(i int) {
+	for j := range n.Nodes {
+		if i != j {
+			n.links[i][j].SetCut(true)
+			n.links[j][i].SetCut(true)
+		}
+	}
+}
+
+// Heal restores all links of the network.
+func (n *Network) Heal() {
+	n.forEachLink(func(link *linkProxy) { link.SetCut(false) })
+}
+
+// SetLatency delays the traffic on all links of the network by latency.
+func (n *Network) SetLatency(latency time.Duration) {
+	n.forEachLink(func(link *linkProxy) { link.SetLatency(latency) })
+}
+
+func (n *Network) forEachLink(f func(link *linkProxy)) {
+	for i, links := range n.links {
+		for j, link := range links {
+			if i != j {
+				f(link)
+			}
+		}
+	}
+}
+
+// linkProxy forwards the p2p connections of one node to another. Connections
+// are authenticated end to end by CometBFT, so the proxy is transparent to
+// the nodes.
+type linkProxy struct {
+	listener net.Listener
+	target   string
+
+	mu      sync.Mutex
+	latency time.Duration
+	cut     bool
+	conns   map[net.Conn]struct{}
+}
+
+func newLinkProxy(target string) (*linkProxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for proxy to %s: %v", target, err)
+	}
+
+	p := &linkProxy{listener: listener, target: target, conns: make(map[net.Conn]struct{})}
+	go p.serve()
+	return p, nil
+}
+
+func (p *linkProxy) Address() string {
+	return p.listener.Addr().String()
+}
+
+func (p *linkProxy) SetLatency(latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.latency = latency
+}
+
+// SetCut closes the connections of a cut link and refuses new ones until
+// the link is restored.
+func (p *linkProxy) SetCut(cut bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cut = cut
+	if cut {
+		for conn := range p.conns {
+			_ = conn.Close()
+		}
+	}
+}
+
+func (p *linkProxy) Close() {
+	_ = p.listener.Close()
+	p.SetCut(true)
+}
+
+func (p *linkProxy) serve() {
+	for {
+		conn, err := p.listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		} else if err != nil {
+			continue
+		}
+		go p.forward(conn)
+	}
+}
+
+func (p *linkProxy) forward(conn net.Conn) {
+	target, err := net.Dial("tcp", p.target)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	p.mu.Lock()
+	if p.cut {
+		p.mu.Unlock()
+		_ = conn.Close()
+		_ = target.Close()
+		return
+	}
+	p.conns[conn] = struct{}{}
+	p.conns[target] = struct{}{}
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); p.pipe(target, conn) }()
+	go func() { defer wg.Done(); p.pipe(conn, target) }()
+	wg.Wait()
+
+	p.mu.Lock()
+	delete(p.conns, conn)
+	delete(p.conns, target)
+	p.mu.Unlock()
+}
+
+// pipe copies src to dst, delivering every chunk read the latency of the link
+// after it was read. Closing either side closes both.
+func (p *linkProxy) pipe(dst, src net.Conn) {
+	defer dst.Close()
+	defer src.Close()
+
+	type chunk struct {
+		data []byte
+		read time.Time
+	}
+	chunks := make(chan chunk, 1024)
+	go func() {
+		defer close(chunks)
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := src.Read(buf)
+			if n > 0 {
+				chunks <- chunk{data: append([]byte(nil), buf[:n]...), read: time.Now()}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for c := range chunks {
+		p.mu.Lock()
+		latency := p.latency
+		p.mu.Unlock()
+		time.Sleep(time.Until(c.read.Add(latency)))
+		if _, err := dst.Write(c.data); err != nil {
+			// unblock the reader and let it finish
+			_ = src.Close()
+			for range chunks {
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	evmupgradetypes "github.com/Asphere-xyz/tacchain/x/evmupgrade/types"
+)
+
+const (
+	PrecompileGovernanceChainID = "tacchain_2410-1"
+
+	bankPrecompile = "0x0000000000000000000000000000000000000804"
+	// the bank precompile is deactivated at deactivationHeight and activated
+	// again at reactivationHeight
+	deactivationHeight = 10
+	reactivationHeight = 16
+)
+
+// PrecompileGovernanceTestSuite runs a network of validators whose genesis
+// schedules the deactivation and reactivation of the bank precompile, and
+// checks every validator switches at the same heights.
+type PrecompileGovernanceTestSuite struct {
+	suite.Suite
+
+	network *Network
+}
+
+func TestPrecompileGovernanceTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("precompile governance tests run a network of validators")
+	}
+	suite.Run(t, new(PrecompileGovernanceTestSuite))
+}
+
+func (s *PrecompileGovernanceTestSuite) SetupSuite() {
+	s.network = &Network{ChainID: PrecompileGovernanceChainID}
+	if err := s.network.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize network: %v", err)
+	}
+	err := s.network.PatchGenesis(func(appState map[string]any) error {
+		evmupgrade, ok := appState[evmupgradetypes.ModuleName].(map[string]any)
+		if !ok {
+			return fmt.Errorf("genesis has no evmupgrade state")
+		}
+		// the module genesis is plain JSON, unlike the amino JSON of param changes
+		params := evmupgradetypes.DefaultParams()
+		params.PrecompileChanges = []evmupgradetypes.PrecompileChange{
+			{Address: bankPrecompile, Active: false, Height: deactivationHeight},
+			{Address: bankPrecompile, Active: true, Height: reactivationHeight},
+		}
+		evmupgrade["params"] = params
+		return nil
+	})
+	if err != nil {
+		s.T().Fatalf("Failed to schedule the precompile changes: %v", err)
+	}
+	if err := s.network.Start(); err != nil {
+		s.T().Fatalf("Failed to start network: %v", err)
+	}
+}
+
+func (s *PrecompileGovernanceTestSuite) TearDownSuite() {
+	if s.network != nil {
+		s.network.Cleanup()
+	}
+}
+
+// activePrecompiles returns the active static precompiles of the EVM params
+// of node at height
+func (s *PrecompileGovernanceTestSuite) activePrecompiles(ctx context.Context, node *Chain, height int64) []string {
+	output, err := ExecuteCommand(ctx, node.QueryParams(), "q", "evm", "params", "--height", strconv.FormatInt(height, 10), "--output", "json")
+	require.NoError(s.T(), err, "Failed to query evm params: %s", output)
+	var res struct {
+		Params struct {
+			ActiveStaticPrecompiles []string `json:"active_static_precompiles"`
+		} `json:"params"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Output should be a json document: %s", output)
+	return res.Params.ActiveStaticPrecompiles
+}
+
+func (s *PrecompileGovernanceTestSuite) TestPrecompileChangeBoundaries() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	nodes := s.network.Nodes
+	require.Less(s.T(), nodes[0].Height(ctx), int64(deactivationHeight), "The network should start before the first change")
+	target := int64(reactivationHeight + 1)
+	for _, node := range nodes {
+		for node.Height(ctx) <= target {
+			require.NoError(s.T(), node.WaitForBlocks(ctx, 1))
+		}
+	}
+
+	for height := int64(deactivationHeight - 1); height <= target; height++ {
+		expected := s.activePrecompiles(ctx, nodes[0], height)
+		active := height < deactivationHeight || height >= reactivationHeight
+		require.Equal(s.T(), active, slices.Contains(expected, bankPrecompile), "Bank precompile active at height %d", height)
+
+		expectedAppHash, err := nodes[0].AppHash(ctx, height)
+		require.NoError(s.T(), err)
+		for i, node := range nodes[1:] {
+			require.Equal(s.T(), expected, s.activePrecompiles(ctx, node, height), "Precompiles of node%d diverged at height %d", i+1, height)
+			appHash, err := node.AppHash(ctx, height)
+			require.NoError(s.T(), err)
+			require.Equal(s.T(), expectedAppHash, appHash, "App hash of node%d diverged at height %d", i+1, height)
+		}
+
+		events, err := nodes[0].BlockEvents(ctx, height)
+		require.NoError(s.T(), err)
+		changes, err := TypedEvents[*evmupgradetypes.EventChangePrecompile](events)
+		require.NoError(s.T(), err)
+		if height != deactivationHeight && height != reactivationHeight {
+			require.Empty(s.T(), changes, "No precompile should change at %d", height)
+			continue
+		}
+		require.Len(s.T(), changes, 1)
+		require.Equal(s.T(), bankPrecompile, changes[0].Address)
+		require.Equal(s.T(), height == reactivationHeight, changes[0].Active)
+	}
+}
@@ -0,0 +1,174 @@
+package e2e
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	PriorityLanesChainID = "tacchain_2403-1"
+
+	// stufferCount is the number of txs filling a block each
+	stufferCount = 5
+)
+
+// PriorityLanesTestSuite runs a dedicated chain whose blocks are filled by
+// txs paying high fees, to check the txs of the priority lanes still make it
+// into the next block.
+type PriorityLanesTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestPriorityLanesTestSuite(t *testing.T) {
+	suite.Run(t, new(PriorityLanesTestSuite))
+}
+
+func (s *PriorityLanesTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: PriorityLanesChainID, PortOffset: 1100}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *PriorityLanesTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+// includedTx is the part of a tx result telling the block including it
+type includedTx struct {
+	TxHash string `json:"txhash"`
+	Height string `json:"height"`
+	Code   uint32 `json:"code"`
+}
+
+// waitForTx waits for the tx broadcast by a tx command to be included and
+// returns its height, whether it succeeded or not.
+func (s *PriorityLanesTestSuite) waitForTx(ctx context.Context, output string) int64 {
+	var res includedTx
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Tx command should have returned a tx response: %s", output)
+	require.Zero(s.T(), res.Code, "Tx should pass CheckTx: %s", output)
+
+	for {
+		output, _ := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "tx", res.TxHash, "--output", "json")
+		var included includedTx
+		if err := json.Unmarshal([]byte(output), &included); err == nil && included.Height != "" {
+			height, err := strconv.ParseInt(included.Height, 10, 64)
+			require.NoError(s.T(), err)
+			return height
+		}
+
+		select {
+		case <-ctx.Done():
+			s.T().Fatalf("Tx %s was not included: %v", res.TxHash, ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// evidenceTx signs a tx of the validator submitting evidence of a made up
+// equivocation. It passes CheckTx and fails when executed, which is enough
+// to tell the block including it.
+func (s *PriorityLanesTestSuite) evidenceTx(ctx context.Context) string {
+	unsigned, err := ExecuteCommand(ctx, s.chain.TxParams(), "tx", "bank", "send", "validator", randomAddress(), UTacAmount("1"),
+		"--gas", DefaultGas, "--gas-prices", DefaultGasPrice, "--generate-only")
+	require.NoError(s.T(), err, "Failed to generate tx: %s", unsigned)
+	validator, err := s.chain.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+
+	consAddr := make([]byte, 20)
+	_, _ = rand.Read(consAddr)
+	var tx map[string]any
+	require.NoError(s.T(), json.Unmarshal([]byte(unsigned), &tx), "Generated tx should be a json document: %s", unsigned)
+	tx["body"].(map[string]any)["messages"] = []any{map[string]any{
+		"@type":     "/cosmos.evidence.v1beta1.MsgSubmitEvidence",
+		"submitter": validator,
+		"evidence": map[string]any{
+			"@type":             "/cosmos.evidence.v1beta1.Equivocation",
+			"height":            "1",
+			"time":              "2024-01-01T00:00:00Z",
+			"power":             "1",
+			"consensus_address": sdk.MustBech32ifyAddressBytes(DefaultBech32Prefix+"valcons", consAddr),
+		},
+	}}
+	bz, err := json.Marshal(tx)
+	require.NoError(s.T(), err)
+	unsignedFile := filepath.Join(s.T().TempDir(), "unsigned.json")
+	require.NoError(s.T(), os.WriteFile(unsignedFile, bz, 0o600))
+
+	signed, err := ExecuteCommand(ctx, s.chain.TxParams(), "tx", "sign", unsignedFile, "--from", "validator")
+	require.NoError(s.T(), err, "Failed to sign tx: %s", signed)
+	signedFile := filepath.Join(s.T().TempDir(), "signed.json")
+	require.NoError(s.T(), os.WriteFile(signedFile, []byte(signed), 0o600))
+	return signedFile
+}
+
+func (s *PriorityLanesTestSuite) TestEvidenceIncludedInFullBlocks() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "consensus", "params", "--output", "json")
+	require.NoError(s.T(), err, "Failed to query consensus params: %s", output)
+	maxGas, err := strconv.ParseInt(parseField(output, "max_gas"), 10, 64)
+	require.NoError(s.T(), err, "Failed to parse block max gas: %s", output)
+	require.Positive(s.T(), maxGas, "Block gas should be limited")
+
+	stuffers := make([]string, stufferCount)
+	args := []string{"bank", "multi-send", "validator"}
+	for i := range stuffers {
+		stuffers[i] = fmt.Sprintf("stuffer%d", i)
+		_, err := s.chain.AddKey(ctx, stuffers[i])
+		require.NoError(s.T(), err)
+		addr, err := s.chain.Address(ctx, stuffers[i])
+		require.NoError(s.T(), err)
+		args = append(args, addr)
+	}
+	// each stuffer pays for a full block at ten times the default gas price
+	_, err = s.chain.Tx(ctx, "validator", append(args, UTacAmount("100000000000000000000"))...)
+	require.NoError(s.T(), err, "Failed to fund stuffers")
+	signedFile := s.evidenceTx(ctx)
+
+	// every stuffer tx wants the gas of a whole block, so they are included
+	// one per block in the order of the mempool
+	outputs := make([]string, stufferCount)
+	var wg sync.WaitGroup
+	for i, stuffer := range stuffers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outputs[i], _ = ExecuteCommand(ctx, s.chain.TxParams(), "tx", "bank", "send", stuffer, randomAddress(), UTacAmount("1"),
+				"--gas", strconv.FormatInt(maxGas, 10), "--gas-prices", "1000000000000utac", "-y")
+		}()
+	}
+	wg.Wait()
+
+	output, err = ExecuteCommand(ctx, s.chain.TxParams(), "tx", "broadcast", signedFile)
+	require.NoError(s.T(), err, "Failed to broadcast evidence: %s", output)
+	evidenceHeight := s.waitForTx(ctx, output)
+
+	var lastStufferHeight int64
+	for _, output := range outputs {
+		lastStufferHeight = max(lastStufferHeight, s.waitForTx(ctx, output))
+	}
+	require.Less(s.T(), evidenceHeight, lastStufferHeight,
+		"Evidence broadcast after the stuffers should be included ahead of them")
+}
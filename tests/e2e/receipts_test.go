@@ -0,0 +1,225 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	ReceiptsChainID = "tacchain_2408-1"
+
+	// receiptsJSONRPCAPI enables the receipts namespace after eth
+	receiptsJSONRPCAPI = `"eth,net,web3,debug,txtypes,receipts"`
+)
+
+// ReceiptsTestSuite runs a validator with the compact receipt store and a
+// second node state syncing from it without the store, so eth_getBlockReceipts
+// is served both from the store and from the block results.
+type ReceiptsTestSuite struct {
+	suite.Suite
+
+	validator *Chain
+	follower  *Chain
+}
+
+func TestReceiptsTestSuite(t *testing.T) {
+	suite.Run(t, new(ReceiptsTestSuite))
+}
+
+func (s *ReceiptsTestSuite) SetupSuite() {
+	s.validator = &Chain{ChainID: ReceiptsChainID, PortOffset: 1500}
+	if err := s.validator.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize validator: %v", err)
+	}
+	for _, kv := range [][3]string{
+		{"json-rpc", "api", receiptsJSONRPCAPI},
+		{"receipts", "compact", "true"},
+		{"state-sync", "snapshot-interval", strconv.Itoa(testSnapshotInterval)},
+	} {
+		if err := s.validator.SetAppConfig(kv[0], kv[1], kv[2]); err != nil {
+			s.T().Fatalf("Failed to set %s.%s: %v", kv[0], kv[1], err)
+		}
+	}
+	if err := s.validator.Start(); err != nil {
+		s.T().Fatalf("Failed to start validator: %v", err)
+	}
+}
+
+func (s *ReceiptsTestSuite) TearDownSuite() {
+	if s.follower != nil {
+		s.follower.Cleanup()
+	}
+	if s.validator != nil {
+		s.validator.Cleanup()
+	}
+}
+
+func (s *ReceiptsTestSuite) ethClient(ctx context.Context, c *Chain) *ethclient.Client {
+	client, err := ethclient.DialContext(ctx, c.JSONRPCAddress())
+	require.NoError(s.T(), err, "Failed to dial json-rpc")
+	s.T().Cleanup(client.Close)
+	return client
+}
+
+// sendTxs broadcasts a contract creation emitting a log and transfers in a
+// row, so most of them land in one block, and returns the heights they were
+// included at
+func (s *ReceiptsTestSuite) sendTxs(ctx context.Context, client *ethclient.Client) []int64 {
+	key, err := s.validator.EthPrivateKey(ctx, "validator")
+	require.NoError(s.T(), err)
+	nonce, err := client.PendingNonceAt(ctx, crypto.PubkeyToAddress(key.PublicKey))
+	require.NoError(s.T(), err)
+
+	// MSTORE(0, 1), LOG0(0, 32)
+	initCode := common.FromHex("0x600160005260206000a000")
+	tx, err := BroadcastEthTx(ctx, client, key, nonce, nil, big.NewInt(0), 100_000, initCode)
+	require.NoError(s.T(), err)
+	txs := []*gethtypes.Transaction{tx}
+	for i := uint64(1); i <= 3; i++ {
+		tx, err = BroadcastEthTransfer(ctx, client, key, common.BytesToAddress(crypto.Keccak256([]byte{byte(i)})), big.NewInt(1), nonce+i)
+		require.NoError(s.T(), err)
+		txs = append(txs, tx)
+	}
+
+	var heights []int64
+	for _, tx := range txs {
+		receipt, err := bind.WaitMined(ctx, client, tx)
+		require.NoError(s.T(), err)
+		if height := receipt.BlockNumber.Int64(); !slices.Contains(heights, height) {
+			heights = append(heights, height)
+		}
+	}
+	return heights
+}
+
+// requireBlockReceipts checks the receipts of the block at height are the
+// receipts of its txs
+func (s *ReceiptsTestSuite) requireBlockReceipts(ctx context.Context, client *ethclient.Client, height int64) []*gethtypes.Receipt {
+	receipts, err := client.BlockReceipts(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(height)))
+	require.NoError(s.T(), err)
+	block, err := client.BlockByNumber(ctx, big.NewInt(height))
+	require.NoError(s.T(), err)
+	require.Len(s.T(), receipts, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), receipt, receipts[i], "receipt %d of block %d", i, height)
+	}
+	return receipts
+}
+
+func (s *ReceiptsTestSuite) TestBlockReceipts() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	client := s.ethClient(ctx, s.validator)
+
+	heights := s.sendTxs(ctx, client)
+	var txs, logs int
+	for _, height := range heights {
+		for _, receipt := range s.requireBlockReceipts(ctx, client, height) {
+			txs++
+			logs += len(receipt.Logs)
+		}
+	}
+	require.Equal(s.T(), 4, txs, "The receipts should be those of the txs sent")
+	require.Equal(s.T(), 1, logs, "The contract creation log should be in the receipts")
+
+	// a block without EVM txs has no receipts, a future block is null
+	receipts, err := client.BlockReceipts(ctx, rpc.BlockNumberOrHashWithNumber(1))
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), receipts)
+	var raw []map[string]any
+	err = client.Client().CallContext(ctx, &raw, "eth_getBlockReceipts", hexutil.EncodeUint64(uint64(heights[len(heights)-1]+1000)))
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), raw)
+}
+
+func (s *ReceiptsTestSuite) TestSyncingDuringStateSync() {
+	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Minute)
+	defer cancel()
+
+	// the follower trusts a block of the validator and restores its latest
+	// snapshot, taken in the background after the commit of its height
+	require.NoError(s.T(), s.validator.WaitForBlocks(ctx, 2*testSnapshotInterval))
+	trustHeight := s.validator.Height(ctx)
+	trustHash, err := s.validator.BlockHash(ctx, trustHeight)
+	require.NoError(s.T(), err)
+	validatorAddr, err := s.validator.P2PAddress(ctx)
+	require.NoError(s.T(), err)
+
+	s.follower = &Chain{ChainID: ReceiptsChainID, PortOffset: 1600}
+	require.NoError(s.T(), s.follower.Init())
+	genesis, err := os.ReadFile(filepath.Join(s.validator.HomeDir, "config", "genesis.json"))
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), os.WriteFile(filepath.Join(s.follower.HomeDir, "config", "genesis.json"), genesis, 0o644))
+	require.NoError(s.T(), s.follower.SetAppConfig("json-rpc", "api", receiptsJSONRPCAPI))
+	rpcServer := fmt.Sprintf("http://127.0.0.1:%d", s.validator.port(26657))
+	for _, kv := range [][3]string{
+		{"statesync", "enable", "true"},
+		{"statesync", "rpc_servers", fmt.Sprintf("%q", rpcServer+","+rpcServer)},
+		{"statesync", "trust_height", strconv.FormatInt(trustHeight, 10)},
+		{"statesync", "trust_hash", fmt.Sprintf("%q", trustHash)},
+		{"p2p", "persistent_peers", fmt.Sprintf("%q", validatorAddr)},
+	} {
+		require.NoError(s.T(), s.follower.SetNodeConfig(kv[0], kv[1], kv[2]), "Failed to set %s.%s", kv[0], kv[1])
+	}
+	require.NoError(s.T(), s.follower.launch())
+
+	// eth_syncing agrees with CometBFT whenever the status is the same
+	// before and after the call
+	client := s.ethClient(ctx, s.follower)
+	var sawSyncing bool
+	for {
+		before, errBefore := s.follower.CatchingUp(ctx)
+		progress, err := client.SyncProgress(ctx)
+		after, errAfter := s.follower.CatchingUp(ctx)
+		if errBefore == nil && err == nil && errAfter == nil && before == after {
+			if !before {
+				require.Nil(s.T(), progress, "eth_syncing should be false once caught up")
+				break
+			}
+			require.NotNil(s.T(), progress, "eth_syncing should report the progress while catching up")
+			require.LessOrEqual(s.T(), progress.CurrentBlock, progress.HighestBlock)
+			sawSyncing = true
+		}
+
+		select {
+		case <-ctx.Done():
+			s.T().Fatalf("Follower did not catch up: %v", ctx.Err())
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+	require.True(s.T(), sawSyncing, "eth_syncing should have reported the state sync")
+
+	// the follower reads the receipts from the block results, they are the
+	// receipts of the validator store
+	validatorClient := s.ethClient(ctx, s.validator)
+	heights := s.sendTxs(ctx, validatorClient)
+	last := heights[len(heights)-1]
+	require.Eventually(s.T(), func() bool {
+		return s.follower.Height(ctx) >= last
+	}, time.Minute, time.Second, "Follower should sync the blocks of the validator")
+	for _, height := range heights {
+		followerReceipts := s.requireBlockReceipts(ctx, client, height)
+		validatorReceipts, err := validatorClient.BlockReceipts(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(height)))
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), validatorReceipts, followerReceipts, "receipts of block %d", height)
+	}
+}
@@ -0,0 +1,140 @@
+package e2e
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+
+	recoverytypes "github.com/Asphere-xyz/tacchain/x/recovery/types"
+)
+
+const RecoveryChainID = "tacchain_2398-1"
+
+// RecoveryTestSuite runs a dedicated chain recovering funds through governance,
+// the claims are executed by the end blocker so their events are block events.
+type RecoveryTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestRecoveryTestSuite(t *testing.T) {
+	suite.Run(t, new(RecoveryTestSuite))
+}
+
+func (s *RecoveryTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: RecoveryChainID, PortOffset: 800}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *RecoveryTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+// stuckClaim sends funds to the Cosmos-style address of a new Ethereum key
+// and returns the claim of the key moving them to recipient
+func (s *RecoveryTestSuite) stuckClaim(ctx context.Context, recipient string) recoverytypes.Claim {
+	key, err := crypto.GenerateKey()
+	require.NoError(s.T(), err)
+	pubKey := crypto.CompressPubkey(&key.PublicKey)
+	stuck := sdk.MustBech32ifyAddressBytes(DefaultBech32Prefix, (&secp256k1.PubKey{Key: pubKey}).Address())
+
+	_, err = s.chain.Tx(ctx, "validator", "bank", "send", "validator", stuck, UTacAmount("1000000"))
+	require.NoError(s.T(), err)
+
+	claim := recoverytypes.Claim{
+		ChainID:      RecoveryChainID,
+		StuckAddress: stuck,
+		Recipient:    recipient,
+		PubKey:       hex.EncodeToString(pubKey),
+		ExpiryHeight: uint64(s.chain.Height(ctx) + 1000),
+	}
+	sig, err := crypto.Sign(crypto.Keccak256(claim.SignBytes()), key)
+	require.NoError(s.T(), err)
+	claim.Signature = hex.EncodeToString(sig)
+	return claim
+}
+
+// approveClaim passes the claim through governance and returns the events of
+// the blocks up to its execution
+func (s *RecoveryTestSuite) approveClaim(ctx context.Context, claim recoverytypes.Claim) []abci.Event {
+	start := s.chain.Height(ctx)
+	err := s.chain.PassParamChange(ctx, "validator", recoverytypes.ModuleName, string(recoverytypes.KeyApprovedClaims), []recoverytypes.Claim{claim})
+	require.NoError(s.T(), err)
+	// the claim is executed at the end of the block the proposal passed in
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 1))
+
+	var events []abci.Event
+	for height := start; height <= s.chain.Height(ctx); height++ {
+		blockEvents, err := s.chain.BlockEvents(ctx, height)
+		require.NoError(s.T(), err)
+		events = append(events, blockEvents...)
+	}
+	return events
+}
+
+func (s *RecoveryTestSuite) TestRecoverFunds() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	recipient := randomAddress()
+	claim := s.stuckClaim(ctx, recipient)
+	stuck := claim.StuckAddress
+
+	recovered, err := TypedEvents[*recoverytypes.EventRecoverFunds](s.approveClaim(ctx, claim))
+	require.NoError(s.T(), err)
+
+	require.Len(s.T(), recovered, 1, "Claim should be executed once")
+	require.Equal(s.T(), stuck, recovered[0].StuckAddress)
+	require.Equal(s.T(), recipient, recovered[0].Recipient)
+	require.Equal(s.T(), UTacAmount("1000000"), recovered[0].Amount.String())
+
+	balance, err := s.chain.Balance(ctx, recipient, DefaultDenom)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "1000000", balance, "Recipient should receive the stuck funds")
+}
+
+// TestRecoverToModuleAccountBlocked moves funds with a keeper send, which the
+// bank blocklist doesn't check: the send restriction must still keep them out
+// of the module accounts.
+func (s *RecoveryTestSuite) TestRecoverToModuleAccountBlocked() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	mintAddr := ModuleAddress(minttypes.ModuleName)
+	claim := s.stuckClaim(ctx, mintAddr)
+	events := s.approveClaim(ctx, claim)
+
+	recovered, err := TypedEvents[*recoverytypes.EventRecoverFunds](events)
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), recovered, "Claim to the mint module account should fail")
+	failed, err := TypedEvents[*recoverytypes.EventRecoverFundsFailed](events)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), failed, 1)
+	require.Equal(s.T(), claim.StuckAddress, failed[0].StuckAddress)
+	require.Contains(s.T(), failed[0].Error, "cannot receive funds")
+
+	balance, err := s.chain.Balance(ctx, claim.StuckAddress, DefaultDenom)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "1000000", balance, "Stuck funds should stay in place")
+	balance, err = s.chain.Balance(ctx, mintAddr, DefaultDenom)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "0", balance, "Mint module account should not hold funds")
+}
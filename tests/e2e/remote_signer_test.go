@@ -0,0 +1,180 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	cmtlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/privval"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const RemoteSignerChainID = "tacchain_2397-1"
+
+// mockRemoteSigner is a tmkms style signer holding the validator key outside
+// of the node. It signs with a FilePV, so the last sign state still prevents
+// double signing, and records the heights it signed votes for.
+type mockRemoteSigner struct {
+	pv *privval.FilePV
+
+	mu          sync.Mutex
+	voteHeights []int64
+	proposals   int
+}
+
+func (s *mockRemoteSigner) GetPubKey() (crypto.PubKey, error) {
+	return s.pv.GetPubKey()
+}
+
+func (s *mockRemoteSigner) SignVote(chainID string, vote *cmtproto.Vote) error {
+	if err := s.pv.SignVote(chainID, vote); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.voteHeights = append(s.voteHeights, vote.Height)
+	return nil
+}
+
+func (s *mockRemoteSigner) SignProposal(chainID string, proposal *cmtproto.Proposal) error {
+	if err := s.pv.SignProposal(chainID, proposal); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.proposals++
+	return nil
+}
+
+// Signed returns the heights of the signed votes and the number of signed proposals.
+func (s *mockRemoteSigner) Signed() ([]int64, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int64(nil), s.voteHeights...), s.proposals
+}
+
+// startSignerServer dials the privval socket of the node and serves its sign
+// requests, retrying until the node listens.
+func startSignerServer(addr, chainID string, signer *mockRemoteSigner) (*privval.SignerServer, error) {
+	dialer := privval.DialTCPFn(addr, 3*time.Second, ed25519.GenPrivKey())
+	endpoint := privval.NewSignerDialerEndpoint(cmtlog.NewNopLogger(), dialer,
+		privval.SignerDialerEndpointTimeoutReadWrite(3*time.Second),
+		privval.SignerDialerEndpointConnRetries(600),
+		privval.SignerDialerEndpointRetryWaitInterval(100*time.Millisecond),
+	)
+	server := privval.NewSignerServer(endpoint, chainID, signer)
+	if err := server.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start signer server: %v", err)
+	}
+	return server, nil
+}
+
+// RemoteSignerTestSuite runs a validator signing through priv_validator_laddr
+// with a mock remote signer holding its key.
+type RemoteSignerTestSuite struct {
+	suite.Suite
+
+	chain     *Chain
+	signer    *mockRemoteSigner
+	server    *privval.SignerServer
+	laddrPort int
+}
+
+func TestRemoteSignerTestSuite(t *testing.T) {
+	suite.Run(t, new(RemoteSignerTestSuite))
+}
+
+func (s *RemoteSignerTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: RemoteSignerChainID, PortOffset: 700}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+
+	// the key only lives with the signer, the node can't sign by itself
+	signerDir := s.T().TempDir()
+	keyFile := filepath.Join(signerDir, "priv_validator_key.json")
+	stateFile := filepath.Join(signerDir, "priv_validator_state.json")
+	if err := os.Rename(filepath.Join(s.chain.HomeDir, "config", "priv_validator_key.json"), keyFile); err != nil {
+		s.T().Fatalf("Failed to move validator key: %v", err)
+	}
+	if err := os.Rename(filepath.Join(s.chain.HomeDir, "data", "priv_validator_state.json"), stateFile); err != nil {
+		s.T().Fatalf("Failed to move validator state: %v", err)
+	}
+	s.signer = &mockRemoteSigner{pv: privval.LoadFilePV(keyFile, stateFile)}
+
+	s.laddrPort = s.chain.port(26659)
+	if err := s.chain.SetNodeConfig("", "priv_validator_laddr", fmt.Sprintf(`"tcp://127.0.0.1:%d"`, s.laddrPort)); err != nil {
+		s.T().Fatalf("Failed to set priv_validator_laddr: %v", err)
+	}
+
+	server, err := startSignerServer(s.signerAddress(), RemoteSignerChainID, s.signer)
+	if err != nil {
+		s.T().Fatal(err)
+	}
+	s.server = server
+
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *RemoteSignerTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+	if s.server != nil {
+		_ = s.server.Stop()
+	}
+}
+
+func (s *RemoteSignerTestSuite) signerAddress() string {
+	return fmt.Sprintf("127.0.0.1:%d", s.laddrPort)
+}
+
+func (s *RemoteSignerTestSuite) TestBlocksSignedRemotely() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	votes, proposals := s.signer.Signed()
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 3))
+	height := s.chain.Height(ctx)
+
+	newVotes, newProposals := s.signer.Signed()
+	require.Greater(s.T(), len(newVotes), len(votes), "Votes should be signed by the remote signer")
+	require.Greater(s.T(), newProposals, proposals, "Proposals should be signed by the remote signer")
+	require.GreaterOrEqual(s.T(), newVotes[len(newVotes)-1], height-1, "Remote signer should sign the latest heights")
+
+	// the FilePV of the signer never goes back in height
+	for i := 1; i < len(newVotes); i++ {
+		require.GreaterOrEqual(s.T(), newVotes[i], newVotes[i-1])
+	}
+}
+
+func (s *RemoteSignerTestSuite) TestChainHaltsWithoutSigner() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	require.NoError(s.T(), s.server.Stop())
+	s.server = nil
+
+	// the only validator can't sign, at most the block in flight is committed
+	time.Sleep(3 * time.Second)
+	height := s.chain.Height(ctx)
+	time.Sleep(5 * time.Second)
+	require.LessOrEqual(s.T(), s.chain.Height(ctx), height+1, "Chain should halt without its signer")
+
+	// the node accepts the signer reconnecting
+	server, err := startSignerServer(s.signerAddress(), RemoteSignerChainID, s.signer)
+	require.NoError(s.T(), err)
+	s.server = server
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 2), "Chain should resume once the signer reconnects")
+}
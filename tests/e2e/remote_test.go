@@ -0,0 +1,219 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// Environment of RemoteTestSuite, the suite is skipped unless EnvRemoteRPC is set.
+const (
+	// EnvRemoteRPC is the CometBFT RPC endpoint of the network, e.g. https://rpc.example.com:443
+	EnvRemoteRPC = "TAC_E2E_RPC"
+	// EnvRemoteGRPC is a plaintext gRPC endpoint of the network, optional
+	EnvRemoteGRPC = "TAC_E2E_GRPC"
+	// EnvRemoteJSONRPC is the EVM JSON-RPC endpoint of the network, optional
+	EnvRemoteJSONRPC = "TAC_E2E_JSON_RPC"
+	// EnvRemoteFaucet is the URL of a tacchaind faucet of the network, the
+	// tests sending txs are skipped without it
+	EnvRemoteFaucet = "TAC_E2E_FAUCET"
+	// EnvRemoteGasPrices are the gas prices of the txs, DefaultGasPrice if unset
+	EnvRemoteGasPrices = "TAC_E2E_GAS_PRICES"
+)
+
+// RemoteTestSuite runs read-only checks and faucet funded txs against an
+// existing network instead of a local node, e.g. to smoke test a testnet
+// after a deployment:
+//
+//	TAC_E2E_RPC=https://rpc.example.com:443 TAC_E2E_FAUCET=https://faucet.example.com make test-e2e-remote
+//
+// Keys are created in a temporary keyring, funds the tests don't spend stay
+// on their accounts.
+type RemoteTestSuite struct {
+	suite.Suite
+
+	chain     *Chain
+	grpc      string
+	jsonRPC   string
+	faucet    string
+	gasPrices string
+}
+
+func TestRemoteTestSuite(t *testing.T) {
+	if os.Getenv(EnvRemoteRPC) == "" {
+		t.Skipf("%s is not set", EnvRemoteRPC)
+	}
+	suite.Run(t, new(RemoteTestSuite))
+}
+
+func (s *RemoteTestSuite) SetupSuite() {
+	s.grpc = os.Getenv(EnvRemoteGRPC)
+	s.jsonRPC = os.Getenv(EnvRemoteJSONRPC)
+	s.faucet = os.Getenv(EnvRemoteFaucet)
+	s.gasPrices = os.Getenv(EnvRemoteGasPrices)
+	if s.gasPrices == "" {
+		s.gasPrices = DefaultGasPrice
+	}
+
+	dir, err := os.MkdirTemp("", "tacchain-remote")
+	if err != nil {
+		s.T().Fatalf("Failed to create temporary directory: %v", err)
+	}
+	s.chain = &Chain{HomeDir: dir, Node: os.Getenv(EnvRemoteRPC)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "status")
+	if err != nil {
+		s.T().Fatalf("Failed to query the status of %s: %v: %s", s.chain.Node, err, output)
+	}
+	s.chain.ChainID = parseField(output, "network")
+	s.T().Logf("Running against %s at %s", s.chain.ChainID, s.chain.Node)
+}
+
+func (s *RemoteTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+func (s *RemoteTestSuite) TestBlockProduction() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 2))
+}
+
+func (s *RemoteTestSuite) TestQueries() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "tac", "all-params")
+	require.NoError(s.T(), err, "Failed to query all params: %s", output)
+	var allParams map[string]json.RawMessage
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &allParams), "Output should be a json document: %s", output)
+	for _, module := range []string{"bank", "staking", "evm", "feemarket", "transfer", "ibchooks", "recovery", "emission", "feeburn", "performance", "autocompound", "selfbond", "evmupgrade", "contractmeta", "bridge", "contractgas", "tacgov", "rewardsnapshot", "accountactivity", "feehistory", "feerouting", "nameservice", "gas_utilization"} {
+		require.Contains(s.T(), allParams, module)
+	}
+
+	for _, query := range []string{"apr", "emission", "fee-report", "bridge-status", "validator-performance", "autocompound", "contract-metadata", "bridge-relayers", "bridge-withdrawals", "bridge-assets", "reward-snapshots"} {
+		output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "tac", query)
+		require.NoError(s.T(), err, "Failed to query %s: %s", query, output)
+		require.True(s.T(), json.Valid([]byte(output)), "Output of %s should be a json document: %s", query, output)
+	}
+
+	output, err = ExecuteCommand(ctx, s.chain.QueryParams(), "q", "staking", "validators", "--output", "json")
+	require.NoError(s.T(), err, "Failed to query validators: %s", output)
+	require.Contains(s.T(), output, "BOND_STATUS_BONDED", "The network should have bonded validators")
+}
+
+func (s *RemoteTestSuite) TestGRPCReflection() {
+	if s.grpc == "" {
+		s.T().Skipf("%s is not set", EnvRemoteGRPC)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	services, err := grpcServices(ctx, s.grpc)
+	require.NoError(s.T(), err, "gRPC server reflection should be enabled")
+	require.Contains(s.T(), services, "cosmos.bank.v1beta1.Query")
+}
+
+func (s *RemoteTestSuite) ethClient(ctx context.Context) *ethclient.Client {
+	if s.jsonRPC == "" {
+		s.T().Skipf("%s is not set", EnvRemoteJSONRPC)
+	}
+	client, err := ethclient.DialContext(ctx, s.jsonRPC)
+	require.NoError(s.T(), err, "Failed to dial json-rpc")
+	s.T().Cleanup(client.Close)
+	return client
+}
+
+func (s *RemoteTestSuite) TestJSONRPC() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	client := s.ethClient(ctx)
+
+	chainID, err := client.ChainID(ctx)
+	require.NoError(s.T(), err)
+	require.Positive(s.T(), chainID.Sign())
+	// the EVM chain id is the number in the Cosmos chain id, e.g. 2391 of tacchain_2391-1
+	require.Contains(s.T(), s.chain.ChainID, fmt.Sprintf("_%s-", chainID))
+
+	start, err := client.BlockNumber(ctx)
+	require.NoError(s.T(), err)
+	require.Eventually(s.T(), func() bool {
+		height, err := client.BlockNumber(ctx)
+		return err == nil && height > start
+	}, 30*time.Second, time.Second, "JSON-RPC should follow the chain")
+}
+
+// fund creates keyName and funds it from the faucet of the network
+func (s *RemoteTestSuite) fund(ctx context.Context, keyName string) string {
+	if s.faucet == "" {
+		s.T().Skipf("%s is not set", EnvRemoteFaucet)
+	}
+	_, err := s.chain.AddKey(ctx, keyName)
+	require.NoError(s.T(), err)
+	address, err := s.chain.Address(ctx, keyName)
+	require.NoError(s.T(), err)
+
+	body, err := json.Marshal(map[string]string{"address": address})
+	require.NoError(s.T(), err)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.faucet, bytes.NewReader(body))
+	require.NoError(s.T(), err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(s.T(), err, "Failed to request funds")
+	defer resp.Body.Close()
+	require.Equal(s.T(), http.StatusOK, resp.StatusCode, "Faucet should fund %s", address)
+
+	require.Eventually(s.T(), func() bool {
+		balance, err := s.chain.Balance(ctx, address, DefaultDenom)
+		return err == nil && balance != "" && balance != "0"
+	}, time.Minute, 2*time.Second, "Faucet funds should arrive")
+	return address
+}
+
+func (s *RemoteTestSuite) TestFundedBankSend() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+	s.fund(ctx, "remote-bank")
+
+	to := randomAddress()
+	output, err := ExecuteCommand(ctx, s.chain.TxParams(), "tx", "bank", "send", "remote-bank", to, UTacAmount("1"),
+		"--gas", "auto", "--gas-adjustment", "1.5", "--gas-prices", s.gasPrices, "-y")
+	require.NoError(s.T(), err, "Failed to send: %s", output)
+
+	require.Eventually(s.T(), func() bool {
+		balance, err := s.chain.Balance(ctx, to, DefaultDenom)
+		return err == nil && balance == "1"
+	}, time.Minute, 2*time.Second, "Recipient should receive the funds")
+}
+
+func (s *RemoteTestSuite) TestFundedEVMTransfer() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+	client := s.ethClient(ctx)
+	s.fund(ctx, "remote-evm")
+
+	key, err := s.chain.EthPrivateKey(ctx, "remote-evm")
+	require.NoError(s.T(), err)
+	toKey, err := crypto.GenerateKey()
+	require.NoError(s.T(), err)
+	to := crypto.PubkeyToAddress(toKey.PublicKey)
+	receipt, err := SendEthTransfer(ctx, client, key, to, big.NewInt(1))
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), uint64(1), receipt.Status)
+	RequireEthTransferBalances(ctx, s.T(), client, crypto.PubkeyToAddress(key.PublicKey), to, big.NewInt(1), receipt)
+}
@@ -0,0 +1,94 @@
+package e2e
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const ResetChainID = "tacchain_2421-1"
+
+// ResetTestSuite runs a dedicated chain which is reset and restarted from
+// its genesis, keeping the keys of its validator.
+type ResetTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestResetTestSuite(t *testing.T) {
+	suite.Run(t, new(ResetTestSuite))
+}
+
+func (s *ResetTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: ResetChainID, PortOffset: 2700}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *ResetTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+func (s *ResetTestSuite) TestResetAndRestartFromGenesis() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	recipient := randomAddress()
+	_, err := s.chain.Tx(ctx, "validator", "bank", "send", "validator", recipient, UTacAmount("1000"))
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 2))
+	height := s.chain.Height(ctx)
+
+	nodeID, err := s.chain.NodeID(ctx)
+	require.NoError(s.T(), err)
+	validator, err := s.chain.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+	pvKeyFile := filepath.Join(s.chain.HomeDir, "config", "priv_validator_key.json")
+	pvKey, err := os.ReadFile(pvKeyFile)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.chain.Stop())
+
+	// a dry run lists the data and removes nothing
+	output, err := ExecuteCommand(ctx, CommandParams{HomeDir: s.chain.HomeDir}, "unsafe-reset-all", "--dry-run")
+	require.NoError(s.T(), err, "Failed to plan the reset: %s", output)
+	require.Contains(s.T(), output, "would remove "+filepath.Join(s.chain.HomeDir, "data", "application.db"))
+	require.Contains(s.T(), output, "would reset "+filepath.Join(s.chain.HomeDir, "data", "priv_validator_state.json"))
+	require.NotContains(s.T(), output, "priv_validator_key.json")
+	require.DirExists(s.T(), filepath.Join(s.chain.HomeDir, "data", "application.db"))
+
+	output, err = ExecuteCommand(ctx, CommandParams{HomeDir: s.chain.HomeDir}, "unsafe-reset-all", "--keep-addrbook")
+	require.NoError(s.T(), err, "Failed to reset the node: %s", output)
+	require.NoDirExists(s.T(), filepath.Join(s.chain.HomeDir, "data", "application.db"))
+
+	// the keys survive the reset
+	resetPVKey, err := os.ReadFile(pvKeyFile)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), pvKey, resetPVKey)
+	resetNodeID, err := s.chain.NodeID(ctx)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), nodeID, resetNodeID)
+	resetValidator, err := s.chain.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), validator, resetValidator)
+
+	// the validator signs the chain again from its genesis
+	require.NoError(s.T(), s.chain.Start())
+	require.Less(s.T(), s.chain.Height(ctx), height)
+	balance, err := s.chain.Balance(ctx, recipient, DefaultDenom)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "0", balance)
+	_, err = s.chain.Tx(ctx, "validator", "bank", "send", "validator", recipient, UTacAmount("1000"))
+	require.NoError(s.T(), err)
+}
@@ -0,0 +1,158 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	SelfBondChainID = "tacchain_2405-1"
+
+	// MinSelfBond is the self delegation of the validators of
+	// init-multi-node.sh, their balance less 100 TAC kept for fees
+	MinSelfBond = "4999900000000000000000000"
+)
+
+// SelfBondTestSuite runs a network of validators with a min self bond equal to
+// their self delegation, so any of them undelegating is jailed.
+//
+// Each validator has a quarter of the voting power, the tests run in order and
+// jail one validator at a time so the others keep producing blocks.
+type SelfBondTestSuite struct {
+	suite.Suite
+
+	network *Network
+}
+
+func TestSelfBondTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("self bond tests run a network of validators")
+	}
+	suite.Run(t, new(SelfBondTestSuite))
+}
+
+func (s *SelfBondTestSuite) SetupSuite() {
+	s.network = &Network{ChainID: SelfBondChainID}
+	if err := s.network.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize network: %v", err)
+	}
+	err := s.network.PatchGenesis(func(appState map[string]any) error {
+		selfbond, ok := appState["selfbond"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("genesis has no selfbond state")
+		}
+		params, ok := selfbond["params"].(map[string]any)
+		if !ok {
+			return fmt.Errorf("genesis has no selfbond params")
+		}
+		params["min_self_bond"] = MinSelfBond
+		return nil
+	})
+	if err != nil {
+		s.T().Fatalf("Failed to set the min self bond: %v", err)
+	}
+	if err := s.network.Start(); err != nil {
+		s.T().Fatalf("Failed to start network: %v", err)
+	}
+}
+
+func (s *SelfBondTestSuite) TearDownSuite() {
+	if s.network != nil {
+		s.network.Cleanup()
+	}
+}
+
+func (s *SelfBondTestSuite) operator(ctx context.Context, node *Chain) string {
+	output, err := ExecuteCommand(ctx, node.KeyParams(), "keys", "show", "validator", "--bech", "val", "-a")
+	require.NoError(s.T(), err, "Failed to get operator address: %s", output)
+	return strings.TrimSpace(output)
+}
+
+func (s *SelfBondTestSuite) jailed(ctx context.Context, operator string) bool {
+	output, err := ExecuteCommand(ctx, s.network.Nodes[0].QueryParams(), "q", "staking", "validator", operator, "--output", "json")
+	require.NoError(s.T(), err, "Failed to query validator: %s", output)
+	jailed, _ := ParseBoolField(output, "jailed")
+	return jailed
+}
+
+func (s *SelfBondTestSuite) TestJailBelowMinSelfBond() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	node := s.network.Nodes[1]
+	operator := s.operator(ctx, node)
+	require.False(s.T(), s.jailed(ctx, operator), "Validator at the min self bond shouldn't be jailed")
+
+	output, err := ExecuteCommand(ctx, s.network.Nodes[0].QueryParams(), "q", "tac", "all-params")
+	require.NoError(s.T(), err, "Failed to query all params: %s", output)
+	require.Contains(s.T(), output, MinSelfBond)
+
+	// undelegating 10 TAC of the self bond jails the validator at the end of the block
+	output, err = node.Tx(ctx, "validator", "staking", "unbond", operator, UTacAmount("10000000000000000000"))
+	require.NoError(s.T(), err, "Failed to unbond: %s", output)
+	require.Eventually(s.T(), func() bool {
+		return s.jailed(ctx, operator)
+	}, time.Minute, time.Second, "Validator below the min self bond should be jailed")
+
+	// delegating the self bond back lets it unjail
+	output, err = node.Tx(ctx, "validator", "staking", "delegate", operator, UTacAmount("10000000000000000000"))
+	require.NoError(s.T(), err, "Failed to delegate: %s", output)
+	output, err = node.Tx(ctx, "validator", "slashing", "unjail")
+	require.NoError(s.T(), err, "Failed to unjail: %s", output)
+	require.NoError(s.T(), node.WaitForBlocks(ctx, 2))
+	require.False(s.T(), s.jailed(ctx, operator), "Validator back at the min self bond should stay unjailed")
+}
+
+func (s *SelfBondTestSuite) TestValidatorExit() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	nodes := s.network.Nodes
+	operator := s.operator(ctx, nodes[2])
+	delegator, err := nodes[0].Address(ctx, "genesis_acc_2")
+	require.NoError(s.T(), err)
+
+	// the genesis accounts of the network are in the keyring of node0
+	output, err := nodes[0].Tx(ctx, "genesis_acc_2", "staking", "delegate", operator, UTacAmount("1000000000000000000000"))
+	require.NoError(s.T(), err, "Failed to delegate: %s", output)
+	require.Len(s.T(), s.delegationsTo(ctx, operator), 2)
+
+	output, err = nodes[2].Tx(ctx, "validator", "tac", "exit-validator")
+	require.NoError(s.T(), err, "Failed to exit validator: %s", output)
+
+	// the exit jails the validator and unbonds every delegation to it
+	require.Eventually(s.T(), func() bool {
+		return len(s.delegationsTo(ctx, operator)) == 0
+	}, time.Minute, time.Second, "Delegations to the exited validator should be unbonded")
+	require.True(s.T(), s.jailed(ctx, operator), "Exited validator should be jailed")
+
+	output, err = ExecuteCommand(ctx, nodes[0].QueryParams(), "q", "staking", "unbonding-delegation", delegator, operator, "--output", "json")
+	require.NoError(s.T(), err, "Failed to query unbonding delegation: %s", output)
+	var res struct {
+		Unbond struct {
+			Entries []struct {
+				Balance string `json:"balance"`
+			} `json:"entries"`
+		} `json:"unbond"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Output should be a json document: %s", output)
+	require.Len(s.T(), res.Unbond.Entries, 1)
+	require.Equal(s.T(), "1000000000000000000000", res.Unbond.Entries[0].Balance)
+}
+
+func (s *SelfBondTestSuite) delegationsTo(ctx context.Context, operator string) []json.RawMessage {
+	output, err := ExecuteCommand(ctx, s.network.Nodes[0].QueryParams(), "q", "staking", "delegations-to", operator, "--output", "json")
+	require.NoError(s.T(), err, "Failed to query delegations: %s", output)
+	var res struct {
+		DelegationResponses []json.RawMessage `json:"delegation_responses"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Output should be a json document: %s", output)
+	return res.DelegationResponses
+}
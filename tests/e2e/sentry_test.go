@@ -0,0 +1,133 @@
+package e2e
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const SentryChainID = "tacchain_2396-1"
+
+// SentryTestSuite runs a validator only reachable through a sentry node, both
+// set up with the tacchaind init presets.
+type SentryTestSuite struct {
+	suite.Suite
+
+	validator *Chain
+	sentry    *Chain
+}
+
+func TestSentryTestSuite(t *testing.T) {
+	suite.Run(t, new(SentryTestSuite))
+}
+
+func (s *SentryTestSuite) SetupSuite() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	// the validator and the sentry need each other's node id, so the sentry
+	// node key is created first and its config is redone once the validator exists
+	s.sentry = &Chain{ChainID: SentryChainID, PortOffset: 600}
+	if err := s.sentry.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize sentry: %v", err)
+	}
+	sentryAddr, err := s.sentry.P2PAddress(ctx)
+	if err != nil {
+		s.T().Fatalf("Failed to get sentry address: %v", err)
+	}
+
+	s.validator = &Chain{ChainID: SentryChainID, PortOffset: 500, InitFlags: "--validator-behind-sentry --sentry-peers " + sentryAddr}
+	if err := s.validator.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize validator: %v", err)
+	}
+	validatorAddr, err := s.validator.P2PAddress(ctx)
+	if err != nil {
+		s.T().Fatalf("Failed to get validator address: %v", err)
+	}
+
+	output, err := ExecuteCommand(ctx, CommandParams{HomeDir: s.sentry.HomeDir, ChainID: SentryChainID},
+		"init", "sentry", "--overwrite", "--sentry", "--private-peers", validatorAddr)
+	if err != nil {
+		s.T().Fatalf("Failed to apply the sentry preset: %v, %s", err, output)
+	}
+
+	// the sentry is a full node of the validator's network
+	genesis, err := os.ReadFile(filepath.Join(s.validator.HomeDir, "config", "genesis.json"))
+	if err != nil {
+		s.T().Fatalf("Failed to read validator genesis: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.sentry.HomeDir, "config", "genesis.json"), genesis, 0o644); err != nil {
+		s.T().Fatalf("Failed to write sentry genesis: %v", err)
+	}
+
+	if err := s.validator.Start(); err != nil {
+		s.T().Fatalf("Failed to start validator: %v", err)
+	}
+	if err := s.sentry.Start(); err != nil {
+		s.T().Fatalf("Failed to start sentry: %v", err)
+	}
+}
+
+func (s *SentryTestSuite) TearDownSuite() {
+	if s.sentry != nil {
+		s.sentry.Cleanup()
+	}
+	if s.validator != nil {
+		s.validator.Cleanup()
+	}
+}
+
+func (s *SentryTestSuite) TestValidatorOnlyPeersWithSentry() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	validatorID, err := s.validator.NodeID(ctx)
+	require.NoError(s.T(), err)
+	sentryID, err := s.sentry.NodeID(ctx)
+	require.NoError(s.T(), err)
+
+	require.Eventually(s.T(), func() bool {
+		peers, err := s.sentry.Peers(ctx)
+		return err == nil && slices.Contains(peers, validatorID)
+	}, 30*time.Second, time.Second, "Sentry should be connected to the validator")
+
+	peers, err := s.validator.Peers(ctx)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []string{sentryID}, peers, "Validator should only be connected to its sentry")
+}
+
+func (s *SentryTestSuite) TestSentryFollowsValidator() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	require.NoError(s.T(), s.validator.WaitForBlocks(ctx, 2))
+	height := s.validator.Height(ctx)
+	require.Eventually(s.T(), func() bool {
+		return s.sentry.Height(ctx) >= height
+	}, 30*time.Second, time.Second, "Sentry should sync the blocks of the validator")
+}
+
+func (s *SentryTestSuite) TestTxThroughSentry() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	recipient := randomAddress()
+
+	// broadcast to the sentry only, the validator gets the tx from its mempool
+	params := s.validator.TxParams()
+	params.Node = s.sentry.RPCAddress()
+	output, err := ExecuteCommand(ctx, params, "tx", "bank", "send", "validator", recipient, UTacAmount("1"),
+		"--gas", DefaultGas, "--gas-prices", DefaultGasPrice, "-y")
+	require.NoError(s.T(), err, "Failed to send tx through the sentry: %s", output)
+
+	require.Eventually(s.T(), func() bool {
+		balance, err := s.validator.Balance(ctx, recipient, DefaultDenom)
+		return err == nil && balance == "1"
+	}, 30*time.Second, time.Second, "Tx sent to the sentry should be included by the validator")
+}
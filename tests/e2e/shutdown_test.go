@@ -0,0 +1,109 @@
+package e2e
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const ShutdownChainID = "tacchain_2413-1"
+
+// ShutdownTestSuite stops a dedicated chain with SIGTERM while it serves
+// queries, then restarts it.
+type ShutdownTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestShutdownTestSuite(t *testing.T) {
+	suite.Run(t, new(ShutdownTestSuite))
+}
+
+func (s *ShutdownTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: ShutdownChainID, PortOffset: 2000}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.SetAppConfig("shutdown", "grace-period", `"5s"`); err != nil {
+		s.T().Fatalf("Failed to configure the shutdown: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *ShutdownTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+// queryLoad queries balances over JSON-RPC with workers in parallel until
+// ctx is done, it returns the number of queries that succeeded
+func (s *ShutdownTestSuite) queryLoad(ctx context.Context, workers int) *atomic.Int64 {
+	client, err := ethclient.DialContext(ctx, s.chain.JSONRPCAddress())
+	require.NoError(s.T(), err)
+
+	var served atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				if _, err := client.BalanceAt(ctx, common.Address{}, nil); err == nil {
+					served.Add(1)
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		client.Close()
+	}()
+	return &served
+}
+
+func (s *ShutdownTestSuite) TestGracefulShutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	loadCtx, stopLoad := context.WithCancel(ctx)
+	defer stopLoad()
+	served := s.queryLoad(loadCtx, 8)
+	require.Eventually(s.T(), func() bool { return served.Load() > 10 }, 30*time.Second, 100*time.Millisecond,
+		"The node should serve the queries")
+
+	height := s.chain.Height(ctx)
+	require.Positive(s.T(), height)
+
+	// the node drains the queries in flight and exits on SIGTERM
+	require.NoError(s.T(), s.chain.Stop())
+	stopLoad()
+
+	bz, err := os.ReadFile(s.chain.LogFile())
+	require.NoError(s.T(), err)
+	logs := string(bz)
+	require.Contains(s.T(), logs, "draining in-flight queries")
+	require.Contains(s.T(), logs, "in-flight queries drained")
+	require.NotContains(s.T(), logs, "shutdown grace period elapsed")
+	require.NotContains(s.T(), logs, "panic")
+
+	// the node restarts from its databases and keeps producing blocks
+	require.NoError(s.T(), s.chain.launch())
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 3))
+	require.Greater(s.T(), s.chain.Height(ctx), height)
+
+	bz, err = os.ReadFile(s.chain.LogFile())
+	require.NoError(s.T(), err)
+	require.NotContains(s.T(), string(bz[len(logs):]), "panic")
+}
@@ -0,0 +1,106 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	SnapshotsChainID = "tacchain_2401-1"
+
+	testSnapshotInterval = 5
+)
+
+// SnapshotsTestSuite runs a dedicated chain taking state sync snapshots, the
+// snapshot commands need the node to be stopped.
+type SnapshotsTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestSnapshotsTestSuite(t *testing.T) {
+	suite.Run(t, new(SnapshotsTestSuite))
+}
+
+func (s *SnapshotsTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: SnapshotsChainID, PortOffset: 900}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.SetAppConfig("state-sync", "snapshot-interval", strconv.Itoa(testSnapshotInterval)); err != nil {
+		s.T().Fatalf("Failed to enable snapshots: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *SnapshotsTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+var snapshotLineRegexp = regexp.MustCompile(`height: (\d+) format: (\d+) chunks: (\d+)`)
+
+// listSnapshots returns the height and format of the snapshots in home
+func (s *SnapshotsTestSuite) listSnapshots(ctx context.Context, home string) [][2]string {
+	output, err := ExecuteCommand(ctx, CommandParams{HomeDir: home}, "snapshots", "list")
+	require.NoError(s.T(), err, "Failed to list snapshots: %s", output)
+
+	var snapshots [][2]string
+	for _, match := range snapshotLineRegexp.FindAllStringSubmatch(output, -1) {
+		snapshots = append(snapshots, [2]string{match[1], match[2]})
+	}
+	return snapshots
+}
+
+func (s *SnapshotsTestSuite) TestVerifyAndMoveSnapshot() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	// snapshots are taken in the background after the commit of their height
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 2*testSnapshotInterval))
+	require.NoError(s.T(), s.chain.Stop())
+
+	snapshots := s.listSnapshots(ctx, s.chain.HomeDir)
+	require.NotEmpty(s.T(), snapshots, "The node should have taken snapshots")
+	height, format := snapshots[0][0], snapshots[0][1]
+
+	output, err := ExecuteCommand(ctx, CommandParams{HomeDir: s.chain.HomeDir}, "snapshots", "verify", height)
+	require.NoError(s.T(), err, "Snapshot taken by the node should be valid: %s", output)
+	require.Contains(s.T(), output, fmt.Sprintf("height: %s format: %s", height, format))
+
+	output, err = ExecuteCommand(ctx, CommandParams{HomeDir: s.chain.HomeDir}, "snapshots", "verify", "1")
+	require.Error(s.T(), err, "There is no snapshot at height 1: %s", output)
+
+	// move the snapshot to another node through an archive
+	archive := filepath.Join(s.T().TempDir(), "snapshot.tar.gz")
+	output, err = ExecuteCommand(ctx, CommandParams{HomeDir: s.chain.HomeDir}, "snapshots", "dump", height, format, "--output", archive)
+	require.NoError(s.T(), err, "Failed to dump snapshot: %s", output)
+
+	otherHome := s.T().TempDir()
+	output, err = ExecuteCommand(ctx, CommandParams{HomeDir: otherHome}, "snapshots", "load", archive)
+	require.NoError(s.T(), err, "Failed to load snapshot: %s", output)
+	require.Equal(s.T(), [][2]string{{height, format}}, s.listSnapshots(ctx, otherHome))
+
+	output, err = ExecuteCommand(ctx, CommandParams{HomeDir: otherHome}, "snapshots", "verify", height)
+	require.NoError(s.T(), err, "Loaded snapshot should be valid: %s", output)
+
+	// a corrupted chunk fails the verification
+	chunk := filepath.Join(otherHome, "data", "snapshots", height, format, "0")
+	require.NoError(s.T(), os.WriteFile(chunk, []byte("corrupted"), 0o644))
+	output, err = ExecuteCommand(ctx, CommandParams{HomeDir: otherHome}, "snapshots", "verify", height)
+	require.Error(s.T(), err, "Corrupted snapshot should fail the verification")
+	require.Contains(s.T(), output, "hash mismatch of chunk 0")
+}
@@ -0,0 +1,243 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Asphere-xyz/tacchain/client/tacsdk"
+)
+
+func (s *TacchainTestSuite) TestTacQueries() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	params := s.CommandParamsHomeDir()
+
+	output, err := ExecuteCommand(ctx, params, "q", "tac", "apr")
+	require.NoError(s.T(), err, "Failed to query apr: %s", output)
+	var apr struct {
+		BondDenom string `json:"bond_denom"`
+		APR       string `json:"apr"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &apr), "Output should be a json document: %s", output)
+	require.Equal(s.T(), DefaultDenom, apr.BondDenom)
+	require.NotEmpty(s.T(), apr.APR)
+
+	output, err = ExecuteCommand(ctx, params, "q", "tac", "emission")
+	require.NoError(s.T(), err, "Failed to query emission: %s", output)
+	var emission struct {
+		Height         int64  `json:"height"`
+		BlockProvision string `json:"block_provision"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &emission), "Output should be a json document: %s", output)
+	require.Positive(s.T(), emission.Height)
+	require.Equal(s.T(), "0", emission.BlockProvision, "The default schedule mints nothing")
+
+	output, err = ExecuteCommand(ctx, params, "q", "tac", "all-params")
+	require.NoError(s.T(), err, "Failed to query all params: %s", output)
+	var allParams map[string]json.RawMessage
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &allParams), "Output should be a json document: %s", output)
+	for _, module := range []string{"bank", "staking", "evm", "feemarket", "transfer", "ibchooks", "recovery", "emission", "feeburn", "performance", "autocompound", "selfbond", "evmupgrade", "contractmeta", "bridge", "contractgas", "tacgov", "rewardsnapshot", "accountactivity", "feehistory", "feerouting", "nameservice", "gas_utilization"} {
+		require.Contains(s.T(), allParams, module)
+	}
+
+	output, err = ExecuteCommand(ctx, params, "q", "tac", "address-mapping", "0x1111111111111111111111111111111111111111")
+	require.NoError(s.T(), err, "Failed to map address: %s", output)
+	var mapping struct {
+		Address    string `json:"address"`
+		EthAddress string `json:"eth_address"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &mapping), "Output should be a json document: %s", output)
+	require.Equal(s.T(), "0x1111111111111111111111111111111111111111", mapping.EthAddress)
+	require.Contains(s.T(), mapping.Address, DefaultBech32Prefix+"1")
+
+	output, err = ExecuteCommand(ctx, params, "q", "tac", "fee-report")
+	require.NoError(s.T(), err, "Failed to query fee report: %s", output)
+	var feeReport struct {
+		EVMDenom string `json:"evm_denom"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &feeReport), "Output should be a json document: %s", output)
+	require.Equal(s.T(), DefaultDenom, feeReport.EVMDenom)
+
+	output, err = ExecuteCommand(ctx, params, "q", "tac", "bridge-status")
+	require.NoError(s.T(), err, "Failed to query bridge status: %s", output)
+	var bridgeStatus struct {
+		TransferChannels []json.RawMessage `json:"transfer_channels"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &bridgeStatus), "Output should be a json document: %s", output)
+	require.NotNil(s.T(), bridgeStatus.TransferChannels)
+
+	output, err = ExecuteCommand(ctx, params, "q", "tac", "gas-utilization", "--blocks", "10")
+	require.NoError(s.T(), err, "Failed to query gas utilization: %s", output)
+	var utilization struct {
+		Blocks    int   `json:"blocks"`
+		MaxGas    int64 `json:"max_gas"`
+		GasWanted struct {
+			Max uint64 `json:"max"`
+		} `json:"gas_wanted"`
+		Headroom string `json:"headroom"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &utilization), "Output should be a json document: %s", output)
+	require.Positive(s.T(), utilization.Blocks)
+	require.LessOrEqual(s.T(), utilization.Blocks, 10)
+	require.Positive(s.T(), utilization.MaxGas)
+	require.LessOrEqual(s.T(), utilization.GasWanted.Max, uint64(utilization.MaxGas))
+	require.NotEmpty(s.T(), utilization.Headroom)
+
+	output, err = ExecuteCommand(ctx, params, "q", "tac", "validator-performance")
+	require.NoError(s.T(), err, "Failed to query validator performance: %s", output)
+	var performance struct {
+		Window     uint64 `json:"window"`
+		Validators []struct {
+			Blocks   uint64 `json:"blocks"`
+			Proposed uint64 `json:"proposed"`
+			Uptime   string `json:"uptime"`
+		} `json:"validators"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &performance), "Output should be a json document: %s", output)
+	require.Positive(s.T(), performance.Window)
+	require.NotEmpty(s.T(), performance.Validators)
+	require.Positive(s.T(), performance.Validators[0].Blocks, "The validator should have signed blocks")
+	require.Positive(s.T(), performance.Validators[0].Proposed, "The validator should have proposed blocks")
+	require.Equal(s.T(), "1.000000000000000000", performance.Validators[0].Uptime)
+
+	output, err = ExecuteCommand(ctx, params, "q", "tac", "reward-snapshots")
+	require.NoError(s.T(), err, "Failed to query reward snapshots: %s", output)
+	var snapshots struct {
+		CurrentEpoch *struct {
+			Number      uint64 `json:"number"`
+			StartHeight int64  `json:"start_height"`
+		} `json:"current_epoch"`
+		Epochs []json.RawMessage `json:"epochs"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &snapshots), "Output should be a json document: %s", output)
+	require.NotNil(s.T(), snapshots.CurrentEpoch, "The first epoch should be in progress")
+	require.EqualValues(s.T(), 1, snapshots.CurrentEpoch.Number)
+	require.Empty(s.T(), snapshots.Epochs, "No epoch should have ended with the default epoch length")
+
+	output, err = ExecuteCommand(ctx, params, "q", "tac", "fee-history", "--blocks", "5")
+	require.NoError(s.T(), err, "Failed to query fee history: %s", output)
+	var feeHistory struct {
+		Retention uint64 `json:"retention"`
+		Blocks    []struct {
+			Height  int64  `json:"height"`
+			BaseFee string `json:"base_fee"`
+			MaxGas  int64  `json:"max_gas"`
+		} `json:"blocks"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &feeHistory), "Output should be a json document: %s", output)
+	require.Positive(s.T(), feeHistory.Retention)
+	require.Len(s.T(), feeHistory.Blocks, 5)
+	for i, block := range feeHistory.Blocks {
+		require.Equal(s.T(), feeHistory.Blocks[0].Height+int64(i), block.Height, "The blocks should be consecutive")
+		require.NotEmpty(s.T(), block.BaseFee)
+		require.Positive(s.T(), block.MaxGas)
+	}
+
+	output, err = ExecuteCommand(ctx, params, "q", "tac", "proposer-tips")
+	require.NoError(s.T(), err, "Failed to query proposer tips: %s", output)
+	var proposerTips struct {
+		EVMTipShare string            `json:"evm_tip_share"`
+		Proposers   []json.RawMessage `json:"proposers"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &proposerTips), "Output should be a json document: %s", output)
+	require.Equal(s.T(), "0.000000000000000000", proposerTips.EVMTipShare)
+	require.Empty(s.T(), proposerTips.Proposers, "No tips should be routed with the default params")
+
+	output, err = ExecuteCommand(ctx, params, "q", "tac", "protocol-revenue")
+	require.NoError(s.T(), err, "Failed to query protocol revenue: %s", output)
+	var revenue struct {
+		Total struct {
+			ProposerTips string `json:"proposer_tips"`
+			CommunityTax string `json:"community_tax"`
+		} `json:"total"`
+		Epochs []json.RawMessage `json:"epochs"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &revenue), "Output should be a json document: %s", output)
+	require.Equal(s.T(), "0", revenue.Total.ProposerTips, "No tips should be routed with the default params")
+	require.NotEmpty(s.T(), revenue.Total.CommunityTax)
+	require.NotNil(s.T(), revenue.Epochs)
+}
+
+// TestTacMempoolQuery broadcasts a bank send without waiting for its inclusion
+// and looks it up, decoded, in the mempool. With blocks every second the tx
+// may be included before the query runs, so it is sent again until it is seen.
+func (s *TacchainTestSuite) TestTacMempoolQuery() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	validatorAddr, err := GetAddress(ctx, s, "validator")
+	require.NoError(s.T(), err, "Failed to get validator address")
+	recipient := randomAddress()
+
+	type mempoolTx struct {
+		Hash    string   `json:"hash"`
+		Senders []string `json:"senders"`
+		Msgs    []struct {
+			Type string          `json:"type"`
+			Msg  json.RawMessage `json:"msg"`
+		} `json:"msgs"`
+		Error string `json:"error"`
+	}
+	var found *mempoolTx
+	for attempt := 0; attempt < 10 && found == nil; attempt++ {
+		// the sequence of the next tx is only known once the last one is included
+		waitForNewBlock(s, nil)
+		output, err := TxBankSend(ctx, s, "validator", recipient, UTacAmount("1"))
+		require.NoError(s.T(), err, "Failed to send tokens: %s", output)
+		txHash := parseField(output, "txhash")
+		require.NotEmpty(s.T(), txHash, "Tx command should have returned a tx response: %s", output)
+
+		output, err = ExecuteCommand(ctx, s.CommandParamsHomeDir(), "q", "tac", "mempool", "--sender", validatorAddr)
+		require.NoError(s.T(), err, "Failed to query mempool: %s", output)
+		var res struct {
+			Txs []mempoolTx `json:"txs"`
+		}
+		require.NoError(s.T(), json.Unmarshal([]byte(output), &res), "Output should be a json document: %s", output)
+		for i := range res.Txs {
+			if res.Txs[i].Hash == txHash {
+				found = &res.Txs[i]
+			}
+		}
+	}
+	require.NotNil(s.T(), found, "The tx should show up in the mempool")
+	require.Empty(s.T(), found.Error)
+	require.Equal(s.T(), []string{validatorAddr}, found.Senders)
+	require.Len(s.T(), found.Msgs, 1)
+	require.Equal(s.T(), "/cosmos.bank.v1beta1.MsgSend", found.Msgs[0].Type)
+	require.Contains(s.T(), string(found.Msgs[0].Msg), recipient)
+}
+
+// TestTacTxProofQuery proves a bank send once the next block commits to its
+// result, and verifies the proof against the header the node signed.
+func (s *TacchainTestSuite) TestTacTxProofQuery() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	params := s.CommandParamsHomeDir()
+	output, err := TxBankSend(ctx, s, "validator", randomAddress(), UTacAmount("1"))
+	require.NoError(s.T(), err, "Failed to send tokens: %s", output)
+	txHash := parseField(output, "txhash")
+	require.NotEmpty(s.T(), txHash, "Tx command should have returned a tx response: %s", output)
+
+	// the tx is included in the next block and its result committed by the one after
+	waitForNewBlock(s, nil)
+	waitForNewBlock(s, nil)
+	tx, err := QueryCommittedTx(ctx, params, txHash)
+	require.NoError(s.T(), err)
+
+	output, err = ExecuteCommand(ctx, params, "q", "tac", "tx-proof", txHash)
+	require.NoError(s.T(), err, "Failed to query tx proof: %s", output)
+	var proof tacsdk.TxProof
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &proof), "Output should be a json document: %s", output)
+	require.NoError(s.T(), proof.Verify())
+	require.Equal(s.T(), tx.Height, proof.Height)
+	require.Equal(s.T(), txHash, fmt.Sprintf("%X", proof.Tx.Data.Hash()))
+	require.Zero(s.T(), proof.Result.Code)
+	require.Equal(s.T(), tx.GasUsed, proof.Result.GasUsed)
+	require.Equal(s.T(), proof.Height+1, proof.NextHeader.Height)
+}
@@ -8,15 +8,23 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"testing"
 	"time"
 
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 )
 
 const (
 	DefaultChainID        = "tacchain_2391-1"
 	DefaultDenom          = "utac"
 	DefaultKeyringBackend = "test"
+	DefaultBech32Prefix   = "tac"
 )
 
 type TacchainTestSuite struct {
@@ -29,6 +37,17 @@ type CommandParams struct {
 	ChainID        string
 	HomeDir        string
 	KeyringBackend string
+	Node           string
+	// KeyringDir is the directory of the keyring when it isn't the home dir
+	KeyringDir string
+	// Stdin is written to the standard input of the command, e.g. the
+	// passphrases of a file keyring, see PassphraseInput
+	Stdin string
+}
+
+// PassphraseInput returns the input answering n passphrase prompts with passphrase
+func PassphraseInput(passphrase string, n int) string {
+	return strings.Repeat(passphrase+"\n", n)
 }
 
 func (s *TacchainTestSuite) CommandParamsHomeDir() CommandParams {
@@ -64,6 +83,18 @@ func ExecuteCommand(ctx context.Context, params CommandParams, args ...string) (
 		cmd.Args = append(cmd.Args, "--keyring-backend", params.KeyringBackend)
 	}
 
+	if params.Node != "" {
+		cmd.Args = append(cmd.Args, "--node", params.Node)
+	}
+
+	if params.KeyringDir != "" {
+		cmd.Args = append(cmd.Args, "--keyring-dir", params.KeyringDir)
+	}
+
+	if params.Stdin != "" {
+		cmd.Stdin = strings.NewReader(params.Stdin)
+	}
+
 	output, err := cmd.CombinedOutput()
 	strOutput := string(output)
 
@@ -255,3 +286,49 @@ func ParseBoolField(output string, fieldName string) (bool, bool) {
 
 	return isTrue, found
 }
+
+// ModuleAddress returns the bech32 address of the given module account.
+func ModuleAddress(moduleName string) string {
+	return sdk.MustBech32ifyAddressBytes(DefaultBech32Prefix, authtypes.NewModuleAddress(moduleName))
+}
+
+// TxResult is the part of a tx response needed to tell why a tx failed.
+type TxResult struct {
+	TxHash    string `json:"txhash"`
+	Code      uint32 `json:"code"`
+	Codespace string `json:"codespace"`
+	RawLog    string `json:"raw_log"`
+}
+
+// AssertTxFailure asserts the tx broadcast by a tx command failed with the
+// expected error, whether CheckTx rejected it or it failed when the block
+// including it was executed. The latter returns code 0 on broadcast, so the
+// result is then looked up by hash with the given params.
+func AssertTxFailure(ctx context.Context, t *testing.T, params CommandParams, output string, expected *errorsmod.Error) {
+	t.Helper()
+
+	var res TxResult
+	require.NoError(t, json.Unmarshal([]byte(output), &res), "Tx command should have returned a tx response: %s", output)
+
+	for res.Code == 0 {
+		require.NotEmpty(t, res.TxHash, "Tx response should have a tx hash: %s", output)
+
+		output, _ = ExecuteCommand(ctx, params, "q", "tx", res.TxHash, "--output", "json")
+		var included TxResult
+		if err := json.Unmarshal([]byte(output), &included); err == nil && included.TxHash != "" {
+			require.NotZero(t, included.Code, "Tx %s should have failed: %s", res.TxHash, included.RawLog)
+			res = included
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			t.Fatalf("Tx %s was not included: %v", res.TxHash, ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+
+	require.Equal(t, expected.Codespace(), res.Codespace, "Unexpected codespace, log: %s", res.RawLog)
+	require.Equal(t, expected.ABCICode(), res.Code, "Unexpected code, log: %s", res.RawLog)
+	require.Contains(t, res.RawLog, expected.Error())
+}
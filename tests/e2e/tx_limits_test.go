@@ -0,0 +1,302 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	TxLimitsChainID = "tacchain_2395-1"
+
+	testMaxTxBytes   = 4096
+	testMaxGasWanted = 1000000
+	testMaxPending   = 3
+)
+
+// TxLimitsTestSuite runs a dedicated chain with low tx limits in app.toml.
+type TxLimitsTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestTxLimitsTestSuite(t *testing.T) {
+	suite.Run(t, new(TxLimitsTestSuite))
+}
+
+func (s *TxLimitsTestSuite) SetupSuite() {
+	s.chain = &Chain{ChainID: TxLimitsChainID, PortOffset: 400}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.SetAppConfig("tx-limits", "max-tx-bytes", strconv.Itoa(testMaxTxBytes)); err != nil {
+		s.T().Fatalf("Failed to set max tx bytes: %v", err)
+	}
+	if err := s.chain.SetAppConfig("tx-limits", "max-gas-wanted", strconv.Itoa(testMaxGasWanted)); err != nil {
+		s.T().Fatalf("Failed to set max gas wanted: %v", err)
+	}
+	for _, key := range []string{"max-pending-cosmos-txs", "max-pending-evm-txs"} {
+		if err := s.chain.SetAppConfig("tx-limits", key, strconv.Itoa(testMaxPending)); err != nil {
+			s.T().Fatalf("Failed to set %s: %v", key, err)
+		}
+	}
+	// the localnet accepts unprotected txs
+	if err := s.chain.SetAppConfig("tx-limits", "reject-unprotected-evm-txs", "true"); err != nil {
+		s.T().Fatalf("Failed to reject unprotected txs: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *TxLimitsTestSuite) TearDownSuite() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+func (s *TxLimitsTestSuite) ethClient(ctx context.Context) *ethclient.Client {
+	client, err := ethclient.DialContext(ctx, s.chain.JSONRPCAddress())
+	require.NoError(s.T(), err, "Failed to dial json-rpc")
+	s.T().Cleanup(client.Close)
+	return client
+}
+
+func randomAddress() string {
+	bz := make([]byte, 20)
+	_, _ = rand.Read(bz)
+	return sdk.MustBech32ifyAddressBytes(DefaultBech32Prefix, bz)
+}
+
+func (s *TxLimitsTestSuite) TestCosmosTxTooLarge() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	// every output adds more than 60 bytes
+	args := []string{"tx", "bank", "multi-send", "validator"}
+	for i := 0; i < 100; i++ {
+		args = append(args, randomAddress())
+	}
+	args = append(args, UTacAmount("1"), "--gas", "900000", "--gas-prices", DefaultGasPrice, "-y")
+
+	output, err := ExecuteCommand(ctx, s.chain.TxParams(), args...)
+	require.Error(s.T(), err, "Oversized tx should be rejected: %s", output)
+	require.Contains(s.T(), output, "tx-limits.max-tx-bytes")
+}
+
+func (s *TxLimitsTestSuite) TestCosmosTxMaxGasWanted() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	send := func(gas int) (string, error) {
+		return ExecuteCommand(ctx, s.chain.TxParams(), "tx", "bank", "send", "validator", randomAddress(), UTacAmount("1"),
+			"--gas", strconv.Itoa(gas), "--gas-prices", DefaultGasPrice, "-y")
+	}
+
+	output, err := send(testMaxGasWanted + 1)
+	require.Error(s.T(), err, "Tx wanting more gas than the limit should be rejected: %s", output)
+	require.Contains(s.T(), output, "tx-limits.max-gas-wanted")
+
+	output, err = send(testMaxGasWanted)
+	require.NoError(s.T(), err, "Tx wanting the gas limit should be accepted: %s", output)
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 1))
+}
+
+func (s *TxLimitsTestSuite) TestEVMTxLimits() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	client := s.ethClient(ctx)
+	key, err := s.chain.EthPrivateKey(ctx, "validator")
+	require.NoError(s.T(), err)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	_, err = SendEthTx(ctx, client, key, &to, big.NewInt(1), 300000, make([]byte, 2*testMaxTxBytes))
+	require.Error(s.T(), err, "Oversized EVM tx should be rejected")
+	require.Contains(s.T(), err.Error(), "tx-limits.max-tx-bytes")
+
+	_, err = SendEthTx(ctx, client, key, &to, big.NewInt(1), testMaxGasWanted+1, nil)
+	require.Error(s.T(), err, "EVM tx wanting more gas than the limit should be rejected")
+	require.Contains(s.T(), err.Error(), "tx-limits.max-gas-wanted")
+
+	// rejected txs don't use up the nonce
+	receipt, err := SendEthTx(ctx, client, key, &to, big.NewInt(1), DefaultEthTransferGas, nil)
+	require.NoError(s.T(), err, "EVM tx within the limits should be accepted")
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status)
+}
+
+func (s *TxLimitsTestSuite) TestEVMReplayProtection() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	client := s.ethClient(ctx)
+	key, err := s.chain.EthPrivateKey(ctx, "validator")
+	require.NoError(s.T(), err)
+	chainID, err := client.ChainID(ctx)
+	require.NoError(s.T(), err)
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	require.NoError(s.T(), err)
+	nonce, err := client.PendingNonceAt(ctx, crypto.PubkeyToAddress(key.PublicKey))
+	require.NoError(s.T(), err)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	otherChainID := new(big.Int).Add(chainID, big.NewInt(1))
+
+	legacy := &gethtypes.LegacyTx{Nonce: nonce, To: &to, Value: big.NewInt(1), Gas: DefaultEthTransferGas, GasPrice: gasPrice}
+	unprotected, err := gethtypes.SignNewTx(key, gethtypes.HomesteadSigner{}, legacy)
+	require.NoError(s.T(), err)
+	err = client.SendTransaction(ctx, unprotected)
+	require.Error(s.T(), err, "Unprotected tx should be rejected")
+	require.Contains(s.T(), err.Error(), "tx-limits.reject-unprotected-evm-txs")
+
+	for _, txData := range []gethtypes.TxData{
+		legacy,
+		&gethtypes.AccessListTx{ChainID: otherChainID, Nonce: nonce, To: &to, Value: big.NewInt(1), Gas: DefaultEthTransferGas, GasPrice: gasPrice},
+		&gethtypes.DynamicFeeTx{ChainID: otherChainID, Nonce: nonce, To: &to, Value: big.NewInt(1), Gas: DefaultEthTransferGas, GasFeeCap: gasPrice, GasTipCap: gasPrice},
+	} {
+		tx, err := gethtypes.SignNewTx(key, gethtypes.LatestSignerForChainID(otherChainID), txData)
+		require.NoError(s.T(), err)
+		err = client.SendTransaction(ctx, tx)
+		require.Error(s.T(), err, "Tx of type %d signed for another chain should be rejected", tx.Type())
+		require.Contains(s.T(), err.Error(), "chain id")
+	}
+
+	// rejected txs don't use up the nonce
+	tx, err := gethtypes.SignNewTx(key, gethtypes.LatestSignerForChainID(chainID), legacy)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), client.SendTransaction(ctx, tx), "Replay protected EVM tx should be accepted")
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status)
+}
+
+// signCosmosSend signs a bank send from the validator offline with the given
+// sequence and returns the base64 encoded tx.
+func (s *TxLimitsTestSuite) signCosmosSend(ctx context.Context, accountNumber, sequence uint64) string {
+	params := s.chain.TxParams()
+	unsigned, err := ExecuteCommand(ctx, params, "tx", "bank", "send", "validator", randomAddress(), UTacAmount("1"),
+		"--gas", DefaultGas, "--gas-prices", DefaultGasPrice, "--generate-only")
+	require.NoError(s.T(), err, "Failed to generate tx: %s", unsigned)
+	unsignedFile := filepath.Join(s.T().TempDir(), "unsigned.json")
+	require.NoError(s.T(), os.WriteFile(unsignedFile, []byte(unsigned), 0o600))
+
+	signed, err := ExecuteCommand(ctx, params, "tx", "sign", unsignedFile, "--from", "validator", "--offline",
+		"--account-number", strconv.FormatUint(accountNumber, 10), "--sequence", strconv.FormatUint(sequence, 10))
+	require.NoError(s.T(), err, "Failed to sign tx: %s", signed)
+	signedFile := filepath.Join(s.T().TempDir(), "signed.json")
+	require.NoError(s.T(), os.WriteFile(signedFile, []byte(signed), 0o600))
+
+	encoded, err := ExecuteCommand(ctx, params, "tx", "encode", signedFile)
+	require.NoError(s.T(), err, "Failed to encode tx: %s", encoded)
+	return strings.TrimSpace(encoded)
+}
+
+// broadcastTxSync broadcasts an encoded tx straight to the CometBFT RPC, which
+// is much faster than the CLI, and returns its CheckTx code and log.
+func (s *TxLimitsTestSuite) broadcastTxSync(ctx context.Context, txBase64 string) (uint32, string) {
+	body, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "broadcast_tx_sync",
+		"params":  map[string]string{"tx": txBase64},
+	})
+	require.NoError(s.T(), err)
+
+	url := strings.Replace(s.chain.RPCAddress(), "tcp://", "http://", 1)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	require.NoError(s.T(), err)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(s.T(), err, "Failed to broadcast tx")
+	defer resp.Body.Close()
+
+	var res struct {
+		Result struct {
+			Code uint32 `json:"code"`
+			Log  string `json:"log"`
+		} `json:"result"`
+		Error *struct {
+			Data string `json:"data"`
+		} `json:"error"`
+	}
+	require.NoError(s.T(), json.NewDecoder(resp.Body).Decode(&res))
+	require.Nil(s.T(), res.Error, "Broadcast failed")
+	return res.Result.Code, res.Result.Log
+}
+
+func (s *TxLimitsTestSuite) TestPendingTxLimits() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	client := s.ethClient(ctx)
+	key, err := s.chain.EthPrivateKey(ctx, "validator")
+	require.NoError(s.T(), err)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	validator, err := s.chain.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+
+	// the txs over the limit must reach the node within the same block as the
+	// pending ones, retry if a block cuts in
+	var cosmosTx, cosmosLog string
+	var cosmosCode uint32
+	var evmErr error
+	for attempt := 0; attempt < 3; attempt++ {
+		output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "account-nonce", validator)
+		require.NoError(s.T(), err, "Failed to query account nonce: %s", output)
+		var account struct {
+			AccountNumber uint64 `json:"account_number"`
+			Sequence      uint64 `json:"sequence"`
+		}
+		require.NoError(s.T(), json.Unmarshal([]byte(output), &account), "Output should be a json document: %s", output)
+
+		// signed up front, broadcasting through the CLI is too slow
+		cosmosTx = s.signCosmosSend(ctx, account.AccountNumber, account.Sequence+testMaxPending)
+
+		for i := uint64(0); i < testMaxPending; i++ {
+			_, err := BroadcastEthTx(ctx, client, key, account.Sequence+i, &to, big.NewInt(1), DefaultEthTransferGas, nil)
+			require.NoError(s.T(), err, "EVM tx %d within the pending limit should be accepted", i)
+		}
+
+		// cosmos and EVM txs share the sequence, so both limits are reached
+		cosmosCode, cosmosLog = s.broadcastTxSync(ctx, cosmosTx)
+		_, evmErr = BroadcastEthTx(ctx, client, key, account.Sequence+testMaxPending, &to, big.NewInt(1), DefaultEthTransferGas, nil)
+		if cosmosCode != 0 && evmErr != nil {
+			break
+		}
+		require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 2))
+	}
+	require.NotZero(s.T(), cosmosCode, "Cosmos tx over the pending limit should be rejected")
+	require.Contains(s.T(), cosmosLog, "tx-limits.max-pending-cosmos-txs")
+	require.Error(s.T(), evmErr, "EVM tx over the pending limit should be rejected")
+	require.Contains(s.T(), evmErr.Error(), "tx-limits.max-pending-evm-txs")
+
+	// once the pending txs are included the rejected tx is accepted
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 2))
+	code, log := s.broadcastTxSync(ctx, cosmosTx)
+	require.Zero(s.T(), code, "Cosmos tx should be accepted once the pending txs are included: %s", log)
+	require.NoError(s.T(), s.chain.WaitForBlocks(ctx, 2))
+
+	receipt, err := SendEthTx(ctx, client, key, &to, big.NewInt(1), DefaultEthTransferGas, nil)
+	require.NoError(s.T(), err, "EVM tx should be accepted once the pending txs are included")
+	require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status)
+}
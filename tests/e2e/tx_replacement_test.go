@@ -0,0 +1,177 @@
+package e2e
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const ReplacementChainID = "tacchain_2416-1"
+
+// ReplacementTestSuite runs a network of validators, so an isolated node
+// keeps the txs sent to it pending while the others produce blocks.
+type ReplacementTestSuite struct {
+	suite.Suite
+
+	network *Network
+}
+
+func TestReplacementTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("replacement tests run a network of validators")
+	}
+	suite.Run(t, new(ReplacementTestSuite))
+}
+
+func (s *ReplacementTestSuite) SetupSuite() {
+	s.network = &Network{ChainID: ReplacementChainID}
+	if err := s.network.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize network: %v", err)
+	}
+	if err := s.network.Start(); err != nil {
+		s.T().Fatalf("Failed to start network: %v", err)
+	}
+}
+
+func (s *ReplacementTestSuite) TearDownSuite() {
+	if s.network != nil {
+		s.network.Cleanup()
+	}
+}
+
+// sendDynamicFeeTx signs and sends a transfer of 1 to to at nonce, with the
+// given fee caps
+func (s *ReplacementTestSuite) sendDynamicFeeTx(ctx context.Context, client *ethclient.Client, key *ecdsa.PrivateKey, nonce uint64, to common.Address, feeCap, tipCap *big.Int) (*gethtypes.Transaction, error) {
+	chainID, err := client.ChainID(ctx)
+	require.NoError(s.T(), err)
+	tx, err := gethtypes.SignNewTx(key, gethtypes.LatestSignerForChainID(chainID), &gethtypes.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		To:        &to,
+		Value:     big.NewInt(1),
+		Gas:       DefaultEthTransferGas,
+		GasFeeCap: feeCap,
+		GasTipCap: tipCap,
+	})
+	require.NoError(s.T(), err)
+	return tx, client.SendTransaction(ctx, tx)
+}
+
+// TestReplaceAndCancelNonce isolates a validator, so the txs sent to it stay
+// in its mempool, replaces a pending EVM tx over JSON-RPC and cancels the
+// nonce with tx evm cancel-nonce, then checks only the cancelling self-send
+// consumed the nonce once the network is healed.
+func (s *ReplacementTestSuite) TestReplaceAndCancelNonce() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	node := s.network.Nodes[0]
+	client, err := ethclient.DialContext(ctx, s.network.JSONRPCAddress(0))
+	require.NoError(s.T(), err, "Failed to dial json-rpc")
+	defer client.Close()
+	key, err := node.EthPrivateKey(ctx, "validator")
+	require.NoError(s.T(), err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	validatorAddr, err := node.Address(ctx, "validator")
+	require.NoError(s.T(), err)
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	require.NoError(s.T(), node.WaitForBlocks(ctx, 2))
+	s.network.Isolate(0)
+	defer s.network.Heal()
+
+	nonce, err := client.NonceAt(ctx, from, nil)
+	require.NoError(s.T(), err)
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	require.NoError(s.T(), err)
+	feeCap := new(big.Int).Mul(gasPrice, big.NewInt(2))
+
+	pending, err := s.sendDynamicFeeTx(ctx, client, key, nonce, to, feeCap, gasPrice)
+	require.NoError(s.T(), err, "Failed to send the pending tx")
+
+	// a replacement must raise both fee caps by the price bump, 10% by default
+	underpriced := func(price *big.Int) *big.Int {
+		return new(big.Int).Div(new(big.Int).Mul(price, big.NewInt(105)), big.NewInt(100))
+	}
+	_, err = s.sendDynamicFeeTx(ctx, client, key, nonce, to, underpriced(feeCap), underpriced(gasPrice))
+	require.ErrorContains(s.T(), err, "replacement transaction underpriced")
+
+	replacement, err := s.sendDynamicFeeTx(ctx, client, key, nonce, to, new(big.Int).Mul(feeCap, big.NewInt(2)), new(big.Int).Mul(gasPrice, big.NewInt(2)))
+	require.NoError(s.T(), err, "Failed to replace the pending tx")
+
+	// the self-send replaces the replacement in turn
+	output, err := ExecuteCommand(ctx, node.TxParams(), "tx", "evm", "cancel-nonce", strconv.FormatUint(nonce, 10), "--from", "validator", "-y")
+	require.NoError(s.T(), err, "Failed to cancel the nonce: %s", output)
+	require.Equal(s.T(), "0", parseField(output, "code"), "The self-send should be accepted: %s", output)
+
+	output, err = ExecuteCommand(ctx, node.QueryParams(), "q", "tac", "mempool", "--sender", validatorAddr)
+	require.NoError(s.T(), err, "Failed to query mempool: %s", output)
+	var mempool struct {
+		Txs []struct {
+			Msgs []struct {
+				EVM *struct {
+					Hash  string `json:"hash"`
+					From  string `json:"from"`
+					To    string `json:"to"`
+					Nonce uint64 `json:"nonce"`
+					Value string `json:"value"`
+				} `json:"evm"`
+			} `json:"msgs"`
+		} `json:"txs"`
+	}
+	require.NoError(s.T(), json.Unmarshal([]byte(output), &mempool), "Output should be a json document: %s", output)
+	var pendingHashes []string
+	var cancelHash common.Hash
+	for _, tx := range mempool.Txs {
+		for _, msg := range tx.Msgs {
+			if msg.EVM == nil || msg.EVM.Nonce != nonce {
+				continue
+			}
+			pendingHashes = append(pendingHashes, msg.EVM.Hash)
+			if msg.EVM.To == from.Hex() {
+				require.Equal(s.T(), from.Hex(), msg.EVM.From)
+				require.Equal(s.T(), "0", msg.EVM.Value)
+				cancelHash = common.HexToHash(msg.EVM.Hash)
+			}
+		}
+	}
+	// the mempool of CometBFT keeps the replaced txs until the next block
+	require.ElementsMatch(s.T(), []string{pending.Hash().Hex(), replacement.Hash().Hex(), cancelHash.Hex()}, pendingHashes)
+
+	s.network.Heal()
+	require.Eventually(s.T(), func() bool {
+		current, err := client.NonceAt(ctx, from, nil)
+		return err == nil && current > nonce
+	}, 2*time.Minute, time.Second, "The nonce should be used once the network is healed")
+
+	// the nodes leave the replaced txs out of the blocks they propose, a
+	// validator could still include one it received before its replacement,
+	// the nonce is used by a single tx in any case
+	var included []common.Hash
+	for _, hash := range []common.Hash{pending.Hash(), replacement.Hash(), cancelHash} {
+		receipt, err := client.TransactionReceipt(ctx, hash)
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		require.NoError(s.T(), err)
+		require.Equal(s.T(), gethtypes.ReceiptStatusSuccessful, receipt.Status)
+		included = append(included, hash)
+	}
+	require.Len(s.T(), included, 1, "A single tx should use the nonce")
+	if included[0] != cancelHash {
+		s.T().Logf("tx %s was included before its replacement reached the proposer", included[0].Hex())
+	}
+}
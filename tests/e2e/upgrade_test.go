@@ -0,0 +1,168 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+)
+
+const (
+	UpgradeChainID = "tacchain_2394-1"
+	// name of an upgrade the binary has no handler for, so the node halts at its height
+	TestUpgradeName = "v99.0.0"
+	// name of the latest upgrade the binary has a handler for, so the node keeps running
+	KnownUpgradeName = "v0.0.12"
+)
+
+// UpgradeTestSuite runs a dedicated chain since a software upgrade halts it.
+type UpgradeTestSuite struct {
+	suite.Suite
+
+	chain *Chain
+}
+
+func TestUpgradeTestSuite(t *testing.T) {
+	suite.Run(t, new(UpgradeTestSuite))
+}
+
+func (s *UpgradeTestSuite) SetupTest() {
+	s.chain = &Chain{ChainID: UpgradeChainID, PortOffset: 300}
+	if err := s.chain.Init(); err != nil {
+		s.T().Fatalf("Failed to initialize chain: %v", err)
+	}
+	if err := s.chain.Start(); err != nil {
+		s.T().Fatalf("Failed to start chain: %v", err)
+	}
+}
+
+func (s *UpgradeTestSuite) TearDownTest() {
+	if s.chain != nil {
+		s.chain.Cleanup()
+	}
+}
+
+// upgradeInfo mirrors the upgrade-info.json cosmovisor reads to switch binaries
+type upgradeInfo struct {
+	Name   string `json:"name"`
+	Height int64  `json:"height"`
+	Info   string `json:"info"`
+}
+
+// scheduleUpgrade passes a software upgrade proposal for the given height.
+func (s *UpgradeTestSuite) scheduleUpgrade(ctx context.Context, name string, height int64, info string) {
+	proposal := map[string]any{
+		"messages": []map[string]any{{
+			"@type":     "/cosmos.upgrade.v1beta1.MsgSoftwareUpgrade",
+			"authority": ModuleAddress(govtypes.ModuleName),
+			"plan": map[string]string{
+				"name":   name,
+				"height": strconv.FormatInt(height, 10),
+				"info":   info,
+			},
+		}},
+		"deposit": UTacAmount("10000000000000000"),
+		"title":   fmt.Sprintf("Upgrade to %s", name),
+		"summary": fmt.Sprintf("Software upgrade to %s at height %d", name, height),
+	}
+	bz, err := json.Marshal(proposal)
+	require.NoError(s.T(), err)
+
+	proposalFile := filepath.Join(s.chain.HomeDir, fmt.Sprintf("upgrade-%s.json", name))
+	require.NoError(s.T(), os.WriteFile(proposalFile, bz, 0o644))
+
+	proposalID, err := s.chain.SubmitProposal(ctx, "validator", proposalFile)
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.chain.PassProposal(ctx, "validator", proposalID))
+
+	output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "upgrade", "plan", "--output", "json")
+	require.NoError(s.T(), err, "Failed to query upgrade plan: %s", output)
+	require.Equal(s.T(), name, parseField(output, "name"), "Upgrade plan should be scheduled")
+}
+
+// waitForUpgradeHalt waits until the node wrote upgrade-info.json and returns
+// its content together with the highest block height the node reported.
+func (s *UpgradeTestSuite) waitForUpgradeHalt(ctx context.Context) (upgradeInfo, int64) {
+	infoPath := filepath.Join(s.chain.HomeDir, "data", "upgrade-info.json")
+
+	var maxHeight int64
+	for {
+		if height := s.chain.Height(ctx); height > maxHeight {
+			maxHeight = height
+		}
+
+		if bz, err := os.ReadFile(infoPath); err == nil {
+			var info upgradeInfo
+			require.NoError(s.T(), json.Unmarshal(bz, &info), "Invalid upgrade-info.json: %s", bz)
+
+			// give a halting node time to make any further progress it would wrongly make
+			time.Sleep(3 * time.Second)
+			if height := s.chain.Height(ctx); height > maxHeight {
+				maxHeight = height
+			}
+			return info, maxHeight
+		}
+
+		select {
+		case <-ctx.Done():
+			s.T().Fatalf("Node did not write %s: %v", infoPath, ctx.Err())
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+func (s *UpgradeTestSuite) TestUpgradeInfoEmission() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	upgradeHeight := s.chain.Height(ctx) + 15
+	planInfo := `{"binaries":{"linux/amd64":"https://example.com/tacchaind"}}`
+	s.scheduleUpgrade(ctx, TestUpgradeName, upgradeHeight, planInfo)
+
+	info, lastHeight := s.waitForUpgradeHalt(ctx)
+	require.Equal(s.T(), TestUpgradeName, info.Name)
+	require.Equal(s.T(), upgradeHeight, info.Height)
+	require.Equal(s.T(), planInfo, info.Info, "Plan info is passed through for cosmovisor auto-download")
+
+	// the upgrade block itself must not be committed by the old binary
+	require.Equal(s.T(), upgradeHeight-1, lastHeight, "Node should halt right before the upgrade height")
+}
+
+func (s *UpgradeTestSuite) TestBackupBeforeUpgrade() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	// the backup is configured in app.toml, so restart the node with it enabled
+	require.NoError(s.T(), s.chain.Stop())
+	require.NoError(s.T(), s.chain.SetAppConfig("backup", "enable", "true"))
+	require.NoError(s.T(), s.chain.Start())
+
+	upgradeHeight := s.chain.Height(ctx) + 15
+	s.scheduleUpgrade(ctx, KnownUpgradeName, upgradeHeight, "")
+
+	// the node runs the handler and continues past the upgrade height
+	for s.chain.Height(ctx) <= upgradeHeight {
+		select {
+		case <-ctx.Done():
+			s.T().Fatalf("Chain did not pass upgrade height %d: %v", upgradeHeight, ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+
+	output, err := ExecuteCommand(ctx, s.chain.QueryParams(), "q", "upgrade", "applied", KnownUpgradeName)
+	require.NoError(s.T(), err, "Upgrade should be applied: %s", output)
+
+	backupDir := filepath.Join(s.chain.HomeDir, "backups", fmt.Sprintf("%s-%d", KnownUpgradeName, upgradeHeight))
+	info, err := os.Stat(filepath.Join(backupDir, "application.db"))
+	require.NoError(s.T(), err, "State should be backed up before the upgrade handler ran")
+	require.True(s.T(), info.IsDir())
+}
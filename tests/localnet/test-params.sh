@@ -277,7 +277,8 @@ expected_evm_params='{
     "0x0000000000000000000000000000000000000804",
     "0x0000000000000000000000000000000000000805",
     "0x0000000000000000000000000000000000000806",
-    "0x0000000000000000000000000000000000000807"
+    "0x0000000000000000000000000000000000000807",
+    "0x0000000000000000000000000000000000000900"
   ]
 }'
 evm_params=$(tacchaind q evm params --node http://localhost:45111 --output json | jq -r '.params')
@@ -0,0 +1,19 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/accountactivity/types"
+)
+
+// InitGenesis initializes the accountactivity module state from a genesis state
+func (k Keeper) InitGenesis(ctx sdk.Context, gs types.GenesisState) {
+	k.SetParams(ctx, gs.Params)
+}
+
+// ExportGenesis returns the accountactivity module genesis state
+func (k Keeper) ExportGenesis(ctx sdk.Context) *types.GenesisState {
+	return &types.GenesisState{
+		Params: k.GetParams(ctx),
+	}
+}
@@ -0,0 +1,98 @@
+package keeper
+
+import (
+	"encoding/json"
+
+	corestoretypes "cosmossdk.io/core/store"
+	"cosmossdk.io/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/Asphere-xyz/tacchain/x/accountactivity/types"
+)
+
+// Keeper of the accountactivity store
+type Keeper struct {
+	storeService corestoretypes.KVStoreService
+	paramSpace   paramtypes.Subspace
+}
+
+// NewKeeper creates a new accountactivity Keeper instance
+func NewKeeper(storeService corestoretypes.KVStoreService, paramSpace paramtypes.Subspace) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		storeService: storeService,
+		paramSpace:   paramSpace,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current accountactivity module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the accountactivity module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// GetActivity returns the activity of an account, if it was seen since the
+// indexing was enabled
+func (k Keeper) GetActivity(ctx sdk.Context, addr sdk.AccAddress) (types.Activity, bool) {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.ActivityKey(addr))
+	if err != nil {
+		panic(err)
+	}
+	if bz == nil {
+		return types.Activity{}, false
+	}
+	var activity types.Activity
+	if err := json.Unmarshal(bz, &activity); err != nil {
+		panic(err)
+	}
+	return activity, true
+}
+
+func (k Keeper) setActivity(ctx sdk.Context, addr sdk.AccAddress, activity types.Activity) {
+	bz, err := json.Marshal(activity)
+	if err != nil {
+		panic(err)
+	}
+	if err := k.storeService.OpenKVStore(ctx).Set(types.ActivityKey(addr), bz); err != nil {
+		panic(err)
+	}
+}
+
+// RecordTx counts a tx of the block for each of its signers, the gas it
+// used goes to the one that paid its fees. It does nothing unless the
+// indexing is enabled.
+func (k Keeper) RecordTx(ctx sdk.Context, signers []sdk.AccAddress, payer sdk.AccAddress, gasUsed uint64) {
+	if !k.GetParams(ctx).Enabled {
+		return
+	}
+
+	seen := map[string]bool{}
+	for _, signer := range signers {
+		if seen[string(signer)] {
+			continue
+		}
+		seen[string(signer)] = true
+
+		activity, _ := k.GetActivity(ctx, signer)
+		var gas uint64
+		if signer.Equals(payer) {
+			gas = gasUsed
+		}
+		k.setActivity(ctx, signer, activity.Record(ctx.BlockHeight(), ctx.BlockTime(), gas))
+	}
+}
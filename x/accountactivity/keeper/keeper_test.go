@@ -0,0 +1,91 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/accountactivity/types"
+)
+
+func setup(t *testing.T) (*app.TacChainApp, sdk.Context) {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	return tacApp, tacApp.NewContext(false).WithChainID(app.DefaultChainID)
+}
+
+var (
+	payer  = sdk.AccAddress([]byte("payer_______________"))
+	signer = sdk.AccAddress([]byte("signer______________"))
+)
+
+func TestRecordTx(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.AccountActivityKeeper
+	first, last := time.Unix(1_700_000_000, 0).UTC(), time.Unix(1_700_000_010, 0).UTC()
+
+	// nothing is recorded by default
+	require.False(t, k.GetParams(ctx).Enabled)
+	k.RecordTx(ctx.WithBlockHeight(4), []sdk.AccAddress{payer}, payer, 50_000)
+	_, found := k.GetActivity(ctx, payer)
+	require.False(t, found)
+
+	k.SetParams(ctx, types.Params{Enabled: true})
+	k.RecordTx(ctx.WithBlockHeight(5).WithBlockTime(first), []sdk.AccAddress{payer}, payer, 50_000)
+	// a signer listed twice counts the tx once, the gas goes to the payer
+	k.RecordTx(ctx.WithBlockHeight(10).WithBlockTime(last), []sdk.AccAddress{payer, signer, signer}, payer, 70_000)
+
+	activity, found := k.GetActivity(ctx, payer)
+	require.True(t, found)
+	require.Equal(t, types.Activity{
+		FirstSeenHeight: 5,
+		FirstSeenTime:   first,
+		LastSeenHeight:  10,
+		LastSeenTime:    last,
+		TxCount:         2,
+		GasUsed:         120_000,
+	}, activity)
+	activity, found = k.GetActivity(ctx, signer)
+	require.True(t, found)
+	require.Equal(t, types.Activity{
+		FirstSeenHeight: 10,
+		FirstSeenTime:   last,
+		LastSeenHeight:  10,
+		LastSeenTime:    last,
+		TxCount:         1,
+	}, activity)
+
+	// disabling keeps the counters recorded so far
+	k.SetParams(ctx, types.Params{})
+	k.RecordTx(ctx.WithBlockHeight(11), []sdk.AccAddress{signer}, signer, 30_000)
+	require.Equal(t, uint64(1), must(k.GetActivity(ctx, signer)).TxCount)
+}
+
+func TestGenesis(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.AccountActivityKeeper
+
+	k.InitGenesis(ctx, types.GenesisState{Params: types.Params{Enabled: true}})
+	require.Equal(t, &types.GenesisState{Params: types.Params{Enabled: true}}, k.ExportGenesis(ctx))
+	require.Equal(t, &types.GenesisState{Params: types.DefaultParams()}, types.DefaultGenesisState())
+}
+
+func must[T any](v T, found bool) T {
+	if !found {
+		panic("not found")
+	}
+	return v
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"time"
+)
+
+// Activity is the summary of the txs of an account since the indexing was
+// enabled. An account is seen when it signs a tx, or sends an EVM tx, that
+// succeeds.
+type Activity struct {
+	FirstSeenHeight int64     `json:"first_seen_height"`
+	FirstSeenTime   time.Time `json:"first_seen_time"`
+	LastSeenHeight  int64     `json:"last_seen_height"`
+	LastSeenTime    time.Time `json:"last_seen_time"`
+	TxCount         uint64    `json:"tx_count"`
+	// GasUsed is the gas of the txs the account paid the fees of
+	GasUsed uint64 `json:"gas_used"`
+}
+
+// Record returns the activity with a tx at height and t, the account paid
+// gasUsed for it
+func (a Activity) Record(height int64, t time.Time, gasUsed uint64) Activity {
+	if a.TxCount == 0 {
+		a.FirstSeenHeight, a.FirstSeenTime = height, t
+	}
+	a.LastSeenHeight, a.LastSeenTime = height, t
+	a.TxCount++
+	a.GasUsed += gasUsed
+	return a
+}
@@ -0,0 +1,35 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Asphere-xyz/tacchain/x/accountactivity/types"
+)
+
+func TestActivityRecord(t *testing.T) {
+	first, last := time.Unix(1_700_000_000, 0).UTC(), time.Unix(1_700_000_100, 0).UTC()
+
+	activity := types.Activity{}.Record(10, first, 21000)
+	require.Equal(t, types.Activity{
+		FirstSeenHeight: 10,
+		FirstSeenTime:   first,
+		LastSeenHeight:  10,
+		LastSeenTime:    first,
+		TxCount:         1,
+		GasUsed:         21000,
+	}, activity)
+
+	// a tx it signed without paying its fees
+	activity = activity.Record(60, last, 0)
+	require.Equal(t, types.Activity{
+		FirstSeenHeight: 10,
+		FirstSeenTime:   first,
+		LastSeenHeight:  60,
+		LastSeenTime:    last,
+		TxCount:         2,
+		GasUsed:         21000,
+	}, activity)
+}
@@ -0,0 +1,18 @@
+package types
+
+// GenesisState defines the accountactivity module genesis state
+type GenesisState struct {
+	Params Params `json:"params" yaml:"params"`
+}
+
+// DefaultGenesisState returns the default accountactivity module genesis state
+func DefaultGenesisState() *GenesisState {
+	return &GenesisState{
+		Params: DefaultParams(),
+	}
+}
+
+// Validate performs basic genesis state validation
+func (gs GenesisState) Validate() error {
+	return gs.Params.Validate()
+}
@@ -0,0 +1,21 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// ModuleName defines the accountactivity module name
+	ModuleName = "accountactivity"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+)
+
+// ActivityPrefix prefixes the activity of an account, keyed by address
+var ActivityPrefix = []byte{0x01}
+
+// ActivityKey returns the store key of the activity of an account
+func ActivityKey(addr sdk.AccAddress) []byte {
+	return append(append([]byte{}, ActivityPrefix...), addr...)
+}
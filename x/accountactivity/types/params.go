@@ -0,0 +1,49 @@
+package types
+
+import (
+	"fmt"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+// KeyEnabled is the param store key for enabling the indexing of the accounts
+var KeyEnabled = []byte("Enabled")
+
+// Params defines the accountactivity module parameters. The activity of the
+// accounts is only recorded when Enabled, the counters recorded before are
+// kept when it is disabled but go stale.
+type Params struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the accountactivity module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default accountactivity module parameters, the
+// indexing is disabled
+func DefaultParams() Params {
+	return Params{}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyEnabled, &p.Enabled, validateEnabled),
+	}
+}
+
+// Validate performs basic validation of the accountactivity module parameters
+func (p Params) Validate() error {
+	return validateEnabled(p.Enabled)
+}
+
+func validateEnabled(i interface{}) error {
+	if _, ok := i.(bool); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package keeper
+
+import (
+	corestoretypes "cosmossdk.io/core/store"
+	errorsmod "cosmossdk.io/errors"
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/Asphere-xyz/tacchain/x/autocompound/types"
+)
+
+// delegateMsgType is the type of the message delegators authorize the module
+// to send on their behalf
+var delegateMsgType = sdk.MsgTypeURL(&stakingtypes.MsgDelegate{})
+
+// Keeper of the autocompound store
+type Keeper struct {
+	storeService  corestoretypes.KVStoreService
+	paramSpace    paramtypes.Subspace
+	authzKeeper   types.AuthzKeeper
+	stakingKeeper types.StakingKeeper
+	distrKeeper   types.DistrKeeper
+}
+
+// NewKeeper creates a new autocompound Keeper instance
+func NewKeeper(
+	storeService corestoretypes.KVStoreService,
+	paramSpace paramtypes.Subspace,
+	authzKeeper types.AuthzKeeper,
+	stakingKeeper types.StakingKeeper,
+	distrKeeper types.DistrKeeper,
+) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		storeService:  storeService,
+		paramSpace:    paramSpace,
+		authzKeeper:   authzKeeper,
+		stakingKeeper: stakingKeeper,
+		distrKeeper:   distrKeeper,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current autocompound module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the autocompound module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// EndBlocker queues the delegators that opted in at the start of a pass and
+// compounds the rewards of at most MaxDelegatorsPerBlock queued delegators.
+func (k Keeper) EndBlocker(ctx sdk.Context) {
+	params := k.GetParams(ctx)
+	if params.IsPassStart(ctx.BlockHeight()) {
+		if queued := k.QueueDelegators(ctx); queued > 0 {
+			k.Logger(ctx).Info("queued delegators for compounding", "count", queued)
+		}
+	}
+	k.ProcessQueue(ctx, params)
+}
+
+// QueueDelegators queues every delegator that authorized the module to
+// delegate on their behalf and returns how many were queued. Nothing is queued
+// while the previous pass is still running.
+func (k Keeper) QueueDelegators(ctx sdk.Context) int {
+	if len(k.queuedDelegators(ctx, 1)) > 0 {
+		k.Logger(ctx).Info("skipping compounding pass, the previous one is still running")
+		return 0
+	}
+
+	store := k.storeService.OpenKVStore(ctx)
+	moduleAddr := types.ModuleAddress()
+	queued := 0
+	err := k.authzKeeper.IterateGrants(ctx, func(granter, grantee sdk.AccAddress, grant authz.Grant) (bool, error) {
+		if !grantee.Equals(moduleAddr) {
+			return false, nil
+		}
+		authorization, err := grant.GetAuthorization()
+		if err != nil || authorization.MsgTypeURL() != delegateMsgType {
+			return false, nil
+		}
+		if err := store.Set(types.QueueKey(granter), []byte{}); err != nil {
+			return true, err
+		}
+		queued++
+		return false, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return queued
+}
+
+// ProcessQueue compounds the rewards of the next MaxDelegatorsPerBlock queued
+// delegators and removes them from the queue. A delegator failing to compound
+// doesn't affect the others.
+func (k Keeper) ProcessQueue(ctx sdk.Context, params types.Params) {
+	store := k.storeService.OpenKVStore(ctx)
+	for _, delegator := range k.queuedDelegators(ctx, params.MaxDelegatorsPerBlock) {
+		if err := store.Delete(types.QueueKey(delegator)); err != nil {
+			panic(err)
+		}
+
+		cacheCtx, write := ctx.CacheContext()
+		if _, err := k.Compound(cacheCtx, delegator, params.MinRewards); err != nil {
+			k.Logger(ctx).Debug("rewards not compounded", "delegator", delegator, "reason", err)
+			continue
+		}
+		write()
+	}
+}
+
+// Compound withdraws the rewards of delegator from the validators its
+// authorization allows and delegates them again to the same validators, under
+// the authorization of the module. It returns the delegated amount and fails
+// if it is below minRewards, leaving the rewards unwithdrawn when run in a
+// cache context.
+func (k Keeper) Compound(ctx sdk.Context, delegator sdk.AccAddress, minRewards sdkmath.Int) (sdkmath.Int, error) {
+	moduleAddr := types.ModuleAddress()
+	if authorization, _ := k.authzKeeper.GetAuthorization(ctx, moduleAddr, delegator, delegateMsgType); authorization == nil {
+		return sdkmath.ZeroInt(), types.ErrNoAuthorization
+	}
+	// the rewards must be withdrawn to the delegator to be delegated again
+	withdrawAddr, err := k.distrKeeper.GetDelegatorWithdrawAddr(ctx, delegator)
+	if err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+	if !withdrawAddr.Equals(delegator) {
+		return sdkmath.ZeroInt(), types.ErrWithdrawAddress
+	}
+
+	bondDenom, err := k.stakingKeeper.BondDenom(ctx)
+	if err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+	delegations, err := k.stakingKeeper.GetDelegatorDelegations(ctx, delegator, types.MaxDelegationsPerDelegator)
+	if err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+
+	total := sdkmath.ZeroInt()
+	validators := uint32(0)
+	for _, delegation := range delegations {
+		// validators the authorization doesn't allow keep their rewards
+		valCtx, write := ctx.CacheContext()
+		amount, err := k.compoundDelegation(valCtx, delegator, delegation.GetValidatorAddr(), bondDenom)
+		if err != nil || !amount.IsPositive() {
+			continue
+		}
+		write()
+		total = total.Add(amount)
+		validators++
+	}
+
+	if total.IsZero() || total.LT(minRewards) {
+		return total, errorsmod.Wrapf(types.ErrBelowMinRewards, "%s%s < %s%s", total, bondDenom, minRewards, bondDenom)
+	}
+
+	if err := ctx.EventManager().EmitTypedEvent(&types.EventCompound{
+		Delegator:  delegator.String(),
+		Amount:     total.String(),
+		Validators: validators,
+	}); err != nil {
+		return total, err
+	}
+	return total, nil
+}
+
+// compoundDelegation withdraws the rewards of a delegation and delegates the
+// ones in the bond denom again to its validator.
+func (k Keeper) compoundDelegation(ctx sdk.Context, delegator sdk.AccAddress, validator string, bondDenom string) (sdkmath.Int, error) {
+	valAddr, err := sdk.ValAddressFromBech32(validator)
+	if err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+	rewards, err := k.distrKeeper.WithdrawDelegationRewards(ctx, delegator, valAddr)
+	if err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+	amount := rewards.AmountOf(bondDenom)
+	if !amount.IsPositive() {
+		return amount, nil
+	}
+
+	msg := stakingtypes.NewMsgDelegate(delegator.String(), validator, sdk.NewCoin(bondDenom, amount))
+	if _, err := k.authzKeeper.DispatchActions(ctx, types.ModuleAddress(), []sdk.Msg{msg}); err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+	return amount, nil
+}
+
+// IsQueued returns true if delegator is waiting to be compounded in the
+// current pass
+func (k Keeper) IsQueued(ctx sdk.Context, delegator sdk.AccAddress) bool {
+	found, err := k.storeService.OpenKVStore(ctx).Has(types.QueueKey(delegator))
+	if err != nil {
+		panic(err)
+	}
+	return found
+}
+
+// queuedDelegators returns at most limit queued delegators
+func (k Keeper) queuedDelegators(ctx sdk.Context, limit uint64) []sdk.AccAddress {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.QueuePrefix, storetypes.PrefixEndBytes(types.QueuePrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	var delegators []sdk.AccAddress
+	for ; iterator.Valid() && uint64(len(delegators)) < limit; iterator.Next() {
+		delegators = append(delegators, sdk.AccAddress(iterator.Key()[len(types.QueuePrefix):]))
+	}
+	return delegators
+}
@@ -0,0 +1,208 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/autocompound/types"
+)
+
+const (
+	stake    = 1_000_000
+	interval = 10
+)
+
+type testChain struct {
+	t       *testing.T
+	app     *app.TacChainApp
+	ctx     sdk.Context
+	valAddr sdk.ValAddress
+}
+
+func setup(t *testing.T) *testChain {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := tacApp.NewContext(false).WithChainID(app.DefaultChainID).WithBlockTime(time.Unix(1_700_000_000, 0))
+
+	params := types.DefaultParams()
+	params.Interval = interval
+	params.MinRewards = sdkmath.NewInt(1)
+	tacApp.AutoCompoundKeeper.SetParams(ctx, params)
+
+	validators, err := tacApp.StakingKeeper.GetAllValidators(ctx)
+	require.NoError(t, err)
+	valAddr, err := sdk.ValAddressFromBech32(validators[0].OperatorAddress)
+	require.NoError(t, err)
+	return &testChain{t: t, app: tacApp, ctx: ctx, valAddr: valAddr}
+}
+
+func (c *testChain) mint(addr sdk.AccAddress, amount sdkmath.Int) {
+	coins := sdk.NewCoins(sdk.NewCoin(app.BaseDenom, amount))
+	require.NoError(c.t, c.app.BankKeeper.MintCoins(c.ctx, minttypes.ModuleName, coins))
+	require.NoError(c.t, c.app.BankKeeper.SendCoinsFromModuleToAccount(c.ctx, minttypes.ModuleName, addr, coins))
+}
+
+// delegate delegates amount from the balance of delegator
+func (c *testChain) delegate(delegator sdk.AccAddress, amount sdkmath.Int) {
+	validator, err := c.app.StakingKeeper.GetValidator(c.ctx, c.valAddr)
+	require.NoError(c.t, err)
+	_, err = c.app.StakingKeeper.Delegate(c.ctx, delegator, amount, stakingtypes.Unbonded, validator, true)
+	require.NoError(c.t, err)
+}
+
+// newDelegator funds a new account and delegates stake to the validator
+func (c *testChain) newDelegator() sdk.AccAddress {
+	delegator := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	c.mint(delegator, sdkmath.NewInt(stake))
+	c.delegate(delegator, sdkmath.NewInt(stake))
+	return delegator
+}
+
+// optIn grants the module the authorization to delegate to the validator
+func (c *testChain) optIn(delegator sdk.AccAddress) {
+	authorization, err := stakingtypes.NewStakeAuthorization([]sdk.ValAddress{c.valAddr}, nil, stakingtypes.AuthorizationType_AUTHORIZATION_TYPE_DELEGATE, nil)
+	require.NoError(c.t, err)
+	expiration := c.ctx.BlockTime().Add(24 * time.Hour)
+	require.NoError(c.t, c.app.AuthzKeeper.SaveGrant(c.ctx, types.ModuleAddress(), delegator, authorization, &expiration))
+}
+
+// allocateRewards hands amount of rewards to the validator and its delegators
+func (c *testChain) allocateRewards(amount int64) {
+	c.mint(c.app.AccountKeeper.GetModuleAddress(distrtypes.ModuleName), sdkmath.NewInt(amount))
+	validator, err := c.app.StakingKeeper.GetValidator(c.ctx, c.valAddr)
+	require.NoError(c.t, err)
+	rewards := sdk.NewDecCoins(sdk.NewDecCoin(app.BaseDenom, sdkmath.NewInt(amount)))
+	require.NoError(c.t, c.app.DistrKeeper.AllocateTokensToValidator(c.ctx, validator, rewards))
+}
+
+func (c *testChain) shares(delegator sdk.AccAddress) sdkmath.LegacyDec {
+	delegation, err := c.app.StakingKeeper.GetDelegation(c.ctx, delegator, c.valAddr)
+	require.NoError(c.t, err)
+	return delegation.Shares
+}
+
+func (c *testChain) endBlock(height int64) []*types.EventCompound {
+	c.ctx = c.ctx.WithBlockHeight(height).WithEventManager(sdk.NewEventManager())
+	c.app.AutoCompoundKeeper.EndBlocker(c.ctx)
+
+	var events []*types.EventCompound
+	for _, event := range c.ctx.EventManager().ABCIEvents() {
+		if event.Type != proto.MessageName(&types.EventCompound{}) {
+			continue
+		}
+		msg, err := sdk.ParseTypedEvent(event)
+		require.NoError(c.t, err)
+		events = append(events, msg.(*types.EventCompound))
+	}
+	return events
+}
+
+func TestCompoundMatchesManualRestaking(t *testing.T) {
+	c := setup(t)
+	auto, manual, idle := c.newDelegator(), c.newDelegator(), c.newDelegator()
+	c.optIn(auto)
+
+	for pass := int64(1); pass <= 5; pass++ {
+		c.allocateRewards(10_000_000)
+
+		events := c.endBlock(pass * interval)
+		require.Len(t, events, 1, "pass %d", pass)
+		require.Equal(t, auto.String(), events[0].Delegator)
+
+		// the manual delegator withdraws and delegates its rewards itself
+		rewards, err := c.app.DistrKeeper.WithdrawDelegationRewards(c.ctx, manual, c.valAddr)
+		require.NoError(t, err)
+		require.Equal(t, rewards.AmountOf(app.BaseDenom).String(), events[0].Amount, "pass %d", pass)
+		c.delegate(manual, rewards.AmountOf(app.BaseDenom))
+
+		require.Equal(t, c.shares(manual).String(), c.shares(auto).String(), "pass %d", pass)
+		require.True(t, c.shares(auto).GT(c.shares(idle)), "pass %d", pass)
+	}
+
+	// the compounded rewards were all delegated
+	require.True(t, c.app.BankKeeper.GetBalance(c.ctx, auto, app.BaseDenom).IsZero())
+	require.Equal(t, c.app.BankKeeper.GetBalance(c.ctx, manual, app.BaseDenom).String(), c.app.BankKeeper.GetBalance(c.ctx, auto, app.BaseDenom).String())
+
+	// compounding only runs at the start of a pass
+	c.allocateRewards(10_000_000)
+	require.Empty(t, c.endBlock(5*interval+1))
+}
+
+func TestCompoundThresholdAndWithdrawAddress(t *testing.T) {
+	c := setup(t)
+	auto, redirected := c.newDelegator(), c.newDelegator()
+	c.optIn(auto)
+	c.optIn(redirected)
+	initialShares := c.shares(auto)
+	require.NoError(t, c.app.DistrKeeper.SetWithdrawAddr(c.ctx, redirected, sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())))
+
+	params := c.app.AutoCompoundKeeper.GetParams(c.ctx)
+	params.MinRewards = sdkmath.NewInt(1_000_000)
+	c.app.AutoCompoundKeeper.SetParams(c.ctx, params)
+
+	// rewards below the threshold are left to accrue
+	c.allocateRewards(1_000_000)
+	require.Empty(t, c.endBlock(interval))
+	require.Equal(t, initialShares.String(), c.shares(auto).String())
+
+	// until they reach it in a later pass
+	c.allocateRewards(10_000_000)
+	events := c.endBlock(2 * interval)
+	require.Len(t, events, 1)
+	require.Equal(t, auto.String(), events[0].Delegator)
+	require.True(t, c.shares(auto).GT(initialShares))
+
+	// rewards withdrawn to another address aren't compounded
+	require.Equal(t, initialShares.String(), c.shares(redirected).String())
+}
+
+func TestMaxDelegatorsPerBlock(t *testing.T) {
+	c := setup(t)
+	k := c.app.AutoCompoundKeeper
+	params := k.GetParams(c.ctx)
+	params.MaxDelegatorsPerBlock = 2
+	k.SetParams(c.ctx, params)
+
+	delegators := []sdk.AccAddress{c.newDelegator(), c.newDelegator(), c.newDelegator(), c.newDelegator(), c.newDelegator()}
+	for _, delegator := range delegators {
+		c.optIn(delegator)
+	}
+	idle := c.newDelegator()
+	c.allocateRewards(10_000_000)
+
+	// the pass is spread over the following blocks
+	compounded := map[string]bool{}
+	for i, expected := range []int{2, 2, 1, 0} {
+		events := c.endBlock(interval + int64(i))
+		require.Len(t, events, expected, "block %d of the pass", i)
+		for _, event := range events {
+			require.False(t, compounded[event.Delegator], "%s compounded twice", event.Delegator)
+			compounded[event.Delegator] = true
+		}
+	}
+	require.Len(t, compounded, len(delegators))
+	require.False(t, compounded[idle.String()])
+	for _, delegator := range delegators {
+		require.False(t, k.IsQueued(c.ctx, delegator))
+	}
+}
@@ -0,0 +1,12 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+)
+
+// x/autocompound module sentinel errors
+var (
+	ErrNoAuthorization = errorsmod.Register(ModuleName, 2, "no authorization to delegate")
+	ErrWithdrawAddress = errorsmod.Register(ModuleName, 3, "rewards are withdrawn to another address")
+	ErrBelowMinRewards = errorsmod.Register(ModuleName, 4, "rewards below the compounding threshold")
+)
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"github.com/cosmos/gogoproto/proto"
+)
+
+// init registers EventCompound, emitted for each delegator whose rewards
+// the module delegated again.
+func init() {
+	proto.RegisterType((*EventCompound)(nil), "tacchain.autocompound.v1.EventCompound")
+}
+
+// EventCompound is emitted when the rewards of a delegator were delegated
+// again.
+type EventCompound struct {
+	Delegator string `protobuf:"bytes,1,opt,name=delegator,proto3" json:"delegator,omitempty"`
+	Amount    string `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Validators is the number of validators the rewards were delegated to
+	Validators uint32 `protobuf:"varint,3,opt,name=validators,proto3" json:"validators,omitempty"`
+}
+
+func (m *EventCompound) Reset()         { *m = EventCompound{} }
+func (m *EventCompound) String() string { return proto.CompactTextString(m) }
+func (*EventCompound) ProtoMessage()    {}
@@ -0,0 +1,34 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+const (
+	// ModuleName defines the autocompound module name
+	ModuleName = "autocompound"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+
+	// MaxDelegationsPerDelegator bounds the delegations of a delegator that are
+	// compounded, the ones past it are left alone
+	MaxDelegationsPerDelegator = 50
+)
+
+var (
+	// QueuePrefix prefixes the delegators left to compound in the current pass
+	QueuePrefix = []byte{0x01}
+)
+
+// ModuleAddress returns the address delegators grant the authorization to
+// delegate on their behalf to
+func ModuleAddress() sdk.AccAddress {
+	return authtypes.NewModuleAddress(ModuleName)
+}
+
+// QueueKey returns the store key queuing delegator for compounding
+func QueueKey(delegator sdk.AccAddress) []byte {
+	return append(append([]byte{}, QueuePrefix...), delegator...)
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"fmt"
+
+	sdkmath "cosmossdk.io/math"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+const (
+	// DefaultInterval is a day of 2 second blocks
+	DefaultInterval uint64 = 43_200
+	// DefaultMaxDelegatorsPerBlock bounds the work of the end blocker
+	DefaultMaxDelegatorsPerBlock uint64 = 100
+)
+
+var (
+	// KeyInterval is the param store key for the number of blocks between compounding passes
+	KeyInterval = []byte("Interval")
+	// KeyMinRewards is the param store key for the rewards a delegator needs to be compounded
+	KeyMinRewards = []byte("MinRewards")
+	// KeyMaxDelegatorsPerBlock is the param store key for the delegators compounded in a block
+	KeyMaxDelegatorsPerBlock = []byte("MaxDelegatorsPerBlock")
+)
+
+// Params defines the autocompound module parameters. Every Interval blocks the
+// delegators that opted in are queued and their rewards are withdrawn and
+// delegated again, MaxDelegatorsPerBlock of them per block, once their
+// rewards in the bond denom reach MinRewards. An Interval of zero disables
+// compounding.
+type Params struct {
+	Interval              uint64      `json:"interval" yaml:"interval"`
+	MinRewards            sdkmath.Int `json:"min_rewards" yaml:"min_rewards"`
+	MaxDelegatorsPerBlock uint64      `json:"max_delegators_per_block" yaml:"max_delegators_per_block"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the autocompound module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default autocompound module parameters, compounding
+// once a day rewards of at least one TAC.
+func DefaultParams() Params {
+	return Params{
+		Interval:              DefaultInterval,
+		MinRewards:            sdkmath.NewIntWithDecimal(1, 18),
+		MaxDelegatorsPerBlock: DefaultMaxDelegatorsPerBlock,
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyInterval, &p.Interval, validateInterval),
+		paramtypes.NewParamSetPair(KeyMinRewards, &p.MinRewards, validateMinRewards),
+		paramtypes.NewParamSetPair(KeyMaxDelegatorsPerBlock, &p.MaxDelegatorsPerBlock, validateMaxDelegatorsPerBlock),
+	}
+}
+
+// Validate performs basic validation of the autocompound module parameters
+func (p Params) Validate() error {
+	if err := validateInterval(p.Interval); err != nil {
+		return err
+	}
+	if err := validateMinRewards(p.MinRewards); err != nil {
+		return err
+	}
+	return validateMaxDelegatorsPerBlock(p.MaxDelegatorsPerBlock)
+}
+
+// IsPassStart returns true if a compounding pass starts at height
+func (p Params) IsPassStart(height int64) bool {
+	return p.Interval > 0 && height > 0 && uint64(height)%p.Interval == 0
+}
+
+func validateInterval(i interface{}) error {
+	if _, ok := i.(uint64); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return nil
+}
+
+func validateMinRewards(i interface{}) error {
+	v, ok := i.(sdkmath.Int)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v.IsNil() || v.IsNegative() {
+		return fmt.Errorf("min rewards must be non-negative: %s", v)
+	}
+	return nil
+}
+
+func validateMaxDelegatorsPerBlock(i interface{}) error {
+	v, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v == 0 {
+		return fmt.Errorf("max delegators per block must be positive")
+	}
+	return nil
+}
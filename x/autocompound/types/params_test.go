@@ -0,0 +1,42 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/Asphere-xyz/tacchain/x/autocompound/types"
+)
+
+func TestParamsValidate(t *testing.T) {
+	require.NoError(t, types.DefaultParams().Validate())
+
+	testCases := []struct {
+		name   string
+		modify func(*types.Params)
+	}{
+		{"nil min rewards", func(p *types.Params) { p.MinRewards = sdkmath.Int{} }},
+		{"negative min rewards", func(p *types.Params) { p.MinRewards = sdkmath.NewInt(-1) }},
+		{"zero max delegators per block", func(p *types.Params) { p.MaxDelegatorsPerBlock = 0 }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := types.DefaultParams()
+			tc.modify(&params)
+			require.Error(t, params.Validate())
+		})
+	}
+}
+
+func TestIsPassStart(t *testing.T) {
+	params := types.Params{Interval: 10}
+	require.False(t, params.IsPassStart(0))
+	require.False(t, params.IsPassStart(9))
+	require.True(t, params.IsPassStart(10))
+	require.True(t, params.IsPassStart(20))
+
+	params.Interval = 0
+	require.False(t, params.IsPassStart(10), "a zero interval disables compounding")
+}
@@ -0,0 +1,240 @@
+package keeper
+
+import (
+	"encoding/json"
+
+	errorsmod "cosmossdk.io/errors"
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+// MintAsset mints the local amount of tonAmount of the asset of jetton,
+// deposited on TON, to recipient. It returns the minted coin.
+func (k Keeper) MintAsset(ctx sdk.Context, jetton string, recipient sdk.AccAddress, tonAmount sdkmath.Int) (sdk.Coin, error) {
+	asset, found := k.GetAsset(ctx, jetton)
+	if !found {
+		return sdk.Coin{}, errorsmod.Wrapf(types.ErrUnknownAsset, "%s", jetton)
+	}
+	if !tonAmount.IsPositive() {
+		return sdk.Coin{}, errorsmod.Wrapf(types.ErrInvalidAsset, "non positive amount of %s: %s", asset.Jetton, tonAmount)
+	}
+
+	amount := sdk.NewCoin(asset.Denom, asset.ToLocal(tonAmount))
+	if err := k.bankKeeper.MintCoins(ctx, types.ModuleName, sdk.NewCoins(amount)); err != nil {
+		return sdk.Coin{}, err
+	}
+	if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, recipient, sdk.NewCoins(amount)); err != nil {
+		return sdk.Coin{}, err
+	}
+	k.emitEvent(ctx, &types.EventMintAsset{
+		Jetton:    asset.Jetton,
+		Recipient: recipient.String(),
+		TonAmount: tonAmount.String(),
+		Amount:    amount,
+	})
+	return amount, nil
+}
+
+// BurnAsset burns amount, the denom of a registered asset, from sender as it
+// is released to recipient on TON. It returns the asset and the amount on
+// TON, the amount must not have more decimals than the asset has on TON.
+func (k Keeper) BurnAsset(ctx sdk.Context, sender sdk.AccAddress, recipient string, amount sdk.Coin) (types.Asset, sdkmath.Int, error) {
+	if err := types.ValidateTONAddress(recipient); err != nil {
+		return types.Asset{}, sdkmath.Int{}, err
+	}
+	asset, found := k.GetAssetByDenom(ctx, amount.Denom)
+	if !found {
+		return types.Asset{}, sdkmath.Int{}, errorsmod.Wrapf(types.ErrUnknownAsset, "%s", amount.Denom)
+	}
+	if !amount.IsPositive() {
+		return types.Asset{}, sdkmath.Int{}, errorsmod.Wrapf(types.ErrInvalidAsset, "non positive amount: %s", amount)
+	}
+	tonAmount, err := asset.ToTON(amount.Amount)
+	if err != nil {
+		return types.Asset{}, sdkmath.Int{}, err
+	}
+
+	if err := k.bankKeeper.SendCoinsFromAccountToModule(ctx, sender, types.ModuleName, sdk.NewCoins(amount)); err != nil {
+		return types.Asset{}, sdkmath.Int{}, err
+	}
+	if err := k.bankKeeper.BurnCoins(ctx, types.ModuleName, sdk.NewCoins(amount)); err != nil {
+		return types.Asset{}, sdkmath.Int{}, err
+	}
+	k.emitEvent(ctx, &types.EventBurnAsset{
+		Jetton:    asset.Jetton,
+		Sender:    sender.String(),
+		Amount:    amount,
+		TonAmount: tonAmount.String(),
+		Recipient: recipient,
+	})
+	return asset, tonAmount, nil
+}
+
+// ApplyAssetChanges removes the assets and registers the ones governance
+// submitted, removals first so an asset can be moved to another denom, and
+// clears the lists. A change that can't be applied doesn't affect the others.
+func (k Keeper) ApplyAssetChanges(ctx sdk.Context) {
+	params := k.GetParams(ctx)
+	if len(params.RemovedAssets) == 0 && len(params.ApprovedAssets) == 0 {
+		return
+	}
+
+	for _, jetton := range params.RemovedAssets {
+		cacheCtx, write := ctx.CacheContext()
+		if err := k.RemoveAsset(cacheCtx, jetton); err != nil {
+			k.Logger(ctx).Error("failed to remove bridge asset", "jetton", jetton, "error", err)
+			continue
+		}
+		write()
+	}
+	for _, asset := range params.ApprovedAssets {
+		cacheCtx, write := ctx.CacheContext()
+		if err := k.SetAsset(cacheCtx, asset); err != nil {
+			k.Logger(ctx).Error("failed to register bridge asset", "jetton", asset.Jetton, "denom", asset.Denom, "error", err)
+			continue
+		}
+		write()
+	}
+
+	params.RemovedAssets = []string{}
+	params.ApprovedAssets = []types.Asset{}
+	k.SetParams(ctx, params)
+}
+
+// SetAsset registers an asset or changes the registered asset of its jetton.
+// The denom must have no supply unless the asset already mints it, so the
+// bridge never mints the denom of another module, and an asset can only move
+// to another denom or change its decimals while none of its denom circulates.
+func (k Keeper) SetAsset(ctx sdk.Context, asset types.Asset) error {
+	asset = asset.Normalized()
+	if err := asset.Validate(); err != nil {
+		return err
+	}
+
+	if jetton, found := k.getAssetJetton(ctx, asset.Denom); found && jetton != asset.Jetton {
+		return errorsmod.Wrapf(types.ErrInvalidAsset, "%s is the denom of %s", asset.Denom, jetton)
+	}
+	existing, found := k.GetAsset(ctx, asset.Jetton)
+	if found && existing == asset {
+		return nil
+	}
+	if found {
+		if supply := k.bankKeeper.GetSupply(ctx, existing.Denom); !supply.IsZero() {
+			return errorsmod.Wrapf(types.ErrInvalidAsset, "%s of %s still circulate", supply, existing.Jetton)
+		}
+	}
+	if !found || existing.Denom != asset.Denom {
+		if supply := k.bankKeeper.GetSupply(ctx, asset.Denom); !supply.IsZero() {
+			return errorsmod.Wrapf(types.ErrInvalidAsset, "%s already has a supply of %s", asset.Denom, supply.Amount)
+		}
+	}
+
+	if found {
+		k.deleteAsset(ctx, existing)
+	}
+	k.setAsset(ctx, asset)
+	k.emitEvent(ctx, &types.EventRegisterAsset{
+		Jetton:      asset.Jetton,
+		Denom:       asset.Denom,
+		TonDecimals: asset.TONDecimals,
+		Decimals:    asset.Decimals,
+	})
+	return nil
+}
+
+// RemoveAsset removes the asset of jetton from the registry, once none of
+// its denom circulates.
+func (k Keeper) RemoveAsset(ctx sdk.Context, jetton string) error {
+	asset, found := k.GetAsset(ctx, jetton)
+	if !found {
+		return errorsmod.Wrapf(types.ErrUnknownAsset, "%s", jetton)
+	}
+	if supply := k.bankKeeper.GetSupply(ctx, asset.Denom); !supply.IsZero() {
+		return errorsmod.Wrapf(types.ErrInvalidAsset, "%s of %s still circulate", supply, asset.Jetton)
+	}
+
+	k.deleteAsset(ctx, asset)
+	k.emitEvent(ctx, &types.EventRemoveAsset{Jetton: asset.Jetton, Denom: asset.Denom})
+	return nil
+}
+
+// GetAsset returns the registered asset of jetton
+func (k Keeper) GetAsset(ctx sdk.Context, jetton string) (types.Asset, bool) {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.AssetKey(types.NormalizeJetton(jetton)))
+	if err != nil {
+		panic(err)
+	}
+	if bz == nil {
+		return types.Asset{}, false
+	}
+	var asset types.Asset
+	if err := json.Unmarshal(bz, &asset); err != nil {
+		panic(err)
+	}
+	return asset, true
+}
+
+// GetAssetByDenom returns the registered asset minting denom
+func (k Keeper) GetAssetByDenom(ctx sdk.Context, denom string) (types.Asset, bool) {
+	jetton, found := k.getAssetJetton(ctx, denom)
+	if !found {
+		return types.Asset{}, false
+	}
+	return k.GetAsset(ctx, jetton)
+}
+
+// GetAssets returns the registered assets by jetton
+func (k Keeper) GetAssets(ctx sdk.Context) []types.Asset {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.AssetPrefix, storetypes.PrefixEndBytes(types.AssetPrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	assets := []types.Asset{}
+	for ; iterator.Valid(); iterator.Next() {
+		var asset types.Asset
+		if err := json.Unmarshal(iterator.Value(), &asset); err != nil {
+			panic(err)
+		}
+		assets = append(assets, asset)
+	}
+	return assets
+}
+
+func (k Keeper) getAssetJetton(ctx sdk.Context, denom string) (string, bool) {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.AssetDenomKey(denom))
+	if err != nil {
+		panic(err)
+	}
+	return string(bz), bz != nil
+}
+
+func (k Keeper) setAsset(ctx sdk.Context, asset types.Asset) {
+	bz, err := json.Marshal(asset)
+	if err != nil {
+		panic(err)
+	}
+	store := k.storeService.OpenKVStore(ctx)
+	if err := store.Set(types.AssetKey(asset.Jetton), bz); err != nil {
+		panic(err)
+	}
+	if err := store.Set(types.AssetDenomKey(asset.Denom), []byte(asset.Jetton)); err != nil {
+		panic(err)
+	}
+}
+
+func (k Keeper) deleteAsset(ctx sdk.Context, asset types.Asset) {
+	store := k.storeService.OpenKVStore(ctx)
+	if err := store.Delete(types.AssetKey(asset.Jetton)); err != nil {
+		panic(err)
+	}
+	if err := store.Delete(types.AssetDenomKey(asset.Denom)); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,203 @@
+package keeper_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+const jetton = "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+
+func jusdt() types.Asset {
+	return types.Asset{Jetton: jetton, Denom: "ajusdt", TONDecimals: 6, Decimals: 18}
+}
+
+// changeAssets submits asset changes like governance would and runs the end
+// blocker at the next height
+func (c *testChain) changeAssets(removed []string, approved ...types.Asset) []proto.Message {
+	params := c.app.BridgeKeeper.GetParams(c.ctx)
+	params.RemovedAssets = removed
+	params.ApprovedAssets = approved
+	c.setParams(params)
+	return c.endBlock(c.ctx.BlockHeight() + 1)
+}
+
+func newAccount() sdk.AccAddress {
+	return sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+}
+
+func TestRegisterAsset(t *testing.T) {
+	c := setup(t)
+	k := c.app.BridgeKeeper
+
+	// jettons are stored lowercase
+	upper := jusdt()
+	upper.Jetton = "0:83DFD552E63729B472FCBCC8C45EBCC6691702558B68EC7527E1BA403A0F31A8"
+	events := c.changeAssets(nil, upper)
+	require.Len(t, events, 1)
+	require.Equal(t, &types.EventRegisterAsset{Jetton: jetton, Denom: "ajusdt", TonDecimals: 6, Decimals: 18}, events[0])
+	require.Equal(t, []types.Asset{jusdt()}, k.GetAssets(c.ctx))
+	asset, found := k.GetAssetByDenom(c.ctx, "ajusdt")
+	require.True(t, found)
+	require.Equal(t, jusdt(), asset)
+	_, found = k.GetAsset(c.ctx, upper.Jetton)
+	require.True(t, found)
+
+	// the lists are cleared once applied
+	params := k.GetParams(c.ctx)
+	require.Empty(t, params.ApprovedAssets)
+	require.Empty(t, params.RemovedAssets)
+
+	// the bridge doesn't mint denoms with a supply of their own, nor a denom
+	// twice, the other changes still apply
+	ton := types.Asset{Jetton: types.JettonNative, Denom: "aton", TONDecimals: 9, Decimals: 18}
+	events = c.changeAssets(nil,
+		types.Asset{Jetton: types.JettonNative, Denom: c.denom, TONDecimals: 9, Decimals: 18},
+		ton,
+	)
+	require.Len(t, events, 1)
+	require.Equal(t, "aton", events[0].(*types.EventRegisterAsset).Denom)
+	require.Error(t, k.SetAsset(c.ctx, types.Asset{Jetton: "0:" + strings.Repeat("1", 64), Denom: "aton", Decimals: 9}))
+	require.Len(t, k.GetAssets(c.ctx), 2)
+}
+
+func TestMintAndBurnAsset(t *testing.T) {
+	c := setup(t)
+	k := c.app.BridgeKeeper
+	c.changeAssets(nil, jusdt())
+	recipient := newAccount()
+
+	c.ctx = c.ctx.WithEventManager(sdk.NewEventManager())
+	minted, err := k.MintAsset(c.ctx, jetton, recipient, sdkmath.NewInt(2_500_000))
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewCoin("ajusdt", sdkmath.NewInt(2_500_000_000_000_000_000)), minted)
+	require.Equal(t, minted, c.app.BankKeeper.GetBalance(c.ctx, recipient, "ajusdt"))
+	require.Equal(t, minted, c.app.BankKeeper.GetSupply(c.ctx, "ajusdt"))
+	require.Equal(t, []proto.Message{&types.EventMintAsset{
+		Jetton:    jetton,
+		Recipient: recipient.String(),
+		TonAmount: "2500000",
+		Amount:    minted,
+	}}, c.events())
+
+	_, err = k.MintAsset(c.ctx, types.JettonNative, recipient, sdkmath.NewInt(1))
+	require.ErrorIs(t, err, types.ErrUnknownAsset)
+	_, err = k.MintAsset(c.ctx, jetton, recipient, sdkmath.ZeroInt())
+	require.ErrorIs(t, err, types.ErrInvalidAsset)
+
+	// amounts with more decimals than on TON can't be released
+	_, _, err = k.BurnAsset(c.ctx, recipient, tonRecipient, sdk.NewCoin("ajusdt", sdkmath.NewInt(1)))
+	require.ErrorIs(t, err, types.ErrInexactAmount)
+	_, _, err = k.BurnAsset(c.ctx, recipient, tonRecipient, sdk.NewCoin(c.denom, sdkmath.NewInt(1)))
+	require.ErrorIs(t, err, types.ErrUnknownAsset)
+	_, _, err = k.BurnAsset(c.ctx, recipient, "tac1xyz", sdk.NewCoin("ajusdt", minted.Amount))
+	require.ErrorIs(t, err, types.ErrInvalidWithdrawal, "recipients are TON addresses")
+
+	c.ctx = c.ctx.WithEventManager(sdk.NewEventManager())
+	burnt := sdk.NewCoin("ajusdt", sdkmath.NewInt(1_000_000_000_000_000_000))
+	asset, tonAmount, err := k.BurnAsset(c.ctx, recipient, tonRecipient, burnt)
+	require.NoError(t, err)
+	require.Equal(t, jusdt(), asset)
+	require.Equal(t, sdkmath.NewInt(1_000_000), tonAmount)
+	require.Equal(t, minted.Sub(burnt), c.app.BankKeeper.GetBalance(c.ctx, recipient, "ajusdt"))
+	require.Equal(t, minted.Sub(burnt), c.app.BankKeeper.GetSupply(c.ctx, "ajusdt"))
+	require.Equal(t, []proto.Message{&types.EventBurnAsset{
+		Jetton:    jetton,
+		Sender:    recipient.String(),
+		Amount:    burnt,
+		TonAmount: "1000000",
+		Recipient: tonRecipient,
+	}}, c.events())
+}
+
+func TestWithdrawAssetCall(t *testing.T) {
+	c := setup(t)
+	k := c.app.BridgeKeeper
+	c.changeAssets(nil, jusdt())
+	sender := newSender(t)
+	minted, err := k.MintAsset(c.ctx, jetton, sdk.AccAddress(sender.Bytes()), sdkmath.NewInt(3_000_000))
+	require.NoError(t, err)
+
+	// the call burns the asset from the balance of the sender
+	c.ctx = c.ctx.WithEventManager(sdk.NewEventManager())
+	burnt := sdk.NewCoin("ajusdt", sdkmath.NewInt(2_000_000_000_000_000_000))
+	require.NoError(t, c.call(sender, 0, types.EscrowCall{Method: types.MethodWithdrawAsset, Recipient: tonRecipient, Amount: burnt}))
+	require.Equal(t, minted.Sub(burnt), c.app.BankKeeper.GetBalance(c.ctx, sdk.AccAddress(sender.Bytes()), "ajusdt"))
+	require.Equal(t, minted.Sub(burnt), c.app.BankKeeper.GetSupply(c.ctx, "ajusdt"))
+	require.Equal(t, []proto.Message{&types.EventBurnAsset{
+		Jetton:    jetton,
+		Sender:    sdk.AccAddress(sender.Bytes()).String(),
+		Amount:    burnt,
+		TonAmount: "2000000",
+		Recipient: tonRecipient,
+	}}, c.events())
+
+	// asset withdrawals don't transfer value nor burn more than the balance
+	err = c.call(sender, 1, types.EscrowCall{Method: types.MethodWithdrawAsset, Recipient: tonRecipient, Amount: minted.Sub(burnt)})
+	require.ErrorIs(t, err, types.ErrInvalidBridgeCall)
+	err = c.call(sender, 0, types.EscrowCall{Method: types.MethodWithdrawAsset, Recipient: tonRecipient, Amount: burnt})
+	require.Error(t, err)
+	require.Equal(t, minted.Sub(burnt), c.app.BankKeeper.GetBalance(c.ctx, sdk.AccAddress(sender.Bytes()), "ajusdt"))
+}
+
+func TestAssetChangesWaitForSupply(t *testing.T) {
+	c := setup(t)
+	k := c.app.BridgeKeeper
+	c.changeAssets(nil, jusdt())
+	recipient := newAccount()
+	minted, err := k.MintAsset(c.ctx, jetton, recipient, sdkmath.NewInt(1))
+	require.NoError(t, err)
+
+	// neither removed nor changed while the denom circulates
+	changed := jusdt()
+	changed.Decimals = 9
+	require.Empty(t, c.changeAssets([]string{jetton}))
+	require.Empty(t, c.changeAssets(nil, changed))
+	require.Equal(t, []types.Asset{jusdt()}, k.GetAssets(c.ctx))
+
+	// registering the same asset again is a no-op
+	require.Empty(t, c.changeAssets(nil, jusdt()))
+
+	_, _, err = k.BurnAsset(c.ctx, recipient, tonRecipient, minted)
+	require.NoError(t, err)
+
+	// removals apply first, so the jetton can move to another denom at once
+	moved := jusdt()
+	moved.Denom = "ujusdt"
+	moved.Decimals = 6
+	events := c.changeAssets([]string{jetton}, moved)
+	require.Equal(t, []proto.Message{
+		&types.EventRemoveAsset{Jetton: jetton, Denom: "ajusdt"},
+		&types.EventRegisterAsset{Jetton: jetton, Denom: "ujusdt", TonDecimals: 6, Decimals: 6},
+	}, events)
+	_, found := k.GetAssetByDenom(c.ctx, "ajusdt")
+	require.False(t, found)
+	require.Equal(t, []types.Asset{moved}, k.GetAssets(c.ctx))
+
+	require.ErrorIs(t, k.RemoveAsset(c.ctx, types.JettonNative), types.ErrUnknownAsset)
+}
+
+func TestAssetGenesis(t *testing.T) {
+	c := setup(t)
+	c.changeAssets(nil, jusdt(), types.Asset{Jetton: types.JettonNative, Denom: "aton", TONDecimals: 9, Decimals: 18})
+
+	exported := c.app.BridgeKeeper.ExportGenesis(c.ctx)
+	require.NoError(t, exported.Validate())
+	require.Len(t, exported.Assets, 2)
+
+	other := setup(t)
+	other.app.BridgeKeeper.InitGenesis(other.ctx, *exported)
+	require.Equal(t, exported, other.app.BridgeKeeper.ExportGenesis(other.ctx))
+	asset, found := other.app.BridgeKeeper.GetAssetByDenom(other.ctx, "aton")
+	require.True(t, found)
+	require.Equal(t, types.JettonNative, asset.Jetton)
+}
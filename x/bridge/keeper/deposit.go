@@ -0,0 +1,91 @@
+package keeper
+
+import (
+	errorsmod "cosmossdk.io/errors"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+// ExecuteDeposit executes a deposit from TON once the signatures prove that
+// relayers holding a quorum of the relayer power attested it, see
+// VerifyAttestation. The EVM denom coming back from TON is minted to the
+// recipient, withdrawals burnt it, and jettons mint the denom of their asset
+// with MintAsset. Each sequence is executed once, a deposit without a quorum
+// can be submitted again with more signatures.
+func (k Keeper) ExecuteDeposit(ctx sdk.Context, evmDenom string, deposit types.Deposit, signatures [][]byte) (sdk.Coin, error) {
+	if err := deposit.Validate(); err != nil {
+		return sdk.Coin{}, err
+	}
+	if k.IsDeposited(ctx, deposit.Sequence) {
+		return sdk.Coin{}, errorsmod.Wrapf(types.ErrDuplicateDeposit, "sequence %d", deposit.Sequence)
+	}
+	jetton := types.NormalizeJetton(deposit.Jetton)
+	if jetton != "" {
+		if _, found := k.GetAsset(ctx, jetton); !found {
+			return sdk.Coin{}, errorsmod.Wrapf(types.ErrUnknownAsset, "%s", deposit.Jetton)
+		}
+	}
+	attestation, err := k.VerifyAttestation(ctx, deposit.Sequence, deposit.PayloadHash(), signatures)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	k.setDeposited(ctx, deposit.Sequence)
+	recipient := sdk.AccAddress(deposit.Recipient.Bytes())
+	amount := sdk.NewCoin(evmDenom, deposit.Amount)
+	if jetton != "" {
+		if amount, err = k.MintAsset(ctx, jetton, recipient, deposit.Amount); err != nil {
+			return sdk.Coin{}, err
+		}
+	} else {
+		if err := k.bankKeeper.MintCoins(ctx, types.ModuleName, sdk.NewCoins(amount)); err != nil {
+			return sdk.Coin{}, err
+		}
+		if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, recipient, sdk.NewCoins(amount)); err != nil {
+			return sdk.Coin{}, err
+		}
+	}
+
+	k.emitEvent(ctx, &types.EventDeposit{
+		Sequence:  deposit.Sequence,
+		Jetton:    jetton,
+		Recipient: deposit.Recipient.Hex(),
+		Amount:    amount,
+		Signers:   attestation.Signers,
+	})
+	return amount, nil
+}
+
+// IsDeposited returns true if the deposit with sequence was executed
+func (k Keeper) IsDeposited(ctx sdk.Context, sequence uint64) bool {
+	found, err := k.storeService.OpenKVStore(ctx).Has(types.DepositKey(sequence))
+	if err != nil {
+		panic(err)
+	}
+	return found
+}
+
+func (k Keeper) setDeposited(ctx sdk.Context, sequence uint64) {
+	if err := k.storeService.OpenKVStore(ctx).Set(types.DepositKey(sequence), []byte{}); err != nil {
+		panic(err)
+	}
+}
+
+// GetDeposits returns the sequences of the executed deposits
+func (k Keeper) GetDeposits(ctx sdk.Context) []uint64 {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.DepositPrefix, storetypes.PrefixEndBytes(types.DepositPrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	sequences := []uint64{}
+	for ; iterator.Valid(); iterator.Next() {
+		sequences = append(sequences, sdk.BigEndianToUint64(iterator.Key()[len(types.DepositPrefix):]))
+	}
+	return sequences
+}
@@ -0,0 +1,107 @@
+package keeper_test
+
+import (
+	"testing"
+
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+// deposit submits a deposit call signed by the relayers
+func (c *testChain) deposit(deposit types.Deposit, relayers ...int) error {
+	c.ctx = c.ctx.WithEventManager(sdk.NewEventManager())
+	signatures := c.sign(deposit.Sequence, deposit.PayloadHash(), relayers...)
+	return c.call(newSender(c.t), 0, types.EscrowCall{Method: types.MethodDeposit, Deposit: deposit, Signatures: signatures})
+}
+
+func (c *testChain) balance(addr sdk.AccAddress) int64 {
+	return c.app.BankKeeper.GetBalance(c.ctx, addr, c.denom).Amount.Int64()
+}
+
+func TestAttestedDeposit(t *testing.T) {
+	// relayers bonding 40, 30, 20 and 10 with the default threshold of 0.667
+	c := setup(t, 40, 30, 20, 10)
+	k := c.app.BridgeKeeper
+	recipient := newSender(t)
+	deposit := types.Deposit{Sequence: 1, Recipient: recipient, Amount: sdkmath.NewInt(500)}
+	supply := c.supply()
+
+	// relayers holding 60 of the power of 100 don't attest the deposit
+	require.ErrorIs(t, c.deposit(deposit, 0, 2), types.ErrNoQuorum)
+	require.False(t, k.IsDeposited(c.ctx, 1))
+	require.Zero(t, c.balance(sdk.AccAddress(recipient.Bytes())))
+	require.Equal(t, supply, c.supply())
+
+	// the signatures of another payload don't attest it
+	tampered := deposit
+	tampered.Amount = sdkmath.NewInt(5000)
+	signatures := c.sign(deposit.Sequence, deposit.PayloadHash(), 0, 1)
+	err := c.call(newSender(t), 0, types.EscrowCall{Method: types.MethodDeposit, Deposit: tampered, Signatures: signatures})
+	require.ErrorIs(t, err, types.ErrUnknownRelayer)
+
+	// a quorum mints the amount to the recipient
+	require.NoError(t, c.deposit(deposit, 0, 1))
+	require.Equal(t, int64(500), c.balance(sdk.AccAddress(recipient.Bytes())))
+	require.Equal(t, supply+500, c.supply())
+	events := c.events()
+	require.Len(t, events, 1)
+	require.Equal(t, &types.EventDeposit{
+		Sequence:  1,
+		Recipient: recipient.Hex(),
+		Amount:    sdk.NewCoin(c.denom, sdkmath.NewInt(500)),
+		Signers:   []string{c.address(0).Hex(), c.address(1).Hex()},
+	}, events[0])
+
+	// a sequence is executed once
+	require.ErrorIs(t, c.deposit(deposit, 0, 1, 2), types.ErrDuplicateDeposit)
+	require.Equal(t, int64(500), c.balance(sdk.AccAddress(recipient.Bytes())))
+
+	// deposits don't transfer value
+	next := types.Deposit{Sequence: 2, Recipient: recipient, Amount: sdkmath.NewInt(1)}
+	err = c.call(newSender(t), 1, types.EscrowCall{
+		Method: types.MethodDeposit, Deposit: next, Signatures: c.sign(next.Sequence, next.PayloadHash(), 0, 1),
+	})
+	require.ErrorIs(t, err, types.ErrInvalidBridgeCall)
+
+	exported := k.ExportGenesis(c.ctx)
+	require.NoError(t, exported.Validate())
+	require.Equal(t, []uint64{1}, exported.Deposits)
+}
+
+func TestAttestedJettonDeposit(t *testing.T) {
+	c := setup(t, 40, 30, 20, 10)
+	c.changeAssets(nil, jusdt())
+	recipient := newSender(t)
+
+	// jettons out of the registry aren't deposited
+	unknown := types.Deposit{Sequence: 1, Jetton: types.JettonNative, Recipient: recipient, Amount: sdkmath.NewInt(1)}
+	require.ErrorIs(t, c.deposit(unknown, 0, 1), types.ErrUnknownAsset)
+	require.False(t, c.app.BridgeKeeper.IsDeposited(c.ctx, 1))
+
+	// the amount on TON mints the denom of the asset
+	deposit := types.Deposit{Sequence: 1, Jetton: jetton, Recipient: recipient, Amount: sdkmath.NewInt(2_500_000)}
+	require.NoError(t, c.deposit(deposit, 0, 1))
+	minted := sdk.NewCoin("ajusdt", sdkmath.NewInt(2_500_000_000_000_000_000))
+	require.Equal(t, minted, c.app.BankKeeper.GetBalance(c.ctx, sdk.AccAddress(recipient.Bytes()), "ajusdt"))
+	require.Equal(t, []proto.Message{
+		&types.EventMintAsset{
+			Jetton:    jetton,
+			Recipient: sdk.AccAddress(recipient.Bytes()).String(),
+			TonAmount: "2500000",
+			Amount:    minted,
+		},
+		&types.EventDeposit{
+			Sequence:  1,
+			Jetton:    jetton,
+			Recipient: recipient.Hex(),
+			Amount:    minted,
+			Signers:   []string{c.address(0).Hex(), c.address(1).Hex()},
+		},
+	}, c.events())
+}
@@ -0,0 +1,58 @@
+package keeper
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+// InitGenesis initializes the bridge module state from a genesis state
+func (k Keeper) InitGenesis(ctx sdk.Context, gs types.GenesisState) {
+	k.SetParams(ctx, gs.Params)
+
+	for _, relayer := range gs.Relayers {
+		k.setAppliedRelayer(ctx, common.HexToAddress(relayer))
+	}
+	for _, bond := range gs.Bonds {
+		k.SetBond(ctx, common.HexToAddress(bond.Relayer), bond.Amount)
+	}
+	for _, unbonding := range gs.Unbondings {
+		k.SetUnbondingHeight(ctx, common.HexToAddress(unbonding.Relayer), unbonding.Height)
+	}
+	for _, offense := range gs.Offenses {
+		k.SetSlashed(ctx, common.HexToAddress(offense.Relayer), offense.Sequence)
+	}
+	for _, withdrawal := range gs.Withdrawals {
+		k.SetWithdrawal(ctx, withdrawal)
+	}
+	if gs.NextWithdrawalID > 0 {
+		k.setNextWithdrawalID(ctx, gs.NextWithdrawalID)
+	}
+	for _, asset := range gs.Assets {
+		k.setAsset(ctx, asset)
+	}
+	for _, sequence := range gs.Deposits {
+		k.setDeposited(ctx, sequence)
+	}
+}
+
+// ExportGenesis returns the bridge module genesis state
+func (k Keeper) ExportGenesis(ctx sdk.Context) *types.GenesisState {
+	relayers := []string{}
+	for _, relayer := range k.GetAppliedRelayers(ctx) {
+		relayers = append(relayers, relayer.Hex())
+	}
+	return &types.GenesisState{
+		Params:           k.GetParams(ctx),
+		Relayers:         relayers,
+		Bonds:            k.GetBonds(ctx),
+		Unbondings:       k.GetUnbondings(ctx),
+		Offenses:         k.GetOffenses(ctx),
+		Withdrawals:      k.GetWithdrawals(ctx),
+		NextWithdrawalID: k.GetNextWithdrawalID(ctx),
+		Assets:           k.GetAssets(ctx),
+		Deposits:         k.GetDeposits(ctx),
+	}
+}
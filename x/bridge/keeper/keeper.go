@@ -0,0 +1,490 @@
+package keeper
+
+import (
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/common"
+
+	corestoretypes "cosmossdk.io/core/store"
+	errorsmod "cosmossdk.io/errors"
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+// Keeper of the bridge store
+type Keeper struct {
+	storeService  corestoretypes.KVStoreService
+	paramSpace    paramtypes.Subspace
+	bankKeeper    types.BankKeeper
+	stakingKeeper types.StakingKeeper
+	distrKeeper   types.DistrKeeper
+}
+
+// NewKeeper creates a new bridge Keeper instance
+func NewKeeper(
+	storeService corestoretypes.KVStoreService,
+	paramSpace paramtypes.Subspace,
+	bankKeeper types.BankKeeper,
+	stakingKeeper types.StakingKeeper,
+	distrKeeper types.DistrKeeper,
+) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		storeService:  storeService,
+		paramSpace:    paramSpace,
+		bankKeeper:    bankKeeper,
+		stakingKeeper: stakingKeeper,
+		distrKeeper:   distrKeeper,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current bridge module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the bridge module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// Bond adds amount, which the bond call of relayer transferred to the escrow
+// address, to the bond of relayer and moves it to the module account. Only
+// relayers of the set bond, in the bond denom.
+func (k Keeper) Bond(ctx sdk.Context, relayer common.Address, amount sdk.Coin) error {
+	denom, err := k.stakingKeeper.BondDenom(ctx)
+	if err != nil {
+		return err
+	}
+	if amount.Denom != denom || !amount.IsPositive() {
+		return errorsmod.Wrapf(types.ErrInvalidBond, "bond must be a positive amount of %s, got %s", denom, amount)
+	}
+	if !k.GetParams(ctx).IsRelayer(relayer) {
+		return errorsmod.Wrapf(types.ErrUnknownRelayer, "%s isn't in the relayer set", relayer.Hex())
+	}
+
+	escrow := sdk.AccAddress(types.EscrowAddress().Bytes())
+	if err := k.bankKeeper.SendCoinsFromAccountToModule(ctx, escrow, types.ModuleName, sdk.NewCoins(amount)); err != nil {
+		return err
+	}
+	bond := k.GetBond(ctx, relayer).Add(amount.Amount)
+	k.SetBond(ctx, relayer, bond)
+	k.emitEvent(ctx, &types.EventBondRelayer{Relayer: relayer.Hex(), Amount: amount, Bond: sdk.NewCoin(denom, bond)})
+	return nil
+}
+
+// RelayerBond returns the bond of relayer in the bond denom
+func (k Keeper) RelayerBond(ctx sdk.Context, relayer common.Address) (sdk.Coin, error) {
+	denom, err := k.stakingKeeper.BondDenom(ctx)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+	return sdk.NewCoin(denom, k.GetBond(ctx, relayer)), nil
+}
+
+// GetBond returns the amount of the bond of relayer, zero if it has none
+func (k Keeper) GetBond(ctx sdk.Context, relayer common.Address) sdkmath.Int {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.BondKey(relayer))
+	if err != nil {
+		panic(err)
+	}
+	if bz == nil {
+		return sdkmath.ZeroInt()
+	}
+	var amount sdkmath.Int
+	if err := amount.Unmarshal(bz); err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// SetBond sets the amount of the bond of relayer, deleting it when zero. The
+// module account must hold the bonds.
+func (k Keeper) SetBond(ctx sdk.Context, relayer common.Address, amount sdkmath.Int) {
+	store := k.storeService.OpenKVStore(ctx)
+	if !amount.IsPositive() {
+		if err := store.Delete(types.BondKey(relayer)); err != nil {
+			panic(err)
+		}
+		return
+	}
+	bz, err := amount.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	if err := store.Set(types.BondKey(relayer), bz); err != nil {
+		panic(err)
+	}
+}
+
+// GetBonds returns the bonds of the relayers
+func (k Keeper) GetBonds(ctx sdk.Context) []types.Bond {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.BondPrefix, storetypes.PrefixEndBytes(types.BondPrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	bonds := []types.Bond{}
+	for ; iterator.Valid(); iterator.Next() {
+		var amount sdkmath.Int
+		if err := amount.Unmarshal(iterator.Value()); err != nil {
+			panic(err)
+		}
+		bonds = append(bonds, types.Bond{
+			Relayer: common.BytesToAddress(iterator.Key()[len(types.BondPrefix):]).Hex(),
+			Amount:  amount,
+		})
+	}
+	return bonds
+}
+
+// RelayerPowers returns the power of every relayer of the set and their total
+func (k Keeper) RelayerPowers(ctx sdk.Context) ([]types.RelayerPower, sdkmath.Int, error) {
+	params := k.GetParams(ctx)
+	powers := make([]types.RelayerPower, 0, len(params.Relayers))
+	total := sdkmath.ZeroInt()
+	for _, relayer := range params.Relayers {
+		bond, err := k.RelayerBond(ctx, common.HexToAddress(relayer))
+		if err != nil {
+			return nil, sdkmath.Int{}, err
+		}
+		power := types.NewRelayerPower(common.HexToAddress(relayer).Hex(), bond.Amount, params.MinRelayerBond)
+		powers = append(powers, power)
+		total = total.Add(power.Power())
+	}
+	return powers, total, nil
+}
+
+// VerifyAttestation checks that the relayers signing the attestation digest
+// of payloadHash for sequence hold more than the attestation threshold of the
+// power of all active relayers. Every signature must come from a distinct
+// active relayer. It returns the signers and their power with ErrNoQuorum when
+// they are too few. Deposits from TON are only executed once verified.
+func (k Keeper) VerifyAttestation(ctx sdk.Context, sequence uint64, payloadHash common.Hash, signatures [][]byte) (types.AttestationResult, error) {
+	powers, total, err := k.RelayerPowers(ctx)
+	if err != nil {
+		return types.AttestationResult{}, err
+	}
+	active := make(map[common.Address]sdkmath.Int, len(powers))
+	for _, power := range powers {
+		if power.Active {
+			active[common.HexToAddress(power.Relayer)] = power.Power()
+		}
+	}
+
+	digest := types.AttestationDigest(ctx.ChainID(), sequence, payloadHash)
+	result := types.AttestationResult{Signers: []string{}, Power: sdkmath.ZeroInt(), TotalPower: total}
+	signed := make(map[common.Address]bool, len(signatures))
+	for _, signature := range signatures {
+		signer, err := types.RecoverSigner(digest, signature)
+		if err != nil {
+			return result, err
+		}
+		power, ok := active[signer]
+		if !ok {
+			return result, errorsmod.Wrapf(types.ErrUnknownRelayer, "%s", signer.Hex())
+		}
+		if signed[signer] {
+			return result, errorsmod.Wrapf(types.ErrDuplicateSignature, "%s", signer.Hex())
+		}
+		signed[signer] = true
+		result.Signers = append(result.Signers, signer.Hex())
+		result.Power = result.Power.Add(power)
+	}
+
+	if !k.GetParams(ctx).HasQuorum(result.Power, total) {
+		return result, errorsmod.Wrapf(types.ErrNoQuorum, "signers hold %s of %s", result.Power, total)
+	}
+	return result, nil
+}
+
+// ExecuteMisbehavior slashes the relayers of the misbehavior submitted by
+// governance, removes them from the set and clears the list. A misbehavior
+// that can't be punished doesn't affect the others.
+func (k Keeper) ExecuteMisbehavior(ctx sdk.Context) {
+	params := k.GetParams(ctx)
+	if len(params.Misbehavior) == 0 {
+		return
+	}
+
+	for _, misbehavior := range params.Misbehavior {
+		cacheCtx, write := ctx.CacheContext()
+		slashed, err := k.slash(cacheCtx, params, misbehavior)
+		if err != nil {
+			k.Logger(ctx).Error("failed to slash relayer", "relayer", misbehavior.Relayer, "sequence", misbehavior.Sequence, "error", err)
+			continue
+		}
+
+		write()
+		relayer := misbehavior.RelayerAddress()
+		params.Relayers = removeRelayer(params.Relayers, relayer)
+		k.emitEvent(ctx, &types.EventSlashRelayer{
+			Relayer:  relayer.Hex(),
+			Sequence: misbehavior.Sequence,
+			Slashed:  slashed,
+		})
+	}
+
+	params.Misbehavior = []types.Misbehavior{}
+	k.SetParams(ctx, params)
+}
+
+// slash moves the slash fraction of the bond of the misbehaving relayer from
+// the module account to the community pool. Relayers of the set and unbonding
+// ones can be slashed.
+func (k Keeper) slash(ctx sdk.Context, params types.Params, misbehavior types.Misbehavior) (sdk.Coins, error) {
+	if misbehavior.ChainID != ctx.ChainID() {
+		return nil, errorsmod.Wrapf(types.ErrWrongChainID, "expected %s, got %s", ctx.ChainID(), misbehavior.ChainID)
+	}
+	if err := misbehavior.Verify(); err != nil {
+		return nil, err
+	}
+
+	relayer := misbehavior.RelayerAddress()
+	if _, unbonding := k.GetUnbondingHeight(ctx, relayer); !unbonding && !params.IsRelayer(relayer) {
+		return nil, errorsmod.Wrapf(types.ErrUnknownRelayer, "%s isn't a relayer nor unbonding", relayer.Hex())
+	}
+	if k.IsSlashed(ctx, relayer, misbehavior.Sequence) {
+		return nil, types.ErrAlreadySlashed
+	}
+	k.SetSlashed(ctx, relayer, misbehavior.Sequence)
+
+	bond, err := k.RelayerBond(ctx, relayer)
+	if err != nil {
+		return nil, err
+	}
+	slashed := sdk.NewCoins(sdk.NewCoin(bond.Denom, params.SlashFraction.MulInt(bond.Amount).TruncateInt()))
+	if slashed.IsZero() {
+		return slashed, nil
+	}
+	k.SetBond(ctx, relayer, bond.Amount.Sub(slashed.AmountOf(bond.Denom)))
+	return slashed, k.distrKeeper.FundCommunityPool(ctx, slashed, authtypes.NewModuleAddress(types.ModuleName))
+}
+
+// UpdateRelayerSet applies the relayer set of the params: the bonds of the
+// relayers that left it start unbonding, the ones that joined again stop
+// unbonding, and the bonds whose unbonding is over are sent back from the
+// module account to their relayer, at most MaxReleasedBondsPerBlock of them.
+func (k Keeper) UpdateRelayerSet(ctx sdk.Context) {
+	params := k.GetParams(ctx)
+	current := make(map[common.Address]bool, len(params.Relayers))
+	for _, relayer := range params.Relayers {
+		current[common.HexToAddress(relayer)] = true
+	}
+	applied := k.GetAppliedRelayers(ctx)
+
+	for _, relayer := range applied {
+		if current[relayer] {
+			delete(current, relayer)
+			continue
+		}
+		k.deleteAppliedRelayer(ctx, relayer)
+		height := ctx.BlockHeight() + int64(params.UnbondingBlocks)
+		k.SetUnbondingHeight(ctx, relayer, height)
+		k.emitEvent(ctx, &types.EventUnbondRelayer{Relayer: relayer.Hex(), ReleaseHeight: height})
+	}
+	// the relayers left in current joined the set
+	for _, relayer := range params.Relayers {
+		addr := common.HexToAddress(relayer)
+		if !current[addr] {
+			continue
+		}
+		k.setAppliedRelayer(ctx, addr)
+		k.deleteUnbonding(ctx, addr)
+	}
+
+	k.releaseBonds(ctx)
+}
+
+func (k Keeper) releaseBonds(ctx sdk.Context) {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.UnbondingQueuePrefix, types.UnbondingQueuePrefixAt(ctx.BlockHeight()+1))
+	if err != nil {
+		panic(err)
+	}
+	var due []common.Address
+	prefixLen := len(types.UnbondingQueuePrefixAt(0))
+	for ; iterator.Valid() && len(due) < types.MaxReleasedBondsPerBlock; iterator.Next() {
+		due = append(due, common.BytesToAddress(iterator.Key()[prefixLen:]))
+	}
+	iterator.Close()
+
+	for _, relayer := range due {
+		k.deleteUnbonding(ctx, relayer)
+
+		bond, err := k.RelayerBond(ctx, relayer)
+		if err != nil {
+			k.Logger(ctx).Error("failed to release relayer bond", "relayer", relayer.Hex(), "error", err)
+			continue
+		}
+		if bond.IsZero() {
+			continue
+		}
+		cacheCtx, write := ctx.CacheContext()
+		k.SetBond(cacheCtx, relayer, sdkmath.ZeroInt())
+		amount := sdk.NewCoins(bond)
+		if err := k.bankKeeper.SendCoinsFromModuleToAccount(cacheCtx, types.ModuleName, sdk.AccAddress(relayer.Bytes()), amount); err != nil {
+			k.Logger(ctx).Error("failed to release relayer bond", "relayer", relayer.Hex(), "error", err)
+			continue
+		}
+		write()
+		k.emitEvent(ctx, &types.EventReleaseRelayerBond{Relayer: relayer.Hex(), Amount: amount})
+	}
+}
+
+// GetAppliedRelayers returns the relayer set applied at the end of the last block
+func (k Keeper) GetAppliedRelayers(ctx sdk.Context) []common.Address {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.RelayerPrefix, storetypes.PrefixEndBytes(types.RelayerPrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	relayers := []common.Address{}
+	for ; iterator.Valid(); iterator.Next() {
+		relayers = append(relayers, common.BytesToAddress(iterator.Key()[len(types.RelayerPrefix):]))
+	}
+	return relayers
+}
+
+func (k Keeper) setAppliedRelayer(ctx sdk.Context, relayer common.Address) {
+	if err := k.storeService.OpenKVStore(ctx).Set(types.RelayerKey(relayer), []byte{}); err != nil {
+		panic(err)
+	}
+}
+
+func (k Keeper) deleteAppliedRelayer(ctx sdk.Context, relayer common.Address) {
+	if err := k.storeService.OpenKVStore(ctx).Delete(types.RelayerKey(relayer)); err != nil {
+		panic(err)
+	}
+}
+
+// GetUnbondingHeight returns the height the bond of relayer is released at,
+// if the relayer is unbonding
+func (k Keeper) GetUnbondingHeight(ctx sdk.Context, relayer common.Address) (int64, bool) {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.UnbondingKey(relayer))
+	if err != nil {
+		panic(err)
+	}
+	if bz == nil {
+		return 0, false
+	}
+	return int64(sdk.BigEndianToUint64(bz)), true
+}
+
+// SetUnbondingHeight schedules the release of the bond of relayer at height
+func (k Keeper) SetUnbondingHeight(ctx sdk.Context, relayer common.Address, height int64) {
+	k.deleteUnbonding(ctx, relayer)
+	store := k.storeService.OpenKVStore(ctx)
+	if err := store.Set(types.UnbondingKey(relayer), sdk.Uint64ToBigEndian(uint64(height))); err != nil {
+		panic(err)
+	}
+	if err := store.Set(types.UnbondingQueueKey(height, relayer), []byte{}); err != nil {
+		panic(err)
+	}
+}
+
+func (k Keeper) deleteUnbonding(ctx sdk.Context, relayer common.Address) {
+	height, found := k.GetUnbondingHeight(ctx, relayer)
+	if !found {
+		return
+	}
+	store := k.storeService.OpenKVStore(ctx)
+	if err := store.Delete(types.UnbondingKey(relayer)); err != nil {
+		panic(err)
+	}
+	if err := store.Delete(types.UnbondingQueueKey(height, relayer)); err != nil {
+		panic(err)
+	}
+}
+
+// GetUnbondings returns the bonds of the removed relayers waiting for their release
+func (k Keeper) GetUnbondings(ctx sdk.Context) []types.Unbonding {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.UnbondingPrefix, storetypes.PrefixEndBytes(types.UnbondingPrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	unbondings := []types.Unbonding{}
+	for ; iterator.Valid(); iterator.Next() {
+		unbondings = append(unbondings, types.Unbonding{
+			Relayer: common.BytesToAddress(iterator.Key()[len(types.UnbondingPrefix):]).Hex(),
+			Height:  int64(sdk.BigEndianToUint64(iterator.Value())),
+		})
+	}
+	return unbondings
+}
+
+// IsSlashed returns true if relayer was slashed for sequence
+func (k Keeper) IsSlashed(ctx sdk.Context, relayer common.Address, sequence uint64) bool {
+	found, err := k.storeService.OpenKVStore(ctx).Has(types.SlashedKey(relayer, sequence))
+	if err != nil {
+		panic(err)
+	}
+	return found
+}
+
+// SetSlashed marks relayer as slashed for sequence
+func (k Keeper) SetSlashed(ctx sdk.Context, relayer common.Address, sequence uint64) {
+	if err := k.storeService.OpenKVStore(ctx).Set(types.SlashedKey(relayer, sequence), []byte{}); err != nil {
+		panic(err)
+	}
+}
+
+// GetOffenses returns the sequences relayers were slashed for
+func (k Keeper) GetOffenses(ctx sdk.Context) []types.Offense {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.SlashedPrefix, storetypes.PrefixEndBytes(types.SlashedPrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	offenses := []types.Offense{}
+	for ; iterator.Valid(); iterator.Next() {
+		key := iterator.Key()[len(types.SlashedPrefix):]
+		offenses = append(offenses, types.Offense{
+			Relayer:  common.BytesToAddress(key[:common.AddressLength]).Hex(),
+			Sequence: sdk.BigEndianToUint64(key[common.AddressLength:]),
+		})
+	}
+	return offenses
+}
+
+// emitEvent emits a typed event. The module runs in the end blocker where
+// there is no tx to fail, so an event that can't be encoded is only logged.
+func (k Keeper) emitEvent(ctx sdk.Context, event proto.Message) {
+	if err := ctx.EventManager().EmitTypedEvent(event); err != nil {
+		k.Logger(ctx).Error("failed to emit bridge event", "event", proto.MessageName(event), "error", err)
+	}
+}
+
+func removeRelayer(relayers []string, relayer common.Address) []string {
+	kept := make([]string, 0, len(relayers))
+	for _, r := range relayers {
+		if common.HexToAddress(r) != relayer {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
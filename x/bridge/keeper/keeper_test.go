@@ -0,0 +1,467 @@
+package keeper_test
+
+import (
+	"crypto/ecdsa"
+	"strings"
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+type testChain struct {
+	t        *testing.T
+	app      *app.TacChainApp
+	ctx      sdk.Context
+	denom    string
+	relayers []*ecdsa.PrivateKey
+}
+
+// setup returns a chain whose relayer set is made of relayers bonding bonds
+func setup(t *testing.T, bonds ...int64) *testChain {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := tacApp.NewContext(false).WithChainID(app.DefaultChainID).WithBlockHeight(1)
+	denom, err := tacApp.StakingKeeper.BondDenom(ctx)
+	require.NoError(t, err)
+
+	c := &testChain{t: t, app: tacApp, ctx: ctx, denom: denom}
+	params := types.DefaultParams()
+	for range bonds {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		c.relayers = append(c.relayers, key)
+		params.Relayers = append(params.Relayers, c.address(len(c.relayers)-1).Hex())
+	}
+	c.setParams(params)
+	for i, bond := range bonds {
+		c.bond(i, bond)
+	}
+	tacApp.BridgeKeeper.UpdateRelayerSet(c.ctx)
+	return c
+}
+
+func (c *testChain) address(i int) common.Address {
+	return crypto.PubkeyToAddress(c.relayers[i].PublicKey)
+}
+
+func (c *testChain) setParams(params types.Params) {
+	require.NoError(c.t, params.Validate())
+	c.app.BridgeKeeper.SetParams(c.ctx, params)
+}
+
+// bond adds amount to the bond of relayer i with a bond call
+func (c *testChain) bond(i int, amount int64) {
+	if amount == 0 {
+		return
+	}
+	require.NoError(c.t, c.call(c.address(i), amount, types.EscrowCall{Method: types.MethodBond}))
+}
+
+func (c *testChain) bondOf(i int) int64 {
+	bond, err := c.app.BridgeKeeper.RelayerBond(c.ctx, c.address(i))
+	require.NoError(c.t, err)
+	return bond.Amount.Int64()
+}
+
+// sign returns the signatures of the attestation of payloadHash at sequence
+// by the relayers
+func (c *testChain) sign(sequence uint64, payloadHash common.Hash, relayers ...int) [][]byte {
+	var sigs [][]byte
+	for _, i := range relayers {
+		sigs = append(sigs, c.signOn(c.ctx.ChainID(), i, sequence, payloadHash))
+	}
+	return sigs
+}
+
+// signOn returns the signature of relayer i of the attestation of payloadHash
+// at sequence on chainID
+func (c *testChain) signOn(chainID string, i int, sequence uint64, payloadHash common.Hash) []byte {
+	sig, err := crypto.Sign(types.AttestationDigest(chainID, sequence, payloadHash).Bytes(), c.relayers[i])
+	require.NoError(c.t, err)
+	return sig
+}
+
+// misbehavior returns the evidence of relayer i attesting two payloads at sequence
+func (c *testChain) misbehavior(i int, sequence uint64) types.Misbehavior {
+	first := crypto.Keccak256Hash([]byte("first"))
+	second := crypto.Keccak256Hash([]byte("second"))
+	return types.Misbehavior{
+		ChainID:           c.ctx.ChainID(),
+		Relayer:           c.address(i).Hex(),
+		Sequence:          sequence,
+		FirstPayloadHash:  first.Hex(),
+		FirstSignature:    hexutil.Encode(c.sign(sequence, first, i)[0]),
+		SecondPayloadHash: second.Hex(),
+		SecondSignature:   hexutil.Encode(c.sign(sequence, second, i)[0]),
+	}
+}
+
+// endBlock runs the end blocker at height and returns the typed events it emitted
+func (c *testChain) endBlock(height int64) []proto.Message {
+	c.ctx = c.ctx.WithBlockHeight(height).WithEventManager(sdk.NewEventManager())
+	c.app.BridgeKeeper.ExecuteMisbehavior(c.ctx)
+	c.app.BridgeKeeper.UpdateRelayerSet(c.ctx)
+	c.app.BridgeKeeper.ApplyAssetChanges(c.ctx)
+	c.app.BridgeKeeper.ApplyWithdrawalRulings(c.ctx)
+	c.app.BridgeKeeper.CompleteWithdrawals(c.ctx)
+	return c.events()
+}
+
+// events returns the typed events of the bridge module emitted in the context
+func (c *testChain) events() []proto.Message {
+	var events []proto.Message
+	for _, event := range c.ctx.EventManager().ABCIEvents() {
+		if !strings.HasPrefix(event.Type, "tacchain.bridge.v1.") {
+			continue
+		}
+		msg, err := sdk.ParseTypedEvent(event)
+		require.NoError(c.t, err)
+		events = append(events, msg)
+	}
+	return events
+}
+
+func TestAttestationQuorum(t *testing.T) {
+	payloadHash := crypto.Keccak256Hash([]byte("payload"))
+	stranger, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		// modify changes the params of a set of 4 relayers bonding 40, 30, 20
+		// and 10, with a threshold of 0.667
+		modify  func(p *types.Params)
+		sigs    func(c *testChain) [][]byte
+		power   int64
+		total   int64
+		wantErr error
+	}{
+		{
+			name:  "two largest relayers",
+			sigs:  func(c *testChain) [][]byte { return c.sign(1, payloadHash, 0, 1) },
+			power: 70,
+			total: 100,
+		},
+		{
+			name:  "all relayers",
+			sigs:  func(c *testChain) [][]byte { return c.sign(1, payloadHash, 3, 2, 1, 0) },
+			power: 100,
+			total: 100,
+		},
+		{
+			name:    "below the threshold",
+			sigs:    func(c *testChain) [][]byte { return c.sign(1, payloadHash, 0, 2) },
+			power:   60,
+			total:   100,
+			wantErr: types.ErrNoQuorum,
+		},
+		{
+			name:    "all but the largest relayer",
+			sigs:    func(c *testChain) [][]byte { return c.sign(1, payloadHash, 1, 2, 3) },
+			power:   60,
+			total:   100,
+			wantErr: types.ErrNoQuorum,
+		},
+		{
+			name:    "exactly the threshold",
+			modify:  func(p *types.Params) { p.AttestationThreshold = sdkmath.LegacyNewDecWithPrec(7, 1) },
+			sigs:    func(c *testChain) [][]byte { return c.sign(1, payloadHash, 0, 1) },
+			power:   70,
+			total:   100,
+			wantErr: types.ErrNoQuorum,
+		},
+		{
+			name:    "no signature",
+			sigs:    func(c *testChain) [][]byte { return nil },
+			power:   0,
+			total:   100,
+			wantErr: types.ErrNoQuorum,
+		},
+		{
+			name: "relayer signing twice",
+			sigs: func(c *testChain) [][]byte {
+				return append(c.sign(1, payloadHash, 0, 1), c.sign(1, payloadHash, 0)...)
+			},
+			wantErr: types.ErrDuplicateSignature,
+		},
+		{
+			name: "signature of a non relayer",
+			sigs: func(c *testChain) [][]byte {
+				sig, err := crypto.Sign(types.AttestationDigest(c.ctx.ChainID(), 1, payloadHash).Bytes(), stranger)
+				require.NoError(t, err)
+				return append(c.sign(1, payloadHash, 0, 1), sig)
+			},
+			wantErr: types.ErrUnknownRelayer,
+		},
+		{
+			name:    "signature of another sequence",
+			sigs:    func(c *testChain) [][]byte { return append(c.sign(1, payloadHash, 0), c.sign(2, payloadHash, 1)...) },
+			wantErr: types.ErrUnknownRelayer,
+		},
+		{
+			name:    "malformed signature",
+			sigs:    func(c *testChain) [][]byte { return append(c.sign(1, payloadHash, 0, 1), []byte{0x01}) },
+			wantErr: types.ErrInvalidSignature,
+		},
+		{
+			name:   "relayers below the min bond have no power",
+			modify: func(p *types.Params) { p.MinRelayerBond = sdkmath.NewInt(25) },
+			sigs:   func(c *testChain) [][]byte { return c.sign(1, payloadHash, 0, 1) },
+			power:  70,
+			total:  70,
+		},
+		{
+			name:    "relayers below the min bond can't sign",
+			modify:  func(p *types.Params) { p.MinRelayerBond = sdkmath.NewInt(25) },
+			sigs:    func(c *testChain) [][]byte { return c.sign(1, payloadHash, 0, 1, 2) },
+			wantErr: types.ErrUnknownRelayer,
+		},
+		{
+			name:   "a single relayer over the min bond",
+			modify: func(p *types.Params) { p.MinRelayerBond = sdkmath.NewInt(35) },
+			sigs:   func(c *testChain) [][]byte { return c.sign(1, payloadHash, 0) },
+			power:  40,
+			total:  40,
+		},
+		{
+			name:   "removed relayers don't count",
+			modify: func(p *types.Params) { p.Relayers = p.Relayers[1:] },
+			sigs:   func(c *testChain) [][]byte { return c.sign(1, payloadHash, 1, 2, 3) },
+			power:  60,
+			total:  60,
+		},
+		{
+			name:    "removed relayers can't sign",
+			modify:  func(p *types.Params) { p.Relayers = p.Relayers[1:] },
+			sigs:    func(c *testChain) [][]byte { return c.sign(1, payloadHash, 0, 1, 2) },
+			wantErr: types.ErrUnknownRelayer,
+		},
+		{
+			name:    "no relayer",
+			modify:  func(p *types.Params) { p.Relayers = []string{} },
+			sigs:    func(c *testChain) [][]byte { return nil },
+			power:   0,
+			total:   0,
+			wantErr: types.ErrNoQuorum,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := setup(t, 40, 30, 20, 10)
+			params := c.app.BridgeKeeper.GetParams(c.ctx)
+			params.AttestationThreshold = sdkmath.LegacyNewDecWithPrec(667, 3)
+			if tc.modify != nil {
+				tc.modify(&params)
+			}
+			c.setParams(params)
+
+			result, err := c.app.BridgeKeeper.VerifyAttestation(c.ctx, 1, payloadHash, tc.sigs(c))
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				if tc.wantErr != types.ErrNoQuorum {
+					return
+				}
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.power, result.Power.Int64())
+			require.Equal(t, tc.total, result.TotalPower.Int64())
+		})
+	}
+}
+
+func TestAttestationQuorumFollowsBonds(t *testing.T) {
+	c := setup(t, 50, 50)
+	payloadHash := crypto.Keccak256Hash([]byte("payload"))
+
+	// half of the power is not a quorum
+	_, err := c.app.BridgeKeeper.VerifyAttestation(c.ctx, 1, payloadHash, c.sign(1, payloadHash, 0))
+	require.ErrorIs(t, err, types.ErrNoQuorum)
+
+	// bonding more gives the relayer more power
+	c.bond(0, 51)
+	result, err := c.app.BridgeKeeper.VerifyAttestation(c.ctx, 1, payloadHash, c.sign(1, payloadHash, 0))
+	require.NoError(t, err)
+	require.Equal(t, int64(101), result.Power.Int64())
+	require.Equal(t, int64(151), result.TotalPower.Int64())
+	require.Equal(t, []string{c.address(0).Hex()}, result.Signers)
+
+	powers, total, err := c.app.BridgeKeeper.RelayerPowers(c.ctx)
+	require.NoError(t, err)
+	require.Len(t, powers, 2)
+	require.Equal(t, int64(151), total.Int64())
+	require.Equal(t, int64(50), powers[1].Bond.Int64())
+}
+
+func TestBondCall(t *testing.T) {
+	c := setup(t, 100)
+	k := c.app.BridgeKeeper
+	moduleAddr := authtypes.NewModuleAddress(types.ModuleName)
+
+	c.ctx = c.ctx.WithEventManager(sdk.NewEventManager())
+	c.bond(0, 50)
+	require.Equal(t, int64(150), c.bondOf(0))
+	require.Zero(t, c.escrowBalance(), "the bond should move to the module account")
+	require.Equal(t, int64(150), c.app.BankKeeper.GetBalance(c.ctx, moduleAddr, c.denom).Amount.Int64())
+	events := c.events()
+	require.Len(t, events, 1)
+	bonded := events[0].(*types.EventBondRelayer)
+	require.Equal(t, "50"+c.denom, bonded.Amount.String())
+	require.Equal(t, "150"+c.denom, bonded.Bond.String())
+
+	// funds sent to the module account without a bond call don't count
+	coins := sdk.NewCoins(sdk.NewInt64Coin(c.denom, 1000))
+	require.NoError(t, c.app.BankKeeper.MintCoins(c.ctx, minttypes.ModuleName, coins))
+	require.NoError(t, c.app.BankKeeper.SendCoinsFromModuleToModule(c.ctx, minttypes.ModuleName, types.ModuleName, coins))
+	require.Equal(t, int64(150), c.bondOf(0))
+
+	// only relayers of the set bond, with a positive amount
+	err := c.call(newSender(t), 50, types.EscrowCall{Method: types.MethodBond})
+	require.ErrorIs(t, err, types.ErrUnknownRelayer)
+	err = c.call(c.address(0), 0, types.EscrowCall{Method: types.MethodBond})
+	require.ErrorIs(t, err, types.ErrInvalidBond)
+
+	exported := k.ExportGenesis(c.ctx)
+	require.NoError(t, exported.Validate())
+	require.Equal(t, []types.Bond{{Relayer: c.address(0).Hex(), Amount: sdkmath.NewInt(150)}}, exported.Bonds)
+}
+
+func TestSlashMisbehavingRelayer(t *testing.T) {
+	c := setup(t, 1000, 1000)
+	k := c.app.BridgeKeeper
+	poolBefore, err := c.app.DistrKeeper.FeePool.Get(c.ctx)
+	require.NoError(t, err)
+
+	params := k.GetParams(c.ctx)
+	params.UnbondingBlocks = 10
+	wrongChain := c.misbehavior(1, 7)
+	wrongChain.ChainID = "tacchain_2390-1"
+	wrongChain.FirstSignature = hexutil.Encode(c.signOn(wrongChain.ChainID, 1, 7, common.HexToHash(wrongChain.FirstPayloadHash)))
+	wrongChain.SecondSignature = hexutil.Encode(c.signOn(wrongChain.ChainID, 1, 7, common.HexToHash(wrongChain.SecondPayloadHash)))
+	params.Misbehavior = []types.Misbehavior{c.misbehavior(0, 7), wrongChain}
+	c.setParams(params)
+
+	events := c.endBlock(2)
+	require.Len(t, events, 2)
+	slash := events[0].(*types.EventSlashRelayer)
+	require.Equal(t, c.address(0).Hex(), slash.Relayer)
+	require.Equal(t, uint64(7), slash.Sequence)
+	require.Equal(t, "100"+c.denom, slash.Slashed.String())
+	unbond := events[1].(*types.EventUnbondRelayer)
+	require.Equal(t, c.address(0).Hex(), unbond.Relayer)
+	require.Equal(t, int64(12), unbond.ReleaseHeight)
+
+	// the slashed relayer left the set, the evidence signed for another chain was ignored
+	params = k.GetParams(c.ctx)
+	require.Empty(t, params.Misbehavior)
+	require.Equal(t, []string{c.address(1).Hex()}, params.Relayers)
+	require.Equal(t, int64(900), c.bondOf(0))
+	require.Equal(t, int64(1000), c.bondOf(1))
+	poolAfter, err := c.app.DistrKeeper.FeePool.Get(c.ctx)
+	require.NoError(t, err)
+	require.Equal(t, "100", poolAfter.CommunityPool.AmountOf(c.denom).Sub(poolBefore.CommunityPool.AmountOf(c.denom)).String())
+
+	// the same misbehavior isn't punished twice, another one of the unbonding
+	// relayer is
+	params.Misbehavior = []types.Misbehavior{c.misbehavior(0, 7), c.misbehavior(0, 8)}
+	c.setParams(params)
+	events = c.endBlock(3)
+	require.Len(t, events, 1)
+	require.Equal(t, uint64(8), events[0].(*types.EventSlashRelayer).Sequence)
+	require.Equal(t, int64(810), c.bondOf(0))
+	require.True(t, k.IsSlashed(c.ctx, c.address(0), 7))
+	require.True(t, k.IsSlashed(c.ctx, c.address(0), 8))
+
+	// what is left of the bond goes back to the relayer after the unbonding
+	balanceBefore := c.app.BankKeeper.GetBalance(c.ctx, sdk.AccAddress(c.address(0).Bytes()), c.denom)
+	require.Empty(t, c.endBlock(11))
+	events = c.endBlock(12)
+	require.Len(t, events, 1)
+	release := events[0].(*types.EventReleaseRelayerBond)
+	require.Equal(t, "810"+c.denom, release.Amount.String())
+	require.Zero(t, c.bondOf(0))
+	balanceAfter := c.app.BankKeeper.GetBalance(c.ctx, sdk.AccAddress(c.address(0).Bytes()), c.denom)
+	require.Equal(t, int64(810), balanceAfter.Amount.Sub(balanceBefore.Amount).Int64())
+
+	// a relayer that never was in the set can't be slashed
+	stranger := c.misbehavior(0, 9)
+	params = k.GetParams(c.ctx)
+	params.Misbehavior = []types.Misbehavior{stranger}
+	c.setParams(params)
+	require.Empty(t, c.endBlock(13))
+	require.False(t, k.IsSlashed(c.ctx, c.address(0), 9))
+}
+
+func TestRelayerUnbonding(t *testing.T) {
+	c := setup(t, 100, 100)
+	k := c.app.BridgeKeeper
+
+	params := k.GetParams(c.ctx)
+	params.UnbondingBlocks = 10
+	removed := params
+	removed.Relayers = params.Relayers[:1]
+
+	// removing a relayer starts its unbonding, adding it back cancels it
+	c.setParams(removed)
+	require.Len(t, c.endBlock(5), 1)
+	height, unbonding := k.GetUnbondingHeight(c.ctx, c.address(1))
+	require.True(t, unbonding)
+	require.Equal(t, int64(15), height)
+
+	c.setParams(params)
+	require.Empty(t, c.endBlock(8))
+	_, unbonding = k.GetUnbondingHeight(c.ctx, c.address(1))
+	require.False(t, unbonding)
+	require.Empty(t, c.endBlock(15), "the cancelled unbonding shouldn't release the bond")
+	require.Equal(t, int64(100), c.bondOf(1))
+
+	c.setParams(removed)
+	require.Len(t, c.endBlock(20), 1)
+	exported := k.ExportGenesis(c.ctx)
+	require.NoError(t, exported.Validate())
+	require.Equal(t, []types.Unbonding{{Relayer: c.address(1).Hex(), Height: 30}}, exported.Unbondings)
+	require.Equal(t, []string{c.address(0).Hex()}, exported.Relayers)
+
+	require.Empty(t, c.endBlock(29))
+	events := c.endBlock(30)
+	require.Len(t, events, 1)
+	require.Zero(t, c.bondOf(1))
+	require.Empty(t, k.GetUnbondings(c.ctx))
+	require.Equal(t, int64(100), c.bondOf(0), "the relayer still in the set keeps its bond")
+}
+
+func TestGenesisRoundTrip(t *testing.T) {
+	c := setup(t, 100, 100)
+	k := c.app.BridgeKeeper
+	k.SetUnbondingHeight(c.ctx, common.HexToAddress("0x01"), 50)
+	k.SetSlashed(c.ctx, c.address(0), 3)
+
+	exported := k.ExportGenesis(c.ctx)
+	require.NoError(t, exported.Validate())
+
+	other := setup(t)
+	other.app.BridgeKeeper.InitGenesis(other.ctx, *exported)
+	require.Equal(t, exported, other.app.BridgeKeeper.ExportGenesis(other.ctx))
+}
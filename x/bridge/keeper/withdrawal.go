@@ -0,0 +1,308 @@
+package keeper
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+// HandleEscrowCall executes a call of sender to the escrow address. value is
+// the amount the call transferred to the escrow address, in the EVM denom,
+// only withdrawals and bonds transfer value. Asset withdrawals burn the
+// denom of the asset from the balance of the sender instead.
+func (k Keeper) HandleEscrowCall(ctx sdk.Context, sender common.Address, value sdk.Coin, data []byte) error {
+	call, err := types.ParseEscrowCall(data)
+	if err != nil {
+		return err
+	}
+	switch call.Method {
+	case types.MethodChallenge:
+		if !value.IsZero() {
+			return errorsmod.Wrap(types.ErrInvalidBridgeCall, "challenges can't transfer value")
+		}
+		return k.ChallengeWithdrawal(ctx, sender, call.ID)
+	case types.MethodBond:
+		return k.Bond(ctx, sender, value)
+	case types.MethodDeposit:
+		if !value.IsZero() {
+			return errorsmod.Wrap(types.ErrInvalidBridgeCall, "deposits can't transfer value")
+		}
+		_, err = k.ExecuteDeposit(ctx, value.Denom, call.Deposit, call.Signatures)
+		return err
+	case types.MethodWithdrawAsset:
+		if !value.IsZero() {
+			return errorsmod.Wrap(types.ErrInvalidBridgeCall, "asset withdrawals can't transfer value")
+		}
+		_, _, err = k.BurnAsset(ctx, sdk.AccAddress(sender.Bytes()), call.Recipient, call.Amount)
+		return err
+	}
+	_, err = k.QueueWithdrawal(ctx, sender, call.Recipient, value)
+	return err
+}
+
+// QueueWithdrawal queues the withdrawal of amount by sender to a TON
+// recipient. The amount must already be held by the escrow address, it moves
+// on to the module account until the withdrawal completes after the
+// withdrawal delay, unless it is challenged.
+func (k Keeper) QueueWithdrawal(ctx sdk.Context, sender common.Address, recipient string, amount sdk.Coin) (types.Withdrawal, error) {
+	withdrawal := types.Withdrawal{
+		ID:             k.GetNextWithdrawalID(ctx),
+		Sender:         sender.Hex(),
+		Recipient:      recipient,
+		Amount:         amount,
+		Status:         types.WithdrawalStatusQueued,
+		CompleteHeight: ctx.BlockHeight() + int64(k.GetParams(ctx).WithdrawalDelay),
+	}
+	if err := withdrawal.Validate(); err != nil {
+		return types.Withdrawal{}, err
+	}
+	escrow := sdk.AccAddress(types.EscrowAddress().Bytes())
+	if err := k.bankKeeper.SendCoinsFromAccountToModule(ctx, escrow, types.ModuleName, sdk.NewCoins(amount)); err != nil {
+		return types.Withdrawal{}, err
+	}
+
+	k.setNextWithdrawalID(ctx, withdrawal.ID+1)
+	k.SetWithdrawal(ctx, withdrawal)
+	k.emitEvent(ctx, &types.EventQueueWithdrawal{
+		Id:             withdrawal.ID,
+		Sender:         withdrawal.Sender,
+		Recipient:      withdrawal.Recipient,
+		Amount:         withdrawal.Amount,
+		CompleteHeight: withdrawal.CompleteHeight,
+	})
+	return withdrawal, nil
+}
+
+// ChallengeWithdrawal freezes a queued withdrawal until governance rules on
+// it. Only active relayers challenge withdrawals, before they complete.
+func (k Keeper) ChallengeWithdrawal(ctx sdk.Context, challenger common.Address, id uint64) error {
+	powers, _, err := k.RelayerPowers(ctx)
+	if err != nil {
+		return err
+	}
+	active := false
+	for _, power := range powers {
+		if power.Active && common.HexToAddress(power.Relayer) == challenger {
+			active = true
+		}
+	}
+	if !active {
+		return errorsmod.Wrapf(types.ErrUnknownRelayer, "%s", challenger.Hex())
+	}
+
+	withdrawal, found := k.GetWithdrawal(ctx, id)
+	if !found {
+		return errorsmod.Wrapf(types.ErrUnknownWithdrawal, "%d", id)
+	}
+	if withdrawal.Status != types.WithdrawalStatusQueued {
+		return errorsmod.Wrapf(types.ErrChallengeClosed, "withdrawal %d is already %s", id, withdrawal.Status)
+	}
+	if ctx.BlockHeight() >= withdrawal.CompleteHeight {
+		return errorsmod.Wrapf(types.ErrChallengeClosed, "withdrawal %d completes at %d", id, withdrawal.CompleteHeight)
+	}
+
+	withdrawal.Status = types.WithdrawalStatusChallenged
+	withdrawal.Challenger = challenger.Hex()
+	withdrawal.ChallengeHeight = ctx.BlockHeight()
+	k.SetWithdrawal(ctx, withdrawal)
+	k.emitEvent(ctx, &types.EventChallengeWithdrawal{Id: id, Challenger: withdrawal.Challenger})
+	return nil
+}
+
+// CompleteWithdrawals completes the queued withdrawals whose challenge window
+// ended, at most MaxCompletedWithdrawalsPerBlock of them. Their amounts are
+// burnt from the module account as they circulate on TON. A withdrawal that
+// can't complete stays queued and is retried in the next block.
+func (k Keeper) CompleteWithdrawals(ctx sdk.Context) {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.WithdrawalQueuePrefix, types.WithdrawalQueuePrefixAt(ctx.BlockHeight()+1))
+	if err != nil {
+		panic(err)
+	}
+	var due []uint64
+	prefixLen := len(types.WithdrawalQueuePrefixAt(0))
+	for ; iterator.Valid() && len(due) < types.MaxCompletedWithdrawalsPerBlock; iterator.Next() {
+		due = append(due, sdk.BigEndianToUint64(iterator.Key()[prefixLen:]))
+	}
+	iterator.Close()
+
+	for _, id := range due {
+		withdrawal, found := k.GetWithdrawal(ctx, id)
+		if !found {
+			continue
+		}
+		cacheCtx, write := ctx.CacheContext()
+		if err := k.completeWithdrawal(cacheCtx, withdrawal); err != nil {
+			k.Logger(ctx).Error("failed to complete withdrawal", "id", id, "error", err)
+			continue
+		}
+		write()
+	}
+}
+
+// ApplyWithdrawalRulings releases or refunds the challenged withdrawals
+// governance ruled on and clears the rulings. A ruling that can't be applied
+// doesn't affect the others.
+func (k Keeper) ApplyWithdrawalRulings(ctx sdk.Context) {
+	params := k.GetParams(ctx)
+	if len(params.WithdrawalRulings) == 0 {
+		return
+	}
+
+	for _, ruling := range params.WithdrawalRulings {
+		cacheCtx, write := ctx.CacheContext()
+		if err := k.applyWithdrawalRuling(cacheCtx, ruling); err != nil {
+			k.Logger(ctx).Error("failed to apply withdrawal ruling", "id", ruling.ID, "release", ruling.Release, "error", err)
+			continue
+		}
+		write()
+	}
+
+	params.WithdrawalRulings = []types.WithdrawalRuling{}
+	k.SetParams(ctx, params)
+}
+
+func (k Keeper) applyWithdrawalRuling(ctx sdk.Context, ruling types.WithdrawalRuling) error {
+	withdrawal, found := k.GetWithdrawal(ctx, ruling.ID)
+	if !found {
+		return errorsmod.Wrapf(types.ErrUnknownWithdrawal, "%d", ruling.ID)
+	}
+	if withdrawal.Status != types.WithdrawalStatusChallenged {
+		return errorsmod.Wrapf(types.ErrInvalidWithdrawal, "withdrawal %d isn't challenged", ruling.ID)
+	}
+
+	if ruling.Release {
+		return k.completeWithdrawal(ctx, withdrawal)
+	}
+
+	if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, withdrawal.SenderAddress(), sdk.NewCoins(withdrawal.Amount)); err != nil {
+		return err
+	}
+	k.deleteWithdrawal(ctx, withdrawal)
+	k.emitEvent(ctx, &types.EventRefundWithdrawal{
+		Id:     withdrawal.ID,
+		Sender: withdrawal.Sender,
+		Amount: withdrawal.Amount,
+	})
+	return nil
+}
+
+func (k Keeper) completeWithdrawal(ctx sdk.Context, withdrawal types.Withdrawal) error {
+	if err := k.bankKeeper.BurnCoins(ctx, types.ModuleName, sdk.NewCoins(withdrawal.Amount)); err != nil {
+		return err
+	}
+	k.deleteWithdrawal(ctx, withdrawal)
+	k.emitEvent(ctx, &types.EventCompleteWithdrawal{
+		Id:        withdrawal.ID,
+		Sender:    withdrawal.Sender,
+		Recipient: withdrawal.Recipient,
+		Amount:    withdrawal.Amount,
+	})
+	return nil
+}
+
+// CheckEscrowBalance fails if the escrow address holds any coins. Withdrawal
+// and bond calls move the value they transfer on to the module account, so
+// anything left was sent without one of them, by a contract, a bank send or
+// an IBC transfer, and would be stuck as no key controls the escrow.
+func (k Keeper) CheckEscrowBalance(ctx sdk.Context) error {
+	escrow := sdk.AccAddress(types.EscrowAddress().Bytes())
+	if balance := k.bankKeeper.GetAllBalances(ctx, escrow); !balance.IsZero() {
+		return errorsmod.Wrapf(types.ErrInvalidBridgeCall, "%s sent to the escrow address without a withdrawal or bond call", balance)
+	}
+	return nil
+}
+
+// GetWithdrawal returns the queued or challenged withdrawal with id
+func (k Keeper) GetWithdrawal(ctx sdk.Context, id uint64) (types.Withdrawal, bool) {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.WithdrawalKey(id))
+	if err != nil {
+		panic(err)
+	}
+	if bz == nil {
+		return types.Withdrawal{}, false
+	}
+	var withdrawal types.Withdrawal
+	if err := json.Unmarshal(bz, &withdrawal); err != nil {
+		panic(err)
+	}
+	return withdrawal, true
+}
+
+// GetWithdrawals returns the queued and challenged withdrawals by id
+func (k Keeper) GetWithdrawals(ctx sdk.Context) []types.Withdrawal {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.WithdrawalPrefix, storetypes.PrefixEndBytes(types.WithdrawalPrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	withdrawals := []types.Withdrawal{}
+	for ; iterator.Valid(); iterator.Next() {
+		var withdrawal types.Withdrawal
+		if err := json.Unmarshal(iterator.Value(), &withdrawal); err != nil {
+			panic(err)
+		}
+		withdrawals = append(withdrawals, withdrawal)
+	}
+	return withdrawals
+}
+
+// SetWithdrawal stores a withdrawal, the queued ones are indexed by the height
+// they complete at
+func (k Keeper) SetWithdrawal(ctx sdk.Context, withdrawal types.Withdrawal) {
+	bz, err := json.Marshal(withdrawal)
+	if err != nil {
+		panic(err)
+	}
+	store := k.storeService.OpenKVStore(ctx)
+	if err := store.Set(types.WithdrawalKey(withdrawal.ID), bz); err != nil {
+		panic(err)
+	}
+
+	queueKey := types.WithdrawalQueueKey(withdrawal.CompleteHeight, withdrawal.ID)
+	if withdrawal.Status == types.WithdrawalStatusQueued {
+		err = store.Set(queueKey, []byte{})
+	} else {
+		err = store.Delete(queueKey)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (k Keeper) deleteWithdrawal(ctx sdk.Context, withdrawal types.Withdrawal) {
+	store := k.storeService.OpenKVStore(ctx)
+	if err := store.Delete(types.WithdrawalKey(withdrawal.ID)); err != nil {
+		panic(err)
+	}
+	if err := store.Delete(types.WithdrawalQueueKey(withdrawal.CompleteHeight, withdrawal.ID)); err != nil {
+		panic(err)
+	}
+}
+
+// GetNextWithdrawalID returns the id of the next withdrawal, ids start at 1
+func (k Keeper) GetNextWithdrawalID(ctx sdk.Context) uint64 {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.NextWithdrawalIDKey)
+	if err != nil {
+		panic(err)
+	}
+	if bz == nil {
+		return 1
+	}
+	return sdk.BigEndianToUint64(bz)
+}
+
+func (k Keeper) setNextWithdrawalID(ctx sdk.Context, id uint64) {
+	if err := k.storeService.OpenKVStore(ctx).Set(types.NextWithdrawalIDKey, sdk.Uint64ToBigEndian(id)); err != nil {
+		panic(err)
+	}
+}
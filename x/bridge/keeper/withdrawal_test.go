@@ -0,0 +1,260 @@
+package keeper_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+
+	"github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+const tonRecipient = "EQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqB2N"
+
+var escrow = sdk.AccAddress(types.EscrowAddress().Bytes())
+
+// setupWithdrawals returns a chain with 2 relayers and a withdrawal delay of 10 blocks
+func setupWithdrawals(t *testing.T) *testChain {
+	c := setup(t, 100, 100)
+	params := c.app.BridgeKeeper.GetParams(c.ctx)
+	params.WithdrawalDelay = 10
+	c.setParams(params)
+	return c
+}
+
+// call executes an escrow call of sender transferring value to the escrow
+// address like the EVM tx would
+func (c *testChain) call(sender common.Address, value int64, call types.EscrowCall) error {
+	coin := sdk.NewCoin(c.denom, sdkmath.NewInt(value))
+	if value > 0 {
+		coins := sdk.NewCoins(coin)
+		require.NoError(c.t, c.app.BankKeeper.MintCoins(c.ctx, minttypes.ModuleName, coins))
+		require.NoError(c.t, c.app.BankKeeper.SendCoinsFromModuleToAccount(c.ctx, minttypes.ModuleName, escrow, coins))
+	}
+	data, err := call.Pack()
+	require.NoError(c.t, err)
+	return c.app.BridgeKeeper.HandleEscrowCall(c.ctx, sender, coin, data)
+}
+
+func (c *testChain) withdraw(sender common.Address, value int64) types.Withdrawal {
+	c.ctx = c.ctx.WithEventManager(sdk.NewEventManager())
+	require.NoError(c.t, c.call(sender, value, types.EscrowCall{Method: types.MethodWithdraw, Recipient: tonRecipient}))
+	events := c.events()
+	require.Len(c.t, events, 1)
+	queued := events[0].(*types.EventQueueWithdrawal)
+	withdrawal, found := c.app.BridgeKeeper.GetWithdrawal(c.ctx, queued.Id)
+	require.True(c.t, found)
+	return withdrawal
+}
+
+func (c *testChain) challenge(relayer int, id uint64) error {
+	return c.call(c.address(relayer), 0, types.EscrowCall{Method: types.MethodChallenge, ID: id})
+}
+
+func (c *testChain) escrowBalance() int64 {
+	return c.app.BankKeeper.GetBalance(c.ctx, escrow, c.denom).Amount.Int64()
+}
+
+// moduleBalance returns the balance of the module account, which holds the
+// bonds and the queued withdrawals
+func (c *testChain) moduleBalance() int64 {
+	return c.app.BankKeeper.GetBalance(c.ctx, authtypes.NewModuleAddress(types.ModuleName), c.denom).Amount.Int64()
+}
+
+func (c *testChain) supply() int64 {
+	return c.app.BankKeeper.GetSupply(c.ctx, c.denom).Amount.Int64()
+}
+
+func (c *testChain) setRulings(rulings ...types.WithdrawalRuling) {
+	params := c.app.BridgeKeeper.GetParams(c.ctx)
+	params.WithdrawalRulings = rulings
+	c.setParams(params)
+}
+
+func newSender(t *testing.T) common.Address {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func TestWithdrawalCompletesAfterDelay(t *testing.T) {
+	c := setupWithdrawals(t)
+	k := c.app.BridgeKeeper
+	sender := newSender(t)
+	bonds := c.moduleBalance()
+
+	withdrawal := c.withdraw(sender, 500)
+	require.Equal(t, types.Withdrawal{
+		ID:             1,
+		Sender:         sender.Hex(),
+		Recipient:      tonRecipient,
+		Amount:         sdk.NewCoin(c.denom, sdkmath.NewInt(500)),
+		Status:         types.WithdrawalStatusQueued,
+		CompleteHeight: 11,
+	}, withdrawal)
+	c.ctx = c.ctx.WithBlockHeight(2)
+	second := c.withdraw(sender, 200)
+	require.Equal(t, uint64(2), second.ID)
+	require.Equal(t, int64(12), second.CompleteHeight)
+	require.Len(t, k.GetWithdrawals(c.ctx), 2)
+
+	// the module account holds the queued amounts, the escrow holds nothing
+	require.Zero(t, c.escrowBalance())
+	require.NoError(t, k.CheckEscrowBalance(c.ctx))
+	require.Equal(t, bonds+700, c.moduleBalance())
+	supply := c.supply()
+
+	require.Empty(t, c.endBlock(10))
+	events := c.endBlock(11)
+	require.Len(t, events, 1)
+	require.Equal(t, &types.EventCompleteWithdrawal{
+		Id:        1,
+		Sender:    sender.Hex(),
+		Recipient: tonRecipient,
+		Amount:    withdrawal.Amount,
+	}, events[0])
+	_, found := k.GetWithdrawal(c.ctx, 1)
+	require.False(t, found)
+	require.Len(t, k.GetWithdrawals(c.ctx), 1)
+
+	// the amounts of completed withdrawals are burnt
+	events = c.endBlock(12)
+	require.Len(t, events, 1)
+	require.Equal(t, second.ID, events[0].(*types.EventCompleteWithdrawal).Id)
+	require.Empty(t, k.GetWithdrawals(c.ctx))
+	require.Equal(t, bonds, c.moduleBalance())
+	require.Equal(t, supply-700, c.supply())
+
+	// once the challenge window ended the withdrawal can't be challenged
+	require.ErrorIs(t, c.challenge(0, 1), types.ErrUnknownWithdrawal)
+}
+
+func TestChallengedWithdrawalWaitsForRuling(t *testing.T) {
+	c := setupWithdrawals(t)
+	k := c.app.BridgeKeeper
+	sender := newSender(t)
+	bonds := c.moduleBalance()
+	refunded := c.withdraw(sender, 500)
+	released := c.withdraw(sender, 300)
+
+	c.ctx = c.ctx.WithBlockHeight(5).WithEventManager(sdk.NewEventManager())
+	require.NoError(t, c.challenge(0, refunded.ID))
+	require.NoError(t, c.challenge(1, released.ID))
+	events := c.events()
+	require.Len(t, events, 2)
+	require.Equal(t, &types.EventChallengeWithdrawal{Id: refunded.ID, Challenger: c.address(0).Hex()}, events[0])
+	require.ErrorIs(t, c.challenge(1, refunded.ID), types.ErrChallengeClosed, "a withdrawal is challenged once")
+
+	frozen, found := k.GetWithdrawal(c.ctx, refunded.ID)
+	require.True(t, found)
+	require.Equal(t, types.WithdrawalStatusChallenged, frozen.Status)
+	require.Equal(t, c.address(0).Hex(), frozen.Challenger)
+	require.Equal(t, int64(5), frozen.ChallengeHeight)
+
+	// challenged withdrawals don't complete at the end of their window
+	require.Empty(t, c.endBlock(11))
+	require.Empty(t, c.endBlock(50))
+	require.Len(t, k.GetWithdrawals(c.ctx), 2)
+
+	c.setRulings(types.WithdrawalRuling{ID: refunded.ID}, types.WithdrawalRuling{ID: released.ID, Release: true})
+	events = c.endBlock(51)
+	require.Len(t, events, 2)
+	require.Equal(t, &types.EventRefundWithdrawal{Id: refunded.ID, Sender: sender.Hex(), Amount: refunded.Amount}, events[0])
+	require.Equal(t, released.ID, events[1].(*types.EventCompleteWithdrawal).Id)
+
+	require.Empty(t, k.GetWithdrawals(c.ctx))
+	require.Empty(t, k.GetParams(c.ctx).WithdrawalRulings)
+	require.Equal(t, bonds, c.moduleBalance())
+	require.Equal(t, int64(500), c.app.BankKeeper.GetBalance(c.ctx, sdk.AccAddress(sender.Bytes()), c.denom).Amount.Int64())
+}
+
+func TestRulingsOnlyApplyToChallengedWithdrawals(t *testing.T) {
+	c := setupWithdrawals(t)
+	k := c.app.BridgeKeeper
+	queued := c.withdraw(newSender(t), 500)
+	challenged := c.withdraw(newSender(t), 300)
+	require.NoError(t, c.challenge(0, challenged.ID))
+
+	// a ruling on a queued or unknown withdrawal is dropped without affecting the others
+	c.setRulings(types.WithdrawalRuling{ID: queued.ID}, types.WithdrawalRuling{ID: 99}, types.WithdrawalRuling{ID: challenged.ID})
+	events := c.endBlock(2)
+	require.Len(t, events, 1)
+	require.Equal(t, challenged.ID, events[0].(*types.EventRefundWithdrawal).Id)
+	require.Empty(t, k.GetParams(c.ctx).WithdrawalRulings)
+
+	stillQueued, found := k.GetWithdrawal(c.ctx, queued.ID)
+	require.True(t, found)
+	require.Equal(t, queued, stillQueued)
+	require.Len(t, c.endBlock(queued.CompleteHeight), 1)
+}
+
+func TestInvalidEscrowCalls(t *testing.T) {
+	c := setupWithdrawals(t)
+	k := c.app.BridgeKeeper
+	withdrawal := c.withdraw(newSender(t), 500)
+
+	err := c.call(newSender(t), 0, types.EscrowCall{Method: types.MethodWithdraw, Recipient: tonRecipient})
+	require.ErrorIs(t, err, types.ErrInvalidWithdrawal, "withdrawals need value")
+	err = c.call(newSender(t), 100, types.EscrowCall{Method: types.MethodWithdraw, Recipient: "tac1xyz"})
+	require.ErrorIs(t, err, types.ErrInvalidWithdrawal, "recipients are TON addresses")
+	err = c.call(c.address(0), 1, types.EscrowCall{Method: types.MethodChallenge, ID: withdrawal.ID})
+	require.ErrorIs(t, err, types.ErrInvalidBridgeCall, "challenges can't transfer value")
+	err = c.app.BridgeKeeper.HandleEscrowCall(c.ctx, c.address(0), sdk.NewCoin(c.denom, sdkmath.ZeroInt()), []byte{0x01})
+	require.ErrorIs(t, err, types.ErrInvalidBridgeCall)
+
+	// only active relayers challenge
+	err = c.call(newSender(t), 0, types.EscrowCall{Method: types.MethodChallenge, ID: withdrawal.ID})
+	require.ErrorIs(t, err, types.ErrUnknownRelayer)
+	params := k.GetParams(c.ctx)
+	params.MinRelayerBond = sdkmath.NewInt(101)
+	c.setParams(params)
+	require.ErrorIs(t, c.challenge(0, withdrawal.ID), types.ErrUnknownRelayer, "relayers below the min bond are inactive")
+	params.MinRelayerBond = sdkmath.ZeroInt()
+	c.setParams(params)
+
+	require.ErrorIs(t, c.challenge(0, 99), types.ErrUnknownWithdrawal)
+	c.ctx = c.ctx.WithBlockHeight(withdrawal.CompleteHeight)
+	require.ErrorIs(t, c.challenge(0, withdrawal.ID), types.ErrChallengeClosed, "the window ends at the complete height")
+
+	// the failed calls didn't queue anything
+	require.Len(t, k.GetWithdrawals(c.ctx), 1)
+	require.Equal(t, uint64(2), k.GetNextWithdrawalID(c.ctx))
+
+	// the value of the failed withdrawal is left at the escrow, which fails
+	// the tx that sent it
+	require.Equal(t, int64(100), c.escrowBalance())
+	require.ErrorIs(t, k.CheckEscrowBalance(c.ctx), types.ErrInvalidBridgeCall)
+}
+
+func TestWithdrawalGenesis(t *testing.T) {
+	c := setupWithdrawals(t)
+	queued := c.withdraw(newSender(t), 500)
+	challenged := c.withdraw(newSender(t), 300)
+	require.NoError(t, c.challenge(0, challenged.ID))
+
+	exported := c.app.BridgeKeeper.ExportGenesis(c.ctx)
+	require.NoError(t, exported.Validate())
+	require.Len(t, exported.Withdrawals, 2)
+	require.Equal(t, uint64(3), exported.NextWithdrawalID)
+
+	// the bank genesis carries the balance of the module account
+	other := setup(t)
+	held := sdk.NewCoins(sdk.NewCoin(other.denom, sdkmath.NewInt(800)))
+	require.NoError(t, other.app.BankKeeper.MintCoins(other.ctx, minttypes.ModuleName, held))
+	require.NoError(t, other.app.BankKeeper.SendCoinsFromModuleToModule(other.ctx, minttypes.ModuleName, types.ModuleName, held))
+	other.app.BridgeKeeper.InitGenesis(other.ctx, *exported)
+	require.Equal(t, exported, other.app.BridgeKeeper.ExportGenesis(other.ctx))
+
+	// the imported queue completes the queued withdrawal only
+	events := other.endBlock(queued.CompleteHeight)
+	require.Len(t, events, 1)
+	require.Equal(t, queued.ID, events[0].(*types.EventCompleteWithdrawal).Id)
+	require.Equal(t, uint64(3), other.app.BridgeKeeper.GetNextWithdrawalID(other.ctx))
+}
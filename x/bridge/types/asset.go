@@ -0,0 +1,97 @@
+package types
+
+import (
+	"math/big"
+	"strings"
+
+	errorsmod "cosmossdk.io/errors"
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// JettonNative identifies Toncoin, the native coin of TON, in the asset registry
+	JettonNative = "native"
+
+	// MaxAssetDecimals bounds the decimals of the assets on both chains
+	MaxAssetDecimals = 18
+)
+
+// Asset maps an asset of TON to the denom the bridge mints for it on
+// tacchain. Jetton is JettonNative or the raw address of the jetton master,
+// lowercase. Amounts on TON have TONDecimals, the denom has Decimals, at
+// least as many so any TON amount has an exact local amount.
+//
+// The denom is a bank denom, governance exposes it to the EVM as an ERC-20 by
+// registering it with x/erc20.
+type Asset struct {
+	Jetton      string `json:"jetton" yaml:"jetton"`
+	Denom       string `json:"denom" yaml:"denom"`
+	TONDecimals uint32 `json:"ton_decimals" yaml:"ton_decimals"`
+	Decimals    uint32 `json:"decimals" yaml:"decimals"`
+}
+
+// Normalized returns the asset with its jetton address lowercase
+func (a Asset) Normalized() Asset {
+	a.Jetton = NormalizeJetton(a.Jetton)
+	return a
+}
+
+// Validate performs basic validation of an asset
+func (a Asset) Validate() error {
+	if err := ValidateJetton(a.Jetton); err != nil {
+		return err
+	}
+	if err := sdk.ValidateDenom(a.Denom); err != nil {
+		return errorsmod.Wrapf(ErrInvalidAsset, "invalid denom of %s: %s", a.Jetton, err)
+	}
+	// these denoms are minted by other modules against what they escrow
+	for _, prefix := range []string{"erc20/", "ibc/"} {
+		if strings.HasPrefix(a.Denom, prefix) {
+			return errorsmod.Wrapf(ErrInvalidAsset, "the bridge can't mint %s denoms: %s", prefix, a.Denom)
+		}
+	}
+	if a.TONDecimals > MaxAssetDecimals || a.Decimals > MaxAssetDecimals {
+		return errorsmod.Wrapf(ErrInvalidAsset, "decimals of %s above %d", a.Jetton, MaxAssetDecimals)
+	}
+	if a.Decimals < a.TONDecimals {
+		return errorsmod.Wrapf(ErrInvalidAsset, "%s has fewer decimals than on TON: %d < %d", a.Denom, a.Decimals, a.TONDecimals)
+	}
+	return nil
+}
+
+// scale returns the local amount of one unit of the TON amount
+func (a Asset) scale() sdkmath.Int {
+	return sdkmath.NewIntFromBigInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(a.Decimals-a.TONDecimals)), nil))
+}
+
+// ToLocal converts an amount on TON to the amount of the denom
+func (a Asset) ToLocal(tonAmount sdkmath.Int) sdkmath.Int {
+	return tonAmount.Mul(a.scale())
+}
+
+// ToTON converts an amount of the denom to the amount on TON. The amount must
+// not have more decimals than the asset has on TON.
+func (a Asset) ToTON(amount sdkmath.Int) (sdkmath.Int, error) {
+	scale := a.scale()
+	if !amount.Mod(scale).IsZero() {
+		return sdkmath.Int{}, errorsmod.Wrapf(ErrInexactAmount, "%s%s has more than the %d decimals of %s on TON", amount, a.Denom, a.TONDecimals, a.Jetton)
+	}
+	return amount.Quo(scale), nil
+}
+
+// NormalizeJetton returns the registry form of a jetton identifier
+func NormalizeJetton(jetton string) string {
+	return strings.ToLower(jetton)
+}
+
+// ValidateJetton checks jetton is JettonNative or the raw address of a jetton
+// master. The user friendly form isn't accepted as it encodes the same
+// address in several ways.
+func ValidateJetton(jetton string) error {
+	if jetton != JettonNative && !tonRawAddress.MatchString(jetton) {
+		return errorsmod.Wrapf(ErrInvalidAsset, "jetton must be %q or a raw TON address: %q", JettonNative, jetton)
+	}
+	return nil
+}
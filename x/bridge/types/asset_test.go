@@ -0,0 +1,103 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+func newAsset() types.Asset {
+	return types.Asset{Jetton: tonRawAddress, Denom: "ujusdt", TONDecimals: 6, Decimals: 18}
+}
+
+func TestAssetValidate(t *testing.T) {
+	require.NoError(t, newAsset().Validate())
+	require.NoError(t, types.Asset{Jetton: types.JettonNative, Denom: "aton", TONDecimals: 9, Decimals: 18}.Validate())
+
+	upper := newAsset()
+	upper.Jetton = "0:83DFD552E63729B472FCBCC8C45EBCC6691702558B68EC7527E1BA403A0F31A8"
+	require.NoError(t, upper.Validate())
+	require.Equal(t, tonRawAddress, upper.Normalized().Jetton)
+
+	testCases := []struct {
+		name   string
+		modify func(*types.Asset)
+	}{
+		{"friendly jetton address", func(a *types.Asset) { a.Jetton = tonFriendlyAddress }},
+		{"empty jetton", func(a *types.Asset) { a.Jetton = "" }},
+		{"invalid denom", func(a *types.Asset) { a.Denom = "1x" }},
+		{"erc20 denom", func(a *types.Asset) { a.Denom = "erc20/0x83dfd552e63729b472fcbcc8c45ebcc6691702" }},
+		{"ibc denom", func(a *types.Asset) { a.Denom = "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2" }},
+		{"fewer local decimals", func(a *types.Asset) { a.Decimals = 5 }},
+		{"too many decimals", func(a *types.Asset) { a.TONDecimals, a.Decimals = 19, 19 }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := newAsset()
+			tc.modify(&a)
+			require.ErrorIs(t, a.Validate(), types.ErrInvalidAsset)
+		})
+	}
+}
+
+func TestAssetConversion(t *testing.T) {
+	asset := newAsset()
+	require.Equal(t, sdkmath.NewInt(1_500_000_000_000_000_000), asset.ToLocal(sdkmath.NewInt(1_500_000)))
+
+	tonAmount, err := asset.ToTON(sdkmath.NewInt(1_500_000_000_000_000_000))
+	require.NoError(t, err)
+	require.Equal(t, sdkmath.NewInt(1_500_000), tonAmount)
+
+	// dust below the decimals on TON can't be released
+	_, err = asset.ToTON(sdkmath.NewInt(1_500_000_000_000_000_001))
+	require.ErrorIs(t, err, types.ErrInexactAmount)
+
+	// same decimals on both chains convert one to one
+	asset.Decimals = asset.TONDecimals
+	require.Equal(t, sdkmath.NewInt(7), asset.ToLocal(sdkmath.NewInt(7)))
+	tonAmount, err = asset.ToTON(sdkmath.NewInt(7))
+	require.NoError(t, err)
+	require.Equal(t, sdkmath.NewInt(7), tonAmount)
+}
+
+func TestParamsValidateAssets(t *testing.T) {
+	params := types.DefaultParams()
+	params.ApprovedAssets = []types.Asset{newAsset(), {Jetton: types.JettonNative, Denom: "aton", TONDecimals: 9, Decimals: 18}}
+	params.RemovedAssets = []string{tonRawAddress}
+	require.NoError(t, params.Validate())
+
+	duplicate := newAsset()
+	duplicate.Denom = "ujusdt2"
+	duplicate.Jetton = "0:83DFD552E63729B472FCBCC8C45EBCC6691702558B68EC7527E1BA403A0F31A8"
+	params.ApprovedAssets = []types.Asset{newAsset(), duplicate}
+	require.Error(t, params.Validate(), "a jetton can only be approved once")
+
+	duplicate = newAsset()
+	duplicate.Jetton = types.JettonNative
+	params.ApprovedAssets = []types.Asset{newAsset(), duplicate}
+	require.Error(t, params.Validate(), "a denom can only be approved once")
+
+	params.ApprovedAssets = []types.Asset{}
+	params.RemovedAssets = []string{tonRawAddress, tonRawAddress}
+	require.Error(t, params.Validate(), "a jetton can only be removed once")
+	params.RemovedAssets = []string{tonFriendlyAddress}
+	require.ErrorIs(t, params.Validate(), types.ErrInvalidAsset)
+}
+
+func TestGenesisValidateAssets(t *testing.T) {
+	gs := types.DefaultGenesisState()
+	gs.Assets = []types.Asset{newAsset()}
+	require.NoError(t, gs.Validate())
+
+	gs.Assets = []types.Asset{newAsset(), newAsset()}
+	require.Error(t, gs.Validate(), "assets must be unique")
+
+	upper := newAsset()
+	upper.Jetton = "0:83DFD552E63729B472FCBCC8C45EBCC6691702558B68EC7527E1BA403A0F31A8"
+	gs.Assets = []types.Asset{upper}
+	require.Error(t, gs.Validate(), "genesis jettons must be lowercase")
+}
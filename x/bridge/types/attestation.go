@@ -0,0 +1,45 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// attestationDomain separates attestation digests from any other message a
+// relayer key signs
+const attestationDomain = "tacchain bridge attestation"
+
+// AttestationDigest returns the digest relayers sign to attest that the bridge
+// message with the given sequence has the given payload hash. The chain id
+// keeps attestations from being replayed on another network.
+func AttestationDigest(chainID string, sequence uint64, payloadHash common.Hash) common.Hash {
+	return crypto.Keccak256Hash(
+		[]byte(attestationDomain),
+		[]byte(chainID),
+		sdk.Uint64ToBigEndian(sequence),
+		payloadHash.Bytes(),
+	)
+}
+
+// RecoverSigner returns the address of the key that produced the 65 bytes
+// [R || S || V] signature of digest. V is 0 or 1, or 27 or 28 like the
+// signatures of Ethereum wallets.
+func RecoverSigner(digest common.Hash, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, errorsmod.Wrapf(ErrInvalidSignature, "expected %d bytes, got %d", crypto.SignatureLength, len(signature))
+	}
+
+	sig := append([]byte{}, signature...)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pubKey, err := crypto.SigToPub(digest.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, errorsmod.Wrap(ErrInvalidSignature, err.Error())
+	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
@@ -0,0 +1,201 @@
+package types_test
+
+import (
+	"crypto/ecdsa"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+const testChainID = "tacchain_239-1"
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, chainID string, sequence uint64, payloadHash common.Hash) []byte {
+	t.Helper()
+
+	sig, err := crypto.Sign(types.AttestationDigest(chainID, sequence, payloadHash).Bytes(), key)
+	require.NoError(t, err)
+	return sig
+}
+
+func TestRecoverSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	payloadHash := crypto.Keccak256Hash([]byte("payload"))
+
+	sig := sign(t, key, testChainID, 7, payloadHash)
+	signer, err := types.RecoverSigner(types.AttestationDigest(testChainID, 7, payloadHash), sig)
+	require.NoError(t, err)
+	require.Equal(t, addr, signer)
+
+	// wallets sign with V = 27 or 28
+	walletSig := append([]byte{}, sig...)
+	walletSig[crypto.RecoveryIDOffset] += 27
+	signer, err = types.RecoverSigner(types.AttestationDigest(testChainID, 7, payloadHash), walletSig)
+	require.NoError(t, err)
+	require.Equal(t, addr, signer)
+
+	// the digest binds the chain, the sequence and the payload
+	for _, digest := range []common.Hash{
+		types.AttestationDigest("tacchain_2390-1", 7, payloadHash),
+		types.AttestationDigest(testChainID, 8, payloadHash),
+		types.AttestationDigest(testChainID, 7, crypto.Keccak256Hash([]byte("other"))),
+	} {
+		signer, err := types.RecoverSigner(digest, sig)
+		require.NoError(t, err)
+		require.NotEqual(t, addr, signer)
+	}
+
+	_, err = types.RecoverSigner(types.AttestationDigest(testChainID, 7, payloadHash), sig[:64])
+	require.ErrorIs(t, err, types.ErrInvalidSignature)
+}
+
+func newMisbehavior(t *testing.T, key *ecdsa.PrivateKey) types.Misbehavior {
+	t.Helper()
+
+	first := crypto.Keccak256Hash([]byte("first"))
+	second := crypto.Keccak256Hash([]byte("second"))
+	return types.Misbehavior{
+		ChainID:           testChainID,
+		Relayer:           crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		Sequence:          42,
+		FirstPayloadHash:  first.Hex(),
+		FirstSignature:    hexutil.Encode(sign(t, key, testChainID, 42, first)),
+		SecondPayloadHash: second.Hex(),
+		SecondSignature:   hexutil.Encode(sign(t, key, testChainID, 42, second)),
+	}
+}
+
+func TestMisbehaviorVerify(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	require.NoError(t, newMisbehavior(t, key).Verify())
+
+	testCases := []struct {
+		name   string
+		modify func(m *types.Misbehavior)
+	}{
+		{"no chain id", func(m *types.Misbehavior) { m.ChainID = "" }},
+		{"other chain", func(m *types.Misbehavior) { m.ChainID = "tacchain_2390-1" }},
+		{"other sequence", func(m *types.Misbehavior) { m.Sequence++ }},
+		{"invalid relayer", func(m *types.Misbehavior) { m.Relayer = "tac1xyz" }},
+		{"other relayer", func(m *types.Misbehavior) { m.Relayer = crypto.PubkeyToAddress(other.PublicKey).Hex() }},
+		{"same payload", func(m *types.Misbehavior) {
+			m.SecondPayloadHash, m.SecondSignature = m.FirstPayloadHash, m.FirstSignature
+		}},
+		{"short payload hash", func(m *types.Misbehavior) { m.FirstPayloadHash = "0x1234" }},
+		{"invalid signature", func(m *types.Misbehavior) { m.SecondSignature = "0x1234" }},
+		{"signed by another key", func(m *types.Misbehavior) {
+			second := common.HexToHash(m.SecondPayloadHash)
+			m.SecondSignature = hexutil.Encode(sign(t, other, testChainID, m.Sequence, second))
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMisbehavior(t, key)
+			tc.modify(&m)
+			require.Error(t, m.Verify())
+		})
+	}
+}
+
+func TestParamsValidate(t *testing.T) {
+	require.NoError(t, types.DefaultParams().Validate())
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	relayer := crypto.PubkeyToAddress(key.PublicKey)
+
+	testCases := []struct {
+		name   string
+		modify func(p *types.Params)
+		valid  bool
+	}{
+		{"relayers", func(p *types.Params) { p.Relayers = []string{relayer.Hex(), common.HexToAddress("0x01").Hex()} }, true},
+		{"invalid relayer", func(p *types.Params) { p.Relayers = []string{"tac1xyz"} }, false},
+		{"zero relayer", func(p *types.Params) { p.Relayers = []string{common.Address{}.Hex()} }, false},
+		{"duplicate relayer", func(p *types.Params) { p.Relayers = []string{relayer.Hex(), strings.ToLower(relayer.Hex())} }, false},
+		{"negative min bond", func(p *types.Params) { p.MinRelayerBond = sdkmath.NewInt(-1) }, false},
+		{"half threshold", func(p *types.Params) { p.AttestationThreshold = sdkmath.LegacyNewDecWithPrec(5, 1) }, true},
+		{"threshold below half", func(p *types.Params) { p.AttestationThreshold = sdkmath.LegacyNewDecWithPrec(49, 2) }, false},
+		{"unanimity threshold", func(p *types.Params) { p.AttestationThreshold = sdkmath.LegacyOneDec() }, false},
+		{"no slashing", func(p *types.Params) { p.SlashFraction = sdkmath.LegacyZeroDec() }, true},
+		{"slash fraction above one", func(p *types.Params) { p.SlashFraction = sdkmath.LegacyNewDec(2) }, false},
+		{"misbehavior", func(p *types.Params) { p.Misbehavior = []types.Misbehavior{newMisbehavior(t, key)} }, true},
+		{"duplicate misbehavior", func(p *types.Params) {
+			p.Misbehavior = []types.Misbehavior{newMisbehavior(t, key), newMisbehavior(t, key)}
+		}, false},
+		{"unverified misbehavior", func(p *types.Params) {
+			m := newMisbehavior(t, key)
+			m.Sequence++
+			p.Misbehavior = []types.Misbehavior{m}
+		}, false},
+		{"zero withdrawal delay", func(p *types.Params) { p.WithdrawalDelay = 0 }, false},
+		{"withdrawal rulings", func(p *types.Params) {
+			p.WithdrawalRulings = []types.WithdrawalRuling{{ID: 1, Release: true}, {ID: 2}}
+		}, true},
+		{"duplicate withdrawal ruling", func(p *types.Params) {
+			p.WithdrawalRulings = []types.WithdrawalRuling{{ID: 1, Release: true}, {ID: 1}}
+		}, false},
+		{"zero deposit gas", func(p *types.Params) { p.DepositGas = 0 }, false},
+		{"zero withdrawal gas", func(p *types.Params) { p.WithdrawalGas = 0 }, false},
+		{"negative relayer fee base", func(p *types.Params) { p.RelayerFeeBase = sdkmath.NewInt(-1) }, false},
+		{"free relaying", func(p *types.Params) { p.RelayerFeeRate = sdkmath.LegacyZeroDec() }, true},
+		{"relayer fee rate of one", func(p *types.Params) { p.RelayerFeeRate = sdkmath.LegacyOneDec() }, false},
+		{"negative TON fee", func(p *types.Params) { p.TONFee = sdkmath.NewInt(-1) }, false},
+		{"negative TON price", func(p *types.Params) { p.TONPrice = sdkmath.LegacyNewDec(-1) }, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := types.DefaultParams()
+			tc.modify(&params)
+			err := params.Validate()
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestHasQuorum(t *testing.T) {
+	params := types.DefaultParams()
+	params.AttestationThreshold = sdkmath.LegacyNewDecWithPrec(7, 1)
+
+	require.False(t, params.HasQuorum(sdkmath.ZeroInt(), sdkmath.ZeroInt()), "no power, no quorum")
+	require.False(t, params.HasQuorum(sdkmath.NewInt(70), sdkmath.NewInt(100)), "the threshold must be exceeded")
+	require.True(t, params.HasQuorum(sdkmath.NewInt(71), sdkmath.NewInt(100)))
+	require.True(t, params.HasQuorum(sdkmath.NewInt(100), sdkmath.NewInt(100)))
+	require.False(t, params.HasQuorum(sdkmath.NewInt(6), sdkmath.NewInt(9)))
+	require.True(t, params.HasQuorum(sdkmath.NewInt(7), sdkmath.NewInt(9)))
+}
+
+func TestRelayerPower(t *testing.T) {
+	relayer := common.HexToAddress("0x01").Hex()
+	minBond := sdkmath.NewInt(100)
+
+	power := types.NewRelayerPower(relayer, sdkmath.NewInt(99), minBond)
+	require.False(t, power.Active)
+	require.True(t, power.Power().IsZero())
+
+	power = types.NewRelayerPower(relayer, sdkmath.NewInt(100), minBond)
+	require.True(t, power.Active)
+	require.Equal(t, int64(100), power.Power().Int64())
+
+	// without min bond, a relayer still needs a bond to attest
+	require.False(t, types.NewRelayerPower(relayer, sdkmath.ZeroInt(), sdkmath.ZeroInt()).Active)
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	errorsmod "cosmossdk.io/errors"
+	sdkmath "cosmossdk.io/math"
+)
+
+// depositPayload is the ABI encoding of the payload of a deposit, its hash is
+// what relayers attest under the sequence of the deposit
+var depositPayload = func() abi.Arguments {
+	stringType, err := abi.NewType("string", "", nil)
+	if err != nil {
+		panic(err)
+	}
+	addressType, err := abi.NewType("address", "", nil)
+	if err != nil {
+		panic(err)
+	}
+	uintType, err := abi.NewType("uint256", "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return abi.Arguments{{Type: stringType}, {Type: addressType}, {Type: uintType}}
+}()
+
+// Deposit is a transfer from TON to tacchain, carried by the bridge message
+// with Sequence. Jetton is empty for the EVM denom coming back from TON,
+// where withdrawals burnt it, Amount then being in the EVM denom. Otherwise
+// Jetton is a registered asset and Amount is in its decimals on TON. The
+// deposit is executed once the relayers holding a quorum of the relayer power
+// attested it.
+type Deposit struct {
+	Sequence  uint64         `json:"sequence" yaml:"sequence"`
+	Jetton    string         `json:"jetton,omitempty" yaml:"jetton,omitempty"`
+	Recipient common.Address `json:"recipient" yaml:"recipient"`
+	Amount    sdkmath.Int    `json:"amount" yaml:"amount"`
+}
+
+// PayloadHash returns the hash relayers attest for the deposit, the keccak256
+// of abi.encode(jetton, recipient, amount)
+func (d Deposit) PayloadHash() common.Hash {
+	bz, err := depositPayload.Pack(d.Jetton, d.Recipient, d.Amount.BigInt())
+	if err != nil {
+		panic(err)
+	}
+	return crypto.Keccak256Hash(bz)
+}
+
+// Validate performs basic validation of a deposit
+func (d Deposit) Validate() error {
+	if d.Sequence == 0 {
+		return errorsmod.Wrap(ErrInvalidDeposit, "sequence must be positive")
+	}
+	if d.Recipient == (common.Address{}) {
+		return errorsmod.Wrap(ErrInvalidDeposit, "missing recipient")
+	}
+	if d.Amount.IsNil() || !d.Amount.IsPositive() {
+		return errorsmod.Wrapf(ErrInvalidDeposit, "invalid amount: %s", d.Amount)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+)
+
+// x/bridge module sentinel errors
+var (
+	ErrInvalidSignature   = errorsmod.Register(ModuleName, 2, "invalid attestation signature")
+	ErrUnknownRelayer     = errorsmod.Register(ModuleName, 3, "signer isn't an active relayer")
+	ErrDuplicateSignature = errorsmod.Register(ModuleName, 4, "relayer signed the attestation twice")
+	ErrNoQuorum           = errorsmod.Register(ModuleName, 5, "attestation power below the quorum")
+	ErrInvalidMisbehavior = errorsmod.Register(ModuleName, 6, "invalid relayer misbehavior")
+	ErrAlreadySlashed     = errorsmod.Register(ModuleName, 7, "relayer already slashed for the sequence")
+	ErrWrongChainID       = errorsmod.Register(ModuleName, 8, "misbehavior signed for another chain")
+	ErrInvalidBridgeCall  = errorsmod.Register(ModuleName, 9, "invalid bridge call")
+	ErrInvalidWithdrawal  = errorsmod.Register(ModuleName, 10, "invalid withdrawal")
+	ErrUnknownWithdrawal  = errorsmod.Register(ModuleName, 11, "unknown withdrawal")
+	ErrChallengeClosed    = errorsmod.Register(ModuleName, 12, "withdrawal can't be challenged")
+	ErrInvalidAsset       = errorsmod.Register(ModuleName, 13, "invalid bridge asset")
+	ErrUnknownAsset       = errorsmod.Register(ModuleName, 14, "unknown bridge asset")
+	ErrInexactAmount      = errorsmod.Register(ModuleName, 15, "amount not representable on TON")
+	ErrInvalidFeeQuote    = errorsmod.Register(ModuleName, 16, "invalid fee quote request")
+	ErrInvalidBond        = errorsmod.Register(ModuleName, 17, "invalid relayer bond")
+	ErrInvalidDeposit     = errorsmod.Register(ModuleName, 18, "invalid deposit")
+	ErrDuplicateDeposit   = errorsmod.Register(ModuleName, 19, "deposit already executed")
+)
@@ -0,0 +1,123 @@
+package types
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// MethodWithdraw queues a withdrawal of the value of the call to a TON address
+	MethodWithdraw = "withdraw"
+	// MethodChallenge freezes a queued withdrawal until governance rules on it
+	MethodChallenge = "challenge"
+	// MethodBond adds the value of the call to the bond of the relayer sending it
+	MethodBond = "bond"
+	// MethodDeposit executes a deposit from TON attested by the relayers
+	MethodDeposit = "deposit"
+	// MethodWithdrawAsset burns an amount of the denom of a registered asset
+	// from the sender as it is released to a TON address
+	MethodWithdrawAsset = "withdrawAsset"
+)
+
+// escrowABI is the interface of the calls sent to EscrowAddress
+const escrowABI = `[
+	{"type":"function","name":"withdraw","stateMutability":"payable","outputs":[],"inputs":[
+		{"name":"recipient","type":"string"}
+	]},
+	{"type":"function","name":"challenge","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"id","type":"uint64"}
+	]},
+	{"type":"function","name":"bond","stateMutability":"payable","outputs":[],"inputs":[]},
+	{"type":"function","name":"deposit","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"sequence","type":"uint64"},
+		{"name":"jetton","type":"string"},
+		{"name":"recipient","type":"address"},
+		{"name":"amount","type":"uint256"},
+		{"name":"signatures","type":"bytes[]"}
+	]},
+	{"type":"function","name":"withdrawAsset","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"recipient","type":"string"},
+		{"name":"denom","type":"string"},
+		{"name":"amount","type":"uint256"}
+	]}
+]`
+
+// EscrowABI is the parsed interface of the escrow calls
+var EscrowABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(escrowABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// EscrowCall is a decoded call to the escrow. Recipient is set for
+// withdrawals, along with Amount for asset withdrawals, ID for challenges,
+// Deposit and Signatures for deposits, bonds have no arguments.
+type EscrowCall struct {
+	Method     string
+	Recipient  string
+	Amount     sdk.Coin
+	ID         uint64
+	Deposit    Deposit
+	Signatures [][]byte
+}
+
+// ParseEscrowCall decodes the calldata of an escrow call
+func ParseEscrowCall(data []byte) (EscrowCall, error) {
+	if len(data) < 4 {
+		return EscrowCall{}, errorsmod.Wrap(ErrInvalidBridgeCall, "missing method selector")
+	}
+	method, err := EscrowABI.MethodById(data[:4])
+	if err != nil {
+		return EscrowCall{}, errorsmod.Wrap(ErrInvalidBridgeCall, err.Error())
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return EscrowCall{}, errorsmod.Wrapf(ErrInvalidBridgeCall, "failed to decode %s arguments: %s", method.Name, err)
+	}
+
+	call := EscrowCall{Method: method.Name}
+	switch method.Name {
+	case MethodChallenge:
+		call.ID = args[0].(uint64)
+	case MethodWithdraw:
+		call.Recipient = args[0].(string)
+	case MethodDeposit:
+		call.Deposit = Deposit{
+			Sequence:  args[0].(uint64),
+			Jetton:    args[1].(string),
+			Recipient: args[2].(common.Address),
+			Amount:    sdkmath.NewIntFromBigInt(args[3].(*big.Int)),
+		}
+		call.Signatures = args[4].([][]byte)
+	case MethodWithdrawAsset:
+		call.Recipient = args[0].(string)
+		call.Amount = sdk.Coin{Denom: args[1].(string), Amount: sdkmath.NewIntFromBigInt(args[2].(*big.Int))}
+	}
+	return call, nil
+}
+
+// Pack encodes the call as calldata for the escrow
+func (c EscrowCall) Pack() ([]byte, error) {
+	switch c.Method {
+	case MethodChallenge:
+		return EscrowABI.Pack(MethodChallenge, c.ID)
+	case MethodBond:
+		return EscrowABI.Pack(MethodBond)
+	case MethodDeposit:
+		d := c.Deposit
+		return EscrowABI.Pack(MethodDeposit, d.Sequence, d.Jetton, d.Recipient, d.Amount.BigInt(), c.Signatures)
+	case MethodWithdrawAsset:
+		return EscrowABI.Pack(MethodWithdrawAsset, c.Recipient, c.Amount.Denom, c.Amount.Amount.BigInt())
+	}
+	return EscrowABI.Pack(MethodWithdraw, c.Recipient)
+}
@@ -0,0 +1,186 @@
+package types
+
+import (
+	"github.com/cosmos/gogoproto/proto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// init registers the bridge events: the relayer bonds, the withdrawals
+// queued to TON and their challenges, the deposits and the asset registry.
+// TestEventsFixture checks them against the fixture of the TS client.
+func init() {
+	proto.RegisterType((*EventBondRelayer)(nil), "tacchain.bridge.v1.EventBondRelayer")
+	proto.RegisterType((*EventSlashRelayer)(nil), "tacchain.bridge.v1.EventSlashRelayer")
+	proto.RegisterType((*EventUnbondRelayer)(nil), "tacchain.bridge.v1.EventUnbondRelayer")
+	proto.RegisterType((*EventReleaseRelayerBond)(nil), "tacchain.bridge.v1.EventReleaseRelayerBond")
+	proto.RegisterType((*EventQueueWithdrawal)(nil), "tacchain.bridge.v1.EventQueueWithdrawal")
+	proto.RegisterType((*EventChallengeWithdrawal)(nil), "tacchain.bridge.v1.EventChallengeWithdrawal")
+	proto.RegisterType((*EventCompleteWithdrawal)(nil), "tacchain.bridge.v1.EventCompleteWithdrawal")
+	proto.RegisterType((*EventRefundWithdrawal)(nil), "tacchain.bridge.v1.EventRefundWithdrawal")
+	proto.RegisterType((*EventDeposit)(nil), "tacchain.bridge.v1.EventDeposit")
+	proto.RegisterType((*EventRegisterAsset)(nil), "tacchain.bridge.v1.EventRegisterAsset")
+	proto.RegisterType((*EventRemoveAsset)(nil), "tacchain.bridge.v1.EventRemoveAsset")
+	proto.RegisterType((*EventMintAsset)(nil), "tacchain.bridge.v1.EventMintAsset")
+	proto.RegisterType((*EventBurnAsset)(nil), "tacchain.bridge.v1.EventBurnAsset")
+}
+
+// EventBondRelayer is emitted when a relayer adds to its bond.
+type EventBondRelayer struct {
+	Relayer string   `protobuf:"bytes,1,opt,name=relayer,proto3" json:"relayer,omitempty"`
+	Amount  sdk.Coin `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount"`
+	Bond    sdk.Coin `protobuf:"bytes,3,opt,name=bond,proto3" json:"bond"`
+}
+
+func (m *EventBondRelayer) Reset()         { *m = EventBondRelayer{} }
+func (m *EventBondRelayer) String() string { return proto.CompactTextString(m) }
+func (*EventBondRelayer) ProtoMessage()    {}
+
+// EventSlashRelayer is emitted when a relayer is slashed for attesting
+// conflicting payloads for a sequence.
+type EventSlashRelayer struct {
+	Relayer  string    `protobuf:"bytes,1,opt,name=relayer,proto3" json:"relayer,omitempty"`
+	Sequence uint64    `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Slashed  sdk.Coins `protobuf:"bytes,3,rep,name=slashed,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins" json:"slashed"`
+}
+
+func (m *EventSlashRelayer) Reset()         { *m = EventSlashRelayer{} }
+func (m *EventSlashRelayer) String() string { return proto.CompactTextString(m) }
+func (*EventSlashRelayer) ProtoMessage()    {}
+
+// EventUnbondRelayer is emitted when a relayer left the set, its bond is
+// released at ReleaseHeight.
+type EventUnbondRelayer struct {
+	Relayer       string `protobuf:"bytes,1,opt,name=relayer,proto3" json:"relayer,omitempty"`
+	ReleaseHeight int64  `protobuf:"varint,2,opt,name=release_height,json=releaseHeight,proto3" json:"release_height,omitempty"`
+}
+
+func (m *EventUnbondRelayer) Reset()         { *m = EventUnbondRelayer{} }
+func (m *EventUnbondRelayer) String() string { return proto.CompactTextString(m) }
+func (*EventUnbondRelayer) ProtoMessage()    {}
+
+// EventReleaseRelayerBond is emitted when the bond of a removed relayer is
+// sent back to it.
+type EventReleaseRelayerBond struct {
+	Relayer string    `protobuf:"bytes,1,opt,name=relayer,proto3" json:"relayer,omitempty"`
+	Amount  sdk.Coins `protobuf:"bytes,2,rep,name=amount,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins" json:"amount"`
+}
+
+func (m *EventReleaseRelayerBond) Reset()         { *m = EventReleaseRelayerBond{} }
+func (m *EventReleaseRelayerBond) String() string { return proto.CompactTextString(m) }
+func (*EventReleaseRelayerBond) ProtoMessage()    {}
+
+// EventQueueWithdrawal is emitted when an account withdraws to TON, the
+// withdrawal completes at CompleteHeight unless it is challenged.
+type EventQueueWithdrawal struct {
+	Id             uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Sender         string   `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	Recipient      string   `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Amount         sdk.Coin `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount"`
+	CompleteHeight int64    `protobuf:"varint,5,opt,name=complete_height,json=completeHeight,proto3" json:"complete_height,omitempty"`
+}
+
+func (m *EventQueueWithdrawal) Reset()         { *m = EventQueueWithdrawal{} }
+func (m *EventQueueWithdrawal) String() string { return proto.CompactTextString(m) }
+func (*EventQueueWithdrawal) ProtoMessage()    {}
+
+// EventChallengeWithdrawal is emitted when a relayer challenges a queued
+// withdrawal, which waits for a ruling of governance.
+type EventChallengeWithdrawal struct {
+	Id         uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Challenger string `protobuf:"bytes,2,opt,name=challenger,proto3" json:"challenger,omitempty"`
+}
+
+func (m *EventChallengeWithdrawal) Reset()         { *m = EventChallengeWithdrawal{} }
+func (m *EventChallengeWithdrawal) String() string { return proto.CompactTextString(m) }
+func (*EventChallengeWithdrawal) ProtoMessage()    {}
+
+// EventCompleteWithdrawal is emitted when a withdrawal completes, the relayers
+// then release the amount to the recipient on TON.
+type EventCompleteWithdrawal struct {
+	Id        uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Sender    string   `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	Recipient string   `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Amount    sdk.Coin `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount"`
+}
+
+func (m *EventCompleteWithdrawal) Reset()         { *m = EventCompleteWithdrawal{} }
+func (m *EventCompleteWithdrawal) String() string { return proto.CompactTextString(m) }
+func (*EventCompleteWithdrawal) ProtoMessage()    {}
+
+// EventRefundWithdrawal is emitted when governance rejects a challenged
+// withdrawal and the amount is sent back to the sender.
+type EventRefundWithdrawal struct {
+	Id     uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Sender string   `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	Amount sdk.Coin `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount"`
+}
+
+func (m *EventRefundWithdrawal) Reset()         { *m = EventRefundWithdrawal{} }
+func (m *EventRefundWithdrawal) String() string { return proto.CompactTextString(m) }
+func (*EventRefundWithdrawal) ProtoMessage()    {}
+
+// EventDeposit is emitted when the bridge executes a deposit from TON the
+// relayers attested.
+type EventDeposit struct {
+	Sequence  uint64   `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Jetton    string   `protobuf:"bytes,2,opt,name=jetton,proto3" json:"jetton,omitempty"`
+	Recipient string   `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Amount    sdk.Coin `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount"`
+	Signers   []string `protobuf:"bytes,5,rep,name=signers,proto3" json:"signers,omitempty"`
+}
+
+func (m *EventDeposit) Reset()         { *m = EventDeposit{} }
+func (m *EventDeposit) String() string { return proto.CompactTextString(m) }
+func (*EventDeposit) ProtoMessage()    {}
+
+// EventRegisterAsset is emitted when governance adds an asset to the registry
+// or changes it.
+type EventRegisterAsset struct {
+	Jetton      string `protobuf:"bytes,1,opt,name=jetton,proto3" json:"jetton,omitempty"`
+	Denom       string `protobuf:"bytes,2,opt,name=denom,proto3" json:"denom,omitempty"`
+	TonDecimals uint32 `protobuf:"varint,3,opt,name=ton_decimals,json=tonDecimals,proto3" json:"ton_decimals,omitempty"`
+	Decimals    uint32 `protobuf:"varint,4,opt,name=decimals,proto3" json:"decimals,omitempty"`
+}
+
+func (m *EventRegisterAsset) Reset()         { *m = EventRegisterAsset{} }
+func (m *EventRegisterAsset) String() string { return proto.CompactTextString(m) }
+func (*EventRegisterAsset) ProtoMessage()    {}
+
+// EventRemoveAsset is emitted when governance removes an asset from the
+// registry.
+type EventRemoveAsset struct {
+	Jetton string `protobuf:"bytes,1,opt,name=jetton,proto3" json:"jetton,omitempty"`
+	Denom  string `protobuf:"bytes,2,opt,name=denom,proto3" json:"denom,omitempty"`
+}
+
+func (m *EventRemoveAsset) Reset()         { *m = EventRemoveAsset{} }
+func (m *EventRemoveAsset) String() string { return proto.CompactTextString(m) }
+func (*EventRemoveAsset) ProtoMessage()    {}
+
+// EventMintAsset is emitted when the bridge mints the denom of an asset
+// deposited on TON.
+type EventMintAsset struct {
+	Jetton    string   `protobuf:"bytes,1,opt,name=jetton,proto3" json:"jetton,omitempty"`
+	Recipient string   `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	TonAmount string   `protobuf:"bytes,3,opt,name=ton_amount,json=tonAmount,proto3" json:"ton_amount,omitempty"`
+	Amount    sdk.Coin `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount"`
+}
+
+func (m *EventMintAsset) Reset()         { *m = EventMintAsset{} }
+func (m *EventMintAsset) String() string { return proto.CompactTextString(m) }
+func (*EventMintAsset) ProtoMessage()    {}
+
+// EventBurnAsset is emitted when the bridge burns the denom of an asset
+// released on TON to Recipient.
+type EventBurnAsset struct {
+	Jetton    string   `protobuf:"bytes,1,opt,name=jetton,proto3" json:"jetton,omitempty"`
+	Sender    string   `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	Amount    sdk.Coin `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount"`
+	TonAmount string   `protobuf:"bytes,4,opt,name=ton_amount,json=tonAmount,proto3" json:"ton_amount,omitempty"`
+	Recipient string   `protobuf:"bytes,5,opt,name=recipient,proto3" json:"recipient,omitempty"`
+}
+
+func (m *EventBurnAsset) Reset()         { *m = EventBurnAsset{} }
+func (m *EventBurnAsset) String() string { return proto.CompactTextString(m) }
+func (*EventBurnAsset) ProtoMessage()    {}
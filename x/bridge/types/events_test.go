@@ -0,0 +1,59 @@
+package types_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+// eventsFixture holds bridge events as the node emits them, the TypeScript
+// client decodes the same file
+const eventsFixture = "../../../client/ts/test/fixtures/bridge_events.json"
+
+func TestEventsFixture(t *testing.T) {
+	bz, err := os.ReadFile(eventsFixture)
+	require.NoError(t, err)
+	var events []abci.Event
+	require.NoError(t, json.Unmarshal(bz, &events))
+
+	seen := map[string]bool{}
+	for _, event := range events {
+		msg, err := sdk.ParseTypedEvent(event)
+		require.NoError(t, err, event.Type)
+
+		// the node emits the same attributes for the decoded event
+		emitted, err := sdk.TypedEventToEvent(msg)
+		require.NoError(t, err, event.Type)
+		require.Equal(t, event.Type, emitted.Type)
+		require.Equal(t, attributeValues(t, event), attributeValues(t, abci.Event(emitted)), event.Type)
+		seen[event.Type] = true
+	}
+
+	for _, msg := range []proto.Message{
+		&types.EventBondRelayer{}, &types.EventSlashRelayer{}, &types.EventUnbondRelayer{}, &types.EventReleaseRelayerBond{},
+		&types.EventQueueWithdrawal{}, &types.EventChallengeWithdrawal{}, &types.EventCompleteWithdrawal{}, &types.EventRefundWithdrawal{},
+		&types.EventDeposit{},
+		&types.EventRegisterAsset{}, &types.EventRemoveAsset{}, &types.EventMintAsset{}, &types.EventBurnAsset{},
+	} {
+		require.True(t, seen[proto.MessageName(msg)], "No %s in the fixture", proto.MessageName(msg))
+	}
+}
+
+// attributeValues returns the decoded JSON values of the attributes of event
+func attributeValues(t *testing.T, event abci.Event) map[string]any {
+	values := map[string]any{}
+	for _, attr := range event.Attributes {
+		var value any
+		require.NoError(t, json.Unmarshal([]byte(attr.Value), &value), attr.Key)
+		values[attr.Key] = value
+	}
+	return values
+}
@@ -0,0 +1,59 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+	sdkmath "cosmossdk.io/math"
+)
+
+const (
+	// FeeDirectionDeposit quotes the fees of bringing an amount from TON
+	FeeDirectionDeposit = "deposit"
+	// FeeDirectionWithdrawal quotes the fees of sending an amount to TON
+	FeeDirectionWithdrawal = "withdrawal"
+)
+
+// FeeQuote is the estimated cost of a bridge transfer of Amount, every fee
+// is in the bond denom and rounded up.
+//
+// LocalGas is the gas the user pays on tacchain, DestinationFee the cost of
+// delivering the transfer on the other side: the TON network fees of a
+// withdrawal, or the gas relayers spend executing a deposit on tacchain.
+type FeeQuote struct {
+	Direction      string            `json:"direction" yaml:"direction"`
+	Amount         sdkmath.Int       `json:"amount" yaml:"amount"`
+	GasPrice       sdkmath.LegacyDec `json:"gas_price" yaml:"gas_price"`
+	LocalGas       sdkmath.Int       `json:"local_gas" yaml:"local_gas"`
+	RelayerFee     sdkmath.Int       `json:"relayer_fee" yaml:"relayer_fee"`
+	DestinationFee sdkmath.Int       `json:"destination_fee" yaml:"destination_fee"`
+	Total          sdkmath.Int       `json:"total" yaml:"total"`
+}
+
+// QuoteFee estimates the fees of a transfer of amount in direction at
+// gasPrice, the price of a unit of gas on tacchain.
+func (p Params) QuoteFee(direction string, amount sdkmath.Int, gasPrice sdkmath.LegacyDec) (FeeQuote, error) {
+	if amount.IsNil() || amount.IsNegative() {
+		return FeeQuote{}, errorsmod.Wrapf(ErrInvalidFeeQuote, "negative amount: %s", amount)
+	}
+	if gasPrice.IsNil() || gasPrice.IsNegative() {
+		return FeeQuote{}, errorsmod.Wrapf(ErrInvalidFeeQuote, "negative gas price: %s", gasPrice)
+	}
+
+	quote := FeeQuote{
+		Direction:  direction,
+		Amount:     amount,
+		GasPrice:   gasPrice,
+		RelayerFee: p.RelayerFeeBase.Add(p.RelayerFeeRate.MulInt(amount).Ceil().TruncateInt()),
+	}
+	switch direction {
+	case FeeDirectionDeposit:
+		quote.LocalGas = sdkmath.ZeroInt()
+		quote.DestinationFee = gasPrice.MulInt(sdkmath.NewIntFromUint64(p.DepositGas)).Ceil().TruncateInt()
+	case FeeDirectionWithdrawal:
+		quote.LocalGas = gasPrice.MulInt(sdkmath.NewIntFromUint64(p.WithdrawalGas)).Ceil().TruncateInt()
+		quote.DestinationFee = p.TONPrice.MulInt(p.TONFee).Ceil().TruncateInt()
+	default:
+		return FeeQuote{}, errorsmod.Wrapf(ErrInvalidFeeQuote, "direction must be %q or %q: %q", FeeDirectionDeposit, FeeDirectionWithdrawal, direction)
+	}
+	quote.Total = quote.LocalGas.Add(quote.RelayerFee).Add(quote.DestinationFee)
+	return quote, nil
+}
@@ -0,0 +1,82 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+func TestQuoteFee(t *testing.T) {
+	amount := sdkmath.NewInt(1_000_000)
+	gasPrice := sdkmath.LegacyNewDec(10)
+
+	testCases := []struct {
+		name      string
+		modify    func(p *types.Params)
+		direction string
+		// local gas, relayer fee and destination fee
+		expected [3]int64
+	}{
+		{"default withdrawal", func(*types.Params) {}, types.FeeDirectionWithdrawal, [3]int64{1_000_000, 1_000, 0}},
+		{"default deposit", func(*types.Params) {}, types.FeeDirectionDeposit, [3]int64{0, 1_000, 2_000_000}},
+		{"priced TON", func(p *types.Params) {
+			p.TONPrice = sdkmath.LegacyNewDecWithPrec(3, 2)
+		}, types.FeeDirectionWithdrawal, [3]int64{1_000_000, 1_000, 1_500_000}},
+		{"TON price rounded up", func(p *types.Params) {
+			p.TONFee = sdkmath.NewInt(3)
+			p.TONPrice = sdkmath.LegacyNewDecWithPrec(1, 1)
+		}, types.FeeDirectionWithdrawal, [3]int64{1_000_000, 1_000, 1}},
+		{"TON price ignored by deposits", func(p *types.Params) {
+			p.TONPrice = sdkmath.LegacyNewDec(1)
+		}, types.FeeDirectionDeposit, [3]int64{0, 1_000, 2_000_000}},
+		{"flat relayer fee", func(p *types.Params) {
+			p.RelayerFeeBase = sdkmath.NewInt(500)
+			p.RelayerFeeRate = sdkmath.LegacyZeroDec()
+		}, types.FeeDirectionDeposit, [3]int64{0, 500, 2_000_000}},
+		{"relayer fee rate rounded up", func(p *types.Params) {
+			p.RelayerFeeBase = sdkmath.NewInt(500)
+			p.RelayerFeeRate = sdkmath.LegacyNewDecWithPrec(3, 7)
+		}, types.FeeDirectionWithdrawal, [3]int64{1_000_000, 501, 0}},
+		{"more gas", func(p *types.Params) {
+			p.DepositGas = 300_000
+			p.WithdrawalGas = 50_000
+		}, types.FeeDirectionWithdrawal, [3]int64{500_000, 1_000, 0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := types.DefaultParams()
+			tc.modify(&params)
+			require.NoError(t, params.Validate())
+
+			quote, err := params.QuoteFee(tc.direction, amount, gasPrice)
+			require.NoError(t, err)
+			require.Equal(t, tc.direction, quote.Direction)
+			require.Equal(t, amount, quote.Amount)
+			require.Equal(t, tc.expected[0], quote.LocalGas.Int64(), "local gas")
+			require.Equal(t, tc.expected[1], quote.RelayerFee.Int64(), "relayer fee")
+			require.Equal(t, tc.expected[2], quote.DestinationFee.Int64(), "destination fee")
+			require.Equal(t, tc.expected[0]+tc.expected[1]+tc.expected[2], quote.Total.Int64())
+		})
+	}
+}
+
+func TestQuoteFeeInvalid(t *testing.T) {
+	params := types.DefaultParams()
+	_, err := params.QuoteFee("transfer", sdkmath.NewInt(1), sdkmath.LegacyOneDec())
+	require.ErrorIs(t, err, types.ErrInvalidFeeQuote)
+	_, err = params.QuoteFee(types.FeeDirectionDeposit, sdkmath.NewInt(-1), sdkmath.LegacyOneDec())
+	require.ErrorIs(t, err, types.ErrInvalidFeeQuote)
+	_, err = params.QuoteFee(types.FeeDirectionDeposit, sdkmath.NewInt(1), sdkmath.LegacyNewDec(-1))
+	require.ErrorIs(t, err, types.ErrInvalidFeeQuote)
+
+	// a zero amount still pays for gas and the flat relayer fee
+	params.RelayerFeeBase = sdkmath.NewInt(7)
+	quote, err := params.QuoteFee(types.FeeDirectionWithdrawal, sdkmath.ZeroInt(), sdkmath.LegacyOneDec())
+	require.NoError(t, err)
+	require.Equal(t, int64(100_007), quote.Total.Int64())
+}
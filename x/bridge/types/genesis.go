@@ -0,0 +1,132 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	sdkmath "cosmossdk.io/math"
+)
+
+// Bond is the bond of a relayer held by the module account
+type Bond struct {
+	Relayer string      `json:"relayer" yaml:"relayer"`
+	Amount  sdkmath.Int `json:"amount" yaml:"amount"`
+}
+
+// Unbonding is the bond of a relayer removed from the set, released at Height
+type Unbonding struct {
+	Relayer string `json:"relayer" yaml:"relayer"`
+	Height  int64  `json:"height" yaml:"height"`
+}
+
+// Offense is a sequence a relayer was slashed for
+type Offense struct {
+	Relayer  string `json:"relayer" yaml:"relayer"`
+	Sequence uint64 `json:"sequence" yaml:"sequence"`
+}
+
+// GenesisState defines the bridge module genesis state
+type GenesisState struct {
+	Params Params `json:"params" yaml:"params"`
+	// Relayers is the relayer set applied at the end of the last block
+	Relayers   []string    `json:"relayers" yaml:"relayers"`
+	Bonds      []Bond      `json:"bonds" yaml:"bonds"`
+	Unbondings []Unbonding `json:"unbondings" yaml:"unbondings"`
+	Offenses   []Offense   `json:"offenses" yaml:"offenses"`
+	// Withdrawals are the queued and challenged withdrawals
+	Withdrawals      []Withdrawal `json:"withdrawals" yaml:"withdrawals"`
+	NextWithdrawalID uint64       `json:"next_withdrawal_id" yaml:"next_withdrawal_id"`
+	// Assets are the registered TON assets
+	Assets []Asset `json:"assets" yaml:"assets"`
+	// Deposits are the sequences of the executed deposits
+	Deposits []uint64 `json:"deposits" yaml:"deposits"`
+}
+
+// DefaultGenesisState returns the default bridge module genesis state
+func DefaultGenesisState() *GenesisState {
+	return &GenesisState{
+		Params:           DefaultParams(),
+		Relayers:         []string{},
+		Bonds:            []Bond{},
+		Unbondings:       []Unbonding{},
+		Offenses:         []Offense{},
+		Withdrawals:      []Withdrawal{},
+		NextWithdrawalID: 1,
+		Assets:           []Asset{},
+		Deposits:         []uint64{},
+	}
+}
+
+// Validate performs basic genesis state validation
+func (gs GenesisState) Validate() error {
+	if err := gs.Params.Validate(); err != nil {
+		return err
+	}
+	if err := validateRelayers(gs.Relayers); err != nil {
+		return err
+	}
+
+	bonded := make(map[common.Address]bool, len(gs.Bonds))
+	for _, bond := range gs.Bonds {
+		if !common.IsHexAddress(bond.Relayer) {
+			return fmt.Errorf("invalid bond relayer address: %q", bond.Relayer)
+		}
+		if bond.Amount.IsNil() || !bond.Amount.IsPositive() {
+			return fmt.Errorf("bond of %s must be positive: %s", bond.Relayer, bond.Amount)
+		}
+		if bonded[common.HexToAddress(bond.Relayer)] {
+			return fmt.Errorf("duplicate bond of %s", bond.Relayer)
+		}
+		bonded[common.HexToAddress(bond.Relayer)] = true
+	}
+
+	seen := make(map[common.Address]bool, len(gs.Unbondings))
+	for _, unbonding := range gs.Unbondings {
+		if !common.IsHexAddress(unbonding.Relayer) {
+			return fmt.Errorf("invalid unbonding relayer address: %q", unbonding.Relayer)
+		}
+		if unbonding.Height <= 0 {
+			return fmt.Errorf("invalid release height of %s: %d", unbonding.Relayer, unbonding.Height)
+		}
+		if seen[common.HexToAddress(unbonding.Relayer)] {
+			return fmt.Errorf("duplicate unbonding of %s", unbonding.Relayer)
+		}
+		seen[common.HexToAddress(unbonding.Relayer)] = true
+	}
+
+	for _, offense := range gs.Offenses {
+		if !common.IsHexAddress(offense.Relayer) {
+			return fmt.Errorf("invalid offense relayer address: %q", offense.Relayer)
+		}
+	}
+
+	ids := make(map[uint64]bool, len(gs.Withdrawals))
+	for _, withdrawal := range gs.Withdrawals {
+		if err := withdrawal.Validate(); err != nil {
+			return fmt.Errorf("withdrawal %d: %w", withdrawal.ID, err)
+		}
+		if withdrawal.ID >= gs.NextWithdrawalID {
+			return fmt.Errorf("withdrawal id %d isn't below the next withdrawal id %d", withdrawal.ID, gs.NextWithdrawalID)
+		}
+		if ids[withdrawal.ID] {
+			return fmt.Errorf("duplicate withdrawal %d", withdrawal.ID)
+		}
+		ids[withdrawal.ID] = true
+	}
+
+	deposited := make(map[uint64]bool, len(gs.Deposits))
+	for _, sequence := range gs.Deposits {
+		if sequence == 0 || deposited[sequence] {
+			return fmt.Errorf("invalid or duplicate deposit sequence %d", sequence)
+		}
+		deposited[sequence] = true
+	}
+
+	for _, asset := range gs.Assets {
+		if asset.Jetton != NormalizeJetton(asset.Jetton) {
+			return fmt.Errorf("jetton of asset %s must be lowercase: %s", asset.Denom, asset.Jetton)
+		}
+	}
+	return validateAssetList(gs.Assets)
+}
@@ -0,0 +1,123 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/address"
+)
+
+const (
+	// ModuleName defines the bridge module name
+	ModuleName = "bridge"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+
+	// MaxReleasedBondsPerBlock bounds the relayer bonds released in a block
+	MaxReleasedBondsPerBlock = 100
+
+	// MaxCompletedWithdrawalsPerBlock bounds the withdrawals completed in a block
+	MaxCompletedWithdrawalsPerBlock = 100
+)
+
+var (
+	// RelayerPrefix prefixes the relayers of the set applied at the end of the
+	// last block, so the module notices the relayers governance removed
+	RelayerPrefix = []byte{0x01}
+	// UnbondingPrefix prefixes the height the bond of a removed relayer is released at
+	UnbondingPrefix = []byte{0x02}
+	// UnbondingQueuePrefix indexes the unbonding relayers by release height
+	UnbondingQueuePrefix = []byte{0x03}
+	// SlashedPrefix marks the sequences a relayer was slashed for, so the same
+	// misbehavior isn't punished twice
+	SlashedPrefix = []byte{0x04}
+	// WithdrawalPrefix prefixes the queued and challenged withdrawals by id
+	WithdrawalPrefix = []byte{0x05}
+	// WithdrawalQueuePrefix indexes the queued withdrawals by the height they complete at
+	WithdrawalQueuePrefix = []byte{0x06}
+	// NextWithdrawalIDKey stores the id of the next withdrawal
+	NextWithdrawalIDKey = []byte{0x07}
+	// AssetPrefix prefixes the registered assets by jetton
+	AssetPrefix = []byte{0x08}
+	// AssetDenomPrefix indexes the jettons of the registered assets by denom
+	AssetDenomPrefix = []byte{0x09}
+	// BondPrefix prefixes the bonds of the relayers, held by the module account
+	BondPrefix = []byte{0x0a}
+	// DepositPrefix marks the sequences of the executed deposits, so a deposit
+	// isn't executed twice
+	DepositPrefix = []byte{0x0b}
+)
+
+// RelayerKey returns the store key of relayer in the applied relayer set
+func RelayerKey(relayer common.Address) []byte {
+	return append(append([]byte{}, RelayerPrefix...), relayer.Bytes()...)
+}
+
+// UnbondingKey returns the store key of the release height of the bond of relayer
+func UnbondingKey(relayer common.Address) []byte {
+	return append(append([]byte{}, UnbondingPrefix...), relayer.Bytes()...)
+}
+
+// UnbondingQueueKey returns the index key of the bond of relayer released at height
+func UnbondingQueueKey(height int64, relayer common.Address) []byte {
+	return append(UnbondingQueuePrefixAt(height), relayer.Bytes()...)
+}
+
+// UnbondingQueuePrefixAt returns the prefix of the index keys of the bonds
+// released at height.
+func UnbondingQueuePrefixAt(height int64) []byte {
+	return append(append([]byte{}, UnbondingQueuePrefix...), sdk.Uint64ToBigEndian(uint64(height))...)
+}
+
+// BondKey returns the store key of the bond of relayer
+func BondKey(relayer common.Address) []byte {
+	return append(append([]byte{}, BondPrefix...), relayer.Bytes()...)
+}
+
+// SlashedKey returns the store key marking relayer as slashed for sequence
+func SlashedKey(relayer common.Address, sequence uint64) []byte {
+	return append(append(append([]byte{}, SlashedPrefix...), relayer.Bytes()...), sdk.Uint64ToBigEndian(sequence)...)
+}
+
+// DepositKey returns the store key marking the deposit with sequence as executed
+func DepositKey(sequence uint64) []byte {
+	return append(append([]byte{}, DepositPrefix...), sdk.Uint64ToBigEndian(sequence)...)
+}
+
+// WithdrawalKey returns the store key of the withdrawal with id
+func WithdrawalKey(id uint64) []byte {
+	return append(append([]byte{}, WithdrawalPrefix...), sdk.Uint64ToBigEndian(id)...)
+}
+
+// WithdrawalQueueKey returns the index key of the withdrawal with id completing at height
+func WithdrawalQueueKey(height int64, id uint64) []byte {
+	return append(WithdrawalQueuePrefixAt(height), sdk.Uint64ToBigEndian(id)...)
+}
+
+// WithdrawalQueuePrefixAt returns the prefix of the index keys of the
+// withdrawals completing at height.
+func WithdrawalQueuePrefixAt(height int64) []byte {
+	return append(append([]byte{}, WithdrawalQueuePrefix...), sdk.Uint64ToBigEndian(uint64(height))...)
+}
+
+// AssetKey returns the store key of the asset of jetton
+func AssetKey(jetton string) []byte {
+	return append(append([]byte{}, AssetPrefix...), jetton...)
+}
+
+// AssetDenomKey returns the index key of the asset minting denom
+func AssetDenomKey(denom string) []byte {
+	return append(append([]byte{}, AssetDenomPrefix...), denom...)
+}
+
+// EscrowAddress is the address users withdraw to TON by sending it an EVM tx
+// calling withdraw with the amount as value, relayers bond by calling bond
+// with their bond as value and submit the attested deposits from TON by
+// calling deposit. No key controls it, the calls move the value on
+// to the module account, which holds the bonds and the amounts of the queued
+// withdrawals until they complete and are burnt. The escrow address holds
+// nothing once a tx ended, a tx leaving coins there fails.
+func EscrowAddress() common.Address {
+	return common.BytesToAddress(address.Module(ModuleName, []byte("escrow"))[:common.AddressLength])
+}
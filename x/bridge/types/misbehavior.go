@@ -0,0 +1,75 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	errorsmod "cosmossdk.io/errors"
+)
+
+// Misbehavior is the evidence that a relayer attested two different payloads
+// for the same bridge sequence. Relayer is a hex address, the payload hashes
+// and the signatures of their attestation digests are 0x prefixed hex.
+type Misbehavior struct {
+	ChainID           string `json:"chain_id" yaml:"chain_id"`
+	Relayer           string `json:"relayer" yaml:"relayer"`
+	Sequence          uint64 `json:"sequence" yaml:"sequence"`
+	FirstPayloadHash  string `json:"first_payload_hash" yaml:"first_payload_hash"`
+	FirstSignature    string `json:"first_signature" yaml:"first_signature"`
+	SecondPayloadHash string `json:"second_payload_hash" yaml:"second_payload_hash"`
+	SecondSignature   string `json:"second_signature" yaml:"second_signature"`
+}
+
+// RelayerAddress returns the address of the relayer, the misbehavior must be valid
+func (m Misbehavior) RelayerAddress() common.Address {
+	return common.HexToAddress(m.Relayer)
+}
+
+// Verify checks the relayer signed both attestations and they conflict
+func (m Misbehavior) Verify() error {
+	if m.ChainID == "" {
+		return errorsmod.Wrap(ErrInvalidMisbehavior, "missing chain id")
+	}
+	if !common.IsHexAddress(m.Relayer) {
+		return errorsmod.Wrapf(ErrInvalidMisbehavior, "invalid relayer address: %q", m.Relayer)
+	}
+
+	first, err := decodeHash(m.FirstPayloadHash)
+	if err != nil {
+		return err
+	}
+	second, err := decodeHash(m.SecondPayloadHash)
+	if err != nil {
+		return err
+	}
+	if first == second {
+		return errorsmod.Wrap(ErrInvalidMisbehavior, "the attestations have the same payload hash")
+	}
+
+	for _, attestation := range []struct {
+		hash      common.Hash
+		signature string
+	}{{first, m.FirstSignature}, {second, m.SecondSignature}} {
+		sig, err := hexutil.Decode(attestation.signature)
+		if err != nil {
+			return errorsmod.Wrapf(ErrInvalidMisbehavior, "invalid signature: %s", err)
+		}
+		signer, err := RecoverSigner(AttestationDigest(m.ChainID, m.Sequence, attestation.hash), sig)
+		if err != nil {
+			return err
+		}
+		if signer != m.RelayerAddress() {
+			return errorsmod.Wrapf(ErrInvalidMisbehavior, "attestation of %s signed by %s", attestation.hash.Hex(), signer.Hex())
+		}
+	}
+
+	return nil
+}
+
+func decodeHash(s string) (common.Hash, error) {
+	bz, err := hexutil.Decode(s)
+	if err != nil || len(bz) != common.HashLength {
+		return common.Hash{}, errorsmod.Wrapf(ErrInvalidMisbehavior, "payload hash must be 32 bytes of 0x prefixed hex: %q", s)
+	}
+	return common.BytesToHash(bz), nil
+}
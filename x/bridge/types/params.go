@@ -0,0 +1,430 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	sdkmath "cosmossdk.io/math"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+var (
+	// KeyRelayers is the param store key for the relayers authorized by governance
+	KeyRelayers = []byte("Relayers")
+	// KeyMinRelayerBond is the param store key for the bond a relayer needs to attest
+	KeyMinRelayerBond = []byte("MinRelayerBond")
+	// KeyAttestationThreshold is the param store key for the share of the relayer power an attestation needs
+	KeyAttestationThreshold = []byte("AttestationThreshold")
+	// KeySlashFraction is the param store key for the share of the bond a misbehaving relayer loses
+	KeySlashFraction = []byte("SlashFraction")
+	// KeyUnbondingBlocks is the param store key for the blocks the bond of a removed relayer stays slashable
+	KeyUnbondingBlocks = []byte("UnbondingBlocks")
+	// KeyMisbehavior is the param store key for the relayer misbehavior submitted by governance
+	KeyMisbehavior = []byte("Misbehavior")
+	// KeyWithdrawalDelay is the param store key for the blocks a withdrawal can be challenged for
+	KeyWithdrawalDelay = []byte("WithdrawalDelay")
+	// KeyWithdrawalRulings is the param store key for the rulings of governance on challenged withdrawals
+	KeyWithdrawalRulings = []byte("WithdrawalRulings")
+	// KeyApprovedAssets is the param store key for the assets governance adds to the registry
+	KeyApprovedAssets = []byte("ApprovedAssets")
+	// KeyRemovedAssets is the param store key for the jettons governance removes from the registry
+	KeyRemovedAssets = []byte("RemovedAssets")
+	// KeyDepositGas is the param store key for the gas relayers spend executing a deposit
+	KeyDepositGas = []byte("DepositGas")
+	// KeyWithdrawalGas is the param store key for the gas of a withdrawal tx
+	KeyWithdrawalGas = []byte("WithdrawalGas")
+	// KeyRelayerFeeBase is the param store key for the flat part of the relayer fee
+	KeyRelayerFeeBase = []byte("RelayerFeeBase")
+	// KeyRelayerFeeRate is the param store key for the share of the amount taken as relayer fee
+	KeyRelayerFeeRate = []byte("RelayerFeeRate")
+	// KeyTONFee is the param store key for the TON network fees of releasing a withdrawal
+	KeyTONFee = []byte("TONFee")
+	// KeyTONPrice is the param store key for the price of a nanoton in the bond denom
+	KeyTONPrice = []byte("TONPrice")
+)
+
+// Params defines the bridge module parameters.
+//
+// Governance adds and removes relayers through a ParameterChangeProposal on
+// Relayers. A relayer attests with the power of its bond, the bond denom it
+// sent with bond calls to the EscrowAddress, as long as the bond is at least
+// MinRelayerBond. An attestation is accepted when its signers hold more than
+// AttestationThreshold of the power of all active relayers.
+//
+// Governance punishes a relayer that attested conflicting payloads by
+// submitting the evidence on Misbehavior: the module checks the signatures at
+// the end of the block, moves SlashFraction of the bond to the community pool,
+// removes the relayer from the set and clears the list. The bond of a removed
+// relayer stays slashable for UnbondingBlocks before it is sent back to it.
+//
+// Withdrawals to TON complete WithdrawalDelay blocks after they were queued.
+// Until then an active relayer can challenge a suspicious one, which freezes
+// it until governance rules on it through WithdrawalRulings, applied and
+// cleared at the end of the block like Misbehavior.
+//
+// Governance maps TON assets to the denoms the bridge mints for them through
+// ApprovedAssets and RemovedAssets, applied at the end of the block, removals
+// first, and cleared afterwards.
+//
+// The fee params only price the quotes wallets show before a transfer, see
+// QuoteFee. Relayers are paid RelayerFeeBase plus RelayerFeeRate of the
+// amount. DepositGas and WithdrawalGas estimate the gas spent on tacchain,
+// TONFee the nanotons released withdrawals cost on TON, converted at
+// TONPrice. The chain has no price oracle, relayers watch the TON price and
+// governance updates TONPrice from their reports.
+type Params struct {
+	Relayers             []string           `json:"relayers" yaml:"relayers"`
+	MinRelayerBond       sdkmath.Int        `json:"min_relayer_bond" yaml:"min_relayer_bond"`
+	AttestationThreshold sdkmath.LegacyDec  `json:"attestation_threshold" yaml:"attestation_threshold"`
+	SlashFraction        sdkmath.LegacyDec  `json:"slash_fraction" yaml:"slash_fraction"`
+	UnbondingBlocks      uint64             `json:"unbonding_blocks" yaml:"unbonding_blocks"`
+	Misbehavior          []Misbehavior      `json:"misbehavior" yaml:"misbehavior"`
+	WithdrawalDelay      uint64             `json:"withdrawal_delay" yaml:"withdrawal_delay"`
+	WithdrawalRulings    []WithdrawalRuling `json:"withdrawal_rulings" yaml:"withdrawal_rulings"`
+	ApprovedAssets       []Asset            `json:"approved_assets" yaml:"approved_assets"`
+	RemovedAssets        []string           `json:"removed_assets" yaml:"removed_assets"`
+	DepositGas           uint64             `json:"deposit_gas" yaml:"deposit_gas"`
+	WithdrawalGas        uint64             `json:"withdrawal_gas" yaml:"withdrawal_gas"`
+	RelayerFeeBase       sdkmath.Int        `json:"relayer_fee_base" yaml:"relayer_fee_base"`
+	RelayerFeeRate       sdkmath.LegacyDec  `json:"relayer_fee_rate" yaml:"relayer_fee_rate"`
+	TONFee               sdkmath.Int        `json:"ton_fee" yaml:"ton_fee"`
+	TONPrice             sdkmath.LegacyDec  `json:"ton_price" yaml:"ton_price"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the bridge module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default bridge module parameters
+func DefaultParams() Params {
+	return Params{
+		Relayers:             []string{},
+		MinRelayerBond:       sdkmath.ZeroInt(),
+		AttestationThreshold: sdkmath.LegacyNewDecWithPrec(667, 3),
+		SlashFraction:        sdkmath.LegacyNewDecWithPrec(1, 1),
+		UnbondingBlocks:      100_000,
+		Misbehavior:          []Misbehavior{},
+		WithdrawalDelay:      43_200,
+		WithdrawalRulings:    []WithdrawalRuling{},
+		ApprovedAssets:       []Asset{},
+		RemovedAssets:        []string{},
+		DepositGas:           200_000,
+		WithdrawalGas:        100_000,
+		RelayerFeeBase:       sdkmath.ZeroInt(),
+		RelayerFeeRate:       sdkmath.LegacyNewDecWithPrec(1, 3),
+		TONFee:               sdkmath.NewInt(50_000_000),
+		TONPrice:             sdkmath.LegacyZeroDec(),
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyRelayers, &p.Relayers, validateRelayers),
+		paramtypes.NewParamSetPair(KeyMinRelayerBond, &p.MinRelayerBond, validateMinRelayerBond),
+		paramtypes.NewParamSetPair(KeyAttestationThreshold, &p.AttestationThreshold, validateAttestationThreshold),
+		paramtypes.NewParamSetPair(KeySlashFraction, &p.SlashFraction, validateSlashFraction),
+		paramtypes.NewParamSetPair(KeyUnbondingBlocks, &p.UnbondingBlocks, validateUnbondingBlocks),
+		paramtypes.NewParamSetPair(KeyMisbehavior, &p.Misbehavior, validateMisbehavior),
+		paramtypes.NewParamSetPair(KeyWithdrawalDelay, &p.WithdrawalDelay, validateWithdrawalDelay),
+		paramtypes.NewParamSetPair(KeyWithdrawalRulings, &p.WithdrawalRulings, validateWithdrawalRulings),
+		paramtypes.NewParamSetPair(KeyApprovedAssets, &p.ApprovedAssets, validateApprovedAssets),
+		paramtypes.NewParamSetPair(KeyRemovedAssets, &p.RemovedAssets, validateRemovedAssets),
+		paramtypes.NewParamSetPair(KeyDepositGas, &p.DepositGas, validateBridgeGas),
+		paramtypes.NewParamSetPair(KeyWithdrawalGas, &p.WithdrawalGas, validateBridgeGas),
+		paramtypes.NewParamSetPair(KeyRelayerFeeBase, &p.RelayerFeeBase, validateRelayerFeeBase),
+		paramtypes.NewParamSetPair(KeyRelayerFeeRate, &p.RelayerFeeRate, validateRelayerFeeRate),
+		paramtypes.NewParamSetPair(KeyTONFee, &p.TONFee, validateTONFee),
+		paramtypes.NewParamSetPair(KeyTONPrice, &p.TONPrice, validateTONPrice),
+	}
+}
+
+// Validate performs basic validation of the bridge module parameters
+func (p Params) Validate() error {
+	if err := validateRelayers(p.Relayers); err != nil {
+		return err
+	}
+	if err := validateMinRelayerBond(p.MinRelayerBond); err != nil {
+		return err
+	}
+	if err := validateAttestationThreshold(p.AttestationThreshold); err != nil {
+		return err
+	}
+	if err := validateSlashFraction(p.SlashFraction); err != nil {
+		return err
+	}
+	if err := validateUnbondingBlocks(p.UnbondingBlocks); err != nil {
+		return err
+	}
+	if err := validateMisbehavior(p.Misbehavior); err != nil {
+		return err
+	}
+	if err := validateWithdrawalDelay(p.WithdrawalDelay); err != nil {
+		return err
+	}
+	if err := validateWithdrawalRulings(p.WithdrawalRulings); err != nil {
+		return err
+	}
+	if err := validateApprovedAssets(p.ApprovedAssets); err != nil {
+		return err
+	}
+	if err := validateRemovedAssets(p.RemovedAssets); err != nil {
+		return err
+	}
+	if err := validateBridgeGas(p.DepositGas); err != nil {
+		return err
+	}
+	if err := validateBridgeGas(p.WithdrawalGas); err != nil {
+		return err
+	}
+	if err := validateRelayerFeeBase(p.RelayerFeeBase); err != nil {
+		return err
+	}
+	if err := validateRelayerFeeRate(p.RelayerFeeRate); err != nil {
+		return err
+	}
+	if err := validateTONFee(p.TONFee); err != nil {
+		return err
+	}
+	return validateTONPrice(p.TONPrice)
+}
+
+// IsRelayer returns true if governance authorized relayer
+func (p Params) IsRelayer(relayer common.Address) bool {
+	for _, r := range p.Relayers {
+		if common.HexToAddress(r) == relayer {
+			return true
+		}
+	}
+	return false
+}
+
+// HasQuorum returns true if power is more than the attestation threshold of
+// the total power. Without any power there is no quorum.
+func (p Params) HasQuorum(power, total sdkmath.Int) bool {
+	if !total.IsPositive() {
+		return false
+	}
+	return sdkmath.LegacyNewDecFromInt(power).GT(p.AttestationThreshold.MulInt(total))
+}
+
+func validateRelayers(i interface{}) error {
+	relayers, ok := i.([]string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	seen := make(map[common.Address]bool, len(relayers))
+	for _, relayer := range relayers {
+		if !common.IsHexAddress(relayer) || common.HexToAddress(relayer) == (common.Address{}) {
+			return fmt.Errorf("invalid relayer address: %q", relayer)
+		}
+		if seen[common.HexToAddress(relayer)] {
+			return fmt.Errorf("duplicate relayer %s", relayer)
+		}
+		seen[common.HexToAddress(relayer)] = true
+	}
+	return nil
+}
+
+func validateMinRelayerBond(i interface{}) error {
+	v, ok := i.(sdkmath.Int)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v.IsNil() || v.IsNegative() {
+		return fmt.Errorf("min relayer bond must be non-negative: %s", v)
+	}
+	return nil
+}
+
+func validateAttestationThreshold(i interface{}) error {
+	v, ok := i.(sdkmath.LegacyDec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	// below a half, two conflicting attestations could both reach the quorum
+	if v.IsNil() || v.LT(sdkmath.LegacyNewDecWithPrec(5, 1)) || v.GTE(sdkmath.LegacyOneDec()) {
+		return fmt.Errorf("attestation threshold must be in [0.5, 1): %s", v)
+	}
+	return nil
+}
+
+func validateSlashFraction(i interface{}) error {
+	v, ok := i.(sdkmath.LegacyDec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v.IsNil() || v.IsNegative() || v.GT(sdkmath.LegacyOneDec()) {
+		return fmt.Errorf("slash fraction must be in [0, 1]: %s", v)
+	}
+	return nil
+}
+
+func validateUnbondingBlocks(i interface{}) error {
+	if _, ok := i.(uint64); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return nil
+}
+
+func validateMisbehavior(i interface{}) error {
+	misbehavior, ok := i.([]Misbehavior)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	type offense struct {
+		relayer  common.Address
+		sequence uint64
+	}
+	seen := make(map[offense]bool, len(misbehavior))
+	for _, m := range misbehavior {
+		if err := m.Verify(); err != nil {
+			return err
+		}
+		o := offense{m.RelayerAddress(), m.Sequence}
+		if seen[o] {
+			return fmt.Errorf("duplicate misbehavior of %s at sequence %d", m.Relayer, m.Sequence)
+		}
+		seen[o] = true
+	}
+	return nil
+}
+
+func validateWithdrawalDelay(i interface{}) error {
+	v, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	// without a delay no withdrawal could be challenged
+	if v == 0 {
+		return fmt.Errorf("withdrawal delay must be positive")
+	}
+	return nil
+}
+
+func validateWithdrawalRulings(i interface{}) error {
+	rulings, ok := i.([]WithdrawalRuling)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	seen := make(map[uint64]bool, len(rulings))
+	for _, ruling := range rulings {
+		if seen[ruling.ID] {
+			return fmt.Errorf("duplicate ruling on withdrawal %d", ruling.ID)
+		}
+		seen[ruling.ID] = true
+	}
+	return nil
+}
+
+func validateApprovedAssets(i interface{}) error {
+	assets, ok := i.([]Asset)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return validateAssetList(assets)
+}
+
+// validateAssetList checks every asset is valid and the list has no duplicate
+// jetton or denom.
+func validateAssetList(assets []Asset) error {
+	jettons := make(map[string]bool, len(assets))
+	denoms := make(map[string]bool, len(assets))
+	for _, asset := range assets {
+		if err := asset.Validate(); err != nil {
+			return err
+		}
+		jetton := NormalizeJetton(asset.Jetton)
+		if jettons[jetton] {
+			return fmt.Errorf("duplicate asset for jetton %s", asset.Jetton)
+		}
+		if denoms[asset.Denom] {
+			return fmt.Errorf("duplicate asset for denom %s", asset.Denom)
+		}
+		jettons[jetton] = true
+		denoms[asset.Denom] = true
+	}
+	return nil
+}
+
+func validateRemovedAssets(i interface{}) error {
+	jettons, ok := i.([]string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	seen := make(map[string]bool, len(jettons))
+	for _, jetton := range jettons {
+		if err := ValidateJetton(jetton); err != nil {
+			return err
+		}
+		if seen[NormalizeJetton(jetton)] {
+			return fmt.Errorf("duplicate removed jetton %s", jetton)
+		}
+		seen[NormalizeJetton(jetton)] = true
+	}
+	return nil
+}
+
+func validateBridgeGas(i interface{}) error {
+	v, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v == 0 {
+		return fmt.Errorf("bridge gas must be positive")
+	}
+	return nil
+}
+
+func validateRelayerFeeBase(i interface{}) error {
+	v, ok := i.(sdkmath.Int)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v.IsNil() || v.IsNegative() {
+		return fmt.Errorf("relayer fee base must be non-negative: %s", v)
+	}
+	return nil
+}
+
+func validateRelayerFeeRate(i interface{}) error {
+	v, ok := i.(sdkmath.LegacyDec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v.IsNil() || v.IsNegative() || v.GTE(sdkmath.LegacyOneDec()) {
+		return fmt.Errorf("relayer fee rate must be in [0, 1): %s", v)
+	}
+	return nil
+}
+
+func validateTONFee(i interface{}) error {
+	v, ok := i.(sdkmath.Int)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v.IsNil() || v.IsNegative() {
+		return fmt.Errorf("TON fee must be non-negative: %s", v)
+	}
+	return nil
+}
+
+func validateTONPrice(i interface{}) error {
+	v, ok := i.(sdkmath.LegacyDec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v.IsNil() || v.IsNegative() {
+		return fmt.Errorf("TON price must be non-negative: %s", v)
+	}
+	return nil
+}
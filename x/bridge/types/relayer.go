@@ -0,0 +1,39 @@
+package types
+
+import (
+	sdkmath "cosmossdk.io/math"
+)
+
+// RelayerPower is the attestation power of a relayer of the set
+type RelayerPower struct {
+	Relayer string      `json:"relayer"`
+	Bond    sdkmath.Int `json:"bond"`
+	// Active is false while the bond is below the min relayer bond, the
+	// relayer has no power then
+	Active bool `json:"active"`
+}
+
+// Power returns the power of the relayer, its bond if it is active
+func (r RelayerPower) Power() sdkmath.Int {
+	if !r.Active {
+		return sdkmath.ZeroInt()
+	}
+	return r.Bond
+}
+
+// NewRelayerPower returns the power of relayer given its bond
+func NewRelayerPower(relayer string, bond, minBond sdkmath.Int) RelayerPower {
+	return RelayerPower{
+		Relayer: relayer,
+		Bond:    bond,
+		Active:  bond.IsPositive() && bond.GTE(minBond),
+	}
+}
+
+// AttestationResult is the outcome of the verification of an attestation
+type AttestationResult struct {
+	// Signers are the hex addresses of the relayers that signed the attestation
+	Signers    []string    `json:"signers"`
+	Power      sdkmath.Int `json:"power"`
+	TotalPower sdkmath.Int `json:"total_power"`
+}
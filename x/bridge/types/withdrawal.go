@@ -0,0 +1,99 @@
+package types
+
+import (
+	"regexp"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// WithdrawalStatusQueued is the status of a withdrawal waiting for the
+	// end of its challenge window
+	WithdrawalStatusQueued = "queued"
+	// WithdrawalStatusChallenged is the status of a withdrawal a relayer
+	// challenged, frozen until governance rules on it
+	WithdrawalStatusChallenged = "challenged"
+)
+
+var (
+	// tonRawAddress matches the raw form of TON addresses, workchain:account
+	tonRawAddress = regexp.MustCompile(`^-?[0-9]{1,10}:[0-9a-fA-F]{64}$`)
+	// tonFriendlyAddress matches the user friendly form of TON addresses, 36
+	// bytes in base64 or base64url
+	tonFriendlyAddress = regexp.MustCompile(`^[A-Za-z0-9+/_-]{48}$`)
+)
+
+// Withdrawal is an exit from tacchain to TON. The amount is held by the
+// module account until the withdrawal completes at CompleteHeight and is
+// burnt, unless a relayer challenges it before. A challenged withdrawal waits for governance
+// to release it or to refund the sender.
+type Withdrawal struct {
+	ID uint64 `json:"id" yaml:"id"`
+	// Sender is the hex address of the account that withdrew
+	Sender string `json:"sender" yaml:"sender"`
+	// Recipient is the TON address receiving the amount
+	Recipient      string   `json:"recipient" yaml:"recipient"`
+	Amount         sdk.Coin `json:"amount" yaml:"amount"`
+	Status         string   `json:"status" yaml:"status"`
+	CompleteHeight int64    `json:"complete_height" yaml:"complete_height"`
+	// Challenger is the hex address of the relayer that challenged the withdrawal
+	Challenger      string `json:"challenger,omitempty" yaml:"challenger,omitempty"`
+	ChallengeHeight int64  `json:"challenge_height,omitempty" yaml:"challenge_height,omitempty"`
+}
+
+// SenderAddress returns the account that withdrew
+func (w Withdrawal) SenderAddress() sdk.AccAddress {
+	return sdk.AccAddress(common.HexToAddress(w.Sender).Bytes())
+}
+
+// Validate performs basic validation of a withdrawal
+func (w Withdrawal) Validate() error {
+	if !common.IsHexAddress(w.Sender) {
+		return errorsmod.Wrapf(ErrInvalidWithdrawal, "invalid sender address: %q", w.Sender)
+	}
+	if err := ValidateTONAddress(w.Recipient); err != nil {
+		return err
+	}
+	if err := w.Amount.Validate(); err != nil || !w.Amount.IsPositive() {
+		return errorsmod.Wrapf(ErrInvalidWithdrawal, "invalid amount: %s", w.Amount)
+	}
+	if w.CompleteHeight <= 0 {
+		return errorsmod.Wrapf(ErrInvalidWithdrawal, "invalid complete height: %d", w.CompleteHeight)
+	}
+
+	switch w.Status {
+	case WithdrawalStatusQueued:
+		if w.Challenger != "" || w.ChallengeHeight != 0 {
+			return errorsmod.Wrap(ErrInvalidWithdrawal, "a queued withdrawal has no challenger")
+		}
+	case WithdrawalStatusChallenged:
+		if !common.IsHexAddress(w.Challenger) {
+			return errorsmod.Wrapf(ErrInvalidWithdrawal, "invalid challenger address: %q", w.Challenger)
+		}
+		if w.ChallengeHeight <= 0 {
+			return errorsmod.Wrapf(ErrInvalidWithdrawal, "invalid challenge height: %d", w.ChallengeHeight)
+		}
+	default:
+		return errorsmod.Wrapf(ErrInvalidWithdrawal, "unknown status: %q", w.Status)
+	}
+	return nil
+}
+
+// ValidateTONAddress checks recipient is a TON address, in raw or user friendly form
+func ValidateTONAddress(recipient string) error {
+	if !tonRawAddress.MatchString(recipient) && !tonFriendlyAddress.MatchString(recipient) {
+		return errorsmod.Wrapf(ErrInvalidWithdrawal, "invalid TON address: %q", recipient)
+	}
+	return nil
+}
+
+// WithdrawalRuling is the decision of governance on a challenged withdrawal:
+// Release completes it, otherwise the amount is refunded to the sender.
+type WithdrawalRuling struct {
+	ID      uint64 `json:"id" yaml:"id"`
+	Release bool   `json:"release" yaml:"release"`
+}
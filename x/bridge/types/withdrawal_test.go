@@ -0,0 +1,134 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/bridge/types"
+)
+
+const (
+	tonRawAddress      = "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+	tonFriendlyAddress = "EQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqB2N"
+)
+
+func TestEscrowCall(t *testing.T) {
+	for _, call := range []types.EscrowCall{
+		{Method: types.MethodWithdraw, Recipient: tonFriendlyAddress},
+		{Method: types.MethodChallenge, ID: 42},
+		{Method: types.MethodBond},
+	} {
+		data, err := call.Pack()
+		require.NoError(t, err)
+		parsed, err := types.ParseEscrowCall(data)
+		require.NoError(t, err)
+		require.Equal(t, call, parsed)
+	}
+
+	for _, data := range [][]byte{nil, {0x01, 0x02, 0x03, 0x04}, types.EscrowABI.Methods[types.MethodChallenge].ID} {
+		_, err := types.ParseEscrowCall(data)
+		require.ErrorIs(t, err, types.ErrInvalidBridgeCall)
+	}
+}
+
+func TestValidateTONAddress(t *testing.T) {
+	for _, recipient := range []string{
+		tonRawAddress,
+		"-1:3333333333333333333333333333333333333333333333333333333333333333",
+		tonFriendlyAddress,
+		"UQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqEBI",
+		"EQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqB2N"[:40] + "_-+/abcd",
+	} {
+		require.NoError(t, types.ValidateTONAddress(recipient), recipient)
+	}
+
+	for _, recipient := range []string{
+		"",
+		"0:83dfd552",
+		"0x83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+		"EQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqB2",
+		"EQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqB2N ",
+		"tac1qqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v",
+	} {
+		require.ErrorIs(t, types.ValidateTONAddress(recipient), types.ErrInvalidWithdrawal, recipient)
+	}
+}
+
+func TestWithdrawalValidate(t *testing.T) {
+	newWithdrawal := func() types.Withdrawal {
+		return types.Withdrawal{
+			ID:             1,
+			Sender:         common.HexToAddress("0x01").Hex(),
+			Recipient:      tonRawAddress,
+			Amount:         sdk.NewCoin("utac", sdkmath.NewInt(100)),
+			Status:         types.WithdrawalStatusQueued,
+			CompleteHeight: 10,
+		}
+	}
+	require.NoError(t, newWithdrawal().Validate())
+
+	challenged := newWithdrawal()
+	challenged.Status = types.WithdrawalStatusChallenged
+	challenged.Challenger = common.HexToAddress("0x02").Hex()
+	challenged.ChallengeHeight = 5
+	require.NoError(t, challenged.Validate())
+
+	testCases := []struct {
+		name   string
+		modify func(w *types.Withdrawal)
+	}{
+		{"invalid sender", func(w *types.Withdrawal) { w.Sender = "tac1xyz" }},
+		{"invalid recipient", func(w *types.Withdrawal) { w.Recipient = "0:00" }},
+		{"zero amount", func(w *types.Withdrawal) { w.Amount = sdk.NewCoin("utac", sdkmath.ZeroInt()) }},
+		{"invalid denom", func(w *types.Withdrawal) { w.Amount = sdk.Coin{Denom: "!", Amount: sdkmath.OneInt()} }},
+		{"no complete height", func(w *types.Withdrawal) { w.CompleteHeight = 0 }},
+		{"unknown status", func(w *types.Withdrawal) { w.Status = "completed" }},
+		{"queued with a challenger", func(w *types.Withdrawal) { w.Challenger = common.HexToAddress("0x02").Hex() }},
+		{"challenged without challenger", func(w *types.Withdrawal) {
+			w.Status = types.WithdrawalStatusChallenged
+			w.ChallengeHeight = 5
+		}},
+		{"challenged without challenge height", func(w *types.Withdrawal) {
+			w.Status = types.WithdrawalStatusChallenged
+			w.Challenger = common.HexToAddress("0x02").Hex()
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := newWithdrawal()
+			tc.modify(&w)
+			require.ErrorIs(t, w.Validate(), types.ErrInvalidWithdrawal)
+		})
+	}
+}
+
+func TestGenesisValidateWithdrawals(t *testing.T) {
+	withdrawal := types.Withdrawal{
+		ID:             3,
+		Sender:         common.HexToAddress("0x01").Hex(),
+		Recipient:      tonRawAddress,
+		Amount:         sdk.NewCoin("utac", sdkmath.NewInt(100)),
+		Status:         types.WithdrawalStatusQueued,
+		CompleteHeight: 10,
+	}
+
+	gs := types.DefaultGenesisState()
+	require.NoError(t, gs.Validate())
+	gs.Withdrawals = []types.Withdrawal{withdrawal}
+	gs.NextWithdrawalID = 4
+	require.NoError(t, gs.Validate())
+
+	gs.NextWithdrawalID = 3
+	require.Error(t, gs.Validate(), "withdrawal ids must be below the next id")
+
+	gs.NextWithdrawalID = 4
+	gs.Withdrawals = []types.Withdrawal{withdrawal, withdrawal}
+	require.Error(t, gs.Validate(), "withdrawal ids must be unique")
+}
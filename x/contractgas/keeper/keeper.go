@@ -0,0 +1,74 @@
+package keeper
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+	"cosmossdk.io/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/Asphere-xyz/tacchain/x/contractgas/types"
+)
+
+// Keeper of the contractgas module
+type Keeper struct {
+	paramSpace paramtypes.Subspace
+	evmKeeper  types.EVMKeeper
+}
+
+// NewKeeper creates a new contractgas Keeper instance
+func NewKeeper(paramSpace paramtypes.Subspace, evmKeeper types.EVMKeeper) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		paramSpace: paramSpace,
+		evmKeeper:  evmKeeper,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current contractgas module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the contractgas module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// CheckCallGas fails if an EVM tx calling to with gasLimit is above the gas
+// cap of contract calls: the cap is enabled, to is a contract and it isn't
+// exempt. A tx can't consume more gas than its limit, so the check caps the
+// gas the call consumes.
+//
+// The cap applies to the contract the tx calls directly, the EVM doesn't
+// check the calls between contracts. Their gas is bounded by the gas limit of
+// the tx, so the cap of the called contract bounds them too, but the calls
+// made by an exempt contract or by the constructor of a contract creation
+// aren't capped.
+func (k Keeper) CheckCallGas(ctx sdk.Context, to common.Address, gasLimit uint64) error {
+	params := k.GetParams(ctx)
+	if params.MaxCallGas == 0 || gasLimit <= params.MaxCallGas {
+		return nil
+	}
+	if params.IsExempt(to) {
+		return nil
+	}
+	if account := k.evmKeeper.GetAccount(ctx, to); account == nil || !account.IsContract() {
+		return nil
+	}
+
+	return errorsmod.Wrapf(types.ErrCallGasCap,
+		"gas limit %d of the call to contract %s is above the cap of %d, lower it or ask governance to exempt the contract",
+		gasLimit, to, params.MaxCallGas)
+}
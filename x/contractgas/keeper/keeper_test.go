@@ -0,0 +1,110 @@
+package keeper_test
+
+import (
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/evm/x/vm/statedb"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/contractgas/types"
+)
+
+var (
+	contract = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	exempt   = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	eoa      = common.HexToAddress("0x3000000000000000000000000000000000000003")
+)
+
+// setup returns a chain where contract and exempt are deployed and eoa has a
+// balance but no code
+func setup(t *testing.T) (*app.TacChainApp, sdk.Context) {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := tacApp.NewContext(false).WithChainID(app.DefaultChainID)
+
+	db := statedb.New(ctx, tacApp.EVMKeeper, statedb.NewEmptyTxConfig(common.BytesToHash(ctx.HeaderHash())))
+	db.SetCode(contract, []byte{0x5b, 0x60, 0x00, 0x56}) // jumpdest, jump(0): loops until out of gas
+	db.SetCode(exempt, []byte{0x5b, 0x60, 0x00, 0x56})
+	db.SetNonce(eoa, 1)
+	require.NoError(t, db.Commit())
+	return tacApp, ctx
+}
+
+func TestCheckCallGas(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.ContractGasKeeper
+
+	params := types.Params{MaxCallGas: 1_000_000, ExemptContracts: []string{exempt.Hex()}}
+	require.NoError(t, params.Validate())
+	k.SetParams(ctx, params)
+
+	testCases := []struct {
+		name     string
+		to       common.Address
+		gasLimit uint64
+		expErr   bool
+	}{
+		{"contract at the cap", contract, 1_000_000, false},
+		{"contract above the cap", contract, 1_000_001, true},
+		{"exempt contract above the cap", exempt, 30_000_000, false},
+		{"account without code above the cap", eoa, 30_000_000, false},
+		{"unknown account above the cap", common.HexToAddress("0xdead"), 30_000_000, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := k.CheckCallGas(ctx, tc.to, tc.gasLimit)
+			if tc.expErr {
+				require.ErrorIs(t, err, types.ErrCallGasCap)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDefaultParamsCapNothing(t *testing.T) {
+	tacApp, ctx := setup(t)
+
+	require.Zero(t, tacApp.ContractGasKeeper.GetParams(ctx).MaxCallGas)
+	require.NoError(t, tacApp.ContractGasKeeper.CheckCallGas(ctx, contract, 30_000_000))
+}
+
+func TestCheckCallGasDirectCallsOnly(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.ContractGasKeeper
+
+	// routers forward all their gas to contract: push the call arguments,
+	// the address of contract and the gas left, then call it
+	code := append([]byte{0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x73}, contract.Bytes()...)
+	code = append(code, 0x5a, 0xf1, 0x00)
+	router := common.HexToAddress("0x4000000000000000000000000000000000000004")
+	exemptRouter := common.HexToAddress("0x5000000000000000000000000000000000000005")
+	db := statedb.New(ctx, tacApp.EVMKeeper, statedb.NewEmptyTxConfig(common.BytesToHash(ctx.HeaderHash())))
+	db.SetCode(router, code)
+	db.SetCode(exemptRouter, code)
+	require.NoError(t, db.Commit())
+
+	k.SetParams(ctx, types.Params{MaxCallGas: 1_000_000, ExemptContracts: []string{exemptRouter.Hex()}})
+
+	// the cap checks the contract the tx calls, the gas of the inner calls is
+	// bounded by the gas limit of the tx
+	require.ErrorIs(t, k.CheckCallGas(ctx, router, 1_000_001), types.ErrCallGasCap)
+	require.NoError(t, k.CheckCallGas(ctx, router, 1_000_000))
+
+	// an exempt contract forwards gas above the cap to a capped contract
+	require.NoError(t, k.CheckCallGas(ctx, exemptRouter, 30_000_000))
+}
@@ -0,0 +1,10 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+)
+
+// x/contractgas module sentinel errors
+var (
+	ErrCallGasCap = errorsmod.Register(ModuleName, 2, "gas limit of the contract call above the cap")
+)
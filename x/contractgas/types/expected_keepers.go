@@ -0,0 +1,15 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/evm/x/vm/statedb"
+)
+
+// EVMKeeper defines the expected EVM keeper used to tell contracts from
+// externally owned accounts
+type EVMKeeper interface {
+	GetAccount(ctx sdk.Context, addr common.Address) *statedb.Account
+}
@@ -0,0 +1,6 @@
+package types
+
+const (
+	// ModuleName defines the contractgas module name
+	ModuleName = "contractgas"
+)
@@ -0,0 +1,100 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+var (
+	// KeyMaxCallGas is the param store key for the gas cap of a contract call
+	KeyMaxCallGas = []byte("MaxCallGas")
+	// KeyExemptContracts is the param store key for the contracts without cap
+	KeyExemptContracts = []byte("ExemptContracts")
+)
+
+// Params defines the contractgas module parameters. MaxCallGas caps the gas
+// an EVM tx calling a contract can consume, on top of the block gas limit: a
+// tx calling a contract with a gas limit above the cap is rejected, so a
+// runaway contract runs out of gas at the cap rather than filling blocks.
+// Zero, the default, disables the cap. Governance exempts the contracts that
+// need more gas with ExemptContracts.
+//
+// Contract creations, transfers to externally owned accounts and calls to
+// precompiles aren't capped.
+type Params struct {
+	MaxCallGas      uint64   `json:"max_call_gas" yaml:"max_call_gas"`
+	ExemptContracts []string `json:"exempt_contracts" yaml:"exempt_contracts"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the contractgas module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default contractgas module parameters
+func DefaultParams() Params {
+	return Params{
+		MaxCallGas:      0,
+		ExemptContracts: []string{},
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyMaxCallGas, &p.MaxCallGas, validateMaxCallGas),
+		paramtypes.NewParamSetPair(KeyExemptContracts, &p.ExemptContracts, validateExemptContracts),
+	}
+}
+
+// Validate performs basic validation of the contractgas module parameters
+func (p Params) Validate() error {
+	if err := validateMaxCallGas(p.MaxCallGas); err != nil {
+		return err
+	}
+	return validateExemptContracts(p.ExemptContracts)
+}
+
+// IsExempt returns true if the calls to contract aren't capped
+func (p Params) IsExempt(contract common.Address) bool {
+	for _, exempt := range p.ExemptContracts {
+		if common.HexToAddress(exempt) == contract {
+			return true
+		}
+	}
+	return false
+}
+
+func validateMaxCallGas(i interface{}) error {
+	if _, ok := i.(uint64); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return nil
+}
+
+func validateExemptContracts(i interface{}) error {
+	contracts, ok := i.([]string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	seen := make(map[string]bool, len(contracts))
+	for _, contract := range contracts {
+		if !common.IsHexAddress(contract) {
+			return fmt.Errorf("invalid contract address: %s", contract)
+		}
+		key := strings.ToLower(common.HexToAddress(contract).Hex())
+		if seen[key] {
+			return fmt.Errorf("duplicate contract address: %s", contract)
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
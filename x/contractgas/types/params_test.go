@@ -0,0 +1,42 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Asphere-xyz/tacchain/x/contractgas/types"
+)
+
+func TestParamsValidate(t *testing.T) {
+	require.NoError(t, types.DefaultParams().Validate())
+
+	valid := types.Params{
+		MaxCallGas:      5_000_000,
+		ExemptContracts: []string{"0x1000000000000000000000000000000000000001", "0x2000000000000000000000000000000000000002"},
+	}
+	require.NoError(t, valid.Validate())
+
+	testCases := []struct {
+		name      string
+		contracts []string
+	}{
+		{"invalid address", []string{"0x1234"}},
+		{"bech32 address", []string{"tac1qyqszqgpqyqszqgpqyqszqgpqyqszqgpjnp7du"}},
+		{"duplicate address", []string{"0x1000000000000000000000000000000000000001", "0x1000000000000000000000000000000000000001"}},
+		{"duplicate address in another case", []string{"0xabcdef0000000000000000000000000000000001", "0xABCDEF0000000000000000000000000000000001"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, types.Params{ExemptContracts: tc.contracts}.Validate())
+		})
+	}
+}
+
+func TestIsExempt(t *testing.T) {
+	params := types.Params{ExemptContracts: []string{"0xabcdef0000000000000000000000000000000001"}}
+	require.True(t, params.IsExempt(common.HexToAddress("0xABCDEF0000000000000000000000000000000001")))
+	require.False(t, params.IsExempt(common.HexToAddress("0xabcdef0000000000000000000000000000000002")))
+	require.False(t, types.DefaultParams().IsExempt(common.Address{}))
+}
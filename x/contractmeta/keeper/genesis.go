@@ -0,0 +1,26 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/contractmeta/types"
+)
+
+// InitGenesis initializes the contractmeta module state from a genesis state
+func (k Keeper) InitGenesis(ctx sdk.Context, gs types.GenesisState) {
+	k.SetParams(ctx, gs.Params)
+
+	for _, metadata := range gs.Metadata {
+		if err := k.SetMetadata(ctx, metadata); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// ExportGenesis returns the contractmeta module genesis state
+func (k Keeper) ExportGenesis(ctx sdk.Context) *types.GenesisState {
+	return &types.GenesisState{
+		Params:   k.GetParams(ctx),
+		Metadata: k.GetAllMetadata(ctx),
+	}
+}
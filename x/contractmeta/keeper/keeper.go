@@ -0,0 +1,257 @@
+package keeper
+
+import (
+	"encoding/json"
+
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	corestoretypes "cosmossdk.io/core/store"
+	errorsmod "cosmossdk.io/errors"
+	"cosmossdk.io/log"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/Asphere-xyz/tacchain/x/contractmeta/types"
+)
+
+// Keeper of the contractmeta store
+type Keeper struct {
+	storeService corestoretypes.KVStoreService
+	paramSpace   paramtypes.Subspace
+	evmKeeper    types.EVMKeeper
+}
+
+// NewKeeper creates a new contractmeta Keeper instance
+func NewKeeper(storeService corestoretypes.KVStoreService, paramSpace paramtypes.Subspace, evmKeeper types.EVMKeeper) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		storeService: storeService,
+		paramSpace:   paramSpace,
+		evmKeeper:    evmKeeper,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current contractmeta module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the contractmeta module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// GetMetadata returns the metadata of contract
+func (k Keeper) GetMetadata(ctx sdk.Context, contract common.Address) (types.Metadata, bool) {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.MetadataKey(contract))
+	if err != nil {
+		panic(err)
+	}
+	if bz == nil {
+		return types.Metadata{}, false
+	}
+
+	var metadata types.Metadata
+	if err := json.Unmarshal(bz, &metadata); err != nil {
+		panic(err)
+	}
+	return metadata, true
+}
+
+// GetContractByName returns the contract registered under name, ignoring case
+func (k Keeper) GetContractByName(ctx sdk.Context, name string) (common.Address, bool) {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.NameKey(name))
+	if err != nil {
+		panic(err)
+	}
+	if bz == nil {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(bz), true
+}
+
+// GetAllMetadata returns the metadata of every registered contract
+func (k Keeper) GetAllMetadata(ctx sdk.Context) []types.Metadata {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.MetadataPrefix, storetypes.PrefixEndBytes(types.MetadataPrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	all := []types.Metadata{}
+	for ; iterator.Valid(); iterator.Next() {
+		var metadata types.Metadata
+		if err := json.Unmarshal(iterator.Value(), &metadata); err != nil {
+			panic(err)
+		}
+		all = append(all, metadata)
+	}
+	return all
+}
+
+// SetMetadata stores valid metadata, replacing the one of the same contract.
+// It fails if the name is registered for another contract.
+func (k Keeper) SetMetadata(ctx sdk.Context, metadata types.Metadata) error {
+	if err := metadata.Validate(); err != nil {
+		return err
+	}
+	contract := metadata.ContractAddress()
+	if other, found := k.GetContractByName(ctx, metadata.Name); found && other != contract {
+		return errorsmod.Wrapf(types.ErrNameTaken, "%q is registered for %s", metadata.Name, other.Hex())
+	}
+
+	bz, err := json.Marshal(metadata.Normalized())
+	if err != nil {
+		return err
+	}
+
+	k.RemoveMetadata(ctx, contract)
+	store := k.storeService.OpenKVStore(ctx)
+	if err := store.Set(types.MetadataKey(contract), bz); err != nil {
+		panic(err)
+	}
+	if err := store.Set(types.NameKey(metadata.Name), contract.Bytes()); err != nil {
+		panic(err)
+	}
+	return nil
+}
+
+// RemoveMetadata removes the metadata of contract and frees its name
+func (k Keeper) RemoveMetadata(ctx sdk.Context, contract common.Address) {
+	metadata, found := k.GetMetadata(ctx, contract)
+	if !found {
+		return
+	}
+
+	store := k.storeService.OpenKVStore(ctx)
+	if err := store.Delete(types.NameKey(metadata.Name)); err != nil {
+		panic(err)
+	}
+	if err := store.Delete(types.MetadataKey(contract)); err != nil {
+		panic(err)
+	}
+}
+
+// HandleRegistryCall executes a call sent by sender to the registry address
+func (k Keeper) HandleRegistryCall(ctx sdk.Context, sender common.Address, data []byte) error {
+	call, err := types.ParseRegistryCall(data)
+	if err != nil {
+		return err
+	}
+	if call.Method == types.MethodUnregister {
+		return k.Unregister(ctx, sender, call.Contract)
+	}
+	return k.Register(ctx, sender, call)
+}
+
+// Register sets the metadata of a contract on behalf of sender, who becomes
+// its owner.
+//
+// To keep anyone from squatting the name of a contract, the first metadata of
+// a contract can only be registered by the account that deployed it: the
+// contract must have code and be at the address sender creates with the
+// DeployNonce of the call. Afterwards only the owner can update it.
+func (k Keeper) Register(ctx sdk.Context, sender common.Address, call types.RegistryCall) error {
+	existing, found := k.GetMetadata(ctx, call.Contract)
+	switch {
+	case found && !existing.IsOwner(sender):
+		return errorsmod.Wrapf(types.ErrUnauthorized, "%s isn't the owner of the metadata of %s", sender.Hex(), call.Contract.Hex())
+	case !found:
+		if crypto.CreateAddress(sender, call.DeployNonce) != call.Contract {
+			return errorsmod.Wrapf(types.ErrNotDeployer, "%s didn't create %s with nonce %d", sender.Hex(), call.Contract.Hex(), call.DeployNonce)
+		}
+		if account := k.evmKeeper.GetAccount(ctx, call.Contract); account == nil || !account.IsContract() {
+			return errorsmod.Wrapf(types.ErrNotDeployer, "no contract is deployed at %s", call.Contract.Hex())
+		}
+	}
+
+	metadata := types.Metadata{
+		Contract:   call.Contract.Hex(),
+		Name:       call.Name,
+		SourceHash: call.SourceHash.Hex(),
+		AuditLink:  call.AuditLink,
+		Owner:      sender.Hex(),
+	}
+	if err := k.SetMetadata(ctx, metadata); err != nil {
+		return err
+	}
+	return ctx.EventManager().EmitTypedEvent(newSetEvent(metadata, false))
+}
+
+// Unregister removes the metadata of contract owned by sender
+func (k Keeper) Unregister(ctx sdk.Context, sender, contract common.Address) error {
+	existing, found := k.GetMetadata(ctx, contract)
+	if !found {
+		return errorsmod.Wrapf(types.ErrNotRegistered, "%s", contract.Hex())
+	}
+	if !existing.IsOwner(sender) {
+		return errorsmod.Wrapf(types.ErrUnauthorized, "%s isn't the owner of the metadata of %s", sender.Hex(), contract.Hex())
+	}
+
+	k.RemoveMetadata(ctx, contract)
+	return ctx.EventManager().EmitTypedEvent(&types.EventRemoveContractMetadata{Contract: contract.Hex()})
+}
+
+// ApplyGovernanceChanges removes then sets the metadata listed by governance
+// and clears both lists. Governance overrides the owner of the metadata, a
+// change that fails, e.g. on a name registered for another contract, is
+// logged and doesn't affect the others.
+func (k Keeper) ApplyGovernanceChanges(ctx sdk.Context) {
+	params := k.GetParams(ctx)
+	if len(params.ApprovedMetadata) == 0 && len(params.RemovedContracts) == 0 {
+		return
+	}
+
+	for _, contract := range params.RemovedContracts {
+		addr := common.HexToAddress(contract)
+		if _, found := k.GetMetadata(ctx, addr); !found {
+			continue
+		}
+		k.RemoveMetadata(ctx, addr)
+		k.emitEvent(ctx, &types.EventRemoveContractMetadata{Contract: addr.Hex(), Governance: true})
+	}
+
+	for _, metadata := range params.ApprovedMetadata {
+		if err := k.SetMetadata(ctx, metadata); err != nil {
+			k.Logger(ctx).Error("failed to set approved contract metadata", "contract", metadata.Contract, "error", err)
+			continue
+		}
+		k.emitEvent(ctx, newSetEvent(metadata, true))
+	}
+
+	k.SetParams(ctx, types.DefaultParams())
+}
+
+// emitEvent emits a typed event. Governance changes run in the end blocker
+// where there is no tx to fail, so an event that can't be encoded is only logged.
+func (k Keeper) emitEvent(ctx sdk.Context, event proto.Message) {
+	if err := ctx.EventManager().EmitTypedEvent(event); err != nil {
+		k.Logger(ctx).Error("failed to emit contractmeta event", "event", proto.MessageName(event), "error", err)
+	}
+}
+
+func newSetEvent(metadata types.Metadata, governance bool) *types.EventSetContractMetadata {
+	metadata = metadata.Normalized()
+	return &types.EventSetContractMetadata{
+		Contract:   metadata.Contract,
+		Name:       metadata.Name,
+		SourceHash: metadata.SourceHash,
+		AuditLink:  metadata.AuditLink,
+		Owner:      metadata.Owner,
+		Governance: governance,
+	}
+}
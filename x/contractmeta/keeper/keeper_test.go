@@ -0,0 +1,264 @@
+package keeper_test
+
+import (
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/evm/x/vm/statedb"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/contractmeta/types"
+)
+
+const deployNonce = 3
+
+var (
+	deployer = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	stranger = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	contract = crypto.CreateAddress(deployer, deployNonce)
+)
+
+// setup returns a chain where deployer deployed contract with deployNonce
+func setup(t *testing.T) (*app.TacChainApp, sdk.Context) {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := tacApp.NewContext(false).WithChainID(app.DefaultChainID)
+
+	deploy(t, tacApp, ctx, contract)
+	return tacApp, ctx
+}
+
+func deploy(t *testing.T, tacApp *app.TacChainApp, ctx sdk.Context, addr common.Address) {
+	t.Helper()
+
+	db := statedb.New(ctx, tacApp.EVMKeeper, statedb.NewEmptyTxConfig(common.BytesToHash(ctx.HeaderHash())))
+	db.SetCode(addr, []byte{0x60, 0x00, 0x60, 0x00, 0xf3}) // return(0, 0)
+	require.NoError(t, db.Commit())
+}
+
+func registerCall(name string) types.RegistryCall {
+	return types.RegistryCall{
+		Method:      types.MethodRegister,
+		Contract:    contract,
+		DeployNonce: deployNonce,
+		Name:        name,
+		SourceHash:  common.HexToHash("0xabcdef"),
+		AuditLink:   "https://audits.example.com/tac-token.pdf",
+	}
+}
+
+// typedEvents returns the contractmeta events emitted on ctx
+func typedEvents(t *testing.T, ctx sdk.Context) []proto.Message {
+	t.Helper()
+
+	var events []proto.Message
+	for _, event := range ctx.EventManager().ABCIEvents() {
+		if event.Type != proto.MessageName(&types.EventSetContractMetadata{}) && event.Type != proto.MessageName(&types.EventRemoveContractMetadata{}) {
+			continue
+		}
+		msg, err := sdk.ParseTypedEvent(event)
+		require.NoError(t, err)
+		events = append(events, msg)
+	}
+	return events
+}
+
+func TestDeployerRegisters(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.ContractMetaKeeper
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+
+	require.NoError(t, k.Register(ctx, deployer, registerCall("Tac Token")))
+
+	metadata, found := k.GetMetadata(ctx, contract)
+	require.True(t, found)
+	require.Equal(t, types.Metadata{
+		Contract:   contract.Hex(),
+		Name:       "Tac Token",
+		SourceHash: common.HexToHash("0xabcdef").Hex(),
+		AuditLink:  "https://audits.example.com/tac-token.pdf",
+		Owner:      deployer.Hex(),
+	}, metadata)
+	byName, found := k.GetContractByName(ctx, "TAC TOKEN")
+	require.True(t, found, "names are looked up regardless of case")
+	require.Equal(t, contract, byName)
+
+	events := typedEvents(t, ctx)
+	require.Len(t, events, 1)
+	event := events[0].(*types.EventSetContractMetadata)
+	require.Equal(t, contract.Hex(), event.Contract)
+	require.Equal(t, deployer.Hex(), event.Owner)
+	require.False(t, event.Governance)
+}
+
+func TestAntiSquatting(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.ContractMetaKeeper
+
+	// only the deployer can register the first metadata of a contract
+	err := k.Register(ctx, stranger, registerCall("Tac Token"))
+	require.ErrorIs(t, err, types.ErrNotDeployer)
+
+	// with the nonce it deployed the contract with
+	call := registerCall("Tac Token")
+	call.DeployNonce++
+	err = k.Register(ctx, deployer, call)
+	require.ErrorIs(t, err, types.ErrNotDeployer)
+
+	// of an address holding code
+	call = registerCall("Tac Token")
+	call.DeployNonce++
+	call.Contract = crypto.CreateAddress(deployer, call.DeployNonce)
+	err = k.Register(ctx, deployer, call)
+	require.ErrorIs(t, err, types.ErrNotDeployer)
+
+	_, found := k.GetMetadata(ctx, contract)
+	require.False(t, found)
+
+	// a name can't be taken from another contract, regardless of case
+	require.NoError(t, k.Register(ctx, deployer, registerCall("Tac Token")))
+	deploy(t, tacApp, ctx, call.Contract)
+	call.Name = "tac token"
+	err = k.Register(ctx, deployer, call)
+	require.ErrorIs(t, err, types.ErrNameTaken)
+	call.Name = "Tac Token v2"
+	require.NoError(t, k.Register(ctx, deployer, call))
+}
+
+func TestOwnerUpdates(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.ContractMetaKeeper
+	require.NoError(t, k.Register(ctx, deployer, registerCall("Tac Token")))
+
+	// nobody but the owner can update or remove the metadata
+	err := k.Register(ctx, stranger, registerCall("Squatted"))
+	require.ErrorIs(t, err, types.ErrUnauthorized)
+	err = k.Unregister(ctx, stranger, contract)
+	require.ErrorIs(t, err, types.ErrUnauthorized)
+
+	// renaming frees the previous name, the deploy nonce isn't checked anymore
+	call := registerCall("Tac Token v2")
+	call.DeployNonce = 0
+	call.AuditLink = ""
+	require.NoError(t, k.Register(ctx, deployer, call))
+	metadata, _ := k.GetMetadata(ctx, contract)
+	require.Equal(t, "Tac Token v2", metadata.Name)
+	require.Empty(t, metadata.AuditLink)
+	_, found := k.GetContractByName(ctx, "Tac Token")
+	require.False(t, found)
+
+	require.NoError(t, k.Unregister(ctx, deployer, contract))
+	_, found = k.GetMetadata(ctx, contract)
+	require.False(t, found)
+	_, found = k.GetContractByName(ctx, "Tac Token v2")
+	require.False(t, found)
+	err = k.Unregister(ctx, deployer, contract)
+	require.ErrorIs(t, err, types.ErrNotRegistered)
+}
+
+func TestGovernanceOverridesOwner(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.ContractMetaKeeper
+	require.NoError(t, k.Register(ctx, deployer, registerCall("Tac Token")))
+
+	other := crypto.CreateAddress(stranger, 0)
+	params := types.DefaultParams()
+	params.ApprovedMetadata = []types.Metadata{
+		{Contract: contract.Hex(), Name: "Official Tac Token", SourceHash: common.HexToHash("0x01").Hex()},
+		// governance doesn't need the contract to be deployed, but names stay unique
+		{Contract: other.Hex(), Name: "Official Tac Token v2", SourceHash: common.HexToHash("0x02").Hex(), Owner: stranger.Hex()},
+	}
+	require.NoError(t, params.Validate())
+	k.SetParams(ctx, params)
+
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	k.ApplyGovernanceChanges(ctx)
+	require.Equal(t, types.DefaultParams(), k.GetParams(ctx), "the approved changes should be cleared")
+	require.Len(t, typedEvents(t, ctx), 2)
+
+	metadata, _ := k.GetMetadata(ctx, contract)
+	require.Equal(t, "Official Tac Token", metadata.Name)
+	require.Empty(t, metadata.Owner)
+	_, found := k.GetContractByName(ctx, "Tac Token")
+	require.False(t, found)
+
+	// the governance owned metadata can't be changed by the deployer anymore,
+	// the owner set by governance can change its metadata
+	err := k.Register(ctx, deployer, registerCall("Tac Token"))
+	require.ErrorIs(t, err, types.ErrUnauthorized)
+	err = k.Unregister(ctx, deployer, contract)
+	require.ErrorIs(t, err, types.ErrUnauthorized)
+	require.NoError(t, k.Unregister(ctx, stranger, other))
+
+	// removals apply before approvals, a name conflicting with another contract is skipped
+	params.ApprovedMetadata = []types.Metadata{
+		{Contract: other.Hex(), Name: "Official Tac Token", SourceHash: common.HexToHash("0x02").Hex()},
+	}
+	params.RemovedContracts = []string{contract.Hex()}
+	k.SetParams(ctx, params)
+	k.ApplyGovernanceChanges(ctx)
+	_, found = k.GetMetadata(ctx, contract)
+	require.False(t, found)
+	byName, _ := k.GetContractByName(ctx, "Official Tac Token")
+	require.Equal(t, other, byName)
+
+	params.RemovedContracts = []string{}
+	params.ApprovedMetadata = []types.Metadata{
+		{Contract: contract.Hex(), Name: "Official Tac Token", SourceHash: common.HexToHash("0x01").Hex()},
+	}
+	k.SetParams(ctx, params)
+	k.ApplyGovernanceChanges(ctx)
+	_, found = k.GetMetadata(ctx, contract)
+	require.False(t, found)
+	require.Equal(t, types.DefaultParams(), k.GetParams(ctx))
+}
+
+func TestHandleRegistryCall(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.ContractMetaKeeper
+
+	data, err := registerCall("Tac Token").Pack()
+	require.NoError(t, err)
+	require.NoError(t, k.HandleRegistryCall(ctx, deployer, data))
+	_, found := k.GetMetadata(ctx, contract)
+	require.True(t, found)
+
+	err = k.HandleRegistryCall(ctx, deployer, []byte{0xde, 0xad, 0xbe, 0xef})
+	require.ErrorIs(t, err, types.ErrInvalidRegistryCall)
+
+	data, err = types.RegistryCall{Method: types.MethodUnregister, Contract: contract}.Pack()
+	require.NoError(t, err)
+	require.ErrorIs(t, k.HandleRegistryCall(ctx, stranger, data), types.ErrUnauthorized)
+	require.NoError(t, k.HandleRegistryCall(ctx, deployer, data))
+	_, found = k.GetMetadata(ctx, contract)
+	require.False(t, found)
+}
+
+func TestGenesisRoundTrip(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.ContractMetaKeeper
+	require.NoError(t, k.Register(ctx, deployer, registerCall("Tac Token")))
+
+	exported := k.ExportGenesis(ctx)
+	require.NoError(t, exported.Validate())
+	require.Len(t, exported.Metadata, 1)
+
+	other, otherCtx := setup(t)
+	other.ContractMetaKeeper.InitGenesis(otherCtx, *exported)
+	require.Equal(t, exported, other.ContractMetaKeeper.ExportGenesis(otherCtx))
+}
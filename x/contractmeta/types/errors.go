@@ -0,0 +1,15 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+)
+
+// x/contractmeta module sentinel errors
+var (
+	ErrInvalidMetadata     = errorsmod.Register(ModuleName, 2, "invalid contract metadata")
+	ErrInvalidRegistryCall = errorsmod.Register(ModuleName, 3, "invalid contract registry call")
+	ErrNotDeployer         = errorsmod.Register(ModuleName, 4, "sender didn't deploy the contract")
+	ErrUnauthorized        = errorsmod.Register(ModuleName, 5, "sender doesn't own the contract metadata")
+	ErrNameTaken           = errorsmod.Register(ModuleName, 6, "contract name already registered")
+	ErrNotRegistered       = errorsmod.Register(ModuleName, 7, "contract has no metadata")
+)
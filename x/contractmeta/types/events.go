@@ -0,0 +1,38 @@
+package types
+
+import (
+	"github.com/cosmos/gogoproto/proto"
+)
+
+// init registers the events of the contract registry, which explorers follow
+// to refresh the names, source hashes and audit links they show.
+func init() {
+	proto.RegisterType((*EventSetContractMetadata)(nil), "tacchain.contractmeta.v1.EventSetContractMetadata")
+	proto.RegisterType((*EventRemoveContractMetadata)(nil), "tacchain.contractmeta.v1.EventRemoveContractMetadata")
+}
+
+// EventSetContractMetadata is emitted when the metadata of a contract is
+// registered or updated, by its owner or by governance.
+type EventSetContractMetadata struct {
+	Contract   string `protobuf:"bytes,1,opt,name=contract,proto3" json:"contract,omitempty"`
+	Name       string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	SourceHash string `protobuf:"bytes,3,opt,name=source_hash,json=sourceHash,proto3" json:"source_hash,omitempty"`
+	AuditLink  string `protobuf:"bytes,4,opt,name=audit_link,json=auditLink,proto3" json:"audit_link,omitempty"`
+	Owner      string `protobuf:"bytes,5,opt,name=owner,proto3" json:"owner,omitempty"`
+	Governance bool   `protobuf:"varint,6,opt,name=governance,proto3" json:"governance,omitempty"`
+}
+
+func (m *EventSetContractMetadata) Reset()         { *m = EventSetContractMetadata{} }
+func (m *EventSetContractMetadata) String() string { return proto.CompactTextString(m) }
+func (*EventSetContractMetadata) ProtoMessage()    {}
+
+// EventRemoveContractMetadata is emitted when the metadata of a contract is
+// removed, by its owner or by governance.
+type EventRemoveContractMetadata struct {
+	Contract   string `protobuf:"bytes,1,opt,name=contract,proto3" json:"contract,omitempty"`
+	Governance bool   `protobuf:"varint,2,opt,name=governance,proto3" json:"governance,omitempty"`
+}
+
+func (m *EventRemoveContractMetadata) Reset()         { *m = EventRemoveContractMetadata{} }
+func (m *EventRemoveContractMetadata) String() string { return proto.CompactTextString(m) }
+func (*EventRemoveContractMetadata) ProtoMessage()    {}
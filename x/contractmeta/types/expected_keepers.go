@@ -0,0 +1,14 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/evm/x/vm/statedb"
+)
+
+// EVMKeeper defines the expected EVM keeper used to check a contract is deployed
+type EVMKeeper interface {
+	GetAccount(ctx sdk.Context, addr common.Address) *statedb.Account
+}
@@ -0,0 +1,23 @@
+package types
+
+// GenesisState defines the contractmeta module genesis state
+type GenesisState struct {
+	Params   Params     `json:"params" yaml:"params"`
+	Metadata []Metadata `json:"metadata" yaml:"metadata"`
+}
+
+// DefaultGenesisState returns the default contractmeta module genesis state
+func DefaultGenesisState() *GenesisState {
+	return &GenesisState{
+		Params:   DefaultParams(),
+		Metadata: []Metadata{},
+	}
+}
+
+// Validate performs basic genesis state validation
+func (gs GenesisState) Validate() error {
+	if err := gs.Params.Validate(); err != nil {
+		return err
+	}
+	return validateMetadataList(gs.Metadata)
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+const (
+	// ModuleName defines the contractmeta module name
+	ModuleName = "contractmeta"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+)
+
+var (
+	// MetadataPrefix prefixes the metadata of a contract, keyed by its address
+	MetadataPrefix = []byte{0x01}
+	// NamePrefix indexes the registered contracts by their lower cased name
+	NamePrefix = []byte{0x02}
+)
+
+// MetadataKey returns the store key of the metadata of contract
+func MetadataKey(contract common.Address) []byte {
+	return append(append([]byte{}, MetadataPrefix...), contract.Bytes()...)
+}
+
+// NameKey returns the index key of name, names are unique regardless of case
+func NameKey(name string) []byte {
+	return append(append([]byte{}, NamePrefix...), []byte(strings.ToLower(name))...)
+}
+
+// RegistryAddress returns the address deployers send their registry calls to.
+// It is derived like a module account address, no account lives there and no
+// code runs at it, the calls are handled after the transaction executed.
+func RegistryAddress() common.Address {
+	return common.BytesToAddress(authtypes.NewModuleAddress(ModuleName))
+}
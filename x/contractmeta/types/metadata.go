@@ -0,0 +1,125 @@
+package types
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+)
+
+const (
+	// MaxNameLength bounds the length of a contract name
+	MaxNameLength = 64
+	// MaxAuditLinkLength bounds the length of an audit link
+	MaxAuditLinkLength = 256
+)
+
+// Metadata describes a contract for explorers and wallets. Contract, Owner and
+// SourceHash are 0x prefixed hex strings.
+//
+// The Owner is the only account allowed to update or remove the metadata. An
+// empty Owner means the metadata was set by governance and only governance
+// can change it.
+type Metadata struct {
+	Contract string `json:"contract" yaml:"contract"`
+	Name     string `json:"name" yaml:"name"`
+	// SourceHash is the 32 bytes hash of the verified sources, e.g. the keccak256
+	// of the standard JSON input of the compiler
+	SourceHash string `json:"source_hash" yaml:"source_hash"`
+	// AuditLink is an optional https link to the audit report of the contract
+	AuditLink string `json:"audit_link" yaml:"audit_link"`
+	Owner     string `json:"owner" yaml:"owner"`
+}
+
+// ContractAddress returns the address of the contract, the metadata must be valid
+func (m Metadata) ContractAddress() common.Address {
+	return common.HexToAddress(m.Contract)
+}
+
+// Normalized returns the metadata with checksummed addresses and a lower case
+// source hash, so the stored metadata doesn't depend on the case the sender
+// used. The metadata must be valid.
+func (m Metadata) Normalized() Metadata {
+	m.Contract = m.ContractAddress().Hex()
+	if m.Owner != "" {
+		m.Owner = common.HexToAddress(m.Owner).Hex()
+	}
+	m.SourceHash = strings.ToLower(m.SourceHash)
+	return m
+}
+
+// IsOwner returns true if addr owns the metadata
+func (m Metadata) IsOwner(addr common.Address) bool {
+	return m.Owner != "" && common.HexToAddress(m.Owner) == addr
+}
+
+// Validate checks the metadata is well formed
+func (m Metadata) Validate() error {
+	if !isHexAddress(m.Contract) {
+		return errorsmod.Wrapf(ErrInvalidMetadata, "invalid contract address: %q", m.Contract)
+	}
+	if m.Owner != "" && !isHexAddress(m.Owner) {
+		return errorsmod.Wrapf(ErrInvalidMetadata, "invalid owner address: %q", m.Owner)
+	}
+	if err := ValidateName(m.Name); err != nil {
+		return err
+	}
+
+	hash := strings.TrimPrefix(m.SourceHash, "0x")
+	if hash == m.SourceHash || len(hash) != 2*common.HashLength || !isHex(hash) {
+		return errorsmod.Wrapf(ErrInvalidMetadata, "source hash must be 32 bytes of 0x prefixed hex: %q", m.SourceHash)
+	}
+
+	if m.AuditLink != "" {
+		if len(m.AuditLink) > MaxAuditLinkLength {
+			return errorsmod.Wrapf(ErrInvalidMetadata, "audit link longer than %d characters", MaxAuditLinkLength)
+		}
+		link, err := url.Parse(m.AuditLink)
+		if err != nil || link.Scheme != "https" || link.Host == "" {
+			return errorsmod.Wrapf(ErrInvalidMetadata, "audit link must be an https url: %q", m.AuditLink)
+		}
+	}
+
+	return nil
+}
+
+// ValidateName checks name is 1 to MaxNameLength printable characters without
+// surrounding spaces. A name can't be a hex address, so a contract is looked up
+// by address or name without ambiguity.
+func ValidateName(name string) error {
+	if name == "" || len(name) > MaxNameLength {
+		return errorsmod.Wrapf(ErrInvalidMetadata, "name must be 1 to %d characters: %q", MaxNameLength, name)
+	}
+	if !utf8.ValidString(name) {
+		return errorsmod.Wrapf(ErrInvalidMetadata, "name isn't valid utf-8: %q", name)
+	}
+	if strings.TrimSpace(name) != name {
+		return errorsmod.Wrapf(ErrInvalidMetadata, "name can't start or end with spaces: %q", name)
+	}
+	for _, r := range name {
+		if !unicode.IsPrint(r) {
+			return errorsmod.Wrapf(ErrInvalidMetadata, "name has non printable characters: %q", name)
+		}
+	}
+	if common.IsHexAddress(name) {
+		return errorsmod.Wrapf(ErrInvalidMetadata, "name can't be an address: %q", name)
+	}
+	return nil
+}
+
+func isHexAddress(s string) bool {
+	return common.IsHexAddress(s) && common.HexToAddress(s) != (common.Address{})
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
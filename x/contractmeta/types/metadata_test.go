@@ -0,0 +1,142 @@
+package types_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Asphere-xyz/tacchain/x/contractmeta/types"
+)
+
+var (
+	testContract = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	testOwner    = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	testHash     = common.HexToHash("0xabcdef")
+)
+
+func validMetadata() types.Metadata {
+	return types.Metadata{
+		Contract:   testContract.Hex(),
+		Name:       "Tac Token",
+		SourceHash: testHash.Hex(),
+		AuditLink:  "https://audits.example.com/tac-token.pdf",
+		Owner:      testOwner.Hex(),
+	}
+}
+
+func TestMetadataValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(m *types.Metadata)
+		valid  bool
+	}{
+		{"valid", func(*types.Metadata) {}, true},
+		{"governance owned", func(m *types.Metadata) { m.Owner = "" }, true},
+		{"no audit link", func(m *types.Metadata) { m.AuditLink = "" }, true},
+		{"lower case addresses", func(m *types.Metadata) { m.Contract = strings.ToLower(m.Contract) }, true},
+		{"invalid contract", func(m *types.Metadata) { m.Contract = "tac1xyz" }, false},
+		{"zero contract", func(m *types.Metadata) { m.Contract = common.Address{}.Hex() }, false},
+		{"invalid owner", func(m *types.Metadata) { m.Owner = "0x1234" }, false},
+		{"empty name", func(m *types.Metadata) { m.Name = "" }, false},
+		{"long name", func(m *types.Metadata) { m.Name = strings.Repeat("a", types.MaxNameLength+1) }, false},
+		{"max name", func(m *types.Metadata) { m.Name = strings.Repeat("a", types.MaxNameLength) }, true},
+		{"padded name", func(m *types.Metadata) { m.Name = " Tac" }, false},
+		{"control characters", func(m *types.Metadata) { m.Name = "Tac\nToken" }, false},
+		{"invalid utf-8", func(m *types.Metadata) { m.Name = "Tac\xff" }, false},
+		{"address as name", func(m *types.Metadata) { m.Name = testOwner.Hex() }, false},
+		{"unprefixed source hash", func(m *types.Metadata) { m.SourceHash = strings.TrimPrefix(m.SourceHash, "0x") }, false},
+		{"short source hash", func(m *types.Metadata) { m.SourceHash = "0xabcdef" }, false},
+		{"non hex source hash", func(m *types.Metadata) { m.SourceHash = "0x" + strings.Repeat("g", 64) }, false},
+		{"http audit link", func(m *types.Metadata) { m.AuditLink = "http://audits.example.com" }, false},
+		{"relative audit link", func(m *types.Metadata) { m.AuditLink = "audits/tac-token.pdf" }, false},
+		{"long audit link", func(m *types.Metadata) {
+			m.AuditLink = "https://example.com/" + strings.Repeat("a", types.MaxAuditLinkLength)
+		}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			metadata := validMetadata()
+			tc.modify(&metadata)
+			err := metadata.Validate()
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, types.ErrInvalidMetadata)
+			}
+		})
+	}
+}
+
+func TestMetadataNormalized(t *testing.T) {
+	metadata := validMetadata()
+	metadata.Contract = strings.ToLower(metadata.Contract)
+	metadata.Owner = strings.ToLower(metadata.Owner)
+	metadata.SourceHash = "0x" + strings.ToUpper(strings.TrimPrefix(metadata.SourceHash, "0x"))
+
+	require.Equal(t, validMetadata(), metadata.Normalized())
+	require.True(t, metadata.IsOwner(testOwner))
+	require.False(t, metadata.IsOwner(testContract))
+
+	metadata.Owner = ""
+	require.False(t, metadata.IsOwner(common.Address{}), "nobody owns the metadata of governance")
+}
+
+func TestParamsValidate(t *testing.T) {
+	require.NoError(t, types.DefaultParams().Validate())
+
+	params := types.DefaultParams()
+	other := validMetadata()
+	other.Contract = testOwner.Hex()
+	other.Name = "Other"
+	params.ApprovedMetadata = []types.Metadata{validMetadata(), other}
+	params.RemovedContracts = []string{testContract.Hex()}
+	require.NoError(t, params.Validate())
+
+	// the same contract twice
+	other.Contract = strings.ToLower(testContract.Hex())
+	params.ApprovedMetadata = []types.Metadata{validMetadata(), other}
+	require.Error(t, params.Validate())
+
+	// the same name twice, regardless of case
+	other.Contract = testOwner.Hex()
+	other.Name = "TAC TOKEN"
+	params.ApprovedMetadata = []types.Metadata{validMetadata(), other}
+	require.Error(t, params.Validate())
+
+	params.ApprovedMetadata = []types.Metadata{validMetadata()}
+	params.RemovedContracts = []string{testContract.Hex(), strings.ToLower(testContract.Hex())}
+	require.Error(t, params.Validate())
+	params.RemovedContracts = []string{"0x1234"}
+	require.Error(t, params.Validate())
+}
+
+func TestRegistryCallRoundTrip(t *testing.T) {
+	register := types.RegistryCall{
+		Method:      types.MethodRegister,
+		Contract:    testContract,
+		DeployNonce: 7,
+		Name:        "Tac Token",
+		SourceHash:  testHash,
+		AuditLink:   "https://audits.example.com/tac-token.pdf",
+	}
+	data, err := register.Pack()
+	require.NoError(t, err)
+	parsed, err := types.ParseRegistryCall(data)
+	require.NoError(t, err)
+	require.Equal(t, register, parsed)
+
+	unregister := types.RegistryCall{Method: types.MethodUnregister, Contract: testContract}
+	data, err = unregister.Pack()
+	require.NoError(t, err)
+	parsed, err = types.ParseRegistryCall(data)
+	require.NoError(t, err)
+	require.Equal(t, unregister, parsed)
+
+	for _, data := range [][]byte{nil, {0x01, 0x02}, {0xde, 0xad, 0xbe, 0xef}, data[:20]} {
+		_, err := types.ParseRegistryCall(data)
+		require.ErrorIs(t, err, types.ErrInvalidRegistryCall)
+	}
+}
@@ -0,0 +1,108 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+var (
+	// KeyApprovedMetadata is the param store key for the metadata set by governance
+	KeyApprovedMetadata = []byte("ApprovedMetadata")
+	// KeyRemovedContracts is the param store key for the contracts whose metadata governance removes
+	KeyRemovedContracts = []byte("RemovedContracts")
+)
+
+// Params defines the contractmeta module parameters. Governance sets or
+// removes metadata through a ParameterChangeProposal on ApprovedMetadata or
+// RemovedContracts, the module applies them at the end of the block, removals
+// first, and clears both lists afterwards.
+//
+// Governance overrides any owner, an approved metadata with an empty Owner
+// can only be changed by governance again.
+type Params struct {
+	ApprovedMetadata []Metadata `json:"approved_metadata" yaml:"approved_metadata"`
+	RemovedContracts []string   `json:"removed_contracts" yaml:"removed_contracts"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the contractmeta module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default contractmeta module parameters
+func DefaultParams() Params {
+	return Params{
+		ApprovedMetadata: []Metadata{},
+		RemovedContracts: []string{},
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyApprovedMetadata, &p.ApprovedMetadata, validateApprovedMetadata),
+		paramtypes.NewParamSetPair(KeyRemovedContracts, &p.RemovedContracts, validateRemovedContracts),
+	}
+}
+
+// Validate performs basic validation of the contractmeta module parameters
+func (p Params) Validate() error {
+	if err := validateApprovedMetadata(p.ApprovedMetadata); err != nil {
+		return err
+	}
+	return validateRemovedContracts(p.RemovedContracts)
+}
+
+func validateApprovedMetadata(i interface{}) error {
+	metadata, ok := i.([]Metadata)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return validateMetadataList(metadata)
+}
+
+// validateMetadataList checks every metadata is valid and the list has no
+// duplicate contract or name.
+func validateMetadataList(metadata []Metadata) error {
+	contracts := make(map[common.Address]bool, len(metadata))
+	names := make(map[string]bool, len(metadata))
+	for _, m := range metadata {
+		if err := m.Validate(); err != nil {
+			return err
+		}
+		if contracts[m.ContractAddress()] {
+			return fmt.Errorf("duplicate metadata for %s", m.Contract)
+		}
+		contracts[m.ContractAddress()] = true
+		if names[strings.ToLower(m.Name)] {
+			return fmt.Errorf("duplicate contract name %q", m.Name)
+		}
+		names[strings.ToLower(m.Name)] = true
+	}
+	return nil
+}
+
+func validateRemovedContracts(i interface{}) error {
+	contracts, ok := i.([]string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	seen := make(map[common.Address]bool, len(contracts))
+	for _, contract := range contracts {
+		if !isHexAddress(contract) {
+			return fmt.Errorf("invalid contract address: %q", contract)
+		}
+		if seen[common.HexToAddress(contract)] {
+			return fmt.Errorf("duplicate removed contract %s", contract)
+		}
+		seen[common.HexToAddress(contract)] = true
+	}
+	return nil
+}
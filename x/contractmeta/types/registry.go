@@ -0,0 +1,85 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+)
+
+const (
+	// MethodRegister sets the metadata of a contract deployed by the sender, or
+	// updates the metadata it owns
+	MethodRegister = "register"
+	// MethodUnregister removes the metadata owned by the sender
+	MethodUnregister = "unregister"
+)
+
+// registryABI is the interface of the calls sent to RegistryAddress
+const registryABI = `[
+	{"type":"function","name":"register","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"contractAddress","type":"address"},
+		{"name":"deployNonce","type":"uint64"},
+		{"name":"name","type":"string"},
+		{"name":"sourceHash","type":"bytes32"},
+		{"name":"auditLink","type":"string"}
+	]},
+	{"type":"function","name":"unregister","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"contractAddress","type":"address"}
+	]}
+]`
+
+// RegistryABI is the parsed interface of the registry calls
+var RegistryABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(registryABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// RegistryCall is a decoded call to the registry. DeployNonce is the nonce
+// the sender deployed the contract with, which proves it is the deployer of
+// a contract without metadata.
+type RegistryCall struct {
+	Method      string
+	Contract    common.Address
+	DeployNonce uint64
+	Name        string
+	SourceHash  common.Hash
+	AuditLink   string
+}
+
+// ParseRegistryCall decodes the calldata of a registry call
+func ParseRegistryCall(data []byte) (RegistryCall, error) {
+	if len(data) < 4 {
+		return RegistryCall{}, errorsmod.Wrap(ErrInvalidRegistryCall, "missing method selector")
+	}
+	method, err := RegistryABI.MethodById(data[:4])
+	if err != nil {
+		return RegistryCall{}, errorsmod.Wrap(ErrInvalidRegistryCall, err.Error())
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return RegistryCall{}, errorsmod.Wrapf(ErrInvalidRegistryCall, "failed to decode %s arguments: %s", method.Name, err)
+	}
+
+	call := RegistryCall{Method: method.Name, Contract: args[0].(common.Address)}
+	if method.Name == MethodRegister {
+		call.DeployNonce = args[1].(uint64)
+		call.Name = args[2].(string)
+		call.SourceHash = common.Hash(args[3].([32]byte))
+		call.AuditLink = args[4].(string)
+	}
+	return call, nil
+}
+
+// Pack encodes the call as calldata for the registry
+func (c RegistryCall) Pack() ([]byte, error) {
+	if c.Method == MethodUnregister {
+		return RegistryABI.Pack(MethodUnregister, c.Contract)
+	}
+	return RegistryABI.Pack(MethodRegister, c.Contract, c.DeployNonce, c.Name, [32]byte(c.SourceHash), c.AuditLink)
+}
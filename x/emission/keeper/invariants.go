@@ -0,0 +1,30 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/emission/types"
+)
+
+// RegisterInvariants registers the emission module invariants
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	ir.RegisterRoute(types.ModuleName, "supply-cap", SupplyCapInvariant(k))
+}
+
+// SupplyCapInvariant checks that the supply of the mint denom doesn't exceed
+// the max supply
+func SupplyCapInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		params := k.GetParams(ctx)
+		if !params.IsCapped() {
+			return sdk.FormatInvariant(types.ModuleName, "supply-cap", "supply is not capped"), false
+		}
+
+		supply := k.bankKeeper.GetSupply(ctx, params.MintDenom)
+		broken := supply.Amount.GT(params.MaxSupply)
+		return sdk.FormatInvariant(types.ModuleName, "supply-cap",
+			fmt.Sprintf("supply %s, max supply %s%s", supply, params.MaxSupply, params.MintDenom)), broken
+	}
+}
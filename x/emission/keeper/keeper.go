@@ -0,0 +1,137 @@
+package keeper
+
+import (
+	"context"
+
+	errorsmod "cosmossdk.io/errors"
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/Asphere-xyz/tacchain/x/emission/types"
+)
+
+// Keeper of the emission module
+type Keeper struct {
+	paramSpace       paramtypes.Subspace
+	bankKeeper       types.BankKeeper
+	hooks            types.EmissionHooks
+	feeCollectorName string
+}
+
+// NewKeeper creates a new emission Keeper instance. Minted coins are sent to
+// the feeCollectorName module account and distributed like fees.
+func NewKeeper(paramSpace paramtypes.Subspace, bankKeeper types.BankKeeper, feeCollectorName string) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		paramSpace:       paramSpace,
+		bankKeeper:       bankKeeper,
+		feeCollectorName: feeCollectorName,
+	}
+}
+
+// SetHooks sets the hooks called once the provision of a block is minted, they
+// can only be set once
+func (k *Keeper) SetHooks(hooks types.EmissionHooks) *Keeper {
+	if k.hooks != nil {
+		panic("cannot set emission hooks twice")
+	}
+	k.hooks = hooks
+	return k
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current emission module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the emission module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// RemainingMintable returns the amount of the mint denom that can still be
+// minted before the supply reaches the max supply, false if it isn't capped.
+func (k Keeper) RemainingMintable(ctx context.Context) (sdkmath.Int, bool) {
+	params := k.GetParams(sdk.UnwrapSDKContext(ctx))
+	if !params.IsCapped() {
+		return sdkmath.ZeroInt(), false
+	}
+	return params.RemainingMintable(k.bankKeeper.GetSupply(ctx, params.MintDenom).Amount), true
+}
+
+// CheckSupplyCap rejects a provision of coins that would take the supply of
+// the mint denom over the max supply. The cap only applies to the provisions
+// of this module: the EVM mints and burns the mint denom to move value, and
+// the erc20, IBC transfer and bridge modules mint their own coins, capping
+// every mint of the bank keeper would fail their txs.
+func (k Keeper) CheckSupplyCap(ctx context.Context, coins sdk.Coins) error {
+	params := k.GetParams(sdk.UnwrapSDKContext(ctx))
+	if !params.IsCapped() {
+		return nil
+	}
+	amount := coins.AmountOf(params.MintDenom)
+	if amount.IsZero() {
+		return nil
+	}
+
+	remaining := params.RemainingMintable(k.bankKeeper.GetSupply(ctx, params.MintDenom).Amount)
+	if amount.GT(remaining) {
+		return errorsmod.Wrapf(types.ErrMaxSupplyExceeded, "minting %s%s, only %s%s left under the cap of %s%s",
+			amount, params.MintDenom, remaining, params.MintDenom, params.MaxSupply, params.MintDenom)
+	}
+	return nil
+}
+
+// MintBlockProvision mints the provision scheduled for the current block to
+// the fee collector and returns the minted amount. The provision is cut to
+// what is left under the max supply.
+func (k Keeper) MintBlockProvision(ctx sdk.Context) (sdkmath.Int, error) {
+	params := k.GetParams(ctx)
+	height := ctx.BlockHeight()
+
+	amount := params.BlockProvisionAt(height)
+	if remaining, capped := k.RemainingMintable(ctx); capped {
+		amount = sdkmath.MinInt(amount, remaining)
+	}
+	if !amount.IsPositive() {
+		return sdkmath.ZeroInt(), nil
+	}
+
+	coins := sdk.NewCoins(sdk.NewCoin(params.MintDenom, amount))
+	if err := k.CheckSupplyCap(ctx, coins); err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+	if err := k.bankKeeper.MintCoins(ctx, types.ModuleName, coins); err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+	if err := k.bankKeeper.SendCoinsFromModuleToModule(ctx, types.ModuleName, k.feeCollectorName, coins); err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+
+	if err := ctx.EventManager().EmitTypedEvent(&types.EventMint{
+		Amount:           amount.String(),
+		AnnualProvisions: params.AnnualProvisionsAt(height).String(),
+		Reductions:       params.Reductions(height),
+	}); err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+	if k.hooks != nil {
+		if err := k.hooks.AfterMintBlockProvision(ctx, coins[0]); err != nil {
+			return sdkmath.ZeroInt(), err
+		}
+	}
+
+	return amount, nil
+}
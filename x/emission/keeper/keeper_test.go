@@ -0,0 +1,185 @@
+package keeper_test
+
+import (
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/emission/keeper"
+	"github.com/Asphere-xyz/tacchain/x/emission/types"
+)
+
+func setup(t *testing.T) (*app.TacChainApp, sdk.Context) {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	return tacApp, tacApp.NewContext(false).WithChainID(app.DefaultChainID)
+}
+
+func TestDefaultParamsMintNothing(t *testing.T) {
+	tacApp, ctx := setup(t)
+
+	params := tacApp.EmissionKeeper.GetParams(ctx)
+	require.NoError(t, params.Validate())
+	require.Equal(t, app.BaseDenom, params.MintDenom)
+	require.True(t, params.AnnualProvisions.IsZero())
+	supply := tacApp.BankKeeper.GetSupply(ctx, app.BaseDenom)
+
+	minted, err := tacApp.EmissionKeeper.MintBlockProvision(ctx.WithBlockHeight(10))
+	require.NoError(t, err)
+	require.True(t, minted.IsZero())
+	require.Equal(t, supply, tacApp.BankKeeper.GetSupply(ctx, app.BaseDenom))
+}
+
+func TestMintOverSimulatedYears(t *testing.T) {
+	tacApp, ctx := setup(t)
+
+	const blocksPerYear = 100
+	params := types.DefaultParams()
+	params.MintDenom = app.BaseDenom
+	params.AnnualProvisions = sdkmath.NewInt(1_000_000)
+	params.BlocksPerYear = blocksPerYear
+	params.StartHeight = 5
+	params.ReductionInterval = 2 * blocksPerYear
+	require.NoError(t, params.Validate())
+	tacApp.EmissionKeeper.SetParams(ctx, params)
+
+	initialSupply := tacApp.BankKeeper.GetSupply(ctx, app.BaseDenom).Amount
+	feeCollector := tacApp.AccountKeeper.GetModuleAddress(authtypes.FeeCollectorName)
+	initialFees := tacApp.BankKeeper.GetBalance(ctx, feeCollector, app.BaseDenom).Amount
+
+	// the provisions of a year, halved every 2 years
+	expectedYearly := []int64{1_000_000, 1_000_000, 500_000, 500_000, 250_000, 250_000, 125_000, 125_000, 62_500, 62_500}
+	expectedSupply := initialSupply
+	for year, provisions := range expectedYearly {
+		for i := int64(0); i < blocksPerYear; i++ {
+			height := params.StartHeight + int64(year)*blocksPerYear + i
+			_, err := tacApp.EmissionKeeper.MintBlockProvision(ctx.WithBlockHeight(height))
+			require.NoError(t, err)
+		}
+
+		expectedSupply = expectedSupply.AddRaw(provisions)
+		supply := tacApp.BankKeeper.GetSupply(ctx, app.BaseDenom).Amount
+		require.Equal(t, expectedSupply.String(), supply.String(), "year %d", year+1)
+
+		lastHeight := params.StartHeight + int64(year+1)*blocksPerYear - 1
+		require.Equal(t, initialSupply.Add(params.ProvisionsBetween(0, lastHeight)).String(), supply.String(), "year %d", year+1)
+	}
+
+	// everything was minted to the fee collector
+	fees := tacApp.BankKeeper.GetBalance(ctx, feeCollector, app.BaseDenom).Amount
+	require.Equal(t, expectedSupply.Sub(initialSupply).String(), fees.Sub(initialFees).String())
+}
+
+func TestMintEvent(t *testing.T) {
+	tacApp, ctx := setup(t)
+
+	params := types.DefaultParams()
+	params.MintDenom = app.BaseDenom
+	params.AnnualProvisions = sdkmath.NewInt(4000)
+	params.BlocksPerYear = 10
+	params.ReductionInterval = 10
+	tacApp.EmissionKeeper.SetParams(ctx, params)
+
+	ctx = ctx.WithBlockHeight(15).WithEventManager(sdk.NewEventManager())
+	minted, err := tacApp.EmissionKeeper.MintBlockProvision(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(200), minted.Int64())
+
+	var events []*types.EventMint
+	for _, event := range ctx.EventManager().ABCIEvents() {
+		if event.Type != proto.MessageName(&types.EventMint{}) {
+			continue
+		}
+		msg, err := sdk.ParseTypedEvent(event)
+		require.NoError(t, err)
+		events = append(events, msg.(*types.EventMint))
+	}
+	require.Len(t, events, 1)
+	require.Equal(t, "200", events[0].Amount)
+	require.Equal(t, uint64(1), events[0].Reductions)
+}
+
+func TestMintHaltsAtMaxSupply(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.EmissionKeeper
+
+	params := types.DefaultParams()
+	params.MintDenom = app.BaseDenom
+	params.AnnualProvisions = sdkmath.NewInt(10_000)
+	params.BlocksPerYear = 10
+	supply := tacApp.BankKeeper.GetSupply(ctx, app.BaseDenom).Amount
+	// two and a half block provisions are left under the cap
+	params.MaxSupply = supply.AddRaw(2500)
+	tacApp.EmissionKeeper.SetParams(ctx, params)
+
+	remaining, capped := k.RemainingMintable(ctx)
+	require.True(t, capped)
+	require.Equal(t, int64(2500), remaining.Int64())
+
+	for i, expected := range []int64{1000, 1000, 500, 0, 0} {
+		minted, err := k.MintBlockProvision(ctx.WithBlockHeight(int64(i + 1)))
+		require.NoError(t, err)
+		require.Equal(t, expected, minted.Int64(), "block %d", i+1)
+	}
+	require.Equal(t, params.MaxSupply.String(), tacApp.BankKeeper.GetSupply(ctx, app.BaseDenom).Amount.String(), "supply should reach the cap exactly")
+	remaining, _ = k.RemainingMintable(ctx)
+	require.True(t, remaining.IsZero())
+
+	_, broken := keeper.SupplyCapInvariant(k)(ctx)
+	require.False(t, broken)
+
+	// lowering the cap under the supply breaks the invariant
+	params.MaxSupply = params.MaxSupply.SubRaw(1)
+	k.SetParams(ctx, params)
+	_, broken = keeper.SupplyCapInvariant(k)(ctx)
+	require.True(t, broken)
+}
+
+func TestMaxSupplyOnlyCapsEmission(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.EmissionKeeper
+
+	params := types.DefaultParams()
+	params.MintDenom = app.BaseDenom
+	params.MaxSupply = tacApp.BankKeeper.GetSupply(ctx, app.BaseDenom).Amount.AddRaw(100)
+	k.SetParams(ctx, params)
+
+	over := sdk.NewCoins(sdk.NewCoin(app.BaseDenom, sdkmath.NewInt(101)))
+	require.ErrorIs(t, k.CheckSupplyCap(ctx, over), types.ErrMaxSupplyExceeded)
+	upToCap := sdk.NewCoins(sdk.NewCoin(app.BaseDenom, sdkmath.NewInt(100)))
+	require.NoError(t, k.CheckSupplyCap(ctx, upToCap))
+	// other denoms aren't capped
+	other := sdk.NewCoins(sdk.NewCoin("uother", sdkmath.NewInt(1_000_000)))
+	require.NoError(t, k.CheckSupplyCap(ctx, other))
+
+	// the EVM mints the mint denom over the cap to move value within a tx,
+	// and burns it back, the bank keeper doesn't restrict its mints
+	require.NoError(t, tacApp.BankKeeper.MintCoins(ctx, evmvmtypes.ModuleName, over))
+	require.NoError(t, tacApp.BankKeeper.BurnCoins(ctx, evmvmtypes.ModuleName, over))
+
+	// coins burnt below the cap can be provisioned again
+	burnt := sdk.NewCoins(sdk.NewCoin(app.BaseDenom, sdkmath.NewInt(10)))
+	require.NoError(t, tacApp.BankKeeper.MintCoins(ctx, minttypes.ModuleName, upToCap))
+	require.ErrorIs(t, k.CheckSupplyCap(ctx, burnt), types.ErrMaxSupplyExceeded)
+	require.NoError(t, tacApp.BankKeeper.SendCoinsFromModuleToModule(ctx, minttypes.ModuleName, evmvmtypes.ModuleName, burnt))
+	require.NoError(t, tacApp.BankKeeper.BurnCoins(ctx, evmvmtypes.ModuleName, burnt))
+	require.NoError(t, k.CheckSupplyCap(ctx, burnt))
+}
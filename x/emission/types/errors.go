@@ -0,0 +1,10 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+)
+
+// x/emission module sentinel errors
+var (
+	ErrMaxSupplyExceeded = errorsmod.Register(ModuleName, 2, "max supply exceeded")
+)
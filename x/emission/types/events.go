@@ -0,0 +1,23 @@
+package types
+
+import (
+	"github.com/cosmos/gogoproto/proto"
+)
+
+// init registers EventMint, emitted with the provision minted to the fee
+// collector each block.
+func init() {
+	proto.RegisterType((*EventMint)(nil), "tacchain.emission.v1.EventMint")
+}
+
+// EventMint is emitted when the block provision was minted to the fee
+// collector.
+type EventMint struct {
+	Amount           string `protobuf:"bytes,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	AnnualProvisions string `protobuf:"bytes,2,opt,name=annual_provisions,json=annualProvisions,proto3" json:"annual_provisions,omitempty"`
+	Reductions       uint64 `protobuf:"varint,3,opt,name=reductions,proto3" json:"reductions,omitempty"`
+}
+
+func (m *EventMint) Reset()         { *m = EventMint{} }
+func (m *EventMint) String() string { return proto.CompactTextString(m) }
+func (*EventMint) ProtoMessage()    {}
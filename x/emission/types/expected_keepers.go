@@ -0,0 +1,20 @@
+package types
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// BankKeeper defines the expected bank keeper
+type BankKeeper interface {
+	MintCoins(ctx context.Context, moduleName string, amt sdk.Coins) error
+	SendCoinsFromModuleToModule(ctx context.Context, senderModule, recipientModule string, amt sdk.Coins) error
+	GetSupply(ctx context.Context, denom string) sdk.Coin
+}
+
+// EmissionHooks are called by the emission module once it minted the
+// provision of a block
+type EmissionHooks interface {
+	AfterMintBlockProvision(ctx sdk.Context, amount sdk.Coin) error
+}
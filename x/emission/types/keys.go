@@ -0,0 +1,6 @@
+package types
+
+const (
+	// ModuleName defines the emission module name
+	ModuleName = "emission"
+)
@@ -0,0 +1,263 @@
+package types
+
+import (
+	"fmt"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+const (
+	// DefaultBlocksPerYear is the number of 2s blocks in a year
+	DefaultBlocksPerYear uint64 = 15_768_000
+	// DefaultReductionYears is the number of years between two reductions of
+	// the provisions by default
+	DefaultReductionYears uint64 = 4
+)
+
+var (
+	// KeyMintDenom is the param store key for the denom of the minted coins
+	KeyMintDenom = []byte("MintDenom")
+	// KeyAnnualProvisions is the param store key for the provisions minted in a year before any reduction
+	KeyAnnualProvisions = []byte("AnnualProvisions")
+	// KeyBlocksPerYear is the param store key for the number of blocks the annual provisions are minted over
+	KeyBlocksPerYear = []byte("BlocksPerYear")
+	// KeyStartHeight is the param store key for the height the schedule starts at
+	KeyStartHeight = []byte("StartHeight")
+	// KeyReductionInterval is the param store key for the number of blocks between two reductions
+	KeyReductionInterval = []byte("ReductionInterval")
+	// KeyReductionFactor is the param store key for the factor applied to the provisions at every reduction
+	KeyReductionFactor = []byte("ReductionFactor")
+	// KeyMaxSupply is the param store key for the cap on the supply of the mint denom
+	KeyMaxSupply = []byte("MaxSupply")
+)
+
+// DefaultReductionFactor halves the provisions at every reduction
+var DefaultReductionFactor = sdkmath.LegacyNewDecWithPrec(5, 1)
+
+// Params defines the emission module parameters. The module mints fixed
+// annual provisions from StartHeight on, spread evenly over the BlocksPerYear
+// blocks of a year, and multiplies them by ReductionFactor every
+// ReductionInterval blocks, a factor of 0.5 halving them. A zero
+// ReductionInterval keeps the provisions constant.
+//
+// A non-zero MaxSupply caps the supply of the mint denom: the last provision
+// is cut to reach the cap exactly and this module mints nothing afterwards.
+// Other modules aren't capped, the EVM mints and burns the mint denom to move
+// value within a transaction. The cap applies to the supply at the time of minting,
+// so coins burnt below the cap can be minted again. Governance must not lower
+// it under the current supply, which would break the supply cap invariant.
+//
+// Unlike x/mint the provisions don't depend on the bonded ratio or the supply,
+// so the supply minted over the years is known in advance, see
+// ProvisionsBetween.
+type Params struct {
+	MintDenom         string            `json:"mint_denom" yaml:"mint_denom"`
+	AnnualProvisions  sdkmath.Int       `json:"annual_provisions" yaml:"annual_provisions"`
+	BlocksPerYear     uint64            `json:"blocks_per_year" yaml:"blocks_per_year"`
+	StartHeight       int64             `json:"start_height" yaml:"start_height"`
+	ReductionInterval uint64            `json:"reduction_interval" yaml:"reduction_interval"`
+	ReductionFactor   sdkmath.LegacyDec `json:"reduction_factor" yaml:"reduction_factor"`
+	MaxSupply         sdkmath.Int       `json:"max_supply" yaml:"max_supply"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the emission module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default emission module parameters, which mint nothing
+// until governance sets the annual provisions.
+func DefaultParams() Params {
+	return Params{
+		MintDenom:         sdk.DefaultBondDenom,
+		AnnualProvisions:  sdkmath.ZeroInt(),
+		BlocksPerYear:     DefaultBlocksPerYear,
+		StartHeight:       0,
+		ReductionInterval: DefaultReductionYears * DefaultBlocksPerYear,
+		ReductionFactor:   DefaultReductionFactor,
+		MaxSupply:         sdkmath.ZeroInt(),
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyMintDenom, &p.MintDenom, validateMintDenom),
+		paramtypes.NewParamSetPair(KeyAnnualProvisions, &p.AnnualProvisions, validateAnnualProvisions),
+		paramtypes.NewParamSetPair(KeyBlocksPerYear, &p.BlocksPerYear, validateBlocksPerYear),
+		paramtypes.NewParamSetPair(KeyStartHeight, &p.StartHeight, validateStartHeight),
+		paramtypes.NewParamSetPair(KeyReductionInterval, &p.ReductionInterval, validateReductionInterval),
+		paramtypes.NewParamSetPair(KeyReductionFactor, &p.ReductionFactor, validateReductionFactor),
+		paramtypes.NewParamSetPair(KeyMaxSupply, &p.MaxSupply, validateMaxSupply),
+	}
+}
+
+// Validate performs basic validation of the emission module parameters
+func (p Params) Validate() error {
+	if err := validateMintDenom(p.MintDenom); err != nil {
+		return err
+	}
+	if err := validateAnnualProvisions(p.AnnualProvisions); err != nil {
+		return err
+	}
+	if err := validateBlocksPerYear(p.BlocksPerYear); err != nil {
+		return err
+	}
+	if err := validateStartHeight(p.StartHeight); err != nil {
+		return err
+	}
+	if err := validateReductionInterval(p.ReductionInterval); err != nil {
+		return err
+	}
+	if err := validateReductionFactor(p.ReductionFactor); err != nil {
+		return err
+	}
+	return validateMaxSupply(p.MaxSupply)
+}
+
+// IsCapped returns true if the supply of the mint denom is capped
+func (p Params) IsCapped() bool {
+	return !p.MaxSupply.IsNil() && p.MaxSupply.IsPositive()
+}
+
+// RemainingMintable returns the amount that can still be minted when the
+// supply of the mint denom is supply, zero once the cap is reached. It is only
+// meaningful if the supply is capped.
+func (p Params) RemainingMintable(supply sdkmath.Int) sdkmath.Int {
+	if supply.GTE(p.MaxSupply) {
+		return sdkmath.ZeroInt()
+	}
+	return p.MaxSupply.Sub(supply)
+}
+
+// Reductions returns the number of reductions applied to the provisions at
+// height.
+func (p Params) Reductions(height int64) uint64 {
+	if p.ReductionInterval == 0 || height < p.StartHeight {
+		return 0
+	}
+	return uint64(height-p.StartHeight) / p.ReductionInterval
+}
+
+// AnnualProvisionsAt returns the annual provisions scheduled at height, zero
+// before StartHeight.
+func (p Params) AnnualProvisionsAt(height int64) sdkmath.LegacyDec {
+	if height < p.StartHeight || !p.AnnualProvisions.IsPositive() {
+		return sdkmath.LegacyZeroDec()
+	}
+	return sdkmath.LegacyNewDecFromInt(p.AnnualProvisions).Mul(p.ReductionFactor.Power(p.Reductions(height)))
+}
+
+// BlockProvisionAt returns the amount minted in the block at height, the
+// annual provisions at height spread over BlocksPerYear blocks and truncated.
+func (p Params) BlockProvisionAt(height int64) sdkmath.Int {
+	return p.AnnualProvisionsAt(height).QuoInt64(int64(p.BlocksPerYear)).TruncateInt()
+}
+
+// NextReductionHeight returns the height of the first reduction after height,
+// zero if the provisions are never reduced.
+func (p Params) NextReductionHeight(height int64) int64 {
+	if p.ReductionInterval == 0 || !p.AnnualProvisions.IsPositive() {
+		return 0
+	}
+	if height < p.StartHeight {
+		return p.StartHeight + int64(p.ReductionInterval)
+	}
+	return p.StartHeight + int64((p.Reductions(height)+1)*p.ReductionInterval)
+}
+
+// ProvisionsBetween returns the amount minted in the blocks from height from to
+// height to included, summing the block provisions of each reduction period.
+func (p Params) ProvisionsBetween(from, to int64) sdkmath.Int {
+	from = max(from, p.StartHeight)
+	total := sdkmath.ZeroInt()
+	for from <= to {
+		end := to
+		if next := p.NextReductionHeight(from); next != 0 {
+			end = min(end, next-1)
+		}
+		provision := p.BlockProvisionAt(from)
+		if provision.IsZero() && end != to {
+			// provisions only decrease, nothing is minted after this period
+			break
+		}
+		total = total.Add(provision.MulRaw(end - from + 1))
+		from = end + 1
+	}
+	return total
+}
+
+func validateMintDenom(i interface{}) error {
+	denom, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return sdk.ValidateDenom(denom)
+}
+
+func validateAnnualProvisions(i interface{}) error {
+	provisions, ok := i.(sdkmath.Int)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if provisions.IsNil() || provisions.IsNegative() {
+		return fmt.Errorf("annual provisions must be non-negative: %s", provisions)
+	}
+	return nil
+}
+
+func validateBlocksPerYear(i interface{}) error {
+	blocks, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if blocks == 0 {
+		return fmt.Errorf("blocks per year must be positive")
+	}
+	return nil
+}
+
+func validateStartHeight(i interface{}) error {
+	height, ok := i.(int64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if height < 0 {
+		return fmt.Errorf("start height must be non-negative: %d", height)
+	}
+	return nil
+}
+
+func validateReductionInterval(i interface{}) error {
+	if _, ok := i.(uint64); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return nil
+}
+
+func validateMaxSupply(i interface{}) error {
+	maxSupply, ok := i.(sdkmath.Int)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if maxSupply.IsNil() || maxSupply.IsNegative() {
+		return fmt.Errorf("max supply must be non-negative: %s", maxSupply)
+	}
+	return nil
+}
+
+func validateReductionFactor(i interface{}) error {
+	factor, ok := i.(sdkmath.LegacyDec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if factor.IsNil() || !factor.IsPositive() || factor.GT(sdkmath.LegacyOneDec()) {
+		return fmt.Errorf("reduction factor must be in (0, 1]: %s", factor)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/Asphere-xyz/tacchain/x/emission/types"
+)
+
+// halvingParams mints 1024 per block over years of 10 blocks and halves the
+// provisions every 4 years, so every block provision is exact until the 10th
+// halving.
+func halvingParams() types.Params {
+	params := types.DefaultParams()
+	params.AnnualProvisions = sdkmath.NewInt(10240)
+	params.BlocksPerYear = 10
+	params.StartHeight = 1
+	params.ReductionInterval = 40
+	return params
+}
+
+func TestParamsValidate(t *testing.T) {
+	require.NoError(t, types.DefaultParams().Validate())
+	require.NoError(t, halvingParams().Validate())
+
+	testCases := []struct {
+		name   string
+		modify func(*types.Params)
+	}{
+		{"invalid denom", func(p *types.Params) { p.MintDenom = "" }},
+		{"nil provisions", func(p *types.Params) { p.AnnualProvisions = sdkmath.Int{} }},
+		{"negative provisions", func(p *types.Params) { p.AnnualProvisions = sdkmath.NewInt(-1) }},
+		{"zero blocks per year", func(p *types.Params) { p.BlocksPerYear = 0 }},
+		{"negative start height", func(p *types.Params) { p.StartHeight = -1 }},
+		{"zero reduction factor", func(p *types.Params) { p.ReductionFactor = sdkmath.LegacyZeroDec() }},
+		{"reduction factor above one", func(p *types.Params) { p.ReductionFactor = sdkmath.LegacyNewDecWithPrec(11, 1) }},
+		{"nil max supply", func(p *types.Params) { p.MaxSupply = sdkmath.Int{} }},
+		{"negative max supply", func(p *types.Params) { p.MaxSupply = sdkmath.NewInt(-1) }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := halvingParams()
+			tc.modify(&params)
+			require.Error(t, params.Validate())
+		})
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	params := halvingParams()
+
+	require.True(t, params.AnnualProvisionsAt(0).IsZero(), "nothing is minted before the start height")
+	require.Equal(t, int64(1024), params.BlockProvisionAt(1).Int64())
+	require.Equal(t, int64(1024), params.BlockProvisionAt(40).Int64())
+	require.Equal(t, int64(512), params.BlockProvisionAt(41).Int64())
+	require.Equal(t, int64(256), params.BlockProvisionAt(81).Int64())
+	require.Equal(t, uint64(2), params.Reductions(81))
+
+	require.Equal(t, int64(41), params.NextReductionHeight(0))
+	require.Equal(t, int64(41), params.NextReductionHeight(1))
+	require.Equal(t, int64(81), params.NextReductionHeight(41))
+
+	// without reductions the provisions stay constant
+	params.ReductionInterval = 0
+	require.Equal(t, int64(1024), params.BlockProvisionAt(1_000_000).Int64())
+	require.Zero(t, params.NextReductionHeight(1))
+
+	require.True(t, types.DefaultParams().BlockProvisionAt(1).IsZero(), "the default params mint nothing")
+}
+
+func TestProjectedSupply(t *testing.T) {
+	params := halvingParams()
+	provisions := params.AnnualProvisions.Int64()
+	blocksPerYear := int64(params.BlocksPerYear)
+
+	// simulate 100 years block by block
+	minted := sdkmath.ZeroInt()
+	for height := int64(1); height <= 100*blocksPerYear; height++ {
+		minted = minted.Add(params.BlockProvisionAt(height))
+
+		if height%blocksPerYear != 0 {
+			continue
+		}
+		year := height / blocksPerYear
+		require.Equal(t, minted.Int64(), params.ProvisionsBetween(1, height).Int64(), "year %d", year)
+
+		// halving every 4 years, 4 * P * (1 + 1/2 + ... + 1/2^(k-1)) = 8P - 8P/2^k
+		// is minted after k halvings, the block provisions are exact until the 10th
+		if year%4 == 0 && year <= 40 {
+			halvings := year / 4
+			require.Equal(t, 8*provisions-8*provisions>>halvings, minted.Int64(), "year %d", year)
+		}
+		require.True(t, minted.LTE(sdkmath.NewInt(8*provisions)), "the supply never exceeds 8 annual provisions")
+	}
+
+	// after 40 years the provision of 10 a year is 1 per block, then rounds down to zero
+	require.Equal(t, 8*provisions-80+40, minted.Int64())
+	require.Equal(t, minted.Int64(), params.ProvisionsBetween(0, 1_000_000_000).Int64())
+
+	// the provisions of a range of blocks
+	require.Equal(t, int64(5*1024+5*512), params.ProvisionsBetween(36, 45).Int64())
+}
+
+func TestRemainingMintable(t *testing.T) {
+	params := halvingParams()
+	require.False(t, params.IsCapped(), "a zero max supply doesn't cap the supply")
+	params.MaxSupply = sdkmath.Int{}
+	require.False(t, params.IsCapped(), "params set before the max supply existed aren't capped")
+
+	params.MaxSupply = sdkmath.NewInt(1000)
+	require.True(t, params.IsCapped())
+	require.Equal(t, int64(1000), params.RemainingMintable(sdkmath.ZeroInt()).Int64())
+	require.Equal(t, int64(1), params.RemainingMintable(sdkmath.NewInt(999)).Int64())
+	require.True(t, params.RemainingMintable(sdkmath.NewInt(1000)).IsZero())
+	require.True(t, params.RemainingMintable(sdkmath.NewInt(2000)).IsZero())
+}
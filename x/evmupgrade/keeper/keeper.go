@@ -0,0 +1,136 @@
+package keeper
+
+import (
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"cosmossdk.io/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/Asphere-xyz/tacchain/x/evmupgrade/types"
+)
+
+// Keeper of the evmupgrade module
+type Keeper struct {
+	paramSpace paramtypes.Subspace
+	evmKeeper  types.EVMKeeper
+}
+
+// NewKeeper creates a new evmupgrade Keeper instance
+func NewKeeper(paramSpace paramtypes.Subspace, evmKeeper types.EVMKeeper) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		paramSpace: paramSpace,
+		evmKeeper:  evmKeeper,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current evmupgrade module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the evmupgrade module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// ActivateEIPs adds the EIPs scheduled at the current height to the extra
+// EIPs of the x/vm params, which the EVM enables for every transaction from
+// this block on. EIPs already enabled are left as they are. It returns the
+// activated EIPs.
+func (k Keeper) ActivateEIPs(ctx sdk.Context) ([]int64, error) {
+	scheduled := k.GetParams(ctx).ActivationsAt(ctx.BlockHeight())
+	if len(scheduled) == 0 {
+		return nil, nil
+	}
+
+	evmParams := k.evmKeeper.GetParams(ctx)
+	var activated []int64
+	for _, eip := range scheduled {
+		if slices.Contains(evmParams.ExtraEIPs, eip) {
+			continue
+		}
+		evmParams.ExtraEIPs = append(evmParams.ExtraEIPs, eip)
+		activated = append(activated, eip)
+	}
+	if len(activated) == 0 {
+		return nil, nil
+	}
+	if err := k.evmKeeper.SetParams(ctx, evmParams); err != nil {
+		return nil, err
+	}
+
+	for _, eip := range activated {
+		k.Logger(ctx).Info("activated EIP", "eip", eip, "height", ctx.BlockHeight())
+		if err := ctx.EventManager().EmitTypedEvent(&types.EventActivateEIP{
+			Eip:    eip,
+			Height: ctx.BlockHeight(),
+		}); err != nil {
+			return activated, err
+		}
+	}
+	return activated, nil
+}
+
+// ChangePrecompiles activates and deactivates the static precompiles as
+// scheduled at the current height in the active static precompiles of the x/vm
+// params, the EVM runs every transaction from this block on with the new set.
+// Precompiles already in the scheduled state are left as they are. It returns
+// the applied changes.
+func (k Keeper) ChangePrecompiles(ctx sdk.Context) ([]types.PrecompileChange, error) {
+	scheduled := k.GetParams(ctx).PrecompileChangesAt(ctx.BlockHeight())
+	if len(scheduled) == 0 {
+		return nil, nil
+	}
+
+	evmParams := k.evmKeeper.GetParams(ctx)
+	var changed []types.PrecompileChange
+	for _, change := range scheduled {
+		address := common.HexToAddress(change.Address)
+		i := slices.IndexFunc(evmParams.ActiveStaticPrecompiles, func(active string) bool {
+			return common.HexToAddress(active) == address
+		})
+		switch {
+		case change.Active && i < 0:
+			evmParams.ActiveStaticPrecompiles = append(evmParams.ActiveStaticPrecompiles, address.Hex())
+		case !change.Active && i >= 0:
+			evmParams.ActiveStaticPrecompiles = slices.Delete(evmParams.ActiveStaticPrecompiles, i, i+1)
+		default:
+			continue
+		}
+		changed = append(changed, change)
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	// x/vm requires the active precompiles sorted
+	slices.Sort(evmParams.ActiveStaticPrecompiles)
+	if err := k.evmKeeper.SetParams(ctx, evmParams); err != nil {
+		return nil, err
+	}
+
+	for _, change := range changed {
+		k.Logger(ctx).Info("changed precompile", "address", change.Address, "active", change.Active, "height", ctx.BlockHeight())
+		if err := ctx.EventManager().EmitTypedEvent(&types.EventChangePrecompile{
+			Address: common.HexToAddress(change.Address).Hex(),
+			Active:  change.Active,
+			Height:  ctx.BlockHeight(),
+		}); err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
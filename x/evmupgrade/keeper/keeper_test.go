@@ -0,0 +1,155 @@
+package keeper_test
+
+import (
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/evmupgrade/types"
+)
+
+func setup(t *testing.T) (*app.TacChainApp, sdk.Context) {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	return tacApp, tacApp.NewContext(false).WithChainID(app.DefaultChainID)
+}
+
+func TestActivateEIPsAtHeight(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.EVMUpgradeKeeper
+
+	evmParams := tacApp.EVMKeeper.GetParams(ctx)
+	evmParams.ExtraEIPs = []int64{3855}
+	require.NoError(t, tacApp.EVMKeeper.SetParams(ctx, evmParams))
+
+	params := types.Params{Activations: []types.Activation{
+		{EIP: 1153, Height: 10},
+		{EIP: 5656, Height: 10},
+		// already enabled, left as it is
+		{EIP: 3855, Height: 12},
+	}}
+	require.NoError(t, params.Validate())
+	k.SetParams(ctx, params)
+
+	testCases := []struct {
+		height    int64
+		activated []int64
+		extraEIPs []int64
+	}{
+		{9, nil, []int64{3855}},
+		{10, []int64{1153, 5656}, []int64{3855, 1153, 5656}},
+		{11, nil, []int64{3855, 1153, 5656}},
+		{12, nil, []int64{3855, 1153, 5656}},
+	}
+	for _, tc := range testCases {
+		blockCtx := ctx.WithBlockHeight(tc.height).WithEventManager(sdk.NewEventManager())
+		activated, err := k.ActivateEIPs(blockCtx)
+		require.NoError(t, err, "height %d", tc.height)
+		require.Equal(t, tc.activated, activated, "height %d", tc.height)
+		require.Equal(t, tc.extraEIPs, tacApp.EVMKeeper.GetParams(ctx).ExtraEIPs, "height %d", tc.height)
+
+		var events []*types.EventActivateEIP
+		for _, event := range blockCtx.EventManager().ABCIEvents() {
+			if event.Type != proto.MessageName(&types.EventActivateEIP{}) {
+				continue
+			}
+			msg, err := sdk.ParseTypedEvent(event)
+			require.NoError(t, err)
+			events = append(events, msg.(*types.EventActivateEIP))
+		}
+		require.Len(t, events, len(tc.activated), "height %d", tc.height)
+		for i, event := range events {
+			require.Equal(t, tc.activated[i], event.Eip)
+			require.Equal(t, tc.height, event.Height)
+		}
+	}
+
+	// the EVM params stay valid with the activated EIPs
+	require.NoError(t, tacApp.EVMKeeper.GetParams(ctx).Validate())
+}
+
+func TestDefaultParamsActivateNothing(t *testing.T) {
+	tacApp, ctx := setup(t)
+
+	require.Empty(t, tacApp.EVMUpgradeKeeper.GetParams(ctx).Activations)
+	extraEIPs := tacApp.EVMKeeper.GetParams(ctx).ExtraEIPs
+	activated, err := tacApp.EVMUpgradeKeeper.ActivateEIPs(ctx.WithBlockHeight(1))
+	require.NoError(t, err)
+	require.Empty(t, activated)
+	require.Equal(t, extraEIPs, tacApp.EVMKeeper.GetParams(ctx).ExtraEIPs)
+}
+
+func TestChangePrecompilesAtHeight(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.EVMUpgradeKeeper
+
+	const (
+		staking = "0x0000000000000000000000000000000000000800"
+		bank    = "0x0000000000000000000000000000000000000804"
+		gov     = "0x0000000000000000000000000000000000000805"
+	)
+	evmParams := tacApp.EVMKeeper.GetParams(ctx)
+	evmParams.ActiveStaticPrecompiles = []string{staking, bank}
+	require.NoError(t, tacApp.EVMKeeper.SetParams(ctx, evmParams))
+
+	params := types.Params{PrecompileChanges: []types.PrecompileChange{
+		{Address: gov, Active: true, Height: 10},
+		{Address: bank, Active: false, Height: 10},
+		// already inactive, left as it is
+		{Address: bank, Active: false, Height: 11},
+		{Address: bank, Active: true, Height: 12},
+	}}
+	require.NoError(t, params.Validate())
+	k.SetParams(ctx, params)
+
+	testCases := []struct {
+		height  int64
+		changed []types.PrecompileChange
+		active  []string
+	}{
+		{9, nil, []string{staking, bank}},
+		{10, params.PrecompileChanges[:2], []string{staking, gov}},
+		{11, nil, []string{staking, gov}},
+		{12, params.PrecompileChanges[3:], []string{staking, bank, gov}},
+		{13, nil, []string{staking, bank, gov}},
+	}
+	for _, tc := range testCases {
+		blockCtx := ctx.WithBlockHeight(tc.height).WithEventManager(sdk.NewEventManager())
+		changed, err := k.ChangePrecompiles(blockCtx)
+		require.NoError(t, err, "height %d", tc.height)
+		require.Equal(t, tc.changed, changed, "height %d", tc.height)
+		evmParams := tacApp.EVMKeeper.GetParams(ctx)
+		require.Equal(t, tc.active, evmParams.ActiveStaticPrecompiles, "height %d", tc.height)
+		// the EVM params stay valid, sorted, with the changed precompiles
+		require.NoError(t, evmParams.Validate(), "height %d", tc.height)
+
+		var events []*types.EventChangePrecompile
+		for _, event := range blockCtx.EventManager().ABCIEvents() {
+			if event.Type != proto.MessageName(&types.EventChangePrecompile{}) {
+				continue
+			}
+			msg, err := sdk.ParseTypedEvent(event)
+			require.NoError(t, err)
+			events = append(events, msg.(*types.EventChangePrecompile))
+		}
+		require.Len(t, events, len(tc.changed), "height %d", tc.height)
+		for i, event := range events {
+			require.Equal(t, tc.changed[i].Address, event.Address)
+			require.Equal(t, tc.changed[i].Active, event.Active)
+			require.Equal(t, tc.height, event.Height)
+		}
+	}
+}
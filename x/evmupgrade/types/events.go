@@ -0,0 +1,35 @@
+package types
+
+import (
+	"github.com/cosmos/gogoproto/proto"
+)
+
+// init registers the events of the scheduled EIP activations and precompile
+// changes, which tests/e2e decodes from the block results.
+func init() {
+	proto.RegisterType((*EventActivateEIP)(nil), "tacchain.evmupgrade.v1.EventActivateEIP")
+	proto.RegisterType((*EventChangePrecompile)(nil), "tacchain.evmupgrade.v1.EventChangePrecompile")
+}
+
+// EventActivateEIP is emitted when a scheduled EIP was added to the extra EIPs
+// of the EVM.
+type EventActivateEIP struct {
+	Eip    int64 `protobuf:"varint,1,opt,name=eip,proto3" json:"eip,omitempty"`
+	Height int64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (m *EventActivateEIP) Reset()         { *m = EventActivateEIP{} }
+func (m *EventActivateEIP) String() string { return proto.CompactTextString(m) }
+func (*EventActivateEIP) ProtoMessage()    {}
+
+// EventChangePrecompile is emitted when a scheduled precompile change was
+// applied to the active static precompiles of the EVM.
+type EventChangePrecompile struct {
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Active  bool   `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
+	Height  int64  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (m *EventChangePrecompile) Reset()         { *m = EventChangePrecompile{} }
+func (m *EventChangePrecompile) String() string { return proto.CompactTextString(m) }
+func (*EventChangePrecompile) ProtoMessage()    {}
@@ -0,0 +1,13 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+// EVMKeeper defines the expected EVM keeper
+type EVMKeeper interface {
+	GetParams(ctx sdk.Context) evmvmtypes.Params
+	SetParams(ctx sdk.Context, params evmvmtypes.Params) error
+}
@@ -0,0 +1,194 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	evmvmcore "github.com/ethereum/go-ethereum/core/vm"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	evmvmtypes "github.com/cosmos/evm/x/vm/types"
+
+	nameservicetypes "github.com/Asphere-xyz/tacchain/x/nameservice/types"
+)
+
+var (
+	// KeyActivations is the param store key for the scheduled EIP activations
+	KeyActivations = []byte("Activations")
+	// KeyPrecompileChanges is the param store key for the scheduled changes of
+	// the active precompiles
+	KeyPrecompileChanges = []byte("PrecompileChanges")
+)
+
+// Activation schedules the activation of an EIP at a block height
+type Activation struct {
+	// EIP is the number of the EIP, one the EVM can enable on top of the
+	// forks of the chain config
+	EIP int64 `json:"eip" yaml:"eip"`
+	// Height is the first block whose transactions run with the EIP enabled
+	Height int64 `json:"height" yaml:"height"`
+}
+
+// PrecompileChange schedules the activation or deactivation of a static
+// precompile at a block height
+type PrecompileChange struct {
+	// Address is the hex address of the precompile, one the binary provides
+	Address string `json:"address" yaml:"address"`
+	// Active is true to activate the precompile, false to deactivate it
+	Active bool `json:"active" yaml:"active"`
+	// Height is the first block whose transactions run with the change
+	Height int64 `json:"height" yaml:"height"`
+}
+
+// Params defines the evmupgrade module parameters. The forks of the EVM are
+// set by the chain config of the binary, the EIPs enabled on top of them are
+// the extra EIPs of the x/vm params. Activations lets governance schedule the
+// addition of an EIP to the extra EIPs at a height, so every node enables its
+// opcodes or gas changes from the same block on without a binary upgrade.
+//
+// An activation only applies at its height: a proposal scheduling one must
+// pick a height after its voting period ends, like an upgrade plan. Executed
+// activations can stay in the params, removing the EIP later takes a change of
+// the x/vm params.
+//
+// PrecompileChanges schedules the same way the activation and deactivation of
+// the static precompiles the binary provides (staking, bank, gov...) in the
+// active static precompiles of the x/vm params. A precompile can be changed
+// several times at different heights, e.g. deactivated then activated again.
+type Params struct {
+	Activations       []Activation       `json:"activations" yaml:"activations"`
+	PrecompileChanges []PrecompileChange `json:"precompile_changes" yaml:"precompile_changes"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the evmupgrade module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default evmupgrade module parameters
+func DefaultParams() Params {
+	return Params{
+		Activations:       []Activation{},
+		PrecompileChanges: []PrecompileChange{},
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyActivations, &p.Activations, validateActivations),
+		paramtypes.NewParamSetPair(KeyPrecompileChanges, &p.PrecompileChanges, validatePrecompileChanges),
+	}
+}
+
+// Validate performs basic validation of the evmupgrade module parameters
+func (p Params) Validate() error {
+	if err := validateActivations(p.Activations); err != nil {
+		return err
+	}
+	return validatePrecompileChanges(p.PrecompileChanges)
+}
+
+// ActivationsAt returns the EIPs activated at height
+func (p Params) ActivationsAt(height int64) []int64 {
+	var eips []int64
+	for _, activation := range p.Activations {
+		if activation.Height == height {
+			eips = append(eips, activation.EIP)
+		}
+	}
+	return eips
+}
+
+// PrecompileChangesAt returns the precompile changes scheduled at height
+func (p Params) PrecompileChangesAt(height int64) []PrecompileChange {
+	var changes []PrecompileChange
+	for _, change := range p.PrecompileChanges {
+		if change.Height == height {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// Validate checks the EIP can be enabled by the EVM and the height is positive
+func (a Activation) Validate() error {
+	if a.EIP < 0 || !evmvmcore.ValidEip(int(a.EIP)) {
+		return fmt.Errorf("EIP %d can't be enabled by the EVM", a.EIP)
+	}
+	if a.Height <= 0 {
+		return fmt.Errorf("activation height of EIP %d must be positive: %d", a.EIP, a.Height)
+	}
+	return nil
+}
+
+func validateActivations(i interface{}) error {
+	activations, ok := i.([]Activation)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	seen := make(map[int64]bool, len(activations))
+	for _, activation := range activations {
+		if err := activation.Validate(); err != nil {
+			return err
+		}
+		if seen[activation.EIP] {
+			return fmt.Errorf("duplicate activation of EIP %d", activation.EIP)
+		}
+		seen[activation.EIP] = true
+	}
+	return nil
+}
+
+// AvailablePrecompiles returns the static precompiles the binary provides,
+// those of Cosmos EVM and the name resolver of x/nameservice
+func AvailablePrecompiles() []string {
+	return append(slices.Clone(evmvmtypes.AvailableStaticPrecompiles), nameservicetypes.ResolverPrecompileAddress)
+}
+
+// Validate checks the precompile is provided by the binary and the height is
+// positive
+func (c PrecompileChange) Validate() error {
+	if !common.IsHexAddress(c.Address) {
+		return fmt.Errorf("invalid precompile address: %s", c.Address)
+	}
+	address := common.HexToAddress(c.Address)
+	if !slices.ContainsFunc(AvailablePrecompiles(), func(available string) bool {
+		return common.HexToAddress(available) == address
+	}) {
+		return fmt.Errorf("precompile %s isn't available, the available precompiles are %v", c.Address, AvailablePrecompiles())
+	}
+	if c.Height <= 0 {
+		return fmt.Errorf("change height of precompile %s must be positive: %d", c.Address, c.Height)
+	}
+	return nil
+}
+
+func validatePrecompileChanges(i interface{}) error {
+	changes, ok := i.([]PrecompileChange)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	type key struct {
+		address common.Address
+		height  int64
+	}
+	seen := make(map[key]bool, len(changes))
+	for _, change := range changes {
+		if err := change.Validate(); err != nil {
+			return err
+		}
+		k := key{common.HexToAddress(change.Address), change.Height}
+		if seen[k] {
+			return fmt.Errorf("duplicate change of precompile %s at height %d", change.Address, change.Height)
+		}
+		seen[k] = true
+	}
+	return nil
+}
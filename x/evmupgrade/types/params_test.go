@@ -0,0 +1,79 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Asphere-xyz/tacchain/x/evmupgrade/types"
+)
+
+func TestParamsValidate(t *testing.T) {
+	require.NoError(t, types.DefaultParams().Validate())
+
+	valid := types.Params{Activations: []types.Activation{{EIP: 1153, Height: 100}, {EIP: 5656, Height: 100}}}
+	require.NoError(t, valid.Validate())
+
+	testCases := []struct {
+		name        string
+		activations []types.Activation
+	}{
+		{"unknown EIP", []types.Activation{{EIP: 9999, Height: 100}}},
+		{"negative EIP", []types.Activation{{EIP: -1, Height: 100}}},
+		{"zero height", []types.Activation{{EIP: 1153, Height: 0}}},
+		{"negative height", []types.Activation{{EIP: 1153, Height: -1}}},
+		{"duplicate EIP", []types.Activation{{EIP: 1153, Height: 100}, {EIP: 1153, Height: 200}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, types.Params{Activations: tc.activations}.Validate())
+		})
+	}
+}
+
+func TestActivationsAt(t *testing.T) {
+	params := types.Params{Activations: []types.Activation{{EIP: 1153, Height: 100}, {EIP: 5656, Height: 200}, {EIP: 3855, Height: 100}}}
+	require.Equal(t, []int64{1153, 3855}, params.ActivationsAt(100))
+	require.Equal(t, []int64{5656}, params.ActivationsAt(200))
+	require.Empty(t, params.ActivationsAt(99))
+	require.Empty(t, params.ActivationsAt(101))
+}
+
+const (
+	stakingPrecompile = "0x0000000000000000000000000000000000000800"
+	bankPrecompile    = "0x0000000000000000000000000000000000000804"
+)
+
+func TestPrecompileChangesValidate(t *testing.T) {
+	valid := types.Params{PrecompileChanges: []types.PrecompileChange{
+		{Address: bankPrecompile, Active: false, Height: 100},
+		{Address: bankPrecompile, Active: true, Height: 200},
+		{Address: stakingPrecompile, Active: true, Height: 100},
+	}}
+	require.NoError(t, valid.Validate())
+
+	testCases := []struct {
+		name    string
+		changes []types.PrecompileChange
+	}{
+		{"invalid address", []types.PrecompileChange{{Address: "0x0804", Active: true, Height: 100}}},
+		{"unavailable precompile", []types.PrecompileChange{{Address: "0x0000000000000000000000000000000000000999", Active: true, Height: 100}}},
+		{"zero height", []types.PrecompileChange{{Address: bankPrecompile, Active: true, Height: 0}}},
+		{"negative height", []types.PrecompileChange{{Address: bankPrecompile, Active: true, Height: -1}}},
+		{"duplicate change", []types.PrecompileChange{{Address: bankPrecompile, Active: true, Height: 100}, {Address: bankPrecompile, Active: false, Height: 100}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, types.Params{PrecompileChanges: tc.changes}.Validate())
+		})
+	}
+}
+
+func TestPrecompileChangesAt(t *testing.T) {
+	deactivate := types.PrecompileChange{Address: bankPrecompile, Active: false, Height: 100}
+	activate := types.PrecompileChange{Address: bankPrecompile, Active: true, Height: 200}
+	params := types.Params{PrecompileChanges: []types.PrecompileChange{deactivate, activate}}
+	require.Equal(t, []types.PrecompileChange{deactivate}, params.PrecompileChangesAt(100))
+	require.Equal(t, []types.PrecompileChange{activate}, params.PrecompileChangesAt(200))
+	require.Empty(t, params.PrecompileChangesAt(150))
+}
@@ -0,0 +1,120 @@
+package keeper
+
+import (
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/Asphere-xyz/tacchain/x/feeburn/types"
+)
+
+// Keeper of the feeburn module
+type Keeper struct {
+	paramSpace       paramtypes.Subspace
+	bankKeeper       types.BankKeeper
+	feeMarketKeeper  types.FeeMarketKeeper
+	hooks            types.FeeBurnHooks
+	feeCollectorName string
+	denom            string
+}
+
+// NewKeeper creates a new feeburn Keeper instance. The fees in denom collected
+// by the feeCollectorName module account are burnt, fees paid in other denoms
+// are always distributed.
+func NewKeeper(paramSpace paramtypes.Subspace, bankKeeper types.BankKeeper, feeMarketKeeper types.FeeMarketKeeper, feeCollectorName, denom string) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		paramSpace:       paramSpace,
+		bankKeeper:       bankKeeper,
+		feeMarketKeeper:  feeMarketKeeper,
+		feeCollectorName: feeCollectorName,
+		denom:            denom,
+	}
+}
+
+// SetHooks sets the hooks called once fees are burnt, they can only be set
+// once
+func (k *Keeper) SetHooks(hooks types.FeeBurnHooks) *Keeper {
+	if k.hooks != nil {
+		panic("cannot set feeburn hooks twice")
+	}
+	k.hooks = hooks
+	return k
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current feeburn module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the feeburn module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// Utilization returns the gas utilization of the previous block, its gas
+// wanted as tracked by the fee market over the block gas limit.
+func (k Keeper) Utilization(ctx sdk.Context) sdkmath.LegacyDec {
+	var maxGas int64
+	if block := ctx.ConsensusParams().Block; block != nil {
+		maxGas = block.MaxGas
+	}
+	return types.Utilization(k.feeMarketKeeper.GetBlockGasWanted(ctx), maxGas)
+}
+
+// BurnFees burns the share of the fees collected in the previous block set by
+// its gas utilization and returns the burnt amount. It must run before
+// x/distribution hands the fees out.
+func (k Keeper) BurnFees(ctx sdk.Context) (sdkmath.Int, error) {
+	params := k.GetParams(ctx)
+	if params.MaxBurnRatio.IsZero() {
+		return sdkmath.ZeroInt(), nil
+	}
+
+	fees := k.bankKeeper.GetBalance(ctx, authtypes.NewModuleAddress(k.feeCollectorName), k.denom)
+	if !fees.IsPositive() {
+		return sdkmath.ZeroInt(), nil
+	}
+
+	utilization := k.Utilization(ctx)
+	ratio := params.BurnRatio(utilization)
+	amount := ratio.MulInt(fees.Amount).TruncateInt()
+	if !amount.IsPositive() {
+		return sdkmath.ZeroInt(), nil
+	}
+
+	coins := sdk.NewCoins(sdk.NewCoin(k.denom, amount))
+	if err := k.bankKeeper.SendCoinsFromModuleToModule(ctx, k.feeCollectorName, types.ModuleName, coins); err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+	if err := k.bankKeeper.BurnCoins(ctx, types.ModuleName, coins); err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+
+	if err := ctx.EventManager().EmitTypedEvent(&types.EventBurnFees{
+		Amount:      amount.String(),
+		Ratio:       ratio.String(),
+		Utilization: utilization.String(),
+	}); err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+	if k.hooks != nil {
+		if err := k.hooks.AfterBurnFees(ctx, coins[0]); err != nil {
+			return sdkmath.ZeroInt(), err
+		}
+	}
+
+	return amount, nil
+}
@@ -0,0 +1,110 @@
+package keeper_test
+
+import (
+	"testing"
+
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/feeburn/types"
+)
+
+const maxGas = 1_000_000
+
+func setup(t *testing.T) (*app.TacChainApp, sdk.Context) {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := tacApp.NewContext(false).WithChainID(app.DefaultChainID).
+		WithConsensusParams(cmtproto.ConsensusParams{Block: &cmtproto.BlockParams{MaxBytes: 22020096, MaxGas: maxGas}})
+	return tacApp, ctx
+}
+
+// collectFees sends coins to the fee collector like the fees of a block
+func collectFees(t *testing.T, tacApp *app.TacChainApp, ctx sdk.Context, coins sdk.Coins) {
+	t.Helper()
+
+	require.NoError(t, tacApp.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins))
+	require.NoError(t, tacApp.BankKeeper.SendCoinsFromModuleToModule(ctx, minttypes.ModuleName, authtypes.FeeCollectorName, coins))
+}
+
+func feeCollectorBalance(tacApp *app.TacChainApp, ctx sdk.Context, denom string) sdkmath.Int {
+	return tacApp.BankKeeper.GetBalance(ctx, authtypes.NewModuleAddress(authtypes.FeeCollectorName), denom).Amount
+}
+
+func TestDefaultParamsBurnNothing(t *testing.T) {
+	tacApp, ctx := setup(t)
+	tacApp.FeeMarketKeeper.SetBlockGasWanted(ctx, maxGas)
+	collectFees(t, tacApp, ctx, sdk.NewCoins(sdk.NewCoin(app.BaseDenom, sdkmath.NewInt(1000))))
+
+	burnt, err := tacApp.FeeBurnKeeper.BurnFees(ctx)
+	require.NoError(t, err)
+	require.True(t, burnt.IsZero())
+}
+
+func TestBurnFeesUtilizationSweep(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.FeeBurnKeeper
+	k.SetParams(ctx, types.Params{
+		MinBurnRatio: sdkmath.LegacyNewDecWithPrec(1, 1),
+		MaxBurnRatio: sdkmath.LegacyNewDecWithPrec(5, 1),
+	})
+
+	require.True(t, feeCollectorBalance(tacApp, ctx, app.BaseDenom).IsZero())
+
+	const fees = 1_000_000
+	testCases := []struct {
+		gasWanted uint64
+		burnt     int64
+	}{
+		{0, 100_000},
+		{250_000, 200_000},
+		{500_000, 300_000},
+		{750_000, 400_000},
+		{maxGas, 500_000},
+		// gas wanted is tracked with the min gas multiplier of the fee market
+		// and can exceed the block gas limit
+		{2 * maxGas, 500_000},
+	}
+	for _, tc := range testCases {
+		ctx, _ := ctx.CacheContext()
+		ctx = ctx.WithEventManager(sdk.NewEventManager())
+		tacApp.FeeMarketKeeper.SetBlockGasWanted(ctx, tc.gasWanted)
+		collectFees(t, tacApp, ctx, sdk.NewCoins(sdk.NewCoin(app.BaseDenom, sdkmath.NewInt(fees)), sdk.NewCoin("uother", sdkmath.NewInt(fees))))
+		supply := tacApp.BankKeeper.GetSupply(ctx, app.BaseDenom).Amount
+
+		burnt, err := k.BurnFees(ctx)
+		require.NoError(t, err)
+		require.Equal(t, tc.burnt, burnt.Int64(), "gas wanted %d", tc.gasWanted)
+		require.Equal(t, supply.SubRaw(tc.burnt).String(), tacApp.BankKeeper.GetSupply(ctx, app.BaseDenom).Amount.String())
+		require.Equal(t, int64(fees-tc.burnt), feeCollectorBalance(tacApp, ctx, app.BaseDenom).Int64(), "the rest is left to x/distribution")
+		require.Equal(t, int64(fees), feeCollectorBalance(tacApp, ctx, "uother").Int64(), "other denoms are not burnt")
+
+		var events []*types.EventBurnFees
+		for _, event := range ctx.EventManager().ABCIEvents() {
+			if event.Type != proto.MessageName(&types.EventBurnFees{}) {
+				continue
+			}
+			msg, err := sdk.ParseTypedEvent(event)
+			require.NoError(t, err)
+			events = append(events, msg.(*types.EventBurnFees))
+		}
+		require.Len(t, events, 1)
+		require.Equal(t, burnt.String(), events[0].Amount)
+	}
+}
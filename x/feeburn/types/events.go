@@ -0,0 +1,23 @@
+package types
+
+import (
+	"github.com/cosmos/gogoproto/proto"
+)
+
+// init registers EventBurnFees, emitted each block the module burns a share
+// of the fees collected in the previous one.
+func init() {
+	proto.RegisterType((*EventBurnFees)(nil), "tacchain.feeburn.v1.EventBurnFees")
+}
+
+// EventBurnFees is emitted when a share of the fees collected in the previous
+// block was burnt.
+type EventBurnFees struct {
+	Amount      string `protobuf:"bytes,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Ratio       string `protobuf:"bytes,2,opt,name=ratio,proto3" json:"ratio,omitempty"`
+	Utilization string `protobuf:"bytes,3,opt,name=utilization,proto3" json:"utilization,omitempty"`
+}
+
+func (m *EventBurnFees) Reset()         { *m = EventBurnFees{} }
+func (m *EventBurnFees) String() string { return proto.CompactTextString(m) }
+func (*EventBurnFees) ProtoMessage()    {}
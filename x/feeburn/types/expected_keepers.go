@@ -0,0 +1,25 @@
+package types
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// BankKeeper defines the expected bank keeper
+type BankKeeper interface {
+	GetBalance(ctx context.Context, addr sdk.AccAddress, denom string) sdk.Coin
+	SendCoinsFromModuleToModule(ctx context.Context, senderModule, recipientModule string, amt sdk.Coins) error
+	BurnCoins(ctx context.Context, moduleName string, amt sdk.Coins) error
+}
+
+// FeeMarketKeeper defines the expected fee market keeper
+type FeeMarketKeeper interface {
+	GetBlockGasWanted(ctx sdk.Context) uint64
+}
+
+// FeeBurnHooks are called by the feeburn module once it burnt a share of the
+// fees of a block
+type FeeBurnHooks interface {
+	AfterBurnFees(ctx sdk.Context, amount sdk.Coin) error
+}
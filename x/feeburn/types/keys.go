@@ -0,0 +1,6 @@
+package types
+
+const (
+	// ModuleName defines the fee burn module name
+	ModuleName = "feeburn"
+)
@@ -0,0 +1,96 @@
+package types
+
+import (
+	"fmt"
+
+	sdkmath "cosmossdk.io/math"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+var (
+	// KeyMinBurnRatio is the param store key for the share of fees burnt in empty blocks
+	KeyMinBurnRatio = []byte("MinBurnRatio")
+	// KeyMaxBurnRatio is the param store key for the share of fees burnt in full blocks
+	KeyMaxBurnRatio = []byte("MaxBurnRatio")
+)
+
+// Params defines the feeburn module parameters. A share of the fees collected
+// in a block is burnt before they are distributed, scaling linearly with the
+// gas utilization of the block from MinBurnRatio in empty blocks to
+// MaxBurnRatio in full ones, so congestion makes the token scarcer the way the
+// base fee burn of EIP-1559 does. Both ratios default to zero, which
+// distributes all fees.
+type Params struct {
+	MinBurnRatio sdkmath.LegacyDec `json:"min_burn_ratio" yaml:"min_burn_ratio"`
+	MaxBurnRatio sdkmath.LegacyDec `json:"max_burn_ratio" yaml:"max_burn_ratio"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the feeburn module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default feeburn module parameters
+func DefaultParams() Params {
+	return Params{
+		MinBurnRatio: sdkmath.LegacyZeroDec(),
+		MaxBurnRatio: sdkmath.LegacyZeroDec(),
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyMinBurnRatio, &p.MinBurnRatio, validateBurnRatio),
+		paramtypes.NewParamSetPair(KeyMaxBurnRatio, &p.MaxBurnRatio, validateBurnRatio),
+	}
+}
+
+// Validate performs basic validation of the feeburn module parameters
+func (p Params) Validate() error {
+	if err := validateBurnRatio(p.MinBurnRatio); err != nil {
+		return err
+	}
+	if err := validateBurnRatio(p.MaxBurnRatio); err != nil {
+		return err
+	}
+	if p.MinBurnRatio.GT(p.MaxBurnRatio) {
+		return fmt.Errorf("min burn ratio %s is greater than max burn ratio %s", p.MinBurnRatio, p.MaxBurnRatio)
+	}
+	return nil
+}
+
+// BurnRatio returns the share of fees burnt at the given gas utilization,
+// clamped to [0, 1].
+func (p Params) BurnRatio(utilization sdkmath.LegacyDec) sdkmath.LegacyDec {
+	if utilization.IsNegative() {
+		utilization = sdkmath.LegacyZeroDec()
+	}
+	if utilization.GT(sdkmath.LegacyOneDec()) {
+		utilization = sdkmath.LegacyOneDec()
+	}
+	return p.MinBurnRatio.Add(p.MaxBurnRatio.Sub(p.MinBurnRatio).Mul(utilization))
+}
+
+// Utilization returns the gas utilization of a block with the given gas
+// wanted under maxGas, zero if block gas is unlimited.
+func Utilization(gasWanted uint64, maxGas int64) sdkmath.LegacyDec {
+	if maxGas <= 0 {
+		return sdkmath.LegacyZeroDec()
+	}
+	return sdkmath.LegacyNewDecFromInt(sdkmath.NewIntFromUint64(gasWanted)).QuoInt64(maxGas)
+}
+
+func validateBurnRatio(i interface{}) error {
+	ratio, ok := i.(sdkmath.LegacyDec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if ratio.IsNil() || ratio.IsNegative() || ratio.GT(sdkmath.LegacyOneDec()) {
+		return fmt.Errorf("burn ratio must be in [0, 1]: %s", ratio)
+	}
+	return nil
+}
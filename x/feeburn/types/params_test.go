@@ -0,0 +1,58 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/Asphere-xyz/tacchain/x/feeburn/types"
+)
+
+func dec(s string) sdkmath.LegacyDec {
+	return sdkmath.LegacyMustNewDecFromStr(s)
+}
+
+func TestParamsValidate(t *testing.T) {
+	require.NoError(t, types.DefaultParams().Validate())
+	require.NoError(t, types.Params{MinBurnRatio: dec("0.1"), MaxBurnRatio: dec("0.5")}.Validate())
+	require.NoError(t, types.Params{MinBurnRatio: dec("1"), MaxBurnRatio: dec("1")}.Validate())
+
+	require.Error(t, types.Params{MinBurnRatio: dec("0.5"), MaxBurnRatio: dec("0.1")}.Validate(), "min above max")
+	require.Error(t, types.Params{MinBurnRatio: dec("-0.1"), MaxBurnRatio: dec("0.5")}.Validate(), "negative ratio")
+	require.Error(t, types.Params{MinBurnRatio: dec("0"), MaxBurnRatio: dec("1.1")}.Validate(), "ratio above one")
+	require.Error(t, types.Params{MaxBurnRatio: dec("0.5")}.Validate(), "nil ratio")
+}
+
+func TestBurnRatioSweep(t *testing.T) {
+	params := types.Params{MinBurnRatio: dec("0.1"), MaxBurnRatio: dec("0.5")}
+
+	// the ratio grows linearly from min to max with utilization
+	previous := sdkmath.LegacyZeroDec()
+	for i := int64(0); i <= 10; i++ {
+		utilization := sdkmath.LegacyNewDecWithPrec(i, 1)
+		ratio := params.BurnRatio(utilization)
+		require.Equal(t, dec("0.1").Add(dec("0.04").MulInt64(i)).String(), ratio.String(), "utilization %s", utilization)
+		require.True(t, ratio.GTE(previous))
+		previous = ratio
+	}
+
+	// out of range utilizations are clamped
+	require.Equal(t, dec("0.1").String(), params.BurnRatio(dec("-0.5")).String())
+	require.Equal(t, dec("0.5").String(), params.BurnRatio(dec("1.5")).String())
+
+	// a flat ratio doesn't depend on utilization
+	flat := types.Params{MinBurnRatio: dec("0.3"), MaxBurnRatio: dec("0.3")}
+	for _, utilization := range []string{"0", "0.5", "1"} {
+		require.Equal(t, dec("0.3").String(), flat.BurnRatio(dec(utilization)).String())
+	}
+	require.True(t, types.DefaultParams().BurnRatio(sdkmath.LegacyOneDec()).IsZero())
+}
+
+func TestUtilization(t *testing.T) {
+	require.Equal(t, dec("0.25").String(), types.Utilization(250, 1000).String())
+	require.Equal(t, dec("2").String(), types.Utilization(2000, 1000).String())
+	require.True(t, types.Utilization(1000, -1).IsZero(), "unlimited block gas")
+	require.True(t, types.Utilization(1000, 0).IsZero())
+}
@@ -0,0 +1,158 @@
+package keeper
+
+import (
+	"encoding/json"
+
+	corestoretypes "cosmossdk.io/core/store"
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/Asphere-xyz/tacchain/x/feehistory/types"
+)
+
+// Keeper of the feehistory store
+type Keeper struct {
+	storeService    corestoretypes.KVStoreService
+	paramSpace      paramtypes.Subspace
+	feeMarketKeeper types.FeeMarketKeeper
+}
+
+// NewKeeper creates a new feehistory Keeper instance
+func NewKeeper(
+	storeService corestoretypes.KVStoreService,
+	paramSpace paramtypes.Subspace,
+	feeMarketKeeper types.FeeMarketKeeper,
+) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		storeService:    storeService,
+		paramSpace:      paramSpace,
+		feeMarketKeeper: feeMarketKeeper,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current feehistory module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the feehistory module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// EndBlocker records the base fee of the block and the gas its txs used, and
+// prunes the blocks past the retention. It runs after the fee market
+// EndBlocker, the base fee of the next block is only set in its BeginBlocker.
+func (k Keeper) EndBlocker(ctx sdk.Context) {
+	params := k.GetParams(ctx)
+	height := uint64(ctx.BlockHeight())
+	if params.Retention > 0 {
+		k.setBlockFee(ctx, k.blockFee(ctx))
+	}
+	// a lower retention prunes the blocks past it at once
+	if height >= params.Retention {
+		k.deleteRange(ctx, types.BlockFeePrefix, types.BlockFeeKey(height-params.Retention+1))
+	}
+}
+
+// blockFee returns the base fee and gas used of the block of ctx
+func (k Keeper) blockFee(ctx sdk.Context) types.BlockFee {
+	fee := types.BlockFee{
+		Height:  ctx.BlockHeight(),
+		BaseFee: k.feeMarketKeeper.GetBaseFee(ctx),
+	}
+	if fee.BaseFee.IsNil() {
+		fee.BaseFee = sdkmath.LegacyZeroDec()
+	}
+	// the block gas meter is the one the fee market adjusts the base fee by
+	if meter := ctx.BlockGasMeter(); meter != nil {
+		fee.GasUsed = meter.GasConsumedToLimit()
+	}
+	if block := ctx.ConsensusParams().Block; block != nil && block.MaxGas > 0 {
+		fee.MaxGas = block.MaxGas
+	}
+	return fee
+}
+
+// GetBlockFee returns the base fee and gas used of the block at height, if it
+// is kept
+func (k Keeper) GetBlockFee(ctx sdk.Context, height uint64) (types.BlockFee, bool) {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.BlockFeeKey(height))
+	if err != nil {
+		panic(err)
+	}
+	if len(bz) == 0 {
+		return types.BlockFee{}, false
+	}
+	var fee types.BlockFee
+	mustUnmarshal(bz, &fee)
+	return fee, true
+}
+
+// GetBlockFees returns the base fee and gas used of the kept blocks from
+// fromHeight to toHeight included, by height
+func (k Keeper) GetBlockFees(ctx sdk.Context, fromHeight, toHeight uint64) []types.BlockFee {
+	fees := []types.BlockFee{}
+	if toHeight < fromHeight {
+		return fees
+	}
+	iterator, err := k.storeService.OpenKVStore(ctx).Iterator(types.BlockFeeKey(fromHeight), types.BlockFeeKey(toHeight+1))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var fee types.BlockFee
+		mustUnmarshal(iterator.Value(), &fee)
+		fees = append(fees, fee)
+	}
+	return fees
+}
+
+func (k Keeper) setBlockFee(ctx sdk.Context, fee types.BlockFee) {
+	bz, err := json.Marshal(fee)
+	if err != nil {
+		panic(err)
+	}
+	if err := k.storeService.OpenKVStore(ctx).Set(types.BlockFeeKey(uint64(fee.Height)), bz); err != nil {
+		panic(err)
+	}
+}
+
+func (k Keeper) deleteRange(ctx sdk.Context, start, end []byte) {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(start, end)
+	if err != nil {
+		panic(err)
+	}
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+
+	for _, key := range keys {
+		if err := store.Delete(key); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func mustUnmarshal(bz []byte, v any) {
+	if err := json.Unmarshal(bz, v); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,123 @@
+package keeper_test
+
+import (
+	"testing"
+
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/feehistory/types"
+)
+
+const maxGas = 1_000_000
+
+func setup(t *testing.T, params types.Params) (*app.TacChainApp, sdk.Context) {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := tacApp.NewContext(false).
+		WithChainID(app.DefaultChainID).
+		WithConsensusParams(cmtproto.ConsensusParams{Block: &cmtproto.BlockParams{MaxGas: maxGas}})
+	tacApp.FeeHistoryKeeper.SetParams(ctx, params)
+	return tacApp, ctx
+}
+
+// endBlock runs the EndBlocker of the block at height, its txs used gasUsed
+func endBlock(tacApp *app.TacChainApp, ctx sdk.Context, height int64, gasUsed uint64) {
+	meter := storetypes.NewGasMeter(maxGas)
+	meter.ConsumeGas(gasUsed, "txs")
+	tacApp.FeeHistoryKeeper.EndBlocker(ctx.WithBlockHeight(height).WithBlockGasMeter(meter))
+}
+
+func heights(fees []types.BlockFee) []int64 {
+	var heights []int64
+	for _, fee := range fees {
+		heights = append(heights, fee.Height)
+	}
+	return heights
+}
+
+func TestEndBlocker(t *testing.T) {
+	tacApp, ctx := setup(t, types.Params{Retention: 3})
+	k := tacApp.FeeHistoryKeeper
+	baseFee := tacApp.FeeMarketKeeper.GetBaseFee(ctx)
+	require.True(t, baseFee.IsPositive())
+
+	for height := int64(1); height <= 5; height++ {
+		endBlock(tacApp, ctx, height, uint64(height)*100_000)
+	}
+	fee, found := k.GetBlockFee(ctx, 5)
+	require.True(t, found)
+	require.Equal(t, types.BlockFee{Height: 5, BaseFee: baseFee, GasUsed: 500_000, MaxGas: maxGas}, fee)
+	require.Equal(t, sdkmath.LegacyNewDecWithPrec(5, 1), fee.GasUsedRatio())
+
+	// the blocks past the retention are pruned
+	_, found = k.GetBlockFee(ctx, 2)
+	require.False(t, found)
+	require.Equal(t, []int64{3, 4, 5}, heights(k.GetBlockFees(ctx, 1, 10)))
+	require.Equal(t, []int64{4}, heights(k.GetBlockFees(ctx, 4, 4)))
+	require.Empty(t, k.GetBlockFees(ctx, 5, 4))
+
+	// a lower retention prunes the blocks past it at once
+	k.SetParams(ctx, types.Params{Retention: 1})
+	endBlock(tacApp, ctx, 6, 0)
+	require.Equal(t, []int64{6}, heights(k.GetBlockFees(ctx, 1, 10)))
+
+	// without a base fee, the base fee is zero
+	params := tacApp.FeeMarketKeeper.GetParams(ctx)
+	params.NoBaseFee = true
+	require.NoError(t, tacApp.FeeMarketKeeper.SetParams(ctx, params))
+	endBlock(tacApp, ctx, 7, 21_000)
+	require.Equal(t, types.BlockFee{Height: 7, BaseFee: sdkmath.LegacyZeroDec(), GasUsed: 21_000, MaxGas: maxGas}, must(k.GetBlockFee(ctx, 7)))
+}
+
+func TestDisable(t *testing.T) {
+	tacApp, ctx := setup(t, types.DefaultParams())
+	k := tacApp.FeeHistoryKeeper
+
+	endBlock(tacApp, ctx, 1, 100)
+	endBlock(tacApp, ctx, 2, 100)
+	require.Len(t, k.GetBlockFees(ctx, 1, 2), 2)
+
+	// disabling drops the blocks kept
+	k.SetParams(ctx, types.Params{})
+	endBlock(tacApp, ctx, 3, 100)
+	require.Empty(t, k.GetBlockFees(ctx, 1, 3))
+
+	// the history restarts from the block enabling it, unlimited block gas
+	// has no gas used ratio
+	k.SetParams(ctx, types.DefaultParams())
+	endBlock(tacApp, ctx.WithConsensusParams(cmtproto.ConsensusParams{Block: &cmtproto.BlockParams{MaxGas: -1}}), 4, 100)
+	fee := must(k.GetBlockFee(ctx, 4))
+	require.Zero(t, fee.MaxGas)
+	require.True(t, fee.GasUsedRatio().IsZero())
+	require.Equal(t, []int64{4}, heights(k.GetBlockFees(ctx, 1, 4)))
+}
+
+func TestGenesis(t *testing.T) {
+	tacApp, ctx := setup(t, types.DefaultParams())
+	k := tacApp.FeeHistoryKeeper
+
+	k.InitGenesis(ctx, types.GenesisState{Params: types.Params{Retention: 100}})
+	require.Equal(t, &types.GenesisState{Params: types.Params{Retention: 100}}, k.ExportGenesis(ctx))
+}
+
+func must[T any](v T, found bool) T {
+	if !found {
+		panic("not found")
+	}
+	return v
+}
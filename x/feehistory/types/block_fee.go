@@ -0,0 +1,27 @@
+package types
+
+import (
+	sdkmath "cosmossdk.io/math"
+)
+
+// BlockFee is the base fee of a block and the gas its txs used against the
+// block gas limit, the series eth_feeHistory and wallets estimate fees from
+type BlockFee struct {
+	Height int64 `json:"height"`
+	// BaseFee is the base fee of the fee market in the block, zero when the
+	// base fee is disabled
+	BaseFee sdkmath.LegacyDec `json:"base_fee"`
+	GasUsed uint64            `json:"gas_used"`
+	// MaxGas is the block gas limit of the consensus params, zero when
+	// unlimited
+	MaxGas int64 `json:"max_gas"`
+}
+
+// GasUsedRatio returns the gas used over the block gas limit, zero when the
+// block gas is unlimited
+func (b BlockFee) GasUsedRatio() sdkmath.LegacyDec {
+	if b.MaxGas <= 0 {
+		return sdkmath.LegacyZeroDec()
+	}
+	return sdkmath.LegacyNewDecFromInt(sdkmath.NewIntFromUint64(b.GasUsed)).QuoInt64(b.MaxGas)
+}
@@ -0,0 +1,24 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/Asphere-xyz/tacchain/x/feehistory/types"
+)
+
+func TestGasUsedRatio(t *testing.T) {
+	require.Equal(t, sdkmath.LegacyNewDecWithPrec(25, 2), types.BlockFee{GasUsed: 250, MaxGas: 1000}.GasUsedRatio())
+	require.Equal(t, sdkmath.LegacyOneDec(), types.BlockFee{GasUsed: 1000, MaxGas: 1000}.GasUsedRatio())
+	require.True(t, types.BlockFee{GasUsed: 250}.GasUsedRatio().IsZero(), "unlimited block gas")
+}
+
+func TestParamsValidate(t *testing.T) {
+	require.NoError(t, types.DefaultParams().Validate())
+	require.NoError(t, types.Params{}.Validate(), "disabled")
+	require.NoError(t, types.Params{Retention: types.MaxRetention}.Validate())
+	require.ErrorContains(t, types.Params{Retention: types.MaxRetention + 1}.Validate(), "retention 43201 is over 43200 blocks")
+}
@@ -0,0 +1,12 @@
+package types
+
+import (
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// FeeMarketKeeper defines the expected fee market keeper
+type FeeMarketKeeper interface {
+	GetBaseFee(ctx sdk.Context) sdkmath.LegacyDec
+}
@@ -0,0 +1,23 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// ModuleName defines the feehistory module name
+	ModuleName = "feehistory"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+)
+
+// BlockFeePrefix prefixes the base fee and gas of the last blocks, keyed by
+// height
+var BlockFeePrefix = []byte{0x01}
+
+// BlockFeeKey returns the store key of the base fee and gas of the block at
+// height
+func BlockFeeKey(height uint64) []byte {
+	return append(append([]byte{}, BlockFeePrefix...), sdk.Uint64ToBigEndian(height)...)
+}
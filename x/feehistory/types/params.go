@@ -0,0 +1,62 @@
+package types
+
+import (
+	"fmt"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+const (
+	// DefaultRetention is the block count cap of eth_feeHistory in go-ethereum
+	DefaultRetention uint64 = 1024
+	// MaxRetention bounds the blocks kept in the store, about a day of 2
+	// second blocks
+	MaxRetention uint64 = 43_200
+)
+
+// KeyRetention is the param store key for the number of blocks kept
+var KeyRetention = []byte("Retention")
+
+// Params defines the feehistory module parameters. The base fee and gas used
+// of the last Retention blocks are kept, a Retention of zero disables the
+// history and drops the blocks kept.
+type Params struct {
+	Retention uint64 `json:"retention" yaml:"retention"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the feehistory module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default feehistory module parameters
+func DefaultParams() Params {
+	return Params{
+		Retention: DefaultRetention,
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyRetention, &p.Retention, validateRetention),
+	}
+}
+
+// Validate performs basic validation of the feehistory module parameters
+func (p Params) Validate() error {
+	return validateRetention(p.Retention)
+}
+
+func validateRetention(i interface{}) error {
+	v, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v > MaxRetention {
+		return fmt.Errorf("retention %d is over %d blocks", v, MaxRetention)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/feerouting/types"
+)
+
+// InitGenesis initializes the feerouting module state from a genesis state
+func (k Keeper) InitGenesis(ctx sdk.Context, gs types.GenesisState) {
+	k.SetParams(ctx, gs.Params)
+	for _, tips := range gs.ProposerTips {
+		valAddr, err := sdk.ValAddressFromBech32(tips.Validator)
+		if err != nil {
+			panic(err)
+		}
+		k.setProposerTips(ctx, valAddr, tips)
+	}
+	k.setTotalRevenue(ctx, gs.TotalRevenue)
+	for _, revenue := range gs.EpochRevenues {
+		k.setEpochRevenue(ctx, revenue)
+	}
+}
+
+// ExportGenesis returns the feerouting module genesis state
+func (k Keeper) ExportGenesis(ctx sdk.Context) *types.GenesisState {
+	return &types.GenesisState{
+		Params:        k.GetParams(ctx),
+		ProposerTips:  k.GetAllProposerTips(ctx),
+		TotalRevenue:  k.GetTotalRevenue(ctx),
+		EpochRevenues: k.GetAllEpochRevenues(ctx),
+	}
+}
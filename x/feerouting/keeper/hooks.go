@@ -0,0 +1,48 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	emissiontypes "github.com/Asphere-xyz/tacchain/x/emission/types"
+	feeburntypes "github.com/Asphere-xyz/tacchain/x/feeburn/types"
+	"github.com/Asphere-xyz/tacchain/x/feerouting/types"
+)
+
+// Hooks add the provisions x/emission mints and the fees x/feeburn burns to
+// the protocol revenue
+type Hooks struct {
+	k Keeper
+}
+
+var (
+	_ emissiontypes.EmissionHooks = Hooks{}
+	_ feeburntypes.FeeBurnHooks   = Hooks{}
+)
+
+// Hooks returns the hooks of the feerouting module
+func (k Keeper) Hooks() Hooks {
+	return Hooks{k: k}
+}
+
+// AfterMintBlockProvision adds the provision of the block to the minted
+// revenue, when it is in the denom of the module
+func (h Hooks) AfterMintBlockProvision(ctx sdk.Context, amount sdk.Coin) error {
+	if amount.Denom != h.k.denom {
+		return nil
+	}
+	revenue := types.ZeroRevenue()
+	revenue.Minted = amount.Amount
+	h.k.AddRevenue(ctx, revenue)
+	return nil
+}
+
+// AfterBurnFees adds the burnt fees to the burned revenue
+func (h Hooks) AfterBurnFees(ctx sdk.Context, amount sdk.Coin) error {
+	if amount.Denom != h.k.denom {
+		return nil
+	}
+	revenue := types.ZeroRevenue()
+	revenue.Burned = amount.Amount
+	h.k.AddRevenue(ctx, revenue)
+	return nil
+}
@@ -0,0 +1,251 @@
+package keeper
+
+import (
+	"encoding/json"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	corestoretypes "cosmossdk.io/core/store"
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/Asphere-xyz/tacchain/x/feerouting/types"
+)
+
+// Keeper of the feerouting store
+type Keeper struct {
+	storeService         corestoretypes.KVStoreService
+	paramSpace           paramtypes.Subspace
+	bankKeeper           types.BankKeeper
+	stakingKeeper        types.StakingKeeper
+	distrKeeper          types.DistrKeeper
+	feeMarketKeeper      types.FeeMarketKeeper
+	rewardSnapshotKeeper types.RewardSnapshotKeeper
+	feeCollectorName     string
+	denom                string
+}
+
+// NewKeeper creates a new feerouting Keeper instance. The tips in denom are
+// routed from the feeCollectorName module account to the block proposers,
+// fees paid in other denoms are always distributed. The protocol revenue in
+// denom is reported over the epochs of x/rewardsnapshot.
+func NewKeeper(
+	storeService corestoretypes.KVStoreService,
+	paramSpace paramtypes.Subspace,
+	bankKeeper types.BankKeeper,
+	stakingKeeper types.StakingKeeper,
+	distrKeeper types.DistrKeeper,
+	feeMarketKeeper types.FeeMarketKeeper,
+	rewardSnapshotKeeper types.RewardSnapshotKeeper,
+	feeCollectorName, denom string,
+) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		storeService:         storeService,
+		paramSpace:           paramSpace,
+		bankKeeper:           bankKeeper,
+		stakingKeeper:        stakingKeeper,
+		distrKeeper:          distrKeeper,
+		feeMarketKeeper:      feeMarketKeeper,
+		rewardSnapshotKeeper: rewardSnapshotKeeper,
+		feeCollectorName:     feeCollectorName,
+		denom:                denom,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current feerouting module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the feerouting module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// BaseGasPrice returns the gas price the tips are paid above: the base fee of
+// the fee market, or its min gas price when the base fee is disabled.
+func (k Keeper) BaseGasPrice(ctx sdk.Context) sdkmath.Int {
+	params := k.feeMarketKeeper.GetParams(ctx)
+	if !params.NoBaseFee {
+		// the ante handlers charge the base fee truncated to an integer
+		if baseFee := k.feeMarketKeeper.GetBaseFee(ctx); !baseFee.IsNil() {
+			return baseFee.TruncateInt()
+		}
+	}
+	return params.MinGasPrice.Ceil().TruncateInt()
+}
+
+// EVMTip returns the priority fee of an EVM tx which used gasUsed gas, its
+// effective gas tip over the base gas price for every unit of gas.
+func (k Keeper) EVMTip(ctx sdk.Context, ethTx *gethtypes.Transaction, gasUsed uint64) sdkmath.Int {
+	tip, err := ethTx.EffectiveGasTip(k.BaseGasPrice(ctx).BigInt())
+	if err != nil || tip.Sign() <= 0 {
+		return sdkmath.ZeroInt()
+	}
+	return sdkmath.NewIntFromBigInt(tip).Mul(sdkmath.NewIntFromUint64(gasUsed))
+}
+
+// CosmosSurplus returns the part of the fee a Cosmos tx with a gas limit of
+// gas was charged above the base gas price. The fees of Cosmos txs aren't
+// refunded, the surplus is counted over the gas limit.
+func (k Keeper) CosmosSurplus(ctx sdk.Context, fee sdk.Coins, gas uint64) sdkmath.Int {
+	surplus := fee.AmountOf(k.denom).Sub(k.BaseGasPrice(ctx).Mul(sdkmath.NewIntFromUint64(gas)))
+	if !surplus.IsPositive() {
+		return sdkmath.ZeroInt()
+	}
+	return surplus
+}
+
+// AddBlockTips adds the shares of the params of the priority fee of an EVM tx
+// and of the fee surplus of a Cosmos tx to the tips of the block in progress.
+func (k Keeper) AddBlockTips(ctx sdk.Context, evmTip, cosmosSurplus sdkmath.Int) {
+	params := k.GetParams(ctx)
+	tips := types.NewTips(
+		params.EVMTipShare.MulInt(evmTip).TruncateInt(),
+		params.CosmosSurplusShare.MulInt(cosmosSurplus).TruncateInt(),
+	)
+	if tips.IsZero() {
+		return
+	}
+	k.setBlockTips(ctx, k.GetBlockTips(ctx).Add(tips))
+}
+
+// GetBlockTips returns the tips collected by the txs of the block in progress
+func (k Keeper) GetBlockTips(ctx sdk.Context) types.Tips {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.BlockTipsKey)
+	if err != nil {
+		panic(err)
+	}
+	if len(bz) == 0 {
+		return types.ZeroTips()
+	}
+	var tips types.Tips
+	mustUnmarshal(bz, &tips)
+	return tips
+}
+
+func (k Keeper) setBlockTips(ctx sdk.Context, tips types.Tips) {
+	k.set(ctx, types.BlockTipsKey, tips)
+}
+
+// RouteTips sends the tips of the block from the fee collector to the
+// validator that proposed it, as rewards x/distribution splits between its
+// commission and its delegators, and adds them to the tips of the validator
+// and to the protocol revenue.
+// It runs at the end of the block, once its txs paid their fees and before
+// x/distribution hands out the rest at the start of the next block.
+//
+// The tips stay with the fee collector, and are distributed to all stakers,
+// when the proposer isn't a known validator.
+func (k Keeper) RouteTips(ctx sdk.Context) error {
+	tips := k.GetBlockTips(ctx)
+	if tips.IsZero() {
+		return nil
+	}
+	if err := k.storeService.OpenKVStore(ctx).Delete(types.BlockTipsKey); err != nil {
+		return err
+	}
+
+	consAddr := sdk.ConsAddress(ctx.BlockHeader().ProposerAddress)
+	validator, err := k.stakingKeeper.GetValidatorByConsAddr(ctx, consAddr)
+	if err != nil {
+		k.Logger(ctx).Error("failed to find the block proposer, its tips are distributed", "proposer", consAddr.String(), "tips", tips.Total(), "err", err)
+		return nil
+	}
+	valAddr, err := sdk.ValAddressFromBech32(validator.GetOperator())
+	if err != nil {
+		return err
+	}
+
+	coins := sdk.NewCoins(sdk.NewCoin(k.denom, tips.Total()))
+	if err := k.bankKeeper.SendCoinsFromModuleToModule(ctx, k.feeCollectorName, distrtypes.ModuleName, coins); err != nil {
+		return err
+	}
+	if err := k.distrKeeper.AllocateTokensToValidator(ctx, validator, sdk.NewDecCoinsFromCoins(coins...)); err != nil {
+		return err
+	}
+
+	proposerTips := k.GetProposerTips(ctx, valAddr)
+	proposerTips.Tips = proposerTips.Tips.Add(tips)
+	proposerTips.Blocks++
+	proposerTips.LastHeight = ctx.BlockHeight()
+	k.setProposerTips(ctx, valAddr, proposerTips)
+	revenue := types.ZeroRevenue()
+	revenue.ProposerTips = tips.Total()
+	k.AddRevenue(ctx, revenue)
+
+	return ctx.EventManager().EmitTypedEvent(&types.EventRouteTips{
+		Validator:     validator.GetOperator(),
+		Amount:        tips.Total().String(),
+		EvmTips:       tips.EVMTips.String(),
+		CosmosSurplus: tips.CosmosSurplus.String(),
+	})
+}
+
+// GetProposerTips returns the tips routed to the validator, zero if it never
+// received any
+func (k Keeper) GetProposerTips(ctx sdk.Context, valAddr sdk.ValAddress) types.ProposerTips {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.ProposerTipsKey(valAddr))
+	if err != nil {
+		panic(err)
+	}
+	if len(bz) == 0 {
+		return types.ProposerTips{Validator: valAddr.String(), Tips: types.ZeroTips()}
+	}
+	var tips types.ProposerTips
+	mustUnmarshal(bz, &tips)
+	return tips
+}
+
+// GetAllProposerTips returns the tips routed to every validator, by operator
+// address
+func (k Keeper) GetAllProposerTips(ctx sdk.Context) []types.ProposerTips {
+	iterator, err := k.storeService.OpenKVStore(ctx).Iterator(types.ProposerTipsPrefix, storetypes.PrefixEndBytes(types.ProposerTipsPrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	all := []types.ProposerTips{}
+	for ; iterator.Valid(); iterator.Next() {
+		var tips types.ProposerTips
+		mustUnmarshal(iterator.Value(), &tips)
+		all = append(all, tips)
+	}
+	return all
+}
+
+func (k Keeper) setProposerTips(ctx sdk.Context, valAddr sdk.ValAddress, tips types.ProposerTips) {
+	k.set(ctx, types.ProposerTipsKey(valAddr), tips)
+}
+
+func (k Keeper) set(ctx sdk.Context, key []byte, v any) {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	if err := k.storeService.OpenKVStore(ctx).Set(key, bz); err != nil {
+		panic(err)
+	}
+}
+
+func mustUnmarshal(bz []byte, v any) {
+	if err := json.Unmarshal(bz, v); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,429 @@
+package keeper_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	emissiontypes "github.com/Asphere-xyz/tacchain/x/emission/types"
+	feeburntypes "github.com/Asphere-xyz/tacchain/x/feeburn/types"
+	"github.com/Asphere-xyz/tacchain/x/feerouting/types"
+	rewardsnapshottypes "github.com/Asphere-xyz/tacchain/x/rewardsnapshot/types"
+)
+
+// commissionRate is the commission rate of the validators of the tests
+var commissionRate = sdkmath.LegacyNewDecWithPrec(1, 1)
+
+type testChain struct {
+	t   *testing.T
+	app *app.TacChainApp
+	ctx sdk.Context
+	// validators are the genesis validator and the two added by setup
+	validators []stakingtypes.Validator
+}
+
+func setup(t *testing.T, params types.Params) *testChain {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := tacApp.NewContext(false).WithChainID(app.DefaultChainID).WithBlockHeight(1)
+	tacApp.FeeRoutingKeeper.SetParams(ctx, params)
+	c := &testChain{t: t, app: tacApp, ctx: ctx}
+
+	validators, err := tacApp.StakingKeeper.GetAllValidators(ctx)
+	require.NoError(t, err)
+	require.Len(t, validators, 1)
+	validators[0].Commission.Rate = commissionRate
+	require.NoError(t, tacApp.StakingKeeper.SetValidator(ctx, validators[0]))
+	c.validators = append(c.validators, validators[0])
+	for i := 0; i < 2; i++ {
+		c.validators = append(c.validators, c.addValidator())
+	}
+	return c
+}
+
+// addValidator adds a bonded validator, with its distribution records
+func (c *testChain) addValidator() stakingtypes.Validator {
+	pubKey := ed25519.GenPrivKey().PubKey()
+	valAddr := sdk.ValAddress(pubKey.Address())
+	validator, err := stakingtypes.NewValidator(valAddr.String(), must(cryptocodec.FromCmtPubKeyInterface(pubKey)), stakingtypes.Description{})
+	require.NoError(c.t, err)
+	validator.Status = stakingtypes.Bonded
+	validator.Tokens = app.PowerReduction
+	validator.DelegatorShares = sdkmath.LegacyNewDecFromInt(app.PowerReduction)
+	validator.Commission.Rate = commissionRate
+	require.NoError(c.t, c.app.StakingKeeper.SetValidator(c.ctx, validator))
+	require.NoError(c.t, c.app.StakingKeeper.SetValidatorByConsAddr(c.ctx, validator))
+	require.NoError(c.t, c.app.DistrKeeper.Hooks().AfterValidatorCreated(c.ctx, valAddr))
+	return validator
+}
+
+// collectFees sends coins to the fee collector like the fees of a block
+func (c *testChain) collectFees(amount int64) {
+	coins := sdk.NewCoins(sdk.NewInt64Coin(app.BaseDenom, amount))
+	require.NoError(c.t, c.app.BankKeeper.MintCoins(c.ctx, minttypes.ModuleName, coins))
+	require.NoError(c.t, c.app.BankKeeper.SendCoinsFromModuleToModule(c.ctx, minttypes.ModuleName, authtypes.FeeCollectorName, coins))
+}
+
+func (c *testChain) feeCollectorBalance() sdkmath.Int {
+	return c.app.BankKeeper.GetBalance(c.ctx, authtypes.NewModuleAddress(authtypes.FeeCollectorName), app.BaseDenom).Amount
+}
+
+// rewards returns the outstanding rewards and the commission of validator
+func (c *testChain) rewards(validator stakingtypes.Validator) (sdkmath.LegacyDec, sdkmath.LegacyDec) {
+	valAddr := must(sdk.ValAddressFromBech32(validator.GetOperator()))
+	outstanding, err := c.app.DistrKeeper.GetValidatorOutstandingRewards(c.ctx, valAddr)
+	require.NoError(c.t, err)
+	commission, err := c.app.DistrKeeper.GetValidatorAccumulatedCommission(c.ctx, valAddr)
+	require.NoError(c.t, err)
+	return outstanding.Rewards.AmountOf(app.BaseDenom), commission.Commission.AmountOf(app.BaseDenom)
+}
+
+// endBlock routes the tips of the block at height proposed by proposer
+func (c *testChain) endBlock(height int64, proposer stakingtypes.Validator) []*types.EventRouteTips {
+	consAddr, err := proposer.GetConsAddr()
+	require.NoError(c.t, err)
+	ctx := c.ctx.WithBlockHeight(height).
+		WithBlockHeader(cmtproto.Header{Height: height, ProposerAddress: consAddr}).
+		WithEventManager(sdk.NewEventManager())
+	require.NoError(c.t, c.app.FeeRoutingKeeper.RouteTips(ctx))
+
+	var events []*types.EventRouteTips
+	for _, event := range ctx.EventManager().ABCIEvents() {
+		if event.Type != proto.MessageName(&types.EventRouteTips{}) {
+			continue
+		}
+		msg, err := sdk.ParseTypedEvent(event)
+		require.NoError(c.t, err)
+		events = append(events, msg.(*types.EventRouteTips))
+	}
+	return events
+}
+
+func TestDefaultParamsRouteNothing(t *testing.T) {
+	c := setup(t, types.DefaultParams())
+	k := c.app.FeeRoutingKeeper
+
+	c.collectFees(10_000)
+	k.AddBlockTips(c.ctx, sdkmath.NewInt(1000), sdkmath.NewInt(1000))
+	require.True(t, k.GetBlockTips(c.ctx).IsZero())
+	require.Empty(t, c.endBlock(1, c.validators[0]))
+	require.Equal(t, int64(10_000), c.feeCollectorBalance().Int64(), "the fees are left to x/distribution")
+	require.Empty(t, k.GetAllProposerTips(c.ctx))
+}
+
+func TestRouteTipsToProposers(t *testing.T) {
+	c := setup(t, types.Params{EVMTipShare: sdkmath.LegacyOneDec(), CosmosSurplusShare: sdkmath.LegacyNewDecWithPrec(5, 1)})
+	k := c.app.FeeRoutingKeeper
+
+	type rewards struct{ outstanding, commission sdkmath.LegacyDec }
+	snapshot := func() []rewards {
+		var all []rewards
+		for _, validator := range c.validators {
+			outstanding, commission := c.rewards(validator)
+			all = append(all, rewards{outstanding, commission})
+		}
+		return all
+	}
+	requireDec := func(expected, actual sdkmath.LegacyDec, msgAndArgs ...any) {
+		require.Equal(t, expected.String(), actual.String(), msgAndArgs...)
+	}
+
+	// every validator proposes a block in turn, the second one twice
+	proposers := []int{0, 1, 2, 1}
+	routed := make([]types.Tips, len(c.validators))
+	for i := range routed {
+		routed[i] = types.ZeroTips()
+	}
+	for i, proposer := range proposers {
+		height := int64(i + 2)
+		c.collectFees(100_000)
+		// the txs of the block: an EVM tx and two Cosmos txs, one of them
+		// paying no more than the base fee
+		evmTip := int64(1000 * (i + 1))
+		k.AddBlockTips(c.ctx, sdkmath.NewInt(evmTip), sdkmath.ZeroInt())
+		k.AddBlockTips(c.ctx, sdkmath.ZeroInt(), sdkmath.NewInt(301))
+		k.AddBlockTips(c.ctx, sdkmath.ZeroInt(), sdkmath.ZeroInt())
+		// half of the surplus, truncated
+		tips := types.NewTips(sdkmath.NewInt(evmTip), sdkmath.NewInt(150))
+		requireJSON(t, tips, k.GetBlockTips(c.ctx))
+
+		before, feeCollector := snapshot(), c.feeCollectorBalance()
+		events := c.endBlock(height, c.validators[proposer])
+		after := snapshot()
+
+		require.Equal(t, feeCollector.Sub(tips.Total()).String(), c.feeCollectorBalance().String(), "the tips leave the fee collector")
+		require.True(t, k.GetBlockTips(c.ctx).IsZero(), "the tips of the block are routed once")
+		for j := range c.validators {
+			if j != proposer {
+				requireDec(before[j].outstanding, after[j].outstanding, "validator %d didn't propose block %d", j, height)
+				requireDec(before[j].commission, after[j].commission, "validator %d didn't propose block %d", j, height)
+				continue
+			}
+			total := sdkmath.LegacyNewDecFromInt(tips.Total())
+			requireDec(before[j].outstanding.Add(total), after[j].outstanding, "the proposer is handed the tips")
+			requireDec(before[j].commission.Add(total.Mul(commissionRate)), after[j].commission, "the proposer takes its commission on the tips")
+		}
+
+		require.Equal(t, []*types.EventRouteTips{{
+			Validator:     c.validators[proposer].GetOperator(),
+			Amount:        tips.Total().String(),
+			EvmTips:       tips.EVMTips.String(),
+			CosmosSurplus: tips.CosmosSurplus.String(),
+		}}, events)
+		routed[proposer] = routed[proposer].Add(tips)
+	}
+
+	// the tips of every validator over the blocks it proposed
+	for i, validator := range c.validators {
+		valAddr := must(sdk.ValAddressFromBech32(validator.GetOperator()))
+		proposerTips := k.GetProposerTips(c.ctx, valAddr)
+		requireJSON(t, routed[i], proposerTips.Tips)
+	}
+	valAddr := must(sdk.ValAddressFromBech32(c.validators[1].GetOperator()))
+	requireJSON(t, types.ProposerTips{
+		Validator:  c.validators[1].GetOperator(),
+		Tips:       types.NewTips(sdkmath.NewInt(2000+4000), sdkmath.NewInt(300)),
+		Blocks:     2,
+		LastHeight: 5,
+	}, k.GetProposerTips(c.ctx, valAddr))
+	require.Len(t, k.GetAllProposerTips(c.ctx), 3)
+
+	// the routed tips are withdrawable rewards, backed by the balance of
+	// x/distribution
+	_, err := c.app.DistrKeeper.WithdrawValidatorCommission(c.ctx, valAddr)
+	require.NoError(t, err)
+}
+
+func TestRouteTipsUnknownProposer(t *testing.T) {
+	c := setup(t, types.Params{EVMTipShare: sdkmath.LegacyOneDec(), CosmosSurplusShare: sdkmath.LegacyOneDec()})
+	k := c.app.FeeRoutingKeeper
+
+	c.collectFees(10_000)
+	k.AddBlockTips(c.ctx, sdkmath.NewInt(1000), sdkmath.ZeroInt())
+	unknown, err := stakingtypes.NewValidator(sdk.ValAddress(ed25519.GenPrivKey().PubKey().Address()).String(), must(cryptocodec.FromCmtPubKeyInterface(ed25519.GenPrivKey().PubKey())), stakingtypes.Description{})
+	require.NoError(t, err)
+
+	require.Empty(t, c.endBlock(2, unknown))
+	require.Equal(t, int64(10_000), c.feeCollectorBalance().Int64(), "the tips are left to x/distribution")
+	require.True(t, k.GetBlockTips(c.ctx).IsZero())
+	require.Empty(t, k.GetAllProposerTips(c.ctx))
+}
+
+func TestTips(t *testing.T) {
+	c := setup(t, types.DefaultParams())
+	k := c.app.FeeRoutingKeeper
+	feeMarketParams := c.app.FeeMarketKeeper.GetParams(c.ctx)
+	feeMarketParams.NoBaseFee = false
+	feeMarketParams.BaseFee = sdkmath.LegacyNewDec(100)
+	feeMarketParams.MinGasPrice = sdkmath.LegacyMustNewDecFromStr("10.5")
+	require.NoError(t, c.app.FeeMarketKeeper.SetParams(c.ctx, feeMarketParams))
+	require.Equal(t, "100", k.BaseGasPrice(c.ctx).String())
+
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	dynamicTx := func(tipCap, feeCap int64) *gethtypes.Transaction {
+		return gethtypes.NewTx(&gethtypes.DynamicFeeTx{GasTipCap: big.NewInt(tipCap), GasFeeCap: big.NewInt(feeCap), Gas: 100_000, To: &to})
+	}
+	legacyTx := gethtypes.NewTx(&gethtypes.LegacyTx{GasPrice: big.NewInt(130), Gas: 100_000, To: &to})
+
+	// the tip is paid for the gas used, up to the fee cap
+	require.Equal(t, int64(20*21_000), k.EVMTip(c.ctx, dynamicTx(20, 1000), 21_000).Int64())
+	require.Equal(t, int64(5*21_000), k.EVMTip(c.ctx, dynamicTx(20, 105), 21_000).Int64())
+	require.Equal(t, int64(30*50_000), k.EVMTip(c.ctx, legacyTx, 50_000).Int64())
+	require.True(t, k.EVMTip(c.ctx, dynamicTx(0, 1000), 21_000).IsZero())
+	require.True(t, k.EVMTip(c.ctx, dynamicTx(20, 90), 21_000).IsZero(), "a fee cap below the base fee")
+
+	// Cosmos txs pay for their gas limit
+	fee := func(amount int64) sdk.Coins { return sdk.NewCoins(sdk.NewInt64Coin(app.BaseDenom, amount)) }
+	require.Equal(t, int64(500), k.CosmosSurplus(c.ctx, fee(100*200+500), 200).Int64())
+	require.True(t, k.CosmosSurplus(c.ctx, fee(100*200), 200).IsZero())
+	require.True(t, k.CosmosSurplus(c.ctx, fee(100), 200).IsZero())
+	require.True(t, k.CosmosSurplus(c.ctx, sdk.NewCoins(sdk.NewInt64Coin("uother", 1_000_000)), 200).IsZero(), "other denoms don't tip")
+
+	// without a base fee, the tips are paid above the min gas price
+	feeMarketParams.NoBaseFee = true
+	require.NoError(t, c.app.FeeMarketKeeper.SetParams(c.ctx, feeMarketParams))
+	require.Equal(t, "11", k.BaseGasPrice(c.ctx).String())
+	require.Equal(t, int64(20*21_000), k.EVMTip(c.ctx, dynamicTx(20, 1000), 21_000).Int64())
+	require.Equal(t, int64(119*50_000), k.EVMTip(c.ctx, legacyTx, 50_000).Int64())
+	require.Equal(t, int64(500), k.CosmosSurplus(c.ctx, fee(11*200+500), 200).Int64())
+}
+
+func TestProtocolRevenue(t *testing.T) {
+	c := setup(t, types.Params{EVMTipShare: sdkmath.LegacyOneDec(), CosmosSurplusShare: sdkmath.LegacyZeroDec()})
+	k := c.app.FeeRoutingKeeper
+
+	// 1000 minted per block, half of the fees burnt and a community tax of 10%
+	emissionParams := emissiontypes.DefaultParams()
+	emissionParams.MintDenom = app.BaseDenom
+	emissionParams.AnnualProvisions = sdkmath.NewInt(100 * 1000)
+	emissionParams.BlocksPerYear = 100
+	emissionParams.ReductionInterval = 0
+	require.NoError(t, emissionParams.Validate())
+	c.app.EmissionKeeper.SetParams(c.ctx, emissionParams)
+	half := sdkmath.LegacyNewDecWithPrec(5, 1)
+	c.app.FeeBurnKeeper.SetParams(c.ctx, feeburntypes.Params{MinBurnRatio: half, MaxBurnRatio: half})
+	distrParams, err := c.app.DistrKeeper.Params.Get(c.ctx)
+	require.NoError(t, err)
+	distrParams.CommunityTax = sdkmath.LegacyNewDecWithPrec(1, 1)
+	require.NoError(t, c.app.DistrKeeper.Params.Set(c.ctx, distrParams))
+	c.app.RewardSnapshotKeeper.SetParams(c.ctx, rewardsnapshottypes.Params{EpochLength: 3, Retention: 2})
+
+	// handOut empties the fee collector in place of x/distribution
+	handOut := func(ctx sdk.Context) {
+		coins := c.app.BankKeeper.GetAllBalances(ctx, authtypes.NewModuleAddress(authtypes.FeeCollectorName))
+		require.NoError(t, c.app.BankKeeper.SendCoinsFromModuleToModule(ctx, authtypes.FeeCollectorName, distrtypes.ModuleName, coins))
+	}
+	handOut(c.ctx)
+
+	var events []*types.EventEpochRevenue
+	for height := int64(2); height <= 10; height++ {
+		ctx := c.ctx.WithBlockHeight(height).
+			WithBlockHeader(cmtproto.Header{Height: height, ProposerAddress: must(c.validators[0].GetConsAddr())}).
+			WithEventManager(sdk.NewEventManager())
+
+		// the begin blockers in the order of the app
+		_, err := c.app.FeeBurnKeeper.BurnFees(ctx)
+		require.NoError(t, err)
+		_, err = c.app.EmissionKeeper.MintBlockProvision(ctx)
+		require.NoError(t, err)
+		require.NoError(t, k.RecordCommunityTax(ctx))
+		handOut(ctx)
+
+		// the txs of the block pay 10_000, 100 of which is tipped
+		c.collectFees(10_000)
+		k.AddBlockTips(ctx, sdkmath.NewInt(100), sdkmath.ZeroInt())
+
+		c.app.RewardSnapshotKeeper.EndBlocker(ctx)
+		require.NoError(t, k.RouteTips(ctx))
+		require.NoError(t, k.ReportEpochRevenue(ctx))
+		for _, event := range ctx.EventManager().ABCIEvents() {
+			if event.Type != proto.MessageName(&types.EventEpochRevenue{}) {
+				continue
+			}
+			msg, err := sdk.ParseTypedEvent(event)
+			require.NoError(t, err)
+			events = append(events, msg.(*types.EventEpochRevenue))
+		}
+	}
+
+	// from the second block, half of the 9_900 fees left after the tips is
+	// burnt and the community tax is taken on the 4_950 left and the 1_000
+	// minted. The epoch of x/rewardsnapshot starts at the end of the first
+	// block, the revenue of the start of the block only counts in the total.
+	revenue := func(minted, burned, tips, tax int64) types.Revenue {
+		return types.Revenue{
+			Minted:       sdkmath.NewInt(minted),
+			Burned:       sdkmath.NewInt(burned),
+			ProposerTips: sdkmath.NewInt(tips),
+			CommunityTax: sdkmath.NewInt(tax),
+		}
+	}
+	requireJSON(t, revenue(9*1000, 8*4950, 9*100, 100+8*595), k.GetTotalRevenue(c.ctx))
+	// the tips of the block ending an epoch count in the next one
+	epoch1 := types.EpochRevenue{Epoch: 1, StartHeight: 2, EndHeight: 4, Revenue: revenue(2*1000, 2*4950, 2*100, 2*595)}
+	epoch2 := types.EpochRevenue{Epoch: 2, StartHeight: 5, EndHeight: 7, Revenue: revenue(3*1000, 3*4950, 3*100, 3*595)}
+	epoch3 := types.EpochRevenue{Epoch: 3, StartHeight: 8, EndHeight: 10, Revenue: epoch2.Revenue}
+	epoch4 := types.EpochRevenue{Epoch: 4, StartHeight: 11, Revenue: revenue(0, 0, 100, 0)}
+
+	eventOf := func(e types.EpochRevenue) *types.EventEpochRevenue {
+		return &types.EventEpochRevenue{
+			Epoch:        e.Epoch,
+			StartHeight:  e.StartHeight,
+			EndHeight:    e.EndHeight,
+			Minted:       e.Minted.String(),
+			Burned:       e.Burned.String(),
+			ProposerTips: e.ProposerTips.String(),
+			CommunityTax: e.CommunityTax.String(),
+		}
+	}
+	require.Equal(t, []*types.EventEpochRevenue{eventOf(epoch1), eventOf(epoch2), eventOf(epoch3)}, events)
+
+	// the first epoch is past the retention of the snapshots
+	_, found := k.GetEpochRevenue(c.ctx, 1)
+	require.False(t, found)
+	requireJSON(t, []types.EpochRevenue{epoch2, epoch3, epoch4}, k.GetAllEpochRevenues(c.ctx))
+
+	// without snapshots, the revenue only counts in the total
+	c.app.RewardSnapshotKeeper.SetParams(c.ctx, rewardsnapshottypes.Params{EpochLength: 0, Retention: 2})
+	ctx := c.ctx.WithBlockHeight(11)
+	c.app.RewardSnapshotKeeper.EndBlocker(ctx)
+	require.NoError(t, c.app.FeeRoutingKeeper.Hooks().AfterBurnFees(ctx, sdk.NewInt64Coin(app.BaseDenom, 50)))
+	require.NoError(t, c.app.FeeRoutingKeeper.Hooks().AfterBurnFees(ctx, sdk.NewInt64Coin("uother", 50)), "other denoms aren't counted")
+	require.Equal(t, int64(8*4950+50), k.GetTotalRevenue(c.ctx).Burned.Int64())
+	requireJSON(t, []types.EpochRevenue{epoch2, epoch3, epoch4}, k.GetAllEpochRevenues(c.ctx))
+}
+
+func TestGenesis(t *testing.T) {
+	c := setup(t, types.DefaultParams())
+	k := c.app.FeeRoutingKeeper
+
+	valAddr := must(sdk.ValAddressFromBech32(c.validators[1].GetOperator()))
+	gs := types.GenesisState{
+		Params: types.Params{EVMTipShare: sdkmath.LegacyOneDec(), CosmosSurplusShare: sdkmath.LegacyZeroDec()},
+		ProposerTips: []types.ProposerTips{{
+			Validator:  valAddr.String(),
+			Tips:       types.NewTips(sdkmath.NewInt(1000), sdkmath.NewInt(10)),
+			Blocks:     3,
+			LastHeight: 42,
+		}},
+		TotalRevenue: types.Revenue{
+			Minted:       sdkmath.NewInt(5000),
+			Burned:       sdkmath.NewInt(700),
+			ProposerTips: sdkmath.NewInt(1010),
+			CommunityTax: sdkmath.NewInt(90),
+		},
+		EpochRevenues: []types.EpochRevenue{
+			{Epoch: 6, StartHeight: 50, EndHeight: 59, Revenue: types.ZeroRevenue()},
+			types.NewEpochRevenue(7, 60),
+		},
+	}
+	require.NoError(t, gs.Validate())
+	k.InitGenesis(c.ctx, gs)
+	requireJSON(t, &gs, k.ExportGenesis(c.ctx))
+	requireJSON(t, gs.ProposerTips[0], k.GetProposerTips(c.ctx, valAddr))
+	requireJSON(t, gs.TotalRevenue, k.GetTotalRevenue(c.ctx))
+}
+
+// requireJSON checks actual encodes to the JSON of expected, the way the
+// store holds it
+func requireJSON(t *testing.T, expected, actual any) {
+	t.Helper()
+
+	expectedBz, err := json.Marshal(expected)
+	require.NoError(t, err)
+	actualBz, err := json.Marshal(actual)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectedBz), string(actualBz))
+}
+
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
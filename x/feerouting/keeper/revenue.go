@@ -0,0 +1,174 @@
+package keeper
+
+import (
+	"github.com/hashicorp/go-metrics"
+
+	storetypes "cosmossdk.io/store/types"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+
+	"github.com/Asphere-xyz/tacchain/x/feerouting/types"
+)
+
+// AddRevenue adds revenue to the protocol revenue since the module was added
+// and to the one of the epoch of x/rewardsnapshot in progress. Nothing is
+// added to an epoch when the snapshots are disabled or before the first epoch
+// started.
+func (k Keeper) AddRevenue(ctx sdk.Context, revenue types.Revenue) {
+	if revenue.IsZero() {
+		return
+	}
+	k.setTotalRevenue(ctx, k.GetTotalRevenue(ctx).Add(revenue))
+
+	if epoch, found := k.rewardSnapshotKeeper.GetCurrentEpoch(ctx); found {
+		epochRevenue, found := k.GetEpochRevenue(ctx, epoch.Number)
+		// an epoch dropped when the snapshots were disabled is started over
+		if !found || epochRevenue.StartHeight != epoch.StartHeight {
+			epochRevenue = types.NewEpochRevenue(epoch.Number, epoch.StartHeight)
+		}
+		epochRevenue.Revenue = epochRevenue.Revenue.Add(revenue)
+		k.setEpochRevenue(ctx, epochRevenue)
+	}
+
+	for _, amount := range revenue.Amounts() {
+		if amount.Amount.IsPositive() {
+			telemetry.IncrCounterWithLabels([]string{"protocol", "revenue"}, float32(amount.Amount.ToLegacyDec().MustFloat64()), []metrics.Label{telemetry.NewLabel("kind", amount.Name)})
+		}
+	}
+}
+
+// RecordCommunityTax adds the community tax x/distribution takes on the fees
+// and the provision held by the fee collector to the protocol revenue. It runs
+// right before x/distribution, which hands them out from the second block.
+func (k Keeper) RecordCommunityTax(ctx sdk.Context) error {
+	if ctx.BlockHeight() <= 1 {
+		return nil
+	}
+	rate, err := k.distrKeeper.GetCommunityTax(ctx)
+	if err != nil {
+		return err
+	}
+	fees := k.bankKeeper.GetBalance(ctx, authtypes.NewModuleAddress(k.feeCollectorName), k.denom)
+
+	revenue := types.ZeroRevenue()
+	revenue.CommunityTax = rate.MulInt(fees.Amount).TruncateInt()
+	k.AddRevenue(ctx, revenue)
+	return nil
+}
+
+// ReportEpochRevenue ends the revenue of the epoch x/rewardsnapshot ended in
+// the block, reports it and prunes the revenue of the epochs past the
+// retention of the snapshots. The tips of the block are routed after the
+// epoch ended and count with the next one, like the rewards they are.
+func (k Keeper) ReportEpochRevenue(ctx sdk.Context) error {
+	current, found := k.rewardSnapshotKeeper.GetCurrentEpoch(ctx)
+	if !found || current.Number <= 1 {
+		return nil
+	}
+	epoch, found := k.rewardSnapshotKeeper.GetEpoch(ctx, current.Number-1)
+	if !found || epoch.EndHeight != ctx.BlockHeight() {
+		return nil
+	}
+
+	revenue, found := k.GetEpochRevenue(ctx, epoch.Number)
+	if !found || revenue.StartHeight != epoch.StartHeight {
+		revenue = types.NewEpochRevenue(epoch.Number, epoch.StartHeight)
+	}
+	revenue.EndHeight = epoch.EndHeight
+	k.setEpochRevenue(ctx, revenue)
+
+	if retention := k.rewardSnapshotKeeper.GetParams(ctx).Retention; epoch.Number > retention {
+		k.deleteRange(ctx, types.EpochRevenuePrefix, types.EpochRevenueKey(epoch.Number-retention+1))
+	}
+
+	for _, amount := range revenue.Amounts() {
+		telemetry.SetGaugeWithLabels([]string{"protocol", "epoch_revenue"}, float32(amount.Amount.ToLegacyDec().MustFloat64()), []metrics.Label{telemetry.NewLabel("kind", amount.Name)})
+	}
+	k.Logger(ctx).Info("protocol revenue of the ended epoch", "epoch", revenue.Epoch, "minted", revenue.Minted, "burned", revenue.Burned,
+		"proposer_tips", revenue.ProposerTips, "community_tax", revenue.CommunityTax)
+
+	return ctx.EventManager().EmitTypedEvent(&types.EventEpochRevenue{
+		Epoch:        revenue.Epoch,
+		StartHeight:  revenue.StartHeight,
+		EndHeight:    revenue.EndHeight,
+		Minted:       revenue.Minted.String(),
+		Burned:       revenue.Burned.String(),
+		ProposerTips: revenue.ProposerTips.String(),
+		CommunityTax: revenue.CommunityTax.String(),
+	})
+}
+
+// GetTotalRevenue returns the protocol revenue since the module was added
+func (k Keeper) GetTotalRevenue(ctx sdk.Context) types.Revenue {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.TotalRevenueKey)
+	if err != nil {
+		panic(err)
+	}
+	if len(bz) == 0 {
+		return types.ZeroRevenue()
+	}
+	var revenue types.Revenue
+	mustUnmarshal(bz, &revenue)
+	return revenue
+}
+
+func (k Keeper) setTotalRevenue(ctx sdk.Context, revenue types.Revenue) {
+	k.set(ctx, types.TotalRevenueKey, revenue)
+}
+
+// GetEpochRevenue returns the revenue of epoch number, if it had any and
+// wasn't pruned
+func (k Keeper) GetEpochRevenue(ctx sdk.Context, number uint64) (types.EpochRevenue, bool) {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.EpochRevenueKey(number))
+	if err != nil {
+		panic(err)
+	}
+	if len(bz) == 0 {
+		return types.EpochRevenue{}, false
+	}
+	var revenue types.EpochRevenue
+	mustUnmarshal(bz, &revenue)
+	return revenue, true
+}
+
+// GetAllEpochRevenues returns the revenue of the kept epochs, by number
+func (k Keeper) GetAllEpochRevenues(ctx sdk.Context) []types.EpochRevenue {
+	iterator, err := k.storeService.OpenKVStore(ctx).Iterator(types.EpochRevenuePrefix, storetypes.PrefixEndBytes(types.EpochRevenuePrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	all := []types.EpochRevenue{}
+	for ; iterator.Valid(); iterator.Next() {
+		var revenue types.EpochRevenue
+		mustUnmarshal(iterator.Value(), &revenue)
+		all = append(all, revenue)
+	}
+	return all
+}
+
+func (k Keeper) setEpochRevenue(ctx sdk.Context, revenue types.EpochRevenue) {
+	k.set(ctx, types.EpochRevenueKey(revenue.Epoch), revenue)
+}
+
+func (k Keeper) deleteRange(ctx sdk.Context, start, end []byte) {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(start, end)
+	if err != nil {
+		panic(err)
+	}
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+
+	for _, key := range keys {
+		if err := store.Delete(key); err != nil {
+			panic(err)
+		}
+	}
+}
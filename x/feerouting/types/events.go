@@ -0,0 +1,41 @@
+package types
+
+import (
+	"github.com/cosmos/gogoproto/proto"
+)
+
+// init registers the events of the tips routed to the block proposers and of
+// the protocol revenue of the epochs of x/rewardsnapshot.
+func init() {
+	proto.RegisterType((*EventRouteTips)(nil), "tacchain.feerouting.v1.EventRouteTips")
+	proto.RegisterType((*EventEpochRevenue)(nil), "tacchain.feerouting.v1.EventEpochRevenue")
+}
+
+// EventRouteTips is emitted when the tips of a block were routed to the
+// validator that proposed it.
+type EventRouteTips struct {
+	Validator     string `protobuf:"bytes,1,opt,name=validator,proto3" json:"validator,omitempty"`
+	Amount        string `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	EvmTips       string `protobuf:"bytes,3,opt,name=evm_tips,json=evmTips,proto3" json:"evm_tips,omitempty"`
+	CosmosSurplus string `protobuf:"bytes,4,opt,name=cosmos_surplus,json=cosmosSurplus,proto3" json:"cosmos_surplus,omitempty"`
+}
+
+func (m *EventRouteTips) Reset()         { *m = EventRouteTips{} }
+func (m *EventRouteTips) String() string { return proto.CompactTextString(m) }
+func (*EventRouteTips) ProtoMessage()    {}
+
+// EventEpochRevenue is emitted when an epoch of x/rewardsnapshot ended, with
+// the protocol revenue of its blocks.
+type EventEpochRevenue struct {
+	Epoch        uint64 `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	StartHeight  int64  `protobuf:"varint,2,opt,name=start_height,json=startHeight,proto3" json:"start_height,omitempty"`
+	EndHeight    int64  `protobuf:"varint,3,opt,name=end_height,json=endHeight,proto3" json:"end_height,omitempty"`
+	Minted       string `protobuf:"bytes,4,opt,name=minted,proto3" json:"minted,omitempty"`
+	Burned       string `protobuf:"bytes,5,opt,name=burned,proto3" json:"burned,omitempty"`
+	ProposerTips string `protobuf:"bytes,6,opt,name=proposer_tips,json=proposerTips,proto3" json:"proposer_tips,omitempty"`
+	CommunityTax string `protobuf:"bytes,7,opt,name=community_tax,json=communityTax,proto3" json:"community_tax,omitempty"`
+}
+
+func (m *EventEpochRevenue) Reset()         { *m = EventEpochRevenue{} }
+func (m *EventEpochRevenue) String() string { return proto.CompactTextString(m) }
+func (*EventEpochRevenue) ProtoMessage()    {}
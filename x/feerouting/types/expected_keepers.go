@@ -0,0 +1,45 @@
+package types
+
+import (
+	"context"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	evmfeemarkettypes "github.com/cosmos/evm/x/feemarket/types"
+
+	rewardsnapshottypes "github.com/Asphere-xyz/tacchain/x/rewardsnapshot/types"
+)
+
+// BankKeeper defines the expected bank keeper
+type BankKeeper interface {
+	GetBalance(ctx context.Context, addr sdk.AccAddress, denom string) sdk.Coin
+	SendCoinsFromModuleToModule(ctx context.Context, senderModule, recipientModule string, amt sdk.Coins) error
+}
+
+// StakingKeeper defines the expected staking keeper
+type StakingKeeper interface {
+	GetValidatorByConsAddr(ctx context.Context, consAddr sdk.ConsAddress) (stakingtypes.Validator, error)
+}
+
+// DistrKeeper defines the expected distribution keeper
+type DistrKeeper interface {
+	AllocateTokensToValidator(ctx context.Context, val stakingtypes.ValidatorI, tokens sdk.DecCoins) error
+	GetCommunityTax(ctx context.Context) (sdkmath.LegacyDec, error)
+}
+
+// FeeMarketKeeper defines the expected fee market keeper
+type FeeMarketKeeper interface {
+	GetBaseFee(ctx sdk.Context) sdkmath.LegacyDec
+	GetParams(ctx sdk.Context) evmfeemarkettypes.Params
+}
+
+// RewardSnapshotKeeper defines the expected rewardsnapshot keeper, the
+// protocol revenue is reported over its epochs
+type RewardSnapshotKeeper interface {
+	GetParams(ctx sdk.Context) rewardsnapshottypes.Params
+	GetCurrentEpoch(ctx sdk.Context) (rewardsnapshottypes.Epoch, bool)
+	GetEpoch(ctx sdk.Context, number uint64) (rewardsnapshottypes.Epoch, bool)
+}
@@ -0,0 +1,53 @@
+package types
+
+import "fmt"
+
+// GenesisState defines the feerouting module genesis state. The tips of a
+// block are routed at its end and are never part of it.
+type GenesisState struct {
+	Params        Params         `json:"params" yaml:"params"`
+	ProposerTips  []ProposerTips `json:"proposer_tips" yaml:"proposer_tips"`
+	TotalRevenue  Revenue        `json:"total_revenue" yaml:"total_revenue"`
+	EpochRevenues []EpochRevenue `json:"epoch_revenues" yaml:"epoch_revenues"`
+}
+
+// DefaultGenesisState returns the default feerouting module genesis state
+func DefaultGenesisState() *GenesisState {
+	return &GenesisState{
+		Params:        DefaultParams(),
+		ProposerTips:  []ProposerTips{},
+		TotalRevenue:  ZeroRevenue(),
+		EpochRevenues: []EpochRevenue{},
+	}
+}
+
+// Validate performs basic genesis state validation
+func (gs GenesisState) Validate() error {
+	if err := gs.Params.Validate(); err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(gs.ProposerTips))
+	for _, tips := range gs.ProposerTips {
+		if err := tips.Validate(); err != nil {
+			return err
+		}
+		if seen[tips.Validator] {
+			return fmt.Errorf("duplicate tips of validator %s", tips.Validator)
+		}
+		seen[tips.Validator] = true
+	}
+	if err := gs.TotalRevenue.Validate(); err != nil {
+		return fmt.Errorf("total revenue: %w", err)
+	}
+	epochs := make(map[uint64]bool, len(gs.EpochRevenues))
+	for _, revenue := range gs.EpochRevenues {
+		if err := revenue.Validate(); err != nil {
+			return err
+		}
+		if epochs[revenue.Epoch] {
+			return fmt.Errorf("duplicate revenue of epoch %d", revenue.Epoch)
+		}
+		epochs[revenue.Epoch] = true
+	}
+	return nil
+}
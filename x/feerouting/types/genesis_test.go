@@ -0,0 +1,101 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/feerouting/types"
+)
+
+func TestParamsValidate(t *testing.T) {
+	require.NoError(t, types.DefaultParams().Validate())
+	require.False(t, types.DefaultParams().Enabled())
+	params := types.Params{EVMTipShare: sdkmath.LegacyOneDec(), CosmosSurplusShare: sdkmath.LegacyNewDecWithPrec(5, 1)}
+	require.NoError(t, params.Validate())
+	require.True(t, params.Enabled())
+
+	require.Error(t, types.Params{EVMTipShare: sdkmath.LegacyNewDecWithPrec(-1, 1), CosmosSurplusShare: sdkmath.LegacyZeroDec()}.Validate(), "negative share")
+	require.Error(t, types.Params{EVMTipShare: sdkmath.LegacyZeroDec(), CosmosSurplusShare: sdkmath.LegacyNewDecWithPrec(11, 1)}.Validate(), "share above one")
+	require.Error(t, types.Params{EVMTipShare: sdkmath.LegacyOneDec()}.Validate(), "nil share")
+}
+
+func TestGenesisValidate(t *testing.T) {
+	require.NoError(t, types.DefaultGenesisState().Validate())
+
+	valAddr := sdk.ValAddress([]byte("validator___________")).String()
+	tips := types.ProposerTips{Validator: valAddr, Tips: types.NewTips(sdkmath.NewInt(10), sdkmath.ZeroInt()), Blocks: 1, LastHeight: 5}
+	gs := types.GenesisState{Params: types.DefaultParams(), ProposerTips: []types.ProposerTips{tips}, TotalRevenue: types.ZeroRevenue()}
+	require.NoError(t, gs.Validate())
+
+	gs.ProposerTips = []types.ProposerTips{tips, tips}
+	require.ErrorContains(t, gs.Validate(), "duplicate tips of validator")
+
+	invalid := tips
+	invalid.Validator = "tacvaloper1invalid"
+	gs.ProposerTips = []types.ProposerTips{invalid}
+	require.ErrorContains(t, gs.Validate(), "invalid validator address")
+
+	negative := tips
+	negative.CosmosSurplus = sdkmath.NewInt(-1)
+	gs.ProposerTips = []types.ProposerTips{negative}
+	require.ErrorContains(t, gs.Validate(), "invalid cosmos surplus")
+
+	gs.ProposerTips = []types.ProposerTips{{Validator: valAddr}}
+	require.ErrorContains(t, gs.Validate(), "invalid evm tips")
+}
+
+func TestGenesisValidateRevenue(t *testing.T) {
+	revenue := types.NewEpochRevenue(3, 100)
+	revenue.Minted = sdkmath.NewInt(1000)
+	revenue.EndHeight = 199
+	gs := types.DefaultGenesisState()
+	gs.TotalRevenue = revenue.Revenue
+	gs.EpochRevenues = []types.EpochRevenue{revenue, types.NewEpochRevenue(4, 200)}
+	require.NoError(t, gs.Validate())
+
+	gs.EpochRevenues = []types.EpochRevenue{revenue, revenue}
+	require.ErrorContains(t, gs.Validate(), "duplicate revenue of epoch 3")
+
+	gs.EpochRevenues = []types.EpochRevenue{types.NewEpochRevenue(0, 100)}
+	require.ErrorContains(t, gs.Validate(), "invalid epoch number")
+
+	ended := revenue
+	ended.EndHeight = 99
+	gs.EpochRevenues = []types.EpochRevenue{ended}
+	require.ErrorContains(t, gs.Validate(), "ends at 99 before it starts at 100")
+
+	negative := revenue
+	negative.Burned = sdkmath.NewInt(-1)
+	gs.EpochRevenues = []types.EpochRevenue{negative}
+	require.ErrorContains(t, gs.Validate(), "epoch 3: invalid burned amount")
+
+	gs.EpochRevenues = nil
+	gs.TotalRevenue = types.Revenue{}
+	require.ErrorContains(t, gs.Validate(), "total revenue: invalid minted amount")
+}
+
+func TestRevenue(t *testing.T) {
+	revenue := types.ZeroRevenue()
+	require.True(t, revenue.IsZero())
+	revenue = revenue.Add(types.Revenue{
+		Minted:       sdkmath.NewInt(100),
+		Burned:       sdkmath.NewInt(150),
+		ProposerTips: sdkmath.NewInt(20),
+		CommunityTax: sdkmath.NewInt(2),
+	})
+	revenue = revenue.Add(types.Revenue{
+		Minted:       sdkmath.NewInt(10),
+		Burned:       sdkmath.ZeroInt(),
+		ProposerTips: sdkmath.ZeroInt(),
+		CommunityTax: sdkmath.NewInt(1),
+	})
+	require.False(t, revenue.IsZero())
+	require.Equal(t, "110", revenue.Minted.String())
+	require.Equal(t, "3", revenue.CommunityTax.String())
+	require.Equal(t, "-40", revenue.NetIssuance().String(), "more was burnt than minted")
+}
@@ -0,0 +1,38 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// ModuleName defines the feerouting module name
+	ModuleName = "feerouting"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+)
+
+var (
+	// BlockTipsKey is the key of the tips collected by the txs of the block in
+	// progress, cleared at the end of the block
+	BlockTipsKey = []byte{0x01}
+	// ProposerTipsPrefix prefixes the tips routed to each validator for the
+	// blocks it proposed, keyed by operator address
+	ProposerTipsPrefix = []byte{0x02}
+	// EpochRevenuePrefix prefixes the protocol revenue of the epochs of
+	// x/rewardsnapshot, keyed by number
+	EpochRevenuePrefix = []byte{0x03}
+	// TotalRevenueKey is the key of the protocol revenue since the module was
+	// added
+	TotalRevenueKey = []byte{0x04}
+)
+
+// ProposerTipsKey returns the store key of the tips routed to the validator
+func ProposerTipsKey(valAddr sdk.ValAddress) []byte {
+	return append(append([]byte{}, ProposerTipsPrefix...), valAddr...)
+}
+
+// EpochRevenueKey returns the store key of the revenue of epoch number
+func EpochRevenueKey(number uint64) []byte {
+	return append(append([]byte{}, EpochRevenuePrefix...), sdk.Uint64ToBigEndian(number)...)
+}
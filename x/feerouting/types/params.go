@@ -0,0 +1,77 @@
+package types
+
+import (
+	"fmt"
+
+	sdkmath "cosmossdk.io/math"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+var (
+	// KeyEVMTipShare is the param store key for the share of the EVM priority
+	// fees routed to the block proposer
+	KeyEVMTipShare = []byte("EVMTipShare")
+	// KeyCosmosSurplusShare is the param store key for the share of the fees
+	// Cosmos txs pay above the base fee routed to the block proposer
+	KeyCosmosSurplusShare = []byte("CosmosSurplusShare")
+)
+
+// Params defines the feerouting module parameters. EVMTipShare of the
+// priority fees of the EVM txs of a block, the gas they used times their
+// effective gas tip over the base fee, is routed to the validator that
+// proposed the block instead of being distributed to all stakers, as on
+// Ethereum. CosmosSurplusShare does the same for the fees Cosmos txs pay above
+// the base fee for their gas limit. Both shares default to zero, which
+// distributes all fees.
+type Params struct {
+	EVMTipShare        sdkmath.LegacyDec `json:"evm_tip_share" yaml:"evm_tip_share"`
+	CosmosSurplusShare sdkmath.LegacyDec `json:"cosmos_surplus_share" yaml:"cosmos_surplus_share"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the feerouting module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default feerouting module parameters
+func DefaultParams() Params {
+	return Params{
+		EVMTipShare:        sdkmath.LegacyZeroDec(),
+		CosmosSurplusShare: sdkmath.LegacyZeroDec(),
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyEVMTipShare, &p.EVMTipShare, validateShare),
+		paramtypes.NewParamSetPair(KeyCosmosSurplusShare, &p.CosmosSurplusShare, validateShare),
+	}
+}
+
+// Validate performs basic validation of the feerouting module parameters
+func (p Params) Validate() error {
+	if err := validateShare(p.EVMTipShare); err != nil {
+		return err
+	}
+	return validateShare(p.CosmosSurplusShare)
+}
+
+// Enabled returns whether any fee is routed to the block proposers
+func (p Params) Enabled() bool {
+	return p.EVMTipShare.IsPositive() || p.CosmosSurplusShare.IsPositive()
+}
+
+func validateShare(i interface{}) error {
+	share, ok := i.(sdkmath.LegacyDec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if share.IsNil() || share.IsNegative() || share.GT(sdkmath.LegacyOneDec()) {
+		return fmt.Errorf("share must be in [0, 1]: %s", share)
+	}
+	return nil
+}
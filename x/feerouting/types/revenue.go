@@ -0,0 +1,107 @@
+package types
+
+import (
+	"fmt"
+
+	sdkmath "cosmossdk.io/math"
+)
+
+// Revenue sums the protocol economics of a range of blocks, in the smallest
+// unit of the EVM denom
+type Revenue struct {
+	// Minted is the provision minted by x/emission
+	Minted sdkmath.Int `json:"minted"`
+	// Burned is the share of the fees burnt by x/feeburn
+	Burned sdkmath.Int `json:"burned"`
+	// ProposerTips are the tips routed to the block proposers
+	ProposerTips sdkmath.Int `json:"proposer_tips"`
+	// CommunityTax is the community tax on the fees and provisions
+	// x/distribution handed out
+	CommunityTax sdkmath.Int `json:"community_tax"`
+}
+
+// ZeroRevenue returns an empty revenue
+func ZeroRevenue() Revenue {
+	return Revenue{
+		Minted:       sdkmath.ZeroInt(),
+		Burned:       sdkmath.ZeroInt(),
+		ProposerTips: sdkmath.ZeroInt(),
+		CommunityTax: sdkmath.ZeroInt(),
+	}
+}
+
+// Add returns the sum of the revenues
+func (r Revenue) Add(other Revenue) Revenue {
+	return Revenue{
+		Minted:       r.Minted.Add(other.Minted),
+		Burned:       r.Burned.Add(other.Burned),
+		ProposerTips: r.ProposerTips.Add(other.ProposerTips),
+		CommunityTax: r.CommunityTax.Add(other.CommunityTax),
+	}
+}
+
+// NetIssuance returns the minted amount less the burnt one, negative when
+// more was burnt than minted
+func (r Revenue) NetIssuance() sdkmath.Int {
+	return r.Minted.Sub(r.Burned)
+}
+
+// IsZero returns whether nothing was minted, burnt or handed out
+func (r Revenue) IsZero() bool {
+	return r.Minted.IsZero() && r.Burned.IsZero() && r.ProposerTips.IsZero() && r.CommunityTax.IsZero()
+}
+
+// RevenueAmount is an amount of a revenue, named after its json field
+type RevenueAmount struct {
+	Name   string
+	Amount sdkmath.Int
+}
+
+// Amounts returns the amounts of the revenue by name
+func (r Revenue) Amounts() []RevenueAmount {
+	return []RevenueAmount{
+		{"minted", r.Minted},
+		{"burned", r.Burned},
+		{"proposer_tips", r.ProposerTips},
+		{"community_tax", r.CommunityTax},
+	}
+}
+
+// Validate checks the amounts aren't negative
+func (r Revenue) Validate() error {
+	for _, amount := range r.Amounts() {
+		if amount.Amount.IsNil() || amount.Amount.IsNegative() {
+			return fmt.Errorf("invalid %s amount: %s", amount.Name, amount.Amount)
+		}
+	}
+	return nil
+}
+
+// EpochRevenue is the revenue of an epoch of x/rewardsnapshot. EndHeight is
+// set once it ended.
+type EpochRevenue struct {
+	Epoch       uint64 `json:"epoch"`
+	StartHeight int64  `json:"start_height"`
+	EndHeight   int64  `json:"end_height,omitempty"`
+	Revenue
+}
+
+// NewEpochRevenue returns the empty revenue of the epoch number starting at
+// startHeight
+func NewEpochRevenue(number uint64, startHeight int64) EpochRevenue {
+	return EpochRevenue{Epoch: number, StartHeight: startHeight, Revenue: ZeroRevenue()}
+}
+
+// Validate performs basic validation of the revenue of an epoch
+func (e EpochRevenue) Validate() error {
+	if e.Epoch == 0 {
+		return fmt.Errorf("invalid epoch number: 0")
+	}
+	if e.EndHeight != 0 && e.EndHeight < e.StartHeight {
+		return fmt.Errorf("epoch %d ends at %d before it starts at %d", e.Epoch, e.EndHeight, e.StartHeight)
+	}
+	if err := e.Revenue.Validate(); err != nil {
+		return fmt.Errorf("epoch %d: %w", e.Epoch, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package types
+
+import (
+	"fmt"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Tips are fees routed to a block proposer, in the smallest unit of the EVM
+// denom
+type Tips struct {
+	// EVMTips is the routed share of the priority fees of EVM txs
+	EVMTips sdkmath.Int `json:"evm_tips"`
+	// CosmosSurplus is the routed share of the fees Cosmos txs paid above the
+	// base fee
+	CosmosSurplus sdkmath.Int `json:"cosmos_surplus"`
+}
+
+// NewTips returns tips of evmTips and cosmosSurplus
+func NewTips(evmTips, cosmosSurplus sdkmath.Int) Tips {
+	return Tips{EVMTips: evmTips, CosmosSurplus: cosmosSurplus}
+}
+
+// ZeroTips returns empty tips
+func ZeroTips() Tips {
+	return NewTips(sdkmath.ZeroInt(), sdkmath.ZeroInt())
+}
+
+// Add returns the sum of the tips
+func (t Tips) Add(other Tips) Tips {
+	return NewTips(t.EVMTips.Add(other.EVMTips), t.CosmosSurplus.Add(other.CosmosSurplus))
+}
+
+// Total returns the amount of the tips
+func (t Tips) Total() sdkmath.Int {
+	return t.EVMTips.Add(t.CosmosSurplus)
+}
+
+// IsZero returns whether no fee is routed
+func (t Tips) IsZero() bool {
+	return t.Total().IsZero()
+}
+
+// Validate checks the tips aren't negative
+func (t Tips) Validate() error {
+	if t.EVMTips.IsNil() || t.EVMTips.IsNegative() {
+		return fmt.Errorf("invalid evm tips: %s", t.EVMTips)
+	}
+	if t.CosmosSurplus.IsNil() || t.CosmosSurplus.IsNegative() {
+		return fmt.Errorf("invalid cosmos surplus: %s", t.CosmosSurplus)
+	}
+	return nil
+}
+
+// ProposerTips are the tips routed to a validator for the blocks it proposed
+// since the module was added
+type ProposerTips struct {
+	Validator string `json:"validator"`
+	Tips
+	// Blocks is the number of proposed blocks the validator received tips for
+	Blocks uint64 `json:"blocks"`
+	// LastHeight is the height of the last of these blocks
+	LastHeight int64 `json:"last_height"`
+}
+
+// Validate performs basic validation of the tips of a proposer
+func (p ProposerTips) Validate() error {
+	if _, err := sdk.ValAddressFromBech32(p.Validator); err != nil {
+		return fmt.Errorf("invalid validator address %q: %w", p.Validator, err)
+	}
+	if err := p.Tips.Validate(); err != nil {
+		return fmt.Errorf("validator %s: %w", p.Validator, err)
+	}
+	return nil
+}
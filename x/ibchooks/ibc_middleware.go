@@ -0,0 +1,165 @@
+package ibchooks
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	transfertypes "github.com/cosmos/ibc-go/v8/modules/apps/transfer/types"
+	channeltypes "github.com/cosmos/ibc-go/v8/modules/core/04-channel/types"
+	porttypes "github.com/cosmos/ibc-go/v8/modules/core/05-port/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+
+	"github.com/Asphere-xyz/tacchain/x/ibchooks/keeper"
+	"github.com/Asphere-xyz/tacchain/x/ibchooks/types"
+)
+
+var _ porttypes.IBCModule = IBCMiddleware{}
+
+// IBCMiddleware executes the EVM call requested in the memo of a received
+// ICS-20 packet. The transferred funds are credited to an intermediate account
+// derived from the channel and the original sender, which then calls the
+// contract with the funds attached as value. If the call fails an error
+// acknowledgement is returned, reverting the transfer and refunding the sender.
+// A memo can instead set the metadata of the voucher of an asset native to the
+// counterparty, which registers its ERC-20 extension.
+type IBCMiddleware struct {
+	porttypes.IBCModule
+
+	keeper keeper.Keeper
+}
+
+// NewIBCMiddleware creates a new ibc hooks middleware wrapping the given application
+func NewIBCMiddleware(app porttypes.IBCModule, k keeper.Keeper) IBCMiddleware {
+	return IBCMiddleware{
+		IBCModule: app,
+		keeper:    k,
+	}
+}
+
+// OnRecvPacket implements the IBCModule interface.
+func (im IBCMiddleware) OnRecvPacket(
+	ctx sdk.Context,
+	packet channeltypes.Packet,
+	relayer sdk.AccAddress,
+) ibcexported.Acknowledgement {
+	var data transfertypes.FungibleTokenPacketData
+	if err := transfertypes.ModuleCdc.UnmarshalJSON(packet.GetData(), &data); err != nil {
+		return im.IBCModule.OnRecvPacket(ctx, packet, relayer)
+	}
+
+	if metadata, ok, err := types.ParseVoucherMetadataMemo(data.Memo); ok {
+		if err != nil {
+			return channeltypes.NewErrorAcknowledgement(err)
+		}
+		return im.onRecvVoucherMetadata(ctx, packet, data, relayer, metadata)
+	}
+
+	memo, ok, err := types.ParseMemo(data.Memo)
+	if !ok {
+		return im.IBCModule.OnRecvPacket(ctx, packet, relayer)
+	}
+	if err != nil {
+		return channeltypes.NewErrorAcknowledgement(err)
+	}
+
+	value, err := im.validatePacket(ctx, packet, data, memo)
+	if err != nil {
+		return channeltypes.NewErrorAcknowledgement(err)
+	}
+
+	// funds are received by the intermediate account executing the call
+	sender := types.IntermediateSender(packet.GetDestChannel(), data.Sender)
+	data.Receiver = sender.String()
+	packet.Data = data.GetBytes()
+
+	ack := im.IBCModule.OnRecvPacket(ctx, packet, relayer)
+	if ack == nil || !ack.Success() {
+		return ack
+	}
+
+	if _, err := im.keeper.ExecuteCall(ctx, common.BytesToAddress(sender), memo, value); err != nil {
+		im.keeper.Logger(ctx).Error("ibc hooks evm call failed", "contract", memo.Contract, "error", err)
+		return channeltypes.NewErrorAcknowledgement(err)
+	}
+
+	return ack
+}
+
+// onRecvVoucherMetadata receives a packet whose memo sets the metadata of the
+// voucher of the transferred asset. The metadata is set, and the voucher
+// registered as an ERC-20, once the transfer succeeded. Metadata that can't
+// be set fails the transfer, so the sender learns about it and is refunded.
+func (im IBCMiddleware) onRecvVoucherMetadata(
+	ctx sdk.Context,
+	packet channeltypes.Packet,
+	data transfertypes.FungibleTokenPacketData,
+	relayer sdk.AccAddress,
+	m types.VoucherMetadata,
+) ibcexported.Acknowledgement {
+	if _, ok, _ := types.ParseMemo(data.Memo); ok {
+		return channeltypes.NewErrorAcknowledgement(errorsmod.Wrap(types.ErrInvalidMemo, "memo can't both set voucher metadata and call a contract"))
+	}
+
+	metadata, err := im.keeper.VoucherMetadata(ctx, packet.GetDestPort(), packet.GetDestChannel(), data.Denom, m)
+	if err != nil {
+		return channeltypes.NewErrorAcknowledgement(err)
+	}
+
+	ack := im.IBCModule.OnRecvPacket(ctx, packet, relayer)
+	if ack == nil || !ack.Success() {
+		return ack
+	}
+
+	if err := im.keeper.SetVoucherMetadata(ctx, data.Denom, packet.GetDestChannel(), metadata); err != nil {
+		im.keeper.Logger(ctx).Error("ibc hooks voucher registration failed", "denom", metadata.Base, "error", err)
+		return channeltypes.NewErrorAcknowledgement(err)
+	}
+
+	return ack
+}
+
+// validatePacket checks that the packet can trigger the call and returns the
+// value to attach to it.
+func (im IBCMiddleware) validatePacket(
+	ctx sdk.Context,
+	packet channeltypes.Packet,
+	data transfertypes.FungibleTokenPacketData,
+	memo types.EVMMemo,
+) (*big.Int, error) {
+	// the receiver has to name the contract so the sender can't be misled into
+	// thinking the funds go to another account
+	contract := memo.ContractAddress()
+	if receiver, err := sdk.AccAddressFromBech32(data.Receiver); err == nil {
+		if common.BytesToAddress(receiver) != contract {
+			return nil, errorsmod.Wrapf(types.ErrInvalidPacket, "receiver %s must be the called contract %s", data.Receiver, contract.Hex())
+		}
+	} else if !common.IsHexAddress(data.Receiver) || common.HexToAddress(data.Receiver) != contract {
+		return nil, errorsmod.Wrapf(types.ErrInvalidPacket, "receiver %s must be the called contract %s", data.Receiver, contract.Hex())
+	}
+
+	// only the native coin returning home can be attached as value
+	if !transfertypes.ReceiverChainIsSource(packet.GetSourcePort(), packet.GetSourceChannel(), data.Denom) {
+		return nil, errorsmod.Wrapf(types.ErrInvalidPacket, "denom %s is not native to this chain", data.Denom)
+	}
+	voucherPrefix := transfertypes.GetDenomPrefix(packet.GetSourcePort(), packet.GetSourceChannel())
+	if denom := data.Denom[len(voucherPrefix):]; denom != im.keeper.EVMDenom() {
+		return nil, errorsmod.Wrapf(types.ErrInvalidPacket, "denom %s cannot be attached to an evm call, expected %s", denom, im.keeper.EVMDenom())
+	}
+
+	amount, ok := sdkmath.NewIntFromString(data.Amount)
+	if !ok || !amount.IsPositive() {
+		return nil, errorsmod.Wrapf(types.ErrInvalidPacket, "invalid amount %s", data.Amount)
+	}
+
+	if err := im.keeper.ValidateCall(ctx, memo); err != nil {
+		return nil, err
+	}
+
+	return amount.BigInt(), nil
+}
@@ -0,0 +1,447 @@
+package ibchooks_test
+
+import (
+	"fmt"
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+
+	"github.com/cosmos/evm/x/vm/statedb"
+	transfertypes "github.com/cosmos/ibc-go/v8/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	channeltypes "github.com/cosmos/ibc-go/v8/modules/core/04-channel/types"
+	porttypes "github.com/cosmos/ibc-go/v8/modules/core/05-port/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/ibchooks"
+	"github.com/Asphere-xyz/tacchain/x/ibchooks/types"
+)
+
+var (
+	// STOP, accepts any call and value
+	acceptCode = common.FromHex("0x00")
+	// PUSH1 0 PUSH1 0 REVERT
+	revertCode = common.FromHex("0x60006000fd")
+	// JUMPDEST PUSH1 0 JUMP, loops until it runs out of gas
+	loopCode = common.FromHex("0x5b600056")
+
+	acceptContract = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	revertContract = common.HexToAddress("0x1000000000000000000000000000000000000002")
+	loopContract   = common.HexToAddress("0x1000000000000000000000000000000000000003")
+)
+
+// mockTransferApp credits the packet amount to the receiver like the transfer
+// module does: the native denom returning home, or else the voucher of the
+// asset of the counterparty.
+type mockTransferApp struct {
+	porttypes.IBCModule
+
+	tacApp *app.TacChainApp
+}
+
+func (m mockTransferApp) OnRecvPacket(ctx sdk.Context, packet channeltypes.Packet, _ sdk.AccAddress) ibcexported.Acknowledgement {
+	var data transfertypes.FungibleTokenPacketData
+	if err := transfertypes.ModuleCdc.UnmarshalJSON(packet.GetData(), &data); err != nil {
+		return channeltypes.NewErrorAcknowledgement(err)
+	}
+
+	receiver, err := sdk.AccAddressFromBech32(data.Receiver)
+	if err != nil {
+		return channeltypes.NewErrorAcknowledgement(err)
+	}
+
+	denom := app.BaseDenom
+	if !transfertypes.ReceiverChainIsSource(packet.GetSourcePort(), packet.GetSourceChannel(), data.Denom) {
+		denom = voucherDenom(packet.GetDestChannel(), data.Denom)
+	}
+
+	amount, _ := sdkmath.NewIntFromString(data.Amount)
+	coins := sdk.NewCoins(sdk.NewCoin(denom, amount))
+	if err := m.tacApp.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins); err != nil {
+		return channeltypes.NewErrorAcknowledgement(err)
+	}
+	if err := m.tacApp.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, receiver, coins); err != nil {
+		return channeltypes.NewErrorAcknowledgement(err)
+	}
+
+	return channeltypes.NewResultAcknowledgement([]byte{byte(1)})
+}
+
+func setup(t *testing.T) (*app.TacChainApp, sdk.Context, ibchooks.IBCMiddleware) {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := tacApp.NewContext(false).WithChainID(app.DefaultChainID)
+
+	// the evm needs a proposer to resolve the coinbase
+	validators, err := tacApp.StakingKeeper.GetAllValidators(ctx)
+	require.NoError(t, err)
+	consAddr, err := validators[0].GetConsAddr()
+	require.NoError(t, err)
+	header := ctx.BlockHeader()
+	header.ProposerAddress = consAddr
+	ctx = ctx.WithBlockHeader(header).WithGasMeter(sdk.NewInfiniteGasMeter())
+
+	db := statedb.New(ctx, tacApp.EVMKeeper, statedb.NewEmptyTxConfig(common.BytesToHash(ctx.HeaderHash())))
+	db.SetCode(acceptContract, acceptCode)
+	db.SetCode(revertContract, revertCode)
+	db.SetCode(loopContract, loopCode)
+	require.NoError(t, db.Commit())
+
+	params := types.DefaultParams()
+	params.AllowedContracts = []string{acceptContract.Hex(), revertContract.Hex(), loopContract.Hex()}
+	tacApp.IBCHooksKeeper.SetParams(ctx, params)
+
+	return tacApp, ctx, ibchooks.NewIBCMiddleware(mockTransferApp{tacApp: tacApp}, tacApp.IBCHooksKeeper)
+}
+
+// newPacket returns a packet sending utac back home over channel-0
+func newPacket(denom, receiver, memo string) channeltypes.Packet {
+	data := transfertypes.NewFungibleTokenPacketData(denom, "1000", "tac1sender", receiver, memo)
+	return channeltypes.NewPacket(data.GetBytes(), 1, transfertypes.PortID, "channel-7", transfertypes.PortID, "channel-0", clienttypes.NewHeight(0, 100), 0)
+}
+
+// newVoucherPacket returns a packet sending denom from the counterparty over
+// destChannel
+func newVoucherPacket(denom, receiver, memo, destChannel string) channeltypes.Packet {
+	data := transfertypes.NewFungibleTokenPacketData(denom, "1000", "cosmos1sender", receiver, memo)
+	return channeltypes.NewPacket(data.GetBytes(), 1, transfertypes.PortID, "channel-7", transfertypes.PortID, destChannel, clienttypes.NewHeight(0, 100), 0)
+}
+
+// voucherDenom returns the denom of the voucher of denom received over destChannel
+func voucherDenom(destChannel, denom string) string {
+	return transfertypes.ParseDenomTrace(transfertypes.GetPrefixedDenom(transfertypes.PortID, destChannel, denom)).IBCDenom()
+}
+
+func metadataMemo(name, symbol, display string, decimals uint32) string {
+	return fmt.Sprintf(`{"erc20":{"name":"%s","symbol":"%s","display":"%s","decimals":%d}}`, name, symbol, display, decimals)
+}
+
+func callMemo(contract common.Address, gasLimit uint64) string {
+	return fmt.Sprintf(`{"evm":{"contract":"%s","gas_limit":%d}}`, contract.Hex(), gasLimit)
+}
+
+func TestOnRecvPacket(t *testing.T) {
+	nativeDenom := "transfer/channel-7/" + app.BaseDenom
+	intermediate := types.IntermediateSender("channel-0", "tac1sender")
+	amount := sdk.NewCoin(app.BaseDenom, sdkmath.NewInt(1000))
+
+	testCases := []struct {
+		name      string
+		denom     string
+		receiver  common.Address
+		memo      string
+		expectAck bool
+		// balance expected at the receiver after the packet was processed
+		expectBalance sdk.Coin
+	}{
+		{
+			"plain transfer is passed through",
+			nativeDenom,
+			acceptContract,
+			"",
+			true,
+			amount,
+		},
+		{
+			"memo of another middleware is passed through",
+			nativeDenom,
+			acceptContract,
+			`{"forward":{"receiver":"tac1receiver"}}`,
+			true,
+			amount,
+		},
+		{
+			"call succeeds and forwards the funds",
+			nativeDenom,
+			acceptContract,
+			callMemo(acceptContract, 100_000),
+			true,
+			amount,
+		},
+		{
+			"reverting call is refunded",
+			nativeDenom,
+			revertContract,
+			callMemo(revertContract, 100_000),
+			false,
+			sdk.NewCoin(app.BaseDenom, sdkmath.ZeroInt()),
+		},
+		{
+			"call running out of gas is refunded",
+			nativeDenom,
+			loopContract,
+			callMemo(loopContract, 100_000),
+			false,
+			sdk.NewCoin(app.BaseDenom, sdkmath.ZeroInt()),
+		},
+		{
+			"gas limit above the maximum is rejected",
+			nativeDenom,
+			acceptContract,
+			callMemo(acceptContract, types.DefaultMaxGasLimit+1),
+			false,
+			sdk.NewCoin(app.BaseDenom, sdkmath.ZeroInt()),
+		},
+		{
+			"contract not whitelisted is rejected",
+			nativeDenom,
+			common.HexToAddress("0x2000000000000000000000000000000000000001"),
+			callMemo(common.HexToAddress("0x2000000000000000000000000000000000000001"), 100_000),
+			false,
+			sdk.NewCoin(app.BaseDenom, sdkmath.ZeroInt()),
+		},
+		{
+			"receiver other than the contract is rejected",
+			nativeDenom,
+			revertContract,
+			callMemo(acceptContract, 100_000),
+			false,
+			sdk.NewCoin(app.BaseDenom, sdkmath.ZeroInt()),
+		},
+		{
+			"voucher denom is rejected",
+			"utac",
+			acceptContract,
+			callMemo(acceptContract, 100_000),
+			false,
+			sdk.NewCoin(app.BaseDenom, sdkmath.ZeroInt()),
+		},
+		{
+			"malformed call is rejected",
+			nativeDenom,
+			acceptContract,
+			`{"evm":{"contract":"0x1234","gas_limit":100000}}`,
+			false,
+			sdk.NewCoin(app.BaseDenom, sdkmath.ZeroInt()),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tacApp, ctx, middleware := setup(t)
+			receiver := sdk.AccAddress(tc.receiver.Bytes())
+
+			// core IBC discards the state changes of a failed acknowledgement
+			cacheCtx, write := ctx.CacheContext()
+			ack := middleware.OnRecvPacket(cacheCtx, newPacket(tc.denom, receiver.String(), tc.memo), sdk.AccAddress{})
+			require.Equal(t, tc.expectAck, ack.Success(), string(ack.Acknowledgement()))
+			if ack.Success() {
+				write()
+			}
+
+			require.Equal(t, tc.expectBalance, tacApp.BankKeeper.GetBalance(ctx, receiver, app.BaseDenom))
+			require.True(t, tacApp.BankKeeper.GetBalance(ctx, intermediate, app.BaseDenom).IsZero(), "intermediate sender should not keep funds")
+		})
+	}
+}
+
+func TestOnRecvPacketHexReceiver(t *testing.T) {
+	tacApp, ctx, middleware := setup(t)
+
+	packet := newPacket("transfer/channel-7/"+app.BaseDenom, acceptContract.Hex(), callMemo(acceptContract, 100_000))
+	ack := middleware.OnRecvPacket(ctx, packet, sdk.AccAddress{})
+	require.True(t, ack.Success(), string(ack.Acknowledgement()))
+
+	balance := tacApp.BankKeeper.GetBalance(ctx, sdk.AccAddress(acceptContract.Bytes()), app.BaseDenom)
+	require.Equal(t, sdkmath.NewInt(1000), balance.Amount)
+}
+
+func TestOnRecvPacketEmitsTypedEvent(t *testing.T) {
+	_, ctx, middleware := setup(t)
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+
+	packet := newPacket("transfer/channel-7/"+app.BaseDenom, acceptContract.Hex(), callMemo(acceptContract, 100_000))
+	ack := middleware.OnRecvPacket(ctx, packet, sdk.AccAddress{})
+	require.True(t, ack.Success(), string(ack.Acknowledgement()))
+
+	var calls []*types.EventEVMCall
+	for _, event := range ctx.EventManager().ABCIEvents() {
+		if event.Type != proto.MessageName(&types.EventEVMCall{}) {
+			continue
+		}
+		msg, err := sdk.ParseTypedEvent(event)
+		require.NoError(t, err)
+		calls = append(calls, msg.(*types.EventEVMCall))
+	}
+
+	require.Len(t, calls, 1)
+	require.Equal(t, acceptContract.Hex(), calls[0].Contract)
+	require.Equal(t, common.BytesToAddress(types.IntermediateSender("channel-0", "tac1sender")).Hex(), calls[0].Sender)
+	require.Equal(t, "1000", calls[0].Amount)
+	require.Positive(t, calls[0].GasUsed)
+}
+
+// setupVouchers allows channel-0 and channel-2 to set voucher metadata, and
+// sets the metadata of the native coin as the genesis does
+func setupVouchers(t *testing.T) (*app.TacChainApp, sdk.Context, ibchooks.IBCMiddleware) {
+	t.Helper()
+
+	tacApp, ctx, middleware := setup(t)
+	params := tacApp.IBCHooksKeeper.GetParams(ctx)
+	params.MetadataChannels = []string{"channel-0", "channel-2"}
+	tacApp.IBCHooksKeeper.SetParams(ctx, params)
+
+	tacApp.BankKeeper.SetDenomMetaData(ctx, banktypes.Metadata{
+		Description: "The native staking token of the TAC chain",
+		DenomUnits: []*banktypes.DenomUnit{
+			{Denom: app.BaseDenom, Exponent: 0},
+			{Denom: "tac", Exponent: 18},
+		},
+		Base:    app.BaseDenom,
+		Display: "tac",
+		Name:    "TAC",
+		Symbol:  "TAC",
+	})
+	return tacApp, ctx, middleware
+}
+
+func TestOnRecvPacketVoucherMetadata(t *testing.T) {
+	atomMemo := metadataMemo("Cosmos Hub Atom", "ATOM", "atom", 6)
+
+	testCases := []struct {
+		name        string
+		denom       string
+		destChannel string
+		memo        string
+		expectAck   bool
+	}{
+		{"metadata of the asset of the counterparty", "uatom", "channel-0", atomMemo, true},
+		{"metadata over a channel not allowed", "uatom", "channel-1", atomMemo, false},
+		{"metadata of the asset of a third chain", "transfer/channel-9/uatom", "channel-0", atomMemo, false},
+		{"metadata of the native coin returning home", "transfer/channel-7/" + app.BaseDenom, "channel-0", metadataMemo("TAC", "TAC", "tac", 18), false},
+		{"symbol of the native coin", "atac", "channel-0", metadataMemo("TAC", "TAC", "tac", 18), false},
+		{"symbol of another asset", "uatom", "channel-0", metadataMemo("USD Coin", "USDC", "atom", 6), false},
+		{"display unit of another asset", "uatom", "channel-0", metadataMemo("USD Coin", "USDC", "usdc", 6), false},
+		{"decimals other than those of the base denom", "uatom", "channel-0", metadataMemo("Cosmos Hub Atom", "ATOM", "atom", 18), false},
+		{"malformed metadata", "uatom", "channel-0", `{"erc20":{"name":"Cosmos Hub Atom","symbol":"ATOM","decimals":"6"}}`, false},
+		{
+			"metadata along with a call", "uatom", "channel-0",
+			`{"erc20":{"name":"Cosmos Hub Atom","symbol":"ATOM","display":"atom","decimals":6},"evm":{"contract":"` + acceptContract.Hex() + `","gas_limit":100000}}`,
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tacApp, ctx, middleware := setupVouchers(t)
+			receiver := sdk.AccAddress(common.HexToAddress("0x3000000000000000000000000000000000000001").Bytes())
+			voucher := voucherDenom(tc.destChannel, tc.denom)
+
+			cacheCtx, write := ctx.CacheContext()
+			ack := middleware.OnRecvPacket(cacheCtx, newVoucherPacket(tc.denom, receiver.String(), tc.memo, tc.destChannel), sdk.AccAddress{})
+			require.Equal(t, tc.expectAck, ack.Success(), string(ack.Acknowledgement()))
+			if ack.Success() {
+				write()
+			}
+
+			metadata, found := tacApp.BankKeeper.GetDenomMetaData(ctx, voucher)
+			require.Equal(t, tc.expectAck, found)
+			require.Equal(t, tc.expectAck, tacApp.Erc20Keeper.IsDenomRegistered(ctx, voucher))
+			if !tc.expectAck {
+				require.True(t, tacApp.BankKeeper.GetBalance(ctx, receiver, voucher).IsZero(), "failed transfer must be refunded")
+				return
+			}
+			require.Equal(t, sdkmath.NewInt(1000), tacApp.BankKeeper.GetBalance(ctx, receiver, voucher).Amount)
+			require.Equal(t, "ATOM", metadata.Symbol)
+			require.Equal(t, "atom", metadata.Display)
+			require.Equal(t, []*banktypes.DenomUnit{{Denom: voucher, Exponent: 0}, {Denom: "atom", Exponent: 6}}, metadata.DenomUnits)
+		})
+	}
+}
+
+func TestOnRecvPacketVoucherMetadataSetOnce(t *testing.T) {
+	tacApp, ctx, middleware := setupVouchers(t)
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	receiver := sdk.AccAddress(common.HexToAddress("0x3000000000000000000000000000000000000001").Bytes())
+	voucher := voucherDenom("channel-0", "uatom")
+
+	recv := func(memo, destChannel string) bool {
+		cacheCtx, write := ctx.CacheContext()
+		ack := middleware.OnRecvPacket(cacheCtx, newVoucherPacket("uatom", receiver.String(), memo, destChannel), sdk.AccAddress{})
+		if ack.Success() {
+			write()
+		}
+		return ack.Success()
+	}
+
+	require.True(t, recv(metadataMemo("Cosmos Hub Atom", "ATOM", "atom", 6), "channel-0"))
+	var events []proto.Message
+	for _, event := range ctx.EventManager().ABCIEvents() {
+		if event.Type != proto.MessageName(&types.EventSetVoucherMetadata{}) && event.Type != proto.MessageName(&types.EventRegisterVoucher{}) {
+			continue
+		}
+		msg, err := sdk.ParseTypedEvent(event)
+		require.NoError(t, err)
+		events = append(events, msg)
+	}
+	require.Len(t, events, 2)
+	require.Equal(t, &types.EventSetVoucherMetadata{Denom: voucher, BaseDenom: "uatom", Channel: "channel-0", Symbol: "ATOM", Decimals: 6}, events[0])
+	registered := events[1].(*types.EventRegisterVoucher)
+	require.Equal(t, voucher, registered.Denom)
+	require.True(t, common.IsHexAddress(registered.Erc20Address))
+	require.Empty(t, registered.Registrar)
+
+	// the same metadata again is a plain transfer
+	require.True(t, recv(metadataMemo("Cosmos Hub Atom", "ATOM", "atom", 6), "channel-0"))
+	// later transfers can't replace it
+	require.False(t, recv(metadataMemo("Cosmos Hub Atom v2", "ATOM", "atom", 6), "channel-0"))
+	metadata, found := tacApp.BankKeeper.GetDenomMetaData(ctx, voucher)
+	require.True(t, found)
+	require.Equal(t, "Cosmos Hub Atom", metadata.Name)
+	// nor can a chain on another channel pass its asset off as the same one
+	require.False(t, recv(metadataMemo("Cosmos Hub Atom", "ATOM", "atom", 6), "channel-2"))
+	_, found = tacApp.BankKeeper.GetDenomMetaData(ctx, voucherDenom("channel-2", "uatom"))
+	require.False(t, found)
+
+	require.Equal(t, sdkmath.NewInt(2000), tacApp.BankKeeper.GetBalance(ctx, receiver, voucher).Amount)
+}
+
+func TestRegisterVoucher(t *testing.T) {
+	tacApp, ctx, _ := setupVouchers(t)
+	k := tacApp.IBCHooksKeeper
+	registrar := common.HexToAddress("0x3000000000000000000000000000000000000002")
+	voucher := voucherDenom("channel-5", "uosmo")
+
+	call := func(denom string) error {
+		data, err := types.VoucherRegistryCall{Method: types.MethodRegisterVoucher, Denom: denom}.Pack()
+		require.NoError(t, err)
+		return k.HandleVoucherRegistryCall(ctx, registrar, data)
+	}
+
+	// a voucher received over a channel not allowed to set its metadata
+	require.ErrorIs(t, call(voucher), types.ErrVoucherNotRegistrable)
+	require.False(t, tacApp.Erc20Keeper.IsDenomRegistered(ctx, voucher))
+
+	// anyone registers it once its metadata is set, e.g. by governance
+	metadata := types.VoucherMetadata{Name: "Osmosis", Symbol: "OSMO", Display: "osmo", Decimals: 6}.BankMetadata(voucher, "uosmo", "channel-5")
+	tacApp.BankKeeper.SetDenomMetaData(ctx, metadata)
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	require.NoError(t, call(voucher))
+	require.True(t, tacApp.Erc20Keeper.IsDenomRegistered(ctx, voucher))
+	events := ctx.EventManager().ABCIEvents()
+	require.NotEmpty(t, events)
+	msg, err := sdk.ParseTypedEvent(events[len(events)-1])
+	require.NoError(t, err)
+	require.Equal(t, registrar.Hex(), msg.(*types.EventRegisterVoucher).Registrar)
+
+	require.ErrorIs(t, call(voucher), types.ErrVoucherNotRegistrable, "already registered")
+	require.ErrorIs(t, call(app.BaseDenom), types.ErrVoucherNotRegistrable, "not a voucher")
+	require.ErrorIs(t, k.HandleVoucherRegistryCall(ctx, registrar, []byte{0x01}), types.ErrInvalidVoucherRegistryCall)
+}
@@ -0,0 +1,126 @@
+package keeper
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+
+	errorsmod "cosmossdk.io/errors"
+	"cosmossdk.io/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	evmtypes "github.com/cosmos/evm/x/vm/types"
+
+	"github.com/Asphere-xyz/tacchain/x/ibchooks/types"
+)
+
+// Keeper of the ibchooks module
+type Keeper struct {
+	paramSpace  paramtypes.Subspace
+	evmKeeper   types.EVMKeeper
+	bankKeeper  types.BankKeeper
+	erc20Keeper types.Erc20Keeper
+	evmDenom    string
+}
+
+// NewKeeper creates a new ibchooks Keeper instance. Received funds in evmDenom
+// are forwarded as value of the hook call.
+func NewKeeper(
+	paramSpace paramtypes.Subspace,
+	evmKeeper types.EVMKeeper,
+	bankKeeper types.BankKeeper,
+	erc20Keeper types.Erc20Keeper,
+	evmDenom string,
+) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		paramSpace:  paramSpace,
+		evmKeeper:   evmKeeper,
+		bankKeeper:  bankKeeper,
+		erc20Keeper: erc20Keeper,
+		evmDenom:    evmDenom,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// EVMDenom returns the denom forwarded as value of hook calls
+func (k Keeper) EVMDenom() string {
+	return k.evmDenom
+}
+
+// GetParams returns the current ibchooks module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the ibchooks module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// ValidateCall checks the hook call against the governance whitelist and gas cap
+func (k Keeper) ValidateCall(ctx sdk.Context, memo types.EVMMemo) error {
+	params := k.GetParams(ctx)
+
+	if !params.IsAllowedContract(memo.ContractAddress()) {
+		return errorsmod.Wrap(types.ErrContractNotAllowed, memo.ContractAddress().Hex())
+	}
+	if memo.GasLimit > params.MaxGasLimit {
+		return errorsmod.Wrapf(types.ErrGasLimitExceeded, "%d > %d", memo.GasLimit, params.MaxGasLimit)
+	}
+	return nil
+}
+
+// ExecuteCall calls the contract from sender, attaching value. The gas used by
+// the EVM is charged to the packet relay transaction.
+func (k Keeper) ExecuteCall(ctx sdk.Context, sender common.Address, memo types.EVMMemo, value *big.Int) ([]byte, error) {
+	if err := k.ValidateCall(ctx, memo); err != nil {
+		return nil, err
+	}
+
+	contract := memo.ContractAddress()
+	msg := core.Message{
+		From:              sender,
+		To:                &contract,
+		Value:             value,
+		GasLimit:          memo.GasLimit,
+		GasPrice:          big.NewInt(0),
+		GasFeeCap:         big.NewInt(0),
+		GasTipCap:         big.NewInt(0),
+		Data:              memo.InputBytes(),
+		SkipAccountChecks: true,
+	}
+
+	res, err := k.evmKeeper.ApplyMessage(ctx, msg, evmtypes.NewNoOpTracer(), true)
+	if err != nil {
+		return nil, errorsmod.Wrap(types.ErrEVMCallFailed, err.Error())
+	}
+
+	ctx.GasMeter().ConsumeGas(res.GasUsed, "ibc hooks evm call")
+
+	if res.Failed() {
+		return nil, errorsmod.Wrapf(types.ErrEVMCallFailed, "%s: %s", contract.Hex(), res.VmError)
+	}
+
+	if err := ctx.EventManager().EmitTypedEvent(&types.EventEVMCall{
+		Contract: contract.Hex(),
+		Sender:   sender.Hex(),
+		Amount:   value.String(),
+		GasUsed:  res.GasUsed,
+	}); err != nil {
+		return nil, err
+	}
+
+	return res.Ret, nil
+}
@@ -0,0 +1,123 @@
+package keeper
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	transfertypes "github.com/cosmos/ibc-go/v8/modules/apps/transfer/types"
+
+	"github.com/Asphere-xyz/tacchain/x/ibchooks/types"
+)
+
+// VoucherMetadata returns the bank metadata m sets for the voucher of denom,
+// the denom of a packet received over destPort/destChannel. Only the assets
+// native to the counterparty get their metadata from it, once, and only over
+// the channels whitelisted by governance. The metadata can't reuse the symbol
+// or the display unit of another denom of the chain.
+func (k Keeper) VoucherMetadata(ctx sdk.Context, destPort, destChannel, denom string, m types.VoucherMetadata) (banktypes.Metadata, error) {
+	if !k.GetParams(ctx).IsMetadataChannel(destChannel) {
+		return banktypes.Metadata{}, errorsmod.Wrap(types.ErrMetadataNotAllowed, destChannel)
+	}
+	// the counterparty only vouches for the assets it issues, not for those
+	// it received itself or those returning home
+	if trace := transfertypes.ParseDenomTrace(denom); trace.Path != "" {
+		return banktypes.Metadata{}, errorsmod.Wrapf(types.ErrInvalidVoucherMetadata, "denom %s is not native to the sender chain", denom)
+	}
+	if err := m.Validate(denom); err != nil {
+		return banktypes.Metadata{}, err
+	}
+
+	voucher := transfertypes.ParseDenomTrace(transfertypes.GetPrefixedDenom(destPort, destChannel, denom)).IBCDenom()
+	metadata := m.BankMetadata(voucher, denom, destChannel)
+	if err := metadata.Validate(); err != nil {
+		return banktypes.Metadata{}, errorsmod.Wrap(types.ErrInvalidVoucherMetadata, err.Error())
+	}
+
+	if existing, found := k.bankKeeper.GetDenomMetaData(ctx, voucher); found {
+		if existing.String() != metadata.String() {
+			return banktypes.Metadata{}, errorsmod.Wrapf(types.ErrInvalidVoucherMetadata, "metadata of %s is already set to %s (%s)", voucher, existing.Symbol, existing.Name)
+		}
+		return metadata, nil
+	}
+
+	var err error
+	k.bankKeeper.IterateAllDenomMetaData(ctx, func(other banktypes.Metadata) bool {
+		if strings.EqualFold(other.Symbol, metadata.Symbol) || strings.EqualFold(other.Display, metadata.Display) {
+			err = errorsmod.Wrapf(types.ErrInvalidVoucherMetadata, "symbol %s is already used by %s", metadata.Symbol, other.Base)
+		}
+		return err != nil
+	})
+	return metadata, err
+}
+
+// SetVoucherMetadata sets the bank metadata returned by VoucherMetadata for
+// the voucher of baseDenom received over channel, unless it's already set,
+// and registers the ERC-20 extension of the voucher when the erc20 module is
+// enabled.
+func (k Keeper) SetVoucherMetadata(ctx sdk.Context, baseDenom, channel string, metadata banktypes.Metadata) error {
+	if _, found := k.bankKeeper.GetDenomMetaData(ctx, metadata.Base); !found {
+		k.bankKeeper.SetDenomMetaData(ctx, metadata)
+		if err := ctx.EventManager().EmitTypedEvent(&types.EventSetVoucherMetadata{
+			Denom:     metadata.Base,
+			BaseDenom: baseDenom,
+			Channel:   channel,
+			Symbol:    metadata.Symbol,
+			Decimals:  metadata.DenomUnits[len(metadata.DenomUnits)-1].Exponent,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if !k.erc20Keeper.IsERC20Enabled(ctx) || k.erc20Keeper.IsDenomRegistered(ctx, metadata.Base) {
+		return nil
+	}
+	_, err := k.RegisterVoucher(ctx, metadata.Base, common.Address{})
+	return err
+}
+
+// RegisterVoucher registers the ERC-20 extension of the voucher denom on
+// behalf of registrar and returns its address. Anyone can register a voucher
+// once its bank metadata is set, the extension takes its name, symbol and
+// decimals from it.
+func (k Keeper) RegisterVoucher(ctx sdk.Context, denom string, registrar common.Address) (common.Address, error) {
+	if !strings.HasPrefix(denom, transfertypes.DenomPrefix+"/") {
+		return common.Address{}, errorsmod.Wrapf(types.ErrVoucherNotRegistrable, "%s is not an ibc voucher", denom)
+	}
+	if !k.erc20Keeper.IsERC20Enabled(ctx) {
+		return common.Address{}, errorsmod.Wrap(types.ErrVoucherNotRegistrable, "erc20 module is disabled")
+	}
+	if k.erc20Keeper.IsDenomRegistered(ctx, denom) {
+		return common.Address{}, errorsmod.Wrapf(types.ErrVoucherNotRegistrable, "%s is already registered", denom)
+	}
+	if _, found := k.bankKeeper.GetDenomMetaData(ctx, denom); !found {
+		return common.Address{}, errorsmod.Wrapf(types.ErrVoucherNotRegistrable, "%s has no metadata", denom)
+	}
+
+	pair, err := k.erc20Keeper.RegisterERC20Extension(ctx, denom)
+	if err != nil {
+		return common.Address{}, errorsmod.Wrap(types.ErrVoucherNotRegistrable, err.Error())
+	}
+
+	event := &types.EventRegisterVoucher{Denom: denom, Erc20Address: pair.Erc20Address}
+	if registrar != (common.Address{}) {
+		event.Registrar = registrar.Hex()
+	}
+	return common.HexToAddress(pair.Erc20Address), ctx.EventManager().EmitTypedEvent(event)
+}
+
+// HandleVoucherRegistryCall executes a call sent by sender to the voucher
+// registry address
+func (k Keeper) HandleVoucherRegistryCall(ctx sdk.Context, sender common.Address, data []byte) error {
+	call, err := types.ParseVoucherRegistryCall(data)
+	if err != nil {
+		return err
+	}
+	_, err = k.RegisterVoucher(ctx, call.Denom, sender)
+	return err
+}
@@ -0,0 +1,19 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+)
+
+// x/ibchooks module sentinel errors
+var (
+	ErrInvalidMemo        = errorsmod.Register(ModuleName, 2, "invalid ibc hooks memo")
+	ErrContractNotAllowed = errorsmod.Register(ModuleName, 3, "contract is not allowed to be called by ibc hooks")
+	ErrGasLimitExceeded   = errorsmod.Register(ModuleName, 4, "gas limit exceeds the maximum allowed")
+	ErrInvalidPacket      = errorsmod.Register(ModuleName, 5, "invalid packet for ibc hooks")
+	ErrEVMCallFailed      = errorsmod.Register(ModuleName, 6, "evm call failed")
+
+	ErrInvalidVoucherMetadata     = errorsmod.Register(ModuleName, 7, "invalid voucher metadata")
+	ErrMetadataNotAllowed         = errorsmod.Register(ModuleName, 8, "voucher metadata is not allowed from this channel")
+	ErrVoucherNotRegistrable      = errorsmod.Register(ModuleName, 9, "voucher cannot be registered as an erc20")
+	ErrInvalidVoucherRegistryCall = errorsmod.Register(ModuleName, 10, "invalid voucher registry call")
+)
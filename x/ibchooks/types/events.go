@@ -0,0 +1,52 @@
+package types
+
+import (
+	"github.com/cosmos/gogoproto/proto"
+)
+
+// init registers the events of the EVM calls run by the memos of incoming
+// transfers and of the voucher registry.
+func init() {
+	proto.RegisterType((*EventEVMCall)(nil), "tacchain.ibchooks.v1.EventEVMCall")
+	proto.RegisterType((*EventSetVoucherMetadata)(nil), "tacchain.ibchooks.v1.EventSetVoucherMetadata")
+	proto.RegisterType((*EventRegisterVoucher)(nil), "tacchain.ibchooks.v1.EventRegisterVoucher")
+}
+
+// EventEVMCall is emitted when the memo of an ICS-20 packet called a
+// whitelisted contract.
+type EventEVMCall struct {
+	Contract string `protobuf:"bytes,1,opt,name=contract,proto3" json:"contract,omitempty"`
+	Sender   string `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	Amount   string `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	GasUsed  uint64 `protobuf:"varint,4,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+}
+
+func (m *EventEVMCall) Reset()         { *m = EventEVMCall{} }
+func (m *EventEVMCall) String() string { return proto.CompactTextString(m) }
+func (*EventEVMCall) ProtoMessage()    {}
+
+// EventSetVoucherMetadata is emitted when the memo of an ICS-20 packet set the
+// bank metadata of the voucher of the transferred asset.
+type EventSetVoucherMetadata struct {
+	Denom     string `protobuf:"bytes,1,opt,name=denom,proto3" json:"denom,omitempty"`
+	BaseDenom string `protobuf:"bytes,2,opt,name=base_denom,json=baseDenom,proto3" json:"base_denom,omitempty"`
+	Channel   string `protobuf:"bytes,3,opt,name=channel,proto3" json:"channel,omitempty"`
+	Symbol    string `protobuf:"bytes,4,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Decimals  uint32 `protobuf:"varint,5,opt,name=decimals,proto3" json:"decimals,omitempty"`
+}
+
+func (m *EventSetVoucherMetadata) Reset()         { *m = EventSetVoucherMetadata{} }
+func (m *EventSetVoucherMetadata) String() string { return proto.CompactTextString(m) }
+func (*EventSetVoucherMetadata) ProtoMessage()    {}
+
+// EventRegisterVoucher is emitted when the ERC-20 extension of a voucher was
+// registered.
+type EventRegisterVoucher struct {
+	Denom        string `protobuf:"bytes,1,opt,name=denom,proto3" json:"denom,omitempty"`
+	Erc20Address string `protobuf:"bytes,2,opt,name=erc20_address,json=erc20Address,proto3" json:"erc20_address,omitempty"`
+	Registrar    string `protobuf:"bytes,3,opt,name=registrar,proto3" json:"registrar,omitempty"`
+}
+
+func (m *EventRegisterVoucher) Reset()         { *m = EventRegisterVoucher{} }
+func (m *EventRegisterVoucher) String() string { return proto.CompactTextString(m) }
+func (*EventRegisterVoucher) ProtoMessage()    {}
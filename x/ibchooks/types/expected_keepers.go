@@ -0,0 +1,34 @@
+package types
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	erc20types "github.com/cosmos/evm/x/erc20/types"
+	evmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+// EVMKeeper defines the expected EVM keeper used to execute hook calls
+type EVMKeeper interface {
+	ApplyMessage(ctx sdk.Context, msg core.Message, tracer vm.EVMLogger, commit bool) (*evmtypes.MsgEthereumTxResponse, error)
+}
+
+// BankKeeper defines the expected bank keeper holding the metadata of vouchers
+type BankKeeper interface {
+	GetDenomMetaData(ctx context.Context, denom string) (banktypes.Metadata, bool)
+	SetDenomMetaData(ctx context.Context, denomMetaData banktypes.Metadata)
+	IterateAllDenomMetaData(ctx context.Context, cb func(banktypes.Metadata) bool)
+}
+
+// Erc20Keeper defines the expected erc20 keeper registering the ERC-20
+// extensions of vouchers
+type Erc20Keeper interface {
+	IsERC20Enabled(ctx sdk.Context) bool
+	IsDenomRegistered(ctx sdk.Context, denom string) bool
+	RegisterERC20Extension(ctx sdk.Context, denom string) (*erc20types.TokenPair, error)
+}
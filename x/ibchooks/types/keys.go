@@ -0,0 +1,20 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/cosmos/cosmos-sdk/types/address"
+)
+
+const (
+	// ModuleName defines the ibc hooks module name
+	ModuleName = "ibchooks"
+)
+
+// VoucherRegistryAddress returns the address accounts send their voucher
+// registration calls to. It is derived like a module account address, no
+// account lives there and no code runs at it, the calls are handled after the
+// transaction executed.
+func VoucherRegistryAddress() common.Address {
+	return common.BytesToAddress(address.Module(ModuleName, []byte("voucher-registry"))[:common.AddressLength])
+}
@@ -0,0 +1,102 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/address"
+)
+
+// MemoKey is the top level key of an ICS-20 memo that triggers a hook call
+const MemoKey = "evm"
+
+// EVMMemo is the hook call carried in the ICS-20 memo, e.g.
+//
+//	{"evm": {"contract": "0x...", "input": "0x...", "gas_limit": 300000}}
+//
+// The received funds are sent as value of the call.
+type EVMMemo struct {
+	// Contract is the hex address of the contract to call
+	Contract string `json:"contract"`
+	// Input is the hex encoded calldata
+	Input string `json:"input,omitempty"`
+	// GasLimit caps the gas available to the call
+	GasLimit uint64 `json:"gas_limit"`
+}
+
+// ParseMemo extracts the hook call from an ICS-20 memo. It returns false if
+// the memo doesn't request one, so the packet is handled as a plain transfer.
+func ParseMemo(memo string) (EVMMemo, bool, error) {
+	raw, ok := memoValue(memo, MemoKey)
+	if !ok {
+		return EVMMemo{}, false, nil
+	}
+
+	var m EVMMemo
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&m); err != nil {
+		return EVMMemo{}, true, errorsmod.Wrapf(ErrInvalidMemo, "failed to parse %s memo: %s", MemoKey, err)
+	}
+
+	return m, true, m.Validate()
+}
+
+// memoValue returns the value of the top level key of a JSON memo, false if
+// the memo isn't a JSON object or has no such key.
+func memoValue(memo, key string) (json.RawMessage, bool) {
+	memo = strings.TrimSpace(memo)
+	if !strings.HasPrefix(memo, "{") {
+		return nil, false
+	}
+
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(memo), &root); err != nil {
+		return nil, false
+	}
+
+	raw, ok := root[key]
+	return raw, ok
+}
+
+// Validate performs stateless validation of the hook call
+func (m EVMMemo) Validate() error {
+	if !common.IsHexAddress(m.Contract) {
+		return errorsmod.Wrapf(ErrInvalidMemo, "invalid contract address: %s", m.Contract)
+	}
+	if m.Input != "" {
+		if _, err := hexutil.Decode(m.Input); err != nil {
+			return errorsmod.Wrapf(ErrInvalidMemo, "invalid input: %s", err)
+		}
+	}
+	if m.GasLimit == 0 {
+		return errorsmod.Wrap(ErrInvalidMemo, "gas limit cannot be zero")
+	}
+	return nil
+}
+
+// ContractAddress returns the address of the contract to call
+func (m EVMMemo) ContractAddress() common.Address {
+	return common.HexToAddress(m.Contract)
+}
+
+// InputBytes returns the decoded calldata
+func (m EVMMemo) InputBytes() []byte {
+	if m.Input == "" {
+		return nil
+	}
+	return hexutil.MustDecode(m.Input)
+}
+
+// IntermediateSender derives the account that receives the funds and executes
+// the hook call on behalf of the sender on the counterparty chain. It is
+// unique per channel and sender, so it can't be impersonated by a local key.
+func IntermediateSender(channel, originalSender string) sdk.AccAddress {
+	return sdk.AccAddress(address.Module(ModuleName, []byte(channel+"/"+originalSender))[:common.AddressLength])
+}
@@ -0,0 +1,101 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Asphere-xyz/tacchain/x/ibchooks/types"
+)
+
+const testContract = "0x1111111111111111111111111111111111111111"
+
+func TestParseMemo(t *testing.T) {
+	testCases := []struct {
+		name      string
+		memo      string
+		expectOK  bool
+		expectErr error
+	}{
+		{"empty memo", "", false, nil},
+		{"plain text memo", "hello", false, nil},
+		{"invalid json", "{", false, nil},
+		{"other middleware memo", `{"forward":{"receiver":"tac1"}}`, false, nil},
+		{
+			"valid call",
+			`{"evm":{"contract":"` + testContract + `","input":"0xd0e30db0","gas_limit":100000}}`,
+			true, nil,
+		},
+		{
+			"valid call without input",
+			`{"evm":{"contract":"` + testContract + `","gas_limit":100000}}`,
+			true, nil,
+		},
+		{
+			"invalid contract",
+			`{"evm":{"contract":"0x1234","gas_limit":100000}}`,
+			true, types.ErrInvalidMemo,
+		},
+		{
+			"invalid input",
+			`{"evm":{"contract":"` + testContract + `","input":"zz","gas_limit":100000}}`,
+			true, types.ErrInvalidMemo,
+		},
+		{
+			"zero gas limit",
+			`{"evm":{"contract":"` + testContract + `"}}`,
+			true, types.ErrInvalidMemo,
+		},
+		{
+			"unknown field",
+			`{"evm":{"contract":"` + testContract + `","gas_limit":100000,"value":"1"}}`,
+			true, types.ErrInvalidMemo,
+		},
+		{
+			"not an object",
+			`{"evm":"call"}`,
+			true, types.ErrInvalidMemo,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			memo, ok, err := types.ParseMemo(tc.memo)
+			require.Equal(t, tc.expectOK, ok)
+			if tc.expectErr != nil {
+				require.ErrorIs(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			if ok {
+				require.Equal(t, testContract, memo.ContractAddress().Hex())
+			}
+		})
+	}
+}
+
+func TestIntermediateSender(t *testing.T) {
+	sender := types.IntermediateSender("channel-0", "tac1sender")
+	require.Len(t, sender, 20)
+	require.Equal(t, sender, types.IntermediateSender("channel-0", "tac1sender"))
+	require.NotEqual(t, sender, types.IntermediateSender("channel-1", "tac1sender"))
+	require.NotEqual(t, sender, types.IntermediateSender("channel-0", "tac1other"))
+}
+
+func TestParamsValidate(t *testing.T) {
+	require.NoError(t, types.DefaultParams().Validate())
+
+	params := types.DefaultParams()
+	params.AllowedContracts = []string{testContract}
+	require.NoError(t, params.Validate())
+
+	params.AllowedContracts = []string{testContract, "0x1111111111111111111111111111111111111111"}
+	require.Error(t, params.Validate(), "duplicate contract")
+
+	params.AllowedContracts = []string{"tac1invalid"}
+	require.Error(t, params.Validate(), "invalid contract")
+
+	params = types.DefaultParams()
+	params.MaxGasLimit = 0
+	require.Error(t, params.Validate(), "zero gas limit")
+}
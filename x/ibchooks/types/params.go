@@ -0,0 +1,145 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+// DefaultMaxGasLimit is the default gas cap of a single hook call
+const DefaultMaxGasLimit uint64 = 1_000_000
+
+var (
+	// KeyAllowedContracts is the param store key for the contracts hooks may call
+	KeyAllowedContracts = []byte("AllowedContracts")
+	// KeyMaxGasLimit is the param store key for the gas cap of a hook call
+	KeyMaxGasLimit = []byte("MaxGasLimit")
+	// KeyMetadataChannels is the param store key for the channels whose
+	// transfers may set the metadata of their vouchers
+	KeyMetadataChannels = []byte("MetadataChannels")
+)
+
+// Params defines the ibchooks module parameters. Only contracts whitelisted by
+// governance can be called from an ICS-20 memo, calls to any other address are
+// rejected with an error acknowledgement so the sender gets refunded.
+// Likewise only the transfers received over MetadataChannels may set the bank
+// metadata of the vouchers of the assets native to the counterparty.
+type Params struct {
+	AllowedContracts []string `json:"allowed_contracts" yaml:"allowed_contracts"`
+	MaxGasLimit      uint64   `json:"max_gas_limit" yaml:"max_gas_limit"`
+	MetadataChannels []string `json:"metadata_channels" yaml:"metadata_channels"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the ibchooks module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default ibchooks module parameters
+func DefaultParams() Params {
+	return Params{
+		AllowedContracts: []string{},
+		MaxGasLimit:      DefaultMaxGasLimit,
+		MetadataChannels: []string{},
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyAllowedContracts, &p.AllowedContracts, validateAllowedContracts),
+		paramtypes.NewParamSetPair(KeyMaxGasLimit, &p.MaxGasLimit, validateMaxGasLimit),
+		paramtypes.NewParamSetPair(KeyMetadataChannels, &p.MetadataChannels, validateMetadataChannels),
+	}
+}
+
+// Validate performs basic validation of the ibchooks module parameters
+func (p Params) Validate() error {
+	if err := validateAllowedContracts(p.AllowedContracts); err != nil {
+		return err
+	}
+	if err := validateMaxGasLimit(p.MaxGasLimit); err != nil {
+		return err
+	}
+	return validateMetadataChannels(p.MetadataChannels)
+}
+
+// IsAllowedContract returns true if hooks may call the given contract
+func (p Params) IsAllowedContract(contract common.Address) bool {
+	for _, allowed := range p.AllowedContracts {
+		if common.HexToAddress(allowed) == contract {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMetadataChannel returns true if the transfers received over channel may
+// set the metadata of their vouchers
+func (p Params) IsMetadataChannel(channel string) bool {
+	for _, allowed := range p.MetadataChannels {
+		if allowed == channel {
+			return true
+		}
+	}
+	return false
+}
+
+func validateAllowedContracts(i interface{}) error {
+	contracts, ok := i.([]string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	seen := make(map[string]bool, len(contracts))
+	for _, contract := range contracts {
+		if !common.IsHexAddress(contract) {
+			return fmt.Errorf("invalid contract address: %s", contract)
+		}
+		key := strings.ToLower(common.HexToAddress(contract).Hex())
+		if seen[key] {
+			return fmt.Errorf("duplicate contract address: %s", contract)
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
+
+func validateMaxGasLimit(i interface{}) error {
+	gasLimit, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if gasLimit == 0 {
+		return fmt.Errorf("max gas limit cannot be zero")
+	}
+	return nil
+}
+
+func validateMetadataChannels(i interface{}) error {
+	channels, ok := i.([]string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	seen := make(map[string]bool, len(channels))
+	for _, channel := range channels {
+		if err := host.ChannelIdentifierValidator(channel); err != nil {
+			return fmt.Errorf("invalid metadata channel %s: %w", channel, err)
+		}
+		if seen[channel] {
+			return fmt.Errorf("duplicate metadata channel: %s", channel)
+		}
+		seen[channel] = true
+	}
+
+	return nil
+}
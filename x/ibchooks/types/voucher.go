@@ -0,0 +1,169 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+
+	errorsmod "cosmossdk.io/errors"
+
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+const (
+	// VoucherMetadataMemoKey is the top level key of an ICS-20 memo that sets
+	// the metadata of the voucher of the transferred asset
+	VoucherMetadataMemoKey = "erc20"
+
+	// MaxVoucherNameLength is the maximum length of the name of a voucher
+	MaxVoucherNameLength = 64
+	// MaxVoucherDecimals is the maximum decimals of the display unit of a
+	// voucher, those of the EVM
+	MaxVoucherDecimals = 18
+
+	// MethodRegisterVoucher registers the ERC-20 extension of a voucher
+	MethodRegisterVoucher = "registerVoucher"
+)
+
+// voucherSymbolRegex matches the symbols of vouchers, e.g. ATOM or stOSMO
+var voucherSymbolRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9.]{2,11}$`)
+
+// siPrefixes are the SI prefixes of base denoms with the decimals of the unit
+// they prefix, e.g. uatom is a millionth of an atom
+var siPrefixes = map[byte]uint32{'m': 3, 'u': 6, 'n': 9, 'p': 12, 'a': 18}
+
+// VoucherMetadata is the metadata of the asset sent by the counterparty chain
+// carried in the ICS-20 memo, e.g.
+//
+//	{"erc20": {"name": "Cosmos Hub Atom", "symbol": "ATOM", "display": "atom", "decimals": 6}}
+//
+// It becomes the bank metadata of the voucher of the asset, from which its
+// ERC-20 extension takes its name, symbol and decimals.
+type VoucherMetadata struct {
+	// Name is the full name of the asset
+	Name string `json:"name"`
+	// Symbol is the ticker of the asset, the display unit regardless of case
+	Symbol string `json:"symbol"`
+	// Display is the unit the asset is displayed in, derived from the base denom
+	Display string `json:"display"`
+	// Decimals is the exponent of the display unit
+	Decimals uint32 `json:"decimals"`
+}
+
+// ParseVoucherMetadataMemo extracts the voucher metadata from an ICS-20 memo.
+// It returns false if the memo doesn't carry any.
+func ParseVoucherMetadataMemo(memo string) (VoucherMetadata, bool, error) {
+	raw, ok := memoValue(memo, VoucherMetadataMemoKey)
+	if !ok {
+		return VoucherMetadata{}, false, nil
+	}
+
+	var m VoucherMetadata
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&m); err != nil {
+		return VoucherMetadata{}, true, errorsmod.Wrapf(ErrInvalidMemo, "failed to parse %s memo: %s", VoucherMetadataMemoKey, err)
+	}
+
+	return m, true, nil
+}
+
+// Validate checks the metadata is well formed and consistent with baseDenom,
+// the denom of the asset on the counterparty chain. The display unit is the
+// base denom, or the base denom without its SI prefix with the decimals of
+// the prefix, and the symbol is the display unit, so an asset can't pass
+// itself off as another one with a different unit.
+func (m VoucherMetadata) Validate(baseDenom string) error {
+	if m.Name == "" || len(m.Name) > MaxVoucherNameLength || strings.TrimSpace(m.Name) != m.Name {
+		return errorsmod.Wrapf(ErrInvalidVoucherMetadata, "name must have 1 to %d characters without surrounding spaces", MaxVoucherNameLength)
+	}
+	for _, c := range m.Name {
+		if c < ' ' || c > '~' {
+			return errorsmod.Wrapf(ErrInvalidVoucherMetadata, "name %q must be printable ascii", m.Name)
+		}
+	}
+	if !voucherSymbolRegex.MatchString(m.Symbol) {
+		return errorsmod.Wrapf(ErrInvalidVoucherMetadata, "invalid symbol %q", m.Symbol)
+	}
+	if m.Display != strings.ToLower(m.Display) || !strings.EqualFold(m.Symbol, m.Display) {
+		return errorsmod.Wrapf(ErrInvalidVoucherMetadata, "symbol %s must be the lowercase display unit %s", m.Symbol, m.Display)
+	}
+	if m.Decimals == 0 || m.Decimals > MaxVoucherDecimals {
+		return errorsmod.Wrapf(ErrInvalidVoucherMetadata, "decimals must be between 1 and %d, got %d", MaxVoucherDecimals, m.Decimals)
+	}
+
+	unit := baseDenom[strings.LastIndex(baseDenom, "/")+1:]
+	switch {
+	case unit == m.Display:
+	case len(unit) == len(m.Display)+1 && unit[1:] == m.Display:
+		if decimals, ok := siPrefixes[unit[0]]; !ok || decimals != m.Decimals {
+			return errorsmod.Wrapf(ErrInvalidVoucherMetadata, "%s doesn't have %d decimals of %s", unit, m.Decimals, m.Display)
+		}
+	default:
+		return errorsmod.Wrapf(ErrInvalidVoucherMetadata, "display unit %s is not derived from the base denom %s", m.Display, baseDenom)
+	}
+	return nil
+}
+
+// BankMetadata returns the bank metadata of voucher, the denom of the asset on
+// this chain, received over channel
+func (m VoucherMetadata) BankMetadata(voucher, baseDenom, channel string) banktypes.Metadata {
+	return banktypes.Metadata{
+		Description: fmt.Sprintf("%s received over %s", baseDenom, channel),
+		DenomUnits: []*banktypes.DenomUnit{
+			{Denom: voucher, Exponent: 0},
+			{Denom: m.Display, Exponent: m.Decimals},
+		},
+		Base:    voucher,
+		Display: m.Display,
+		Name:    m.Name,
+		Symbol:  m.Symbol,
+	}
+}
+
+// voucherRegistryABI is the interface of the calls sent to VoucherRegistryAddress
+const voucherRegistryABI = `[
+	{"type":"function","name":"registerVoucher","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"denom","type":"string"}
+	]}
+]`
+
+// VoucherRegistryABI is the parsed interface of the voucher registry calls
+var VoucherRegistryABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(voucherRegistryABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// VoucherRegistryCall is a decoded call to the voucher registry address
+type VoucherRegistryCall struct {
+	Method string
+	Denom  string
+}
+
+// ParseVoucherRegistryCall decodes the calldata of a voucher registry call
+func ParseVoucherRegistryCall(data []byte) (VoucherRegistryCall, error) {
+	if len(data) < 4 {
+		return VoucherRegistryCall{}, errorsmod.Wrap(ErrInvalidVoucherRegistryCall, "missing method selector")
+	}
+	method, err := VoucherRegistryABI.MethodById(data[:4])
+	if err != nil {
+		return VoucherRegistryCall{}, errorsmod.Wrap(ErrInvalidVoucherRegistryCall, err.Error())
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return VoucherRegistryCall{}, errorsmod.Wrapf(ErrInvalidVoucherRegistryCall, "failed to decode %s arguments: %s", method.Name, err)
+	}
+
+	return VoucherRegistryCall{Method: method.Name, Denom: args[0].(string)}, nil
+}
+
+// Pack encodes the call as calldata for the voucher registry address
+func (c VoucherRegistryCall) Pack() ([]byte, error) {
+	return VoucherRegistryABI.Pack(MethodRegisterVoucher, c.Denom)
+}
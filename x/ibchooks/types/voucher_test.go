@@ -0,0 +1,119 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Asphere-xyz/tacchain/x/ibchooks/types"
+)
+
+func TestParseVoucherMetadataMemo(t *testing.T) {
+	metadata, ok, err := types.ParseVoucherMetadataMemo(`{"erc20":{"name":"Cosmos Hub Atom","symbol":"ATOM","display":"atom","decimals":6}}`)
+	require.True(t, ok)
+	require.NoError(t, err)
+	require.Equal(t, types.VoucherMetadata{Name: "Cosmos Hub Atom", Symbol: "ATOM", Display: "atom", Decimals: 6}, metadata)
+
+	for _, memo := range []string{"", "hello", "{", `{"evm":{"contract":"` + testContract + `","gas_limit":100000}}`} {
+		_, ok, err = types.ParseVoucherMetadataMemo(memo)
+		require.False(t, ok, memo)
+		require.NoError(t, err, memo)
+	}
+
+	for _, memo := range []string{
+		`{"erc20":"ATOM"}`,
+		`{"erc20":{"name":"Cosmos Hub Atom","symbol":"ATOM","display":"atom","decimals":6,"logo":"https://"}}`,
+		`{"erc20":{"name":"Cosmos Hub Atom","symbol":"ATOM","display":"atom","decimals":-6}}`,
+	} {
+		_, ok, err = types.ParseVoucherMetadataMemo(memo)
+		require.True(t, ok, memo)
+		require.ErrorIs(t, err, types.ErrInvalidMemo, memo)
+	}
+}
+
+func TestVoucherMetadataValidate(t *testing.T) {
+	atom := types.VoucherMetadata{Name: "Cosmos Hub Atom", Symbol: "ATOM", Display: "atom", Decimals: 6}
+
+	testCases := []struct {
+		name      string
+		metadata  func(m *types.VoucherMetadata)
+		baseDenom string
+		expectErr bool
+	}{
+		{"display unit without the si prefix", func(*types.VoucherMetadata) {}, "uatom", false},
+		{"display unit of a path denom", func(*types.VoucherMetadata) {}, "factory/cosmos1abc/uatom", false},
+		{"symbol in mixed case", func(m *types.VoucherMetadata) { m.Symbol = "stATOM"; m.Display = "statom" }, "ustatom", false},
+		{"display unit is the base denom", func(m *types.VoucherMetadata) { m.Symbol = "INJ"; m.Display = "inj"; m.Decimals = 18 }, "inj", false},
+		{"atto prefix", func(m *types.VoucherMetadata) { m.Symbol = "EVMOS"; m.Display = "evmos"; m.Decimals = 18 }, "aevmos", false},
+		{"decimals spoofing the si prefix", func(m *types.VoucherMetadata) { m.Decimals = 18 }, "uatom", true},
+		{"unknown prefix", func(*types.VoucherMetadata) {}, "xatom", true},
+		{"display unit of another asset", func(m *types.VoucherMetadata) { m.Symbol = "USDC"; m.Display = "usdc" }, "uatom", true},
+		{"symbol of another asset", func(m *types.VoucherMetadata) { m.Symbol = "USDC" }, "uatom", true},
+		{"uppercase display unit", func(m *types.VoucherMetadata) { m.Display = "ATOM" }, "uATOM", true},
+		{"invalid symbol", func(m *types.VoucherMetadata) { m.Symbol = "A TOM"; m.Display = "a tom" }, "ua tom", true},
+		{"zero decimals", func(m *types.VoucherMetadata) { m.Decimals = 0; m.Display = "uatom"; m.Symbol = "UATOM" }, "uatom", true},
+		{"too many decimals", func(m *types.VoucherMetadata) { m.Decimals = 19; m.Display = "uatom"; m.Symbol = "UATOM" }, "uatom", true},
+		{"empty name", func(m *types.VoucherMetadata) { m.Name = "" }, "uatom", true},
+		{"padded name", func(m *types.VoucherMetadata) { m.Name = " Cosmos Hub Atom" }, "uatom", true},
+		{"non ascii name", func(m *types.VoucherMetadata) { m.Name = "Cosmos Hub Аtom" }, "uatom", true},
+		{"name too long", func(m *types.VoucherMetadata) { m.Name = string(make([]byte, types.MaxVoucherNameLength+1)) }, "uatom", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			metadata := atom
+			tc.metadata(&metadata)
+			err := metadata.Validate(tc.baseDenom)
+			if tc.expectErr {
+				require.ErrorIs(t, err, types.ErrInvalidVoucherMetadata)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestVoucherMetadataBankMetadata(t *testing.T) {
+	voucher := "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2"
+	metadata := types.VoucherMetadata{Name: "Cosmos Hub Atom", Symbol: "ATOM", Display: "atom", Decimals: 6}.
+		BankMetadata(voucher, "uatom", "channel-0")
+	require.NoError(t, metadata.Validate())
+	require.Equal(t, voucher, metadata.Base)
+	require.Equal(t, "atom", metadata.Display)
+	require.Equal(t, uint32(6), metadata.DenomUnits[1].Exponent)
+}
+
+func TestVoucherRegistryCall(t *testing.T) {
+	call := types.VoucherRegistryCall{Method: types.MethodRegisterVoucher, Denom: "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2"}
+	data, err := call.Pack()
+	require.NoError(t, err)
+	decoded, err := types.ParseVoucherRegistryCall(data)
+	require.NoError(t, err)
+	require.Equal(t, call, decoded)
+
+	_, err = types.ParseVoucherRegistryCall(data[:3])
+	require.ErrorIs(t, err, types.ErrInvalidVoucherRegistryCall)
+	_, err = types.ParseVoucherRegistryCall(append([]byte{0xde, 0xad, 0xbe, 0xef}, data[4:]...))
+	require.ErrorIs(t, err, types.ErrInvalidVoucherRegistryCall)
+}
+
+func TestParamsMetadataChannels(t *testing.T) {
+	params := types.DefaultParams()
+	require.False(t, params.IsMetadataChannel("channel-0"))
+
+	params.MetadataChannels = []string{"channel-0", "channel-12"}
+	require.NoError(t, params.Validate())
+	require.True(t, params.IsMetadataChannel("channel-12"))
+	require.False(t, params.IsMetadataChannel("channel-1"))
+
+	params.MetadataChannels = []string{"channel-0", "channel-0"}
+	require.Error(t, params.Validate())
+	params.MetadataChannels = []string{"channel 0"}
+	require.Error(t, params.Validate())
+}
+
+func TestVoucherRegistryAddress(t *testing.T) {
+	// the address is documented, it must not change
+	require.Equal(t, common.HexToAddress("0x0e92e0894d17e9ad61850383f2fd920ce56e167f"), types.VoucherRegistryAddress())
+}
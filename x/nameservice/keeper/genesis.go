@@ -0,0 +1,32 @@
+package keeper
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/nameservice/types"
+)
+
+// InitGenesis initializes the nameservice module state from a genesis state
+func (k Keeper) InitGenesis(ctx sdk.Context, gs types.GenesisState) {
+	k.SetParams(ctx, gs.Params)
+
+	for _, record := range gs.Records {
+		if err := k.SetRecord(ctx, record); err != nil {
+			panic(err)
+		}
+	}
+	for _, primary := range gs.PrimaryNames {
+		k.setPrimaryName(ctx, common.HexToAddress(primary.Address), primary.Name)
+	}
+}
+
+// ExportGenesis returns the nameservice module genesis state
+func (k Keeper) ExportGenesis(ctx sdk.Context) *types.GenesisState {
+	return &types.GenesisState{
+		Params:       k.GetParams(ctx),
+		Records:      k.GetAllRecords(ctx),
+		PrimaryNames: k.GetAllPrimaryNames(ctx),
+	}
+}
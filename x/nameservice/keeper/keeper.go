@@ -0,0 +1,340 @@
+package keeper
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	corestoretypes "cosmossdk.io/core/store"
+	errorsmod "cosmossdk.io/errors"
+	"cosmossdk.io/log"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/Asphere-xyz/tacchain/x/nameservice/types"
+)
+
+// Keeper of the nameservice store
+type Keeper struct {
+	storeService corestoretypes.KVStoreService
+	paramSpace   paramtypes.Subspace
+	distrKeeper  types.DistrKeeper
+}
+
+// NewKeeper creates a new nameservice Keeper instance
+func NewKeeper(storeService corestoretypes.KVStoreService, paramSpace paramtypes.Subspace, distrKeeper types.DistrKeeper) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		storeService: storeService,
+		paramSpace:   paramSpace,
+		distrKeeper:  distrKeeper,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current nameservice module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the nameservice module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// GetRecord returns the record of name, expired or not, regardless of case
+func (k Keeper) GetRecord(ctx sdk.Context, name string) (types.NameRecord, bool) {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.RecordKey(name))
+	if err != nil {
+		panic(err)
+	}
+	if bz == nil {
+		return types.NameRecord{}, false
+	}
+
+	var record types.NameRecord
+	if err := json.Unmarshal(bz, &record); err != nil {
+		panic(err)
+	}
+	return record, true
+}
+
+// SetRecord stores a valid record, replacing the one of the same name
+func (k Keeper) SetRecord(ctx sdk.Context, record types.NameRecord) error {
+	if err := record.Validate(); err != nil {
+		return err
+	}
+	bz, err := json.Marshal(record.Normalized())
+	if err != nil {
+		return err
+	}
+	if err := k.storeService.OpenKVStore(ctx).Set(types.RecordKey(record.Name), bz); err != nil {
+		panic(err)
+	}
+	return nil
+}
+
+// GetAllRecords returns the records of every name, expired or not
+func (k Keeper) GetAllRecords(ctx sdk.Context) []types.NameRecord {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.RecordPrefix, storetypes.PrefixEndBytes(types.RecordPrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	records := []types.NameRecord{}
+	for ; iterator.Valid(); iterator.Next() {
+		var record types.NameRecord
+		if err := json.Unmarshal(iterator.Value(), &record); err != nil {
+			panic(err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// GetPrimaryName returns the primary name addr set, whether it still resolves
+// to addr or not
+func (k Keeper) GetPrimaryName(ctx sdk.Context, addr common.Address) (string, bool) {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.PrimaryNameKey(addr))
+	if err != nil {
+		panic(err)
+	}
+	if bz == nil {
+		return "", false
+	}
+	return string(bz), true
+}
+
+// setPrimaryName stores the primary name of addr, an empty name removes it
+func (k Keeper) setPrimaryName(ctx sdk.Context, addr common.Address, name string) {
+	store := k.storeService.OpenKVStore(ctx)
+	var err error
+	if name == "" {
+		err = store.Delete(types.PrimaryNameKey(addr))
+	} else {
+		err = store.Set(types.PrimaryNameKey(addr), []byte(types.NormalizeName(name)))
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetAllPrimaryNames returns the primary names of every address
+func (k Keeper) GetAllPrimaryNames(ctx sdk.Context) []types.PrimaryName {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.PrimaryNamePrefix, storetypes.PrefixEndBytes(types.PrimaryNamePrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	names := []types.PrimaryName{}
+	for ; iterator.Valid(); iterator.Next() {
+		addr := common.BytesToAddress(iterator.Key()[len(types.PrimaryNamePrefix):])
+		names = append(names, types.PrimaryName{Address: addr.Hex(), Name: string(iterator.Value())})
+	}
+	return names
+}
+
+// Resolve returns the address name resolves to, if it's registered and not
+// expired
+func (k Keeper) Resolve(ctx sdk.Context, name string) (common.Address, bool) {
+	record, found := k.GetRecord(ctx, name)
+	if !found || record.IsExpired(ctx.BlockHeight()) {
+		return common.Address{}, false
+	}
+	return record.TargetAddress(), true
+}
+
+// ReverseLookup returns the primary name of addr. The name must still resolve
+// to addr, so an expired, re-registered or redirected name isn't reported.
+func (k Keeper) ReverseLookup(ctx sdk.Context, addr common.Address) (string, bool) {
+	name, found := k.GetPrimaryName(ctx, addr)
+	if !found {
+		return "", false
+	}
+	if target, ok := k.Resolve(ctx, name); !ok || target != addr {
+		return "", false
+	}
+	return name, true
+}
+
+// HandleRegistryCall executes a call sent by sender to the registry address
+func (k Keeper) HandleRegistryCall(ctx sdk.Context, sender common.Address, data []byte) error {
+	call, err := types.ParseRegistryCall(data)
+	if err != nil {
+		return err
+	}
+
+	switch call.Method {
+	case types.MethodRegister:
+		return k.Register(ctx, sender, call.Name, call.Address)
+	case types.MethodRenew:
+		return k.Renew(ctx, sender, call.Name)
+	case types.MethodSetAddress:
+		return k.SetAddress(ctx, sender, call.Name, call.Address)
+	case types.MethodTransfer:
+		return k.Transfer(ctx, sender, call.Name, call.Address)
+	default:
+		return k.SetPrimaryName(ctx, sender, call.Name)
+	}
+}
+
+// Register registers name for sender, who becomes its owner, pointing to
+// target. The name must be free or expired, sender pays the registration fee
+// to the community pool.
+func (k Keeper) Register(ctx sdk.Context, sender common.Address, name string, target common.Address) error {
+	if err := types.ValidateName(name); err != nil {
+		return err
+	}
+	if existing, found := k.GetRecord(ctx, name); found && !existing.IsExpired(ctx.BlockHeight()) {
+		return errorsmod.Wrapf(types.ErrNameTaken, "%s is registered until block %d", existing.Name, existing.Expiry)
+	}
+
+	params := k.GetParams(ctx)
+	record := types.NameRecord{
+		Name:    name,
+		Owner:   sender.Hex(),
+		Address: target.Hex(),
+		Expiry:  ctx.BlockHeight() + int64(params.RegistrationPeriod),
+	}
+	if err := record.Validate(); err != nil {
+		return err
+	}
+	if err := k.chargeFee(ctx, sender, params.RegistrationFee); err != nil {
+		return err
+	}
+	record = record.Normalized()
+	if err := k.SetRecord(ctx, record); err != nil {
+		return err
+	}
+
+	return ctx.EventManager().EmitTypedEvent(&types.EventRegisterName{
+		Name:    record.Name,
+		Owner:   record.Owner,
+		Address: record.Address,
+		Expiry:  record.Expiry,
+		Fee:     params.RegistrationFee.String(),
+	})
+}
+
+// Renew extends the registration of name owned by sender by the registration
+// period, from its expiry or, when it already expired, from the current
+// block. Sender pays the registration fee to the community pool. An expired
+// name can be renewed by its owner as long as nobody registered it again.
+func (k Keeper) Renew(ctx sdk.Context, sender common.Address, name string) error {
+	record, err := k.ownedRecord(ctx, sender, name)
+	if err != nil {
+		return err
+	}
+
+	params := k.GetParams(ctx)
+	if err := k.chargeFee(ctx, sender, params.RegistrationFee); err != nil {
+		return err
+	}
+	record.Expiry = max(record.Expiry, ctx.BlockHeight()) + int64(params.RegistrationPeriod)
+	if err := k.SetRecord(ctx, record); err != nil {
+		return err
+	}
+
+	return ctx.EventManager().EmitTypedEvent(&types.EventRenewName{
+		Name:   record.Name,
+		Expiry: record.Expiry,
+		Fee:    params.RegistrationFee.String(),
+	})
+}
+
+// SetAddress points name owned by sender to target
+func (k Keeper) SetAddress(ctx sdk.Context, sender common.Address, name string, target common.Address) error {
+	record, err := k.activeRecord(ctx, sender, name)
+	if err != nil {
+		return err
+	}
+	record.Address = target.Hex()
+	return k.updateRecord(ctx, record)
+}
+
+// Transfer gives name owned by sender to newOwner. The name keeps resolving
+// to the same address.
+func (k Keeper) Transfer(ctx sdk.Context, sender common.Address, name string, newOwner common.Address) error {
+	record, err := k.activeRecord(ctx, sender, name)
+	if err != nil {
+		return err
+	}
+	record.Owner = newOwner.Hex()
+	return k.updateRecord(ctx, record)
+}
+
+// SetPrimaryName sets name as the primary name of sender, the name reverse
+// lookups return. The name must resolve to sender, an empty name clears the
+// primary name.
+func (k Keeper) SetPrimaryName(ctx sdk.Context, sender common.Address, name string) error {
+	if name != "" {
+		if target, ok := k.Resolve(ctx, name); !ok || target != sender {
+			return errorsmod.Wrapf(types.ErrUnauthorized, "%s doesn't resolve to %s", name, sender.Hex())
+		}
+	}
+
+	k.setPrimaryName(ctx, sender, name)
+	return ctx.EventManager().EmitTypedEvent(&types.EventSetPrimaryName{
+		Address: sender.Hex(),
+		Name:    types.NormalizeName(name),
+	})
+}
+
+// ownedRecord returns the record of name, which sender must own
+func (k Keeper) ownedRecord(ctx sdk.Context, sender common.Address, name string) (types.NameRecord, error) {
+	record, found := k.GetRecord(ctx, name)
+	if !found {
+		return types.NameRecord{}, errorsmod.Wrap(types.ErrNameNotFound, name)
+	}
+	if record.OwnerAddress() != sender {
+		return types.NameRecord{}, errorsmod.Wrapf(types.ErrUnauthorized, "%s isn't the owner of %s", sender.Hex(), record.Name)
+	}
+	return record, nil
+}
+
+// activeRecord returns the record of name, which sender must own and must
+// not be expired
+func (k Keeper) activeRecord(ctx sdk.Context, sender common.Address, name string) (types.NameRecord, error) {
+	record, err := k.ownedRecord(ctx, sender, name)
+	if err != nil {
+		return types.NameRecord{}, err
+	}
+	if record.IsExpired(ctx.BlockHeight()) {
+		return types.NameRecord{}, errorsmod.Wrapf(types.ErrNameExpired, "%s expired at block %d", record.Name, record.Expiry)
+	}
+	return record, nil
+}
+
+func (k Keeper) updateRecord(ctx sdk.Context, record types.NameRecord) error {
+	if err := k.SetRecord(ctx, record); err != nil {
+		return err
+	}
+	record = record.Normalized()
+	return ctx.EventManager().EmitTypedEvent(&types.EventUpdateName{
+		Name:    record.Name,
+		Owner:   record.Owner,
+		Address: record.Address,
+	})
+}
+
+// chargeFee pays fee from sender to the community pool
+func (k Keeper) chargeFee(ctx sdk.Context, sender common.Address, fee sdk.Coins) error {
+	if fee.IsZero() {
+		return nil
+	}
+	return k.distrKeeper.FundCommunityPool(ctx, fee, sdk.AccAddress(sender.Bytes()))
+}
@@ -0,0 +1,258 @@
+package keeper_test
+
+import (
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/nameservice/types"
+)
+
+const (
+	fee    = 5
+	period = 100
+)
+
+var (
+	owner    = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	target   = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	stranger = common.HexToAddress("0x3000000000000000000000000000000000000003")
+)
+
+type testChain struct {
+	t     *testing.T
+	app   *app.TacChainApp
+	ctx   sdk.Context
+	denom string
+}
+
+// setup returns a chain at height 10 where registrations cost fee and last
+// period blocks, and owner can pay for two of them
+func setup(t *testing.T) *testChain {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := tacApp.NewContext(false).WithChainID(app.DefaultChainID).WithBlockHeight(10)
+	denom, err := tacApp.StakingKeeper.BondDenom(ctx)
+	require.NoError(t, err)
+
+	c := &testChain{t: t, app: tacApp, ctx: ctx, denom: denom}
+	tacApp.NameServiceKeeper.SetParams(ctx, types.Params{
+		RegistrationFee:    sdk.NewCoins(sdk.NewInt64Coin(denom, fee)),
+		RegistrationPeriod: period,
+	})
+	c.fund(owner, 2*fee)
+	return c
+}
+
+func (c *testChain) fund(addr common.Address, amount int64) {
+	coins := sdk.NewCoins(sdk.NewInt64Coin(c.denom, amount))
+	require.NoError(c.t, c.app.BankKeeper.MintCoins(c.ctx, minttypes.ModuleName, coins))
+	require.NoError(c.t, c.app.BankKeeper.SendCoinsFromModuleToAccount(c.ctx, minttypes.ModuleName, addr.Bytes(), coins))
+}
+
+func (c *testChain) balance(addr common.Address) int64 {
+	return c.app.BankKeeper.GetBalance(c.ctx, addr.Bytes(), c.denom).Amount.Int64()
+}
+
+func (c *testChain) communityPool() sdkmath.Int {
+	pool, err := c.app.DistrKeeper.FeePool.Get(c.ctx)
+	require.NoError(c.t, err)
+	return pool.CommunityPool.AmountOf(c.denom).TruncateInt()
+}
+
+func (c *testChain) atHeight(height int64) {
+	c.ctx = c.ctx.WithBlockHeight(height)
+}
+
+func (c *testChain) call(sender common.Address, call types.RegistryCall) error {
+	data, err := call.Pack()
+	require.NoError(c.t, err)
+	return c.app.NameServiceKeeper.HandleRegistryCall(c.ctx, sender, data)
+}
+
+func TestRegister(t *testing.T) {
+	c := setup(t)
+	k := c.app.NameServiceKeeper
+	poolBefore := c.communityPool()
+
+	require.NoError(t, c.call(owner, types.RegistryCall{Method: types.MethodRegister, Name: "Alice", Address: target}))
+	record, found := k.GetRecord(c.ctx, "alice")
+	require.True(t, found)
+	require.Equal(t, types.NameRecord{Name: "alice", Owner: owner.Hex(), Address: target.Hex(), Expiry: 10 + period}, record)
+	resolved, ok := k.Resolve(c.ctx, "ALICE")
+	require.True(t, ok)
+	require.Equal(t, target, resolved)
+
+	// the fee went to the community pool
+	require.Equal(t, int64(fee), c.balance(owner))
+	require.Equal(t, int64(fee), c.communityPool().Sub(poolBefore).Int64())
+
+	events := c.ctx.EventManager().Events()
+	event, err := sdk.ParseTypedEvent(events[len(events)-1].ToABCIEvent())
+	require.NoError(t, err)
+	require.Equal(t, &types.EventRegisterName{Name: "alice", Owner: owner.Hex(), Address: target.Hex(), Expiry: 10 + period, Fee: "5" + c.denom}, event)
+
+	// the name is taken regardless of case, until it expires
+	c.fund(stranger, fee)
+	err = c.call(stranger, types.RegistryCall{Method: types.MethodRegister, Name: "ALICE", Address: stranger})
+	require.ErrorIs(t, err, types.ErrNameTaken)
+
+	c.atHeight(10 + period)
+	_, ok = k.Resolve(c.ctx, "alice")
+	require.False(t, ok, "the name expired")
+	require.NoError(t, c.call(stranger, types.RegistryCall{Method: types.MethodRegister, Name: "alice", Address: stranger}))
+	resolved, ok = k.Resolve(c.ctx, "alice")
+	require.True(t, ok)
+	require.Equal(t, stranger, resolved)
+	require.Zero(t, c.balance(stranger))
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	c := setup(t)
+
+	for _, tc := range []struct {
+		name   string
+		sender common.Address
+		call   types.RegistryCall
+		err    error
+	}{
+		{"invalid name", owner, types.RegistryCall{Method: types.MethodRegister, Name: "a", Address: target}, types.ErrInvalidName},
+		{"zero address", owner, types.RegistryCall{Method: types.MethodRegister, Name: "alice"}, types.ErrInvalidRecord},
+		{"fee not covered", stranger, types.RegistryCall{Method: types.MethodRegister, Name: "alice", Address: target}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := c.call(tc.sender, tc.call)
+			require.Error(t, err)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			}
+			_, found := c.app.NameServiceKeeper.GetRecord(c.ctx, tc.call.Name)
+			require.False(t, found)
+		})
+	}
+	require.Equal(t, int64(2*fee), c.balance(owner), "no fee was charged")
+
+	err := c.app.NameServiceKeeper.HandleRegistryCall(c.ctx, owner, []byte{0x01, 0x02})
+	require.ErrorIs(t, err, types.ErrInvalidRegistryCall)
+}
+
+func TestRenew(t *testing.T) {
+	c := setup(t)
+	k := c.app.NameServiceKeeper
+	require.NoError(t, k.Register(c.ctx, owner, "alice", target))
+
+	require.ErrorIs(t, k.Renew(c.ctx, stranger, "alice"), types.ErrUnauthorized)
+	require.ErrorIs(t, k.Renew(c.ctx, owner, "bob"), types.ErrNameNotFound)
+
+	// a renewal extends the registration from its expiry
+	c.atHeight(50)
+	require.NoError(t, k.Renew(c.ctx, owner, "alice"))
+	record, _ := k.GetRecord(c.ctx, "alice")
+	require.Equal(t, int64(10+2*period), record.Expiry)
+	require.Zero(t, c.balance(owner))
+
+	// and once expired, from the renewal
+	c.fund(owner, fee)
+	c.atHeight(500)
+	require.NoError(t, k.Renew(c.ctx, owner, "alice"))
+	record, _ = k.GetRecord(c.ctx, "alice")
+	require.Equal(t, int64(500+period), record.Expiry)
+	_, ok := k.Resolve(c.ctx, "alice")
+	require.True(t, ok)
+}
+
+func TestSetAddressAndTransfer(t *testing.T) {
+	c := setup(t)
+	k := c.app.NameServiceKeeper
+	require.NoError(t, k.Register(c.ctx, owner, "alice", target))
+
+	require.ErrorIs(t, c.call(stranger, types.RegistryCall{Method: types.MethodSetAddress, Name: "alice", Address: stranger}), types.ErrUnauthorized)
+	require.NoError(t, c.call(owner, types.RegistryCall{Method: types.MethodSetAddress, Name: "alice", Address: stranger}))
+	resolved, _ := k.Resolve(c.ctx, "alice")
+	require.Equal(t, stranger, resolved)
+
+	require.ErrorIs(t, c.call(stranger, types.RegistryCall{Method: types.MethodTransfer, Name: "alice", Address: stranger}), types.ErrUnauthorized)
+	require.NoError(t, c.call(owner, types.RegistryCall{Method: types.MethodTransfer, Name: "alice", Address: stranger}))
+	record, _ := k.GetRecord(c.ctx, "alice")
+	require.Equal(t, stranger.Hex(), record.Owner)
+	require.Equal(t, stranger.Hex(), record.Address)
+
+	// the previous owner lost control of the name
+	require.ErrorIs(t, k.SetAddress(c.ctx, owner, "alice", owner), types.ErrUnauthorized)
+	require.NoError(t, k.SetAddress(c.ctx, stranger, "alice", target))
+
+	// an expired name can't be updated
+	c.atHeight(10 + period)
+	require.ErrorIs(t, k.SetAddress(c.ctx, stranger, "alice", stranger), types.ErrNameExpired)
+	require.ErrorIs(t, k.Transfer(c.ctx, stranger, "alice", owner), types.ErrNameExpired)
+}
+
+func TestPrimaryName(t *testing.T) {
+	c := setup(t)
+	k := c.app.NameServiceKeeper
+	require.NoError(t, k.Register(c.ctx, owner, "alice", target))
+
+	// only the address the name resolves to picks it as primary name
+	require.ErrorIs(t, c.call(owner, types.RegistryCall{Method: types.MethodSetPrimaryName, Name: "alice"}), types.ErrUnauthorized)
+	require.ErrorIs(t, c.call(target, types.RegistryCall{Method: types.MethodSetPrimaryName, Name: "bob"}), types.ErrUnauthorized)
+	require.NoError(t, c.call(target, types.RegistryCall{Method: types.MethodSetPrimaryName, Name: "Alice"}))
+	name, ok := k.ReverseLookup(c.ctx, target)
+	require.True(t, ok)
+	require.Equal(t, "alice", name)
+	_, ok = k.ReverseLookup(c.ctx, owner)
+	require.False(t, ok)
+
+	// the primary name isn't reported once it resolves to another address
+	require.NoError(t, k.SetAddress(c.ctx, owner, "alice", stranger))
+	_, ok = k.ReverseLookup(c.ctx, target)
+	require.False(t, ok)
+	require.NoError(t, k.SetAddress(c.ctx, owner, "alice", target))
+	_, ok = k.ReverseLookup(c.ctx, target)
+	require.True(t, ok)
+
+	// nor once it expired
+	c.atHeight(10 + period)
+	_, ok = k.ReverseLookup(c.ctx, target)
+	require.False(t, ok)
+
+	// an empty name clears it
+	c.atHeight(10)
+	require.NoError(t, c.call(target, types.RegistryCall{Method: types.MethodSetPrimaryName}))
+	_, found := k.GetPrimaryName(c.ctx, target)
+	require.False(t, found)
+}
+
+func TestGenesis(t *testing.T) {
+	c := setup(t)
+	k := c.app.NameServiceKeeper
+	require.NoError(t, k.Register(c.ctx, owner, "alice", target))
+	require.NoError(t, k.Register(c.ctx, owner, "bob", owner))
+	require.NoError(t, k.SetPrimaryName(c.ctx, target, "alice"))
+
+	exported := k.ExportGenesis(c.ctx)
+	require.NoError(t, exported.Validate())
+	require.Len(t, exported.Records, 2)
+	require.Equal(t, []types.PrimaryName{{Address: target.Hex(), Name: "alice"}}, exported.PrimaryNames)
+
+	other := setup(t)
+	other.app.NameServiceKeeper.InitGenesis(other.ctx, *exported)
+	require.Equal(t, exported, other.app.NameServiceKeeper.ExportGenesis(other.ctx))
+	name, ok := other.app.NameServiceKeeper.ReverseLookup(other.ctx, target)
+	require.True(t, ok)
+	require.Equal(t, "alice", name)
+}
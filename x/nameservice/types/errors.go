@@ -0,0 +1,16 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+)
+
+// x/nameservice module sentinel errors
+var (
+	ErrInvalidName         = errorsmod.Register(ModuleName, 2, "invalid name")
+	ErrInvalidRegistryCall = errorsmod.Register(ModuleName, 3, "invalid name registry call")
+	ErrNameTaken           = errorsmod.Register(ModuleName, 4, "name already registered")
+	ErrNameNotFound        = errorsmod.Register(ModuleName, 5, "name not registered")
+	ErrNameExpired         = errorsmod.Register(ModuleName, 6, "name expired")
+	ErrUnauthorized        = errorsmod.Register(ModuleName, 7, "sender doesn't own the name")
+	ErrInvalidRecord       = errorsmod.Register(ModuleName, 8, "invalid name record")
+)
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"github.com/cosmos/gogoproto/proto"
+)
+
+// init registers the events of the name registry: the registrations,
+// renewals and updates of names and the primary names of addresses.
+func init() {
+	proto.RegisterType((*EventRegisterName)(nil), "tacchain.nameservice.v1.EventRegisterName")
+	proto.RegisterType((*EventRenewName)(nil), "tacchain.nameservice.v1.EventRenewName")
+	proto.RegisterType((*EventUpdateName)(nil), "tacchain.nameservice.v1.EventUpdateName")
+	proto.RegisterType((*EventSetPrimaryName)(nil), "tacchain.nameservice.v1.EventSetPrimaryName")
+}
+
+// EventRegisterName is emitted when a free or expired name is registered.
+type EventRegisterName struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Owner   string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Address string `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Expiry  int64  `protobuf:"varint,4,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	Fee     string `protobuf:"bytes,5,opt,name=fee,proto3" json:"fee,omitempty"`
+}
+
+func (m *EventRegisterName) Reset()         { *m = EventRegisterName{} }
+func (m *EventRegisterName) String() string { return proto.CompactTextString(m) }
+func (*EventRegisterName) ProtoMessage()    {}
+
+// EventRenewName is emitted when the owner of a name extends its registration.
+type EventRenewName struct {
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Expiry int64  `protobuf:"varint,2,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	Fee    string `protobuf:"bytes,3,opt,name=fee,proto3" json:"fee,omitempty"`
+}
+
+func (m *EventRenewName) Reset()         { *m = EventRenewName{} }
+func (m *EventRenewName) String() string { return proto.CompactTextString(m) }
+func (*EventRenewName) ProtoMessage()    {}
+
+// EventUpdateName is emitted when the owner of a name points it to another
+// address or transfers it.
+type EventUpdateName struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Owner   string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Address string `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+}
+
+func (m *EventUpdateName) Reset()         { *m = EventUpdateName{} }
+func (m *EventUpdateName) String() string { return proto.CompactTextString(m) }
+func (*EventUpdateName) ProtoMessage()    {}
+
+// EventSetPrimaryName is emitted when an address sets or clears its primary
+// name.
+type EventSetPrimaryName struct {
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (m *EventSetPrimaryName) Reset()         { *m = EventSetPrimaryName{} }
+func (m *EventSetPrimaryName) String() string { return proto.CompactTextString(m) }
+func (*EventSetPrimaryName) ProtoMessage()    {}
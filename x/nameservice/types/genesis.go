@@ -0,0 +1,57 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GenesisState defines the nameservice module genesis state
+type GenesisState struct {
+	Params       Params        `json:"params" yaml:"params"`
+	Records      []NameRecord  `json:"records" yaml:"records"`
+	PrimaryNames []PrimaryName `json:"primary_names" yaml:"primary_names"`
+}
+
+// DefaultGenesisState returns the default nameservice module genesis state
+func DefaultGenesisState() *GenesisState {
+	return &GenesisState{
+		Params:       DefaultParams(),
+		Records:      []NameRecord{},
+		PrimaryNames: []PrimaryName{},
+	}
+}
+
+// Validate performs basic genesis state validation
+func (gs GenesisState) Validate() error {
+	if err := gs.Params.Validate(); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool, len(gs.Records))
+	for _, record := range gs.Records {
+		if err := record.Validate(); err != nil {
+			return err
+		}
+		if names[NormalizeName(record.Name)] {
+			return fmt.Errorf("duplicate record of name %s", record.Name)
+		}
+		names[NormalizeName(record.Name)] = true
+	}
+
+	addresses := make(map[common.Address]bool, len(gs.PrimaryNames))
+	for _, primary := range gs.PrimaryNames {
+		if !isHexAddress(primary.Address) {
+			return fmt.Errorf("invalid primary name address: %q", primary.Address)
+		}
+		if !names[NormalizeName(primary.Name)] {
+			return fmt.Errorf("primary name %q of %s isn't registered", primary.Name, primary.Address)
+		}
+		addr := common.HexToAddress(primary.Address)
+		if addresses[addr] {
+			return fmt.Errorf("duplicate primary name of %s", primary.Address)
+		}
+		addresses[addr] = true
+	}
+	return nil
+}
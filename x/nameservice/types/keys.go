@@ -0,0 +1,49 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+const (
+	// ModuleName defines the nameservice module name
+	ModuleName = "nameservice"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+
+	// ResolverPrecompileAddress is the address of the static precompile
+	// contracts call to resolve names
+	ResolverPrecompileAddress = "0x0000000000000000000000000000000000000900"
+)
+
+var (
+	// RecordPrefix prefixes the record of a name, keyed by the name
+	RecordPrefix = []byte{0x01}
+	// PrimaryNamePrefix prefixes the primary name of an address, keyed by the
+	// address
+	PrimaryNamePrefix = []byte{0x02}
+)
+
+// RecordKey returns the store key of the record of name
+func RecordKey(name string) []byte {
+	return append(append([]byte{}, RecordPrefix...), []byte(NormalizeName(name))...)
+}
+
+// PrimaryNameKey returns the store key of the primary name of addr
+func PrimaryNameKey(addr common.Address) []byte {
+	return append(append([]byte{}, PrimaryNamePrefix...), addr.Bytes()...)
+}
+
+// RegistryAddress returns the address accounts send their name registry calls
+// to. It is derived like a module account address, no account lives there and
+// no code runs at it, the calls are handled after the transaction executed.
+func RegistryAddress() common.Address {
+	return common.BytesToAddress(authtypes.NewModuleAddress(ModuleName))
+}
+
+// ResolverAddress returns the address of the resolver precompile
+func ResolverAddress() common.Address {
+	return common.HexToAddress(ResolverPrecompileAddress)
+}
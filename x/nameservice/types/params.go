@@ -0,0 +1,84 @@
+package types
+
+import (
+	"fmt"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+var (
+	// KeyRegistrationFee is the param store key for the fee of a registration
+	// or renewal
+	KeyRegistrationFee = []byte("RegistrationFee")
+	// KeyRegistrationPeriod is the param store key for the blocks a
+	// registration or renewal lasts
+	KeyRegistrationPeriod = []byte("RegistrationPeriod")
+)
+
+// DefaultRegistrationPeriod is the number of 2s blocks in a year
+const DefaultRegistrationPeriod uint64 = 15_768_000
+
+// Params defines the nameservice module parameters. Registering or renewing a
+// name costs RegistrationFee, paid to the community pool, and keeps it for
+// RegistrationPeriod blocks.
+type Params struct {
+	RegistrationFee    sdk.Coins `json:"registration_fee" yaml:"registration_fee"`
+	RegistrationPeriod uint64    `json:"registration_period" yaml:"registration_period"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the nameservice module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default nameservice module parameters, a fee of one
+// TAC a year
+func DefaultParams() Params {
+	return Params{
+		RegistrationFee:    sdk.NewCoins(sdk.NewCoin(sdk.DefaultBondDenom, sdkmath.NewIntWithDecimal(1, 18))),
+		RegistrationPeriod: DefaultRegistrationPeriod,
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyRegistrationFee, &p.RegistrationFee, validateRegistrationFee),
+		paramtypes.NewParamSetPair(KeyRegistrationPeriod, &p.RegistrationPeriod, validateRegistrationPeriod),
+	}
+}
+
+// Validate performs basic validation of the nameservice module parameters
+func (p Params) Validate() error {
+	if err := validateRegistrationFee(p.RegistrationFee); err != nil {
+		return err
+	}
+	return validateRegistrationPeriod(p.RegistrationPeriod)
+}
+
+func validateRegistrationFee(i interface{}) error {
+	fee, ok := i.(sdk.Coins)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if err := fee.Validate(); err != nil {
+		return fmt.Errorf("invalid registration fee %s: %w", fee, err)
+	}
+	return nil
+}
+
+func validateRegistrationPeriod(i interface{}) error {
+	period, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if period == 0 {
+		return fmt.Errorf("registration period must be positive")
+	}
+	return nil
+}
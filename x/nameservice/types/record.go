@@ -0,0 +1,107 @@
+package types
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+)
+
+const (
+	// MinNameLength is the minimum length of a name
+	MinNameLength = 3
+	// MaxNameLength is the maximum length of a name, shorter than any address
+	// so a name is never mistaken for one
+	MaxNameLength = 32
+)
+
+// nameRegex matches the names: ascii letters, digits and hyphens, not
+// starting or ending with a hyphen
+var nameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*[A-Za-z0-9]$`)
+
+// NameRecord maps a name to an address until the name expires. Owner and
+// Address are 0x prefixed hex strings.
+//
+// The Owner renews the name, points it to another Address and transfers it.
+// The name resolves to Address up to the block before Expiry, afterwards it
+// doesn't resolve anymore and anyone can register it again.
+type NameRecord struct {
+	Name    string `json:"name" yaml:"name"`
+	Owner   string `json:"owner" yaml:"owner"`
+	Address string `json:"address" yaml:"address"`
+	// Expiry is the first block height the name doesn't resolve at
+	Expiry int64 `json:"expiry" yaml:"expiry"`
+}
+
+// OwnerAddress returns the owner of the name, the record must be valid
+func (r NameRecord) OwnerAddress() common.Address {
+	return common.HexToAddress(r.Owner)
+}
+
+// TargetAddress returns the address the name resolves to, the record must be
+// valid
+func (r NameRecord) TargetAddress() common.Address {
+	return common.HexToAddress(r.Address)
+}
+
+// IsExpired returns true if the name doesn't resolve at height
+func (r NameRecord) IsExpired(height int64) bool {
+	return height >= r.Expiry
+}
+
+// Normalized returns the record with a lower case name and checksummed
+// addresses. The record must be valid.
+func (r NameRecord) Normalized() NameRecord {
+	r.Name = NormalizeName(r.Name)
+	r.Owner = r.OwnerAddress().Hex()
+	r.Address = r.TargetAddress().Hex()
+	return r
+}
+
+// Validate checks the record is well formed
+func (r NameRecord) Validate() error {
+	if err := ValidateName(r.Name); err != nil {
+		return err
+	}
+	if !isHexAddress(r.Owner) {
+		return errorsmod.Wrapf(ErrInvalidRecord, "invalid owner address of %s: %q", r.Name, r.Owner)
+	}
+	if !isHexAddress(r.Address) {
+		return errorsmod.Wrapf(ErrInvalidRecord, "invalid address of %s: %q", r.Name, r.Address)
+	}
+	if r.Expiry <= 0 {
+		return errorsmod.Wrapf(ErrInvalidRecord, "expiry of %s must be positive: %d", r.Name, r.Expiry)
+	}
+	return nil
+}
+
+// PrimaryName is the name an address chose to be displayed with, the reverse
+// record of the address
+type PrimaryName struct {
+	Address string `json:"address" yaml:"address"`
+	Name    string `json:"name" yaml:"name"`
+}
+
+// NormalizeName returns name in lower case, names are unique regardless of
+// case
+func NormalizeName(name string) string {
+	return strings.ToLower(name)
+}
+
+// ValidateName checks name is MinNameLength to MaxNameLength ascii letters,
+// digits and hyphens, not starting or ending with a hyphen
+func ValidateName(name string) error {
+	if len(name) < MinNameLength || len(name) > MaxNameLength {
+		return errorsmod.Wrapf(ErrInvalidName, "name must be %d to %d characters: %q", MinNameLength, MaxNameLength, name)
+	}
+	if !nameRegex.MatchString(name) {
+		return errorsmod.Wrapf(ErrInvalidName, "name must be letters, digits and hyphens not starting or ending with a hyphen: %q", name)
+	}
+	return nil
+}
+
+func isHexAddress(s string) bool {
+	return common.IsHexAddress(s) && common.HexToAddress(s) != (common.Address{})
+}
@@ -0,0 +1,137 @@
+package types_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/nameservice/types"
+)
+
+var (
+	owner  = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	target = common.HexToAddress("0x2000000000000000000000000000000000000002")
+)
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"abc", "alice", "Alice", "tac-2025", "0x1", strings.Repeat("a", types.MaxNameLength)} {
+		require.NoError(t, types.ValidateName(name), name)
+	}
+	for _, name := range []string{"", "ab", strings.Repeat("a", types.MaxNameLength+1), "-alice", "alice-", "al ice", "al.ice", "al_ice", "\u212Aab", "alice\n"} {
+		require.ErrorIs(t, types.ValidateName(name), types.ErrInvalidName, name)
+	}
+}
+
+func TestNameRecordValidate(t *testing.T) {
+	valid := types.NameRecord{Name: "Alice", Owner: strings.ToLower(owner.Hex()), Address: target.Hex(), Expiry: 100}
+	require.NoError(t, valid.Validate())
+	require.Equal(t, types.NameRecord{Name: "alice", Owner: owner.Hex(), Address: target.Hex(), Expiry: 100}, valid.Normalized())
+	require.False(t, valid.IsExpired(99))
+	require.True(t, valid.IsExpired(100))
+
+	for _, tc := range []struct {
+		name   string
+		record func(r *types.NameRecord)
+	}{
+		{"invalid name", func(r *types.NameRecord) { r.Name = "a" }},
+		{"invalid owner", func(r *types.NameRecord) { r.Owner = "alice" }},
+		{"zero owner", func(r *types.NameRecord) { r.Owner = common.Address{}.Hex() }},
+		{"zero address", func(r *types.NameRecord) { r.Address = common.Address{}.Hex() }},
+		{"bech32 address", func(r *types.NameRecord) { r.Address = sdk.AccAddress(target.Bytes()).String() }},
+		{"zero expiry", func(r *types.NameRecord) { r.Expiry = 0 }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			record := valid
+			tc.record(&record)
+			require.Error(t, record.Validate())
+		})
+	}
+}
+
+func TestRegistryCall(t *testing.T) {
+	for _, call := range []types.RegistryCall{
+		{Method: types.MethodRegister, Name: "alice", Address: target},
+		{Method: types.MethodRenew, Name: "alice"},
+		{Method: types.MethodSetAddress, Name: "alice", Address: target},
+		{Method: types.MethodTransfer, Name: "alice", Address: owner},
+		{Method: types.MethodSetPrimaryName, Name: "alice"},
+		{Method: types.MethodSetPrimaryName},
+	} {
+		data, err := call.Pack()
+		require.NoError(t, err)
+		decoded, err := types.ParseRegistryCall(data)
+		require.NoError(t, err)
+		require.Equal(t, call, decoded)
+	}
+
+	data, err := types.RegistryCall{Method: types.MethodRenew, Name: "alice"}.Pack()
+	require.NoError(t, err)
+	_, err = types.ParseRegistryCall(data[:3])
+	require.ErrorIs(t, err, types.ErrInvalidRegistryCall)
+	_, err = types.ParseRegistryCall(append([]byte{0xde, 0xad, 0xbe, 0xef}, data[4:]...))
+	require.ErrorIs(t, err, types.ErrInvalidRegistryCall)
+	_, err = types.ParseRegistryCall(data[:20])
+	require.ErrorIs(t, err, types.ErrInvalidRegistryCall)
+}
+
+func TestParamsValidate(t *testing.T) {
+	params := types.DefaultParams()
+	require.NoError(t, params.Validate())
+
+	params.RegistrationFee = sdk.Coins{}
+	require.NoError(t, params.Validate(), "registrations can be free")
+	params.RegistrationFee = sdk.Coins{sdk.Coin{Denom: "utac", Amount: sdkmath.NewInt(-1)}}
+	require.Error(t, params.Validate())
+
+	params = types.DefaultParams()
+	params.RegistrationPeriod = 0
+	require.Error(t, params.Validate())
+}
+
+func TestGenesisStateValidate(t *testing.T) {
+	record := types.NameRecord{Name: "alice", Owner: owner.Hex(), Address: target.Hex(), Expiry: 100}
+	valid := types.GenesisState{
+		Params:       types.DefaultParams(),
+		Records:      []types.NameRecord{record},
+		PrimaryNames: []types.PrimaryName{{Address: target.Hex(), Name: "alice"}},
+	}
+	require.NoError(t, valid.Validate())
+	require.NoError(t, types.DefaultGenesisState().Validate())
+
+	for _, tc := range []struct {
+		name    string
+		genesis func(gs *types.GenesisState)
+	}{
+		{"duplicate name", func(gs *types.GenesisState) {
+			other := record
+			other.Name = "ALICE"
+			gs.Records = append(gs.Records, other)
+		}},
+		{"invalid record", func(gs *types.GenesisState) { gs.Records[0].Expiry = 0 }},
+		{"unregistered primary name", func(gs *types.GenesisState) { gs.PrimaryNames[0].Name = "bob" }},
+		{"invalid primary name address", func(gs *types.GenesisState) { gs.PrimaryNames[0].Address = "alice" }},
+		{"duplicate primary name", func(gs *types.GenesisState) {
+			gs.PrimaryNames = append(gs.PrimaryNames, gs.PrimaryNames[0])
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gs := valid
+			gs.Records = append([]types.NameRecord{}, valid.Records...)
+			gs.PrimaryNames = append([]types.PrimaryName{}, valid.PrimaryNames...)
+			tc.genesis(&gs)
+			require.Error(t, gs.Validate())
+		})
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	// the addresses are documented, they must not change
+	require.Equal(t, common.HexToAddress("0xa054d3d2145eec06c752d0e8ee4511c9f8075ab3"), types.RegistryAddress())
+	require.Equal(t, common.HexToAddress("0x0000000000000000000000000000000000000900"), types.ResolverAddress())
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+)
+
+const (
+	// MethodRegister registers a name that is free or expired for the sender,
+	// pointing to an address
+	MethodRegister = "register"
+	// MethodRenew extends the registration of a name owned by the sender
+	MethodRenew = "renew"
+	// MethodSetAddress points a name owned by the sender to another address
+	MethodSetAddress = "setAddress"
+	// MethodTransfer gives a name owned by the sender to another owner
+	MethodTransfer = "transfer"
+	// MethodSetPrimaryName sets the name the sender is displayed with, one
+	// resolving to it, or clears it with an empty name
+	MethodSetPrimaryName = "setPrimaryName"
+)
+
+// registryABI is the interface of the calls sent to RegistryAddress
+const registryABI = `[
+	{"type":"function","name":"register","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"name","type":"string"},
+		{"name":"target","type":"address"}
+	]},
+	{"type":"function","name":"renew","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"name","type":"string"}
+	]},
+	{"type":"function","name":"setAddress","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"name","type":"string"},
+		{"name":"target","type":"address"}
+	]},
+	{"type":"function","name":"transfer","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"name","type":"string"},
+		{"name":"newOwner","type":"address"}
+	]},
+	{"type":"function","name":"setPrimaryName","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"name","type":"string"}
+	]}
+]`
+
+// RegistryABI is the parsed interface of the name registry calls
+var RegistryABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(registryABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// RegistryCall is a decoded call to the name registry. Address is the target
+// of register and setAddress and the new owner of transfer.
+type RegistryCall struct {
+	Method  string
+	Name    string
+	Address common.Address
+}
+
+// ParseRegistryCall decodes the calldata of a name registry call
+func ParseRegistryCall(data []byte) (RegistryCall, error) {
+	if len(data) < 4 {
+		return RegistryCall{}, errorsmod.Wrap(ErrInvalidRegistryCall, "missing method selector")
+	}
+	method, err := RegistryABI.MethodById(data[:4])
+	if err != nil {
+		return RegistryCall{}, errorsmod.Wrap(ErrInvalidRegistryCall, err.Error())
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return RegistryCall{}, errorsmod.Wrapf(ErrInvalidRegistryCall, "failed to decode %s arguments: %s", method.Name, err)
+	}
+
+	call := RegistryCall{Method: method.Name, Name: args[0].(string)}
+	if len(args) > 1 {
+		call.Address = args[1].(common.Address)
+	}
+	return call, nil
+}
+
+// Pack encodes the call as calldata for the name registry
+func (c RegistryCall) Pack() ([]byte, error) {
+	switch c.Method {
+	case MethodRegister, MethodSetAddress, MethodTransfer:
+		return RegistryABI.Pack(c.Method, c.Name, c.Address)
+	default:
+		return RegistryABI.Pack(c.Method, c.Name)
+	}
+}
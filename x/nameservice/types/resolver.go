@@ -0,0 +1,49 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+const (
+	// MethodResolve returns the address a name resolves to, the zero address
+	// if it doesn't resolve
+	MethodResolve = "resolve"
+	// MethodReverseResolve returns the primary name of an address, an empty
+	// string if it has none
+	MethodReverseResolve = "reverseResolve"
+	// MethodRecord returns the owner, address and expiry of a name, expired or
+	// not, zero values if it was never registered
+	MethodRecord = "record"
+)
+
+// resolverABI is the interface of the resolver precompile
+const resolverABI = `[
+	{"type":"function","name":"resolve","stateMutability":"view","inputs":[
+		{"name":"name","type":"string"}
+	],"outputs":[
+		{"name":"target","type":"address"}
+	]},
+	{"type":"function","name":"reverseResolve","stateMutability":"view","inputs":[
+		{"name":"addr","type":"address"}
+	],"outputs":[
+		{"name":"name","type":"string"}
+	]},
+	{"type":"function","name":"record","stateMutability":"view","inputs":[
+		{"name":"name","type":"string"}
+	],"outputs":[
+		{"name":"owner","type":"address"},
+		{"name":"target","type":"address"},
+		{"name":"expiry","type":"int64"}
+	]}
+]`
+
+// ResolverABI is the parsed interface of the resolver precompile
+var ResolverABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(resolverABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
@@ -0,0 +1,202 @@
+package keeper
+
+import (
+	"cosmossdk.io/core/comet"
+	corestoretypes "cosmossdk.io/core/store"
+	"cosmossdk.io/log"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/Asphere-xyz/tacchain/x/performance/types"
+)
+
+// Keeper of the performance store
+type Keeper struct {
+	storeService corestoretypes.KVStoreService
+	paramSpace   paramtypes.Subspace
+}
+
+// NewKeeper creates a new performance Keeper instance
+func NewKeeper(storeService corestoretypes.KVStoreService, paramSpace paramtypes.Subspace) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		storeService: storeService,
+		paramSpace:   paramSpace,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current performance module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the performance module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// GetWindowCounters returns the counters of the validator with the given
+// consensus address over the window.
+func (k Keeper) GetWindowCounters(ctx sdk.Context, consAddr sdk.ConsAddress) types.Counters {
+	return k.getCounters(ctx, types.WindowCountersKey(consAddr))
+}
+
+// TrackBlock counts the votes of the last commit and the proposer of the
+// current block.
+func (k Keeper) TrackBlock(ctx sdk.Context) {
+	info := ctx.CometInfo()
+	if info == nil {
+		return
+	}
+
+	var votes []types.Vote
+	if lastCommit := info.GetLastCommit(); lastCommit != nil {
+		infos := lastCommit.Votes()
+		for i := 0; i < infos.Len(); i++ {
+			vote := infos.Get(i)
+			votes = append(votes, types.Vote{
+				ConsAddress: vote.Validator().Address(),
+				// like x/slashing, a nil vote counts as signed
+				Missed: vote.GetBlockIDFlag() == comet.BlockIDFlagAbsent,
+			})
+		}
+	}
+	k.RecordBlock(ctx, info.GetProposerAddress(), votes)
+}
+
+// RecordBlock adds the votes on the previous block and the proposer of the
+// current one to the bucket of the current height, after dropping the
+// buckets that left the window.
+func (k Keeper) RecordBlock(ctx sdk.Context, proposer sdk.ConsAddress, votes []types.Vote) {
+	params := k.GetParams(ctx)
+	k.resetOnBucketSizeChange(ctx, params.BucketSize())
+	k.pruneBuckets(ctx, params.FirstBucket(ctx.BlockHeight()))
+
+	bucket := params.Bucket(ctx.BlockHeight())
+	for _, vote := range votes {
+		counters := types.Counters{Blocks: 1}
+		if vote.Missed {
+			counters.Missed = 1
+		}
+		k.addCounters(ctx, bucket, vote.ConsAddress, counters)
+	}
+	if len(proposer) > 0 {
+		k.addCounters(ctx, bucket, proposer, types.Counters{Proposed: 1})
+	}
+}
+
+func (k Keeper) addCounters(ctx sdk.Context, bucket uint64, consAddr sdk.ConsAddress, counters types.Counters) {
+	bucketKey := types.BucketCountersKey(bucket, consAddr)
+	k.setCounters(ctx, bucketKey, k.getCounters(ctx, bucketKey).Add(counters))
+	windowKey := types.WindowCountersKey(consAddr)
+	k.setCounters(ctx, windowKey, k.getCounters(ctx, windowKey).Add(counters))
+}
+
+// pruneBuckets drops the buckets before firstBucket, subtracting their
+// counters from the window counters.
+func (k Keeper) pruneBuckets(ctx sdk.Context, firstBucket uint64) {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.BucketCountersPrefix, types.BucketCountersPrefixAt(firstBucket))
+	if err != nil {
+		panic(err)
+	}
+	var keys, values [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+		values = append(values, iterator.Value())
+	}
+	iterator.Close()
+
+	prefixLen := len(types.BucketCountersPrefixAt(0))
+	for i, key := range keys {
+		counters, err := types.UnmarshalCounters(values[i])
+		if err != nil {
+			panic(err)
+		}
+		windowKey := types.WindowCountersKey(key[prefixLen:])
+		k.setCounters(ctx, windowKey, k.getCounters(ctx, windowKey).Sub(counters))
+		if err := store.Delete(key); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// resetOnBucketSizeChange clears all counters when the window changed, the
+// buckets recorded before don't line up with the new ones.
+func (k Keeper) resetOnBucketSizeChange(ctx sdk.Context, bucketSize uint64) {
+	store := k.storeService.OpenKVStore(ctx)
+	bz, err := store.Get(types.BucketSizeKey)
+	if err != nil {
+		panic(err)
+	}
+	if len(bz) > 0 && sdk.BigEndianToUint64(bz) == bucketSize {
+		return
+	}
+
+	if len(bz) > 0 {
+		for _, prefix := range [][]byte{types.BucketCountersPrefix, types.WindowCountersPrefix} {
+			k.deletePrefix(ctx, prefix)
+		}
+		k.Logger(ctx).Info("reset validator performance counters", "bucket_size", bucketSize)
+	}
+	if err := store.Set(types.BucketSizeKey, sdk.Uint64ToBigEndian(bucketSize)); err != nil {
+		panic(err)
+	}
+}
+
+func (k Keeper) deletePrefix(ctx sdk.Context, prefix []byte) {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(prefix, storetypes.PrefixEndBytes(prefix))
+	if err != nil {
+		panic(err)
+	}
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+
+	for _, key := range keys {
+		if err := store.Delete(key); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (k Keeper) getCounters(ctx sdk.Context, key []byte) types.Counters {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(key)
+	if err != nil {
+		panic(err)
+	}
+	counters, err := types.UnmarshalCounters(bz)
+	if err != nil {
+		panic(err)
+	}
+	return counters
+}
+
+// setCounters stores counters at key, deleting the key once they are zero so
+// validators that left the window don't linger in the store.
+func (k Keeper) setCounters(ctx sdk.Context, key []byte, counters types.Counters) {
+	store := k.storeService.OpenKVStore(ctx)
+	var err error
+	if counters.IsZero() {
+		err = store.Delete(key)
+	} else {
+		err = store.Set(key, counters.Marshal())
+	}
+	if err != nil {
+		panic(err)
+	}
+}
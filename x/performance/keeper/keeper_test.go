@@ -0,0 +1,114 @@
+package keeper_test
+
+import (
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/performance/types"
+)
+
+func setup(t *testing.T) (*app.TacChainApp, sdk.Context) {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	return tacApp, tacApp.NewContext(false).WithChainID(app.DefaultChainID)
+}
+
+var (
+	reliable = sdk.ConsAddress([]byte("reliable_validator__"))
+	flaky    = sdk.ConsAddress([]byte("flaky_validator_____"))
+)
+
+// blockVotes has the reliable validator sign every block and the flaky one miss
+// every fourth, they take turns proposing.
+func blockVotes(height int64) (sdk.ConsAddress, []types.Vote) {
+	proposer := reliable
+	if height%2 == 0 {
+		proposer = flaky
+	}
+	return proposer, []types.Vote{
+		{ConsAddress: reliable},
+		{ConsAddress: flaky, Missed: height%4 == 0},
+	}
+}
+
+// expectedCounters counts the blocks from height from to to
+func expectedCounters(consAddr sdk.ConsAddress, from, to int64) types.Counters {
+	var counters types.Counters
+	for height := from; height <= to; height++ {
+		proposer, votes := blockVotes(height)
+		for _, vote := range votes {
+			if vote.ConsAddress.Equals(consAddr) {
+				counters.Blocks++
+				if vote.Missed {
+					counters.Missed++
+				}
+			}
+		}
+		if proposer.Equals(consAddr) {
+			counters.Proposed++
+		}
+	}
+	return counters
+}
+
+func TestRollingWindow(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.PerformanceKeeper
+	params := types.Params{Window: 100}
+	k.SetParams(ctx, params)
+
+	for height := int64(1); height <= 250; height++ {
+		proposer, votes := blockVotes(height)
+		k.RecordBlock(ctx.WithBlockHeight(height), proposer, votes)
+
+		// the window holds the blocks from the oldest bucket in it
+		from := max(int64(params.FirstBucket(height)*params.BucketSize()), 1)
+		for _, consAddr := range []sdk.ConsAddress{reliable, flaky} {
+			require.Equal(t, expectedCounters(consAddr, from, height), k.GetWindowCounters(ctx, consAddr), "height %d", height)
+		}
+	}
+
+	// at height 250 the window covers the blocks from 160
+	counters := k.GetWindowCounters(ctx, flaky)
+	require.Equal(t, uint64(91), counters.Blocks)
+	require.Equal(t, uint64(23), counters.Missed)
+	require.Equal(t, uint64(46), counters.Proposed)
+	require.Equal(t, "1.000000000000000000", k.GetWindowCounters(ctx, reliable).Uptime().String())
+
+	// validators that stop voting drop out of the window
+	for height := int64(251); height <= 360; height++ {
+		k.RecordBlock(ctx.WithBlockHeight(height), reliable, []types.Vote{{ConsAddress: reliable}})
+	}
+	require.True(t, k.GetWindowCounters(ctx, flaky).IsZero())
+	require.Equal(t, uint64(91), k.GetWindowCounters(ctx, reliable).Blocks)
+}
+
+func TestWindowChangeResetsCounters(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.PerformanceKeeper
+	k.SetParams(ctx, types.Params{Window: 100})
+
+	for height := int64(1); height <= 50; height++ {
+		proposer, votes := blockVotes(height)
+		k.RecordBlock(ctx.WithBlockHeight(height), proposer, votes)
+	}
+	require.Equal(t, uint64(50), k.GetWindowCounters(ctx, reliable).Blocks)
+
+	k.SetParams(ctx, types.Params{Window: 200})
+	proposer, votes := blockVotes(51)
+	k.RecordBlock(ctx.WithBlockHeight(51), proposer, votes)
+	require.Equal(t, expectedCounters(reliable, 51, 51), k.GetWindowCounters(ctx, reliable))
+}
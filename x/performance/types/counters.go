@@ -0,0 +1,97 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// countersLen is the length of encoded Counters
+const countersLen = 24
+
+// Counters count the blocks a validator was in the validator set of, the ones
+// it missed and the ones it proposed.
+type Counters struct {
+	// Blocks is the number of blocks the validator was expected to sign
+	Blocks uint64 `json:"blocks"`
+	// Missed is the number of those blocks its signature is absent from
+	Missed uint64 `json:"missed"`
+	// Proposed is the number of blocks it proposed
+	Proposed uint64 `json:"proposed"`
+}
+
+// Vote is the vote of a validator on the previous block
+type Vote struct {
+	ConsAddress sdk.ConsAddress
+	Missed      bool
+}
+
+// Add returns the sum of c and o
+func (c Counters) Add(o Counters) Counters {
+	return Counters{
+		Blocks:   c.Blocks + o.Blocks,
+		Missed:   c.Missed + o.Missed,
+		Proposed: c.Proposed + o.Proposed,
+	}
+}
+
+// Sub returns c less o, floored at zero
+func (c Counters) Sub(o Counters) Counters {
+	sub := func(a, b uint64) uint64 {
+		if b > a {
+			return 0
+		}
+		return a - b
+	}
+	return Counters{
+		Blocks:   sub(c.Blocks, o.Blocks),
+		Missed:   sub(c.Missed, o.Missed),
+		Proposed: sub(c.Proposed, o.Proposed),
+	}
+}
+
+// IsZero returns true if nothing was counted
+func (c Counters) IsZero() bool {
+	return c == Counters{}
+}
+
+// Signed returns the number of blocks the validator signed
+func (c Counters) Signed() uint64 {
+	return c.Blocks - min(c.Missed, c.Blocks)
+}
+
+// Uptime returns the share of the blocks the validator signed, zero if it
+// wasn't expected to sign any.
+func (c Counters) Uptime() sdkmath.LegacyDec {
+	if c.Blocks == 0 {
+		return sdkmath.LegacyZeroDec()
+	}
+	return sdkmath.LegacyNewDec(int64(c.Signed())).QuoInt64(int64(c.Blocks))
+}
+
+// Marshal encodes the counters as three big endian uint64
+func (c Counters) Marshal() []byte {
+	bz := make([]byte, 0, countersLen)
+	bz = binary.BigEndian.AppendUint64(bz, c.Blocks)
+	bz = binary.BigEndian.AppendUint64(bz, c.Missed)
+	return binary.BigEndian.AppendUint64(bz, c.Proposed)
+}
+
+// UnmarshalCounters decodes counters encoded by Counters.Marshal, an empty
+// value decodes to zero counters.
+func UnmarshalCounters(bz []byte) (Counters, error) {
+	if len(bz) == 0 {
+		return Counters{}, nil
+	}
+	if len(bz) != countersLen {
+		return Counters{}, fmt.Errorf("invalid counters length %d, expected %d", len(bz), countersLen)
+	}
+	return Counters{
+		Blocks:   binary.BigEndian.Uint64(bz[0:8]),
+		Missed:   binary.BigEndian.Uint64(bz[8:16]),
+		Proposed: binary.BigEndian.Uint64(bz[16:24]),
+	}, nil
+}
@@ -0,0 +1,47 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Asphere-xyz/tacchain/x/performance/types"
+)
+
+func TestCounters(t *testing.T) {
+	counters := types.Counters{Blocks: 8, Missed: 2, Proposed: 1}
+	require.Equal(t, uint64(6), counters.Signed())
+	require.Equal(t, "0.750000000000000000", counters.Uptime().String())
+	require.True(t, types.Counters{}.Uptime().IsZero(), "no blocks is no uptime")
+
+	sum := counters.Add(types.Counters{Blocks: 2, Proposed: 1})
+	require.Equal(t, types.Counters{Blocks: 10, Missed: 2, Proposed: 2}, sum)
+	require.Equal(t, counters, sum.Sub(types.Counters{Blocks: 2, Proposed: 1}))
+	require.True(t, counters.Sub(sum).IsZero(), "subtraction floors at zero")
+
+	decoded, err := types.UnmarshalCounters(counters.Marshal())
+	require.NoError(t, err)
+	require.Equal(t, counters, decoded)
+	decoded, err = types.UnmarshalCounters(nil)
+	require.NoError(t, err)
+	require.True(t, decoded.IsZero())
+	_, err = types.UnmarshalCounters([]byte{1, 2, 3})
+	require.Error(t, err)
+}
+
+func TestParamsBuckets(t *testing.T) {
+	require.NoError(t, types.DefaultParams().Validate())
+	require.Error(t, types.Params{Window: types.NumBuckets - 1}.Validate())
+
+	params := types.Params{Window: 100}
+	require.Equal(t, uint64(10), params.BucketSize())
+	require.Equal(t, uint64(0), params.Bucket(9))
+	require.Equal(t, uint64(1), params.Bucket(10))
+	require.Equal(t, uint64(0), params.FirstBucket(99))
+	require.Equal(t, uint64(1), params.FirstBucket(100))
+	require.Equal(t, uint64(16), params.FirstBucket(250))
+
+	// a window not divisible by the number of buckets covers a little less
+	params.Window = 105
+	require.Equal(t, uint64(10), params.BucketSize())
+}
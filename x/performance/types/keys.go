@@ -0,0 +1,46 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// ModuleName defines the performance module name
+	ModuleName = "performance"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+
+	// NumBuckets is the number of buckets the window is split into, the
+	// counters of the oldest bucket are dropped at once when it leaves the window
+	NumBuckets = 10
+)
+
+var (
+	// BucketCountersPrefix prefixes the counters of a validator in a bucket
+	// of blocks, keyed by bucket then consensus address
+	BucketCountersPrefix = []byte{0x01}
+	// WindowCountersPrefix prefixes the counters of a validator summed over
+	// the buckets in the window, keyed by consensus address
+	WindowCountersPrefix = []byte{0x02}
+	// BucketSizeKey stores the bucket size the counters were recorded with
+	BucketSizeKey = []byte{0x03}
+)
+
+// BucketCountersKey returns the store key of the counters of the validator
+// with the given consensus address in bucket.
+func BucketCountersKey(bucket uint64, consAddr sdk.ConsAddress) []byte {
+	return append(BucketCountersPrefixAt(bucket), consAddr...)
+}
+
+// BucketCountersPrefixAt returns the prefix of the counters of all
+// validators in bucket.
+func BucketCountersPrefixAt(bucket uint64) []byte {
+	return append(append([]byte{}, BucketCountersPrefix...), sdk.Uint64ToBigEndian(bucket)...)
+}
+
+// WindowCountersKey returns the store key of the window counters of the
+// validator with the given consensus address.
+func WindowCountersKey(consAddr sdk.ConsAddress) []byte {
+	return append(append([]byte{}, WindowCountersPrefix...), consAddr...)
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"fmt"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+// DefaultWindow is a day of 2 second blocks
+const DefaultWindow uint64 = 43_200
+
+// KeyWindow is the param store key for the number of blocks the counters cover
+var KeyWindow = []byte("Window")
+
+// Params defines the performance module parameters. The signatures and
+// proposals of every validator are counted over the last Window blocks, split
+// into NumBuckets buckets so the oldest blocks are dropped a bucket at a time.
+// The counters therefore cover between Window less a bucket and Window blocks.
+// Changing the window resets the counters.
+type Params struct {
+	Window uint64 `json:"window" yaml:"window"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the performance module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default performance module parameters
+func DefaultParams() Params {
+	return Params{
+		Window: DefaultWindow,
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyWindow, &p.Window, validateWindow),
+	}
+}
+
+// Validate performs basic validation of the performance module parameters
+func (p Params) Validate() error {
+	return validateWindow(p.Window)
+}
+
+// BucketSize returns the number of blocks in a bucket
+func (p Params) BucketSize() uint64 {
+	return max(p.Window/NumBuckets, 1)
+}
+
+// Bucket returns the bucket the block at height is counted in
+func (p Params) Bucket(height int64) uint64 {
+	return uint64(height) / p.BucketSize()
+}
+
+// FirstBucket returns the oldest bucket still in the window at height
+func (p Params) FirstBucket(height int64) uint64 {
+	bucket := p.Bucket(height)
+	if bucket < NumBuckets-1 {
+		return 0
+	}
+	return bucket - (NumBuckets - 1)
+}
+
+func validateWindow(i interface{}) error {
+	window, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if window < NumBuckets {
+		return fmt.Errorf("window must be at least %d blocks: %d", NumBuckets, window)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package keeper
+
+import (
+	"encoding/hex"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/recovery/types"
+)
+
+// InitGenesis initializes the recovery module state from a genesis state
+func (k Keeper) InitGenesis(ctx sdk.Context, gs types.GenesisState) {
+	k.SetParams(ctx, gs.Params)
+
+	for _, claim := range gs.ProcessedClaims {
+		bz, err := hex.DecodeString(claim.Hash)
+		if err != nil {
+			panic(err)
+		}
+		k.SetClaimProcessed(ctx, bz, claim.ExpiryHeight)
+	}
+}
+
+// ExportGenesis returns the recovery module genesis state
+func (k Keeper) ExportGenesis(ctx sdk.Context) *types.GenesisState {
+	return &types.GenesisState{
+		Params:          k.GetParams(ctx),
+		ProcessedClaims: k.GetProcessedClaims(ctx),
+	}
+}
@@ -0,0 +1,201 @@
+package keeper
+
+import (
+	"encoding/hex"
+
+	"github.com/cosmos/gogoproto/proto"
+
+	corestoretypes "cosmossdk.io/core/store"
+	errorsmod "cosmossdk.io/errors"
+	"cosmossdk.io/log"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/Asphere-xyz/tacchain/x/recovery/types"
+)
+
+// Keeper of the recovery store
+type Keeper struct {
+	storeService corestoretypes.KVStoreService
+	paramSpace   paramtypes.Subspace
+	bankKeeper   types.BankKeeper
+}
+
+// NewKeeper creates a new recovery Keeper instance
+func NewKeeper(storeService corestoretypes.KVStoreService, paramSpace paramtypes.Subspace, bankKeeper types.BankKeeper) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		storeService: storeService,
+		paramSpace:   paramSpace,
+		bankKeeper:   bankKeeper,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current recovery module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the recovery module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// IsClaimProcessed returns true if the claim with the given hash was already executed
+func (k Keeper) IsClaimProcessed(ctx sdk.Context, hash []byte) bool {
+	found, err := k.storeService.OpenKVStore(ctx).Has(types.ProcessedClaimKey(hash))
+	if err != nil {
+		panic(err)
+	}
+	return found
+}
+
+// SetClaimProcessed marks the claim with the given hash and expiry height as executed
+func (k Keeper) SetClaimProcessed(ctx sdk.Context, hash []byte, expiryHeight uint64) {
+	store := k.storeService.OpenKVStore(ctx)
+	if err := store.Set(types.ProcessedClaimKey(hash), sdk.Uint64ToBigEndian(expiryHeight)); err != nil {
+		panic(err)
+	}
+	if err := store.Set(types.ProcessedClaimByExpiryKey(expiryHeight, hash), []byte{}); err != nil {
+		panic(err)
+	}
+}
+
+// PruneProcessedClaims forgets the claims that expired ProcessedClaimRetention
+// blocks ago or earlier, at most MaxPrunedClaimsPerBlock of them so a burst of
+// claims is spread over several blocks. An expired claim can't be executed, so
+// forgetting it doesn't allow its replay. It returns the number of claims
+// pruned.
+func (k Keeper) PruneProcessedClaims(ctx sdk.Context) int {
+	retention := k.GetParams(ctx).ProcessedClaimRetention
+	if retention == 0 || ctx.BlockHeight() < int64(retention) {
+		return 0
+	}
+
+	store := k.storeService.OpenKVStore(ctx)
+	end := types.ProcessedClaimByExpiryPrefixAt(uint64(ctx.BlockHeight()) - retention + 1)
+	iterator, err := store.Iterator(types.ProcessedClaimByExpiryPrefix, end)
+	if err != nil {
+		panic(err)
+	}
+	var keys [][]byte
+	for ; iterator.Valid() && len(keys) < types.MaxPrunedClaimsPerBlock; iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+
+	prefixLen := len(types.ProcessedClaimByExpiryPrefixAt(0))
+	for _, key := range keys {
+		if err := store.Delete(types.ProcessedClaimKey(key[prefixLen:])); err != nil {
+			panic(err)
+		}
+		if err := store.Delete(key); err != nil {
+			panic(err)
+		}
+	}
+	if len(keys) > 0 {
+		k.Logger(ctx).Debug("pruned processed claims", "count", len(keys))
+	}
+	return len(keys)
+}
+
+// GetProcessedClaims returns all executed claims
+func (k Keeper) GetProcessedClaims(ctx sdk.Context) []types.ProcessedClaim {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.ProcessedClaimPrefix, storetypes.PrefixEndBytes(types.ProcessedClaimPrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	claims := []types.ProcessedClaim{}
+	for ; iterator.Valid(); iterator.Next() {
+		claims = append(claims, types.ProcessedClaim{
+			Hash:         hex.EncodeToString(iterator.Key()[len(types.ProcessedClaimPrefix):]),
+			ExpiryHeight: sdk.BigEndianToUint64(iterator.Value()),
+		})
+	}
+	return claims
+}
+
+// ExecuteApprovedClaims moves the funds of every claim approved by governance
+// and clears the approved list. A failing claim doesn't affect the others.
+func (k Keeper) ExecuteApprovedClaims(ctx sdk.Context) {
+	params := k.GetParams(ctx)
+	if len(params.ApprovedClaims) == 0 {
+		return
+	}
+
+	for _, claim := range params.ApprovedClaims {
+		cacheCtx, write := ctx.CacheContext()
+		amount, err := k.executeClaim(cacheCtx, claim)
+		if err != nil {
+			k.Logger(ctx).Error("failed to execute recovery claim", "stuck_address", claim.StuckAddress, "error", err)
+			k.emitEvent(ctx, &types.EventRecoverFundsFailed{
+				StuckAddress: claim.StuckAddress,
+				Recipient:    claim.Recipient,
+				Error:        err.Error(),
+			})
+			continue
+		}
+
+		write()
+		k.emitEvent(ctx, &types.EventRecoverFunds{
+			StuckAddress: claim.StuckAddress,
+			Recipient:    claim.Recipient,
+			Amount:       amount,
+		})
+	}
+
+	params.ApprovedClaims = []types.Claim{}
+	k.SetParams(ctx, params)
+}
+
+// emitEvent emits a typed event. Claims run in the end blocker where there is
+// no tx to fail, so an event that can't be encoded is only logged.
+func (k Keeper) emitEvent(ctx sdk.Context, event proto.Message) {
+	if err := ctx.EventManager().EmitTypedEvent(event); err != nil {
+		k.Logger(ctx).Error("failed to emit recovery event", "event", proto.MessageName(event), "error", err)
+	}
+}
+
+func (k Keeper) executeClaim(ctx sdk.Context, claim types.Claim) (sdk.Coins, error) {
+	if claim.ChainID != ctx.ChainID() {
+		return nil, errorsmod.Wrapf(types.ErrWrongChainID, "expected %s, got %s", ctx.ChainID(), claim.ChainID)
+	}
+
+	if claim.IsExpired(ctx.BlockHeight()) {
+		return nil, errorsmod.Wrapf(types.ErrClaimExpired, "expired at height %d", claim.ExpiryHeight)
+	}
+
+	if err := claim.Verify(); err != nil {
+		return nil, err
+	}
+
+	hash := claim.Hash()
+	if k.IsClaimProcessed(ctx, hash) {
+		return nil, types.ErrClaimProcessed
+	}
+	k.SetClaimProcessed(ctx, hash, claim.ExpiryHeight)
+
+	stuck := sdk.MustAccAddressFromBech32(claim.StuckAddress)
+	recipient := sdk.MustAccAddressFromBech32(claim.Recipient)
+
+	amount := k.bankKeeper.SpendableCoins(ctx, stuck)
+	if amount.IsZero() {
+		return amount, nil
+	}
+
+	return amount, k.bankKeeper.SendCoins(ctx, stuck, recipient, amount)
+}
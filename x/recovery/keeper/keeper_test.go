@@ -0,0 +1,323 @@
+package keeper_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
+	paramproposal "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/recovery/types"
+)
+
+func setup(t *testing.T) (*app.TacChainApp, sdk.Context) {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	return tacApp, tacApp.NewContext(false).WithChainID(app.DefaultChainID)
+}
+
+func fund(t *testing.T, tacApp *app.TacChainApp, ctx sdk.Context, addr sdk.AccAddress, coins sdk.Coins) {
+	t.Helper()
+
+	require.NoError(t, tacApp.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins))
+	require.NoError(t, tacApp.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, addr, coins))
+}
+
+// claimExpiryHeight is the expiry height of the claims of the tests
+const claimExpiryHeight = 1_000_000
+
+func newClaim(t *testing.T, chainID string) (types.Claim, sdk.AccAddress, sdk.AccAddress) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	pubKey := crypto.CompressPubkey(&key.PublicKey)
+	stuck := sdk.AccAddress((&secp256k1.PubKey{Key: pubKey}).Address())
+	recipient := sdk.AccAddress(crypto.PubkeyToAddress(key.PublicKey).Bytes())
+
+	claim := types.Claim{
+		ChainID:      chainID,
+		StuckAddress: stuck.String(),
+		Recipient:    recipient.String(),
+		PubKey:       hex.EncodeToString(pubKey),
+		ExpiryHeight: claimExpiryHeight,
+	}
+	sig, err := crypto.Sign(crypto.Keccak256(claim.SignBytes()), key)
+	require.NoError(t, err)
+	claim.Signature = hex.EncodeToString(sig)
+
+	return claim, stuck, recipient
+}
+
+// typedEvents decodes the events of type T emitted in ctx
+func typedEvents[T proto.Message](t *testing.T, ctx sdk.Context) []T {
+	t.Helper()
+
+	var events []T
+	for _, event := range ctx.EventManager().ABCIEvents() {
+		if event.Type != proto.MessageName(*new(T)) {
+			continue
+		}
+		msg, err := sdk.ParseTypedEvent(event)
+		require.NoError(t, err)
+		events = append(events, msg.(T))
+	}
+	return events
+}
+
+func TestExecuteApprovedClaims(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.RecoveryKeeper
+
+	claim, stuck, recipient := newClaim(t, app.DefaultChainID)
+	coins := sdk.NewCoins(sdk.NewCoin(app.BaseDenom, sdkmath.NewInt(1000)))
+	fund(t, tacApp, ctx, stuck, coins)
+
+	k.SetParams(ctx, types.Params{ApprovedClaims: []types.Claim{claim}})
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	k.ExecuteApprovedClaims(ctx)
+
+	require.True(t, tacApp.BankKeeper.GetAllBalances(ctx, stuck).IsZero())
+	require.Equal(t, coins, tacApp.BankKeeper.GetAllBalances(ctx, recipient))
+	require.Empty(t, k.GetParams(ctx).ApprovedClaims, "approved claims should be cleared")
+	require.True(t, k.IsClaimProcessed(ctx, claim.Hash()))
+	require.Equal(t, []*types.EventRecoverFunds{{
+		StuckAddress: stuck.String(),
+		Recipient:    recipient.String(),
+		Amount:       coins,
+	}}, typedEvents[*types.EventRecoverFunds](t, ctx))
+
+	// replaying the same claim must not move funds received afterwards
+	fund(t, tacApp, ctx, stuck, coins)
+	k.SetParams(ctx, types.Params{ApprovedClaims: []types.Claim{claim}})
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	k.ExecuteApprovedClaims(ctx)
+
+	require.Equal(t, coins, tacApp.BankKeeper.GetAllBalances(ctx, stuck))
+	require.Equal(t, coins, tacApp.BankKeeper.GetAllBalances(ctx, recipient))
+	require.Empty(t, k.GetParams(ctx).ApprovedClaims)
+	require.Empty(t, typedEvents[*types.EventRecoverFunds](t, ctx))
+	failed := typedEvents[*types.EventRecoverFundsFailed](t, ctx)
+	require.Len(t, failed, 1)
+	require.Equal(t, stuck.String(), failed[0].StuckAddress)
+	require.Contains(t, failed[0].Error, types.ErrClaimProcessed.Error())
+}
+
+func TestExecuteApprovedClaimsExpired(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.RecoveryKeeper
+
+	claim, stuck, recipient := newClaim(t, app.DefaultChainID)
+	coins := sdk.NewCoins(sdk.NewCoin(app.BaseDenom, sdkmath.NewInt(1000)))
+	fund(t, tacApp, ctx, stuck, coins)
+
+	// the claim can't be executed from its expiry height
+	ctx = ctx.WithBlockHeight(claimExpiryHeight).WithEventManager(sdk.NewEventManager())
+	k.SetParams(ctx, types.Params{ApprovedClaims: []types.Claim{claim}})
+	k.ExecuteApprovedClaims(ctx)
+
+	require.Equal(t, coins, tacApp.BankKeeper.GetAllBalances(ctx, stuck))
+	require.True(t, tacApp.BankKeeper.GetAllBalances(ctx, recipient).IsZero())
+	require.False(t, k.IsClaimProcessed(ctx, claim.Hash()))
+	failed := typedEvents[*types.EventRecoverFundsFailed](t, ctx)
+	require.Len(t, failed, 1)
+	require.Contains(t, failed[0].Error, types.ErrClaimExpired.Error())
+}
+
+// TestReplayPrunedClaim checks a claim forgotten once its retention passed
+// can't be replayed, being expired
+func TestReplayPrunedClaim(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.RecoveryKeeper
+
+	claim, stuck, recipient := newClaim(t, app.DefaultChainID)
+	coins := sdk.NewCoins(sdk.NewCoin(app.BaseDenom, sdkmath.NewInt(1000)))
+	fund(t, tacApp, ctx, stuck, coins)
+
+	const retention = 10
+	ctx = ctx.WithBlockHeight(claimExpiryHeight - 1)
+	k.SetParams(ctx, types.Params{ApprovedClaims: []types.Claim{claim}, ProcessedClaimRetention: retention})
+	k.ExecuteApprovedClaims(ctx)
+	require.Equal(t, coins, tacApp.BankKeeper.GetAllBalances(ctx, recipient))
+
+	// the claim is remembered until retention blocks after its expiry
+	require.Zero(t, k.PruneProcessedClaims(ctx.WithBlockHeight(claimExpiryHeight+retention-1)))
+	require.True(t, k.IsClaimProcessed(ctx, claim.Hash()))
+	ctx = ctx.WithBlockHeight(claimExpiryHeight + retention)
+	require.Equal(t, 1, k.PruneProcessedClaims(ctx))
+	require.False(t, k.IsClaimProcessed(ctx, claim.Hash()))
+
+	// approving the claim again doesn't move the funds received since
+	fund(t, tacApp, ctx, stuck, coins)
+	params := k.GetParams(ctx)
+	params.ApprovedClaims = []types.Claim{claim}
+	k.SetParams(ctx, params)
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	k.ExecuteApprovedClaims(ctx)
+
+	require.Equal(t, coins, tacApp.BankKeeper.GetAllBalances(ctx, stuck))
+	require.Equal(t, coins, tacApp.BankKeeper.GetAllBalances(ctx, recipient))
+	failed := typedEvents[*types.EventRecoverFundsFailed](t, ctx)
+	require.Len(t, failed, 1)
+	require.Contains(t, failed[0].Error, types.ErrClaimExpired.Error())
+}
+
+func TestExecuteApprovedClaimsWrongChainID(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.RecoveryKeeper
+
+	claim, stuck, recipient := newClaim(t, "tacchain_239-1")
+	coins := sdk.NewCoins(sdk.NewCoin(app.BaseDenom, sdkmath.NewInt(1000)))
+	fund(t, tacApp, ctx, stuck, coins)
+
+	k.SetParams(ctx, types.Params{ApprovedClaims: []types.Claim{claim}})
+	k.ExecuteApprovedClaims(ctx)
+
+	require.Equal(t, coins, tacApp.BankKeeper.GetAllBalances(ctx, stuck))
+	require.True(t, tacApp.BankKeeper.GetAllBalances(ctx, recipient).IsZero())
+	require.False(t, k.IsClaimProcessed(ctx, claim.Hash()))
+}
+
+func TestExecuteApprovedClaimsIsolatesFailures(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.RecoveryKeeper
+
+	badClaim, badStuck, _ := newClaim(t, "tacchain_239-1")
+	goodClaim, goodStuck, goodRecipient := newClaim(t, app.DefaultChainID)
+	coins := sdk.NewCoins(sdk.NewCoin(app.BaseDenom, sdkmath.NewInt(1000)))
+	fund(t, tacApp, ctx, badStuck, coins)
+	fund(t, tacApp, ctx, goodStuck, coins)
+
+	k.SetParams(ctx, types.Params{ApprovedClaims: []types.Claim{badClaim, goodClaim}})
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	k.ExecuteApprovedClaims(ctx)
+
+	require.Equal(t, coins, tacApp.BankKeeper.GetAllBalances(ctx, badStuck))
+	require.Equal(t, coins, tacApp.BankKeeper.GetAllBalances(ctx, goodRecipient))
+
+	failed := typedEvents[*types.EventRecoverFundsFailed](t, ctx)
+	require.Len(t, failed, 1)
+	require.Equal(t, badStuck.String(), failed[0].StuckAddress)
+	require.Contains(t, failed[0].Error, types.ErrWrongChainID.Error())
+	recovered := typedEvents[*types.EventRecoverFunds](t, ctx)
+	require.Len(t, recovered, 1)
+	require.Equal(t, goodRecipient.String(), recovered[0].Recipient)
+}
+
+func TestParamChangeProposal(t *testing.T) {
+	tacApp, ctx := setup(t)
+	handler := params.NewParamChangeProposalHandler(tacApp.ParamsKeeper)
+
+	claim, stuck, recipient := newClaim(t, app.DefaultChainID)
+	coins := sdk.NewCoins(sdk.NewCoin(app.BaseDenom, sdkmath.NewInt(1000)))
+	fund(t, tacApp, ctx, stuck, coins)
+
+	value, err := json.Marshal([]types.Claim{claim})
+	require.NoError(t, err)
+
+	proposal := paramproposal.NewParameterChangeProposal("recover funds", "recover funds", []paramproposal.ParamChange{
+		paramproposal.NewParamChange(types.ModuleName, string(types.KeyApprovedClaims), string(value)),
+	})
+	require.NoError(t, handler(ctx, proposal))
+
+	tacApp.RecoveryKeeper.ExecuteApprovedClaims(ctx)
+	require.Equal(t, coins, tacApp.BankKeeper.GetAllBalances(ctx, recipient))
+
+	// a proposal carrying a claim without a valid proof is rejected
+	invalid := claim
+	invalid.Signature = claim.Signature[:10]
+	value, err = json.Marshal([]types.Claim{invalid})
+	require.NoError(t, err)
+
+	proposal = paramproposal.NewParameterChangeProposal("recover funds", "recover funds", []paramproposal.ParamChange{
+		paramproposal.NewParamChange(types.ModuleName, string(types.KeyApprovedClaims), string(value)),
+	})
+	require.Error(t, handler(ctx, proposal))
+	require.Empty(t, tacApp.RecoveryKeeper.GetParams(ctx).ApprovedClaims)
+}
+
+// storeKeys counts the keys of the recovery store
+func storeKeys(t *testing.T, tacApp *app.TacChainApp, ctx sdk.Context) int {
+	t.Helper()
+
+	it := ctx.KVStore(tacApp.GetKey(types.StoreKey)).Iterator(nil, nil)
+	defer it.Close()
+	var n int
+	for ; it.Valid(); it.Next() {
+		n++
+	}
+	return n
+}
+
+func claimHash(i int) []byte {
+	return crypto.Keccak256([]byte(fmt.Sprintf("claim %d", i)))
+}
+
+func TestPruneProcessedClaims(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.RecoveryKeeper
+
+	const retention = 100
+	k.SetParams(ctx, types.Params{ApprovedClaims: []types.Claim{}, ProcessedClaimRetention: retention})
+
+	// one claim per block expiring 10 blocks later, the state stays bounded
+	// by the retention
+	const expiry = 10
+	for height := int64(1); height <= 3000; height++ {
+		ctx = ctx.WithBlockHeight(height)
+		k.SetClaimProcessed(ctx, claimHash(int(height)), uint64(height+expiry))
+		k.PruneProcessedClaims(ctx)
+
+		remembered := min(height, retention+expiry)
+		require.Len(t, k.GetProcessedClaims(ctx), int(remembered), "height %d", height)
+		require.Equal(t, 2*int(remembered), storeKeys(t, tacApp, ctx), "height %d", height)
+	}
+	require.True(t, k.IsClaimProcessed(ctx, claimHash(3000)))
+	require.True(t, k.IsClaimProcessed(ctx, claimHash(3000-retention-expiry+1)))
+	require.False(t, k.IsClaimProcessed(ctx, claimHash(3000-retention-expiry)))
+	require.False(t, k.IsClaimProcessed(ctx, claimHash(1)))
+}
+
+func TestPruneProcessedClaimsBurst(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.RecoveryKeeper
+
+	ctx = ctx.WithBlockHeight(10)
+	for i := 0; i < 2*types.MaxPrunedClaimsPerBlock+50; i++ {
+		k.SetClaimProcessed(ctx, claimHash(i), 20)
+	}
+
+	// without a retention claims are remembered forever
+	ctx = ctx.WithBlockHeight(1000)
+	require.Zero(t, k.PruneProcessedClaims(ctx))
+
+	// a burst of claims is pruned over several blocks
+	k.SetParams(ctx, types.Params{ApprovedClaims: []types.Claim{}, ProcessedClaimRetention: 50})
+	require.Equal(t, types.MaxPrunedClaimsPerBlock, k.PruneProcessedClaims(ctx))
+	require.Equal(t, types.MaxPrunedClaimsPerBlock, k.PruneProcessedClaims(ctx.WithBlockHeight(1001)))
+	require.Equal(t, 50, k.PruneProcessedClaims(ctx.WithBlockHeight(1002)))
+	require.Zero(t, k.PruneProcessedClaims(ctx.WithBlockHeight(1003)))
+	require.Empty(t, k.GetProcessedClaims(ctx))
+	require.Zero(t, storeKeys(t, tacApp, ctx))
+}
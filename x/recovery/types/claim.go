@@ -0,0 +1,121 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	errorsmod "cosmossdk.io/errors"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Claim proves that the holder of a secp256k1 key owns funds stuck at the
+// Cosmos-style (sha256 + ripemd160) address of that key. TAC accounts are
+// derived the Ethereum way (keccak256), so e.g. an exchange paying out to the
+// bech32 encoding of its Cosmos address sends funds nobody can sign for.
+//
+// The claim is approved by governance and executed by the module, moving all
+// spendable funds from StuckAddress to Recipient.
+type Claim struct {
+	// ChainID the claim is valid for, part of the signed payload to prevent cross-chain replay
+	ChainID string `json:"chain_id" yaml:"chain_id"`
+	// StuckAddress is the bech32 address holding the funds
+	StuckAddress string `json:"stuck_address" yaml:"stuck_address"`
+	// Recipient is the bech32 address the funds are moved to
+	Recipient string `json:"recipient" yaml:"recipient"`
+	// PubKey is the hex encoded compressed secp256k1 public key owning StuckAddress
+	PubKey string `json:"pub_key" yaml:"pub_key"`
+	// ExpiryHeight is the height from which the claim can't be executed, part
+	// of the signed payload so that a claim forgotten after it expired can't
+	// be replayed
+	ExpiryHeight uint64 `json:"expiry_height" yaml:"expiry_height"`
+	// Signature is the hex encoded [R || S || V] signature of keccak256(SignBytes())
+	Signature string `json:"signature" yaml:"signature"`
+}
+
+// SignBytes returns the payload the owner of the stuck funds has to sign.
+func (c Claim) SignBytes() []byte {
+	return []byte(fmt.Sprintf(
+		"tacchain recovery claim\nchain-id: %s\nfrom: %s\nto: %s\nexpiry-height: %d",
+		c.ChainID, c.StuckAddress, c.Recipient, c.ExpiryHeight,
+	))
+}
+
+// IsExpired returns true if the claim can't be executed at height
+func (c Claim) IsExpired(height int64) bool {
+	return height >= 0 && uint64(height) >= c.ExpiryHeight
+}
+
+// Hash uniquely identifies the claim, it is used to prevent executing it twice.
+func (c Claim) Hash() []byte {
+	hash := sha256.Sum256(c.SignBytes())
+	return hash[:]
+}
+
+// Verify statelessly checks that the claim is well formed and that the
+// signature proves ownership of the stuck address.
+func (c Claim) Verify() error {
+	if strings.TrimSpace(c.ChainID) == "" {
+		return errorsmod.Wrap(ErrInvalidClaim, "chain id cannot be empty")
+	}
+	if c.ExpiryHeight == 0 {
+		return errorsmod.Wrap(ErrInvalidClaim, "expiry height cannot be zero")
+	}
+
+	stuck, err := sdk.AccAddressFromBech32(c.StuckAddress)
+	if err != nil {
+		return errorsmod.Wrapf(ErrInvalidClaim, "invalid stuck address: %s", err)
+	}
+
+	recipient, err := sdk.AccAddressFromBech32(c.Recipient)
+	if err != nil {
+		return errorsmod.Wrapf(ErrInvalidClaim, "invalid recipient: %s", err)
+	}
+
+	if stuck.Equals(recipient) {
+		return errorsmod.Wrap(ErrInvalidClaim, "recipient must differ from the stuck address")
+	}
+
+	pubKeyBz, err := hex.DecodeString(strings.TrimPrefix(c.PubKey, "0x"))
+	if err != nil {
+		return errorsmod.Wrapf(ErrInvalidClaim, "invalid public key hex: %s", err)
+	}
+	if len(pubKeyBz) != secp256k1.PubKeySize {
+		return errorsmod.Wrapf(ErrInvalidClaim, "public key must be %d bytes compressed secp256k1, got %d", secp256k1.PubKeySize, len(pubKeyBz))
+	}
+
+	pubKey, err := crypto.DecompressPubkey(pubKeyBz)
+	if err != nil {
+		return errorsmod.Wrapf(ErrInvalidClaim, "invalid secp256k1 public key: %s", err)
+	}
+
+	// funds at the canonical address can be moved by the owner directly
+	if bytes.Equal(stuck, crypto.PubkeyToAddress(*pubKey).Bytes()) {
+		return errorsmod.Wrap(ErrInvalidClaim, "stuck address is the canonical address of the key, funds are not stuck")
+	}
+
+	if !bytes.Equal(stuck, (&secp256k1.PubKey{Key: pubKeyBz}).Address()) {
+		return errorsmod.Wrap(ErrInvalidClaim, "stuck address is not derived from the public key")
+	}
+
+	sig, err := hex.DecodeString(strings.TrimPrefix(c.Signature, "0x"))
+	if err != nil {
+		return errorsmod.Wrapf(ErrInvalidSignature, "invalid signature hex: %s", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return errorsmod.Wrapf(ErrInvalidSignature, "signature must be %d bytes, got %d", crypto.SignatureLength, len(sig))
+	}
+
+	// VerifySignature rejects malleable (high S) signatures
+	if !crypto.VerifySignature(pubKeyBz, crypto.Keccak256(c.SignBytes()), sig[:crypto.RecoveryIDOffset]) {
+		return errorsmod.Wrap(ErrInvalidSignature, "signature does not match public key")
+	}
+
+	return nil
+}
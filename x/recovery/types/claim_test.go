@@ -0,0 +1,287 @@
+package types_test
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/recovery/types"
+)
+
+const testChainID = "tacchain_2391-1"
+
+func signClaim(t *testing.T, key *ecdsa.PrivateKey, claim types.Claim) types.Claim {
+	t.Helper()
+
+	sig, err := crypto.Sign(crypto.Keccak256(claim.SignBytes()), key)
+	require.NoError(t, err)
+	claim.Signature = hex.EncodeToString(sig)
+	return claim
+}
+
+func newClaim(t *testing.T, key *ecdsa.PrivateKey, recipient sdk.AccAddress) types.Claim {
+	t.Helper()
+
+	pubKey := crypto.CompressPubkey(&key.PublicKey)
+	stuck := sdk.AccAddress((&secp256k1.PubKey{Key: pubKey}).Address())
+
+	return signClaim(t, key, types.Claim{
+		ChainID:      testChainID,
+		StuckAddress: stuck.String(),
+		Recipient:    recipient.String(),
+		PubKey:       hex.EncodeToString(pubKey),
+		ExpiryHeight: 1_000_000,
+	})
+}
+
+func TestClaimVerify(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	canonical := sdk.AccAddress(crypto.PubkeyToAddress(key.PublicKey).Bytes())
+	otherStuck := sdk.AccAddress((&secp256k1.PubKey{Key: crypto.CompressPubkey(&otherKey.PublicKey)}).Address())
+
+	testCases := []struct {
+		name      string
+		malleate  func(types.Claim) types.Claim
+		expectErr error
+	}{
+		{
+			"valid claim",
+			func(c types.Claim) types.Claim { return c },
+			nil,
+		},
+		{
+			"valid claim with 0x prefixed hex",
+			func(c types.Claim) types.Claim {
+				c.PubKey = "0x" + c.PubKey
+				c.Signature = "0x" + c.Signature
+				return c
+			},
+			nil,
+		},
+		{
+			"empty chain id",
+			func(c types.Claim) types.Claim {
+				c.ChainID = ""
+				return signClaim(t, key, c)
+			},
+			types.ErrInvalidClaim,
+		},
+		{
+			"zero expiry height",
+			func(c types.Claim) types.Claim {
+				c.ExpiryHeight = 0
+				return signClaim(t, key, c)
+			},
+			types.ErrInvalidClaim,
+		},
+		{
+			"invalid stuck address",
+			func(c types.Claim) types.Claim {
+				c.StuckAddress = "tac1invalid"
+				return c
+			},
+			types.ErrInvalidClaim,
+		},
+		{
+			"invalid recipient",
+			func(c types.Claim) types.Claim {
+				c.Recipient = "0x0000000000000000000000000000000000000001"
+				return c
+			},
+			types.ErrInvalidClaim,
+		},
+		{
+			"recipient equals stuck address",
+			func(c types.Claim) types.Claim {
+				c.Recipient = c.StuckAddress
+				return signClaim(t, key, c)
+			},
+			types.ErrInvalidClaim,
+		},
+		{
+			"public key is not hex",
+			func(c types.Claim) types.Claim {
+				c.PubKey = "not-hex"
+				return c
+			},
+			types.ErrInvalidClaim,
+		},
+		{
+			"uncompressed public key",
+			func(c types.Claim) types.Claim {
+				c.PubKey = hex.EncodeToString(crypto.FromECDSAPub(&key.PublicKey))
+				return c
+			},
+			types.ErrInvalidClaim,
+		},
+		{
+			"public key not on curve",
+			func(c types.Claim) types.Claim {
+				bz := make([]byte, secp256k1.PubKeySize)
+				bz[0] = 0x05
+				c.PubKey = hex.EncodeToString(bz)
+				return c
+			},
+			types.ErrInvalidClaim,
+		},
+		{
+			"stuck address is the canonical address",
+			func(c types.Claim) types.Claim {
+				c.StuckAddress = canonical.String()
+				return signClaim(t, key, c)
+			},
+			types.ErrInvalidClaim,
+		},
+		{
+			"stuck address derived from another key",
+			func(c types.Claim) types.Claim {
+				c.StuckAddress = otherStuck.String()
+				return signClaim(t, key, c)
+			},
+			types.ErrInvalidClaim,
+		},
+		{
+			"public key of another key",
+			func(c types.Claim) types.Claim {
+				c.PubKey = hex.EncodeToString(crypto.CompressPubkey(&otherKey.PublicKey))
+				return signClaim(t, otherKey, c)
+			},
+			types.ErrInvalidClaim,
+		},
+		{
+			"signature is not hex",
+			func(c types.Claim) types.Claim {
+				c.Signature = "zz"
+				return c
+			},
+			types.ErrInvalidSignature,
+		},
+		{
+			"signature too short",
+			func(c types.Claim) types.Claim {
+				c.Signature = c.Signature[:2*crypto.RecoveryIDOffset]
+				return c
+			},
+			types.ErrInvalidSignature,
+		},
+		{
+			"empty signature",
+			func(c types.Claim) types.Claim {
+				c.Signature = ""
+				return c
+			},
+			types.ErrInvalidSignature,
+		},
+		{
+			"signed by another key",
+			func(c types.Claim) types.Claim {
+				return signClaim(t, otherKey, c)
+			},
+			types.ErrInvalidSignature,
+		},
+		{
+			"recipient changed after signing",
+			func(c types.Claim) types.Claim {
+				c.Recipient = otherStuck.String()
+				return c
+			},
+			types.ErrInvalidSignature,
+		},
+		{
+			"chain id changed after signing",
+			func(c types.Claim) types.Claim {
+				c.ChainID = "tacchain_239-1"
+				return c
+			},
+			types.ErrInvalidSignature,
+		},
+		{
+			"expiry height changed after signing",
+			func(c types.Claim) types.Claim {
+				c.ExpiryHeight++
+				return c
+			},
+			types.ErrInvalidSignature,
+		},
+		{
+			"malleated high S signature",
+			func(c types.Claim) types.Claim {
+				sig, err := hex.DecodeString(c.Signature)
+				require.NoError(t, err)
+				s := new(big.Int).SetBytes(sig[32:64])
+				s.Sub(crypto.S256().Params().N, s)
+				s.FillBytes(sig[32:64])
+				c.Signature = hex.EncodeToString(sig)
+				return c
+			},
+			types.ErrInvalidSignature,
+		},
+	}
+
+	recipient := sdk.AccAddress(crypto.PubkeyToAddress(otherKey.PublicKey).Bytes())
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			claim := tc.malleate(newClaim(t, key, recipient))
+
+			err := claim.Verify()
+			if tc.expectErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestParamsValidate(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	recipient := sdk.AccAddress(crypto.PubkeyToAddress(key.PublicKey).Bytes())
+	claim := newClaim(t, key, recipient)
+
+	require.NoError(t, types.DefaultParams().Validate())
+	require.NoError(t, types.Params{ApprovedClaims: []types.Claim{claim}}.Validate())
+
+	duplicate := types.Params{ApprovedClaims: []types.Claim{claim, claim}}
+	require.Error(t, duplicate.Validate())
+
+	invalid := claim
+	invalid.Signature = ""
+	require.Error(t, types.Params{ApprovedClaims: []types.Claim{invalid}}.Validate())
+}
+
+func TestGenesisValidate(t *testing.T) {
+	require.NoError(t, types.DefaultGenesisState().Validate())
+
+	gs := types.DefaultGenesisState()
+	gs.ProcessedClaims = []types.ProcessedClaim{{Hash: "aa", ExpiryHeight: 10}}
+	require.NoError(t, gs.Validate())
+
+	gs.ProcessedClaims = []types.ProcessedClaim{{Hash: "zz", ExpiryHeight: 10}}
+	require.Error(t, gs.Validate())
+
+	gs.ProcessedClaims = []types.ProcessedClaim{{Hash: "aa"}}
+	require.Error(t, gs.Validate())
+
+	gs.ProcessedClaims = []types.ProcessedClaim{{Hash: "aa", ExpiryHeight: 10}, {Hash: "aa", ExpiryHeight: 10}}
+	require.Error(t, gs.Validate())
+}
+
+func TestClaimIsExpired(t *testing.T) {
+	claim := types.Claim{ExpiryHeight: 100}
+	require.False(t, claim.IsExpired(99))
+	require.True(t, claim.IsExpired(100))
+	require.True(t, claim.IsExpired(101))
+}
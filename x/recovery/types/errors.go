@@ -0,0 +1,14 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+)
+
+// x/recovery module sentinel errors
+var (
+	ErrInvalidClaim     = errorsmod.Register(ModuleName, 2, "invalid recovery claim")
+	ErrInvalidSignature = errorsmod.Register(ModuleName, 3, "invalid recovery claim signature")
+	ErrClaimProcessed   = errorsmod.Register(ModuleName, 4, "recovery claim already processed")
+	ErrWrongChainID     = errorsmod.Register(ModuleName, 5, "recovery claim signed for another chain")
+	ErrClaimExpired     = errorsmod.Register(ModuleName, 6, "recovery claim expired")
+)
@@ -0,0 +1,38 @@
+package types
+
+import (
+	"github.com/cosmos/gogoproto/proto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// init registers the outcomes of the approved claims: the end blocker emits
+// EventRecoverFunds or EventRecoverFundsFailed for every claim it runs.
+func init() {
+	proto.RegisterType((*EventRecoverFunds)(nil), "tacchain.recovery.v1.EventRecoverFunds")
+	proto.RegisterType((*EventRecoverFundsFailed)(nil), "tacchain.recovery.v1.EventRecoverFundsFailed")
+}
+
+// EventRecoverFunds is emitted when an approved claim moved the funds of a
+// stuck address to its recipient.
+type EventRecoverFunds struct {
+	StuckAddress string    `protobuf:"bytes,1,opt,name=stuck_address,json=stuckAddress,proto3" json:"stuck_address,omitempty"`
+	Recipient    string    `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Amount       sdk.Coins `protobuf:"bytes,3,rep,name=amount,proto3,castrepeated=github.com/cosmos/cosmos-sdk/types.Coins" json:"amount"`
+}
+
+func (m *EventRecoverFunds) Reset()         { *m = EventRecoverFunds{} }
+func (m *EventRecoverFunds) String() string { return proto.CompactTextString(m) }
+func (*EventRecoverFunds) ProtoMessage()    {}
+
+// EventRecoverFundsFailed is emitted when an approved claim couldn't be
+// executed, the funds stay at the stuck address.
+type EventRecoverFundsFailed struct {
+	StuckAddress string `protobuf:"bytes,1,opt,name=stuck_address,json=stuckAddress,proto3" json:"stuck_address,omitempty"`
+	Recipient    string `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Error        string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *EventRecoverFundsFailed) Reset()         { *m = EventRecoverFundsFailed{} }
+func (m *EventRecoverFundsFailed) String() string { return proto.CompactTextString(m) }
+func (*EventRecoverFundsFailed) ProtoMessage()    {}
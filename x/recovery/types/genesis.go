@@ -0,0 +1,53 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// GenesisState defines the recovery module genesis state
+type GenesisState struct {
+	Params Params `json:"params" yaml:"params"`
+	// ProcessedClaims are the claims already executed
+	ProcessedClaims []ProcessedClaim `json:"processed_claims" yaml:"processed_claims"`
+}
+
+// ProcessedClaim is a claim already executed
+type ProcessedClaim struct {
+	// Hash is the hex encoded hash of the claim
+	Hash string `json:"hash" yaml:"hash"`
+	// ExpiryHeight is the expiry height of the claim
+	ExpiryHeight uint64 `json:"expiry_height" yaml:"expiry_height"`
+}
+
+// DefaultGenesisState returns the default recovery module genesis state
+func DefaultGenesisState() *GenesisState {
+	return &GenesisState{
+		Params:          DefaultParams(),
+		ProcessedClaims: []ProcessedClaim{},
+	}
+}
+
+// Validate performs basic genesis state validation
+func (gs GenesisState) Validate() error {
+	if err := gs.Params.Validate(); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(gs.ProcessedClaims))
+	for _, claim := range gs.ProcessedClaims {
+		bz, err := hex.DecodeString(claim.Hash)
+		if err != nil || len(bz) == 0 {
+			return fmt.Errorf("invalid processed claim hash: %s", claim.Hash)
+		}
+		if claim.ExpiryHeight == 0 {
+			return fmt.Errorf("processed claim %s without expiry height", claim.Hash)
+		}
+		if seen[claim.Hash] {
+			return fmt.Errorf("duplicate processed claim hash: %s", claim.Hash)
+		}
+		seen[claim.Hash] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// ModuleName defines the recovery module name
+	ModuleName = "recovery"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+
+	// MaxPrunedClaimsPerBlock bounds the processed claims pruned in a block
+	MaxPrunedClaimsPerBlock = 100
+)
+
+var (
+	// ProcessedClaimPrefix prefixes the hashes of claims that were already executed,
+	// the value is the expiry height of the claim
+	ProcessedClaimPrefix = []byte{0x01}
+	// ProcessedClaimByExpiryPrefix indexes the executed claims by expiry
+	// height, so the ones past their retention are pruned without walking all
+	// of them
+	ProcessedClaimByExpiryPrefix = []byte{0x02}
+)
+
+// ProcessedClaimKey returns the store key marking the claim with the given hash as executed
+func ProcessedClaimKey(hash []byte) []byte {
+	return append(append([]byte{}, ProcessedClaimPrefix...), hash...)
+}
+
+// ProcessedClaimByExpiryKey returns the index key of the executed claim with
+// the given hash expiring at height.
+func ProcessedClaimByExpiryKey(height uint64, hash []byte) []byte {
+	return append(ProcessedClaimByExpiryPrefixAt(height), hash...)
+}
+
+// ProcessedClaimByExpiryPrefixAt returns the prefix of the index keys of the
+// executed claims expiring at height.
+func ProcessedClaimByExpiryPrefixAt(height uint64) []byte {
+	return append(append([]byte{}, ProcessedClaimByExpiryPrefix...), sdk.Uint64ToBigEndian(height)...)
+}
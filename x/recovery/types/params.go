@@ -0,0 +1,87 @@
+package types
+
+import (
+	"fmt"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+var (
+	// KeyApprovedClaims is the param store key for the claims approved by governance
+	KeyApprovedClaims = []byte("ApprovedClaims")
+	// KeyProcessedClaimRetention is the param store key for the number of blocks executed claims are remembered after they expire
+	KeyProcessedClaimRetention = []byte("ProcessedClaimRetention")
+)
+
+// Params defines the recovery module parameters. Governance approves claims
+// through a ParameterChangeProposal on ApprovedClaims, every claim is verified
+// when the proposal executes and the module moves the funds at the end of the
+// block, clearing the list afterwards.
+//
+// Executed claims are remembered to refuse their replay until they expire,
+// a claim can't be executed from its signed expiry height. With a non-zero
+// ProcessedClaimRetention they are forgotten that many blocks after their
+// expiry height, which keeps the module state bounded; a forgotten claim is
+// expired and can't be replayed.
+type Params struct {
+	ApprovedClaims          []Claim `json:"approved_claims" yaml:"approved_claims"`
+	ProcessedClaimRetention uint64  `json:"processed_claim_retention" yaml:"processed_claim_retention"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the recovery module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default recovery module parameters
+func DefaultParams() Params {
+	return Params{
+		ApprovedClaims:          []Claim{},
+		ProcessedClaimRetention: 0,
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyApprovedClaims, &p.ApprovedClaims, validateClaims),
+		paramtypes.NewParamSetPair(KeyProcessedClaimRetention, &p.ProcessedClaimRetention, validateProcessedClaimRetention),
+	}
+}
+
+// Validate performs basic validation of the recovery module parameters
+func (p Params) Validate() error {
+	if err := validateClaims(p.ApprovedClaims); err != nil {
+		return err
+	}
+	return validateProcessedClaimRetention(p.ProcessedClaimRetention)
+}
+
+func validateClaims(i interface{}) error {
+	claims, ok := i.([]Claim)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	seen := make(map[string]bool, len(claims))
+	for _, claim := range claims {
+		if err := claim.Verify(); err != nil {
+			return err
+		}
+		if seen[claim.StuckAddress] {
+			return fmt.Errorf("duplicate claim for %s", claim.StuckAddress)
+		}
+		seen[claim.StuckAddress] = true
+	}
+
+	return nil
+}
+
+func validateProcessedClaimRetention(i interface{}) error {
+	if _, ok := i.(uint64); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return nil
+}
@@ -0,0 +1,367 @@
+package keeper
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	corestoretypes "cosmossdk.io/core/store"
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/Asphere-xyz/tacchain/x/rewardsnapshot/types"
+)
+
+// Keeper of the rewardsnapshot store
+type Keeper struct {
+	storeService  corestoretypes.KVStoreService
+	paramSpace    paramtypes.Subspace
+	stakingKeeper types.StakingKeeper
+	distrKeeper   types.DistrKeeper
+}
+
+// NewKeeper creates a new rewardsnapshot Keeper instance
+func NewKeeper(
+	storeService corestoretypes.KVStoreService,
+	paramSpace paramtypes.Subspace,
+	stakingKeeper types.StakingKeeper,
+	distrKeeper types.DistrKeeper,
+) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		storeService:  storeService,
+		paramSpace:    paramSpace,
+		stakingKeeper: stakingKeeper,
+		distrKeeper:   distrKeeper,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current rewardsnapshot module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the rewardsnapshot module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// TrackRewards adds the rewards x/distribution handed out in the block to the
+// rewards of the validators in the epoch, it runs right after x/distribution.
+func (k Keeper) TrackRewards(ctx sdk.Context) {
+	if !k.GetParams(ctx).Enabled() {
+		return
+	}
+
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.TrackerPrefix, storetypes.PrefixEndBytes(types.TrackerPrefix))
+	if err != nil {
+		panic(err)
+	}
+	var validators []sdk.ValAddress
+	var trackers []types.Tracker
+	for ; iterator.Valid(); iterator.Next() {
+		var tracker types.Tracker
+		mustUnmarshal(iterator.Value(), &tracker)
+		validators = append(validators, iterator.Key()[len(types.TrackerPrefix):])
+		trackers = append(trackers, tracker)
+	}
+	iterator.Close()
+
+	for i, valAddr := range validators {
+		rewards := k.tracker(ctx, valAddr).Since(trackers[i])
+		if !rewards.IsZero() {
+			k.setEpochRewards(ctx, valAddr, k.GetEpochRewards(ctx, valAddr).Add(rewards))
+		}
+	}
+}
+
+// EndBlocker records the rewards of the validators at the end of the block,
+// and snapshots the validators at the end of an epoch. It runs after
+// x/staking applied the validator set updates.
+func (k Keeper) EndBlocker(ctx sdk.Context) {
+	params := k.GetParams(ctx)
+	epoch, found := k.GetCurrentEpoch(ctx)
+	if !params.Enabled() {
+		if found {
+			k.reset(ctx)
+			k.Logger(ctx).Info("disabled reward snapshots, dropped the epoch in progress", "epoch", epoch.Number)
+		}
+		return
+	}
+	if !found {
+		// the rewards are tracked from the next block
+		epoch = types.Epoch{Number: k.lastEpochNumber(ctx) + 1, StartHeight: ctx.BlockHeight()}
+	}
+	if epoch.StartTime.IsZero() {
+		epoch.StartTime = ctx.BlockTime()
+		k.setCurrentEpoch(ctx, epoch)
+	}
+
+	k.updateTrackers(ctx)
+
+	if ctx.BlockHeight()-epoch.StartHeight+1 >= int64(params.EpochLength) {
+		k.endEpoch(ctx, epoch, params)
+	}
+}
+
+// updateTrackers records the outstanding rewards and commission of the bonded
+// validators and of the validators tracked before, which may still be handed
+// out the rewards of the blocks they signed. Removed validators are dropped.
+func (k Keeper) updateTrackers(ctx sdk.Context) {
+	store := k.storeService.OpenKVStore(ctx)
+	for _, valAddr := range k.bondedValidatorsAnd(ctx, types.TrackerPrefix) {
+		if _, err := k.stakingKeeper.GetValidator(ctx, valAddr); err != nil {
+			if err := store.Delete(types.TrackerKey(valAddr)); err != nil {
+				panic(err)
+			}
+			continue
+		}
+		k.set(ctx, types.TrackerKey(valAddr), k.tracker(ctx, valAddr))
+	}
+}
+
+// endEpoch snapshots the bonded validators and the validators handed out
+// rewards in epoch, prunes the epochs past the retention and starts the next
+// epoch.
+func (k Keeper) endEpoch(ctx sdk.Context, epoch types.Epoch, params types.Params) {
+	powerReduction := k.stakingKeeper.PowerReduction(ctx)
+	epoch.EndHeight = ctx.BlockHeight()
+	epoch.EndTime = ctx.BlockTime()
+	store := k.storeService.OpenKVStore(ctx)
+	for _, valAddr := range k.bondedValidatorsAnd(ctx, types.EpochRewardsPrefix) {
+		rewards := k.GetEpochRewards(ctx, valAddr)
+		snapshot := types.ValidatorSnapshot{
+			Epoch:          epoch.Number,
+			Validator:      valAddr.String(),
+			Tokens:         sdkmath.ZeroInt(),
+			CommissionRate: sdkmath.LegacyZeroDec(),
+			Rewards:        rewards.Rewards,
+			Commission:     rewards.Commission,
+		}
+		// a validator removed during the epoch keeps its rewards only
+		if validator, err := k.stakingKeeper.GetValidator(ctx, valAddr); err == nil {
+			snapshot.Power = validator.ConsensusPower(powerReduction)
+			snapshot.Tokens = validator.Tokens
+			snapshot.Jailed = validator.Jailed
+			snapshot.CommissionRate = validator.Commission.Rate
+		}
+		k.set(ctx, types.SnapshotKey(epoch.Number, valAddr), snapshot)
+
+		epoch.TotalPower += snapshot.Power
+		epoch.Rewards = epoch.Rewards.Add(rewards.Rewards...)
+		if err := store.Delete(types.EpochRewardsKey(valAddr)); err != nil {
+			panic(err)
+		}
+	}
+	k.set(ctx, types.EpochKey(epoch.Number), epoch)
+
+	if epoch.Number > params.Retention {
+		k.pruneEpochs(ctx, epoch.Number-params.Retention+1)
+	}
+	// the start time is the time of the first block of the epoch
+	k.setCurrentEpoch(ctx, types.Epoch{Number: epoch.Number + 1, StartHeight: ctx.BlockHeight() + 1})
+}
+
+// pruneEpochs drops the epochs before firstEpoch and their snapshots
+func (k Keeper) pruneEpochs(ctx sdk.Context, firstEpoch uint64) {
+	k.deleteRange(ctx, types.EpochPrefix, types.EpochKey(firstEpoch))
+	k.deleteRange(ctx, types.SnapshotPrefix, types.SnapshotPrefixAt(firstEpoch))
+}
+
+// reset drops the epoch in progress, the rewards tracked in it and the
+// trackers, the ended epochs are kept
+func (k Keeper) reset(ctx sdk.Context) {
+	k.deleteRange(ctx, types.TrackerPrefix, storetypes.PrefixEndBytes(types.TrackerPrefix))
+	k.deleteRange(ctx, types.EpochRewardsPrefix, storetypes.PrefixEndBytes(types.EpochRewardsPrefix))
+	if err := k.storeService.OpenKVStore(ctx).Delete(types.CurrentEpochKey); err != nil {
+		panic(err)
+	}
+}
+
+// GetCurrentEpoch returns the epoch in progress, if the snapshots are enabled
+func (k Keeper) GetCurrentEpoch(ctx sdk.Context) (types.Epoch, bool) {
+	var epoch types.Epoch
+	return epoch, k.get(ctx, types.CurrentEpochKey, &epoch)
+}
+
+func (k Keeper) setCurrentEpoch(ctx sdk.Context, epoch types.Epoch) {
+	k.set(ctx, types.CurrentEpochKey, epoch)
+}
+
+// GetEpoch returns the ended epoch number, if it wasn't pruned
+func (k Keeper) GetEpoch(ctx sdk.Context, number uint64) (types.Epoch, bool) {
+	var epoch types.Epoch
+	return epoch, k.get(ctx, types.EpochKey(number), &epoch)
+}
+
+// GetEpochRewards returns the rewards of a validator in the epoch in progress
+func (k Keeper) GetEpochRewards(ctx sdk.Context, valAddr sdk.ValAddress) types.EpochRewards {
+	var rewards types.EpochRewards
+	k.get(ctx, types.EpochRewardsKey(valAddr), &rewards)
+	return rewards
+}
+
+func (k Keeper) setEpochRewards(ctx sdk.Context, valAddr sdk.ValAddress, rewards types.EpochRewards) {
+	k.set(ctx, types.EpochRewardsKey(valAddr), rewards)
+}
+
+// GetSnapshots returns the snapshots of the validators at the end of the
+// epochs from fromEpoch to toEpoch included, by epoch then operator address
+func (k Keeper) GetSnapshots(ctx sdk.Context, fromEpoch, toEpoch uint64) []types.ValidatorSnapshot {
+	snapshots := []types.ValidatorSnapshot{}
+	if toEpoch < fromEpoch {
+		return snapshots
+	}
+	iterator, err := k.storeService.OpenKVStore(ctx).Iterator(types.SnapshotPrefixAt(fromEpoch), types.SnapshotPrefixAt(toEpoch+1))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var snapshot types.ValidatorSnapshot
+		mustUnmarshal(iterator.Value(), &snapshot)
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
+
+// GetValidatorSnapshot returns the snapshot of a validator at the end of
+// epoch number, if it was bonded or handed out rewards in the epoch
+func (k Keeper) GetValidatorSnapshot(ctx sdk.Context, number uint64, valAddr sdk.ValAddress) (types.ValidatorSnapshot, bool) {
+	var snapshot types.ValidatorSnapshot
+	return snapshot, k.get(ctx, types.SnapshotKey(number, valAddr), &snapshot)
+}
+
+// lastEpochNumber returns the number of the last ended epoch, zero if none
+// is kept
+func (k Keeper) lastEpochNumber(ctx sdk.Context) uint64 {
+	iterator, err := k.storeService.OpenKVStore(ctx).ReverseIterator(types.EpochPrefix, storetypes.PrefixEndBytes(types.EpochPrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+	if !iterator.Valid() {
+		return 0
+	}
+	return sdk.BigEndianToUint64(iterator.Key()[len(types.EpochPrefix):])
+}
+
+// tracker returns the outstanding rewards and the accumulated commission of
+// a validator
+func (k Keeper) tracker(ctx sdk.Context, valAddr sdk.ValAddress) types.Tracker {
+	outstanding, err := k.distrKeeper.GetValidatorOutstandingRewards(ctx, valAddr)
+	if err != nil {
+		panic(err)
+	}
+	commission, err := k.distrKeeper.GetValidatorAccumulatedCommission(ctx, valAddr)
+	if err != nil {
+		panic(err)
+	}
+	return types.Tracker{Outstanding: outstanding.Rewards, Commission: commission.Commission}
+}
+
+// bondedValidatorsAnd returns the bonded validators and the validators keyed
+// under prefix, by operator address
+func (k Keeper) bondedValidatorsAnd(ctx sdk.Context, prefix []byte) []sdk.ValAddress {
+	validators := map[string]sdk.ValAddress{}
+	err := k.stakingKeeper.IterateBondedValidatorsByPower(ctx, func(_ int64, validator stakingtypes.ValidatorI) bool {
+		valAddr, err := sdk.ValAddressFromBech32(validator.GetOperator())
+		if err != nil {
+			panic(err)
+		}
+		validators[string(valAddr)] = valAddr
+		return false
+	})
+	if err != nil {
+		panic(err)
+	}
+	for _, valAddr := range k.keysUnder(ctx, prefix) {
+		validators[string(valAddr)] = valAddr
+	}
+
+	addrs := make([]sdk.ValAddress, 0, len(validators))
+	for _, valAddr := range validators {
+		addrs = append(addrs, valAddr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i], addrs[j]) < 0
+	})
+	return addrs
+}
+
+// keysUnder returns the operator addresses keyed under prefix
+func (k Keeper) keysUnder(ctx sdk.Context, prefix []byte) []sdk.ValAddress {
+	iterator, err := k.storeService.OpenKVStore(ctx).Iterator(prefix, storetypes.PrefixEndBytes(prefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+	var addrs []sdk.ValAddress
+	for ; iterator.Valid(); iterator.Next() {
+		addrs = append(addrs, sdk.ValAddress(iterator.Key()[len(prefix):]))
+	}
+	return addrs
+}
+
+func (k Keeper) deleteRange(ctx sdk.Context, start, end []byte) {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(start, end)
+	if err != nil {
+		panic(err)
+	}
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+
+	for _, key := range keys {
+		if err := store.Delete(key); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// get decodes the value of key into v, it returns false if the key is unset
+func (k Keeper) get(ctx sdk.Context, key []byte, v any) bool {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(key)
+	if err != nil {
+		panic(err)
+	}
+	if len(bz) == 0 {
+		return false
+	}
+	mustUnmarshal(bz, v)
+	return true
+}
+
+func (k Keeper) set(ctx sdk.Context, key []byte, v any) {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	if err := k.storeService.OpenKVStore(ctx).Set(key, bz); err != nil {
+		panic(err)
+	}
+}
+
+func mustUnmarshal(bz []byte, v any) {
+	if err := json.Unmarshal(bz, v); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,201 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/rewardsnapshot/types"
+)
+
+type testChain struct {
+	t       *testing.T
+	app     *app.TacChainApp
+	ctx     sdk.Context
+	valAddr sdk.ValAddress
+}
+
+func setup(t *testing.T, params types.Params) *testChain {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := tacApp.NewContext(false).WithChainID(app.DefaultChainID)
+	tacApp.RewardSnapshotKeeper.SetParams(ctx, params)
+
+	validators, err := tacApp.StakingKeeper.GetAllValidators(ctx)
+	require.NoError(t, err)
+	valAddr, err := sdk.ValAddressFromBech32(validators[0].OperatorAddress)
+	require.NoError(t, err)
+	// the validator keeps a tenth of the rewards
+	validators[0].Commission.Rate = sdkmath.LegacyNewDecWithPrec(1, 1)
+	require.NoError(t, tacApp.StakingKeeper.SetValidator(ctx, validators[0]))
+	return &testChain{t: t, app: tacApp, ctx: ctx, valAddr: valAddr}
+}
+
+// blockTime is the time of the block at height
+func blockTime(height int64) time.Time {
+	return time.Unix(1_700_000_000, 0).Add(time.Duration(height) * 2 * time.Second).UTC()
+}
+
+// block runs the block at height, x/distribution handing out reward to the
+// validator, and withdraw runs in the txs of the block
+func (c *testChain) block(height, reward int64, withdraw func()) {
+	c.ctx = c.ctx.WithBlockHeight(height).WithBlockTime(blockTime(height))
+	if reward > 0 {
+		validator, err := c.app.StakingKeeper.GetValidator(c.ctx, c.valAddr)
+		require.NoError(c.t, err)
+		rewards := sdk.NewDecCoins(sdk.NewInt64DecCoin(app.BaseDenom, reward))
+		require.NoError(c.t, c.app.DistrKeeper.AllocateTokensToValidator(c.ctx, validator, rewards))
+	}
+	c.app.RewardSnapshotKeeper.TrackRewards(c.ctx)
+	if withdraw != nil {
+		withdraw()
+	}
+	c.app.RewardSnapshotKeeper.EndBlocker(c.ctx)
+}
+
+// withdrawAll empties the outstanding rewards and the commission of the
+// validator, like the withdrawals of its rewards would
+func (c *testChain) withdrawAll() {
+	require.NoError(c.t, c.app.DistrKeeper.SetValidatorOutstandingRewards(c.ctx, c.valAddr, distrtypes.ValidatorOutstandingRewards{}))
+	require.NoError(c.t, c.app.DistrKeeper.SetValidatorAccumulatedCommission(c.ctx, c.valAddr, distrtypes.ValidatorAccumulatedCommission{}))
+}
+
+func TestEpochs(t *testing.T) {
+	c := setup(t, types.Params{EpochLength: 3, Retention: 2})
+	k := c.app.RewardSnapshotKeeper
+
+	// the rewards of the first block are handed out before the validator is
+	// tracked
+	c.block(1, 100, nil)
+	epoch, found := k.GetCurrentEpoch(c.ctx)
+	require.True(t, found)
+	require.Equal(t, types.Epoch{Number: 1, StartHeight: 1, StartTime: blockTime(1)}, epoch)
+	c.block(2, 100, nil)
+	// the withdrawals don't count as negative rewards
+	c.block(3, 50, c.withdrawAll)
+
+	epoch, found = k.GetEpoch(c.ctx, 1)
+	require.True(t, found)
+	validator, err := c.app.StakingKeeper.GetValidator(c.ctx, c.valAddr)
+	require.NoError(t, err)
+	power := validator.ConsensusPower(c.app.StakingKeeper.PowerReduction(c.ctx))
+	require.Equal(t, types.Epoch{
+		Number:      1,
+		StartHeight: 1,
+		StartTime:   blockTime(1),
+		EndHeight:   3,
+		EndTime:     blockTime(3),
+		TotalPower:  power,
+		Rewards:     sdk.NewDecCoins(sdk.NewInt64DecCoin(app.BaseDenom, 150)),
+	}, epoch)
+	snapshot, found := k.GetValidatorSnapshot(c.ctx, 1, c.valAddr)
+	require.True(t, found)
+	require.Equal(t, types.ValidatorSnapshot{
+		Epoch:          1,
+		Validator:      c.valAddr.String(),
+		Power:          power,
+		Tokens:         validator.Tokens,
+		CommissionRate: validator.Commission.Rate,
+		Rewards:        sdk.NewDecCoins(sdk.NewInt64DecCoin(app.BaseDenom, 150)),
+		Commission:     sdk.NewDecCoins(sdk.NewInt64DecCoin(app.BaseDenom, 15)),
+	}, snapshot)
+	require.True(t, k.GetEpochRewards(c.ctx, c.valAddr).IsZero(), "the rewards restart with the next epoch")
+
+	epoch, found = k.GetCurrentEpoch(c.ctx)
+	require.True(t, found)
+	require.Equal(t, types.Epoch{Number: 2, StartHeight: 4}, epoch, "the start time is set by the first block")
+
+	// the rewards are handed out after the withdrawal
+	c.block(4, 30, nil)
+	require.Equal(t, blockTime(4), must(k.GetCurrentEpoch(c.ctx)).StartTime)
+	require.Equal(t, sdk.NewDecCoins(sdk.NewInt64DecCoin(app.BaseDenom, 30)), k.GetEpochRewards(c.ctx, c.valAddr).Rewards)
+	for height := int64(5); height <= 9; height++ {
+		c.block(height, 10, nil)
+	}
+
+	// the epochs past the retention are pruned
+	_, found = k.GetEpoch(c.ctx, 1)
+	require.False(t, found)
+	snapshots := k.GetSnapshots(c.ctx, 1, 3)
+	require.Len(t, snapshots, 2)
+	require.Equal(t, []uint64{2, 3}, []uint64{snapshots[0].Epoch, snapshots[1].Epoch})
+	require.Equal(t, sdk.NewDecCoins(sdk.NewInt64DecCoin(app.BaseDenom, 50)), snapshots[0].Rewards)
+	require.Equal(t, sdk.NewDecCoins(sdk.NewInt64DecCoin(app.BaseDenom, 30)), snapshots[1].Rewards)
+	require.Len(t, k.GetSnapshots(c.ctx, 3, 3), 1)
+	require.Empty(t, k.GetSnapshots(c.ctx, 3, 2))
+}
+
+func TestDisable(t *testing.T) {
+	c := setup(t, types.Params{EpochLength: 2, Retention: 10})
+	k := c.app.RewardSnapshotKeeper
+
+	for height := int64(1); height <= 3; height++ {
+		c.block(height, 100, nil)
+	}
+	require.Equal(t, uint64(2), must(k.GetCurrentEpoch(c.ctx)).Number)
+	require.False(t, k.GetEpochRewards(c.ctx, c.valAddr).IsZero())
+
+	// disabling drops the epoch in progress and keeps the ended ones
+	k.SetParams(c.ctx, types.Params{Retention: 10})
+	c.block(4, 100, nil)
+	_, found := k.GetCurrentEpoch(c.ctx)
+	require.False(t, found)
+	require.True(t, k.GetEpochRewards(c.ctx, c.valAddr).IsZero())
+	_, found = k.GetEpoch(c.ctx, 1)
+	require.True(t, found)
+
+	// the epochs continue from the last ended one
+	k.SetParams(c.ctx, types.Params{EpochLength: 2, Retention: 10})
+	c.block(5, 100, nil)
+	require.Equal(t, types.Epoch{Number: 2, StartHeight: 5, StartTime: blockTime(5)}, must(k.GetCurrentEpoch(c.ctx)))
+	c.block(6, 100, nil)
+	snapshot, found := k.GetValidatorSnapshot(c.ctx, 2, c.valAddr)
+	require.True(t, found)
+	require.Equal(t, sdk.NewDecCoins(sdk.NewInt64DecCoin(app.BaseDenom, 100)), snapshot.Rewards, "the rewards of the block re-enabling aren't tracked")
+
+	// a shorter length ends the epoch in progress at the next block
+	k.SetParams(c.ctx, types.Params{EpochLength: 4, Retention: 10})
+	c.block(7, 0, nil)
+	c.block(8, 0, nil)
+	_, found = k.GetEpoch(c.ctx, 3)
+	require.False(t, found)
+	k.SetParams(c.ctx, types.Params{EpochLength: 2, Retention: 10})
+	c.block(9, 0, nil)
+	epoch, found := k.GetEpoch(c.ctx, 3)
+	require.True(t, found)
+	require.Equal(t, int64(7), epoch.StartHeight)
+	require.Equal(t, int64(9), epoch.EndHeight)
+	require.Empty(t, epoch.Rewards)
+	require.True(t, sdkmath.ZeroInt().LT(must(k.GetValidatorSnapshot(c.ctx, 3, c.valAddr)).Tokens))
+}
+
+func TestGenesis(t *testing.T) {
+	c := setup(t, types.DefaultParams())
+	k := c.app.RewardSnapshotKeeper
+
+	params := types.Params{EpochLength: 100, Retention: 5}
+	k.InitGenesis(c.ctx, types.GenesisState{Params: params})
+	require.Equal(t, &types.GenesisState{Params: params}, k.ExportGenesis(c.ctx))
+}
+
+func must[T any](v T, found bool) T {
+	if !found {
+		panic("not found")
+	}
+	return v
+}
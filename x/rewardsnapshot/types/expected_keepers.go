@@ -0,0 +1,24 @@
+package types
+
+import (
+	"context"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// StakingKeeper defines the expected staking keeper
+type StakingKeeper interface {
+	GetValidator(ctx context.Context, addr sdk.ValAddress) (stakingtypes.Validator, error)
+	IterateBondedValidatorsByPower(ctx context.Context, fn func(index int64, validator stakingtypes.ValidatorI) (stop bool)) error
+	PowerReduction(ctx context.Context) sdkmath.Int
+}
+
+// DistrKeeper defines the expected distribution keeper
+type DistrKeeper interface {
+	GetValidatorOutstandingRewards(ctx context.Context, val sdk.ValAddress) (distrtypes.ValidatorOutstandingRewards, error)
+	GetValidatorAccumulatedCommission(ctx context.Context, val sdk.ValAddress) (distrtypes.ValidatorAccumulatedCommission, error)
+}
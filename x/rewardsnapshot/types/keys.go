@@ -0,0 +1,57 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// ModuleName defines the rewardsnapshot module name
+	ModuleName = "rewardsnapshot"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+)
+
+var (
+	// CurrentEpochKey stores the epoch in progress
+	CurrentEpochKey = []byte{0x01}
+	// TrackerPrefix prefixes the outstanding rewards and commission of a
+	// validator at the end of the last block, keyed by operator address
+	TrackerPrefix = []byte{0x02}
+	// EpochRewardsPrefix prefixes the rewards of a validator in the epoch in
+	// progress, keyed by operator address
+	EpochRewardsPrefix = []byte{0x03}
+	// EpochPrefix prefixes the ended epochs, keyed by number
+	EpochPrefix = []byte{0x04}
+	// SnapshotPrefix prefixes the snapshots of the validators at the end of
+	// an epoch, keyed by epoch number then operator address
+	SnapshotPrefix = []byte{0x05}
+)
+
+// TrackerKey returns the store key of the rewards tracker of a validator
+func TrackerKey(valAddr sdk.ValAddress) []byte {
+	return append(append([]byte{}, TrackerPrefix...), valAddr...)
+}
+
+// EpochRewardsKey returns the store key of the rewards of a validator in the
+// epoch in progress
+func EpochRewardsKey(valAddr sdk.ValAddress) []byte {
+	return append(append([]byte{}, EpochRewardsPrefix...), valAddr...)
+}
+
+// EpochKey returns the store key of the ended epoch number
+func EpochKey(number uint64) []byte {
+	return append(append([]byte{}, EpochPrefix...), sdk.Uint64ToBigEndian(number)...)
+}
+
+// SnapshotPrefixAt returns the prefix of the snapshots of the validators at
+// the end of epoch number
+func SnapshotPrefixAt(number uint64) []byte {
+	return append(append([]byte{}, SnapshotPrefix...), sdk.Uint64ToBigEndian(number)...)
+}
+
+// SnapshotKey returns the store key of the snapshot of a validator at the end
+// of epoch number
+func SnapshotKey(number uint64, valAddr sdk.ValAddress) []byte {
+	return append(SnapshotPrefixAt(number), valAddr...)
+}
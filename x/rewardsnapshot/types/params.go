@@ -0,0 +1,86 @@
+package types
+
+import (
+	"fmt"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+const (
+	// DefaultEpochLength is a day of 2 second blocks
+	DefaultEpochLength uint64 = 43_200
+	// DefaultRetention keeps about three months of daily epochs
+	DefaultRetention uint64 = 90
+)
+
+var (
+	// KeyEpochLength is the param store key for the number of blocks of an epoch
+	KeyEpochLength = []byte("EpochLength")
+	// KeyRetention is the param store key for the number of ended epochs kept
+	KeyRetention = []byte("Retention")
+)
+
+// Params defines the rewardsnapshot module parameters. The rewards handed out
+// to each validator are summed over epochs of EpochLength blocks, and at the
+// end of an epoch the power, commission and rewards of the validators are
+// snapshotted. The snapshots of the last Retention epochs are kept. An
+// EpochLength of zero disables the snapshots and drops the epoch in progress,
+// a new length applies from the epoch in progress.
+type Params struct {
+	EpochLength uint64 `json:"epoch_length" yaml:"epoch_length"`
+	Retention   uint64 `json:"retention" yaml:"retention"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the rewardsnapshot module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default rewardsnapshot module parameters
+func DefaultParams() Params {
+	return Params{
+		EpochLength: DefaultEpochLength,
+		Retention:   DefaultRetention,
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyEpochLength, &p.EpochLength, validateEpochLength),
+		paramtypes.NewParamSetPair(KeyRetention, &p.Retention, validateRetention),
+	}
+}
+
+// Validate performs basic validation of the rewardsnapshot module parameters
+func (p Params) Validate() error {
+	if err := validateEpochLength(p.EpochLength); err != nil {
+		return err
+	}
+	return validateRetention(p.Retention)
+}
+
+// Enabled returns true if the rewards are snapshotted
+func (p Params) Enabled() bool {
+	return p.EpochLength > 0
+}
+
+func validateEpochLength(i interface{}) error {
+	if _, ok := i.(uint64); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return nil
+}
+
+func validateRetention(i interface{}) error {
+	v, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v == 0 {
+		return fmt.Errorf("retention must be at least one epoch")
+	}
+	return nil
+}
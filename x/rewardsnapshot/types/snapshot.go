@@ -0,0 +1,91 @@
+package types
+
+import (
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Epoch is a range of blocks the rewards of the validators are summed over.
+// EndHeight and EndTime are set once it ended.
+type Epoch struct {
+	Number      uint64    `json:"number"`
+	StartHeight int64     `json:"start_height"`
+	StartTime   time.Time `json:"start_time"`
+	EndHeight   int64     `json:"end_height,omitempty"`
+	EndTime     time.Time `json:"end_time"`
+	// TotalPower is the consensus power of the bonded validators at the end
+	TotalPower int64 `json:"total_power,omitempty"`
+	// Rewards are the rewards handed out to all validators in the epoch,
+	// commission included
+	Rewards sdk.DecCoins `json:"rewards,omitempty"`
+}
+
+// ValidatorSnapshot is a validator at the end of an epoch, with the rewards
+// it was handed out during the epoch.
+type ValidatorSnapshot struct {
+	Epoch     uint64 `json:"epoch"`
+	Validator string `json:"validator"`
+	// Power is the consensus power of the validator, zero when it isn't
+	// bonded
+	Power          int64             `json:"power"`
+	Tokens         sdkmath.Int       `json:"tokens"`
+	Jailed         bool              `json:"jailed"`
+	CommissionRate sdkmath.LegacyDec `json:"commission_rate"`
+	// Rewards are the rewards of the validator and its delegators, commission
+	// included
+	Rewards sdk.DecCoins `json:"rewards"`
+	// Commission is the share of the rewards kept by the validator
+	Commission sdk.DecCoins `json:"commission"`
+}
+
+// Tracker is the outstanding rewards and the accumulated commission of a
+// validator at the end of the last block. x/distribution only adds to them
+// when it hands out the rewards of a block, so they tell the rewards of a
+// block apart from the withdrawals.
+type Tracker struct {
+	Outstanding sdk.DecCoins `json:"outstanding"`
+	Commission  sdk.DecCoins `json:"commission"`
+}
+
+// EpochRewards are the rewards handed out to a validator in the epoch in
+// progress.
+type EpochRewards struct {
+	Rewards    sdk.DecCoins `json:"rewards"`
+	Commission sdk.DecCoins `json:"commission"`
+}
+
+// Add returns the sum of r and o
+func (r EpochRewards) Add(o EpochRewards) EpochRewards {
+	return EpochRewards{
+		Rewards:    r.Rewards.Add(o.Rewards...),
+		Commission: r.Commission.Add(o.Commission...),
+	}
+}
+
+// IsZero returns true if no rewards were handed out
+func (r EpochRewards) IsZero() bool {
+	return r.Rewards.IsZero() && r.Commission.IsZero()
+}
+
+// Since returns the rewards handed out between the tracker o and t, the
+// amounts t didn't grow by, withdrawn in between, count as zero.
+func (t Tracker) Since(o Tracker) EpochRewards {
+	return EpochRewards{
+		Rewards:    growth(t.Outstanding, o.Outstanding),
+		Commission: growth(t.Commission, o.Commission),
+	}
+}
+
+// growth returns the amounts of each denom of to above the ones of from
+func growth(to, from sdk.DecCoins) sdk.DecCoins {
+	var grown sdk.DecCoins
+	for _, coin := range to {
+		if amount := coin.Amount.Sub(from.AmountOf(coin.Denom)); amount.IsPositive() {
+			grown = grown.Add(sdk.NewDecCoinFromDec(coin.Denom, amount))
+		}
+	}
+	return grown
+}
@@ -0,0 +1,42 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/rewardsnapshot/types"
+)
+
+func TestTrackerSince(t *testing.T) {
+	last := types.Tracker{
+		Outstanding: sdk.NewDecCoins(sdk.NewInt64DecCoin("utac", 100), sdk.NewInt64DecCoin("uusdt", 10)),
+		Commission:  sdk.NewDecCoins(sdk.NewInt64DecCoin("utac", 10)),
+	}
+	current := types.Tracker{
+		// the uusdt rewards were withdrawn and uatom ones handed out
+		Outstanding: sdk.NewDecCoins(sdk.NewInt64DecCoin("uatom", 5), sdk.NewInt64DecCoin("utac", 150)),
+		Commission:  sdk.NewDecCoins(sdk.NewInt64DecCoin("utac", 15)),
+	}
+	require.Equal(t, types.EpochRewards{
+		Rewards:    sdk.NewDecCoins(sdk.NewInt64DecCoin("uatom", 5), sdk.NewInt64DecCoin("utac", 50)),
+		Commission: sdk.NewDecCoins(sdk.NewInt64DecCoin("utac", 5)),
+	}, current.Since(last))
+
+	// the commission was withdrawn and nothing was handed out
+	withdrawn := types.Tracker{Outstanding: current.Outstanding}
+	require.True(t, withdrawn.Since(current).IsZero())
+	require.True(t, current.Since(current).IsZero())
+
+	sum := current.Since(last).Add(types.EpochRewards{Rewards: sdk.NewDecCoins(sdk.NewInt64DecCoin("utac", 1))})
+	require.Equal(t, sdk.NewDecCoins(sdk.NewInt64DecCoin("uatom", 5), sdk.NewInt64DecCoin("utac", 51)), sum.Rewards)
+}
+
+func TestParamsValidate(t *testing.T) {
+	require.NoError(t, types.DefaultParams().Validate())
+	require.NoError(t, types.Params{Retention: 1}.Validate(), "disabled")
+	require.False(t, types.Params{Retention: 1}.Enabled())
+	require.ErrorContains(t, types.Params{EpochLength: 10}.Validate(), "retention must be at least one epoch")
+}
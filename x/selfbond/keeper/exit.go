@@ -0,0 +1,80 @@
+package keeper
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/Asphere-xyz/tacchain/x/selfbond/types"
+)
+
+// HandleExitCall executes an exit call sent by sender to the exit address
+func (k Keeper) HandleExitCall(ctx sdk.Context, sender common.Address, data []byte) error {
+	if _, err := types.ParseExitCall(data); err != nil {
+		return err
+	}
+	return k.Exit(ctx, sdk.ValAddress(sender.Bytes()))
+}
+
+// Exit exits the validator valAddr at the request of its operator: the whole
+// self bond is undelegated, the validator is jailed and queued for the end
+// blocker to unbond the other delegations to it. Exits are only enabled with a
+// min self bond, see types.Params.
+func (k Keeper) Exit(ctx sdk.Context, valAddr sdk.ValAddress) error {
+	if !k.GetParams(ctx).ExitEnabled() {
+		return errorsmod.Wrap(types.ErrExitDisabled, "no min self bond is set")
+	}
+	validator, err := k.stakingKeeper.GetValidator(ctx, valAddr)
+	if errors.Is(err, stakingtypes.ErrNoValidatorFound) {
+		return errorsmod.Wrapf(types.ErrInvalidExit, "%s operates no validator", sdk.AccAddress(valAddr))
+	}
+	if err != nil {
+		return err
+	}
+	if k.IsQueuedForExit(ctx, valAddr) {
+		return errorsmod.Wrapf(types.ErrInvalidExit, "validator %s is already exiting", valAddr)
+	}
+
+	selfBond := sdkmath.ZeroInt()
+	delegation, err := k.stakingKeeper.GetDelegation(ctx, sdk.AccAddress(valAddr), valAddr)
+	switch {
+	case err == nil:
+		if _, selfBond, err = k.stakingKeeper.Undelegate(ctx, sdk.AccAddress(valAddr), valAddr, delegation.Shares); err != nil {
+			return err
+		}
+	case !errors.Is(err, stakingtypes.ErrNoDelegation):
+		return err
+	}
+
+	// x/staking jails a validator whose self bond falls below its own min
+	// self delegation, the others are jailed here
+	validator, err = k.stakingKeeper.GetValidator(ctx, valAddr)
+	if errors.Is(err, stakingtypes.ErrNoValidatorFound) {
+		// the self delegation was the last one, x/staking removed the validator
+		return ctx.EventManager().EmitTypedEvent(&types.EventExitValidator{Validator: valAddr.String(), SelfBond: selfBond.String()})
+	}
+	if err != nil {
+		return err
+	}
+	if !validator.IsJailed() {
+		consAddr, err := validator.GetConsAddr()
+		if err != nil {
+			return err
+		}
+		if err := k.stakingKeeper.Jail(ctx, consAddr); err != nil {
+			return err
+		}
+	}
+	if err := k.QueueExit(ctx, valAddr); err != nil {
+		return err
+	}
+
+	k.Logger(ctx).Info("queued validator for exit", "validator", valAddr.String(), "self_bond", selfBond)
+	return ctx.EventManager().EmitTypedEvent(&types.EventExitValidator{Validator: valAddr.String(), SelfBond: selfBond.String()})
+}
@@ -0,0 +1,61 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/Asphere-xyz/tacchain/x/selfbond/types"
+)
+
+// InitGenesis initializes the selfbond module state from a genesis state
+func (k Keeper) InitGenesis(ctx sdk.Context, gs types.GenesisState) {
+	k.SetParams(ctx, gs.Params)
+	store := k.storeService.OpenKVStore(ctx)
+	for _, exiting := range gs.ExitQueue {
+		valAddr, err := sdk.ValAddressFromBech32(exiting.Validator)
+		if err != nil {
+			panic(err)
+		}
+		cursor := exiting.Cursor
+		if cursor == nil {
+			cursor = []byte{}
+		}
+		if err := store.Set(types.ExitQueueKey(valAddr), cursor); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// ValidateGenesisValidators checks the validator set the chain starts with
+// against max_validators and the max genesis power share. It must run once
+// the genesis of every module is initialized, the gentxs included.
+func (k Keeper) ValidateGenesisValidators(ctx sdk.Context) error {
+	maxValidators, err := k.stakingKeeper.MaxValidators(ctx)
+	if err != nil {
+		return err
+	}
+	powerReduction := k.stakingKeeper.PowerReduction(ctx)
+
+	var validators []types.GenesisValidator
+	err = k.stakingKeeper.IterateValidators(ctx, func(_ int64, validator stakingtypes.ValidatorI) bool {
+		if !validator.IsJailed() {
+			validators = append(validators, types.GenesisValidator{
+				Operator: validator.GetOperator(),
+				Power:    sdk.TokensToConsensusPower(validator.GetTokens(), powerReduction),
+			})
+		}
+		return false
+	})
+	if err != nil {
+		return err
+	}
+	return k.GetParams(ctx).ValidateGenesisValidators(validators, maxValidators)
+}
+
+// ExportGenesis returns the selfbond module genesis state
+func (k Keeper) ExportGenesis(ctx sdk.Context) *types.GenesisState {
+	return &types.GenesisState{
+		Params:    k.GetParams(ctx),
+		ExitQueue: k.ExitQueue(ctx, 0),
+	}
+}
@@ -0,0 +1,54 @@
+package keeper
+
+import (
+	"context"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/Asphere-xyz/tacchain/x/selfbond/types"
+)
+
+// Hooks take the removed validators out of the exit queue
+type Hooks struct {
+	k Keeper
+}
+
+var _ stakingtypes.StakingHooks = Hooks{}
+
+// Hooks returns the staking hooks of the selfbond module
+func (k Keeper) Hooks() Hooks {
+	return Hooks{k: k}
+}
+
+// AfterValidatorRemoved takes the removed validator out of the exit queue
+func (h Hooks) AfterValidatorRemoved(ctx context.Context, _ sdk.ConsAddress, valAddr sdk.ValAddress) error {
+	return h.k.storeService.OpenKVStore(ctx).Delete(types.ExitQueueKey(valAddr))
+}
+
+func (Hooks) AfterValidatorCreated(context.Context, sdk.ValAddress) error   { return nil }
+func (Hooks) BeforeValidatorModified(context.Context, sdk.ValAddress) error { return nil }
+func (Hooks) AfterValidatorBonded(context.Context, sdk.ConsAddress, sdk.ValAddress) error {
+	return nil
+}
+func (Hooks) AfterValidatorBeginUnbonding(context.Context, sdk.ConsAddress, sdk.ValAddress) error {
+	return nil
+}
+func (Hooks) BeforeDelegationCreated(context.Context, sdk.AccAddress, sdk.ValAddress) error {
+	return nil
+}
+func (Hooks) BeforeDelegationSharesModified(context.Context, sdk.AccAddress, sdk.ValAddress) error {
+	return nil
+}
+func (Hooks) AfterDelegationModified(context.Context, sdk.AccAddress, sdk.ValAddress) error {
+	return nil
+}
+func (Hooks) BeforeValidatorSlashed(context.Context, sdk.ValAddress, sdkmath.LegacyDec) error {
+	return nil
+}
+func (Hooks) BeforeDelegationRemoved(context.Context, sdk.AccAddress, sdk.ValAddress) error {
+	return nil
+}
+func (Hooks) AfterUnbondingInitiated(context.Context, uint64) error { return nil }
@@ -0,0 +1,289 @@
+package keeper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	corestoretypes "cosmossdk.io/core/store"
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/Asphere-xyz/tacchain/x/selfbond/types"
+)
+
+// Keeper of the selfbond module
+type Keeper struct {
+	storeService   corestoretypes.KVStoreService
+	paramSpace     paramtypes.Subspace
+	stakingKeeper  types.StakingKeeper
+	stakingQuerier types.StakingQuerier
+}
+
+// NewKeeper creates a new selfbond Keeper instance
+func NewKeeper(
+	storeService corestoretypes.KVStoreService,
+	paramSpace paramtypes.Subspace,
+	stakingKeeper types.StakingKeeper,
+	stakingQuerier types.StakingQuerier,
+) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		storeService:   storeService,
+		paramSpace:     paramSpace,
+		stakingKeeper:  stakingKeeper,
+		stakingQuerier: stakingQuerier,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current selfbond module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the selfbond module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// SelfBond returns the tokens the operator of validator delegates to it
+func (k Keeper) SelfBond(ctx sdk.Context, validator stakingtypes.ValidatorI) (sdkmath.Int, error) {
+	valAddr, err := sdk.ValAddressFromBech32(validator.GetOperator())
+	if err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+	delegation, err := k.stakingKeeper.GetDelegation(ctx, sdk.AccAddress(valAddr), valAddr)
+	if errors.Is(err, stakingtypes.ErrNoDelegation) {
+		return sdkmath.ZeroInt(), nil
+	}
+	if err != nil {
+		return sdkmath.ZeroInt(), err
+	}
+	return validator.TokensFromShares(delegation.Shares).TruncateInt(), nil
+}
+
+// EndBlocker jails the bonded validators below the min self bond and unbonds
+// the delegations to the validators in the exit queue, which their operator
+// exited with an exit call, see Exit. It must run before the x/staking end
+// blocker, which takes jailed validators out of the validator set.
+func (k Keeper) EndBlocker(ctx sdk.Context) error {
+	if err := k.JailBelowMinSelfBond(ctx); err != nil {
+		return err
+	}
+	_, err := k.ProcessExitQueue(ctx, types.MaxExitUnbondingsPerBlock)
+	return err
+}
+
+// JailBelowMinSelfBond jails the bonded validators whose self bond is below
+// the min self bond. Only the validators of the validator set are checked,
+// the others can't unjail below the min self bond.
+func (k Keeper) JailBelowMinSelfBond(ctx sdk.Context) error {
+	params := k.GetParams(ctx)
+	if !params.MinSelfBond.IsPositive() {
+		return nil
+	}
+
+	type belowMinValidator struct {
+		validator stakingtypes.ValidatorI
+		selfBond  sdkmath.Int
+	}
+	var belowMin []belowMinValidator
+	err := k.stakingKeeper.IterateBondedValidatorsByPower(ctx, func(_ int64, validator stakingtypes.ValidatorI) bool {
+		if validator.IsJailed() {
+			return false
+		}
+		selfBond, err := k.SelfBond(ctx, validator)
+		if err != nil {
+			k.Logger(ctx).Error("failed to get self bond", "validator", validator.GetOperator(), "error", err)
+			return false
+		}
+		if params.IsBelowMinSelfBond(selfBond) {
+			belowMin = append(belowMin, belowMinValidator{validator, selfBond})
+		}
+		return false
+	})
+	if err != nil {
+		return err
+	}
+
+	// validators are jailed once the iteration is over, jailing updates the
+	// validator store iterated
+	for _, v := range belowMin {
+		if err := k.jailBelowMinSelfBond(ctx, v.validator, v.selfBond, params.MinSelfBond); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k Keeper) jailBelowMinSelfBond(ctx sdk.Context, validator stakingtypes.ValidatorI, selfBond, minSelfBond sdkmath.Int) error {
+	consAddr, err := validator.GetConsAddr()
+	if err != nil {
+		return err
+	}
+	if err := k.stakingKeeper.Jail(ctx, consAddr); err != nil {
+		return err
+	}
+
+	k.Logger(ctx).Info("jailed validator below the min self bond", "validator", validator.GetOperator(), "self_bond", selfBond)
+	return ctx.EventManager().EmitTypedEvent(&types.EventJailBelowMinSelfBond{
+		Validator:   validator.GetOperator(),
+		SelfBond:    selfBond.String(),
+		MinSelfBond: minSelfBond.String(),
+	})
+}
+
+// QueueExit queues validator for exit, keeping the cursor of a validator
+// already queued. Validators are queued by Exit and by the genesis.
+func (k Keeper) QueueExit(ctx context.Context, validator sdk.ValAddress) error {
+	store := k.storeService.OpenKVStore(ctx)
+	queued, err := store.Has(types.ExitQueueKey(validator))
+	if err != nil || queued {
+		return err
+	}
+	return store.Set(types.ExitQueueKey(validator), []byte{})
+}
+
+// IsQueuedForExit returns true if validator is in the exit queue
+func (k Keeper) IsQueuedForExit(ctx context.Context, validator sdk.ValAddress) bool {
+	queued, err := k.storeService.OpenKVStore(ctx).Has(types.ExitQueueKey(validator))
+	if err != nil {
+		panic(err)
+	}
+	return queued
+}
+
+// ExitQueue returns at most limit validators of the exit queue with their
+// cursor, all of them if limit is zero
+func (k Keeper) ExitQueue(ctx context.Context, limit int) []types.ExitingValidator {
+	store := k.storeService.OpenKVStore(ctx)
+	iterator, err := store.Iterator(types.ExitQueuePrefix, storetypes.PrefixEndBytes(types.ExitQueuePrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	var queue []types.ExitingValidator
+	for ; iterator.Valid() && (limit <= 0 || len(queue) < limit); iterator.Next() {
+		queue = append(queue, types.ExitingValidator{
+			Validator: sdk.ValAddress(iterator.Key()[len(types.ExitQueuePrefix):]).String(),
+			Cursor:    bytes.Clone(iterator.Value()),
+		})
+	}
+	return queue
+}
+
+// ProcessExitQueue unbonds at most limit delegations to the validators in the
+// exit queue, in the order of the queue. It returns the number of delegations
+// processed. Nothing is unbonded while exits are disabled, the queue resumes
+// once governance enables them again.
+func (k Keeper) ProcessExitQueue(ctx sdk.Context, limit int) (int, error) {
+	if !k.GetParams(ctx).ExitEnabled() {
+		return 0, nil
+	}
+	processed := 0
+	for _, exiting := range k.ExitQueue(ctx, limit) {
+		if processed >= limit {
+			break
+		}
+		valAddr, err := sdk.ValAddressFromBech32(exiting.Validator)
+		if err != nil {
+			return processed, err
+		}
+		n, err := k.UnbondExitedValidator(ctx, valAddr, limit-processed)
+		if err != nil {
+			return processed, err
+		}
+		processed += n
+	}
+	return processed, nil
+}
+
+// UnbondExitedValidator unbonds at most limit delegations to a validator in
+// the exit queue, from the cursor it reached in the previous blocks. The
+// delegators get their tokens back after the unbonding period like for any
+// undelegation. A delegation that can't be unbonded, e.g. because the
+// delegator reached the max unbonding entries, is skipped and left to its
+// delegator: every delegation is processed once. The validator leaves the
+// queue once its last delegation is processed, or when its operator bonded
+// again. It returns the number of delegations processed.
+func (k Keeper) UnbondExitedValidator(ctx sdk.Context, valAddr sdk.ValAddress, limit int) (int, error) {
+	store := k.storeService.OpenKVStore(ctx)
+	key := types.ExitQueueKey(valAddr)
+	if limit <= 0 || !k.GetParams(ctx).ExitEnabled() || !k.IsQueuedForExit(ctx, valAddr) {
+		return 0, nil
+	}
+	cursor, err := store.Get(key)
+	if err != nil {
+		return 0, err
+	}
+
+	validator, err := k.stakingKeeper.GetValidator(ctx, valAddr)
+	if errors.Is(err, stakingtypes.ErrNoValidatorFound) {
+		return 0, store.Delete(key)
+	}
+	if err != nil {
+		return 0, err
+	}
+	selfBond, err := k.SelfBond(ctx, validator)
+	if err != nil {
+		return 0, err
+	}
+	if !validator.IsJailed() || selfBond.IsPositive() {
+		return 0, store.Delete(key)
+	}
+
+	res, err := k.stakingQuerier.ValidatorDelegations(ctx, &stakingtypes.QueryValidatorDelegationsRequest{
+		ValidatorAddr: validator.GetOperator(),
+		Pagination:    &query.PageRequest{Key: cursor, Limit: uint64(limit)},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, response := range res.DelegationResponses {
+		delegation := response.Delegation
+		delAddr, err := sdk.AccAddressFromBech32(delegation.DelegatorAddress)
+		if err != nil {
+			return 0, err
+		}
+
+		cacheCtx, write := ctx.CacheContext()
+		completionTime, amount, err := k.stakingKeeper.Undelegate(cacheCtx, delAddr, valAddr, delegation.Shares)
+		if err != nil {
+			k.Logger(ctx).Error("skipped delegation to exited validator", "validator", validator.GetOperator(), "delegator", delegation.DelegatorAddress, "error", err)
+			continue
+		}
+		write()
+
+		if err := ctx.EventManager().EmitTypedEvent(&types.EventExitUnbond{
+			Validator:      validator.GetOperator(),
+			Delegator:      delegation.DelegatorAddress,
+			Amount:         amount.String(),
+			CompletionTime: completionTime.Format(time.RFC3339),
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+		return len(res.DelegationResponses), store.Delete(key)
+	}
+	return len(res.DelegationResponses), store.Set(key, res.Pagination.NextKey)
+}
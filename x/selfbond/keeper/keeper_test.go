@@ -0,0 +1,341 @@
+package keeper_test
+
+import (
+	"testing"
+	"time"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/selfbond/keeper"
+	"github.com/Asphere-xyz/tacchain/x/selfbond/types"
+)
+
+const selfBond = 1000
+
+type testChain struct {
+	t        *testing.T
+	app      *app.TacChainApp
+	ctx      sdk.Context
+	valAddr  sdk.ValAddress
+	operator sdk.AccAddress
+}
+
+// setup returns a chain whose validator operator self delegates selfBond
+// tokens. Like validators created by a transaction, the validator has a min
+// self delegation of 1, so x/staking jails it when its operator undelegates
+// its whole self bond.
+func setup(t *testing.T) *testChain {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := tacApp.NewContext(false).WithChainID(app.DefaultChainID).WithBlockTime(time.Unix(1_700_000_000, 0))
+
+	validators, err := tacApp.StakingKeeper.GetAllValidators(ctx)
+	require.NoError(t, err)
+	validator := validators[0]
+	validator.MinSelfDelegation = sdkmath.OneInt()
+	require.NoError(t, tacApp.StakingKeeper.SetValidator(ctx, validator))
+	valAddr, err := sdk.ValAddressFromBech32(validator.OperatorAddress)
+	require.NoError(t, err)
+
+	c := &testChain{t: t, app: tacApp, ctx: ctx, valAddr: valAddr, operator: sdk.AccAddress(valAddr)}
+	c.delegate(c.operator, selfBond)
+	return c
+}
+
+func (c *testChain) validator() stakingtypes.Validator {
+	validator, err := c.app.StakingKeeper.GetValidator(c.ctx, c.valAddr)
+	require.NoError(c.t, err)
+	return validator
+}
+
+// delegate funds delegator and delegates amount to the validator
+func (c *testChain) delegate(delegator sdk.AccAddress, amount int64) {
+	coins := sdk.NewCoins(sdk.NewCoin(app.BaseDenom, sdkmath.NewInt(amount)))
+	require.NoError(c.t, c.app.BankKeeper.MintCoins(c.ctx, minttypes.ModuleName, coins))
+	require.NoError(c.t, c.app.BankKeeper.SendCoinsFromModuleToAccount(c.ctx, minttypes.ModuleName, delegator, coins))
+	_, err := c.app.StakingKeeper.Delegate(c.ctx, delegator, sdkmath.NewInt(amount), stakingtypes.Unbonded, c.validator(), true)
+	require.NoError(c.t, err)
+}
+
+// undelegate undelegates the shares worth amount tokens of delegator, or all
+// its shares if amount is zero
+func (c *testChain) undelegate(delegator sdk.AccAddress, amount int64) {
+	delegation, err := c.app.StakingKeeper.GetDelegation(c.ctx, delegator, c.valAddr)
+	require.NoError(c.t, err)
+	shares := delegation.Shares
+	if amount > 0 {
+		shares, err = c.validator().SharesFromTokens(sdkmath.NewInt(amount))
+		require.NoError(c.t, err)
+	}
+	_, _, err = c.app.StakingKeeper.Undelegate(c.ctx, delegator, c.valAddr, shares)
+	require.NoError(c.t, err)
+}
+
+func (c *testChain) setMinSelfBond(amount int64) {
+	params := types.DefaultParams()
+	params.MinSelfBond = sdkmath.NewInt(amount)
+	require.NoError(c.t, params.Validate())
+	c.app.SelfBondKeeper.SetParams(c.ctx, params)
+}
+
+// exit sends the exit call of the operator
+func (c *testChain) exit() error {
+	data, err := types.ExitCall{Method: types.MethodExit}.Pack()
+	require.NoError(c.t, err)
+	return c.app.SelfBondKeeper.HandleExitCall(c.ctx, common.BytesToAddress(c.operator), data)
+}
+
+// endBlock runs the end blocker and returns the typed events it emitted
+func (c *testChain) endBlock() []proto.Message {
+	c.ctx = c.ctx.WithEventManager(sdk.NewEventManager())
+	require.NoError(c.t, c.app.SelfBondKeeper.EndBlocker(c.ctx))
+
+	var events []proto.Message
+	for _, event := range c.ctx.EventManager().ABCIEvents() {
+		if event.Type != proto.MessageName(&types.EventJailBelowMinSelfBond{}) && event.Type != proto.MessageName(&types.EventExitUnbond{}) {
+			continue
+		}
+		msg, err := sdk.ParseTypedEvent(event)
+		require.NoError(c.t, err)
+		events = append(events, msg)
+	}
+	return events
+}
+
+func TestJailBelowMinSelfBond(t *testing.T) {
+	c := setup(t)
+
+	selfBondAmount, err := c.app.SelfBondKeeper.SelfBond(c.ctx, c.validator())
+	require.NoError(t, err)
+	require.Equal(t, int64(selfBond), selfBondAmount.Int64())
+
+	// the default params don't jail anyone
+	c.undelegate(c.operator, 1)
+	require.Empty(t, c.endBlock())
+	require.False(t, c.validator().IsJailed())
+
+	c.setMinSelfBond(selfBond)
+	events := c.endBlock()
+	require.Len(t, events, 1)
+	event := events[0].(*types.EventJailBelowMinSelfBond)
+	require.Equal(t, c.valAddr.String(), event.Validator)
+	require.Equal(t, "999", event.SelfBond)
+	require.Equal(t, "1000", event.MinSelfBond)
+	require.True(t, c.validator().IsJailed())
+
+	// a jailed validator with a self bond didn't exit, its delegations stay
+	require.Empty(t, c.endBlock())
+	delegations, err := c.app.StakingKeeper.GetValidatorDelegations(c.ctx, c.valAddr)
+	require.NoError(t, err)
+	require.Len(t, delegations, 2)
+
+	// the validator can't unjail below the min self bond, even above its own
+	// min self delegation
+	unjailKeeper := keeper.NewUnjailStakingKeeper(c.app.StakingKeeper, c.app.SelfBondKeeper)
+	consAddr, err := c.validator().GetConsAddr()
+	require.NoError(t, err)
+	require.ErrorIs(t, unjailKeeper.Unjail(c.ctx, consAddr), types.ErrBelowMinSelfBond)
+	require.True(t, c.validator().IsJailed())
+
+	// once the operator delegated enough the validator can unjail and stays unjailed
+	c.delegate(c.operator, 1)
+	require.NoError(t, unjailKeeper.Unjail(c.ctx, consAddr))
+	require.Empty(t, c.endBlock())
+	require.False(t, c.validator().IsJailed())
+}
+
+func TestExitUnbondsAllDelegations(t *testing.T) {
+	c := setup(t)
+	delegators := []sdk.AccAddress{
+		sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()),
+		sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()),
+	}
+	for _, delegator := range delegators {
+		c.delegate(delegator, 500)
+	}
+	initial, err := c.app.StakingKeeper.GetValidatorDelegations(c.ctx, c.valAddr)
+	require.NoError(t, err)
+	require.Len(t, initial, 4)
+
+	// the operator exits, its whole self bond is undelegated, the validator
+	// is jailed and queued for exit
+	c.setMinSelfBond(selfBond)
+	require.False(t, c.app.SelfBondKeeper.IsQueuedForExit(c.ctx, c.valAddr))
+	require.NoError(t, c.exit())
+	require.True(t, c.validator().IsJailed())
+	require.True(t, c.app.SelfBondKeeper.IsQueuedForExit(c.ctx, c.valAddr))
+	_, err = c.app.StakingKeeper.GetDelegation(c.ctx, c.operator, c.valAddr)
+	require.ErrorIs(t, err, stakingtypes.ErrNoDelegation)
+	require.ErrorIs(t, c.exit(), types.ErrInvalidExit)
+
+	// the delegations are unbonded over the blocks when limited, from the
+	// cursor the validator reached
+	unbonded, err := c.app.SelfBondKeeper.UnbondExitedValidator(c.ctx, c.valAddr, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, unbonded)
+	remaining, err := c.app.StakingKeeper.GetValidatorDelegations(c.ctx, c.valAddr)
+	require.NoError(t, err)
+	require.Len(t, remaining, 2)
+	queue := c.app.SelfBondKeeper.ExitQueue(c.ctx, 0)
+	require.Len(t, queue, 1)
+	require.Equal(t, c.valAddr.String(), queue[0].Validator)
+	require.NotEmpty(t, queue[0].Cursor)
+
+	events := c.endBlock()
+	require.Len(t, events, 2)
+	for _, msg := range events {
+		event := msg.(*types.EventExitUnbond)
+		require.Equal(t, c.valAddr.String(), event.Validator)
+		require.NotEmpty(t, event.CompletionTime)
+	}
+	remaining, err = c.app.StakingKeeper.GetValidatorDelegations(c.ctx, c.valAddr)
+	require.NoError(t, err)
+	require.Empty(t, remaining)
+	require.True(t, c.validator().DelegatorShares.IsZero())
+
+	// every delegator gets its tokens back after the unbonding period
+	for _, delegator := range delegators {
+		ubd, err := c.app.StakingKeeper.GetUnbondingDelegation(c.ctx, delegator, c.valAddr)
+		require.NoError(t, err)
+		require.Len(t, ubd.Entries, 1)
+		require.Equal(t, "500", ubd.Entries[0].Balance.String())
+	}
+
+	// nothing is left to unbond, the validator left the queue
+	require.False(t, c.app.SelfBondKeeper.IsQueuedForExit(c.ctx, c.valAddr))
+	require.Empty(t, c.endBlock())
+}
+
+func TestExitSkipsFailedUnbondings(t *testing.T) {
+	c := setup(t)
+	delegators := []sdk.AccAddress{
+		sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()),
+		sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()),
+	}
+	for _, delegator := range delegators {
+		c.delegate(delegator, 500)
+	}
+	// the first delegator reached the max unbonding entries, it can't be
+	// unbonded. The entries of a block are merged, each is in its own block.
+	stakingParams, err := c.app.StakingKeeper.GetParams(c.ctx)
+	require.NoError(t, err)
+	for i := uint32(0); i < stakingParams.MaxEntries; i++ {
+		c.ctx = c.ctx.WithBlockHeight(c.ctx.BlockHeight() + 1)
+		c.undelegate(delegators[0], 1)
+	}
+
+	c.setMinSelfBond(selfBond)
+	require.NoError(t, c.exit())
+	events := c.endBlock()
+	require.Len(t, events, 2)
+	for _, msg := range events {
+		require.NotEqual(t, delegators[0].String(), msg.(*types.EventExitUnbond).Delegator)
+	}
+
+	// the delegation that failed is left to its delegator, it isn't retried
+	require.False(t, c.app.SelfBondKeeper.IsQueuedForExit(c.ctx, c.valAddr))
+	require.Empty(t, c.endBlock())
+	remaining, err := c.app.StakingKeeper.GetValidatorDelegations(c.ctx, c.valAddr)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	require.Equal(t, delegators[0].String(), remaining[0].DelegatorAddress)
+}
+
+func TestExitOperatorBondsAgain(t *testing.T) {
+	c := setup(t)
+	c.setMinSelfBond(selfBond)
+	require.NoError(t, c.exit())
+	require.True(t, c.app.SelfBondKeeper.IsQueuedForExit(c.ctx, c.valAddr))
+
+	// the operator bonded again before the delegations were unbonded
+	c.delegate(c.operator, selfBond)
+	require.Empty(t, c.endBlock())
+	require.False(t, c.app.SelfBondKeeper.IsQueuedForExit(c.ctx, c.valAddr))
+	delegations, err := c.app.StakingKeeper.GetValidatorDelegations(c.ctx, c.valAddr)
+	require.NoError(t, err)
+	require.Len(t, delegations, 2)
+}
+
+func TestExitDisabled(t *testing.T) {
+	c := setup(t)
+
+	// the default params don't enable exits
+	require.ErrorIs(t, c.exit(), types.ErrExitDisabled)
+	require.False(t, c.validator().IsJailed())
+
+	// undelegating the self bond doesn't exit the validator, x/staking jails
+	// it but its delegations stay
+	c.setMinSelfBond(selfBond)
+	c.undelegate(c.operator, 0)
+	require.True(t, c.validator().IsJailed())
+	require.False(t, c.app.SelfBondKeeper.IsQueuedForExit(c.ctx, c.valAddr))
+	require.Empty(t, c.endBlock())
+	delegations, err := c.app.StakingKeeper.GetValidatorDelegations(c.ctx, c.valAddr)
+	require.NoError(t, err)
+	require.Len(t, delegations, 1)
+
+	// only the operator of a validator exits
+	data, err := types.ExitCall{Method: types.MethodExit}.Pack()
+	require.NoError(t, err)
+	stranger := common.BytesToAddress(secp256k1.GenPrivKey().PubKey().Address())
+	require.ErrorIs(t, c.app.SelfBondKeeper.HandleExitCall(c.ctx, stranger, data), types.ErrInvalidExit)
+	require.ErrorIs(t, c.app.SelfBondKeeper.HandleExitCall(c.ctx, common.BytesToAddress(c.operator), []byte{0x01}), types.ErrInvalidExitCall)
+}
+
+func TestValidateGenesisValidators(t *testing.T) {
+	c := setup(t)
+
+	// a second validator with 3 times the power of the first one
+	valAddr := sdk.ValAddress(secp256k1.GenPrivKey().PubKey().Address())
+	validator, err := stakingtypes.NewValidator(valAddr.String(), secp256k1.GenPrivKey().PubKey(), stakingtypes.Description{})
+	require.NoError(t, err)
+	validator.Tokens = app.PowerReduction.MulRaw(3)
+	require.NoError(t, c.app.StakingKeeper.SetValidator(c.ctx, validator))
+
+	// the default params only check max_validators
+	require.NoError(t, c.app.SelfBondKeeper.ValidateGenesisValidators(c.ctx))
+	stakingParams, err := c.app.StakingKeeper.GetParams(c.ctx)
+	require.NoError(t, err)
+	stakingParams.MaxValidators = 1
+	require.NoError(t, c.app.StakingKeeper.SetParams(c.ctx, stakingParams))
+	require.ErrorIs(t, c.app.SelfBondKeeper.ValidateGenesisValidators(c.ctx), types.ErrTooManyGenesisValidators)
+	stakingParams.MaxValidators = 2
+	require.NoError(t, c.app.StakingKeeper.SetParams(c.ctx, stakingParams))
+
+	params := types.DefaultParams()
+	params.MaxGenesisPowerShare = sdkmath.LegacyNewDecWithPrec(75, 2)
+	c.app.SelfBondKeeper.SetParams(c.ctx, params)
+	require.NoError(t, c.app.SelfBondKeeper.ValidateGenesisValidators(c.ctx))
+
+	params.MaxGenesisPowerShare = sdkmath.LegacyNewDecWithPrec(7, 1)
+	c.app.SelfBondKeeper.SetParams(c.ctx, params)
+	err = c.app.SelfBondKeeper.ValidateGenesisValidators(c.ctx)
+	require.ErrorIs(t, err, types.ErrGenesisPowerShare)
+	require.ErrorContains(t, err, valAddr.String())
+
+	// jailed validators aren't part of the validator set
+	validator.Jailed = true
+	require.NoError(t, c.app.StakingKeeper.SetValidator(c.ctx, validator))
+	c.app.SelfBondKeeper.SetParams(c.ctx, types.DefaultParams())
+	stakingParams.MaxValidators = 1
+	require.NoError(t, c.app.StakingKeeper.SetParams(c.ctx, stakingParams))
+	require.NoError(t, c.app.SelfBondKeeper.ValidateGenesisValidators(c.ctx))
+}
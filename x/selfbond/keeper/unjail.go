@@ -0,0 +1,49 @@
+package keeper
+
+import (
+	"context"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+
+	"github.com/Asphere-xyz/tacchain/x/selfbond/types"
+)
+
+var _ slashingtypes.StakingKeeper = UnjailStakingKeeper{}
+
+// UnjailStakingKeeper is the staking keeper of the slashing keeper. x/slashing
+// lets a validator unjail once its self bond reaches its own min self
+// delegation, the validator would then be jailed again at the end of the
+// block if it is below the min self bond. Unjail checks the min self bond
+// too, for MsgUnjail whether it is sent directly, through authz or by a
+// precompile.
+type UnjailStakingKeeper struct {
+	slashingtypes.StakingKeeper
+
+	keeper Keeper
+}
+
+// NewUnjailStakingKeeper wraps the staking keeper of the slashing keeper
+func NewUnjailStakingKeeper(stakingKeeper slashingtypes.StakingKeeper, keeper Keeper) UnjailStakingKeeper {
+	return UnjailStakingKeeper{StakingKeeper: stakingKeeper, keeper: keeper}
+}
+
+// Unjail implements slashingtypes.StakingKeeper. It fails if the self bond of
+// the validator is below the min self bond.
+func (k UnjailStakingKeeper) Unjail(ctx context.Context, consAddr sdk.ConsAddress) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	validator, err := k.StakingKeeper.ValidatorByConsAddr(ctx, consAddr)
+	if err != nil {
+		return err
+	}
+	selfBond, err := k.keeper.SelfBond(sdkCtx, validator)
+	if err != nil {
+		return err
+	}
+	if params := k.keeper.GetParams(sdkCtx); params.IsBelowMinSelfBond(selfBond) {
+		return errorsmod.Wrapf(types.ErrBelowMinSelfBond, "validator %s self bonds %s, the min self bond is %s", validator.GetOperator(), selfBond, params.MinSelfBond)
+	}
+	return k.StakingKeeper.Unjail(ctx, consAddr)
+}
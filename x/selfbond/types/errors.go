@@ -0,0 +1,15 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+)
+
+// x/selfbond module sentinel errors
+var (
+	ErrTooManyGenesisValidators = errorsmod.Register(ModuleName, 2, "genesis validator set larger than max_validators")
+	ErrGenesisPowerShare        = errorsmod.Register(ModuleName, 3, "genesis validator above the max genesis power share")
+	ErrBelowMinSelfBond         = errorsmod.Register(ModuleName, 4, "self bond below the min self bond")
+	ErrInvalidExitCall          = errorsmod.Register(ModuleName, 5, "invalid exit call")
+	ErrExitDisabled             = errorsmod.Register(ModuleName, 6, "validator exits are disabled")
+	ErrInvalidExit              = errorsmod.Register(ModuleName, 7, "invalid validator exit")
+)
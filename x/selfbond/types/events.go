@@ -0,0 +1,49 @@
+package types
+
+import (
+	"github.com/cosmos/gogoproto/proto"
+)
+
+// init registers the events of the validators jailed below their min self
+// bond and of the validator exits, with the delegations they unbonded.
+func init() {
+	proto.RegisterType((*EventJailBelowMinSelfBond)(nil), "tacchain.selfbond.v1.EventJailBelowMinSelfBond")
+	proto.RegisterType((*EventExitValidator)(nil), "tacchain.selfbond.v1.EventExitValidator")
+	proto.RegisterType((*EventExitUnbond)(nil), "tacchain.selfbond.v1.EventExitUnbond")
+}
+
+// EventJailBelowMinSelfBond is emitted when a validator was jailed because its
+// self bond fell below the min self bond.
+type EventJailBelowMinSelfBond struct {
+	Validator   string `protobuf:"bytes,1,opt,name=validator,proto3" json:"validator,omitempty"`
+	SelfBond    string `protobuf:"bytes,2,opt,name=self_bond,json=selfBond,proto3" json:"self_bond,omitempty"`
+	MinSelfBond string `protobuf:"bytes,3,opt,name=min_self_bond,json=minSelfBond,proto3" json:"min_self_bond,omitempty"`
+}
+
+func (m *EventJailBelowMinSelfBond) Reset()         { *m = EventJailBelowMinSelfBond{} }
+func (m *EventJailBelowMinSelfBond) String() string { return proto.CompactTextString(m) }
+func (*EventJailBelowMinSelfBond) ProtoMessage()    {}
+
+// EventExitValidator is emitted when the operator of a validator exited it,
+// undelegating its self bond.
+type EventExitValidator struct {
+	Validator string `protobuf:"bytes,1,opt,name=validator,proto3" json:"validator,omitempty"`
+	SelfBond  string `protobuf:"bytes,2,opt,name=self_bond,json=selfBond,proto3" json:"self_bond,omitempty"`
+}
+
+func (m *EventExitValidator) Reset()         { *m = EventExitValidator{} }
+func (m *EventExitValidator) String() string { return proto.CompactTextString(m) }
+func (*EventExitValidator) ProtoMessage()    {}
+
+// EventExitUnbond is emitted when a delegation to a validator that exited was
+// unbonded.
+type EventExitUnbond struct {
+	Validator      string `protobuf:"bytes,1,opt,name=validator,proto3" json:"validator,omitempty"`
+	Delegator      string `protobuf:"bytes,2,opt,name=delegator,proto3" json:"delegator,omitempty"`
+	Amount         string `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	CompletionTime string `protobuf:"bytes,4,opt,name=completion_time,json=completionTime,proto3" json:"completion_time,omitempty"`
+}
+
+func (m *EventExitUnbond) Reset()         { *m = EventExitUnbond{} }
+func (m *EventExitUnbond) String() string { return proto.CompactTextString(m) }
+func (*EventExitUnbond) ProtoMessage()    {}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+
+	"github.com/cosmos/cosmos-sdk/types/address"
+)
+
+// MethodExit exits the validator operated by the sender
+const MethodExit = "exit"
+
+// exitABI is the interface of the calls sent to ExitAddress
+const exitABI = `[
+	{"type":"function","name":"exit","stateMutability":"nonpayable","outputs":[],"inputs":[]}
+]`
+
+// ExitABI is the parsed interface of the exit calls
+var ExitABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(exitABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// ExitAddress returns the address validator operators send their exit calls
+// to. It is derived like a module account address, no account lives there
+// and no code runs at it, the calls are handled after the transaction
+// executed.
+func ExitAddress() common.Address {
+	return common.BytesToAddress(address.Module(ModuleName, []byte("exit"))[:common.AddressLength])
+}
+
+// ExitCall is a decoded call to the exit address
+type ExitCall struct {
+	Method string
+}
+
+// ParseExitCall decodes the calldata of an exit call
+func ParseExitCall(data []byte) (ExitCall, error) {
+	if len(data) < 4 {
+		return ExitCall{}, errorsmod.Wrap(ErrInvalidExitCall, "missing method selector")
+	}
+	method, err := ExitABI.MethodById(data[:4])
+	if err != nil {
+		return ExitCall{}, errorsmod.Wrap(ErrInvalidExitCall, err.Error())
+	}
+	if _, err := method.Inputs.Unpack(data[4:]); err != nil {
+		return ExitCall{}, errorsmod.Wrapf(ErrInvalidExitCall, "failed to decode %s arguments: %s", method.Name, err)
+	}
+	return ExitCall{Method: method.Name}, nil
+}
+
+// Pack encodes the call as calldata for the exit address
+func (c ExitCall) Pack() ([]byte, error) {
+	return ExitABI.Pack(c.Method)
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// StakingKeeper defines the expected staking keeper
+type StakingKeeper interface {
+	IterateValidators(ctx context.Context, fn func(index int64, validator stakingtypes.ValidatorI) (stop bool)) error
+	IterateBondedValidatorsByPower(ctx context.Context, fn func(index int64, validator stakingtypes.ValidatorI) (stop bool)) error
+	GetValidator(ctx context.Context, addr sdk.ValAddress) (stakingtypes.Validator, error)
+	GetDelegation(ctx context.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress) (stakingtypes.Delegation, error)
+	Undelegate(ctx context.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress, sharesAmount sdkmath.LegacyDec) (time.Time, sdkmath.Int, error)
+	Jail(ctx context.Context, consAddr sdk.ConsAddress) error
+	MaxValidators(ctx context.Context) (uint32, error)
+	PowerReduction(ctx context.Context) sdkmath.Int
+}
+
+// StakingQuerier defines the expected staking query server, which pages
+// through the delegations to a validator without loading all of them
+type StakingQuerier interface {
+	ValidatorDelegations(ctx context.Context, req *stakingtypes.QueryValidatorDelegationsRequest) (*stakingtypes.QueryValidatorDelegationsResponse, error)
+}
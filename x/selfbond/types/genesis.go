@@ -0,0 +1,46 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GenesisState defines the selfbond module genesis state
+type GenesisState struct {
+	Params    Params             `json:"params" yaml:"params"`
+	ExitQueue []ExitingValidator `json:"exit_queue,omitempty" yaml:"exit_queue,omitempty"`
+}
+
+// ExitingValidator is a validator that exited whose delegations are left to
+// unbond. Cursor is the key of the next delegation to unbond in the
+// delegations of the validator, empty to start from the first one.
+type ExitingValidator struct {
+	Validator string `json:"validator" yaml:"validator"`
+	Cursor    []byte `json:"cursor,omitempty" yaml:"cursor,omitempty"`
+}
+
+// DefaultGenesisState returns the default selfbond module genesis state
+func DefaultGenesisState() *GenesisState {
+	return &GenesisState{
+		Params: DefaultParams(),
+	}
+}
+
+// Validate performs basic genesis state validation
+func (gs GenesisState) Validate() error {
+	if err := gs.Params.Validate(); err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, exiting := range gs.ExitQueue {
+		if _, err := sdk.ValAddressFromBech32(exiting.Validator); err != nil {
+			return fmt.Errorf("invalid exiting validator %s: %w", exiting.Validator, err)
+		}
+		if seen[exiting.Validator] {
+			return fmt.Errorf("duplicate exiting validator %s", exiting.Validator)
+		}
+		seen[exiting.Validator] = true
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+	sdkmath "cosmossdk.io/math"
+)
+
+// GenesisValidator is a validator of the validator set a chain starts with
+type GenesisValidator struct {
+	Operator string
+	Power    int64
+}
+
+// ValidateGenesisValidators fails if the validator set a chain starts with
+// has more than maxValidators validators, whose extra validators x/staking
+// would leave unbonded, or if a validator has a larger share of the voting
+// power than the max genesis power share. Validators without power are left
+// out.
+func (p Params) ValidateGenesisValidators(validators []GenesisValidator, maxValidators uint32) error {
+	var total int64
+	count := uint32(0)
+	for _, validator := range validators {
+		if validator.Power > 0 {
+			total += validator.Power
+			count++
+		}
+	}
+	if count > maxValidators {
+		return errorsmod.Wrapf(ErrTooManyGenesisValidators, "%d validators with voting power, max_validators is %d", count, maxValidators)
+	}
+
+	if !p.MaxGenesisPowerShare.IsPositive() || total == 0 {
+		return nil
+	}
+	for _, validator := range validators {
+		share := sdkmath.LegacyNewDec(validator.Power).QuoInt64(total)
+		if share.GT(p.MaxGenesisPowerShare) {
+			return errorsmod.Wrapf(ErrGenesisPowerShare, "validator %s has %d of the %d voting power (%s), max_genesis_power_share is %s",
+				validator.Operator, validator.Power, total, share, p.MaxGenesisPowerShare)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// ModuleName defines the selfbond module name
+	ModuleName = "selfbond"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+
+	// MaxExitUnbondingsPerBlock bounds the delegations to exited validators
+	// processed in a block, the rest are processed in the following blocks
+	MaxExitUnbondingsPerBlock = 100
+)
+
+var (
+	// ExitQueuePrefix prefixes the validators that exited whose delegations
+	// are left to unbond, each with the cursor of the next delegation
+	ExitQueuePrefix = []byte{0x01}
+)
+
+// ExitQueueKey returns the store key queuing validator for exit
+func ExitQueueKey(validator sdk.ValAddress) []byte {
+	return append(append([]byte{}, ExitQueuePrefix...), validator...)
+}
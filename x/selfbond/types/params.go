@@ -0,0 +1,100 @@
+package types
+
+import (
+	"fmt"
+
+	sdkmath "cosmossdk.io/math"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+var (
+	// KeyMinSelfBond is the param store key for the tokens validators must self-delegate
+	KeyMinSelfBond = []byte("MinSelfBond")
+	// KeyMaxGenesisPowerShare is the param store key for the max share of the
+	// genesis voting power of a validator
+	KeyMaxGenesisPowerShare = []byte("MaxGenesisPowerShare")
+)
+
+// Params defines the selfbond module parameters. Bonded validators whose
+// operator delegates less than MinSelfBond tokens to them are jailed at the
+// end of the block, they can unjail once they delegated enough. Unlike the
+// min self delegation of x/staking, which every validator picks for itself,
+// MinSelfBond applies to all validators. It defaults to zero, which doesn't
+// jail anyone.
+//
+// A non-zero MinSelfBond also enables validator exits: the operator of a
+// validator exits it with a call to ExitAddress, and the module unbonds the
+// delegations to it. Undelegating the self bond alone doesn't exit a
+// validator.
+//
+// MaxGenesisPowerShare caps the share of the voting power of a validator in
+// the validator set the chain starts with, zero doesn't cap it.
+type Params struct {
+	MinSelfBond          sdkmath.Int       `json:"min_self_bond" yaml:"min_self_bond"`
+	MaxGenesisPowerShare sdkmath.LegacyDec `json:"max_genesis_power_share" yaml:"max_genesis_power_share"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the selfbond module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default selfbond module parameters
+func DefaultParams() Params {
+	return Params{
+		MinSelfBond:          sdkmath.ZeroInt(),
+		MaxGenesisPowerShare: sdkmath.LegacyZeroDec(),
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyMinSelfBond, &p.MinSelfBond, validateMinSelfBond),
+		paramtypes.NewParamSetPair(KeyMaxGenesisPowerShare, &p.MaxGenesisPowerShare, validateMaxGenesisPowerShare),
+	}
+}
+
+// Validate performs basic validation of the selfbond module parameters
+func (p Params) Validate() error {
+	if err := validateMinSelfBond(p.MinSelfBond); err != nil {
+		return err
+	}
+	return validateMaxGenesisPowerShare(p.MaxGenesisPowerShare)
+}
+
+// IsBelowMinSelfBond returns true if selfBond is below a non-zero min self bond
+func (p Params) IsBelowMinSelfBond(selfBond sdkmath.Int) bool {
+	return p.MinSelfBond.IsPositive() && selfBond.LT(p.MinSelfBond)
+}
+
+// ExitEnabled returns true if validators can exit, which needs a non-zero
+// min self bond
+func (p Params) ExitEnabled() bool {
+	return p.MinSelfBond.IsPositive()
+}
+
+func validateMinSelfBond(i interface{}) error {
+	v, ok := i.(sdkmath.Int)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v.IsNil() || v.IsNegative() {
+		return fmt.Errorf("min self bond must be non-negative: %s", v)
+	}
+	return nil
+}
+
+func validateMaxGenesisPowerShare(i interface{}) error {
+	v, ok := i.(sdkmath.LegacyDec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v.IsNil() || v.IsNegative() || v.GT(sdkmath.LegacyOneDec()) {
+		return fmt.Errorf("max genesis power share must be between 0 and 1: %s", v)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/Asphere-xyz/tacchain/x/selfbond/types"
+)
+
+func TestParamsValidate(t *testing.T) {
+	require.NoError(t, types.DefaultParams().Validate())
+	require.NoError(t, types.Params{MinSelfBond: sdkmath.NewInt(1000), MaxGenesisPowerShare: sdkmath.LegacyOneDec()}.Validate())
+	require.Error(t, types.Params{MaxGenesisPowerShare: sdkmath.LegacyZeroDec()}.Validate(), "nil min self bond")
+	require.Error(t, types.Params{MinSelfBond: sdkmath.NewInt(-1), MaxGenesisPowerShare: sdkmath.LegacyZeroDec()}.Validate())
+	require.Error(t, types.Params{MinSelfBond: sdkmath.ZeroInt()}.Validate(), "nil max genesis power share")
+	require.Error(t, types.Params{MinSelfBond: sdkmath.ZeroInt(), MaxGenesisPowerShare: sdkmath.LegacyNewDec(-1)}.Validate())
+	require.Error(t, types.Params{MinSelfBond: sdkmath.ZeroInt(), MaxGenesisPowerShare: sdkmath.LegacyNewDecWithPrec(101, 2)}.Validate())
+}
+
+func TestIsBelowMinSelfBond(t *testing.T) {
+	params := types.DefaultParams()
+	require.False(t, params.IsBelowMinSelfBond(sdkmath.ZeroInt()), "a zero min self bond doesn't jail anyone")
+
+	params.MinSelfBond = sdkmath.NewInt(1000)
+	require.True(t, params.IsBelowMinSelfBond(sdkmath.ZeroInt()))
+	require.True(t, params.IsBelowMinSelfBond(sdkmath.NewInt(999)))
+	require.False(t, params.IsBelowMinSelfBond(sdkmath.NewInt(1000)))
+}
+
+func TestValidateGenesisValidators(t *testing.T) {
+	validators := []types.GenesisValidator{
+		{Operator: "val1", Power: 50},
+		{Operator: "val2", Power: 30},
+		{Operator: "val3", Power: 20},
+		{Operator: "val4", Power: 0},
+	}
+	params := types.DefaultParams()
+	require.NoError(t, params.ValidateGenesisValidators(validators, 3), "validators without power don't count")
+	require.ErrorIs(t, params.ValidateGenesisValidators(validators, 2), types.ErrTooManyGenesisValidators)
+
+	params.MaxGenesisPowerShare = sdkmath.LegacyNewDecWithPrec(5, 1)
+	require.NoError(t, params.ValidateGenesisValidators(validators, 3), "a validator may have the max share")
+	params.MaxGenesisPowerShare = sdkmath.LegacyNewDecWithPrec(49, 2)
+	err := params.ValidateGenesisValidators(validators, 3)
+	require.ErrorIs(t, err, types.ErrGenesisPowerShare)
+	require.ErrorContains(t, err, "validator val1 has 50 of the 100 voting power")
+
+	require.NoError(t, params.ValidateGenesisValidators(nil, 3), "empty validator set")
+}
@@ -0,0 +1,27 @@
+package keeper
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+// InitGenesis initializes the tacgov module state from a genesis state
+func (k Keeper) InitGenesis(ctx sdk.Context, gs types.GenesisState) {
+	k.SetParams(ctx, gs.Params)
+	for _, delegation := range gs.VoteDelegations {
+		if err := k.setVoteDelegate(ctx, common.HexToAddress(delegation.Delegator), common.HexToAddress(delegation.Delegate)); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// ExportGenesis returns the tacgov module genesis state
+func (k Keeper) ExportGenesis(ctx sdk.Context) *types.GenesisState {
+	return &types.GenesisState{
+		Params:          k.GetParams(ctx),
+		VoteDelegations: k.GetAllVoteDelegations(ctx),
+	}
+}
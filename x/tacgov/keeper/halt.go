@@ -0,0 +1,32 @@
+package keeper
+
+import (
+	"fmt"
+
+	errorsmod "cosmossdk.io/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+// CheckHalt fails the block at the halt height set by governance, unless the
+// node was started to skip it. Every node fails the same block, so the chain
+// stops there until the operators restart their nodes with the height in
+// --unsafe-skip-halt-heights.
+func (k Keeper) CheckHalt(ctx sdk.Context) error {
+	params := k.GetParams(ctx)
+	height := ctx.BlockHeight()
+	if params.HaltHeight == 0 || uint64(height) != params.HaltHeight {
+		return nil
+	}
+
+	if k.skipHaltHeights[height] {
+		k.Logger(ctx).Info("skipping the chain halt set by governance", "height", height, "reason", params.HaltReason)
+		return nil
+	}
+
+	msg := fmt.Sprintf("CHAIN HALTED BY GOVERNANCE AT HEIGHT %d: %s", height, params.HaltReason)
+	k.Logger(ctx).Error(msg)
+	return errorsmod.Wrap(types.ErrChainHalted, msg)
+}
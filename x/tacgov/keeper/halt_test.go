@@ -0,0 +1,59 @@
+package keeper_test
+
+import (
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/tacgov"
+	"github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+func TestCheckHalt(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.TacGovKeeper
+
+	// no halt is scheduled by default
+	require.NoError(t, k.CheckHalt(ctx.WithBlockHeight(100)))
+
+	params := k.GetParams(ctx)
+	params.HaltHeight = 100
+	params.HaltReason = "bridge exploit"
+	k.SetParams(ctx, params)
+
+	require.NoError(t, k.CheckHalt(ctx.WithBlockHeight(99)))
+	err := k.CheckHalt(ctx.WithBlockHeight(100))
+	require.ErrorIs(t, err, types.ErrChainHalted)
+	require.ErrorContains(t, err, "CHAIN HALTED BY GOVERNANCE AT HEIGHT 100: bridge exploit")
+	// the chain never runs past the halt height, unless the node skips it
+	require.NoError(t, k.CheckHalt(ctx.WithBlockHeight(101)))
+}
+
+func TestCheckHaltSkipped(t *testing.T) {
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger: log.NewTestLogger(t),
+		DB:     dbm.NewMemDB(),
+		AppOpts: simtestutil.AppOptionsMap{
+			flags.FlagHome:                   t.TempDir(),
+			tacgov.FlagUnsafeSkipHaltHeights: []int{100},
+		},
+	})
+	ctx := tacApp.NewContext(false).WithChainID(app.DefaultChainID)
+	k := tacApp.TacGovKeeper
+
+	params := k.GetParams(ctx)
+	params.HaltHeight = 100
+	k.SetParams(ctx, params)
+	require.NoError(t, k.CheckHalt(ctx.WithBlockHeight(100)))
+
+	params.HaltHeight = 200
+	k.SetParams(ctx, params)
+	require.ErrorIs(t, k.CheckHalt(ctx.WithBlockHeight(200)), types.ErrChainHalted)
+}
@@ -0,0 +1,64 @@
+package keeper
+
+import (
+	corestoretypes "cosmossdk.io/core/store"
+	"cosmossdk.io/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govkeeper "github.com/cosmos/cosmos-sdk/x/gov/keeper"
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+
+	"github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+// Keeper of the tacgov module
+type Keeper struct {
+	storeService  corestoretypes.KVStoreService
+	paramSpace    paramtypes.Subspace
+	stakingKeeper types.StakingKeeper
+	evmKeeper     types.EVMKeeper
+	// govKeeper casts the delegated votes, it is read through its collections
+	govKeeper *govkeeper.Keeper
+	// skipHaltHeights are the halt heights the node executes anyway
+	skipHaltHeights map[int64]bool
+}
+
+// NewKeeper creates a new tacgov Keeper instance
+func NewKeeper(
+	skipHaltHeights map[int64]bool,
+	storeService corestoretypes.KVStoreService,
+	paramSpace paramtypes.Subspace,
+	stakingKeeper types.StakingKeeper,
+	evmKeeper types.EVMKeeper,
+	govKeeper *govkeeper.Keeper,
+) Keeper {
+	if !paramSpace.HasKeyTable() {
+		paramSpace = paramSpace.WithKeyTable(types.ParamKeyTable())
+	}
+
+	return Keeper{
+		storeService:  storeService,
+		paramSpace:    paramSpace,
+		stakingKeeper: stakingKeeper,
+		evmKeeper:     evmKeeper,
+		govKeeper:     govKeeper,
+
+		skipHaltHeights: skipHaltHeights,
+	}
+}
+
+// Logger returns a module-specific logger.
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the current tacgov module parameters
+func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	k.paramSpace.GetParamSetIfExists(ctx, &params)
+	return params
+}
+
+// SetParams sets the tacgov module parameters
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
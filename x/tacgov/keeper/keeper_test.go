@@ -0,0 +1,199 @@
+package keeper_test
+
+import (
+	"math/big"
+	"testing"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/cosmos/evm/x/vm/statedb"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+var (
+	contract = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	reverter = common.HexToAddress("0x2000000000000000000000000000000000000002")
+
+	holder  = sdk.AccAddress(common.HexToAddress("0x3000000000000000000000000000000000000003").Bytes())
+	other   = sdk.AccAddress(common.HexToAddress("0x4000000000000000000000000000000000000004").Bytes())
+	nobody  = sdk.AccAddress(common.HexToAddress("0x5000000000000000000000000000000000000005").Bytes())
+	staked  = sdkmath.NewInt(1_000_000)
+	supply  = int64(1_000)
+	balance = int64(250)
+)
+
+// derivativeCode answers totalSupply() with the storage slot 0 and
+// balanceOf(account) with the storage slot of the account, and reverts the
+// other calls
+var derivativeCode = common.FromHex("60003560e01c806318160ddd14601d576370a0823114602557600080fd" +
+	"5b50600054602a56" + // totalSupply: sload(0), jump to return
+	"5b60043554" + // balanceOf: sload(account)
+	"5b60005260206000f3") // return the word on the stack
+
+// setup returns a chain where contract is a staking derivative with a supply
+// of 1000, of which holder holds 250 and other 2000 (more than the supply),
+// whose account delegates 1000000 to the genesis validator. reverter reverts
+// every call.
+func setup(t *testing.T) (*app.TacChainApp, sdk.Context) {
+	t.Helper()
+
+	tacApp := app.NewTacChainAppWithCustomOptions(t, false, 0, app.SetupOptions{
+		Logger:  log.NewTestLogger(t),
+		DB:      dbm.NewMemDB(),
+		AppOpts: simtestutil.NewAppOptionsWithFlagHome(t.TempDir()),
+	})
+	ctx := tacApp.NewContext(false).WithChainID(app.DefaultChainID)
+
+	db := statedb.New(ctx, tacApp.EVMKeeper, statedb.NewEmptyTxConfig(common.BytesToHash(ctx.HeaderHash())))
+	db.SetCode(contract, derivativeCode)
+	db.SetState(contract, common.Hash{}, common.BigToHash(big.NewInt(supply)))
+	db.SetState(contract, common.BytesToHash(holder), common.BigToHash(big.NewInt(balance)))
+	db.SetState(contract, common.BytesToHash(other), common.BigToHash(big.NewInt(2*supply)))
+	db.SetCode(reverter, []byte{0x60, 0x00, 0x80, 0xfd}) // revert(0, 0)
+	require.NoError(t, db.Commit())
+
+	contractAcc := sdk.AccAddress(contract.Bytes())
+	coins := sdk.NewCoins(sdk.NewCoin(app.BaseDenom, staked))
+	require.NoError(t, tacApp.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins))
+	require.NoError(t, tacApp.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, contractAcc, coins))
+	validators, err := tacApp.StakingKeeper.GetAllValidators(ctx)
+	require.NoError(t, err)
+	_, err = tacApp.StakingKeeper.Delegate(ctx, contractAcc, staked, stakingtypes.Unbonded, validators[0], true)
+	require.NoError(t, err)
+
+	return tacApp, ctx
+}
+
+func enable(ctx sdk.Context, tacApp *app.TacChainApp, contract common.Address) {
+	tacApp.TacGovKeeper.SetParams(ctx, types.Params{
+		VotingWeightContract: contract.Hex(),
+		VotingWeightGasLimit: types.DefaultVotingWeightGasLimit,
+	})
+}
+
+func TestDerivativeDelegations(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.TacGovKeeper
+
+	// disabled by default
+	delegations, err := k.DerivativeDelegations(ctx, holder)
+	require.NoError(t, err)
+	require.Empty(t, delegations)
+
+	enable(ctx, tacApp, contract)
+	delegations, err = k.DerivativeDelegations(ctx, holder)
+	require.NoError(t, err)
+	require.Len(t, delegations, 1)
+	require.Equal(t, holder.String(), delegations[0].DelegatorAddress)
+	contractDelegation, err := tacApp.StakingKeeper.GetDelegatorDelegations(ctx, sdk.AccAddress(contract.Bytes()), 10)
+	require.NoError(t, err)
+	require.Len(t, contractDelegation, 1)
+	require.Equal(t, contractDelegation[0].ValidatorAddress, delegations[0].ValidatorAddress)
+	require.Equal(t, contractDelegation[0].Shares.MulInt64(balance).QuoInt64(supply), delegations[0].Shares)
+
+	// a balance above the supply is capped at the stake of the contract
+	delegations, err = k.DerivativeDelegations(ctx, other)
+	require.NoError(t, err)
+	require.Len(t, delegations, 1)
+	require.Equal(t, contractDelegation[0].Shares, delegations[0].Shares)
+
+	delegations, err = k.DerivativeDelegations(ctx, nobody)
+	require.NoError(t, err)
+	require.Empty(t, delegations)
+
+	// a failing call is an error, and so is running out of gas
+	enable(ctx, tacApp, reverter)
+	_, err = k.DerivativeDelegations(ctx, holder)
+	require.ErrorIs(t, err, types.ErrVotingWeightCall)
+	k.SetParams(ctx, types.Params{VotingWeightContract: contract.Hex(), VotingWeightGasLimit: 100})
+	_, err = k.DerivativeDelegations(ctx, holder)
+	require.ErrorIs(t, err, types.ErrVotingWeightCall)
+}
+
+// tally returns the tally of the proposal without ending it, since gov
+// removes the votes it tallies
+func tally(t *testing.T, tacApp *app.TacChainApp, ctx sdk.Context, proposalID uint64) govv1.TallyResult {
+	t.Helper()
+
+	cacheCtx, _ := ctx.CacheContext()
+	proposal, err := tacApp.GovKeeper.Proposals.Get(cacheCtx, proposalID)
+	require.NoError(t, err)
+	_, _, result, err := tacApp.GovKeeper.Tally(cacheCtx, proposal)
+	require.NoError(t, err)
+	return result
+}
+
+func TestTallyVotingWeight(t *testing.T) {
+	tacApp, ctx := setup(t)
+
+	proposal, err := tacApp.GovKeeper.SubmitProposal(ctx, nil, "", "voting weight", "summary", holder, false)
+	require.NoError(t, err)
+	require.NoError(t, tacApp.GovKeeper.ActivateVotingPeriod(ctx, proposal))
+	require.NoError(t, tacApp.GovKeeper.AddVote(ctx, proposal.Id, holder, govv1.NewNonSplitVoteOption(govv1.OptionYes), ""))
+	require.NoError(t, tacApp.GovKeeper.AddVote(ctx, proposal.Id, sdk.AccAddress(contract.Bytes()), govv1.NewNonSplitVoteOption(govv1.OptionNo), ""))
+
+	// disabled, the holder has no stake and the contract votes with its own
+	result := tally(t, tacApp, ctx, proposal.Id)
+	require.Equal(t, "0", result.YesCount)
+	require.Equal(t, staked.String(), result.NoCount)
+
+	// enabled, the holder votes with its share of the stake of the contract
+	// and the contract doesn't vote with it again
+	enable(ctx, tacApp, contract)
+	result = tally(t, tacApp, ctx, proposal.Id)
+	require.Equal(t, staked.MulRaw(balance).QuoRaw(supply).String(), result.YesCount)
+	require.Equal(t, "0", result.NoCount)
+
+	// a contract failing to answer counts no voting weight rather than
+	// failing the tally
+	enable(ctx, tacApp, reverter)
+	result = tally(t, tacApp, ctx, proposal.Id)
+	require.Equal(t, "0", result.YesCount)
+}
+
+// TestTallyVotingWeightDeterminism checks the tally only depends on the
+// state: it is the same whatever the gas left in the context tallying, when
+// tallied again, and reading the contract writes nothing.
+func TestTallyVotingWeightDeterminism(t *testing.T) {
+	tacApp, ctx := setup(t)
+	enable(ctx, tacApp, contract)
+
+	proposal, err := tacApp.GovKeeper.SubmitProposal(ctx, nil, "", "voting weight", "summary", holder, false)
+	require.NoError(t, err)
+	require.NoError(t, tacApp.GovKeeper.ActivateVotingPeriod(ctx, proposal))
+	require.NoError(t, tacApp.GovKeeper.AddVote(ctx, proposal.Id, holder, govv1.NewNonSplitVoteOption(govv1.OptionYes), ""))
+	require.NoError(t, tacApp.GovKeeper.AddVote(ctx, proposal.Id, other, govv1.NewNonSplitVoteOption(govv1.OptionAbstain), ""))
+
+	expected := tally(t, tacApp, ctx, proposal.Id)
+	require.NotEqual(t, "0", expected.YesCount)
+	require.NotEqual(t, "0", expected.AbstainCount)
+
+	for i := 0; i < 3; i++ {
+		meter := storetypes.NewGasMeter(10_000_000)
+		require.Equal(t, expected, tally(t, tacApp, ctx.WithGasMeter(meter), proposal.Id))
+	}
+
+	// the calls reading the contract leave no trace, e.g. the nonce of the
+	// caller isn't increased
+	caller := common.BytesToAddress(authtypes.NewModuleAddress(types.ModuleName))
+	require.Nil(t, tacApp.EVMKeeper.GetAccount(ctx, caller))
+	_, err = tacApp.TacGovKeeper.DerivativeDelegations(ctx, holder)
+	require.NoError(t, err)
+	require.Nil(t, tacApp.EVMKeeper.GetAccount(ctx, caller))
+	require.Equal(t, expected, tally(t, tacApp, ctx, proposal.Id))
+}
@@ -0,0 +1,66 @@
+package keeper
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+)
+
+var _ govtypes.StakingKeeper = TallyStakingKeeper{}
+
+// TallyStakingKeeper is the staking keeper of the gov keeper, which tallies
+// the votes with the delegations of the voters. It adds to them the share of
+// the stake of the voting weight contract the voters hold.
+type TallyStakingKeeper struct {
+	govtypes.StakingKeeper
+
+	keeper *Keeper
+}
+
+// NewTallyStakingKeeper wraps the staking keeper of the gov keeper. keeper is
+// a reference so that the gov keeper can be created before the EVM keeper the
+// tacgov keeper depends on.
+func NewTallyStakingKeeper(stakingKeeper govtypes.StakingKeeper, keeper *Keeper) TallyStakingKeeper {
+	return TallyStakingKeeper{StakingKeeper: stakingKeeper, keeper: keeper}
+}
+
+// IterateDelegations implements govtypes.StakingKeeper. When the adapter is
+// enabled, the delegations of delegator are followed by its derivative
+// delegations, and the delegations of the contract itself are skipped since
+// its stake is counted for its holders. A contract call that fails counts no
+// derivative delegation, on every node alike.
+func (k TallyStakingKeeper) IterateDelegations(ctx context.Context, delegator sdk.AccAddress, fn func(index int64, delegation sdk.DelegationI) (stop bool)) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	contract, enabled := k.keeper.GetParams(sdkCtx).VotingWeightContractAddress()
+	if !enabled {
+		return k.StakingKeeper.IterateDelegations(ctx, delegator, fn)
+	}
+	if delegator.Equals(sdk.AccAddress(contract.Bytes())) {
+		return nil
+	}
+
+	var index int64
+	stopped := false
+	err := k.StakingKeeper.IterateDelegations(ctx, delegator, func(i int64, delegation sdk.DelegationI) bool {
+		index = i + 1
+		stopped = fn(i, delegation)
+		return stopped
+	})
+	if err != nil || stopped {
+		return err
+	}
+
+	delegations, err := k.keeper.DerivativeDelegations(sdkCtx, delegator)
+	if err != nil {
+		k.keeper.Logger(sdkCtx).Error("failed to read the voting weight of a voter", "voter", delegator.String(), "error", err)
+		return nil
+	}
+	for _, delegation := range delegations {
+		if fn(index, delegation) {
+			break
+		}
+		index++
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package keeper
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"cosmossdk.io/collections"
+	errorsmod "cosmossdk.io/errors"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+
+	"github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+// GetVoteDelegate returns the vote delegate of delegator, if it has one
+func (k Keeper) GetVoteDelegate(ctx sdk.Context, delegator common.Address) (common.Address, bool) {
+	bz, err := k.storeService.OpenKVStore(ctx).Get(types.VoteDelegateKey(delegator))
+	if err != nil {
+		panic(err)
+	}
+	if bz == nil {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(bz), true
+}
+
+// IterateVoteDelegators calls cb with the accounts delegating their votes to
+// delegate, in address order, until it returns true
+func (k Keeper) IterateVoteDelegators(ctx sdk.Context, delegate common.Address, cb func(delegator common.Address) (stop bool)) {
+	prefix := types.VoteDelegatorsKey(delegate)
+	iterator, err := k.storeService.OpenKVStore(ctx).Iterator(prefix, storetypes.PrefixEndBytes(prefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		if cb(common.BytesToAddress(iterator.Key()[len(prefix):])) {
+			return
+		}
+	}
+}
+
+// GetAllVoteDelegations returns the vote delegations of all accounts
+func (k Keeper) GetAllVoteDelegations(ctx sdk.Context) []types.VoteDelegation {
+	iterator, err := k.storeService.OpenKVStore(ctx).Iterator(types.VoteDelegatePrefix, storetypes.PrefixEndBytes(types.VoteDelegatePrefix))
+	if err != nil {
+		panic(err)
+	}
+	defer iterator.Close()
+
+	all := []types.VoteDelegation{}
+	for ; iterator.Valid(); iterator.Next() {
+		all = append(all, types.VoteDelegation{
+			Delegator: common.BytesToAddress(iterator.Key()[len(types.VoteDelegatePrefix):]).Hex(),
+			Delegate:  common.BytesToAddress(iterator.Value()).Hex(),
+		})
+	}
+	return all
+}
+
+// HandleVoteDelegationCall executes a call sent by sender to the vote
+// delegation address
+func (k Keeper) HandleVoteDelegationCall(ctx sdk.Context, sender common.Address, data []byte) error {
+	call, err := types.ParseVoteDelegationCall(data)
+	if err != nil {
+		return err
+	}
+	if call.Method == types.MethodClearVoteDelegate {
+		return k.ClearVoteDelegate(ctx, sender)
+	}
+	return k.SetVoteDelegate(ctx, sender, call.Delegate)
+}
+
+// SetVoteDelegate makes delegate vote on behalf of delegator, replacing its
+// previous delegate
+func (k Keeper) SetVoteDelegate(ctx sdk.Context, delegator, delegate common.Address) error {
+	if err := k.setVoteDelegate(ctx, delegator, delegate); err != nil {
+		return err
+	}
+	return ctx.EventManager().EmitTypedEvent(&types.EventSetVoteDelegate{
+		Delegator: delegator.Hex(),
+		Delegate:  delegate.Hex(),
+	})
+}
+
+func (k Keeper) setVoteDelegate(ctx sdk.Context, delegator, delegate common.Address) error {
+	if err := types.ValidateVoteDelegate(delegator, delegate); err != nil {
+		return err
+	}
+
+	store := k.storeService.OpenKVStore(ctx)
+	if previous, found := k.GetVoteDelegate(ctx, delegator); found {
+		if err := store.Delete(types.VoteDelegatorKey(previous, delegator)); err != nil {
+			panic(err)
+		}
+	}
+	if err := store.Set(types.VoteDelegateKey(delegator), delegate.Bytes()); err != nil {
+		panic(err)
+	}
+	if err := store.Set(types.VoteDelegatorKey(delegate, delegator), []byte{}); err != nil {
+		panic(err)
+	}
+	return nil
+}
+
+// ClearVoteDelegate removes the vote delegate of delegator
+func (k Keeper) ClearVoteDelegate(ctx sdk.Context, delegator common.Address) error {
+	delegate, found := k.GetVoteDelegate(ctx, delegator)
+	if !found {
+		return errorsmod.Wrapf(types.ErrNoVoteDelegate, "%s", delegator.Hex())
+	}
+
+	store := k.storeService.OpenKVStore(ctx)
+	if err := store.Delete(types.VoteDelegatorKey(delegate, delegator)); err != nil {
+		panic(err)
+	}
+	if err := store.Delete(types.VoteDelegateKey(delegator)); err != nil {
+		panic(err)
+	}
+	return ctx.EventManager().EmitTypedEvent(&types.EventClearVoteDelegate{
+		Delegator: delegator.Hex(),
+		Delegate:  delegate.Hex(),
+	})
+}
+
+// ApplyDelegatedVotes casts the votes of the delegates on behalf of the
+// accounts delegating to them, on the proposals whose voting period ends in
+// the block, right before x/gov tallies them. The precedence rules are:
+//
+//   - the vote of an account itself always wins over the one of its delegate,
+//     whether it was cast before or after;
+//   - otherwise the vote the delegate cast itself applies, with its options,
+//     to the stake of the account, as if the account had cast it;
+//   - delegation isn't transitive: when the delegate didn't vote itself, the
+//     vote of its own delegate doesn't apply to the account, and the
+//     validators vote with its stake as usual.
+func (k Keeper) ApplyDelegatedVotes(ctx sdk.Context) error {
+	var proposals []uint64
+	rng := collections.NewPrefixUntilPairRange[time.Time, uint64](ctx.BlockTime())
+	err := k.govKeeper.ActiveProposalsQueue.Walk(ctx, rng, func(key collections.Pair[time.Time, uint64], _ uint64) (bool, error) {
+		proposals = append(proposals, key.K2())
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, proposalID := range proposals {
+		if err := k.applyDelegatedVotes(ctx, proposalID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k Keeper) applyDelegatedVotes(ctx sdk.Context, proposalID uint64) error {
+	// the votes cast by the accounts themselves, before any delegated vote
+	var votes []govv1.Vote
+	voted := map[common.Address]bool{}
+	rng := collections.NewPrefixedPairRange[uint64, sdk.AccAddress](proposalID)
+	err := k.govKeeper.Votes.Walk(ctx, rng, func(key collections.Pair[uint64, sdk.AccAddress], vote govv1.Vote) (bool, error) {
+		votes = append(votes, vote)
+		voted[common.BytesToAddress(key.K2())] = true
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, vote := range votes {
+		voter, err := sdk.AccAddressFromBech32(vote.Voter)
+		if err != nil {
+			return err
+		}
+		k.IterateVoteDelegators(ctx, common.BytesToAddress(voter), func(delegator common.Address) bool {
+			if voted[delegator] {
+				return false
+			}
+			if err := k.govKeeper.AddVote(ctx, proposalID, sdk.AccAddress(delegator.Bytes()), vote.Options, ""); err != nil {
+				k.Logger(ctx).Error("failed to cast delegated vote", "proposal", proposalID, "delegator", delegator.Hex(), "delegate", vote.Voter, "error", err)
+			}
+			return false
+		})
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package keeper_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/collections"
+	sdkmath "cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/Asphere-xyz/tacchain/app"
+	"github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+var (
+	alice = common.HexToAddress("0xa000000000000000000000000000000000000001")
+	bob   = common.HexToAddress("0xb000000000000000000000000000000000000002")
+	carol = common.HexToAddress("0xc000000000000000000000000000000000000003")
+	dave  = common.HexToAddress("0xd000000000000000000000000000000000000004")
+	erin  = common.HexToAddress("0xe000000000000000000000000000000000000005")
+)
+
+func TestVoteDelegate(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.TacGovKeeper
+
+	_, found := k.GetVoteDelegate(ctx, alice)
+	require.False(t, found)
+	require.ErrorIs(t, k.ClearVoteDelegate(ctx, alice), types.ErrNoVoteDelegate)
+	require.ErrorIs(t, k.SetVoteDelegate(ctx, alice, alice), types.ErrInvalidVoteDelegate)
+	require.ErrorIs(t, k.SetVoteDelegate(ctx, alice, common.Address{}), types.ErrInvalidVoteDelegate)
+
+	require.NoError(t, k.SetVoteDelegate(ctx, alice, dave))
+	require.NoError(t, k.SetVoteDelegate(ctx, bob, dave))
+	delegate, found := k.GetVoteDelegate(ctx, alice)
+	require.True(t, found)
+	require.Equal(t, dave, delegate)
+	require.Equal(t, []common.Address{alice, bob}, delegators(ctx, tacApp, dave))
+
+	// changing the delegate moves the delegator in the index
+	require.NoError(t, k.SetVoteDelegate(ctx, alice, carol))
+	require.Equal(t, []common.Address{bob}, delegators(ctx, tacApp, dave))
+	require.Equal(t, []common.Address{alice}, delegators(ctx, tacApp, carol))
+
+	require.NoError(t, k.ClearVoteDelegate(ctx, bob))
+	_, found = k.GetVoteDelegate(ctx, bob)
+	require.False(t, found)
+	require.Empty(t, delegators(ctx, tacApp, dave))
+
+	// the state round trips through genesis
+	gs := k.ExportGenesis(ctx)
+	require.Equal(t, []types.VoteDelegation{{Delegator: alice.Hex(), Delegate: carol.Hex()}}, gs.VoteDelegations)
+	require.NoError(t, gs.Validate())
+	tacApp2, ctx2 := setup(t)
+	tacApp2.TacGovKeeper.InitGenesis(ctx2, *gs)
+	require.Equal(t, gs, tacApp2.TacGovKeeper.ExportGenesis(ctx2))
+	require.Equal(t, []common.Address{alice}, delegators(ctx2, tacApp2, carol))
+}
+
+func TestHandleVoteDelegationCall(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.TacGovKeeper
+
+	setCall, err := types.VoteDelegationCall{Method: types.MethodSetVoteDelegate, Delegate: dave}.Pack()
+	require.NoError(t, err)
+	clearCall, err := types.VoteDelegationCall{Method: types.MethodClearVoteDelegate}.Pack()
+	require.NoError(t, err)
+
+	require.NoError(t, k.HandleVoteDelegationCall(ctx, alice, setCall))
+	delegate, found := k.GetVoteDelegate(ctx, alice)
+	require.True(t, found)
+	require.Equal(t, dave, delegate)
+	require.ErrorIs(t, k.HandleVoteDelegationCall(ctx, dave, setCall), types.ErrInvalidVoteDelegate)
+
+	require.NoError(t, k.HandleVoteDelegationCall(ctx, alice, clearCall))
+	_, found = k.GetVoteDelegate(ctx, alice)
+	require.False(t, found)
+	require.ErrorIs(t, k.HandleVoteDelegationCall(ctx, alice, clearCall), types.ErrNoVoteDelegate)
+	require.ErrorIs(t, k.HandleVoteDelegationCall(ctx, alice, []byte{0x01}), types.ErrInvalidVoteDelegationCall)
+}
+
+func delegators(ctx sdk.Context, tacApp *app.TacChainApp, delegate common.Address) []common.Address {
+	var all []common.Address
+	tacApp.TacGovKeeper.IterateVoteDelegators(ctx, delegate, func(delegator common.Address) bool {
+		all = append(all, delegator)
+		return false
+	})
+	return all
+}
+
+// stake delegates amount of the bond denom from account to the genesis validator
+func stake(t *testing.T, tacApp *app.TacChainApp, ctx sdk.Context, account common.Address, amount sdkmath.Int) {
+	t.Helper()
+
+	acc := sdk.AccAddress(account.Bytes())
+	coins := sdk.NewCoins(sdk.NewCoin(app.BaseDenom, amount))
+	require.NoError(t, tacApp.BankKeeper.MintCoins(ctx, minttypes.ModuleName, coins))
+	require.NoError(t, tacApp.BankKeeper.SendCoinsFromModuleToAccount(ctx, minttypes.ModuleName, acc, coins))
+	validators, err := tacApp.StakingKeeper.GetAllValidators(ctx)
+	require.NoError(t, err)
+	_, err = tacApp.StakingKeeper.Delegate(ctx, acc, amount, stakingtypes.Unbonded, validators[0], true)
+	require.NoError(t, err)
+}
+
+func vote(t *testing.T, tacApp *app.TacChainApp, ctx sdk.Context, proposalID uint64, voter common.Address, option govv1.VoteOption) {
+	t.Helper()
+	require.NoError(t, tacApp.GovKeeper.AddVote(ctx, proposalID, sdk.AccAddress(voter.Bytes()), govv1.NewNonSplitVoteOption(option), ""))
+}
+
+// TestTallyVoteDelegation checks the precedence rules of the delegated votes:
+// dave votes yes, alice delegates to dave and follows the vote, bob delegates
+// to dave and overrides the vote with no, carol delegates to alice, who
+// doesn't vote itself, and erin delegates to carol, who doesn't vote at all.
+func TestTallyVoteDelegation(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.TacGovKeeper
+
+	unit := sdkmath.NewInt(1_000_000)
+	stake(t, tacApp, ctx, dave, unit)
+	stake(t, tacApp, ctx, alice, unit.MulRaw(10))
+	stake(t, tacApp, ctx, bob, unit.MulRaw(100))
+	stake(t, tacApp, ctx, carol, unit.MulRaw(1_000))
+	stake(t, tacApp, ctx, erin, unit.MulRaw(10_000))
+	require.NoError(t, k.SetVoteDelegate(ctx, alice, dave))
+	require.NoError(t, k.SetVoteDelegate(ctx, bob, dave))
+	require.NoError(t, k.SetVoteDelegate(ctx, carol, alice))
+	require.NoError(t, k.SetVoteDelegate(ctx, erin, carol))
+
+	proposal, err := tacApp.GovKeeper.SubmitProposal(ctx, nil, "", "vote delegation", "summary", sdk.AccAddress(dave.Bytes()), false)
+	require.NoError(t, err)
+	require.NoError(t, tacApp.GovKeeper.ActivateVotingPeriod(ctx, proposal))
+	proposal, err = tacApp.GovKeeper.Proposals.Get(ctx, proposal.Id)
+	require.NoError(t, err)
+
+	// the override of bob is cast before the vote of its delegate
+	vote(t, tacApp, ctx, proposal.Id, bob, govv1.OptionNo)
+	vote(t, tacApp, ctx, proposal.Id, dave, govv1.OptionYes)
+
+	// the delegated votes are only cast at the end of the voting period
+	require.NoError(t, k.ApplyDelegatedVotes(ctx))
+	result := tally(t, tacApp, ctx, proposal.Id)
+	require.Equal(t, unit.String(), result.YesCount)
+	require.Equal(t, unit.MulRaw(100).String(), result.NoCount)
+
+	endCtx := ctx.WithBlockTime(*proposal.VotingEndTime)
+	require.NoError(t, k.ApplyDelegatedVotes(endCtx))
+	result = tally(t, tacApp, endCtx, proposal.Id)
+	require.Equal(t, unit.MulRaw(11).String(), result.YesCount)
+	require.Equal(t, unit.MulRaw(100).String(), result.NoCount)
+	require.Equal(t, "0", result.AbstainCount)
+
+	// the delegated vote is recorded as the vote of alice, and the votes of
+	// the accounts whose delegates didn't vote are left unset
+	aliceVote, err := tacApp.GovKeeper.Votes.Get(endCtx, collections.Join(proposal.Id, sdk.AccAddress(alice.Bytes())))
+	require.NoError(t, err)
+	require.Equal(t, govv1.NewNonSplitVoteOption(govv1.OptionYes), aliceVote.Options)
+	for _, account := range []common.Address{carol, erin} {
+		_, err := tacApp.GovKeeper.Votes.Get(endCtx, collections.Join(proposal.Id, sdk.AccAddress(account.Bytes())))
+		require.ErrorIs(t, err, collections.ErrNotFound)
+	}
+}
+
+func TestTallyVoteDelegationWeighted(t *testing.T) {
+	tacApp, ctx := setup(t)
+	k := tacApp.TacGovKeeper
+
+	unit := sdkmath.NewInt(1_000_000)
+	stake(t, tacApp, ctx, alice, unit.MulRaw(10))
+	require.NoError(t, k.SetVoteDelegate(ctx, alice, dave))
+
+	proposal, err := tacApp.GovKeeper.SubmitProposal(ctx, nil, "", "vote delegation", "summary", sdk.AccAddress(dave.Bytes()), false)
+	require.NoError(t, err)
+	require.NoError(t, tacApp.GovKeeper.ActivateVotingPeriod(ctx, proposal))
+	proposal, err = tacApp.GovKeeper.Proposals.Get(ctx, proposal.Id)
+	require.NoError(t, err)
+
+	// the delegate votes without stake, splitting its vote
+	options := govv1.WeightedVoteOptions{
+		govv1.NewWeightedVoteOption(govv1.OptionYes, sdkmath.LegacyNewDecWithPrec(7, 1)),
+		govv1.NewWeightedVoteOption(govv1.OptionAbstain, sdkmath.LegacyNewDecWithPrec(3, 1)),
+	}
+	require.NoError(t, tacApp.GovKeeper.AddVote(ctx, proposal.Id, sdk.AccAddress(dave.Bytes()), options, ""))
+
+	// a delegate cleared before the end of the voting period doesn't vote for
+	// the account anymore
+	endCtx := ctx.WithBlockTime(*proposal.VotingEndTime)
+	cacheCtx, _ := endCtx.CacheContext()
+	require.NoError(t, k.ClearVoteDelegate(cacheCtx, alice))
+	require.NoError(t, k.ApplyDelegatedVotes(cacheCtx))
+	result := tally(t, tacApp, cacheCtx, proposal.Id)
+	require.Equal(t, "0", result.YesCount)
+
+	require.NoError(t, k.ApplyDelegatedVotes(endCtx))
+	result = tally(t, tacApp, endCtx, proposal.Id)
+	require.Equal(t, unit.MulRaw(7).String(), result.YesCount)
+	require.Equal(t, unit.MulRaw(3).String(), result.AbstainCount)
+}
@@ -0,0 +1,106 @@
+package keeper
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+
+	errorsmod "cosmossdk.io/errors"
+	sdkmath "cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	evmtypes "github.com/cosmos/evm/x/vm/types"
+
+	"github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+// DerivativeDelegations returns the delegations of the voting weight contract
+// scaled to the share of its derivative held by holder, as delegations of
+// holder. It returns none when the adapter is disabled or holder has no
+// balance.
+func (k Keeper) DerivativeDelegations(ctx sdk.Context, holder sdk.AccAddress) ([]stakingtypes.Delegation, error) {
+	params := k.GetParams(ctx)
+	contract, enabled := params.VotingWeightContractAddress()
+	if !enabled {
+		return nil, nil
+	}
+
+	balance, err := k.callUint256(ctx, contract, params.VotingWeightGasLimit, types.MethodBalanceOf, common.BytesToAddress(holder))
+	if err != nil || balance.Sign() <= 0 {
+		return nil, err
+	}
+	supply, err := k.callUint256(ctx, contract, params.VotingWeightGasLimit, types.MethodTotalSupply)
+	if err != nil || supply.Sign() <= 0 {
+		return nil, err
+	}
+	// a holder can't vote with more than the stake of the contract
+	if balance.Cmp(supply) > 0 {
+		balance = supply
+	}
+
+	var delegations []stakingtypes.Delegation
+	err = k.stakingKeeper.IterateDelegations(ctx, sdk.AccAddress(contract.Bytes()), func(_ int64, delegation sdk.DelegationI) bool {
+		// shares * balance / supply in integers, truncated, so that every node
+		// tallies the same shares
+		scaled := new(big.Int).Mul(delegation.GetShares().BigInt(), balance)
+		shares := sdkmath.LegacyNewDecFromBigIntWithPrec(scaled.Quo(scaled, supply), sdkmath.LegacyPrecision)
+		if shares.IsPositive() {
+			delegations = append(delegations, stakingtypes.Delegation{
+				DelegatorAddress: holder.String(),
+				ValidatorAddress: delegation.GetValidatorAddr(),
+				Shares:           shares,
+			})
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+// callUint256 calls a view method of the voting weight contract returning a
+// uint256. The call runs on a cached state which is discarded, with the gas
+// of the EVM limited to gasLimit only, so that it returns the same on every
+// node whatever context tallies.
+func (k Keeper) callUint256(ctx sdk.Context, contract common.Address, gasLimit uint64, method string, args ...interface{}) (*big.Int, error) {
+	data, err := types.VotingWeightABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := core.Message{
+		From:              common.BytesToAddress(authtypes.NewModuleAddress(types.ModuleName)),
+		To:                &contract,
+		Value:             big.NewInt(0),
+		GasLimit:          gasLimit,
+		GasPrice:          big.NewInt(0),
+		GasFeeCap:         big.NewInt(0),
+		GasTipCap:         big.NewInt(0),
+		Data:              data,
+		SkipAccountChecks: true,
+	}
+	cacheCtx, _ := ctx.WithGasMeter(storetypes.NewInfiniteGasMeter()).CacheContext()
+	res, err := k.evmKeeper.ApplyMessage(cacheCtx, msg, evmtypes.NewNoOpTracer(), false)
+	if err != nil {
+		return nil, errorsmod.Wrapf(types.ErrVotingWeightCall, "%s of %s: %s", method, contract, err)
+	}
+	if res.Failed() {
+		return nil, errorsmod.Wrapf(types.ErrVotingWeightCall, "%s of %s: %s", method, contract, res.VmError)
+	}
+
+	out, err := types.VotingWeightABI.Unpack(method, res.Ret)
+	if err != nil {
+		return nil, errorsmod.Wrapf(types.ErrVotingWeightCall, "%s of %s: %s", method, contract, err)
+	}
+	value, ok := out[0].(*big.Int)
+	if !ok {
+		return nil, errorsmod.Wrapf(types.ErrVotingWeightCall, "%s of %s returned %T", method, contract, out[0])
+	}
+	return value, nil
+}
@@ -0,0 +1,14 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+)
+
+// x/tacgov module sentinel errors
+var (
+	ErrVotingWeightCall          = errorsmod.Register(ModuleName, 2, "voting weight contract call failed")
+	ErrInvalidVoteDelegationCall = errorsmod.Register(ModuleName, 3, "invalid vote delegation call")
+	ErrInvalidVoteDelegate       = errorsmod.Register(ModuleName, 4, "invalid vote delegate")
+	ErrNoVoteDelegate            = errorsmod.Register(ModuleName, 5, "account has no vote delegate")
+	ErrChainHalted               = errorsmod.Register(ModuleName, 6, "chain halted by governance")
+)
@@ -0,0 +1,33 @@
+package types
+
+import (
+	"github.com/cosmos/gogoproto/proto"
+)
+
+// init registers the events of the vote delegations set and cleared through
+// the vote delegation address.
+func init() {
+	proto.RegisterType((*EventSetVoteDelegate)(nil), "tacchain.tacgov.v1.EventSetVoteDelegate")
+	proto.RegisterType((*EventClearVoteDelegate)(nil), "tacchain.tacgov.v1.EventClearVoteDelegate")
+}
+
+// EventSetVoteDelegate is emitted when an account sets or changes its vote
+// delegate.
+type EventSetVoteDelegate struct {
+	Delegator string `protobuf:"bytes,1,opt,name=delegator,proto3" json:"delegator,omitempty"`
+	Delegate  string `protobuf:"bytes,2,opt,name=delegate,proto3" json:"delegate,omitempty"`
+}
+
+func (m *EventSetVoteDelegate) Reset()         { *m = EventSetVoteDelegate{} }
+func (m *EventSetVoteDelegate) String() string { return proto.CompactTextString(m) }
+func (*EventSetVoteDelegate) ProtoMessage()    {}
+
+// EventClearVoteDelegate is emitted when an account removes its vote delegate.
+type EventClearVoteDelegate struct {
+	Delegator string `protobuf:"bytes,1,opt,name=delegator,proto3" json:"delegator,omitempty"`
+	Delegate  string `protobuf:"bytes,2,opt,name=delegate,proto3" json:"delegate,omitempty"`
+}
+
+func (m *EventClearVoteDelegate) Reset()         { *m = EventClearVoteDelegate{} }
+func (m *EventClearVoteDelegate) String() string { return proto.CompactTextString(m) }
+func (*EventClearVoteDelegate) ProtoMessage()    {}
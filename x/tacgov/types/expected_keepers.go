@@ -0,0 +1,24 @@
+package types
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	evmtypes "github.com/cosmos/evm/x/vm/types"
+)
+
+// StakingKeeper defines the expected staking keeper used to read the
+// delegations of the voting weight contract
+type StakingKeeper interface {
+	IterateDelegations(ctx context.Context, delegator sdk.AccAddress, fn func(index int64, delegation sdk.DelegationI) (stop bool)) error
+}
+
+// EVMKeeper defines the expected EVM keeper used to read the balances of the
+// voting weight contract
+type EVMKeeper interface {
+	ApplyMessage(ctx sdk.Context, msg core.Message, tracer vm.EVMLogger, commit bool) (*evmtypes.MsgEthereumTxResponse, error)
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GenesisState defines the tacgov module genesis state
+type GenesisState struct {
+	Params          Params           `json:"params" yaml:"params"`
+	VoteDelegations []VoteDelegation `json:"vote_delegations" yaml:"vote_delegations"`
+}
+
+// DefaultGenesisState returns the default tacgov module genesis state
+func DefaultGenesisState() *GenesisState {
+	return &GenesisState{
+		Params:          DefaultParams(),
+		VoteDelegations: []VoteDelegation{},
+	}
+}
+
+// Validate performs basic genesis state validation
+func (gs GenesisState) Validate() error {
+	if err := gs.Params.Validate(); err != nil {
+		return err
+	}
+
+	seen := make(map[common.Address]bool, len(gs.VoteDelegations))
+	for _, delegation := range gs.VoteDelegations {
+		if err := delegation.Validate(); err != nil {
+			return err
+		}
+		delegator := common.HexToAddress(delegation.Delegator)
+		if seen[delegator] {
+			return fmt.Errorf("duplicate vote delegation of %s", delegation.Delegator)
+		}
+		seen[delegator] = true
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/cosmos/cosmos-sdk/types/address"
+)
+
+const (
+	// ModuleName defines the tacgov module name
+	ModuleName = "tacgov"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+)
+
+var (
+	// VoteDelegatePrefix prefixes the vote delegate of an account, keyed by the
+	// account address
+	VoteDelegatePrefix = []byte{0x01}
+	// VoteDelegatorsPrefix indexes the accounts by their vote delegate
+	VoteDelegatorsPrefix = []byte{0x02}
+)
+
+// VoteDelegateKey returns the store key of the vote delegate of delegator
+func VoteDelegateKey(delegator common.Address) []byte {
+	return append(append([]byte{}, VoteDelegatePrefix...), delegator.Bytes()...)
+}
+
+// VoteDelegatorsKey returns the prefix of the index keys of the accounts
+// delegating their votes to delegate
+func VoteDelegatorsKey(delegate common.Address) []byte {
+	return append(append([]byte{}, VoteDelegatorsPrefix...), delegate.Bytes()...)
+}
+
+// VoteDelegatorKey returns the index key of delegator under its delegate
+func VoteDelegatorKey(delegate, delegator common.Address) []byte {
+	return append(VoteDelegatorsKey(delegate), delegator.Bytes()...)
+}
+
+// VoteDelegationAddress returns the address accounts send their vote
+// delegation calls to. It is derived like a module account address, no
+// account lives there and no code runs at it, the calls are handled after the
+// transaction executed.
+func VoteDelegationAddress() common.Address {
+	return common.BytesToAddress(address.Module(ModuleName, []byte("vote-delegation"))[:common.AddressLength])
+}
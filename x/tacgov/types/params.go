@@ -0,0 +1,146 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	paramtypes "github.com/cosmos/cosmos-sdk/x/params/types"
+)
+
+const (
+	// DefaultVotingWeightGasLimit is the default gas limit of a call reading
+	// the voting weight contract, enough for the balanceOf of an ERC-20
+	DefaultVotingWeightGasLimit uint64 = 100_000
+
+	// MaxHaltReasonLength is the max length of the reason of a chain halt
+	MaxHaltReasonLength = 256
+)
+
+var (
+	// KeyVotingWeightContract is the param store key for the staking derivative
+	// contract counted in tallies
+	KeyVotingWeightContract = []byte("VotingWeightContract")
+	// KeyVotingWeightGasLimit is the param store key for the gas limit of the
+	// calls reading the contract
+	KeyVotingWeightGasLimit = []byte("VotingWeightGasLimit")
+	// KeyHaltHeight is the param store key for the height the chain halts at
+	KeyHaltHeight = []byte("HaltHeight")
+	// KeyHaltReason is the param store key for the reason of the halt
+	KeyHaltReason = []byte("HaltReason")
+)
+
+// Params defines the tacgov module parameters. VotingWeightContract is an
+// ERC-20 staking derivative whose stake is the delegations of the contract
+// account. When it is set, governance tallies count the stake of the contract
+// for the holders of the derivative rather than for the contract: a holder of
+// a share of its totalSupply votes with that share of each delegation of the
+// contract, and the validators inherit the rest. Empty, the default, disables
+// the adapter. Governance trusts the contract it sets to report balances
+// summing up to its supply.
+//
+// The balances are read with calls limited to VotingWeightGasLimit gas, a
+// call that fails counts no voting weight.
+//
+// HaltHeight is the emergency brake of governance: every node stops before
+// executing the block at that height, logging HaltReason, like at the height
+// of an upgrade plan. Zero, the default, schedules no halt, and a height the
+// chain already passed never applies. The operators resume the chain by
+// restarting their nodes with the flag --unsafe-skip-halt-heights set to the
+// height.
+type Params struct {
+	VotingWeightContract string `json:"voting_weight_contract" yaml:"voting_weight_contract"`
+	VotingWeightGasLimit uint64 `json:"voting_weight_gas_limit" yaml:"voting_weight_gas_limit"`
+	HaltHeight           uint64 `json:"halt_height" yaml:"halt_height"`
+	HaltReason           string `json:"halt_reason" yaml:"halt_reason"`
+}
+
+var _ paramtypes.ParamSet = (*Params)(nil)
+
+// ParamKeyTable returns the parameter key table for the tacgov module
+func ParamKeyTable() paramtypes.KeyTable {
+	return paramtypes.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns default tacgov module parameters
+func DefaultParams() Params {
+	return Params{
+		VotingWeightContract: "",
+		VotingWeightGasLimit: DefaultVotingWeightGasLimit,
+		HaltHeight:           0,
+		HaltReason:           "",
+	}
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() paramtypes.ParamSetPairs {
+	return paramtypes.ParamSetPairs{
+		paramtypes.NewParamSetPair(KeyVotingWeightContract, &p.VotingWeightContract, validateVotingWeightContract),
+		paramtypes.NewParamSetPair(KeyVotingWeightGasLimit, &p.VotingWeightGasLimit, validateVotingWeightGasLimit),
+		paramtypes.NewParamSetPair(KeyHaltHeight, &p.HaltHeight, validateHaltHeight),
+		paramtypes.NewParamSetPair(KeyHaltReason, &p.HaltReason, validateHaltReason),
+	}
+}
+
+// Validate performs basic validation of the tacgov module parameters
+func (p Params) Validate() error {
+	if err := validateVotingWeightContract(p.VotingWeightContract); err != nil {
+		return err
+	}
+	if err := validateVotingWeightGasLimit(p.VotingWeightGasLimit); err != nil {
+		return err
+	}
+	if err := validateHaltHeight(p.HaltHeight); err != nil {
+		return err
+	}
+	return validateHaltReason(p.HaltReason)
+}
+
+// VotingWeightContractAddress returns the voting weight contract and whether
+// the adapter is enabled
+func (p Params) VotingWeightContractAddress() (common.Address, bool) {
+	if p.VotingWeightContract == "" {
+		return common.Address{}, false
+	}
+	return common.HexToAddress(p.VotingWeightContract), true
+}
+
+func validateVotingWeightContract(i interface{}) error {
+	contract, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if contract != "" && !common.IsHexAddress(contract) {
+		return fmt.Errorf("invalid voting weight contract address: %s", contract)
+	}
+	return nil
+}
+
+func validateVotingWeightGasLimit(i interface{}) error {
+	gasLimit, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if gasLimit == 0 {
+		return fmt.Errorf("voting weight gas limit must be positive")
+	}
+	return nil
+}
+
+func validateHaltHeight(i interface{}) error {
+	if _, ok := i.(uint64); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return nil
+}
+
+func validateHaltReason(i interface{}) error {
+	reason, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if len(reason) > MaxHaltReasonLength {
+		return fmt.Errorf("halt reason longer than %d bytes", MaxHaltReasonLength)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package types_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+func TestParamsValidate(t *testing.T) {
+	require.NoError(t, types.DefaultParams().Validate())
+
+	valid := types.Params{
+		VotingWeightContract: "0x1000000000000000000000000000000000000001",
+		VotingWeightGasLimit: 50_000,
+		HaltHeight:           100,
+		HaltReason:           strings.Repeat("a", types.MaxHaltReasonLength),
+	}
+	require.NoError(t, valid.Validate())
+
+	testCases := []struct {
+		name   string
+		params types.Params
+	}{
+		{"invalid address", types.Params{VotingWeightContract: "0x1234", VotingWeightGasLimit: 50_000}},
+		{"bech32 address", types.Params{VotingWeightContract: "tac1qyqszqgpqyqszqgpqyqszqgpqyqszqgpjnp7du", VotingWeightGasLimit: 50_000}},
+		{"zero gas limit", types.Params{VotingWeightContract: "0x1000000000000000000000000000000000000001"}},
+		{"long halt reason", types.Params{VotingWeightGasLimit: 50_000, HaltHeight: 100, HaltReason: strings.Repeat("a", types.MaxHaltReasonLength+1)}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, tc.params.Validate())
+		})
+	}
+}
+
+func TestVotingWeightContractAddress(t *testing.T) {
+	_, enabled := types.DefaultParams().VotingWeightContractAddress()
+	require.False(t, enabled)
+
+	params := types.Params{VotingWeightContract: "0xabcdef0000000000000000000000000000000001"}
+	contract, enabled := params.VotingWeightContractAddress()
+	require.True(t, enabled)
+	require.Equal(t, common.HexToAddress("0xABCDEF0000000000000000000000000000000001"), contract)
+}
@@ -0,0 +1,101 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	errorsmod "cosmossdk.io/errors"
+)
+
+const (
+	// MethodSetVoteDelegate sets the vote delegate of the sender
+	MethodSetVoteDelegate = "setVoteDelegate"
+	// MethodClearVoteDelegate removes the vote delegate of the sender
+	MethodClearVoteDelegate = "clearVoteDelegate"
+)
+
+// voteDelegationABI is the interface of the calls sent to VoteDelegationAddress
+const voteDelegationABI = `[
+	{"type":"function","name":"setVoteDelegate","stateMutability":"nonpayable","outputs":[],"inputs":[
+		{"name":"delegate","type":"address"}
+	]},
+	{"type":"function","name":"clearVoteDelegate","stateMutability":"nonpayable","outputs":[],"inputs":[]}
+]`
+
+// VoteDelegationABI is the parsed interface of the vote delegation calls
+var VoteDelegationABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(voteDelegationABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// VoteDelegationCall is a decoded call to the vote delegation address
+type VoteDelegationCall struct {
+	Method   string
+	Delegate common.Address
+}
+
+// ParseVoteDelegationCall decodes the calldata of a vote delegation call
+func ParseVoteDelegationCall(data []byte) (VoteDelegationCall, error) {
+	if len(data) < 4 {
+		return VoteDelegationCall{}, errorsmod.Wrap(ErrInvalidVoteDelegationCall, "missing method selector")
+	}
+	method, err := VoteDelegationABI.MethodById(data[:4])
+	if err != nil {
+		return VoteDelegationCall{}, errorsmod.Wrap(ErrInvalidVoteDelegationCall, err.Error())
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return VoteDelegationCall{}, errorsmod.Wrapf(ErrInvalidVoteDelegationCall, "failed to decode %s arguments: %s", method.Name, err)
+	}
+
+	call := VoteDelegationCall{Method: method.Name}
+	if method.Name == MethodSetVoteDelegate {
+		call.Delegate = args[0].(common.Address)
+	}
+	return call, nil
+}
+
+// Pack encodes the call as calldata for the vote delegation address
+func (c VoteDelegationCall) Pack() ([]byte, error) {
+	if c.Method == MethodClearVoteDelegate {
+		return VoteDelegationABI.Pack(MethodClearVoteDelegate)
+	}
+	return VoteDelegationABI.Pack(MethodSetVoteDelegate, c.Delegate)
+}
+
+// VoteDelegation is the governance delegate of an account: unless the account
+// votes itself, the vote of the delegate on a proposal applies to its stake.
+type VoteDelegation struct {
+	// Delegator is the hex address of the account
+	Delegator string `json:"delegator" yaml:"delegator"`
+	// Delegate is the hex address voting on its behalf
+	Delegate string `json:"delegate" yaml:"delegate"`
+}
+
+// Validate performs basic validation of a vote delegation
+func (d VoteDelegation) Validate() error {
+	if !common.IsHexAddress(d.Delegator) {
+		return fmt.Errorf("invalid vote delegator address: %s", d.Delegator)
+	}
+	if !common.IsHexAddress(d.Delegate) {
+		return fmt.Errorf("invalid vote delegate address: %s", d.Delegate)
+	}
+	return ValidateVoteDelegate(common.HexToAddress(d.Delegator), common.HexToAddress(d.Delegate))
+}
+
+// ValidateVoteDelegate checks delegator can delegate its votes to delegate
+func ValidateVoteDelegate(delegator, delegate common.Address) error {
+	if delegate == (common.Address{}) {
+		return errorsmod.Wrap(ErrInvalidVoteDelegate, "the zero address can't vote")
+	}
+	if delegate == delegator {
+		return errorsmod.Wrapf(ErrInvalidVoteDelegate, "%s can't delegate its votes to itself", delegator.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Asphere-xyz/tacchain/x/tacgov/types"
+)
+
+func TestVoteDelegationCall(t *testing.T) {
+	delegate := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	for _, call := range []types.VoteDelegationCall{
+		{Method: types.MethodSetVoteDelegate, Delegate: delegate},
+		{Method: types.MethodClearVoteDelegate},
+	} {
+		data, err := call.Pack()
+		require.NoError(t, err)
+		parsed, err := types.ParseVoteDelegationCall(data)
+		require.NoError(t, err)
+		require.Equal(t, call, parsed)
+	}
+
+	data, err := types.VoteDelegationCall{Method: types.MethodSetVoteDelegate, Delegate: delegate}.Pack()
+	require.NoError(t, err)
+	for _, invalid := range [][]byte{nil, data[:3], {0xde, 0xad, 0xbe, 0xef}, data[:20]} {
+		_, err := types.ParseVoteDelegationCall(invalid)
+		require.ErrorIs(t, err, types.ErrInvalidVoteDelegationCall)
+	}
+}
+
+func TestGenesisValidate(t *testing.T) {
+	require.NoError(t, types.DefaultGenesisState().Validate())
+
+	alice := "0x1000000000000000000000000000000000000001"
+	bob := "0x2000000000000000000000000000000000000002"
+	valid := types.GenesisState{
+		Params: types.DefaultParams(),
+		VoteDelegations: []types.VoteDelegation{
+			{Delegator: alice, Delegate: bob},
+			{Delegator: bob, Delegate: alice},
+		},
+	}
+	require.NoError(t, valid.Validate())
+
+	testCases := []struct {
+		name        string
+		delegations []types.VoteDelegation
+	}{
+		{"invalid delegator", []types.VoteDelegation{{Delegator: "0x1234", Delegate: bob}}},
+		{"invalid delegate", []types.VoteDelegation{{Delegator: alice, Delegate: "tac1qyqszqgpqyqszqgpqyqszqgpqyqszqgpjnp7du"}}},
+		{"zero delegate", []types.VoteDelegation{{Delegator: alice, Delegate: common.Address{}.Hex()}}},
+		{"self delegation", []types.VoteDelegation{{Delegator: alice, Delegate: alice}}},
+		{"duplicate delegator", []types.VoteDelegation{
+			{Delegator: alice, Delegate: bob},
+			{Delegator: "0x1000000000000000000000000000000000000001", Delegate: "0x3000000000000000000000000000000000000003"},
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gs := types.GenesisState{Params: types.DefaultParams(), VoteDelegations: tc.delegations}
+			require.Error(t, gs.Validate())
+		})
+	}
+}
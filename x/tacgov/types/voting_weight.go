@@ -0,0 +1,36 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+const (
+	// MethodBalanceOf returns the derivative balance of a holder
+	MethodBalanceOf = "balanceOf"
+	// MethodTotalSupply returns the derivative supply
+	MethodTotalSupply = "totalSupply"
+)
+
+// votingWeightABI is the part of the ERC-20 interface read from the voting
+// weight contract
+const votingWeightABI = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[
+		{"name":"account","type":"address"}
+	],"outputs":[
+		{"name":"","type":"uint256"}
+	]},
+	{"type":"function","name":"totalSupply","stateMutability":"view","inputs":[],"outputs":[
+		{"name":"","type":"uint256"}
+	]}
+]`
+
+// VotingWeightABI is the parsed interface of the voting weight contract
+var VotingWeightABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(votingWeightABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()